@@ -0,0 +1,155 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Command addrbook generates and inspects signed peer address book bundles
+// for bootstrapping nodes without seed connectivity.
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keygen":
+		err = keygen(os.Args[2:])
+	case "generate":
+		err = generate(os.Args[2:])
+	case "inspect":
+		err = inspect(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "addrbook: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: addrbook keygen|generate|inspect [flags]")
+}
+
+func keygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := fs.String("out", "addrbook.key", "path for the hex-encoded signing key seed")
+	_ = fs.Parse(args)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generate key: %w", err)
+	}
+	if err := os.WriteFile(*out, []byte(hex.EncodeToString(priv.Seed())+"\n"), 0o600); err != nil {
+		return fmt.Errorf("write key: %w", err)
+	}
+	fmt.Println(hex.EncodeToString(pub))
+	return nil
+}
+
+func generate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	keyPath := fs.String("key", "addrbook.key", "hex-encoded signing key seed")
+	peersPath := fs.String("peers", "", "JSON array of address book entries")
+	issuer := fs.String("issuer", "", "issuer node ID, typically the regional aggregator")
+	ttl := fs.Duration("ttl", 72*time.Hour, "bundle validity period")
+	out := fs.String("out", "addrbook.json", "output bundle path")
+	_ = fs.Parse(args)
+
+	key, err := loadKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(*peersPath) // #nosec G304 -- operator-supplied peer list
+	if err != nil {
+		return fmt.Errorf("read peers: %w", err)
+	}
+	var entries []p2p.AddressBookEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("decode peers: %w", err)
+	}
+
+	book, err := p2p.NewAddressBook(*issuer, entries, *ttl)
+	if err != nil {
+		return err
+	}
+	if err := book.Sign(key); err != nil {
+		return err
+	}
+	encoded, err := json.MarshalIndent(book, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode bundle: %w", err)
+	}
+	if err := os.WriteFile(*out, encoded, 0o600); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	fmt.Printf("wrote %d entries to %s, expires %s\n", len(book.Entries), *out, book.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+func inspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	in := fs.String("in", "addrbook.json", "bundle to inspect")
+	signer := fs.String("signer", "", "hex-encoded trusted public key; verifies the bundle when set")
+	_ = fs.Parse(args)
+
+	data, err := os.ReadFile(*in) // #nosec G304 -- operator-supplied bundle path
+	if err != nil {
+		return fmt.Errorf("read bundle: %w", err)
+	}
+	var book p2p.AddressBook
+	if err := json.Unmarshal(data, &book); err != nil {
+		return fmt.Errorf("decode bundle: %w", err)
+	}
+
+	fmt.Printf("issuer:  %s\nsigner:  %s\nissued:  %s\nexpires: %s\nentries: %d\n",
+		book.IssuerID, hex.EncodeToString(book.SignerKey),
+		book.IssuedAt.Format(time.RFC3339), book.ExpiresAt.Format(time.RFC3339), len(book.Entries))
+	for _, entry := range book.Entries {
+		fmt.Printf("  %s  prior=%.2f  %s\n", entry.PeerID, entry.ReputationPrior, strings.Join(entry.Addresses, ","))
+	}
+
+	if *signer == "" {
+		return nil
+	}
+	trusted, err := hex.DecodeString(strings.TrimSpace(*signer))
+	if err != nil {
+		return fmt.Errorf("decode signer key: %w", err)
+	}
+	if err := book.Verify(ed25519.PublicKey(trusted), time.Now()); err != nil {
+		return err
+	}
+	fmt.Println("signature: valid")
+	return nil
+}
+
+func loadKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-supplied key path
+	if err != nil {
+		return nil, fmt.Errorf("read key: %w", err)
+	}
+	seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decode key: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid key seed: need %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
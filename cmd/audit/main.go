@@ -0,0 +1,92 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Command audit checks a round's audit bundle offline. It needs nothing
+// but the bundle and, optionally, the exporter's public key obtained out of
+// band: every artifact is checked against the signed index and every
+// signature against the keys the bundle carries, using only pkg/protocol
+// and the standard library's crypto.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "verify":
+		err = verify(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "audit: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: audit verify [flags]")
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	in := fs.String("in", "audit.tar", "audit bundle to verify")
+	keyPath := fs.String("key", "", "PEM public key the exporter must have signed with; unchecked if empty")
+	_ = fs.Parse(args)
+
+	var trusted []byte
+	if *keyPath != "" {
+		var err error
+		if trusted, err = os.ReadFile(filepath.Clean(*keyPath)); err != nil {
+			return fmt.Errorf("read trusted key: %w", err)
+		}
+	}
+	f, err := os.Open(filepath.Clean(*in))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	report, err := protocol.VerifyAuditBundle(f, trusted)
+	if err != nil {
+		return err
+	}
+	index := report.Index
+	fmt.Printf("federation: %s\nround:      %d\nexported:   %s by %s\n", index.FederationID, index.Round, index.ExportedAt.Format("2006-01-02T15:04:05Z07:00"), index.ExporterID)
+	faulty := make(map[string]bool)
+	for _, artifact := range report.Faulty() {
+		faulty[artifact] = true
+	}
+	for _, path := range append([]string{protocol.AuditIndexPath}, protocol.AuditArtifactPaths()...) {
+		status := "ok"
+		if faulty[path] {
+			status = "FAILED"
+		}
+		fmt.Printf("  %-32s %s\n", path, status)
+	}
+	for _, finding := range report.Findings {
+		fmt.Printf("%s: %s\n", finding.Artifact, finding.Detail)
+	}
+	if !report.Valid() {
+		return fmt.Errorf("%d findings in %d entries", len(report.Findings), len(report.Faulty()))
+	}
+	if len(trusted) == 0 {
+		fmt.Println("bundle: intact, exporter key not checked against a trusted key")
+		return nil
+	}
+	fmt.Println("bundle: valid")
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Command backup creates, inspects, and restores federation state archives
+// through a node's admin API.
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/backup"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "create":
+		err = create(os.Args[2:])
+	case "inspect":
+		err = inspect(os.Args[2:])
+	case "restore":
+		err = restore(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "backup: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: backup create|inspect|restore [flags]")
+}
+
+// nodeFlags are the flags shared by subcommands that talk to a node.
+type nodeFlags struct {
+	node      *string
+	tokenFile *string
+	timeout   *time.Duration
+}
+
+func addNodeFlags(fs *flag.FlagSet) nodeFlags {
+	return nodeFlags{
+		node:      fs.String("node", "http://127.0.0.1:8082", "node API base URL"),
+		tokenFile: fs.String("token-file", os.Getenv("MOHAWK_API_TOKEN_FILE"), "file holding the admin API token"),
+		timeout:   fs.Duration("timeout", 5*time.Minute, "request timeout"),
+	}
+}
+
+func (f nodeFlags) request(method, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	endpoint, err := url.JoinPath(*f.node, path)
+	if err != nil {
+		return nil, fmt.Errorf("node url: %w", err)
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-API-Role", "admin")
+	if *f.tokenFile != "" {
+		token, err := os.ReadFile(*f.tokenFile) // #nosec G304 -- operator-supplied token path
+		if err != nil {
+			return nil, fmt.Errorf("read token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	client := &http.Client{Timeout: *f.timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+func create(args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	nf := addNodeFlags(fs)
+	out := fs.String("out", "federation-backup.tar", "output archive path")
+	_ = fs.Parse(args)
+
+	resp, err := nf.request(http.MethodGet, "/api/v1/admin/backup", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("download archive: %w", err)
+	}
+	archive, err := backup.Read(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("downloaded archive is unreadable: %w", err)
+	}
+	if corrupt := archive.Corrupt(); len(corrupt) > 0 {
+		return fmt.Errorf("downloaded archive has %d corrupt entries", len(corrupt))
+	}
+	if err := os.WriteFile(*out, data, 0o600); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	fmt.Printf("wrote %d components from %s to %s\n", len(archive.Entries), archive.Manifest.NodeID, *out)
+	return nil
+}
+
+func inspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	in := fs.String("in", "federation-backup.tar", "archive to inspect")
+	signer := fs.String("signer", "", "hex-encoded trusted public key; verifies the manifest when set")
+	_ = fs.Parse(args)
+
+	file, err := os.Open(*in) // #nosec G304 -- operator-supplied archive path
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer file.Close()
+	archive, err := backup.Read(file)
+	if err != nil {
+		return err
+	}
+
+	m := archive.Manifest
+	fmt.Printf("version: %d\nnode:    %s\ncreated: %s\nsigner:  %s\nentries: %d\n",
+		m.Version, m.NodeID, m.CreatedAt.Format(time.RFC3339), hex.EncodeToString(m.SignerKey), len(archive.Entries))
+	for _, entry := range archive.Entries {
+		status := "ok"
+		if !entry.Valid {
+			status = "CORRUPT: " + entry.Reason
+		}
+		fmt.Printf("  %-12s %10d bytes  %s\n", entry.Component, entry.Size, status)
+	}
+
+	if *signer != "" {
+		trusted, err := hex.DecodeString(strings.TrimSpace(*signer))
+		if err != nil {
+			return fmt.Errorf("decode signer key: %w", err)
+		}
+		if err := m.Verify(ed25519.PublicKey(trusted)); err != nil {
+			return err
+		}
+		fmt.Println("signature: valid")
+	}
+	if corrupt := archive.Corrupt(); len(corrupt) > 0 {
+		return fmt.Errorf("%d of %d entries are corrupt", len(corrupt), len(archive.Entries))
+	}
+	return nil
+}
+
+func restore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	nf := addNodeFlags(fs)
+	in := fs.String("in", "federation-backup.tar", "archive to restore")
+	partial := fs.Bool("partial", false, "restore intact components of a partially corrupt archive")
+	_ = fs.Parse(args)
+
+	data, err := os.ReadFile(*in) // #nosec G304 -- operator-supplied archive path
+	if err != nil {
+		return fmt.Errorf("read archive: %w", err)
+	}
+	query := url.Values{}
+	if *partial {
+		query.Set("partial", "true")
+	}
+	resp, err := nf.request(http.MethodPost, "/api/v1/admin/restore", query, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var report backup.RestoreReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("decode restore report: %w", err)
+	}
+	fmt.Printf("restored: %s\n", strings.Join(report.Restored, ", "))
+	skipped := make([]string, 0, len(report.Skipped))
+	for name := range report.Skipped {
+		skipped = append(skipped, name)
+	}
+	sort.Strings(skipped)
+	for _, name := range skipped {
+		fmt.Printf("skipped:  %s (%s)\n", name, report.Skipped[name])
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Command bootstrap runs a federation's genesis ceremony: it assembles the
+// founders' keys and capability manifests, the model spec, fault model,
+// privacy budget, and shard layout into a genesis document, collects the
+// founders' signatures on it, and verifies the result against a pinned
+// digest.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/genesis"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// founderInput is one founder as the operator lists it: the paths of the
+// PEM public key and capability manifest the founder handed over, relative
+// to the founders file.
+type founderInput struct {
+	NodeID           string `json:"node_id"`
+	PublicKeyFile    string `json:"public_key_file"`
+	CapabilitiesFile string `json:"capabilities_file,omitempty"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "init":
+		err = initGenesis(os.Args[2:])
+	case "sign":
+		err = sign(os.Args[2:])
+	case "verify":
+		err = verify(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bootstrap: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: bootstrap init|sign|verify [flags]")
+}
+
+func initGenesis(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	federationID := fs.String("federation", protocol.DefaultFederation, "federation the genesis founds")
+	foundersPath := fs.String("founders", "founders.json", "JSON array of founders: node_id, public_key_file, capabilities_file")
+	specPath := fs.String("spec", "model_spec.json", "model spec JSON")
+	model := fs.String("fault-model", string(faultmodel.Classic33), "fault model: classic33 or hierarchical55")
+	epsilon := fs.Float64("epsilon", 8, "federation privacy budget epsilon")
+	delta := fs.Float64("delta", 1e-5, "federation privacy budget delta")
+	shardsPath := fs.String("shards", "", "JSON array of shards: shard_id, aggregator, members, budget")
+	threshold := fs.Int("threshold", 0, "founder signatures required; 0 is a majority")
+	out := fs.String("out", "genesis.json", "output genesis path")
+	_ = fs.Parse(args)
+
+	founders, err := loadFounders(*foundersPath)
+	if err != nil {
+		return err
+	}
+	var spec protocol.ModelSpec
+	if err := readJSON(*specPath, &spec); err != nil {
+		return fmt.Errorf("read model spec: %w", err)
+	}
+	var shards []genesis.Shard
+	if *shardsPath != "" {
+		if err := readJSON(*shardsPath, &shards); err != nil {
+			return fmt.Errorf("read shards: %w", err)
+		}
+	}
+	faultModel, err := faultmodel.Parse(*model)
+	if err != nil {
+		return err
+	}
+	if *threshold == 0 {
+		*threshold = len(founders)/2 + 1
+	}
+
+	g, err := genesis.New(genesis.Genesis{
+		FederationID:  *federationID,
+		Founders:      founders,
+		Threshold:     *threshold,
+		ModelSpec:     spec,
+		FaultModel:    faultModel,
+		PrivacyBudget: privacy.Budget{Epsilon: *epsilon, Delta: *delta},
+		Shards:        shards,
+	})
+	if err != nil {
+		return err
+	}
+	if err := write(*out, g); err != nil {
+		return err
+	}
+	fmt.Printf("wrote genesis of %s to %s: %d founders, %d signatures required\ndigest: %s\n", g.FederationID, *out, len(g.Founders), g.Threshold, g.Digest())
+	return nil
+}
+
+func sign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	in := fs.String("in", "genesis.json", "genesis to sign, rewritten in place")
+	nodeID := fs.String("node-id", "", "signing founder's node ID")
+	keyPath := fs.String("key", "", "founder's identity key file")
+	passphraseFile := fs.String("passphrase-file", "", "file holding the identity passphrase; MOHAWK_IDENTITY_PASSPHRASE otherwise")
+	_ = fs.Parse(args)
+
+	g, err := genesis.Load(*in)
+	if err != nil {
+		return err
+	}
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	passphrase := []byte(os.Getenv("MOHAWK_IDENTITY_PASSPHRASE"))
+	if *passphraseFile != "" {
+		raw, err := os.ReadFile(*passphraseFile) // #nosec G304 -- operator-supplied passphrase path
+		if err != nil {
+			return fmt.Errorf("read passphrase: %w", err)
+		}
+		passphrase = []byte(strings.TrimRight(string(raw), "\r\n"))
+	}
+	store, err := crypto.NewIdentityStore(*keyPath, passphrase)
+	if err != nil {
+		return err
+	}
+	identity, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("load identity: %w", err)
+	}
+	if err := g.Sign(*nodeID, identity); err != nil {
+		return err
+	}
+	if _, err := g.Signers(); err != nil {
+		return fmt.Errorf("%s's identity is not the key the genesis lists: %w", *nodeID, err)
+	}
+	if err := write(*in, g); err != nil {
+		return err
+	}
+	fmt.Printf("%s signed %s; %d of %d signatures required\n", *nodeID, g.Digest(), len(g.Signatures), g.Threshold)
+	return nil
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	in := fs.String("in", "genesis.json", "genesis to verify")
+	pinned := fs.String("digest", "", "pinned genesis digest; the one nodes set as MOHAWK_GENESIS_DIGEST")
+	_ = fs.Parse(args)
+
+	g, err := genesis.Load(*in)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("federation:  %s\ndigest:      %s\nfault model: %s\nmodel:       %s v%d (%d weights)\nshards:      %d\n",
+		g.FederationID, g.Digest(), g.FaultModel, g.ModelSpec.Name, g.ModelSpec.Version, g.ModelSpec.Dimension, len(g.Shards))
+	signers, _ := g.Signers()
+	fmt.Printf("signed by:   %s (%d of %d required)\n", strings.Join(signers, ", "), len(signers), g.Threshold)
+	if err := g.Verify(strings.ToLower(strings.TrimSpace(*pinned))); err != nil {
+		return err
+	}
+	fmt.Println("genesis: valid")
+	return nil
+}
+
+// loadFounders reads the founders file and the keys and manifests it
+// points to.
+func loadFounders(path string) ([]genesis.Founder, error) {
+	var inputs []founderInput
+	if err := readJSON(path, &inputs); err != nil {
+		return nil, fmt.Errorf("read founders: %w", err)
+	}
+	dir := filepath.Dir(path)
+	founders := make([]genesis.Founder, 0, len(inputs))
+	for _, input := range inputs {
+		publicKey, err := os.ReadFile(filepath.Join(dir, input.PublicKeyFile)) // #nosec G304 -- operator-supplied key path
+		if err != nil {
+			return nil, fmt.Errorf("founder %s key: %w", input.NodeID, err)
+		}
+		founder := genesis.Founder{NodeID: input.NodeID, PublicKey: publicKey}
+		if input.CapabilitiesFile != "" {
+			founder.Capabilities = &protocol.CapabilityManifest{}
+			if err := readJSON(filepath.Join(dir, input.CapabilitiesFile), founder.Capabilities); err != nil {
+				return nil, fmt.Errorf("founder %s capabilities: %w", input.NodeID, err)
+			}
+		}
+		founders = append(founders, founder)
+	}
+	return founders, nil
+}
+
+func readJSON(path string, out interface{}) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func write(path string, g *genesis.Genesis) error {
+	encoded, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode genesis: %w", err)
+	}
+	if err := os.WriteFile(path, encoded, 0o600); err != nil {
+		return fmt.Errorf("write genesis: %w", err)
+	}
+	return nil
+}
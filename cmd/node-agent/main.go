@@ -16,21 +16,61 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/api"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/backup"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/blockchain"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/capability"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/certchain"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crash"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/evaluation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/failover"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/genesis"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/lifecycle"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/moduledist"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/role"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/rollback"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/sharding"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/simrand"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/tpm"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/upload"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
 )
 
 // Config simulates the capability manifest for a 10M-node edge participant.
@@ -53,6 +93,8 @@ func withCORS(next http.Handler) http.Handler {
 }
 
 func main() {
+	logRing := crash.NewLogRing(parsePositiveIntEnv("MOHAWK_CRASH_LOG_LINES", crash.DefaultLogLines))
+	log.SetOutput(io.MultiWriter(os.Stderr, logRing))
 	log.Println("Sovereign-Mohawk Node Agent starting...")
 
 	// 1. Configuration Setup
@@ -61,6 +103,27 @@ func main() {
 		WasmModulePath: "proof_verifier.wasm",
 		NodeID:         "edge-node-001",
 	}
+	if nodeID := strings.TrimSpace(os.Getenv("MOHAWK_NODE_ID")); nodeID != "" {
+		conf.NodeID = nodeID
+	}
+	// MOHAWK_ROLE picks the tier this agent runs; identity, wasm, and
+	// monitoring below are common to every tier.
+	nodeRole, err := role.Parse(os.Getenv("MOHAWK_ROLE"))
+	if err != nil {
+		log.Fatalf("Critical Failure: %v", err)
+	}
+	log.Printf("Node %s running as %s", sanitizeLogValue(conf.NodeID), nodeRole)
+
+	identity, err := loadNodeIdentity()
+	if err != nil {
+		log.Fatalf("Critical Failure: %v", err)
+	}
+	// The federation's root of trust: a node refuses to start on any
+	// genesis but the one it pinned.
+	founding, genesisDigest, err := loadGenesis()
+	if err != nil {
+		log.Fatalf("Critical Failure: %v", err)
+	}
 
 	// 2. Load Wasm Proof Module (Theorem 5)
 	// The binary is required for the new high-performance wazero host.
@@ -76,11 +139,44 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	runner, err := wasmhost.NewRunner(ctx, wasmBin)
+	// Verification, training, sync, and admin work share the CPU through
+	// one scheduler so a burst of proofs cannot starve the rest.
+	workConfig, err := newWorkSchedulerConfigFromEnv()
+	if err != nil {
+		log.Printf("work scheduler left at defaults: %v", err)
+		workConfig = scheduler.DefaultWorkSchedulerConfig()
+	}
+	workScheduler, err := scheduler.NewWorkScheduler(workConfig)
+	if err != nil {
+		log.Fatalf("Critical Failure: Could not start work scheduler: %v", err)
+	}
+	workScheduler.SetDepthObserver(func(class scheduler.WorkClass, depth int) {
+		api.ObserveWorkQueueDepth(string(class), depth)
+	})
+
+	collector := monitoring.NewCollector(1024)
+	if sampling, err := newSamplingConfigFromEnv(); err != nil {
+		log.Printf("telemetry sampling left off: %v", err)
+	} else if sampling != nil {
+		collector.SetSampling(sampling)
+	}
+	verifySLO := newVerificationSLOFromEnv(collector)
+	verifySLO.AddAlertListener(func(alert monitoring.SLOAlert) {
+		if alert.Firing {
+			log.Printf("ALERT %s firing: %s", alert.Rule, alert.Summary)
+		} else {
+			log.Printf("ALERT %s resolved: %s", alert.Rule, alert.Summary)
+		}
+	})
+	observeVerification := func(sample monitoring.VerificationSample) {
+		api.ObserveWasmVerification(sample, verifySLO.Observe(sample))
+	}
+	runner, err := newWasmVerifierPool(ctx, wasmBin, workScheduler, observeVerification)
 	if err != nil {
 		log.Fatalf("Critical Failure: Could not initialize Wasm Runner: %v", err)
 	}
 	defer func() {
+		workScheduler.Close()
 		if closeErr := runner.Close(ctx); closeErr != nil {
 			log.Printf("warning: failed to close runner: %v", closeErr)
 		}
@@ -91,21 +187,24 @@ func main() {
 	// 4. Verification Loop
 	// Simulates the 10ms verification window (Theorem 5) required for 10M-node scale.
 	mockProof := make([]byte, 200) // Theorem 5: 200-byte proof target
-	success, err := runner.Verify(ctx, mockProof)
-	if err != nil {
+	success, verifyErr := runner.Verify(ctx, mockProof)
+	if verifyErr != nil {
 		// Note: Mock modules will likely fail verification; this is expected in CI.
-		log.Printf("Verification Process Executed: %v", err)
+		log.Printf("Verification Process Executed: %v", verifyErr)
 	} else {
 		log.Printf("Theorem 5 Verification Status: %v", success)
 	}
 
 	chain := blockchain.NewBlockChain()
 	var proofVerifier blockchain.ProofVerifier
+	var attestationManager *tpm.AttestationManager
+	// syntheticBatch runs once the crash handler can watch it.
+	var syntheticBatch func(crashes *crash.Handler)
 	if os.Getenv("MOHAWK_ENABLE_TPM_VERIFIER") != "false" {
 		maxReports := parsePositiveIntEnv("TPM_ATTESTATION_MAX_REPORTS", 256)
 		cacheTTL := parseDurationEnv("TPM_ATTESTATION_CACHE_TTL", 30*time.Second)
 		spikeThreshold := parseDurationEnv("TPM_ATTESTATION_SPIKE_THRESHOLD", 200*time.Microsecond)
-		attestationManager := tpm.NewAttestationManager(maxReports, cacheTTL, true)
+		attestationManager = tpm.NewAttestationManager(maxReports, cacheTTL, true)
 		attestationManager.SetLatencySpikeThreshold(spikeThreshold)
 		proofVerifier = tpm.NewTPMProofVerifier(attestationManager)
 		chain.SetProofVerifier(proofVerifier)
@@ -126,17 +225,464 @@ func main() {
 			if syntheticWorkers > syntheticBatchCount {
 				syntheticWorkers = syntheticBatchCount
 			}
-			go runTPMSyntheticBatch(proofVerifier, syntheticBatchCount, syntheticWorkers)
+			syntheticBatch = func(crashes *crash.Handler) {
+				runTPMSyntheticBatch(crashes, proofVerifier, syntheticBatchCount, syntheticWorkers)
+			}
 		}
 	}
 
-	collector := monitoring.NewCollector(1024)
 	coordinator := consensus.NewCoordinator(conf.NodeID, 5, 10*time.Second)
+	coordinator.SetCollector(collector)
 	distributedAggregator := consensus.NewDistributedAggregator(conf.NodeID, []string{"peer-1", "peer-2", "peer-3", "peer-4"}, 10*time.Second)
+	distributedAggregator.SetHistorySize(parsePositiveIntEnv("MOHAWK_AGGREGATION_HISTORY", consensus.DefaultAggregationHistory))
+	distributedAggregator.SetCollector(collector)
+	distributedAggregator.SetRoundObserver(api.ObserveAggregationRound)
+
+	modelStore := modeldist.NewModelStore(parsePositiveIntEnv("MOHAWK_MODEL_STORE_ROUNDS", 256))
+	modelSigner, err := loadModelSigner(identity)
+	if err != nil {
+		log.Printf("model signing disabled: %v", err)
+	} else if err := modelStore.SetSigner(modelSigner); err != nil {
+		log.Printf("model signing disabled: %v", err)
+		modelSigner = nil
+	}
+	validators, err := loadValidators()
+	if err != nil {
+		log.Fatalf("Critical Failure: %v", err)
+	}
+	if err := modelStore.SetValidators(validators); err != nil {
+		log.Fatalf("Critical Failure: %v", err)
+	}
+
+	// Aggregator TLS keys are trusted on first use and pinned from then on,
+	// so a hijacked name cannot silently take this node's updates.
+	aggregatorPins, err := loadAggregatorPins()
+	if err != nil {
+		log.Fatalf("Critical Failure: %v", err)
+	}
+	// Every call to the aggregator one tier up, from health probes to
+	// update submissions, shares one pool of warm connections.
+	aggregatorTransport, err := newAggregatorTransport(aggregatorPins, identity)
+	if err != nil {
+		log.Fatalf("Critical Failure: %v", err)
+	}
+
+	// Background components start in dependency order once the node is
+	// wired, restart after crashes, and stop in reverse on SIGTERM. A failed
+	// API server takes the node down.
+	supervisor := lifecycle.NewSupervisor()
+	apiFailed := make(chan lifecycle.ComponentStatus, 1)
+	supervisor.SetStateListener(func(status lifecycle.ComponentStatus) {
+		switch status.State {
+		case lifecycle.StateRestarting:
+			log.Printf("%s crashed, restart %d: %s", status.Name, status.Restarts, status.LastError)
+		case lifecycle.StateFailed:
+			log.Printf("%s failed: %s", status.Name, status.LastError)
+			if status.Name == "api" {
+				apiFailed <- status
+			}
+		}
+	})
+
+	var (
+		islandMgr       *island.Manager
+		initialBackfill func()
+	)
+	if aggregatorURL := strings.TrimSpace(os.Getenv("MOHAWK_AGGREGATOR_URL")); aggregatorURL != "" {
+		islandMgr, initialBackfill = startIslandRejoin(supervisor, aggregatorURL, modelStore, aggregatorTransport)
+	}
+
+	crashHandler, recovery := startCrashHandler(conf.NodeID, logRing, coordinator, distributedAggregator, islandMgr)
+	if syntheticBatch != nil {
+		crashHandler.Go("tpm-synthetic-batch", func() { syntheticBatch(crashHandler) })
+	}
+	if initialBackfill != nil {
+		crashHandler.Go("initial-backfill", initialBackfill)
+	}
+	metricsPrivatizer, err := newMetricsPrivatizerFromEnv()
+	if err != nil {
+		log.Printf("metrics privacy disabled: %v", err)
+	}
+	// Heartbeats carry the crash snapshot this run recovered from, the
+	// TPM's health, and the verifier module an edge installed, and leave
+	// the node privatized when metrics privacy is configured.
+	var moduleInstaller *moduledist.Installer
+	stampStatus := func(update *protocol.StatusUpdate) {
+		recovery.StampHeartbeat(update)
+		if attestationManager != nil {
+			attestationManager.StampHeartbeat(update)
+		}
+		if moduleInstaller != nil {
+			moduleInstaller.StampHeartbeat(update)
+		}
+		if metricsPrivatizer != nil {
+			*update = metricsPrivatizer.PrivatizeStatus(*update)
+		}
+	}
+	crashHandler.SetDrain(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), parseDurationEnv("MOHAWK_SHUTDOWN_TIMEOUT", 30*time.Second))
+		defer cancel()
+		if err := supervisor.Stop(ctx); err != nil {
+			log.Printf("warning: shutdown incomplete: %v", err)
+		}
+	})
 
-	handler := api.NewHandler(nil, nil, collector, nil)
+	handler := api.NewHandler(nil, islandMgr, collector, nil)
 	handler.SetBlockchain(chain)
 	handler.SetConsensusReaders(coordinator, distributedAggregator)
+	handler.SetAggregationHistory(distributedAggregator)
+	handler.SetModelStore(modelStore)
+	// Sampled nodes post evaluation reports of committed models; completed
+	// rounds are recorded next to them in the model store.
+	handler.SetEvaluator(evaluation.NewEvaluator(modelStore))
+	handler.SetAggregatorPins(aggregatorPins)
+	inbound := p2p.NewInboundGuard(p2p.DefaultInboundLimits())
+	inbound.SetViolationHandler(func(peerID string, err error) {
+		log.Printf("warning: refused inbound message from %s: %v", peerID, err)
+	})
+	handler.SetInboundGuard(inbound)
+	// Messages peers post through their p2p.HTTPSender are dispatched by
+	// topic to the components that register for them.
+	receiver := p2p.NewReceiver()
+	handler.SetMessageReceiver(receiver)
+	if founding != nil {
+		handler.SetGenesisDigest(genesisDigest)
+	}
+	// Round lifecycle events, streamed on /api/events/stream and followed
+	// by alerting and provenance rather than called by the components
+	// that publish them.
+	roundEvents := events.NewBus()
+	coordinator.SetEvents(roundEvents)
+	handler.SetEventBus(roundEvents)
+	roundAlerts := monitoring.NewRoundAlerts()
+	roundAlerts.AddAlertListener(func(alert monitoring.RoundAlert) {
+		if alert.Firing {
+			log.Printf("ALERT %s firing for %q: %s", alert.Rule, alert.FederationID, alert.Summary)
+		} else {
+			log.Printf("ALERT %s resolved for %q: %s", alert.Rule, alert.FederationID, alert.Summary)
+		}
+	})
+	roundAlerts.Subscribe(roundEvents)
+
+	// Peers registering through /api/v1/register must present a verifiable
+	// attestation envelope whenever the TPM verifier is enabled.
+	peerVerifier := p2p.NewVerifier(conf.NodeID, parsePositiveIntEnv("MOHAWK_MIN_VERIFICATIONS", 3), 30*time.Second)
+	if path := strings.TrimSpace(os.Getenv("MOHAWK_COMMITTEE_POLICY_PATH")); path != "" {
+		if err := peerVerifier.LoadCommitteePolicies(path); err != nil {
+			log.Fatalf("Critical Failure: %v", err)
+		}
+	}
+	if attestationManager != nil {
+		peerVerifier.SetAttestationVerifier(attestationManager.VerifyEnvelope)
+	}
+	handler.SetVerifier(peerVerifier)
+	fragmenter, err := startFragmentation(supervisor, identity, peerVerifier, receiver)
+	if err != nil {
+		log.Printf("message fragmentation disabled: %v", err)
+	}
+	// Votes are authenticated by signature, or by pairwise MAC where the
+	// shard negotiated MACs, and commits rest on signed approvals either
+	// way.
+	voteAuth, err := newVoteAuthFromEnv(conf.NodeID, identity, peerVerifier, founding)
+	if err != nil {
+		log.Printf("vote authentication disabled: %v", err)
+	} else if voteAuth != nil {
+		coordinator.SetCommitVerifier(voteAuth.VerifySignature)
+	}
+	// A node whose signed votes contradict each other, here or in a vote
+	// set a peer gossips, is blacklisted on that proof.
+	var equivocation *consensus.EquivocationDetector
+	if voteAuth != nil {
+		equivocation = consensus.NewEquivocationDetector(voteAuth.VerifySignature)
+		equivocation.SetBlacklistObserver(func(proof consensus.EquivocationProof) {
+			log.Printf("warning: %s equivocated on proposal %s and is blacklisted", sanitizeLogValue(proof.NodeID), sanitizeLogValue(proof.ProposalID))
+		})
+		coordinator.SetEquivocationDetector(equivocation)
+		if err := startVoteSetGossip(supervisor, conf.NodeID, coordinator, receiver, aggregatorTransport, fragmenter); err != nil {
+			log.Printf("vote set gossip disabled: %v", err)
+		}
+	}
+	if middleware, err := newVoteMiddlewareFromEnv(coordinator, peerVerifier, voteAuth, equivocation); err != nil {
+		log.Printf("vote middleware left at defaults: %v", err)
+	} else {
+		coordinator.SetVoteMiddleware(middleware...)
+	}
+	coordinator.SetVoteRejectionObserver(api.ObserveVoteRejection)
+	// Sealed votes keep Byzantine voters from swinging quorum on the votes
+	// they have already seen.
+	if os.Getenv("MOHAWK_VOTE_COMMIT_REVEAL") == "true" {
+		coordinator.SetCommitReveal(&consensus.CommitRevealConfig{
+			CommitWindow: parseDurationEnv("MOHAWK_VOTE_COMMIT_WINDOW", 0),
+			RevealWindow: parseDurationEnv("MOHAWK_VOTE_REVEAL_WINDOW", 0),
+			Penalize:     peerVerifier.PenalizeUnrevealedVote,
+		})
+	}
+	// Quorums and admission thresholds follow one explicit fault model:
+	// the genesis's when the node started from one.
+	faultModel, topology, err := newFaultModelFromEnv()
+	if founding != nil {
+		faultModel, topology, err = founding.FaultModel, founding.Topology(), nil
+	}
+	if err != nil {
+		log.Printf("fault model left at %s: %v", faultmodel.Classic33, err)
+		faultModel, topology = faultmodel.Classic33, faultmodel.Topology{}
+	}
+	if err := coordinator.SetFaultModel(faultModel, topology); err != nil {
+		log.Printf("fault model left at %s: %v", faultmodel.Classic33, err)
+	}
+	health := monitoring.NewHealthEvaluator()
+	if verifyErr != nil {
+		health.ObserveWasm(false, verifyErr.Error())
+	} else {
+		health.ObserveWasm(true, "")
+	}
+	handler.SetHealthEvaluator(health)
+	health.PublishTransitions(roundEvents, "health")
+	// The node took part in a round of its coordinator if it proposed it
+	// or voted on it.
+	health.FollowRounds(roundEvents, func(event events.Event) bool {
+		round, err := coordinator.GetConsensusRound(event.ProposalID)
+		if err != nil {
+			return false
+		}
+		if round.ProposerID == conf.NodeID {
+			return true
+		}
+		for _, vote := range round.ValidatorVotes {
+			if vote.NodeID == conf.NodeID {
+				return true
+			}
+		}
+		return false
+	})
+	if err := supervisor.Register(lifecycle.Spec{
+		Name:      "health",
+		Component: lifecycle.Loop(newHealthSampler(health, conf.NodeID, peerVerifier, workScheduler)),
+	}); err != nil {
+		log.Printf("health sampling disabled: %v", err)
+	}
+	if attestationManager != nil {
+		attestationManager.SetAttestationObserver(func(report *tpm.AttestationReport) {
+			health.ObserveAttestation(report.Timestamp)
+		})
+		// A TPM that stops answering degrades the node rather than failing
+		// every quote; the device is reopened with backoff meanwhile.
+		policy := tpm.DefaultDegradationPolicy()
+		if os.Getenv("MOHAWK_TPM_DEGRADED_PARTICIPATION") == string(tpm.ParticipationPaused) {
+			policy.Degraded = tpm.ParticipationPaused
+		}
+		if err := attestationManager.SetDegradationPolicy(policy); err != nil {
+			log.Printf("TPM degradation policy left at defaults: %v", err)
+		}
+		attestationManager.AddHardwareStateListener(func(hw tpm.HardwareHealth) {
+			if hw.State == tpm.HardwareDegraded {
+				log.Printf("TPM degraded after %d errors (%s); participating as %s", hw.ConsecutiveErrors, hw.LastError, hw.Participation)
+				health.ObserveAttestationHardware(false, hw.LastError)
+				return
+			}
+			log.Printf("TPM recovered after %d reopen attempts", hw.ReopenAttempts)
+			health.ObserveAttestationHardware(true, "")
+			health.ObserveAttestation(time.Now())
+		})
+		if err := supervisor.Register(lifecycle.Spec{
+			Name:      "attestation",
+			Component: lifecycle.Loop(func(ctx context.Context) { attestationManager.Run(ctx, policy.ReopenBackoff) }),
+		}); err != nil {
+			log.Printf("TPM reopen loop disabled: %v", err)
+		}
+	}
+	if err := startFailover(supervisor, conf.NodeID, coordinator, islandMgr, aggregatorTransport, fragmenter, receiver); err != nil {
+		log.Printf("failover disabled: %v", err)
+	}
+	if archiver, err := newArchiverFromEnv(conf.NodeID, coordinator, modelStore, islandMgr); err != nil {
+		log.Printf("backup disabled: %v", err)
+	} else if archiver != nil {
+		handler.SetArchiver(archiver)
+	}
+	// Registering peers must carry a capability manifest that meets the
+	// admission policy; this node's own manifest is re-probed periodically.
+	// Federation rounds select the members able to train their model.
+	capabilities := scheduler.NewCapabilityRegistry(newAdmissionPolicyFromEnv())
+	handler.SetCapabilityRegistry(capabilities)
+	// Newly registered peers serve a probation before they may propose,
+	// verify, or count toward quorum.
+	if probationCfg, err := newProbationConfigFromEnv(); err != nil {
+		log.Printf("admission probation disabled: %v", err)
+	} else if probationCfg != nil {
+		if probation, err := consensus.NewProbation(*probationCfg); err != nil {
+			log.Printf("admission probation disabled: %v", err)
+		} else {
+			coordinator.SetProbation(probation)
+			peerVerifier.SetProbationCheck(probation.OnProbation)
+			handler.SetProbation(probation)
+		}
+	}
+	// Registering peers are admitted into shards by consistent hashing
+	// and receive signed shard assignments; each epoch moves a bounded
+	// share of them after the shard layout changes. Every change to a
+	// shard's members rotates its gossip group key, which members fetch
+	// from /api/v1/topic-keys. At the global tier it also commits the
+	// shard's next membership epoch, served on /api/v1/shards, under which
+	// the shard's members certify the summaries its regional aggregator
+	// submits.
+	var epochs *shardEpochs
+	if shardCfg, err := newShardConfigFromEnv(faultModel); err != nil {
+		log.Printf("shard assignment disabled: %v", err)
+	} else if shardCfg != nil {
+		if assigner, err := sharding.NewAssigner(*shardCfg, identity); err != nil {
+			log.Printf("shard assignment disabled: %v", err)
+		} else {
+			topicKeys := p2p.NewTopicKeyManager(conf.NodeID, identity)
+			if nodeRole == role.Global {
+				if epochs, err = newShardEpochsFromEnv(identity, faultModel); err != nil {
+					log.Printf("shard certificates disabled: %v", err)
+				} else if epochs != nil {
+					handler.SetEpochLedger(epochs.ledger)
+				}
+			}
+			assigner.SetMembershipHook(func(shardID string, members []protocol.ShardMember) {
+				if _, err := topicKeys.SetMemberKeys(p2p.ShardTopic(shardID), members); err != nil {
+					log.Printf("shard %s rekey: %v", sanitizeLogValue(shardID), err)
+				}
+				if epochs != nil {
+					if err := epochs.commit(shardID, members); err != nil {
+						log.Printf("shard %s epoch: %v", sanitizeLogValue(shardID), err)
+					}
+				}
+			})
+			handler.SetTopicKeys(topicKeys)
+			handler.SetShardAssigner(assigner)
+			startShardRebalancer(supervisor, assigner, parseDurationEnv("MOHAWK_SHARD_EPOCH", 10*time.Minute))
+		}
+	}
+	// Regional shards' privacy budgets, served on /api/privacy.
+	var privacyBudgets *privacy.BudgetRegistry
+	if path := strings.TrimSpace(os.Getenv("MOHAWK_PRIVACY_BUDGETS_FILE")); path != "" {
+		if budgets, err := privacy.LoadBudgetRegistry(path); err != nil {
+			log.Printf("privacy budgets disabled: %v", err)
+		} else {
+			privacyBudgets = budgets
+			handler.SetPrivacyBudgets(budgets)
+		}
+	} else if founding != nil {
+		if budgets, err := founding.BudgetRegistry(); err != nil {
+			log.Printf("privacy budgets disabled: %v", err)
+		} else {
+			privacyBudgets = budgets
+			handler.SetPrivacyBudgets(budgets)
+		}
+	}
+	// Region topology from which regional and global rounds derive their
+	// budgets, served on /api/latency_budgets.
+	if topology, err := newTopologyFromEnv(founding); err != nil {
+		log.Printf("latency budgets disabled: %v", err)
+	} else if topology != nil {
+		handler.SetTopology(topology)
+	}
+	// Convergence history of past training campaigns, compared on
+	// /api/convergence/campaigns.
+	if dir := strings.TrimSpace(os.Getenv("MOHAWK_CAMPAIGN_DIR")); dir != "" {
+		if tracker, err := convergence.NewCampaignTracker(dir); err != nil {
+			log.Printf("campaign tracker disabled: %v", err)
+		} else {
+			handler.SetCampaignTracker(tracker)
+		}
+	}
+	// Lifecycle of every update from ingest to commit, served on
+	// /api/provenance.
+	var provenanceSink provenance.Sink
+	if dir := strings.TrimSpace(os.Getenv("MOHAWK_PROVENANCE_DIR")); dir != "" {
+		if tracker, err := provenance.NewTracker(dir, parsePositiveIntEnv("MOHAWK_PROVENANCE_RETAIN_ROUNDS", provenance.DefaultRetainRounds)); err != nil {
+			log.Printf("provenance tracker disabled: %v", err)
+		} else {
+			provenanceSink = tracker
+			provenance.Subscribe(roundEvents, tracker)
+			peerVerifier.SetProvenance(tracker)
+			handler.SetProvenanceTracker(tracker)
+		}
+	}
+	// Independent federations served side by side on
+	// /api/{federation}/updates, sharing only this host's identity.
+	// Aggregator tiers ingest their members' updates through them.
+	ids := strings.TrimSpace(os.Getenv("MOHAWK_FEDERATIONS"))
+	if ids == "" && founding != nil {
+		ids = founding.FederationID
+	}
+	if ids == "" && nodeRole != role.Edge {
+		ids = protocol.DefaultFederation
+	}
+	var federations *federation.Registry
+	if ids != "" {
+		if registry, err := newFederationRegistry(conf.NodeID, ids, provenanceSink, roundEvents, faultModel, topology, modelSigner, validators, founding, capabilities); err != nil {
+			log.Printf("federations disabled: %v", err)
+		} else {
+			federations = registry
+			handler.SetFederationRegistry(registry)
+		}
+	}
+	if epochs != nil && federations != nil {
+		for _, id := range federations.IDs() {
+			if f, err := federations.Get(id); err == nil {
+				f.SetSummaryCheck(epochs.checkSummary)
+			}
+		}
+	}
+	if err := applyShardBudgets(privacyBudgets, nodeRole, conf.NodeID, federations, founding); err != nil {
+		log.Printf("shard privacy budgets not applied: %v", err)
+	}
+	if err := startAutoRollback(handler, roundEvents, nodeRole, conf.NodeID, coordinator, modelStore, federations); err != nil {
+		log.Printf("auto rollback disabled: %v", err)
+	}
+	// Verifier modules approved per campaign by the federation's members,
+	// proposed, voted, committed, and served to nodes on /api/modules.
+	// Edges install their campaign's latest module from their regional
+	// aggregator's catalog and report it in their heartbeats.
+	if nodeRole != role.Edge {
+		if catalog, err := newModuleCatalog(conf.NodeID, federations, faultModel, topology); err != nil {
+			log.Printf("module catalog disabled: %v", err)
+		} else {
+			handler.SetModuleCatalog(catalog, moduledist.NewRollout(catalog, moduledist.DefaultRolloutPolicy()))
+		}
+	} else if installer, err := startModuleInstaller(supervisor, aggregatorTransport, runner, observeVerification); err != nil {
+		log.Printf("module installer disabled: %v", err)
+	} else {
+		moduleInstaller = installer
+	}
+	var batchTuner *batch.AutoTuner
+	if nodeRole != role.Edge {
+		if tuner, err := newBatchTunerFromEnv(collector); err != nil {
+			log.Printf("batch tuner disabled: %v", err)
+		} else {
+			batchTuner = tuner
+			handler.SetBatchTuner(tuner)
+		}
+	}
+	var benchmark func() error
+	if verifyErr == nil {
+		benchmark = func() error {
+			_, err := runner.Verify(context.Background(), mockProof)
+			return err
+		}
+	}
+	reporter := startCapabilityReporter(supervisor, conf.NodeID, benchmark)
+	var uploads *upload.Policy
+	if policy, err := newUploadPolicyFromEnv(); err != nil {
+		log.Printf("upload policy disabled: %v", err)
+	} else {
+		uploads = policy
+		constraints := policy.Constraints()
+		log.Printf(
+			"Node %s upload policy (max_bytes_per_day=%d unmetered_only=%t min_battery=%.2f)",
+			conf.NodeID,
+			constraints.MaxBytesPerDay,
+			constraints.UnmeteredOnly,
+			constraints.MinBattery,
+		)
+	}
+	if err := startRole(supervisor, nodeRole, conf.NodeID, federations, modelStore, workScheduler, reporter, batchTuner, aggregatorTransport, genesisDigest, stampStatus, uploads); err != nil {
+		crashHandler.Fatal(fmt.Errorf("start %s role: %w", nodeRole, err))
+	}
+
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 	handler.RegisterRoutes(mux)
@@ -155,9 +701,1780 @@ func main() {
 		WriteTimeout:      15 * time.Second,
 		IdleTimeout:       60 * time.Second,
 	}
-	if err := server.ListenAndServe(); err != nil { // #nosec G706 -- listen address is sanitized before logging
-		log.Fatalf("API server failed: %v", err)
+	if err := supervisor.Register(lifecycle.Spec{
+		Name: "api",
+		Component: lifecycle.Funcs{
+			StartFunc: func(context.Context) error {
+				if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) { // #nosec G706 -- listen address is sanitized before logging
+					return err
+				}
+				return nil
+			},
+			StopFunc: server.Shutdown,
+		},
+	}); err != nil {
+		crashHandler.Fatal(fmt.Errorf("register API server: %w", err))
+	}
+	if err := supervisor.Start(context.Background()); err != nil {
+		crashHandler.Fatal(fmt.Errorf("start node: %w", err))
+	}
+	status := <-apiFailed
+	crashHandler.Fatal(fmt.Errorf("API server failed: %s", status.LastError))
+}
+
+// startIslandRejoin runs Island Mode against the aggregator, under
+// supervisor, and backfills missed rounds into the local model store
+// whenever the node returns online. It returns the initial backfill, for
+// the caller to run in its own goroutine.
+func startIslandRejoin(supervisor *lifecycle.Supervisor, aggregatorURL string, store *modeldist.ModelStore, transport *role.Transport) (*island.Manager, func()) {
+	probe := transport.Client(2 * time.Second)
+	healthURL := strings.TrimRight(aggregatorURL, "/") + "/health"
+	islandMgr := island.NewManager(
+		parseDurationEnv("MOHAWK_ISLAND_CHECK_INTERVAL", 10*time.Second),
+		parsePositiveIntEnv("MOHAWK_ISLAND_MAX_CACHED", 100),
+		func() bool {
+			resp, err := probe.Get(healthURL) // #nosec G107 -- aggregator URL is operator configuration
+			if err != nil {
+				return false
+			}
+			// Reading the body off returns the connection to the pool, so
+			// the probe keeps the submission path's connection warm.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+			_ = resp.Body.Close()
+			return resp.StatusCode == http.StatusOK
+		},
+	)
+
+	watchdog := island.DefaultWatchdogConfig()
+	watchdog.TransitionDeadline = parseDurationEnv("MOHAWK_ISLAND_TRANSITION_DEADLINE", watchdog.TransitionDeadline)
+	watchdog.StallTimeout = parseDurationEnv("MOHAWK_ISLAND_SYNC_STALL_TIMEOUT", watchdog.StallTimeout)
+	islandMgr.SetWatchdog(watchdog)
+
+	backfill := modeldist.NewBackfillClient(aggregatorURL, store)
+	backfill.HTTPClient = transport.Client(backfill.HTTPClient.Timeout)
+	backfill.MaxFullGap = parsePositiveIntEnv("MOHAWK_BACKFILL_MAX_FULL_GAP", modeldist.DefaultMaxFullBackfill)
+	negotiator := island.NewRejoinNegotiator(islandMgr, nil, backfill, 2*time.Minute)
+	negotiator.SetRoundSource(store.LatestRound)
+	negotiator.Attach()
+	if err := supervisor.Register(lifecycle.Spec{Name: "island", Component: lifecycle.Service(islandMgr.Start, islandMgr.Stop)}); err != nil {
+		log.Printf("island mode disabled: %v", err)
+	}
+
+	return islandMgr, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		if round, err := negotiator.Negotiate(ctx); err != nil {
+			log.Printf("warning: initial round backfill failed: %v", err)
+		} else {
+			log.Printf("backfilled committed rounds through %d", round)
+		}
+	}
+}
+
+// applyShardBudgets puts an aggregator tier's federation under the shard
+// privacy budgets. A global aggregator charges one round to every shard
+// contributing to each global aggregation, refusing the aggregation once a
+// shard's allocation is spent; a regional aggregator accounts its releases
+// with its own shard's instance. Aggregators map to shards by the
+// genesis layout, or are their shards' IDs without one.
+func applyShardBudgets(budgets *privacy.BudgetRegistry, nodeRole role.Role, nodeID string, federations *federation.Registry, founding *genesis.Genesis) error {
+	if budgets == nil || federations == nil || nodeRole == role.Edge {
+		return nil
+	}
+	f, err := federations.Get(federations.IDs()[0])
+	if err != nil {
+		return err
+	}
+	regions := map[string]string{}
+	if founding != nil {
+		regions = founding.Regions()
+	}
+	shardOf := func(nodeID string) string {
+		if shardID, ok := regions[nodeID]; ok {
+			return shardID
+		}
+		return nodeID
+	}
+	if nodeRole == role.Global {
+		f.SetAggregationGate(func(round int, nodeIDs []string) error {
+			shardIDs := make([]string, 0, len(nodeIDs))
+			for _, nodeID := range nodeIDs {
+				shardIDs = append(shardIDs, shardOf(nodeID))
+			}
+			return budgets.BeginGlobalRound(round, shardIDs)
+		})
+		return nil
+	}
+	dp, err := budgets.Shard(shardOf(nodeID))
+	if err != nil {
+		return err
+	}
+	f.Privacy = dp
+	return nil
+}
+
+// newModuleCatalog returns the catalog through which the members of this
+// aggregator's first federation approve verifier modules. It votes on a
+// coordinator of its own, so module votes never hold up a training round,
+// over the federation's membership and under the node's fault model.
+// Votes close after MOHAWK_MODULE_VOTE_TIMEOUT, default 10m.
+func newModuleCatalog(nodeID string, federations *federation.Registry, model faultmodel.Model, topology faultmodel.Topology) (*moduledist.Catalog, error) {
+	if federations == nil {
+		return nil, fmt.Errorf("module catalog needs a federation")
+	}
+	f, err := federations.Get(federations.IDs()[0])
+	if err != nil {
+		return nil, err
+	}
+	coordinator := consensus.NewCoordinator(nodeID, 1, parseDurationEnv("MOHAWK_MODULE_VOTE_TIMEOUT", 10*time.Minute))
+	if err := coordinator.SetFaultModel(model, topology); err != nil {
+		return nil, err
+	}
+	coordinator.SetMembershipView(f.MembershipView())
+	return moduledist.NewCatalog(coordinator, nodeID), nil
+}
+
+// startModuleInstaller installs, under supervisor, the latest verifier
+// module committed for MOHAWK_MODULE_CAMPAIGN in the catalog of the
+// regional aggregator at MOHAWK_REGIONAL_URL, checking for a new one every
+// MOHAWK_MODULE_SYNC_INTERVAL (default 1m). Installed modules verify the
+// pool's proofs from then on, and their verifications are counted, with
+// observe's, for the heartbeat. It returns nil when either variable is
+// unset.
+func startModuleInstaller(supervisor *lifecycle.Supervisor, transport *role.Transport, pool *wasmVerifierPool, observe wasmhost.VerifyObserver) (*moduledist.Installer, error) {
+	campaignID := strings.TrimSpace(os.Getenv("MOHAWK_MODULE_CAMPAIGN"))
+	regionalURL := strings.TrimSpace(os.Getenv("MOHAWK_REGIONAL_URL"))
+	if campaignID == "" || regionalURL == "" {
+		return nil, nil
+	}
+	catalog := moduledist.NewCatalogClient(regionalURL, transport.Client(10*time.Second))
+	modules := wasmhost.NewRegistry()
+	installer := moduledist.NewInstaller(campaignID, catalog, moduledist.NewFetcher(transport.Client(time.Minute), 0), modules)
+	modules.SetObserver(func(sample monitoring.VerificationSample) {
+		observe(sample)
+		installer.ObserveVerification(sample)
+	})
+	pool.SetModules(modules)
+
+	interval := parseDurationEnv("MOHAWK_MODULE_SYNC_INTERVAL", time.Minute)
+	sync := func(ctx context.Context) {
+		latest, ok, err := catalog.Latest(ctx, campaignID)
+		if err != nil {
+			log.Printf("module sync: %v", err)
+			return
+		}
+		if !ok || latest.HaltReason != "" || latest.Digest == installer.ActiveDigest() {
+			return
+		}
+		if err := installer.Install(ctx, latest.Digest); err != nil {
+			log.Printf("module %s not installed: %v", sanitizeLogValue(latest.Digest), err)
+			return
+		}
+		log.Printf("installed verifier module %s for campaign %s", sanitizeLogValue(latest.Digest), sanitizeLogValue(campaignID))
+	}
+	if err := supervisor.Register(lifecycle.Spec{
+		Name: "modules",
+		Component: lifecycle.Loop(func(ctx context.Context) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				sync(ctx)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}),
+	}); err != nil {
+		return nil, err
+	}
+	return installer, nil
+}
+
+// startAutoRollback serves the automatic rollback engine's actions and,
+// on a global aggregator, feeds it the rounds its first federation commits
+// into store, judged by the loss the federation's members reported and
+// the exclusions in the federation's manifests.
+func startAutoRollback(handler *api.Handler, bus *events.Bus, nodeRole role.Role, nodeID string, coordinator *consensus.Coordinator, store *modeldist.ModelStore, federations *federation.Registry) error {
+	var f *federation.Federation
+	var detector rollback.Detector
+	if nodeRole == role.Global && federations != nil {
+		var err error
+		if f, err = federations.Get(federations.IDs()[0]); err != nil {
+			return err
+		}
+		detector = rollback.ManifestDetector{Store: f.ModelStore}
+	}
+	autoRollback, err := rollback.NewAutoRollback(nodeID, rollback.DefaultPolicy(), coordinator, store, detector)
+	if err != nil {
+		return err
+	}
+	autoRollback.SetEvents(bus)
+	handler.SetAutoRollback(autoRollback)
+	if f != nil {
+		rollback.Follow(bus, autoRollback, f.ID, func(_ string, round int) (float64, bool) {
+			return meanLoss(f.Metrics, f.ID, round)
+		})
+	}
+	return nil
+}
+
+// meanLoss averages the training loss the federation's members reported
+// with their updates for round.
+func meanLoss(metrics *monitoring.Collector, federationID string, round int) (float64, bool) {
+	page := metrics.QueryMetrics(monitoring.MetricQuery{
+		Types:  []monitoring.MetricType{monitoring.MetricLoss},
+		Labels: map[string]string{"federation": federationID, "round": strconv.Itoa(round)},
+	})
+	if len(page.Metrics) == 0 {
+		return 0, false
+	}
+	total := 0.0
+	for _, metric := range page.Metrics {
+		total += metric.Value
+	}
+	return total / float64(len(page.Metrics)), true
+}
+
+// startCrashHandler writes a shutdown snapshot under MOHAWK_CRASH_DIR when
+// the node fails fatally or is terminated, after reporting the snapshot the
+// previous run left behind, if any. The returned recovery manager stamps
+// that snapshot's ID on the first heartbeat.
+func startCrashHandler(nodeID string, logs *crash.LogRing, coordinator *consensus.Coordinator, aggregator *consensus.DistributedAggregator, islandMgr *island.Manager) (*crash.Handler, *island.RecoveryManager) {
+	dir := strings.TrimSpace(os.Getenv("MOHAWK_CRASH_DIR"))
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "mohawk-crash")
+	}
+	recovery := island.NewRecoveryManager(nil, islandMgr, "")
+	if _, err := recovery.DetectShutdownSnapshot(dir); err != nil {
+		log.Printf("warning: shutdown snapshot check failed: %v", err)
+	}
+
+	sources := crash.Sources{
+		Consensus:    coordinator.ProposalStates,
+		PendingBatch: aggregator.PendingModels,
+		RoundBudget:  aggregator.ActiveBudget,
+	}
+	if islandMgr != nil {
+		sources.Island = func() crash.IslandState {
+			cached, _ := islandMgr.GetCachedUpdateStats()
+			return crash.IslandState{Mode: islandMgr.CurrentMode().String(), CachedUpdates: cached}
+		}
+	}
+	handler := crash.NewHandler(dir, nodeID, logs, sources)
+	handler.WatchSignals(os.Interrupt, syscall.SIGTERM)
+	return handler, recovery
+}
+
+// startFailover runs this node as the failover primary or standby
+// MOHAWK_FAILOVER_ROLE names; unset runs neither. MOHAWK_FAILOVER_PEERS
+// lists the other nodes as node=url pairs: a primary's standbys, or a
+// standby's primary, named by MOHAWK_FAILOVER_PRIMARY, and the peers its
+// FailoverNotice is announced to. A standby signs its notice with the
+// hex-encoded ed25519 seed in MOHAWK_FAILOVER_KEY_FILE and takes over
+// after MOHAWK_FAILOVER_HEARTBEAT_TIMEOUT without a heartbeat; a primary
+// leads MOHAWK_FAILOVER_EPOCH, honors notices signed by the hex-encoded
+// public key MOHAWK_FAILOVER_TRUSTED_KEY, and heartbeats and replicates
+// its commits every MOHAWK_FAILOVER_INTERVAL.
+func startFailover(supervisor *lifecycle.Supervisor, nodeID string, coordinator *consensus.Coordinator, islandMgr *island.Manager, transport *role.Transport, fragmenter *p2p.Fragmenter, receiver *p2p.Receiver) error {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("MOHAWK_FAILOVER_ROLE")))
+	if mode == "" {
+		return nil
+	}
+	peers, err := parsePeerURLs("MOHAWK_FAILOVER_PEERS")
+	if err != nil {
+		return err
+	}
+	if len(peers) == 0 {
+		return fmt.Errorf("MOHAWK_FAILOVER_PEERS lists no peers")
+	}
+	wire, err := newPeerTransport(nodeID, transport, fragmenter, peers)
+	if err != nil {
+		return err
+	}
+	interval := parseDurationEnv("MOHAWK_FAILOVER_INTERVAL", time.Second)
+
+	var run func(ctx context.Context)
+	switch mode {
+	case "primary":
+		raw, err := hex.DecodeString(strings.TrimSpace(os.Getenv("MOHAWK_FAILOVER_TRUSTED_KEY")))
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return fmt.Errorf("MOHAWK_FAILOVER_TRUSTED_KEY must be a hex-encoded %d-byte public key", ed25519.PublicKeySize)
+		}
+		standbys := make([]string, 0, len(peers))
+		for id := range peers {
+			standbys = append(standbys, id)
+		}
+		sort.Strings(standbys)
+		primary := failover.NewPrimary(nodeID, uint64(parsePositiveIntEnv("MOHAWK_FAILOVER_EPOCH", 1)), coordinator, wire, standbys...)
+		primary.SetTrustedKey(ed25519.PublicKey(raw))
+		receiver.Handle(failover.TopicNotice, func(_ context.Context, _ string, payload []byte) error {
+			return primary.HandleMessage(failover.TopicNotice, payload)
+		})
+		run = func(ctx context.Context) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				err := primary.Heartbeat()
+				if err == nil {
+					err = primary.ReplicateCommits()
+				}
+				if errors.Is(err, failover.ErrSuperseded) {
+					log.Printf("failover: %v", err)
+					return
+				}
+				if err != nil {
+					log.Printf("failover: replication failed: %v", err)
+				}
+			}
+		}
+	case "standby":
+		primaryID := strings.TrimSpace(os.Getenv("MOHAWK_FAILOVER_PRIMARY"))
+		if _, ok := peers[primaryID]; !ok {
+			return fmt.Errorf("MOHAWK_FAILOVER_PRIMARY %q is not in MOHAWK_FAILOVER_PEERS", primaryID)
+		}
+		keyFile := strings.TrimSpace(os.Getenv("MOHAWK_FAILOVER_KEY_FILE"))
+		if keyFile == "" {
+			return fmt.Errorf("a standby needs MOHAWK_FAILOVER_KEY_FILE")
+		}
+		rawSeed, err := os.ReadFile(keyFile) // #nosec G304 -- operator-supplied key path
+		if err != nil {
+			return fmt.Errorf("read failover key: %w", err)
+		}
+		seed, err := hex.DecodeString(strings.TrimSpace(string(rawSeed)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return fmt.Errorf("failover key must be a hex-encoded %d-byte seed", ed25519.SeedSize)
+		}
+		var announce []string
+		for id := range peers {
+			if id != primaryID {
+				announce = append(announce, id)
+			}
+		}
+		sort.Strings(announce)
+		standby, err := failover.NewStandby(nodeID, primaryID, ed25519.NewKeyFromSeed(seed), failover.StandbyConfig{
+			HeartbeatTimeout: parseDurationEnv("MOHAWK_FAILOVER_HEARTBEAT_TIMEOUT", failover.DefaultHeartbeatTimeout),
+			Announce:         announce,
+		}, coordinator, islandMgr, wire)
+		if err != nil {
+			return err
+		}
+		for _, topic := range []string{failover.TopicHeartbeat, failover.TopicReplication} {
+			receiver.Handle(topic, func(_ context.Context, _ string, payload []byte) error {
+				return standby.HandleMessage(topic, payload)
+			})
+		}
+		run = func(ctx context.Context) { standby.Run(ctx, interval) }
+	default:
+		return fmt.Errorf("MOHAWK_FAILOVER_ROLE %q is not primary or standby", mode)
+	}
+	return registerPeerLoop(supervisor, "failover", wire, run)
+}
+
+// startVoteSetGossip feeds the vote sets peers gossip on
+// consensus.TopicVoteSet to coordinator's equivocation detector and, when
+// MOHAWK_EQUIVOCATION_PEERS lists peers as node=url pairs, gossips this
+// node's open vote sets to them every MOHAWK_EQUIVOCATION_GOSSIP_INTERVAL.
+func startVoteSetGossip(supervisor *lifecycle.Supervisor, nodeID string, coordinator *consensus.Coordinator, receiver *p2p.Receiver, transport *role.Transport, fragmenter *p2p.Fragmenter) error {
+	receiver.Handle(consensus.TopicVoteSet, func(_ context.Context, from string, payload []byte) error {
+		var votes []consensus.Vote
+		if err := json.Unmarshal(payload, &votes); err != nil {
+			return fmt.Errorf("%w: vote set from %s: %v", p2p.ErrMalformedPayload, from, err)
+		}
+		coordinator.ObserveVoteSet(votes)
+		return nil
+	})
+
+	peers, err := parsePeerURLs("MOHAWK_EQUIVOCATION_PEERS")
+	if err != nil || len(peers) == 0 {
+		return err
+	}
+	wire, err := newPeerTransport(nodeID, transport, fragmenter, peers)
+	if err != nil {
+		return err
+	}
+	peerIDs := make([]string, 0, len(peers))
+	for id := range peers {
+		peerIDs = append(peerIDs, id)
+	}
+	sort.Strings(peerIDs)
+	interval := parseDurationEnv("MOHAWK_EQUIVOCATION_GOSSIP_INTERVAL", 5*time.Second)
+	return registerPeerLoop(supervisor, "vote-set-gossip", wire, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if coordinator.GetState() != consensus.Voting {
+				continue
+			}
+			for _, state := range coordinator.ProposalStates() {
+				votes, err := coordinator.VoteSet(state.ProposalID)
+				if err != nil || len(votes) == 0 {
+					continue
+				}
+				payload, err := json.Marshal(votes)
+				if err != nil {
+					log.Printf("vote set gossip: %v", err)
+					continue
+				}
+				if _, err := wire.Broadcast(peerIDs, p2p.OutboundMessage{Topic: consensus.TopicVoteSet, Payload: payload, Priority: p2p.PriorityVote}); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// newPeerTransport sends as nodeID to peers, which maps node IDs to node
+// API URLs, over transport's connections and with the upstream token,
+// splitting messages with fragmenter when it is set.
+func newPeerTransport(nodeID string, transport *role.Transport, fragmenter *p2p.Fragmenter, peers map[string]string) (*p2p.Transport, error) {
+	token, err := loadRoleToken()
+	if err != nil {
+		return nil, err
+	}
+	sender := p2p.NewHTTPSender(nodeID, token, transport.Client(10*time.Second), peers)
+	wire := p2p.NewTransport(sender, p2p.QueueConfig{Depth: p2p.DefaultQueueDepth, DrainTimeout: p2p.DefaultDrainTimeout})
+	if fragmenter != nil {
+		wire.SetFragmenter(fragmenter)
+	}
+	return wire, nil
+}
+
+// startFragmentation splits peer messages larger than
+// MOHAWK_P2P_FRAGMENT_MTU bytes, default 1 MiB, into fragments tagged under
+// the pairwise key identity derives with each peer, and has receiver
+// reassemble the fragments peers send, dropping partial messages after
+// MOHAWK_P2P_REASSEMBLY_TIMEOUT. Without an identity messages travel
+// whole.
+func startFragmentation(supervisor *lifecycle.Supervisor, identity *crypto.SecureChannel, verifier *p2p.Verifier, receiver *p2p.Receiver) (*p2p.Fragmenter, error) {
+	if identity == nil {
+		return nil, nil
+	}
+	keys := pairwiseKeySource(identity, verifier, identity.FragmentMACKey)
+	fragmenter, err := p2p.NewFragmenter(parsePositiveIntEnv("MOHAWK_P2P_FRAGMENT_MTU", 1<<20), keys)
+	if err != nil {
+		return nil, err
+	}
+	timeout := parseDurationEnv("MOHAWK_P2P_REASSEMBLY_TIMEOUT", p2p.DefaultReassemblyTimeout)
+	reassembler := p2p.NewReassembler(keys, timeout, 0)
+	reassembler.SetDropHandler(func(peerID string, messageID uint64, received, total int) {
+		log.Printf("warning: dropped message %x from %s with %d of %d fragments", messageID, sanitizeLogValue(peerID), received, total)
+	})
+	if err := supervisor.Register(lifecycle.Spec{
+		Name: "fragment-reassembly",
+		Component: lifecycle.Loop(func(ctx context.Context) {
+			ticker := time.NewTicker(timeout)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					reassembler.Expire()
+				}
+			}
+		}),
+	}); err != nil {
+		return nil, err
+	}
+	receiver.SetReassembler(reassembler)
+	return fragmenter, nil
+}
+
+// pairwiseKeySource returns the key derive yields for a peer, registering
+// the identity key the peer registered with verifier on first use.
+func pairwiseKeySource(identity *crypto.SecureChannel, verifier *p2p.Verifier, derive func(peerID string) ([]byte, error)) func(peerID string) ([]byte, error) {
+	return func(peerID string) ([]byte, error) {
+		key, err := derive(peerID)
+		if !errors.Is(err, crypto.ErrPeerKeyUnknown) {
+			return key, err
+		}
+		peer, ok := verifier.Peer(peerID)
+		if !ok {
+			return nil, err
+		}
+		publicKey, err := crypto.ImportPublicKey(peer.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := identity.RegisterPeer(peerID, publicKey); err != nil {
+			return nil, err
+		}
+		return derive(peerID)
+	}
+}
+
+// registerPeerLoop supervises run as name, with wire registered as its
+// own component so the transport outlives restarts of the loop and drains
+// its queues once the loop has stopped.
+func registerPeerLoop(supervisor *lifecycle.Supervisor, name string, wire *p2p.Transport, run func(ctx context.Context)) error {
+	if err := supervisor.Register(lifecycle.Spec{
+		Name: name + "-transport",
+		Component: lifecycle.Funcs{
+			StartFunc: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+			StopFunc: func(context.Context) error { return wire.Close() },
+		},
+	}); err != nil {
+		return err
+	}
+	return supervisor.Register(lifecycle.Spec{
+		Name:      name,
+		Component: lifecycle.Loop(run),
+		DependsOn: []string{name + "-transport"},
+	})
+}
+
+// parsePeerURLs parses the node=url pairs the environment variable key
+// lists, separated by commas.
+func parsePeerURLs(key string) (map[string]string, error) {
+	peers := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		nodeID, url, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		nodeID, url = strings.TrimSpace(nodeID), strings.TrimSpace(url)
+		if !ok || nodeID == "" || url == "" {
+			return nil, fmt.Errorf("%s entry %q is not node=url", key, pair)
+		}
+		peers[nodeID] = url
+	}
+	return peers, nil
+}
+
+// newArchiverFromEnv enables the backup endpoints when
+// MOHAWK_BACKUP_KEY_FILE holds a hex-encoded ed25519 seed. Archives from
+// another host are accepted when MOHAWK_BACKUP_TRUSTED_KEY names its
+// hex-encoded public key.
+func newArchiverFromEnv(nodeID string, coordinator *consensus.Coordinator, store *modeldist.ModelStore, islandMgr *island.Manager) (*backup.Archiver, error) {
+	keyFile := strings.TrimSpace(os.Getenv("MOHAWK_BACKUP_KEY_FILE"))
+	if keyFile == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(keyFile) // #nosec G304 -- operator-supplied key path
+	if err != nil {
+		return nil, fmt.Errorf("read backup key: %w", err)
+	}
+	seed, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("backup key must be a hex-encoded %d-byte seed", ed25519.SeedSize)
+	}
+
+	components := []backup.Component{
+		backup.ModelStoreComponent{Store: store},
+		backup.ConsensusComponent{Coordinator: coordinator},
+	}
+	if islandMgr != nil {
+		components = append(components, backup.QueueComponent{Manager: islandMgr})
+	}
+	archiver, err := backup.NewArchiver(nodeID, ed25519.NewKeyFromSeed(seed), components...)
+	if err != nil {
+		return nil, err
+	}
+	if trusted := strings.TrimSpace(os.Getenv("MOHAWK_BACKUP_TRUSTED_KEY")); trusted != "" {
+		key, err := hex.DecodeString(trusted)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("MOHAWK_BACKUP_TRUSTED_KEY must be a hex-encoded %d-byte public key", ed25519.PublicKeySize)
+		}
+		archiver.SetTrustedKey(ed25519.PublicKey(key))
+	}
+	return archiver, nil
+}
+
+// newFederationRegistry serves each federation in the comma-separated ids
+// with components sized like this node's own, publishing their round
+// lifecycle to bus, emitting update lifecycle events to sink when it is
+// set, signing committed rounds with signer, and requiring validators'
+// signed approvals on them when set. The model spec is the
+// genesis's, when founding is set, unless MOHAWK_MODEL_SPEC names another.
+// Rounds select participants by the manifests in capabilities.
+// Aggregators run the detection plugins MOHAWK_DETECTION_CONFIG approves.
+func newFederationRegistry(nodeID string, ids string, sink provenance.Sink, bus *events.Bus, model faultmodel.Model, topology faultmodel.Topology, signer modeldist.ModelSigner, validators *modeldist.Validators, founding *genesis.Genesis, capabilities *scheduler.CapabilityRegistry) (*federation.Registry, error) {
+	spec, err := loadModelSpec()
+	if spec == nil && err == nil && founding != nil {
+		spec = &founding.ModelSpec
+	}
+	if err != nil {
+		return nil, err
+	}
+	probation, err := newProbationConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	centralDP, err := newCentralDPConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	committeePolicies, err := loadCommitteePolicies()
+	if err != nil {
+		return nil, err
+	}
+	detection, err := loadDetectionConfig()
+	if err != nil {
+		return nil, err
+	}
+	checkpoints := newAggregationCheckpointsFromEnv()
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{
+		HostID:            nodeID,
+		ModelStoreRounds:  parsePositiveIntEnv("MOHAWK_MODEL_STORE_ROUNDS", 256),
+		MinVerifications:  parsePositiveIntEnv("MOHAWK_MIN_VERIFICATIONS", 3),
+		CommitteePolicies: committeePolicies,
+		FaultModel:        model,
+		Topology:          topology,
+		ModelSpec:         spec,
+		Signer:            signer,
+		Validators:        validators,
+		Probation:         probation,
+		CentralDP:         centralDP,
+		Checkpoints:       checkpoints,
+		Capabilities:      capabilities,
+		Detection:         detection,
+	}))
+	for _, id := range strings.Split(ids, ",") {
+		f, err := registry.Create(strings.TrimSpace(id))
+		if err != nil {
+			return nil, err
+		}
+		f.SetEvents(bus)
+		if sink != nil {
+			f.SetProvenance(sink)
+		}
+	}
+	return registry, nil
+}
+
+// loadDetectionConfig loads the Byzantine detection plugins every
+// federation's aggregator runs from the JSON file MOHAWK_DETECTION_CONFIG
+// names; see wasmhost.DetectionFile. Only plugins whose digests the file
+// approves are loaded. Unset, aggregators use the norm detector alone.
+func loadDetectionConfig() (*batch.DetectionConfig, error) {
+	path := strings.TrimSpace(os.Getenv("MOHAWK_DETECTION_CONFIG"))
+	if path == "" {
+		return nil, nil
+	}
+	return wasmhost.LoadDetectionConfig(context.Background(), path)
+}
+
+// loadModelSpec reads the model spec every federation registers from the
+// JSON file MOHAWK_MODEL_SPEC names, or none when unset.
+func loadModelSpec() (*protocol.ModelSpec, error) {
+	path := strings.TrimSpace(os.Getenv("MOHAWK_MODEL_SPEC"))
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path) // #nosec G304 -- operator-supplied spec path
+	if err != nil {
+		return nil, fmt.Errorf("read model spec: %w", err)
+	}
+	var spec protocol.ModelSpec
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", protocol.ErrInvalidModelSpec, path, err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// newFaultModelFromEnv reads MOHAWK_FAULT_MODEL (classic33, the default,
+// or hierarchical55) and whether updates pass through intermediate
+// aggregation tiers from MOHAWK_MULTI_TIER, refusing a model unsafe for
+// that topology.
+func newFaultModelFromEnv() (faultmodel.Model, faultmodel.Topology, error) {
+	model, err := faultmodel.Parse(os.Getenv("MOHAWK_FAULT_MODEL"))
+	if err != nil {
+		return "", faultmodel.Topology{}, err
+	}
+	topology := faultmodel.Topology{MultiTier: os.Getenv("MOHAWK_MULTI_TIER") == "true"}
+	if err := model.Validate(topology); err != nil {
+		return "", faultmodel.Topology{}, err
+	}
+	return model, topology, nil
+}
+
+// newWorkSchedulerConfigFromEnv reads the work scheduler's CPU budget from
+// MOHAWK_WORK_WORKERS and its class shares from MOHAWK_WORK_SHARES, a
+// comma-separated list such as "verification=0.4,training=0.4,sync=0.1,
+// admin=0.1". Classes left out of the list are not scheduled.
+func newWorkSchedulerConfigFromEnv() (scheduler.WorkSchedulerConfig, error) {
+	config := scheduler.DefaultWorkSchedulerConfig()
+	config.Workers = parseIntEnv("MOHAWK_WORK_WORKERS", 0)
+	config.Window = parseDurationEnv("MOHAWK_WORK_SHARE_WINDOW", scheduler.DefaultShareWindow)
+	raw := strings.TrimSpace(os.Getenv("MOHAWK_WORK_SHARES"))
+	if raw == "" {
+		return config, nil
+	}
+	depth := parseIntEnv("MOHAWK_WORK_QUEUE_DEPTH", scheduler.DefaultWorkQueueDepth)
+	config.Classes = make(map[scheduler.WorkClass]scheduler.ClassConfig)
+	for _, entry := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return config, fmt.Errorf("MOHAWK_WORK_SHARES entry %q is not class=share", sanitizeLogValue(entry))
+		}
+		share, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return config, fmt.Errorf("MOHAWK_WORK_SHARES share for %s: %w", sanitizeLogValue(name), err)
+		}
+		config.Classes[scheduler.WorkClass(strings.TrimSpace(name))] = scheduler.ClassConfig{Share: share, Depth: depth}
+	}
+	return config, nil
+}
+
+// wasmVerifierPool verifies proofs on one wasm runner per verification
+// worker, or on the default module of its module registry once one is
+// installed. Every verification is pulled through the work scheduler's
+// verification class, so it runs only within that class's CPU share, and
+// is timed by its runner or module for the verification SLO.
+type wasmVerifierPool struct {
+	scheduler *scheduler.WorkScheduler
+	runners   chan *wasmhost.Runner
+	modules   atomic.Pointer[wasmhost.Registry]
+}
+
+func newWasmVerifierPool(ctx context.Context, wasmBin []byte, work *scheduler.WorkScheduler, observer wasmhost.VerifyObserver) (*wasmVerifierPool, error) {
+	size := work.Workers(scheduler.ClassVerification)
+	if size == 0 {
+		return nil, fmt.Errorf("work scheduler has no %s class", scheduler.ClassVerification)
+	}
+	pool := &wasmVerifierPool{scheduler: work, runners: make(chan *wasmhost.Runner, size)}
+	for i := 0; i < size; i++ {
+		runner, err := wasmhost.NewRunner(ctx, wasmBin)
+		if err != nil {
+			_ = pool.Close(ctx)
+			return nil, err
+		}
+		runner.SetObserver(observer)
+		pool.runners <- runner
+	}
+	return pool, nil
+}
+
+// Verify queues proof for verification and waits for the verdict.
+func (p *wasmVerifierPool) Verify(ctx context.Context, proof []byte) (bool, error) {
+	var verified bool
+	err := p.scheduler.Do(ctx, scheduler.ClassVerification, func(taskCtx context.Context) error {
+		if modules := p.modules.Load(); modules != nil {
+			if host := modules.Default(); host != nil {
+				var err error
+				verified, err = host.Verify(taskCtx, proof)
+				return err
+			}
+		}
+		runner := <-p.runners
+		defer func() { p.runners <- runner }()
+		var err error
+		verified, err = runner.Verify(taskCtx, proof)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return verified, nil
+}
+
+// SetModules makes the pool verify on modules' default module whenever it
+// has one.
+func (p *wasmVerifierPool) SetModules(modules *wasmhost.Registry) {
+	p.modules.Store(modules)
+}
+
+// Close closes every idle runner and the module registry.
+func (p *wasmVerifierPool) Close(ctx context.Context) error {
+	var firstErr error
+	if modules := p.modules.Load(); modules != nil {
+		firstErr = modules.Close(ctx)
+	}
+	for {
+		select {
+		case runner := <-p.runners:
+			if err := runner.Close(ctx); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		default:
+			return firstErr
+		}
+	}
+}
+
+// newVerificationSLOFromEnv tracks Wasm verification latency against the
+// Theorem 5 bound, MOHAWK_WASM_SLO_BOUND (default 10ms), alerting when
+// fewer than MOHAWK_WASM_SLO_TARGET (default 0.99) of the last
+// MOHAWK_WASM_SLO_WINDOW warm verifications finish within it.
+func newVerificationSLOFromEnv(collector *monitoring.Collector) *monitoring.VerificationSLO {
+	cfg := monitoring.VerificationSLOConfig{
+		Bound:     parseDurationEnv("MOHAWK_WASM_SLO_BOUND", monitoring.DefaultVerificationBound),
+		Window:    parsePositiveIntEnv("MOHAWK_WASM_SLO_WINDOW", monitoring.DefaultVerificationWindow),
+		Collector: collector,
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_WASM_SLO_TARGET")); raw != "" {
+		target, err := strconv.ParseFloat(raw, 64)
+		if err != nil || target <= 0 || target > 1 {
+			log.Printf("MOHAWK_WASM_SLO_TARGET %q is not in (0, 1]; using %g", raw, monitoring.DefaultVerificationTarget)
+		} else {
+			cfg.Target = target
+		}
+	}
+	return monitoring.NewVerificationSLO(cfg)
+}
+
+// newAdmissionPolicyFromEnv reads the minimum capabilities a registering
+// node must report. Unset variables impose no constraint.
+func newAdmissionPolicyFromEnv() scheduler.AdmissionPolicy {
+	policy := scheduler.AdmissionPolicy{
+		MinCPUCount:     parseIntEnv("MOHAWK_ADMISSION_MIN_CPUS", 0),
+		MinMemoryBytes:  int64(parseIntEnv("MOHAWK_ADMISSION_MIN_MEMORY_BYTES", 0)),
+		ModelParameters: int64(parseIntEnv("MOHAWK_ADMISSION_MODEL_PARAMETERS", 0)),
+		RequireTPM:      os.Getenv("MOHAWK_ADMISSION_REQUIRE_TPM") == "true",
+	}
+	if strings.TrimSpace(os.Getenv("MOHAWK_ADMISSION_MAX_VERIFY_LATENCY")) != "" {
+		policy.MaxWasmVerifyLatency = parseDurationEnv("MOHAWK_ADMISSION_MAX_VERIFY_LATENCY", 0)
+	}
+	return policy
+}
+
+// startCapabilityReporter probes this node's capabilities now and, under
+// supervisor, every MOHAWK_CAPABILITY_PROBE_INTERVAL, logging the manifest
+// whenever it changes materially. benchmark times one wasm verification;
+// nil skips it.
+func startCapabilityReporter(supervisor *lifecycle.Supervisor, nodeID string, benchmark func() error) *capability.Reporter {
+	reporter := capability.NewReporter(capability.Probe{
+		NodeID:               nodeID,
+		BandwidthBytesPerSec: int64(parseIntEnv("MOHAWK_BANDWIDTH_BYTES_PER_SEC", 0)),
+		Verify:               benchmark,
+	}, func(manifest *protocol.CapabilityManifest) error {
+		log.Printf(
+			"Node %s capabilities (cpu_class=%s cpus=%d memory_bytes=%d wasm_simd=%t tpm=%t wasm_verify=%s batch=%d)",
+			nodeID,
+			manifest.CPUClass,
+			manifest.CPUCount,
+			manifest.MemoryBytes,
+			manifest.WasmSIMD,
+			manifest.TPMPresent,
+			manifest.WasmVerifyLatency(),
+			wasmhost.BatchSize(manifest, wasmhost.DefaultBatchBudget),
+		)
+		return nil
+	})
+	if _, err := reporter.Check(); err != nil {
+		log.Printf("capability probe failed: %v", err)
+	}
+	interval := parseDurationEnv("MOHAWK_CAPABILITY_PROBE_INTERVAL", 5*time.Minute)
+	if err := supervisor.Register(lifecycle.Spec{
+		Name:      "capabilities",
+		Component: lifecycle.Loop(func(ctx context.Context) { reporter.Run(ctx, interval) }),
+	}); err != nil {
+		log.Printf("capability reprobing disabled: %v", err)
+	}
+	return reporter
+}
+
+// startRole runs nodeRole's round loop under supervisor, rejoining after a
+// crash. Edges train
+// against the regional aggregator at MOHAWK_REGIONAL_URL, when set, in the
+// scheduler's training class; regionals aggregate their federation and
+// report upstream to the global aggregator at MOHAWK_UPSTREAM_URL, their
+// summaries certified by the members of shard MOHAWK_REGIONAL_SHARD when
+// set; the
+// global aggregator commits into store, which the API serves. Aggregators
+// run consensus over the first of MOHAWK_FEDERATIONS, default
+// protocol.DefaultFederation, and wait for MOHAWK_ROUND_EXPECTED updates,
+// default every member, per round, or the trigger tuner sets while it is
+// enabled. Edges stamp their status heartbeats with stamp and choose each
+// round's upload tier with uploads, when set.
+func startRole(supervisor *lifecycle.Supervisor, nodeRole role.Role, nodeID string, federations *federation.Registry, store *modeldist.ModelStore, work *scheduler.WorkScheduler, reporter *capability.Reporter, tuner *batch.AutoTuner, transport *role.Transport, genesisDigest string, stamp func(*protocol.StatusUpdate), uploads *upload.Policy) error {
+	token, err := loadRoleToken()
+	if err != nil {
+		return err
+	}
+	upstreamSigner, err := loadUpstreamSigner()
+	if err != nil {
+		return err
+	}
+	newClient := func(baseURL string) *role.Client {
+		client := role.NewClient(baseURL, token)
+		client.TrustedSigner = upstreamSigner
+		client.Validators = store.Validators()
+		client.Signer = store.Signer()
+		client.GenesisDigest = genesisDigest
+		client.HTTPClient = transport.Client(client.HTTPClient.Timeout)
+		return client
+	}
+	interval := parseDurationEnv("MOHAWK_ROUND_POLL_INTERVAL", time.Second)
+
+	var (
+		join func(context.Context) (int, error)
+		run  func(context.Context, int) error
+	)
+	switch nodeRole {
+	case role.Edge:
+		regionalURL := strings.TrimSpace(os.Getenv("MOHAWK_REGIONAL_URL"))
+		if regionalURL == "" {
+			return nil
+		}
+		edge := role.NewEdge(role.EdgeConfig{
+			NodeID:       nodeID,
+			Federation:   os.Getenv("MOHAWK_REGIONAL_FEDERATION"),
+			Regional:     newClient(regionalURL),
+			Trainer:      newEdgeTrainer(nodeID, work),
+			Store:        store,
+			Cache:        newModelCacheFromEnv(),
+			Dim:          parsePositiveIntEnv("MOHAWK_TRAIN_DIM", 8),
+			PollInterval: interval,
+			Capabilities: reporter.Last(),
+			Stamp:        stamp,
+			Upload:       uploads,
+		})
+		join, run = edge.Join, edge.Run
+	case role.Regional, role.Global:
+		if federations == nil {
+			return fmt.Errorf("%s role needs a federation", nodeRole)
+		}
+		f, err := federations.Get(federations.IDs()[0])
+		if err != nil {
+			return err
+		}
+		tier := role.TierConfig{
+			NodeID:        nodeID,
+			Federation:    f,
+			Expected:      parseIntEnv("MOHAWK_ROUND_EXPECTED", 0),
+			CollectWindow: parseDurationEnv("MOHAWK_ROUND_COLLECT_WINDOW", role.DefaultCollectWindow),
+			VoteWindow:    parseDurationEnv("MOHAWK_ROUND_VOTE_WINDOW", role.DefaultVoteWindow),
+			PollInterval:  interval,
+			Tuner:         tuner,
+		}
+		if nodeRole == role.Global {
+			global := role.NewGlobal(role.GlobalConfig{TierConfig: tier, Store: store})
+			join = func(context.Context) (int, error) { return store.LatestRound() + 1, nil }
+			run = global.Run
+			break
+		}
+		upstreamURL := strings.TrimSpace(os.Getenv("MOHAWK_UPSTREAM_URL"))
+		if upstreamURL == "" {
+			return fmt.Errorf("regional role needs MOHAWK_UPSTREAM_URL")
+		}
+		regionalCfg := role.RegionalConfig{
+			TierConfig:         tier,
+			Upstream:           newClient(upstreamURL),
+			UpstreamFederation: os.Getenv("MOHAWK_UPSTREAM_FEDERATION"),
+			Store:              store,
+			Capabilities:       reporter.Last(),
+			ShardID:            strings.TrimSpace(os.Getenv("MOHAWK_REGIONAL_SHARD")),
+		}
+		if regionalCfg.ShardID != "" {
+			regionalCfg.Certify = role.CertifyShard(f)
+		}
+		regional := role.NewRegional(regionalCfg)
+		join, run = regional.Join, regional.Run
+	}
+
+	return supervisor.Register(lifecycle.Spec{
+		Name:      "role",
+		DependsOn: []string{"capabilities"},
+		Component: lifecycle.Funcs{StartFunc: func(ctx context.Context) error {
+			var round int
+			for {
+				var err error
+				if round, err = join(ctx); err == nil {
+					break
+				}
+				log.Printf("%s %s could not join, retrying: %v", nodeRole, sanitizeLogValue(nodeID), err)
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(5 * time.Second):
+				}
+			}
+			log.Printf("%s %s starting at round %d", nodeRole, sanitizeLogValue(nodeID), round)
+			if err := run(ctx, round); err != nil && ctx.Err() == nil {
+				return fmt.Errorf("%s %s stopped: %w", nodeRole, sanitizeLogValue(nodeID), err)
+			}
+			return nil
+		}},
+	})
+}
+
+// newModelCacheFromEnv opens the edge model cache in MOHAWK_MODEL_CACHE_DIR,
+// keeping MOHAWK_MODEL_CACHE_ROUNDS rounds within MOHAWK_MODEL_CACHE_BYTES,
+// or returns nil when the directory is unset or unusable.
+func newModelCacheFromEnv() *modeldist.ModelCache {
+	dir := strings.TrimSpace(os.Getenv("MOHAWK_MODEL_CACHE_DIR"))
+	if dir == "" {
+		return nil
+	}
+	cache, err := modeldist.NewModelCache(dir,
+		parsePositiveIntEnv("MOHAWK_MODEL_CACHE_ROUNDS", modeldist.DefaultCacheRounds),
+		int64(parsePositiveIntEnv("MOHAWK_MODEL_CACHE_BYTES", modeldist.DefaultCacheBytes)))
+	if err != nil {
+		log.Printf("model cache disabled: %v", err)
+		return nil
+	}
+	return cache
+}
+
+// loadRoleToken reads the token a tier presents one tier up from
+// MOHAWK_UPSTREAM_TOKEN_FILE, or none when unset.
+func loadRoleToken() (string, error) {
+	path := strings.TrimSpace(os.Getenv("MOHAWK_UPSTREAM_TOKEN_FILE"))
+	if path == "" {
+		return "", nil
+	}
+	raw, err := os.ReadFile(path) // #nosec G304 -- operator-supplied token path
+	if err != nil {
+		return "", fmt.Errorf("read upstream token: %w", err)
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+// loadAggregatorPins opens the TLS pin store at MOHAWK_AGGREGATOR_PIN_FILE,
+// default aggregator-pins.json in the node's state directory next to
+// MOHAWK_IDENTITY_KEY_FILE, which the store creates at mode 0700, and
+// approves the signed rotation records listed in
+// MOHAWK_AGGREGATOR_PIN_ROTATION_FILE. A node with neither that calls an
+// aggregator is refused, since pins in a shared temp directory could be
+// planted by another user. MOHAWK_AGGREGATOR_PIN_OVERRIDE=true re-pins
+// aggregators whose key changed, for provisioning;
+// MOHAWK_AGGREGATOR_PIN_ONLY=true skips CA verification so self-signed
+// aggregators are trusted by pin alone. It returns nil when
+// MOHAWK_AGGREGATOR_PINNING=false turns pinning off, or when the node calls
+// no aggregator and has nowhere to keep pins.
+func loadAggregatorPins() (*crypto.PinStore, error) {
+	if os.Getenv("MOHAWK_AGGREGATOR_PINNING") == "false" {
+		return nil, nil
+	}
+	path := strings.TrimSpace(os.Getenv("MOHAWK_AGGREGATOR_PIN_FILE"))
+	if identityPath := strings.TrimSpace(os.Getenv("MOHAWK_IDENTITY_KEY_FILE")); path == "" && identityPath != "" {
+		path = filepath.Join(filepath.Dir(identityPath), "aggregator-pins.json")
+	}
+	if path == "" {
+		for _, name := range []string{"MOHAWK_AGGREGATOR_URL", "MOHAWK_REGIONAL_URL", "MOHAWK_UPSTREAM_URL"} {
+			if strings.TrimSpace(os.Getenv(name)) != "" {
+				return nil, fmt.Errorf("aggregator pins: %s is set but neither MOHAWK_AGGREGATOR_PIN_FILE nor MOHAWK_IDENTITY_KEY_FILE names where to keep pins", name)
+			}
+		}
+		return nil, nil
+	}
+	pins, err := crypto.NewPinStore(path)
+	if err != nil {
+		return nil, fmt.Errorf("aggregator pins: %w", err)
+	}
+	if os.Getenv("MOHAWK_AGGREGATOR_PIN_OVERRIDE") == "true" {
+		log.Printf("warning: aggregator pin override on; changed aggregator keys are re-pinned")
+		pins.SetOverride(true)
+	}
+	rotationPath := strings.TrimSpace(os.Getenv("MOHAWK_AGGREGATOR_PIN_ROTATION_FILE"))
+	if rotationPath == "" {
+		return pins, nil
+	}
+	raw, err := os.ReadFile(rotationPath) // #nosec G304 -- operator-supplied rotation path
+	if err != nil {
+		return nil, fmt.Errorf("read aggregator pin rotations: %w", err)
+	}
+	var rotations []crypto.PinRotation
+	if err := json.Unmarshal(raw, &rotations); err != nil {
+		return nil, fmt.Errorf("decode aggregator pin rotations: %w", err)
+	}
+	for _, rotation := range rotations {
+		if err := pins.AddRotation(rotation); err != nil {
+			log.Printf("warning: aggregator pin rotation refused: %v", err)
+		}
+	}
+	return pins, nil
+}
+
+// newAggregatorTransport returns the connection pool for calling
+// aggregators, pinned by pins unless it is nil, resuming TLS sessions
+// through identity's session cache when the node has an identity. TCP
+// keep-alives follow the island health probe interval,
+// MOHAWK_ISLAND_CHECK_INTERVAL, so probes and keep-alives alike hold NAT
+// mappings open; MOHAWK_AGGREGATOR_KEEPALIVE overrides it.
+// MOHAWK_AGGREGATOR_MAX_CONNS caps connections per aggregator and
+// MOHAWK_AGGREGATOR_IDLE_TIMEOUT closes idle ones.
+func newAggregatorTransport(pins *crypto.PinStore, identity *crypto.SecureChannel) (*role.Transport, error) {
+	cfg := role.DefaultTransportConfig()
+	cfg.KeepAlive = parseDurationEnv("MOHAWK_AGGREGATOR_KEEPALIVE", parseDurationEnv("MOHAWK_ISLAND_CHECK_INTERVAL", 10*time.Second))
+	cfg.MaxConnsPerHost = parsePositiveIntEnv("MOHAWK_AGGREGATOR_MAX_CONNS", cfg.MaxConnsPerHost)
+	cfg.MaxIdleConnsPerHost = min(cfg.MaxIdleConnsPerHost, cfg.MaxConnsPerHost)
+	cfg.IdleTimeout = parseDurationEnv("MOHAWK_AGGREGATOR_IDLE_TIMEOUT", cfg.IdleTimeout)
+
+	base := &tls.Config{MinVersion: tls.VersionTLS12}
+	if identity != nil {
+		base.ClientSessionCache = identity.GetTLSConfig().ClientSessionCache
+	}
+	if pins != nil && os.Getenv("MOHAWK_AGGREGATOR_PIN_ONLY") == "true" {
+		base.InsecureSkipVerify = true // #nosec G402 -- the pin authenticates the aggregator
+	}
+	return role.NewTransport("aggregator", cfg, base, pins)
+}
+
+// loadNodeIdentity loads this node's identity key from the
+// passphrase-encrypted file MOHAWK_IDENTITY_KEY_FILE, creating it on first
+// start, so the node keeps its identity, reputation, and registration
+// across restarts. The passphrase comes from MOHAWK_IDENTITY_PASSPHRASE_FILE
+// or else MOHAWK_IDENTITY_PASSPHRASE. A new identity is a P-256 key unless
+// MOHAWK_IDENTITY_ALGORITHM names another (ed25519); an existing one keeps
+// its algorithm. MOHAWK_IDENTITY_MIGRATE=true first encrypts a plaintext
+// PEM key at that path in place. A key file other users can read is
+// refused unless MOHAWK_IDENTITY_ALLOW_INSECURE_PERMS=true. It returns nil
+// when no key file is configured.
+func loadNodeIdentity() (*crypto.SecureChannel, error) {
+	path := strings.TrimSpace(os.Getenv("MOHAWK_IDENTITY_KEY_FILE"))
+	if path == "" {
+		return nil, nil
+	}
+	passphrase := []byte(os.Getenv("MOHAWK_IDENTITY_PASSPHRASE"))
+	if passphraseFile := strings.TrimSpace(os.Getenv("MOHAWK_IDENTITY_PASSPHRASE_FILE")); passphraseFile != "" {
+		raw, err := os.ReadFile(passphraseFile) // #nosec G304 -- operator-supplied passphrase path
+		if err != nil {
+			return nil, fmt.Errorf("read identity passphrase: %w", err)
+		}
+		passphrase = []byte(strings.TrimRight(string(raw), "\r\n"))
+	}
+	store, err := crypto.NewIdentityStore(path, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("identity store: %w", err)
+	}
+	if err := store.SetAlgorithm(protocol.AlgorithmID(strings.TrimSpace(os.Getenv("MOHAWK_IDENTITY_ALGORITHM")))); err != nil {
+		return nil, fmt.Errorf("identity algorithm: %w", err)
+	}
+	if allow, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("MOHAWK_IDENTITY_ALLOW_INSECURE_PERMS"))); allow {
+		log.Printf("warning: accepting identity key %s whatever its permissions", sanitizeLogValue(path))
+		store.SetAllowInsecurePermissions(true)
+	}
+	if migrate, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("MOHAWK_IDENTITY_MIGRATE"))); migrate {
+		migrated, err := store.MigratePlaintext()
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("migrate identity: %w", err)
+		}
+		if migrated {
+			log.Printf("Encrypted plaintext identity key %s in place", sanitizeLogValue(path))
+		}
+	}
+	identity, err := store.LoadOrCreate()
+	if err != nil {
+		return nil, fmt.Errorf("load identity: %w", err)
+	}
+	publicKey, err := identity.ExportPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := crypto.PublicKeyFingerprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Node identity %s (%s) loaded from %s", fingerprint, identity.Algorithm(), sanitizeLogValue(path))
+	return identity, nil
+}
+
+// loadGenesis reads the genesis MOHAWK_GENESIS_FILE names and checks it
+// against the digest MOHAWK_GENESIS_DIGEST pins and for its founders'
+// threshold signatures. A node may pin a digest without holding the
+// genesis; it then only refuses aggregators started from another.
+func loadGenesis() (*genesis.Genesis, string, error) {
+	pinned := strings.ToLower(strings.TrimSpace(os.Getenv("MOHAWK_GENESIS_DIGEST")))
+	path := strings.TrimSpace(os.Getenv("MOHAWK_GENESIS_FILE"))
+	if path == "" {
+		return nil, pinned, nil
+	}
+	if pinned == "" {
+		return nil, "", fmt.Errorf("MOHAWK_GENESIS_FILE %s needs a digest pinned in MOHAWK_GENESIS_DIGEST", path)
+	}
+	founding, err := genesis.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := founding.Verify(pinned); err != nil {
+		return nil, "", fmt.Errorf("genesis %s: %w", path, err)
+	}
+	log.Printf("Genesis %s of federation %s verified", pinned, founding.FederationID)
+	return founding, pinned, nil
+}
+
+// loadUpstreamSigner reads the PEM identity key of the aggregator one tier
+// up from MOHAWK_UPSTREAM_SIGNER_KEY_FILE. When set, models that key did
+// not sign are refused. Unset trusts the commit certificate alone.
+func loadUpstreamSigner() ([]byte, error) {
+	path := strings.TrimSpace(os.Getenv("MOHAWK_UPSTREAM_SIGNER_KEY_FILE"))
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path) // #nosec G304 -- operator-supplied key path
+	if err != nil {
+		return nil, fmt.Errorf("read upstream signer key: %w", err)
+	}
+	if _, err := crypto.PublicKeyFingerprint(raw); err != nil {
+		return nil, fmt.Errorf("upstream signer key: %w", err)
+	}
+	return raw, nil
+}
+
+// loadCommitteePolicies reads the per-artifact verification committee
+// policies from the JSON file MOHAWK_COMMITTEE_POLICY_PATH names; see
+// p2p.ParseCommitteePolicies. Unset keeps the built-in defaults.
+func loadCommitteePolicies() (map[p2p.ArtifactType]p2p.CommitteePolicy, error) {
+	path := strings.TrimSpace(os.Getenv("MOHAWK_COMMITTEE_POLICY_PATH"))
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path) // #nosec G304 -- operator-supplied policy path
+	if err != nil {
+		return nil, fmt.Errorf("read committee policies: %w", err)
+	}
+	return p2p.ParseCommitteePolicies(raw)
+}
+
+// loadValidators reads the validator set every commit certificate this
+// node accepts must be signed by from the JSON file
+// MOHAWK_VALIDATORS_FILE; see modeldist.LoadValidators. Unset, certificates
+// are checked for the model they commit to but not for who approved it.
+func loadValidators() (*modeldist.Validators, error) {
+	path := strings.TrimSpace(os.Getenv("MOHAWK_VALIDATORS_FILE"))
+	if path == "" {
+		return nil, nil
+	}
+	return modeldist.LoadValidators(path)
+}
+
+// loadModelSigner loads the identity key this node signs the models it
+// commits and serves with, a PEM P-256 or Ed25519 private key, from
+// MOHAWK_MODEL_SIGNING_KEY_FILE. Unset, the node signs with its persistent
+// identity when it has one, and not at all otherwise.
+func loadModelSigner(identity *crypto.SecureChannel) (modeldist.ModelSigner, error) {
+	path := strings.TrimSpace(os.Getenv("MOHAWK_MODEL_SIGNING_KEY_FILE"))
+	if path == "" {
+		if identity == nil {
+			return nil, nil
+		}
+		return identity, nil
+	}
+	raw, err := os.ReadFile(path) // #nosec G304 -- operator-supplied key path
+	if err != nil {
+		return nil, fmt.Errorf("read model signing key: %w", err)
+	}
+	channel, err := crypto.LoadSecureChannel(raw)
+	if err != nil {
+		return nil, fmt.Errorf("model signing key: %w", err)
+	}
+	return channel, nil
+}
+
+// newEdgeTrainer trains the toy quadratic objective toward a target derived
+// from nodeID, as MOHAWK_TRAIN_SAMPLES samples, in the scheduler's training
+// class so proof verification cannot starve it.
+func newEdgeTrainer(nodeID string, work *scheduler.WorkScheduler) role.Trainer {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(nodeID))
+	rng := simrand.New(int64(hash.Sum64() >> 1)) // #nosec G115 -- the shift keeps the seed non-negative
+	target := make([]float64, parsePositiveIntEnv("MOHAWK_TRAIN_DIM", 8))
+	for i := range target {
+		target[i] = rng.NormFloat64()
+	}
+	step := role.QuadraticTrainer(target, 0.5, parsePositiveIntEnv("MOHAWK_TRAIN_SAMPLES", 100))
+	return func(ctx context.Context, round int, base []float64) ([]float64, int, error) {
+		var (
+			local   []float64
+			samples int
+		)
+		err := work.Do(ctx, scheduler.ClassTraining, func(ctx context.Context) error {
+			var err error
+			local, samples, err = step(ctx, round, base)
+			return err
+		})
+		return local, samples, err
+	}
+}
+
+// newSamplingConfigFromEnv builds the collector's telemetry sampling:
+// MOHAWK_METRICS_SAMPLE_RATES lists per-type rates as "gradient=100,loss=10",
+// MOHAWK_METRICS_ADAPTIVE_THRESHOLD caps the observations a second kept per
+// type, and MOHAWK_METRICS_RESERVOIR sizes each type's value reservoir. It
+// returns nil when none is set.
+func newSamplingConfigFromEnv() (*monitoring.SamplingConfig, error) {
+	config := &monitoring.SamplingConfig{
+		ReservoirSize: parseIntEnv("MOHAWK_METRICS_RESERVOIR", 0),
+		MaxRate:       parseIntEnv("MOHAWK_METRICS_MAX_SAMPLE_RATE", monitoring.DefaultMaxSampleRate),
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_METRICS_ADAPTIVE_THRESHOLD")); raw != "" {
+		threshold, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_METRICS_ADAPTIVE_THRESHOLD must be a number: %w", err)
+		}
+		config.AdaptiveThreshold = threshold
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_METRICS_SAMPLE_RATES")); raw != "" {
+		config.Rates = make(map[monitoring.MetricType]int)
+		for _, entry := range strings.Split(raw, ",") {
+			name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok {
+				return nil, fmt.Errorf("MOHAWK_METRICS_SAMPLE_RATES entry %q is not type=rate", sanitizeLogValue(entry))
+			}
+			rate, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("MOHAWK_METRICS_SAMPLE_RATES rate for %s: %w", sanitizeLogValue(name), err)
+			}
+			config.Rates[monitoring.MetricType(strings.TrimSpace(name))] = rate
+		}
+	}
+	if config.Rates == nil && config.AdaptiveThreshold <= 0 && config.ReservoirSize <= 0 {
+		return nil, nil
+	}
+	return config, nil
+}
+
+// newBatchTunerFromEnv builds the round trigger auto-tuner, bounded by
+// MOHAWK_BATCH_TUNER_MIN and MOHAWK_BATCH_TUNER_MAX, aiming at
+// MOHAWK_BATCH_TUNER_PERCENTILE over MOHAWK_BATCH_TUNER_WINDOW rounds. It
+// starts off unless MOHAWK_BATCH_TUNER is true; /api/admin/batch_tuner
+// toggles it at runtime.
+func newBatchTunerFromEnv(collector *monitoring.Collector) (*batch.AutoTuner, error) {
+	cfg := batch.AutoTunerConfig{
+		Min:       parsePositiveIntEnv("MOHAWK_BATCH_TUNER_MIN", 1),
+		Max:       parsePositiveIntEnv("MOHAWK_BATCH_TUNER_MAX", 10000),
+		Window:    parsePositiveIntEnv("MOHAWK_BATCH_TUNER_WINDOW", batch.DefaultTunerWindow),
+		Collector: collector,
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_BATCH_TUNER_PERCENTILE")); raw != "" {
+		percentile, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_BATCH_TUNER_PERCENTILE must be a number: %w", err)
+		}
+		cfg.Percentile = percentile
+	}
+	tuner, err := batch.NewAutoTuner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if enabled, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("MOHAWK_BATCH_TUNER"))); enabled {
+		tuner.SetEnabled(true)
+	}
+	return tuner, nil
+}
+
+// newVoteMiddlewareFromEnv builds the vote checks for this deployment: a
+// dedup window dropping redelivered votes, remembered per proposal for
+// MOHAWK_VOTE_DEDUP_TTL up to MOHAWK_VOTE_DEDUP_MAX_ENTRIES votes, vote
+// authentication by auth when set, the default chain, then a per-node rate
+// limit of MOHAWK_VOTE_RATE_LIMIT votes per MOHAWK_VOTE_RATE_WINDOW when
+// set, then a reputation floor of MOHAWK_VOTE_REPUTATION_FLOOR when set.
+func newVoteMiddlewareFromEnv(coordinator *consensus.Coordinator, verifier *p2p.Verifier, auth *consensus.ShardVoteAuth, equivocation *consensus.EquivocationDetector) ([]consensus.VoteMiddleware, error) {
+	dedupConfig := consensus.DefaultVoteDedupConfig()
+	dedupConfig.TTL = parseDurationEnv("MOHAWK_VOTE_DEDUP_TTL", dedupConfig.TTL)
+	dedupConfig.MaxEntries = parseIntEnv("MOHAWK_VOTE_DEDUP_MAX_ENTRIES", dedupConfig.MaxEntries)
+	dedup, err := consensus.NewVoteDedup(dedupConfig)
+	if err != nil {
+		return nil, err
+	}
+	dedup.SetObserver(api.ObserveVoteDedup)
+	middleware := []consensus.VoteMiddleware{consensus.DedupCheck(dedup)}
+	if auth != nil {
+		middleware = append(middleware, consensus.VoteAuthCheck(auth))
+	}
+	if equivocation != nil {
+		middleware = append(middleware, consensus.EquivocationCheck(equivocation))
+	}
+	middleware = append(middleware, consensus.DefaultVoteChain(coordinator)...)
+	if limit := parseIntEnv("MOHAWK_VOTE_RATE_LIMIT", 0); limit > 0 {
+		middleware = append(middleware, consensus.RateLimit(limit, parseDurationEnv("MOHAWK_VOTE_RATE_WINDOW", time.Minute)))
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_VOTE_REPUTATION_FLOOR")); raw != "" {
+		floor, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_VOTE_REPUTATION_FLOOR must be a number: %w", err)
+		}
+		middleware = append(middleware, consensus.ReputationFloorCheck(verifier.Reputation, floor))
+	}
+	return middleware, nil
+}
+
+// newVoteAuthFromEnv authenticates the votes of this aggregator's shard
+// when MOHAWK_VOTE_AUTH names its mode, "signature" or "mac", and returns
+// nil otherwise. Signatures are checked over consensus.VoteSigningBytes
+// against the identity key a voter registered with verifier. In "mac" mode
+// a voter's pairwise key is derived from identity's session key with it,
+// registering its identity key on first use. The shard is the node's in
+// the genesis layout, or the node's own ID without one.
+func newVoteAuthFromEnv(nodeID string, identity *crypto.SecureChannel, verifier *p2p.Verifier, founding *genesis.Genesis) (*consensus.ShardVoteAuth, error) {
+	raw := strings.TrimSpace(os.Getenv("MOHAWK_VOTE_AUTH"))
+	if raw == "" {
+		return nil, nil
+	}
+	mode := consensus.VoteAuthMode(raw)
+	if mode != consensus.VoteAuthSignature && mode != consensus.VoteAuthMAC {
+		return nil, fmt.Errorf("MOHAWK_VOTE_AUTH must be %q or %q, got %q", consensus.VoteAuthSignature, consensus.VoteAuthMAC, sanitizeLogValue(raw))
+	}
+	if mode == consensus.VoteAuthMAC && identity == nil {
+		return nil, fmt.Errorf("MAC vote authentication needs a node identity")
+	}
+	shardID := nodeID
+	if founding != nil {
+		if region, ok := founding.Regions()[nodeID]; ok {
+			shardID = region
+		}
+	}
+	auth := consensus.NewShardVoteAuth(shardID, mode, func(vote *consensus.Vote) error {
+		peer, ok := verifier.Peer(vote.NodeID)
+		if !ok {
+			return fmt.Errorf("%w: %s", p2p.ErrPeerNotFound, vote.NodeID)
+		}
+		return crypto.VerifyWithPublicKey(peer.PublicKey, consensus.VoteSigningBytes(vote), vote.Signature)
+	})
+	if mode == consensus.VoteAuthMAC {
+		auth.SetKeySource(pairwiseKeySource(identity, verifier, identity.VoteMACKey))
+	}
+	return auth, nil
+}
+
+// newTopologyFromEnv places nodes in regions for tiered round budgets:
+// MOHAWK_REGIONS lists node=region pairs separated by commas; otherwise
+// each genesis shard is a region. It returns nil when neither is set.
+func newTopologyFromEnv(founding *genesis.Genesis) (*scheduler.Topology, error) {
+	var regions map[string]string
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_REGIONS")); raw != "" {
+		regions = make(map[string]string)
+		for _, pair := range strings.Split(raw, ",") {
+			nodeID, region, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return nil, fmt.Errorf("MOHAWK_REGIONS entry %q must be node=region", pair)
+			}
+			regions[strings.TrimSpace(nodeID)] = strings.TrimSpace(region)
+		}
+	} else if founding != nil && len(founding.Shards) > 0 {
+		regions = founding.Regions()
+	} else {
+		return nil, nil
+	}
+	return scheduler.NewTopology(scheduler.DefaultLatencyPolicy(), regions)
+}
+
+// newShardConfigFromEnv returns the shard layout registering peers are
+// admitted into, or nil when MOHAWK_SHARD_COUNT is unset. Each shard
+// tolerates MOHAWK_SHARD_MAX_FAULTY faulty members, default one, under
+// model; MOHAWK_SHARD_MAX_MOVED_FRACTION bounds the nodes one epoch moves.
+func newShardConfigFromEnv(model faultmodel.Model) (*sharding.Config, error) {
+	count := parseIntEnv("MOHAWK_SHARD_COUNT", 0)
+	if count <= 0 {
+		return nil, nil
+	}
+	cfg := sharding.DefaultConfig()
+	cfg.Shards, cfg.FaultModel = count, model
+	cfg.MaxFaulty = parseIntEnv("MOHAWK_SHARD_MAX_FAULTY", cfg.MaxFaulty)
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_SHARD_MAX_MOVED_FRACTION")); raw != "" {
+		fraction, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_SHARD_MAX_MOVED_FRACTION must be a number: %w", err)
+		}
+		cfg.MaxMovedFraction = fraction
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// shardEpochs is the global tier's ledger of shard membership epochs and
+// the regional aggregator that submits each shard's summaries.
+type shardEpochs struct {
+	ledger      *certchain.EpochLedger
+	model       faultmodel.Model
+	aggregators map[string]string
+	regionals   map[string]bool
+}
+
+// newShardEpochsFromEnv returns a ledger committing shard epochs with
+// identity for the shards MOHAWK_SHARD_AGGREGATORS assigns regional
+// aggregators, as comma-separated shard=node pairs, or nil when it is
+// unset.
+func newShardEpochsFromEnv(identity *crypto.SecureChannel, model faultmodel.Model) (*shardEpochs, error) {
+	raw := strings.TrimSpace(os.Getenv("MOHAWK_SHARD_AGGREGATORS"))
+	if raw == "" {
+		return nil, nil
+	}
+	if identity == nil {
+		return nil, fmt.Errorf("shard epochs need a node identity")
+	}
+	epochs := &shardEpochs{model: model, aggregators: make(map[string]string), regionals: make(map[string]bool)}
+	for _, pair := range strings.Split(raw, ",") {
+		shardID, nodeID, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		shardID, nodeID = strings.TrimSpace(shardID), strings.TrimSpace(nodeID)
+		if !ok || shardID == "" || nodeID == "" {
+			return nil, fmt.Errorf("MOHAWK_SHARD_AGGREGATORS entry %q is not shard=node", pair)
+		}
+		epochs.aggregators[shardID] = nodeID
+		epochs.regionals[nodeID] = true
+	}
+	ledger, err := certchain.NewEpochLedger(identity)
+	if err != nil {
+		return nil, err
+	}
+	epochs.ledger = ledger
+	return epochs, nil
+}
+
+// commit commits shardID's next epoch with members, if the shard has a
+// regional aggregator.
+func (e *shardEpochs) commit(shardID string, members []protocol.ShardMember) error {
+	aggregator, ok := e.aggregators[shardID]
+	if !ok {
+		return nil
+	}
+	next := uint64(1)
+	if current, ok := e.ledger.Epoch(shardID); ok {
+		next = current.Epoch + 1
+	}
+	_, err := e.ledger.Commit(protocol.MembershipEpochRecord{
+		ShardID:    shardID,
+		Epoch:      next,
+		Aggregator: aggregator,
+		FaultModel: string(e.model),
+		Members:    members,
+	})
+	return err
+}
+
+// checkSummary requires a certificate chain of the summaries regional
+// aggregators submit, admitting every other node's update unchecked.
+func (e *shardEpochs) checkSummary(update *protocol.ModelUpdate) error {
+	if !e.regionals[update.NodeID] {
+		return nil
+	}
+	return e.ledger.CheckSummary(update)
+}
+
+// startShardRebalancer ends a shard assignment epoch every interval under
+// supervisor, logging the nodes it moved.
+func startShardRebalancer(supervisor *lifecycle.Supervisor, assigner *sharding.Assigner, interval time.Duration) {
+	if err := supervisor.Register(lifecycle.Spec{
+		Name: "shard-rebalancer",
+		Component: lifecycle.Loop(func(ctx context.Context) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					report, err := assigner.Rebalance()
+					if err != nil {
+						log.Printf("shard rebalance: %v", err)
+						continue
+					}
+					if len(report.Moves) > 0 || report.Pending > 0 {
+						log.Printf("shard epoch %d: moved %d nodes, %d pending", report.Epoch, len(report.Moves), report.Pending)
+					}
+				}
+			}
+		}),
+	}); err != nil {
+		log.Printf("shard rebalancing disabled: %v", err)
+	}
+}
+
+// newProbationConfigFromEnv returns the admission probation new nodes
+// serve, or nil when MOHAWK_PROBATION_ROUNDS, the verified-honest rounds
+// to graduate, is unset. MOHAWK_PROBATION_VOTE_WEIGHT and
+// MOHAWK_PROBATION_UPDATE_WEIGHT override the default weights.
+func newProbationConfigFromEnv() (*consensus.ProbationConfig, error) {
+	rounds := parseIntEnv("MOHAWK_PROBATION_ROUNDS", 0)
+	if rounds <= 0 {
+		return nil, nil
+	}
+	cfg := consensus.DefaultProbationConfig()
+	cfg.RequiredRounds = rounds
+	for key, weight := range map[string]*float64{
+		"MOHAWK_PROBATION_VOTE_WEIGHT":   &cfg.VoteWeight,
+		"MOHAWK_PROBATION_UPDATE_WEIGHT": &cfg.UpdateWeight,
+	} {
+		raw := strings.TrimSpace(os.Getenv(key))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number: %w", key, err)
+		}
+		*weight = value
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// newCentralDPConfigFromEnv returns the central DP every federation's
+// aggregates are noised under, or nil when MOHAWK_CENTRAL_DP_COHORT, the
+// participants a round is planned for, is unset. MOHAWK_CENTRAL_DP_MIN_COHORT
+// overrides the fewest participants a round is released for, and
+// MOHAWK_CENTRAL_DP_EPSILON, MOHAWK_CENTRAL_DP_ALLOCATION, and
+// MOHAWK_CENTRAL_DP_CLIP_NORM the per-round ε, total ε, and clip norm.
+func newCentralDPConfigFromEnv() (*privacy.DPAggregatorConfig, error) {
+	cohort := parseIntEnv("MOHAWK_CENTRAL_DP_COHORT", 0)
+	if cohort <= 0 {
+		return nil, nil
+	}
+	cfg := privacy.DefaultDPAggregatorConfig()
+	cfg.ExpectedCohort = cohort
+	cfg.MinCohort = parsePositiveIntEnv("MOHAWK_CENTRAL_DP_MIN_COHORT", min(cfg.MinCohort, cohort))
+	for key, value := range map[string]*float64{
+		"MOHAWK_CENTRAL_DP_EPSILON":    &cfg.PerRound.Epsilon,
+		"MOHAWK_CENTRAL_DP_ALLOCATION": &cfg.Allocation.Epsilon,
+		"MOHAWK_CENTRAL_DP_CLIP_NORM":  &cfg.ClipNorm,
+	} {
+		raw := strings.TrimSpace(os.Getenv(key))
+		if raw == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number: %w", key, err)
+		}
+		*value = parsed
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// newHealthSampler returns a loop feeding health, every
+// MOHAWK_HEALTH_INTERVAL (default 30s), the signals nothing pushes to it:
+// this node's reputation as its verifier records it, and the fullest work
+// queue with the usage of the filesystem holding MOHAWK_HEALTH_DISK_PATH
+// (default the working directory). Each pass re-evaluates health, so
+// status transitions are published without anyone polling
+// /api/health/detailed.
+func newHealthSampler(health *monitoring.HealthEvaluator, nodeID string, verifier *p2p.Verifier, work *scheduler.WorkScheduler) func(ctx context.Context) {
+	interval := parseDurationEnv("MOHAWK_HEALTH_INTERVAL", 30*time.Second)
+	diskPath := strings.TrimSpace(os.Getenv("MOHAWK_HEALTH_DISK_PATH"))
+	if diskPath == "" {
+		diskPath = "."
+	}
+	if _, err := monitoring.DiskUsage(diskPath); err != nil {
+		log.Printf("disk pressure disabled: %v", err)
+		diskPath = ""
+	}
+	sample := func() {
+		if score, ok := verifier.Reputation(nodeID); ok {
+			health.ObserveReputation(score)
+		}
+		var diskUsed float64
+		if diskPath != "" {
+			if used, err := monitoring.DiskUsage(diskPath); err == nil {
+				diskUsed = used
+			}
+		}
+		var depth, capacity int
+		for _, stats := range work.Stats() {
+			if capacity == 0 || stats.Depth*capacity > depth*stats.Capacity {
+				depth, capacity = stats.Depth, stats.Capacity
+			}
+		}
+		health.ObservePressure(diskUsed, depth, capacity)
+		health.Evaluate()
+	}
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			sample()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// newMetricsPrivatizerFromEnv returns the privatizer status heartbeats are
+// noised by before they leave the node, or nil when
+// MOHAWK_METRICS_DP_EPSILON is unset. MOHAWK_METRICS_DP_SENSITIVITY
+// overrides the unit sensitivity and MOHAWK_METRICS_DP_COUNT_BUCKET the
+// sample count bucket. Rounds stay exact unless
+// MOHAWK_METRICS_DP_ROUND_BUCKET widens them, since the aggregator settles
+// declared skips by round.
+func newMetricsPrivatizerFromEnv() (*privacy.MetricsPrivatizer, error) {
+	raw := strings.TrimSpace(os.Getenv("MOHAWK_METRICS_DP_EPSILON"))
+	if raw == "" {
+		return nil, nil
+	}
+	cfg := privacy.DefaultMetricsDPConfig()
+	cfg.RoundBucket = parsePositiveIntEnv("MOHAWK_METRICS_DP_ROUND_BUCKET", 1)
+	cfg.CountBucket = parsePositiveIntEnv("MOHAWK_METRICS_DP_COUNT_BUCKET", cfg.CountBucket)
+	epsilon, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("MOHAWK_METRICS_DP_EPSILON must be a number: %w", err)
+	}
+	cfg.Epsilon = epsilon
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_METRICS_DP_SENSITIVITY")); raw != "" {
+		sensitivity, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_METRICS_DP_SENSITIVITY must be a number: %w", err)
+		}
+		cfg.Sensitivity = sensitivity
+	}
+	return privacy.NewMetricsPrivatizer(cfg)
+}
+
+// newAggregationCheckpointsFromEnv checkpoints each federation's queued
+// updates under MOHAWK_AGGREGATION_CHECKPOINT_DIR, when set, so a restarted
+// aggregator resumes its uncommitted batches: after every
+// MOHAWK_AGGREGATION_CHECKPOINT_EVERY updates (default 1) and, when
+// MOHAWK_AGGREGATION_CHECKPOINT_INTERVAL is set, on the first update that
+// long after the last checkpoint.
+func newAggregationCheckpointsFromEnv() batch.CheckpointPolicy {
+	dir := strings.TrimSpace(os.Getenv("MOHAWK_AGGREGATION_CHECKPOINT_DIR"))
+	if dir == "" {
+		return batch.CheckpointPolicy{}
+	}
+	return batch.CheckpointPolicy{
+		Path:     dir,
+		Every:    parsePositiveIntEnv("MOHAWK_AGGREGATION_CHECKPOINT_EVERY", 1),
+		Interval: parseDurationEnv("MOHAWK_AGGREGATION_CHECKPOINT_INTERVAL", 0),
+	}
+}
+
+// newUploadPolicyFromEnv builds the policy that chooses each round's upload
+// tier. MOHAWK_UPLOAD_MAX_BYTES_PER_DAY caps daily upload bytes,
+// MOHAWK_UPLOAD_UNMETERED_ONLY skips rounds while MOHAWK_UPLOAD_METERED is
+// true, and MOHAWK_UPLOAD_MIN_BATTERY (a fraction) skips rounds while the
+// battery under MOHAWK_UPLOAD_BATTERY_PATH is low and not charging.
+func newUploadPolicyFromEnv() (*upload.Policy, error) {
+	constraints := upload.Constraints{
+		MaxBytesPerDay: int64(parseIntEnv("MOHAWK_UPLOAD_MAX_BYTES_PER_DAY", 0)),
+		UnmeteredOnly:  os.Getenv("MOHAWK_UPLOAD_UNMETERED_ONLY") == "true",
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_UPLOAD_MIN_BATTERY")); raw != "" {
+		level, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("MOHAWK_UPLOAD_MIN_BATTERY must be a fraction: %w", err)
+		}
+		constraints.MinBattery = level
+	}
+	policy, err := upload.NewPolicy(constraints)
+	if err != nil {
+		return nil, err
+	}
+	batteryPath := strings.TrimSpace(os.Getenv("MOHAWK_UPLOAD_BATTERY_PATH"))
+	if batteryPath == "" {
+		batteryPath = "/sys/class/power_supply/BAT0"
 	}
+	policy.SetBatteryProbe(upload.SysfsBattery(batteryPath))
+	metered := os.Getenv("MOHAWK_UPLOAD_METERED") == "true"
+	policy.SetNetworkProbe(func() bool { return metered })
+	return policy, nil
 }
 
 func sanitizeLogValue(v string) string {
@@ -203,7 +2520,7 @@ func parsePositiveIntEnv(key string, fallback int) int {
 	return parsed
 }
 
-func runTPMSyntheticBatch(verifier blockchain.ProofVerifier, total int, workers int) {
+func runTPMSyntheticBatch(crashes *crash.Handler, verifier blockchain.ProofVerifier, total int, workers int) {
 	if verifier == nil || total <= 0 || workers <= 0 {
 		return
 	}
@@ -220,7 +2537,7 @@ func runTPMSyntheticBatch(verifier blockchain.ProofVerifier, total int, workers
 	var wg sync.WaitGroup
 	for w := 0; w < workers; w++ {
 		wg.Add(1)
-		go func(workerID int) {
+		crashes.Go("tpm-synthetic-worker", func() {
 			defer wg.Done()
 			for i := range jobs {
 				nodeID := "synthetic-node-" + strconv.Itoa(i%64)
@@ -233,7 +2550,7 @@ func runTPMSyntheticBatch(verifier blockchain.ProofVerifier, total int, workers
 					},
 				})
 			}
-		}(w)
+		})
 	}
 
 	wg.Wait()
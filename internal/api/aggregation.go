@@ -0,0 +1,39 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"net/http"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+)
+
+// AggregationHistoryReader exposes the retained aggregation round history.
+type AggregationHistoryReader interface {
+	History() []consensus.AggregationRound
+}
+
+// SetAggregationHistory attaches the round history behind
+// /api/aggregation/rounds.
+func (h *Handler) SetAggregationHistory(reader AggregationHistoryReader) {
+	h.aggregationHistory = reader
+}
+
+// GetAggregationRounds lists the retained aggregation rounds, oldest first,
+// with outcome counts and duration and participant percentiles.
+func (h *Handler) GetAggregationRounds(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.aggregationHistory == nil {
+		http.Error(w, "aggregation history unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	rounds := h.aggregationHistory.History()
+	writeJSON(w, map[string]interface{}{
+		"rounds":  rounds,
+		"summary": consensus.SummarizeRounds(rounds),
+		"count":   len(rounds),
+	})
+}
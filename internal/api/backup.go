@@ -0,0 +1,77 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/backup"
+)
+
+// SetArchiver attaches the archiver behind /api/admin/backup and
+// /api/admin/restore.
+func (h *Handler) SetArchiver(archiver *backup.Archiver) {
+	h.archiver = archiver
+}
+
+func requireBackupAuth(w http.ResponseWriter, r *http.Request) bool {
+	return requireScopedAuth(w, r, "MOHAWK_API_BACKUP_ALLOWED_ROLES", "admin")
+}
+
+// GetBackup returns a signed backup archive of the node's federation state.
+func (h *Handler) GetBackup(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if !requireBackupAuth(w, r) {
+		return
+	}
+	if h.archiver == nil {
+		http.Error(w, "backup unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Buffer the archive so a failing component yields an error status
+	// rather than a truncated download.
+	var buf bytes.Buffer
+	manifest, err := h.archiver.Backup(&buf)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+manifest.NodeID+`-backup.tar"`)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// PostRestore restores a backup archive from the request body. With
+// ?partial=true intact components of a partially corrupt archive are
+// restored instead of refusing it.
+func (h *Handler) PostRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	if !requireBackupAuth(w, r) {
+		return
+	}
+	if h.archiver == nil {
+		http.Error(w, "restore unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	restore := h.archiver.Restore
+	if partial, _ := strconv.ParseBool(r.URL.Query().Get("partial")); partial {
+		restore = h.archiver.RestorePartial
+	}
+	report, err := restore(r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, report)
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+)
+
+// SetCampaignTracker attaches the campaign history behind
+// /api/convergence/campaigns.
+func (h *Handler) SetCampaignTracker(tracker *convergence.CampaignTracker) {
+	h.campaigns = tracker
+}
+
+// GetCampaigns lists every training campaign with its round count, loss,
+// and heterogeneity summary, oldest first.
+func (h *Handler) GetCampaigns(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.campaigns == nil {
+		http.Error(w, "campaign tracker unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	campaigns := h.campaigns.Campaigns()
+	writeJSON(w, map[string]interface{}{
+		"campaigns": campaigns,
+		"count":     len(campaigns),
+	})
+}
+
+// GetCampaignComparison aligns campaigns a and b round by round. threshold
+// is the loss the rounds-to-threshold summary counts towards; format=csv
+// exports the aligned series instead of JSON.
+func (h *Handler) GetCampaignComparison(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.campaigns == nil {
+		http.Error(w, "campaign tracker unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	a, b := strings.TrimSpace(query.Get("a")), strings.TrimSpace(query.Get("b"))
+	if a == "" || b == "" {
+		http.Error(w, "campaigns a and b are required", http.StatusBadRequest)
+		return
+	}
+	threshold := 0.0
+	if raw := strings.TrimSpace(query.Get("threshold")); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			http.Error(w, "invalid threshold", http.StatusBadRequest)
+			return
+		}
+		threshold = value
+	}
+
+	comparison, err := h.campaigns.CompareCampaigns(a, b, threshold)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	switch strings.ToLower(strings.TrimSpace(query.Get("format"))) {
+	case "", "json":
+		writeJSON(w, comparison)
+	case "csv":
+		var buf bytes.Buffer
+		if err := comparison.WriteCSV(&buf); err != nil {
+			http.Error(w, "failed to encode comparison", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-API-Version", "v1")
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+a+"-vs-"+b+`.csv"`)
+		_, _ = w.Write(buf.Bytes())
+	default:
+		http.Error(w, "format must be json or csv", http.StatusBadRequest)
+	}
+}
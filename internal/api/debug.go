@@ -0,0 +1,54 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"net/http"
+	// Importing net/http/pprof also registers its handlers on
+	// http.DefaultServeMux; no server importing this package serves that
+	// mux, so they are reachable only through ServeDebug.
+	"net/http/pprof" // #nosec G108 -- served only behind requireDebugAuth
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/profiling"
+)
+
+func requireDebugAuth(w http.ResponseWriter, r *http.Request) bool {
+	return requireScopedAuth(w, r, "MOHAWK_API_DEBUG_ALLOWED_ROLES", "admin")
+}
+
+// ServeDebug serves the runtime profiles of net/http/pprof under
+// /api/admin/debug/pprof/: the index at the root, and each profile, such
+// as heap or goroutine, at its name.
+func (h *Handler) ServeDebug(w http.ResponseWriter, r *http.Request) {
+	if !requireDebugAuth(w, r) {
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	switch profile := r.PathValue("profile"); profile {
+	case "":
+		pprof.Index(w, r)
+	case "cmdline":
+		pprof.Cmdline(w, r)
+	case "profile":
+		pprof.Profile(w, r)
+	case "symbol":
+		pprof.Symbol(w, r)
+	case "trace":
+		pprof.Trace(w, r)
+	default:
+		pprof.Handler(profile).ServeHTTP(w, r)
+	}
+}
+
+// GetTimings returns the node's phase timers as a profiling.Breakdown:
+// each phase in total and per recent round, and a flame graph of both.
+func (h *Handler) GetTimings(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if !requireDebugAuth(w, r) {
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, profiling.Default().Snapshot())
+}
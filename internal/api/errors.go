@@ -0,0 +1,189 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/backup"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/certchain"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/evaluation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/failover"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/genesis"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/moduledist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// statusForError maps the consensus, convergence, p2p, batch, backup,
+// scheduler, federation, genesis, island, provenance, evaluation,
+// certificate chain, model and module distribution, and protocol error
+// taxonomy to an HTTP status code. Unknown errors map to 500.
+func statusForError(err error) int {
+	var quorumErr *consensus.ErrQuorumNotReached
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.As(err, &quorumErr),
+		errors.Is(err, consensus.ErrNoModels),
+		errors.Is(err, consensus.ErrAllModelsStale),
+		errors.Is(err, p2p.ErrNoValidVerifiers),
+		errors.Is(err, p2p.ErrNoTopicKey),
+		errors.Is(err, batch.ErrLivenessUnmet),
+		errors.Is(err, moduledist.ErrFetchFailed):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, p2p.ErrRequestTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, consensus.ErrProposalNotFound),
+		errors.Is(err, p2p.ErrPeerNotFound),
+		errors.Is(err, p2p.ErrUnknownRequest),
+		errors.Is(err, convergence.ErrUnknownCampaign),
+		errors.Is(err, federation.ErrUnknownFederation),
+		errors.Is(err, federation.ErrNoProposal),
+		errors.Is(err, provenance.ErrUnknownUpdate),
+		errors.Is(err, provenance.ErrUnknownRound),
+		errors.Is(err, evaluation.ErrUnknownEvaluation),
+		errors.Is(err, modeldist.ErrNoReceipt),
+		errors.Is(err, island.ErrUpdateNotCached),
+		errors.Is(err, moduledist.ErrNotApproved),
+		errors.Is(err, certchain.ErrUnknownShard),
+		errors.Is(err, p2p.ErrUnknownTopic):
+		return http.StatusNotFound
+	case errors.Is(err, consensus.ErrInvalidState),
+		errors.Is(err, consensus.ErrMembershipRegressed),
+		errors.Is(err, consensus.ErrEpochSealed),
+		errors.Is(err, consensus.ErrEpochRegressed),
+		errors.Is(err, consensus.ErrNotLeader),
+		errors.Is(err, consensus.ErrCommitmentRequired),
+		errors.Is(err, p2p.ErrPeerExists),
+		errors.Is(err, batch.ErrDuplicateUpdate),
+		errors.Is(err, federation.ErrFederationExists),
+		errors.Is(err, protocol.ErrModelSpecMismatch),
+		errors.Is(err, genesis.ErrGenesisMismatch),
+		errors.Is(err, moduledist.ErrRolloutHalted),
+		errors.Is(err, failover.ErrReplicationGap):
+		return http.StatusConflict
+	case errors.Is(err, consensus.ErrNotRoundMember),
+		errors.Is(err, consensus.ErrRevealMismatch),
+		errors.Is(err, consensus.ErrProbationaryNode),
+		errors.Is(err, p2p.ErrUnknownVerifier),
+		errors.Is(err, p2p.ErrAddressBookSignature),
+		errors.Is(err, backup.ErrSignature),
+		errors.Is(err, p2p.ErrAttestationRejected),
+		errors.Is(err, scheduler.ErrUnderCapacity),
+		errors.Is(err, p2p.ErrNotTopicMember),
+		errors.Is(err, federation.ErrNotMember),
+		errors.Is(err, evaluation.ErrNotSampled),
+		errors.Is(err, failover.ErrNoticeSignature),
+		errors.Is(err, p2p.ErrFragmentAuth):
+		return http.StatusForbidden
+	case errors.Is(err, consensus.ErrInvalidArgument),
+		errors.Is(err, consensus.ErrShapeMismatch),
+		errors.Is(err, p2p.ErrInvalidPeer),
+		errors.Is(err, p2p.ErrUnknownArtifactType),
+		errors.Is(err, p2p.ErrMalformedPayload),
+		errors.Is(err, batch.ErrShapeMismatch),
+		errors.Is(err, batch.ErrClipNormExceeded),
+		errors.Is(err, backup.ErrCorruptArchive),
+		errors.Is(err, backup.ErrUnsupportedVersion),
+		errors.Is(err, scheduler.ErrInvalidManifest),
+		errors.Is(err, federation.ErrInvalidFederation),
+		errors.Is(err, federation.ErrFederationMismatch),
+		errors.Is(err, federation.ErrInvalidOrigin),
+		errors.Is(err, provenance.ErrInvalidEvent),
+		errors.Is(err, evaluation.ErrInvalidReport),
+		errors.Is(err, protocol.ErrInvalidModelSpec),
+		errors.Is(err, moduledist.ErrInvalidProposal):
+		return http.StatusBadRequest
+	case errors.Is(err, p2p.ErrMessageTooLarge),
+		errors.Is(err, p2p.ErrDecompressionLimit),
+		errors.Is(err, p2p.ErrWeightsTooLong):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, batch.ErrSafetyViolation),
+		errors.Is(err, p2p.ErrAddressBookExpired),
+		errors.Is(err, backup.ErrChecksumMismatch),
+		errors.Is(err, moduledist.ErrDigestMismatch),
+		errors.Is(err, moduledist.ErrIncompatibleModule):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, consensus.ErrNotConfigured):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ErrorBody is the JSON body of every error writeError reports. Clients
+// switch on Code; Detail is the error text, for operators.
+type ErrorBody struct {
+	Code     reasons.Code     `json:"code"`
+	ID       uint16           `json:"id"`
+	Severity reasons.Severity `json:"severity"`
+	Category reasons.Category `json:"category"`
+	Message  string           `json:"message"`
+	Detail   string           `json:"detail"`
+}
+
+// statusReasons is the generic reason code of each status statusForError
+// returns.
+var statusReasons = map[int]reasons.Code{
+	http.StatusServiceUnavailable:    reasons.Unavailable,
+	http.StatusGatewayTimeout:        reasons.Timeout,
+	http.StatusNotFound:              reasons.NotFound,
+	http.StatusConflict:              reasons.Conflict,
+	http.StatusForbidden:             reasons.Forbidden,
+	http.StatusBadRequest:            reasons.InvalidRequest,
+	http.StatusRequestEntityTooLarge: reasons.TooLarge,
+	http.StatusUnprocessableEntity:   reasons.Unprocessable,
+	http.StatusNotImplemented:        reasons.NotImplemented,
+	http.StatusInternalServerError:   reasons.Internal,
+}
+
+// reasonForError is the reason code of err: the code a vote rejection or
+// an unreached quorum carries, or else the generic code of its status.
+func reasonForError(err error) reasons.Code {
+	var rejection *consensus.VoteRejection
+	var quorumErr *consensus.ErrQuorumNotReached
+	switch {
+	case errors.As(err, &rejection):
+		return rejection.Reason
+	case errors.As(err, &quorumErr):
+		return reasons.QuorumNotReached
+	}
+	if code, ok := statusReasons[statusForError(err)]; ok {
+		return code
+	}
+	return reasons.Internal
+}
+
+// writeError reports err as an ErrorBody with the status of its reason
+// code. Retryable failures carry a Retry-After hint so clients back off
+// instead of failing.
+func writeError(w http.ResponseWriter, err error) {
+	if consensus.Retryable(err) || p2p.Retryable(err) || batch.Retryable(err) {
+		w.Header().Set("Retry-After", "1")
+	}
+	code := reasonForError(err)
+	info, _ := reasons.Lookup(code)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(reasons.HTTPStatus(code))
+	_ = json.NewEncoder(w).Encode(ErrorBody{
+		Code:     code,
+		ID:       info.ID,
+		Severity: info.Severity,
+		Category: info.Category,
+		Message:  info.Message,
+		Detail:   err.Error(),
+	})
+}
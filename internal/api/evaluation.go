@@ -0,0 +1,90 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/evaluation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// SetEvaluator attaches the evaluator that takes evaluation reports posted
+// to /api/evaluation.
+func (h *Handler) SetEvaluator(evaluator *evaluation.Evaluator) {
+	h.evaluator = evaluator
+}
+
+// HandleEvaluation returns the evaluations recorded in the model store for
+// rounds in [from, to] on GET, with the rounds still open, and takes a
+// sampled node's EvaluationReport on POST.
+func (h *Handler) HandleEvaluation(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getEvaluations(w, r)
+	case http.MethodPost:
+		h.postEvaluationReport(w, r)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (h *Handler) getEvaluations(w http.ResponseWriter, r *http.Request) {
+	if h.modelStore == nil {
+		http.Error(w, "model store unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	query := r.URL.Query()
+	from, ok := parseRoundParam(query.Get("from"), 1)
+	if !ok {
+		http.Error(w, "invalid from round", http.StatusBadRequest)
+		return
+	}
+	to, ok := parseRoundParam(query.Get("to"), 0)
+	if !ok {
+		http.Error(w, "invalid to round", http.StatusBadRequest)
+		return
+	}
+	if to > 0 && to < from {
+		http.Error(w, "to must not be less than from", http.StatusBadRequest)
+		return
+	}
+
+	evaluations := h.modelStore.Evaluations(from, to)
+	open := []int{}
+	if h.evaluator != nil {
+		open = h.evaluator.Open()
+	}
+	writeJSON(w, map[string]interface{}{
+		"evaluations": evaluations,
+		"count":       len(evaluations),
+		"open_rounds": open,
+	})
+}
+
+func (h *Handler) postEvaluationReport(w http.ResponseWriter, r *http.Request) {
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.evaluator == nil {
+		http.Error(w, "evaluator unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	var report protocol.EvaluationReport
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	report.NodeID = strings.TrimSpace(report.NodeID)
+	if err := h.evaluator.Submit(report); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"node_id":  report.NodeID,
+		"round":    report.Round,
+		"accepted": true,
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+)
+
+// eventStreamHeartbeat is how often an idle event stream sends a comment,
+// keeping proxies from closing it.
+const eventStreamHeartbeat = 15 * time.Second
+
+// SetEventBus attaches the round lifecycle bus streamed on
+// /api/events/stream.
+func (h *Handler) SetEventBus(bus *events.Bus) {
+	h.eventBus = bus
+}
+
+// StreamEvents streams round lifecycle events as server-sent events until
+// the client disconnects. kind, a comma-separated list, and federation
+// narrow the stream. Each event's id is its bus sequence number; a client
+// too slow for its buffer sees a gap there rather than stalling the
+// round.
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.eventBus == nil {
+		http.Error(w, "event stream unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	kinds, err := parseEventKinds(r.URL.Query().Get("kind"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	federationID := strings.TrimSpace(r.URL.Query().Get("federation"))
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := h.eventBus.Subscribe("sse "+r.RemoteAddr, 0, kinds...)
+	defer sub.Close()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-API-Version", "v1")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event := <-sub.Events():
+			if federationID != "" && event.FederationID != federationID {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Kind, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// parseEventKinds reads a comma-separated list of lifecycle kinds; empty
+// means every kind.
+func parseEventKinds(raw string) ([]events.Kind, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	known := make(map[events.Kind]bool)
+	for _, kind := range events.Kinds() {
+		known[kind] = true
+	}
+	var kinds []events.Kind
+	for _, part := range strings.Split(raw, ",") {
+		kind := events.Kind(strings.TrimSpace(part))
+		if !known[kind] {
+			return nil, fmt.Errorf("unknown event kind %q", part)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
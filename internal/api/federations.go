@@ -0,0 +1,242 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func requireUpdateAuth(w http.ResponseWriter, r *http.Request) bool {
+	return requireScopedAuth(w, r, "MOHAWK_API_UPDATE_ALLOWED_ROLES", "node,admin")
+}
+
+// SetFederationRegistry serves the registry's federations on
+// /api/{federation}/updates, /votes, /heartbeats, /rounds/{round}/proposal,
+// and /rounds/{round}/task and binds registering nodes to the federations
+// their registration lists, registering them in each federation's peer
+// table.
+func (h *Handler) SetFederationRegistry(registry *federation.Registry) {
+	h.federations = registry
+}
+
+// bindFederations binds a registering node to the federations it asked
+// for. Each federation keeps its own peer table, so the peer is registered
+// in every one of them.
+func (h *Handler) bindFederations(req *protocol.RegistrationRequest) ([]string, error) {
+	bound, err := h.federations.Bind(req.NodeID, req.Federations)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range bound {
+		f, err := h.federations.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		peer := &p2p.PeerDetail{ID: req.NodeID, TPMAttestation: req.TPMAttestat, PublicKey: req.PublicKey}
+		if err := f.Peers.RegisterPeer(peer); err != nil {
+			return nil, err
+		}
+	}
+	return bound, nil
+}
+
+// PostFederationUpdate queues a member's model update for its round in the
+// federation named by the path. Updates from nodes not bound to the
+// federation, or naming another federation, are refused.
+func (h *Handler) PostFederationUpdate(w http.ResponseWriter, r *http.Request) {
+	if !ensurePostMethod(w, r) {
+		return
+	}
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.federations == nil {
+		http.Error(w, "federations unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var update protocol.ModelUpdate
+	if !h.decodeInbound(w, r, p2p.KindUpdate, &update) {
+		return
+	}
+	update.NodeID = strings.TrimSpace(update.NodeID)
+
+	f, err := h.federations.Route(r.PathValue("federation"), update.NodeID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if spec, ok := f.Spec(); ok {
+		if err := h.inbound.CheckWeights(update.NodeID, declaredWeights(&update), spec.Dimension); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	if err := f.Submit(&update); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"federation": f.ID,
+		"node_id":    update.NodeID,
+		"round":      update.Round,
+		"pending":    f.Pending(update.Round),
+	})
+}
+
+// PostFederationVote passes a member's vote on the open proposal to the
+// coordinator of the federation named by the path.
+func (h *Handler) PostFederationVote(w http.ResponseWriter, r *http.Request) {
+	if !ensurePostMethod(w, r) {
+		return
+	}
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.federations == nil {
+		http.Error(w, "federations unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var vote consensus.Vote
+	if !h.decodeInbound(w, r, p2p.KindVote, &vote) {
+		return
+	}
+	vote.NodeID = strings.TrimSpace(vote.NodeID)
+
+	f, err := h.federations.Route(r.PathValue("federation"), vote.NodeID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := f.CastVote(r.Context(), &vote); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"federation":  f.ID,
+		"node_id":     vote.NodeID,
+		"proposal_id": vote.ProposalID,
+		"approve":     vote.Approve,
+	})
+}
+
+// PostFederationStatus takes a member's status heartbeat in the federation
+// named by the path. A heartbeat reporting a degraded TPM puts the node back
+// on probation, one reporting a verifier module counts toward its
+// campaign's rollout, and the upload tier it declares settles its part in
+// the round.
+func (h *Handler) PostFederationStatus(w http.ResponseWriter, r *http.Request) {
+	if !ensurePostMethod(w, r) {
+		return
+	}
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.federations == nil {
+		http.Error(w, "federations unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var update protocol.StatusUpdate
+	if !h.decodeInbound(w, r, p2p.KindStatus, &update) {
+		return
+	}
+	update.NodeID = strings.TrimSpace(update.NodeID)
+
+	f, err := h.federations.Route(r.PathValue("federation"), update.NodeID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if update.FederationID != "" && update.FederationID != f.ID {
+		writeError(w, fmt.Errorf("%w: status names %s, routed to %s", federation.ErrFederationMismatch, update.FederationID, f.ID))
+		return
+	}
+	if h.probation != nil {
+		h.probation.RecordStatus(update)
+	}
+	if f.Participation != nil {
+		f.Participation.RecordStatus(update)
+	}
+	if h.moduleRollout != nil {
+		h.moduleRollout.Observe(update)
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"federation": f.ID,
+		"node_id":    update.NodeID,
+		"round":      update.Round,
+	})
+}
+
+// GetFederationProposal returns the open proposal for the round in the
+// federation named by the path, so members can check their contribution
+// before voting.
+func (h *Handler) GetFederationProposal(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.federations == nil {
+		http.Error(w, "federations unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	round, err := strconv.Atoi(strings.TrimSpace(r.PathValue("round")))
+	if err != nil || round <= 0 {
+		http.Error(w, "invalid round", http.StatusBadRequest)
+		return
+	}
+	f, err := h.federations.Get(r.PathValue("federation"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	proposal, err := f.Proposal(round)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, proposal)
+}
+
+// GetFederationTask returns the training task for the round in the
+// federation named by the path: the base model and the model spec whose
+// version the round's updates must carry.
+func (h *Handler) GetFederationTask(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.federations == nil {
+		http.Error(w, "federations unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	round, err := strconv.Atoi(strings.TrimSpace(r.PathValue("round")))
+	if err != nil || round <= 0 {
+		http.Error(w, "invalid round", http.StatusBadRequest)
+		return
+	}
+	f, err := h.federations.Get(r.PathValue("federation"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, f.TrainingTask(round))
+}
@@ -13,16 +13,32 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	internalproof "github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/backup"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/blockchain"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/certchain"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/evaluation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/hybrid"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/moduledist"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/rollback"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/sharding"
 )
 
 type proofVerifyRequest struct {
@@ -60,15 +76,41 @@ type AggregationStatusReader interface {
 
 // Handler provides HTTP endpoints for the federated learning system
 type Handler struct {
-	convergence       *convergence.Detector
-	island            *island.Manager
-	metrics           *monitoring.Collector
-	p2pNetwork        *p2p.Network
-	ledger            ProofLedgerStore
-	ledgerInitError   string
-	blockchain        *blockchain.BlockChain
-	consensusReader   ConsensusStatusReader
-	aggregationReader AggregationStatusReader
+	convergence        *convergence.Detector
+	campaigns          *convergence.CampaignTracker
+	island             *island.Manager
+	metrics            *monitoring.Collector
+	p2pNetwork         *p2p.Network
+	ledger             ProofLedgerStore
+	ledgerInitError    string
+	blockchain         *blockchain.BlockChain
+	consensusReader    ConsensusStatusReader
+	aggregationReader  AggregationStatusReader
+	aggregationHistory AggregationHistoryReader
+	modelStore         *modeldist.ModelStore
+	verifier           *p2p.Verifier
+	health             *monitoring.HealthEvaluator
+	metricsPrivacy     *privacy.MetricsPrivatizer
+	privacyBudgets     *privacy.BudgetRegistry
+	autoRollback       *rollback.AutoRollback
+	archiver           *backup.Archiver
+	capabilities       *scheduler.CapabilityRegistry
+	federations        *federation.Registry
+	provenance         *provenance.Tracker
+	batchTuner         *batch.AutoTuner
+	aggregatorPins     *crypto.PinStore
+	evaluator          *evaluation.Evaluator
+	probation          *consensus.Probation
+	genesisDigest      string
+	eventBus           *events.Bus
+	modules            *moduledist.Catalog
+	moduleRollout      *moduledist.Rollout
+	topology           *scheduler.Topology
+	shards             *sharding.Assigner
+	inbound            *p2p.InboundGuard
+	topicKeys          *p2p.TopicKeyManager
+	epochs             *certchain.EpochLedger
+	receiver           *p2p.Receiver
 }
 
 func writeJSON(w http.ResponseWriter, payload interface{}) {
@@ -201,13 +243,13 @@ func loadExpectedToken() (string, error) {
 	return token, nil
 }
 
-func requireScopedAuth(w http.ResponseWriter, r *http.Request, envName string, defaultRoles string) bool {
+func authDisabled() bool {
 	authMode := strings.ToLower(strings.TrimSpace(os.Getenv("MOHAWK_API_AUTH_MODE")))
-	if authMode == "" {
-		authMode = "file-only"
-	}
+	return authMode == "off" || authMode == "disabled" || authMode == "none"
+}
 
-	if authMode == "off" || authMode == "disabled" || authMode == "none" {
+func requireScopedAuth(w http.ResponseWriter, r *http.Request, envName string, defaultRoles string) bool {
+	if authDisabled() {
 		return true
 	}
 
@@ -276,6 +318,8 @@ func NewHandler(detector *convergence.Detector, islandMgr *island.Manager, colle
 		p2pNetwork:      network,
 		ledger:          ledgerStore,
 		ledgerInitError: initErr,
+		metricsPrivacy:  newMetricsPrivacyFromEnv(),
+		inbound:         p2p.NewInboundGuard(p2p.DefaultInboundLimits()),
 	}
 }
 
@@ -305,11 +349,46 @@ func (h *Handler) SetConsensusReaders(consensusReader ConsensusStatusReader, agg
 	h.aggregationReader = aggregationReader
 }
 
+// SetAggregatorPins attaches the TLS pins of the aggregators this node
+// calls, reported by the status endpoint.
+func (h *Handler) SetAggregatorPins(pins *crypto.PinStore) {
+	h.aggregatorPins = pins
+}
+
 // RegisterRoutes sets up HTTP routes
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	// Legacy + current endpoints
 	mux.HandleFunc("/health", h.HealthCheck)
 	mux.HandleFunc("/readyz", h.ReadinessCheck)
+	mux.HandleFunc("/api/health/detailed", h.GetDetailedHealth)
+	mux.HandleFunc("/api/v1/health/detailed", h.GetDetailedHealth)
+	mux.HandleFunc("/api/events/rollback", h.GetRollbackEvents)
+	mux.HandleFunc("/api/v1/events/rollback", h.GetRollbackEvents)
+	mux.HandleFunc("/api/events/stream", h.StreamEvents)
+	mux.HandleFunc("/api/v1/events/stream", h.StreamEvents)
+	mux.HandleFunc("/api/admin/backup", h.GetBackup)
+	mux.HandleFunc("/api/v1/admin/backup", h.GetBackup)
+	mux.HandleFunc("/api/admin/restore", h.PostRestore)
+	mux.HandleFunc("/api/v1/admin/restore", h.PostRestore)
+	mux.HandleFunc("/api/admin/batch_tuner", h.HandleBatchTuner)
+	mux.HandleFunc("/api/v1/admin/batch_tuner", h.HandleBatchTuner)
+	mux.HandleFunc("/api/admin/debug/pprof/{profile...}", h.ServeDebug)
+	mux.HandleFunc("/api/v1/admin/debug/pprof/{profile...}", h.ServeDebug)
+	mux.HandleFunc("/api/admin/timings", h.GetTimings)
+	mux.HandleFunc("/api/v1/admin/timings", h.GetTimings)
+	mux.HandleFunc("/api/admin/island/cache", h.GetIslandCache)
+	mux.HandleFunc("/api/v1/admin/island/cache", h.GetIslandCache)
+	mux.HandleFunc("/api/admin/island/cache/{id}", h.DeleteIslandCacheUpdate)
+	mux.HandleFunc("/api/v1/admin/island/cache/{id}", h.DeleteIslandCacheUpdate)
+	mux.HandleFunc("/api/admin/island/cache/requeue/{id}", h.RequeueIslandCacheUpdate)
+	mux.HandleFunc("/api/v1/admin/island/cache/requeue/{id}", h.RequeueIslandCacheUpdate)
+	mux.HandleFunc("/api/register", h.PostRegister)
+	mux.HandleFunc("/api/v1/register", h.PostRegister)
+	mux.HandleFunc("/api/v1/topic-keys", h.PostTopicKey)
+	mux.HandleFunc("GET /api/shards/{shard}/epoch", h.GetShardEpoch)
+	mux.HandleFunc("GET /api/v1/shards/{shard}/epoch", h.GetShardEpoch)
+	mux.HandleFunc("POST /api/p2p/messages", h.PostP2PMessage)
+	mux.HandleFunc("POST /api/v1/p2p/messages", h.PostP2PMessage)
 	mux.HandleFunc("/api/status", h.GetStatus)
 	mux.HandleFunc("/api/readiness", h.ReadinessCheck)
 	mux.HandleFunc("/api/metrics", h.GetMetrics)
@@ -321,22 +400,66 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/trust_status", h.GetTrustStatus)
 	mux.HandleFunc("/api/trust_snapshot", h.GetTrustSnapshot)
 	mux.HandleFunc("/api/consensus/status", h.GetConsensusStatus)
+	mux.HandleFunc("/api/rounds", h.GetRounds)
+	mux.HandleFunc("/api/evaluation", h.HandleEvaluation)
+	// Round lookups are GET-only so their wildcards do not overlap the
+	// federation-scoped POST routes below.
+	mux.HandleFunc("GET /api/model/{round}", h.GetModel)
+	mux.HandleFunc("GET /api/model/{round}/delta", h.GetModelDelta)
+	mux.HandleFunc("GET /api/manifest/{round}", h.GetManifest)
+	mux.HandleFunc("GET /api/receipts/{round}/{nodeID}", h.GetReceipt)
+	mux.HandleFunc("/api/peers/{id}/reputation", h.GetPeerReputation)
+	mux.HandleFunc("GET /api/provenance/{updateID}", h.GetProvenance)
+	mux.HandleFunc("GET /api/provenance/rounds/{round}", h.GetRoundProvenance)
 
 	// Versioned aliases
 	mux.HandleFunc("/api/v1/status", h.GetStatus)
 	mux.HandleFunc("/api/v1/readiness", h.ReadinessCheck)
 	mux.HandleFunc("/api/v1/metrics", h.GetMetrics)
 	mux.HandleFunc("/api/v1/convergence", h.GetConvergence)
+	mux.HandleFunc("/api/aggregation/rounds", h.GetAggregationRounds)
+	mux.HandleFunc("/api/v1/aggregation/rounds", h.GetAggregationRounds)
+	mux.HandleFunc("/api/convergence/campaigns", h.GetCampaigns)
+	mux.HandleFunc("/api/v1/convergence/campaigns", h.GetCampaigns)
+	mux.HandleFunc("/api/convergence/campaigns/compare", h.GetCampaignComparison)
+	mux.HandleFunc("/api/v1/convergence/campaigns/compare", h.GetCampaignComparison)
 	mux.HandleFunc("/api/v1/island/status", h.GetIslandStatus)
 	mux.HandleFunc("/api/v1/peers", h.GetPeers)
 	mux.HandleFunc("/api/v1/network_status", h.GetNetworkStatus)
 	mux.HandleFunc("/api/v1/trust_status", h.GetTrustStatus)
 	mux.HandleFunc("/api/v1/trust_snapshot", h.GetTrustSnapshot)
 	mux.HandleFunc("/api/v1/consensus/status", h.GetConsensusStatus)
+	mux.HandleFunc("/api/v1/rounds", h.GetRounds)
+	mux.HandleFunc("/api/v1/evaluation", h.HandleEvaluation)
+	mux.HandleFunc("GET /api/v1/model/{round}", h.GetModel)
+	mux.HandleFunc("GET /api/v1/model/{round}/delta", h.GetModelDelta)
+	mux.HandleFunc("GET /api/v1/manifest/{round}", h.GetManifest)
+	mux.HandleFunc("GET /api/v1/receipts/{round}/{nodeID}", h.GetReceipt)
+	mux.HandleFunc("/api/v1/peers/{id}/reputation", h.GetPeerReputation)
+	mux.HandleFunc("GET /api/v1/provenance/{updateID}", h.GetProvenance)
+	mux.HandleFunc("GET /api/v1/provenance/rounds/{round}", h.GetRoundProvenance)
+	mux.HandleFunc("GET /api/modules/{campaign}", h.GetModuleApprovals)
+	mux.HandleFunc("GET /api/v1/modules/{campaign}", h.GetModuleApprovals)
+	mux.HandleFunc("GET /api/modules/{campaign}/rollout", h.GetModuleRollout)
+	mux.HandleFunc("GET /api/v1/modules/{campaign}/rollout", h.GetModuleRollout)
+	mux.HandleFunc("GET /api/modules/{campaign}/{digest}", h.GetModule)
+	mux.HandleFunc("GET /api/v1/modules/{campaign}/{digest}", h.GetModule)
+	mux.HandleFunc("POST /api/modules/{campaign}/proposals", h.PostModuleProposal)
+	mux.HandleFunc("POST /api/v1/modules/{campaign}/proposals", h.PostModuleProposal)
+	mux.HandleFunc("GET /api/modules/{campaign}/proposals", h.GetModuleProposals)
+	mux.HandleFunc("GET /api/v1/modules/{campaign}/proposals", h.GetModuleProposals)
+	mux.HandleFunc("POST /api/modules/{campaign}/proposals/{id}/votes", h.PostModuleVote)
+	mux.HandleFunc("POST /api/v1/modules/{campaign}/proposals/{id}/votes", h.PostModuleVote)
+	mux.HandleFunc("POST /api/modules/{campaign}/proposals/{id}/commit", h.PostModuleCommit)
+	mux.HandleFunc("POST /api/v1/modules/{campaign}/proposals/{id}/commit", h.PostModuleCommit)
+	mux.HandleFunc("GET /api/latency_budgets", h.GetLatencyBudgets)
+	mux.HandleFunc("GET /api/v1/latency_budgets", h.GetLatencyBudgets)
 	mux.HandleFunc("/api/proof/verify", h.VerifyProof)
 	mux.HandleFunc("/api/v1/proof/verify", h.VerifyProof)
 	mux.HandleFunc("/api/proof/hybrid/verify", h.VerifyHybridProof)
 	mux.HandleFunc("/api/v1/proof/hybrid/verify", h.VerifyHybridProof)
+	mux.HandleFunc("/api/privacy", h.GetPrivacy)
+	mux.HandleFunc("/api/v1/privacy", h.GetPrivacy)
 	mux.HandleFunc("/api/capabilities", h.GetCapabilities)
 	mux.HandleFunc("/api/v1/capabilities", h.GetCapabilities)
 	mux.HandleFunc("/api/ledger", h.GetLedger)
@@ -345,6 +468,16 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/v1/ledger/reconcile", h.GetLedgerReconcile)
 	mux.HandleFunc("/api/verification_policy", h.HandleVerificationPolicy)
 	mux.HandleFunc("/api/v1/verification_policy", h.HandleVerificationPolicy)
+	mux.HandleFunc("POST /api/{federation}/updates", h.PostFederationUpdate)
+	mux.HandleFunc("POST /api/v1/{federation}/updates", h.PostFederationUpdate)
+	mux.HandleFunc("POST /api/{federation}/votes", h.PostFederationVote)
+	mux.HandleFunc("POST /api/v1/{federation}/votes", h.PostFederationVote)
+	mux.HandleFunc("POST /api/{federation}/heartbeats", h.PostFederationStatus)
+	mux.HandleFunc("POST /api/v1/{federation}/heartbeats", h.PostFederationStatus)
+	mux.HandleFunc("GET /api/{federation}/rounds/{round}/proposal", h.GetFederationProposal)
+	mux.HandleFunc("GET /api/v1/{federation}/rounds/{round}/proposal", h.GetFederationProposal)
+	mux.HandleFunc("GET /api/{federation}/rounds/{round}/task", h.GetFederationTask)
+	mux.HandleFunc("GET /api/v1/{federation}/rounds/{round}/task", h.GetFederationTask)
 }
 
 // HealthCheck returns basic health status
@@ -431,15 +564,24 @@ func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	if h.aggregationReader != nil {
 		response["aggregation"] = h.aggregationReader.GetRuntimeStatus()
 	}
+	if h.aggregatorPins != nil {
+		response["aggregator_pins"] = h.aggregatorPins.Pins()
+	}
 
 	writeJSON(w, response)
 }
 
-// GetMetrics returns collected metrics
+// GetMetrics returns collected metrics: summary aggregates and a page of
+// raw observations, filtered by type, node, label (label=name=value,
+// repeatable), and time range and paged by cursor.
 func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
 	if !ensureGetMethod(w, r) {
 		return
 	}
+	query, ok := metricQuery(w, r)
+	if !ok {
+		return
+	}
 
 	response := map[string]interface{}{
 		"total_rounds":                0,
@@ -449,9 +591,30 @@ func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
 		"churn_joins_total":           0,
 		"churn_leaves_total":          0,
 		"async_staleness_avg_seconds": 0.0,
+		"metrics":                     []metricEntry{},
+		"total":                       0,
+		"next_cursor":                 "",
 	}
 
 	if h.metrics != nil {
+		page := h.metrics.QueryMetrics(query)
+		entries := make([]metricEntry, 0, len(page.Metrics))
+		for _, metric := range page.Metrics {
+			entries = append(entries, metricEntry{
+				Seq:       metric.Seq,
+				Type:      string(metric.Type),
+				Value:     metric.Value,
+				Timestamp: metric.Timestamp,
+				NodeID:    metric.NodeID,
+				Labels:    metric.Labels,
+			})
+		}
+		response["metrics"] = entries
+		response["total"] = page.Total
+		if page.NextCursor != 0 {
+			response["next_cursor"] = strconv.FormatUint(page.NextCursor, 10)
+		}
+
 		summary := h.metrics.GetSummary()
 		response["total_metrics"] = summary["total_metrics"]
 		response["aggregations"] = summary["aggregations"]
@@ -474,6 +637,9 @@ func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if h.metricsPrivacy != nil && !isAdminRequest(r) {
+		response = privatizeMetricsResponse(h.metricsPrivacy, response)
+	}
 	writeJSON(w, response)
 }
 
@@ -567,30 +733,56 @@ func (h *Handler) GetIslandStatus(w http.ResponseWriter, r *http.Request) {
 		response["cached_updates"] = cachedCount
 		response["max_cached_updates"] = maxCached
 		response["time_since_last_sync"] = timeSinceSync.String()
+
+		// Surface the transition watchdog, so a sync that died is visible.
+		status := h.island.GetStatus()
+		for _, key := range []string{"transition_failures", "sync_stuck", "last_sync_error", "last_transition_error"} {
+			if value, ok := status[key]; ok {
+				response[key] = value
+			}
+		}
 	}
 
 	writeJSON(w, response)
 }
 
-// GetPeers returns information about connected peers
+// GetPeers returns a page of peers in ID order, filtered by status and
+// reputation range. total counts the peers matching the filter;
+// total_peers counts every peer.
 func (h *Handler) GetPeers(w http.ResponseWriter, r *http.Request) {
 	if !ensureGetMethod(w, r) {
 		return
 	}
+	filter, after, limit, ok := peerQuery(w, r)
+	if !ok {
+		return
+	}
 
 	response := map[string]interface{}{
 		"total_peers":  0,
 		"active_peers": 0,
 		"peers":        []map[string]interface{}{},
+		"total":        0,
+		"next_cursor":  "",
 	}
 
 	if h.p2pNetwork != nil {
-		peers := h.p2pNetwork.GetPeers()
-		activePeers := h.p2pNetwork.GetActivePeerCount()
-
-		response["total_peers"] = len(peers)
-		response["active_peers"] = activePeers
-		response["peers"] = peers
+		page := h.p2pNetwork.QueryPeers(filter, after, limit)
+		response["total_peers"] = h.p2pNetwork.PeerCount()
+		response["active_peers"] = h.p2pNetwork.GetActivePeerCount()
+		response["peers"] = page.Peers
+		response["total"] = page.Total
+		response["next_cursor"] = encodePeerCursor(page.NextCursor)
+		if h.probation != nil {
+			for _, peer := range page.Peers {
+				if id, _ := peer["id"].(string); id != "" {
+					peer["probation"] = h.probationStatus(id)
+				}
+			}
+		}
+	}
+	if h.probation != nil {
+		response["probationary_peers"] = h.probationaryCount()
 	}
 
 	writeJSON(w, response)
@@ -1,8 +1,13 @@
 package api
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -11,9 +16,27 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/blockchain"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/evaluation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/profiling"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/rollback"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/tpm"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
 )
 
 type mockStatusReader struct {
@@ -1122,3 +1145,1493 @@ func TestUpdateVerificationPolicyEndpoint(t *testing.T) {
 		t.Fatal("expected api verification policy audit entry to be written")
 	}
 }
+
+func TestRoundBackfillEndpoints(t *testing.T) {
+	store := modeldist.NewModelStore(16)
+	for round := 1; round <= 4; round++ {
+		weights := []byte{byte(round), byte(round), byte(round)}
+		cert := modeldist.CommitCertificate{
+			Round:       round,
+			ModelDigest: protocol.WeightsDigest(weights),
+			QuorumSize:  1,
+			Approvals:   []string{"agg"},
+		}
+		if _, err := store.Commit(round, weights, 5, nil, cert); err != nil {
+			t.Fatalf("commit round %d: %v", round, err)
+		}
+	}
+
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetModelStore(store)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/rounds?from=2&to=3", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("rounds status = %d, want 200", w.Code)
+	}
+	var rounds modeldist.RoundsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &rounds); err != nil {
+		t.Fatalf("json decode failed: %v", err)
+	}
+	if rounds.Count != 2 || rounds.LatestRound != 4 || rounds.Rounds[0].Round != 2 {
+		t.Fatalf("unexpected rounds payload: %+v", rounds)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/model/4", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("model status = %d, want 200", w.Code)
+	}
+	var model modeldist.ModelResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &model); err != nil {
+		t.Fatalf("json decode failed: %v", err)
+	}
+	if err := model.Summary.Certificate.Verify(model.Weights, nil); err != nil {
+		t.Fatalf("served model does not verify: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/model/99", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("missing model status = %d, want 404", w.Code)
+	}
+
+	// These weights are not float arrays, so no round has a delta.
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/model/4/delta", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("missing delta status = %d, want 404", w.Code)
+	}
+}
+
+func TestModelDeltaEndpoint(t *testing.T) {
+	store := modeldist.NewModelStore(16)
+	var weights []float64
+	for round := 1; round <= 2; round++ {
+		weights = append(weights[:0], float64(round), 0.5, -0.25, 0.125)
+		encoded := protocol.ModelWeights{Weights: weights}.Canonical()
+		cert := modeldist.CommitCertificate{Round: round, ModelDigest: protocol.WeightsDigest(encoded), QuorumSize: 1, Approvals: []string{"agg"}}
+		if _, err := store.Commit(round, encoded, 5, nil, cert); err != nil {
+			t.Fatalf("commit round %d: %v", round, err)
+		}
+	}
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetModelStore(store)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/model/2/delta", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delta status = %d, want 200", w.Code)
+	}
+	var delta modeldist.ModelDelta
+	if err := json.Unmarshal(w.Body.Bytes(), &delta); err != nil {
+		t.Fatalf("json decode failed: %v", err)
+	}
+	base, _, _ := store.Model(1)
+	full, summary, _ := store.Model(2)
+	applied, err := delta.ApplyDelta(base)
+	if err != nil || !bytes.Equal(applied, full) || delta.ModelDigest != summary.ModelDigest {
+		t.Fatalf("served delta does not reproduce round 2: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/model/1/delta", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("first round delta status = %d, want 404", w.Code)
+	}
+}
+
+func TestWriteErrorMapsTaxonomyToStatus(t *testing.T) {
+	cases := []struct {
+		err        error
+		status     int
+		retryAfter bool
+	}{
+		{fmt.Errorf("round 3: %w", &consensus.ErrQuorumNotReached{Got: 2, Need: 5}), http.StatusServiceUnavailable, true},
+		{fmt.Errorf("commit failed: %w", fmt.Errorf("%w: p-1", consensus.ErrProposalNotFound)), http.StatusNotFound, false},
+		{fmt.Errorf("vote: %w", consensus.ErrNotRoundMember), http.StatusForbidden, false},
+		{fmt.Errorf("verify: %w", p2p.ErrRequestTimeout), http.StatusGatewayTimeout, true},
+		{fmt.Errorf("verify: %w", p2p.ErrUnknownVerifier), http.StatusForbidden, false},
+		{fmt.Errorf("round: %w", batch.ErrDuplicateUpdate), http.StatusConflict, false},
+		{fmt.Errorf("round: %w", batch.ErrSafetyViolation), http.StatusUnprocessableEntity, false},
+		{fmt.Errorf("opaque failure"), http.StatusInternalServerError, false},
+	}
+
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		writeError(rec, tc.err)
+		if rec.Code != tc.status {
+			t.Fatalf("%v: expected status %d, got %d", tc.err, tc.status, rec.Code)
+		}
+		if got := rec.Header().Get("Retry-After") != ""; got != tc.retryAfter {
+			t.Fatalf("%v: expected Retry-After=%v, got %v", tc.err, tc.retryAfter, got)
+		}
+		var body ErrorBody
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || reasons.HTTPStatus(body.Code) != tc.status || body.Detail != tc.err.Error() {
+			t.Fatalf("%v: body %+v (%v)", tc.err, body, err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	writeError(rec, &consensus.VoteRejection{Middleware: consensus.MiddlewareRateLimit, Reason: consensus.ReasonRateLimited, Err: consensus.ErrVoteRateLimited})
+	var body ErrorBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusTooManyRequests || body.Code != reasons.RateLimited || body.Severity != reasons.SeverityWarning {
+		t.Fatalf("rate limited vote: %d %+v (%v)", rec.Code, body, err)
+	}
+}
+
+func TestManifestEndpoint(t *testing.T) {
+	store := modeldist.NewModelStore(4)
+	weights := []byte{1, 2, 3}
+	cert := modeldist.CommitCertificate{Round: 1, ModelDigest: protocol.WeightsDigest(weights), QuorumSize: 1, Approvals: []string{"agg"}}
+	if _, err := store.Commit(1, weights, 2, nil, cert); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	manifest := &protocol.ContributionManifest{
+		Round: 1,
+		Entries: []protocol.ContributionEntry{
+			{NodeID: "node-a", UpdateDigest: "aa", SampleCount: 10, AppliedWeight: 1, Included: true, Reason: protocol.ReasonIncluded},
+			{NodeID: "node-b", UpdateDigest: "bb", SampleCount: 10, Reason: protocol.ReasonNormOutlier},
+		},
+	}
+	if err := store.AttachManifest(manifest); err != nil {
+		t.Fatalf("attach manifest: %v", err)
+	}
+
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetModelStore(store)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/manifest/1", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("manifest status = %d, want 200", w.Code)
+	}
+	var payload struct {
+		Manifest       protocol.ContributionManifest `json:"manifest"`
+		ManifestDigest string                        `json:"manifest_digest"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("json decode failed: %v", err)
+	}
+	if payload.ManifestDigest != manifest.Digest() || payload.Manifest.Digest() != manifest.Digest() {
+		t.Fatal("served manifest does not match its digest")
+	}
+	if summaries := store.Summaries(1, 1); summaries[0].ManifestDigest != manifest.Digest() {
+		t.Fatal("round summary does not carry the manifest digest")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/manifest/2", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("missing manifest status = %d, want 404", w.Code)
+	}
+}
+
+func TestPeerReputationEndpointPaginates(t *testing.T) {
+	verifier := p2p.NewVerifier("node-main", 1, time.Second)
+	if err := verifier.RegisterPeer(&p2p.PeerDetail{ID: "peer-a"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 10; i++ {
+		if err := verifier.RecordReputation("peer-a", start.Add(time.Duration(i)*time.Minute), 1-0.01*float64(i), p2p.CauseVerificationInvalid); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetVerifier(verifier)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	fetch := func(query string) (int, reputationHistoryResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/peers/peer-a/reputation"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var payload reputationHistoryResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+				t.Fatalf("json decode failed: %v", err)
+			}
+		}
+		return w.Code, payload
+	}
+
+	code, first := fetch("?window=2h&limit=4")
+	if code != http.StatusOK || first.Total != 11 || len(first.Samples) != 4 || first.NextOffset != 4 {
+		t.Fatalf("unexpected first page: code=%d %+v", code, first)
+	}
+	code, last := fetch("?window=2h&limit=4&offset=8")
+	if code != http.StatusOK || len(last.Samples) != 3 || last.NextOffset != 0 {
+		t.Fatalf("unexpected last page: code=%d %+v", code, last)
+	}
+	if !last.Samples[0].Timestamp.After(first.Samples[3].Timestamp) {
+		t.Fatal("pages are not in chronological order")
+	}
+	if code, _ := fetch("?limit=0"); code != http.StatusBadRequest {
+		t.Fatalf("limit=0 status = %d, want 400", code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/peers/missing/reputation", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("unknown peer status = %d, want 404", w.Code)
+	}
+}
+
+func TestDetailedHealthEndpoint(t *testing.T) {
+	islandMgr := island.NewManager(time.Hour, 10, func() bool { return true })
+	collector := monitoring.NewCollector(10)
+	h := NewHandler(nil, islandMgr, collector, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	fetch := func() (int, monitoring.HealthReport) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/health/detailed", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var report monitoring.HealthReport
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("json decode failed: %v", err)
+			}
+		}
+		return w.Code, report
+	}
+
+	if code, _ := fetch(); code != http.StatusServiceUnavailable {
+		t.Fatalf("status without evaluator = %d, want 503", code)
+	}
+
+	evaluator := monitoring.NewHealthEvaluator()
+	h.SetHealthEvaluator(evaluator)
+	evaluator.ObserveWasm(true, "")
+	code, report := fetch()
+	if code != http.StatusOK || report.Status != monitoring.HealthHealthy {
+		t.Fatalf("unexpected healthy report: code=%d %+v", code, report)
+	}
+	if dim, ok := report.Dimension(monitoring.DimensionIsland); !ok || dim.Status != monitoring.HealthHealthy {
+		t.Fatalf("expected island dimension filled from the island manager, got %+v", dim)
+	}
+
+	evaluator.ObserveWasm(false, "module missing verify_proof")
+	_, report = fetch()
+	if report.Status != monitoring.HealthCritical || report.Dimensions[0].Dimension != monitoring.DimensionWasm {
+		t.Fatalf("expected wasm-driven critical report, got %+v", report)
+	}
+	if events := collector.GetMetricsByType(monitoring.MetricHealth); len(events) != 2 || events[1].Labels["to"] != "critical" {
+		t.Fatalf("expected two health transitions in the collector, got %+v", events)
+	}
+}
+
+func TestMetricsEndpointAppliesLocalDPToNonAdmins(t *testing.T) {
+	configureProofAuthForTests(t)
+	collector := monitoring.NewCollector(100)
+	for i := 0; i < 23; i++ {
+		collector.Record(monitoring.MetricLoss, 0.42, nil, "node-a")
+	}
+	collector.Record(monitoring.MetricNetworkLag, 12.5, nil, "node-a")
+	h := NewHandler(nil, nil, collector, nil)
+	privatizer, err := privacy.NewMetricsPrivatizer(privacy.DefaultMetricsDPConfig())
+	if err != nil {
+		t.Fatalf("privatizer: %v", err)
+	}
+	h.SetMetricsPrivacy(privatizer)
+
+	fetch := func(admin bool) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil)
+		if admin {
+			req.Header.Set("Authorization", "Bearer test-token")
+			req.Header.Set("X-API-Role", "admin")
+		}
+		w := httptest.NewRecorder()
+		h.GetMetrics(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("metrics status = %d", w.Code)
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("json decode failed: %v", err)
+		}
+		return payload
+	}
+	lossMean := func(payload map[string]interface{}) float64 {
+		return payload["aggregations"].(map[string]interface{})["loss"].(map[string]interface{})["mean"].(float64)
+	}
+
+	exact := fetch(true)
+	if exact["local_dp"] != nil || exact["total_metrics"].(float64) != 24 || lossMean(exact) != 0.42 || exact["network_lag_ms"].(float64) != 12.5 {
+		t.Fatalf("expected exact values for admin, got %+v", exact)
+	}
+
+	noised := fetch(false)
+	if noised["local_dp"] == nil {
+		t.Fatal("expected local_dp marker on privatized response")
+	}
+	if noised["total_metrics"].(float64) != 20 {
+		t.Fatalf("expected total_metrics bucketed to 20, got %v", noised["total_metrics"])
+	}
+	if lossMean(noised) == 0.42 || noised["network_lag_ms"].(float64) == 12.5 {
+		t.Fatalf("expected noised values to differ from internal values, got %+v", noised)
+	}
+	if agg := collector.GetAggregation(monitoring.MetricLoss); agg.Mean != 0.42 {
+		t.Fatalf("local aggregation changed to %f", agg.Mean)
+	}
+}
+
+func TestRollbackEventsEndpoint(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/rollback", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status without auto rollback = %d, want 503", w.Code)
+	}
+
+	coordinator := consensus.NewCoordinator("node-0", 4, time.Minute)
+	store := modeldist.NewModelStore(8)
+	auto, err := rollback.NewAutoRollback("node-0", rollback.DefaultPolicy(), coordinator, store, nil)
+	if err != nil {
+		t.Fatalf("new auto rollback: %v", err)
+	}
+	h.SetAutoRollback(auto)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events/rollback", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp struct {
+		Events  []rollback.Event `json:"events"`
+		Count   int              `json:"count"`
+		Pending bool             `json:"pending"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("json decode: %v", err)
+	}
+	if resp.Count != 0 || len(resp.Events) != 0 || resp.Pending {
+		t.Fatalf("expected empty event log, got %+v", resp)
+	}
+}
+
+func TestRegisterRequiresVerifiedAttestationEnvelope(t *testing.T) {
+	configureProofAuthForTests(t)
+	manager := tpm.NewAttestationManager(8, time.Minute, true)
+	verifier := p2p.NewVerifier("node-0", 1, time.Second)
+	verifier.SetAttestationVerifier(manager.VerifyEnvelope)
+
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetVerifier(verifier)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	register := func(req protocol.RegistrationRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/register", strings.NewReader(string(body)))
+		r.Header.Set("Authorization", "Bearer test-token")
+		r.Header.Set("X-API-Role", "node")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	report, err := manager.GenerateAttestation("edge-7", []byte("register-nonce"))
+	if err != nil {
+		t.Fatalf("generate attestation: %v", err)
+	}
+	envelope, err := tpm.ToEnvelope(report)
+	if err != nil {
+		t.Fatalf("to envelope: %v", err)
+	}
+	encoded, err := envelope.Marshal()
+	if err != nil {
+		t.Fatalf("marshal envelope: %v", err)
+	}
+
+	if w := register(protocol.RegistrationRequest{NodeID: "edge-7"}); w.Code != http.StatusForbidden {
+		t.Fatalf("status without attestation = %d, want 403", w.Code)
+	}
+	if w := register(protocol.RegistrationRequest{NodeID: "edge-8", TPMAttestat: encoded}); w.Code != http.StatusForbidden {
+		t.Fatalf("status for another node's attestation = %d, want 403", w.Code)
+	}
+
+	w := register(protocol.RegistrationRequest{NodeID: "edge-7", Capacity: 4, TPMAttestat: encoded})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var resp protocol.RegistrationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("json decode: %v", err)
+	}
+	if !resp.Approved || resp.NodeID != "edge-7" {
+		t.Fatalf("expected edge-7 approved, got %+v", resp)
+	}
+}
+
+func TestRegisterRejectsUnderCapacityNodes(t *testing.T) {
+	configureProofAuthForTests(t)
+	verifier := p2p.NewVerifier("node-0", 1, time.Second)
+	registry := scheduler.NewCapabilityRegistry(scheduler.AdmissionPolicy{MinCPUCount: 2, ModelParameters: 1_000_000})
+
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetVerifier(verifier)
+	h.SetCapabilityRegistry(registry)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	register := func(req protocol.RegistrationRequest) int {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/register", strings.NewReader(string(body)))
+		r.Header.Set("Authorization", "Bearer test-token")
+		r.Header.Set("X-API-Role", "node")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w.Code
+	}
+	manifest := func(nodeID string, cpus int, memory int64) *protocol.CapabilityManifest {
+		return &protocol.CapabilityManifest{NodeID: nodeID, CPUClass: protocol.CPUClassFor(cpus), CPUCount: cpus, MemoryBytes: memory}
+	}
+
+	if code := register(protocol.RegistrationRequest{NodeID: "edge-1"}); code != http.StatusBadRequest {
+		t.Fatalf("status without a manifest = %d, want 400", code)
+	}
+	if code := register(protocol.RegistrationRequest{NodeID: "edge-1", Capabilities: manifest("edge-1", 4, 1<<20)}); code != http.StatusForbidden {
+		t.Fatalf("status for too little memory = %d, want 403", code)
+	}
+	if len(verifier.GetActivePeers()) != 0 {
+		t.Fatal("expected a refused node not to remain a verification peer")
+	}
+	if code := register(protocol.RegistrationRequest{NodeID: "edge-1", Capabilities: manifest("edge-1", 4, 1<<30)}); code != http.StatusOK {
+		t.Fatalf("status for a capable node = %d, want 200", code)
+	}
+	if _, ok := registry.Manifest("edge-1"); !ok {
+		t.Fatal("expected the admitted manifest to be stored")
+	}
+}
+
+func TestRegisterReferencesTheGenesisDigest(t *testing.T) {
+	configureProofAuthForTests(t)
+	verifier := p2p.NewVerifier("node-0", 1, time.Second)
+	digest := protocol.UpdateDigest([]byte("genesis"))
+
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetVerifier(verifier)
+	h.SetGenesisDigest(digest)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	register := func(nodeID, pinned string) (int, protocol.RegistrationResponse) {
+		body, _ := json.Marshal(protocol.RegistrationRequest{NodeID: nodeID, GenesisDigest: pinned})
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/register", bytes.NewReader(body))
+		r.Header.Set("Authorization", "Bearer test-token")
+		r.Header.Set("X-API-Role", "node")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		var resp protocol.RegistrationResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+	for nodeID, pinned := range map[string]string{"edge-1": digest, "edge-2": ""} {
+		if code, resp := register(nodeID, pinned); code != http.StatusOK || resp.GenesisDigest != digest {
+			t.Fatalf("%s: status %d, response %+v; want the genesis digest referenced", nodeID, code, resp)
+		}
+	}
+	if code, _ := register("edge-3", protocol.UpdateDigest([]byte("another genesis"))); code != http.StatusConflict {
+		t.Fatalf("node pinning another genesis: status %d, want 409", code)
+	}
+	if len(verifier.GetActivePeers()) != 2 {
+		t.Fatalf("peers = %v, want the two nodes of this genesis", verifier.GetActivePeers())
+	}
+}
+
+func TestRegisterAdmitsNewNodesOnProbation(t *testing.T) {
+	configureProofAuthForTests(t)
+	probation, err := consensus.NewProbation(consensus.DefaultProbationConfig())
+	if err != nil {
+		t.Fatalf("new probation: %v", err)
+	}
+	probation.Graduate("founder")
+	network := p2p.NewNetwork("node-0", 1, time.Second)
+	network.AddPeer("founder", "addr", 1)
+	network.AddPeer("edge-1", "addr", 0.5)
+
+	h := NewHandler(nil, nil, nil, network)
+	h.SetVerifier(p2p.NewVerifier("node-0", 1, time.Second))
+	h.SetProbation(probation)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	register := func(nodeID string) protocol.RegistrationResponse {
+		body, _ := json.Marshal(protocol.RegistrationRequest{NodeID: nodeID})
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/register", bytes.NewReader(body))
+		r.Header.Set("Authorization", "Bearer test-token")
+		r.Header.Set("X-API-Role", "node")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("register %s = %d: %s", nodeID, w.Code, w.Body.String())
+		}
+		var resp protocol.RegistrationResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp
+	}
+	if !register("edge-1").Probationary {
+		t.Fatal("expected a new node to start on probation")
+	}
+	if register("founder").Probationary {
+		t.Fatal("expected a graduated node to keep full privileges")
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/peers", nil))
+	var peers struct {
+		Peers []struct {
+			ID        string                    `json:"id"`
+			Probation consensus.ProbationStatus `json:"probation"`
+		} `json:"peers"`
+		ProbationaryPeers int `json:"probationary_peers"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &peers); err != nil {
+		t.Fatalf("decode peers: %v", err)
+	}
+	if peers.ProbationaryPeers != 1 || len(peers.Peers) != 2 {
+		t.Fatalf("peers = %+v", peers)
+	}
+	for _, peer := range peers.Peers {
+		if peer.Probation.OnProbation != (peer.ID == "edge-1") || peer.Probation.RequiredRounds != 10 {
+			t.Fatalf("probation of %s = %+v", peer.ID, peer.Probation)
+		}
+	}
+}
+
+func TestStreamEventsServesLifecycleAsSSE(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/events/stream", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status without a bus = %d, want 503", w.Code)
+	}
+
+	bus := events.NewBus()
+	h.SetEventBus(bus)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/events/stream?kind=committed,bogus", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status for an unknown kind = %d, want 400", w.Code)
+	}
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/events/stream?kind=committed,aborted&federation=traffic", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("content type = %q", resp.Header.Get("Content-Type"))
+	}
+	if stats := bus.Stats(); len(stats) != 1 {
+		t.Fatalf("subscribers = %+v", stats)
+	}
+
+	bus.Publish(events.Event{Kind: events.KindProposalCreated, FederationID: "traffic", Round: 1})
+	bus.Publish(events.Event{Kind: events.KindCommitted, FederationID: "energy", Round: 1})
+	bus.Publish(events.Event{Kind: events.KindCommitted, FederationID: "traffic", Round: 1, ProposalID: "p-1", Approvals: 3, QuorumSize: 3})
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if lines[0] != "id: 3" || lines[1] != "event: committed" || !strings.HasPrefix(lines[2], "data: ") {
+		t.Fatalf("stream = %q", lines)
+	}
+	var event events.Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &event); err != nil || event.ProposalID != "p-1" || event.Approvals != 3 {
+		t.Fatalf("event = %+v, %v", event, err)
+	}
+
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(bus.Stats()) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the stream's subscription outlived the client")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGetPrivacyServesBudgetRegistry(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/privacy", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status without a registry = %d, want 503", w.Code)
+	}
+
+	registry := privacy.NewBudgetRegistry()
+	for _, shard := range []string{"eu", "us"} {
+		if err := registry.Register(shard, privacy.ShardBudget{
+			Allocation:    privacy.Budget{Epsilon: 1, Delta: 1e-5},
+			PerRound:      privacy.Budget{Epsilon: 0.25, Delta: 1e-6},
+			L2Sensitivity: 1,
+		}); err != nil {
+			t.Fatalf("register %s: %v", shard, err)
+		}
+	}
+	if err := registry.BeginGlobalRound(1, []string{"eu", "us"}); err != nil {
+		t.Fatalf("round 1: %v", err)
+	}
+	if err := registry.BeginGlobalRound(2, []string{"eu"}); err != nil {
+		t.Fatalf("round 2: %v", err)
+	}
+	h.SetPrivacyBudgets(registry)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/privacy", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var state privacy.RegistryState
+	if err := json.Unmarshal(w.Body.Bytes(), &state); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if state.Global.Epsilon != 0.5 || len(state.Shards) != 2 || state.Shards[1].Rounds != 1 {
+		t.Fatalf("unexpected registry state %+v", state)
+	}
+}
+
+func TestCampaignEndpointsCompareAndExport(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/convergence/campaigns", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status without a tracker = %d, want 503", w.Code)
+	}
+
+	tracker, err := convergence.NewCampaignTracker(t.TempDir())
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	for _, campaign := range []struct {
+		id   string
+		loss []float64
+	}{{"baseline", []float64{1, 0.6, 0.3}}, {"tuned", []float64{0.8, 0.2}}} {
+		if err := tracker.StartCampaign(convergence.Campaign{ID: campaign.id}); err != nil {
+			t.Fatalf("start %s: %v", campaign.id, err)
+		}
+		for i, loss := range campaign.loss {
+			if err := tracker.Record(convergence.RoundReport{CampaignID: campaign.id, Round: i + 1, Loss: loss}); err != nil {
+				t.Fatalf("record %s: %v", campaign.id, err)
+			}
+		}
+	}
+	h.SetCampaignTracker(tracker)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/convergence/campaigns", nil))
+	var listing struct {
+		Campaigns []convergence.CampaignSummary `json:"campaigns"`
+		Count     int                           `json:"count"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &listing); err != nil || listing.Count != 2 {
+		t.Fatalf("listing %s err=%v", w.Body.String(), err)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/convergence/campaigns/compare?a=baseline&b=tuned&threshold=0.5", nil))
+	var comparison convergence.Comparison
+	if err := json.Unmarshal(w.Body.Bytes(), &comparison); err != nil {
+		t.Fatalf("decode comparison %s: %v", w.Body.String(), err)
+	}
+	if comparison.A.RoundsToThreshold != 3 || comparison.B.RoundsToThreshold != 2 || len(comparison.Rounds) != 3 {
+		t.Fatalf("comparison %+v", comparison)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/convergence/campaigns/compare?a=baseline&b=tuned&format=csv", nil))
+	if got := w.Header().Get("Content-Type"); got != "text/csv" {
+		t.Fatalf("content type %q", got)
+	}
+	if want := "round,loss_baseline,loss_tuned,heterogeneity_baseline,heterogeneity_tuned\n1,1,0.8,0,0\n2,0.6,0.2,0,0\n3,0.3,,0,\n"; w.Body.String() != want {
+		t.Fatalf("csv = %q, want %q", w.Body.String(), want)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/convergence/campaigns/compare?a=baseline&b=missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("unknown campaign status = %d, want 404", w.Code)
+	}
+}
+
+func TestProvenanceEndpointsServeChainsAndExport(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/provenance/missing", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status without a tracker = %d, want 503", w.Code)
+	}
+
+	tracker, err := provenance.NewTracker(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	updateID := provenance.UpdateID("node-1", 4, "digest")
+	for _, kind := range []provenance.Kind{provenance.KindReceived, provenance.KindIncluded, provenance.KindCommitted} {
+		tracker.Emit(provenance.Event{UpdateID: updateID, NodeID: "node-1", Round: 4, Kind: kind, Source: "test"})
+	}
+	h.SetProvenanceTracker(tracker)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/provenance/"+updateID, nil))
+	var chain struct {
+		UpdateID string             `json:"update_id"`
+		Events   []provenance.Event `json:"events"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &chain); err != nil || chain.UpdateID != updateID || len(chain.Events) != 3 {
+		t.Fatalf("chain %s err=%v", w.Body.String(), err)
+	}
+	if chain.Events[2].Kind != provenance.KindCommitted {
+		t.Fatalf("last event = %s, want committed", chain.Events[2].Kind)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/provenance/rounds/4?format=jsonl", nil))
+	if got := w.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Fatalf("content type %q", got)
+	}
+	if lines := strings.Count(w.Body.String(), "\n"); lines != 3 {
+		t.Fatalf("export has %d lines, want 3: %s", lines, w.Body.String())
+	}
+
+	for path, want := range map[string]int{
+		"/api/provenance/unknown":             http.StatusNotFound,
+		"/api/provenance/rounds/5":            http.StatusNotFound,
+		"/api/provenance/rounds/x":            http.StatusBadRequest,
+		"/api/provenance/rounds/4?format=xml": http.StatusBadRequest,
+	} {
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Fatalf("%s status = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestFederationUpdatesAreScopedToBoundNodes(t *testing.T) {
+	configureProofAuthForTests(t)
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "node-0", MinVerifications: 1}))
+	for _, id := range []string{"traffic", "parking"} {
+		if _, err := registry.Create(id); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetVerifier(p2p.NewVerifier("node-0", 1, time.Second))
+	h.SetFederationRegistry(registry)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	post := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(body)))
+		r.Header.Set("Authorization", "Bearer test-token")
+		r.Header.Set("X-API-Role", "node")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	w := post("/api/v1/register", protocol.RegistrationRequest{NodeID: "edge-1", Federations: []string{"traffic"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("register status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var resp protocol.RegistrationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode registration: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Federations, []string{"traffic"}) {
+		t.Fatalf("bound federations = %v, want [traffic]", resp.Federations)
+	}
+	if w := post("/api/v1/register", protocol.RegistrationRequest{NodeID: "edge-2", Federations: []string{"weather"}}); w.Code != http.StatusNotFound {
+		t.Fatalf("status for an unknown federation = %d, want 404", w.Code)
+	}
+
+	update := protocol.ModelUpdate{NodeID: "edge-1", Round: 1, Weights: batch.Update{Weights: []float64{1, 2}}.Bytes(), Metrics: protocol.Metrics{Samples: 10}}
+	if w := post("/api/traffic/updates", update); w.Code != http.StatusOK {
+		t.Fatalf("member update status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if w := post("/api/v1/parking/updates", update); w.Code != http.StatusForbidden {
+		t.Fatalf("non-member update status = %d, want 403", w.Code)
+	}
+	update.Round = 2
+	update.FederationID = "parking"
+	if w := post("/api/traffic/updates", update); w.Code != http.StatusBadRequest {
+		t.Fatalf("mismatched federation status = %d, want 400", w.Code)
+	}
+
+	traffic, _ := registry.Get("traffic")
+	parking, _ := registry.Get("parking")
+	if traffic.Pending(1) != 1 || parking.Pending(1) != 0 {
+		t.Fatalf("pending updates traffic=%d parking=%d, want 1 and 0", traffic.Pending(1), parking.Pending(1))
+	}
+	if len(traffic.Peers.GetActivePeers()) != 1 || len(parking.Peers.GetActivePeers()) != 0 {
+		t.Fatal("expected edge-1 in the traffic peer table only")
+	}
+}
+
+func TestGetAggregationRoundsReportsHistory(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/aggregation/rounds", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status without history = %d, want 503", w.Code)
+	}
+
+	ctx := context.Background()
+	aggregator := consensus.NewDistributedAggregator("node-1", []string{"peer1"}, time.Second)
+	for _, nodeID := range []string{"node-1", "peer1"} {
+		if err := aggregator.SubmitModel(ctx, nodeID, []byte{1, 2}); err != nil {
+			t.Fatalf("submit %s: %v", nodeID, err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := aggregator.AggregateWithConsensus(ctx); err != nil {
+			t.Fatalf("aggregate: %v", err)
+		}
+	}
+	h.SetAggregationHistory(aggregator)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/aggregation/rounds", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Rounds  []consensus.AggregationRound `json:"rounds"`
+		Summary consensus.AggregationSummary `json:"summary"`
+		Count   int                          `json:"count"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Count != 3 || len(resp.Rounds) != 3 || resp.Rounds[2].Round != 3 || resp.Rounds[2].Participants != 2 {
+		t.Fatalf("unexpected rounds: %+v", resp)
+	}
+	if resp.Summary.Committed != 3 || resp.Summary.ParticipantsP50 != 2 {
+		t.Fatalf("unexpected summary: %+v", resp.Summary)
+	}
+}
+
+func TestPeersAndMetricsPagination(t *testing.T) {
+	network := p2p.NewNetwork("node-1", 1, time.Second)
+	for i := 0; i < 5; i++ {
+		network.AddPeer(fmt.Sprintf("peer-%d", i), "addr", float64(i)/4)
+	}
+	collector := monitoring.NewCollector(100)
+	for i := 0; i < 5; i++ {
+		collector.Record(monitoring.MetricLoss, float64(i), nil, "node-1")
+		collector.Record(monitoring.MetricAccuracy, float64(i), nil, "node-1")
+	}
+	collector.Record(monitoring.MetricLoss, 9, map[string]string{"phase": "train"}, "node-2")
+	h := NewHandler(nil, nil, collector, network)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	get := func(path string, out interface{}) int {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+				t.Fatalf("decode %s: %v", path, err)
+			}
+		}
+		return w.Code
+	}
+
+	var peers struct {
+		Peers      []map[string]interface{} `json:"peers"`
+		Total      int                      `json:"total"`
+		TotalPeers int                      `json:"total_peers"`
+		NextCursor string                   `json:"next_cursor"`
+	}
+	var ids []string
+	path := "/api/peers?min_reputation=0.25&limit=2"
+	for {
+		if code := get(path, &peers); code != http.StatusOK {
+			t.Fatalf("GET %s = %d", path, code)
+		}
+		if peers.Total != 4 || peers.TotalPeers != 5 {
+			t.Fatalf("unexpected peer totals: %+v", peers)
+		}
+		for _, peer := range peers.Peers {
+			ids = append(ids, peer["id"].(string))
+		}
+		if peers.NextCursor == "" {
+			break
+		}
+		path = "/api/peers?min_reputation=0.25&limit=2&cursor=" + peers.NextCursor
+	}
+	if fmt.Sprint(ids) != "[peer-1 peer-2 peer-3 peer-4]" {
+		t.Fatalf("walked peers %v", ids)
+	}
+
+	var metrics struct {
+		Metrics    []metricEntry `json:"metrics"`
+		Total      int           `json:"total"`
+		NextCursor string        `json:"next_cursor"`
+	}
+	if code := get("/api/v1/metrics?type=accuracy&limit=3", &metrics); code != http.StatusOK {
+		t.Fatalf("GET metrics = %d", code)
+	}
+	if len(metrics.Metrics) != 3 || metrics.Total != 5 || metrics.NextCursor != "6" || metrics.Metrics[0].Type != "accuracy" {
+		t.Fatalf("unexpected metrics page: %+v", metrics)
+	}
+	metrics.NextCursor = ""
+	if code := get("/api/v1/metrics?type=accuracy&limit=3&cursor=6", &metrics); code != http.StatusOK || len(metrics.Metrics) != 2 || metrics.NextCursor != "" {
+		t.Fatalf("unexpected last metrics page: %d %+v", code, metrics)
+	}
+
+	if code := get("/api/v1/metrics?node=node-2&label=phase=train", &metrics); code != http.StatusOK || metrics.Total != 1 || metrics.Metrics[0].Value != 9 {
+		t.Fatalf("unexpected labelled metrics: %d %+v", code, metrics)
+	}
+	if code := get("/api/v1/metrics?node=node-1&label=phase=train", &metrics); code != http.StatusOK || metrics.Total != 0 {
+		t.Fatalf("unexpected metrics for node-1: %d %+v", code, metrics)
+	}
+
+	for _, bad := range []string{"/api/peers?limit=0", "/api/peers?status=banned", "/api/peers?cursor=!!", "/api/peers?max_reputation=x", "/api/metrics?since=yesterday", "/api/metrics?cursor=-1", "/api/metrics?limit=5000", "/api/metrics?label=phase"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, bad, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("GET %s = %d, want 400", bad, w.Code)
+		}
+	}
+}
+
+func TestFederationUpdatesForAnotherSpecAreRefused(t *testing.T) {
+	configureProofAuthForTests(t)
+	spec := protocol.ModelSpec{Name: "mlp", Version: 3, Dimension: 2, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp"))}
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "node-0", MinVerifications: 1, ModelSpec: &spec}))
+	if _, err := registry.Create("traffic"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := registry.Bind("edge-1", []string{"traffic"}); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetFederationRegistry(registry)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	serve := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		body := ""
+		if payload != nil {
+			encoded, _ := json.Marshal(payload)
+			body = string(encoded)
+		}
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer test-token")
+		r.Header.Set("X-API-Role", "node")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(http.MethodGet, "/api/v1/traffic/rounds/1/task", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("task status = %d: %s", w.Code, w.Body.String())
+	}
+	var task protocol.TrainingTask
+	if err := json.Unmarshal(w.Body.Bytes(), &task); err != nil {
+		t.Fatalf("decode task: %v", err)
+	}
+	if task.Spec == nil || task.Spec.Version != 3 || task.Spec.Digest() != spec.Digest() {
+		t.Fatalf("task spec = %+v", task.Spec)
+	}
+
+	update := protocol.ModelUpdate{NodeID: "edge-1", Round: 1, Weights: batch.Update{Weights: []float64{1, 2}}.Bytes(), Metrics: protocol.Metrics{Samples: 10}, SpecVersion: 2}
+	if w := serve(http.MethodPost, "/api/traffic/updates", update); w.Code != http.StatusConflict {
+		t.Fatalf("stale spec update status = %d, want 409: %s", w.Code, w.Body.String())
+	}
+	update.SpecVersion = task.Spec.Version
+	if w := serve(http.MethodPost, "/api/traffic/updates", update); w.Code != http.StatusOK {
+		t.Fatalf("current spec update status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestFederationIngestIsBoundedByTheInboundGuard(t *testing.T) {
+	configureProofAuthForTests(t)
+	spec := protocol.ModelSpec{Name: "mlp", Version: 1, Dimension: 2, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp"))}
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "node-0", MinVerifications: 1, ModelSpec: &spec}))
+	if _, err := registry.Create("traffic"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := registry.Bind("edge-1", []string{"traffic"}); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	limits := p2p.DefaultInboundLimits()
+	limits.MaxEncryptedBytes[p2p.KindVote] = 256
+	guard := p2p.NewInboundGuard(limits)
+	var violations []string
+	guard.SetViolationHandler(func(peerID string, _ error) { violations = append(violations, peerID) })
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetFederationRegistry(registry)
+	h.SetInboundGuard(guard)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	serve := func(path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer test-token")
+		r.Header.Set("X-API-Role", "node")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	vote := `{"node_id":"edge-1","proposal_id":"` + strings.Repeat("p", 512) + `"}`
+	if w := serve("/api/traffic/votes", vote); w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized vote status = %d, want 413: %s", w.Code, w.Body.String())
+	}
+	update, _ := json.Marshal(protocol.ModelUpdate{NodeID: "edge-1", Round: 1, Weights: batch.Update{Weights: []float64{1, 2, 3}}.Bytes(), SpecVersion: 1})
+	if w := serve("/api/traffic/updates", string(update)); w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("overlong update status = %d, want 413: %s", w.Code, w.Body.String())
+	}
+	if len(violations) != 2 || violations[1] != "edge-1" {
+		t.Fatalf("violations = %v, want the vote's sender then edge-1", violations)
+	}
+}
+
+func TestFederationStatusReturnsDegradedNodesToProbation(t *testing.T) {
+	configureProofAuthForTests(t)
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "node-0", MinVerifications: 1}))
+	if _, err := registry.Create("traffic"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := registry.Bind("edge-1", []string{"traffic"}); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	probation, err := consensus.NewProbation(consensus.DefaultProbationConfig())
+	if err != nil {
+		t.Fatalf("new probation: %v", err)
+	}
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetFederationRegistry(registry)
+	h.SetProbation(probation)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	serve := func(path string, update protocol.StatusUpdate) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(update)
+		r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		r.Header.Set("Authorization", "Bearer test-token")
+		r.Header.Set("X-API-Role", "node")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := serve("/api/v1/traffic/heartbeats", protocol.StatusUpdate{NodeID: "edge-2", Status: "training", AttestationDegraded: true}); w.Code == http.StatusOK {
+		t.Fatal("status from a node outside the federation accepted")
+	}
+	if w := serve("/api/v1/traffic/heartbeats", protocol.StatusUpdate{NodeID: "edge-1", Status: "training", Round: 4, AttestationDegraded: true}); w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if !probation.OnProbation("edge-1") {
+		t.Fatal("a degraded TPM heartbeat did not put the node on probation")
+	}
+}
+
+func TestBatchTunerTogglesAtRuntime(t *testing.T) {
+	configureProofAuthForTests(t)
+
+	h := NewHandler(nil, nil, nil, nil)
+	tuner, err := batch.NewAutoTuner(batch.AutoTunerConfig{Min: 1, Max: 50})
+	if err != nil {
+		t.Fatalf("new tuner: %v", err)
+	}
+	h.SetBatchTuner(tuner)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	toggle := func(role, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/batch_tuner", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("X-API-Role", role)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	if w := toggle("verifier", `{"enabled":true}`); w.Code != http.StatusForbidden {
+		t.Fatalf("verifier toggle: status = %d, want 403", w.Code)
+	}
+	if w := toggle("admin", `{}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("toggle without enabled: status = %d, want 400", w.Code)
+	}
+	w := toggle("admin", `{"enabled":true}`)
+	var status batch.AutoTunerStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil || w.Code != http.StatusOK || !status.Enabled || !tuner.Enabled() {
+		t.Fatalf("enable: status %d, %+v, %v", w.Code, status, err)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/batch_tuner", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"enabled":true`) {
+		t.Fatalf("GET: status %d, body %s", w.Code, w.Body.String())
+	}
+	if w := toggle("admin", `{"enabled":false}`); w.Code != http.StatusOK || tuner.Enabled() {
+		t.Fatalf("disable: status %d", w.Code)
+	}
+}
+
+func TestEvaluationEndpointTakesReportsAndServesEstimates(t *testing.T) {
+	configureProofAuthForTests(t)
+	weights := []byte{1, 2, 3}
+	store := modeldist.NewModelStore(8)
+	cert := modeldist.CommitCertificate{Round: 1, ModelDigest: protocol.WeightsDigest(weights), QuorumSize: 1, Approvals: []string{"agg"}}
+	if _, err := store.Commit(1, weights, 2, nil, cert); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	evaluator := evaluation.NewEvaluator(store)
+	task := protocol.EvaluationTask{Round: 1, ModelDigest: cert.ModelDigest, Metrics: protocol.DefaultEvaluationMetrics()}
+	if err := evaluator.Begin(task, []string{"edge-1"}); err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	h := NewHandler(nil, nil, nil, nil)
+	h.SetModelStore(store)
+	h.SetEvaluator(evaluator)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	post := func(report protocol.EvaluationReport) int {
+		body, _ := json.Marshal(report)
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/evaluation", strings.NewReader(string(body)))
+		r.Header.Set("Authorization", "Bearer test-token")
+		r.Header.Set("X-API-Role", "node")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	report := protocol.EvaluationReport{NodeID: "edge-1", Round: 1, ModelDigest: cert.ModelDigest, Metrics: protocol.Metrics{Loss: 0.3, Accuracy: 0.8, Samples: 40}}
+	if code := post(report); code != http.StatusOK {
+		t.Fatalf("report status = %d, want 200", code)
+	}
+	report.NodeID = "edge-2"
+	if code := post(report); code != http.StatusForbidden {
+		t.Fatalf("unsampled report status = %d, want 403", code)
+	}
+	report.Round = 7
+	if code := post(report); code != http.StatusNotFound {
+		t.Fatalf("unknown round status = %d, want 404", code)
+	}
+	if _, err := evaluator.Complete(1); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/evaluation?from=1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("evaluation status = %d, want 200", w.Code)
+	}
+	var payload struct {
+		Evaluations []modeldist.Evaluation `json:"evaluations"`
+		Count       int                    `json:"count"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Count != 1 || payload.Evaluations[0].Accuracy.Mean != 0.8 || payload.Evaluations[0].Samples != 40 {
+		t.Fatalf("evaluations = %+v", payload)
+	}
+}
+
+func TestDebugEndpointsRequireAdmin(t *testing.T) {
+	configureProofAuthForTests(t)
+
+	h := NewHandler(nil, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	get := func(path, token, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if role != "" {
+			req.Header.Set("X-API-Role", role)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, path := range []string{
+		"/api/admin/debug/pprof/",
+		"/api/v1/admin/debug/pprof/goroutine?debug=1",
+		"/api/admin/debug/pprof/cmdline",
+		"/api/admin/timings",
+		"/api/v1/admin/timings",
+	} {
+		if w := get(path, "", ""); w.Code != http.StatusUnauthorized {
+			t.Fatalf("%s without token: status = %d, want 401", path, w.Code)
+		}
+		if w := get(path, "wrong-token", "admin"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("%s with wrong token: status = %d, want 401", path, w.Code)
+		}
+		if w := get(path, "test-token", "node"); w.Code != http.StatusForbidden {
+			t.Fatalf("%s as node: status = %d, want 403", path, w.Code)
+		}
+		if w := get(path, "test-token", "admin"); w.Code != http.StatusOK {
+			t.Fatalf("%s as admin: status = %d, want 200: %s", path, w.Code, w.Body.String())
+		}
+	}
+	if w := get("/api/admin/debug/pprof/goroutine?debug=1", "test-token", "admin"); !strings.Contains(w.Body.String(), "goroutine") {
+		t.Fatalf("goroutine profile body = %q", w.Body.String())
+	}
+}
+
+func TestTimingsAttributeConsensusPhasesToTheirRound(t *testing.T) {
+	configureProofAuthForTests(t)
+	ctx := context.Background()
+	// Far above any round another test proposes, so the default timers
+	// attribute only this test's observations to it.
+	const round = 1 << 30
+
+	coordinator := consensus.NewCoordinator("node-0", 4, time.Minute)
+	proposalID, err := coordinator.ProposeModel(ctx, &consensus.ModelProposal{Round: round, Weights: []byte("w"), ProposerID: "node-0", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := coordinator.CastVote(ctx, &consensus.Vote{NodeID: fmt.Sprintf("node-%d", i), ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote %d: %v", i, err)
+		}
+	}
+	if err := coordinator.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	h := NewHandler(nil, nil, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/timings", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	req.Header.Set("X-API-Role", "admin")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var breakdown profiling.Breakdown
+	if err := json.Unmarshal(w.Body.Bytes(), &breakdown); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	var attributed *profiling.RoundTiming
+	for i := range breakdown.Rounds {
+		if breakdown.Rounds[i].Round == round {
+			attributed = &breakdown.Rounds[i]
+		}
+	}
+	if attributed == nil {
+		t.Fatalf("round %d missing from %+v", round, breakdown.Rounds)
+	}
+	counts := make(map[string]int64)
+	for _, phase := range attributed.Phases {
+		counts[phase.Phase] = phase.Count
+	}
+	want := map[string]int64{"consensus.propose": 1, "consensus.vote": 4, "consensus.commit": 1}
+	for phase, count := range want {
+		if counts[phase] != count {
+			t.Fatalf("round %d %s count = %d, want %d (%+v)", round, phase, counts[phase], count, attributed.Phases)
+		}
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("round %d has unexpected phases: %+v", round, attributed.Phases)
+	}
+
+	var flame *profiling.FlameNode
+	for i := range breakdown.Flame.Children {
+		if breakdown.Flame.Children[i].Name == fmt.Sprintf("round %d", round) {
+			flame = &breakdown.Flame.Children[i]
+		}
+	}
+	if flame == nil || len(flame.Children) != 1 || flame.Children[0].Name != "consensus" || len(flame.Children[0].Children) != 3 {
+		t.Fatalf("flame node for round %d = %+v", round, flame)
+	}
+}
+
+func TestIslandCacheAdminListsPurgesAndRequeues(t *testing.T) {
+	configureProofAuthForTests(t)
+	mgr := island.NewManager(time.Minute, 10, func() bool { return false })
+	for round := 1; round <= 5; round++ {
+		_ = mgr.CacheUpdate(island.Update{Round: round, Timestamp: time.Now(), ModelDelta: []byte("secret-weights"), PeerID: "node-1"})
+	}
+	h := NewHandler(nil, mgr, nil, nil)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	do := func(method, path, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("X-API-Role", role)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	type cachePage struct {
+		Updates    []island.CachedUpdateInfo `json:"updates"`
+		Total      int                       `json:"total"`
+		NextCursor string                    `json:"next_cursor"`
+	}
+	list := func(path string) cachePage {
+		t.Helper()
+		w := do(http.MethodGet, path, "admin")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: status = %d: %s", path, w.Code, w.Body.String())
+		}
+		if strings.Contains(w.Body.String(), "secret-weights") || strings.Contains(w.Body.String(), "c2VjcmV0") {
+			t.Fatalf("listing leaks model deltas: %s", w.Body.String())
+		}
+		var page cachePage
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return page
+	}
+	rounds := func() []int {
+		var out []int
+		for path := "/api/admin/island/cache?limit=2"; ; {
+			page := list(path)
+			for _, update := range page.Updates {
+				out = append(out, update.Round)
+			}
+			if page.NextCursor == "" {
+				return out
+			}
+			path = "/api/v1/admin/island/cache?limit=2&cursor=" + page.NextCursor
+		}
+	}
+
+	if w := do(http.MethodGet, "/api/admin/island/cache", "node"); w.Code != http.StatusForbidden {
+		t.Fatalf("listing as node: status = %d, want 403", w.Code)
+	}
+	first := list("/api/admin/island/cache?limit=2")
+	if len(first.Updates) != 2 || first.Total != 5 || first.NextCursor != first.Updates[1].ID || first.Updates[0].Size != len("secret-weights") {
+		t.Fatalf("unexpected first page %+v", first)
+	}
+	if got := rounds(); fmt.Sprint(got) != "[1 2 3 4 5]" {
+		t.Fatalf("paged rounds = %v", got)
+	}
+
+	all := list("/api/admin/island/cache")
+	if w := do(http.MethodDelete, "/api/admin/island/cache/"+all.Updates[2].ID, "node"); w.Code != http.StatusForbidden {
+		t.Fatalf("purge as node: status = %d, want 403", w.Code)
+	}
+	if w := do(http.MethodDelete, "/api/admin/island/cache/"+all.Updates[2].ID, "admin"); w.Code != http.StatusOK {
+		t.Fatalf("purge: status = %d: %s", w.Code, w.Body.String())
+	}
+	if got := rounds(); fmt.Sprint(got) != "[1 2 4 5]" {
+		t.Fatalf("rounds after purging round 3 = %v", got)
+	}
+	if w := do(http.MethodDelete, "/api/admin/island/cache/"+all.Updates[2].ID, "admin"); w.Code != http.StatusNotFound {
+		t.Fatalf("purging again: status = %d, want 404", w.Code)
+	}
+	if w := do(http.MethodPost, "/api/v1/admin/island/cache/requeue/"+all.Updates[4].ID, "admin"); w.Code != http.StatusOK {
+		t.Fatalf("requeue: status = %d: %s", w.Code, w.Body.String())
+	}
+	if got := rounds(); fmt.Sprint(got) != "[5 1 2 4]" {
+		t.Fatalf("rounds after requeueing round 5 = %v", got)
+	}
+
+	audit := mgr.CacheAudit()
+	if len(audit) != 2 {
+		t.Fatalf("expected 2 audit records, got %+v", audit)
+	}
+	if audit[0].Action != island.CacheActionPurge || audit[0].UpdateID != all.Updates[2].ID || audit[0].Round != 3 || audit[0].Actor != "admin" {
+		t.Fatalf("unexpected purge record %+v", audit[0])
+	}
+	if audit[1].Action != island.CacheActionRequeue || audit[1].Round != 5 {
+		t.Fatalf("unexpected requeue record %+v", audit[1])
+	}
+}
+
+func TestHTTPSenderDeliversThroughMessageEndpoint(t *testing.T) {
+	configureProofAuthForTests(t)
+	h := NewHandler(nil, nil, nil, nil)
+	receiver := p2p.NewReceiver()
+	var mu sync.Mutex
+	var got []string
+	receiver.Handle("failover/heartbeat", func(_ context.Context, from string, payload []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, from+":"+string(payload))
+		return nil
+	})
+	h.SetMessageReceiver(receiver)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	sender := p2p.NewHTTPSender("primary-1", "test-token", server.Client(), map[string]string{"standby-1": server.URL + "/"})
+	ctx := context.Background()
+	if err := sender.Send(ctx, "standby-1", p2p.OutboundMessage{Topic: "failover/heartbeat", Payload: []byte(`{"epoch":1}`)}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	mu.Lock()
+	if len(got) != 1 || got[0] != `primary-1:{"epoch":1}` {
+		t.Fatalf("delivered %q, want the heartbeat from primary-1", got)
+	}
+	mu.Unlock()
+
+	if err := sender.Send(ctx, "standby-1", p2p.OutboundMessage{Topic: "failover/unknown"}); !errors.Is(err, p2p.ErrSendFailed) {
+		t.Fatalf("unhandled topic: got %v, want ErrSendFailed", err)
+	}
+	if err := sender.Send(ctx, "standby-2", p2p.OutboundMessage{Topic: "failover/heartbeat"}); !errors.Is(err, p2p.ErrPeerNotFound) {
+		t.Fatalf("unknown peer: got %v, want ErrPeerNotFound", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+// SetHealthEvaluator attaches the evaluator behind /api/health/detailed.
+// Status transitions are recorded in the metrics collector and exported as
+// the mohawk_node_health_status gauge.
+func (h *Handler) SetHealthEvaluator(evaluator *monitoring.HealthEvaluator) {
+	h.health = evaluator
+	if evaluator == nil {
+		return
+	}
+	collector := h.metrics
+	evaluator.AddStatusChangeListener(func(change monitoring.HealthChange) {
+		observeHealthStatus(change.To)
+		if collector != nil {
+			collector.Record(monitoring.MetricHealth, float64(change.To.Severity()), map[string]string{
+				"from":  string(change.From),
+				"to":    string(change.To),
+				"score": strconv.FormatFloat(change.Report.Score, 'f', 3, 64),
+			}, "")
+		}
+	})
+}
+
+// GetDetailedHealth returns the aggregate node status with the score and
+// explanation for each subsystem.
+func (h *Handler) GetDetailedHealth(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.health == nil {
+		http.Error(w, "health evaluator unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	if h.island != nil {
+		cached, maxCached := h.island.GetCachedUpdateStats()
+		h.health.ObserveIsland(h.island.CurrentMode() == island.ModeIsland, cached, maxCached)
+	}
+
+	writeJSON(w, h.health.Evaluate())
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// SetInboundGuard bounds federation update and vote bodies by guard's
+// limits in place of p2p.DefaultInboundLimits. Oversized bodies, reported
+// under the request's remote address, and updates declaring more weights
+// than the federation's model, reported under their node, reach guard's
+// violation handler.
+func (h *Handler) SetInboundGuard(guard *p2p.InboundGuard) {
+	h.inbound = guard
+}
+
+// decodeInbound decodes r's JSON body, a message of kind, into v, reading
+// no more than one byte past kind's limit. It writes the error response and
+// returns false when the body is too large or malformed.
+func (h *Handler) decodeInbound(w http.ResponseWriter, r *http.Request, kind p2p.MessageKind, v interface{}) bool {
+	body, ok := h.readInbound(w, r, kind)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// readInbound reads r's body, a message of kind, reading no more than one
+// byte past kind's limit. It writes the error response and returns false
+// when the body is too large.
+func (h *Handler) readInbound(w http.ResponseWriter, r *http.Request, kind p2p.MessageKind) ([]byte, bool) {
+	limit, ok := h.inbound.Limit(kind)
+	if !ok {
+		writeError(w, h.inbound.CheckPayload(r.RemoteAddr, kind, nil))
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(limit)+1))
+	var tooLarge *http.MaxBytesError
+	if err != nil && !errors.As(err, &tooLarge) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := h.inbound.CheckPayload(r.RemoteAddr, kind, body); err != nil {
+		writeError(w, err)
+		return nil, false
+	}
+	return body, true
+}
+
+// declaredWeights is the number of weights whichever encoding update
+// carries declares, counted without decoding them.
+func declaredWeights(update *protocol.ModelUpdate) int {
+	switch {
+	case update.Quantized != nil:
+		return update.Quantized.Len()
+	case update.Sparse != nil:
+		return update.Sparse.Dim
+	default:
+		return len(update.Weights) / 8
+	}
+}
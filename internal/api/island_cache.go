@@ -0,0 +1,100 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+)
+
+func requireIslandAdminAuth(w http.ResponseWriter, r *http.Request) bool {
+	return requireScopedAuth(w, r, "MOHAWK_API_ISLAND_ALLOWED_ROLES", "admin")
+}
+
+// GetIslandCache lists the island manager's cached updates in sync order,
+// a page at a time: round, peer, size, timestamp, and a metadata summary,
+// never the model deltas. ?cursor= continues after the last ID served.
+func (h *Handler) GetIslandCache(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if !requireIslandAdminAuth(w, r) {
+		return
+	}
+	if h.island == nil {
+		http.Error(w, "island mode unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	limit, ok := parsePageLimit(r.URL.Query().Get("limit"))
+	if !ok {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+		return
+	}
+	page, more, err := h.island.CachedUpdatePage(strings.TrimSpace(r.URL.Query().Get("cursor")), limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	cached, maxCached := h.island.GetCachedUpdateStats()
+	response := map[string]interface{}{
+		"updates":     page,
+		"total":       cached,
+		"max_cached":  maxCached,
+		"next_cursor": "",
+	}
+	if more && len(page) > 0 {
+		response["next_cursor"] = page[len(page)-1].ID
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, response)
+}
+
+// DeleteIslandCacheUpdate purges one cached update, keeping the rest in
+// order, and records the purge in the island cache audit trail.
+func (h *Handler) DeleteIslandCacheUpdate(w http.ResponseWriter, r *http.Request) {
+	h.islandCacheAction(w, r, http.MethodDelete, func(id, actor string) (island.CacheAuditRecord, error) {
+		return h.island.PurgeCachedUpdate(id, actor)
+	})
+}
+
+// RequeueIslandCacheUpdate moves one cached update to the front of the
+// sync order and records the move in the island cache audit trail.
+func (h *Handler) RequeueIslandCacheUpdate(w http.ResponseWriter, r *http.Request) {
+	h.islandCacheAction(w, r, http.MethodPost, func(id, actor string) (island.CacheAuditRecord, error) {
+		return h.island.RequeueCachedUpdate(id, actor)
+	})
+}
+
+// islandCacheAction applies action to the cached update named by the {id}
+// path value on behalf of the caller's role, and mirrors the audit record
+// into the ledger state when one is attached.
+func (h *Handler) islandCacheAction(w http.ResponseWriter, r *http.Request, method string, action func(id, actor string) (island.CacheAuditRecord, error)) {
+	if r.Method != method {
+		methodNotAllowed(w)
+		return
+	}
+	if !requireIslandAdminAuth(w, r) {
+		return
+	}
+	if h.island == nil {
+		http.Error(w, "island mode unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	actor := strings.ToLower(strings.TrimSpace(r.Header.Get("X-API-Role")))
+	record, err := action(r.PathValue("id"), actor)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if h.blockchain != nil {
+		auditKey := fmt.Sprintf("api_island_cache_audit:%d", record.At.UnixNano())
+		_ = h.blockchain.StateDB.Set(auditKey, record)
+	}
+	writeJSON(w, map[string]interface{}{
+		"status": record.Action,
+		"audit":  record,
+	})
+}
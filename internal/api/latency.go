@@ -0,0 +1,28 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"net/http"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+)
+
+// SetTopology attaches the region topology whose derived round budgets
+// /api/latency_budgets reports.
+func (h *Handler) SetTopology(topology *scheduler.Topology) {
+	h.topology = topology
+}
+
+// GetLatencyBudgets reports the round budget each region's regional rounds
+// and the global rounds currently derive from their measured RTTs.
+func (h *Handler) GetLatencyBudgets(w http.ResponseWriter, r *http.Request) {
+	if h.topology == nil {
+		http.Error(w, "region topology unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"policy":  h.topology.Policy(),
+		"budgets": h.topology.Budgets(),
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+)
+
+// SetMessageReceiver accepts messages peers' p2p.HTTPSender posts on
+// /api/p2p/messages and delivers them to receiver.
+func (h *Handler) SetMessageReceiver(receiver *p2p.Receiver) {
+	h.receiver = receiver
+}
+
+// PostP2PMessage delivers a message posted by a peer's p2p.HTTPSender. The
+// body, the message payload, is bounded like a whole model update.
+func (h *Handler) PostP2PMessage(w http.ResponseWriter, r *http.Request) {
+	if !ensurePostMethod(w, r) {
+		return
+	}
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.receiver == nil {
+		http.Error(w, "p2p messaging unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	from := strings.TrimSpace(r.Header.Get(p2p.HeaderFrom))
+	topic := strings.TrimSpace(r.Header.Get(p2p.HeaderTopic))
+	if from == "" || topic == "" {
+		http.Error(w, "missing message sender or topic", http.StatusBadRequest)
+		return
+	}
+	priority, _ := strconv.Atoi(r.Header.Get(p2p.HeaderPriority))
+	payload, ok := h.readInbound(w, r, p2p.KindUpdate)
+	if !ok {
+		return
+	}
+	msg := p2p.OutboundMessage{Topic: topic, Payload: payload, Priority: p2p.Priority(priority), Enqueued: time.Now()}
+	if err := h.receiver.Deliver(r.Context(), from, msg); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost"
 )
 
 var (
@@ -38,6 +41,101 @@ var (
 			Help: "Current number of entries stored in the in-memory proof ledger.",
 		},
 	)
+
+	peerReputationSlope = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mohawk_peer_reputation_slope_per_hour",
+			Help: "Least-squares reputation trend per peer over the recent trend window.",
+		},
+		[]string{"peer_id"},
+	)
+
+	consensusVotesRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mohawk_consensus_votes_rejected_total",
+			Help: "Total number of consensus votes rejected by vote middleware and reason.",
+		},
+		[]string{"middleware", "reason"},
+	)
+
+	consensusVoteDedup = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mohawk_consensus_vote_dedup_total",
+			Help: "Total number of delivered consensus votes by dedup result (hit, miss, or conflict).",
+		},
+		[]string{"result"},
+	)
+
+	workQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mohawk_work_queue_depth",
+			Help: "Tasks waiting in the node-agent work scheduler by work class.",
+		},
+		[]string{"class"},
+	)
+
+	aggregationRoundsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mohawk_aggregation_rounds_total",
+			Help: "Total number of distributed aggregation rounds by outcome (committed or failed).",
+		},
+		[]string{"outcome"},
+	)
+
+	aggregationRoundDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "mohawk_aggregation_round_duration_seconds",
+			Help:    "Distributed aggregation round duration in seconds.",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+	)
+
+	wasmVerificationLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mohawk_wasm_verification_latency_seconds",
+			Help:    "Wasm proof verification latency in seconds by module digest, outcome, and cold or warm start.",
+			Buckets: []float64{0.0005, 0.001, 0.0025, 0.005, 0.0075, 0.01, 0.015, 0.025, 0.05, 0.1, 0.25},
+		},
+		[]string{"module", "outcome", "start"},
+	)
+
+	wasmVerificationSLOCompliance = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "mohawk_wasm_verification_slo_compliance",
+			Help: "Share of recent warm Wasm verifications finishing within the Theorem 5 bound.",
+		},
+	)
+
+	wasmVerificationSLOTarget = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "mohawk_wasm_verification_slo_target",
+			Help: "Configured target share of Wasm verifications within the Theorem 5 bound.",
+		},
+	)
+
+	wasmReclaimedBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mohawk_wasm_reclaimed_bytes_total",
+			Help: "Linear memory freed by reclaiming idle Wasm module instances, by module digest.",
+		},
+		[]string{"module"},
+	)
+
+	wasmReinstantiateLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mohawk_wasm_reinstantiate_latency_seconds",
+			Help:    "Time to re-instantiate a reclaimed Wasm module from the compilation cache, by module digest.",
+			Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+		},
+		[]string{"module"},
+	)
+
+	nodeHealthStatus = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "mohawk_node_health_status",
+			Help: "Aggregate node health: 0 healthy or unknown, 1 degraded, 2 critical.",
+		},
+	)
 )
 
 func init() {
@@ -46,6 +144,18 @@ func init() {
 		proofVerificationLatency,
 		ledgerEventsTotal,
 		ledgerEntriesGauge,
+		peerReputationSlope,
+		consensusVotesRejected,
+		consensusVoteDedup,
+		workQueueDepth,
+		aggregationRoundsTotal,
+		aggregationRoundDuration,
+		wasmVerificationLatency,
+		wasmVerificationSLOCompliance,
+		wasmVerificationSLOTarget,
+		wasmReclaimedBytesTotal,
+		wasmReinstantiateLatency,
+		nodeHealthStatus,
 	)
 }
 
@@ -62,3 +172,61 @@ func observeLedgerEvent(eventType string, currentEntries int) {
 	ledgerEventsTotal.WithLabelValues(eventType).Inc()
 	ledgerEntriesGauge.Set(float64(currentEntries))
 }
+
+func observeReputationTrend(peerID string, slopePerHour float64) {
+	peerReputationSlope.WithLabelValues(peerID).Set(slopePerHour)
+}
+
+// ObserveVoteRejection counts a vote rejected by consensus vote middleware.
+// Install it with consensus.Coordinator.SetVoteRejectionObserver.
+func ObserveVoteRejection(middleware, reason string) {
+	consensusVotesRejected.WithLabelValues(middleware, reason).Inc()
+}
+
+// ObserveVoteDedup counts a delivered vote by how the vote dedup window
+// classified it. Install it with consensus.VoteDedup.SetObserver.
+func ObserveVoteDedup(result consensus.DedupResult) {
+	consensusVoteDedup.WithLabelValues(string(result)).Inc()
+}
+
+// ObserveWorkQueueDepth records a work class's queue depth. Install it with
+// the node-agent work scheduler's depth observer.
+func ObserveWorkQueueDepth(class string, depth int) {
+	workQueueDepth.WithLabelValues(class).Set(float64(depth))
+}
+
+// ObserveAggregationRound counts a finished aggregation round by outcome and
+// records its duration. Install it with
+// consensus.DistributedAggregator.SetRoundObserver.
+func ObserveAggregationRound(round consensus.AggregationRound) {
+	aggregationRoundsTotal.WithLabelValues(string(round.Outcome)).Inc()
+	aggregationRoundDuration.Observe(round.Duration.Seconds())
+}
+
+// ObserveWasmVerification records one Wasm verification and the SLO status
+// after it. Install it with wasmhost.Host.SetObserver, passing the status
+// monitoring.VerificationSLO.Observe returned.
+func ObserveWasmVerification(sample monitoring.VerificationSample, status monitoring.VerificationSLOStatus) {
+	start := "warm"
+	if sample.Cold {
+		start = "cold"
+	}
+	wasmVerificationLatency.WithLabelValues(sample.Module, sample.Outcome, start).Observe(sample.Latency.Seconds())
+	wasmVerificationSLOCompliance.Set(status.Compliance)
+	wasmVerificationSLOTarget.Set(status.Target)
+}
+
+// ObserveWasmReclaim records one reclaim or re-instantiation of a Wasm
+// module. Install it with wasmhost.Host.SetReclaimObserver.
+func ObserveWasmReclaim(event wasmhost.ReclaimEvent) {
+	switch event.Kind {
+	case wasmhost.ReclaimIdle:
+		wasmReclaimedBytesTotal.WithLabelValues(event.Module).Add(float64(event.Bytes))
+	case wasmhost.ReclaimReinstantiated:
+		wasmReinstantiateLatency.WithLabelValues(event.Module).Observe(event.Latency.Seconds())
+	}
+}
+
+func observeHealthStatus(status monitoring.HealthStatus) {
+	nodeHealthStatus.Set(float64(status.Severity()))
+}
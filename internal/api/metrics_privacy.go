@@ -0,0 +1,171 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+)
+
+var (
+	// Fields of the /api/metrics response treated as rounds, counts, or
+	// continuous values when local DP is applied.
+	metricsRoundFields = []string{"total_rounds"}
+	metricsCountFields = []string{"active_nodes", "churn_joins_total", "churn_leaves_total", "total_metrics", "total"}
+	metricsValueFields = []string{"convergence_rate", "network_lag_ms", "async_staleness_avg_seconds"}
+)
+
+// SetMetricsPrivacy enables local differential privacy on /api/metrics for
+// callers that are not authenticated admins. Pass nil to serve exact values.
+func (h *Handler) SetMetricsPrivacy(privatizer *privacy.MetricsPrivatizer) {
+	h.metricsPrivacy = privatizer
+}
+
+// newMetricsPrivacyFromEnv enables metrics DP when MOHAWK_METRICS_DP_EPSILON
+// is set. MOHAWK_METRICS_DP_SENSITIVITY and MOHAWK_METRICS_DP_BUCKET
+// override the defaults.
+func newMetricsPrivacyFromEnv() *privacy.MetricsPrivatizer {
+	rawEpsilon := strings.TrimSpace(os.Getenv("MOHAWK_METRICS_DP_EPSILON"))
+	if rawEpsilon == "" {
+		return nil
+	}
+	config := privacy.DefaultMetricsDPConfig()
+	var err error
+	if config.Epsilon, err = strconv.ParseFloat(rawEpsilon, 64); err != nil {
+		log.Printf("ignoring MOHAWK_METRICS_DP_EPSILON=%q: %v", rawEpsilon, err)
+		return nil
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_METRICS_DP_SENSITIVITY")); raw != "" {
+		if value, parseErr := strconv.ParseFloat(raw, 64); parseErr == nil {
+			config.Sensitivity = value
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("MOHAWK_METRICS_DP_BUCKET")); raw != "" {
+		if value, parseErr := strconv.Atoi(raw); parseErr == nil {
+			config.RoundBucket, config.CountBucket = value, value
+		}
+	}
+	privatizer, err := privacy.NewMetricsPrivatizer(config)
+	if err != nil {
+		log.Printf("metrics local DP disabled: %v", err)
+		return nil
+	}
+	return privatizer
+}
+
+// isAdminRequest reports whether r carries a valid API token with the admin
+// role. Unlike requireScopedAuth it never writes a response.
+func isAdminRequest(r *http.Request) bool {
+	if strings.ToLower(strings.TrimSpace(r.Header.Get("X-API-Role"))) != "admin" {
+		return false
+	}
+	if authDisabled() {
+		return true
+	}
+	expectedToken, err := loadExpectedToken()
+	if err != nil {
+		return false
+	}
+	receivedToken := extractRequestToken(r)
+	return receivedToken != "" && subtle.ConstantTimeCompare([]byte(expectedToken), []byte(receivedToken)) == 1
+}
+
+// privatizeMetricsResponse returns a copy of a /api/metrics response with
+// rounds and counts bucketed and continuous values Laplace-noised. Raw
+// observations are withheld.
+func privatizeMetricsResponse(p *privacy.MetricsPrivatizer, response map[string]interface{}) map[string]interface{} {
+	config := p.Config()
+	out := make(map[string]interface{}, len(response)+1)
+	for key, value := range response {
+		out[key] = value
+	}
+	out["metrics"] = []metricEntry{}
+	out["next_cursor"] = ""
+	for _, key := range metricsRoundFields {
+		if value, ok := numericValue(out[key]); ok {
+			out[key] = p.BucketRound(int(value))
+		}
+	}
+	for _, key := range metricsCountFields {
+		if value, ok := numericValue(out[key]); ok {
+			out[key] = p.BucketCount(int(value))
+		}
+	}
+	for _, key := range metricsValueFields {
+		if value, ok := numericValue(out[key]); ok {
+			out[key] = p.Noise(value, config.Sensitivity)
+		}
+	}
+
+	if aggregations, ok := out["aggregations"].(map[string]interface{}); ok {
+		noised := make(map[string]interface{}, len(aggregations))
+		for metricType, raw := range aggregations {
+			fields, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			copied := make(map[string]interface{}, len(fields))
+			for field, value := range fields {
+				number, isNumber := numericValue(value)
+				switch {
+				case !isNumber:
+					copied[field] = value
+				case field == "count" || field == "sampled":
+					copied[field] = p.BucketCount(int(number))
+				case field == "sample_rate":
+					copied[field] = value
+				default:
+					copied[field] = p.Noise(number, config.Sensitivity)
+				}
+			}
+			noised[metricType] = copied
+		}
+		out["aggregations"] = noised
+	}
+
+	out["local_dp"] = map[string]interface{}{
+		"epsilon":      config.Epsilon,
+		"sensitivity":  config.Sensitivity,
+		"round_bucket": config.RoundBucket,
+		"count_bucket": config.CountBucket,
+	}
+	return out
+}
+
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// SetPrivacyBudgets attaches the per-shard privacy budget registry served by
+// /api/privacy.
+func (h *Handler) SetPrivacyBudgets(registry *privacy.BudgetRegistry) {
+	h.privacyBudgets = registry
+}
+
+// GetPrivacy serves the budget registry: each shard's allocation and spend
+// and the global (ε, δ) they compose to.
+func (h *Handler) GetPrivacy(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.privacyBudgets == nil {
+		http.Error(w, "privacy budgets unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, h.privacyBudgets.State())
+}
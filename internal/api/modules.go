@@ -0,0 +1,219 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/moduledist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// maxModuleProposalBytes bounds a module proposal body, the module
+// included.
+const maxModuleProposalBytes = 64 << 20
+
+func requireModuleAdminAuth(w http.ResponseWriter, r *http.Request) bool {
+	return requireScopedAuth(w, r, "MOHAWK_API_MODULE_ALLOWED_ROLES", "admin")
+}
+
+// ModuleProposalRequest is the body of a module proposal: the proposal and
+// the module it describes.
+type ModuleProposalRequest struct {
+	Proposal protocol.ModuleProposal `json:"proposal"`
+	Module   []byte                  `json:"module"`
+}
+
+// SetModuleCatalog attaches the verifier modules behind /api/modules, the
+// proposals, votes, and commits that approve them, and the rollout
+// tracking their adoption.
+func (h *Handler) SetModuleCatalog(catalog *moduledist.Catalog, rollout *moduledist.Rollout) {
+	h.modules = catalog
+	h.moduleRollout = rollout
+}
+
+// GetModuleApprovals lists the modules committed for a campaign, oldest
+// first.
+func (h *Handler) GetModuleApprovals(w http.ResponseWriter, r *http.Request) {
+	if h.modules == nil {
+		http.Error(w, "module catalog unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	campaignID := strings.TrimSpace(r.PathValue("campaign"))
+	approvals := h.modules.Approvals(campaignID)
+	writeJSON(w, map[string]interface{}{
+		"campaign_id": campaignID,
+		"approvals":   approvals,
+		"count":       len(approvals),
+	})
+}
+
+// GetModuleRollout reports how far a campaign's latest module has rolled
+// out across the nodes heard from.
+func (h *Handler) GetModuleRollout(w http.ResponseWriter, r *http.Request) {
+	if h.modules == nil || h.moduleRollout == nil {
+		http.Error(w, "module catalog unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, h.moduleRollout.Progress(strings.TrimSpace(r.PathValue("campaign"))))
+}
+
+// GetModule serves a committed module's bytes. Range requests are honoured,
+// so nodes fetch large modules in chunks.
+func (h *Handler) GetModule(w http.ResponseWriter, r *http.Request) {
+	if h.modules == nil {
+		http.Error(w, "module catalog unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	digest := strings.TrimSpace(r.PathValue("digest"))
+	wasmBin, err := h.modules.Module(strings.TrimSpace(r.PathValue("campaign")), digest)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/wasm")
+	w.Header().Set("ETag", `"`+digest+`"`)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(wasmBin))
+}
+
+// PostModuleProposal opens a proposal for the campaign named by the path to
+// approve the module in the body, for the catalog's voters to judge.
+func (h *Handler) PostModuleProposal(w http.ResponseWriter, r *http.Request) {
+	if !ensurePostMethod(w, r) {
+		return
+	}
+	if !requireModuleAdminAuth(w, r) {
+		return
+	}
+	if h.modules == nil {
+		http.Error(w, "module catalog unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req ModuleProposalRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxModuleProposalBytes)).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	campaignID := strings.TrimSpace(r.PathValue("campaign"))
+	if req.Proposal.CampaignID != campaignID {
+		writeError(w, fmt.Errorf("%w: proposal names campaign %s, posted to %s", moduledist.ErrInvalidProposal, req.Proposal.CampaignID, campaignID))
+		return
+	}
+	proposalID, err := h.modules.Propose(r.Context(), req.Proposal, req.Module)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"campaign_id": campaignID,
+		"proposal_id": proposalID,
+		"digest":      req.Proposal.Digest,
+	})
+}
+
+// GetModuleProposals lists the module proposals open for a campaign, by
+// proposal ID, so voters can check each module before voting.
+func (h *Handler) GetModuleProposals(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.modules == nil {
+		http.Error(w, "module catalog unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	campaignID := strings.TrimSpace(r.PathValue("campaign"))
+	proposals := h.modules.OpenProposals(campaignID)
+	writeJSON(w, map[string]interface{}{
+		"campaign_id": campaignID,
+		"proposals":   proposals,
+		"count":       len(proposals),
+	})
+}
+
+// PostModuleVote passes a node's vote on the module proposal open under
+// the path's ID to the catalog.
+func (h *Handler) PostModuleVote(w http.ResponseWriter, r *http.Request) {
+	if !ensurePostMethod(w, r) {
+		return
+	}
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.modules == nil {
+		http.Error(w, "module catalog unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var vote consensus.Vote
+	if !h.decodeInbound(w, r, p2p.KindVote, &vote) {
+		return
+	}
+	if _, ok := h.openModuleProposal(w, r); !ok {
+		return
+	}
+	vote.NodeID = strings.TrimSpace(vote.NodeID)
+	vote.ProposalID = strings.TrimSpace(r.PathValue("id"))
+	if err := h.modules.CastVote(r.Context(), &vote); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"node_id":     vote.NodeID,
+		"proposal_id": vote.ProposalID,
+		"approve":     vote.Approve,
+	})
+}
+
+// PostModuleCommit closes the module proposal open under the path's ID,
+// approving its module if the proposal reached quorum.
+func (h *Handler) PostModuleCommit(w http.ResponseWriter, r *http.Request) {
+	if !ensurePostMethod(w, r) {
+		return
+	}
+	if !requireModuleAdminAuth(w, r) {
+		return
+	}
+	if h.modules == nil {
+		http.Error(w, "module catalog unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	if _, ok := h.openModuleProposal(w, r); !ok {
+		return
+	}
+	approval, err := h.modules.Commit(r.Context(), strings.TrimSpace(r.PathValue("id")))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, approval)
+}
+
+// openModuleProposal returns the module proposal open under the path's ID
+// for the path's campaign, writing the error response if there is none.
+func (h *Handler) openModuleProposal(w http.ResponseWriter, r *http.Request) (protocol.ModuleProposal, bool) {
+	proposalID := strings.TrimSpace(r.PathValue("id"))
+	proposal, err := h.modules.Proposal(proposalID)
+	if err == nil && proposal.CampaignID != strings.TrimSpace(r.PathValue("campaign")) {
+		err = fmt.Errorf("%w: %s", consensus.ErrProposalNotFound, proposalID)
+	}
+	if err != nil {
+		writeError(w, err)
+		return protocol.ModuleProposal{}, false
+	}
+	return proposal, true
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// metricEntry is one observation in a /api/metrics page.
+type metricEntry struct {
+	Seq       uint64            `json:"seq"`
+	Type      string            `json:"type"`
+	Value     float64           `json:"value"`
+	Timestamp time.Time         `json:"timestamp"`
+	NodeID    string            `json:"node_id,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// parsePageLimit parses the limit query parameter: defaultPageLimit when
+// absent, otherwise 1 to maxPageLimit.
+func parsePageLimit(raw string) (int, bool) {
+	limit, ok := parseNonNegativeInt(raw, defaultPageLimit)
+	if !ok || limit == 0 || limit > maxPageLimit {
+		return 0, false
+	}
+	return limit, true
+}
+
+func parseOptionalFloat(raw string) (*float64, bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return nil, true
+	}
+	value, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &value, true
+}
+
+func parseOptionalTime(raw string) (time.Time, bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return time.Time{}, true
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, trimmed)
+	return parsed, err == nil
+}
+
+// Peer cursors are opaque to clients: the base64 of the last peer ID served.
+func encodePeerCursor(peerID string) string {
+	if peerID == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(peerID))
+}
+
+func decodePeerCursor(cursor string) (string, bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(cursor))
+	return string(decoded), err == nil
+}
+
+// peerQuery parses GetPeers' status, min_reputation, max_reputation, cursor,
+// and limit parameters, writing a 400 and returning false if any is invalid.
+func peerQuery(w http.ResponseWriter, r *http.Request) (p2p.PeerFilter, string, int, bool) {
+	query := r.URL.Query()
+	var filter p2p.PeerFilter
+	switch status := p2p.PeerStatus(strings.ToLower(strings.TrimSpace(query.Get("status")))); status {
+	case "", p2p.PeerStatusActive, p2p.PeerStatusInactive:
+		filter.Status = status
+	default:
+		http.Error(w, "invalid status", http.StatusBadRequest)
+		return filter, "", 0, false
+	}
+	var ok bool
+	if filter.MinReputation, ok = parseOptionalFloat(query.Get("min_reputation")); !ok {
+		http.Error(w, "invalid min_reputation", http.StatusBadRequest)
+		return filter, "", 0, false
+	}
+	if filter.MaxReputation, ok = parseOptionalFloat(query.Get("max_reputation")); !ok {
+		http.Error(w, "invalid max_reputation", http.StatusBadRequest)
+		return filter, "", 0, false
+	}
+	after, ok := decodePeerCursor(query.Get("cursor"))
+	if !ok {
+		http.Error(w, "invalid cursor", http.StatusBadRequest)
+		return filter, "", 0, false
+	}
+	limit, ok := parsePageLimit(query.Get("limit"))
+	if !ok {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+		return filter, "", 0, false
+	}
+	return filter, after, limit, true
+}
+
+// metricQuery parses GetMetrics' type (comma-separated or repeated), since,
+// until, cursor, and limit parameters, writing a 400 and returning false if
+// any is invalid.
+func metricQuery(w http.ResponseWriter, r *http.Request) (monitoring.MetricQuery, bool) {
+	query := r.URL.Query()
+	var q monitoring.MetricQuery
+	for _, raw := range query["type"] {
+		for _, metricType := range strings.Split(raw, ",") {
+			if metricType = strings.TrimSpace(metricType); metricType != "" {
+				q.Types = append(q.Types, monitoring.MetricType(metricType))
+			}
+		}
+	}
+	q.NodeID = strings.TrimSpace(query.Get("node"))
+	for _, raw := range query["label"] {
+		name, value, found := strings.Cut(raw, "=")
+		if name = strings.TrimSpace(name); !found || name == "" {
+			http.Error(w, "invalid label matcher", http.StatusBadRequest)
+			return q, false
+		}
+		if q.Labels == nil {
+			q.Labels = make(map[string]string)
+		}
+		q.Labels[name] = value
+	}
+	var ok bool
+	if q.Since, ok = parseOptionalTime(query.Get("since")); !ok {
+		http.Error(w, "invalid since", http.StatusBadRequest)
+		return q, false
+	}
+	if q.Until, ok = parseOptionalTime(query.Get("until")); !ok {
+		http.Error(w, "invalid until", http.StatusBadRequest)
+		return q, false
+	}
+	if raw := strings.TrimSpace(query.Get("cursor")); raw != "" {
+		after, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return q, false
+		}
+		q.After = after
+	}
+	if q.Limit, ok = parsePageLimit(query.Get("limit")); !ok {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+		return q, false
+	}
+	return q, true
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+)
+
+// SetProvenanceTracker attaches the update provenance store behind
+// /api/provenance.
+func (h *Handler) SetProvenanceTracker(tracker *provenance.Tracker) {
+	h.provenance = tracker
+}
+
+// GetProvenance returns every lifecycle event recorded for one update, from
+// ingest to commit, in the order recorded.
+func (h *Handler) GetProvenance(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.provenance == nil {
+		http.Error(w, "provenance tracker unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	updateID := strings.TrimSpace(r.PathValue("updateID"))
+	events, err := h.provenance.Chain(updateID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"update_id": updateID,
+		"events":    events,
+	})
+}
+
+// GetRoundProvenance returns every event recorded for a round's updates;
+// format=jsonl exports them as JSON Lines instead.
+func (h *Handler) GetRoundProvenance(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.provenance == nil {
+		http.Error(w, "provenance tracker unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	round, err := strconv.Atoi(strings.TrimSpace(r.PathValue("round")))
+	if err != nil || round < 0 {
+		http.Error(w, "invalid round", http.StatusBadRequest)
+		return
+	}
+
+	switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))) {
+	case "", "json":
+		events, err := h.provenance.RoundEvents(round)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"round":  round,
+			"events": events,
+			"count":  len(events),
+		})
+	case "jsonl":
+		var buf bytes.Buffer
+		if err := h.provenance.ExportRound(round, &buf); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-API-Version", "v1")
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="provenance-round-`+strconv.Itoa(round)+`.jsonl"`)
+		_, _ = w.Write(buf.Bytes())
+	default:
+		http.Error(w, "format must be json or jsonl", http.StatusBadRequest)
+	}
+}
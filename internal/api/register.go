@@ -0,0 +1,148 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/genesis"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/sharding"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func requireRegisterAuth(w http.ResponseWriter, r *http.Request) bool {
+	return requireScopedAuth(w, r, "MOHAWK_API_REGISTER_ALLOWED_ROLES", "node,admin")
+}
+
+// SetCapabilityRegistry requires registrations to carry a capability
+// manifest that meets the registry's admission policy. Nodes re-register to
+// re-submit a manifest after a material change.
+func (h *Handler) SetCapabilityRegistry(registry *scheduler.CapabilityRegistry) {
+	h.capabilities = registry
+}
+
+// SetProbation puts every node that registers for the first time on
+// probation and reports probation status in /api/peers. Wire the same
+// tracker into the coordinator, aggregator, and verifier so the probation
+// limits take effect.
+func (h *Handler) SetProbation(probation *consensus.Probation) {
+	h.probation = probation
+}
+
+// SetShardAssigner admits every registering node into a shard and returns
+// its signed shard assignment in the registration response.
+func (h *Handler) SetShardAssigner(assigner *sharding.Assigner) {
+	h.shards = assigner
+}
+
+// SetGenesisDigest references digest, of the genesis the node started
+// from, in registration responses, and refuses registrations from nodes
+// that pinned another.
+func (h *Handler) SetGenesisDigest(digest string) {
+	h.genesisDigest = digest
+}
+
+// probationStatus is nodeID's probation status as /api/peers reports it:
+// never-admitted and graduated nodes are off probation.
+func (h *Handler) probationStatus(nodeID string) consensus.ProbationStatus {
+	status, ok := h.probation.Status(nodeID)
+	if !ok {
+		return consensus.ProbationStatus{NodeID: nodeID, RequiredRounds: h.probation.Config().RequiredRounds}
+	}
+	return status
+}
+
+func (h *Handler) probationaryCount() int {
+	count := 0
+	for _, status := range h.probation.Statuses() {
+		if status.OnProbation {
+			count++
+		}
+	}
+	return count
+}
+
+// PostRegister admits a node as a verification peer. The request's
+// tpm_attestation carries an AttestationEnvelope, which the verifier checks
+// when an attestation gate is configured. With a capability registry set the
+// node is also admitted, or refused, on its capability manifest. With a
+// federation registry set the node is bound to the federations it lists.
+// With probation set a node registering for the first time starts on
+// probation. With a shard assigner set the node is admitted into a shard.
+// With a genesis digest set, a node that pinned another genesis is refused
+// before it is admitted.
+func (h *Handler) PostRegister(w http.ResponseWriter, r *http.Request) {
+	if !ensurePostMethod(w, r) {
+		return
+	}
+	if !requireRegisterAuth(w, r) {
+		return
+	}
+	if h.verifier == nil {
+		http.Error(w, "registration unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req protocol.RegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.NodeID = strings.TrimSpace(req.NodeID)
+	if err := genesis.Check(req.GenesisDigest, h.genesisDigest); err != nil {
+		writeError(w, fmt.Errorf("node %s: %w", req.NodeID, err))
+		return
+	}
+
+	peer := &p2p.PeerDetail{ID: req.NodeID, TPMAttestation: req.TPMAttestat, PublicKey: req.PublicKey, KeyAlgorithm: req.KeyAlgorithm}
+	if err := h.verifier.RegisterPeer(peer); err != nil {
+		writeError(w, err)
+		return
+	}
+	if h.capabilities != nil {
+		if req.Capabilities != nil && req.Capabilities.NodeID != req.NodeID {
+			h.verifier.RemovePeer(req.NodeID)
+			http.Error(w, "capability manifest is for another node", http.StatusBadRequest)
+			return
+		}
+		if err := h.capabilities.Admit(req.Capabilities); err != nil {
+			h.verifier.RemovePeer(req.NodeID)
+			writeError(w, err)
+			return
+		}
+	}
+
+	resp := protocol.RegistrationResponse{NodeID: req.NodeID, Approved: true, GenesisDigest: h.genesisDigest}
+	if h.federations != nil {
+		bound, err := h.bindFederations(&req)
+		if err != nil {
+			h.verifier.RemovePeer(req.NodeID)
+			writeError(w, err)
+			return
+		}
+		resp.Federations = bound
+	}
+	if h.shards != nil {
+		assignment, err := h.shards.Admit(req.NodeID, req.PublicKey)
+		if err != nil {
+			h.verifier.RemovePeer(req.NodeID)
+			writeError(w, err)
+			return
+		}
+		resp.ShardAssignment = &assignment
+	}
+	if h.probation != nil {
+		h.probation.Admit(req.NodeID)
+		resp.Probationary = h.probation.OnProbation(req.NodeID)
+	}
+	if h.modelStore != nil {
+		resp.Round = h.modelStore.LatestRound() + 1
+	}
+	writeJSON(w, resp)
+}
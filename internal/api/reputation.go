@@ -0,0 +1,115 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+)
+
+const (
+	defaultReputationWindow = 7 * 24 * time.Hour
+	defaultReputationLimit  = 500
+	maxReputationLimit      = 5000
+)
+
+type reputationHistoryResponse struct {
+	PeerID       string                 `json:"peer_id"`
+	Window       string                 `json:"window"`
+	SlopePerHour float64                `json:"slope_per_hour"`
+	Total        int                    `json:"total"`
+	Offset       int                    `json:"offset"`
+	Limit        int                    `json:"limit"`
+	NextOffset   int                    `json:"next_offset,omitempty"`
+	Samples      []p2p.ReputationSample `json:"samples"`
+}
+
+// SetVerifier attaches the verifier whose reputation history is served and
+// exports its reputation trends as metrics.
+func (h *Handler) SetVerifier(verifier *p2p.Verifier) {
+	h.verifier = verifier
+	if verifier != nil {
+		verifier.SetReputationTrendObserver(observeReputationTrend)
+	}
+}
+
+func parseNonNegativeInt(raw string, fallback int) (int, bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return fallback, true
+	}
+	value, err := strconv.Atoi(trimmed)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+// GetPeerReputation returns a page of a peer's reputation history over a
+// window (default 7 days) together with the trend over that window.
+func (h *Handler) GetPeerReputation(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.verifier == nil {
+		http.Error(w, "verifier unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	window := defaultReputationWindow
+	if raw := strings.TrimSpace(query.Get("window")); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "invalid window", http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+	offset, ok := parseNonNegativeInt(query.Get("offset"), 0)
+	if !ok {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+		return
+	}
+	limit, ok := parseNonNegativeInt(query.Get("limit"), defaultReputationLimit)
+	if !ok || limit == 0 || limit > maxReputationLimit {
+		http.Error(w, "invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	peerID := r.PathValue("id")
+	samples, err := h.verifier.GetReputationHistory(peerID, window)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	slope, _, err := h.verifier.ReputationTrend(peerID, window)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := reputationHistoryResponse{
+		PeerID:       peerID,
+		Window:       window.String(),
+		SlopePerHour: slope,
+		Total:        len(samples),
+		Offset:       offset,
+		Limit:        limit,
+		Samples:      []p2p.ReputationSample{},
+	}
+	if offset < len(samples) {
+		end := offset + limit
+		if end < len(samples) {
+			response.NextOffset = end
+		} else {
+			end = len(samples)
+		}
+		response.Samples = samples[offset:end]
+	}
+	writeJSON(w, response)
+}
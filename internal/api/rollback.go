@@ -0,0 +1,36 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"net/http"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/rollback"
+)
+
+// SetAutoRollback attaches the rollback policy engine behind
+// /api/events/rollback.
+func (h *Handler) SetAutoRollback(auto *rollback.AutoRollback) {
+	h.autoRollback = auto
+}
+
+// GetRollbackEvents returns automatic rollback actions with the evidence
+// that triggered each one, oldest first.
+func (h *Handler) GetRollbackEvents(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.autoRollback == nil {
+		http.Error(w, "auto rollback unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	events := h.autoRollback.Events()
+	pending, hasPending := h.autoRollback.Pending()
+	writeJSON(w, map[string]interface{}{
+		"events":           events,
+		"count":            len(events),
+		"pending_proposal": pending,
+		"pending":          hasPending,
+	})
+}
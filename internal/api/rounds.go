@@ -0,0 +1,170 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+)
+
+// SetModelStore attaches the committed model store used by round backfill endpoints.
+func (h *Handler) SetModelStore(store *modeldist.ModelStore) {
+	h.modelStore = store
+}
+
+func parseRoundParam(raw string, fallback int) (int, bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return fallback, true
+	}
+	value, err := strconv.Atoi(trimmed)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+// GetRounds returns committed round summaries in the [from, to] range.
+func (h *Handler) GetRounds(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.modelStore == nil {
+		http.Error(w, "model store unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	from, ok := parseRoundParam(query.Get("from"), 1)
+	if !ok {
+		http.Error(w, "invalid from round", http.StatusBadRequest)
+		return
+	}
+	to, ok := parseRoundParam(query.Get("to"), 0)
+	if !ok {
+		http.Error(w, "invalid to round", http.StatusBadRequest)
+		return
+	}
+	if to > 0 && to < from {
+		http.Error(w, "to must not be less than from", http.StatusBadRequest)
+		return
+	}
+
+	summaries := h.modelStore.Summaries(from, to)
+	writeJSON(w, modeldist.RoundsResponse{
+		Rounds:      summaries,
+		LatestRound: h.modelStore.LatestRound(),
+		Count:       len(summaries),
+	})
+}
+
+// GetManifest returns the contribution manifest recorded for a round.
+func (h *Handler) GetManifest(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.modelStore == nil {
+		http.Error(w, "model store unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	round, err := strconv.Atoi(strings.TrimSpace(r.PathValue("round")))
+	if err != nil || round <= 0 {
+		http.Error(w, "invalid round", http.StatusBadRequest)
+		return
+	}
+
+	manifest, ok := h.modelStore.Manifest(round)
+	if !ok {
+		http.Error(w, "manifest not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"round":           round,
+		"manifest":        manifest,
+		"manifest_digest": manifest.Digest(),
+	})
+}
+
+// GetReceipt returns a node's signed inclusion receipt for a committed
+// round: whether its update was included, with what weight, or why not.
+func (h *Handler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.modelStore == nil {
+		http.Error(w, "model store unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	round, err := strconv.Atoi(strings.TrimSpace(r.PathValue("round")))
+	if err != nil || round <= 0 {
+		http.Error(w, "invalid round", http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := h.modelStore.Receipt(round, strings.TrimSpace(r.PathValue("nodeID")))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, receipt)
+}
+
+// GetModel returns the committed model weights for a single round.
+func (h *Handler) GetModel(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.modelStore == nil {
+		http.Error(w, "model store unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	round, err := strconv.Atoi(strings.TrimSpace(r.PathValue("round")))
+	if err != nil || round <= 0 {
+		http.Error(w, "invalid round", http.StatusBadRequest)
+		return
+	}
+
+	weights, summary, ok := h.modelStore.Model(round)
+	if !ok {
+		http.Error(w, "round not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, modeldist.ModelResponse{
+		Round:   round,
+		Weights: weights,
+		Summary: summary,
+	})
+}
+
+// GetModelDelta returns a round's weights encoded as a delta against an
+// earlier round's, as the round summary's delta variant advertises.
+func (h *Handler) GetModelDelta(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if h.modelStore == nil {
+		http.Error(w, "model store unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	round, err := strconv.Atoi(strings.TrimSpace(r.PathValue("round")))
+	if err != nil || round <= 0 {
+		http.Error(w, "invalid round", http.StatusBadRequest)
+		return
+	}
+
+	delta, ok := h.modelStore.Delta(round)
+	if !ok {
+		http.Error(w, "delta not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, delta)
+}
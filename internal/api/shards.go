@@ -0,0 +1,42 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/certchain"
+)
+
+// SetEpochLedger serves the shard membership epochs ledger committed on
+// /api/shards/{shard}/epoch, for regional aggregators to certify their
+// shards' commits under.
+func (h *Handler) SetEpochLedger(ledger *certchain.EpochLedger) {
+	h.epochs = ledger
+}
+
+// GetShardEpoch returns the current membership epoch record of the shard
+// named by the path.
+func (h *Handler) GetShardEpoch(w http.ResponseWriter, r *http.Request) {
+	if !ensureGetMethod(w, r) {
+		return
+	}
+	if !requireUpdateAuth(w, r) {
+		return
+	}
+	if h.epochs == nil {
+		http.Error(w, "shard epochs unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	shardID := strings.TrimSpace(r.PathValue("shard"))
+	record, ok := h.epochs.Epoch(shardID)
+	if !ok {
+		writeError(w, fmt.Errorf("%w: %q", certchain.ErrUnknownShard, shardID))
+		return
+	}
+	writeJSON(w, record)
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+)
+
+// SetTopicKeys serves the shard group keys keys holds on
+// /api/v1/topic-keys, to members that missed a rekey. The shard assigner's
+// membership hook keeps keys current; see sharding.Assigner.SetMembershipHook.
+func (h *Handler) SetTopicKeys(keys *p2p.TopicKeyManager) {
+	h.topicKeys = keys
+}
+
+// PostTopicKey answers a member's signed p2p.KeyRequest with the topic's
+// current group key wrapped for it, as a p2p.KeyGrant. A request that is
+// stale, unsigned by the member, or from a node outside the topic is
+// refused.
+func (h *Handler) PostTopicKey(w http.ResponseWriter, r *http.Request) {
+	if !ensurePostMethod(w, r) {
+		return
+	}
+	if h.topicKeys == nil {
+		http.Error(w, "topic keys unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	var req p2p.KeyRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	wrapped, err := h.topicKeys.HandleKeyRequest(&req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, p2p.KeyGrant{Topic: req.Topic, Wrapped: wrapped})
+}
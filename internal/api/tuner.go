@@ -0,0 +1,48 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+)
+
+// SetBatchTuner attaches the batch trigger auto-tuner behind
+// /api/admin/batch_tuner.
+func (h *Handler) SetBatchTuner(tuner *batch.AutoTuner) {
+	h.batchTuner = tuner
+}
+
+type batchTunerRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// HandleBatchTuner reports the batch auto-tuner's state and recent
+// decisions on GET and turns it on or off on POST with {"enabled": bool}.
+func (h *Handler) HandleBatchTuner(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !requirePolicyAuth(w, r) {
+			return
+		}
+	default:
+		methodNotAllowed(w)
+		return
+	}
+	if h.batchTuner == nil {
+		http.Error(w, "batch tuner unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if r.Method == http.MethodPost {
+		var req batchTunerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+			http.Error(w, "request body must set enabled", http.StatusBadRequest)
+			return
+		}
+		h.batchTuner.SetEnabled(*req.Enabled)
+	}
+	writeJSON(w, h.batchTuner.Status())
+}
@@ -0,0 +1,328 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package audit exports the evidence for a committed round as a
+// self-contained bundle an external auditor checks offline with
+// protocol.VerifyAuditBundle: the signed commit and its certificate, the
+// contribution manifest, a sample of the signed verification responses,
+// the public keys those signatures verify against, the participants'
+// attestation envelopes, the privacy accounting, and the round's consensus
+// replay log entry.
+package audit
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Signer signs a bundle's index with the exporter's identity key.
+// *crypto.SecureChannel implements it.
+type Signer interface {
+	SignData(data []byte) ([]byte, error)
+	ExportPublicKey() ([]byte, error)
+}
+
+// Options tunes what Collect gathers.
+type Options struct {
+	// HostID names the host, whose key signs the federation's rounds.
+	HostID string
+	// Sample bounds how many of the round's verification requests have
+	// their responses exported. The sample is the requests whose IDs hash
+	// lowest, so repeated exports of a round agree. Zero exports every
+	// request.
+	Sample int
+	// Budgets, when set, adds each shard accountant's position to the
+	// privacy record.
+	Budgets *privacy.BudgetRegistry
+}
+
+// Bundle is the evidence for one committed round, as Export writes it.
+type Bundle struct {
+	FederationID  string
+	Commit        protocol.AuditCommit
+	Manifest      *protocol.ContributionManifest
+	Verifications []protocol.AuditVerification
+	Keys          []protocol.AuditKey
+	Attestations  []protocol.AttestationEnvelope
+	Privacy       protocol.AuditPrivacy
+	Replay        []protocol.AuditReplay
+}
+
+// Collect gathers f's evidence for round. The round must still be
+// retained by f's model store with its manifest, signed by the store's
+// signer, and in f's consensus replay log; otherwise Collect fails with
+// ErrRoundUnavailable, or protocol.ErrUnsignedModel for an unsigned round.
+func Collect(f *federation.Federation, round int, opts Options) (*Bundle, error) {
+	_, summary, ok := f.ModelStore.Model(round)
+	if !ok {
+		return nil, fmt.Errorf("%w: round %d is not retained", ErrRoundUnavailable, round)
+	}
+	manifest, ok := f.ModelStore.Manifest(round)
+	if !ok {
+		return nil, fmt.Errorf("%w: round %d has no manifest", ErrRoundUnavailable, round)
+	}
+	signer := f.ModelStore.Signer()
+	if len(summary.Signature) == 0 || signer == nil {
+		return nil, fmt.Errorf("%w: round %d", protocol.ErrUnsignedModel, round)
+	}
+	aggregatorKey, err := signer.ExportPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if fingerprint, err := crypto.PublicKeyFingerprint(aggregatorKey); err != nil || fingerprint != summary.SignerFingerprint {
+		return nil, fmt.Errorf("%w: round %d was signed by %s, no longer the store's signer", protocol.ErrUnsignedModel, round, summary.SignerFingerprint)
+	}
+
+	b := &Bundle{
+		FederationID: f.ID,
+		Commit: protocol.AuditCommit{
+			Round:            summary.Round,
+			ModelDigest:      summary.ModelDigest,
+			ParticipantCount: summary.ParticipantCount,
+			Certificate: protocol.AuditCertificate{
+				Round:       summary.Certificate.Round,
+				ProposalID:  summary.Certificate.ProposalID,
+				ModelDigest: summary.Certificate.ModelDigest,
+				QuorumSize:  summary.Certificate.QuorumSize,
+				Approvals:   append([]string(nil), summary.Certificate.Approvals...),
+			},
+			CommittedAt:        summary.CommittedAt,
+			ManifestDigest:     summary.ManifestDigest,
+			ParticipantsDigest: summary.ParticipantsDigest,
+			Signature:          summary.Signature,
+			SignerFingerprint:  summary.SignerFingerprint,
+			SignatureAlgorithm: summary.SignatureAlgorithm,
+		},
+		Manifest: manifest,
+		Keys:     []protocol.AuditKey{{NodeID: opts.HostID, Role: protocol.AuditRoleAggregator, PublicKey: aggregatorKey}},
+	}
+	sort.Strings(b.Commit.Certificate.Approvals)
+
+	for _, entry := range f.Coordinator.ReplayLog() {
+		if entry.Round != round || entry.ProposalID != summary.Certificate.ProposalID {
+			continue
+		}
+		equivocations, err := json.Marshal(entry.Equivocations)
+		if err != nil {
+			return nil, fmt.Errorf("encode equivocations: %w", err)
+		}
+		if len(entry.Equivocations) == 0 {
+			equivocations = nil
+		}
+		b.Replay = append(b.Replay, protocol.AuditReplay{
+			Round:           entry.Round,
+			ProposalID:      entry.ProposalID,
+			ProposerID:      entry.ProposerID,
+			WeightsDigest:   entry.WeightsDigest,
+			ManifestDigest:  entry.ManifestDigest,
+			Approvals:       entry.Approvals,
+			QuorumSize:      entry.QuorumSize,
+			MembershipEpoch: entry.MembershipEpoch,
+			CommittedAt:     entry.CommittedAt,
+			VoteDigests:     entry.VoteDigests,
+			Equivocations:   equivocations,
+		})
+	}
+	if len(b.Replay) == 0 {
+		return nil, fmt.Errorf("%w: round %d is not in the replay log", ErrRoundUnavailable, round)
+	}
+
+	b.collectVerifications(f, round, opts.Sample)
+	b.collectAttestations(f)
+	b.collectPrivacy(f, round, opts.Budgets)
+	return b, nil
+}
+
+// collectVerifications exports the responses to a sample of round's
+// requests and the keys of the verifiers that sent them.
+func (b *Bundle) collectVerifications(f *federation.Federation, round, sample int) {
+	evidence := f.Peers.Evidence(round)
+	sort.Slice(evidence, func(i, j int) bool {
+		return sampleRank(evidence[i].RequestID) < sampleRank(evidence[j].RequestID)
+	})
+	if sample > 0 && len(evidence) > sample {
+		evidence = evidence[:sample]
+	}
+	sort.Slice(evidence, func(i, j int) bool { return evidence[i].RequestID < evidence[j].RequestID })
+
+	verifiers := make(map[string]bool)
+	for _, item := range evidence {
+		for _, resp := range item.Responses {
+			b.Verifications = append(b.Verifications, protocol.AuditVerification{
+				RequestID:          item.RequestID,
+				ProposerID:         item.ProposerID,
+				Round:              item.Round,
+				UpdateDigest:       item.UpdateDigest,
+				VerifierID:         resp.VerifierID,
+				Valid:              resp.Valid,
+				ReasonCode:         resp.ReasonCode,
+				Timestamp:          resp.Timestamp.UTC(),
+				Signature:          resp.Signature,
+				SignatureAlgorithm: resp.SignatureAlgorithm,
+			})
+			verifiers[resp.VerifierID] = true
+		}
+	}
+	ids := make([]string, 0, len(verifiers))
+	for id := range verifiers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		// A verifier removed since it responded leaves its responses
+		// without a key, which the auditor sees as such.
+		if peer, ok := f.Peers.Peer(id); ok && len(peer.PublicKey) > 0 {
+			b.Keys = append(b.Keys, protocol.AuditKey{NodeID: id, Role: protocol.AuditRoleVerifier, PublicKey: peer.PublicKey})
+		}
+	}
+}
+
+// collectAttestations exports the attestation envelopes of the manifest's
+// participants that registered one.
+func (b *Bundle) collectAttestations(f *federation.Federation) {
+	for _, entry := range b.Manifest.Entries {
+		peer, ok := f.Peers.Peer(entry.NodeID)
+		if !ok || len(peer.TPMAttestation) == 0 {
+			continue
+		}
+		if envelope, err := protocol.UnmarshalAttestation(peer.TPMAttestation); err == nil {
+			b.Attestations = append(b.Attestations, *envelope)
+		}
+	}
+	sort.Slice(b.Attestations, func(i, j int) bool { return b.Attestations[i].NodeID < b.Attestations[j].NodeID })
+}
+
+// collectPrivacy records f's privacy spending, from its central DP
+// accountant and release log when it has one, and, with budgets, every
+// shard's.
+func (b *Bundle) collectPrivacy(f *federation.Federation, round int, budgets *privacy.BudgetRegistry) {
+	b.Privacy = protocol.AuditPrivacy{Round: round}
+	if f.Privacy != nil {
+		used, total := f.Privacy.GetPrivacyBudget()
+		b.Privacy.Budget.Epsilon, b.Privacy.Spent.Epsilon = total, used
+	}
+	if f.CentralDP != nil {
+		b.Privacy.Budget = protocol.AuditBudget(f.CentralDP.Config().Allocation)
+		b.Privacy.Spent = protocol.AuditBudget{}
+		for _, release := range f.CentralDP.Releases() {
+			if release.Round <= round {
+				b.Privacy.Releases = append(b.Privacy.Releases, release)
+				b.Privacy.Spent.Epsilon += release.Epsilon
+				b.Privacy.Spent.Delta += release.Delta
+			}
+		}
+	}
+	if budgets == nil {
+		return
+	}
+	for _, shard := range budgets.State().Shards {
+		b.Privacy.Shards = append(b.Privacy.Shards, protocol.AuditShard{
+			ShardID:    shard.ShardID,
+			Allocation: protocol.AuditBudget(shard.Allocation),
+			PerRound:   protocol.AuditBudget(shard.PerRound),
+			Spent:      protocol.AuditBudget(shard.Spent),
+			Rounds:     shard.Rounds,
+		})
+	}
+}
+
+// sampleRank orders requests for sampling.
+func sampleRank(requestID string) uint64 {
+	sum := sha256.Sum256([]byte(requestID))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// Export writes b to w as a tar stream of its artifacts followed by an
+// index signed by signer on behalf of exporterID, and returns the index.
+func (b *Bundle) Export(w io.Writer, exporterID string, signer Signer) (*protocol.AuditIndex, error) {
+	exporterKey, err := signer.ExportPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := crypto.PublicKeyAlgorithm(exporterKey)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := map[string]interface{}{
+		protocol.AuditCommitPath:        b.Commit,
+		protocol.AuditManifestPath:      b.Manifest,
+		protocol.AuditVerificationsPath: nonNil(b.Verifications),
+		protocol.AuditKeysPath:          nonNil(b.Keys),
+		protocol.AuditAttestationsPath:  nonNil(b.Attestations),
+		protocol.AuditPrivacyPath:       b.Privacy,
+		protocol.AuditReplayPath:        nonNil(b.Replay),
+	}
+
+	now := time.Now().UTC()
+	index := &protocol.AuditIndex{
+		Version:            protocol.AuditBundleVersion,
+		FederationID:       b.FederationID,
+		Round:              b.Commit.Round,
+		ExportedAt:         now,
+		ExporterID:         exporterID,
+		ExporterKey:        exporterKey,
+		SignatureAlgorithm: algorithm,
+	}
+	tw := tar.NewWriter(w)
+	for _, path := range protocol.AuditArtifactPaths() {
+		data, err := json.MarshalIndent(artifacts[path], "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encode %s: %w", path, err)
+		}
+		if err := writeEntry(tw, path, data, now); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		index.Artifacts = append(index.Artifacts, protocol.AuditArtifact{Path: path, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+	}
+
+	digest, err := index.SigningDigest()
+	if err != nil {
+		return nil, fmt.Errorf("encode index: %w", err)
+	}
+	if index.Signature, err = signer.SignData(digest[:]); err != nil {
+		return nil, fmt.Errorf("sign index: %w", err)
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode index: %w", err)
+	}
+	if err := writeEntry(tw, protocol.AuditIndexPath, data, now); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("finish bundle: %w", err)
+	}
+	return index, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write %s header: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// nonNil keeps an empty artifact list encoding as [] rather than null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
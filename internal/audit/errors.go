@@ -0,0 +1,15 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package audit
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by audit exports. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrRoundUnavailable means the federation no longer holds, or never
+	// committed, the evidence for a round: its model, manifest, or replay
+	// log entry. Not retryable.
+	ErrRoundUnavailable = errors.New("audit round unavailable")
+)
@@ -0,0 +1,344 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package backup exports and restores federation state for disaster
+// recovery. An archive is a tar stream holding one entry per component
+// followed by a signed manifest listing every entry's SHA-256 checksum, so a
+// replacement host can verify an archive before reconstituting anything.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Version is the archive format version written by Backup.
+const Version = 1
+
+const (
+	// manifestPath is the archive entry holding the signed manifest. It is
+	// written last so components can be streamed before it.
+	manifestPath = "MANIFEST.json"
+	// componentDir prefixes component entries.
+	componentDir = "components/"
+	// maxEntrySize bounds a single archive entry read during restore.
+	maxEntrySize = 4 << 30
+)
+
+// Component is one piece of federation state that can be backed up.
+type Component interface {
+	// Name identifies the component in the archive; it must be unique.
+	Name() string
+	// Export returns the component's state.
+	Export() ([]byte, error)
+	// Restore reconstitutes state produced by Export.
+	Restore(data []byte) error
+}
+
+// Entry describes one component entry in the manifest.
+type Entry struct {
+	Component string `json:"component"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+}
+
+// Manifest lists an archive's entries and is signed by the node that
+// created it.
+type Manifest struct {
+	Version   int       `json:"version"`
+	NodeID    string    `json:"node_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Entries   []Entry   `json:"entries"`
+	SignerKey []byte    `json:"signer_key"`
+	Signature []byte    `json:"signature,omitempty"`
+}
+
+// signingPayload is the canonical JSON of the manifest without its
+// signature.
+func (m *Manifest) signingPayload() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	return payload, nil
+}
+
+// Verify checks that the manifest was signed by trusted.
+func (m *Manifest) Verify(trusted ed25519.PublicKey) error {
+	if len(trusted) != ed25519.PublicKeySize || !bytes.Equal(trusted, m.SignerKey) {
+		return fmt.Errorf("%w: signed by untrusted key", ErrSignature)
+	}
+	payload, err := m.signingPayload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(trusted, payload, m.Signature) {
+		return fmt.Errorf("%w: signature does not match manifest", ErrSignature)
+	}
+	return nil
+}
+
+// EntryStatus is the verification outcome of one manifest entry.
+type EntryStatus struct {
+	Entry
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Archive is a read and checked, but not yet restored, backup.
+type Archive struct {
+	Manifest *Manifest
+	Entries  []EntryStatus
+	data     map[string][]byte
+}
+
+// Corrupt returns the entries that are missing or fail their checksum.
+func (a *Archive) Corrupt() []EntryStatus {
+	var corrupt []EntryStatus
+	for _, entry := range a.Entries {
+		if !entry.Valid {
+			corrupt = append(corrupt, entry)
+		}
+	}
+	return corrupt
+}
+
+// Read parses an archive and checks every entry against the manifest. It
+// does not verify the manifest signature; Restore does.
+func Read(r io.Reader) (*Archive, error) {
+	data := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			// io.EOF ends a complete archive. A damaged or truncated stream
+			// still yields the entries read so far; a lost manifest is
+			// caught below and lost components by their checksums.
+			break
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size < 0 || hdr.Size > maxEntrySize {
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(tr, hdr.Size))
+		if err != nil || int64(len(body)) != hdr.Size {
+			continue
+		}
+		data[hdr.Name] = body
+	}
+
+	raw, ok := data[manifestPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: no %s", ErrCorruptArchive, manifestPath)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: decode manifest: %v", ErrCorruptArchive, err)
+	}
+	if manifest.Version != Version {
+		return nil, fmt.Errorf("%w: version %d, this build reads %d", ErrUnsupportedVersion, manifest.Version, Version)
+	}
+
+	archive := &Archive{Manifest: &manifest, data: data}
+	for _, entry := range manifest.Entries {
+		status := EntryStatus{Entry: entry, Valid: true}
+		body, ok := data[entry.Path]
+		switch {
+		case !ok:
+			status.Valid, status.Reason = false, "missing"
+		case int64(len(body)) != entry.Size:
+			status.Valid, status.Reason = false, fmt.Sprintf("size %d, manifest says %d", len(body), entry.Size)
+		case checksum(body) != entry.SHA256:
+			status.Valid, status.Reason = false, "checksum mismatch"
+		}
+		archive.Entries = append(archive.Entries, status)
+	}
+	return archive, nil
+}
+
+// RestoreReport summarizes a restore.
+type RestoreReport struct {
+	Manifest *Manifest `json:"manifest"`
+	Restored []string  `json:"restored"`
+	// Skipped maps component names to why they were not restored.
+	Skipped map[string]string `json:"skipped,omitempty"`
+}
+
+// Archiver backs up and restores a fixed set of components.
+type Archiver struct {
+	nodeID     string
+	key        ed25519.PrivateKey
+	trusted    ed25519.PublicKey
+	components []Component
+}
+
+// NewArchiver creates an archiver that signs with key. Restores trust the
+// public half of key until SetTrustedKey says otherwise.
+func NewArchiver(nodeID string, key ed25519.PrivateKey, components ...Component) (*Archiver, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid backup signing key: need %d bytes, got %d", ed25519.PrivateKeySize, len(key))
+	}
+	seen := make(map[string]bool, len(components))
+	for _, component := range components {
+		name := component.Name()
+		if name == "" || seen[name] {
+			return nil, fmt.Errorf("component name %q is empty or duplicated", name)
+		}
+		seen[name] = true
+	}
+	return &Archiver{
+		nodeID:     nodeID,
+		key:        key,
+		trusted:    key.Public().(ed25519.PublicKey),
+		components: components,
+	}, nil
+}
+
+// SetTrustedKey sets the key archives must be signed with to be restored,
+// e.g. the failed host's key when restoring onto its replacement.
+func (a *Archiver) SetTrustedKey(trusted ed25519.PublicKey) {
+	a.trusted = trusted
+}
+
+// Backup streams an archive of every component to w and returns its
+// manifest.
+func (a *Archiver) Backup(w io.Writer) (*Manifest, error) {
+	now := time.Now().UTC()
+	manifest := &Manifest{
+		Version:   Version,
+		NodeID:    a.nodeID,
+		CreatedAt: now,
+		SignerKey: append([]byte(nil), a.key.Public().(ed25519.PublicKey)...),
+	}
+
+	tw := tar.NewWriter(w)
+	for _, component := range a.components {
+		data, err := component.Export()
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", component.Name(), err)
+		}
+		entry := Entry{
+			Component: component.Name(),
+			Path:      componentDir + component.Name() + ".json",
+			Size:      int64(len(data)),
+			SHA256:    checksum(data),
+		}
+		if err := writeEntry(tw, entry.Path, data, now); err != nil {
+			return nil, err
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	payload, err := manifest.signingPayload()
+	if err != nil {
+		return nil, err
+	}
+	manifest.Signature = ed25519.Sign(a.key, payload)
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := writeEntry(tw, manifestPath, encoded, now); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("finish archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// Restore verifies the archive read from r and reconstitutes its
+// components in the order they were backed up. Nothing is restored unless
+// the manifest signature is trusted and every entry matches its checksum.
+func (a *Archiver) Restore(r io.Reader) (*RestoreReport, error) {
+	return a.restore(r, false)
+}
+
+// RestorePartial is Restore for archives with corrupt entries: intact
+// components are reconstituted and corrupt ones reported as skipped. The
+// manifest signature must still be trusted.
+func (a *Archiver) RestorePartial(r io.Reader) (*RestoreReport, error) {
+	return a.restore(r, true)
+}
+
+func (a *Archiver) restore(r io.Reader, allowPartial bool) (*RestoreReport, error) {
+	archive, err := Read(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := archive.Manifest.Verify(a.trusted); err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{Manifest: archive.Manifest, Skipped: make(map[string]string)}
+	corrupt := archive.Corrupt()
+	if len(corrupt) > 0 && !allowPartial {
+		names := make([]string, len(corrupt))
+		for i, entry := range corrupt {
+			names[i] = entry.Component + " (" + entry.Reason + ")"
+		}
+		return nil, fmt.Errorf("%w: %v", ErrChecksumMismatch, names)
+	}
+
+	byName := make(map[string]Component, len(a.components))
+	for _, component := range a.components {
+		byName[component.Name()] = component
+	}
+	for _, status := range archive.Entries {
+		component, ok := byName[status.Component]
+		switch {
+		case !status.Valid:
+			report.Skipped[status.Component] = status.Reason
+			continue
+		case !ok:
+			report.Skipped[status.Component] = "no component registered"
+			continue
+		}
+		if err := component.Restore(archive.data[status.Path]); err != nil {
+			return report, fmt.Errorf("restore %s: %w", status.Component, err)
+		}
+		report.Restored = append(report.Restored, status.Component)
+		delete(byName, status.Component)
+	}
+	missing := make([]string, 0, len(byName))
+	for name := range byName {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		report.Skipped[name] = "not in archive"
+	}
+	return report, nil
+}
+
+func writeEntry(tw *tar.Writer, path string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    path,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+		Format:  tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write %s header: %w", path, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
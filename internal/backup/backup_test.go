@@ -0,0 +1,280 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+const campaignNodes = 4
+
+// campaignNode is the state a regional aggregator accumulates over a
+// training campaign.
+type campaignNode struct {
+	coordinator *consensus.Coordinator
+	store       *modeldist.ModelStore
+	network     *p2p.Network
+	state       *island.StateManager
+	queue       *island.Manager
+}
+
+func newCampaignNode() *campaignNode {
+	return &campaignNode{
+		coordinator: consensus.NewCoordinator("aggregator", campaignNodes, time.Minute),
+		store:       modeldist.NewModelStore(64),
+		network:     p2p.NewNetwork("aggregator", 1, time.Minute),
+		state:       island.NewStateManager(16),
+		queue:       island.NewManager(time.Hour, 16, func() bool { return true }),
+	}
+}
+
+func (n *campaignNode) components() []Component {
+	return []Component{
+		ModelStoreComponent{Store: n.store},
+		ConsensusComponent{Coordinator: n.coordinator},
+		PeersComponent{Network: n.network},
+		IslandComponent{State: n.state},
+		QueueComponent{Manager: n.queue},
+	}
+}
+
+// runRound commits the next round through consensus and records it the way
+// the simulator does.
+func (n *campaignNode) runRound(t *testing.T) int {
+	t.Helper()
+	ctx := context.Background()
+	round := n.store.LatestRound() + 1
+	weights := []byte(fmt.Sprintf("global-model-%d", round))
+
+	proposalID, err := n.coordinator.ProposeModel(ctx, &consensus.ModelProposal{Round: round, Weights: weights, ProposerID: "aggregator", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("round %d: propose: %v", round, err)
+	}
+	voters := []string{"aggregator"}
+	for i := 1; i < campaignNodes; i++ {
+		voters = append(voters, fmt.Sprintf("member-%d", i))
+	}
+	for _, nodeID := range voters {
+		if err := n.coordinator.CastVote(ctx, &consensus.Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("round %d: vote: %v", round, err)
+		}
+	}
+	if err := n.coordinator.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("round %d: commit: %v", round, err)
+	}
+	n.coordinator.Reset()
+
+	cert := modeldist.CommitCertificate{Round: round, ProposalID: proposalID, ModelDigest: protocol.WeightsDigest(weights), QuorumSize: 3, Approvals: voters}
+	if _, err := n.store.Commit(round, weights, campaignNodes, map[string]float64{"loss": 1 / float64(round)}, cert); err != nil {
+		t.Fatalf("round %d: store: %v", round, err)
+	}
+	manifest := &protocol.ContributionManifest{Round: round}
+	for _, nodeID := range voters {
+		manifest.Entries = append(manifest.Entries, protocol.ContributionEntry{NodeID: nodeID, Included: true, Reason: protocol.ReasonIncluded})
+	}
+	if err := n.store.AttachManifest(manifest); err != nil {
+		t.Fatalf("round %d: manifest: %v", round, err)
+	}
+	if _, err := n.state.CreateSnapshot(round, protocol.WeightsDigest(weights), campaignNodes, map[string]interface{}{"phase": "committed"}); err != nil {
+		t.Fatalf("round %d: snapshot: %v", round, err)
+	}
+	return round
+}
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+// campaignBackup runs five rounds, queues updates, and backs the node up.
+func campaignBackup(t *testing.T, key ed25519.PrivateKey) (*campaignNode, []byte) {
+	t.Helper()
+	node := newCampaignNode()
+	node.network.AddPeer("peer-1", "10.0.0.1:4001", 0.9)
+	node.network.AddPeer("peer-2", "10.0.0.2:4001", 0.4)
+	for i := 0; i < 5; i++ {
+		node.runRound(t)
+	}
+	if err := node.queue.CacheUpdate(island.Update{Round: 6, PeerID: "peer-1", ModelDelta: []byte("delta"), Timestamp: time.Now()}); err != nil {
+		t.Fatalf("queue update: %v", err)
+	}
+
+	archiver, err := NewArchiver("aggregator", key, node.components()...)
+	if err != nil {
+		t.Fatalf("new archiver: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := archiver.Backup(&buf); err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	return node, buf.Bytes()
+}
+
+func TestRestoreMidCampaignAndContinueRounds(t *testing.T) {
+	key := newKey(t)
+	original, archive := campaignBackup(t, key)
+
+	replacement := newCampaignNode()
+	archiver, err := NewArchiver("aggregator-replacement", newKey(t), replacement.components()...)
+	if err != nil {
+		t.Fatalf("new archiver: %v", err)
+	}
+	if _, err := archiver.Restore(bytes.NewReader(archive)); !errors.Is(err, ErrSignature) {
+		t.Fatalf("expected an archive from an untrusted key to be refused, got %v", err)
+	}
+	archiver.SetTrustedKey(key.Public().(ed25519.PublicKey))
+	report, err := archiver.Restore(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if len(report.Restored) != 5 || len(report.Skipped) != 0 {
+		t.Fatalf("expected every component restored, got %+v", report)
+	}
+
+	if replacement.store.LatestRound() != 5 {
+		t.Fatalf("expected round history through 5, got %d", replacement.store.LatestRound())
+	}
+	if _, ok := replacement.store.Manifest(3); !ok {
+		t.Fatal("expected contribution manifests to be restored")
+	}
+	if peer, ok := replacement.network.GetPeer("peer-2"); !ok || peer.Reputation != 0.4 || peer.Address != "10.0.0.2:4001" {
+		t.Fatalf("expected peer-2 with its reputation and address, got %+v", peer)
+	}
+	if got := len(replacement.queue.GetCachedUpdates()); got != 1 {
+		t.Fatalf("expected the queued update to be restored, got %d", got)
+	}
+
+	for i := 0; i < 5; i++ {
+		replacement.runRound(t)
+	}
+	if replacement.store.LatestRound() != 10 {
+		t.Fatalf("expected rounds to continue through 10, got %d", replacement.store.LatestRound())
+	}
+	replay := replacement.coordinator.ReplayLog()
+	if len(replay) != 10 || replay[0].Round != 1 || replay[9].Round != 10 {
+		t.Fatalf("expected a replay log covering rounds 1-10, got %d entries", len(replay))
+	}
+	if original.coordinator.ReplayLog()[4].ProposalID != replay[4].ProposalID {
+		t.Fatal("expected restored replay entries to match the original")
+	}
+	if ok, err := replacement.state.VerifyChain(); !ok || err != nil {
+		t.Fatalf("expected the snapshot chain to extend the restored one: %v", err)
+	}
+}
+
+// rewrite copies an archive, letting edit replace or drop entries by path.
+func rewrite(t *testing.T, archive []byte, edit func(name string, body []byte) ([]byte, bool)) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	tw := tar.NewWriter(&out)
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read archive: %v", err)
+		}
+		body, _ := io.ReadAll(tr)
+		body, keep := edit(hdr.Name, body)
+		if !keep {
+			continue
+		}
+		hdr.Size = int64(len(body))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		_, _ = tw.Write(body)
+	}
+	_ = tw.Close()
+	return out.Bytes()
+}
+
+func TestRestoreRefusesCorruptArchiveUnlessPartial(t *testing.T) {
+	key := newKey(t)
+	_, archive := campaignBackup(t, key)
+	corrupted := rewrite(t, archive, func(name string, body []byte) ([]byte, bool) {
+		if name == componentDir+ComponentPeers+".json" {
+			body = append([]byte(nil), body...)
+			body[len(body)/2] ^= 0xff
+		}
+		return body, name != componentDir+ComponentQueue+".json"
+	})
+
+	read, err := Read(bytes.NewReader(corrupted))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if corrupt := read.Corrupt(); len(corrupt) != 2 {
+		t.Fatalf("expected two corrupt entries, got %+v", corrupt)
+	}
+
+	replacement := newCampaignNode()
+	archiver, err := NewArchiver("aggregator", key, replacement.components()...)
+	if err != nil {
+		t.Fatalf("new archiver: %v", err)
+	}
+	if _, err := archiver.Restore(bytes.NewReader(corrupted)); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if replacement.store.LatestRound() != 0 {
+		t.Fatal("expected nothing restored from a refused archive")
+	}
+
+	report, err := archiver.RestorePartial(bytes.NewReader(corrupted))
+	if err != nil {
+		t.Fatalf("partial restore: %v", err)
+	}
+	if len(report.Restored) != 3 || report.Skipped[ComponentPeers] == "" || report.Skipped[ComponentQueue] == "" {
+		t.Fatalf("expected peers and queue skipped, got %+v", report)
+	}
+	if replacement.store.LatestRound() != 5 {
+		t.Fatalf("expected intact round history restored, got %d", replacement.store.LatestRound())
+	}
+}
+
+func TestRestoreRejectsTamperedManifest(t *testing.T) {
+	key := newKey(t)
+	_, archive := campaignBackup(t, key)
+
+	if _, err := Read(bytes.NewReader(rewrite(t, archive, func(name string, body []byte) ([]byte, bool) {
+		return body, name != manifestPath
+	}))); !errors.Is(err, ErrCorruptArchive) {
+		t.Fatalf("expected ErrCorruptArchive without a manifest, got %v", err)
+	}
+
+	tampered := rewrite(t, archive, func(name string, body []byte) ([]byte, bool) {
+		if name == manifestPath {
+			body = bytes.Replace(body, []byte(`"node_id": "aggregator"`), []byte(`"node_id": "impostor"`), 1)
+		}
+		return body, true
+	})
+	archiver, err := NewArchiver("aggregator", key, newCampaignNode().components()...)
+	if err != nil {
+		t.Fatalf("new archiver: %v", err)
+	}
+	if _, err := archiver.RestorePartial(bytes.NewReader(tampered)); !errors.Is(err, ErrSignature) {
+		t.Fatalf("expected ErrSignature for a tampered manifest, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Component names written by the built-in adapters.
+const (
+	ComponentModelStore = "modelstore"
+	ComponentConsensus  = "consensus"
+	ComponentPeers      = "peers"
+	ComponentIsland     = "island"
+	ComponentQueue      = "queue"
+)
+
+type storedRound struct {
+	Summary  modeldist.RoundSummary         `json:"summary"`
+	Weights  []byte                         `json:"weights,omitempty"`
+	Manifest *protocol.ContributionManifest `json:"manifest,omitempty"`
+}
+
+// ModelStoreComponent backs up every retained round with its weights,
+// certificate, and contribution manifest.
+type ModelStoreComponent struct {
+	Store *modeldist.ModelStore
+}
+
+// Name implements Component.
+func (c ModelStoreComponent) Name() string { return ComponentModelStore }
+
+// Export implements Component.
+func (c ModelStoreComponent) Export() ([]byte, error) {
+	summaries := c.Store.Summaries(0, 0)
+	rounds := make([]storedRound, 0, len(summaries))
+	for _, summary := range summaries {
+		round := storedRound{Summary: summary}
+		if weights, _, ok := c.Store.Model(summary.Round); ok {
+			round.Weights = weights
+		}
+		if manifest, ok := c.Store.Manifest(summary.Round); ok {
+			round.Manifest = manifest
+		}
+		rounds = append(rounds, round)
+	}
+	return json.Marshal(rounds)
+}
+
+// Restore implements Component. Every round's certificate is verified
+// again on import.
+func (c ModelStoreComponent) Restore(data []byte) error {
+	var rounds []storedRound
+	if err := json.Unmarshal(data, &rounds); err != nil {
+		return fmt.Errorf("decode rounds: %w", err)
+	}
+	for _, round := range rounds {
+		if err := c.Store.Import(round.Summary, round.Weights); err != nil {
+			return fmt.Errorf("round %d: %w", round.Summary.Round, err)
+		}
+		if round.Manifest != nil {
+			if err := c.Store.AttachManifest(round.Manifest); err != nil {
+				return fmt.Errorf("round %d manifest: %w", round.Summary.Round, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ConsensusComponent backs up the coordinator's replay log.
+type ConsensusComponent struct {
+	Coordinator *consensus.Coordinator
+}
+
+// Name implements Component.
+func (c ConsensusComponent) Name() string { return ComponentConsensus }
+
+// Export implements Component.
+func (c ConsensusComponent) Export() ([]byte, error) {
+	return json.Marshal(c.Coordinator.ReplayLog())
+}
+
+// Restore implements Component.
+func (c ConsensusComponent) Restore(data []byte) error {
+	var entries []consensus.ReplayEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("decode replay log: %w", err)
+	}
+	return c.Coordinator.RestoreReplayLog(entries)
+}
+
+// PeersComponent backs up the peer table: addresses, public keys, and
+// reputation.
+type PeersComponent struct {
+	Network *p2p.Network
+}
+
+// Name implements Component.
+func (c PeersComponent) Name() string { return ComponentPeers }
+
+// Export implements Component.
+func (c PeersComponent) Export() ([]byte, error) {
+	return json.Marshal(c.Network.PeerTable())
+}
+
+// Restore implements Component.
+func (c PeersComponent) Restore(data []byte) error {
+	var peers []p2p.Peer
+	if err := json.Unmarshal(data, &peers); err != nil {
+		return fmt.Errorf("decode peers: %w", err)
+	}
+	_, err := c.Network.RestorePeerTable(peers)
+	return err
+}
+
+// IslandComponent backs up the island state snapshot chain.
+type IslandComponent struct {
+	State *island.StateManager
+}
+
+// Name implements Component.
+func (c IslandComponent) Name() string { return ComponentIsland }
+
+// Export implements Component.
+func (c IslandComponent) Export() ([]byte, error) {
+	return json.Marshal(c.State.GetSnapshots())
+}
+
+// Restore implements Component.
+func (c IslandComponent) Restore(data []byte) error {
+	var snapshots []island.StateSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return fmt.Errorf("decode snapshots: %w", err)
+	}
+	return c.State.RestoreSnapshots(snapshots)
+}
+
+// QueueComponent backs up updates queued for sync while in island mode.
+type QueueComponent struct {
+	Manager *island.Manager
+}
+
+// Name implements Component.
+func (c QueueComponent) Name() string { return ComponentQueue }
+
+// Export implements Component.
+func (c QueueComponent) Export() ([]byte, error) {
+	return json.Marshal(c.Manager.GetCachedUpdates())
+}
+
+// Restore implements Component.
+func (c QueueComponent) Restore(data []byte) error {
+	var updates []island.Update
+	if err := json.Unmarshal(data, &updates); err != nil {
+		return fmt.Errorf("decode queued updates: %w", err)
+	}
+	for i, update := range updates {
+		if err := c.Manager.CacheUpdate(update); err != nil {
+			return fmt.Errorf("queued update %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package backup
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by backup and restore. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrCorruptArchive means the archive cannot be read or lacks its
+	// manifest. Not retryable with the same archive.
+	ErrCorruptArchive = errors.New("corrupt backup archive")
+	// ErrUnsupportedVersion means the archive was written by an unknown
+	// format version. Not retryable with this build.
+	ErrUnsupportedVersion = errors.New("unsupported backup version")
+	// ErrSignature means the manifest signature is missing, invalid, or
+	// made by an untrusted key. Not retryable with the same archive.
+	ErrSignature = errors.New("backup signature invalid")
+	// ErrChecksumMismatch means one or more component entries are missing
+	// or do not match their manifest checksum. Restore with partial restore
+	// enabled to recover the intact components.
+	ErrChecksumMismatch = errors.New("backup entry checksum mismatch")
+)
@@ -0,0 +1,385 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// AccumulatorCheckpointVersion is the checkpoint format this package writes
+// and the only one OpenAccumulator resumes from. Version 1 checkpoints held
+// running sums, which no outlier filter can be applied to.
+const AccumulatorCheckpointVersion = 2
+
+// CheckpointPolicy says where and how often an Accumulator persists its
+// running state. The zero policy never checkpoints.
+type CheckpointPolicy struct {
+	// Path is the checkpoint file. Empty disables checkpointing.
+	Path string
+	// Every checkpoints after this many accepted updates. Zero disables.
+	Every int
+	// Interval checkpoints on the first accepted update at least this long
+	// after the last checkpoint. Zero disables.
+	Interval time.Duration
+}
+
+// Accumulator collects a round's updates as they arrive and aggregates them
+// through the aggregator once the round closes, so the outlier filter,
+// detection plugins, central DP, exclusions, and Multi-Krum all see the
+// whole batch. Updates are ingested on arrival, so one the aggregator can
+// never decode, or whose shape differs from the batch's, is refused then
+// rather than failing the round.
+//
+// Each accepted update gets a receipt ID, its provenance.UpdateID. With a
+// CheckpointPolicy the queued updates and their receipts are written
+// atomically as they arrive, so an aggregator restarted mid-batch resumes
+// from the last checkpoint and refuses updates it already holds. Results
+// aggregate the updates in node order, so a resumed accumulator produces
+// bit-for-bit the result of an uninterrupted one whatever order the
+// remaining updates arrive in.
+type Accumulator struct {
+	agg        *Aggregator
+	round      int
+	policy     CheckpointPolicy
+	specDigest string
+
+	mu        sync.Mutex
+	updates   []Update
+	receipts  []string
+	dims      int
+	nodes     map[string]int
+	included  map[string]bool
+	unsaved   int
+	lastSaved time.Time
+	now       func() time.Time
+}
+
+// accumulatorCheckpoint is the persisted state of an Accumulator. JSON
+// encodes float64 values exactly, so resumed updates are the saved ones.
+type accumulatorCheckpoint struct {
+	Version    int                `json:"version"`
+	Round      int                `json:"round"`
+	SpecDigest string             `json:"spec_digest,omitempty"`
+	Updates    []checkpointUpdate `json:"updates"`
+	Receipts   []string           `json:"receipts"`
+}
+
+// checkpointUpdate is an Update as a checkpoint stores it.
+type checkpointUpdate struct {
+	NodeID      string                      `json:"node_id"`
+	Weights     []float64                   `json:"weights,omitempty"`
+	Quantized   *protocol.QuantizedUpdate   `json:"quantized,omitempty"`
+	Sparse      *protocol.SparseUpdate      `json:"sparse,omitempty"`
+	SampleCount int                         `json:"sample_count"`
+	Statement   *protocol.TrainingStatement `json:"statement,omitempty"`
+	SpecVersion int                         `json:"spec_version,omitempty"`
+}
+
+// OpenAccumulator starts accumulating round, resuming from the checkpoint at
+// policy.Path if there is one. A checkpoint from another format version,
+// round, or model spec (see SetModelSpec) fails with ErrCheckpointMismatch.
+func (a *Aggregator) OpenAccumulator(round int, policy CheckpointPolicy) (*Accumulator, error) {
+	if policy.Every < 0 || policy.Interval < 0 {
+		return nil, fmt.Errorf("%w: every %d, interval %v", ErrInvalidCheckpointPolicy, policy.Every, policy.Interval)
+	}
+	acc := &Accumulator{
+		agg:        a,
+		round:      round,
+		policy:     policy,
+		specDigest: a.specDigest(),
+		nodes:      make(map[string]int),
+		included:   make(map[string]bool),
+		now:        time.Now,
+	}
+	acc.lastSaved = acc.now()
+	if policy.Path == "" {
+		return acc, nil
+	}
+	data, err := os.ReadFile(policy.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return acc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("round %d: read checkpoint: %w", round, err)
+	}
+	if err := acc.restore(data); err != nil {
+		return nil, fmt.Errorf("round %d: %w", round, err)
+	}
+	return acc, nil
+}
+
+// specDigest is the digest of the registered model spec, or "" while none
+// is registered.
+func (a *Aggregator) specDigest() string {
+	a.mu.RLock()
+	source := a.spec
+	a.mu.RUnlock()
+	if source == nil {
+		return ""
+	}
+	spec, ok := source()
+	if !ok {
+		return ""
+	}
+	return spec.Digest()
+}
+
+// restore loads a checkpoint written by save.
+func (acc *Accumulator) restore(data []byte) error {
+	var cp accumulatorCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("%w: %v", ErrCheckpointMismatch, err)
+	}
+	switch {
+	case cp.Version != AccumulatorCheckpointVersion:
+		return fmt.Errorf("%w: format version %d, want %d", ErrCheckpointMismatch, cp.Version, AccumulatorCheckpointVersion)
+	case cp.Round != acc.round:
+		return fmt.Errorf("%w: checkpoint is for round %d", ErrCheckpointMismatch, cp.Round)
+	case cp.SpecDigest != acc.specDigest:
+		return fmt.Errorf("%w: checkpoint is for model spec %q", ErrCheckpointMismatch, cp.SpecDigest)
+	case len(cp.Receipts) != len(cp.Updates):
+		return fmt.Errorf("%w: %d updates, %d receipts", ErrCheckpointMismatch, len(cp.Updates), len(cp.Receipts))
+	}
+	for i, saved := range cp.Updates {
+		update := Update(saved)
+		if _, exists := acc.nodes[update.NodeID]; exists {
+			return fmt.Errorf("%w: node %s saved twice", ErrCheckpointMismatch, update.NodeID)
+		}
+		w, err := acc.agg.ingest(update)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrCheckpointMismatch, err)
+		}
+		if i > 0 && len(w) != acc.dims {
+			return fmt.Errorf("%w: node %s has %d weights, expected %d", ErrCheckpointMismatch, update.NodeID, len(w), acc.dims)
+		}
+		acc.dims = len(w)
+		acc.nodes[update.NodeID] = len(acc.updates)
+		acc.included[cp.Receipts[i]] = true
+		acc.updates = append(acc.updates, update)
+		acc.receipts = append(acc.receipts, cp.Receipts[i])
+	}
+	return nil
+}
+
+// Add queues update and returns its receipt ID. An update whose receipt or
+// node is already queued, including before a restart, fails with
+// ErrDuplicateUpdate; one that cannot be ingested, or whose dimension
+// differs from the batch's, fails as Aggregate would. Updates without
+// samples or with a failed training statement are queued and recorded as
+// excluded when the round is aggregated. If the update is queued but the
+// checkpoint it triggers cannot be written, Add returns the receipt with
+// the write error.
+func (acc *Accumulator) Add(update Update) (string, error) {
+	receipt := acc.receipt(update)
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	if acc.included[receipt] {
+		return "", fmt.Errorf("%w: receipt %s in round %d", ErrDuplicateUpdate, receipt, acc.round)
+	}
+	if _, exists := acc.nodes[update.NodeID]; exists {
+		return "", fmt.Errorf("%w: node %s in round %d", ErrDuplicateUpdate, update.NodeID, acc.round)
+	}
+	return acc.addLocked(update, receipt)
+}
+
+// Replace queues update in place of the one its node already queued, as
+// when the federation's dedup layer decides a second copy of the node's
+// round update supersedes the first, and returns its receipt ID. A node
+// with nothing queued is added as by Add.
+func (acc *Accumulator) Replace(update Update) (string, error) {
+	receipt := acc.receipt(update)
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	i, exists := acc.nodes[update.NodeID]
+	if !exists {
+		return acc.addLocked(update, receipt)
+	}
+	if acc.receipts[i] == receipt {
+		return receipt, nil
+	}
+	if err := acc.checkLocked(update, true); err != nil {
+		return "", err
+	}
+	delete(acc.included, acc.receipts[i])
+	acc.updates[i] = update
+	acc.receipts[i] = receipt
+	return receipt, acc.acceptedLocked(receipt)
+}
+
+// addLocked queues update, a node's first, under receipt.
+func (acc *Accumulator) addLocked(update Update, receipt string) (string, error) {
+	if err := acc.checkLocked(update, false); err != nil {
+		return "", err
+	}
+	acc.nodes[update.NodeID] = len(acc.updates)
+	acc.updates = append(acc.updates, update)
+	acc.receipts = append(acc.receipts, receipt)
+	return receipt, acc.acceptedLocked(receipt)
+}
+
+// receipt is update's receipt ID in the accumulator's round.
+func (acc *Accumulator) receipt(update Update) string {
+	return provenance.UpdateID(update.NodeID, acc.round, protocol.UpdateDigest(update.Bytes()))
+}
+
+// checkLocked ingests update to check that it decodes and has the batch's
+// dimension, which the first update sets. replacing says update takes the
+// place of a queued one, so replacing a lone update may change it.
+func (acc *Accumulator) checkLocked(update Update, replacing bool) error {
+	w, err := acc.agg.ingest(update)
+	if err != nil {
+		return fmt.Errorf("round %d: %w", acc.round, err)
+	}
+	others := len(acc.updates)
+	if replacing {
+		others--
+	}
+	if others > 0 && len(w) != acc.dims {
+		return fmt.Errorf("%w: node %s has %d weights, expected %d", ErrShapeMismatch, update.NodeID, len(w), acc.dims)
+	}
+	acc.dims = len(w)
+	return nil
+}
+
+// acceptedLocked records the receipt of a newly queued update and writes
+// the checkpoint if the policy calls for one.
+func (acc *Accumulator) acceptedLocked(receipt string) error {
+	acc.included[receipt] = true
+	acc.unsaved++
+	if acc.dueLocked() {
+		return acc.saveLocked()
+	}
+	return nil
+}
+
+// Included reports whether the update with receipt ID receipt is queued.
+func (acc *Accumulator) Included(receipt string) bool {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return acc.included[receipt]
+}
+
+// Len returns the number of updates queued.
+func (acc *Accumulator) Len() int {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return len(acc.updates)
+}
+
+// Updates returns the queued updates in node order.
+func (acc *Accumulator) Updates() []Update {
+	acc.mu.Lock()
+	updates := append([]Update(nil), acc.updates...)
+	acc.mu.Unlock()
+	sort.Slice(updates, func(i, j int) bool { return updates[i].NodeID < updates[j].NodeID })
+	return updates
+}
+
+// Result aggregates the queued updates, in node order, with Aggregate.
+func (acc *Accumulator) Result(ctx context.Context) (*AggregationResult, error) {
+	return acc.agg.Aggregate(ctx, acc.round, acc.Updates())
+}
+
+// ResultExcluding aggregates the queued updates, in node order, with
+// AggregateExcluding.
+func (acc *Accumulator) ResultExcluding(ctx context.Context, exclusions Exclusions) (*AggregationResult, error) {
+	return acc.agg.AggregateExcluding(ctx, acc.round, acc.Updates(), exclusions)
+}
+
+// ResultMultiKrum aggregates the queued updates, in node order, with
+// AggregateMultiKrum.
+func (acc *Accumulator) ResultMultiKrum(cfg KrumConfig) (*AggregationResult, *KrumResult, error) {
+	return acc.agg.AggregateMultiKrum(acc.round, acc.Updates(), cfg)
+}
+
+// Checkpoint writes the accumulator's state now, regardless of the policy's
+// triggers. It does nothing without a checkpoint path.
+func (acc *Accumulator) Checkpoint() error {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return acc.saveLocked()
+}
+
+// Discard removes the checkpoint, once the round's result is committed and
+// a restart should no longer resume it.
+func (acc *Accumulator) Discard() error {
+	if acc.policy.Path == "" {
+		return nil
+	}
+	if err := os.Remove(acc.policy.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("round %d: discard checkpoint: %w", acc.round, err)
+	}
+	return nil
+}
+
+// dueLocked reports whether the policy calls for a checkpoint now.
+func (acc *Accumulator) dueLocked() bool {
+	if acc.policy.Path == "" || acc.unsaved == 0 {
+		return false
+	}
+	if acc.policy.Every > 0 && acc.unsaved >= acc.policy.Every {
+		return true
+	}
+	return acc.policy.Interval > 0 && acc.now().Sub(acc.lastSaved) >= acc.policy.Interval
+}
+
+// saveLocked writes the checkpoint to a temporary file and renames it over
+// the previous one, so a crash mid-write leaves the previous checkpoint.
+func (acc *Accumulator) saveLocked() error {
+	if acc.policy.Path == "" {
+		return nil
+	}
+	saved := make([]checkpointUpdate, len(acc.updates))
+	for i, update := range acc.updates {
+		saved[i] = checkpointUpdate(update)
+	}
+	data, err := json.Marshal(accumulatorCheckpoint{
+		Version:    AccumulatorCheckpointVersion,
+		Round:      acc.round,
+		SpecDigest: acc.specDigest,
+		Updates:    saved,
+		Receipts:   acc.receipts,
+	})
+	if err != nil {
+		return fmt.Errorf("round %d: checkpoint: %w", acc.round, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(acc.policy.Path), filepath.Base(acc.policy.Path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("round %d: checkpoint: %w", acc.round, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("round %d: checkpoint: %w", acc.round, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("round %d: checkpoint: %w", acc.round, err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("round %d: checkpoint: %w", acc.round, err)
+	}
+	if err := os.Rename(tmp.Name(), acc.policy.Path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("round %d: checkpoint: %w", acc.round, err)
+	}
+	acc.unsaved = 0
+	acc.lastSaved = acc.now()
+	return nil
+}
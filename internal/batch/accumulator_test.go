@@ -0,0 +1,234 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func accumulatorUpdates(t *testing.T, n, dim int) []Update {
+	t.Helper()
+	rng := rand.New(rand.NewSource(705))
+	updates := make([]Update, n)
+	for i := range updates {
+		weights := make([]float64, dim)
+		for j := range weights {
+			weights[j] = rng.NormFloat64()
+		}
+		updates[i] = Update{NodeID: fmt.Sprintf("node-%03d", i), Weights: weights, SampleCount: 1 + rng.Intn(500), SpecVersion: 1}
+		if i%3 == 0 {
+			quantized, err := protocol.Quantize(weights)
+			if err != nil {
+				t.Fatalf("quantize: %v", err)
+			}
+			updates[i].Weights, updates[i].Quantized = nil, quantized
+		}
+	}
+	return updates
+}
+
+func TestAccumulatorResumesBitForBitAfterRestart(t *testing.T) {
+	spec := testSpec(1, 32)
+	agg := NewAggregator(&Config{})
+	agg.SetModelSpec(func() (protocol.ModelSpec, bool) { return spec, true })
+	updates := accumulatorUpdates(t, 200, 32)
+
+	uninterrupted, err := agg.OpenAccumulator(1, CheckpointPolicy{})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, update := range updates {
+		if _, err := uninterrupted.Add(update); err != nil {
+			t.Fatalf("add %s: %v", update.NodeID, err)
+		}
+	}
+	want, err := uninterrupted.Result(context.Background())
+	if err != nil {
+		t.Fatalf("result: %v", err)
+	}
+
+	// The aggregator dies at 75%, five updates past its last checkpoint.
+	policy := CheckpointPolicy{Path: filepath.Join(t.TempDir(), "round-1.ckpt"), Every: 25}
+	crashed, err := agg.OpenAccumulator(1, policy)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, update := range updates[:155] {
+		if _, err := crashed.Add(update); err != nil {
+			t.Fatalf("add %s: %v", update.NodeID, err)
+		}
+	}
+
+	// Restarted, it resumes at 150 and refuses what the checkpoint holds
+	// while every node resubmits.
+	resumed, err := agg.OpenAccumulator(1, policy)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if resumed.Len() != 150 {
+		t.Fatalf("resumed with %d updates, want 150", resumed.Len())
+	}
+	for i, update := range updates {
+		receipt, err := resumed.Add(update)
+		if i < 150 {
+			if !errors.Is(err, ErrDuplicateUpdate) {
+				t.Fatalf("resubmitted %s: %v, want ErrDuplicateUpdate", update.NodeID, err)
+			}
+			continue
+		}
+		if err != nil || !resumed.Included(receipt) {
+			t.Fatalf("add %s: %v", update.NodeID, err)
+		}
+	}
+	got, err := resumed.Result(context.Background())
+	if err != nil {
+		t.Fatalf("result: %v", err)
+	}
+
+	for j := range want.Weights {
+		if math.Float64bits(got.Weights[j]) != math.Float64bits(want.Weights[j]) {
+			t.Fatalf("coordinate %d = %v, want %v bit for bit", j, got.Weights[j], want.Weights[j])
+		}
+	}
+	if len(got.Manifest.Entries) != len(updates) {
+		t.Fatalf("manifest has %d entries, want %d", len(got.Manifest.Entries), len(updates))
+	}
+	for i, entry := range got.Manifest.Entries {
+		if entry != want.Manifest.Entries[i] {
+			t.Fatalf("entry %d = %+v, want %+v", i, entry, want.Manifest.Entries[i])
+		}
+	}
+	if err := resumed.Discard(); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+	if fresh, err := agg.OpenAccumulator(1, policy); err != nil || fresh.Len() != 0 {
+		t.Fatalf("after discard: %d updates, %v", fresh.Len(), err)
+	}
+}
+
+func TestAccumulatorRefusesMismatchedCheckpoints(t *testing.T) {
+	spec := testSpec(1, 8)
+	agg := NewAggregator(&Config{})
+	agg.SetModelSpec(func() (protocol.ModelSpec, bool) { return spec, true })
+	policy := CheckpointPolicy{Path: filepath.Join(t.TempDir(), "round-3.ckpt"), Every: 1}
+	acc, err := agg.OpenAccumulator(3, policy)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, update := range accumulatorUpdates(t, 4, 8) {
+		if _, err := acc.Add(update); err != nil {
+			t.Fatalf("add %s: %v", update.NodeID, err)
+		}
+	}
+	if _, err := acc.Add(Update{NodeID: "wide", Weights: make([]float64, 9), SampleCount: 1, SpecVersion: 1}); !errors.Is(err, ErrShapeMismatch) {
+		t.Fatalf("wider update: %v, want ErrShapeMismatch", err)
+	}
+
+	if _, err := agg.OpenAccumulator(4, policy); !errors.Is(err, ErrCheckpointMismatch) {
+		t.Fatalf("other round: %v, want ErrCheckpointMismatch", err)
+	}
+	spec = testSpec(2, 8)
+	if _, err := agg.OpenAccumulator(3, policy); !errors.Is(err, ErrCheckpointMismatch) {
+		t.Fatalf("other spec: %v, want ErrCheckpointMismatch", err)
+	}
+	if _, err := agg.OpenAccumulator(3, CheckpointPolicy{Every: -1}); !errors.Is(err, ErrInvalidCheckpointPolicy) {
+		t.Fatalf("negative trigger: %v, want ErrInvalidCheckpointPolicy", err)
+	}
+}
+
+func TestAccumulatorCheckpointsOnInterval(t *testing.T) {
+	agg := NewAggregator(&Config{})
+	policy := CheckpointPolicy{Path: filepath.Join(t.TempDir(), "round-1.ckpt"), Interval: time.Minute}
+	acc, err := agg.OpenAccumulator(1, policy)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	now := time.Now()
+	acc.now = func() time.Time { return now }
+	acc.lastSaved = now
+
+	updates := accumulatorUpdates(t, 3, 4)
+	add := func(update Update) {
+		t.Helper()
+		if _, err := acc.Add(update); err != nil {
+			t.Fatalf("add %s: %v", update.NodeID, err)
+		}
+	}
+	reopened := func() int {
+		t.Helper()
+		resumed, err := agg.OpenAccumulator(1, policy)
+		if err != nil {
+			t.Fatalf("reopen: %v", err)
+		}
+		return resumed.Len()
+	}
+
+	add(updates[0])
+	if n := reopened(); n != 0 {
+		t.Fatalf("checkpointed %d updates before the interval", n)
+	}
+	now = now.Add(time.Minute)
+	add(updates[1])
+	if n := reopened(); n != 2 {
+		t.Fatalf("checkpoint holds %d updates, want 2", n)
+	}
+	add(updates[2])
+	if err := acc.Checkpoint(); err != nil {
+		t.Fatalf("checkpoint: %v", err)
+	}
+	if n := reopened(); n != 3 {
+		t.Fatalf("checkpoint holds %d updates, want 3", n)
+	}
+}
+
+func TestAccumulatorFiltersTheWholeBatch(t *testing.T) {
+	agg := NewAggregator(&Config{})
+	policy := CheckpointPolicy{Path: filepath.Join(t.TempDir(), "round-2.ckpt"), Every: 1}
+	acc, err := agg.OpenAccumulator(2, policy)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	updates := []Update{
+		{NodeID: "c", Weights: []float64{1, 1}, SampleCount: 10},
+		{NodeID: "a", Weights: []float64{1, 1}, SampleCount: 10},
+		{NodeID: "b", Weights: []float64{1, 1}, SampleCount: 10},
+		{NodeID: "d", Weights: []float64{100, 100}, SampleCount: 10},
+	}
+	for _, update := range updates {
+		if _, err := acc.Add(update); err != nil {
+			t.Fatalf("add %s: %v", update.NodeID, err)
+		}
+	}
+	// b's second copy supersedes its first; the restart keeps it.
+	if _, err := acc.Replace(Update{NodeID: "b", Weights: []float64{3, 3}, SampleCount: 10}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	resumed, err := agg.OpenAccumulator(2, policy)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	result, err := resumed.Result(context.Background())
+	if err != nil {
+		t.Fatalf("result: %v", err)
+	}
+	var order []string
+	for _, entry := range result.Manifest.Entries {
+		order = append(order, entry.NodeID)
+		if entry.NodeID == "d" && entry.Reason != protocol.ReasonNormOutlier {
+			t.Fatalf("outlier recorded as %q, want %q", entry.Reason, protocol.ReasonNormOutlier)
+		}
+	}
+	if fmt.Sprint(order) != "[a b c d]" {
+		t.Fatalf("manifest order %v, want node order", order)
+	}
+	if want := 5.0 / 3; math.Abs(result.Weights[0]-want) > 1e-12 {
+		t.Fatalf("aggregate %v, want %v", result.Weights[0], want)
+	}
+}
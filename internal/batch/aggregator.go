@@ -8,6 +8,12 @@ package batch
 import (
 	"fmt"
 	"math"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
 )
 
 // Mode defines the operational state of the aggregator.
@@ -16,7 +22,8 @@ type Mode int
 const (
 	// ModeHonestOnly assumes all participants follow protocol.
 	ModeHonestOnly Mode = iota
-	// ModeByzantineMix assumes up to 55.5% malicious nodes.
+	// ModeByzantineMix assumes malicious nodes up to Config.FaultModel's
+	// threshold.
 	ModeByzantineMix
 )
 
@@ -26,11 +33,47 @@ type Config struct {
 	HonestNodes      int
 	MaliciousNodes   int
 	RedundancyFactor int
+	// OutlierFactor excludes updates whose L2 norm exceeds this multiple of
+	// the median norm. Zero uses DefaultOutlierFactor; negative disables.
+	OutlierFactor float64
+	// ClipNorm bounds update magnitudes. Quantized updates whose declared
+	// scale can represent an element beyond it, and sparse updates carrying
+	// such an element, are rejected. Zero disables.
+	ClipNorm float64
+	// ModelDimension is the registered length of the model. Updates of any
+	// other length, including sparse updates declaring one, are rejected
+	// before their weights are expanded. Zero disables.
+	ModelDimension int
+	// FaultModel sets the safety check ProcessRound applies: at most
+	// FaultModel.MaxFaulty(TotalNodes) malicious nodes. Zero is Classic33.
+	FaultModel faultmodel.Model
+	// Topology is validated against FaultModel; Hierarchical55 needs
+	// MultiTier.
+	Topology faultmodel.Topology
 }
 
+// BaseModelResolver returns the digest of the global model distributed for
+// round, or false if that model is unknown.
+type BaseModelResolver func(round int) (string, bool)
+
+// BaseWeightsResolver returns the weights of the global model distributed
+// for round, or false if that model is unknown. Empty weights stand for
+// the zero model a federation's first round trains from.
+type BaseWeightsResolver func(round int) ([]float64, bool)
+
 // Aggregator handles the secure summation of updates.
 type Aggregator struct {
 	Config *Config
+
+	mu          sync.RWMutex
+	baseModel   BaseModelResolver
+	baseWeights BaseWeightsResolver
+	detection   *DetectionConfig
+	provenance  provenance.Sink
+	lifecycle   events.Publisher
+	spec        ModelSpecSource
+	scale       func(nodeID string) float64
+	centralDP   *privacy.DPAggregator
 }
 
 // NewAggregator creates a verified aggregator instance.
@@ -38,19 +81,76 @@ func NewAggregator(cfg *Config) *Aggregator {
 	return &Aggregator{Config: cfg}
 }
 
-// ProcessRound verifies liveness and safety per Theorem 4 and Theorem 1.
+// SetBaseModelResolver requires every update to carry a training statement
+// whose base digest is the model resolve returns for the round. Updates
+// trained from any other model are excluded as stale_base_model. nil
+// disables the check.
+func (a *Aggregator) SetBaseModelResolver(resolve BaseModelResolver) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.baseModel = resolve
+}
+
+// SetBaseWeights gives central DP the global model each round was trained
+// from, so it clips and noises each update's change from that model rather
+// than the model itself. Central DP refuses rounds whose base model resolve
+// does not know.
+func (a *Aggregator) SetBaseWeights(resolve BaseWeightsResolver) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.baseWeights = resolve
+}
+
+// SetWeightScale multiplies each included update's sample count by
+// scale(nodeID) before weights are normalized, so nodes on probation (see
+// consensus.Probation.UpdateWeight) count for less than their samples.
+// nil weighs every update by its samples alone.
+func (a *Aggregator) SetWeightScale(scale func(nodeID string) float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.scale = scale
+}
+
+// SetCentralDP clips every included update's change from the round's base
+// model (see SetBaseWeights) and noises the mean change through dp before
+// adding it back to the base, recording the release in the round's
+// manifest. nil releases aggregates without noise.
+func (a *Aggregator) SetCentralDP(dp *privacy.DPAggregator) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.centralDP = dp
+}
+
+// sampleWeight is update's unnormalized aggregation weight: its sample
+// count, scaled when a weight scale is set.
+func (a *Aggregator) sampleWeight(update Update) float64 {
+	a.mu.RLock()
+	scale := a.scale
+	a.mu.RUnlock()
+	if scale == nil {
+		return float64(update.SampleCount)
+	}
+	return float64(update.SampleCount) * scale(update.NodeID)
+}
+
+// ProcessRound verifies liveness per Theorem 4 and safety per the
+// configured fault model.
 func (a *Aggregator) ProcessRound(mode Mode) error {
 	// Liveness Check (Theorem 4): P > 1 - exp(-k/2)
 	k := float64(a.Config.HonestNodes) * (1.0 - math.Pow(0.5, float64(a.Config.RedundancyFactor)))
 	prob := 1.0 - math.Exp(-k/2.0)
 
 	if prob < 0.9999 {
-		return fmt.Errorf("liveness check failed: success probability %f below 99.99%% threshold", prob)
+		return fmt.Errorf("%w: success probability %f below 99.99%% threshold", ErrLivenessUnmet, prob)
 	}
 
-	// Safety Check (Theorem 1): n > 2f
-	if a.Config.TotalNodes <= 2*a.Config.MaliciousNodes {
-		return fmt.Errorf("byzantine safety violation: n <= 2f")
+	// Safety Check: f within the fault model's threshold (n >= 3f+1 for
+	// Classic33, Theorem 1 for Hierarchical55)
+	if err := a.Config.FaultModel.Validate(a.Config.Topology); err != nil {
+		return fmt.Errorf("%w: %w", ErrSafetyViolation, err)
+	}
+	if err := a.Config.FaultModel.CheckFaulty(a.Config.TotalNodes, a.Config.MaliciousNodes); err != nil {
+		return fmt.Errorf("%w: %w", ErrSafetyViolation, err)
 	}
 
 	return nil
@@ -1,7 +1,20 @@
 package batch
 
 import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
 	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
 )
 
 func TestNewAggregator(t *testing.T) {
@@ -59,3 +72,739 @@ func TestProcessRoundSafetyFailure(t *testing.T) {
 		t.Fatal("expected safety failure error")
 	}
 }
+
+func TestProcessRoundSafetyFollowsFaultModel(t *testing.T) {
+	cases := []struct {
+		model     faultmodel.Model
+		total     int
+		malicious int
+		safe      bool
+	}{
+		{faultmodel.Classic33, 30, 9, true},
+		{faultmodel.Classic33, 30, 10, false},
+		{faultmodel.Classic33, 100, 33, true},
+		{faultmodel.Classic33, 100, 34, false},
+		{faultmodel.Hierarchical55, 36, 16, true},
+		{faultmodel.Hierarchical55, 36, 17, false},
+		{faultmodel.Hierarchical55, 100, 44, true},
+		{faultmodel.Hierarchical55, 100, 45, false},
+	}
+	for _, tc := range cases {
+		agg := NewAggregator(&Config{
+			TotalNodes:       tc.total,
+			HonestNodes:      tc.total - tc.malicious,
+			MaliciousNodes:   tc.malicious,
+			RedundancyFactor: 10,
+			FaultModel:       tc.model,
+			Topology:         faultmodel.Topology{MultiTier: true},
+		})
+		err := agg.ProcessRound(ModeByzantineMix)
+		if tc.safe && err != nil {
+			t.Errorf("%s with %d of %d malicious: %v", tc.model, tc.malicious, tc.total, err)
+		}
+		if !tc.safe && (!errors.Is(err, ErrSafetyViolation) || !errors.Is(err, faultmodel.ErrThresholdExceeded)) {
+			t.Errorf("%s with %d of %d malicious: expected a threshold violation, got %v", tc.model, tc.malicious, tc.total, err)
+		}
+	}
+
+	flat := NewAggregator(&Config{TotalNodes: 25, HonestNodes: 25, RedundancyFactor: 10, FaultModel: faultmodel.Hierarchical55})
+	if err := flat.ProcessRound(ModeByzantineMix); !errors.Is(err, faultmodel.ErrUnsafeTopology) {
+		t.Fatalf("hierarchical55 on a flat topology: expected ErrUnsafeTopology, got %v", err)
+	}
+}
+
+func TestAggregateManifestRecordsExclusions(t *testing.T) {
+	agg := NewAggregator(&Config{TotalNodes: 5, HonestNodes: 5, RedundancyFactor: 10})
+	updates := []Update{
+		{NodeID: "node-a", Weights: []float64{1, 1}, SampleCount: 100},
+		{NodeID: "node-b", Weights: []float64{1.2, 0.8}, SampleCount: 300},
+		{NodeID: "node-c", Weights: []float64{0.9, 1.1}, SampleCount: 100},
+		{NodeID: "node-d", Weights: []float64{40, -40}, SampleCount: 500},
+		{NodeID: "node-e", Weights: []float64{1, 1}, SampleCount: 0},
+	}
+
+	result, err := agg.Aggregate(context.Background(), 7, updates)
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	manifest := result.Manifest
+	if manifest.Round != 7 || len(manifest.Entries) != len(updates) {
+		t.Fatalf("expected 5 entries for round 7, got %d for round %d", len(manifest.Entries), manifest.Round)
+	}
+
+	expectedWeights := map[string]float64{"node-a": 0.2, "node-b": 0.6, "node-c": 0.2}
+	expected := []float64{0, 0}
+	for _, update := range updates {
+		entry, ok := manifest.Entry(update.NodeID)
+		if !ok {
+			t.Fatalf("missing manifest entry for %s", update.NodeID)
+		}
+		if entry.UpdateDigest != protocol.UpdateDigest(encodeWeights(update.Weights)) {
+			t.Fatalf("%s: manifest digest does not match update", update.NodeID)
+		}
+		weight, included := expectedWeights[update.NodeID]
+		if entry.Included != included || math.Abs(entry.AppliedWeight-weight) > 1e-9 {
+			t.Fatalf("%s: expected included=%v weight=%f, got %+v", update.NodeID, included, weight, entry)
+		}
+		for j, w := range update.Weights {
+			expected[j] += entry.AppliedWeight * w
+		}
+	}
+	if entry, _ := manifest.Entry("node-d"); entry.Reason != protocol.ReasonNormOutlier {
+		t.Fatalf("expected node-d excluded as norm outlier, got %q", entry.Reason)
+	}
+	if entry, _ := manifest.Entry("node-e"); entry.Reason != protocol.ReasonNoSamples {
+		t.Fatalf("expected node-e excluded for no samples, got %q", entry.Reason)
+	}
+	for j := range expected {
+		if math.Abs(result.Weights[j]-expected[j]) > 1e-9 {
+			t.Fatalf("aggregate does not match manifest weights: %v vs %v", result.Weights, expected)
+		}
+	}
+
+	if _, err := agg.Aggregate(context.Background(), 8, append(updates[:1:1], updates[0])); !errors.Is(err, ErrDuplicateUpdate) {
+		t.Fatalf("expected ErrDuplicateUpdate, got %v", err)
+	}
+	if _, err := agg.Aggregate(context.Background(), 8, []Update{updates[0], {NodeID: "x", Weights: []float64{1}, SampleCount: 1}}); !errors.Is(err, ErrShapeMismatch) {
+		t.Fatalf("expected ErrShapeMismatch, got %v", err)
+	}
+}
+
+func TestQuantizedUpdateRoundTrip(t *testing.T) {
+	weights := []float64{-0.75, -0.1, 0, 0.02, 0.5, 1.25}
+	q, err := protocol.Quantize(weights)
+	if err != nil {
+		t.Fatalf("quantize: %v", err)
+	}
+	restored := q.Dequantize()
+	for i, w := range weights {
+		if math.Abs(restored[i]-w) > q.Scale/2+1e-12 {
+			t.Fatalf("element %d: %f restored as %f, beyond scale/2 = %f", i, w, restored[i], q.Scale/2)
+		}
+	}
+	if restored[2] != 0 {
+		t.Fatalf("expected zero to survive quantization exactly, got %f", restored[2])
+	}
+	if diff := l2Distance(restored, weights); diff > q.ErrorBound() {
+		t.Fatalf("round-trip error %f exceeds bound %f", diff, q.ErrorBound())
+	}
+}
+
+func TestAggregateMixedPrecisionWithinBound(t *testing.T) {
+	agg := NewAggregator(&Config{TotalNodes: 3, HonestNodes: 3, RedundancyFactor: 10, ClipNorm: 2})
+	original := [][]float64{
+		{0.10, -0.20, 0.30, 0.05},
+		{0.12, -0.18, 0.28, 0.07},
+		{0.09, -0.22, 0.31, 0.04},
+	}
+	q1, _ := protocol.Quantize(original[1])
+	q2, _ := protocol.Quantize(original[2])
+	updates := []Update{
+		{NodeID: "float", Weights: original[0], SampleCount: 100},
+		{NodeID: "int8-a", Quantized: q1, SampleCount: 200},
+		{NodeID: "int8-b", Quantized: q2, SampleCount: 100},
+	}
+
+	result, err := agg.Aggregate(context.Background(), 3, updates)
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+
+	exact := make([]float64, len(original[0]))
+	bound := 0.0
+	for i, update := range updates {
+		entry, _ := result.Manifest.Entry(update.NodeID)
+		if (update.Quantized != nil) != (entry.QuantizationErrorBound > 0) {
+			t.Fatalf("%s: unexpected quantization error bound %f", update.NodeID, entry.QuantizationErrorBound)
+		}
+		wantTier := protocol.UploadTierFull
+		if update.Quantized != nil {
+			wantTier = protocol.UploadTierQuantized
+		}
+		if entry.UploadTier != wantTier {
+			t.Fatalf("%s: expected upload tier %q, got %q", update.NodeID, wantTier, entry.UploadTier)
+		}
+		if want := float64(update.SampleCount) / 400; math.Abs(entry.AppliedWeight-want) > 1e-12 {
+			t.Fatalf("%s: expected weight %f regardless of tier, got %f", update.NodeID, want, entry.AppliedWeight)
+		}
+		for j, w := range original[i] {
+			exact[j] += entry.AppliedWeight * w
+		}
+		bound += entry.AppliedWeight * entry.QuantizationErrorBound
+	}
+	if diff := l2Distance(result.Weights, exact); diff > bound {
+		t.Fatalf("mixed-precision aggregate error %f exceeds documented bound %f", diff, bound)
+	}
+
+	wide := &protocol.QuantizedUpdate{Scale: 0.5, Payload: make([]byte, 4)}
+	updates[1] = Update{NodeID: "int8-a", Quantized: wide, SampleCount: 200}
+	if _, err := agg.Aggregate(context.Background(), 4, updates); !errors.Is(err, ErrClipNormExceeded) {
+		t.Fatalf("expected ErrClipNormExceeded for oversized scale, got %v", err)
+	}
+}
+
+func TestAggregateMergesSparseAndDense(t *testing.T) {
+	agg := NewAggregator(&Config{ClipNorm: 2})
+	dense := []float64{0.4, 0, -0.2, 0.1}
+	sparse, err := protocol.TopK([]float64{0, 0.8, -0.1, 0.4}, 2)
+	if err != nil {
+		t.Fatalf("top-k: %v", err)
+	}
+	if len(sparse.Indices) != 2 || sparse.Indices[0] != 1 || sparse.Indices[1] != 3 {
+		t.Fatalf("expected the two largest coordinates in index order, got %v", sparse.Indices)
+	}
+
+	result, err := agg.Aggregate(context.Background(), 1, []Update{
+		{NodeID: "dense", Weights: dense, SampleCount: 300},
+		{NodeID: "sparse", Sparse: sparse, SampleCount: 100},
+	})
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	want := []float64{0.3, 0.2, -0.15, 0.175}
+	if diff := l2Distance(result.Weights, want); diff > 1e-12 {
+		t.Fatalf("expected %v, got %v", want, result.Weights)
+	}
+	if entry, _ := result.Manifest.Entry("sparse"); entry.UploadTier != protocol.UploadTierSparse || entry.AppliedWeight != 0.25 {
+		t.Fatalf("unexpected sparse manifest entry %+v", entry)
+	}
+}
+
+func TestAggregateScalesProbationaryWeight(t *testing.T) {
+	agg := NewAggregator(&Config{OutlierFactor: -1})
+	agg.SetWeightScale(func(nodeID string) float64 {
+		if nodeID == "newcomer" {
+			return 0.1
+		}
+		return 1
+	})
+	// Equal samples, but the newcomer counts a tenth: weights 10/11 and
+	// 1/11.
+	result, err := agg.Aggregate(context.Background(), 1, []Update{
+		{NodeID: "member", Weights: []float64{1, 0}, SampleCount: 100},
+		{NodeID: "newcomer", Weights: []float64{-10, 11}, SampleCount: 100},
+	})
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if diff := l2Distance(result.Weights, []float64{0, 1}); diff > 1e-12 {
+		t.Fatalf("aggregate = %v, want [0 1]", result.Weights)
+	}
+	if entry, _ := result.Manifest.Entry("newcomer"); math.Abs(entry.AppliedWeight-1.0/11) > 1e-12 || entry.SampleCount != 100 {
+		t.Fatalf("newcomer entry = %+v", entry)
+	}
+
+	agg.SetWeightScale(nil)
+	result, err = agg.Aggregate(context.Background(), 2, []Update{
+		{NodeID: "member", Weights: []float64{1, 0}, SampleCount: 100},
+		{NodeID: "newcomer", Weights: []float64{-10, 11}, SampleCount: 100},
+	})
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if entry, _ := result.Manifest.Entry("newcomer"); entry.AppliedWeight != 0.5 {
+		t.Fatalf("unscaled newcomer entry = %+v", entry)
+	}
+}
+
+func TestAggregateNoisesForTheIncludedCohort(t *testing.T) {
+	config := privacy.DefaultDPAggregatorConfig()
+	config.ExpectedCohort, config.MinCohort = 10, 5
+	dp, err := privacy.NewDPAggregator(config)
+	if err != nil {
+		t.Fatalf("dp aggregator: %v", err)
+	}
+	dp.SetNoiseSource(rand.New(rand.NewSource(719)))
+	agg := NewAggregator(&Config{})
+	agg.SetCentralDP(dp)
+	if _, err := agg.Aggregate(context.Background(), 1, []Update{{NodeID: "node-0", Weights: []float64{0.3, 0.4}, SampleCount: 10}}); !errors.Is(err, ErrUnknownBaseModel) {
+		t.Fatalf("expected ErrUnknownBaseModel without a base model, got %v", err)
+	}
+	base := map[int][]float64{3: {100, 100}}
+	agg.SetBaseWeights(func(round int) ([]float64, bool) {
+		return base[round], true
+	})
+
+	// Six of the ten expected report and one is an outlier, so the noise
+	// is calibrated to the five included; the outlier's norm is clipped
+	// anyway.
+	updates := func(count int) []Update {
+		var updates []Update
+		for i := 0; i < count; i++ {
+			updates = append(updates, Update{NodeID: fmt.Sprintf("node-%d", i), Weights: []float64{0.3, 0.4}, SampleCount: 10})
+		}
+		return append(updates, Update{NodeID: "outlier", Weights: []float64{30, 40}, SampleCount: 10})
+	}
+	result, err := agg.Aggregate(context.Background(), 1, updates(5))
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	release := result.Manifest.Privacy
+	if release == nil || release.Participants != 5 || release.ExpectedCohort != 10 || math.Abs(release.NoiseScale-dp.NoiseScaleFor(5)) > 1e-12 {
+		t.Fatalf("manifest privacy = %+v, want noise for 5 participants", release)
+	}
+	if releases := dp.Releases(); len(releases) != 1 || releases[0] != *release {
+		t.Fatalf("audit log %+v does not match the manifest", releases)
+	}
+
+	// Four included is below the minimum cohort: refused, and retryable
+	// once more participants report.
+	if _, err := agg.Aggregate(context.Background(), 2, updates(4)); !errors.Is(err, privacy.ErrCohortTooSmall) || !Retryable(err) {
+		t.Fatalf("tiny cohort: expected a retryable ErrCohortTooSmall, got %v", err)
+	}
+	if len(dp.Releases()) != 1 {
+		t.Fatalf("refused round was charged: %+v", dp.Releases())
+	}
+
+	// Nodes submit trained models: only their change from the base is
+	// clipped and noised, so the model survives.
+	var trained []Update
+	for i := 0; i < 5; i++ {
+		trained = append(trained, Update{NodeID: fmt.Sprintf("node-%d", i), Weights: []float64{100.3, 100.4}, SampleCount: 10})
+	}
+	result, err = agg.Aggregate(context.Background(), 3, trained)
+	if err != nil {
+		t.Fatalf("aggregate trained models: %v", err)
+	}
+	bound := 6 * result.Manifest.Privacy.NoiseScale
+	if math.Abs(result.Weights[0]-100.3) > bound || math.Abs(result.Weights[1]-100.4) > bound {
+		t.Fatalf("aggregate %v strayed from the trained models beyond the noise", result.Weights)
+	}
+
+	// A round whose context ends is abandoned before it is charged.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := agg.Aggregate(ctx, 4, trained); !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled round: expected context.Canceled, got %v", err)
+	}
+	if len(dp.Releases()) != 2 {
+		t.Fatalf("canceled round was charged: %+v", dp.Releases())
+	}
+}
+
+func TestAggregateRejectsMalformedSparseUpdates(t *testing.T) {
+	agg := NewAggregator(&Config{ClipNorm: 2})
+	dense := Update{NodeID: "dense", Weights: []float64{0.1, 0.2, 0.3}, SampleCount: 10}
+	cases := map[string]*protocol.SparseUpdate{
+		"index out of range": {Dim: 3, Indices: []uint32{3}, Values: []float64{0.1}},
+		"duplicate index":    {Dim: 3, Indices: []uint32{1, 1}, Values: []float64{0.1, 0.2}},
+		"length mismatch":    {Dim: 3, Indices: []uint32{0, 1}, Values: []float64{0.1}},
+		"wrong dimension":    {Dim: 4, Indices: []uint32{0}, Values: []float64{0.1}},
+	}
+	for name, sparse := range cases {
+		_, err := agg.Aggregate(context.Background(), 1, []Update{dense, {NodeID: "sparse", Sparse: sparse, SampleCount: 10}})
+		if !errors.Is(err, ErrShapeMismatch) {
+			t.Fatalf("%s: expected ErrShapeMismatch, got %v", name, err)
+		}
+	}
+
+	wide := &protocol.SparseUpdate{Dim: 3, Indices: []uint32{0}, Values: []float64{5}}
+	if _, err := agg.Aggregate(context.Background(), 1, []Update{dense, {NodeID: "sparse", Sparse: wide, SampleCount: 10}}); !errors.Is(err, ErrClipNormExceeded) {
+		t.Fatalf("expected ErrClipNormExceeded for an oversized sparse value, got %v", err)
+	}
+}
+
+func TestAggregateChecksTrainingStatementBaseModel(t *testing.T) {
+	previous := []byte("global-model-round-1")
+	current := []byte("global-model-round-2")
+	agg := NewAggregator(&Config{})
+	agg.SetBaseModelResolver(func(round int) (string, bool) {
+		if round != 3 {
+			return "", false
+		}
+		return protocol.UpdateDigest(current), true
+	})
+
+	trained := func(nodeID string, base []byte) Update {
+		update := Update{NodeID: nodeID, Weights: []float64{0.1, 0.2}, SampleCount: 10}
+		update.Statement = protocol.NewTrainingStatement(nodeID, protocol.TrainingTask{Round: 3, GlobalWeights: base}, update.Bytes())
+		return update
+	}
+	honest := trained("honest", current)
+	stale := trained("stale", previous)
+	forged := trained("forged", []byte("never-distributed"))
+	// Relabeling a stale statement with the current base breaks its
+	// commitment.
+	relabeled := trained("relabeled", previous)
+	relabeled.Statement.BaseDigest = protocol.UpdateDigest(current)
+	missing := Update{NodeID: "missing", Weights: []float64{0.1, 0.2}, SampleCount: 10}
+
+	result, err := agg.Aggregate(context.Background(), 3, []Update{honest, stale, forged, relabeled, missing})
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	want := map[string]reasons.Code{
+		"honest":    protocol.ReasonIncluded,
+		"stale":     protocol.ReasonStaleBaseModel,
+		"forged":    protocol.ReasonStaleBaseModel,
+		"relabeled": protocol.ReasonInvalidStatement,
+		"missing":   protocol.ReasonInvalidStatement,
+	}
+	for nodeID, reason := range want {
+		entry, _ := result.Manifest.Entry(nodeID)
+		if entry.Reason != reason {
+			t.Fatalf("%s: reason %q, want %q", nodeID, entry.Reason, reason)
+		}
+	}
+	if entry, _ := result.Manifest.Entry("honest"); !entry.Included || entry.BaseDigest != protocol.UpdateDigest(current) {
+		t.Fatalf("expected the honest base digest in the manifest, got %+v", entry)
+	}
+	if entry, _ := result.Manifest.Entry("stale"); entry.BaseDigest != protocol.UpdateDigest(previous) {
+		t.Fatalf("expected the claimed stale base digest in the manifest, got %+v", entry)
+	}
+
+	// An update claiming the wrong round is invalid even with the right base.
+	if _, err := agg.Aggregate(context.Background(), 4, []Update{honest}); err == nil {
+		t.Fatal("expected a statement for round 3 to be excluded from round 4")
+	}
+}
+
+func l2Distance(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(sum)
+}
+
+// poisonedFixture returns honest updates scattered around a shared model and
+// byzantine updates that all pull in the same direction.
+func poisonedFixture(honest, byzantine, dims int) [][]float64 {
+	rng := rand.New(rand.NewSource(641))
+	base := make([]float64, dims)
+	shift := make([]float64, dims)
+	for j := range base {
+		base[j] = rng.NormFloat64()
+		shift[j] = 0.2
+		if rng.Intn(2) == 0 {
+			shift[j] = -0.2
+		}
+	}
+	updates := make([][]float64, honest+byzantine)
+	for i := range updates {
+		u := make([]float64, dims)
+		for j := range u {
+			u[j] = base[j] + 0.1*rng.NormFloat64()
+			if i >= honest {
+				u[j] += shift[j]
+			}
+		}
+		updates[i] = u
+	}
+	return updates
+}
+
+func TestPairwiseDistancesMatchesNaive(t *testing.T) {
+	vectors := poisonedFixture(11, 2, 5000)
+	for _, workers := range []int{1, 3, 8} {
+		for _, block := range []int{1, 4, 16} {
+			got := PairwiseDistances(vectors, workers, block)
+			for i := range vectors {
+				for j := range vectors {
+					want := l2Distance(vectors[i], vectors[j])
+					want *= want
+					if math.Abs(got[i][j]-want) > 1e-9*math.Max(1, want) {
+						t.Fatalf("workers=%d block=%d: d[%d][%d] = %f, want %f", workers, block, i, j, got[i][j], want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMultiKrumRejectsPoisonedUpdates(t *testing.T) {
+	const honest, byzantine = 32, 8
+	updates := poisonedFixture(honest, byzantine, 4096)
+	collector := monitoring.NewCollector(10)
+
+	result, err := MultiKrum(updates, KrumConfig{Byzantine: byzantine, Workers: 4, Collector: collector})
+	if err != nil {
+		t.Fatalf("multi-krum: %v", err)
+	}
+	if len(result.Selected) != honest {
+		t.Fatalf("expected %d selected updates, got %d", honest, len(result.Selected))
+	}
+	for _, i := range result.Selected {
+		if i >= honest {
+			t.Fatalf("poisoned update %d was selected", i)
+		}
+	}
+	if got := len(collector.GetMetricsByType(monitoring.MetricKrumPhase)); got != 3 {
+		t.Fatalf("expected three phase timings, got %d", got)
+	}
+
+	if _, err := MultiKrum(updates[:2*byzantine+2], KrumConfig{Byzantine: byzantine}); !errors.Is(err, ErrTooFewUpdates) {
+		t.Fatalf("expected ErrTooFewUpdates, got %v", err)
+	}
+}
+
+func TestMultiKrumProjectionAgreesWithExact(t *testing.T) {
+	const honest, byzantine = 32, 8
+	updates := poisonedFixture(honest, byzantine, 16384)
+
+	exact, err := MultiKrum(updates, KrumConfig{Byzantine: byzantine})
+	if err != nil {
+		t.Fatalf("exact multi-krum: %v", err)
+	}
+	projected, err := MultiKrum(updates, KrumConfig{Byzantine: byzantine, ProjectionDim: 512, ProjectionSeed: 7})
+	if err != nil {
+		t.Fatalf("projected multi-krum: %v", err)
+	}
+	if projected.Timing.Projection <= 0 {
+		t.Fatal("expected projection time to be reported")
+	}
+
+	chosen := make(map[int]bool, len(exact.Selected))
+	for _, i := range exact.Selected {
+		chosen[i] = true
+	}
+	agree := 0
+	for _, i := range projected.Selected {
+		if chosen[i] {
+			agree++
+		}
+	}
+	if ratio := float64(agree) / float64(len(exact.Selected)); ratio < 0.95 {
+		t.Fatalf("projected selection agreement %.2f below 0.95", ratio)
+	}
+}
+
+func TestAggregateMultiKrumRecordsRejections(t *testing.T) {
+	const honest, byzantine = 6, 1
+	weights := poisonedFixture(honest, byzantine, 64)
+	updates := make([]Update, len(weights))
+	for i, w := range weights {
+		updates[i] = Update{NodeID: fmt.Sprintf("node-%d", i), Weights: w, SampleCount: 10}
+	}
+
+	agg := NewAggregator(&Config{})
+	result, krum, err := agg.AggregateMultiKrum(5, updates, KrumConfig{Byzantine: byzantine})
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if len(krum.Selected) != honest {
+		t.Fatalf("expected %d selected, got %d", honest, len(krum.Selected))
+	}
+	entry, _ := result.Manifest.Entry(fmt.Sprintf("node-%d", honest))
+	if entry.Included || entry.Reason != protocol.ReasonKrumRejected {
+		t.Fatalf("expected the poisoned update to be krum rejected, got %+v", entry)
+	}
+	if entry, _ := result.Manifest.Entry("node-0"); !entry.Included || math.Abs(entry.AppliedWeight-1.0/honest) > 1e-12 {
+		t.Fatalf("expected an equal share for honest updates, got %+v", entry)
+	}
+}
+
+func BenchmarkPairwiseDistances(b *testing.B) {
+	vectors := poisonedFixture(56, 8, 1<<16)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				PairwiseDistances(vectors, workers, DefaultKrumBlockSize)
+			}
+		})
+	}
+}
+
+func BenchmarkMultiKrumProjected(b *testing.B) {
+	vectors := poisonedFixture(56, 8, 1<<16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := MultiKrum(vectors, KrumConfig{Byzantine: 8, ProjectionDim: 1024}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAggregateRejectsAbsurdDeclaredDimensionBeforeAllocating(t *testing.T) {
+	agg := NewAggregator(&Config{OutlierFactor: -1, ModelDimension: 4})
+	honest := Update{NodeID: "honest", Weights: []float64{1, 2, 3, 4}, SampleCount: 10}
+	forged := Update{NodeID: "forged", Sparse: &protocol.SparseUpdate{Dim: 1 << 40, Indices: []uint32{0}, Values: []float64{1}}, SampleCount: 10}
+
+	encoded := (&protocol.SparseUpdate{Dim: 1 << 31, Indices: []uint32{7}, Values: []float64{1}}).Bytes()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	if _, err := agg.Aggregate(context.Background(), 1, []Update{honest, forged}); !errors.Is(err, ErrShapeMismatch) {
+		t.Fatalf("expected ErrShapeMismatch for a forged dimension, got %v", err)
+	}
+	if _, err := protocol.DecodeSparseUpdate(encoded, 4); err == nil {
+		t.Fatal("expected the decoder to refuse a dimension beyond the model")
+	}
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Fatalf("rejecting a forged dimension allocated %d bytes", allocated)
+	}
+
+	short := Update{NodeID: "short", Weights: []float64{1, 2}, SampleCount: 10}
+	if _, err := agg.Aggregate(context.Background(), 2, []Update{short}); !errors.Is(err, ErrShapeMismatch) {
+		t.Fatalf("expected ErrShapeMismatch for a short update, got %v", err)
+	}
+	decoded, err := protocol.DecodeSparseUpdate((&protocol.SparseUpdate{Dim: 4, Indices: []uint32{1}, Values: []float64{2}}).Bytes(), 4)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	result, err := agg.Aggregate(context.Background(), 3, []Update{honest, {NodeID: "sparse", Sparse: decoded, SampleCount: 10}})
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if want := []float64{0.5, 2, 1.5, 2}; fmt.Sprint(result.Weights) != fmt.Sprint(want) {
+		t.Fatalf("weights %v, want %v", result.Weights, want)
+	}
+}
+
+// fixedPlugin scores updates by their first weight and can be made to fail.
+type fixedPlugin struct {
+	id    string
+	fail  bool
+	calls int
+}
+
+func (p *fixedPlugin) ID() string { return p.id }
+
+func (p *fixedPlugin) Analyze(update []byte) (DetectionVerdict, error) {
+	p.calls++
+	if p.fail {
+		return DetectionVerdict{}, errors.New("trapped")
+	}
+	first := math.Float64frombits(binary.LittleEndian.Uint64(update))
+	return DetectionVerdict{Score: first / 100, Byzantine: first >= 100}, nil
+}
+
+func TestAggregateCombinesDetectionPluginScores(t *testing.T) {
+	updates := []Update{
+		{NodeID: "a", Weights: []float64{1, 1}, SampleCount: 1},
+		{NodeID: "b", Weights: []float64{1, 1}, SampleCount: 1},
+		{NodeID: "c", Weights: []float64{60, 1}, SampleCount: 1},
+		{NodeID: "d", Weights: []float64{100, 1}, SampleCount: 1},
+	}
+	scorer := &fixedPlugin{id: "scorer"}
+	broken := &fixedPlugin{id: "broken", fail: true}
+	agg := NewAggregator(&Config{OutlierFactor: -1})
+	agg.SetDetection(&DetectionConfig{
+		Plugins:       []DetectionPlugin{broken, scorer},
+		PluginWeights: map[string]float64{"scorer": 3},
+		Threshold:     0.5,
+	})
+
+	result, err := agg.Aggregate(context.Background(), 1, updates)
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if broken.calls != 1 || len(result.PluginFailures) != 1 || result.PluginFailures[0].Plugin != "broken" || result.PluginFailures[0].NodeID != "a" {
+		t.Fatalf("broken plugin should fail once and be dropped: calls=%d failures=%+v", broken.calls, result.PluginFailures)
+	}
+	// The outlier filter is off, so the built-in score is 0 and the scorer
+	// carries 3/4 of the weight: c scores 0.45, d is flagged Byzantine.
+	want := []struct {
+		score    float64
+		included bool
+	}{{0.0075, true}, {0.0075, true}, {0.45, true}, {0.75, false}}
+	for i, entry := range result.Manifest.Entries {
+		if math.Abs(entry.DetectionScore-want[i].score) > 1e-12 || entry.Included != want[i].included {
+			t.Fatalf("entry %d = %+v, want score %v included %t", i, entry, want[i].score, want[i].included)
+		}
+	}
+	if result.Manifest.Entries[3].Reason != protocol.ReasonDetected {
+		t.Fatalf("reason %q", result.Manifest.Entries[3].Reason)
+	}
+
+	agg.SetDetection(nil)
+	result, err = agg.Aggregate(context.Background(), 2, updates)
+	if err != nil {
+		t.Fatalf("aggregate without detection: %v", err)
+	}
+	if !result.Manifest.Entries[3].Included || result.Manifest.Entries[3].DetectionScore != 0 {
+		t.Fatalf("detection still applied: %+v", result.Manifest.Entries[3])
+	}
+}
+
+func testSpec(version, dim int) protocol.ModelSpec {
+	return protocol.ModelSpec{Name: "mlp", Version: version, Dimension: dim, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp"))}
+}
+
+func TestAggregateRejectsUpdatesForAnotherSpec(t *testing.T) {
+	spec := testSpec(2, 4)
+	agg := NewAggregator(&Config{OutlierFactor: -1})
+	agg.SetModelSpec(func() (protocol.ModelSpec, bool) { return spec, true })
+
+	current := Update{NodeID: "current", Weights: []float64{1, 2, 3, 4}, SampleCount: 10, SpecVersion: 2}
+	quantized, err := protocol.Quantize([]float64{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("quantize: %v", err)
+	}
+	for name, stale := range map[string]Update{
+		"stale dense":     {NodeID: "stale", Weights: []float64{1, 2, 3, 4}, SampleCount: 10, SpecVersion: 1},
+		"stale quantized": {NodeID: "stale", Quantized: quantized, SampleCount: 10, SpecVersion: 1},
+		"wrong dimension": {NodeID: "stale", Sparse: &protocol.SparseUpdate{Dim: 3, Indices: []uint32{0}, Values: []float64{1}}, SampleCount: 10, SpecVersion: 2},
+	} {
+		_, err := agg.Aggregate(context.Background(), 1, []Update{current, stale})
+		if !errors.Is(err, protocol.ErrModelSpecMismatch) || !errors.Is(err, ErrShapeMismatch) {
+			t.Fatalf("%s: expected ErrModelSpecMismatch and ErrShapeMismatch, got %v", name, err)
+		}
+	}
+	if _, err := agg.Aggregate(context.Background(), 1, []Update{current}); err != nil {
+		t.Fatalf("aggregate current spec: %v", err)
+	}
+}
+
+func TestSpecDecodersRejectAnotherDimension(t *testing.T) {
+	spec := testSpec(1, 4)
+	sparse := (&protocol.SparseUpdate{Dim: 5, Indices: []uint32{4}, Values: []float64{1}}).Bytes()
+	if _, err := spec.DecodeSparse(sparse); !errors.Is(err, protocol.ErrModelSpecMismatch) {
+		t.Fatalf("sparse: expected ErrModelSpecMismatch, got %v", err)
+	}
+	quantized, _ := protocol.Quantize([]float64{1, 2, 3})
+	if _, err := spec.DecodeQuantized(quantized.Bytes()); !errors.Is(err, protocol.ErrModelSpecMismatch) {
+		t.Fatalf("quantized: expected ErrModelSpecMismatch, got %v", err)
+	}
+
+	quantized, _ = protocol.Quantize([]float64{1, 2, 3, 4})
+	decoded, err := spec.DecodeQuantized(quantized.Bytes())
+	if err != nil || decoded.Len() != 4 {
+		t.Fatalf("DecodeQuantized = %v, %v", decoded, err)
+	}
+	if _, err := spec.DecodeSparse((&protocol.SparseUpdate{Dim: 4, Indices: []uint32{3}, Values: []float64{1}}).Bytes()); err != nil {
+		t.Fatalf("DecodeSparse: %v", err)
+	}
+}
+
+func TestAggregateExcludingRecordsConsensusFlags(t *testing.T) {
+	agg := NewAggregator(&Config{OutlierFactor: -1})
+	updates := []Update{
+		{NodeID: "node-a", Weights: []float64{1, 1}, SampleCount: 100},
+		{NodeID: "node-b", Weights: []float64{3, 3}, SampleCount: 100},
+		{NodeID: "node-c", Weights: []float64{40, -40}, SampleCount: 100},
+	}
+	receipt := func(i int) string {
+		return ReceiptID(4, newContributionEntry(updates[i]))
+	}
+	exclusions := Exclusions{
+		Excluded:     map[string]reasons.Code{receipt(2): protocol.ReasonDetected},
+		Downweighted: map[string]float64{receipt(1): 0.5},
+	}
+	result, err := agg.AggregateExcluding(context.Background(), 4, updates, exclusions)
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if entry, _ := result.Manifest.Entry("node-c"); entry.Included || entry.Reason != protocol.ReasonDetected {
+		t.Fatalf("flagged update entry = %+v", entry)
+	}
+	if entry, _ := result.Manifest.Entry("node-b"); math.Abs(entry.AppliedWeight-1.0/3) > 1e-9 || entry.ConsensusWeight != 0.5 {
+		t.Fatalf("down-weighted entry = %+v", entry)
+	}
+	if want := 1.0*2/3 + 3.0/3; math.Abs(result.Weights[0]-want) > 1e-9 {
+		t.Fatalf("aggregate = %v, want %g", result.Weights, want)
+	}
+
+	for name, invalid := range map[string]Exclusions{
+		"unknown reason":  {Excluded: map[string]reasons.Code{receipt(2): "not_a_reason"}},
+		"included reason": {Excluded: map[string]reasons.Code{receipt(2): protocol.ReasonIncluded}},
+		"zero factor":     {Downweighted: map[string]float64{receipt(1): 0}},
+		"both":            {Excluded: exclusions.Excluded, Downweighted: map[string]float64{receipt(2): 0.5}},
+	} {
+		if _, err := agg.AggregateExcluding(context.Background(), 4, updates, invalid); !errors.Is(err, ErrInvalidExclusions) {
+			t.Fatalf("%s: expected ErrInvalidExclusions, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+// AutoTuner defaults.
+const (
+	// DefaultTunerPercentile is the share of rounds, in percent, expected
+	// to reach the tuned trigger.
+	DefaultTunerPercentile = 85
+	// DefaultTunerWindow is how many trailing rounds the tuner's
+	// participation statistics cover.
+	DefaultTunerWindow = 20
+	// DefaultTunerWarmup is how many rounds the tuner observes before it
+	// sets a trigger.
+	DefaultTunerWarmup = 3
+	// DefaultTunerHysteresis is how far, in participants, the target must
+	// move from the current trigger before the trigger follows it.
+	DefaultTunerHysteresis = 2
+	// tunerDecisionHistory bounds the decisions Status reports.
+	tunerDecisionHistory = 32
+)
+
+// Tuning decision reasons.
+const (
+	TuneWarmingUp = "warming_up"
+	TuneSet       = "set"
+	TuneRaised    = "raised"
+	TuneLowered   = "lowered"
+	TuneHeld      = "held"
+	TuneDisabled  = "disabled"
+)
+
+// AutoTunerConfig bounds an AutoTuner. Zero fields take the defaults noted
+// on them.
+type AutoTunerConfig struct {
+	// Min and Max bound the trigger; 1 <= Min <= Max.
+	Min int
+	Max int
+	// Percentile is the share of rounds, in percent, the trigger is set
+	// for: a round is expected to reach it with this probability under a
+	// normal fit of recent participation. Default DefaultTunerPercentile.
+	Percentile float64
+	// Window is how many trailing rounds the statistics cover. Default
+	// DefaultTunerWindow.
+	Window int
+	// Warmup is how many rounds are observed before a trigger is set.
+	// Default DefaultTunerWarmup.
+	Warmup int
+	// Hysteresis keeps the trigger where it is until the target moves this
+	// many participants away, so rounds scattered around a steady mean do
+	// not make it oscillate. Default DefaultTunerHysteresis; negative
+	// follows every move.
+	Hysteresis int
+	// Collector, when set, receives every decision as a batch_trigger
+	// observation labelled with its rationale.
+	Collector *monitoring.Collector
+}
+
+// Validate checks the bounds and percentile.
+func (c AutoTunerConfig) Validate() error {
+	if c.Min < 1 || c.Max < c.Min {
+		return fmt.Errorf("%w: trigger bounds [%d, %d]", ErrInvalidTunerConfig, c.Min, c.Max)
+	}
+	if c.Percentile < 0 || c.Percentile >= 100 {
+		return fmt.Errorf("%w: percentile %g outside [0, 100)", ErrInvalidTunerConfig, c.Percentile)
+	}
+	if c.Window < 0 || c.Warmup < 0 {
+		return fmt.Errorf("%w: window %d and warmup %d must not be negative", ErrInvalidTunerConfig, c.Window, c.Warmup)
+	}
+	return nil
+}
+
+// TuningDecision records what the tuner made of one round.
+type TuningDecision struct {
+	Round int `json:"round"`
+	// Observed is the round's on-time submissions.
+	Observed int `json:"observed"`
+	// Mean and StdDev describe participation over the window, Rounds long.
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	Rounds int     `json:"rounds"`
+	// Target is the participation the percentile falls at, before bounds
+	// and hysteresis.
+	Target   float64 `json:"target"`
+	Previous int     `json:"previous"`
+	Trigger  int     `json:"trigger"`
+	// Reason is one of the Tune constants; Rationale explains it.
+	Reason    string    `json:"reason"`
+	Rationale string    `json:"rationale"`
+	At        time.Time `json:"at"`
+}
+
+// AutoTunerStatus is a snapshot of an AutoTuner.
+type AutoTunerStatus struct {
+	Enabled    bool    `json:"enabled"`
+	Trigger    int     `json:"trigger"`
+	Min        int     `json:"min"`
+	Max        int     `json:"max"`
+	Percentile float64 `json:"percentile"`
+	Window     int     `json:"window"`
+	Mean       float64 `json:"mean"`
+	StdDev     float64 `json:"std_dev"`
+	Rounds     int     `json:"rounds"`
+	// Decisions holds the most recent decisions, oldest first.
+	Decisions []TuningDecision `json:"decisions"`
+}
+
+// AutoTuner sets the batch trigger, the number of updates a round waits
+// for before it aggregates, from the trailing mean and variance of on-time
+// submissions. It is disabled until SetEnabled; while disabled it keeps
+// observing, so enabling it tunes from the rounds already seen, but
+// Trigger returns the configured trigger.
+type AutoTuner struct {
+	cfg AutoTunerConfig
+	z   float64
+
+	mu        sync.Mutex
+	enabled   bool
+	trigger   int
+	observed  []int
+	next      int
+	decisions []TuningDecision
+}
+
+// NewAutoTuner creates a disabled tuner bounded by cfg.
+func NewAutoTuner(cfg AutoTunerConfig) (*AutoTuner, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Percentile == 0 {
+		cfg.Percentile = DefaultTunerPercentile
+	}
+	if cfg.Window == 0 {
+		cfg.Window = DefaultTunerWindow
+	}
+	if cfg.Warmup == 0 {
+		cfg.Warmup = DefaultTunerWarmup
+	}
+	if cfg.Warmup > cfg.Window {
+		cfg.Warmup = cfg.Window
+	}
+	if cfg.Hysteresis == 0 {
+		cfg.Hysteresis = DefaultTunerHysteresis
+	}
+	// A round reaches mean + z*sd with probability Percentile, so z is the
+	// standard normal quantile of 1 - Percentile.
+	z := math.Sqrt2 * math.Erfinv(1-2*cfg.Percentile/100)
+	return &AutoTuner{cfg: cfg, z: z, observed: make([]int, 0, cfg.Window)}, nil
+}
+
+// SetEnabled turns tuning on or off. Turning it off drops the tuned
+// trigger at once; turning it on tunes from the next observed round.
+func (t *AutoTuner) SetEnabled(enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.enabled != enabled {
+		t.enabled = enabled
+		t.trigger = 0
+	}
+}
+
+// Enabled reports whether tuning is on.
+func (t *AutoTuner) Enabled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.enabled
+}
+
+// Trigger returns the tuned trigger, or configured while tuning is off or
+// still warming up.
+func (t *AutoTuner) Trigger(configured int) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.enabled || t.trigger == 0 {
+		return configured
+	}
+	return t.trigger
+}
+
+// Observe records onTime submissions for round and, while tuning is on,
+// decides the trigger for the next round and reports the decision.
+func (t *AutoTuner) Observe(round, onTime int) TuningDecision {
+	t.mu.Lock()
+	if len(t.observed) < t.cfg.Window {
+		t.observed = append(t.observed, onTime)
+	} else {
+		t.observed[t.next] = onTime
+	}
+	t.next = (t.next + 1) % t.cfg.Window
+	mean, sd := t.statsLocked()
+	decision := TuningDecision{
+		Round:    round,
+		Observed: onTime,
+		Mean:     mean,
+		StdDev:   sd,
+		Rounds:   len(t.observed),
+		Previous: t.trigger,
+		At:       time.Now().UTC(),
+	}
+	if !t.enabled {
+		t.mu.Unlock()
+		decision.Reason = TuneDisabled
+		decision.Rationale = "tuning is off"
+		return decision
+	}
+	t.decideLocked(&decision)
+	t.decisions = append(t.decisions, decision)
+	if len(t.decisions) > tunerDecisionHistory {
+		t.decisions = t.decisions[len(t.decisions)-tunerDecisionHistory:]
+	}
+	t.mu.Unlock()
+
+	if t.cfg.Collector != nil {
+		t.cfg.Collector.RecordBatchTrigger(float64(decision.Trigger), map[string]string{
+			"round":     strconv.Itoa(decision.Round),
+			"reason":    decision.Reason,
+			"observed":  strconv.Itoa(decision.Observed),
+			"rationale": decision.Rationale,
+		})
+	}
+	return decision
+}
+
+// decideLocked sets decision's target, trigger, and reason, and moves the
+// tuner's trigger to it. The caller holds t.mu.
+func (t *AutoTuner) decideLocked(decision *TuningDecision) {
+	if decision.Rounds < t.cfg.Warmup {
+		decision.Reason = TuneWarmingUp
+		decision.Rationale = fmt.Sprintf("%d of %d warm-up rounds observed", decision.Rounds, t.cfg.Warmup)
+		return
+	}
+	decision.Target = decision.Mean + t.z*decision.StdDev
+	target := int(math.Floor(decision.Target))
+	target = min(max(target, t.cfg.Min), t.cfg.Max)
+	stats := fmt.Sprintf("participation mean %.1f, stddev %.1f over %d rounds puts the %g%% level at %.1f", decision.Mean, decision.StdDev, decision.Rounds, t.cfg.Percentile, decision.Target)
+
+	switch previous := t.trigger; {
+	case previous == 0:
+		decision.Reason = TuneSet
+	case abs(target-previous) <= t.cfg.Hysteresis && target != t.cfg.Min && target != t.cfg.Max:
+		decision.Reason = TuneHeld
+		decision.Trigger = previous
+		decision.Rationale = fmt.Sprintf("%s; %d is within %d of trigger %d", stats, target, t.cfg.Hysteresis, previous)
+		return
+	case target > previous:
+		decision.Reason = TuneRaised
+	case target < previous:
+		decision.Reason = TuneLowered
+	default:
+		decision.Reason = TuneHeld
+	}
+	decision.Trigger = target
+	t.trigger = target
+	decision.Rationale = fmt.Sprintf("%s; trigger %d within [%d, %d]", stats, target, t.cfg.Min, t.cfg.Max)
+}
+
+// statsLocked returns the mean and sample standard deviation of the
+// observed window. The caller holds t.mu.
+func (t *AutoTuner) statsLocked() (float64, float64) {
+	n := len(t.observed)
+	if n == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, v := range t.observed {
+		sum += float64(v)
+	}
+	mean := sum / float64(n)
+	if n < 2 {
+		return mean, 0
+	}
+	squares := 0.0
+	for _, v := range t.observed {
+		d := float64(v) - mean
+		squares += d * d
+	}
+	return mean, math.Sqrt(squares / float64(n-1))
+}
+
+// Status returns a snapshot of the tuner.
+func (t *AutoTuner) Status() AutoTunerStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	mean, sd := t.statsLocked()
+	return AutoTunerStatus{
+		Enabled:    t.enabled,
+		Trigger:    t.trigger,
+		Min:        t.cfg.Min,
+		Max:        t.cfg.Max,
+		Percentile: t.cfg.Percentile,
+		Window:     t.cfg.Window,
+		Mean:       mean,
+		StdDev:     sd,
+		Rounds:     len(t.observed),
+		Decisions:  append([]TuningDecision(nil), t.decisions...),
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
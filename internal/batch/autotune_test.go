@@ -0,0 +1,203 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/chaos"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+// tuneTrace feeds participation to tuner round by round and returns the
+// trigger in force for each round, before its participation was observed.
+func tuneTrace(tuner *AutoTuner, trace []int, configured int) []int {
+	triggers := make([]int, len(trace))
+	for i, onTime := range trace {
+		triggers[i] = tuner.Trigger(configured)
+		tuner.Observe(i+1, onTime)
+	}
+	return triggers
+}
+
+// changes counts trigger moves in triggers and how often a move reversed
+// the direction of the one before it.
+func changes(triggers []int) (moves, reversals int) {
+	direction := 0
+	for i := 1; i < len(triggers); i++ {
+		delta := triggers[i] - triggers[i-1]
+		if delta == 0 {
+			continue
+		}
+		moves++
+		if sign := delta / abs(delta); direction != 0 && sign != direction {
+			reversals++
+		}
+		direction = delta / abs(delta)
+	}
+	return moves, reversals
+}
+
+func TestAutoTunerIsOffUntilEnabled(t *testing.T) {
+	if _, err := NewAutoTuner(AutoTunerConfig{Min: 5, Max: 4}); !errors.Is(err, ErrInvalidTunerConfig) {
+		t.Fatalf("inverted bounds: expected ErrInvalidTunerConfig, got %v", err)
+	}
+	collector := monitoring.NewCollector(64)
+	tuner, err := NewAutoTuner(AutoTunerConfig{Min: 1, Max: 100, Collector: collector})
+	if err != nil {
+		t.Fatalf("new tuner: %v", err)
+	}
+	for round := 1; round <= 5; round++ {
+		if decision := tuner.Observe(round, 40); decision.Reason != TuneDisabled {
+			t.Fatalf("round %d decided %s while off", round, decision.Reason)
+		}
+	}
+	if got := tuner.Trigger(64); got != 64 {
+		t.Fatalf("trigger while off = %d, want the configured 64", got)
+	}
+	if len(collector.GetMetricsByType(monitoring.MetricBatchTrigger)) != 0 {
+		t.Fatal("a disabled tuner reported decisions")
+	}
+
+	// Enabled, it tunes from the rounds it saw while off.
+	tuner.SetEnabled(true)
+	decision := tuner.Observe(6, 40)
+	if decision.Reason != TuneSet || decision.Trigger != 40 || tuner.Trigger(64) != 40 {
+		t.Fatalf("first decision = %+v", decision)
+	}
+	reported := collector.GetMetricsByType(monitoring.MetricBatchTrigger)
+	if len(reported) != 1 || reported[0].Value != 40 || reported[0].Labels["rationale"] == "" {
+		t.Fatalf("reported decisions = %+v", reported)
+	}
+
+	tuner.SetEnabled(false)
+	if got := tuner.Trigger(64); got != 64 {
+		t.Fatalf("trigger after disabling = %d, want 64", got)
+	}
+	if status := tuner.Status(); status.Enabled || status.Rounds != 6 || len(status.Decisions) != 1 {
+		t.Fatalf("status = %+v", status)
+	}
+}
+
+func TestAutoTunerTracksFluctuatingParticipation(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 11))
+	trace := make([]int, 0, 120)
+	for i := 0; i < 60; i++ {
+		trace = append(trace, int(math.Round(60+8*rng.NormFloat64())))
+	}
+	// Half the federation drops out at once.
+	for i := 0; i < 60; i++ {
+		trace = append(trace, int(math.Round(30+4*rng.NormFloat64())))
+	}
+	tuner, err := NewAutoTuner(AutoTunerConfig{Min: 5, Max: 100})
+	if err != nil {
+		t.Fatalf("new tuner: %v", err)
+	}
+	tuner.SetEnabled(true)
+	triggers := tuneTrace(tuner, trace, 100)
+
+	// Each phase settles near its 85% level, mean - 1.04 sd, and rounds
+	// reach it about that often.
+	for _, phase := range []struct {
+		from, to int
+		want     float64
+	}{{30, 60, 60 - 1.04*8}, {90, 120, 30 - 1.04*4}} {
+		reached := 0
+		for i := phase.from; i < phase.to; i++ {
+			if math.Abs(float64(triggers[i])-phase.want) > 5 {
+				t.Fatalf("round %d trigger %d, want about %.1f", i+1, triggers[i], phase.want)
+			}
+			if trace[i] >= triggers[i] {
+				reached++
+			}
+		}
+		if share := float64(reached) / float64(phase.to-phase.from); share < 0.7 {
+			t.Fatalf("rounds %d-%d reached the trigger %.0f%% of the time", phase.from+1, phase.to, 100*share)
+		}
+		if moves, reversals := changes(triggers[phase.from:phase.to]); moves > 4 || reversals > 2 {
+			t.Fatalf("rounds %d-%d: trigger moved %d times, reversing %d: %v", phase.from+1, phase.to, moves, reversals, triggers[phase.from:phase.to])
+		}
+	}
+	// The drop is followed within one window.
+	if got := triggers[60+DefaultTunerWindow]; got > 35 {
+		t.Fatalf("trigger %d a window after participation halved", got)
+	}
+}
+
+// TestAutoTunerFollowsChurnScenario tunes over the participation of the
+// byzantine-55 chaos plan, crashes, a partition, and churn among 100
+// nodes, each of which also misses a round one time in ten. A third of the
+// nodes leave at round 50 and rejoin at round 80.
+func TestAutoTunerFollowsChurnScenario(t *testing.T) {
+	plan, err := chaos.LoadPlan("../../testnet/simulator/plans/byzantine-55-chaos.json")
+	if err != nil {
+		t.Fatalf("load chaos plan: %v", err)
+	}
+	const nodes, rounds = 100, 110
+	var leaving []string
+	for i := 60; i < 93; i++ {
+		leaving = append(leaving, fmt.Sprintf("node-%03d", i))
+	}
+	plan.Churn = append(plan.Churn, chaos.ChurnEvent{Round: 50, Leave: leaving}, chaos.ChurnEvent{Round: 80, Join: leaving})
+	inj := chaos.NewInjector(plan, plan.Seed)
+	rng := rand.New(rand.NewPCG(uint64(plan.Seed), 1))
+	trace := make([]int, rounds)
+	for round := 1; round <= rounds; round++ {
+		inj.AdvanceRound(round)
+		for i := 0; i < nodes; i++ {
+			peer := fmt.Sprintf("node-%03d", i)
+			if !inj.PeerDown(peer) && !inj.MessageFault(peer, "aggregator").Drop && rng.Float64() >= 0.1 {
+				trace[round-1]++
+			}
+		}
+	}
+
+	tuner, err := NewAutoTuner(AutoTunerConfig{Min: 10, Max: nodes})
+	if err != nil {
+		t.Fatalf("new tuner: %v", err)
+	}
+	tuner.SetEnabled(true)
+	triggers := tuneTrace(tuner, trace, nodes)
+
+	mean := func(from, to int) float64 {
+		sum := 0
+		for _, onTime := range trace[from-1 : to] {
+			sum += onTime
+		}
+		return float64(sum) / float64(to-from+1)
+	}
+	// In each steady stretch the trigger sits a little below participation
+	// and barely moves; rounds reach it most of the time.
+	for _, stretch := range []struct{ from, to int }{{20, 49}, {65, 79}, {100, 110}} {
+		level := mean(stretch.from, stretch.to)
+		reached := 0
+		for round := stretch.from; round <= stretch.to; round++ {
+			trigger := triggers[round-1]
+			if float64(trigger) > level || float64(trigger) < level-15 {
+				t.Fatalf("round %d trigger %d, participation averages %.1f", round, trigger, level)
+			}
+			if trace[round-1] >= trigger {
+				reached++
+			}
+		}
+		if share := float64(reached) / float64(stretch.to-stretch.from+1); share < 0.7 {
+			t.Fatalf("rounds %d-%d reached the trigger %.0f%% of the time", stretch.from, stretch.to, 100*share)
+		}
+		if moves, reversals := changes(triggers[stretch.from-1 : stretch.to]); moves > 3 || reversals > 2 {
+			t.Fatalf("rounds %d-%d: trigger moved %d times, reversing %d: %v", stretch.from, stretch.to, moves, reversals, triggers[stretch.from-1:stretch.to])
+		}
+	}
+	// Both steps are followed within a window: down after the leave, back up
+	// after the rejoin.
+	if after := triggers[50+DefaultTunerWindow-1]; float64(after) > mean(50, 79) {
+		t.Fatalf("trigger %d a window after a third of the nodes left", after)
+	}
+	if after := triggers[80+DefaultTunerWindow-1]; float64(after) < mean(90, 110)-10 {
+		t.Fatalf("trigger %d a window after they rejoined", after)
+	}
+}
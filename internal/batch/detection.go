@@ -0,0 +1,136 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import "math"
+
+// DefaultDetectionThreshold is the combined suspicion score at or above
+// which an update is excluded.
+const DefaultDetectionThreshold = 0.5
+
+// DetectionVerdict is a plugin's judgement of one update.
+type DetectionVerdict struct {
+	// Score is the suspicion in [0, 1].
+	Score float64
+	// Byzantine marks the update malicious; it counts as a score of 1.
+	Byzantine bool
+}
+
+// DetectionPlugin is a deployment-supplied Byzantine detection heuristic,
+// such as a Wasm module loaded by package wasmhost.
+type DetectionPlugin interface {
+	// ID identifies the plugin in weights and failure reports.
+	ID() string
+	// Analyze judges one update, given as little-endian float64 weights.
+	Analyze(update []byte) (DetectionVerdict, error)
+}
+
+// DetectionConfig combines plugin scores with the built-in norm detector.
+// An update's combined score is the weighted mean of the built-in score
+// (its norm over the outlier threshold, capped at 1) and the score of every
+// plugin still running in the round.
+type DetectionConfig struct {
+	Plugins []DetectionPlugin
+	// PluginWeights maps plugin IDs to weights. Plugins not listed weigh 1.
+	PluginWeights map[string]float64
+	// BuiltinWeight weighs the norm detector. Zero uses 1; negative leaves
+	// the decision to the plugins.
+	BuiltinWeight float64
+	// Threshold is the combined score at or above which an update is
+	// excluded. Zero uses DefaultDetectionThreshold.
+	Threshold float64
+}
+
+// PluginFailure reports a plugin disabled for a round because it failed on
+// one of its updates.
+type PluginFailure struct {
+	Plugin string `json:"plugin"`
+	NodeID string `json:"node_id"`
+	Error  string `json:"error"`
+}
+
+// SetDetection runs cfg's plugins over every update Aggregate sees. nil
+// leaves the norm detector alone.
+func (a *Aggregator) SetDetection(cfg *DetectionConfig) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.detection = cfg
+}
+
+// detect scores every update. A plugin that fails on any update is dropped
+// for the whole round and reported, so one broken plugin never fails
+// aggregation. scores is nil when no plugins are configured.
+func (a *Aggregator) detect(updates []Update, weights [][]float64, norms []float64, normThreshold float64) (scores []float64, threshold float64, failures []PluginFailure) {
+	a.mu.RLock()
+	cfg := a.detection
+	a.mu.RUnlock()
+	if cfg == nil || len(cfg.Plugins) == 0 {
+		return nil, 0, nil
+	}
+	threshold = cfg.Threshold
+	if threshold == 0 {
+		threshold = DefaultDetectionThreshold
+	}
+	builtinWeight := cfg.BuiltinWeight
+	if builtinWeight == 0 {
+		builtinWeight = 1
+	}
+
+	totals := make([]float64, len(updates))
+	weightSum := 0.0
+	if builtinWeight > 0 {
+		for i := range updates {
+			builtin := 0.0
+			if !math.IsInf(normThreshold, 1) && normThreshold > 0 {
+				builtin = math.Min(norms[i]/normThreshold, 1)
+			}
+			totals[i] = builtinWeight * builtin
+		}
+		weightSum = builtinWeight
+	}
+
+	encoded := make([][]byte, len(weights))
+	for i, w := range weights {
+		encoded[i] = encodeWeights(w)
+	}
+	for _, plugin := range cfg.Plugins {
+		pluginScores, failure := runPlugin(plugin, updates, encoded)
+		if failure != nil {
+			failures = append(failures, *failure)
+			continue
+		}
+		weight := 1.0
+		if w, ok := cfg.PluginWeights[plugin.ID()]; ok {
+			weight = w
+		}
+		for i, score := range pluginScores {
+			totals[i] += weight * score
+		}
+		weightSum += weight
+	}
+
+	scores = make([]float64, len(updates))
+	if weightSum > 0 {
+		for i, total := range totals {
+			scores[i] = total / weightSum
+		}
+	}
+	return scores, threshold, failures
+}
+
+// runPlugin scores every update with plugin, stopping at its first failure.
+func runPlugin(plugin DetectionPlugin, updates []Update, encoded [][]byte) ([]float64, *PluginFailure) {
+	scores := make([]float64, len(updates))
+	for i, update := range updates {
+		verdict, err := plugin.Analyze(encoded[i])
+		if err != nil {
+			return nil, &PluginFailure{Plugin: plugin.ID(), NodeID: update.NodeID, Error: err.Error()}
+		}
+		scores[i] = math.Min(math.Max(verdict.Score, 0), 1)
+		if verdict.Byzantine {
+			scores[i] = 1
+		}
+	}
+	return scores, nil
+}
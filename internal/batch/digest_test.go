@@ -0,0 +1,120 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// jsonWeights writes m as JSON by hand, with weights spelled by format and
+// the fields in the given order, the way another node's encoder might.
+func jsonWeights(m protocol.ModelWeights, format byte, versionFirst bool) []byte {
+	spelled := make([]string, len(m.Weights))
+	for i, w := range m.Weights {
+		spelled[i] = strconv.FormatFloat(w, format, -1, 64)
+	}
+	weights := `"weights": [` + strings.Join(spelled, ", ") + `]`
+	version := fmt.Sprintf(`"spec_version": %d`, m.SpecVersion)
+	if versionFirst {
+		return []byte("{" + version + ", " + weights + "}")
+	}
+	return []byte("{" + weights + ", " + version + "}")
+}
+
+// TestWeightsDigestAgreesAcrossEncodings serializes one logical model along
+// every path weights take between nodes and checks that each decodes to
+// the same canonical digest, so nodes holding the same aggregate never
+// disagree in consensus over how they encoded it.
+func TestWeightsDigestAgreesAcrossEncodings(t *testing.T) {
+	const specVersion = 3
+	rng := rand.New(rand.NewSource(11))
+	raw := make([]float64, 257)
+	for i := range raw {
+		raw[i] = rng.NormFloat64() / 7
+	}
+	raw[5] = 0
+	// The logical model is the quantized one, so every path can carry it
+	// exactly.
+	quantized, err := protocol.Quantize(raw)
+	if err != nil {
+		t.Fatalf("quantize: %v", err)
+	}
+	model := protocol.ModelWeights{SpecVersion: specVersion, Weights: quantized.Dequantize()}
+	want := model.Digest()
+
+	digests := map[string]string{}
+	// JSON: the standard encoder, plus hand-written variants that order
+	// fields differently and spell floats in exponent form.
+	standard, err := json.Marshal(model)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	signedZero := jsonWeights(model, 'e', true)
+	signedZero = []byte(strings.Replace(string(signedZero), "0e+00", "-0.0", 1))
+	if !strings.Contains(string(signedZero), "-0.0") {
+		t.Fatal("model should hold a zero weight to spell as negative zero")
+	}
+	for name, encoded := range map[string][]byte{
+		"json":               standard,
+		"json/exponent":      jsonWeights(model, 'e', true),
+		"json/reordered":     jsonWeights(model, 'g', false),
+		"json/negative-zero": signedZero,
+	} {
+		var decoded protocol.ModelWeights
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		digests[name] = decoded.Digest()
+	}
+
+	// Binary: the canonical encoding, and the bare float64 array aggregates
+	// travel as, which the registered spec's version qualifies.
+	canonical, err := protocol.DecodeModelWeights(model.Canonical())
+	if err != nil {
+		t.Fatalf("decode canonical: %v", err)
+	}
+	digests["binary/canonical"] = canonical.Digest()
+	digests["binary/canonical-bytes"] = protocol.WeightsDigest(model.Canonical())
+	bare, err := protocol.ParseModelWeights(Update{Weights: model.Weights}.Bytes())
+	if err != nil {
+		t.Fatalf("parse bare weights: %v", err)
+	}
+	bare.SpecVersion = specVersion
+	digests["binary/bare"] = bare.Digest()
+
+	// Quantized: the int8 payload as uploaded.
+	decoded, err := protocol.DecodeQuantizedUpdate(Update{Quantized: quantized}.Bytes())
+	if err != nil {
+		t.Fatalf("decode quantized: %v", err)
+	}
+	digests["quantized"] = protocol.ModelWeights{SpecVersion: specVersion, Weights: decoded.Dequantize()}.Digest()
+
+	for path, digest := range digests {
+		if digest != want {
+			t.Errorf("%s digest %s, want %s", path, digest, want)
+		}
+	}
+
+	// The header binds the dimension and spec version, and NaNs collapse to
+	// one encoding.
+	if (protocol.ModelWeights{SpecVersion: specVersion + 1, Weights: model.Weights}).Digest() == want {
+		t.Fatal("spec version should change the digest")
+	}
+	if (protocol.ModelWeights{SpecVersion: specVersion, Weights: append(model.Weights, 0)}).Digest() == want {
+		t.Fatal("dimension should change the digest")
+	}
+	nan := protocol.ModelWeights{Weights: []float64{math.NaN()}}
+	other := protocol.ModelWeights{Weights: []float64{math.Float64frombits(0x7ff8dead00000000)}}
+	if nan.Digest() != other.Digest() {
+		t.Fatal("NaN payloads should share one digest")
+	}
+	if bare, legacy := protocol.WeightsDigest(Update{Weights: model.Weights}.Bytes()), protocol.UpdateDigest(Update{Weights: model.Weights}.Bytes()); bare == legacy {
+		t.Fatal("bare weights should digest through the canonical encoding")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"errors"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+)
+
+// Sentinel errors returned (wrapped) by batch aggregation. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrLivenessUnmet means the configured honest redundancy cannot reach
+	// the 99.99% liveness bound (Theorem 4). Retryable once more honest
+	// nodes join the round.
+	ErrLivenessUnmet = errors.New("liveness check failed")
+	// ErrSafetyViolation means n <= 2f (Theorem 1). Not retryable without
+	// reconfiguring the federation.
+	ErrSafetyViolation = errors.New("byzantine safety violation")
+	// ErrShapeMismatch means an update's dimensions do not match the round's
+	// model. Not retryable with the same update.
+	ErrShapeMismatch = errors.New("update shape mismatch")
+	// ErrDuplicateUpdate means a node submitted more than one update for a
+	// round. Not retryable; the first update stands.
+	ErrDuplicateUpdate = errors.New("duplicate update")
+	// ErrClipNormExceeded means an update can represent values beyond the
+	// configured clip norm. Not retryable with the same update.
+	ErrClipNormExceeded = errors.New("update exceeds clip norm")
+	// ErrTooFewUpdates means a round has too few updates for the configured
+	// Byzantine bound. Retryable once more updates arrive.
+	ErrTooFewUpdates = errors.New("too few updates")
+	// ErrInvalidTunerConfig means an AutoTunerConfig's trigger bounds,
+	// percentile, or window are out of range. Not retryable without
+	// changing the config.
+	ErrInvalidTunerConfig = errors.New("invalid batch tuner config")
+	// ErrInvalidCheckpointPolicy means a CheckpointPolicy's triggers are
+	// negative. Not retryable without changing the policy.
+	ErrInvalidCheckpointPolicy = errors.New("invalid checkpoint policy")
+	// ErrCheckpointMismatch means an accumulator checkpoint is corrupt, from
+	// another format version, or for another round or model spec. Not
+	// retryable; discard the checkpoint and restart the batch.
+	ErrCheckpointMismatch = errors.New("accumulator checkpoint mismatch")
+	// ErrInvalidExclusions means an exclusion list names an unregistered
+	// reason code or a down-weighting factor outside (0, 1]. Not retryable
+	// with the same list.
+	ErrInvalidExclusions = errors.New("invalid exclusion list")
+	// ErrUnknownBaseModel means central DP has no base model for a round to
+	// measure updates against. Not retryable until the round's base model
+	// is committed or backfilled.
+	ErrUnknownBaseModel = errors.New("unknown base model")
+)
+
+// Retryable reports whether err is a transient batch aggregation failure,
+// including a cohort too small for central DP.
+func Retryable(err error) bool {
+	return errors.Is(err, ErrLivenessUnmet) || errors.Is(err, ErrTooFewUpdates) || errors.Is(err, privacy.ErrCohortTooSmall)
+}
@@ -0,0 +1,28 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+)
+
+// SetEvents publishes updates_closed to pub when a round's updates are
+// taken for aggregation, with how many there were. nil stops publishing.
+func (a *Aggregator) SetEvents(pub events.Publisher) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lifecycle = pub
+}
+
+// publishClosed announces that round's updates were closed for
+// aggregation.
+func (a *Aggregator) publishClosed(round int, updates []Update) {
+	a.mu.RLock()
+	pub := a.lifecycle
+	a.mu.RUnlock()
+	if pub == nil {
+		return
+	}
+	pub.Publish(events.Event{Kind: events.KindUpdatesClosed, Round: round, Source: "batch", Updates: len(updates)})
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// Exclusions names updates, by receipt ID (see ReceiptID), that consensus
+// flagged when it rejected a round's proposal, so the round's retry is
+// aggregated without them.
+type Exclusions struct {
+	// Excluded maps the receipt IDs of updates to leave out to the reason
+	// code each one's manifest entry records.
+	Excluded map[string]reasons.Code `json:"excluded,omitempty"`
+	// Downweighted maps the receipt IDs of updates that are suspicious but
+	// not excluded to the factor, in (0, 1], their weight is scaled by.
+	Downweighted map[string]float64 `json:"downweighted,omitempty"`
+}
+
+// ReceiptID is the receipt ID of the update a round's manifest entry
+// records, the provenance ID the update was given on ingest.
+func ReceiptID(round int, entry protocol.ContributionEntry) string {
+	return provenance.UpdateID(entry.NodeID, round, entry.UpdateDigest)
+}
+
+// Empty reports whether e excludes and down-weights nothing.
+func (e Exclusions) Empty() bool {
+	return len(e.Excluded) == 0 && len(e.Downweighted) == 0
+}
+
+// Validate checks that every exclusion carries a registered reason code,
+// that every factor is in (0, 1], and that no update is both excluded and
+// down-weighted.
+func (e Exclusions) Validate() error {
+	for receipt, reason := range e.Excluded {
+		if _, ok := reasons.Lookup(reason); !ok || reason == protocol.ReasonIncluded {
+			return fmt.Errorf("%w: receipt %s excluded for %q", ErrInvalidExclusions, receipt, reason)
+		}
+		if _, both := e.Downweighted[receipt]; both {
+			return fmt.Errorf("%w: receipt %s is both excluded and down-weighted", ErrInvalidExclusions, receipt)
+		}
+	}
+	for receipt, factor := range e.Downweighted {
+		if !(factor > 0) || factor > 1 || math.IsNaN(factor) {
+			return fmt.Errorf("%w: receipt %s down-weighted by %g, need (0, 1]", ErrInvalidExclusions, receipt, factor)
+		}
+	}
+	return nil
+}
+
+// Merge returns e with other's exclusions added. An update other excludes
+// is excluded even if e only down-weighted it, and an update both
+// down-weight keeps the smaller factor.
+func (e Exclusions) Merge(other Exclusions) Exclusions {
+	merged := Exclusions{
+		Excluded:     make(map[string]reasons.Code, len(e.Excluded)+len(other.Excluded)),
+		Downweighted: make(map[string]float64, len(e.Downweighted)+len(other.Downweighted)),
+	}
+	for _, excluded := range []map[string]reasons.Code{e.Excluded, other.Excluded} {
+		for receipt, reason := range excluded {
+			if _, ok := merged.Excluded[receipt]; !ok {
+				merged.Excluded[receipt] = reason
+			}
+		}
+	}
+	for _, downweighted := range []map[string]float64{e.Downweighted, other.Downweighted} {
+		for receipt, factor := range downweighted {
+			if _, excluded := merged.Excluded[receipt]; excluded {
+				continue
+			}
+			if current, ok := merged.Downweighted[receipt]; !ok || factor < current {
+				merged.Downweighted[receipt] = factor
+			}
+		}
+	}
+	return merged
+}
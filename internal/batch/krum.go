@@ -0,0 +1,344 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+const (
+	// DefaultKrumBlockSize is how many updates share a distance tile. A tile
+	// covers BlockSize x BlockSize pairs.
+	DefaultKrumBlockSize = 16
+	// krumDimChunk is how many coordinates of every update in a tile are
+	// walked together, sized so a tile's slices stay in L2 cache.
+	krumDimChunk = 2048
+)
+
+// KrumConfig configures Multi-Krum selection.
+type KrumConfig struct {
+	// Byzantine is f, the number of updates assumed malicious. Multi-Krum
+	// needs at least 2f+3 updates.
+	Byzantine int
+	// Select is m, how many updates are kept. Zero keeps n-f.
+	Select int
+	// Workers bounds distance and projection parallelism. Zero uses
+	// GOMAXPROCS.
+	Workers int
+	// BlockSize is the tile edge for the distance matrix. Zero uses
+	// DefaultKrumBlockSize.
+	BlockSize int
+	// ProjectionDim, when positive and below the model dimension, projects
+	// updates to that many dimensions before computing distances. See
+	// Project for the approximation this introduces.
+	ProjectionDim int
+	// ProjectionSeed fixes the projection so every aggregator selects the
+	// same updates.
+	ProjectionSeed int64
+	// Collector, when set, receives the phase timings.
+	Collector *monitoring.Collector
+}
+
+// KrumTiming breaks a Multi-Krum run down by phase.
+type KrumTiming struct {
+	Projection time.Duration `json:"projection"`
+	Distances  time.Duration `json:"distances"`
+	Scoring    time.Duration `json:"scoring"`
+}
+
+// KrumResult is the outcome of Multi-Krum selection.
+type KrumResult struct {
+	// Selected holds the indices of the kept updates, best score first.
+	Selected []int
+	// Scores holds each update's Krum score: the sum of squared distances
+	// to its n-f-2 nearest neighbours.
+	Scores []float64
+	Timing KrumTiming
+}
+
+// MultiKrum scores every update by its distance to its nearest neighbours
+// and selects the m lowest-scoring ones.
+func MultiKrum(updates [][]float64, cfg KrumConfig) (*KrumResult, error) {
+	n := len(updates)
+	if cfg.Byzantine < 0 {
+		return nil, fmt.Errorf("byzantine count must not be negative, got %d", cfg.Byzantine)
+	}
+	if n < 2*cfg.Byzantine+3 {
+		return nil, fmt.Errorf("%w: multi-krum with f=%d needs %d updates, got %d", ErrTooFewUpdates, cfg.Byzantine, 2*cfg.Byzantine+3, n)
+	}
+	dims := len(updates[0])
+	for i, u := range updates {
+		if len(u) != dims {
+			return nil, fmt.Errorf("%w: update %d has %d weights, expected %d", ErrShapeMismatch, i, len(u), dims)
+		}
+	}
+	selectCount := cfg.Select
+	if selectCount <= 0 {
+		selectCount = n - cfg.Byzantine
+	}
+	if selectCount > n {
+		selectCount = n
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	result := &KrumResult{}
+	vectors := updates
+	if cfg.ProjectionDim > 0 && cfg.ProjectionDim < dims {
+		start := time.Now()
+		vectors = Project(updates, cfg.ProjectionDim, cfg.ProjectionSeed, workers)
+		result.Timing.Projection = time.Since(start)
+	}
+
+	start := time.Now()
+	distances := PairwiseDistances(vectors, workers, cfg.BlockSize)
+	result.Timing.Distances = time.Since(start)
+
+	start = time.Now()
+	result.Scores = krumScores(distances, n-cfg.Byzantine-2)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return result.Scores[order[a]] < result.Scores[order[b]] })
+	result.Selected = order[:selectCount]
+	result.Timing.Scoring = time.Since(start)
+
+	if cfg.Collector != nil {
+		labels := map[string]string{"updates": strconv.Itoa(n), "dims": strconv.Itoa(len(vectors[0]))}
+		cfg.Collector.RecordKrumPhase("projection", result.Timing.Projection.Seconds(), labels)
+		cfg.Collector.RecordKrumPhase("distances", result.Timing.Distances.Seconds(), labels)
+		cfg.Collector.RecordKrumPhase("scoring", result.Timing.Scoring.Seconds(), labels)
+	}
+	return result, nil
+}
+
+// krumScores sums each row's k smallest off-diagonal distances.
+func krumScores(distances [][]float64, k int) []float64 {
+	scores := make([]float64, len(distances))
+	row := make([]float64, 0, len(distances))
+	for i, d := range distances {
+		row = row[:0]
+		for j, v := range d {
+			if j != i {
+				row = append(row, v)
+			}
+		}
+		sort.Float64s(row)
+		for _, v := range row[:k] {
+			scores[i] += v
+		}
+	}
+	return scores
+}
+
+type distanceTile struct {
+	rowStart, rowEnd int
+	colStart, colEnd int
+}
+
+// PairwiseDistances returns the symmetric matrix of squared L2 distances
+// between vectors, which must share a length. The upper triangle is split
+// into blockSize x blockSize tiles spread over workers; each tile walks the
+// coordinates in chunks so the slices it reuses stay cache resident.
+func PairwiseDistances(vectors [][]float64, workers, blockSize int) [][]float64 {
+	n := len(vectors)
+	if workers <= 0 {
+		workers = 1
+	}
+	if blockSize <= 0 {
+		blockSize = DefaultKrumBlockSize
+	}
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+	}
+
+	var tiles []distanceTile
+	for rs := 0; rs < n; rs += blockSize {
+		for cs := rs; cs < n; cs += blockSize {
+			tiles = append(tiles, distanceTile{rs, min(rs+blockSize, n), cs, min(cs+blockSize, n)})
+		}
+	}
+	if workers > len(tiles) {
+		workers = len(tiles)
+	}
+
+	// Tiles cover disjoint pairs, so workers write disjoint cells.
+	work := make(chan distanceTile)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			acc := make([]float64, blockSize*blockSize)
+			for tile := range work {
+				computeTile(vectors, matrix, tile, acc, blockSize)
+			}
+		}()
+	}
+	for _, tile := range tiles {
+		work <- tile
+	}
+	close(work)
+	wg.Wait()
+	return matrix
+}
+
+func computeTile(vectors, matrix [][]float64, tile distanceTile, acc []float64, blockSize int) {
+	clear(acc)
+	dims := 0
+	if len(vectors) > 0 {
+		dims = len(vectors[0])
+	}
+	for lo := 0; lo < dims; lo += krumDimChunk {
+		hi := min(lo+krumDimChunk, dims)
+		for i := tile.rowStart; i < tile.rowEnd; i++ {
+			a := vectors[i][lo:hi]
+			for j := max(tile.colStart, i+1); j < tile.colEnd; j++ {
+				b := vectors[j][lo:hi]
+				sum := 0.0
+				for k := range a {
+					d := a[k] - b[k]
+					sum += d * d
+				}
+				acc[(i-tile.rowStart)*blockSize+j-tile.colStart] += sum
+			}
+		}
+	}
+	for i := tile.rowStart; i < tile.rowEnd; i++ {
+		for j := max(tile.colStart, i+1); j < tile.colEnd; j++ {
+			d := acc[(i-tile.rowStart)*blockSize+j-tile.colStart]
+			matrix[i][j] = d
+			matrix[j][i] = d
+		}
+	}
+}
+
+// Project maps vectors to dim dimensions with a sparse Johnson–Lindenstrauss
+// transform (a CountSketch): each input coordinate is added, with a random
+// sign, to one randomly chosen output coordinate. The map depends only on
+// seed and the input dimension, so every caller with the same seed projects
+// identically, and it costs one pass over each vector.
+//
+// Squared distances are preserved in expectation. For any pair the variance
+// is at most 2‖x−y‖⁴/dim, so the relative error of a projected squared
+// distance is about sqrt(2/dim): roughly 4% at dim = 1024. Multi-Krum only
+// ranks distances, so selection is unchanged whenever honest and Byzantine
+// scores are separated by more than that error; near-tied honest updates
+// may swap places.
+func Project(vectors [][]float64, dim int, seed int64, workers int) [][]float64 {
+	if len(vectors) == 0 {
+		return nil
+	}
+	dims := len(vectors[0])
+	rng := rand.New(rand.NewSource(seed))
+	buckets := make([]int32, dims)
+	signs := make([]float64, dims)
+	for j := range buckets {
+		buckets[j] = int32(rng.Intn(dim))
+		signs[j] = 1
+		if rng.Intn(2) == 0 {
+			signs[j] = -1
+		}
+	}
+
+	if workers <= 0 {
+		workers = 1
+	}
+	projected := make([][]float64, len(vectors))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(vectors)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				out := make([]float64, dim)
+				for j, v := range vectors[i] {
+					out[buckets[j]] += signs[j] * v
+				}
+				projected[i] = out
+			}
+		}()
+	}
+	for i := range vectors {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return projected
+}
+
+// AggregateMultiKrum runs Multi-Krum over updates and returns the
+// sample-weighted average of the selected ones. Updates Multi-Krum did not
+// select are recorded in the manifest as krum rejections.
+func (a *Aggregator) AggregateMultiKrum(round int, updates []Update, cfg KrumConfig) (*AggregationResult, *KrumResult, error) {
+	a.publishClosed(round, updates)
+	weights, err := a.ingestAll(round, updates)
+	if err != nil {
+		return nil, nil, err
+	}
+	krum, err := MultiKrum(weights, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("round %d: %w", round, err)
+	}
+	selected := make(map[int]bool, len(krum.Selected))
+	for _, i := range krum.Selected {
+		selected[i] = true
+	}
+
+	manifest := &protocol.ContributionManifest{
+		Round:   round,
+		Entries: make([]protocol.ContributionEntry, len(updates)),
+	}
+	totalWeight := 0.0
+	sampleWeights := make([]float64, len(updates))
+	for i, update := range updates {
+		entry := newContributionEntry(update)
+		rejection := a.statementRejection(round, update, entry)
+		switch {
+		case update.SampleCount <= 0:
+			entry.Reason = protocol.ReasonNoSamples
+		case rejection != "":
+			entry.Reason = rejection
+		case !selected[i]:
+			entry.Reason = protocol.ReasonKrumRejected
+		default:
+			entry.Included = true
+			entry.Reason = protocol.ReasonIncluded
+			sampleWeights[i] = a.sampleWeight(update)
+			totalWeight += sampleWeights[i]
+		}
+		manifest.Entries[i] = entry
+	}
+	a.emitManifest(manifest)
+	if totalWeight == 0 {
+		return nil, nil, fmt.Errorf("round %d: every update was excluded", round)
+	}
+
+	aggregated := make([]float64, len(weights[0]))
+	for i := range updates {
+		entry := &manifest.Entries[i]
+		if !entry.Included {
+			continue
+		}
+		entry.AppliedWeight = sampleWeights[i] / totalWeight
+		for j, w := range weights[i] {
+			aggregated[j] += entry.AppliedWeight * w
+		}
+	}
+	return &AggregationResult{Weights: aggregated, Manifest: manifest}, krum, nil
+}
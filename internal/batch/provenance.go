@@ -0,0 +1,42 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// SetProvenance emits an included or excluded event, with the manifest
+// reason, for every update aggregated. nil stops emitting.
+func (a *Aggregator) SetProvenance(sink provenance.Sink) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.provenance = sink
+}
+
+// emitManifest reports each manifest entry's treatment to the provenance
+// sink, if one is set.
+func (a *Aggregator) emitManifest(manifest *protocol.ContributionManifest) {
+	a.mu.RLock()
+	sink := a.provenance
+	a.mu.RUnlock()
+	if sink == nil {
+		return
+	}
+	for _, entry := range manifest.Entries {
+		kind := provenance.KindExcluded
+		if entry.Included {
+			kind = provenance.KindIncluded
+		}
+		sink.Emit(provenance.Event{
+			UpdateID: provenance.UpdateID(entry.NodeID, manifest.Round, entry.UpdateDigest),
+			NodeID:   entry.NodeID,
+			Round:    manifest.Round,
+			Kind:     kind,
+			Source:   "batch",
+			Reason:   entry.Reason,
+		})
+	}
+}
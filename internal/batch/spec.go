@@ -0,0 +1,48 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"fmt"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// ModelSpecSource returns the federation's registered model spec, or false
+// while none is registered.
+type ModelSpecSource func() (protocol.ModelSpec, bool)
+
+// SetModelSpec checks every update against the spec source returns before
+// its weights are ingested: its SpecVersion must be the spec's version and
+// its declared length the spec's dimension. Mismatches fail the round with
+// an error matching both ErrShapeMismatch and protocol.ErrModelSpecMismatch.
+// nil disables the check.
+func (a *Aggregator) SetModelSpec(source ModelSpecSource) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.spec = source
+}
+
+// checkSpec compares an update's spec version and declared length with the
+// registered spec, if there is one.
+func (a *Aggregator) checkSpec(update Update) error {
+	a.mu.RLock()
+	source := a.spec
+	a.mu.RUnlock()
+	if source == nil {
+		return nil
+	}
+	spec, ok := source()
+	if !ok {
+		return nil
+	}
+	err := spec.CheckVersion(update.SpecVersion)
+	if err == nil {
+		err = spec.CheckDimension(declaredDimension(update))
+	}
+	if err != nil {
+		return fmt.Errorf("%w: node %s: %w", ErrShapeMismatch, update.NodeID, err)
+	}
+	return nil
+}
@@ -0,0 +1,445 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package batch
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/profiling"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// DefaultOutlierFactor is the norm multiple over the median beyond which an
+// update is excluded as an outlier.
+const DefaultOutlierFactor = 3.0
+
+// minUpdatesForOutlierFilter is the smallest round in which a median norm is
+// meaningful enough to exclude anyone.
+const minUpdatesForOutlierFilter = 3
+
+// aggregateBlockSize is how many coordinates Aggregate sums between checks
+// of its context.
+const aggregateBlockSize = 1 << 14
+
+// Update is one participant's model update for a round. Exactly one of
+// Weights, Quantized, and Sparse is set; quantized updates are dequantized
+// and sparse updates densified on ingest, then aggregated in float64
+// alongside float updates.
+type Update struct {
+	NodeID      string
+	Weights     []float64
+	Quantized   *protocol.QuantizedUpdate
+	Sparse      *protocol.SparseUpdate
+	SampleCount int
+	// Statement binds the update to the global model it was trained from.
+	// It is required once the aggregator has a base model resolver.
+	Statement *protocol.TrainingStatement
+	// SpecVersion is the model spec version the update was trained
+	// against; see SetModelSpec.
+	SpecVersion int
+}
+
+// AggregationResult is a sample-weighted average plus the manifest recording
+// how each update was treated.
+type AggregationResult struct {
+	Weights  []float64
+	Manifest *protocol.ContributionManifest
+	// PluginFailures lists detection plugins disabled during the round.
+	PluginFailures []PluginFailure
+}
+
+// Aggregate computes a sample-weighted average of updates, excluding updates
+// with no samples, updates whose training statement fails (see
+// SetBaseModelResolver), norm outliers, and updates the detection plugins
+// flag (see SetDetection). Every update appears in the manifest.
+// Full, quantized, and sparse updates are weighted alike, by SampleCount
+// and the weight scale (see SetWeightScale) alone: a node that downgrades
+// its upload to save bandwidth loses precision, not weight. Coordinates a
+// sparse update omits count as zero.
+//
+// With quantized updates the aggregate differs from the average of the
+// original float weights by at most the sum over included updates of
+// AppliedWeight * QuantizationErrorBound.
+//
+// With central DP (see SetCentralDP) each included update's change from the
+// round's base model is clipped, the mean change is noised for the cohort
+// actually included and added back to the base; a round whose cohort is
+// below the minimum, whose base model is unknown, or that the privacy
+// allocation cannot afford, fails and appears in no manifest.
+//
+// The sum is taken in blocks of coordinates, and a round whose ctx ends
+// between blocks fails with ctx.Err(), spends no privacy budget, and
+// appears in no manifest.
+func (a *Aggregator) Aggregate(ctx context.Context, round int, updates []Update) (*AggregationResult, error) {
+	return a.AggregateExcluding(ctx, round, updates, Exclusions{})
+}
+
+// AggregateExcluding is Aggregate for the retry of a round whose proposal
+// consensus rejected: updates exclusions excludes are left out under the
+// reason it gives, which their manifest entries and so their inclusion
+// receipts record, and updates it down-weights count for their factor of
+// their weight.
+func (a *Aggregator) AggregateExcluding(ctx context.Context, round int, updates []Update, exclusions Exclusions) (*AggregationResult, error) {
+	defer profiling.ObserveSince(profiling.PhaseAggregation, round, time.Now())
+	if err := exclusions.Validate(); err != nil {
+		return nil, fmt.Errorf("round %d: %w", round, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("round %d: %w", round, err)
+	}
+	a.publishClosed(round, updates)
+	weights, err := a.ingestAll(round, updates)
+	if err != nil {
+		return nil, err
+	}
+	norms := make([]float64, len(updates))
+	for i, w := range weights {
+		norms[i] = l2Norm(w)
+	}
+
+	threshold := math.Inf(1)
+	factor := DefaultOutlierFactor
+	if a.Config != nil && a.Config.OutlierFactor != 0 {
+		factor = a.Config.OutlierFactor
+	}
+	if factor > 0 && len(updates) >= minUpdatesForOutlierFilter {
+		threshold = factor * median(norms)
+	}
+	scores, detectionThreshold, failures := a.detect(updates, weights, norms, threshold)
+
+	manifest := &protocol.ContributionManifest{
+		Round:   round,
+		Entries: make([]protocol.ContributionEntry, len(updates)),
+	}
+	totalWeight := 0.0
+	sampleWeights := make([]float64, len(updates))
+	for i, update := range updates {
+		entry := newContributionEntry(update)
+		rejection := a.statementRejection(round, update, entry)
+		if scores != nil {
+			entry.DetectionScore = scores[i]
+		}
+		receipt := ReceiptID(round, entry)
+		excluded, isExcluded := exclusions.Excluded[receipt]
+		switch {
+		case isExcluded:
+			entry.Reason = excluded
+		case update.SampleCount <= 0:
+			entry.Reason = protocol.ReasonNoSamples
+		case rejection != "":
+			entry.Reason = rejection
+		case norms[i] > threshold:
+			entry.Reason = protocol.ReasonNormOutlier
+		case scores != nil && scores[i] >= detectionThreshold:
+			entry.Reason = protocol.ReasonDetected
+		default:
+			entry.Included = true
+			entry.Reason = protocol.ReasonIncluded
+			sampleWeights[i] = a.sampleWeight(update)
+			if factor, ok := exclusions.Downweighted[receipt]; ok {
+				sampleWeights[i] *= factor
+				entry.ConsensusWeight = factor
+			}
+			totalWeight += sampleWeights[i]
+		}
+		manifest.Entries[i] = entry
+	}
+	if totalWeight == 0 {
+		a.emitManifest(manifest)
+		return nil, fmt.Errorf("round %d: every update was excluded", round)
+	}
+
+	a.mu.RLock()
+	dp := a.centralDP
+	a.mu.RUnlock()
+	var base []float64
+	if dp != nil {
+		if base, err = a.dpBase(round, len(weights[0])); err != nil {
+			return nil, err
+		}
+	}
+	aggregated := make([]float64, len(weights[0]))
+	var applied []float64
+	included := make([][]float64, 0, len(updates))
+	for i := range updates {
+		entry := &manifest.Entries[i]
+		if !entry.Included {
+			continue
+		}
+		entry.AppliedWeight = sampleWeights[i] / totalWeight
+		applied = append(applied, entry.AppliedWeight)
+		w := weights[i]
+		if dp != nil {
+			w = dp.Clip(subtract(w, base))
+		}
+		included = append(included, w)
+	}
+	for start := 0; start < len(aggregated); start += aggregateBlockSize {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("round %d: %w", round, err)
+		}
+		end := min(start+aggregateBlockSize, len(aggregated))
+		for k, w := range included {
+			for j := start; j < end; j++ {
+				aggregated[j] += applied[k] * w[j]
+			}
+		}
+	}
+	if dp != nil {
+		release, err := dp.Privatize(round, aggregated, applied)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Privacy = &release
+		for j, v := range base {
+			aggregated[j] += v
+		}
+	}
+	a.emitManifest(manifest)
+
+	return &AggregationResult{Weights: aggregated, Manifest: manifest, PluginFailures: failures}, nil
+}
+
+// dpBase returns the weights of round's base model, which central DP
+// measures updates against, or nil for the zero model.
+func (a *Aggregator) dpBase(round, dims int) ([]float64, error) {
+	a.mu.RLock()
+	resolve := a.baseWeights
+	a.mu.RUnlock()
+	if resolve == nil {
+		return nil, fmt.Errorf("round %d: %w: central DP has no base model resolver", round, ErrUnknownBaseModel)
+	}
+	base, ok := resolve(round)
+	if !ok {
+		return nil, fmt.Errorf("round %d: %w", round, ErrUnknownBaseModel)
+	}
+	if len(base) == 0 {
+		return nil, nil
+	}
+	if len(base) != dims {
+		return nil, fmt.Errorf("%w: round %d base model has %d weights, updates have %d", ErrShapeMismatch, round, len(base), dims)
+	}
+	return base, nil
+}
+
+// subtract returns weights minus base, or weights itself when base is the
+// zero model.
+func subtract(weights, base []float64) []float64 {
+	if base == nil {
+		return weights
+	}
+	delta := make([]float64, len(weights))
+	for i, w := range weights {
+		delta[i] = w - base[i]
+	}
+	return delta
+}
+
+// ingestAll ingests every update of a round, rejecting duplicate nodes and
+// updates whose dimensions differ from the first.
+func (a *Aggregator) ingestAll(round int, updates []Update) ([][]float64, error) {
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("round %d: no updates to aggregate", round)
+	}
+	weights := make([][]float64, len(updates))
+	dims := -1
+	seen := make(map[string]bool, len(updates))
+	for i, update := range updates {
+		if seen[update.NodeID] {
+			return nil, fmt.Errorf("%w: node %s in round %d", ErrDuplicateUpdate, update.NodeID, round)
+		}
+		seen[update.NodeID] = true
+
+		w, err := a.ingest(update)
+		if err != nil {
+			return nil, fmt.Errorf("round %d: %w", round, err)
+		}
+		if dims < 0 {
+			dims = len(w)
+		}
+		if len(w) != dims {
+			return nil, fmt.Errorf("%w: node %s has %d weights, expected %d", ErrShapeMismatch, update.NodeID, len(w), dims)
+		}
+		weights[i] = w
+	}
+	return weights, nil
+}
+
+// newContributionEntry starts a manifest entry with the update's digest.
+func newContributionEntry(update Update) protocol.ContributionEntry {
+	entry := protocol.ContributionEntry{
+		NodeID:       update.NodeID,
+		SampleCount:  update.SampleCount,
+		UpdateDigest: protocol.UpdateDigest(update.Bytes()),
+	}
+	switch {
+	case update.Quantized != nil:
+		entry.QuantizationErrorBound = update.Quantized.ErrorBound()
+		entry.UploadTier = protocol.UploadTierQuantized
+	case update.Sparse != nil:
+		entry.UploadTier = protocol.UploadTierSparse
+	default:
+		entry.UploadTier = protocol.UploadTierFull
+	}
+	if update.Statement != nil {
+		entry.BaseDigest = update.Statement.BaseDigest
+	}
+	return entry
+}
+
+// Bytes is the canonical encoding of the update's weights digested into the
+// contribution manifest. Clients pass it to protocol.NewTrainingStatement.
+func (u Update) Bytes() []byte {
+	switch {
+	case u.Quantized != nil:
+		return u.Quantized.Bytes()
+	case u.Sparse != nil:
+		return u.Sparse.Bytes()
+	default:
+		return encodeWeights(u.Weights)
+	}
+}
+
+// statementRejection returns the manifest reason for excluding update on
+// its training statement, or "" when the statement holds or no base model
+// resolver is set.
+func (a *Aggregator) statementRejection(round int, update Update, entry protocol.ContributionEntry) reasons.Code {
+	a.mu.RLock()
+	resolve := a.baseModel
+	a.mu.RUnlock()
+	if resolve == nil {
+		return ""
+	}
+	statement := update.Statement
+	if statement == nil || statement.Check(update.NodeID, round, entry.UpdateDigest) != nil {
+		return protocol.ReasonInvalidStatement
+	}
+	committed, ok := resolve(round)
+	if !ok || statement.CheckBase(committed) != nil {
+		return protocol.ReasonStaleBaseModel
+	}
+	return ""
+}
+
+// ingest returns an update's weights in float64, dequantizing int8 updates
+// and densifying sparse ones after checking their declared range against the
+// clip norm.
+func (a *Aggregator) ingest(update Update) ([]float64, error) {
+	encodings := 0
+	for _, set := range []bool{update.Weights != nil, update.Quantized != nil, update.Sparse != nil} {
+		if set {
+			encodings++
+		}
+	}
+	if encodings > 1 {
+		return nil, fmt.Errorf("%w: node %s sent more than one weight encoding", ErrShapeMismatch, update.NodeID)
+	}
+	if err := a.checkSpec(update); err != nil {
+		return nil, err
+	}
+	if err := a.checkDimension(update); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case update.Quantized != nil:
+		q := update.Quantized
+		if err := q.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: node %s: %v", ErrShapeMismatch, update.NodeID, err)
+		}
+		if err := a.checkClipNorm(update.NodeID, q.MaxMagnitude()); err != nil {
+			return nil, err
+		}
+		return q.Dequantize(), nil
+	case update.Sparse != nil:
+		s := update.Sparse
+		if err := s.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: node %s: %v", ErrShapeMismatch, update.NodeID, err)
+		}
+		if err := a.checkClipNorm(update.NodeID, s.MaxMagnitude()); err != nil {
+			return nil, err
+		}
+		return s.Densify(), nil
+	default:
+		return update.Weights, nil
+	}
+}
+
+// checkDimension compares an update's declared length with the registered
+// model dimension before any weights are allocated for it.
+func (a *Aggregator) checkDimension(update Update) error {
+	if a.Config == nil || a.Config.ModelDimension <= 0 {
+		return nil
+	}
+	declared := declaredDimension(update)
+	if declared != a.Config.ModelDimension {
+		return fmt.Errorf("%w: node %s declares %d weights, model has %d", ErrShapeMismatch, update.NodeID, declared, a.Config.ModelDimension)
+	}
+	return nil
+}
+
+// declaredDimension is the model length an update's encoding declares.
+func declaredDimension(update Update) int {
+	switch {
+	case update.Quantized != nil:
+		return update.Quantized.Len()
+	case update.Sparse != nil:
+		return update.Sparse.Dim
+	default:
+		return len(update.Weights)
+	}
+}
+
+func (a *Aggregator) checkClipNorm(nodeID string, magnitude float64) error {
+	if a.Config != nil && a.Config.ClipNorm > 0 && magnitude > a.Config.ClipNorm {
+		return fmt.Errorf("%w: node %s declares range %.6g beyond clip norm %.6g", ErrClipNormExceeded, nodeID, magnitude, a.Config.ClipNorm)
+	}
+	return nil
+}
+
+// encodeWeights is the little-endian float64 encoding digested for updates.
+func encodeWeights(weights []float64) []byte {
+	buf := make([]byte, 8*len(weights))
+	for i, w := range weights {
+		binary.LittleEndian.PutUint64(buf[i*8:], math.Float64bits(w))
+	}
+	return buf
+}
+
+// DecodeWeights is the inverse of Update.Bytes for float updates, as carried
+// in protocol.ModelUpdate.Weights.
+func DecodeWeights(buf []byte) ([]float64, error) {
+	if len(buf)%8 != 0 {
+		return nil, fmt.Errorf("%w: %d bytes is not a whole number of float64 weights", ErrShapeMismatch, len(buf))
+	}
+	weights := make([]float64, len(buf)/8)
+	for i := range weights {
+		weights[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[i*8:]))
+	}
+	return weights, nil
+}
+
+func l2Norm(weights []float64) float64 {
+	sum := 0.0
+	for _, w := range weights {
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/simrand"
 )
 
 // Test block creation and validation
@@ -436,6 +438,33 @@ func TestValidatorSelection(t *testing.T) {
 	// This test documents weighted random selection behavior
 }
 
+func TestSeededValidatorSelectionIsReproducible(t *testing.T) {
+	selectWithSeed := func(seed int64) []string {
+		validators := NewValidatorSet()
+		for i := 1; i <= 8; i++ {
+			if err := validators.AddValidator(fmt.Sprintf("node_%d", i), uint64(1000000*i)); err != nil {
+				t.Fatalf("failed to add validator: %v", err)
+			}
+		}
+		validators.SetRandomSource(simrand.New(seed))
+		var ids []string
+		for round := 0; round < 5; round++ {
+			for _, v := range validators.SelectValidators(3) {
+				ids = append(ids, v.NodeID)
+			}
+		}
+		return ids
+	}
+
+	first, second := selectWithSeed(659), selectWithSeed(659)
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Fatalf("same seed selected differently:\n%v\n%v", first, second)
+	}
+	if other := selectWithSeed(660); fmt.Sprint(first) == fmt.Sprint(other) {
+		t.Fatalf("different seeds selected identically: %v", first)
+	}
+}
+
 func TestValidatorRewardDistribution(t *testing.T) {
 	validators := NewValidatorSet()
 
@@ -5,6 +5,7 @@ package blockchain
 import (
 	cryptorand "crypto/rand"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"sort"
@@ -87,6 +88,17 @@ type ValidatorSet struct {
 	MaxValidators   int
 	MinStakeAmount  uint64
 	Policy          ReputationPolicy
+	// random is the entropy selection draws from; nil means crypto/rand.
+	random io.Reader
+}
+
+// SetRandomSource replaces crypto/rand as the entropy validator selection
+// draws from. Only simulations should call it, with a seeded source, so
+// that a run's validator choices are reproducible.
+func (vs *ValidatorSet) SetRandomSource(source io.Reader) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	vs.random = source
 }
 
 // NewValidatorSet creates a new validator set
@@ -243,6 +255,8 @@ func (vs *ValidatorSet) SelectValidators(count int) []*Validator {
 			active = append(active, v)
 		}
 	}
+	// Map order is random; fix it so a seeded source selects reproducibly.
+	sort.Slice(active, func(i, j int) bool { return active[i].NodeID < active[j].NodeID })
 
 	if len(active) == 0 {
 		return []*Validator{}
@@ -286,7 +300,11 @@ func (vs *ValidatorSet) selectWeighted(validators []*Validator, used map[string]
 	}
 
 	// Select random value between 0 and totalStake
-	r, err := cryptoRandomUint64(totalStake)
+	source := vs.random
+	if source == nil {
+		source = cryptorand.Reader
+	}
+	r, err := randomUint64(source, totalStake)
 	if err != nil {
 		// Fall back to deterministic selection if secure randomness fails.
 		return available[0]
@@ -668,11 +686,11 @@ func (vs *ValidatorSet) RotateEpoch(currentBlock uint64) {
 	}
 }
 
-func cryptoRandomUint64(max uint64) (uint64, error) {
+func randomUint64(source io.Reader, max uint64) (uint64, error) {
 	if max == 0 {
 		return 0, fmt.Errorf("max must be greater than zero")
 	}
-	n, err := cryptorand.Int(cryptorand.Reader, new(big.Int).SetUint64(max))
+	n, err := cryptorand.Int(source, new(big.Int).SetUint64(max))
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,195 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package capability probes the hardware a node runs on and keeps its
+// capability manifest current.
+package capability
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// benchmarkRuns is how many verifications the latency benchmark times; the
+// median is reported.
+const benchmarkRuns = 9
+
+// Probe gathers a capability manifest from the runtime.
+type Probe struct {
+	NodeID string
+	// Root prefixes every filesystem path read, for tests. Empty reads the
+	// real /sys, /proc, and /dev.
+	Root string
+	// BandwidthBytesPerSec is the configured upload bandwidth estimate.
+	BandwidthBytesPerSec int64
+	// Verify runs one wasm proof verification for the latency benchmark;
+	// nil skips the benchmark.
+	Verify func() error
+	// CPUCount overrides runtime.NumCPU when positive.
+	CPUCount int
+}
+
+// Run probes the node and returns its manifest.
+func (p Probe) Run() (*protocol.CapabilityManifest, error) {
+	cpus := p.CPUCount
+	if cpus <= 0 {
+		cpus = runtime.NumCPU()
+	}
+	memory, err := p.memoryBytes()
+	if err != nil {
+		return nil, err
+	}
+	latency, err := p.benchmark()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &protocol.CapabilityManifest{
+		NodeID:               p.NodeID,
+		CPUClass:             protocol.CPUClassFor(cpus),
+		CPUCount:             cpus,
+		MemoryBytes:          memory,
+		WasmSIMD:             runtime.GOARCH == "amd64" || runtime.GOARCH == "arm64",
+		TPMPresent:           p.exists("/dev/tpmrm0") || p.exists("/dev/tpm0"),
+		BandwidthBytesPerSec: p.BandwidthBytesPerSec,
+		WasmVerifyMicros:     latency.Microseconds(),
+		ProbedAt:             time.Now().UTC(),
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (p Probe) path(name string) string {
+	return filepath.Join(p.Root, name)
+}
+
+func (p Probe) exists(name string) bool {
+	_, err := os.Stat(p.path(name))
+	return err == nil
+}
+
+// memoryBytes returns the cgroup memory limit, v2 then v1, or the host's
+// total memory when no limit is set.
+func (p Probe) memoryBytes() (int64, error) {
+	for _, limitFile := range []string{"/sys/fs/cgroup/memory.max", "/sys/fs/cgroup/memory/memory.limit_in_bytes"} {
+		raw, err := os.ReadFile(p.path(limitFile)) // #nosec G304 -- fixed cgroup paths
+		if err != nil {
+			continue
+		}
+		limit, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+		// cgroup v1 reports "no limit" as a value near MaxInt64.
+		if err == nil && limit > 0 && limit < 1<<62 {
+			return limit, nil
+		}
+	}
+
+	file, err := os.Open(p.path("/proc/meminfo"))
+	if err != nil {
+		return 0, fmt.Errorf("read memory size: %w", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kib, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("parse MemTotal: %w", err)
+			}
+			return kib * 1024, nil
+		}
+	}
+	return 0, fmt.Errorf("read memory size: no MemTotal in /proc/meminfo")
+}
+
+// benchmark returns the median latency of benchmarkRuns verifications.
+func (p Probe) benchmark() (time.Duration, error) {
+	if p.Verify == nil {
+		return 0, nil
+	}
+	samples := make([]time.Duration, benchmarkRuns)
+	for i := range samples {
+		started := time.Now()
+		if err := p.Verify(); err != nil {
+			return 0, fmt.Errorf("wasm verification benchmark: %w", err)
+		}
+		samples[i] = time.Since(started)
+	}
+	sort.Slice(samples, func(a, b int) bool { return samples[a] < samples[b] })
+	return samples[len(samples)/2], nil
+}
+
+// Reporter re-probes a node and submits its manifest whenever it changes
+// materially.
+type Reporter struct {
+	mu     sync.Mutex
+	probe  Probe
+	submit func(*protocol.CapabilityManifest) error
+	last   *protocol.CapabilityManifest
+}
+
+// NewReporter submits manifests from probe through submit.
+func NewReporter(probe Probe, submit func(*protocol.CapabilityManifest) error) *Reporter {
+	return &Reporter{probe: probe, submit: submit}
+}
+
+// Check probes once and submits the manifest if it is the first one or has
+// changed materially since the last submitted. It reports whether it
+// submitted.
+func (r *Reporter) Check() (bool, error) {
+	manifest, err := r.probe.Run()
+	if err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !manifest.MaterialChange(r.last) {
+		return false, nil
+	}
+	if err := r.submit(manifest); err != nil {
+		return false, err
+	}
+	r.last = manifest
+	return true, nil
+}
+
+// Last returns the last submitted manifest, or nil.
+func (r *Reporter) Last() *protocol.CapabilityManifest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last == nil {
+		return nil
+	}
+	manifest := *r.last
+	return &manifest
+}
+
+// Run checks every interval until ctx is done.
+func (r *Reporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Check(); err != nil {
+				log.Printf("capability probe failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package capability
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func TestProbeReadsCgroupLimitAndTPM(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "proc/meminfo", "MemTotal:       16384000 kB\n")
+	probe := Probe{NodeID: "edge-1", Root: root, CPUCount: 4, Verify: func() error { return nil }}
+
+	manifest, err := probe.Run()
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if manifest.MemoryBytes != 16384000*1024 || manifest.TPMPresent || manifest.CPUClass != protocol.CPUClassStandard {
+		t.Fatalf("unexpected manifest without a cgroup limit: %+v", manifest)
+	}
+
+	writeFile(t, root, "sys/fs/cgroup/memory.max", "536870912\n")
+	writeFile(t, root, "dev/tpmrm0", "")
+	manifest, err = probe.Run()
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if manifest.MemoryBytes != 536870912 || !manifest.TPMPresent {
+		t.Fatalf("expected the cgroup limit and TPM, got %+v", manifest)
+	}
+}
+
+func TestReporterResubmitsOnMaterialChange(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "sys/fs/cgroup/memory.max", "1000000000\n")
+	var submitted []*protocol.CapabilityManifest
+	reporter := NewReporter(Probe{NodeID: "edge-1", Root: root, CPUCount: 4}, func(m *protocol.CapabilityManifest) error {
+		submitted = append(submitted, m)
+		return nil
+	})
+
+	check := func(memory string, wantSubmit bool) {
+		t.Helper()
+		writeFile(t, root, "sys/fs/cgroup/memory.max", memory)
+		sent, err := reporter.Check()
+		if err != nil {
+			t.Fatalf("check: %v", err)
+		}
+		if sent != wantSubmit {
+			t.Fatalf("memory %s: submitted=%t, want %t", memory, sent, wantSubmit)
+		}
+	}
+	check("1000000000\n", true)
+	check("1100000000\n", false)
+	check("700000000\n", true)
+	if len(submitted) != 2 || reporter.Last().MemoryBytes != 700000000 {
+		t.Fatalf("unexpected submissions %d, last %+v", len(submitted), reporter.Last())
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package certchain validates the quorum certificate chain behind a
+// regional summary, so the global tier accepts a shard's model only on
+// proof that the shard committed it. The chain has two links: the shard's
+// MembershipEpochRecord, committed and signed at the global tier, and the
+// QuorumCertificate its voters signed for the committed model, which
+// references that record by epoch and digest. ValidateCertificateChain
+// checks both links and needs nothing but the global tier's public key, so
+// auditors can run it on archived summaries.
+package certchain
+
+import (
+	"fmt"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Signer signs with a node's identity key. *crypto.SecureChannel
+// implements it.
+type Signer interface {
+	SignData(data []byte) ([]byte, error)
+	ExportPublicKey() ([]byte, error)
+}
+
+// NewCertificate returns the unsigned certificate of the shard's commit of
+// summary by the members of record.
+func NewCertificate(record protocol.MembershipEpochRecord, summary modeldist.RoundSummary) protocol.QuorumCertificate {
+	return protocol.QuorumCertificate{
+		ShardID:     record.ShardID,
+		Epoch:       record.Epoch,
+		EpochDigest: record.Digest(),
+		Round:       summary.Round,
+		ProposalID:  summary.Certificate.ProposalID,
+		ModelDigest: summary.ModelDigest,
+	}
+}
+
+// SignCertificate adds nodeID's signature, by signer, to cert.
+func SignCertificate(cert *protocol.QuorumCertificate, nodeID string, signer Signer) error {
+	publicKey, err := signer.ExportPublicKey()
+	if err != nil {
+		return err
+	}
+	algorithm, err := crypto.PublicKeyAlgorithm(publicKey)
+	if err != nil {
+		return err
+	}
+	digest := cert.SigningDigest()
+	signature, err := signer.SignData(digest[:])
+	if err != nil {
+		return fmt.Errorf("sign certificate for round %d: %w", cert.Round, err)
+	}
+	cert.Signatures = append(cert.Signatures, protocol.VoterSignature{NodeID: nodeID, Signature: signature, Algorithm: algorithm})
+	return nil
+}
+
+// ValidateCertificateChain checks that chain proves its shard committed
+// its certificate's model:
+//
+//   - the epoch record is signed by the global tier, whose PEM public key
+//     is globalKey, and is well formed;
+//   - the record is the shard's current epoch, when currentEpoch is not
+//     zero; an older one fails with ErrStaleEpoch;
+//   - the certificate references the record by shard, epoch, and digest;
+//   - every signature on the certificate is a distinct member's, valid
+//     under that member's key and algorithm;
+//   - the signers make a quorum of the members under the record's fault
+//     model.
+//
+// An auditor replaying history passes zero for currentEpoch to accept
+// certificates of any committed epoch.
+func ValidateCertificateChain(chain protocol.CertificateChain, globalKey []byte, currentEpoch uint64) error {
+	record, cert := &chain.Epoch, &chain.Certificate
+	model, err := verifyEpochRecord(record, globalKey)
+	if err != nil {
+		return err
+	}
+	switch {
+	case currentEpoch == 0:
+	case record.Epoch < currentEpoch:
+		return fmt.Errorf("%w: shard %s certified under epoch %d, current epoch is %d", ErrStaleEpoch, record.ShardID, record.Epoch, currentEpoch)
+	case record.Epoch > currentEpoch:
+		return fmt.Errorf("%w: shard %s epoch %d is newer than the current epoch %d", ErrInvalidEpochRecord, record.ShardID, record.Epoch, currentEpoch)
+	}
+
+	if cert.ShardID != record.ShardID || cert.Epoch != record.Epoch || cert.EpochDigest != record.Digest() {
+		return fmt.Errorf("%w: certificate names shard %s epoch %d, not the epoch record of shard %s epoch %d", ErrForgedCertificate, cert.ShardID, cert.Epoch, record.ShardID, record.Epoch)
+	}
+	if cert.Round <= 0 || cert.ModelDigest == "" {
+		return fmt.Errorf("%w: shard %s certificate for round %d names no model", ErrForgedCertificate, cert.ShardID, cert.Round)
+	}
+
+	digest := cert.SigningDigest()
+	signed := make(map[string]bool, len(cert.Signatures))
+	for _, signature := range cert.Signatures {
+		member, ok := record.Member(signature.NodeID)
+		if !ok {
+			return fmt.Errorf("%w: shard %s round %d signed by %q, not a member at epoch %d", ErrForgedCertificate, cert.ShardID, cert.Round, signature.NodeID, record.Epoch)
+		}
+		if signed[member.NodeID] {
+			return fmt.Errorf("%w: shard %s round %d signed twice by %s", ErrForgedCertificate, cert.ShardID, cert.Round, member.NodeID)
+		}
+		if err := verifySigned(member.PublicKey, signature.Algorithm, digest[:], signature.Signature); err != nil {
+			return fmt.Errorf("%w: shard %s round %d signature of %s: %w", ErrForgedCertificate, cert.ShardID, cert.Round, member.NodeID, err)
+		}
+		signed[member.NodeID] = true
+	}
+
+	if quorum := model.Quorum(len(record.Members)); len(signed) < quorum {
+		return fmt.Errorf("%w: shard %s round %d signed by %d of %d members, %s needs %d", ErrQuorumNotMet, cert.ShardID, cert.Round, len(signed), len(record.Members), model, quorum)
+	}
+	return nil
+}
+
+// verifyEpochRecord checks that record is well formed and signed by the
+// global tier's key, and returns its fault model.
+func verifyEpochRecord(record *protocol.MembershipEpochRecord, globalKey []byte) (faultmodel.Model, error) {
+	model, err := checkRecord(record)
+	if err != nil {
+		return "", err
+	}
+	if len(record.Signature) == 0 {
+		return "", fmt.Errorf("%w: shard %s epoch %d is unsigned", ErrInvalidEpochRecord, record.ShardID, record.Epoch)
+	}
+	fingerprint, err := crypto.PublicKeyFingerprint(globalKey)
+	if err != nil {
+		return "", fmt.Errorf("trusted global key: %w", err)
+	}
+	if record.SignerFingerprint != fingerprint {
+		return "", fmt.Errorf("%w: shard %s epoch %d signed by %q, not the global tier", ErrInvalidEpochRecord, record.ShardID, record.Epoch, record.SignerFingerprint)
+	}
+	digest := record.SigningDigest()
+	if err := verifySigned(globalKey, record.SignatureAlgorithm, digest[:], record.Signature); err != nil {
+		return "", fmt.Errorf("%w: shard %s epoch %d: %w", ErrInvalidEpochRecord, record.ShardID, record.Epoch, err)
+	}
+	return model, nil
+}
+
+// checkRecord checks that record names its shard, epoch, and aggregator
+// and lists distinct members with identity keys, and returns its fault
+// model.
+func checkRecord(record *protocol.MembershipEpochRecord) (faultmodel.Model, error) {
+	if record.ShardID == "" || record.Epoch == 0 || record.Aggregator == "" {
+		return "", fmt.Errorf("%w: shard %q epoch %d names no aggregator", ErrInvalidEpochRecord, record.ShardID, record.Epoch)
+	}
+	if len(record.Members) == 0 {
+		return "", fmt.Errorf("%w: shard %s epoch %d has no members", ErrInvalidEpochRecord, record.ShardID, record.Epoch)
+	}
+	model, err := faultmodel.Parse(record.FaultModel)
+	if err != nil {
+		return "", fmt.Errorf("%w: shard %s epoch %d: %w", ErrInvalidEpochRecord, record.ShardID, record.Epoch, err)
+	}
+	seen := make(map[string]bool, len(record.Members))
+	for _, member := range record.Members {
+		if member.NodeID == "" || seen[member.NodeID] {
+			return "", fmt.Errorf("%w: shard %s epoch %d lists member %q twice or unnamed", ErrInvalidEpochRecord, record.ShardID, record.Epoch, member.NodeID)
+		}
+		seen[member.NodeID] = true
+		if _, err := crypto.PublicKeyAlgorithm(member.PublicKey); err != nil {
+			return "", fmt.Errorf("%w: shard %s epoch %d member %s key: %w", ErrInvalidEpochRecord, record.ShardID, record.Epoch, member.NodeID, err)
+		}
+	}
+	return model, nil
+}
+
+// verifySigned checks signature over data by the PEM publicKey, refusing
+// one that declares another algorithm than the key's.
+func verifySigned(publicKey []byte, declared protocol.AlgorithmID, data, signature []byte) error {
+	algorithm, err := crypto.PublicKeyAlgorithm(publicKey)
+	if err != nil {
+		return err
+	}
+	if err := protocol.CheckAlgorithm(declared, algorithm); err != nil {
+		return err
+	}
+	return crypto.VerifyWithPublicKey(publicKey, data, signature)
+}
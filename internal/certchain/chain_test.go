@@ -0,0 +1,244 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package certchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// shard is a regional shard's voters and the epoch the global tier
+// committed for them.
+type shard struct {
+	record protocol.MembershipEpochRecord
+	voters map[string]*crypto.SecureChannel
+}
+
+func newChannel(t *testing.T, algorithm protocol.AlgorithmID) *crypto.SecureChannel {
+	t.Helper()
+	sc, err := crypto.NewSecureChannelWithAlgorithm(algorithm)
+	if err != nil {
+		t.Fatalf("identity: %v", err)
+	}
+	return sc
+}
+
+func newLedger(t *testing.T) *EpochLedger {
+	t.Helper()
+	ledger, err := NewEpochLedger(newChannel(t, protocol.AlgorithmECDSAP256))
+	if err != nil {
+		t.Fatalf("ledger: %v", err)
+	}
+	return ledger
+}
+
+// commitShard commits epoch of shardID with n voters, every other one
+// Ed25519, under faultModel.
+func commitShard(t *testing.T, ledger *EpochLedger, shardID string, epoch uint64, n int, faultModel string) *shard {
+	t.Helper()
+	s := &shard{voters: make(map[string]*crypto.SecureChannel)}
+	record := protocol.MembershipEpochRecord{ShardID: shardID, Epoch: epoch, Aggregator: "regional-" + shardID, FaultModel: faultModel}
+	for i := 0; i < n; i++ {
+		algorithm := protocol.AlgorithmECDSAP256
+		if i%2 == 1 {
+			algorithm = protocol.AlgorithmEd25519
+		}
+		nodeID := fmt.Sprintf("edge-%d", i)
+		voter := newChannel(t, algorithm)
+		publicKey, _ := voter.ExportPublicKey()
+		s.voters[nodeID] = voter
+		record.Members = append(record.Members, protocol.ShardMember{NodeID: nodeID, PublicKey: publicKey})
+	}
+	committed, err := ledger.Commit(record)
+	if err != nil {
+		t.Fatalf("commit epoch: %v", err)
+	}
+	s.record = committed
+	return s
+}
+
+// certify returns a regional summary of round whose certificate the first
+// signers voters of s signed.
+func (s *shard) certify(t *testing.T, round, signers int) *protocol.ModelUpdate {
+	t.Helper()
+	weights := batch.Update{Weights: []float64{0.25, -0.75}}.Bytes()
+	cert := NewCertificate(s.record, modeldist.RoundSummary{
+		Round:       round,
+		ModelDigest: protocol.WeightsDigest(weights),
+		Certificate: modeldist.CommitCertificate{ProposalID: fmt.Sprintf("%s-%d", s.record.Aggregator, round)},
+	})
+	for _, member := range s.record.Members[:signers] {
+		if err := SignCertificate(&cert, member.NodeID, s.voters[member.NodeID]); err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+	}
+	return &protocol.ModelUpdate{
+		NodeID:      s.record.Aggregator,
+		Round:       round,
+		Weights:     weights,
+		Metrics:     protocol.Metrics{Samples: 40},
+		Certificate: &protocol.CertificateChain{Epoch: s.record, Certificate: cert},
+	}
+}
+
+func TestValidChainAdmitsRegionalSummaryToGlobalAggregation(t *testing.T) {
+	ledger := newLedger(t)
+	east := commitShard(t, ledger, "east", 1, 4, "")
+
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "global", Timeout: time.Second}))
+	global, err := registry.Create(protocol.DefaultFederation)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := registry.Bind(east.record.Aggregator, []string{global.ID}); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	global.SetSummaryCheck(ledger.CheckSummary)
+
+	uncertified := east.certify(t, 1, 3)
+	uncertified.Certificate = nil
+	if err := global.Submit(uncertified); !errors.Is(err, ErrMissingCertificate) {
+		t.Fatalf("expected ErrMissingCertificate, got %v", err)
+	}
+	// Three of four members are a classic quorum.
+	summary := east.certify(t, 1, 3)
+	if err := global.Submit(summary); err != nil {
+		t.Fatalf("submit certified summary: %v", err)
+	}
+	if global.Pending(1) != 1 {
+		t.Fatalf("pending = %d", global.Pending(1))
+	}
+
+	// An auditor validates the archived chain with only the global key.
+	archived, err := json.Marshal(summary.Certificate)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var chain protocol.CertificateChain
+	if err := json.Unmarshal(archived, &chain); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := ValidateCertificateChain(chain, ledger.PublicKey(), 0); err != nil {
+		t.Fatalf("audit: %v", err)
+	}
+}
+
+func TestForgedRegionalCertificateIsRejected(t *testing.T) {
+	ledger := newLedger(t)
+	east := commitShard(t, ledger, "east", 1, 4, "")
+
+	for name, c := range map[string]struct {
+		forge func(update *protocol.ModelUpdate)
+		want  error
+	}{
+		"outsider signs as a member": {func(update *protocol.ModelUpdate) {
+			cert := &update.Certificate.Certificate
+			cert.Signatures = cert.Signatures[:2]
+			if err := SignCertificate(cert, "edge-3", newChannel(t, protocol.AlgorithmECDSAP256)); err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+		}, ErrForgedCertificate},
+		"non-member signs": {func(update *protocol.ModelUpdate) {
+			_ = SignCertificate(&update.Certificate.Certificate, "edge-9", newChannel(t, protocol.AlgorithmECDSAP256))
+		}, ErrForgedCertificate},
+		"member signs twice": {func(update *protocol.ModelUpdate) {
+			cert := &update.Certificate.Certificate
+			cert.Signatures = append(cert.Signatures[:2], cert.Signatures[0])
+		}, ErrForgedCertificate},
+		"model swapped after signing": {func(update *protocol.ModelUpdate) {
+			update.Weights = batch.Update{Weights: []float64{9, 9}}.Bytes()
+			update.Certificate.Certificate.ModelDigest = protocol.WeightsDigest(update.Weights)
+		}, ErrForgedCertificate},
+		"weights are not the certified model": {func(update *protocol.ModelUpdate) {
+			update.Weights = batch.Update{Weights: []float64{9, 9}}.Bytes()
+		}, ErrForgedCertificate},
+		"submitted by another aggregator": {func(update *protocol.ModelUpdate) {
+			update.NodeID = "regional-west"
+		}, ErrForgedCertificate},
+		"signature claims another algorithm": {func(update *protocol.ModelUpdate) {
+			update.Certificate.Certificate.Signatures[1].Algorithm = protocol.AlgorithmECDSAP256
+		}, protocol.ErrAlgorithmMismatch},
+		"epoch record signed by the regional": {func(update *protocol.ModelUpdate) {
+			regional := newChannel(t, protocol.AlgorithmECDSAP256)
+			forgedLedger, _ := NewEpochLedger(regional)
+			record := update.Certificate.Epoch
+			record.Members = append(record.Members, protocol.ShardMember{NodeID: "sybil", PublicKey: record.Members[0].PublicKey})
+			update.Certificate.Epoch, _ = forgedLedger.Commit(record)
+		}, ErrInvalidEpochRecord},
+		"members added to the committed record": {func(update *protocol.ModelUpdate) {
+			record := &update.Certificate.Epoch
+			record.Members = append(record.Members, protocol.ShardMember{NodeID: "sybil", PublicKey: record.Members[0].PublicKey})
+		}, ErrInvalidEpochRecord},
+		"too few signers": {func(update *protocol.ModelUpdate) {
+			cert := &update.Certificate.Certificate
+			cert.Signatures = cert.Signatures[:2]
+		}, ErrQuorumNotMet},
+	} {
+		t.Run(name, func(t *testing.T) {
+			update := east.certify(t, 2, 3)
+			c.forge(update)
+			if err := ledger.CheckSummary(update); !errors.Is(err, c.want) {
+				t.Fatalf("expected %v, got %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestCertificateFromOutdatedEpochIsRejected(t *testing.T) {
+	ledger := newLedger(t)
+	east := commitShard(t, ledger, "east", 1, 4, "")
+	outdated := east.certify(t, 3, 4)
+
+	// The shard's membership changes; the global tier commits epoch 2.
+	next := east.record
+	next.Epoch = 2
+	next.Members = next.Members[:3]
+	if _, err := ledger.Commit(next); err != nil {
+		t.Fatalf("commit epoch 2: %v", err)
+	}
+	if _, err := ledger.Commit(east.record); !errors.Is(err, ErrStaleEpoch) {
+		t.Fatalf("recommitting epoch 1: expected ErrStaleEpoch, got %v", err)
+	}
+
+	if err := ledger.CheckSummary(outdated); !errors.Is(err, ErrStaleEpoch) {
+		t.Fatalf("expected ErrStaleEpoch, got %v", err)
+	}
+	// It was valid when made, which is all an auditor of history asks.
+	if err := ValidateCertificateChain(*outdated.Certificate, ledger.PublicKey(), 0); err != nil {
+		t.Fatalf("audit of epoch 1: %v", err)
+	}
+	if err := ledger.Validate(protocol.CertificateChain{Epoch: protocol.MembershipEpochRecord{ShardID: "west"}}); !errors.Is(err, ErrUnknownShard) {
+		t.Fatalf("expected ErrUnknownShard, got %v", err)
+	}
+}
+
+func TestQuorumFollowsTheShardsFaultModel(t *testing.T) {
+	ledger := newLedger(t)
+	// Of six members, classic33 needs five signers and hierarchical55
+	// four.
+	classic := commitShard(t, ledger, "classic", 1, 6, "classic33")
+	hierarchical := commitShard(t, ledger, "hierarchical", 1, 6, "hierarchical55")
+
+	if err := ledger.CheckSummary(classic.certify(t, 1, 4)); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("classic33 with 4 of 6: expected ErrQuorumNotMet, got %v", err)
+	}
+	if err := ledger.CheckSummary(classic.certify(t, 1, 5)); err != nil {
+		t.Fatalf("classic33 with 5 of 6: %v", err)
+	}
+	if err := ledger.CheckSummary(hierarchical.certify(t, 1, 4)); err != nil {
+		t.Fatalf("hierarchical55 with 4 of 6: %v", err)
+	}
+	if err := ledger.CheckSummary(hierarchical.certify(t, 1, 3)); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("hierarchical55 with 3 of 6: expected ErrQuorumNotMet, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package certchain
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by certificate chain validation. Match
+// them with errors.Is; never compare error strings.
+var (
+	// ErrMissingCertificate means a regional summary carries no certificate
+	// chain where one is required. Not retryable with the same summary.
+	ErrMissingCertificate = errors.New("regional summary has no certificate chain")
+	// ErrInvalidEpochRecord means a membership epoch record is malformed or
+	// not signed by the trusted global tier. Not retryable with the same
+	// record.
+	ErrInvalidEpochRecord = errors.New("invalid membership epoch record")
+	// ErrStaleEpoch means a certificate's membership epoch is older than
+	// the shard's latest committed one. Not retryable; the shard must
+	// recertify under its current membership.
+	ErrStaleEpoch = errors.New("membership epoch is outdated")
+	// ErrForgedCertificate means a certificate does not match its epoch
+	// record or summary, or carries a signature that is not its member's.
+	// Not retryable with the same certificate.
+	ErrForgedCertificate = errors.New("forged quorum certificate")
+	// ErrQuorumNotMet means too few members signed a certificate for a
+	// quorum under the shard's fault model. Not retryable with the same
+	// certificate.
+	ErrQuorumNotMet = errors.New("certificate quorum not met")
+	// ErrUnknownShard means the global tier has committed no membership
+	// epoch for the shard. Retryable once the epoch is committed.
+	ErrUnknownShard = errors.New("unknown shard")
+)
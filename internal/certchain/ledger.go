@@ -0,0 +1,118 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package certchain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// EpochLedger is the global tier's record of the membership epochs it has
+// committed for each shard, signed with its identity key. It validates
+// regional summaries against each shard's latest epoch.
+type EpochLedger struct {
+	signer      Signer
+	publicKey   []byte
+	fingerprint string
+	algorithm   protocol.AlgorithmID
+
+	mu     sync.RWMutex
+	epochs map[string]protocol.MembershipEpochRecord
+}
+
+// NewEpochLedger creates a ledger committing epochs with signer, the
+// global tier's identity.
+func NewEpochLedger(signer Signer) (*EpochLedger, error) {
+	publicKey, err := signer.ExportPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := crypto.PublicKeyFingerprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := crypto.PublicKeyAlgorithm(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &EpochLedger{
+		signer:      signer,
+		publicKey:   publicKey,
+		fingerprint: fingerprint,
+		algorithm:   algorithm,
+		epochs:      make(map[string]protocol.MembershipEpochRecord),
+	}, nil
+}
+
+// PublicKey returns the PEM key epochs are signed with, the one auditors
+// pass to ValidateCertificateChain.
+func (l *EpochLedger) PublicKey() []byte {
+	return append([]byte(nil), l.publicKey...)
+}
+
+// Commit signs record and makes it the shard's current epoch. Its epoch
+// must be later than the shard's current one, with ErrStaleEpoch
+// otherwise.
+func (l *EpochLedger) Commit(record protocol.MembershipEpochRecord) (protocol.MembershipEpochRecord, error) {
+	if _, err := checkRecord(&record); err != nil {
+		return protocol.MembershipEpochRecord{}, err
+	}
+	record.Members = append([]protocol.ShardMember(nil), record.Members...)
+	digest := record.SigningDigest()
+	signature, err := l.signer.SignData(digest[:])
+	if err != nil {
+		return protocol.MembershipEpochRecord{}, fmt.Errorf("sign shard %s epoch %d: %w", record.ShardID, record.Epoch, err)
+	}
+	record.Signature, record.SignerFingerprint, record.SignatureAlgorithm = signature, l.fingerprint, l.algorithm
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if current, ok := l.epochs[record.ShardID]; ok && record.Epoch <= current.Epoch {
+		return protocol.MembershipEpochRecord{}, fmt.Errorf("%w: shard %s epoch %d does not follow the current epoch %d", ErrStaleEpoch, record.ShardID, record.Epoch, current.Epoch)
+	}
+	l.epochs[record.ShardID] = record
+	return record, nil
+}
+
+// Epoch returns the shard's current epoch record, if one is committed.
+func (l *EpochLedger) Epoch(shardID string) (protocol.MembershipEpochRecord, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	record, ok := l.epochs[shardID]
+	return record, ok
+}
+
+// Validate runs ValidateCertificateChain on chain against its shard's
+// current epoch, failing with ErrUnknownShard if there is none.
+func (l *EpochLedger) Validate(chain protocol.CertificateChain) error {
+	current, ok := l.Epoch(chain.Epoch.ShardID)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownShard, chain.Epoch.ShardID)
+	}
+	return ValidateCertificateChain(chain, l.publicKey, current.Epoch)
+}
+
+// CheckSummary admits a regional summary into global aggregation only if
+// its certificate chain validates and certifies exactly the summary: its
+// round and weights, submitted by the shard's aggregator. Install it with
+// federation.Federation.SetSummaryCheck.
+func (l *EpochLedger) CheckSummary(update *protocol.ModelUpdate) error {
+	chain := update.Certificate
+	if chain == nil {
+		return fmt.Errorf("%w: node %s round %d", ErrMissingCertificate, update.NodeID, update.Round)
+	}
+	if chain.Epoch.Aggregator != update.NodeID {
+		return fmt.Errorf("%w: shard %s submits through %s, not %s", ErrForgedCertificate, chain.Epoch.ShardID, chain.Epoch.Aggregator, update.NodeID)
+	}
+	if chain.Certificate.Round != update.Round {
+		return fmt.Errorf("%w: certificate for round %d on a round %d summary", ErrForgedCertificate, chain.Certificate.Round, update.Round)
+	}
+	if update.Weights == nil || protocol.WeightsDigest(update.Weights) != chain.Certificate.ModelDigest {
+		return fmt.Errorf("%w: shard %s round %d weights are not the certified model", ErrForgedCertificate, chain.Epoch.ShardID, update.Round)
+	}
+	return l.Validate(*chain)
+}
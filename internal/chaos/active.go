@@ -0,0 +1,15 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+//go:build !chaos
+
+package chaos
+
+// Enabled reports whether the binary was built with the chaos tag.
+const Enabled = false
+
+// Active returns the process-wide injector. Without the chaos build tag it is
+// always nil, so injection points in production code compile to no-ops.
+func Active() *Injector {
+	return nil
+}
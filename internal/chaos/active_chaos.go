@@ -0,0 +1,42 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+//go:build chaos
+
+package chaos
+
+import (
+	"log"
+	"os"
+	"sync/atomic"
+)
+
+// Enabled reports whether the binary was built with the chaos tag.
+const Enabled = true
+
+var active atomic.Pointer[Injector]
+
+// init loads the plan named by MOHAWK_CHAOS_PLAN, if set.
+func init() {
+	path := os.Getenv("MOHAWK_CHAOS_PLAN")
+	if path == "" {
+		return
+	}
+	plan, err := LoadPlan(path)
+	if err != nil {
+		log.Printf("chaos: %v", err)
+		return
+	}
+	active.Store(NewInjector(plan, 1))
+	log.Printf("chaos: fault injection active from %s (seed %d)", path, plan.Seed)
+}
+
+// Active returns the process-wide injector, or nil when no plan is loaded.
+func Active() *Injector {
+	return active.Load()
+}
+
+// SetActive installs inj as the process-wide injector; nil disables it.
+func SetActive(inj *Injector) {
+	active.Store(inj)
+}
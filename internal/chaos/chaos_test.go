@@ -0,0 +1,83 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package chaos
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePlanRejectsInvalidProbabilities(t *testing.T) {
+	if _, err := ParsePlan([]byte(`{"messages":{"drop":1.5}}`)); err == nil {
+		t.Fatal("expected out-of-range drop probability to be rejected")
+	}
+	if _, err := ParsePlan([]byte(`{"crashes":[{"peer":"a","round":3,"restart_round":2}]}`)); err == nil {
+		t.Fatal("expected restart before crash to be rejected")
+	}
+	if _, err := ParsePlan([]byte(`{"partitions":[{"start_round":1,"end_round":2,"groups":[["a"]]}]}`)); err == nil {
+		t.Fatal("expected single-group partition to be rejected")
+	}
+}
+
+func TestInjectorIsDeterministicForSeed(t *testing.T) {
+	plan := &Plan{Messages: MessageFaults{Drop: 0.2, Duplicate: 0.1, Delay: 0.3, MaxDelayMs: 50, Reorder: 0.1}}
+	run := func() Report {
+		inj := NewInjector(plan, 77)
+		for round := 1; round <= 10; round++ {
+			inj.AdvanceRound(round)
+			for i := 0; i < 20; i++ {
+				inj.MessageFault("a", "b")
+			}
+		}
+		return inj.Report()
+	}
+
+	first, second := run(), run()
+	if first != second {
+		t.Fatalf("expected identical reports for the same seed, got %+v and %+v", first, second)
+	}
+	if first.Dropped == 0 || first.Delayed == 0 {
+		t.Fatalf("expected faults to be injected, got %+v", first)
+	}
+}
+
+func TestInjectorAppliesCrashPartitionAndChurn(t *testing.T) {
+	inj := NewInjector(&Plan{
+		Crashes:    []CrashEvent{{Peer: "b", Round: 2, RestartRound: 4}},
+		Partitions: []Partition{{StartRound: 5, EndRound: 5, Groups: [][]string{{"a"}, {"c"}}}},
+		Churn:      []ChurnEvent{{Round: 6, Leave: []string{"d"}}},
+	}, 1)
+
+	inj.AdvanceRound(1)
+	if inj.MessageFault("a", "b").Drop {
+		t.Fatal("expected delivery before the crash")
+	}
+	inj.AdvanceRound(2)
+	if !inj.PeerDown("b") || !inj.MessageFault("a", "b").Drop {
+		t.Fatal("expected crashed peer to drop messages")
+	}
+	inj.AdvanceRound(3)
+	inj.AdvanceRound(4)
+	if inj.PeerDown("b") {
+		t.Fatal("expected peer to restart at round 4")
+	}
+	inj.AdvanceRound(5)
+	if !inj.MessageFault("a", "c").Drop {
+		t.Fatal("expected partitioned peers to be unreachable")
+	}
+	inj.AdvanceRound(6)
+	if got := inj.DownPeers(); len(got) != 1 || got[0] != "d" {
+		t.Fatalf("expected churned peer d to be down, got %v", got)
+	}
+	if report := inj.Report(); report.Partitioned != 1 || report.PeerDownRounds != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestInjectorFailsDiskWrites(t *testing.T) {
+	inj := NewInjector(&Plan{Disk: DiskFaults{WriteFailure: 1}}, 1)
+	if err := inj.DiskWrite("/tmp/state.json"); !errors.Is(err, ErrInjectedDiskFailure) {
+		t.Fatalf("expected injected disk failure, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInjectedDiskFailure is returned by DiskWrite when a write is failed on
+// purpose.
+var ErrInjectedDiskFailure = errors.New("chaos: injected disk write failure")
+
+// Fault is the decision for a single message.
+type Fault struct {
+	Drop      bool
+	Duplicate bool
+	Reorder   bool
+	Delay     time.Duration
+}
+
+// Report counts every injected fault.
+type Report struct {
+	Seed               int64 `json:"seed"`
+	Rounds             int   `json:"rounds"`
+	Messages           int   `json:"messages"`
+	Dropped            int   `json:"dropped"`
+	Partitioned        int   `json:"partitioned"`
+	Duplicated         int   `json:"duplicated"`
+	Delayed            int   `json:"delayed"`
+	Reordered          int   `json:"reordered"`
+	TotalDelayMs       int64 `json:"total_delay_ms"`
+	PeerDownRounds     int   `json:"peer_down_rounds"`
+	DiskWrites         int   `json:"disk_writes"`
+	DiskWriteFailures  int   `json:"disk_write_failures"`
+	SkewedPeers        int   `json:"skewed_peers"`
+	MaxClockSkewMillis int64 `json:"max_clock_skew_ms"`
+}
+
+// Injector makes seeded injection decisions for a Plan. Decisions are
+// deterministic for a given seed and call order.
+type Injector struct {
+	mu     sync.Mutex
+	plan   Plan
+	rng    *rand.Rand
+	round  int
+	down   map[string]bool
+	skew   map[string]time.Duration
+	report Report
+}
+
+// NewInjector creates an injector. A zero plan seed falls back to seed.
+func NewInjector(plan *Plan, seed int64) *Injector {
+	p := Plan{}
+	if plan != nil {
+		p = *plan
+	}
+	if p.Seed != 0 {
+		seed = p.Seed
+	}
+	inj := &Injector{
+		plan: p,
+		rng:  rand.New(rand.NewSource(seed)), // #nosec G404 -- reproducible fault injection, not security sensitive
+		down: make(map[string]bool),
+		skew: make(map[string]time.Duration, len(p.ClockSkew)),
+	}
+	inj.report.Seed = seed
+	for _, skew := range p.ClockSkew {
+		offset := time.Duration(skew.OffsetMs) * time.Millisecond
+		inj.skew[skew.Peer] = offset
+		inj.report.SkewedPeers++
+		if abs := absMillis(skew.OffsetMs); abs > inj.report.MaxClockSkewMillis {
+			inj.report.MaxClockSkewMillis = abs
+		}
+	}
+	return inj
+}
+
+// AdvanceRound applies crash, restart, and churn events scheduled for round.
+// Rounds must be advanced in order.
+func (inj *Injector) AdvanceRound(round int) {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.round = round
+	inj.report.Rounds++
+	for _, crash := range inj.plan.Crashes {
+		if crash.Round == round {
+			inj.down[crash.Peer] = true
+		}
+		if crash.RestartRound == round {
+			delete(inj.down, crash.Peer)
+		}
+	}
+	for _, churn := range inj.plan.Churn {
+		if churn.Round != round {
+			continue
+		}
+		for _, peer := range churn.Leave {
+			inj.down[peer] = true
+		}
+		for _, peer := range churn.Join {
+			delete(inj.down, peer)
+		}
+	}
+	inj.report.PeerDownRounds += len(inj.down)
+}
+
+// Round returns the current round.
+func (inj *Injector) Round() int {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	return inj.round
+}
+
+// PeerDown reports whether peer is crashed or churned out this round.
+func (inj *Injector) PeerDown(peer string) bool {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	return inj.down[peer]
+}
+
+// DownPeers returns the peers currently down, sorted.
+func (inj *Injector) DownPeers() []string {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	peers := make([]string, 0, len(inj.down))
+	for peer := range inj.down {
+		peers = append(peers, peer)
+	}
+	sort.Strings(peers)
+	return peers
+}
+
+// MessageFault decides the fate of a message from one peer to another.
+// Messages to or from a down peer, or across a partition, are dropped.
+func (inj *Injector) MessageFault(from, to string) Fault {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.report.Messages++
+	if inj.down[from] || inj.down[to] {
+		inj.report.Dropped++
+		return Fault{Drop: true}
+	}
+	if inj.partitionedLocked(from, to) {
+		inj.report.Partitioned++
+		return Fault{Drop: true}
+	}
+
+	// Draw every probability on every call so one fault's outcome does not
+	// shift the random stream for the others.
+	faults := inj.plan.Messages
+	dropDraw, dupDraw, delayDraw, reorderDraw, delayAmount := inj.rng.Float64(), inj.rng.Float64(), inj.rng.Float64(), inj.rng.Float64(), inj.rng.Float64()
+
+	if dropDraw < faults.Drop {
+		inj.report.Dropped++
+		return Fault{Drop: true}
+	}
+	var fault Fault
+	if dupDraw < faults.Duplicate {
+		fault.Duplicate = true
+		inj.report.Duplicated++
+	}
+	if delayDraw < faults.Delay && faults.MaxDelayMs > 0 {
+		fault.Delay = time.Duration(1+int(delayAmount*float64(faults.MaxDelayMs))) * time.Millisecond
+		inj.report.Delayed++
+		inj.report.TotalDelayMs += fault.Delay.Milliseconds()
+	}
+	if reorderDraw < faults.Reorder {
+		fault.Reorder = true
+		inj.report.Reordered++
+	}
+	return fault
+}
+
+// DiskWrite decides whether a persistence write to path fails.
+func (inj *Injector) DiskWrite(path string) error {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.report.DiskWrites++
+	if inj.rng.Float64() < inj.plan.Disk.WriteFailure {
+		inj.report.DiskWriteFailures++
+		return fmt.Errorf("%w: %s", ErrInjectedDiskFailure, path)
+	}
+	return nil
+}
+
+// Skew returns the clock offset injected for peer.
+func (inj *Injector) Skew(peer string) time.Duration {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	return inj.skew[peer]
+}
+
+// Now returns peer's skewed wall clock.
+func (inj *Injector) Now(peer string) time.Time {
+	return time.Now().Add(inj.Skew(peer))
+}
+
+// Report returns a copy of the fault counters.
+func (inj *Injector) Report() Report {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	return inj.report
+}
+
+func (inj *Injector) partitionedLocked(from, to string) bool {
+	for _, partition := range inj.plan.Partitions {
+		if inj.round < partition.StartRound || inj.round > partition.EndRound {
+			continue
+		}
+		if groupOf(partition, from) != groupOf(partition, to) {
+			return true
+		}
+	}
+	return false
+}
+
+func groupOf(partition Partition, peer string) int {
+	for i, group := range partition.Groups {
+		for _, member := range group {
+			if member == peer {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+func absMillis(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package chaos provides seeded failure injection for the simulator and, in
+// binaries built with the chaos tag, for the real transport and persistence
+// layers.
+package chaos
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Plan describes the faults to inject. It is loaded from JSON and, together
+// with Seed, fully determines every injection decision.
+type Plan struct {
+	Seed       int64         `json:"seed"`
+	Messages   MessageFaults `json:"messages"`
+	Crashes    []CrashEvent  `json:"crashes,omitempty"`
+	Disk       DiskFaults    `json:"disk"`
+	ClockSkew  []ClockSkew   `json:"clock_skew,omitempty"`
+	Partitions []Partition   `json:"partitions,omitempty"`
+	Churn      []ChurnEvent  `json:"churn,omitempty"`
+}
+
+// MessageFaults are per-message probabilities in [0,1].
+type MessageFaults struct {
+	Drop       float64 `json:"drop"`
+	Duplicate  float64 `json:"duplicate"`
+	Delay      float64 `json:"delay"`
+	MaxDelayMs int     `json:"max_delay_ms"`
+	Reorder    float64 `json:"reorder"`
+}
+
+// CrashEvent takes a peer down at a round boundary and optionally restarts
+// it at a later one. RestartRound 0 means the peer stays down.
+type CrashEvent struct {
+	Peer         string `json:"peer"`
+	Round        int    `json:"round"`
+	RestartRound int    `json:"restart_round,omitempty"`
+}
+
+// DiskFaults configures write failures for persistence layers.
+type DiskFaults struct {
+	WriteFailure float64 `json:"write_failure"`
+}
+
+// ClockSkew offsets a peer's clock.
+type ClockSkew struct {
+	Peer     string `json:"peer"`
+	OffsetMs int64  `json:"offset_ms"`
+}
+
+// Partition splits peers into groups that cannot reach each other for
+// rounds [StartRound, EndRound]. Peers not listed belong to the first group.
+type Partition struct {
+	StartRound int        `json:"start_round"`
+	EndRound   int        `json:"end_round"`
+	Groups     [][]string `json:"groups"`
+}
+
+// ChurnEvent removes and admits peers at a round boundary.
+type ChurnEvent struct {
+	Round int      `json:"round"`
+	Leave []string `json:"leave,omitempty"`
+	Join  []string `json:"join,omitempty"`
+}
+
+// LoadPlan reads and validates a JSON plan file.
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-supplied chaos plan path
+	if err != nil {
+		return nil, fmt.Errorf("read chaos plan: %w", err)
+	}
+	return ParsePlan(data)
+}
+
+// ParsePlan decodes and validates a JSON plan.
+func ParsePlan(data []byte) (*Plan, error) {
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("decode chaos plan: %w", err)
+	}
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// Validate checks probabilities and round ranges.
+func (p *Plan) Validate() error {
+	probabilities := map[string]float64{
+		"messages.drop":      p.Messages.Drop,
+		"messages.duplicate": p.Messages.Duplicate,
+		"messages.delay":     p.Messages.Delay,
+		"messages.reorder":   p.Messages.Reorder,
+		"disk.write_failure": p.Disk.WriteFailure,
+	}
+	for name, value := range probabilities {
+		if value < 0 || value > 1 {
+			return fmt.Errorf("chaos plan: %s must be in [0,1], got %f", name, value)
+		}
+	}
+	if p.Messages.MaxDelayMs < 0 {
+		return fmt.Errorf("chaos plan: messages.max_delay_ms must not be negative")
+	}
+	for _, crash := range p.Crashes {
+		if crash.Peer == "" || crash.Round <= 0 {
+			return fmt.Errorf("chaos plan: crash needs a peer and a positive round")
+		}
+		if crash.RestartRound != 0 && crash.RestartRound <= crash.Round {
+			return fmt.Errorf("chaos plan: crash of %s restarts at round %d, not after %d", crash.Peer, crash.RestartRound, crash.Round)
+		}
+	}
+	for _, partition := range p.Partitions {
+		if partition.StartRound <= 0 || partition.EndRound < partition.StartRound {
+			return fmt.Errorf("chaos plan: partition rounds [%d,%d] are invalid", partition.StartRound, partition.EndRound)
+		}
+		if len(partition.Groups) < 2 {
+			return fmt.Errorf("chaos plan: partition needs at least two groups")
+		}
+	}
+	for _, churn := range p.Churn {
+		if churn.Round <= 0 {
+			return fmt.Errorf("chaos plan: churn round must be positive")
+		}
+	}
+	return nil
+}
@@ -5,13 +5,25 @@ package consensus
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/chaos"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/profiling"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
 )
 
+// aggregationBlockSize is how many model coordinates are summed between
+// cancellation checks, so very large models stop promptly on shutdown.
+const aggregationBlockSize = 1 << 16
+
 // DistributedAggregator coordinates model aggregation across nodes with consensus.
 type DistributedAggregator struct {
 	mu          sync.RWMutex
@@ -21,9 +33,23 @@ type DistributedAggregator struct {
 	models      map[string]modelSubmission
 	roundNumber int
 	aggregated  []byte
+	manifest    *protocol.ContributionManifest
 	metrics     *AggregationMetrics
 	asyncMode   bool
 	maxStaleAge time.Duration
+	// arrivals is signalled on every submission so a budgeted round can
+	// stop waiting as soon as every expected model is in.
+	arrivals chan struct{}
+	// activeBudget is the budget of the round in progress, if it has one.
+	activeBudget *scheduler.RoundBudget
+	// history holds the newest historySize rounds, oldest first.
+	history       []AggregationRound
+	historySize   int
+	roundObserver func(AggregationRound)
+	collector     *monitoring.Collector
+	retry         RetryPolicy
+	batch         *batch.Aggregator
+	reviewer      PeerReviewer
 }
 
 type modelSubmission struct {
@@ -40,6 +66,11 @@ type AggregationMetrics struct {
 	LastRoundTime    time.Time
 	StaleDrops       int
 	AsyncRounds      int
+	// RetriedAttempts counts attempts made after a round's first failed.
+	RetriedAttempts int
+	// SupersededRounds counts attempts abandoned because their epoch was
+	// sealed before they committed; they are not FailedRounds.
+	SupersededRounds int
 }
 
 // NewDistributedAggregator creates a new distributed aggregator.
@@ -58,9 +89,43 @@ func NewDistributedAggregator(nodeID string, peerNodes []string, timeout time.Du
 		metrics:     &AggregationMetrics{},
 		asyncMode:   false,
 		maxStaleAge: timeout,
+		arrivals:    make(chan struct{}, 1),
+		historySize: DefaultAggregationHistory,
 	}
 }
 
+// SetCollector records each round's duration and participant count into
+// collector.
+func (da *DistributedAggregator) SetCollector(collector *monitoring.Collector) {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	da.collector = collector
+}
+
+// PeerReviewer decides a peer's vote on a proposal: whether it approves,
+// and, when it rejects, the updates in the proposal's manifest it flags,
+// by receipt ID, with the reason for each.
+type PeerReviewer func(peerID string, proposal *ModelProposal) (approve bool, flags map[string]reasons.Code)
+
+// SetPeerReviewer has each peer's vote decided by review. nil has every
+// peer approve.
+func (da *DistributedAggregator) SetPeerReviewer(review PeerReviewer) {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	da.reviewer = review
+}
+
+// SetBatchAggregator aggregates submissions, in the batch.Update encoding,
+// through agg: each counts as one sample, and the exclusions a rejected
+// proposal's outcome carries are passed to agg for the round's retry, so
+// down-weighted submissions count for less. Without one, submissions are
+// averaged bytewise and down-weighting is ignored.
+func (da *DistributedAggregator) SetBatchAggregator(agg *batch.Aggregator) {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	da.batch = agg
+}
+
 // EnableAsyncMode allows commits to progress with lower vote requirements while
 // filtering stale model submissions.
 func (da *DistributedAggregator) EnableAsyncMode(minVotes int, maxStaleAge time.Duration) {
@@ -92,24 +157,112 @@ func (da *DistributedAggregator) SubmitModel(ctx context.Context, nodeID string,
 		weights:   append([]byte(nil), modelWeights...),
 		submitted: time.Now(),
 	}
+	select {
+	case da.arrivals <- struct{}{}:
+	default:
+	}
 	return nil
 }
 
-// AggregateWithConsensus performs model aggregation with distributed consensus.
+// AggregateWithConsensus performs model aggregation with distributed
+// consensus, retrying a round that misses quorum or times out as the retry
+// policy allows; see SetRetryPolicy. A retry of a proposal whose rejecting
+// voters flagged updates aggregates without them, and the committed
+// round's manifest records why each was left out.
 func (da *DistributedAggregator) AggregateWithConsensus(ctx context.Context) ([]byte, error) {
-	startTime := time.Now()
+	return da.aggregateWithRetry(ctx)
+}
+
+// AggregateWithBudget runs a round within budget. Aggregation waits for
+// submissions until every peer has submitted or its phase budget runs out,
+// then proceeds with the models received so far; consensus runs within its
+// own phase budget, so a slow aggregation cannot starve voting. Callers
+// verify submissions within budget.DeadlineFor(scheduler.PhaseVerification)
+// before calling. A budgeted round is never retried: once its budget is
+// spent there is no time left to retry in.
+func (da *DistributedAggregator) AggregateWithBudget(budget *scheduler.RoundBudget) ([]byte, error) {
+	return da.attemptRound(budget.Context(), budget, da.nextRound(), 1, &roundAggregate{})
+}
 
+// nextRound advances and returns the round number.
+func (da *DistributedAggregator) nextRound() int {
 	da.mu.Lock()
+	defer da.mu.Unlock()
 	da.roundNumber++
-	currentRound := da.roundNumber
+	return da.roundNumber
+}
+
+// roundAggregate carries a round's aggregate between its attempts, with
+// the proposal it was put to vote in and the exclusions it was aggregated
+// under.
+type roundAggregate struct {
+	weights    []byte
+	manifest   *protocol.ContributionManifest
+	proposalID string
+	exclusions batch.Exclusions
+}
+
+// attemptRound makes one attempt at currentRound. It aggregates the
+// submissions into prior unless prior already holds an aggregate, in which
+// case the attempt re-proposes that.
+func (da *DistributedAggregator) attemptRound(ctx context.Context, budget *scheduler.RoundBudget, currentRound, attempt int, prior *roundAggregate) (_ []byte, err error) {
+	startTime := time.Now()
+	prior.proposalID = ""
+
+	da.mu.Lock()
+	da.activeBudget = budget
 	da.mu.Unlock()
+	record := AggregationRound{Round: currentRound, Attempt: attempt, StartedAt: startTime}
+	defer func() { da.recordRound(record, err) }()
+	defer func() {
+		if err != nil {
+			da.recordFailedRound(err)
+		}
+	}()
+	defer func() {
+		da.mu.Lock()
+		da.activeBudget = nil
+		da.mu.Unlock()
+	}()
+
+	// A round abandoned on cancellation must not leave its proposal open,
+	// otherwise the coordinator refuses the next round. A budgeted round is
+	// over when it returns, so it never leaves its proposal open.
+	committed := false
+	defer func() {
+		if !committed && (ctx.Err() != nil || budget != nil) {
+			da.coordinator.Reset()
+		}
+	}()
 
 	// Step 1: Aggregate local models.
-	aggregated, err := da.aggregateModels()
+	aggregationDone := func() {}
+	if budget != nil {
+		var waitCtx context.Context
+		waitCtx, aggregationDone = budget.DeadlineFor(scheduler.PhaseAggregation)
+		da.awaitSubmissions(waitCtx)
+	}
+	aggregated, manifest := prior.weights, prior.manifest
+	if aggregated == nil {
+		aggregated, manifest, err = da.aggregateModels(ctx, currentRound, prior.exclusions)
+	}
+	aggregationDone()
 	if err != nil {
-		da.recordFailedRound()
 		return nil, fmt.Errorf("aggregation failed: %w", err)
 	}
+	prior.weights, prior.manifest = aggregated, manifest
+	for _, entry := range manifest.Entries {
+		if entry.Included {
+			record.Participants++
+		} else {
+			record.DetectedFaults++
+		}
+	}
+	if budget != nil {
+		var done context.CancelFunc
+		ctx, done = budget.DeadlineFor(scheduler.PhaseConsensus)
+		defer done()
+	}
 
 	// Step 2: Create proposal.
 	proposal := &ModelProposal{
@@ -119,23 +272,28 @@ func (da *DistributedAggregator) AggregateWithConsensus(ctx context.Context) ([]
 		Proof:      da.generateProof(aggregated),
 		Timestamp:  time.Now(),
 	}
+	proposal.AttachManifest(manifest)
 
 	// Step 3: Submit proposal to consensus.
 	proposalID, err := da.coordinator.ProposeModel(ctx, proposal)
 	if err != nil {
-		da.recordFailedRound()
 		return nil, fmt.Errorf("proposal failed: %w", err)
 	}
+	prior.proposalID = proposalID
+	record.ConsensusRounds = 1
 
-	if err := da.castSelfVote(ctx, proposalID); err != nil {
-		da.recordFailedRound()
+	// Under commit-reveal the votes below are sealed, and revealed together
+	// once the coordinator opens the reveal phase.
+	var sealed []sealedVote
+	if err := da.castSelfVote(ctx, proposalID, &sealed); err != nil {
 		return nil, fmt.Errorf("self vote failed: %w", err)
 	}
 
 	// Step 4: Collect votes from peers unless async mode is enabled.
 	if !da.isAsyncMode() {
-		if err := da.collectVotes(ctx, proposalID); err != nil {
-			da.recordFailedRound()
+		dropped, err := da.collectVotes(ctx, proposal, proposalID, &sealed)
+		record.DetectedFaults += dropped
+		if err != nil {
 			return nil, fmt.Errorf("vote collection failed: %w", err)
 		}
 	} else {
@@ -143,29 +301,33 @@ func (da *DistributedAggregator) AggregateWithConsensus(ctx context.Context) ([]
 		da.metrics.AsyncRounds++
 		da.mu.Unlock()
 	}
+	if err := da.revealVotes(ctx, proposalID, sealed); err != nil {
+		return nil, fmt.Errorf("vote reveal failed: %w", err)
+	}
 
 	// Step 5: Check consensus.
 	consensusReached, err := da.coordinator.CheckConsensus(proposalID)
 	if err != nil {
-		da.recordFailedRound()
 		return nil, fmt.Errorf("consensus check failed: %w", err)
 	}
 
 	if !consensusReached {
-		da.recordFailedRound()
-		return nil, fmt.Errorf("consensus not reached for round %d", currentRound)
+		got, need, _ := da.coordinator.QuorumProgress(proposalID)
+		return nil, fmt.Errorf("round %d: %w", currentRound, &ErrQuorumNotReached{Got: got, Need: need})
 	}
 
 	// Step 6: Commit the aggregated model.
 	if err := da.coordinator.CommitModel(ctx, proposalID); err != nil {
-		da.recordFailedRound()
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
 
+	committed = true
+
 	// Update metrics.
 	da.mu.Lock()
 	latency := time.Since(startTime)
 	da.aggregated = append([]byte(nil), aggregated...)
+	da.manifest = manifest
 	da.metrics.SuccessfulRounds++
 	da.metrics.TotalRounds++
 	da.metrics.LastRoundTime = time.Now()
@@ -183,10 +345,34 @@ func (da *DistributedAggregator) AggregateWithConsensus(ctx context.Context) ([]
 	return append([]byte(nil), aggregated...), nil
 }
 
-// aggregateModels performs weighted average aggregation.
-func (da *DistributedAggregator) aggregateModels() ([]byte, error) {
+// awaitSubmissions blocks until every peer and this node have submitted a
+// model or ctx is done.
+func (da *DistributedAggregator) awaitSubmissions(ctx context.Context) {
+	expected := len(da.peerNodes) + 1
+	for {
+		da.mu.RLock()
+		received := len(da.models)
+		da.mu.RUnlock()
+		if received >= expected {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-da.arrivals:
+		}
+	}
+}
+
+// aggregateModels performs weighted average aggregation and records each
+// submission's treatment in a contribution manifest. Submissions exclusions
+// excludes are left out under the reason it gives; see SetBatchAggregator
+// for how the rest are weighed.
+func (da *DistributedAggregator) aggregateModels(ctx context.Context, round int, exclusions batch.Exclusions) ([]byte, *protocol.ContributionManifest, error) {
+	defer profiling.ObserveSince(profiling.PhaseAggregation, round, time.Now())
 	da.mu.RLock()
 	maxStaleAge := da.maxStaleAge
+	batchAggregator := da.batch
 	models := make(map[string]modelSubmission, len(da.models))
 	for nodeID, model := range da.models {
 		models[nodeID] = model
@@ -194,18 +380,36 @@ func (da *DistributedAggregator) aggregateModels() ([]byte, error) {
 	da.mu.RUnlock()
 
 	if len(models) == 0 {
-		return nil, fmt.Errorf("no models to aggregate")
+		return nil, nil, ErrNoModels
+	}
+	if batchAggregator != nil {
+		return da.aggregateBatch(ctx, batchAggregator, round, models, maxStaleAge, exclusions)
 	}
 
 	var aggregated []byte
 	now := time.Now()
 	validModels := 0
+	manifest := &protocol.ContributionManifest{
+		Round:   round,
+		Entries: make([]protocol.ContributionEntry, 0, len(models)),
+	}
 
-	for _, model := range models {
+	for nodeID, model := range models {
+		entry := protocol.ContributionEntry{
+			NodeID:       nodeID,
+			UpdateDigest: protocol.UpdateDigest(model.weights),
+		}
 		if maxStaleAge > 0 && now.Sub(model.submitted) > maxStaleAge {
 			da.mu.Lock()
 			da.metrics.StaleDrops++
 			da.mu.Unlock()
+			entry.Reason = protocol.ReasonStaleUpdate
+			manifest.Entries = append(manifest.Entries, entry)
+			continue
+		}
+		if reason, excluded := exclusions.Excluded[batch.ReceiptID(round, entry)]; excluded {
+			entry.Reason = reason
+			manifest.Entries = append(manifest.Entries, entry)
 			continue
 		}
 
@@ -213,35 +417,113 @@ func (da *DistributedAggregator) aggregateModels() ([]byte, error) {
 			aggregated = make([]byte, len(model.weights))
 		}
 		if len(model.weights) != len(aggregated) {
-			return nil, fmt.Errorf("inconsistent model size: expected %d, got %d", len(aggregated), len(model.weights))
+			return nil, nil, fmt.Errorf("%w: expected %d, got %d", ErrShapeMismatch, len(aggregated), len(model.weights))
 		}
 
-		for i := range model.weights {
-			aggregated[i] += model.weights[i]
+		for start := 0; start < len(model.weights); start += aggregationBlockSize {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+			end := start + aggregationBlockSize
+			if end > len(model.weights) {
+				end = len(model.weights)
+			}
+			for i := start; i < end; i++ {
+				aggregated[i] += model.weights[i]
+			}
 		}
 		validModels++
+		entry.Included = true
+		entry.Reason = protocol.ReasonIncluded
+		manifest.Entries = append(manifest.Entries, entry)
 	}
 
 	if validModels == 0 {
-		return nil, fmt.Errorf("all candidate models were stale")
+		return nil, nil, ErrAllModelsStale
 	}
 
 	for i := range aggregated {
 		aggregated[i] /= byte(validModels)
 	}
+	for i := range manifest.Entries {
+		if manifest.Entries[i].Included {
+			manifest.Entries[i].AppliedWeight = 1 / float64(validModels)
+		}
+	}
 
-	return aggregated, nil
+	return aggregated, manifest, nil
+}
+
+// aggregateBatch aggregates the fresh submissions through agg under
+// exclusions and adds the stale ones to its manifest.
+func (da *DistributedAggregator) aggregateBatch(ctx context.Context, agg *batch.Aggregator, round int, models map[string]modelSubmission, maxStaleAge time.Duration, exclusions batch.Exclusions) ([]byte, *protocol.ContributionManifest, error) {
+	nodeIDs := make([]string, 0, len(models))
+	for nodeID := range models {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	now := time.Now()
+	updates := make([]batch.Update, 0, len(models))
+	var stale []protocol.ContributionEntry
+	for _, nodeID := range nodeIDs {
+		model := models[nodeID]
+		if maxStaleAge > 0 && now.Sub(model.submitted) > maxStaleAge {
+			stale = append(stale, protocol.ContributionEntry{
+				NodeID:       nodeID,
+				UpdateDigest: protocol.UpdateDigest(model.weights),
+				Reason:       protocol.ReasonStaleUpdate,
+			})
+			continue
+		}
+		weights, err := batch.DecodeWeights(model.weights)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: node %s: %v", ErrShapeMismatch, nodeID, err)
+		}
+		updates = append(updates, batch.Update{NodeID: nodeID, Weights: weights, SampleCount: 1})
+	}
+	if len(updates) == 0 {
+		return nil, nil, ErrAllModelsStale
+	}
+	if len(stale) > 0 {
+		da.mu.Lock()
+		da.metrics.StaleDrops += len(stale)
+		da.mu.Unlock()
+	}
+
+	result, err := agg.AggregateExcluding(ctx, round, updates, exclusions)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Manifest.Entries = append(result.Manifest.Entries, stale...)
+	return batch.Update{Weights: result.Weights}.Bytes(), result.Manifest, nil
 }
 
 // generateProof creates a cryptographic proof of the aggregation.
 func (da *DistributedAggregator) generateProof(aggregated []byte) []byte {
-	hash := sha256.Sum256(aggregated)
-	return []byte(hex.EncodeToString(hash[:]))
+	return []byte(protocol.WeightsDigest(aggregated))
 }
 
-// collectVotes simulates collecting votes from peer nodes.
-func (da *DistributedAggregator) collectVotes(ctx context.Context, proposalID string) error {
+// collectVotes simulates collecting votes from peer nodes, each reviewing
+// proposal with the peer reviewer if one is set. It returns how many
+// peers' votes were lost.
+func (da *DistributedAggregator) collectVotes(ctx context.Context, proposal *ModelProposal, proposalID string, sealed *[]sealedVote) (int, error) {
+	da.mu.RLock()
+	reviewer := da.reviewer
+	da.mu.RUnlock()
+	dropped := 0
 	for _, peerID := range da.peerNodes {
+		select {
+		case <-ctx.Done():
+			return dropped, ctx.Err()
+		default:
+		}
+
+		if inj := chaos.Active(); inj != nil && inj.MessageFault(peerID, da.nodeID).Drop {
+			dropped++
+			continue
+		}
+
 		vote := &Vote{
 			NodeID:     peerID,
 			ProposalID: proposalID,
@@ -249,14 +531,17 @@ func (da *DistributedAggregator) collectVotes(ctx context.Context, proposalID st
 			Signature:  []byte("signature-" + peerID),
 			Timestamp:  time.Now(),
 		}
-		if err := da.coordinator.CastVote(ctx, vote); err != nil {
-			return err
+		if reviewer != nil {
+			vote.Approve, vote.Flags = reviewer(peerID, proposal)
+		}
+		if err := da.castVote(ctx, vote, sealed); err != nil {
+			return dropped, err
 		}
 	}
-	return nil
+	return dropped, nil
 }
 
-func (da *DistributedAggregator) castSelfVote(ctx context.Context, proposalID string) error {
+func (da *DistributedAggregator) castSelfVote(ctx context.Context, proposalID string, sealed *[]sealedVote) error {
 	vote := &Vote{
 		NodeID:     da.nodeID,
 		ProposalID: proposalID,
@@ -264,7 +549,48 @@ func (da *DistributedAggregator) castSelfVote(ctx context.Context, proposalID st
 		Signature:  []byte("signature-" + da.nodeID),
 		Timestamp:  time.Now(),
 	}
-	return da.coordinator.CastVote(ctx, vote)
+	return da.castVote(ctx, vote, sealed)
+}
+
+// sealedVote is a vote committed to under commit-reveal and the salt that
+// opens it.
+type sealedVote struct {
+	vote *Vote
+	salt []byte
+}
+
+// castVote casts vote, or on a commit-reveal proposal commits to it and
+// keeps it in sealed for revealVotes.
+func (da *DistributedAggregator) castVote(ctx context.Context, vote *Vote, sealed *[]sealedVote) error {
+	if !da.coordinator.CommitRevealEnabled(vote.ProposalID) {
+		return da.coordinator.CastVote(ctx, vote)
+	}
+	salt, err := NewVoteSalt()
+	if err != nil {
+		return err
+	}
+	if err := da.coordinator.CommitVote(ctx, SealVote(vote, salt)); err != nil {
+		return err
+	}
+	*sealed = append(*sealed, sealedVote{vote: vote, salt: salt})
+	return nil
+}
+
+// revealVotes waits for proposalID's reveal phase and reveals the sealed
+// votes.
+func (da *DistributedAggregator) revealVotes(ctx context.Context, proposalID string, sealed []sealedVote) error {
+	if len(sealed) == 0 {
+		return nil
+	}
+	if err := da.coordinator.AwaitReveals(ctx, proposalID); err != nil {
+		return err
+	}
+	for _, s := range sealed {
+		if err := da.coordinator.RevealVote(ctx, s.vote, s.salt); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (da *DistributedAggregator) isAsyncMode() bool {
@@ -273,10 +599,17 @@ func (da *DistributedAggregator) isAsyncMode() bool {
 	return da.asyncMode
 }
 
-func (da *DistributedAggregator) recordFailedRound() {
+// recordFailedRound counts an attempt that ended in err. An attempt whose
+// epoch was sealed under it, by a reset elsewhere, did not fail; it is
+// counted as superseded.
+func (da *DistributedAggregator) recordFailedRound(err error) {
 	da.mu.Lock()
 	defer da.mu.Unlock()
-	da.metrics.FailedRounds++
+	if errors.Is(err, ErrEpochSealed) {
+		da.metrics.SupersededRounds++
+	} else {
+		da.metrics.FailedRounds++
+	}
 	da.metrics.TotalRounds++
 	da.metrics.LastRoundTime = time.Now()
 }
@@ -289,6 +622,29 @@ func (da *DistributedAggregator) GetMetrics() *AggregationMetrics {
 	return &copyMetrics
 }
 
+// GetLastManifest returns the contribution manifest of the last committed
+// round, or nil before the first commit.
+func (da *DistributedAggregator) GetLastManifest() *protocol.ContributionManifest {
+	da.mu.RLock()
+	defer da.mu.RUnlock()
+	return da.manifest
+}
+
+// PendingModels returns how many submitted models await aggregation.
+func (da *DistributedAggregator) PendingModels() int {
+	da.mu.RLock()
+	defer da.mu.RUnlock()
+	return len(da.models)
+}
+
+// ActiveBudget returns the budget of the round in progress, or nil when no
+// budgeted round is running.
+func (da *DistributedAggregator) ActiveBudget() *scheduler.RoundBudget {
+	da.mu.RLock()
+	defer da.mu.RUnlock()
+	return da.activeBudget
+}
+
 // GetLastAggregated returns the most recently aggregated model.
 func (da *DistributedAggregator) GetLastAggregated() []byte {
 	da.mu.RLock()
@@ -317,6 +673,8 @@ func (da *DistributedAggregator) GetRuntimeStatus() map[string]interface{} {
 			"average_latency_ms": metricsCopy.AverageLatency.Milliseconds(),
 			"stale_drops":        metricsCopy.StaleDrops,
 			"async_rounds":       metricsCopy.AsyncRounds,
+			"retried_attempts":   metricsCopy.RetriedAttempts,
+			"superseded_rounds":  metricsCopy.SupersededRounds,
 			"last_round_time":    metricsCopy.LastRoundTime,
 		},
 	}
@@ -0,0 +1,387 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// voteSaltBytes is the size of the salts NewVoteSalt draws.
+const voteSaltBytes = 32
+
+// CommitRevealConfig enables commit-reveal voting; see SetCommitReveal.
+type CommitRevealConfig struct {
+	// CommitWindow bounds the commitment phase and RevealWindow the reveal
+	// phase. Zero takes the coordinator's timeout. Both are cut short so
+	// the two phases fit within the deadline of the context the proposal
+	// was made under, such as a round budget's consensus phase.
+	CommitWindow time.Duration
+	RevealWindow time.Duration
+	// Penalize is called, without the coordinator's lock held, for each
+	// node that committed to a vote on a proposal whose reveal phase
+	// opened but never revealed a vote matching its commitment before the
+	// proposal closed.
+	Penalize func(nodeID string)
+}
+
+// VoteCommitment is a node's sealed vote on a proposal: the hash of its
+// vote and a salt it keeps until the reveal phase.
+type VoteCommitment struct {
+	NodeID     string
+	ProposalID string
+	Commitment []byte
+	Timestamp  time.Time
+}
+
+// NewVoteSalt draws a fresh random salt for sealing a vote.
+func NewVoteSalt() ([]byte, error) {
+	salt := make([]byte, voteSaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// SealVote returns the commitment to vote under salt.
+func SealVote(vote *Vote, salt []byte) *VoteCommitment {
+	return &VoteCommitment{
+		NodeID:     vote.NodeID,
+		ProposalID: vote.ProposalID,
+		Commitment: voteCommitment(vote, salt),
+		Timestamp:  time.Now(),
+	}
+}
+
+// voteCommitment is H(vote ‖ salt): SHA-256 over the length-prefixed node
+// ID and proposal ID, the approval byte, the vote's flags, and the salt, so
+// a voter cannot change what it flags after seeing the others' reveals.
+func voteCommitment(vote *Vote, salt []byte) []byte {
+	h := sha256.New()
+	for _, field := range [][]byte{[]byte(vote.NodeID), []byte(vote.ProposalID)} {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+		h.Write(length[:])
+		h.Write(field)
+	}
+	if vote.Approve {
+		h.Write([]byte{1})
+	} else {
+		h.Write([]byte{0})
+	}
+	h.Write(appendFlags(nil, vote.Flags))
+	h.Write(salt)
+	return h.Sum(nil)
+}
+
+// RevealPhase is the phase of a commit-reveal proposal.
+type RevealPhase int
+
+const (
+	// PhaseCommitting accepts commitments; reveals are refused.
+	PhaseCommitting RevealPhase = iota
+	// PhaseRevealing accepts reveals until the reveal deadline.
+	PhaseRevealing
+	// PhaseClosed accepts nothing; the proposal committed or was reset.
+	PhaseClosed
+)
+
+// String returns a stable string form for the reveal phase.
+func (p RevealPhase) String() string {
+	switch p {
+	case PhaseCommitting:
+		return "committing"
+	case PhaseRevealing:
+		return "revealing"
+	case PhaseClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// revealRound is the commit-reveal state of one proposal.
+type revealRound struct {
+	phase          RevealPhase
+	expected       int
+	revealWindow   time.Duration
+	deadline       time.Time
+	revealDeadline time.Time
+	timer          *time.Timer
+	revealing      chan struct{}
+	commitments    map[string][]byte
+	revealed       map[string]bool
+}
+
+// SetCommitReveal makes every later proposal vote by commit-reveal: each
+// voter first submits SealVote's commitment with CommitVote, and once the
+// commitment window closes or every round member has committed, reveals the
+// vote and salt with RevealVote. Only revealed votes matching their
+// commitment reach the vote chain and count toward quorum; CastVote is
+// refused for such proposals. nil returns to plain voting for later
+// proposals.
+func (c *Coordinator) SetCommitReveal(cfg *CommitRevealConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cfg == nil {
+		c.commitReveal = nil
+		return
+	}
+	copied := *cfg
+	c.commitReveal = &copied
+}
+
+// CommitRevealEnabled reports whether proposalID votes by commit-reveal.
+func (c *Coordinator) CommitRevealEnabled(proposalID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.reveals[proposalID] != nil
+}
+
+// RevealPhaseOf returns proposalID's commit-reveal phase.
+func (c *Coordinator) RevealPhaseOf(proposalID string) (RevealPhase, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	round := c.reveals[proposalID]
+	if round == nil {
+		return PhaseClosed, fmt.Errorf("%w: no commit-reveal round for %s", ErrProposalNotFound, proposalID)
+	}
+	return round.phase, nil
+}
+
+// openRevealRoundLocked starts proposalID's commitment phase, fitting both
+// phases within ctx's deadline. Callers must hold c.mu.
+func (c *Coordinator) openRevealRoundLocked(ctx context.Context, proposalID string, expected int) {
+	now := time.Now()
+	commitWindow, revealWindow := c.commitReveal.CommitWindow, c.commitReveal.RevealWindow
+	if commitWindow <= 0 {
+		commitWindow = c.timeout
+	}
+	if revealWindow <= 0 {
+		revealWindow = c.timeout
+	}
+	round := &revealRound{
+		expected:     expected,
+		revealWindow: revealWindow,
+		revealing:    make(chan struct{}),
+		commitments:  make(map[string][]byte),
+		revealed:     make(map[string]bool),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		round.deadline = deadline
+		if total := commitWindow + revealWindow; now.Add(total).After(deadline) {
+			remaining := max(deadline.Sub(now), 0)
+			commitWindow = time.Duration(float64(remaining) * float64(commitWindow) / float64(total))
+		}
+	}
+	round.timer = time.AfterFunc(commitWindow, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.reveals[proposalID] == round {
+			c.startRevealsLocked(round)
+		}
+	})
+	c.reveals[proposalID] = round
+}
+
+// startRevealsLocked moves round into its reveal phase. Callers must hold
+// c.mu.
+func (c *Coordinator) startRevealsLocked(round *revealRound) {
+	if round.phase != PhaseCommitting {
+		return
+	}
+	round.timer.Stop()
+	round.phase = PhaseRevealing
+	round.revealDeadline = time.Now().Add(round.revealWindow)
+	if !round.deadline.IsZero() && round.revealDeadline.After(round.deadline) {
+		round.revealDeadline = round.deadline
+	}
+	close(round.revealing)
+}
+
+// closeRevealRoundLocked closes round and returns the nodes to penalize:
+// those that committed but never revealed once reveals opened. Callers
+// must hold c.mu.
+func closeRevealRoundLocked(round *revealRound) []string {
+	if round.phase == PhaseClosed {
+		return nil
+	}
+	round.timer.Stop()
+	opened := round.phase == PhaseRevealing
+	round.phase = PhaseClosed
+	if !opened {
+		return nil
+	}
+	var unrevealed []string
+	for nodeID := range round.commitments {
+		if !round.revealed[nodeID] {
+			unrevealed = append(unrevealed, nodeID)
+		}
+	}
+	sort.Strings(unrevealed)
+	return unrevealed
+}
+
+// penalizeUnrevealed reports nodes that never revealed to the configured
+// penalty. Callers must not hold c.mu.
+func (c *Coordinator) penalizeUnrevealed(nodeIDs []string) {
+	if len(nodeIDs) == 0 {
+		return
+	}
+	c.mu.RLock()
+	cfg := c.commitReveal
+	c.mu.RUnlock()
+	if cfg == nil || cfg.Penalize == nil {
+		return
+	}
+	for _, nodeID := range nodeIDs {
+		cfg.Penalize(nodeID)
+	}
+}
+
+// CommitVote records a round member's commitment to its vote on a
+// commit-reveal proposal. Commitments are accepted only during the
+// commitment phase, and a node's first commitment stands. A late
+// commitment for a proposal of a sealed epoch is dropped.
+func (c *Coordinator) CommitVote(ctx context.Context, commitment *VoteCommitment) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	if len(commitment.Commitment) != sha256.Size {
+		return fmt.Errorf("%w: commitment of %d bytes", ErrInvalidArgument, len(commitment.Commitment))
+	}
+
+	var sealed *VoteRejection
+	defer func() {
+		if sealed != nil {
+			c.recordRejection(sealed)
+		}
+	}()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sealed = c.sealedRejectionLocked(commitment.ProposalID); sealed != nil {
+		return nil
+	}
+	round, err := c.revealRoundLocked(commitment.ProposalID)
+	if err != nil {
+		return err
+	}
+	if round.phase != PhaseCommitting {
+		return fmt.Errorf("%w: commitments to %s closed", ErrInvalidState, commitment.ProposalID)
+	}
+	if snapshot := c.roundMembership[commitment.ProposalID]; snapshot != nil && snapshot.Closed {
+		if _, member := snapshot.ActiveNodes[commitment.NodeID]; !member {
+			return fmt.Errorf("%w: node %s at epoch %d of proposal %s", ErrNotRoundMember, commitment.NodeID, snapshot.Epoch, commitment.ProposalID)
+		}
+	}
+	if _, exists := round.commitments[commitment.NodeID]; exists {
+		return fmt.Errorf("%w: node %s already committed on proposal %s", ErrDuplicateVote, commitment.NodeID, commitment.ProposalID)
+	}
+	round.commitments[commitment.NodeID] = append([]byte(nil), commitment.Commitment...)
+	if len(round.commitments) >= round.expected {
+		c.startRevealsLocked(round)
+	}
+	return nil
+}
+
+// AwaitReveals blocks until proposalID's reveal phase opens or ctx ends.
+func (c *Coordinator) AwaitReveals(ctx context.Context, proposalID string) error {
+	c.mu.RLock()
+	round, err := c.revealRoundLocked(proposalID)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	select {
+	case <-round.revealing:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RevealVote opens a node's committed vote. The vote must hash with salt to
+// the node's commitment and arrive within the reveal phase; it then runs
+// through the vote chain like any cast vote. A mismatched reveal is
+// refused with ErrRevealMismatch and leaves the node unrevealed. A late
+// reveal for a proposal of a sealed epoch is dropped.
+func (c *Coordinator) RevealVote(ctx context.Context, vote *Vote, salt []byte) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	c.mu.RLock()
+	sealed := c.sealedRejectionLocked(vote.ProposalID)
+	round, err := c.revealRoundLocked(vote.ProposalID)
+	var commitment []byte
+	phase := PhaseClosed
+	if err == nil {
+		phase = round.phase
+		commitment = round.commitments[vote.NodeID]
+		if phase == PhaseRevealing && time.Now().After(round.revealDeadline) {
+			phase = PhaseClosed
+		}
+	}
+	c.mu.RUnlock()
+	switch {
+	case sealed != nil:
+		c.recordRejection(sealed)
+		return nil
+	case err != nil:
+		return err
+	case phase == PhaseCommitting:
+		return fmt.Errorf("%w: reveals for %s have not opened", ErrInvalidState, vote.ProposalID)
+	case phase == PhaseClosed:
+		return fmt.Errorf("%w: reveals for %s closed", ErrInvalidState, vote.ProposalID)
+	}
+	if commitment == nil || subtle.ConstantTimeCompare(commitment, voteCommitment(vote, salt)) != 1 {
+		rejection := &VoteRejection{Middleware: MiddlewareCommitReveal, Reason: ReasonRevealMismatch,
+			Err: fmt.Errorf("%w: node %s on proposal %s", ErrRevealMismatch, vote.NodeID, vote.ProposalID)}
+		c.recordRejection(rejection)
+		return rejection
+	}
+
+	if err := c.handleVote(ctx, vote); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if c.reveals[vote.ProposalID] == round {
+		round.revealed[vote.NodeID] = true
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// revealRoundLocked returns proposalID's commit-reveal state. Callers must
+// hold c.mu.
+func (c *Coordinator) revealRoundLocked(proposalID string) (*revealRound, error) {
+	if _, exists := c.proposals[proposalID]; !exists {
+		return nil, c.missingProposalLocked(proposalID)
+	}
+	round := c.reveals[proposalID]
+	if round == nil {
+		return nil, fmt.Errorf("%w: proposal %s does not vote by commit-reveal", ErrInvalidState, proposalID)
+	}
+	return round, nil
+}
+
+// refuseUnsealedLocked rejects a vote cast directly on a commit-reveal
+// proposal. Callers must hold c.mu.
+func (c *Coordinator) refuseUnsealedLocked(vote *Vote) *VoteRejection {
+	if c.reveals[vote.ProposalID] == nil {
+		return nil
+	}
+	return &VoteRejection{Middleware: MiddlewareCommitReveal, Reason: ReasonNotRevealed,
+		Err: fmt.Errorf("%w: node %s on proposal %s", ErrCommitmentRequired, vote.NodeID, vote.ProposalID)}
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// penaltyLog records the nodes a commit-reveal coordinator penalizes.
+type penaltyLog struct {
+	mu    sync.Mutex
+	nodes []string
+}
+
+func (p *penaltyLog) penalize(nodeID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nodes = append(p.nodes, nodeID)
+}
+
+func (p *penaltyLog) penalized() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.nodes...)
+}
+
+// sealFor commits nodeID's approval of proposalID and returns the vote and
+// salt that reveal it.
+func sealFor(t *testing.T, coord *Coordinator, proposalID, nodeID string) (*Vote, []byte) {
+	t.Helper()
+	vote := &Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}
+	salt, err := NewVoteSalt()
+	if err != nil {
+		t.Fatalf("salt: %v", err)
+	}
+	if err := coord.CommitVote(context.Background(), SealVote(vote, salt)); err != nil {
+		t.Fatalf("commit %s: %v", nodeID, err)
+	}
+	return vote, salt
+}
+
+func TestCommitRevealHappyPath(t *testing.T) {
+	ctx := context.Background()
+	penalties := &penaltyLog{}
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	coord.SetCommitReveal(&CommitRevealConfig{CommitWindow: time.Minute, RevealWindow: time.Minute, Penalize: penalties.penalize})
+	proposalID := proposeForVotes(t, coord)
+
+	direct := &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true, Timestamp: time.Now()}
+	if rejection := rejectionOf(t, coord.CastVote(ctx, direct)); rejection.Reason != ReasonNotRevealed || !errors.Is(rejection, ErrCommitmentRequired) {
+		t.Fatalf("direct vote: got %s (%v)", rejection.Reason, rejection)
+	}
+
+	voters := []string{"node-1", "member-1", "member-2", "member-3"}
+	votes := make([]*Vote, len(voters))
+	salts := make([][]byte, len(voters))
+	for i, nodeID := range voters[:3] {
+		votes[i], salts[i] = sealFor(t, coord, proposalID, nodeID)
+	}
+	if err := coord.RevealVote(ctx, votes[0], salts[0]); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("reveal during the commitment phase: expected ErrInvalidState, got %v", err)
+	}
+	if approvals, _, _ := coord.QuorumProgress(proposalID); approvals != 0 {
+		t.Fatalf("%d approvals counted before any reveal", approvals)
+	}
+
+	// The last expected commitment opens the reveal phase at once.
+	votes[3], salts[3] = sealFor(t, coord, proposalID, voters[3])
+	if phase, _ := coord.RevealPhaseOf(proposalID); phase != PhaseRevealing {
+		t.Fatalf("phase after every commitment = %s, want revealing", phase)
+	}
+	late := &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: false}
+	if err := coord.CommitVote(ctx, SealVote(late, []byte("salt"))); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("commitment after the window: expected ErrInvalidState, got %v", err)
+	}
+	for i := range voters {
+		if err := coord.RevealVote(ctx, votes[i], salts[i]); err != nil {
+			t.Fatalf("reveal %s: %v", voters[i], err)
+		}
+	}
+	if err := coord.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if got := penalties.penalized(); len(got) != 0 {
+		t.Fatalf("penalized %v after every node revealed", got)
+	}
+	if phase, _ := coord.RevealPhaseOf(proposalID); phase != PhaseClosed {
+		t.Fatalf("phase after commit = %s, want closed", phase)
+	}
+}
+
+func TestCommitRevealPenalizesNodesThatDoNotReveal(t *testing.T) {
+	ctx := context.Background()
+	penalties := &penaltyLog{}
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	coord.SetCommitReveal(&CommitRevealConfig{CommitWindow: time.Minute, RevealWindow: time.Minute, Penalize: penalties.penalize})
+	proposalID := proposeForVotes(t, coord)
+
+	voters := []string{"node-1", "member-1", "member-2", "member-3"}
+	votes := make([]*Vote, len(voters))
+	salts := make([][]byte, len(voters))
+	for i, nodeID := range voters {
+		votes[i], salts[i] = sealFor(t, coord, proposalID, nodeID)
+	}
+	// member-3 withholds its reveal; the other three still make quorum.
+	for i := range voters[:3] {
+		if err := coord.RevealVote(ctx, votes[i], salts[i]); err != nil {
+			t.Fatalf("reveal %s: %v", voters[i], err)
+		}
+	}
+	if err := coord.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if got := penalties.penalized(); !reflect.DeepEqual(got, []string{"member-3"}) {
+		t.Fatalf("penalized %v, want [member-3]", got)
+	}
+	coord.Reset()
+	if got := penalties.penalized(); len(got) != 1 {
+		t.Fatalf("Reset penalized again: %v", got)
+	}
+}
+
+func TestCommitRevealRejectsMismatchedReveal(t *testing.T) {
+	ctx := context.Background()
+	penalties := &penaltyLog{}
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	coord.SetCommitReveal(&CommitRevealConfig{CommitWindow: 20 * time.Millisecond, RevealWindow: time.Minute, Penalize: penalties.penalize})
+	proposalID := proposeForVotes(t, coord)
+
+	proposer, proposerSalt := sealFor(t, coord, proposalID, "node-1")
+	member, memberSalt := sealFor(t, coord, proposalID, "member-1")
+	swing, swingSalt := sealFor(t, coord, proposalID, "member-2")
+	// member-3 never commits, so reveals open when the window closes.
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := coord.AwaitReveals(waitCtx, proposalID); err != nil {
+		t.Fatalf("await reveals: %v", err)
+	}
+
+	for _, sealed := range []struct {
+		vote *Vote
+		salt []byte
+	}{{proposer, proposerSalt}, {member, memberSalt}} {
+		if err := coord.RevealVote(ctx, sealed.vote, sealed.salt); err != nil {
+			t.Fatalf("reveal %s: %v", sealed.vote.NodeID, err)
+		}
+	}
+	// member-2 committed to approve but tries to reveal a rejection, as a
+	// node swinging its vote after seeing the others would.
+	swung := *swing
+	swung.Approve = false
+	if rejection := rejectionOf(t, coord.RevealVote(ctx, &swung, swingSalt)); rejection.Reason != ReasonRevealMismatch || !errors.Is(rejection, ErrRevealMismatch) {
+		t.Fatalf("mismatched reveal: got %s (%v)", rejection.Reason, rejection)
+	}
+	// Nor can it add flags it did not commit to.
+	reflagged := *swing
+	reflagged.Flags = map[string]reasons.Code{"receipt-honest": reasons.ByzantineDetected}
+	if rejection := rejectionOf(t, coord.RevealVote(ctx, &reflagged, swingSalt)); rejection.Reason != ReasonRevealMismatch {
+		t.Fatalf("reflagged reveal: got %s (%v)", rejection.Reason, rejection)
+	}
+	if err := coord.RevealVote(ctx, &Vote{NodeID: "member-3", ProposalID: proposalID, Approve: true}, []byte("salt")); !errors.Is(err, ErrRevealMismatch) {
+		t.Fatalf("reveal without a commitment: expected ErrRevealMismatch, got %v", err)
+	}
+
+	var quorumErr *ErrQuorumNotReached
+	if err := coord.CommitModel(ctx, proposalID); !errors.As(err, &quorumErr) || quorumErr.Got != 2 {
+		t.Fatalf("expected quorum short with 2 revealed approvals, got %v", err)
+	}
+	if got := penalties.penalized(); !reflect.DeepEqual(got, []string{"member-2"}) {
+		t.Fatalf("penalized %v, want [member-2]", got)
+	}
+	counts := coord.VoteRejections()
+	if len(counts) != 1 || counts[0].Middleware != MiddlewareCommitReveal || counts[0].Count != 3 {
+		t.Fatalf("rejection counts = %+v", counts)
+	}
+}
+
+func TestCommitRevealWindowsFitTheRoundBudget(t *testing.T) {
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	coord.SetCommitReveal(&CommitRevealConfig{CommitWindow: time.Minute, RevealWindow: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	proposalID, err := coord.ProposeModel(ctx, &ModelProposal{Round: 1, Weights: []byte("w"), ProposerID: "node-1", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	// Half the budget goes to commitments, so reveals open well before the
+	// configured minute.
+	if err := coord.AwaitReveals(ctx, proposalID); err != nil {
+		t.Fatalf("reveals did not open within the budget: %v", err)
+	}
+	<-ctx.Done()
+	vote := &Vote{NodeID: "node-1", ProposalID: proposalID, Approve: true}
+	if err := coord.RevealVote(context.Background(), vote, nil); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("reveal after the budget: expected ErrInvalidState, got %v", err)
+	}
+}
+
+func TestAggregatorCommitsThroughCommitReveal(t *testing.T) {
+	ctx := context.Background()
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1", "peer2"}, 5*time.Second)
+	aggregator.coordinator.SetCommitReveal(&CommitRevealConfig{CommitWindow: time.Second, RevealWindow: time.Second})
+	for _, nodeID := range []string{"node-1", "peer1", "peer2"} {
+		if err := aggregator.SubmitModel(ctx, nodeID, []byte{3, 6}); err != nil {
+			t.Fatalf("submit %s: %v", nodeID, err)
+		}
+	}
+	if _, err := aggregator.AggregateWithConsensus(ctx); err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if history := aggregator.History(); len(history) != 1 || history[0].Outcome != OutcomeCommitted {
+		t.Fatalf("history = %+v", history)
+	}
+}
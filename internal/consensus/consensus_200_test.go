@@ -66,3 +66,142 @@ func Test200NodeConsensusCommit(t *testing.T) {
 		t.Fatalf("expected committed state, got %v", coord.GetState())
 	}
 }
+
+func nodeRange(count int) []string {
+	nodes := make([]string, count)
+	for i := range nodes {
+		nodes[i] = fmt.Sprintf("node-%03d", i+1)
+	}
+	return nodes
+}
+
+func TestMembershipViewQuorumFollowsChurn(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-001", 200, 5*time.Second)
+	view := NewStaticMembershipView(nodeRange(200))
+	coord.SetMembershipView(view)
+
+	cases := []struct {
+		members        int
+		expectedQuorum int
+	}{
+		{members: 200, expectedQuorum: 134},
+		{members: 120, expectedQuorum: 81},
+		{members: 260, expectedQuorum: 174},
+	}
+
+	for round, tc := range cases {
+		if round > 0 {
+			view.SetMembers(nodeRange(tc.members))
+		}
+		proposalID, err := coord.ProposeModel(ctx, &ModelProposal{
+			Round:      round + 1,
+			Weights:    []byte("weights"),
+			ProposerID: "node-001",
+			Timestamp:  time.Now().Add(time.Duration(round) * time.Second),
+		})
+		if err != nil {
+			t.Fatalf("round %d: propose failed: %v", round+1, err)
+		}
+
+		snapshot, err := coord.GetRoundMembership(proposalID)
+		if err != nil {
+			t.Fatalf("round %d: snapshot lookup failed: %v", round+1, err)
+		}
+		if snapshot.ActiveCount != tc.members || snapshot.QuorumSize != tc.expectedQuorum {
+			t.Fatalf("round %d: expected %d members / quorum %d, got %d / %d",
+				round+1, tc.members, tc.expectedQuorum, snapshot.ActiveCount, snapshot.QuorumSize)
+		}
+
+		for i := 1; i < tc.expectedQuorum; i++ {
+			if err := coord.CastVote(ctx, &Vote{NodeID: fmt.Sprintf("node-%03d", i), ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+				t.Fatalf("round %d: vote %d failed: %v", round+1, i, err)
+			}
+		}
+		if reached, _ := coord.CheckConsensus(proposalID); reached {
+			t.Fatalf("round %d: consensus reached one vote short of quorum", round+1)
+		}
+		if err := coord.CastVote(ctx, &Vote{NodeID: fmt.Sprintf("node-%03d", tc.expectedQuorum), ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("round %d: final vote failed: %v", round+1, err)
+		}
+		if reached, err := coord.CheckConsensus(proposalID); err != nil || !reached {
+			t.Fatalf("round %d: expected consensus at quorum, got %v (%v)", round+1, reached, err)
+		}
+		coord.Reset()
+	}
+}
+
+func TestMembershipViewRejectsLateAdmittedVoters(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-001", 120, 5*time.Second)
+	view := NewStaticMembershipView(nodeRange(120))
+	coord.SetMembershipView(view)
+
+	proposalID, err := coord.ProposeModel(ctx, &ModelProposal{Round: 1, ProposerID: "node-001", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose failed: %v", err)
+	}
+
+	view.SetMembers(nodeRange(200))
+	if err := coord.CastVote(ctx, &Vote{NodeID: "node-150", ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err == nil {
+		t.Fatal("expected vote from node admitted after the snapshot to be rejected")
+	}
+	if err := coord.CastVote(ctx, &Vote{NodeID: "node-050", ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("expected vote from snapshot member to be accepted: %v", err)
+	}
+}
+
+func TestLeaveNodeKeepsClosedSnapshotQuorum(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-001", 120, 5*time.Second)
+	coord.SetMembershipView(NewStaticMembershipView(nodeRange(120)))
+
+	proposalID, err := coord.ProposeModel(ctx, &ModelProposal{Round: 1, ProposerID: "node-001", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose failed: %v", err)
+	}
+	for i := 2; i <= 40; i++ {
+		coord.LeaveNode(fmt.Sprintf("node-%03d", i))
+	}
+
+	snapshot, err := coord.GetRoundMembership(proposalID)
+	if err != nil {
+		t.Fatalf("snapshot lookup failed: %v", err)
+	}
+	if snapshot.ActiveCount != 120 || snapshot.QuorumSize != 81 || !snapshot.ActiveNodes["node-002"] {
+		t.Fatalf("closed snapshot rebalanced on leave: %d members, quorum %d", snapshot.ActiveCount, snapshot.QuorumSize)
+	}
+	for i := 41; i < 121; i++ {
+		if err := coord.CastVote(ctx, &Vote{NodeID: fmt.Sprintf("node-%03d", i), ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote %d failed: %v", i, err)
+		}
+	}
+	if reached, _ := coord.CheckConsensus(proposalID); reached {
+		t.Fatal("consensus reached one vote short of the epoch's quorum")
+	}
+}
+
+type fixedMembershipView struct {
+	membership Membership
+}
+
+func (v *fixedMembershipView) Snapshot() Membership {
+	return v.membership
+}
+
+func TestMembershipViewRejectsEpochRegression(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-001", 10, 5*time.Second)
+	view := &fixedMembershipView{membership: Membership{Epoch: 5, ActiveNodes: nodeRange(10)}}
+	coord.SetMembershipView(view)
+
+	if _, err := coord.ProposeModel(ctx, &ModelProposal{Round: 1, ProposerID: "node-001", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("propose at epoch 5 failed: %v", err)
+	}
+	coord.Reset()
+
+	view.membership = Membership{Epoch: 4, ActiveNodes: nodeRange(4)}
+	if _, err := coord.ProposeModel(ctx, &ModelProposal{Round: 2, ProposerID: "node-001", Timestamp: time.Now()}); err == nil {
+		t.Fatal("expected proposal with regressed membership epoch to fail")
+	}
+}
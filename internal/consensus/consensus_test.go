@@ -5,8 +5,13 @@ package consensus
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
 )
 
 // TestCoordinatorCreation tests coordinator initialization
@@ -130,6 +135,64 @@ func TestByzantineQuorum(t *testing.T) {
 	}
 }
 
+func TestFaultModelDrivesQuorum(t *testing.T) {
+	multiTier := faultmodel.Topology{MultiTier: true}
+	for _, tt := range []struct {
+		totalNodes, classic, hierarchical int
+	}{
+		{3, 3, 2},
+		{10, 7, 6},
+		{100, 67, 56},
+	} {
+		coord := NewCoordinator("test-node", tt.totalNodes, 5*time.Second)
+		if coord.quorumSize != tt.classic || coord.FaultModel() != faultmodel.Classic33 {
+			t.Fatalf("default for %d nodes: %s quorum %d, want classic33 %d", tt.totalNodes, coord.FaultModel(), coord.quorumSize, tt.classic)
+		}
+		if err := coord.SetFaultModel(faultmodel.Hierarchical55, multiTier); err != nil {
+			t.Fatalf("set hierarchical55: %v", err)
+		}
+		if coord.quorumSize != tt.hierarchical {
+			t.Fatalf("hierarchical55 quorum for %d nodes = %d, want %d", tt.totalNodes, coord.quorumSize, tt.hierarchical)
+		}
+		if status := coord.GetRuntimeStatus(); status["fault_model"] != "hierarchical55" {
+			t.Fatalf("status reports fault model %v", status["fault_model"])
+		}
+	}
+
+	// Hierarchical55 is refused on a flat topology, leaving the model as is.
+	coord := NewCoordinator("test-node", 10, 5*time.Second)
+	err := coord.SetFaultModel(faultmodel.Hierarchical55, faultmodel.Topology{})
+	if !errors.Is(err, ErrInvalidArgument) || !errors.Is(err, faultmodel.ErrUnsafeTopology) {
+		t.Fatalf("flat topology: expected ErrUnsafeTopology, got %v", err)
+	}
+	if coord.quorumSize != 7 {
+		t.Fatalf("refused model changed quorum to %d", coord.quorumSize)
+	}
+
+	// Under Hierarchical55, 6 of 10 approvals commit where classic33
+	// needs 7.
+	if err := coord.SetFaultModel(faultmodel.Hierarchical55, multiTier); err != nil {
+		t.Fatalf("set hierarchical55: %v", err)
+	}
+	ctx := context.Background()
+	proposalID, err := coord.ProposeModel(ctx, &ModelProposal{Round: 1, Weights: []byte("w"), ProposerID: "test-node", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	for i := 1; i <= 6; i++ {
+		if reached, _ := coord.CheckConsensus(proposalID); reached {
+			t.Fatalf("consensus reached with %d approvals", i-1)
+		}
+		vote := &Vote{NodeID: "member-" + string(rune('0'+i)), ProposalID: proposalID, Approve: true, Signature: []byte("sig"), Timestamp: time.Now()}
+		if err := coord.CastVote(ctx, vote); err != nil {
+			t.Fatalf("vote %d: %v", i, err)
+		}
+	}
+	if reached, err := coord.CheckConsensus(proposalID); err != nil || !reached {
+		t.Fatalf("6 of 10 approvals under hierarchical55: reached=%v err=%v", reached, err)
+	}
+}
+
 // TestConsensusFailure tests consensus failure scenarios
 func TestConsensusFailure(t *testing.T) {
 	coord := NewCoordinator("node-1", 10, 5*time.Second)
@@ -426,3 +489,155 @@ func TestDistributedAggregatorAsyncDropsStaleModels(t *testing.T) {
 		t.Fatal("expected stale model drop metric to increment")
 	}
 }
+
+func TestTypedErrorsSurviveWrapping(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-1", 10, 5*time.Second)
+
+	proposalID, err := coord.ProposeModel(ctx, &ModelProposal{Round: 1, ProposerID: "node-1", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose failed: %v", err)
+	}
+
+	err = coord.CastVote(ctx, &Vote{NodeID: "node-2", ProposalID: "missing", Approve: true})
+	if !errors.Is(err, ErrProposalNotFound) || Retryable(err) {
+		t.Fatalf("expected non-retryable ErrProposalNotFound, got %v", err)
+	}
+
+	if err := coord.CastVote(ctx, &Vote{NodeID: "node-2", ProposalID: proposalID, Approve: true}); err != nil {
+		t.Fatalf("vote failed: %v", err)
+	}
+	err = coord.CommitModel(ctx, proposalID)
+	var quorumErr *ErrQuorumNotReached
+	if !errors.As(err, &quorumErr) {
+		t.Fatalf("expected ErrQuorumNotReached, got %v", err)
+	}
+	if quorumErr.Got != 1 || quorumErr.Need != 7 {
+		t.Fatalf("expected 1 of 7 approvals, got %d of %d", quorumErr.Got, quorumErr.Need)
+	}
+	if !Retryable(err) {
+		t.Fatal("expected quorum shortfall to be retryable")
+	}
+
+	err = coord.CastVote(ctx, &Vote{NodeID: "node-3", ProposalID: proposalID, Approve: true})
+	if !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("expected ErrInvalidState after abort, got %v", err)
+	}
+
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1"}, time.Second)
+	_, err = aggregator.AggregateWithConsensus(ctx)
+	if !errors.Is(err, ErrNoModels) || !Retryable(err) {
+		t.Fatalf("expected retryable ErrNoModels through aggregator wrapping, got %v", err)
+	}
+}
+
+func TestAggregateWithConsensusHonoursCancellation(t *testing.T) {
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1", "peer2"}, 30*time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if err := aggregator.SubmitModel(ctx, "node-1", make([]byte, 4*aggregationBlockSize)); err != nil {
+		t.Fatalf("submit model: %v", err)
+	}
+	cancel()
+
+	if _, err := aggregator.AggregateWithConsensus(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if err := aggregator.coordinator.CastVote(ctx, &Vote{NodeID: "peer1", ProposalID: "p"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected CastVote to observe cancellation, got %v", err)
+	}
+
+	if _, err := aggregator.AggregateWithConsensus(context.Background()); err != nil {
+		t.Fatalf("expected next round to succeed after cancelled round, got %v", err)
+	}
+}
+
+func TestAggregateWithBudgetProceedsWithReceivedModels(t *testing.T) {
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1", "peer2", "peer3"}, 30*time.Second)
+	ctx := context.Background()
+	for _, nodeID := range []string{"node-1", "peer1"} {
+		if err := aggregator.SubmitModel(ctx, nodeID, []byte{2, 4}); err != nil {
+			t.Fatalf("submit %s: %v", nodeID, err)
+		}
+	}
+
+	budget, err := scheduler.NewRoundBudget(ctx, 1, 200*time.Millisecond, scheduler.DefaultBudgetSplit())
+	if err != nil {
+		t.Fatalf("new budget: %v", err)
+	}
+	defer budget.Close()
+
+	// A slow verification phase eats its whole share first.
+	verifyCtx, done := budget.DeadlineFor(scheduler.PhaseVerification)
+	<-verifyCtx.Done()
+	done()
+
+	// peer2 and peer3 never submit, so aggregation waits out its budget
+	// and proceeds with the two models it has.
+	if _, err := aggregator.AggregateWithBudget(budget); err != nil {
+		t.Fatalf("aggregate with budget: %v", err)
+	}
+	if late := time.Since(budget.Deadline()); late > 0 {
+		t.Fatalf("round resolved %s after its deadline", late)
+	}
+	if manifest := aggregator.GetLastManifest(); manifest == nil || len(manifest.Entries) != 2 {
+		t.Fatalf("expected a manifest with the two received models, got %+v", manifest)
+	}
+	reports := budget.Reports()
+	if len(reports) != 3 || reports[1].Phase != scheduler.PhaseAggregation || reports[1].Actual < reports[1].Budget {
+		t.Fatalf("expected aggregation to wait out its budget, got %+v", reports)
+	}
+}
+
+func TestProposalBindsContributionManifest(t *testing.T) {
+	ctx := context.Background()
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1", "peer2"}, 30*time.Second)
+	_ = aggregator.SubmitModel(ctx, "node-1", []byte{2, 4})
+	_ = aggregator.SubmitModel(ctx, "peer1", []byte{4, 6})
+	aggregator.mu.Lock()
+	aggregator.models["peer2"] = modelSubmission{weights: []byte{9, 9}, submitted: time.Now().Add(-time.Hour)}
+	aggregator.mu.Unlock()
+
+	if _, err := aggregator.AggregateWithConsensus(ctx); err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	manifest := aggregator.GetLastManifest()
+	if manifest == nil || manifest.Round != 1 {
+		t.Fatalf("expected manifest for round 1, got %+v", manifest)
+	}
+	included := manifest.IncludedNodes()
+	if len(included) != 2 || included[0] != "node-1" || included[1] != "peer1" {
+		t.Fatalf("expected node-1 and peer1 included, got %v", included)
+	}
+	if entry, _ := manifest.Entry("peer2"); entry.Included || entry.Reason != protocol.ReasonStaleUpdate {
+		t.Fatalf("expected stale peer2 excluded, got %+v", entry)
+	}
+
+	proposal := &ModelProposal{Round: 1, Weights: []byte{3, 5}, ProposerID: "node-1", Timestamp: time.Now()}
+	proposal.AttachManifest(manifest)
+	digest := proposal.Digest()
+	if err := VerifyProposalDigest(proposal, digest); err != nil {
+		t.Fatalf("expected bound manifest to verify: %v", err)
+	}
+
+	tampered := *manifest
+	tampered.Entries = append([]protocol.ContributionEntry(nil), manifest.Entries...)
+	for i := range tampered.Entries {
+		tampered.Entries[i].Included = true
+	}
+	forged := *proposal
+	forged.Manifest = &tampered
+	if err := VerifyProposalDigest(&forged, digest); !errors.Is(err, ErrManifestMismatch) {
+		t.Fatalf("expected ErrManifestMismatch for swapped manifest, got %v", err)
+	}
+	forged.ManifestDigest = tampered.Digest()
+	if err := VerifyProposalDigest(&forged, digest); !errors.Is(err, ErrManifestMismatch) {
+		t.Fatalf("expected proposal digest mismatch after rebinding, got %v", err)
+	}
+
+	coord := NewCoordinator("node-1", 3, time.Second)
+	forged.ManifestDigest = digest
+	if _, err := coord.ProposeModel(ctx, &forged); !errors.Is(err, ErrManifestMismatch) {
+		t.Fatalf("expected ProposeModel to reject unbound manifest, got %v", err)
+	}
+}
@@ -19,20 +19,33 @@ package consensus
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/blockchain"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/profiling"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
 )
 
 // ModelProposal represents a proposed model update for consensus
 type ModelProposal struct {
-	Round      int
-	Weights    []byte
-	ProposerID string
-	Proof      []byte
-	Timestamp  time.Time
+	Round           int
+	Weights         []byte
+	ProposerID      string
+	Proof           []byte
+	Timestamp       time.Time
+	MembershipEpoch uint64
+	// Manifest records which updates fed the proposed weights; its digest is
+	// bound into the proposal digest.
+	Manifest       *protocol.ContributionManifest
+	ManifestDigest string
 }
 
 // Vote represents a node's vote on a proposal
@@ -42,6 +55,24 @@ type Vote struct {
 	Approve    bool
 	Signature  []byte
 	Timestamp  time.Time
+	// MAC, when set, is an HMAC-SHA256 tag under the voter's pairwise key
+	// with the aggregator; see ShardVoteAuth.
+	MAC []byte
+	// Flags names the updates in the proposal's manifest a rejecting voter
+	// found bad, by receipt ID (see batch.ReceiptID), with the reason for
+	// each. A retry of the round leaves out or down-weights them; see
+	// Coordinator.Outcome.
+	Flags map[string]reasons.Code
+	// CommitSignature, on an approval, is the voter's signature over the
+	// commit certificate digest of the proposal it approves (see
+	// modeldist.ApprovalDigest), carried into the certificate so nodes
+	// that know the voter's key can check the commit.
+	CommitSignature []byte
+	// CertificateSignature, on an approval of a regional shard's
+	// proposal by a member of the shard, is the voter's signature over
+	// the proposal's QuorumCertificate, which the shard's aggregator
+	// carries upstream to prove the commit; see package certchain.
+	CertificateSignature []byte
 }
 
 // ConsensusState tracks the current state of consensus
@@ -63,6 +94,7 @@ type ConsensusRound struct {
 	Metrics        map[string]float64
 	CommitTime     time.Time
 	ValidatorVotes []*Vote
+	Manifest       *protocol.ContributionManifest
 }
 
 // RoundMembershipSnapshot captures a per-proposal membership view that can be
@@ -71,6 +103,15 @@ type RoundMembershipSnapshot struct {
 	ActiveNodes map[string]bool
 	ActiveCount int
 	QuorumSize  int
+	Epoch       uint64
+	// Closed snapshots come from a MembershipView and reject votes from
+	// nodes that were not admitted when the proposal was made.
+	Closed bool
+	// Probationary holds the nodes on probation when the proposal was
+	// made. They may vote but are left out of ActiveCount, and their
+	// approvals count ProbationVoteWeight.
+	Probationary        map[string]bool
+	ProbationVoteWeight float64
 }
 
 // Coordinator manages distributed consensus for model aggregation
@@ -90,6 +131,33 @@ type Coordinator struct {
 	asyncMode            bool
 	asyncMinVotes        int
 	maxVoteStaleness     time.Duration
+	membershipView       MembershipView
+	membershipEpoch      uint64
+	replay               []ReplayEntry
+	leaderGate           func() error
+	voteHandler          VoteHandler
+	voteRejections       map[voteRejectionKey]int
+	rejectionObserver    func(middleware, reason string)
+	commitVerifier       func(vote *Vote) error
+	certified            map[string][]string
+	lifecycle            events.Publisher
+	quorumAnnounced      map[string]bool
+	faultModel           faultmodel.Model
+	commitReveal         *CommitRevealConfig
+	reveals              map[string]*revealRound
+	equivocation         *EquivocationDetector
+	probation            *Probation
+	freshness            VoteFreshness
+	voteChanges          map[string][]VoteChange
+	clock                func() time.Time
+	voteTimes            map[string]*voteTimes
+	closedTimelines      map[string]protocol.VoteTimeline
+	quorumRisk           QuorumRisk
+	slowRounds           int
+	timelineObserver     func(protocol.VoteTimeline)
+	collector            *monitoring.Collector
+	epoch                uint64
+	sealed               map[string]uint64
 
 	// Blockchain integration (NEW)
 	blockchain      *blockchain.BlockChain
@@ -104,8 +172,9 @@ type Coordinator struct {
 // NewCoordinator creates a new consensus coordinator
 func NewCoordinator(nodeID string, totalNodes int, timeout time.Duration) *Coordinator {
 	// Byzantine fault tolerance: quorum = 2f + 1 where f is max faulty nodes
-	// For n nodes, f < n/3, so quorum = ⌈(2n/3)⌉
-	quorumSize := (2 * totalNodes / 3) + 1
+	// For n nodes, f < n/3, so quorum = ⌈(2n/3)⌉. SetFaultModel changes
+	// the model.
+	quorumSize := faultmodel.Classic33.Quorum(totalNodes)
 
 	coordinator := &Coordinator{
 		nodeID:               nodeID,
@@ -122,6 +191,16 @@ func NewCoordinator(nodeID string, totalNodes int, timeout time.Duration) *Coord
 		asyncMode:            false,
 		asyncMinVotes:        0,
 		maxVoteStaleness:     timeout * 2,
+		voteRejections:       make(map[voteRejectionKey]int),
+		certified:            make(map[string][]string),
+		quorumAnnounced:      make(map[string]bool),
+		faultModel:           faultmodel.Classic33,
+		reveals:              make(map[string]*revealRound),
+		voteChanges:          make(map[string][]VoteChange),
+		voteTimes:            make(map[string]*voteTimes),
+		closedTimelines:      make(map[string]protocol.VoteTimeline),
+		quorumRisk:           DefaultQuorumRisk(),
+		sealed:               make(map[string]uint64),
 
 		// Initialize blockchain components (NEW)
 		blockchain:      &blockchain.BlockChain{},
@@ -131,6 +210,7 @@ func NewCoordinator(nodeID string, totalNodes int, timeout time.Duration) *Coord
 		roundNumber:     0,
 	}
 
+	coordinator.voteHandler = ChainVotes(coordinator.recordVote, DefaultVoteChain(coordinator)...)
 	coordinator.activeNodes[nodeID] = true
 	for i := 1; i < totalNodes; i++ {
 		coordinator.activeNodes[fmt.Sprintf("member-%d", i)] = true
@@ -139,11 +219,33 @@ func NewCoordinator(nodeID string, totalNodes int, timeout time.Duration) *Coord
 	return coordinator
 }
 
-func quorumForNodes(totalNodes int) int {
-	if totalNodes <= 1 {
-		return 1
+// SetFaultModel derives quorums from model, after checking it is safe under
+// topology. Rounds already open keep their quorum. Classic33 is the
+// default.
+func (c *Coordinator) SetFaultModel(model faultmodel.Model, topology faultmodel.Topology) error {
+	if err := model.Validate(topology); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgument, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.faultModel = model
+	if !c.asyncMode {
+		c.quorumSize = c.quorumForNodes(c.totalNodes)
 	}
-	return (2*totalNodes/3 + 1)
+	return nil
+}
+
+// FaultModel returns the model quorums are derived from.
+func (c *Coordinator) FaultModel() faultmodel.Model {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.faultModel
+}
+
+// quorumForNodes is the fault model's quorum of totalNodes. Callers must
+// hold c.mu.
+func (c *Coordinator) quorumForNodes(totalNodes int) int {
+	return c.faultModel.Quorum(totalNodes)
 }
 
 func cloneMembership(src map[string]bool) map[string]bool {
@@ -154,10 +256,12 @@ func cloneMembership(src map[string]bool) map[string]bool {
 	return cloned
 }
 
-func countActiveNodes(nodes map[string]bool) int {
+// countActiveNodes counts the active nodes not in probationary, at least
+// one.
+func countActiveNodes(nodes map[string]bool, probationary map[string]bool) int {
 	count := 0
-	for _, active := range nodes {
-		if active {
+	for nodeID, active := range nodes {
+		if active && !probationary[nodeID] {
 			count++
 		}
 	}
@@ -184,6 +288,43 @@ func (c *Coordinator) SetAsyncMode(enabled bool, minVotes int, maxStaleness time
 	}
 }
 
+// NodeID returns the ID of the node the coordinator runs on.
+func (c *Coordinator) NodeID() string {
+	return c.nodeID
+}
+
+// SetMembershipView makes the coordinator derive each proposal's quorum from
+// the admitted membership at proposal time instead of the static node count.
+func (c *Coordinator) SetMembershipView(view MembershipView) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.membershipView = view
+}
+
+// SetLeaderGate installs a check run before every proposal and commit. A
+// non-nil error from gate refuses the call with ErrNotLeader; failover uses
+// it to keep a standby passive and to fence a superseded primary.
+func (c *Coordinator) SetLeaderGate(gate func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.leaderGate = gate
+}
+
+// checkLeaderGate runs the leader gate without holding c.mu, so the gate may
+// take its own locks.
+func (c *Coordinator) checkLeaderGate() error {
+	c.mu.RLock()
+	gate := c.leaderGate
+	c.mu.RUnlock()
+	if gate == nil {
+		return nil
+	}
+	if err := gate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrNotLeader, err)
+	}
+	return nil
+}
+
 // JoinNode marks a node as active for subsequent rounds.
 func (c *Coordinator) JoinNode(nodeID string) {
 	c.mu.Lock()
@@ -192,13 +333,12 @@ func (c *Coordinator) JoinNode(nodeID string) {
 		return
 	}
 	c.activeNodes[nodeID] = true
-	c.totalNodes = countActiveNodes(c.activeNodes)
-	if !c.asyncMode {
-		c.quorumSize = quorumForNodes(c.totalNodes)
-	}
+	c.recountLocked()
 }
 
 // LeaveNode marks a node inactive and rebalances open rounds in-place.
+// Rounds whose snapshot a MembershipView closed keep the quorum of the
+// epoch they were proposed in.
 func (c *Coordinator) LeaveNode(nodeID string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -210,20 +350,17 @@ func (c *Coordinator) LeaveNode(nodeID string) {
 	}
 
 	for _, snapshot := range c.roundMembership {
-		if snapshot == nil {
+		if snapshot == nil || snapshot.Closed {
 			continue
 		}
 		if active, exists := snapshot.ActiveNodes[nodeID]; exists && active {
 			snapshot.ActiveNodes[nodeID] = false
-			snapshot.ActiveCount = countActiveNodes(snapshot.ActiveNodes)
-			snapshot.QuorumSize = quorumForNodes(snapshot.ActiveCount)
+			snapshot.ActiveCount = countActiveNodes(snapshot.ActiveNodes, snapshot.Probationary)
+			snapshot.QuorumSize = c.quorumForNodes(snapshot.ActiveCount)
 		}
 	}
 
-	c.totalNodes = countActiveNodes(c.activeNodes)
-	if !c.asyncMode {
-		c.quorumSize = quorumForNodes(c.totalNodes)
-	}
+	c.recountLocked()
 }
 
 // SetupBlockchainIntegration configures blockchain components (NEW)
@@ -232,7 +369,7 @@ func (c *Coordinator) SetupBlockchainIntegration(proposer *blockchain.BlockPropo
 	defer c.mu.Unlock()
 
 	if proposer == nil {
-		return fmt.Errorf("block proposer cannot be nil")
+		return fmt.Errorf("%w: block proposer cannot be nil", ErrInvalidArgument)
 	}
 
 	c.blockProposer = proposer
@@ -245,7 +382,7 @@ func (c *Coordinator) SetupContractExecutor(executor *blockchain.SmartContractEx
 	defer c.mu.Unlock()
 
 	if executor == nil {
-		return fmt.Errorf("contract executor cannot be nil")
+		return fmt.Errorf("%w: contract executor cannot be nil", ErrInvalidArgument)
 	}
 
 	c.contractExec = executor
@@ -260,52 +397,216 @@ func (c *Coordinator) RegisterValidator(nodeID string, stake uint64) error {
 	return c.validators.Stake(nodeID, stake)
 }
 
-// ProposeModel submits a new model update for consensus
+// ProposeModel submits a new model update for consensus. With a membership
+// view set, the proposer must be among the view's members.
 func (c *Coordinator) ProposeModel(ctx context.Context, proposal *ModelProposal) (string, error) {
+	defer profiling.ObserveSince(profiling.PhaseConsensusPropose, proposal.Round, time.Now())
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+	if err := c.checkLeaderGate(); err != nil {
+		return "", err
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.state != Proposing {
-		return "", fmt.Errorf("cannot propose: current state is %v", c.state)
+		return "", fmt.Errorf("%w: cannot propose in state %v", ErrInvalidState, c.state)
+	}
+	if err := proposal.VerifyManifest(); err != nil {
+		return "", err
+	}
+	if c.onProbationLocked(proposal.ProposerID) {
+		return "", fmt.Errorf("%w: %s cannot propose", ErrProbationaryNode, proposal.ProposerID)
+	}
+
+	var snapshotNodes map[string]bool
+	closed := false
+	if c.membershipView != nil {
+		membership := c.membershipView.Snapshot()
+		if membership.Epoch < c.membershipEpoch {
+			return "", fmt.Errorf("%w: got %d, already saw %d", ErrMembershipRegressed, membership.Epoch, c.membershipEpoch)
+		}
+		c.membershipEpoch = membership.Epoch
+		proposal.MembershipEpoch = membership.Epoch
+
+		snapshotNodes = make(map[string]bool, len(membership.ActiveNodes))
+		for _, nodeID := range membership.ActiveNodes {
+			snapshotNodes[nodeID] = true
+		}
+		// A closed snapshot admits only the view's members; a proposer
+		// outside it must not widen the round's quorum.
+		if !snapshotNodes[proposal.ProposerID] {
+			return "", fmt.Errorf("%w: proposer %s at epoch %d", ErrNotRoundMember, proposal.ProposerID, membership.Epoch)
+		}
+		closed = true
+	} else {
+		snapshotNodes = cloneMembership(c.activeNodes)
+		snapshotNodes[proposal.ProposerID] = true
 	}
 
 	proposalID := fmt.Sprintf("%s-%d-%d", proposal.ProposerID, proposal.Round, proposal.Timestamp.Unix())
+	// A round re-proposed within the second reuses the ID it had in the
+	// epoch that sealed it; it is live again.
+	delete(c.sealed, proposalID)
 	c.proposals[proposalID] = proposal
 	c.votes[proposalID] = make([]*Vote, 0)
 	c.votedByProposal[proposalID] = make(map[string]bool)
+	c.openTimelineLocked(proposalID)
 
-	snapshotNodes := cloneMembership(c.activeNodes)
-	snapshotNodes[proposal.ProposerID] = true
 	snapshot := &RoundMembershipSnapshot{
-		ActiveNodes: snapshotNodes,
-		ActiveCount: countActiveNodes(snapshotNodes),
+		ActiveNodes:  snapshotNodes,
+		Epoch:        proposal.MembershipEpoch,
+		Closed:       closed,
+		Probationary: c.probationaryLocked(snapshotNodes),
+	}
+	if c.probation != nil {
+		snapshot.ProbationVoteWeight = c.probation.Config().VoteWeight
 	}
-	snapshot.QuorumSize = quorumForNodes(snapshot.ActiveCount)
+	snapshot.ActiveCount = countActiveNodes(snapshotNodes, snapshot.Probationary)
+	snapshot.QuorumSize = c.quorumForNodes(snapshot.ActiveCount)
 	c.roundMembership[proposalID] = snapshot
+	if c.commitReveal != nil {
+		c.openRevealRoundLocked(ctx, proposalID, snapshot.ActiveCount)
+	}
 
 	// Transition to voting state
 	c.state = Voting
+	profiling.SetRound(proposal.Round)
+	c.publishLocked(events.KindProposalCreated, proposalID, func(event *events.Event) {
+		event.QuorumSize = snapshot.QuorumSize
+	})
 
 	return proposalID, nil
 }
 
-// CastVote records a vote for a proposal
+// CastVote runs a vote through the coordinator's vote middleware and
+// records it if every check passes. Rejections are counted per middleware
+// and reason; see VoteRejections. Votes on commit-reveal proposals are
+// refused with ErrCommitmentRequired; they are cast with RevealVote.
 func (c *Coordinator) CastVote(ctx context.Context, vote *Vote) error {
+	defer c.observePhase(profiling.PhaseConsensusVote, vote.ProposalID, time.Now())
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	c.mu.RLock()
+	unsealed := c.refuseUnsealedLocked(vote)
+	c.mu.RUnlock()
+	if unsealed != nil {
+		c.recordRejection(unsealed)
+		return unsealed
+	}
+	return c.handleVote(ctx, vote)
+}
+
+// handleVote runs vote through the vote chain and counts its rejection, if
+// any.
+func (c *Coordinator) handleVote(ctx context.Context, vote *Vote) error {
+	c.mu.RLock()
+	handler := c.voteHandler
+	c.mu.RUnlock()
+
+	err := handler(ctx, vote)
+	var rejection *VoteRejection
+	if errors.As(err, &rejection) {
+		c.recordRejection(rejection)
+		if rejection.Drop {
+			return nil
+		}
+	}
+	return err
+}
+
+// SetVoteMiddleware replaces the checks CastVote runs, outermost first.
+// With no middleware the DefaultVoteChain is restored. Whatever the chain,
+// a vote is recorded only while voting on a known proposal, and only a
+// node's first vote counts unless SetVoteFreshness lets newer votes
+// replace it.
+func (c *Coordinator) SetVoteMiddleware(middleware ...VoteMiddleware) {
+	if len(middleware) == 0 {
+		middleware = DefaultVoteChain(c)
+	}
+	handler := ChainVotes(c.recordVote, middleware...)
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.voteHandler = handler
+}
 
-	if c.state != Voting {
-		return fmt.Errorf("cannot vote: current state is %v", c.state)
+// SetVoteRejectionObserver registers a callback receiving each rejected
+// vote's middleware and reason, for exporting metrics.
+func (c *Coordinator) SetVoteRejectionObserver(observer func(middleware, reason string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rejectionObserver = observer
+}
+
+// VoteRejections returns how many votes each middleware rejected, by
+// reason, sorted by middleware then reason.
+func (c *Coordinator) VoteRejections() []VoteRejectionCount {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	counts := make([]VoteRejectionCount, 0, len(c.voteRejections))
+	for key, count := range c.voteRejections {
+		counts = append(counts, VoteRejectionCount{Middleware: key.middleware, Reason: key.reason, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Middleware != counts[j].Middleware {
+			return counts[i].Middleware < counts[j].Middleware
+		}
+		return counts[i].Reason < counts[j].Reason
+	})
+	return counts
+}
+
+type voteRejectionKey struct {
+	middleware string
+	reason     reasons.Code
+}
+
+func (c *Coordinator) recordRejection(rejection *VoteRejection) {
+	c.mu.Lock()
+	c.voteRejections[voteRejectionKey{middleware: rejection.Middleware, reason: rejection.Reason}]++
+	observer := c.rejectionObserver
+	c.mu.Unlock()
+	if observer != nil {
+		observer(rejection.Middleware, reasons.MetricLabel(rejection.Reason))
 	}
+}
 
-	// Verify proposal exists
+// recordVote is the end of every vote chain. It re-checks under the lock
+// what the chain checked without it, so a vote racing a round reset or its
+// own duplicate is never recorded, and a vote that lost the race to an
+// epoch transition is dropped rather than failed.
+func (c *Coordinator) recordVote(_ context.Context, vote *Vote) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if sealed := c.sealedRejectionLocked(vote.ProposalID); sealed != nil {
+		return sealed
+	}
+	if c.state != Voting {
+		return fmt.Errorf("%w: cannot vote in state %v", ErrInvalidState, c.state)
+	}
 	if _, exists := c.proposals[vote.ProposalID]; !exists {
-		return fmt.Errorf("proposal %s not found", vote.ProposalID)
+		return fmt.Errorf("%w: %s", ErrProposalNotFound, vote.ProposalID)
+	}
+	if future := c.futureRejectionLocked(vote); future != nil {
+		return future
 	}
 	if c.votedByProposal[vote.ProposalID] == nil {
 		c.votedByProposal[vote.ProposalID] = make(map[string]bool)
 	}
 	if c.votedByProposal[vote.ProposalID][vote.NodeID] {
+		if c.refreshVoteLocked(vote) {
+			c.timeQuorumLocked(vote.ProposalID)
+			c.announceQuorumLocked(vote.ProposalID)
+		}
 		return nil
 	}
 	c.votedByProposal[vote.ProposalID][vote.NodeID] = true
@@ -314,14 +615,16 @@ func (c *Coordinator) CastVote(ctx context.Context, vote *Vote) error {
 		if active, known := snapshot.ActiveNodes[vote.NodeID]; known {
 			if !active {
 				snapshot.ActiveNodes[vote.NodeID] = true
-				snapshot.ActiveCount++
-				snapshot.QuorumSize = quorumForNodes(snapshot.ActiveCount)
+				snapshot.ActiveCount = countActiveNodes(snapshot.ActiveNodes, snapshot.Probationary)
+				snapshot.QuorumSize = c.quorumForNodes(snapshot.ActiveCount)
 			}
 		}
 	}
 
 	// Record vote
 	c.votes[vote.ProposalID] = append(c.votes[vote.ProposalID], vote)
+	c.timeVoteLocked(vote.ProposalID)
+	c.announceQuorumLocked(vote.ProposalID)
 
 	return nil
 }
@@ -331,20 +634,43 @@ func (c *Coordinator) CheckConsensus(proposalID string) (bool, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	approvalCount, requiredVotes, err := c.tallyLocked(proposalID)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if quorum reached
+	return approvalCount >= requiredVotes, nil
+}
+
+// QuorumProgress returns the approvals counted so far and the approvals
+// required for the proposal to commit.
+func (c *Coordinator) QuorumProgress(proposalID string) (int, int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tallyLocked(proposalID)
+}
+
+// tallyLocked counts usable approvals and the quorum they must reach.
+// Callers must hold c.mu.
+func (c *Coordinator) tallyLocked(proposalID string) (int, int, error) {
 	votes, exists := c.votes[proposalID]
 	if !exists {
-		return false, fmt.Errorf("proposal %s not found", proposalID)
+		return 0, 0, c.missingProposalLocked(proposalID)
 	}
 
 	proposal, hasProposal := c.proposals[proposalID]
 	if !hasProposal {
-		return false, fmt.Errorf("proposal %s not found", proposalID)
+		return 0, 0, c.missingProposalLocked(proposalID)
 	}
 
-	// Count affirmative votes
-	approvalCount := 0
+	// Count affirmative votes, probationary ones at their capped weight
+	// and old ones at their decayed weight
+	snapshot := c.roundMembership[proposalID]
+	now := c.nowLocked()
+	approvalWeight := 0.0
 	for _, vote := range votes {
-		if vote == nil {
+		if vote == nil || c.blacklistedLocked(vote.NodeID) {
 			continue
 		}
 		if c.asyncMode && c.maxVoteStaleness > 0 {
@@ -353,12 +679,13 @@ func (c *Coordinator) CheckConsensus(proposalID string) (bool, error) {
 			}
 		}
 		if vote.Approve {
-			approvalCount++
+			approvalWeight += c.voteWeightLocked(snapshot, vote, now)
 		}
 	}
+	approvalCount := wholeVotes(approvalWeight)
 
 	requiredVotes := c.quorumSize
-	if snapshot, exists := c.roundMembership[proposalID]; exists && snapshot != nil {
+	if snapshot != nil {
 		requiredVotes = snapshot.QuorumSize
 	}
 	if c.asyncMode {
@@ -366,65 +693,64 @@ func (c *Coordinator) CheckConsensus(proposalID string) (bool, error) {
 		if requiredVotes <= 0 {
 			requiredVotes = 1
 		}
-		if snapshot, exists := c.roundMembership[proposalID]; exists && snapshot != nil && requiredVotes > snapshot.ActiveCount {
+		if snapshot != nil && requiredVotes > snapshot.ActiveCount {
 			requiredVotes = snapshot.ActiveCount
 		}
 	}
 
-	// Check if quorum reached
-	return approvalCount >= requiredVotes, nil
+	return approvalCount, requiredVotes, nil
 }
 
 // CommitModel finalizes the consensus and commits the model
 func (c *Coordinator) CommitModel(ctx context.Context, proposalID string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	votes, exists := c.votes[proposalID]
-	if !exists {
-		return fmt.Errorf("proposal %s not found", proposalID)
+	defer c.observePhase(profiling.PhaseConsensusCommit, proposalID, time.Now())
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
 	}
-
-	proposal, hasProposal := c.proposals[proposalID]
-	if !hasProposal {
-		return fmt.Errorf("proposal %s not found", proposalID)
+	if err := c.checkLeaderGate(); err != nil {
+		return err
 	}
 
-	approvalCount := 0
-	for _, vote := range votes {
-		if vote == nil {
-			continue
-		}
-		if c.asyncMode && c.maxVoteStaleness > 0 {
-			if vote.Timestamp.After(proposal.Timestamp.Add(c.maxVoteStaleness)) {
-				continue
-			}
-		}
-		if vote.Approve {
-			approvalCount++
-		}
-	}
+	var unrevealed []string
+	var timeline *protocol.VoteTimeline
+	defer func() { c.reportTimeline(timeline) }()
+	defer func() { c.penalizeUnrevealed(unrevealed) }()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	requiredVotes := c.quorumSize
-	if snapshot, exists := c.roundMembership[proposalID]; exists && snapshot != nil {
-		requiredVotes = snapshot.QuorumSize
+	if round := c.reveals[proposalID]; round != nil {
+		unrevealed = closeRevealRoundLocked(round)
 	}
-	if c.asyncMode {
-		requiredVotes = c.asyncMinVotes
-		if requiredVotes <= 0 {
-			requiredVotes = 1
-		}
-		if snapshot, exists := c.roundMembership[proposalID]; exists && snapshot != nil && requiredVotes > snapshot.ActiveCount {
-			requiredVotes = snapshot.ActiveCount
-		}
+	approvalCount, requiredVotes, err := c.tallyLocked(proposalID)
+	if err != nil {
+		return err
 	}
+	timeline = c.closeTimelineLocked(proposalID)
 
 	if approvalCount < requiredVotes {
 		c.state = Aborted
-		return fmt.Errorf("consensus not reached: insufficient votes")
+		c.publishLocked(events.KindAborted, proposalID, withRisk(timeline, tallied(approvalCount, requiredVotes, events.ReasonQuorumNotReached)))
+		return &ErrQuorumNotReached{Got: approvalCount, Need: requiredVotes}
+	}
+
+	if c.commitVerifier != nil {
+		certified, weight := c.certifyLocked(proposalID, requiredVotes)
+		if weight < requiredVotes {
+			c.state = Aborted
+			c.publishLocked(events.KindAborted, proposalID, withRisk(timeline, tallied(weight, requiredVotes, events.ReasonInvalidSignatures)))
+			return fmt.Errorf("%w: %d of %d required approvals carry a valid signature", ErrInvalidVoteSignature, weight, requiredVotes)
+		}
+		c.certified[proposalID] = certified
 	}
 
 	c.state = Committed
+	votes := c.votes[proposalID]
+	c.recordReplayLocked(proposalID, requiredVotes)
+	c.forgetVotesLocked(proposalID)
+	c.recordProbationLocked(proposalID)
+	c.publishLocked(events.KindCommitted, proposalID, withRisk(timeline, tallied(approvalCount, requiredVotes, "")))
 
 	// NEW: Create blockchain block for this consensus round
 	if c.blockProposer != nil && c.proposals[proposalID] != nil {
@@ -476,6 +802,64 @@ func (c *Coordinator) CommitModel(ctx context.Context, proposalID string) error
 	return nil
 }
 
+// observePhase records the time since started in phase, attributed to the
+// round of proposalID. It takes c.mu, so it is deferred before the caller
+// locks.
+func (c *Coordinator) observePhase(phase profiling.Phase, proposalID string, started time.Time) {
+	elapsed := time.Since(started)
+	round := 0
+	c.mu.RLock()
+	if proposal := c.proposals[proposalID]; proposal != nil {
+		round = proposal.Round
+	}
+	c.mu.RUnlock()
+	profiling.ObserveRound(phase, round, elapsed)
+}
+
+// SetCommitVerifier makes CommitModel verify approvals' signatures with
+// verify, in arrival order, until a quorum of them verifies; the commit
+// fails with ErrInvalidVoteSignature if too few do. Votes counted toward
+// quorum by MAC alone are thereby still signed in every commit
+// certificate.
+func (c *Coordinator) SetCommitVerifier(verify func(vote *Vote) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commitVerifier = verify
+}
+
+// certifyLocked returns approvers of proposalID whose signatures verify,
+// until their approvals reach quorum, and the whole votes they carry.
+// Callers must hold c.mu.
+func (c *Coordinator) certifyLocked(proposalID string, quorum int) ([]string, int) {
+	snapshot := c.roundMembership[proposalID]
+	now := c.nowLocked()
+	var certified []string
+	weight := 0.0
+	for _, vote := range c.votes[proposalID] {
+		if wholeVotes(weight) >= quorum {
+			break
+		}
+		if vote == nil || !vote.Approve || c.blacklistedLocked(vote.NodeID) {
+			continue
+		}
+		if err := c.commitVerifier(vote); err != nil {
+			continue
+		}
+		certified = append(certified, vote.NodeID)
+		weight += c.voteWeightLocked(snapshot, vote, now)
+	}
+	return certified, wholeVotes(weight)
+}
+
+// CertifiedApprovals returns the approvers whose signatures CommitModel
+// verified for proposalID, in arrival order, for the commit certificate.
+// It is empty unless a commit verifier is set and the proposal committed.
+func (c *Coordinator) CertifiedApprovals(proposalID string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.certified[proposalID]...)
+}
+
 // CommitGovernanceProposal finalizes consensus and executes a governance proposal via contract executor.
 func (c *Coordinator) CommitGovernanceProposal(ctx context.Context, consensusProposalID string, contractAddress string, governanceProposalID string) error {
 	c.mu.Lock()
@@ -483,7 +867,7 @@ func (c *Coordinator) CommitGovernanceProposal(ctx context.Context, consensusPro
 
 	votes, exists := c.votes[consensusProposalID]
 	if !exists {
-		return fmt.Errorf("proposal %s not found", consensusProposalID)
+		return fmt.Errorf("%w: %s", ErrProposalNotFound, consensusProposalID)
 	}
 
 	approvalCount := 0
@@ -495,10 +879,10 @@ func (c *Coordinator) CommitGovernanceProposal(ctx context.Context, consensusPro
 
 	if approvalCount < c.quorumSize {
 		c.state = Aborted
-		return fmt.Errorf("consensus not reached: insufficient votes")
+		return &ErrQuorumNotReached{Got: approvalCount, Need: c.quorumSize}
 	}
 	if c.contractExec == nil {
-		return fmt.Errorf("contract executor not configured")
+		return fmt.Errorf("%w: contract executor", ErrNotConfigured)
 	}
 
 	txn := &blockchain.Transaction{
@@ -543,10 +927,10 @@ func (c *Coordinator) SubmitGovernancePolicyProposal(
 	c.mu.RUnlock()
 
 	if exec == nil {
-		return "", fmt.Errorf("contract executor not configured")
+		return "", fmt.Errorf("%w: contract executor", ErrNotConfigured)
 	}
 	if contractAddress == "" {
-		return "", fmt.Errorf("contract address is required")
+		return "", fmt.Errorf("%w: contract address is required", ErrInvalidArgument)
 	}
 	if minVotes == 0 {
 		minVotes = 1
@@ -611,10 +995,10 @@ func (c *Coordinator) CastGovernanceVote(
 	c.mu.RUnlock()
 
 	if exec == nil {
-		return fmt.Errorf("contract executor not configured")
+		return fmt.Errorf("%w: contract executor", ErrNotConfigured)
 	}
 	if contractAddress == "" || voterID == "" || governanceProposalID == "" {
-		return fmt.Errorf("contract address, voter id, and proposal id are required")
+		return fmt.Errorf("%w: contract address, voter id, and proposal id are required", ErrInvalidArgument)
 	}
 
 	txn := &blockchain.Transaction{
@@ -656,6 +1040,38 @@ func (c *Coordinator) GetState() ConsensusState {
 	return c.state
 }
 
+// ProposalState is a proposal's progress towards quorum.
+type ProposalState struct {
+	ProposalID string `json:"proposal_id"`
+	Round      int    `json:"round"`
+	ProposerID string `json:"proposer_id"`
+	Votes      int    `json:"votes"`
+	Approvals  int    `json:"approvals"`
+	Required   int    `json:"required"`
+}
+
+// ProposalStates returns every open proposal's progress, by proposal ID.
+func (c *Coordinator) ProposalStates() []ProposalState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.proposalStatesLocked()
+}
+
+// GetRoundMembership returns a copy of the membership snapshot taken when
+// the proposal was made.
+func (c *Coordinator) GetRoundMembership(proposalID string) (RoundMembershipSnapshot, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	snapshot, exists := c.roundMembership[proposalID]
+	if !exists || snapshot == nil {
+		return RoundMembershipSnapshot{}, c.missingProposalLocked(proposalID)
+	}
+	copied := *snapshot
+	copied.ActiveNodes = cloneMembership(snapshot.ActiveNodes)
+	return copied, nil
+}
+
 // GetRuntimeStatus returns a snapshot of coordinator runtime configuration and
 // live membership state for observability endpoints.
 func (c *Coordinator) GetRuntimeStatus() map[string]interface{} {
@@ -666,10 +1082,14 @@ func (c *Coordinator) GetRuntimeStatus() map[string]interface{} {
 		"state":                 c.state.String(),
 		"total_nodes":           c.totalNodes,
 		"quorum_size":           c.quorumSize,
+		"fault_model":           c.faultModel.String(),
 		"async_mode":            c.asyncMode,
 		"async_min_votes":       c.asyncMinVotes,
 		"max_vote_staleness_ms": c.maxVoteStaleness.Milliseconds(),
 		"open_rounds":           len(c.roundMembership),
+		"membership_epoch":      c.membershipEpoch,
+		"epoch":                 c.epoch,
+		"sealed_proposals":      len(c.sealed),
 	}
 
 	activeNodes := make([]string, 0, len(c.activeNodes))
@@ -680,20 +1100,22 @@ func (c *Coordinator) GetRuntimeStatus() map[string]interface{} {
 	}
 	status["active_nodes"] = activeNodes
 	status["active_node_count"] = len(activeNodes)
+	status["probationary_node_count"] = len(c.probationaryLocked(c.activeNodes))
 
 	return status
 }
 
-// Reset resets the coordinator for a new round
+// Reset readies the coordinator for a new round by beginning the epoch
+// after the current one; see BeginEpoch. Late messages for the rounds it
+// closes are dropped, not failed, so it is safe to call while votes are
+// still arriving.
 func (c *Coordinator) Reset() {
+	var unrevealed []string
+	defer func() { c.penalizeUnrevealed(unrevealed) }()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.proposals = make(map[string]*ModelProposal)
-	c.votes = make(map[string][]*Vote)
-	c.roundMembership = make(map[string]*RoundMembershipSnapshot)
-	c.votedByProposal = make(map[string]map[string]bool)
-	c.state = Proposing
+	unrevealed = c.beginEpochLocked(c.epoch + 1)
 	// Note: roundNumber is NOT reset - it increments monotonically
 }
 
@@ -720,7 +1142,7 @@ func (c *Coordinator) GetConsensusRound(proposalID string) (*ConsensusRound, err
 
 	proposal, exists := c.proposals[proposalID]
 	if !exists {
-		return nil, fmt.Errorf("proposal %s not found", proposalID)
+		return nil, c.missingProposalLocked(proposalID)
 	}
 
 	votes := c.votes[proposalID]
@@ -732,6 +1154,7 @@ func (c *Coordinator) GetConsensusRound(proposalID string) (*ConsensusRound, err
 		ModelWeights:   proposal.Weights,
 		CommitTime:     time.Now(),
 		ValidatorVotes: votes,
+		Manifest:       proposal.Manifest,
 	}, nil
 }
 
@@ -751,10 +1174,10 @@ func (c *Coordinator) SubmitVerificationPolicyProposal(
 	c.mu.RUnlock()
 
 	if exec == nil {
-		return "", fmt.Errorf("contract executor not configured")
+		return "", fmt.Errorf("%w: contract executor", ErrNotConfigured)
 	}
 	if contractAddress == "" {
-		return "", fmt.Errorf("contract address is required")
+		return "", fmt.Errorf("%w: contract address is required", ErrInvalidArgument)
 	}
 	if minVotes == 0 {
 		minVotes = 1
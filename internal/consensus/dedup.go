@@ -0,0 +1,239 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+)
+
+// VoteDedupConfig sizes the window in which VoteDedup remembers delivered
+// votes. Both limits apply per proposal, that is per voting round.
+type VoteDedupConfig struct {
+	// TTL is how long a proposal's votes are remembered after the first
+	// one is; it should outlast the round's voting window.
+	TTL time.Duration
+	// MaxEntries caps the distinct votes remembered per proposal. Votes
+	// beyond it are verified on every delivery.
+	MaxEntries int
+}
+
+// DefaultVoteDedupConfig returns the dedup window used unless configured
+// otherwise: ten minutes and 4096 votes per proposal.
+func DefaultVoteDedupConfig() VoteDedupConfig {
+	return VoteDedupConfig{TTL: 10 * time.Minute, MaxEntries: 4096}
+}
+
+// Validate checks that cfg's TTL and size are positive.
+func (cfg VoteDedupConfig) Validate() error {
+	if cfg.TTL <= 0 {
+		return fmt.Errorf("%w: vote dedup TTL must be positive, got %s", ErrInvalidArgument, cfg.TTL)
+	}
+	if cfg.MaxEntries < 1 {
+		return fmt.Errorf("%w: vote dedup must hold at least one vote per proposal, got %d", ErrInvalidArgument, cfg.MaxEntries)
+	}
+	return nil
+}
+
+// DedupResult is how VoteDedup classified one delivered vote.
+type DedupResult string
+
+const (
+	// DedupHit is a repeat of a vote already accepted; it is dropped.
+	DedupHit DedupResult = "hit"
+	// DedupMiss is a vote not seen before from its voter.
+	DedupMiss DedupResult = "miss"
+	// DedupConflict is a vote whose voter already had a different one
+	// accepted on the proposal. It is never dropped, so equivocation handling
+	// sees it.
+	DedupConflict DedupResult = "conflict"
+)
+
+// VoteDedupStats counts the votes VoteDedup has classified.
+type VoteDedupStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Conflicts uint64 `json:"conflicts"`
+	// Proposals and Entries are the proposals and votes remembered now.
+	Proposals int `json:"proposals"`
+	Entries   int `json:"entries"`
+}
+
+// VoteDedup remembers the votes a node has accepted so that transport
+// redeliveries of them, common under gossip, are dropped before their
+// signatures are checked again. A vote is looked up by a cheap digest of
+// its voter, proposal, choice, timestamp, and flags, kept per voter so one
+// node's votes can never be mistaken for another's, and is a repeat only
+// if those signed fields match exactly, so a conflicting vote ground to
+// collide with an accepted one is not dropped. Only votes the rest of the
+// chain accepted are remembered, so a forged copy delivered first cannot
+// suppress the genuine vote; copies delivered concurrently may each be
+// verified.
+type VoteDedup struct {
+	cfg VoteDedupConfig
+	now func() time.Time
+
+	mu        sync.Mutex
+	proposals map[string]*dedupRound
+	hits      uint64
+	misses    uint64
+	conflicts uint64
+	observer  func(result DedupResult)
+}
+
+// dedupRound is the votes remembered for one proposal.
+type dedupRound struct {
+	created time.Time
+	entries int
+	voters  map[string][]dedupEntry
+}
+
+// dedupEntry is one accepted vote: its digest, and the signing bytes a
+// delivery must match exactly to repeat it.
+type dedupEntry struct {
+	key     uint64
+	signing string
+}
+
+// matches reports whether the vote keyed key with signing bytes signing is
+// the one e remembers.
+func (e dedupEntry) matches(key uint64, signing string) bool {
+	return e.key == key && e.signing == signing
+}
+
+// NewVoteDedup creates an empty dedup window.
+func NewVoteDedup(cfg VoteDedupConfig) (*VoteDedup, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &VoteDedup{cfg: cfg, now: time.Now, proposals: make(map[string]*dedupRound)}, nil
+}
+
+// SetObserver registers a callback told how each delivered vote was
+// classified, for example to export dedup hit rates. It is called without
+// the window's lock held.
+func (d *VoteDedup) SetObserver(observer func(result DedupResult)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.observer = observer
+}
+
+// Stats returns the classification counters and the window's current size.
+func (d *VoteDedup) Stats() VoteDedupStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pruneLocked(d.now())
+	stats := VoteDedupStats{Hits: d.hits, Misses: d.misses, Conflicts: d.conflicts, Proposals: len(d.proposals)}
+	for _, round := range d.proposals {
+		stats.Entries += round.entries
+	}
+	return stats
+}
+
+// Forget drops the votes remembered for proposalID once it can no longer
+// be voted on.
+func (d *VoteDedup) Forget(proposalID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.proposals, proposalID)
+}
+
+// classify reports whether entry was already accepted from vote.NodeID on
+// vote.ProposalID, and counts the result.
+func (d *VoteDedup) classify(vote *Vote, entry dedupEntry) DedupResult {
+	d.mu.Lock()
+	d.pruneLocked(d.now())
+	var seen []dedupEntry
+	if round := d.proposals[vote.ProposalID]; round != nil {
+		seen = round.voters[vote.NodeID]
+	}
+	result := DedupMiss
+	switch {
+	case slices.ContainsFunc(seen, func(e dedupEntry) bool { return e.matches(entry.key, entry.signing) }):
+		result = DedupHit
+		d.hits++
+	case len(seen) > 0:
+		result = DedupConflict
+		d.conflicts++
+	default:
+		d.misses++
+	}
+	observer := d.observer
+	d.mu.Unlock()
+	if observer != nil {
+		observer(result)
+	}
+	return result
+}
+
+// remember records entry as accepted from vote.NodeID, unless its
+// proposal's window is full.
+func (d *VoteDedup) remember(vote *Vote, entry dedupEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	round := d.proposals[vote.ProposalID]
+	if round == nil {
+		round = &dedupRound{created: d.now(), voters: make(map[string][]dedupEntry)}
+		d.proposals[vote.ProposalID] = round
+	}
+	seen := round.voters[vote.NodeID]
+	if round.entries >= d.cfg.MaxEntries || slices.ContainsFunc(seen, func(e dedupEntry) bool { return e.matches(entry.key, entry.signing) }) {
+		return
+	}
+	round.voters[vote.NodeID] = append(seen, entry)
+	round.entries++
+}
+
+// pruneLocked drops proposals remembered for longer than the TTL. Callers
+// must hold d.mu.
+func (d *VoteDedup) pruneLocked(now time.Time) {
+	for proposalID, round := range d.proposals {
+		if now.Sub(round.created) >= d.cfg.TTL {
+			delete(d.proposals, proposalID)
+		}
+	}
+}
+
+// voteDedupKey is the FNV-1a digest of a vote's signing bytes, far cheaper
+// than verifying the signature. It only narrows the lookup: a voter can
+// grind a conflicting vote onto an accepted one's digest, so a repeat must
+// also match the signing bytes exactly.
+var voteDedupKey = func(signing []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(signing)
+	return h.Sum64()
+}
+
+// newDedupEntry returns the entry remembering vote.
+func newDedupEntry(vote *Vote) dedupEntry {
+	signing := VoteSigningBytes(vote)
+	return dedupEntry{key: voteDedupKey(signing), signing: string(signing)}
+}
+
+// DedupCheck drops repeat deliveries of votes d has seen accepted. Put it
+// first in the chain, ahead of SignatureCheck, VoteAuthCheck, and
+// EquivocationCheck, so a repeat costs one hash and a comparison. A
+// voter's vote that differs from one accepted before, in choice,
+// timestamp, or flags, is never dropped here, even if its digest
+// collides, so conflicting votes always reach equivocation handling.
+func DedupCheck(d *VoteDedup) VoteMiddleware {
+	return func(next VoteHandler) VoteHandler {
+		return func(ctx context.Context, vote *Vote) error {
+			entry := newDedupEntry(vote)
+			if d.classify(vote, entry) == DedupHit {
+				return &VoteRejection{Middleware: MiddlewareDedup, Reason: ReasonDuplicateDelivery, Drop: true,
+					Err: fmt.Errorf("%w: node %s on proposal %s", ErrDuplicateDelivery, vote.NodeID, vote.ProposalID)}
+			}
+			if err := next(ctx, vote); err != nil {
+				return err
+			}
+			d.remember(vote, entry)
+			return nil
+		}
+	}
+}
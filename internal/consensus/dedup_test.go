@@ -0,0 +1,228 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// countingVerify wraps verify, counting the signatures it checks.
+func countingVerify(verify func(vote *Vote) error, checked *int) func(vote *Vote) error {
+	return func(vote *Vote) error {
+		*checked++
+		return verify(vote)
+	}
+}
+
+func newTestVoteDedup(t *testing.T, cfg VoteDedupConfig) *VoteDedup {
+	t.Helper()
+	d, err := NewVoteDedup(cfg)
+	if err != nil {
+		t.Fatalf("new vote dedup: %v", err)
+	}
+	return d
+}
+
+func TestDedupDropsRedeliveriesBeforeVerification(t *testing.T) {
+	ctx := context.Background()
+	signers := newVoteSigners(t, "member-1")
+	d := newTestVoteDedup(t, DefaultVoteDedupConfig())
+	checked, reached := 0, 0
+	handler := ChainVotes(acceptVote(&reached), DedupCheck(d), SignatureCheck(countingVerify(signers.verify, &checked)))
+
+	// A forged copy delivered first is rejected and does not shadow the
+	// genuine vote.
+	vote := signedVote(t, signers, "member-1", "proposal", true)
+	forged := *vote
+	forged.Signature = []byte("forged")
+	if rejection := rejectionOf(t, handler(ctx, &forged)); rejection.Reason != ReasonInvalidSignature {
+		t.Fatalf("forged copy: got %s (%v)", rejection.Reason, rejection)
+	}
+
+	for i := 0; i < 5; i++ {
+		delivery := *vote
+		err := handler(ctx, &delivery)
+		if i == 0 {
+			if err != nil {
+				t.Fatalf("first delivery: %v", err)
+			}
+			continue
+		}
+		rejection := rejectionOf(t, err)
+		if rejection.Reason != ReasonDuplicateDelivery || !rejection.Drop || !errors.Is(err, ErrDuplicateDelivery) {
+			t.Fatalf("redelivery %d: got %s drop=%v (%v)", i, rejection.Reason, rejection.Drop, err)
+		}
+	}
+	if checked != 2 || reached != 1 {
+		t.Fatalf("checked %d signatures and accepted %d votes, want 2 and 1", checked, reached)
+	}
+	if stats := d.Stats(); stats.Hits != 4 || stats.Misses != 2 || stats.Conflicts != 0 || stats.Entries != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestDedupNeverSuppressesConflictingVotes(t *testing.T) {
+	ctx := context.Background()
+	signers := newVoteSigners(t, "node-1", "member-1", "member-2", "member-3")
+	d := newTestVoteDedup(t, DefaultVoteDedupConfig())
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	detector := NewEquivocationDetector(signers.verify)
+	coord.SetVoteMiddleware(append([]VoteMiddleware{DedupCheck(d), EquivocationCheck(detector)}, DefaultVoteChain(coord)...)...)
+	coord.SetEquivocationDetector(detector)
+	proposalID := proposeForVotes(t, coord)
+
+	approve := signedVote(t, signers, "member-3", proposalID, true)
+	for i := 0; i < 3; i++ {
+		delivery := *approve
+		if err := coord.CastVote(ctx, &delivery); err != nil {
+			t.Fatalf("approval delivery %d: %v", i, err)
+		}
+	}
+
+	// Every delivery of the conflicting vote reaches the detector and is
+	// rejected there, not dropped as a duplicate.
+	reject := signedVote(t, signers, "member-3", proposalID, false)
+	for i := 0; i < 3; i++ {
+		delivery := *reject
+		err := coord.CastVote(ctx, &delivery)
+		if rejection := rejectionOf(t, err); rejection.Reason != ReasonEquivocated || !errors.Is(err, ErrEquivocation) {
+			t.Fatalf("conflicting delivery %d: got %s (%v)", i, rejection.Reason, err)
+		}
+	}
+	if got := detector.Blacklist(); len(got) != 1 || got[0] != "member-3" {
+		t.Fatalf("blacklist = %v", got)
+	}
+	if stats := d.Stats(); stats.Hits != 2 || stats.Conflicts != 3 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	counts := map[string]int{}
+	for _, rejection := range coord.VoteRejections() {
+		counts[rejection.Middleware+"/"+string(rejection.Reason)] = rejection.Count
+	}
+	if counts["dedup/duplicate_delivery"] != 2 || counts["equivocation/equivocated"] != 3 {
+		t.Fatalf("rejections = %v", counts)
+	}
+}
+
+func TestDedupWindowIsBoundedPerRound(t *testing.T) {
+	ctx := context.Background()
+	d := newTestVoteDedup(t, VoteDedupConfig{TTL: time.Minute, MaxEntries: 2})
+	now := time.Unix(1_700_000_000, 0)
+	d.now = func() time.Time { return now }
+	reached := 0
+	handler := DedupCheck(d)(acceptVote(&reached))
+
+	at := time.Unix(1_700_000_000, 0)
+	for _, nodeID := range []string{"member-1", "member-2", "member-3"} {
+		if err := handler(ctx, &Vote{NodeID: nodeID, ProposalID: "round-1", Approve: true, Timestamp: at}); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+	// The third voter did not fit, so its redelivery is passed on again.
+	if err := handler(ctx, &Vote{NodeID: "member-3", ProposalID: "round-1", Approve: true, Timestamp: at}); err != nil || reached != 4 {
+		t.Fatalf("redelivery beyond the window: err=%v reached=%d", err, reached)
+	}
+	if stats := d.Stats(); stats.Entries != 2 || stats.Proposals != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	now = now.Add(time.Minute)
+	if err := handler(ctx, &Vote{NodeID: "member-1", ProposalID: "round-1", Approve: true, Timestamp: at}); err != nil || reached != 5 {
+		t.Fatalf("redelivery after the TTL: err=%v reached=%d", err, reached)
+	}
+	d.Forget("round-1")
+	if stats := d.Stats(); stats.Entries != 0 || stats.Proposals != 0 {
+		t.Fatalf("stats after forget = %+v", stats)
+	}
+
+	if _, err := NewVoteDedup(VoteDedupConfig{TTL: 0, MaxEntries: 1}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("zero TTL: %v", err)
+	}
+}
+
+// BenchmarkVoteDedup compares the signatures checked per delivered vote
+// when gossip delivers each of a round's votes five times, with and
+// without the dedup window ahead of signature verification.
+func BenchmarkVoteDedup(b *testing.B) {
+	const voters, copies = 200, 5
+	members := make([]string, voters)
+	for i := range members {
+		members[i] = fmt.Sprintf("member-%d", i)
+	}
+	signers := newVoteSigners(b, members...)
+	votes := make([]*Vote, voters)
+	for i, nodeID := range members {
+		vote := &Vote{NodeID: nodeID, ProposalID: "proposal", Approve: true, Timestamp: time.Now()}
+		signers.sign(b, vote)
+		votes[i] = vote
+	}
+
+	for _, dedup := range []bool{false, true} {
+		b.Run(fmt.Sprintf("dedup=%v", dedup), func(b *testing.B) {
+			checked, reached := 0, 0
+			middleware := []VoteMiddleware{SignatureCheck(countingVerify(signers.verify, &checked))}
+			d, err := NewVoteDedup(DefaultVoteDedupConfig())
+			if err != nil {
+				b.Fatal(err)
+			}
+			if dedup {
+				middleware = append([]VoteMiddleware{DedupCheck(d)}, middleware...)
+			}
+			handler := ChainVotes(acceptVote(&reached), middleware...)
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Each round delivers every vote copies times over.
+				if i%(voters*copies) == 0 {
+					d.Forget("proposal")
+				}
+				vote := *votes[(i/copies)%voters]
+				if err := handler(ctx, &vote); err != nil && !errors.Is(err, ErrDuplicateDelivery) {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(checked)/float64(b.N), "verifications/vote")
+		})
+	}
+}
+
+func TestDedupConfirmsDigestCollisions(t *testing.T) {
+	collide := voteDedupKey
+	voteDedupKey = func([]byte) uint64 { return 42 }
+	t.Cleanup(func() { voteDedupKey = collide })
+
+	ctx := context.Background()
+	signers := newVoteSigners(t, "node-1", "member-1", "member-2", "member-3")
+	d := newTestVoteDedup(t, DefaultVoteDedupConfig())
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	detector := NewEquivocationDetector(signers.verify)
+	coord.SetVoteMiddleware(append([]VoteMiddleware{DedupCheck(d), EquivocationCheck(detector)}, DefaultVoteChain(coord)...)...)
+	coord.SetEquivocationDetector(detector)
+	proposalID := proposeForVotes(t, coord)
+
+	approve := signedVote(t, signers, "member-3", proposalID, true)
+	first := *approve
+	if err := coord.CastVote(ctx, &first); err != nil {
+		t.Fatalf("approval: %v", err)
+	}
+	// The conflicting vote digests like the accepted one, but is not a
+	// repeat of it: it reaches the detector instead of being dropped.
+	reject := signedVote(t, signers, "member-3", proposalID, false)
+	err := coord.CastVote(ctx, reject)
+	if rejection := rejectionOf(t, err); rejection.Reason != ReasonEquivocated || !errors.Is(err, ErrEquivocation) {
+		t.Fatalf("colliding conflicting vote: got %s (%v)", rejection.Reason, err)
+	}
+	// A true repeat of the accepted vote is still dropped.
+	redelivery := *approve
+	if err := coord.CastVote(ctx, &redelivery); err != nil {
+		t.Fatalf("redelivery: %v", err)
+	}
+	if stats := d.Stats(); stats.Hits != 1 || stats.Conflicts != 1 || stats.Misses != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
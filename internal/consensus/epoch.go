@@ -0,0 +1,140 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// sealedEpochRetention is how many epochs back the coordinator remembers
+// sealed proposals, to tell their late messages from unknown ones.
+const sealedEpochRetention = 16
+
+// EpochSnapshot is a consistent view of the coordinator: its epoch, state,
+// and open proposals, all read at one instant.
+type EpochSnapshot struct {
+	Epoch     uint64          `json:"epoch"`
+	State     string          `json:"state"`
+	Proposals []ProposalState `json:"proposals"`
+}
+
+// Epoch returns the coordinator's current epoch. It starts at zero and
+// advances with every BeginEpoch or Reset.
+func (c *Coordinator) Epoch() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.epoch
+}
+
+// BeginEpoch seals the current epoch and opens epoch n, which must be
+// later. Proposals of the sealed epoch are closed: a proposal still open
+// is aborted, its unrevealed committers are penalized, and votes,
+// commitments, and reveals for it that arrive late are dropped and counted
+// as rejections with ReasonSealedEpoch instead of failing. Reads of a
+// sealed proposal fail with ErrEpochSealed.
+func (c *Coordinator) BeginEpoch(n uint64) error {
+	var unrevealed []string
+	defer func() { c.penalizeUnrevealed(unrevealed) }()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n <= c.epoch {
+		return fmt.Errorf("%w: asked for %d, already at %d", ErrEpochRegressed, n, c.epoch)
+	}
+	unrevealed = c.beginEpochLocked(n)
+	return nil
+}
+
+// Snapshot returns the coordinator's epoch, state, and open proposals'
+// progress as of one instant, so none of them can come from either side
+// of an epoch transition.
+func (c *Coordinator) Snapshot() EpochSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return EpochSnapshot{
+		Epoch:     c.epoch,
+		State:     c.state.String(),
+		Proposals: c.proposalStatesLocked(),
+	}
+}
+
+// beginEpochLocked seals the current epoch, clears its round state, and
+// opens epoch n. It returns the unrevealed committers of the proposals it
+// closed. Callers must hold c.mu.
+func (c *Coordinator) beginEpochLocked(n uint64) []string {
+	var unrevealed []string
+	for _, round := range c.reveals {
+		unrevealed = append(unrevealed, closeRevealRoundLocked(round)...)
+	}
+	if c.state == Voting {
+		c.abortOpenLocked()
+	}
+	for proposalID := range c.proposals {
+		c.sealed[proposalID] = c.epoch
+		c.forgetVotesLocked(proposalID)
+	}
+	for proposalID, epoch := range c.sealed {
+		if n > sealedEpochRetention && epoch < n-sealedEpochRetention {
+			delete(c.sealed, proposalID)
+		}
+	}
+
+	c.reveals = make(map[string]*revealRound)
+	c.proposals = make(map[string]*ModelProposal)
+	c.votes = make(map[string][]*Vote)
+	c.roundMembership = make(map[string]*RoundMembershipSnapshot)
+	c.votedByProposal = make(map[string]map[string]bool)
+	c.voteChanges = make(map[string][]VoteChange)
+	c.certified = make(map[string][]string)
+	c.quorumAnnounced = make(map[string]bool)
+	c.voteTimes = make(map[string]*voteTimes)
+	c.closedTimelines = make(map[string]protocol.VoteTimeline)
+	c.state = Proposing
+	c.epoch = n
+	return unrevealed
+}
+
+// sealedRejectionLocked returns the rejection dropping a late message for
+// proposalID if it belongs to a sealed epoch, and nil otherwise. Callers
+// must hold c.mu.
+func (c *Coordinator) sealedRejectionLocked(proposalID string) *VoteRejection {
+	epoch, sealed := c.sealed[proposalID]
+	if !sealed {
+		return nil
+	}
+	return &VoteRejection{Middleware: MiddlewareRoundWindow, Reason: ReasonSealedEpoch, Drop: true,
+		Err: fmt.Errorf("%w: %s sealed with epoch %d", ErrEpochSealed, proposalID, epoch)}
+}
+
+// missingProposalLocked is the error for a proposalID the coordinator
+// holds no round state for: ErrEpochSealed if its epoch was sealed, and
+// ErrProposalNotFound otherwise. Callers must hold c.mu.
+func (c *Coordinator) missingProposalLocked(proposalID string) error {
+	if epoch, sealed := c.sealed[proposalID]; sealed {
+		return fmt.Errorf("%w: %s sealed with epoch %d", ErrEpochSealed, proposalID, epoch)
+	}
+	return fmt.Errorf("%w: %s", ErrProposalNotFound, proposalID)
+}
+
+// proposalStatesLocked returns every open proposal's progress, by
+// proposal ID. Callers must hold c.mu.
+func (c *Coordinator) proposalStatesLocked() []ProposalState {
+	states := make([]ProposalState, 0, len(c.proposals))
+	for proposalID, proposal := range c.proposals {
+		approvals, required, _ := c.tallyLocked(proposalID)
+		states = append(states, ProposalState{
+			ProposalID: proposalID,
+			Round:      proposal.Round,
+			ProposerID: proposal.ProposerID,
+			Votes:      len(c.votes[proposalID]),
+			Approvals:  approvals,
+			Required:   required,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].ProposalID < states[j].ProposalID })
+	return states
+}
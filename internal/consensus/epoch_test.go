@@ -0,0 +1,195 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLateMessagesForSealedEpochsAreDropped(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	proposalID := proposeForVotes(t, coord)
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true}); err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+	coord.Reset()
+	if epoch := coord.Epoch(); epoch != 1 {
+		t.Fatalf("epoch after reset = %d, want 1", epoch)
+	}
+
+	// A vote arriving after the reset is acknowledged and counted, not
+	// failed; reads of the sealed proposal say why it is gone.
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-2", ProposalID: proposalID, Approve: true}); err != nil {
+		t.Fatalf("late vote: %v", err)
+	}
+	if _, err := coord.CheckConsensus(proposalID); !errors.Is(err, ErrEpochSealed) {
+		t.Fatalf("check sealed proposal: expected ErrEpochSealed, got %v", err)
+	}
+	if _, _, err := coord.QuorumProgress("unknown"); !errors.Is(err, ErrProposalNotFound) {
+		t.Fatalf("unknown proposal: expected ErrProposalNotFound, got %v", err)
+	}
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-2", ProposalID: "unknown"}); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("vote on an unknown proposal between rounds: %v", err)
+	}
+
+	// The next epoch's round proceeds, and late votes for the old one
+	// still do not touch it.
+	if err := coord.BeginEpoch(1); !errors.Is(err, ErrEpochRegressed) {
+		t.Fatalf("BeginEpoch(1) at epoch 1: expected ErrEpochRegressed, got %v", err)
+	}
+	if err := coord.BeginEpoch(7); err != nil {
+		t.Fatalf("BeginEpoch(7): %v", err)
+	}
+	next, err := coord.ProposeModel(ctx, &ModelProposal{Round: 2, Weights: []byte("weights"), ProposerID: "node-1", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-3", ProposalID: proposalID, Approve: true}); err != nil {
+		t.Fatalf("late vote during the next round: %v", err)
+	}
+	snapshot := coord.Snapshot()
+	if snapshot.Epoch != 7 || snapshot.State != "voting" || len(snapshot.Proposals) != 1 || snapshot.Proposals[0].ProposalID != next || snapshot.Proposals[0].Votes != 0 {
+		t.Fatalf("snapshot = %+v", snapshot)
+	}
+
+	var sealed int
+	for _, count := range coord.VoteRejections() {
+		if count.Reason == ReasonSealedEpoch {
+			sealed += count.Count
+		}
+	}
+	if sealed != 2 {
+		t.Fatalf("sealed-epoch drops = %d, want 2", sealed)
+	}
+}
+
+func TestLateCommitmentsAndRevealsForSealedEpochsAreDropped(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	coord.SetCommitReveal(&CommitRevealConfig{CommitWindow: time.Minute, RevealWindow: time.Minute})
+	proposalID := proposeForVotes(t, coord)
+	vote, salt := sealFor(t, coord, proposalID, "member-1")
+	coord.Reset()
+
+	late := &Vote{NodeID: "member-2", ProposalID: proposalID, Approve: true}
+	if err := coord.CommitVote(ctx, SealVote(late, []byte("salt"))); err != nil {
+		t.Fatalf("late commitment: %v", err)
+	}
+	if err := coord.RevealVote(ctx, vote, salt); err != nil {
+		t.Fatalf("late reveal: %v", err)
+	}
+	if _, err := coord.RevealPhaseOf(proposalID); !errors.Is(err, ErrProposalNotFound) {
+		t.Fatalf("reveal phase of a sealed proposal: %v", err)
+	}
+	if got := coord.VoteRejections(); len(got) != 1 || got[0].Reason != ReasonSealedEpoch || got[0].Count != 2 {
+		t.Fatalf("rejections = %+v", got)
+	}
+}
+
+// TestSupersededRoundIsNotCountedAsFailed reproduces a round whose
+// proposal is sealed by a reset racing it, such as a late retry of the
+// previous round: it used to fail with ErrProposalNotFound and count as a
+// failed round.
+func TestSupersededRoundIsNotCountedAsFailed(t *testing.T) {
+	ctx := context.Background()
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1", "peer2", "peer3"}, 30*time.Second)
+	coord := aggregator.coordinator
+	var once sync.Once
+	resetOnce := func(next VoteHandler) VoteHandler {
+		return func(ctx context.Context, vote *Vote) error {
+			once.Do(coord.Reset)
+			return next(ctx, vote)
+		}
+	}
+	coord.SetVoteMiddleware(append([]VoteMiddleware{resetOnce}, DefaultVoteChain(coord)...)...)
+	if err := aggregator.SubmitModel(ctx, "node-1", []byte{2, 4}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	if _, err := aggregator.AggregateWithConsensus(ctx); !errors.Is(err, ErrEpochSealed) {
+		t.Fatalf("expected the round superseded, got %v", err)
+	}
+	metrics := aggregator.GetMetrics()
+	if metrics.FailedRounds != 0 || metrics.SupersededRounds != 1 || metrics.TotalRounds != 1 {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+	if summary := aggregator.HistorySummary(); summary.Failed != 0 || summary.Superseded != 1 {
+		t.Fatalf("summary = %+v", summary)
+	}
+}
+
+// TestRoundsSurviveConcurrentLateVotes runs rounds back to back while
+// other goroutines replay votes for every proposal seen so far and read
+// the coordinator. Run it with -race.
+func TestRoundsSurviveConcurrentLateVotes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1", "peer2", "peer3"}, 30*time.Second)
+	coord := aggregator.coordinator
+
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(voter string) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				snapshot := coord.Snapshot()
+				mu.Lock()
+				for _, proposal := range snapshot.Proposals {
+					seen[proposal.ProposalID] = true
+				}
+				ids := make([]string, 0, len(seen))
+				for proposalID := range seen {
+					ids = append(ids, proposalID)
+				}
+				mu.Unlock()
+				for _, proposalID := range ids {
+					err := coord.CastVote(ctx, &Vote{NodeID: voter, ProposalID: proposalID, Approve: true, Timestamp: time.Now()})
+					if errors.Is(err, ErrProposalNotFound) {
+						select {
+						case errs <- err:
+						default:
+						}
+					}
+					_, _ = coord.CheckConsensus(proposalID)
+				}
+			}
+		}(fmt.Sprintf("peer%d", i))
+	}
+
+	const rounds = 20
+	for round := 1; round <= rounds; round++ {
+		for _, nodeID := range []string{"node-1", "peer1"} {
+			if err := aggregator.SubmitModel(ctx, nodeID, []byte{byte(round), 4}); err != nil {
+				t.Fatalf("round %d submit %s: %v", round, nodeID, err)
+			}
+		}
+		if _, err := aggregator.AggregateWithConsensus(ctx); err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+	}
+	cancel()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("late vote failed: %v", err)
+	}
+
+	metrics := aggregator.GetMetrics()
+	if metrics.FailedRounds != 0 || metrics.SuccessfulRounds != rounds {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+	if epoch := coord.Epoch(); epoch != rounds {
+		t.Fatalf("epoch = %d after %d rounds", epoch, rounds)
+	}
+}
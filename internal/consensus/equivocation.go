@@ -0,0 +1,320 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TopicVoteSet is the gossip topic on which nodes exchange the vote sets
+// of their open proposals for ObserveVoteSet.
+const TopicVoteSet = "consensus/voteset"
+
+// EquivocationProof is two validly signed votes a node cast on the same
+// proposal with opposite choices. Signed votes cannot be forged, so the pair
+// alone proves the node equivocated; anyone holding the voter's public key
+// can check it with VerifyEquivocationProof.
+type EquivocationProof struct {
+	NodeID     string `json:"node_id"`
+	ProposalID string `json:"proposal_id"`
+	First      Vote   `json:"first"`
+	Second     Vote   `json:"second"`
+}
+
+// VerifyEquivocationProof checks that proof holds two conflicting votes by
+// proof.NodeID on proof.ProposalID and that verify accepts both signatures.
+// Failures wrap ErrInvalidEquivocationProof.
+func VerifyEquivocationProof(proof *EquivocationProof, verify func(vote *Vote) error) error {
+	if proof == nil {
+		return fmt.Errorf("%w: no proof", ErrInvalidEquivocationProof)
+	}
+	for _, vote := range []*Vote{&proof.First, &proof.Second} {
+		if vote.NodeID != proof.NodeID || vote.ProposalID != proof.ProposalID {
+			return fmt.Errorf("%w: vote by %s on %s does not match proof for %s on %s",
+				ErrInvalidEquivocationProof, vote.NodeID, vote.ProposalID, proof.NodeID, proof.ProposalID)
+		}
+	}
+	if proof.First.Approve == proof.Second.Approve {
+		return fmt.Errorf("%w: votes do not conflict", ErrInvalidEquivocationProof)
+	}
+	for _, vote := range []*Vote{&proof.First, &proof.Second} {
+		if err := verify(vote); err != nil {
+			return fmt.Errorf("%w: node %s: %v", ErrInvalidEquivocationProof, proof.NodeID, err)
+		}
+	}
+	return nil
+}
+
+// VoteDigest is the hex SHA-256 of a vote's signing bytes. The replay log
+// records it per voter, so a vote produced later can be matched against
+// the one that was counted.
+func VoteDigest(vote *Vote) string {
+	sum := sha256.Sum256(VoteSigningBytes(vote))
+	return hex.EncodeToString(sum[:])
+}
+
+// EquivocationDetector compares the vote sets nodes gossip for a proposal.
+// The first validly signed vote it sees from a node on a proposal stands;
+// a later one with the opposite choice is proof of equivocation, and the
+// node is blacklisted at once. Proofs wait as evidence until a coordinator
+// the detector is attached to commits, which records them in the commit's
+// replay entry.
+type EquivocationDetector struct {
+	verify func(vote *Vote) error
+
+	mu           sync.Mutex
+	seen         map[string]map[string][]Vote
+	allowChanges bool
+	blacklist    map[string]bool
+	proofs       []EquivocationProof
+	pending      []EquivocationProof
+	onBlacklist  func(proof EquivocationProof)
+}
+
+// NewEquivocationDetector creates a detector that accepts only votes whose
+// signature verify accepts; unsigned or forged votes prove nothing.
+func NewEquivocationDetector(verify func(vote *Vote) error) *EquivocationDetector {
+	return &EquivocationDetector{
+		verify:    verify,
+		seen:      make(map[string]map[string][]Vote),
+		blacklist: make(map[string]bool),
+	}
+}
+
+// SetBlacklistObserver registers a callback told of each node as it is
+// blacklisted, with the proof against it, for example to slash its stake.
+// It is called without the detector's lock held.
+func (d *EquivocationDetector) SetBlacklistObserver(observer func(proof EquivocationProof)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onBlacklist = observer
+}
+
+// SetAllowVoteChanges makes the detector accept a node's vote of the
+// opposite choice that carries another timestamp as a changed vote, as
+// coordinators under SetVoteFreshness do; only conflicting votes cast at
+// the same instant then prove equivocation. Every detector in a federation
+// should agree on the setting, or one will blacklist what another allows.
+func (d *EquivocationDetector) SetAllowVoteChanges(allowed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.allowChanges = allowed
+}
+
+// Observe compares a gossiped vote set with every vote seen before and
+// returns the proofs of equivocation it found.
+func (d *EquivocationDetector) Observe(votes []Vote) []EquivocationProof {
+	var found []EquivocationProof
+	for i := range votes {
+		if proof, ok := d.observe(votes[i]); ok {
+			found = append(found, proof)
+		}
+	}
+	return found
+}
+
+func (d *EquivocationDetector) observe(vote Vote) (EquivocationProof, bool) {
+	if vote.NodeID == "" || vote.ProposalID == "" || d.verify(&vote) != nil {
+		return EquivocationProof{}, false
+	}
+	vote.Signature = append([]byte(nil), vote.Signature...)
+	vote.MAC = nil
+
+	d.mu.Lock()
+	byNode := d.seen[vote.ProposalID]
+	if byNode == nil {
+		byNode = make(map[string][]Vote)
+		d.seen[vote.ProposalID] = byNode
+	}
+	first, conflicts := d.conflictLocked(byNode[vote.NodeID], vote)
+	if !conflicts || d.proven(vote.NodeID, vote.ProposalID) {
+		d.rememberLocked(byNode, vote)
+		d.mu.Unlock()
+		return EquivocationProof{}, false
+	}
+	proof := EquivocationProof{NodeID: vote.NodeID, ProposalID: vote.ProposalID, First: first, Second: vote}
+	d.blacklist[vote.NodeID] = true
+	d.proofs = append(d.proofs, proof)
+	d.pending = append(d.pending, proof)
+	observer := d.onBlacklist
+	d.mu.Unlock()
+
+	if observer != nil {
+		observer(proof)
+	}
+	return proof, true
+}
+
+// conflictLocked returns the vote among seen, a node's earlier votes on a
+// proposal, that vote contradicts. Callers must hold d.mu.
+func (d *EquivocationDetector) conflictLocked(seen []Vote, vote Vote) (Vote, bool) {
+	for _, earlier := range seen {
+		if earlier.Approve == vote.Approve {
+			continue
+		}
+		if !d.allowChanges || earlier.Timestamp.Equal(vote.Timestamp) {
+			return earlier, true
+		}
+	}
+	return Vote{}, false
+}
+
+// rememberLocked keeps vote for comparison with the node's later votes:
+// only its first, unless changed votes are allowed, in which case every
+// distinct one. Callers must hold d.mu.
+func (d *EquivocationDetector) rememberLocked(byNode map[string][]Vote, vote Vote) {
+	seen := byNode[vote.NodeID]
+	if len(seen) > 0 && !d.allowChanges {
+		return
+	}
+	for _, earlier := range seen {
+		if earlier.Approve == vote.Approve && earlier.Timestamp.Equal(vote.Timestamp) {
+			return
+		}
+	}
+	byNode[vote.NodeID] = append(seen, vote)
+}
+
+// proven reports whether a proof against nodeID on proposalID is already
+// held. Callers must hold d.mu.
+func (d *EquivocationDetector) proven(nodeID, proposalID string) bool {
+	for _, proof := range d.proofs {
+		if proof.NodeID == nodeID && proof.ProposalID == proposalID {
+			return true
+		}
+	}
+	return false
+}
+
+// Blacklisted reports whether nodeID has been proven to equivocate.
+func (d *EquivocationDetector) Blacklisted(nodeID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.blacklist[nodeID]
+}
+
+// Blacklist returns the blacklisted nodes, sorted.
+func (d *EquivocationDetector) Blacklist() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	nodes := make([]string, 0, len(d.blacklist))
+	for nodeID := range d.blacklist {
+		nodes = append(nodes, nodeID)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Proofs returns every proof found, in the order found.
+func (d *EquivocationDetector) Proofs() []EquivocationProof {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]EquivocationProof(nil), d.proofs...)
+}
+
+// Forget drops the votes seen on proposalID once it can no longer be
+// voted on. Blacklist entries and proofs are kept.
+func (d *EquivocationDetector) Forget(proposalID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.seen, proposalID)
+}
+
+// takeEvidence returns the proofs not yet included in a commit and clears
+// them.
+func (d *EquivocationDetector) takeEvidence() []EquivocationProof {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	evidence := d.pending
+	d.pending = nil
+	return evidence
+}
+
+// EquivocationCheck feeds every vote to d and rejects votes from
+// blacklisted nodes, including the vote that gets its sender blacklisted.
+// Put it before DuplicateCheck, which would drop the conflicting vote
+// unseen.
+func EquivocationCheck(d *EquivocationDetector) VoteMiddleware {
+	return func(next VoteHandler) VoteHandler {
+		return func(ctx context.Context, vote *Vote) error {
+			d.observe(*vote)
+			if d.Blacklisted(vote.NodeID) {
+				return &VoteRejection{Middleware: MiddlewareEquivocation, Reason: ReasonEquivocated,
+					Err: fmt.Errorf("%w: node %s", ErrEquivocation, vote.NodeID)}
+			}
+			return next(ctx, vote)
+		}
+	}
+}
+
+// SetEquivocationDetector attaches d to the coordinator: votes from nodes
+// it blacklists stop counting toward quorum, even if already recorded, and
+// each commit's replay entry carries the proofs found since the previous
+// commit. Add EquivocationCheck to the vote chain to reject their new
+// votes as well, and feed peers' vote sets to ObserveVoteSet. The detector
+// forgets a proposal's votes once it is committed or sealed.
+func (c *Coordinator) SetEquivocationDetector(d *EquivocationDetector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.equivocation = d
+}
+
+// VoteSet returns copies of the votes recorded on proposalID, in arrival
+// order, for gossiping to other nodes' equivocation detectors.
+func (c *Coordinator) VoteSet(proposalID string) ([]Vote, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	votes, exists := c.votes[proposalID]
+	if !exists {
+		return nil, c.missingProposalLocked(proposalID)
+	}
+	out := make([]Vote, 0, len(votes))
+	for _, vote := range votes {
+		if vote != nil {
+			out = append(out, *vote)
+		}
+	}
+	return out, nil
+}
+
+// ObserveVoteSet feeds a vote set a peer gossiped to the attached detector
+// and returns the proofs of equivocation it found. Only votes on proposals
+// open for voting here are observed, so gossip cannot refill what the
+// detector forgot when a proposal was committed or sealed.
+func (c *Coordinator) ObserveVoteSet(votes []Vote) []EquivocationProof {
+	c.mu.RLock()
+	detector := c.equivocation
+	open := make([]Vote, 0, len(votes))
+	if detector != nil && c.state == Voting {
+		for _, vote := range votes {
+			if _, exists := c.proposals[vote.ProposalID]; exists {
+				open = append(open, vote)
+			}
+		}
+	}
+	c.mu.RUnlock()
+	if len(open) == 0 {
+		return nil
+	}
+	return detector.Observe(open)
+}
+
+// forgetVotesLocked drops the detector's votes on proposalID once it can
+// no longer be voted on. Callers must hold c.mu.
+func (c *Coordinator) forgetVotesLocked(proposalID string) {
+	if c.equivocation != nil {
+		c.equivocation.Forget(proposalID)
+	}
+}
+
+// blacklistedLocked reports whether the attached detector has blacklisted
+// nodeID. Callers must hold c.mu.
+func (c *Coordinator) blacklistedLocked(nodeID string) bool {
+	return c.equivocation != nil && c.equivocation.Blacklisted(nodeID)
+}
@@ -0,0 +1,224 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newEquivocationPair returns two coordinators voting on the same proposal,
+// the first with an equivocation detector in its vote chain.
+func newEquivocationPair(t *testing.T, signers *voteSigners) (*Coordinator, *Coordinator, *EquivocationDetector, string) {
+	t.Helper()
+	proposal := ModelProposal{Round: 1, Weights: []byte("weights"), ProposerID: "node-1", Proof: []byte("proof"), Timestamp: time.Now()}
+	detector := NewEquivocationDetector(signers.verify)
+	a := NewCoordinator("node-1", 4, 5*time.Second)
+	a.SetVoteMiddleware(append([]VoteMiddleware{EquivocationCheck(detector)}, DefaultVoteChain(a)...)...)
+	a.SetEquivocationDetector(detector)
+	b := NewCoordinator("node-1", 4, 5*time.Second)
+
+	ctx := context.Background()
+	first, second := proposal, proposal
+	proposalID, err := a.ProposeModel(ctx, &first)
+	if err != nil {
+		t.Fatalf("propose on a: %v", err)
+	}
+	if id, err := b.ProposeModel(ctx, &second); err != nil || id != proposalID {
+		t.Fatalf("propose on b: id=%s err=%v", id, err)
+	}
+	return a, b, detector, proposalID
+}
+
+func signedVote(t *testing.T, signers *voteSigners, nodeID, proposalID string, approve bool) *Vote {
+	t.Helper()
+	vote := &Vote{NodeID: nodeID, ProposalID: proposalID, Approve: approve, Timestamp: time.Now()}
+	signers.sign(t, vote)
+	return vote
+}
+
+func TestEquivocationDetectedFromGossipedVoteSets(t *testing.T) {
+	ctx := context.Background()
+	signers := newVoteSigners(t, "node-1", "member-1", "member-2", "member-3")
+	a, b, detector, proposalID := newEquivocationPair(t, signers)
+	var observed []EquivocationProof
+	detector.SetBlacklistObserver(func(proof EquivocationProof) { observed = append(observed, proof) })
+
+	// member-3 approves on a and rejects on b; everyone else votes once.
+	for _, nodeID := range []string{"node-1", "member-1", "member-2", "member-3"} {
+		if err := a.CastVote(ctx, signedVote(t, signers, nodeID, proposalID, true)); err != nil {
+			t.Fatalf("vote %s on a: %v", nodeID, err)
+		}
+	}
+	if err := b.CastVote(ctx, signedVote(t, signers, "member-3", proposalID, false)); err != nil {
+		t.Fatalf("vote on b: %v", err)
+	}
+	if approvals, _, _ := a.QuorumProgress(proposalID); approvals != 4 {
+		t.Fatalf("approvals before gossip = %d, want 4", approvals)
+	}
+
+	// A forged conflicting vote proves nothing.
+	forged := Vote{NodeID: "member-2", ProposalID: proposalID, Approve: false, Timestamp: time.Now(), Signature: []byte("forged")}
+	if proofs := detector.Observe([]Vote{forged}); len(proofs) != 0 {
+		t.Fatalf("forged vote produced proofs: %+v", proofs)
+	}
+
+	gossiped, err := b.VoteSet(proposalID)
+	if err != nil {
+		t.Fatalf("vote set: %v", err)
+	}
+	proofs := detector.Observe(gossiped)
+	if len(proofs) != 1 || proofs[0].NodeID != "member-3" || len(observed) != 1 {
+		t.Fatalf("proofs = %+v, observed %d", proofs, len(observed))
+	}
+	if got := detector.Blacklist(); len(got) != 1 || got[0] != "member-3" {
+		t.Fatalf("blacklist = %v", got)
+	}
+	// Gossiping the same set again finds nothing new.
+	if again := detector.Observe(gossiped); len(again) != 0 {
+		t.Fatalf("repeat gossip produced proofs: %+v", again)
+	}
+
+	// The equivocator's recorded vote stops counting and new ones are refused.
+	if approvals, _, _ := a.QuorumProgress(proposalID); approvals != 3 {
+		t.Fatalf("approvals after blacklisting = %d, want 3", approvals)
+	}
+	err = a.CastVote(ctx, signedVote(t, signers, "member-3", proposalID, true))
+	if rejection := rejectionOf(t, err); rejection.Reason != ReasonEquivocated || !errors.Is(err, ErrEquivocation) {
+		t.Fatalf("blacklisted vote: got %s (%v)", rejection.Reason, err)
+	}
+
+	if err := a.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	log := a.ReplayLog()
+	if len(log) != 1 || len(log[0].Equivocations) != 1 {
+		t.Fatalf("replay log = %+v", log)
+	}
+	entry := log[0]
+	if len(entry.VoteDigests) != 4 {
+		t.Fatalf("vote digests = %v, want one per voter", entry.VoteDigests)
+	}
+	// The recorded digest is of the vote a counted, not b's.
+	if entry.VoteDigests["member-3"] == VoteDigest(&gossiped[0]) {
+		t.Fatal("vote digest matches the conflicting vote")
+	}
+
+	// A third node, holding only the commit's replay entry and the voters'
+	// public keys, verifies the evidence.
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("encode entry: %v", err)
+	}
+	var received ReplayEntry
+	if err := json.Unmarshal(data, &received); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	evidence := received.Equivocations[0]
+	if err := VerifyEquivocationProof(&evidence, signers.verify); err != nil {
+		t.Fatalf("third node rejected proof: %v", err)
+	}
+	tampered := evidence
+	tampered.Second.Approve = tampered.First.Approve
+	if err := VerifyEquivocationProof(&tampered, signers.verify); !errors.Is(err, ErrInvalidEquivocationProof) {
+		t.Fatalf("non-conflicting votes: expected ErrInvalidEquivocationProof, got %v", err)
+	}
+	framed := evidence
+	framed.Second.Signature = evidence.First.Signature
+	if err := VerifyEquivocationProof(&framed, signers.verify); !errors.Is(err, ErrInvalidEquivocationProof) {
+		t.Fatalf("unsigned vote: expected ErrInvalidEquivocationProof, got %v", err)
+	}
+
+	// Evidence is included in one commit only.
+	a.Reset()
+	nextID := proposeForVotes(t, a)
+	for _, nodeID := range []string{"node-1", "member-1", "member-2"} {
+		if err := a.CastVote(ctx, signedVote(t, signers, nodeID, nextID, true)); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+	if err := a.CommitModel(ctx, nextID); err != nil {
+		t.Fatalf("second commit: %v", err)
+	}
+	if log := a.ReplayLog(); len(log) != 2 || len(log[1].Equivocations) != 0 {
+		t.Fatalf("second entry carried evidence: %+v", log[len(log)-1])
+	}
+}
+
+func TestEquivocationCheckCatchesDirectConflict(t *testing.T) {
+	ctx := context.Background()
+	signers := newVoteSigners(t, "member-1")
+	a, _, detector, proposalID := newEquivocationPair(t, signers)
+
+	if err := a.CastVote(ctx, signedVote(t, signers, "member-1", proposalID, false)); err != nil {
+		t.Fatalf("first vote: %v", err)
+	}
+	err := a.CastVote(ctx, signedVote(t, signers, "member-1", proposalID, true))
+	if !errors.Is(err, ErrEquivocation) {
+		t.Fatalf("conflicting vote: expected ErrEquivocation, got %v", err)
+	}
+	if !detector.Blacklisted("member-1") || len(detector.Proofs()) != 1 {
+		t.Fatalf("member-1 not blacklisted: %v", detector.Blacklist())
+	}
+	proof := detector.Proofs()[0]
+	if err := VerifyEquivocationProof(&proof, signers.verify); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+}
+
+func TestEquivocationDetectorForgetsSettledProposals(t *testing.T) {
+	ctx := context.Background()
+	signers := newVoteSigners(t, "node-1", "member-1", "member-2", "member-3")
+	a, b, detector, proposalID := newEquivocationPair(t, signers)
+	seen := func() int {
+		detector.mu.Lock()
+		defer detector.mu.Unlock()
+		return len(detector.seen)
+	}
+
+	for _, nodeID := range []string{"node-1", "member-1", "member-2"} {
+		if err := a.CastVote(ctx, signedVote(t, signers, nodeID, proposalID, true)); err != nil {
+			t.Fatalf("vote %s on a: %v", nodeID, err)
+		}
+	}
+	if err := b.CastVote(ctx, signedVote(t, signers, "member-3", proposalID, true)); err != nil {
+		t.Fatalf("vote on b: %v", err)
+	}
+	gossiped, err := b.VoteSet(proposalID)
+	if err != nil {
+		t.Fatalf("vote set: %v", err)
+	}
+	if proofs := a.ObserveVoteSet(gossiped); len(proofs) != 0 || seen() != 1 {
+		t.Fatalf("open proposal: proofs %+v, %d proposals seen", proofs, seen())
+	}
+
+	if err := a.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if seen() != 0 {
+		t.Fatalf("committed proposal still tracked: %d proposals seen", seen())
+	}
+	// Gossip arriving after the commit does not refill the detector.
+	if a.ObserveVoteSet(gossiped); seen() != 0 {
+		t.Fatalf("late gossip tracked: %d proposals seen", seen())
+	}
+
+	// A proposal sealed by an epoch transition is forgotten too.
+	if err := a.BeginEpoch(a.Epoch() + 1); err != nil {
+		t.Fatalf("begin epoch: %v", err)
+	}
+	nextID := proposeForVotes(t, a)
+	if err := a.CastVote(ctx, signedVote(t, signers, "member-1", nextID, true)); err != nil {
+		t.Fatalf("vote on next: %v", err)
+	}
+	if seen() != 1 {
+		t.Fatalf("open proposal untracked: %d proposals seen", seen())
+	}
+	if err := a.BeginEpoch(a.Epoch() + 1); err != nil {
+		t.Fatalf("begin epoch: %v", err)
+	}
+	if seen() != 0 {
+		t.Fatalf("sealed proposal still tracked: %d proposals seen", seen())
+	}
+}
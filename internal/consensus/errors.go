@@ -0,0 +1,127 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned (wrapped) by the coordinator and aggregator.
+// Match them with errors.Is; never compare error strings.
+var (
+	// ErrProposalNotFound means the proposal ID is unknown, usually because the
+	// round was reset. Not retryable with the same ID.
+	ErrProposalNotFound = errors.New("proposal not found")
+	// ErrEpochSealed means the proposal belonged to an epoch the coordinator
+	// has since sealed, so the round it was part of is over. Not retryable
+	// with the same ID.
+	ErrEpochSealed = errors.New("proposal epoch sealed")
+	// ErrEpochRegressed means BeginEpoch was asked for an epoch no later
+	// than the current one. Not retryable with the same epoch.
+	ErrEpochRegressed = errors.New("consensus epoch regressed")
+	// ErrInvalidState means the coordinator is in the wrong phase for the call
+	// (for example voting before a proposal). Retryable once the round advances.
+	ErrInvalidState = errors.New("invalid consensus state")
+	// ErrNotRoundMember means the voter was not admitted when the proposal's
+	// membership snapshot was taken, or the proposer is not in the
+	// membership view it would be taken from. Not retryable for this
+	// proposal.
+	ErrNotRoundMember = errors.New("node not in round membership")
+	// ErrMembershipRegressed means the membership view went back to an older
+	// epoch. Not retryable until the view catches up.
+	ErrMembershipRegressed = errors.New("membership epoch regressed")
+	// ErrNotConfigured means an optional component such as the contract
+	// executor has not been wired in. Not retryable.
+	ErrNotConfigured = errors.New("component not configured")
+	// ErrInvalidArgument means the caller supplied a missing or malformed
+	// value. Not retryable.
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrNoModels means no local models were submitted for the round.
+	// Retryable after more submissions arrive.
+	ErrNoModels = errors.New("no models to aggregate")
+	// ErrAllModelsStale means every submission exceeded the staleness window.
+	// Retryable after fresh submissions arrive.
+	ErrAllModelsStale = errors.New("all candidate models were stale")
+	// ErrShapeMismatch means submitted models disagree on size. Not retryable
+	// until the offending submission is replaced.
+	ErrShapeMismatch = errors.New("inconsistent model size")
+	// ErrManifestMismatch means a contribution manifest does not match the
+	// digest bound into its proposal. Not retryable.
+	ErrManifestMismatch = errors.New("contribution manifest mismatch")
+	// ErrNotLeader means the coordinator's leader gate refused a proposal or
+	// commit, for example on a passive standby or a primary fenced by a
+	// failover. Not retryable on this node.
+	ErrNotLeader = errors.New("coordinator is not the current leader")
+	// ErrDuplicateVote means the node already voted on the proposal. Not
+	// retryable; CastVote drops duplicates without reporting it.
+	ErrDuplicateVote = errors.New("duplicate vote")
+	// ErrDuplicateDelivery means the same vote was already delivered and
+	// accepted. Not retryable; CastVote drops redeliveries without
+	// reporting it.
+	ErrDuplicateDelivery = errors.New("vote already delivered")
+	// ErrFutureVote means a vote's timestamp is ahead of the coordinator's
+	// clock by more than the allowed skew. Not retryable with the same
+	// vote.
+	ErrFutureVote = errors.New("vote timestamp in the future")
+	// ErrInvalidVoteSignature means a vote's signature did not verify. Not
+	// retryable.
+	ErrInvalidVoteSignature = errors.New("invalid vote signature")
+	// ErrInvalidVoteMAC means a vote's MAC did not match the voter's
+	// pairwise key. Not retryable.
+	ErrInvalidVoteMAC = errors.New("invalid vote MAC")
+	// ErrReputationTooLow means the voter's reputation is below the
+	// deployment's floor. Not retryable until its reputation recovers.
+	ErrReputationTooLow = errors.New("voter reputation below floor")
+	// ErrVoteRateLimited means the voter exceeded its vote rate. Retryable
+	// after the rate window passes.
+	ErrVoteRateLimited = errors.New("vote rate limit exceeded")
+	// ErrCommitmentRequired means a vote was cast directly on a proposal
+	// that votes by commit-reveal. Not retryable; commit to the vote and
+	// reveal it instead.
+	ErrCommitmentRequired = errors.New("vote must be committed and revealed")
+	// ErrRevealMismatch means a revealed vote and salt do not hash to the
+	// node's commitment, or the node never committed. Not retryable with
+	// the same vote and salt.
+	ErrRevealMismatch = errors.New("revealed vote does not match commitment")
+	// ErrEquivocation means the voter was proven to have cast conflicting
+	// signed votes and is blacklisted. Not retryable.
+	ErrEquivocation = errors.New("voter equivocated")
+	// ErrInvalidEquivocationProof means an equivocation proof does not hold
+	// two conflicting, validly signed votes by the accused node. Not
+	// retryable.
+	ErrInvalidEquivocationProof = errors.New("invalid equivocation proof")
+	// ErrProbationaryNode means a node still on probation tried something
+	// only full members may, such as proposing. Retryable once it
+	// graduates.
+	ErrProbationaryNode = errors.New("node is on probation")
+)
+
+// ErrQuorumNotReached reports how far a proposal was from quorum. It is
+// retryable: a later round with more live voters may succeed.
+type ErrQuorumNotReached struct {
+	Got  int
+	Need int
+}
+
+func (e *ErrQuorumNotReached) Error() string {
+	return fmt.Sprintf("consensus not reached: %d of %d required approvals", e.Got, e.Need)
+}
+
+// Retryable reports whether err is a transient consensus failure that may
+// succeed if the caller tries again in a later round.
+func Retryable(err error) bool {
+	var quorumErr *ErrQuorumNotReached
+	switch {
+	case errors.As(err, &quorumErr):
+		return true
+	case errors.Is(err, ErrInvalidState),
+		errors.Is(err, ErrNoModels),
+		errors.Is(err, ErrAllModelsStale),
+		errors.Is(err, ErrVoteRateLimited):
+		return true
+	default:
+		return false
+	}
+}
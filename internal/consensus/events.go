@@ -0,0 +1,92 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"sort"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// SetEvents publishes the coordinator's part of each round's lifecycle to
+// pub: proposal_created when a proposal opens, quorum_reached the first
+// time its approvals reach its quorum, then committed, or aborted when it
+// falls short at commit or is reset while still voting. Proposal events
+// carry the proposal's contribution manifest. Events are published under
+// the coordinator's lock, in the order its state changed. nil stops
+// publishing.
+func (c *Coordinator) SetEvents(pub events.Publisher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lifecycle = pub
+}
+
+// tallied fills in an event's approvals, quorum, and reason.
+func tallied(approvals, quorum int, reason reasons.Code) func(*events.Event) {
+	return func(event *events.Event) {
+		event.Approvals = approvals
+		event.QuorumSize = quorum
+		event.Reason = reason
+	}
+}
+
+// withRisk also marks an event with whether timeline closed at risk of
+// missing quorum.
+func withRisk(timeline *protocol.VoteTimeline, fill func(*events.Event)) func(*events.Event) {
+	return func(event *events.Event) {
+		fill(event)
+		event.QuorumAtRisk = timeline != nil && timeline.AtRisk
+	}
+}
+
+// publishLocked publishes a kind event for proposalID, adjusted by fill.
+// Callers must hold c.mu.
+func (c *Coordinator) publishLocked(kind events.Kind, proposalID string, fill func(*events.Event)) {
+	if c.lifecycle == nil {
+		return
+	}
+	event := events.Event{Kind: kind, Source: "consensus", ProposalID: proposalID}
+	if proposal := c.proposals[proposalID]; proposal != nil {
+		event.Round = proposal.Round
+		event.NodeID = proposal.ProposerID
+		event.Manifest = proposal.Manifest
+	}
+	if fill != nil {
+		fill(&event)
+	}
+	c.lifecycle.Publish(event)
+}
+
+// announceQuorumLocked publishes quorum_reached the first time
+// proposalID's approvals reach its quorum. Callers must hold c.mu.
+func (c *Coordinator) announceQuorumLocked(proposalID string) {
+	if c.lifecycle == nil || c.quorumAnnounced[proposalID] {
+		return
+	}
+	approvals, required, err := c.tallyLocked(proposalID)
+	if err != nil || approvals < required {
+		return
+	}
+	c.quorumAnnounced[proposalID] = true
+	c.publishLocked(events.KindQuorumReached, proposalID, tallied(approvals, required, ""))
+}
+
+// abortOpenLocked publishes aborted for every proposal still voting, in ID
+// order. Callers must hold c.mu.
+func (c *Coordinator) abortOpenLocked() {
+	if c.lifecycle == nil {
+		return
+	}
+	ids := make([]string, 0, len(c.proposals))
+	for id := range c.proposals {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		approvals, required, _ := c.tallyLocked(id)
+		c.publishLocked(events.KindAborted, id, tallied(approvals, required, events.ReasonReset))
+	}
+}
@@ -0,0 +1,94 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func drain(t *testing.T, sub *events.Subscription) []events.Event {
+	t.Helper()
+	var got []events.Event
+	for {
+		select {
+		case event := <-sub.Events():
+			got = append(got, event)
+		default:
+			return got
+		}
+	}
+}
+
+func TestCoordinatorPublishesRoundLifecycle(t *testing.T) {
+	ctx := context.Background()
+	bus := events.NewBus()
+	sub := bus.Subscribe("test", 16)
+	c := NewCoordinator("node-1", 4, 5*time.Second)
+	c.SetEvents(events.Scope(bus, "traffic"))
+
+	proposal := &ModelProposal{Round: 1, Weights: []byte("weights"), ProposerID: "node-1", Timestamp: time.Unix(1, 0)}
+	proposal.AttachManifest(probationManifest(1, included("member-1")))
+	proposalID, err := c.ProposeModel(ctx, proposal)
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	for _, nodeID := range []string{"node-1", "member-1", "member-2", "member-3"} {
+		if err := c.CastVote(ctx, &Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+	if err := c.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	c.Reset()
+
+	got := drain(t, sub)
+	want := []events.Kind{events.KindProposalCreated, events.KindQuorumReached, events.KindCommitted}
+	if len(got) != len(want) {
+		t.Fatalf("events = %+v", got)
+	}
+	for i, event := range got {
+		if event.Kind != want[i] || event.ProposalID != proposalID || event.Round != 1 || event.FederationID != "traffic" || event.Source != "consensus" {
+			t.Fatalf("event %d = %+v", i, event)
+		}
+	}
+	if got[0].Manifest == nil || got[0].NodeID != "node-1" || got[0].QuorumSize != 3 {
+		t.Fatalf("proposal event = %+v", got[0])
+	}
+	// Quorum is announced on the third approval, once.
+	if got[1].Approvals != 3 || got[2].Approvals != 4 || got[2].QuorumSize != 3 {
+		t.Fatalf("quorum events = %+v", got[1:])
+	}
+
+	// A proposal short of quorum aborts at commit; one reset while voting
+	// aborts then.
+	for round, commit := range map[int]bool{2: true, 3: false} {
+		proposalID, err := c.ProposeModel(ctx, &ModelProposal{Round: round, ProposerID: "node-1", Timestamp: time.Unix(int64(round), 0)})
+		if err != nil {
+			t.Fatalf("propose round %d: %v", round, err)
+		}
+		if commit {
+			if err := c.CommitModel(ctx, proposalID); err == nil {
+				t.Fatal("committed without votes")
+			}
+		}
+		c.Reset()
+		got := drain(t, sub)
+		if len(got) != 2 || got[1].Kind != events.KindAborted || got[1].Round != round {
+			t.Fatalf("round %d events = %+v", round, got)
+		}
+		reason := events.ReasonReset
+		if commit {
+			reason = events.ReasonQuorumNotReached
+		}
+		if got[1].Reason != reason || got[1].QuorumSize != 3 {
+			t.Fatalf("round %d abort = %+v", round, got[1])
+		}
+	}
+	if entries := got[0].Manifest.Entries; len(entries) != 1 || entries[0].Reason != protocol.ReasonIncluded {
+		t.Fatalf("manifest = %+v", got[0].Manifest)
+	}
+}
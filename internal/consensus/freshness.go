@@ -0,0 +1,152 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultVoteClockSkew is how far ahead of the coordinator's clock a vote
+// may be stamped under VoteFreshness when MaxSkew is unset.
+const DefaultVoteClockSkew = 5 * time.Second
+
+// VoteFreshness weights approvals by the age of the vote at tally time. A
+// vote cast within Window counts in full; beyond it, its weight halves
+// every HalfLife. Votes stamped more than MaxSkew ahead of the tally clock,
+// which would never age and could never be refreshed, are refused. The
+// zero value disables weighting.
+type VoteFreshness struct {
+	Window   time.Duration
+	HalfLife time.Duration
+	// MaxSkew defaults to DefaultVoteClockSkew.
+	MaxSkew time.Duration
+}
+
+// Enabled reports whether f weights votes at all.
+func (f VoteFreshness) Enabled() bool {
+	return f.Window > 0
+}
+
+// Weight is the share of a full vote that a vote of age counts for.
+func (f VoteFreshness) Weight(age time.Duration) float64 {
+	if !f.Enabled() || age <= f.Window {
+		return 1
+	}
+	return math.Pow(0.5, float64(age-f.Window)/float64(f.HalfLife))
+}
+
+// maxSkew is how far ahead of now a vote may be stamped.
+func (f VoteFreshness) maxSkew() time.Duration {
+	if f.MaxSkew > 0 {
+		return f.MaxSkew
+	}
+	return DefaultVoteClockSkew
+}
+
+// VoteChange records a voter replacing its vote on a proposal with a newer
+// one of the opposite choice. Both votes are signed and carry distinct
+// timestamps, so unlike an EquivocationProof the pair is legal.
+type VoteChange struct {
+	NodeID     string `json:"node_id"`
+	ProposalID string `json:"proposal_id"`
+	Previous   Vote   `json:"previous"`
+	Current    Vote   `json:"current"`
+}
+
+// SetVoteFreshness makes tallies weight approvals by their age, so a quorum
+// gathered from votes cast long ago must be refreshed to commit. Voters may
+// then recast: a vote newer than the node's recorded one replaces it, and
+// a replacement that flips the choice is kept as a VoteChange and in the
+// commit's replay entry. The zero VoteFreshness restores counting every
+// recorded vote in full and dropping repeats.
+func (c *Coordinator) SetVoteFreshness(freshness VoteFreshness) error {
+	if freshness.Window < 0 || freshness.MaxSkew < 0 || (freshness.Enabled() && freshness.HalfLife <= 0) {
+		return fmt.Errorf("%w: vote freshness window %v, half-life %v, max skew %v", ErrInvalidArgument, freshness.Window, freshness.HalfLife, freshness.MaxSkew)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.freshness = freshness
+	return nil
+}
+
+// VoteChanges returns the changed votes recorded on proposalID, in the
+// order they arrived.
+func (c *Coordinator) VoteChanges(proposalID string) []VoteChange {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]VoteChange(nil), c.voteChanges[proposalID]...)
+}
+
+// voteWeightLocked is what vote's approval counts toward quorum at now.
+// Callers must hold c.mu.
+func (c *Coordinator) voteWeightLocked(snapshot *RoundMembershipSnapshot, vote *Vote, now time.Time) float64 {
+	return approvalWeightOf(snapshot, vote.NodeID) * c.freshness.Weight(now.Sub(vote.Timestamp))
+}
+
+// nowLocked is the tally clock. Callers must hold c.mu.
+func (c *Coordinator) nowLocked() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
+// futureRejectionLocked returns the rejection refusing vote if freshness
+// is on and vote is stamped past the allowed skew, and nil otherwise.
+// Callers must hold c.mu.
+func (c *Coordinator) futureRejectionLocked(vote *Vote) *VoteRejection {
+	if !c.freshness.Enabled() {
+		return nil
+	}
+	now := c.nowLocked()
+	if !vote.Timestamp.After(now.Add(c.freshness.maxSkew())) {
+		return nil
+	}
+	return &VoteRejection{Middleware: MiddlewareFreshness, Reason: ReasonFutureVote,
+		Err: fmt.Errorf("%w: node %s stamped its vote %v ahead of the tally clock", ErrFutureVote, vote.NodeID, vote.Timestamp.Sub(now))}
+}
+
+// refreshesLocked reports whether vote would replace its voter's recorded
+// vote on the proposal. Callers must hold c.mu.
+func (c *Coordinator) refreshesLocked(vote *Vote) bool {
+	if !c.freshness.Enabled() {
+		return false
+	}
+	_, recorded := c.recordedVoteLocked(vote.ProposalID, vote.NodeID)
+	return recorded != nil && vote.Timestamp.After(recorded.Timestamp)
+}
+
+// refreshVoteLocked replaces the voter's recorded vote with vote if vote is
+// newer, recording a change of choice. It reports whether it did. Callers
+// must hold c.mu.
+func (c *Coordinator) refreshVoteLocked(vote *Vote) bool {
+	if !c.refreshesLocked(vote) {
+		return false
+	}
+	i, previous := c.recordedVoteLocked(vote.ProposalID, vote.NodeID)
+	votes := c.votes[vote.ProposalID]
+	c.votes[vote.ProposalID] = append(append(votes[:i:i], votes[i+1:]...), vote)
+	if previous.Approve != vote.Approve {
+		c.voteChanges[vote.ProposalID] = append(c.voteChanges[vote.ProposalID], VoteChange{
+			NodeID:     vote.NodeID,
+			ProposalID: vote.ProposalID,
+			Previous:   *previous,
+			Current:    *vote,
+		})
+	}
+	return true
+}
+
+// recordedVoteLocked returns the index and vote recorded for nodeID on
+// proposalID, or nil. Callers must hold c.mu.
+func (c *Coordinator) recordedVoteLocked(proposalID, nodeID string) (int, *Vote) {
+	for i, vote := range c.votes[proposalID] {
+		if vote != nil && vote.NodeID == nodeID {
+			return i, vote
+		}
+	}
+	return -1, nil
+}
@@ -0,0 +1,214 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestVoteFreshnessWeight(t *testing.T) {
+	freshness := VoteFreshness{Window: 2 * time.Minute, HalfLife: time.Minute}
+	for _, tc := range []struct {
+		age  time.Duration
+		want float64
+	}{
+		{-time.Second, 1},
+		{time.Minute, 1},
+		{2 * time.Minute, 1},
+		{3 * time.Minute, 0.5},
+		{4 * time.Minute, 0.25},
+		{150 * time.Second, math.Sqrt(0.5)},
+	} {
+		if got := freshness.Weight(tc.age); math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("weight at %v = %v, want %v", tc.age, got, tc.want)
+		}
+	}
+	if got := (VoteFreshness{}).Weight(time.Hour); got != 1 {
+		t.Fatalf("disabled weight = %v, want 1", got)
+	}
+
+	c := NewCoordinator("node-1", 4, time.Minute)
+	for _, invalid := range []VoteFreshness{{Window: -time.Second}, {Window: time.Minute}, {Window: time.Minute, HalfLife: -time.Second}, {Window: time.Minute, HalfLife: time.Minute, MaxSkew: -time.Second}} {
+		if err := c.SetVoteFreshness(invalid); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("SetVoteFreshness(%+v) = %v, want ErrInvalidArgument", invalid, err)
+		}
+	}
+}
+
+func TestDecayedAndRefreshedVotesTally(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewCoordinator("node-1", 4, 5*time.Minute)
+	c.clock = func() time.Time { return now }
+	if err := c.SetVoteFreshness(VoteFreshness{Window: 2 * time.Minute, HalfLife: time.Minute}); err != nil {
+		t.Fatalf("set freshness: %v", err)
+	}
+	proposalID, err := c.ProposeModel(ctx, &ModelProposal{Round: 1, Weights: []byte("weights"), ProposerID: "node-1", Proof: []byte("proof"), Timestamp: now.Add(-5 * time.Minute)})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	cast := func(nodeID string, approve bool, age time.Duration) {
+		t.Helper()
+		if err := c.CastVote(ctx, &Vote{NodeID: nodeID, ProposalID: proposalID, Approve: approve, Timestamp: now.Add(-age)}); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+	progress := func(want int) {
+		t.Helper()
+		if got, required, err := c.QuorumProgress(proposalID); err != nil || got != want || required != 3 {
+			t.Fatalf("progress = %d of %d (%v), want %d of 3", got, required, err, want)
+		}
+	}
+
+	// 1 + 1 + 0.5 + 0.25: four approvals, but only 2.75 weighted.
+	cast("node-1", true, 0)
+	cast("member-1", true, 2*time.Minute)
+	cast("member-2", true, 3*time.Minute)
+	cast("member-3", true, 4*time.Minute)
+	progress(2)
+	if reached, _ := c.CheckConsensus(proposalID); reached {
+		t.Fatal("consensus reached on stale votes")
+	}
+
+	// A vote older than the recorded one, or as old, replaces nothing.
+	cast("member-2", true, 4*time.Minute)
+	cast("member-2", false, 3*time.Minute)
+	progress(2)
+
+	// member-2 refreshes its approval: 1 + 1 + 1 + 0.25.
+	cast("member-2", true, 0)
+	progress(3)
+
+	// member-3 changes its mind: its old approval no longer counts at all.
+	cast("member-3", false, 0)
+	progress(3)
+	if votes, _ := c.VoteSet(proposalID); len(votes) != 4 {
+		t.Fatalf("recorded %d votes, want the latest of each of 4 voters", len(votes))
+	}
+	changes := c.VoteChanges(proposalID)
+	if len(changes) != 1 || changes[0].NodeID != "member-3" || !changes[0].Previous.Approve || changes[0].Current.Approve {
+		t.Fatalf("changes = %+v, want member-3's approval changed to a rejection", changes)
+	}
+
+	// A minute passes: member-1's approval halves before the commit.
+	now = now.Add(time.Minute)
+	progress(2)
+	if err := c.CommitModel(ctx, proposalID); err == nil {
+		t.Fatal("committed below the weighted quorum")
+	}
+}
+
+func TestFutureVotesAreRefusedUnderFreshness(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewCoordinator("node-1", 4, 5*time.Minute)
+	c.clock = func() time.Time { return now }
+	if err := c.SetVoteFreshness(VoteFreshness{Window: time.Minute, HalfLife: time.Minute, MaxSkew: 10 * time.Second}); err != nil {
+		t.Fatalf("set freshness: %v", err)
+	}
+	proposalID, err := c.ProposeModel(ctx, &ModelProposal{Round: 1, Weights: []byte("weights"), ProposerID: "node-1", Proof: []byte("proof"), Timestamp: now})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+
+	// A vote stamped an hour ahead would never age out nor be refreshed.
+	err = c.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: false, Timestamp: now.Add(time.Hour)})
+	if rejection := rejectionOf(t, err); rejection.Reason != ReasonFutureVote || !errors.Is(err, ErrFutureVote) {
+		t.Fatalf("future vote: got %s (%v)", rejection.Reason, err)
+	}
+	if votes, _ := c.VoteSet(proposalID); len(votes) != 0 {
+		t.Fatalf("future vote recorded: %+v", votes)
+	}
+
+	// Within the skew a vote is taken, and a later one still refreshes it.
+	if err := c.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: false, Timestamp: now.Add(5 * time.Second)}); err != nil {
+		t.Fatalf("vote within skew: %v", err)
+	}
+	now = now.Add(10 * time.Second)
+	if err := c.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true, Timestamp: now}); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if changes := c.VoteChanges(proposalID); len(changes) != 1 || !changes[0].Current.Approve {
+		t.Fatalf("changes = %+v, want member-1's refresh", changes)
+	}
+
+	// Without freshness, timestamps are not checked.
+	plain := NewCoordinator("node-1", 4, 5*time.Minute)
+	plainID := proposeForVotes(t, plain)
+	if err := plain.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: plainID, Approve: true, Timestamp: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("vote without freshness: %v", err)
+	}
+}
+
+func TestRefreshedVotesCommitWithChangesInReplay(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewCoordinator("node-1", 4, 5*time.Minute)
+	c.clock = func() time.Time { return now }
+	if err := c.SetVoteFreshness(VoteFreshness{Window: time.Minute, HalfLife: time.Minute}); err != nil {
+		t.Fatalf("set freshness: %v", err)
+	}
+	proposalID, err := c.ProposeModel(ctx, &ModelProposal{Round: 1, Weights: []byte("weights"), ProposerID: "node-1", Proof: []byte("proof"), Timestamp: now})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	for _, vote := range []Vote{
+		{NodeID: "node-1", Approve: true, Timestamp: now},
+		{NodeID: "member-1", Approve: false, Timestamp: now.Add(-3 * time.Minute)},
+		{NodeID: "member-1", Approve: true, Timestamp: now},
+		{NodeID: "member-2", Approve: true, Timestamp: now},
+	} {
+		vote.ProposalID = proposalID
+		if err := c.CastVote(ctx, &vote); err != nil {
+			t.Fatalf("vote %s: %v", vote.NodeID, err)
+		}
+	}
+	if err := c.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	entry := c.ReplayLog()[0]
+	if len(entry.Approvals) != 3 || len(entry.VoteChanges) != 1 || entry.VoteChanges[0].NodeID != "member-1" {
+		t.Fatalf("replay entry = %+v", entry)
+	}
+	if entry.VoteDigests["member-1"] != VoteDigest(&entry.VoteChanges[0].Current) {
+		t.Fatal("replay digest is not of member-1's latest vote")
+	}
+}
+
+func TestDetectorAllowsSignedChangesButNotSimultaneousConflicts(t *testing.T) {
+	signers := newVoteSigners(t, "member-1", "member-2")
+	detector := NewEquivocationDetector(signers.verify)
+	detector.SetAllowVoteChanges(true)
+	at := time.Now()
+	vote := func(nodeID string, approve bool, ts time.Time) Vote {
+		v := Vote{NodeID: nodeID, ProposalID: "p", Approve: approve, Timestamp: ts}
+		signers.sign(t, &v)
+		return v
+	}
+
+	// member-1 approves, later rejects, and later still approves again.
+	changed := []Vote{vote("member-1", true, at), vote("member-1", false, at.Add(time.Minute)), vote("member-1", true, at.Add(2*time.Minute))}
+	if proofs := detector.Observe(changed); len(proofs) != 0 {
+		t.Fatalf("changed votes produced proofs: %+v", proofs)
+	}
+
+	// member-2 signs both choices for the same instant.
+	if proofs := detector.Observe([]Vote{vote("member-2", true, at)}); len(proofs) != 0 {
+		t.Fatalf("first vote produced proofs: %+v", proofs)
+	}
+	proofs := detector.Observe([]Vote{vote("member-2", false, at.Add(time.Minute)), vote("member-2", false, at)})
+	if len(proofs) != 1 || proofs[0].NodeID != "member-2" || !proofs[0].First.Timestamp.Equal(proofs[0].Second.Timestamp) {
+		t.Fatalf("proofs = %+v, want one against member-2's simultaneous votes", proofs)
+	}
+	if got := detector.Blacklist(); len(got) != 1 || got[0] != "member-2" {
+		t.Fatalf("blacklist = %v", got)
+	}
+
+	// Without changes allowed, any conflicting pair is equivocation.
+	strict := NewEquivocationDetector(signers.verify)
+	if proofs := strict.Observe(changed); len(proofs) != 1 {
+		t.Fatalf("strict detector found %d proofs, want 1", len(proofs))
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+// DefaultAggregationHistory is how many rounds a DistributedAggregator
+// keeps in its history until SetHistorySize changes it.
+const DefaultAggregationHistory = 256
+
+// RoundOutcome is how an aggregation round ended.
+type RoundOutcome string
+
+const (
+	// OutcomeCommitted: the round's aggregate was committed.
+	OutcomeCommitted RoundOutcome = "committed"
+	// OutcomeFailed: the round ended without a commit; Error says why.
+	OutcomeFailed RoundOutcome = "failed"
+	// OutcomeSuperseded: the round's epoch was sealed before it committed,
+	// so a later round took its place.
+	OutcomeSuperseded RoundOutcome = "superseded"
+)
+
+// AggregationRound records one attempt at an aggregation round.
+type AggregationRound struct {
+	Round int `json:"round"`
+	// Attempt numbers the round's attempts from 1; a retried round has a
+	// record per attempt.
+	Attempt   int           `json:"attempt"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration_ns"`
+	// Participants counts the submissions applied to the aggregate.
+	Participants int `json:"participants"`
+	// ConsensusRounds counts the proposals put to vote: one, or zero when
+	// the round failed before proposing.
+	ConsensusRounds int `json:"consensus_rounds"`
+	// DetectedFaults counts submissions excluded from the aggregate and
+	// peer votes that never arrived.
+	DetectedFaults int          `json:"detected_faults"`
+	Outcome        RoundOutcome `json:"outcome"`
+	Error          string       `json:"error,omitempty"`
+}
+
+// AggregationSummary summarizes a run of aggregation rounds. Percentiles
+// use the nearest-rank method and are zero for an empty run.
+type AggregationSummary struct {
+	Rounds          int           `json:"rounds"`
+	Committed       int           `json:"committed"`
+	Failed          int           `json:"failed"`
+	Superseded      int           `json:"superseded"`
+	DurationP50     time.Duration `json:"duration_p50_ns"`
+	DurationP90     time.Duration `json:"duration_p90_ns"`
+	DurationP99     time.Duration `json:"duration_p99_ns"`
+	ParticipantsP50 int           `json:"participants_p50"`
+	ParticipantsP90 int           `json:"participants_p90"`
+	ParticipantsP99 int           `json:"participants_p99"`
+}
+
+// SummarizeRounds counts rounds by outcome and takes percentiles of their
+// durations and participant counts.
+func SummarizeRounds(rounds []AggregationRound) AggregationSummary {
+	summary := AggregationSummary{Rounds: len(rounds)}
+	if len(rounds) == 0 {
+		return summary
+	}
+	durations := make([]float64, len(rounds))
+	participants := make([]float64, len(rounds))
+	for i, round := range rounds {
+		switch round.Outcome {
+		case OutcomeCommitted:
+			summary.Committed++
+		case OutcomeSuperseded:
+			summary.Superseded++
+		default:
+			summary.Failed++
+		}
+		durations[i] = float64(round.Duration)
+		participants[i] = float64(round.Participants)
+	}
+	sort.Float64s(durations)
+	sort.Float64s(participants)
+	summary.DurationP50 = time.Duration(monitoring.NearestRank(durations, 50))
+	summary.DurationP90 = time.Duration(monitoring.NearestRank(durations, 90))
+	summary.DurationP99 = time.Duration(monitoring.NearestRank(durations, 99))
+	summary.ParticipantsP50 = int(monitoring.NearestRank(participants, 50))
+	summary.ParticipantsP90 = int(monitoring.NearestRank(participants, 90))
+	summary.ParticipantsP99 = int(monitoring.NearestRank(participants, 99))
+	return summary
+}
+
+// SetHistorySize bounds the round history to the newest size rounds,
+// dropping older ones now if it holds more. Non-positive sizes keep
+// DefaultAggregationHistory.
+func (da *DistributedAggregator) SetHistorySize(size int) {
+	if size <= 0 {
+		size = DefaultAggregationHistory
+	}
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	da.historySize = size
+	da.trimHistoryLocked()
+}
+
+// SetRoundObserver calls observe with each round's record once the round
+// ends. The observer runs without the aggregator's lock held.
+func (da *DistributedAggregator) SetRoundObserver(observe func(AggregationRound)) {
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	da.roundObserver = observe
+}
+
+// History returns the retained rounds, oldest first.
+func (da *DistributedAggregator) History() []AggregationRound {
+	da.mu.RLock()
+	defer da.mu.RUnlock()
+	return append([]AggregationRound(nil), da.history...)
+}
+
+// HistorySummary summarizes the retained rounds.
+func (da *DistributedAggregator) HistorySummary() AggregationSummary {
+	return SummarizeRounds(da.History())
+}
+
+// recordRound finishes record with the round's duration and outcome, keeps
+// it in the history, and reports it to the observer and collector.
+func (da *DistributedAggregator) recordRound(record AggregationRound, err error) {
+	record.Duration = time.Since(record.StartedAt)
+	record.Outcome = OutcomeCommitted
+	if err != nil {
+		record.Outcome = OutcomeFailed
+		if errors.Is(err, ErrEpochSealed) {
+			record.Outcome = OutcomeSuperseded
+		}
+		record.Error = err.Error()
+	}
+
+	da.mu.Lock()
+	da.history = append(da.history, record)
+	da.trimHistoryLocked()
+	observe := da.roundObserver
+	collector := da.collector
+	da.mu.Unlock()
+
+	if observe != nil {
+		observe(record)
+	}
+	if collector != nil {
+		collector.RecordAggregationRound(record.Round, record.Duration.Seconds(), record.Participants, string(record.Outcome), da.nodeID)
+	}
+}
+
+func (da *DistributedAggregator) trimHistoryLocked() {
+	if excess := len(da.history) - da.historySize; excess > 0 {
+		da.history = append(da.history[:0:0], da.history[excess:]...)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+func TestSummarizeRoundsPercentiles(t *testing.T) {
+	rounds := make([]AggregationRound, 0, 100)
+	// Reverse order checks the summary sorts before ranking.
+	for i := 100; i >= 1; i-- {
+		outcome := OutcomeCommitted
+		if i%10 == 0 {
+			outcome = OutcomeFailed
+		}
+		rounds = append(rounds, AggregationRound{Round: i, Duration: time.Duration(i) * time.Millisecond, Participants: i, Outcome: outcome})
+	}
+	summary := SummarizeRounds(rounds)
+	if summary.Rounds != 100 || summary.Committed != 90 || summary.Failed != 10 {
+		t.Fatalf("unexpected counts: %+v", summary)
+	}
+	if summary.DurationP50 != 50*time.Millisecond || summary.DurationP90 != 90*time.Millisecond || summary.DurationP99 != 99*time.Millisecond {
+		t.Fatalf("unexpected duration percentiles: %+v", summary)
+	}
+	if summary.ParticipantsP50 != 50 || summary.ParticipantsP90 != 90 || summary.ParticipantsP99 != 99 {
+		t.Fatalf("unexpected participant percentiles: %+v", summary)
+	}
+
+	// Nearest rank over three values: p50 is the 2nd, p90 and p99 the 3rd.
+	small := SummarizeRounds([]AggregationRound{{Participants: 7}, {Participants: 1}, {Participants: 4}})
+	if small.ParticipantsP50 != 4 || small.ParticipantsP90 != 7 || small.ParticipantsP99 != 7 {
+		t.Fatalf("unexpected small-run percentiles: %+v", small)
+	}
+	if empty := SummarizeRounds(nil); empty != (AggregationSummary{}) {
+		t.Fatalf("expected zero summary for no rounds, got %+v", empty)
+	}
+}
+
+// TestAggregationHistoryBounded runs 50 rounds, every fifth cancelled, while readers poll the history and metrics concurrently.
+func TestAggregationHistoryBounded(t *testing.T) {
+	const rounds, keep = 50, 20
+	ctx := context.Background()
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1", "peer2", "peer3"}, 30*time.Second)
+	aggregator.SetHistorySize(keep)
+	collector := monitoring.NewCollector(4 * rounds)
+	aggregator.SetCollector(collector)
+	var observed []AggregationRound
+	aggregator.SetRoundObserver(func(round AggregationRound) { observed = append(observed, round) })
+
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if history := aggregator.History(); len(history) > keep {
+					t.Errorf("history holds %d rounds, bound is %d", len(history), keep)
+				}
+				_ = aggregator.HistorySummary()
+				_ = aggregator.GetMetrics()
+			}
+		}()
+	}
+
+	nodes := []string{"node-1", "peer1", "peer2", "peer3"}
+	for r := 1; r <= rounds; r++ {
+		for i, nodeID := range nodes {
+			if err := aggregator.SubmitModel(ctx, nodeID, []byte{byte(r), byte(i)}); err != nil {
+				t.Fatalf("round %d: submit %s: %v", r, nodeID, err)
+			}
+		}
+		roundCtx := ctx
+		if r%5 == 0 {
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+			roundCtx = cancelled
+		}
+		_, err := aggregator.AggregateWithConsensus(roundCtx)
+		if r%5 == 0 && !errors.Is(err, context.Canceled) {
+			t.Fatalf("round %d: expected context.Canceled, got %v", r, err)
+		}
+		if r%5 != 0 && err != nil {
+			t.Fatalf("round %d: %v", r, err)
+		}
+	}
+	close(stop)
+	readers.Wait()
+
+	history := aggregator.History()
+	if len(history) != keep {
+		t.Fatalf("expected %d retained rounds, got %d", keep, len(history))
+	}
+	for i, round := range history {
+		want := rounds - keep + 1 + i
+		if round.Round != want {
+			t.Fatalf("history[%d] is round %d, want %d", i, round.Round, want)
+		}
+		if want%5 == 0 {
+			if round.Outcome != OutcomeFailed || round.Error == "" || round.ConsensusRounds != 0 {
+				t.Fatalf("round %d should have failed before proposing: %+v", want, round)
+			}
+			continue
+		}
+		if round.Outcome != OutcomeCommitted || round.Participants != len(nodes) || round.ConsensusRounds != 1 || round.Duration <= 0 {
+			t.Fatalf("unexpected record for round %d: %+v", want, round)
+		}
+	}
+	summary := aggregator.HistorySummary()
+	if summary.Rounds != keep || summary.Failed != keep/5 || summary.Committed != keep-keep/5 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	if len(observed) != rounds {
+		t.Fatalf("observer saw %d rounds, want %d", len(observed), rounds)
+	}
+	if recorded := collector.GetMetricsByType(monitoring.MetricAggregationRound); len(recorded) != rounds {
+		t.Fatalf("collector recorded %d rounds, want %d", len(recorded), rounds)
+	}
+	if metrics := aggregator.GetMetrics(); metrics.SuccessfulRounds != rounds-rounds/5 {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+}
+
+func TestSetHistorySizeTrimsRetainedRounds(t *testing.T) {
+	aggregator := NewDistributedAggregator("node-1", nil, time.Second)
+	for r := 1; r <= 5; r++ {
+		aggregator.recordRound(AggregationRound{Round: r, StartedAt: time.Now()}, nil)
+	}
+	aggregator.SetHistorySize(2)
+	history := aggregator.History()
+	if len(history) != 2 || history[0].Round != 4 || history[1].Round != 5 {
+		t.Fatalf("expected rounds 4 and 5 retained, got %+v", history)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Digest binds the proposal's round, proposer, weights, and contribution
+// manifest digest into one hex SHA-256 value, so a manifest cannot be
+// swapped without changing what validators vote on.
+func (p *ModelProposal) Digest() string {
+	h := sha256.New()
+	var round [8]byte
+	binary.BigEndian.PutUint64(round[:], uint64(p.Round))
+	h.Write(round[:])
+	h.Write([]byte(p.ProposerID))
+	h.Write([]byte{0})
+	h.Write([]byte(protocol.WeightsDigest(p.Weights)))
+	h.Write([]byte(p.ManifestDigest))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyManifest checks that the attached manifest matches the digest bound
+// into the proposal and belongs to the proposal's round.
+func (p *ModelProposal) VerifyManifest() error {
+	if p.Manifest == nil {
+		if p.ManifestDigest != "" {
+			return fmt.Errorf("%w: digest %s has no manifest", ErrManifestMismatch, p.ManifestDigest)
+		}
+		return nil
+	}
+	if p.Manifest.Round != p.Round {
+		return fmt.Errorf("%w: manifest round %d, proposal round %d", ErrManifestMismatch, p.Manifest.Round, p.Round)
+	}
+	if digest := p.Manifest.Digest(); digest != p.ManifestDigest {
+		return fmt.Errorf("%w: manifest digest %s, bound digest %s", ErrManifestMismatch, digest, p.ManifestDigest)
+	}
+	return nil
+}
+
+// VerifyProposalDigest recomputes the proposal digest, including the bound
+// manifest digest, and compares it with the digest a verifier was given.
+func VerifyProposalDigest(p *ModelProposal, digest string) error {
+	if err := p.VerifyManifest(); err != nil {
+		return err
+	}
+	if computed := p.Digest(); computed != digest {
+		return fmt.Errorf("%w: proposal digest %s, expected %s", ErrManifestMismatch, computed, digest)
+	}
+	return nil
+}
+
+// AttachManifest sets the manifest and its bound digest on the proposal.
+func (p *ModelProposal) AttachManifest(manifest *protocol.ContributionManifest) {
+	p.Manifest = manifest
+	p.ManifestDigest = manifest.Digest()
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"sync"
+)
+
+// Membership is an admitted-member snapshot tagged with the epoch at which
+// the admission layer produced it.
+type Membership struct {
+	Epoch       uint64
+	ActiveNodes []string
+}
+
+// MembershipView supplies the current admitted membership. The Coordinator
+// snapshots it once per proposal so churn during voting cannot move the quorum.
+type MembershipView interface {
+	Snapshot() Membership
+}
+
+// StaticMembershipView is a MembershipView whose membership is replaced
+// explicitly, bumping the epoch on every change.
+type StaticMembershipView struct {
+	mu      sync.RWMutex
+	epoch   uint64
+	members []string
+}
+
+// NewStaticMembershipView creates a view at epoch 1 with the given members.
+func NewStaticMembershipView(members []string) *StaticMembershipView {
+	return &StaticMembershipView{
+		epoch:   1,
+		members: append([]string(nil), members...),
+	}
+}
+
+// SetMembers replaces the admitted membership and advances the epoch.
+func (v *StaticMembershipView) SetMembers(members []string) uint64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.epoch++
+	v.members = append([]string(nil), members...)
+	return v.epoch
+}
+
+// Snapshot returns a copy of the current membership.
+func (v *StaticMembershipView) Snapshot() Membership {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return Membership{
+		Epoch:       v.epoch,
+		ActiveNodes: append([]string(nil), v.members...),
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"sort"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// ConsensusOutcome is how the vote on a proposal ended.
+type ConsensusOutcome struct {
+	ProposalID string
+	Round      int
+	// State is Committed when the approvals reached quorum and Aborted
+	// otherwise.
+	State ConsensusState
+	// Approvals and Required are the approvals counted and the quorum they
+	// had to reach; Rejections counts the rejecting votes.
+	Approvals  int
+	Required   int
+	Rejections int
+	// Exclusions are the updates an aborted proposal's rejecting voters
+	// flagged, for the round's retry to aggregate without. Flags are
+	// counted against the quorum, not the rejecting voters, so a round
+	// that missed quorum through timeouts cannot be steered by the few who
+	// did reject: an update flagged by voters amounting to at least half
+	// the quorum is excluded for the reason most of them gave; one flagged
+	// by fewer is down-weighted by its flaggers' share of the quorum. A
+	// committed proposal carries none.
+	Exclusions batch.Exclusions
+}
+
+// Outcome returns the outcome of proposalID's vote as it stands. Call it
+// once the proposal has closed or failed to reach quorum, and before the
+// coordinator is reset.
+func (c *Coordinator) Outcome(proposalID string) (ConsensusOutcome, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	approvals, required, err := c.tallyLocked(proposalID)
+	if err != nil {
+		return ConsensusOutcome{}, err
+	}
+	outcome := ConsensusOutcome{
+		ProposalID: proposalID,
+		Round:      c.proposals[proposalID].Round,
+		State:      Committed,
+		Approvals:  approvals,
+		Required:   required,
+	}
+	var rejections []*Vote
+	for _, vote := range c.votes[proposalID] {
+		if vote != nil && !vote.Approve && !c.blacklistedLocked(vote.NodeID) {
+			rejections = append(rejections, vote)
+		}
+	}
+	outcome.Rejections = len(rejections)
+	if approvals >= required {
+		return outcome, nil
+	}
+	outcome.State = Aborted
+	outcome.Exclusions = flaggedExclusions(rejections, required)
+	return outcome, nil
+}
+
+// flaggedExclusions turns rejecting votes' flags into exclusions, weighing
+// each update's flags against quorum. Flags with an unregistered reason
+// code, or the included code, are ignored.
+func flaggedExclusions(rejections []*Vote, quorum int) batch.Exclusions {
+	flagged := make(map[string]map[reasons.Code]int)
+	for _, vote := range rejections {
+		for receipt, reason := range vote.Flags {
+			if _, ok := reasons.Lookup(reason); !ok || reason == protocol.ReasonIncluded {
+				continue
+			}
+			if flagged[receipt] == nil {
+				flagged[receipt] = make(map[reasons.Code]int)
+			}
+			flagged[receipt][reason]++
+		}
+	}
+
+	exclusions := batch.Exclusions{}
+	for receipt, byReason := range flagged {
+		flags := 0
+		codes := make([]reasons.Code, 0, len(byReason))
+		for reason, count := range byReason {
+			flags += count
+			codes = append(codes, reason)
+		}
+		if 2*flags < quorum {
+			if exclusions.Downweighted == nil {
+				exclusions.Downweighted = make(map[string]float64)
+			}
+			exclusions.Downweighted[receipt] = 1 - float64(flags)/float64(quorum)
+			continue
+		}
+		// The most given reason, the first in code order among ties.
+		sort.Slice(codes, func(i, j int) bool {
+			if byReason[codes[i]] != byReason[codes[j]] {
+				return byReason[codes[i]] > byReason[codes[j]]
+			}
+			return codes[i] < codes[j]
+		})
+		if exclusions.Excluded == nil {
+			exclusions.Excluded = make(map[string]reasons.Code)
+		}
+		exclusions.Excluded[receipt] = codes[0]
+	}
+	return exclusions
+}
@@ -0,0 +1,313 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// ProbationConfig sets the privileges of newly admitted nodes and how they
+// earn full ones.
+type ProbationConfig struct {
+	// RequiredRounds is how many verified-honest rounds a node must
+	// complete to graduate.
+	RequiredRounds int
+	// VoteWeight is what a probationary approval counts for toward quorum,
+	// from 0 up to but excluding a full vote. Probationary nodes never
+	// count toward the quorum itself, so any positive weight lets them
+	// help reach a threshold they did not raise.
+	VoteWeight float64
+	// UpdateWeight scales a probationary node's aggregation weight, in
+	// (0, 1].
+	UpdateWeight float64
+}
+
+// DefaultProbationConfig returns the probation new nodes serve unless
+// configured otherwise: ten honest rounds, no vote weight, and a tenth of
+// the aggregation weight.
+func DefaultProbationConfig() ProbationConfig {
+	return ProbationConfig{RequiredRounds: 10, VoteWeight: 0, UpdateWeight: 0.1}
+}
+
+// Validate checks that cfg's weights are in range and at least one round
+// is required.
+func (cfg ProbationConfig) Validate() error {
+	if cfg.RequiredRounds < 1 {
+		return fmt.Errorf("%w: probation must require at least one round, got %d", ErrInvalidArgument, cfg.RequiredRounds)
+	}
+	if cfg.VoteWeight < 0 || cfg.VoteWeight >= 1 {
+		return fmt.Errorf("%w: probationary vote weight must be in [0, 1), got %g", ErrInvalidArgument, cfg.VoteWeight)
+	}
+	if cfg.UpdateWeight <= 0 || cfg.UpdateWeight > 1 {
+		return fmt.Errorf("%w: probationary update weight must be in (0, 1], got %g", ErrInvalidArgument, cfg.UpdateWeight)
+	}
+	return nil
+}
+
+// ProbationStatus is one node's standing in its probation.
+type ProbationStatus struct {
+	NodeID         string `json:"node_id"`
+	OnProbation    bool   `json:"on_probation"`
+	HonestRounds   int    `json:"honest_rounds"`
+	RequiredRounds int    `json:"required_rounds"`
+	// FlaggedRounds counts rounds in which the node's update was excluded
+	// as Byzantine; each one resets HonestRounds.
+	FlaggedRounds int `json:"flagged_rounds"`
+	// LastRound is the latest round recorded for the node.
+	LastRound int `json:"last_round,omitempty"`
+}
+
+// Probation tracks the nodes serving probation. A node admitted with Admit
+// may vote, train, and verify, but its approvals count VoteWeight, its
+// updates UpdateWeight, it cannot propose or sit on a verification
+// committee, and it does not count toward quorums. It graduates once its
+// updates have been included in RequiredRounds committed rounds without one
+// being excluded as Byzantine in between. Nodes never admitted, including
+// every node known before probation was configured, hold full privileges.
+type Probation struct {
+	cfg ProbationConfig
+
+	mu    sync.RWMutex
+	nodes map[string]*ProbationStatus
+}
+
+// NewProbation creates an empty probation tracker.
+func NewProbation(cfg ProbationConfig) (*Probation, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Probation{cfg: cfg, nodes: make(map[string]*ProbationStatus)}, nil
+}
+
+// Config returns the probation's configuration.
+func (p *Probation) Config() ProbationConfig {
+	return p.cfg
+}
+
+// Admit puts a newly registered node on probation. It returns false, and
+// changes nothing, for a node already on probation or graduated, so
+// re-registering neither resets nor restarts a probation.
+func (p *Probation) Admit(nodeID string) bool {
+	if nodeID == "" {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, known := p.nodes[nodeID]; known {
+		return false
+	}
+	p.nodes[nodeID] = &ProbationStatus{NodeID: nodeID, OnProbation: true, RequiredRounds: p.cfg.RequiredRounds}
+	return true
+}
+
+// Graduate ends nodeID's probation at once, for nodes an operator vouches
+// for such as a deployment's founding members.
+func (p *Probation) Graduate(nodeID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status, known := p.nodes[nodeID]
+	if !known {
+		status = &ProbationStatus{NodeID: nodeID, RequiredRounds: p.cfg.RequiredRounds}
+		p.nodes[nodeID] = status
+	}
+	status.OnProbation = false
+}
+
+// Remove forgets nodeID, so a node that leaves and is admitted again serves
+// a new probation.
+func (p *Probation) Remove(nodeID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.nodes, nodeID)
+}
+
+// RecordStatus puts a node whose heartbeat reports a degraded TPM back on
+// probation, restarting its count of honest rounds, since its updates are
+// no longer backed by a hardware quote. It returns whether the node was
+// put back; a node already on probation is left as it is.
+func (p *Probation) RecordStatus(update protocol.StatusUpdate) bool {
+	if !update.AttestationDegraded || update.NodeID == "" {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status, known := p.nodes[update.NodeID]
+	if known && status.OnProbation {
+		return false
+	}
+	if !known {
+		status = &ProbationStatus{NodeID: update.NodeID}
+		p.nodes[update.NodeID] = status
+	}
+	status.OnProbation = true
+	status.HonestRounds = 0
+	status.RequiredRounds = p.cfg.RequiredRounds
+	return true
+}
+
+// OnProbation reports whether nodeID is serving probation.
+func (p *Probation) OnProbation(nodeID string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	status, known := p.nodes[nodeID]
+	return known && status.OnProbation
+}
+
+// UpdateWeight is the factor scaling nodeID's aggregation weight; pass it
+// to batch.Aggregator.SetWeightScale.
+func (p *Probation) UpdateWeight(nodeID string) float64 {
+	if p.OnProbation(nodeID) {
+		return p.cfg.UpdateWeight
+	}
+	return 1
+}
+
+// Status returns nodeID's probation status, or false for a node never
+// admitted or graduated.
+func (p *Probation) Status(nodeID string) (ProbationStatus, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	status, known := p.nodes[nodeID]
+	if !known {
+		return ProbationStatus{}, false
+	}
+	return *status, true
+}
+
+// Statuses returns every tracked node's status, sorted by node ID.
+func (p *Probation) Statuses() []ProbationStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	statuses := make([]ProbationStatus, 0, len(p.nodes))
+	for _, status := range p.nodes {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].NodeID < statuses[j].NodeID })
+	return statuses
+}
+
+// RecordRound credits probationary nodes with the committed round manifest
+// describes and returns the nodes that graduated, sorted. An included
+// update is a verified-honest round; one the Byzantine detector, norm
+// filter, or Multi-Krum excluded resets the node's count. Other
+// exclusions, such as a stale update, neither credit nor reset. A round is
+// recorded at most once per node.
+func (p *Probation) RecordRound(manifest *protocol.ContributionManifest) []string {
+	if manifest == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var graduated []string
+	for _, entry := range manifest.Entries {
+		status, known := p.nodes[entry.NodeID]
+		if !known || !status.OnProbation || manifest.Round <= status.LastRound {
+			continue
+		}
+		status.LastRound = manifest.Round
+		switch {
+		case entry.Included:
+			status.HonestRounds++
+		case byzantineExclusion(entry.Reason):
+			status.HonestRounds = 0
+			status.FlaggedRounds++
+		}
+		if status.HonestRounds >= p.cfg.RequiredRounds {
+			status.OnProbation = false
+			graduated = append(graduated, entry.NodeID)
+		}
+	}
+	sort.Strings(graduated)
+	return graduated
+}
+
+// byzantineExclusion reports whether an update excluded for reason was
+// judged malicious rather than late or empty.
+func byzantineExclusion(reason reasons.Code) bool {
+	switch reason {
+	case protocol.ReasonNormOutlier, protocol.ReasonDetected, protocol.ReasonKrumRejected, protocol.ReasonInvalidStatement:
+		return true
+	}
+	return false
+}
+
+// SetProbation attaches p to the coordinator: probationary nodes are left
+// out of every quorum, their approvals count p's VoteWeight, they cannot
+// propose, and each committed proposal's manifest is recorded toward their
+// graduation. Rounds already open keep their quorum.
+func (c *Coordinator) SetProbation(p *Probation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probation = p
+	c.recountLocked()
+}
+
+// onProbationLocked reports whether nodeID is serving probation. Callers
+// must hold c.mu.
+func (c *Coordinator) onProbationLocked(nodeID string) bool {
+	return c.probation != nil && c.probation.OnProbation(nodeID)
+}
+
+// approvalWeightOf is what an approval by nodeID counts for toward
+// snapshot's quorum: a full vote, or the probationary vote weight for a
+// node on probation when the proposal was made.
+func approvalWeightOf(snapshot *RoundMembershipSnapshot, nodeID string) float64 {
+	if snapshot != nil && snapshot.Probationary[nodeID] {
+		return snapshot.ProbationVoteWeight
+	}
+	return 1
+}
+
+// wholeVotes is the number of full votes weight amounts to. The epsilon
+// keeps fractional weights that sum to a whole number from falling short
+// of it by a rounding error.
+func wholeVotes(weight float64) int {
+	return int(math.Floor(weight + 1e-9))
+}
+
+// probationaryLocked returns the nodes of nodes serving probation, nil if
+// none are. Callers must hold c.mu.
+func (c *Coordinator) probationaryLocked(nodes map[string]bool) map[string]bool {
+	if c.probation == nil {
+		return nil
+	}
+	var probationary map[string]bool
+	for nodeID := range nodes {
+		if c.probation.OnProbation(nodeID) {
+			if probationary == nil {
+				probationary = make(map[string]bool)
+			}
+			probationary[nodeID] = true
+		}
+	}
+	return probationary
+}
+
+// recountLocked recomputes the node count and quorum for rounds not yet
+// proposed. Callers must hold c.mu.
+func (c *Coordinator) recountLocked() {
+	c.totalNodes = countActiveNodes(c.activeNodes, c.probationaryLocked(c.activeNodes))
+	if !c.asyncMode {
+		c.quorumSize = c.quorumForNodes(c.totalNodes)
+	}
+}
+
+// recordProbationLocked credits probationary nodes with the committed
+// proposalID's manifest and recounts the quorum for later rounds, which
+// graduates join. Callers must hold c.mu.
+func (c *Coordinator) recordProbationLocked(proposalID string) {
+	proposal := c.proposals[proposalID]
+	if c.probation == nil || proposal == nil || proposal.Manifest == nil {
+		return
+	}
+	if graduated := c.probation.RecordRound(proposal.Manifest); len(graduated) > 0 {
+		c.recountLocked()
+	}
+}
@@ -0,0 +1,216 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+func probationManifest(round int, entries ...protocol.ContributionEntry) *protocol.ContributionManifest {
+	return &protocol.ContributionManifest{Round: round, Entries: entries}
+}
+
+func included(nodeID string) protocol.ContributionEntry {
+	return protocol.ContributionEntry{NodeID: nodeID, SampleCount: 10, Included: true, Reason: protocol.ReasonIncluded}
+}
+
+func excluded(nodeID string, reason reasons.Code) protocol.ContributionEntry {
+	return protocol.ContributionEntry{NodeID: nodeID, SampleCount: 10, Reason: reason}
+}
+
+func TestProbationGraduatesAfterHonestRounds(t *testing.T) {
+	probation, err := NewProbation(ProbationConfig{RequiredRounds: 3, UpdateWeight: 0.2})
+	if err != nil {
+		t.Fatalf("new probation: %v", err)
+	}
+	if !probation.Admit("new-1") || !probation.Admit("new-2") || probation.Admit("new-1") {
+		t.Fatal("admit should enroll each node once")
+	}
+	if probation.OnProbation("founder") || probation.UpdateWeight("founder") != 1 || probation.UpdateWeight("new-1") != 0.2 {
+		t.Fatal("only admitted nodes serve probation")
+	}
+
+	probation.RecordRound(probationManifest(1, included("new-1"), included("new-2")))
+	// new-2's update is caught by the Byzantine detector, resetting its
+	// count; a stale update neither credits nor resets.
+	probation.RecordRound(probationManifest(2, included("new-1"), excluded("new-2", protocol.ReasonDetected)))
+	probation.RecordRound(probationManifest(3, excluded("new-1", protocol.ReasonStaleUpdate), included("new-2")))
+	// Recording a round twice credits it once.
+	probation.RecordRound(probationManifest(3, included("new-1")))
+	if graduated := probation.RecordRound(probationManifest(4, included("new-1"), included("new-2"))); !reflect.DeepEqual(graduated, []string{"new-1"}) {
+		t.Fatalf("graduated = %v, want new-1", graduated)
+	}
+
+	status, _ := probation.Status("new-2")
+	if !status.OnProbation || status.HonestRounds != 2 || status.FlaggedRounds != 1 || status.LastRound != 4 {
+		t.Fatalf("new-2 status = %+v", status)
+	}
+	if probation.OnProbation("new-1") || probation.Admit("new-1") {
+		t.Fatal("a graduate is not put back on probation by registering again")
+	}
+	probation.Remove("new-1")
+	if !probation.Admit("new-1") {
+		t.Fatal("a node that left serves a new probation")
+	}
+
+	for _, bad := range []ProbationConfig{{RequiredRounds: 0, UpdateWeight: 1}, {RequiredRounds: 1, VoteWeight: 1, UpdateWeight: 1}, {RequiredRounds: 1}} {
+		if _, err := NewProbation(bad); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("accepted %+v", bad)
+		}
+	}
+}
+
+// newProbationCoordinator returns a four-member coordinator with sybils
+// admitted on probation alongside.
+func newProbationCoordinator(t *testing.T, cfg ProbationConfig, sybils ...string) (*Coordinator, *Probation) {
+	t.Helper()
+	probation, err := NewProbation(cfg)
+	if err != nil {
+		t.Fatalf("new probation: %v", err)
+	}
+	c := NewCoordinator("node-1", 4, 5*time.Second)
+	c.SetProbation(probation)
+	for _, nodeID := range sybils {
+		probation.Admit(nodeID)
+		c.JoinNode(nodeID)
+	}
+	return c, probation
+}
+
+func TestProbationaryNodesDoNotMoveQuorum(t *testing.T) {
+	ctx := context.Background()
+	sybils := []string{"sybil-1", "sybil-2", "sybil-3", "sybil-4", "sybil-5", "sybil-6"}
+	c, _ := newProbationCoordinator(t, DefaultProbationConfig(), sybils...)
+	if status := c.GetRuntimeStatus(); status["total_nodes"] != 4 || status["quorum_size"] != 3 || status["probationary_node_count"] != 6 {
+		t.Fatalf("runtime status = %+v", status)
+	}
+
+	if _, err := c.ProposeModel(ctx, &ModelProposal{Round: 1, ProposerID: "sybil-1", Timestamp: time.Now()}); !errors.Is(err, ErrProbationaryNode) {
+		t.Fatalf("probationary proposer: %v", err)
+	}
+	proposalID, err := c.ProposeModel(ctx, &ModelProposal{Round: 1, ProposerID: "node-1", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	membership, _ := c.GetRoundMembership(proposalID)
+	if membership.ActiveCount != 4 || membership.QuorumSize != 3 || len(membership.Probationary) != 6 {
+		t.Fatalf("membership = %+v", membership)
+	}
+
+	// The sybils' approvals carry no weight; the honest quorum is
+	// unaffected by their rejections.
+	for _, nodeID := range sybils {
+		if err := c.CastVote(ctx, &Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+	if approvals, required, _ := c.QuorumProgress(proposalID); approvals != 0 || required != 3 {
+		t.Fatalf("progress after sybil approvals = %d/%d", approvals, required)
+	}
+	for _, nodeID := range []string{"node-1", "member-1", "member-2"} {
+		if err := c.CastVote(ctx, &Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+	if err := c.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+}
+
+func TestProbationaryVoteWeightIsCapped(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newProbationCoordinator(t, ProbationConfig{RequiredRounds: 5, VoteWeight: 0.25, UpdateWeight: 1}, "sybil-1", "sybil-2", "sybil-3", "sybil-4")
+	proposalID, err := c.ProposeModel(ctx, &ModelProposal{Round: 1, ProposerID: "node-1", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	for _, nodeID := range []string{"node-1", "member-1", "sybil-1", "sybil-2", "sybil-3"} {
+		if err := c.CastVote(ctx, &Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+	// Three quarter votes do not make a whole one.
+	if approvals, _, _ := c.QuorumProgress(proposalID); approvals != 2 {
+		t.Fatalf("approvals = %d, want 2", approvals)
+	}
+	if err := c.CastVote(ctx, &Vote{NodeID: "sybil-4", ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+	if approvals, required, _ := c.QuorumProgress(proposalID); approvals != 3 || required != 3 {
+		t.Fatalf("progress = %d/%d, want 3/3", approvals, required)
+	}
+}
+
+func TestCommittedManifestsGraduateProbationaryNodes(t *testing.T) {
+	ctx := context.Background()
+	c, probation := newProbationCoordinator(t, ProbationConfig{RequiredRounds: 2, UpdateWeight: 0.5}, "new-1", "new-2")
+
+	for round := 1; round <= 2; round++ {
+		proposal := &ModelProposal{Round: round, Weights: []byte("weights"), ProposerID: "node-1", Timestamp: time.Unix(int64(round), 0)}
+		proposal.AttachManifest(probationManifest(round, included("member-1"), included("new-1"), excluded("new-2", protocol.ReasonNormOutlier)))
+		proposalID, err := c.ProposeModel(ctx, proposal)
+		if err != nil {
+			t.Fatalf("round %d propose: %v", round, err)
+		}
+		for _, nodeID := range []string{"node-1", "member-1", "member-2"} {
+			if err := c.CastVote(ctx, &Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+				t.Fatalf("round %d vote %s: %v", round, nodeID, err)
+			}
+		}
+		if err := c.CommitModel(ctx, proposalID); err != nil {
+			t.Fatalf("round %d commit: %v", round, err)
+		}
+		c.Reset()
+	}
+
+	if probation.OnProbation("new-1") || !probation.OnProbation("new-2") {
+		t.Fatalf("statuses = %+v", probation.Statuses())
+	}
+	// The graduate counts toward later quorums and may propose.
+	if status := c.GetRuntimeStatus(); status["total_nodes"] != 5 || status["quorum_size"] != 4 {
+		t.Fatalf("runtime status after graduation = %+v", status)
+	}
+	if _, err := c.ProposeModel(ctx, &ModelProposal{Round: 3, ProposerID: "new-1", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("graduate proposes: %v", err)
+	}
+}
+
+func TestDegradedAttestationReturnsNodesToProbation(t *testing.T) {
+	probation, err := NewProbation(ProbationConfig{RequiredRounds: 2, UpdateWeight: 0.25})
+	if err != nil {
+		t.Fatalf("new probation: %v", err)
+	}
+	probation.Admit("edge-1")
+	probation.RecordRound(probationManifest(1, included("edge-1")))
+	probation.RecordRound(probationManifest(2, included("edge-1")))
+	if probation.OnProbation("edge-1") {
+		t.Fatal("edge-1 should have graduated")
+	}
+
+	if probation.RecordStatus(protocol.StatusUpdate{NodeID: "edge-1", Status: "training"}) {
+		t.Fatal("a healthy heartbeat must not demote")
+	}
+	if !probation.RecordStatus(protocol.StatusUpdate{NodeID: "edge-1", AttestationDegraded: true}) {
+		t.Fatal("a degraded heartbeat should put edge-1 back on probation")
+	}
+	if !probation.OnProbation("edge-1") || probation.UpdateWeight("edge-1") != 0.25 {
+		t.Fatalf("edge-1 status = %+v", probation.Statuses())
+	}
+	if probation.RecordStatus(protocol.StatusUpdate{NodeID: "edge-1", AttestationDegraded: true}) {
+		t.Fatal("repeated degraded heartbeats must not restart the probation again")
+	}
+	if !probation.RecordStatus(protocol.StatusUpdate{NodeID: "founder", AttestationDegraded: true}) || !probation.OnProbation("founder") {
+		t.Fatal("a node never admitted loses its full privileges too")
+	}
+
+	probation.RecordRound(probationManifest(3, included("edge-1")))
+	probation.RecordRound(probationManifest(4, included("edge-1")))
+	if probation.OnProbation("edge-1") {
+		t.Fatal("edge-1 should graduate again after its recovered rounds")
+	}
+}
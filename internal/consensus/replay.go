@@ -0,0 +1,130 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// maxReplayEntries bounds the replay log; older commits are dropped first.
+const maxReplayEntries = 4096
+
+// ReplayEntry records one committed proposal: enough for a replacement
+// coordinator or an auditor to account for past rounds without their
+// weights.
+type ReplayEntry struct {
+	Round           int       `json:"round"`
+	ProposalID      string    `json:"proposal_id"`
+	ProposerID      string    `json:"proposer_id"`
+	WeightsDigest   string    `json:"weights_digest"`
+	ManifestDigest  string    `json:"manifest_digest,omitempty"`
+	Approvals       []string  `json:"approvals"`
+	QuorumSize      int       `json:"quorum_size"`
+	MembershipEpoch uint64    `json:"membership_epoch"`
+	CommittedAt     time.Time `json:"committed_at"`
+	// VoteDigests maps every voter on the proposal to the VoteDigest of
+	// the vote that was counted.
+	VoteDigests map[string]string `json:"vote_digests,omitempty"`
+	// Equivocations holds the equivocation proofs found since the previous
+	// commit, when an equivocation detector is attached.
+	Equivocations []EquivocationProof `json:"equivocations,omitempty"`
+	// VoteChanges holds the votes replaced by a newer vote of the opposite
+	// choice, when SetVoteFreshness allows refreshing votes.
+	VoteChanges []VoteChange `json:"vote_changes,omitempty"`
+}
+
+// recordReplayLocked appends the commit of proposalID to the replay log.
+func (c *Coordinator) recordReplayLocked(proposalID string, quorumSize int) {
+	proposal := c.proposals[proposalID]
+	if proposal == nil {
+		return
+	}
+	entry := ReplayEntry{
+		Round:          proposal.Round,
+		ProposalID:     proposalID,
+		ProposerID:     proposal.ProposerID,
+		WeightsDigest:  protocol.WeightsDigest(proposal.Weights),
+		ManifestDigest: proposal.ManifestDigest,
+		QuorumSize:     quorumSize,
+		CommittedAt:    time.Now().UTC(),
+	}
+	for _, vote := range c.votes[proposalID] {
+		if vote == nil {
+			continue
+		}
+		if entry.VoteDigests == nil {
+			entry.VoteDigests = make(map[string]string)
+		}
+		entry.VoteDigests[vote.NodeID] = VoteDigest(vote)
+		if vote.Approve {
+			entry.Approvals = append(entry.Approvals, vote.NodeID)
+		}
+	}
+	sort.Strings(entry.Approvals)
+	if snapshot := c.roundMembership[proposalID]; snapshot != nil {
+		entry.MembershipEpoch = snapshot.Epoch
+	}
+	if c.equivocation != nil {
+		entry.Equivocations = c.equivocation.takeEvidence()
+	}
+	entry.VoteChanges = append([]VoteChange(nil), c.voteChanges[proposalID]...)
+
+	c.replay = append(c.replay, entry)
+	if len(c.replay) > maxReplayEntries {
+		c.replay = append(c.replay[:0], c.replay[len(c.replay)-maxReplayEntries:]...)
+	}
+}
+
+// ReplayLog returns the committed proposals in commit order. Unlike
+// proposals and votes it survives Reset.
+func (c *Coordinator) ReplayLog() []ReplayEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]ReplayEntry, len(c.replay))
+	for i, entry := range c.replay {
+		out[i] = cloneReplayEntry(entry)
+	}
+	return out
+}
+
+// RestoreReplayLog loads entries into a coordinator that has not committed
+// anything yet, typically one replacing a failed host.
+func (c *Coordinator) RestoreReplayLog(entries []ReplayEntry) error {
+	for i, entry := range entries {
+		if entry.ProposalID == "" || entry.WeightsDigest == "" {
+			return fmt.Errorf("%w: replay entry %d lacks a proposal ID or weights digest", ErrInvalidArgument, i)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.replay) > 0 {
+		return fmt.Errorf("%w: replay log already has %d entries", ErrInvalidState, len(c.replay))
+	}
+	c.replay = make([]ReplayEntry, len(entries))
+	for i, entry := range entries {
+		c.replay[i] = cloneReplayEntry(entry)
+	}
+	return nil
+}
+
+func cloneReplayEntry(entry ReplayEntry) ReplayEntry {
+	entry.Approvals = append([]string(nil), entry.Approvals...)
+	if entry.VoteDigests != nil {
+		digests := make(map[string]string, len(entry.VoteDigests))
+		for nodeID, digest := range entry.VoteDigests {
+			digests[nodeID] = digest
+		}
+		entry.VoteDigests = digests
+	}
+	entry.Equivocations = append([]EquivocationProof(nil), entry.Equivocations...)
+	entry.VoteChanges = append([]VoteChange(nil), entry.VoteChanges...)
+	return entry
+}
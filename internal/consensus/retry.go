@@ -0,0 +1,120 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how AggregateWithConsensus retries a round that
+// missed quorum or timed out. Other failures, such as invalid signatures,
+// manifest mismatches, or a fenced leader, are never retried. The zero
+// policy makes a single attempt.
+type RetryPolicy struct {
+	// MaxAttempts bounds the attempts per round, the first included.
+	MaxAttempts int
+	// BaseBackoff is the wait before the second attempt; each later wait
+	// doubles, up to MaxBackoff when it is positive.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Jitter is the fraction, in [0, 0.5], of each wait drawn at random, so
+	// nodes failing together do not retry in lockstep. Below MaxBackoff each
+	// wait still exceeds the one before.
+	Jitter float64
+	// AttemptTimeout bounds each attempt; zero leaves attempts bounded only
+	// by the caller's context.
+	AttemptTimeout time.Duration
+	// Recollect re-aggregates before each retry, taking in updates that
+	// arrived during the wait. Otherwise retries re-propose the first
+	// attempt's aggregate, unless the rejected proposal's voters flagged
+	// updates: a retry is always re-aggregated without those; see
+	// Coordinator.Outcome.
+	Recollect bool
+}
+
+// SetRetryPolicy sets how AggregateWithConsensus retries failed rounds.
+func (da *DistributedAggregator) SetRetryPolicy(policy RetryPolicy) {
+	if policy.Jitter < 0 {
+		policy.Jitter = 0
+	}
+	if policy.Jitter > 0.5 {
+		policy.Jitter = 0.5
+	}
+	da.mu.Lock()
+	defer da.mu.Unlock()
+	da.retry = policy
+}
+
+// backoff returns the wait after the given failed attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.BaseBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		// #nosec G404 -- retry jitter only spreads load; it need not be unpredictable
+		wait -= time.Duration(p.Jitter * rand.Float64() * float64(wait))
+	}
+	return wait
+}
+
+// transientRoundFailure reports whether an attempt's err may clear on
+// retry: the proposal missed quorum, or the attempt ran out of time while
+// ctx, the whole round's context, has not.
+func transientRoundFailure(ctx context.Context, err error) bool {
+	var quorumErr *ErrQuorumNotReached
+	if errors.As(err, &quorumErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil
+}
+
+func (da *DistributedAggregator) aggregateWithRetry(ctx context.Context) ([]byte, error) {
+	da.mu.RLock()
+	policy := da.retry
+	da.mu.RUnlock()
+
+	round := da.nextRound()
+	prior := &roundAggregate{}
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if policy.AttemptTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, policy.AttemptTimeout)
+		}
+		aggregated, err := da.attemptRound(attemptCtx, nil, round, attempt, prior)
+		cancel()
+		if err == nil || attempt >= policy.MaxAttempts || !transientRoundFailure(ctx, err) {
+			return aggregated, err
+		}
+
+		// Flagged updates are read off the failed proposal before the reset
+		// closes it, and carried into every later attempt.
+		exclusions, reaggregate := prior.exclusions, policy.Recollect
+		if outcome, err := da.coordinator.Outcome(prior.proposalID); err == nil && !outcome.Exclusions.Empty() {
+			exclusions, reaggregate = exclusions.Merge(outcome.Exclusions), true
+		}
+		// The failed attempt's proposal must not block the retry's.
+		da.coordinator.Reset()
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("round %d abandoned after %d attempts: %w", round, attempt, ctx.Err())
+		case <-timer.C:
+		}
+		if reaggregate {
+			prior = &roundAggregate{exclusions: exclusions}
+		}
+		da.mu.Lock()
+		da.metrics.RetriedAttempts++
+		da.mu.Unlock()
+	}
+}
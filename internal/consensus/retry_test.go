@@ -0,0 +1,194 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// failFirstProposals drops every peer vote on the first n proposals, so
+// they miss quorum, and records when each proposal's self vote arrived.
+type failFirstProposals struct {
+	nodeID string
+	n      int
+
+	mu        sync.Mutex
+	proposals []time.Time
+}
+
+func (f *failFirstProposals) middleware(next VoteHandler) VoteHandler {
+	return func(ctx context.Context, vote *Vote) error {
+		f.mu.Lock()
+		if vote.NodeID == f.nodeID {
+			f.proposals = append(f.proposals, time.Now())
+		}
+		failing := len(f.proposals) <= f.n
+		f.mu.Unlock()
+		if failing && vote.NodeID != f.nodeID {
+			return &VoteRejection{Middleware: "test", Reason: "partitioned", Err: errors.New("peer unreachable"), Drop: true}
+		}
+		return next(ctx, vote)
+	}
+}
+
+func TestAggregateWithConsensusRetriesTransientFailures(t *testing.T) {
+	ctx := context.Background()
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1", "peer2", "peer3"}, 30*time.Second)
+	partition := &failFirstProposals{nodeID: "node-1", n: 2}
+	aggregator.coordinator.SetVoteMiddleware(append([]VoteMiddleware{partition.middleware}, DefaultVoteChain(aggregator.coordinator)...)...)
+	aggregator.SetRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseBackoff: 20 * time.Millisecond, Jitter: 0.25, Recollect: true})
+	for _, nodeID := range []string{"node-1", "peer1"} {
+		if err := aggregator.SubmitModel(ctx, nodeID, []byte{2, 4}); err != nil {
+			t.Fatalf("submit %s: %v", nodeID, err)
+		}
+	}
+
+	if _, err := aggregator.AggregateWithConsensus(ctx); err != nil {
+		t.Fatalf("expected the third attempt to commit, got %v", err)
+	}
+
+	partition.mu.Lock()
+	proposals := append([]time.Time(nil), partition.proposals...)
+	partition.mu.Unlock()
+	if len(proposals) != 3 {
+		t.Fatalf("expected exactly 3 proposals, got %d", len(proposals))
+	}
+	first, second := proposals[1].Sub(proposals[0]), proposals[2].Sub(proposals[1])
+	if first < 15*time.Millisecond || second <= first {
+		t.Fatalf("expected growing backoff gaps of at least 15ms, got %s then %s", first, second)
+	}
+
+	history := aggregator.History()
+	if len(history) != 3 {
+		t.Fatalf("expected a history record per attempt, got %+v", history)
+	}
+	for i, record := range history {
+		wantOutcome := OutcomeFailed
+		if i == 2 {
+			wantOutcome = OutcomeCommitted
+		}
+		if record.Round != 1 || record.Attempt != i+1 || record.Outcome != wantOutcome {
+			t.Fatalf("attempt %d recorded as %+v", i+1, record)
+		}
+	}
+	if metrics := aggregator.GetMetrics(); metrics.RetriedAttempts != 2 || metrics.SuccessfulRounds != 1 {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+}
+
+func TestAggregateWithConsensusStopsAtAttemptLimit(t *testing.T) {
+	ctx := context.Background()
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1", "peer2", "peer3"}, 30*time.Second)
+	partition := &failFirstProposals{nodeID: "node-1", n: 10}
+	aggregator.coordinator.SetVoteMiddleware(append([]VoteMiddleware{partition.middleware}, DefaultVoteChain(aggregator.coordinator)...)...)
+	aggregator.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond})
+	_ = aggregator.SubmitModel(ctx, "node-1", []byte{2, 4})
+
+	_, err := aggregator.AggregateWithConsensus(ctx)
+	var quorumErr *ErrQuorumNotReached
+	if !errors.As(err, &quorumErr) {
+		t.Fatalf("expected ErrQuorumNotReached after the last attempt, got %v", err)
+	}
+	if len(partition.proposals) != 2 {
+		t.Fatalf("expected 2 proposals, got %d", len(partition.proposals))
+	}
+
+}
+
+func TestAggregateWithConsensusDoesNotRetryPermanentFailures(t *testing.T) {
+	ctx := context.Background()
+	aggregator := NewDistributedAggregator("node-1", []string{"peer1"}, 30*time.Second)
+	aggregator.SetRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseBackoff: time.Millisecond})
+	aggregator.coordinator.SetLeaderGate(func() error { return ErrNotLeader })
+	_ = aggregator.SubmitModel(ctx, "node-1", []byte{2, 4})
+
+	if _, err := aggregator.AggregateWithConsensus(ctx); !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("expected ErrNotLeader, got %v", err)
+	}
+	if history := aggregator.History(); len(history) != 1 {
+		t.Fatalf("expected a single attempt, got %+v", history)
+	}
+}
+
+func TestRetryPolicyBackoffGrowsToCap(t *testing.T) {
+	policy := RetryPolicy{BaseBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 3: 40 * time.Millisecond, 4: 50 * time.Millisecond, 30: 50 * time.Millisecond} {
+		if got := policy.backoff(attempt); got != want {
+			t.Fatalf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(2); got <= 10*time.Millisecond || got > 20*time.Millisecond {
+			t.Fatalf("jittered backoff(2) = %s, want (10ms, 20ms]", got)
+		}
+	}
+}
+
+func TestOutcomeExcludesUpdatesMostRejectersFlag(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-1", 5, 5*time.Second)
+	proposalID, err := coord.ProposeModel(ctx, &ModelProposal{Round: 3, Weights: []byte{1}, ProposerID: "node-1", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	for voter, flags := range map[string]map[string]reasons.Code{
+		"member-1": {"receipt-poison": reasons.ByzantineDetected, "receipt-odd": reasons.NormOutlier},
+		"member-2": {"receipt-poison": reasons.ByzantineDetected},
+		"member-3": {"receipt-poison": reasons.NormOutlier, "receipt-made-up": "not_a_reason"},
+		"member-4": nil,
+	} {
+		if err := coord.CastVote(ctx, &Vote{NodeID: voter, ProposalID: proposalID, Flags: flags, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote %s: %v", voter, err)
+		}
+	}
+
+	outcome, err := coord.Outcome(proposalID)
+	if err != nil {
+		t.Fatalf("outcome: %v", err)
+	}
+	if outcome.State != Aborted || outcome.Round != 3 || outcome.Rejections != 4 {
+		t.Fatalf("outcome = %+v", outcome)
+	}
+	// Three of four rejecters flagged the poison, most as Byzantine; one
+	// flagged the odd update, which is down-weighted by the other three.
+	if len(outcome.Exclusions.Excluded) != 1 || outcome.Exclusions.Excluded["receipt-poison"] != reasons.ByzantineDetected {
+		t.Fatalf("excluded = %v", outcome.Exclusions.Excluded)
+	}
+	if len(outcome.Exclusions.Downweighted) != 1 || outcome.Exclusions.Downweighted["receipt-odd"] != 0.75 {
+		t.Fatalf("down-weighted = %v", outcome.Exclusions.Downweighted)
+	}
+
+	// A round that missed quorum through timeouts: the lone rejecter's
+	// flags fall short of half the quorum, so nothing is excluded.
+	coord.Reset()
+	lone, err := coord.ProposeModel(ctx, &ModelProposal{Round: 4, Weights: []byte{2}, ProposerID: "node-1", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: lone, Flags: map[string]reasons.Code{"receipt-honest": reasons.ByzantineDetected}, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+	outcome, err = coord.Outcome(lone)
+	if err != nil {
+		t.Fatalf("outcome: %v", err)
+	}
+	if len(outcome.Exclusions.Excluded) != 0 || outcome.Exclusions.Downweighted["receipt-honest"] != 1-1/float64(outcome.Required) {
+		t.Fatalf("lone rejecter's exclusions = %+v", outcome.Exclusions)
+	}
+
+	// A vote's flags are signed with it.
+	flagged := &Vote{NodeID: "member-1", ProposalID: proposalID, Timestamp: time.Now()}
+	bare := VoteDigest(flagged)
+	flagged.Flags = map[string]reasons.Code{"receipt-poison": reasons.ByzantineDetected}
+	if VoteDigest(flagged) == bare {
+		t.Fatal("vote signing bytes do not cover flags")
+	}
+}
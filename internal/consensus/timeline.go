@@ -0,0 +1,238 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// QuorumRisk sets when closing rounds are flagged at risk of missing
+// quorum: once Rounds consecutive proposals took more than Fraction of the
+// round budget to reach quorum, or closed without it. The budget is the
+// coordinator's timeout unless Budget sets one.
+type QuorumRisk struct {
+	Budget   time.Duration
+	Fraction float64
+	Rounds   int
+}
+
+// DefaultQuorumRisk returns the risk rule coordinators start with: quorum
+// later than 80% of the budget for three rounds running.
+func DefaultQuorumRisk() QuorumRisk {
+	return QuorumRisk{Fraction: 0.8, Rounds: 3}
+}
+
+// voteTimes records when a proposal opened and its votes arrived.
+type voteTimes struct {
+	proposed   time.Time
+	arrivals   []time.Time
+	halfQuorum time.Time
+	quorum     time.Time
+	late       int
+}
+
+// SetQuorumRisk replaces the rule flagging rounds at risk of missing
+// quorum. It restarts the count of consecutive slow rounds.
+func (c *Coordinator) SetQuorumRisk(risk QuorumRisk) error {
+	if risk.Budget < 0 || risk.Fraction <= 0 || risk.Fraction > 1 || risk.Rounds < 1 {
+		return fmt.Errorf("%w: quorum risk budget %v, fraction %g, rounds %d", ErrInvalidArgument, risk.Budget, risk.Fraction, risk.Rounds)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.quorumRisk = risk
+	c.slowRounds = 0
+	return nil
+}
+
+// SetTimelineObserver calls observe with each proposal's vote timeline
+// when CommitModel closes it. The observer runs without the coordinator's
+// lock held.
+func (c *Coordinator) SetTimelineObserver(observe func(protocol.VoteTimeline)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timelineObserver = observe
+}
+
+// SetCollector records each closed proposal's vote timeline into
+// collector, labelled with its round.
+func (c *Coordinator) SetCollector(collector *monitoring.Collector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.collector = collector
+}
+
+// VoteTimeline returns proposalID's vote timeline: as it closed, once
+// CommitModel has closed it, and so far otherwise.
+func (c *Coordinator) VoteTimeline(proposalID string) (protocol.VoteTimeline, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if timeline, ok := c.closedTimelines[proposalID]; ok {
+		return timeline, nil
+	}
+	if c.voteTimes[proposalID] == nil {
+		return protocol.VoteTimeline{}, c.missingProposalLocked(proposalID)
+	}
+	return c.timelineLocked(proposalID), nil
+}
+
+// QuorumAtRisk reports whether the last closed proposal was flagged at
+// risk of missing quorum.
+func (c *Coordinator) QuorumAtRisk() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.slowRounds >= c.quorumRisk.Rounds
+}
+
+// openTimelineLocked starts proposalID's timeline. Callers must hold c.mu.
+func (c *Coordinator) openTimelineLocked(proposalID string) {
+	c.voteTimes[proposalID] = &voteTimes{proposed: c.nowLocked()}
+}
+
+// timeVoteLocked records a new voter's arrival on proposalID and the
+// stages its approvals have reached. Callers must hold c.mu.
+func (c *Coordinator) timeVoteLocked(proposalID string) {
+	times := c.voteTimes[proposalID]
+	if times == nil {
+		return
+	}
+	now := c.nowLocked()
+	times.arrivals = append(times.arrivals, now)
+	if !times.quorum.IsZero() {
+		times.late++
+	}
+	c.timeQuorumLocked(proposalID)
+}
+
+// timeQuorumLocked records when proposalID's approvals first reach half its
+// quorum and its quorum. Callers must hold c.mu.
+func (c *Coordinator) timeQuorumLocked(proposalID string) {
+	times := c.voteTimes[proposalID]
+	if times == nil || !times.quorum.IsZero() {
+		return
+	}
+	approvals, required, err := c.tallyLocked(proposalID)
+	if err != nil {
+		return
+	}
+	now := c.nowLocked()
+	if times.halfQuorum.IsZero() && 2*approvals >= required {
+		times.halfQuorum = now
+	}
+	if approvals >= required {
+		times.quorum = now
+	}
+}
+
+// timelineLocked computes proposalID's timeline so far. Callers must hold
+// c.mu.
+func (c *Coordinator) timelineLocked(proposalID string) protocol.VoteTimeline {
+	times := c.voteTimes[proposalID]
+	timeline := protocol.VoteTimeline{
+		ProposalID: proposalID,
+		Votes:      len(times.arrivals),
+		LateVotes:  times.late,
+		Budget:     c.quorumRisk.Budget,
+	}
+	if timeline.Budget == 0 {
+		timeline.Budget = c.timeout
+	}
+	if proposal := c.proposals[proposalID]; proposal != nil {
+		timeline.Round = proposal.Round
+	}
+	if snapshot := c.roundMembership[proposalID]; snapshot != nil {
+		timeline.QuorumSize = snapshot.QuorumSize
+	}
+	if len(times.arrivals) > 0 {
+		timeline.FirstVote = times.arrivals[0].Sub(times.proposed)
+	}
+	if !times.halfQuorum.IsZero() {
+		timeline.HalfQuorum = times.halfQuorum.Sub(times.proposed)
+	}
+	if !times.quorum.IsZero() {
+		timeline.Quorum = times.quorum.Sub(times.proposed)
+		timeline.QuorumReached = true
+	}
+	timeline.InterArrival = interArrival(times.arrivals)
+	return timeline
+}
+
+// closeTimelineLocked fixes proposalID's timeline as CommitModel closes it
+// and counts it toward the quorum risk. Callers must hold c.mu.
+func (c *Coordinator) closeTimelineLocked(proposalID string) *protocol.VoteTimeline {
+	if c.voteTimes[proposalID] == nil {
+		return nil
+	}
+	timeline := c.timelineLocked(proposalID)
+	slow := !timeline.QuorumReached
+	if timeline.Budget > 0 && float64(timeline.Quorum) > c.quorumRisk.Fraction*float64(timeline.Budget) {
+		slow = true
+	}
+	if slow {
+		c.slowRounds++
+	} else {
+		c.slowRounds = 0
+	}
+	timeline.AtRisk = c.slowRounds >= c.quorumRisk.Rounds
+	c.closedTimelines[proposalID] = timeline
+	return &timeline
+}
+
+// reportTimeline passes a closed timeline to the observer and collector.
+// It takes c.mu, so it is deferred before the caller locks.
+func (c *Coordinator) reportTimeline(timeline *protocol.VoteTimeline) {
+	if timeline == nil {
+		return
+	}
+	c.mu.RLock()
+	observe, collector, nodeID := c.timelineObserver, c.collector, c.nodeID
+	c.mu.RUnlock()
+	if observe != nil {
+		observe(*timeline)
+	}
+	if collector == nil {
+		return
+	}
+	stages := map[string]float64{"first_vote": timeline.FirstVote.Seconds()}
+	if timeline.HalfQuorum > 0 {
+		stages["half_quorum"] = timeline.HalfQuorum.Seconds()
+	}
+	if timeline.QuorumReached {
+		stages["quorum"] = timeline.Quorum.Seconds()
+	}
+	if timeline.InterArrival.Count > 0 {
+		stages["inter_arrival_p50"] = timeline.InterArrival.P50.Seconds()
+		stages["inter_arrival_p90"] = timeline.InterArrival.P90.Seconds()
+	}
+	collector.RecordVoteTimeline(timeline.Round, stages, timeline.LateVotes, map[string]string{
+		"proposal_id": timeline.ProposalID,
+		"at_risk":     strconv.FormatBool(timeline.AtRisk),
+	}, nodeID)
+}
+
+// interArrival summarizes the gaps between consecutive arrivals.
+func interArrival(arrivals []time.Time) protocol.VoteInterArrival {
+	if len(arrivals) < 2 {
+		return protocol.VoteInterArrival{}
+	}
+	gaps := make([]float64, len(arrivals)-1)
+	total := 0.0
+	for i := 1; i < len(arrivals); i++ {
+		gaps[i-1] = float64(arrivals[i].Sub(arrivals[i-1]))
+		total += gaps[i-1]
+	}
+	sort.Float64s(gaps)
+	return protocol.VoteInterArrival{
+		Count: len(gaps),
+		Mean:  time.Duration(total / float64(len(gaps))),
+		P50:   time.Duration(monitoring.NearestRank(gaps, 50)),
+		P90:   time.Duration(monitoring.NearestRank(gaps, 90)),
+		Max:   time.Duration(gaps[len(gaps)-1]),
+	}
+}
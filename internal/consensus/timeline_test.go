@@ -0,0 +1,149 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// runSchedule proposes round and casts one approval per offset, each at
+// that offset from the proposal, then commits.
+func runSchedule(t *testing.T, c *Coordinator, now *time.Time, round int, offsets ...time.Duration) (string, error) {
+	t.Helper()
+	ctx := context.Background()
+	c.Reset()
+	start := *now
+	proposalID, err := c.ProposeModel(ctx, &ModelProposal{Round: round, Weights: []byte("weights"), ProposerID: "node-1", Timestamp: start})
+	if err != nil {
+		t.Fatalf("propose round %d: %v", round, err)
+	}
+	voters := []string{"node-1", "member-1", "member-2", "member-3"}
+	for i, offset := range offsets {
+		*now = start.Add(offset)
+		if err := c.CastVote(ctx, &Vote{NodeID: voters[i], ProposalID: proposalID, Approve: true, Timestamp: *now}); err != nil {
+			t.Fatalf("round %d vote %s: %v", round, voters[i], err)
+		}
+	}
+	*now = start.Add(time.Minute)
+	return proposalID, c.CommitModel(ctx, proposalID)
+}
+
+func TestVoteTimelineFollowsTheSchedule(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := NewCoordinator("node-1", 4, 10*time.Second)
+	c.clock = func() time.Time { return now }
+	collector := monitoring.NewCollector(100)
+	c.SetCollector(collector)
+	var observed []protocol.VoteTimeline
+	c.SetTimelineObserver(func(timeline protocol.VoteTimeline) { observed = append(observed, timeline) })
+
+	// Quorum is three of four: half of it on the second approval, all of it
+	// on the third, and the fourth arrives late.
+	proposalID, err := runSchedule(t, c, &now, 1, time.Second, 2*time.Second, 4*time.Second, 5*time.Second)
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	timeline, err := c.VoteTimeline(proposalID)
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	if timeline.Round != 1 || timeline.QuorumSize != 3 || timeline.Votes != 4 || timeline.Budget != 10*time.Second {
+		t.Fatalf("timeline = %+v", timeline)
+	}
+	if timeline.FirstVote != time.Second || timeline.HalfQuorum != 2*time.Second || timeline.Quorum != 4*time.Second || !timeline.QuorumReached {
+		t.Fatalf("stages = %s, %s, %s", timeline.FirstVote, timeline.HalfQuorum, timeline.Quorum)
+	}
+	// Gaps of 1s, 2s, and 1s.
+	want := protocol.VoteInterArrival{Count: 3, Mean: 4 * time.Second / 3, P50: time.Second, P90: 2 * time.Second, Max: 2 * time.Second}
+	if timeline.InterArrival != want {
+		t.Fatalf("inter-arrival = %+v, want %+v", timeline.InterArrival, want)
+	}
+	if timeline.LateVotes != 1 || timeline.AtRisk {
+		t.Fatalf("late %d, at risk %v", timeline.LateVotes, timeline.AtRisk)
+	}
+	if len(observed) != 1 || observed[0] != timeline {
+		t.Fatalf("observed = %+v", observed)
+	}
+
+	stages := map[string]float64{}
+	for _, metric := range collector.GetMetricsByType(monitoring.MetricVoteTimeline) {
+		if metric.Labels["round"] != "1" || metric.Labels["proposal_id"] != proposalID {
+			t.Fatalf("metric labels = %v", metric.Labels)
+		}
+		stages[metric.Labels["stage"]] = metric.Value
+	}
+	if len(stages) != 5 || stages["first_vote"] != 1 || stages["half_quorum"] != 2 || stages["quorum"] != 4 || stages["inter_arrival_p90"] != 2 {
+		t.Fatalf("stages recorded = %v", stages)
+	}
+	if late := collector.GetMetricsByType(monitoring.MetricLateVotes); len(late) != 1 || late[0].Value != 1 {
+		t.Fatalf("late votes recorded = %+v", late)
+	}
+
+	if _, err := c.VoteTimeline("missing"); !errors.Is(err, ErrProposalNotFound) {
+		t.Fatalf("unknown proposal: expected ErrProposalNotFound, got %v", err)
+	}
+}
+
+func TestSlowQuorumForConsecutiveRoundsIsAtRisk(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := NewCoordinator("node-1", 4, 10*time.Second)
+	c.clock = func() time.Time { return now }
+	if err := c.SetQuorumRisk(QuorumRisk{Fraction: 0.5, Rounds: 2}); err != nil {
+		t.Fatalf("set quorum risk: %v", err)
+	}
+	bus := events.NewBus()
+	sub := bus.Subscribe("test", 64)
+	c.SetEvents(events.Scope(bus, "traffic"))
+
+	// Quorum after 5s is within half the 10s budget; after 6s it is slow,
+	// as is a round that never reaches quorum.
+	schedules := []struct {
+		offsets   []time.Duration
+		committed bool
+		atRisk    bool
+	}{
+		{[]time.Duration{time.Second, 3 * time.Second, 5 * time.Second}, true, false},
+		{[]time.Duration{time.Second, 3 * time.Second, 6 * time.Second}, true, false},
+		{[]time.Duration{time.Second}, false, true},
+		{[]time.Duration{time.Second, 2 * time.Second, 8 * time.Second}, true, true},
+		{[]time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, true, false},
+	}
+	for i, schedule := range schedules {
+		round := i + 1
+		proposalID, err := runSchedule(t, c, &now, round, schedule.offsets...)
+		if (err == nil) != schedule.committed {
+			t.Fatalf("round %d: commit error %v", round, err)
+		}
+		timeline, err := c.VoteTimeline(proposalID)
+		if err != nil || timeline.AtRisk != schedule.atRisk || c.QuorumAtRisk() != schedule.atRisk {
+			t.Fatalf("round %d: timeline %+v (%v), want at risk %v", round, timeline, err, schedule.atRisk)
+		}
+		closed := events.KindCommitted
+		if !schedule.committed {
+			closed = events.KindAborted
+		}
+		var found bool
+		for _, event := range drain(t, sub) {
+			if event.Kind == closed {
+				found = true
+				if event.QuorumAtRisk != schedule.atRisk {
+					t.Fatalf("round %d: %s event at risk %v", round, closed, event.QuorumAtRisk)
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("round %d: no %s event", round, closed)
+		}
+	}
+
+	for _, invalid := range []QuorumRisk{{Fraction: 0, Rounds: 1}, {Fraction: 1.5, Rounds: 1}, {Fraction: 0.5}, {Budget: -time.Second, Fraction: 0.5, Rounds: 1}} {
+		if err := c.SetQuorumRisk(invalid); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("SetQuorumRisk(%+v) = %v, want ErrInvalidArgument", invalid, err)
+		}
+	}
+}
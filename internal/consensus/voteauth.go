@@ -0,0 +1,202 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// VoteAuthMode is how a shard's members authenticate votes to its
+// aggregator.
+type VoteAuthMode string
+
+const (
+	// VoteAuthSignature verifies every vote's signature.
+	VoteAuthSignature VoteAuthMode = "signature"
+	// VoteAuthMAC verifies votes from members holding a pairwise key by
+	// HMAC-SHA256 tag, and every other vote by signature. Signatures are
+	// still checked before a commit.
+	VoteAuthMAC VoteAuthMode = "mac"
+)
+
+// NegotiateVoteAuth picks a shard's mode: VoteAuthMAC when both the
+// aggregator and the shard offer it, VoteAuthSignature otherwise.
+func NegotiateVoteAuth(local, remote []VoteAuthMode) VoteAuthMode {
+	offered := func(modes []VoteAuthMode) bool {
+		for _, mode := range modes {
+			if mode == VoteAuthMAC {
+				return true
+			}
+		}
+		return false
+	}
+	if offered(local) && offered(remote) {
+		return VoteAuthMAC
+	}
+	return VoteAuthSignature
+}
+
+// voteAuthDomain separates vote signatures and MACs from every other use of
+// the same keys.
+const voteAuthDomain = "sovereign-vote/v1"
+
+// VoteSigningBytes is the canonical encoding of a vote that both its
+// signature and its MAC cover.
+func VoteSigningBytes(vote *Vote) []byte {
+	buf := make([]byte, 0, len(voteAuthDomain)+len(vote.NodeID)+len(vote.ProposalID)+25)
+	buf = append(buf, voteAuthDomain...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(vote.NodeID))) // #nosec G115 -- node IDs are far below 4 GiB
+	buf = append(buf, vote.NodeID...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(vote.ProposalID))) // #nosec G115 -- proposal IDs are far below 4 GiB
+	buf = append(buf, vote.ProposalID...)
+	if vote.Approve {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	buf = binary.BigEndian.AppendUint64(buf, uint64(vote.Timestamp.UnixNano())) // #nosec G115 -- the timestamp is encoded, not compared
+	return appendFlags(buf, vote.Flags)
+}
+
+// appendFlags appends the canonical encoding of a vote's flags to buf.
+// Flags are covered only when present, so votes without them encode the
+// same bytes as before flags existed.
+func appendFlags(buf []byte, flags map[string]reasons.Code) []byte {
+	if len(flags) == 0 {
+		return buf
+	}
+	receipts := make([]string, 0, len(flags))
+	for receipt := range flags {
+		receipts = append(receipts, receipt)
+	}
+	sort.Strings(receipts)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(receipts))) // #nosec G115 -- a manifest has far fewer than 4G entries
+	for _, receipt := range receipts {
+		for _, field := range []string{receipt, string(flags[receipt])} {
+			buf = binary.BigEndian.AppendUint32(buf, uint32(len(field))) // #nosec G115 -- receipt IDs and reason codes are short
+			buf = append(buf, field...)
+		}
+	}
+	return buf
+}
+
+// SealVoteMAC tags vote with HMAC-SHA256 under the voter's pairwise key
+// with the aggregator.
+func SealVoteMAC(vote *Vote, key []byte) {
+	vote.MAC = voteMAC(vote, key)
+}
+
+func voteMAC(vote *Vote, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(VoteSigningBytes(vote))
+	return mac.Sum(nil)
+}
+
+// ShardVoteAuth authenticates one shard's votes at its aggregator. In
+// VoteAuthMAC mode a vote carrying a MAC from a member whose pairwise key is
+// known is checked by MAC alone, so it can be counted toward quorum without
+// a signature verification; votes from unknown peers, and every vote in
+// VoteAuthSignature mode, are checked by signature.
+type ShardVoteAuth struct {
+	shardID         string
+	mode            VoteAuthMode
+	verifySignature func(vote *Vote) error
+
+	mu   sync.RWMutex
+	keys map[string][]byte
+	// source derives the keys of members not set with SetPeerKey.
+	source func(nodeID string) ([]byte, error)
+}
+
+// NewShardVoteAuth creates the authenticator for shardID in the negotiated
+// mode. verifySignature checks a vote's signature over VoteSigningBytes.
+func NewShardVoteAuth(shardID string, mode VoteAuthMode, verifySignature func(vote *Vote) error) *ShardVoteAuth {
+	return &ShardVoteAuth{
+		shardID:         shardID,
+		mode:            mode,
+		verifySignature: verifySignature,
+		keys:            make(map[string][]byte),
+	}
+}
+
+// Mode returns the shard's negotiated mode.
+func (a *ShardVoteAuth) Mode() VoteAuthMode {
+	return a.mode
+}
+
+// SetPeerKey records nodeID's pairwise MAC key, derived from its session
+// key with the aggregator.
+func (a *ShardVoteAuth) SetPeerKey(nodeID string, key []byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys[nodeID] = append([]byte(nil), key...)
+}
+
+// SetKeySource derives the pairwise key of each member not set with
+// SetPeerKey by source, typically SecureChannel.VoteMACKey, which follows
+// the member's session key as it rotates. A member source fails for is
+// checked by signature.
+func (a *ShardVoteAuth) SetKeySource(source func(nodeID string) ([]byte, error)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.source = source
+}
+
+// RemovePeerKey forgets nodeID's key; its votes fall back to signatures.
+func (a *ShardVoteAuth) RemovePeerKey(nodeID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.keys, nodeID)
+}
+
+// VerifySignature checks vote's signature. Pass it to
+// Coordinator.SetCommitVerifier so commits rest on signed approvals.
+func (a *ShardVoteAuth) VerifySignature(vote *Vote) error {
+	return a.verifySignature(vote)
+}
+
+func (a *ShardVoteAuth) peerKey(nodeID string) ([]byte, bool) {
+	if a.mode != VoteAuthMAC {
+		return nil, false
+	}
+	a.mu.RLock()
+	key, ok := a.keys[nodeID]
+	source := a.source
+	a.mu.RUnlock()
+	if ok || source == nil {
+		return key, ok
+	}
+	key, err := source(nodeID)
+	return key, err == nil
+}
+
+// VoteAuthCheck rejects votes auth cannot authenticate: by MAC when the
+// shard negotiated MACs and the voter's key is known, by signature
+// otherwise. A forged MAC is rejected, never retried as a signature.
+func VoteAuthCheck(auth *ShardVoteAuth) VoteMiddleware {
+	return func(next VoteHandler) VoteHandler {
+		return func(ctx context.Context, vote *Vote) error {
+			if key, ok := auth.peerKey(vote.NodeID); ok && vote.MAC != nil {
+				if !hmac.Equal(vote.MAC, voteMAC(vote, key)) {
+					return &VoteRejection{Middleware: MiddlewareSignature, Reason: ReasonInvalidMAC,
+						Err: fmt.Errorf("%w: node %s in shard %s", ErrInvalidVoteMAC, vote.NodeID, auth.shardID)}
+				}
+				return next(ctx, vote)
+			}
+			if err := auth.verifySignature(vote); err != nil {
+				return &VoteRejection{Middleware: MiddlewareSignature, Reason: ReasonInvalidSignature,
+					Err: fmt.Errorf("%w: node %s in shard %s: %v", ErrInvalidVoteSignature, vote.NodeID, auth.shardID, err)}
+			}
+			return next(ctx, vote)
+		}
+	}
+}
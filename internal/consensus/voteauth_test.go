@@ -0,0 +1,326 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// voteSigners holds an ECDSA key and a pairwise MAC key per member.
+type voteSigners struct {
+	keys    map[string]*ecdsa.PrivateKey
+	macKeys map[string][]byte
+}
+
+func newVoteSigners(tb testing.TB, members ...string) *voteSigners {
+	tb.Helper()
+	s := &voteSigners{keys: make(map[string]*ecdsa.PrivateKey), macKeys: make(map[string][]byte)}
+	for _, member := range members {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			tb.Fatalf("generate key: %v", err)
+		}
+		s.keys[member] = key
+		s.macKeys[member] = []byte("pairwise-key-" + member)
+	}
+	return s
+}
+
+func (s *voteSigners) sign(tb testing.TB, vote *Vote) {
+	tb.Helper()
+	digest := sha256.Sum256(VoteSigningBytes(vote))
+	signature, err := ecdsa.SignASN1(rand.Reader, s.keys[vote.NodeID], digest[:])
+	if err != nil {
+		tb.Fatalf("sign: %v", err)
+	}
+	vote.Signature = signature
+}
+
+func (s *voteSigners) verify(vote *Vote) error {
+	key, ok := s.keys[vote.NodeID]
+	if !ok {
+		return errors.New("unknown voter")
+	}
+	digest := sha256.Sum256(VoteSigningBytes(vote))
+	if !ecdsa.VerifyASN1(&key.PublicKey, digest[:], vote.Signature) {
+		return errors.New("bad signature")
+	}
+	return nil
+}
+
+// newMACShard returns a coordinator whose shard negotiated MACs, with pairwise
+// keys for every member except those in unknown.
+func newMACShard(t *testing.T, signers *voteSigners, unknown ...string) (*Coordinator, *ShardVoteAuth) {
+	t.Helper()
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	auth := NewShardVoteAuth("shard-a", VoteAuthMAC, signers.verify)
+	skip := make(map[string]bool)
+	for _, nodeID := range unknown {
+		skip[nodeID] = true
+	}
+	for nodeID, key := range signers.macKeys {
+		if !skip[nodeID] {
+			auth.SetPeerKey(nodeID, key)
+		}
+	}
+	coord.SetVoteMiddleware(append(DefaultVoteChain(coord), VoteAuthCheck(auth))...)
+	coord.SetCommitVerifier(auth.VerifySignature)
+	return coord, auth
+}
+
+func TestNegotiateVoteAuth(t *testing.T) {
+	both := []VoteAuthMode{VoteAuthSignature, VoteAuthMAC}
+	if got := NegotiateVoteAuth(both, both); got != VoteAuthMAC {
+		t.Fatalf("both offer MACs: got %s", got)
+	}
+	if got := NegotiateVoteAuth(both, []VoteAuthMode{VoteAuthSignature}); got != VoteAuthSignature {
+		t.Fatalf("shard without MACs: got %s", got)
+	}
+	if got := NegotiateVoteAuth(nil, both); got != VoteAuthSignature {
+		t.Fatalf("aggregator without MACs: got %s", got)
+	}
+}
+
+func TestForgedVoteMACNeverReachesTally(t *testing.T) {
+	ctx := context.Background()
+	signers := newVoteSigners(t, "member-1", "member-2", "member-3")
+	coord, _ := newMACShard(t, signers)
+	proposalID := proposeForVotes(t, coord)
+
+	forged := &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true, Timestamp: time.Now()}
+	SealVoteMAC(forged, []byte("guessed-key"))
+	err := coord.CastVote(ctx, forged)
+	if rejection := rejectionOf(t, err); rejection.Reason != ReasonInvalidMAC || !errors.Is(err, ErrInvalidVoteMAC) {
+		t.Fatalf("forged MAC: got %s (%v)", rejection.Reason, err)
+	}
+
+	// A genuine tag over different contents is a forgery too.
+	tampered := &Vote{NodeID: "member-2", ProposalID: proposalID, Approve: false, Timestamp: time.Now()}
+	SealVoteMAC(tampered, signers.macKeys["member-2"])
+	tampered.Approve = true
+	if err := coord.CastVote(ctx, tampered); !errors.Is(err, ErrInvalidVoteMAC) {
+		t.Fatalf("tampered vote: expected ErrInvalidVoteMAC, got %v", err)
+	}
+
+	if approvals, _, err := coord.QuorumProgress(proposalID); err != nil || approvals != 0 {
+		t.Fatalf("forged votes reached the tally: approvals=%d err=%v", approvals, err)
+	}
+	want := VoteRejectionCount{Middleware: MiddlewareSignature, Reason: ReasonInvalidMAC, Count: 2}
+	if got := coord.VoteRejections(); len(got) != 1 || got[0] != want {
+		t.Fatalf("rejections = %+v, want %+v", got, want)
+	}
+
+	// The forger's genuine vote still counts.
+	genuine := &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true, Timestamp: time.Now()}
+	SealVoteMAC(genuine, signers.macKeys["member-1"])
+	if err := coord.CastVote(ctx, genuine); err != nil {
+		t.Fatalf("genuine vote: %v", err)
+	}
+	if approvals, _, _ := coord.QuorumProgress(proposalID); approvals != 1 {
+		t.Fatalf("approvals = %d, want 1", approvals)
+	}
+}
+
+func TestUnknownPeersFallBackToSignatures(t *testing.T) {
+	ctx := context.Background()
+	signers := newVoteSigners(t, "member-1", "member-2", "member-3")
+	coord, _ := newMACShard(t, signers, "member-3")
+	proposalID := proposeForVotes(t, coord)
+
+	// member-3 has no pairwise key, so its MAC is ignored and its missing
+	// signature rejected.
+	unsigned := &Vote{NodeID: "member-3", ProposalID: proposalID, Approve: true, Timestamp: time.Now()}
+	SealVoteMAC(unsigned, signers.macKeys["member-3"])
+	if err := coord.CastVote(ctx, unsigned); !errors.Is(err, ErrInvalidVoteSignature) {
+		t.Fatalf("expected ErrInvalidVoteSignature, got %v", err)
+	}
+	signers.sign(t, unsigned)
+	if err := coord.CastVote(ctx, unsigned); err != nil {
+		t.Fatalf("signed vote: %v", err)
+	}
+
+	// A shard that negotiated signatures ignores MACs entirely.
+	auth := NewShardVoteAuth("shard-b", VoteAuthSignature, signers.verify)
+	auth.SetPeerKey("member-1", signers.macKeys["member-1"])
+	reached := 0
+	handler := VoteAuthCheck(auth)(acceptVote(&reached))
+	macOnly := &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true, Timestamp: time.Now()}
+	SealVoteMAC(macOnly, signers.macKeys["member-1"])
+	if err := handler(ctx, macOnly); !errors.Is(err, ErrInvalidVoteSignature) || reached != 0 {
+		t.Fatalf("signature shard accepted a MAC-only vote: err=%v reached=%d", err, reached)
+	}
+}
+
+func TestKeySourceDerivesMemberKeys(t *testing.T) {
+	ctx := context.Background()
+	signers := newVoteSigners(t, "member-1", "member-2")
+	auth := NewShardVoteAuth("shard-a", VoteAuthMAC, signers.verify)
+	auth.SetKeySource(func(nodeID string) ([]byte, error) {
+		if nodeID == "member-2" {
+			return nil, crypto.ErrPeerKeyUnknown
+		}
+		return signers.macKeys[nodeID], nil
+	})
+	reached := 0
+	handler := VoteAuthCheck(auth)(acceptVote(&reached))
+
+	derived := &Vote{NodeID: "member-1", ProposalID: "p", Approve: true, Timestamp: time.Now()}
+	SealVoteMAC(derived, signers.macKeys["member-1"])
+	if err := handler(ctx, derived); err != nil || reached != 1 {
+		t.Fatalf("vote under a derived key: err=%v reached=%d", err, reached)
+	}
+	// member-2's key cannot be derived, so its vote needs a signature.
+	underived := &Vote{NodeID: "member-2", ProposalID: "p", Approve: true, Timestamp: time.Now()}
+	SealVoteMAC(underived, signers.macKeys["member-2"])
+	if err := handler(ctx, underived); !errors.Is(err, ErrInvalidVoteSignature) || reached != 1 {
+		t.Fatalf("vote without a derivable key: err=%v reached=%d", err, reached)
+	}
+}
+
+func TestCommitRequiresSignedApprovals(t *testing.T) {
+	ctx := context.Background()
+	signers := newVoteSigners(t, "node-1", "member-1", "member-2", "member-3")
+	coord, _ := newMACShard(t, signers)
+	proposalID := proposeForVotes(t, coord)
+
+	// MAC-only votes reach quorum for counting...
+	var votes []*Vote
+	for _, nodeID := range []string{"member-1", "member-2", "member-3"} {
+		vote := &Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}
+		SealVoteMAC(vote, signers.macKeys[nodeID])
+		if err := coord.CastVote(ctx, vote); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+		votes = append(votes, vote)
+	}
+	if reached, err := coord.CheckConsensus(proposalID); err != nil || !reached {
+		t.Fatalf("MAC votes did not reach quorum: reached=%v err=%v", reached, err)
+	}
+
+	// ...but cannot commit without signatures.
+	if err := coord.CommitModel(ctx, proposalID); !errors.Is(err, ErrInvalidVoteSignature) {
+		t.Fatalf("expected ErrInvalidVoteSignature, got %v", err)
+	}
+
+	coord.Reset()
+	proposalID = proposeForVotes(t, coord)
+	for _, vote := range votes {
+		vote.ProposalID = proposalID
+		SealVoteMAC(vote, signers.macKeys[vote.NodeID])
+		signers.sign(t, vote)
+		if err := coord.CastVote(ctx, vote); err != nil {
+			t.Fatalf("vote %s: %v", vote.NodeID, err)
+		}
+	}
+	if err := coord.CommitModel(ctx, proposalID); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if got := coord.CertifiedApprovals(proposalID); fmt.Sprint(got) != "[member-1 member-2 member-3]" {
+		t.Fatalf("certified approvals = %v", got)
+	}
+}
+
+// BenchmarkVoteVerification compares the per-vote cost of counting a
+// round's votes by signature and by MAC.
+func BenchmarkVoteVerification(b *testing.B) {
+	const voters = 200
+	members := make([]string, voters)
+	for i := range members {
+		members[i] = fmt.Sprintf("member-%d", i)
+	}
+	signers := newVoteSigners(b, members...)
+	votes := make([]*Vote, voters)
+	for i, nodeID := range members {
+		vote := &Vote{NodeID: nodeID, ProposalID: "proposal", Approve: true, Timestamp: time.Now()}
+		signers.sign(b, vote)
+		SealVoteMAC(vote, signers.macKeys[nodeID])
+		votes[i] = vote
+	}
+
+	for _, mode := range []VoteAuthMode{VoteAuthSignature, VoteAuthMAC} {
+		b.Run(string(mode), func(b *testing.B) {
+			auth := NewShardVoteAuth("bench", mode, signers.verify)
+			for nodeID, key := range signers.macKeys {
+				auth.SetPeerKey(nodeID, key)
+			}
+			reached := 0
+			handler := VoteAuthCheck(auth)(acceptVote(&reached))
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := handler(ctx, votes[i%voters]); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "votes/s")
+		})
+	}
+}
+
+// BenchmarkVoteSignatureAlgorithms compares the per-vote cost of checking
+// a round's vote signatures through the aggregator's secure channel when
+// the shard's members hold P-256 identities, Ed25519 identities, or a mix,
+// each verified by the member's registered algorithm.
+func BenchmarkVoteSignatureAlgorithms(b *testing.B) {
+	const voters = 200
+	for _, mix := range []struct {
+		name       string
+		algorithms []protocol.AlgorithmID
+	}{
+		{string(protocol.AlgorithmECDSAP256), []protocol.AlgorithmID{protocol.AlgorithmECDSAP256}},
+		{string(protocol.AlgorithmEd25519), []protocol.AlgorithmID{protocol.AlgorithmEd25519}},
+		{"mixed", []protocol.AlgorithmID{protocol.AlgorithmECDSAP256, protocol.AlgorithmEd25519}},
+	} {
+		b.Run(mix.name, func(b *testing.B) {
+			aggregator, err := crypto.NewSecureChannel()
+			if err != nil {
+				b.Fatal(err)
+			}
+			votes := make([]*Vote, voters)
+			for i := range votes {
+				member, err := crypto.NewSecureChannelWithAlgorithm(mix.algorithms[i%len(mix.algorithms)])
+				if err != nil {
+					b.Fatal(err)
+				}
+				vote := &Vote{NodeID: fmt.Sprintf("member-%d", i), ProposalID: "proposal", Approve: true, Timestamp: time.Now()}
+				if vote.Signature, err = member.SignData(VoteSigningBytes(vote)); err != nil {
+					b.Fatal(err)
+				}
+				publicKey, _ := member.ExportPublicKey()
+				key, err := crypto.ImportPublicKey(publicKey)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := aggregator.RegisterPeer(vote.NodeID, key); err != nil {
+					b.Fatal(err)
+				}
+				votes[i] = vote
+			}
+			auth := NewShardVoteAuth("bench", VoteAuthSignature, func(vote *Vote) error {
+				return aggregator.VerifySignature(vote.NodeID, VoteSigningBytes(vote), vote.Signature)
+			})
+			reached := 0
+			handler := VoteAuthCheck(auth)(acceptVote(&reached))
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := handler(ctx, votes[i%voters]); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "votes/s")
+		})
+	}
+}
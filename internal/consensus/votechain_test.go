@@ -0,0 +1,277 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package consensus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// acceptVote is a terminal handler that counts the votes reaching it.
+func acceptVote(reached *int) VoteHandler {
+	return func(context.Context, *Vote) error {
+		*reached++
+		return nil
+	}
+}
+
+func rejectionOf(t *testing.T, err error) *VoteRejection {
+	t.Helper()
+	var rejection *VoteRejection
+	if !errors.As(err, &rejection) {
+		t.Fatalf("expected a vote rejection, got %v", err)
+	}
+	return rejection
+}
+
+func proposeForVotes(t *testing.T, coord *Coordinator) string {
+	t.Helper()
+	proposalID, err := coord.ProposeModel(context.Background(), &ModelProposal{
+		Round:      1,
+		Weights:    []byte("weights"),
+		ProposerID: "node-1",
+		Proof:      []byte("proof"),
+		Timestamp:  time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	return proposalID
+}
+
+func TestRoundWindowCheck(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	reached := 0
+	handler := RoundWindowCheck(coord)(acceptVote(&reached))
+
+	rejection := rejectionOf(t, handler(ctx, &Vote{NodeID: "member-1", ProposalID: "none"}))
+	if rejection.Reason != ReasonNotVoting || !errors.Is(rejection, ErrInvalidState) {
+		t.Fatalf("vote before a proposal: got %s (%v)", rejection.Reason, rejection)
+	}
+
+	proposalID := proposeForVotes(t, coord)
+	rejection = rejectionOf(t, handler(ctx, &Vote{NodeID: "member-1", ProposalID: "unknown"}))
+	if rejection.Reason != ReasonUnknownProposal || !errors.Is(rejection, ErrProposalNotFound) {
+		t.Fatalf("vote on an unknown proposal: got %s (%v)", rejection.Reason, rejection)
+	}
+
+	if err := handler(ctx, &Vote{NodeID: "member-1", ProposalID: proposalID}); err != nil || reached != 1 {
+		t.Fatalf("vote in window: err=%v reached=%d", err, reached)
+	}
+}
+
+func TestMembershipCheck(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	coord.SetMembershipView(NewStaticMembershipView([]string{"node-1", "member-1", "member-2"}))
+	proposalID := proposeForVotes(t, coord)
+	reached := 0
+	handler := MembershipCheck(coord)(acceptVote(&reached))
+
+	rejection := rejectionOf(t, handler(ctx, &Vote{NodeID: "late-joiner", ProposalID: proposalID}))
+	if rejection.Reason != ReasonNotRoundMember || !errors.Is(rejection, ErrNotRoundMember) {
+		t.Fatalf("non-member vote: got %s (%v)", rejection.Reason, rejection)
+	}
+	if err := handler(ctx, &Vote{NodeID: "member-2", ProposalID: proposalID}); err != nil || reached != 1 {
+		t.Fatalf("member vote: err=%v reached=%d", err, reached)
+	}
+}
+
+func TestDuplicateCheck(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	proposalID := proposeForVotes(t, coord)
+	handler := DuplicateCheck(coord)(coord.recordVote)
+
+	vote := &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true}
+	if err := handler(ctx, vote); err != nil {
+		t.Fatalf("first vote: %v", err)
+	}
+	rejection := rejectionOf(t, handler(ctx, vote))
+	if rejection.Reason != ReasonDuplicateVote || !rejection.Drop || !errors.Is(rejection, ErrDuplicateVote) {
+		t.Fatalf("repeat vote: got %+v", rejection)
+	}
+	if got := len(coord.votes[proposalID]); got != 1 {
+		t.Fatalf("recorded %d votes, want 1", got)
+	}
+}
+
+func TestSignatureCheck(t *testing.T) {
+	ctx := context.Background()
+	reached := 0
+	handler := SignatureCheck(func(vote *Vote) error {
+		if string(vote.Signature) != "signature-"+vote.NodeID {
+			return errors.New("bad signature")
+		}
+		return nil
+	})(acceptVote(&reached))
+
+	rejection := rejectionOf(t, handler(ctx, &Vote{NodeID: "member-1", Signature: []byte("signature-member-2")}))
+	if rejection.Reason != ReasonInvalidSignature || !errors.Is(rejection, ErrInvalidVoteSignature) {
+		t.Fatalf("forged vote: got %s (%v)", rejection.Reason, rejection)
+	}
+	if err := handler(ctx, &Vote{NodeID: "member-1", Signature: []byte("signature-member-1")}); err != nil || reached != 1 {
+		t.Fatalf("signed vote: err=%v reached=%d", err, reached)
+	}
+}
+
+func TestReputationFloorCheck(t *testing.T) {
+	ctx := context.Background()
+	scores := map[string]float64{"trusted": 1.2, "slashed": 0.1}
+	reached := 0
+	handler := ReputationFloorCheck(func(nodeID string) (float64, bool) {
+		score, ok := scores[nodeID]
+		return score, ok
+	}, 0.5)(acceptVote(&reached))
+
+	for _, nodeID := range []string{"slashed", "stranger"} {
+		rejection := rejectionOf(t, handler(ctx, &Vote{NodeID: nodeID}))
+		if rejection.Reason != ReasonBelowReputation || !errors.Is(rejection, ErrReputationTooLow) {
+			t.Fatalf("%s: got %s (%v)", nodeID, rejection.Reason, rejection)
+		}
+	}
+	if err := handler(ctx, &Vote{NodeID: "trusted"}); err != nil || reached != 1 {
+		t.Fatalf("trusted vote: err=%v reached=%d", err, reached)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+	reached := 0
+	handler := rateLimit(2, time.Minute, func() time.Time { return now })(acceptVote(&reached))
+
+	for i := 0; i < 2; i++ {
+		if err := handler(ctx, &Vote{NodeID: "chatty"}); err != nil {
+			t.Fatalf("vote %d within limit: %v", i, err)
+		}
+	}
+	rejection := rejectionOf(t, handler(ctx, &Vote{NodeID: "chatty"}))
+	if rejection.Reason != ReasonRateLimited || !Retryable(rejection) {
+		t.Fatalf("vote over limit: got %s, retryable=%t", rejection.Reason, Retryable(rejection))
+	}
+	if err := handler(ctx, &Vote{NodeID: "quiet"}); err != nil {
+		t.Fatalf("other node limited: %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := handler(ctx, &Vote{NodeID: "chatty"}); err != nil {
+		t.Fatalf("vote in next window: %v", err)
+	}
+	if reached != 4 {
+		t.Fatalf("reached terminal %d times, want 4", reached)
+	}
+}
+
+func TestClosedMembershipRejectsNonMemberProposers(t *testing.T) {
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	coord.SetMembershipView(NewStaticMembershipView([]string{"node-1", "member-1", "member-2"}))
+
+	_, err := coord.ProposeModel(context.Background(), &ModelProposal{
+		Round:      1,
+		Weights:    []byte("weights"),
+		ProposerID: "outsider",
+		Timestamp:  time.Now(),
+	})
+	if !errors.Is(err, ErrNotRoundMember) {
+		t.Fatalf("non-member proposal: %v", err)
+	}
+	proposalID := proposeForVotes(t, coord)
+	membership, err := coord.GetRoundMembership(proposalID)
+	if err != nil {
+		t.Fatalf("round membership: %v", err)
+	}
+	if len(membership.ActiveNodes) != 3 {
+		t.Fatalf("round admitted %v, want the view's three members", membership.ActiveNodes)
+	}
+}
+
+func TestDefaultVoteChainMatchesCastVote(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	coord.SetMembershipView(NewStaticMembershipView([]string{"node-1", "member-1", "member-2"}))
+
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: "none"}); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("vote before proposal: %v", err)
+	}
+	proposalID := proposeForVotes(t, coord)
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: "unknown"}); !errors.Is(err, ErrProposalNotFound) {
+		t.Fatalf("vote on unknown proposal: %v", err)
+	}
+	if err := coord.CastVote(ctx, &Vote{NodeID: "late-joiner", ProposalID: proposalID}); !errors.Is(err, ErrNotRoundMember) {
+		t.Fatalf("non-member vote: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := coord.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true}); err != nil {
+			t.Fatalf("vote %d: %v", i, err)
+		}
+	}
+	if approvals, _, err := coord.QuorumProgress(proposalID); err != nil || approvals != 1 {
+		t.Fatalf("approvals=%d err=%v, want the duplicate dropped", approvals, err)
+	}
+
+	want := []VoteRejectionCount{
+		{Middleware: MiddlewareDuplicate, Reason: ReasonDuplicateVote, Count: 1},
+		{Middleware: MiddlewareMembership, Reason: ReasonNotRoundMember, Count: 1},
+		{Middleware: MiddlewareRoundWindow, Reason: ReasonNotVoting, Count: 1},
+		{Middleware: MiddlewareRoundWindow, Reason: ReasonUnknownProposal, Count: 1},
+	}
+	if got := coord.VoteRejections(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rejections = %+v, want %+v", got, want)
+	}
+}
+
+func TestComposedVoteChainRejectsInOrder(t *testing.T) {
+	ctx := context.Background()
+	coord := NewCoordinator("node-1", 4, 5*time.Second)
+	proposalID := proposeForVotes(t, coord)
+	observed := make(map[string]int)
+	coord.SetVoteRejectionObserver(func(middleware, reason string) {
+		observed[middleware+"/"+reason]++
+	})
+	chain := append(DefaultVoteChain(coord),
+		SignatureCheck(func(vote *Vote) error {
+			if len(vote.Signature) == 0 {
+				return errors.New("unsigned")
+			}
+			return nil
+		}),
+		ReputationFloorCheck(func(nodeID string) (float64, bool) { return 1.0, nodeID != "member-3" }, 0.5),
+		RateLimit(1, time.Hour),
+	)
+	coord.SetVoteMiddleware(chain...)
+
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true}); !errors.Is(err, ErrInvalidVoteSignature) {
+		t.Fatalf("unsigned vote: %v", err)
+	}
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-3", ProposalID: proposalID, Approve: true, Signature: []byte("sig")}); !errors.Is(err, ErrReputationTooLow) {
+		t.Fatalf("unknown reputation: %v", err)
+	}
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true, Signature: []byte("sig")}); err != nil {
+		t.Fatalf("valid vote: %v", err)
+	}
+	// The duplicate check runs before the rate limit, so a repeat vote is
+	// dropped rather than rate limited.
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-1", ProposalID: proposalID, Approve: true, Signature: []byte("sig")}); err != nil {
+		t.Fatalf("duplicate vote: %v", err)
+	}
+
+	wantObserved := map[string]int{
+		MiddlewareSignature + "/" + string(ReasonInvalidSignature):      1,
+		MiddlewareReputationFloor + "/" + string(ReasonBelowReputation): 1,
+		MiddlewareDuplicate + "/" + string(ReasonDuplicateVote):         1,
+	}
+	if !reflect.DeepEqual(observed, wantObserved) {
+		t.Fatalf("observed %v, want %v", observed, wantObserved)
+	}
+
+	coord.SetVoteMiddleware()
+	if err := coord.CastVote(ctx, &Vote{NodeID: "member-2", ProposalID: proposalID, Approve: true}); err != nil {
+		t.Fatalf("unsigned vote after restoring defaults: %v", err)
+	}
+}
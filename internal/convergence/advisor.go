@@ -0,0 +1,152 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package convergence
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// MaxAdjustment bounds how far an advisor moves the learning rate in one
+// round: at most halved or doubled. Epochs move by at most one.
+const MaxAdjustment = 2.0
+
+// MinLearningRateScale is the smallest fraction of the static learning rate
+// an advisor recommends.
+const MinLearningRateScale = 1.0 / 64
+
+// Hyperparameters are the local training settings a TrainingTask carries.
+type Hyperparameters struct {
+	LearningRate float64 `json:"learning_rate"`
+	Epochs       int     `json:"epochs"`
+}
+
+// Observation is what an advisor sees of the round just finished.
+type Observation struct {
+	Round int `json:"round"`
+	// Heterogeneity is the shard's ζ² estimate; see
+	// Detector.GetHeterogeneityEstimate.
+	Heterogeneity float64 `json:"heterogeneity"`
+	// ConvergenceRate is the recent fractional decrease of the gradient
+	// norm; negative while training diverges. See
+	// Detector.GetConvergenceRate.
+	ConvergenceRate float64 `json:"convergence_rate"`
+}
+
+// Recommendation is the hyperparameters recommended for a round and the
+// observation they were derived from.
+type Recommendation struct {
+	Round           int             `json:"round"`
+	Hyperparameters Hyperparameters `json:"hyperparameters"`
+	Observation     Observation     `json:"observation"`
+}
+
+// AdvisorPolicy maps an observation to the hyperparameters of the next
+// round. The advisor bounds whatever it returns; see MaxAdjustment.
+type AdvisorPolicy interface {
+	Recommend(static, last Hyperparameters, obs Observation) Hyperparameters
+}
+
+// DispersionPolicy scales the static hyperparameters down as heterogeneity
+// grows past Reference: each doubling of ζ² halves the learning rate and
+// the local epochs, so highly non-IID shards take smaller, shorter steps.
+// A round whose gradient norm grew also halves the last learning rate and
+// drops an epoch.
+type DispersionPolicy struct {
+	// Reference is the heterogeneity the static hyperparameters were tuned
+	// for. Zero never scales for heterogeneity.
+	Reference float64
+}
+
+// Recommend implements AdvisorPolicy.
+func (p DispersionPolicy) Recommend(static, last Hyperparameters, obs Observation) Hyperparameters {
+	next := static
+	if p.Reference > 0 && obs.Heterogeneity > p.Reference {
+		scale := p.Reference / obs.Heterogeneity
+		next.LearningRate *= scale
+		next.Epochs = int(math.Ceil(float64(static.Epochs) * scale))
+	}
+	if obs.ConvergenceRate < 0 {
+		next.LearningRate = math.Min(next.LearningRate, last.LearningRate/2)
+		next.Epochs = min(next.Epochs, last.Epochs-1)
+	}
+	return next
+}
+
+// HyperparameterAdvisor recommends the learning rate and local epochs of
+// each round's TrainingTask for one shard or region, and records every
+// recommendation so its effect can be evaluated. Without a policy it
+// recommends the static hyperparameters every round.
+type HyperparameterAdvisor struct {
+	static Hyperparameters
+	policy AdvisorPolicy
+
+	mu      sync.Mutex
+	current Hyperparameters
+	history []Recommendation
+}
+
+// NewHyperparameterAdvisor starts an advisor at static. policy may be nil.
+func NewHyperparameterAdvisor(static Hyperparameters, policy AdvisorPolicy) (*HyperparameterAdvisor, error) {
+	if static.LearningRate <= 0 || math.IsNaN(static.LearningRate) || math.IsInf(static.LearningRate, 0) || static.Epochs < 1 {
+		return nil, fmt.Errorf("%w: learning rate %v, epochs %d", ErrInvalidHyperparameters, static.LearningRate, static.Epochs)
+	}
+	return &HyperparameterAdvisor{static: static, policy: policy, current: static}, nil
+}
+
+// Observe feeds the advisor the round just finished and returns its
+// recommendation for the next one. The learning rate moves by at most
+// MaxAdjustment and stays within [MinLearningRateScale, 1] times the
+// static rate; epochs move by at most one and stay within [1, static].
+func (a *HyperparameterAdvisor) Observe(obs Observation) Recommendation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.policy != nil {
+		a.current = a.bound(a.policy.Recommend(a.static, a.current, obs))
+	}
+	rec := Recommendation{Round: obs.Round + 1, Hyperparameters: a.current, Observation: obs}
+	a.history = append(a.history, rec)
+	return rec
+}
+
+// bound clamps a policy's recommendation; a NaN learning rate keeps the
+// current one.
+func (a *HyperparameterAdvisor) bound(next Hyperparameters) Hyperparameters {
+	last := a.current
+	lr := next.LearningRate
+	if math.IsNaN(lr) {
+		lr = last.LearningRate
+	}
+	lr = math.Min(math.Max(lr, last.LearningRate/MaxAdjustment), last.LearningRate*MaxAdjustment)
+	lr = math.Min(math.Max(lr, a.static.LearningRate*MinLearningRateScale), a.static.LearningRate)
+
+	epochs := min(max(next.Epochs, last.Epochs-1), last.Epochs+1)
+	epochs = min(max(epochs, 1), a.static.Epochs)
+	return Hyperparameters{LearningRate: lr, Epochs: epochs}
+}
+
+// Current returns the hyperparameters recommended for the next round.
+func (a *HyperparameterAdvisor) Current() Hyperparameters {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.current
+}
+
+// Stamp sets task's learning rate and epochs to the current
+// recommendation.
+func (a *HyperparameterAdvisor) Stamp(task *protocol.TrainingTask) {
+	current := a.Current()
+	task.LearningRate = current.LearningRate
+	task.Epochs = current.Epochs
+}
+
+// History returns every recommendation, oldest first.
+func (a *HyperparameterAdvisor) History() []Recommendation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Recommendation(nil), a.history...)
+}
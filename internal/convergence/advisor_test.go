@@ -0,0 +1,84 @@
+package convergence
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func TestAdvisorBoundsDispersionPolicy(t *testing.T) {
+	static := Hyperparameters{LearningRate: 0.4, Epochs: 4}
+	advisor, err := NewHyperparameterAdvisor(static, DispersionPolicy{Reference: 1})
+	if err != nil {
+		t.Fatalf("new advisor: %v", err)
+	}
+	for _, tc := range []struct {
+		obs  Observation
+		want Hyperparameters
+	}{
+		// ζ² doubles: the rate halves and half the epochs remain, one at a time.
+		{Observation{Round: 1, Heterogeneity: 2, ConvergenceRate: 0.5}, Hyperparameters{0.2, 3}},
+		{Observation{Round: 2, Heterogeneity: 2, ConvergenceRate: 0.5}, Hyperparameters{0.2, 2}},
+		// ζ² grows sixteenfold, but the rate only halves per round.
+		{Observation{Round: 3, Heterogeneity: 16, ConvergenceRate: 0.5}, Hyperparameters{0.1, 1}},
+		{Observation{Round: 4, Heterogeneity: 16, ConvergenceRate: 0.5}, Hyperparameters{0.05, 1}},
+		{Observation{Round: 5, Heterogeneity: 16, ConvergenceRate: 0.5}, Hyperparameters{0.025, 1}},
+		// The shard homogenizes: the rate doubles back toward, never past,
+		// the static one.
+		{Observation{Round: 6, Heterogeneity: 0.5, ConvergenceRate: 0.5}, Hyperparameters{0.05, 2}},
+		{Observation{Round: 7, Heterogeneity: 0.5, ConvergenceRate: 0.5}, Hyperparameters{0.1, 3}},
+		{Observation{Round: 8, Heterogeneity: 0.5, ConvergenceRate: 0.5}, Hyperparameters{0.2, 4}},
+		{Observation{Round: 9, Heterogeneity: 0.5, ConvergenceRate: 0.5}, Hyperparameters{0.4, 4}},
+		{Observation{Round: 10, Heterogeneity: 0.5, ConvergenceRate: 0.5}, Hyperparameters{0.4, 4}},
+		// A growing gradient norm backs off even at low heterogeneity.
+		{Observation{Round: 11, Heterogeneity: 0.5, ConvergenceRate: -0.1}, Hyperparameters{0.2, 3}},
+	} {
+		if got := advisor.Observe(tc.obs); got.Round != tc.obs.Round+1 || got.Hyperparameters != tc.want {
+			t.Fatalf("after round %d: %+v, want %+v for round %d", tc.obs.Round, got, tc.want, tc.obs.Round+1)
+		}
+	}
+	if history := advisor.History(); len(history) != 11 || history[0].Observation.Heterogeneity != 2 {
+		t.Fatalf("history = %+v", history)
+	}
+
+	task := protocol.TrainingTask{Round: 12}
+	advisor.Stamp(&task)
+	if task.LearningRate != 0.2 || task.Epochs != 3 {
+		t.Fatalf("stamped task = %+v", task)
+	}
+}
+
+func TestAdvisorWithoutPolicyKeepsStaticValues(t *testing.T) {
+	static := Hyperparameters{LearningRate: 0.1, Epochs: 2}
+	advisor, err := NewHyperparameterAdvisor(static, nil)
+	if err != nil {
+		t.Fatalf("new advisor: %v", err)
+	}
+	if rec := advisor.Observe(Observation{Round: 1, Heterogeneity: 100, ConvergenceRate: -1}); rec.Hyperparameters != static {
+		t.Fatalf("recommendation = %+v, want the static values", rec)
+	}
+	for _, invalid := range []Hyperparameters{{0, 1}, {-1, 1}, {0.1, 0}} {
+		if _, err := NewHyperparameterAdvisor(invalid, nil); !errors.Is(err, ErrInvalidHyperparameters) {
+			t.Fatalf("static %+v: %v, want ErrInvalidHyperparameters", invalid, err)
+		}
+	}
+}
+
+func TestDetectorMeasuresNodeGradientDispersion(t *testing.T) {
+	d := NewDetector(0.001, 0.1, 2, 2)
+	d.RecordNodeGradients([][]float64{{1, 0}, {-1, 0}, {0, 3}, {0, -3}})
+	// Mean gradient is zero; squared norms are 1, 1, 9, 9.
+	if got := d.GetHeterogeneityEstimate(); got != 5 {
+		t.Fatalf("heterogeneity = %v, want 5", got)
+	}
+	d.RecordLoss(2)
+	d.RecordLoss(3)
+	if !d.Diverging() {
+		t.Fatal("rising loss not flagged as diverging")
+	}
+	d.RecordLoss(1)
+	if d.Diverging() {
+		t.Fatal("falling loss flagged as diverging")
+	}
+}
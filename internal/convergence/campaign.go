@@ -0,0 +1,305 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package convergence
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// campaignIDPattern keeps campaign IDs usable as file names.
+var campaignIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// Campaign is one training campaign, typically one model version trained
+// from scratch.
+type Campaign struct {
+	ID           string            `json:"id"`
+	ModelVersion string            `json:"model_version,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	StartedAt    time.Time         `json:"started_at"`
+}
+
+// RoundReport is the convergence state of one round of a campaign.
+type RoundReport struct {
+	CampaignID    string    `json:"campaign_id"`
+	Round         int       `json:"round"`
+	Loss          float64   `json:"loss"`
+	GradientNorm  float64   `json:"gradient_norm"`
+	Heterogeneity float64   `json:"heterogeneity"`
+	RecordedAt    time.Time `json:"recorded_at"`
+}
+
+// campaignFile is the on-disk form of a campaign and its reports.
+type campaignFile struct {
+	Campaign Campaign      `json:"campaign"`
+	Reports  []RoundReport `json:"reports"`
+}
+
+// CampaignTracker keeps every campaign's round reports, one JSON file per
+// campaign under its directory, so history survives restarts.
+type CampaignTracker struct {
+	mu        sync.RWMutex
+	dir       string
+	campaigns map[string]*campaignFile
+}
+
+// NewCampaignTracker loads the campaigns persisted under dir, creating it
+// if needed.
+func NewCampaignTracker(dir string) (*CampaignTracker, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create campaign directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list campaigns: %w", err)
+	}
+	tracker := &CampaignTracker{dir: dir, campaigns: make(map[string]*campaignFile, len(paths))}
+	for _, path := range paths {
+		raw, err := os.ReadFile(path) // #nosec G304 -- files under the tracker's own directory
+		if err != nil {
+			return nil, fmt.Errorf("read campaign %s: %w", filepath.Base(path), err)
+		}
+		var file campaignFile
+		if err := json.Unmarshal(raw, &file); err != nil {
+			return nil, fmt.Errorf("parse campaign %s: %w", filepath.Base(path), err)
+		}
+		tracker.campaigns[file.Campaign.ID] = &file
+	}
+	return tracker, nil
+}
+
+// StartCampaign registers a new campaign. StartedAt defaults to now.
+func (t *CampaignTracker) StartCampaign(campaign Campaign) error {
+	if !campaignIDPattern.MatchString(campaign.ID) {
+		return fmt.Errorf("%w: id %q", ErrInvalidCampaign, campaign.ID)
+	}
+	if campaign.StartedAt.IsZero() {
+		campaign.StartedAt = time.Now().UTC()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, exists := t.campaigns[campaign.ID]; exists {
+		return fmt.Errorf("%w: campaign %s already exists", ErrInvalidCampaign, campaign.ID)
+	}
+	file := &campaignFile{Campaign: campaign, Reports: []RoundReport{}}
+	if err := t.persistLocked(file); err != nil {
+		return err
+	}
+	t.campaigns[campaign.ID] = file
+	return nil
+}
+
+// Record adds a round report to its campaign. Reporting a round again
+// replaces the earlier report.
+func (t *CampaignTracker) Record(report RoundReport) error {
+	if report.Round < 0 || math.IsNaN(report.Loss) || math.IsInf(report.Loss, 0) {
+		return fmt.Errorf("%w: round %d loss %v", ErrInvalidCampaign, report.Round, report.Loss)
+	}
+	if report.RecordedAt.IsZero() {
+		report.RecordedAt = time.Now().UTC()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	file, ok := t.campaigns[report.CampaignID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCampaign, report.CampaignID)
+	}
+	updated := &campaignFile{Campaign: file.Campaign, Reports: make([]RoundReport, 0, len(file.Reports)+1)}
+	for _, existing := range file.Reports {
+		if existing.Round != report.Round {
+			updated.Reports = append(updated.Reports, existing)
+		}
+	}
+	updated.Reports = append(updated.Reports, report)
+	sort.Slice(updated.Reports, func(i, j int) bool { return updated.Reports[i].Round < updated.Reports[j].Round })
+	if err := t.persistLocked(updated); err != nil {
+		return err
+	}
+	t.campaigns[report.CampaignID] = updated
+	return nil
+}
+
+// persistLocked writes file through a temporary file and a rename, so a
+// crash leaves either the old or the new campaign on disk.
+func (t *CampaignTracker) persistLocked(file *campaignFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serialize campaign %s: %w", file.Campaign.ID, err)
+	}
+	path := filepath.Join(t.dir, file.Campaign.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write campaign %s: %w", file.Campaign.ID, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write campaign %s: %w", file.Campaign.ID, err)
+	}
+	return nil
+}
+
+// CampaignSummary describes a campaign's convergence.
+type CampaignSummary struct {
+	Campaign Campaign `json:"campaign"`
+	Rounds   int      `json:"rounds"`
+	// RoundsToThreshold is the first round whose loss is at or below the
+	// comparison threshold; ThresholdReached is false if none is.
+	RoundsToThreshold  int     `json:"rounds_to_threshold,omitempty"`
+	ThresholdReached   bool    `json:"threshold_reached"`
+	InitialLoss        float64 `json:"initial_loss"`
+	FinalLoss          float64 `json:"final_loss"`
+	MeanHeterogeneity  float64 `json:"mean_heterogeneity"`
+	FinalHeterogeneity float64 `json:"final_heterogeneity"`
+}
+
+// Campaigns returns every campaign summarized without a threshold, sorted
+// by start time.
+func (t *CampaignTracker) Campaigns() []CampaignSummary {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	summaries := make([]CampaignSummary, 0, len(t.campaigns))
+	for _, file := range t.campaigns {
+		summaries = append(summaries, summarize(file, math.Inf(-1)))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if !summaries[i].Campaign.StartedAt.Equal(summaries[j].Campaign.StartedAt) {
+			return summaries[i].Campaign.StartedAt.Before(summaries[j].Campaign.StartedAt)
+		}
+		return summaries[i].Campaign.ID < summaries[j].Campaign.ID
+	})
+	return summaries
+}
+
+// Reports returns a campaign's round reports in round order.
+func (t *CampaignTracker) Reports(campaignID string) ([]RoundReport, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	file, ok := t.campaigns[campaignID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCampaign, campaignID)
+	}
+	return append([]RoundReport(nil), file.Reports...), nil
+}
+
+func summarize(file *campaignFile, threshold float64) CampaignSummary {
+	summary := CampaignSummary{Campaign: file.Campaign, Rounds: len(file.Reports)}
+	if len(file.Reports) == 0 {
+		return summary
+	}
+	summary.InitialLoss = file.Reports[0].Loss
+	last := file.Reports[len(file.Reports)-1]
+	summary.FinalLoss = last.Loss
+	summary.FinalHeterogeneity = last.Heterogeneity
+	for _, report := range file.Reports {
+		summary.MeanHeterogeneity += report.Heterogeneity
+		if !summary.ThresholdReached && report.Loss <= threshold {
+			summary.ThresholdReached = true
+			summary.RoundsToThreshold = report.Round
+		}
+	}
+	summary.MeanHeterogeneity /= float64(len(file.Reports))
+	return summary
+}
+
+// AlignedRound is one round of a comparison. A value is nil when that
+// campaign has no report for the round.
+type AlignedRound struct {
+	Round          int      `json:"round"`
+	LossA          *float64 `json:"loss_a,omitempty"`
+	LossB          *float64 `json:"loss_b,omitempty"`
+	HeterogeneityA *float64 `json:"heterogeneity_a,omitempty"`
+	HeterogeneityB *float64 `json:"heterogeneity_b,omitempty"`
+}
+
+// Comparison aligns two campaigns round by round.
+type Comparison struct {
+	Threshold float64         `json:"threshold"`
+	A         CampaignSummary `json:"a"`
+	B         CampaignSummary `json:"b"`
+	Rounds    []AlignedRound  `json:"rounds"`
+}
+
+// CompareCampaigns aligns campaigns a and b on round number over the union
+// of their rounds and summarizes each against the loss threshold.
+func (t *CampaignTracker) CompareCampaigns(a, b string, threshold float64) (*Comparison, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	fileA, ok := t.campaigns[a]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCampaign, a)
+	}
+	fileB, ok := t.campaigns[b]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCampaign, b)
+	}
+
+	byRound := make(map[int]*AlignedRound)
+	aligned := func(round int) *AlignedRound {
+		if byRound[round] == nil {
+			byRound[round] = &AlignedRound{Round: round}
+		}
+		return byRound[round]
+	}
+	for _, report := range fileA.Reports {
+		row := aligned(report.Round)
+		row.LossA, row.HeterogeneityA = floatPtr(report.Loss), floatPtr(report.Heterogeneity)
+	}
+	for _, report := range fileB.Reports {
+		row := aligned(report.Round)
+		row.LossB, row.HeterogeneityB = floatPtr(report.Loss), floatPtr(report.Heterogeneity)
+	}
+
+	comparison := &Comparison{
+		Threshold: threshold,
+		A:         summarize(fileA, threshold),
+		B:         summarize(fileB, threshold),
+		Rounds:    make([]AlignedRound, 0, len(byRound)),
+	}
+	for _, row := range byRound {
+		comparison.Rounds = append(comparison.Rounds, *row)
+	}
+	sort.Slice(comparison.Rounds, func(i, j int) bool { return comparison.Rounds[i].Round < comparison.Rounds[j].Round })
+	return comparison, nil
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// WriteCSV writes the aligned series with a header naming both campaigns.
+// Rounds a campaign did not report are left empty.
+func (c *Comparison) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	a, b := c.A.Campaign.ID, c.B.Campaign.ID
+	if err := writer.Write([]string{"round", "loss_" + a, "loss_" + b, "heterogeneity_" + a, "heterogeneity_" + b}); err != nil {
+		return err
+	}
+	for _, row := range c.Rounds {
+		record := []string{strconv.Itoa(row.Round), csvFloat(row.LossA), csvFloat(row.LossB), csvFloat(row.HeterogeneityA), csvFloat(row.HeterogeneityB)}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strings.TrimSpace(strconv.FormatFloat(*v, 'g', -1, 64))
+}
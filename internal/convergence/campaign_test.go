@@ -0,0 +1,161 @@
+package convergence
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordCampaign starts a campaign whose loss decays by rate each round,
+// feeding each round through a detector as the runtime does.
+func recordCampaign(t *testing.T, tracker *CampaignTracker, id string, rounds int, rate float64, started time.Time) {
+	t.Helper()
+	if err := tracker.StartCampaign(Campaign{ID: id, ModelVersion: "v1", Metadata: map[string]string{"rate": id}, StartedAt: started}); err != nil {
+		t.Fatalf("start %s: %v", id, err)
+	}
+	detector := NewDetector(0.01, 0.001, 5, 3)
+	for round := 1; round <= rounds; round++ {
+		loss := math.Pow(rate, float64(round))
+		detector.RecordLoss(loss)
+		detector.RecordGradient(loss / 2)
+		if err := tracker.Record(detector.RoundReport(id, round)); err != nil {
+			t.Fatalf("record %s round %d: %v", id, round, err)
+		}
+	}
+}
+
+func TestCompareCampaigns(t *testing.T) {
+	tracker, err := NewCampaignTracker(t.TempDir())
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recordCampaign(t, tracker, "fast", 6, 0.5, start)
+	recordCampaign(t, tracker, "slow", 8, 0.8, start.Add(time.Hour))
+
+	comparison, err := tracker.CompareCampaigns("fast", "slow", 0.2)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	// 0.5^3 = 0.125 is the first fast loss under 0.2; the slow campaign
+	// needs 0.8^8 ≈ 0.168.
+	if !comparison.A.ThresholdReached || comparison.A.RoundsToThreshold != 3 {
+		t.Fatalf("fast summary %+v", comparison.A)
+	}
+	if !comparison.B.ThresholdReached || comparison.B.RoundsToThreshold != 8 {
+		t.Fatalf("slow summary %+v", comparison.B)
+	}
+	if comparison.A.FinalLoss != math.Pow(0.5, 6) || comparison.B.FinalLoss != math.Pow(0.8, 8) {
+		t.Fatalf("final losses %v / %v", comparison.A.FinalLoss, comparison.B.FinalLoss)
+	}
+	if comparison.A.InitialLoss != 0.5 || comparison.B.Rounds != 8 {
+		t.Fatalf("summaries %+v / %+v", comparison.A, comparison.B)
+	}
+
+	if len(comparison.Rounds) != 8 {
+		t.Fatalf("aligned %d rounds, want 8", len(comparison.Rounds))
+	}
+	for i, row := range comparison.Rounds {
+		if row.Round != i+1 || row.LossB == nil || row.HeterogeneityB == nil {
+			t.Fatalf("row %d: %+v", i, row)
+		}
+		if (row.LossA == nil) != (row.Round > 6) {
+			t.Fatalf("row %d: fast loss present=%t", row.Round, row.LossA != nil)
+		}
+	}
+
+	unreached, err := tracker.CompareCampaigns("fast", "slow", 0.001)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if unreached.A.ThresholdReached || unreached.B.ThresholdReached {
+		t.Fatalf("threshold 0.001 reached: %+v / %+v", unreached.A, unreached.B)
+	}
+
+	var csv bytes.Buffer
+	if err := comparison.WriteCSV(&csv); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != 9 || lines[0] != "round,loss_fast,loss_slow,heterogeneity_fast,heterogeneity_slow" {
+		t.Fatalf("csv header/rows: %q", lines)
+	}
+	if !strings.HasPrefix(lines[8], "8,,") {
+		t.Fatalf("round 8 should have no fast loss: %q", lines[8])
+	}
+
+	if _, err := tracker.CompareCampaigns("fast", "missing", 0.2); !errors.Is(err, ErrUnknownCampaign) {
+		t.Fatalf("unknown campaign: %v", err)
+	}
+}
+
+func TestCampaignTrackerPersistsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	tracker, err := NewCampaignTracker(dir)
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recordCampaign(t, tracker, "fast", 4, 0.5, start)
+	recordCampaign(t, tracker, "slow", 4, 0.8, start.Add(time.Hour))
+	before, err := tracker.CompareCampaigns("fast", "slow", 0.3)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+
+	reopened, err := NewCampaignTracker(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	campaigns := reopened.Campaigns()
+	if len(campaigns) != 2 || campaigns[0].Campaign.ID != "fast" || campaigns[1].Rounds != 4 {
+		t.Fatalf("reloaded campaigns %+v", campaigns)
+	}
+	if campaigns[0].Campaign.Metadata["rate"] != "fast" {
+		t.Fatalf("metadata lost: %+v", campaigns[0].Campaign)
+	}
+	after, err := reopened.CompareCampaigns("fast", "slow", 0.3)
+	if err != nil {
+		t.Fatalf("compare after restart: %v", err)
+	}
+	if !reflect.DeepEqual(after, before) {
+		t.Fatalf("comparison changed across restart:\n%+v\n%+v", before, after)
+	}
+
+	// Re-reporting a round replaces it rather than appending.
+	if err := reopened.Record(RoundReport{CampaignID: "fast", Round: 4, Loss: 0.01}); err != nil {
+		t.Fatalf("re-record: %v", err)
+	}
+	reports, err := reopened.Reports("fast")
+	if err != nil || len(reports) != 4 || reports[3].Loss != 0.01 {
+		t.Fatalf("reports %+v err=%v", reports, err)
+	}
+}
+
+func TestCampaignTrackerRejectsInvalidInput(t *testing.T) {
+	tracker, err := NewCampaignTracker(t.TempDir())
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	for _, id := range []string{"", "../escape", "a/b"} {
+		if err := tracker.StartCampaign(Campaign{ID: id}); !errors.Is(err, ErrInvalidCampaign) {
+			t.Fatalf("id %q: %v", id, err)
+		}
+	}
+	if err := tracker.StartCampaign(Campaign{ID: "c1"}); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := tracker.StartCampaign(Campaign{ID: "c1"}); !errors.Is(err, ErrInvalidCampaign) {
+		t.Fatalf("restart existing campaign: %v", err)
+	}
+	if err := tracker.Record(RoundReport{CampaignID: "c1", Round: 1, Loss: math.NaN()}); !errors.Is(err, ErrInvalidCampaign) {
+		t.Fatalf("NaN loss: %v", err)
+	}
+	if err := tracker.Record(RoundReport{CampaignID: "c2", Round: 1, Loss: 1}); !errors.Is(err, ErrUnknownCampaign) {
+		t.Fatalf("unknown campaign: %v", err)
+	}
+}
@@ -19,6 +19,8 @@ type Detector struct {
 	windowSize      int       // Moving window for convergence detection
 	minIterations   int       // Minimum iterations before declaring convergence
 	lastCheckTime   time.Time
+	dispersion      float64 // ζ² measured from node gradients, if any
+	measured        bool
 }
 
 // NewDetector initializes convergence detector with proof-backed bounds
@@ -117,7 +119,16 @@ func (d *Detector) GetConvergenceRate() float64 {
 func (d *Detector) GetHeterogeneityEstimate() float64 {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
+	return d.heterogeneityLocked()
+}
 
+// heterogeneityLocked is the latest measured dispersion when node gradients
+// were recorded, otherwise the variance of the gradient norm history, never
+// below the configured ζ² bound.
+func (d *Detector) heterogeneityLocked() float64 {
+	if d.measured {
+		return math.Max(d.dispersion, d.heterogeneity)
+	}
 	if len(d.gradientHistory) < 2 {
 		return d.heterogeneity
 	}
@@ -127,6 +138,78 @@ func (d *Detector) GetHeterogeneityEstimate() float64 {
 	return math.Max(variance, d.heterogeneity)
 }
 
+// RecordNodeGradients measures ζ² for a round from the gradients of a
+// shard's nodes at the same global model: their mean squared distance from
+// the mean gradient. Later heterogeneity estimates report the latest
+// measurement. Gradients of a length other than the first are ignored.
+func (d *Detector) RecordNodeGradients(grads [][]float64) {
+	if len(grads) == 0 {
+		return
+	}
+	dim := len(grads[0])
+	mean := make([]float64, dim)
+	count := 0
+	for _, g := range grads {
+		if len(g) != dim {
+			continue
+		}
+		for j, v := range g {
+			mean[j] += v
+		}
+		count++
+	}
+	for j := range mean {
+		mean[j] /= float64(count)
+	}
+	dispersion := 0.0
+	for _, g := range grads {
+		if len(g) != dim {
+			continue
+		}
+		for j, v := range g {
+			diff := v - mean[j]
+			dispersion += diff * diff
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dispersion = dispersion / float64(count)
+	d.measured = true
+}
+
+// Diverging reports whether the latest recorded loss exceeds the one
+// before it.
+func (d *Detector) Diverging() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	n := len(d.lossHistory)
+	return n >= 2 && d.lossHistory[n-1] > d.lossHistory[n-2]
+}
+
+// Observation is the detector's state as a hyperparameter advisor sees it
+// after round.
+func (d *Detector) Observation(round int) Observation {
+	return Observation{Round: round, Heterogeneity: d.GetHeterogeneityEstimate(), ConvergenceRate: d.GetConvergenceRate()}
+}
+
+// RoundReport captures the latest loss, gradient norm, and heterogeneity
+// estimate as the report of round in campaignID.
+func (d *Detector) RoundReport(campaignID string, round int) RoundReport {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	report := RoundReport{CampaignID: campaignID, Round: round, Heterogeneity: d.heterogeneity, RecordedAt: time.Now().UTC()}
+	if len(d.lossHistory) > 0 {
+		report.Loss = d.lossHistory[len(d.lossHistory)-1]
+	}
+	if n := len(d.gradientHistory); n > 0 {
+		report.GradientNorm = d.gradientHistory[n-1]
+	}
+	report.Heterogeneity = d.heterogeneityLocked()
+	return report
+}
+
 // calculateVariance computes variance of a sample
 func (d *Detector) calculateVariance(data []float64) float64 {
 	if len(data) == 0 {
@@ -156,6 +239,8 @@ func (d *Detector) Reset() {
 	d.gradientHistory = make([]float64, 0)
 	d.lossHistory = make([]float64, 0)
 	d.lastCheckTime = time.Now()
+	d.dispersion = 0
+	d.measured = false
 }
 
 // GetMetrics returns current convergence metrics
@@ -0,0 +1,21 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package convergence
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the campaign tracker and the
+// hyperparameter advisor. Match them with errors.Is; never compare error
+// strings.
+var (
+	// ErrUnknownCampaign means no campaign has the ID. Not retryable until
+	// the campaign is started.
+	ErrUnknownCampaign = errors.New("unknown campaign")
+	// ErrInvalidCampaign means a campaign ID or round report is malformed,
+	// or the campaign already exists. Not retryable.
+	ErrInvalidCampaign = errors.New("invalid campaign")
+	// ErrInvalidHyperparameters means an advisor's static learning rate is
+	// not positive and finite, or its epochs are below one. Not retryable.
+	ErrInvalidHyperparameters = errors.New("invalid hyperparameters")
+)
@@ -0,0 +1,229 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package crash
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+)
+
+// sourceTimeout bounds each source while a snapshot is taken. A goroutine
+// that panicked while holding a lock would otherwise hang the snapshot.
+const sourceTimeout = time.Second
+
+// maxGoroutineDump caps the goroutine dump.
+const maxGoroutineDump = 8 << 20
+
+// Sources reads the state a snapshot records. Unset sources are skipped.
+type Sources struct {
+	Consensus    func() []consensus.ProposalState
+	Island       func() IslandState
+	PendingBatch func() int
+	// RoundBudget returns the budget of the round in progress, or nil.
+	RoundBudget func() *scheduler.RoundBudget
+}
+
+// Handler writes a ShutdownSnapshot when the node goes down and then exits.
+type Handler struct {
+	dir     string
+	nodeID  string
+	logs    *LogRing
+	sources Sources
+
+	mu    sync.Mutex
+	exit  func(code int)
+	drain func()
+}
+
+// NewHandler writes snapshots for nodeID under dir. logs may be nil.
+func NewHandler(dir, nodeID string, logs *LogRing, sources Sources) *Handler {
+	return &Handler{dir: dir, nodeID: nodeID, logs: logs, sources: sources, exit: os.Exit}
+}
+
+// SetDrain runs drain after the snapshot a signal triggers and before the
+// process exits, so background work can stop in order. drain must return
+// in bounded time.
+func (h *Handler) SetDrain(drain func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drain = drain
+}
+
+// Go runs fn in a goroutine that writes a snapshot if fn panics.
+func (h *Handler) Go(name string, fn func()) {
+	go func() {
+		defer h.Recover(name)
+		fn()
+	}()
+}
+
+// Recover must be deferred directly at the top of a goroutine. On a panic
+// it writes a snapshot naming the goroutine and exits with status 2, as an
+// unrecovered panic would.
+func (h *Handler) Recover(name string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	h.shutdown(ReasonPanic, fmt.Sprint(r), name, 2)
+}
+
+// Fatal writes a snapshot for err and exits with status 1. Use it in place
+// of log.Fatal.
+func (h *Handler) Fatal(err error) {
+	h.shutdown(ReasonFatal, err.Error(), "", 1)
+}
+
+// WatchSignals writes a snapshot, drains, and exits with status 1 when one
+// of signals arrives. The returned function stops watching.
+func (h *Handler) WatchSignals(signals ...os.Signal) (stop func()) {
+	received := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(received, signals...)
+	go func() {
+		select {
+		case sig := <-received:
+			h.shutdown(ReasonSignal, sig.String(), "", 1)
+		case <-done:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(done)
+		})
+	}
+}
+
+// shutdown writes one snapshot, even if several goroutines fail at once,
+// and exits.
+func (h *Handler) shutdown(reason, detail, goroutine string, code int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, err := h.writeLocked(reason, detail, goroutine); err != nil {
+		log.Printf("shutdown snapshot failed: %v", err)
+	}
+	if reason == ReasonSignal && h.drain != nil {
+		h.drain()
+	}
+	h.exit(code)
+}
+
+// Snapshot captures and writes a snapshot without exiting.
+func (h *Handler) Snapshot(reason, detail, goroutine string) (*ShutdownSnapshot, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.writeLocked(reason, detail, goroutine)
+}
+
+func (h *Handler) writeLocked(reason, detail, goroutine string) (*ShutdownSnapshot, error) {
+	snapshot := h.Capture(reason, detail, goroutine)
+	path, err := writeSnapshot(h.dir, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("wrote shutdown snapshot %s to %s", snapshot.ID, path)
+	return snapshot, nil
+}
+
+// Capture collects a snapshot without writing it.
+func (h *Handler) Capture(reason, detail, goroutine string) *ShutdownSnapshot {
+	snapshot := &ShutdownSnapshot{
+		NodeID:     h.nodeID,
+		Reason:     reason,
+		Detail:     detail,
+		Goroutine:  goroutine,
+		Timestamp:  time.Now().UTC(),
+		Goroutines: goroutineDump(),
+	}
+	if h.logs != nil {
+		snapshot.Logs = h.logs.Lines()
+	}
+	if h.sources.Consensus != nil {
+		if value, ok := collect(snapshot, "consensus", func() interface{} { return h.sources.Consensus() }); ok {
+			snapshot.Consensus = value.([]consensus.ProposalState)
+		}
+	}
+	if h.sources.Island != nil {
+		if value, ok := collect(snapshot, "island", func() interface{} { return h.sources.Island() }); ok {
+			state := value.(IslandState)
+			snapshot.Island = &state
+		}
+	}
+	if h.sources.PendingBatch != nil {
+		if value, ok := collect(snapshot, "pending_batch", func() interface{} { return h.sources.PendingBatch() }); ok {
+			snapshot.PendingBatch = value.(int)
+		}
+	}
+	if h.sources.RoundBudget != nil {
+		if value, ok := collect(snapshot, "round_budget", func() interface{} { return h.sources.RoundBudget() }); ok {
+			if budget := value.(*scheduler.RoundBudget); budget != nil {
+				snapshot.RoundBudget = &BudgetUsage{
+					Round:     budget.Round(),
+					Deadline:  budget.Deadline(),
+					Remaining: budget.Remaining(),
+					Phases:    budget.Reports(),
+				}
+				if tier, ok := budget.Tier(); ok {
+					snapshot.RoundBudget.Tier = &tier
+				}
+			}
+		}
+	}
+	return snapshot
+}
+
+// collect runs read with a timeout. A panic or overrun is recorded in the
+// snapshot's SourceErrors instead of failing the snapshot.
+func collect(snapshot *ShutdownSnapshot, source string, read func() interface{}) (interface{}, bool) {
+	type result struct {
+		value   interface{}
+		failure string
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{failure: fmt.Sprintf("panic: %v", r)}
+			}
+		}()
+		done <- result{value: read()}
+	}()
+
+	var failure string
+	select {
+	case res := <-done:
+		if res.failure == "" {
+			return res.value, true
+		}
+		failure = res.failure
+	case <-time.After(sourceTimeout):
+		failure = fmt.Sprintf("no answer within %s", sourceTimeout)
+	}
+	if snapshot.SourceErrors == nil {
+		snapshot.SourceErrors = make(map[string]string)
+	}
+	snapshot.SourceErrors[source] = failure
+	return nil, false
+}
+
+func goroutineDump() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxGoroutineDump {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
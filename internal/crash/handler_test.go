@@ -0,0 +1,170 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package crash
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+)
+
+func newTestHandler(t *testing.T, dir string, sources Sources, logs *LogRing) (*Handler, chan int) {
+	t.Helper()
+	exited := make(chan int, 1)
+	h := NewHandler(dir, "node-1", logs, sources)
+	h.exit = func(code int) { exited <- code }
+	return h, exited
+}
+
+func TestPanicInConsensusGoroutineWritesSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	coord := consensus.NewCoordinator("node-1", 4, 5*time.Second)
+	proposalID, err := coord.ProposeModel(context.Background(), &consensus.ModelProposal{
+		Round:      7,
+		Weights:    []byte("weights"),
+		ProposerID: "node-1",
+		Proof:      []byte("proof"),
+		Timestamp:  time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	if err := coord.CastVote(context.Background(), &consensus.Vote{NodeID: "node-2", ProposalID: proposalID, Approve: true}); err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+	budget, err := scheduler.NewRoundBudget(context.Background(), 7, time.Minute, scheduler.DefaultBudgetSplit())
+	if err != nil {
+		t.Fatalf("round budget: %v", err)
+	}
+	defer budget.Close()
+	_, verified := budget.DeadlineFor(scheduler.PhaseVerification)
+	verified()
+
+	logs := NewLogRing(3)
+	logger := log.New(logs, "", 0)
+	for _, line := range []string{"round 5 committed", "round 6 committed", "round 7 proposed", "collecting votes"} {
+		logger.Print(line)
+	}
+	h, exited := newTestHandler(t, dir, Sources{
+		Consensus:    coord.ProposalStates,
+		Island:       func() IslandState { return IslandState{Mode: "island", CachedUpdates: 4} },
+		PendingBatch: func() int { return 12 },
+		RoundBudget:  func() *scheduler.RoundBudget { return budget },
+	}, logs)
+
+	h.Go("consensus-commit", func() {
+		panic("synthetic commit failure")
+	})
+	select {
+	case code := <-exited:
+		if code != 2 {
+			t.Fatalf("exit code %d, want 2", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not exit after the panic")
+	}
+
+	snapshot, err := LatestUnreported(dir)
+	if err != nil || snapshot == nil {
+		t.Fatalf("snapshot %v err=%v", snapshot, err)
+	}
+	if snapshot.ID != "node-1-000001" || snapshot.Sequence != 1 || snapshot.Reason != ReasonPanic ||
+		snapshot.Detail != "synthetic commit failure" || snapshot.Goroutine != "consensus-commit" {
+		t.Fatalf("snapshot header %+v", snapshot)
+	}
+	if len(snapshot.Consensus) != 1 {
+		t.Fatalf("consensus state %+v", snapshot.Consensus)
+	}
+	if state := snapshot.Consensus[0]; state.ProposalID != proposalID || state.Round != 7 || state.Votes != 1 || state.Approvals != 1 {
+		t.Fatalf("proposal state %+v", state)
+	}
+	if snapshot.Island == nil || *snapshot.Island != (IslandState{Mode: "island", CachedUpdates: 4}) || snapshot.PendingBatch != 12 {
+		t.Fatalf("island %+v pending %d", snapshot.Island, snapshot.PendingBatch)
+	}
+	usage := snapshot.RoundBudget
+	if usage == nil || usage.Round != 7 || usage.Remaining <= 0 || len(usage.Phases) != 1 || usage.Phases[0].Phase != scheduler.PhaseVerification {
+		t.Fatalf("round budget %+v", usage)
+	}
+	if strings.Join(snapshot.Logs, "|") != "round 6 committed|round 7 proposed|collecting votes" {
+		t.Fatalf("logs %q", snapshot.Logs)
+	}
+	if !strings.Contains(snapshot.Goroutines, "goroutine ") {
+		t.Fatalf("goroutine dump missing: %q", snapshot.Goroutines)
+	}
+	if len(snapshot.SourceErrors) != 0 {
+		t.Fatalf("source errors %v", snapshot.SourceErrors)
+	}
+}
+
+func TestSnapshotSequenceIsMonotonic(t *testing.T) {
+	dir := t.TempDir()
+	h, exited := newTestHandler(t, dir, Sources{
+		Consensus: func() []consensus.ProposalState { panic("coordinator corrupted") },
+	}, nil)
+
+	h.Fatal(errors.New("listener closed"))
+	<-exited
+	first, err := LatestUnreported(dir)
+	if err != nil || first == nil || first.Sequence != 1 || first.Reason != ReasonFatal {
+		t.Fatalf("first snapshot %+v err=%v", first, err)
+	}
+	if !strings.Contains(first.SourceErrors["consensus"], "coordinator corrupted") {
+		t.Fatalf("source errors %v", first.SourceErrors)
+	}
+	if err := MarkReported(dir, first); err != nil {
+		t.Fatalf("mark reported: %v", err)
+	}
+	if again, err := LatestUnreported(dir); err != nil || again != nil {
+		t.Fatalf("reported snapshot returned again: %+v err=%v", again, err)
+	}
+
+	h.Fatal(errors.New("listener closed"))
+	<-exited
+	second, err := LatestUnreported(dir)
+	if err != nil || second == nil || second.Sequence != 2 || second.ID != "node-1-000002" {
+		t.Fatalf("second snapshot %+v err=%v", second, err)
+	}
+}
+
+func TestSignalDrainsBeforeExit(t *testing.T) {
+	dir := t.TempDir()
+	h, exited := newTestHandler(t, dir, Sources{}, nil)
+	drained := make(chan struct{})
+	h.SetDrain(func() { close(drained) })
+	stop := h.WatchSignals(syscall.SIGUSR1)
+	defer stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("signal self: %v", err)
+	}
+	select {
+	case code := <-exited:
+		select {
+		case <-drained:
+		default:
+			t.Fatal("exited without draining")
+		}
+		if code != 1 {
+			t.Fatalf("exit code %d, want 1", code)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not exit after the signal")
+	}
+	if snapshot, err := LatestUnreported(dir); err != nil || snapshot == nil || snapshot.Reason != ReasonSignal {
+		t.Fatalf("snapshot %+v err=%v", snapshot, err)
+	}
+
+	// Fatal errors exit without draining.
+	h.SetDrain(func() { t.Fatal("drained on a fatal error") })
+	h.Fatal(errors.New("listener closed"))
+	<-exited
+}
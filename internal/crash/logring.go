@@ -0,0 +1,54 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package crash
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultLogLines is how many recent log lines a snapshot keeps.
+const DefaultLogLines = 200
+
+// LogRing keeps the most recent log lines in memory. Install it with
+// log.SetOutput(io.MultiWriter(os.Stderr, ring)).
+type LogRing struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+// NewLogRing keeps the last size lines. A non-positive size uses
+// DefaultLogLines.
+func NewLogRing(size int) *LogRing {
+	if size <= 0 {
+		size = DefaultLogLines
+	}
+	return &LogRing{lines: make([]string, size)}
+}
+
+// Write records each line of p. The log package writes one entry per call.
+func (r *LogRing) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		r.lines[r.next] = line
+		r.next = (r.next + 1) % len(r.lines)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+	return len(p), nil
+}
+
+// Lines returns the retained lines, oldest first.
+func (r *LogRing) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+	return append(append([]string(nil), r.lines[r.next:]...), r.lines[:r.next]...)
+}
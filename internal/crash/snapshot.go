@@ -0,0 +1,182 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package crash records what a node was doing when it went down. A Handler
+// writes a ShutdownSnapshot on a panic in a supervised goroutine, on a
+// fatal error, or on a termination signal; the next start finds it with
+// LatestUnreported.
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+)
+
+// Snapshot reasons.
+const (
+	ReasonPanic  = "panic"
+	ReasonFatal  = "fatal"
+	ReasonSignal = "signal"
+)
+
+// snapshotPattern matches snapshot files; reported ones carry a .reported
+// infix so their sequence numbers are never reused.
+var snapshotPattern = regexp.MustCompile(`^shutdown-(\d+)(\.reported)?\.json$`)
+
+// IslandState is the node's Island Mode state.
+type IslandState struct {
+	Mode          string `json:"mode"`
+	CachedUpdates int    `json:"cached_updates"`
+}
+
+// BudgetUsage is how the round in progress had used its time budget.
+type BudgetUsage struct {
+	Round     int                     `json:"round"`
+	Deadline  time.Time               `json:"deadline"`
+	Remaining time.Duration           `json:"remaining"`
+	Phases    []scheduler.PhaseReport `json:"phases,omitempty"`
+	// Tier is the derived budget a tiered round was started with.
+	Tier *scheduler.TierBudget `json:"tier,omitempty"`
+}
+
+// ShutdownSnapshot is the node's state at the moment it went down.
+type ShutdownSnapshot struct {
+	ID       string `json:"id"`
+	Sequence uint64 `json:"sequence"`
+	NodeID   string `json:"node_id"`
+	Reason   string `json:"reason"`
+	// Detail is the panic value, the fatal error, or the signal.
+	Detail string `json:"detail"`
+	// Goroutine names the supervised goroutine that panicked.
+	Goroutine    string                    `json:"goroutine,omitempty"`
+	Timestamp    time.Time                 `json:"timestamp"`
+	Consensus    []consensus.ProposalState `json:"consensus,omitempty"`
+	Island       *IslandState              `json:"island,omitempty"`
+	PendingBatch int                       `json:"pending_batch"`
+	RoundBudget  *BudgetUsage              `json:"round_budget,omitempty"`
+	Logs         []string                  `json:"logs,omitempty"`
+	Goroutines   string                    `json:"goroutines"`
+	// SourceErrors records sources that panicked or did not answer in
+	// time, keyed by source.
+	SourceErrors map[string]string `json:"source_errors,omitempty"`
+}
+
+// Summary is a one-line description for logs.
+func (s *ShutdownSnapshot) Summary() string {
+	summary := fmt.Sprintf("%s at %s (%s: %s)", s.ID, s.Timestamp.Format(time.RFC3339), s.Reason, s.Detail)
+	if s.Goroutine != "" {
+		summary += " in " + s.Goroutine
+	}
+	summary += fmt.Sprintf(": %d proposals, pending batch %d", len(s.Consensus), s.PendingBatch)
+	if s.Island != nil {
+		summary += fmt.Sprintf(", island %s with %d cached", s.Island.Mode, s.Island.CachedUpdates)
+	}
+	return summary
+}
+
+func snapshotPath(dir string, sequence uint64, reported bool) string {
+	name := fmt.Sprintf("shutdown-%06d.json", sequence)
+	if reported {
+		name = fmt.Sprintf("shutdown-%06d.reported.json", sequence)
+	}
+	return filepath.Join(dir, name)
+}
+
+// snapshotFile is a snapshot on disk.
+type snapshotFile struct {
+	sequence uint64
+	reported bool
+}
+
+// listSnapshots returns the snapshots in dir, lowest sequence first.
+func listSnapshots(dir string) ([]snapshotFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list shutdown snapshots: %w", err)
+	}
+	var files []snapshotFile
+	for _, entry := range entries {
+		match := snapshotPattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		sequence, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		files = append(files, snapshotFile{sequence: sequence, reported: match[2] != ""})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].sequence < files[j].sequence })
+	return files, nil
+}
+
+// writeSnapshot assigns the next sequence number in dir and writes s.
+func writeSnapshot(dir string, s *ShutdownSnapshot) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create snapshot directory: %w", err)
+	}
+	files, err := listSnapshots(dir)
+	if err != nil {
+		return "", err
+	}
+	s.Sequence = 1
+	if len(files) > 0 {
+		s.Sequence = files[len(files)-1].sequence + 1
+	}
+	s.ID = fmt.Sprintf("%s-%06d", s.NodeID, s.Sequence)
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("serialize shutdown snapshot: %w", err)
+	}
+	path := snapshotPath(dir, s.Sequence, false)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("write shutdown snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// LatestUnreported returns the newest snapshot in dir that MarkReported has
+// not yet acknowledged, or nil if there is none.
+func LatestUnreported(dir string) (*ShutdownSnapshot, error) {
+	files, err := listSnapshots(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(files) - 1; i >= 0; i-- {
+		if files[i].reported {
+			continue
+		}
+		data, err := os.ReadFile(snapshotPath(dir, files[i].sequence, false)) // #nosec G304 -- path built from a matched file name
+		if err != nil {
+			return nil, fmt.Errorf("read shutdown snapshot: %w", err)
+		}
+		var snapshot ShutdownSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("parse shutdown snapshot %d: %w", files[i].sequence, err)
+		}
+		return &snapshot, nil
+	}
+	return nil, nil
+}
+
+// MarkReported acknowledges a snapshot so later starts do not report it
+// again. The file is kept, renamed, for operators.
+func MarkReported(dir string, snapshot *ShutdownSnapshot) error {
+	if err := os.Rename(snapshotPath(dir, snapshot.Sequence, false), snapshotPath(dir, snapshot.Sequence, true)); err != nil {
+		return fmt.Errorf("mark shutdown snapshot reported: %w", err)
+	}
+	return nil
+}
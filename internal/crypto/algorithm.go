@@ -0,0 +1,263 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// An identity is a P-256 ECDSA or an Ed25519 key. Either way a signature
+// is over the SHA-256 of the signed data, as the hash ECDSA signs or as
+// the message Ed25519 does, and is verified by the algorithm of the
+// signer's registered key, never by one a message names. Peers of the same
+// algorithm derive pairwise keys, by ECDH on P-256 or by X25519 on the
+// Montgomery forms of their Ed25519 keys; a mixed pair shares no curve, so
+// it authenticates by signature alone (see protocol.SharesKeyAgreement).
+
+// NewSecureChannelWithAlgorithm creates a channel with a fresh identity of
+// algorithm; NewSecureChannel is the same for protocol.AlgorithmECDSAP256.
+func NewSecureChannelWithAlgorithm(algorithm protocol.AlgorithmID) (*SecureChannel, error) {
+	privateKey, err := generateIdentityKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return newSecureChannel(privateKey), nil
+}
+
+// Algorithm returns the algorithm of the channel's identity key.
+func (sc *SecureChannel) Algorithm() protocol.AlgorithmID {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	algorithm, _ := KeyAlgorithm(sc.publicKey)
+	return algorithm
+}
+
+// PeerAlgorithm returns the algorithm of peerID's registered key.
+func (sc *SecureChannel) PeerAlgorithm(peerID string) (protocol.AlgorithmID, error) {
+	publicKey, err := sc.peerKey(peerID)
+	if err != nil {
+		return "", err
+	}
+	return KeyAlgorithm(publicKey)
+}
+
+// KeyAlgorithm returns the algorithm of an identity public key, failing
+// with protocol.ErrUnsupportedAlgorithm for any other kind of key.
+func KeyAlgorithm(publicKey stdcrypto.PublicKey) (protocol.AlgorithmID, error) {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if key == nil {
+			return "", errors.New("public key cannot be nil")
+		}
+		if key.Curve != elliptic.P256() {
+			return "", fmt.Errorf("%w: ECDSA on %s", protocol.ErrUnsupportedAlgorithm, key.Curve.Params().Name)
+		}
+		return protocol.AlgorithmECDSAP256, nil
+	case ed25519.PublicKey:
+		if len(key) != ed25519.PublicKeySize {
+			return "", fmt.Errorf("Ed25519 public key is %d bytes", len(key))
+		}
+		return protocol.AlgorithmEd25519, nil
+	case nil:
+		return "", errors.New("public key cannot be nil")
+	default:
+		return "", fmt.Errorf("%w: %T", protocol.ErrUnsupportedAlgorithm, publicKey)
+	}
+}
+
+// PublicKeyAlgorithm returns the algorithm of a PEM identity public key.
+func PublicKeyAlgorithm(publicKeyPEM []byte) (protocol.AlgorithmID, error) {
+	publicKey, err := ImportPublicKey(publicKeyPEM)
+	if err != nil {
+		return "", err
+	}
+	return KeyAlgorithm(publicKey)
+}
+
+// FingerprintAlgorithm returns the algorithm a PublicKeyFingerprint names.
+func FingerprintAlgorithm(fingerprint string) (protocol.AlgorithmID, error) {
+	name, _, found := strings.Cut(fingerprint, ":")
+	if !found {
+		return protocol.AlgorithmECDSAP256, nil
+	}
+	return protocol.ParseAlgorithmID(name)
+}
+
+// identityFingerprint is PublicKeyFingerprint of a key of algorithm with
+// PKIX DER der. P-256 fingerprints keep the bare form they had before
+// Ed25519, so existing pins and signed rounds still match.
+func identityFingerprint(algorithm protocol.AlgorithmID, der []byte) string {
+	if algorithm == protocol.AlgorithmECDSAP256 {
+		return keyFingerprint(der)
+	}
+	return string(algorithm) + ":" + keyFingerprint(der)
+}
+
+func generateIdentityKey(algorithm protocol.AlgorithmID) (stdcrypto.Signer, error) {
+	switch algorithm.OrDefault() {
+	case protocol.AlgorithmECDSAP256:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key pair: %w", err)
+		}
+		return key, nil
+	case protocol.AlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key pair: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", protocol.ErrUnsupportedAlgorithm, string(algorithm))
+	}
+}
+
+// identityPrivateKey accepts a parsed private key as an identity if it is
+// a P-256 ECDSA or an Ed25519 key.
+func identityPrivateKey(key any) (stdcrypto.Signer, error) {
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() {
+			return nil, errors.New("identity key is not on P-256")
+		}
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: %T identity key", protocol.ErrUnsupportedAlgorithm, key)
+	}
+}
+
+// parseIdentityPublicKey parses a PKIX DER identity public key.
+func parseIdentityPublicKey(der []byte) (stdcrypto.PublicKey, error) {
+	publicKey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	if _, err := KeyAlgorithm(publicKey); err != nil {
+		return nil, err
+	}
+	return publicKey, nil
+}
+
+// decodePublicKeyPEM returns the DER of a PEM identity public key and
+// its algorithm.
+func decodePublicKeyPEM(publicKeyPEM []byte) ([]byte, protocol.AlgorithmID, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, "", errors.New("failed to decode PEM block")
+	}
+	publicKey, err := parseIdentityPublicKey(block.Bytes)
+	if err != nil {
+		return nil, "", err
+	}
+	algorithm, err := KeyAlgorithm(publicKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return block.Bytes, algorithm, nil
+}
+
+// signDigest signs a SHA-256 digest with an identity key.
+func signDigest(privateKey stdcrypto.Signer, digest []byte) ([]byte, error) {
+	switch key := privateKey.(type) {
+	case *ecdsa.PrivateKey:
+		return ecdsa.SignASN1(rand.Reader, key, digest)
+	case ed25519.PrivateKey:
+		return ed25519.Sign(key, digest), nil
+	default:
+		return nil, fmt.Errorf("%w: %T", protocol.ErrUnsupportedAlgorithm, privateKey)
+	}
+}
+
+// verifyDigest checks a signDigest signature with the algorithm of
+// publicKey.
+func verifyDigest(publicKey stdcrypto.PublicKey, digest, signature []byte) bool {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest, signature)
+	case ed25519.PublicKey:
+		return len(key) == ed25519.PublicKeySize && ed25519.Verify(key, digest, signature)
+	default:
+		return false
+	}
+}
+
+// agreementPrivateKey returns the key agreement form of an identity key:
+// the same scalar on P-256, or the X25519 scalar an Ed25519 seed expands
+// to.
+func agreementPrivateKey(privateKey stdcrypto.Signer) (*ecdh.PrivateKey, error) {
+	switch key := privateKey.(type) {
+	case *ecdsa.PrivateKey:
+		return key.ECDH()
+	case ed25519.PrivateKey:
+		expanded := sha512.Sum512(key.Seed())
+		return ecdh.X25519().NewPrivateKey(expanded[:32])
+	default:
+		return nil, fmt.Errorf("%w: %T", protocol.ErrUnsupportedAlgorithm, privateKey)
+	}
+}
+
+// agreementPublicKey returns the key agreement form of an identity public
+// key, matching agreementPrivateKey.
+func agreementPublicKey(publicKey stdcrypto.PublicKey) (*ecdh.PublicKey, error) {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return key.ECDH()
+	case ed25519.PublicKey:
+		return edwardsToMontgomery(key)
+	default:
+		return nil, fmt.Errorf("%w: %T", protocol.ErrUnsupportedAlgorithm, publicKey)
+	}
+}
+
+// curve25519P is the field prime 2^255 - 19.
+var curve25519P = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// edwardsToMontgomery maps an Ed25519 public key to the X25519 public key
+// of the same scalar, u = (1 + y) / (1 - y) mod p, as RFC 7748 section 4.1
+// relates the curves.
+func edwardsToMontgomery(key ed25519.PublicKey) (*ecdh.PublicKey, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("Ed25519 public key is %d bytes", len(key))
+	}
+	be := make([]byte, len(key))
+	for i, b := range key {
+		be[len(key)-1-i] = b
+	}
+	be[0] &= 0x7f // the sign of x
+	y := new(big.Int).SetBytes(be)
+	if y.Cmp(curve25519P) >= 0 {
+		return nil, errors.New("Ed25519 public key is not canonical")
+	}
+	one := big.NewInt(1)
+	den := new(big.Int).Sub(one, y)
+	den.Mod(den, curve25519P)
+	if den.Sign() == 0 {
+		return nil, errors.New("Ed25519 public key is the identity point")
+	}
+	u := new(big.Int).Add(one, y)
+	u.Mul(u, den.ModInverse(den, curve25519P))
+	u.Mod(u, curve25519P)
+
+	le := u.FillBytes(make([]byte, 32))
+	for i, j := 0, len(le)-1; i < j; i, j = i+1, j-1 {
+		le[i], le[j] = le[j], le[i]
+	}
+	return ecdh.X25519().NewPublicKey(le)
+}
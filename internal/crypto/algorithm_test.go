@@ -0,0 +1,243 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package crypto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func newChannel(t *testing.T, algorithm protocol.AlgorithmID) *SecureChannel {
+	t.Helper()
+	sc, err := NewSecureChannelWithAlgorithm(algorithm)
+	if err != nil {
+		t.Fatalf("new %s channel: %v", algorithm, err)
+	}
+	if sc.Algorithm() != algorithm {
+		t.Fatalf("channel algorithm %s, want %s", sc.Algorithm(), algorithm)
+	}
+	return sc
+}
+
+// introduce registers a and b with each other from their exported keys.
+func introduce(t *testing.T, aID string, a *SecureChannel, bID string, b *SecureChannel) {
+	t.Helper()
+	for _, pair := range []struct {
+		id       string
+		from, to *SecureChannel
+	}{{aID, a, b}, {bID, b, a}} {
+		exported, err := pair.from.ExportPublicKey()
+		if err != nil {
+			t.Fatalf("export %s: %v", pair.id, err)
+		}
+		key, err := ImportPublicKey(exported)
+		if err != nil {
+			t.Fatalf("import %s: %v", pair.id, err)
+		}
+		if err := pair.to.RegisterPeer(pair.id, key); err != nil {
+			t.Fatalf("register %s: %v", pair.id, err)
+		}
+	}
+}
+
+func TestEd25519PeersSignAndShareSessionKeys(t *testing.T) {
+	alice := newChannel(t, protocol.AlgorithmEd25519)
+	bob := newChannel(t, protocol.AlgorithmEd25519)
+	introduce(t, "alice", alice, "bob", bob)
+
+	sig, err := alice.SignData([]byte("round 7 vote"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := bob.VerifySignature("alice", []byte("round 7 vote"), sig); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := bob.VerifySignature("alice", []byte("round 8 vote"), sig); err == nil {
+		t.Fatal("verified a signature over other data")
+	}
+
+	// Both ends derive the same X25519 session key from their Ed25519
+	// identities.
+	msg, err := alice.SecureModelUpdate("bob", []byte("weights"))
+	if err != nil {
+		t.Fatalf("secure update: %v", err)
+	}
+	msg.SenderID = "alice"
+	if msg.Algorithm != protocol.AlgorithmEd25519 {
+		t.Fatalf("message algorithm %q", msg.Algorithm)
+	}
+	if plaintext, err := bob.VerifyAndDecryptMessage(msg); err != nil || string(plaintext) != "weights" {
+		t.Fatalf("decrypt = %q, %v", plaintext, err)
+	}
+	aliceMAC, _ := alice.VoteMACKey("bob")
+	bobMAC, _ := bob.VoteMACKey("alice")
+	if len(aliceMAC) == 0 || !bytes.Equal(aliceMAC, bobMAC) {
+		t.Fatal("Ed25519 peers derived different vote MAC keys")
+	}
+
+	// A message naming the other algorithm is refused before verifying.
+	msg.Algorithm = protocol.AlgorithmECDSAP256
+	if _, err := bob.VerifyAndDecryptMessage(msg); !errors.Is(err, protocol.ErrAlgorithmMismatch) {
+		t.Fatalf("expected ErrAlgorithmMismatch, got %v", err)
+	}
+}
+
+func TestMixedAlgorithmPeersVerifyByRegisteredAlgorithm(t *testing.T) {
+	p256 := newChannel(t, protocol.AlgorithmECDSAP256)
+	ed := newChannel(t, protocol.AlgorithmEd25519)
+	introduce(t, "p256", p256, "ed", ed)
+
+	if algorithm, err := p256.PeerAlgorithm("ed"); err != nil || algorithm != protocol.AlgorithmEd25519 {
+		t.Fatalf("peer algorithm = %s, %v", algorithm, err)
+	}
+	for _, c := range []struct {
+		signerID         string
+		signer, verifier *SecureChannel
+	}{{"p256", p256, ed}, {"ed", ed, p256}} {
+		data := []byte("approve proposal-3 from " + c.signerID)
+		sig, err := c.signer.SignData(data)
+		if err != nil {
+			t.Fatalf("%s sign: %v", c.signerID, err)
+		}
+		if err := c.verifier.VerifySignatureAs(c.signerID, c.signer.Algorithm(), data, sig); err != nil {
+			t.Fatalf("verify %s: %v", c.signerID, err)
+		}
+		publicKey, _ := c.signer.ExportPublicKey()
+		if err := VerifyWithPublicKey(publicKey, data, sig); err != nil {
+			t.Fatalf("verify %s with its PEM key: %v", c.signerID, err)
+		}
+	}
+
+	// An Ed25519 signature passed off under the P-256 peer's name fails,
+	// as does one declaring another algorithm than the peer registered.
+	data := []byte("approve proposal-4")
+	edSig, _ := ed.SignData(data)
+	if err := ed.VerifySignature("p256", data, edSig); err == nil {
+		t.Fatal("an Ed25519 signature verified as the P-256 peer's")
+	}
+	if err := p256.VerifySignatureAs("ed", protocol.AlgorithmECDSAP256, data, edSig); !errors.Is(err, protocol.ErrAlgorithmMismatch) {
+		t.Fatalf("expected ErrAlgorithmMismatch, got %v", err)
+	}
+
+	// A mixed pair shares no key agreement curve.
+	if _, err := p256.EncryptMessage("ed", []byte("x")); !errors.Is(err, protocol.ErrAlgorithmMismatch) {
+		t.Fatalf("mixed pair session: expected ErrAlgorithmMismatch, got %v", err)
+	}
+	if _, err := ed.VoteMACKey("p256"); !errors.Is(err, protocol.ErrAlgorithmMismatch) {
+		t.Fatalf("mixed pair vote MAC key: expected ErrAlgorithmMismatch, got %v", err)
+	}
+}
+
+func TestKeyEncodingsNameTheirAlgorithm(t *testing.T) {
+	p256 := newChannel(t, protocol.AlgorithmECDSAP256)
+	ed := newChannel(t, protocol.AlgorithmEd25519)
+
+	p256Key, _ := p256.ExportPublicKey()
+	edKey, _ := ed.ExportPublicKey()
+	p256Fingerprint, err := PublicKeyFingerprint(p256Key)
+	if err != nil {
+		t.Fatalf("P-256 fingerprint: %v", err)
+	}
+	edFingerprint, err := PublicKeyFingerprint(edKey)
+	if err != nil {
+		t.Fatalf("Ed25519 fingerprint: %v", err)
+	}
+	block, _ := pem.Decode(p256Key)
+	if p256Fingerprint != keyFingerprint(block.Bytes) {
+		t.Fatalf("P-256 fingerprint %s changed form", p256Fingerprint)
+	}
+	if !strings.HasPrefix(edFingerprint, "ed25519:") {
+		t.Fatalf("Ed25519 fingerprint %s does not name its algorithm", edFingerprint)
+	}
+	for fingerprint, want := range map[string]protocol.AlgorithmID{p256Fingerprint: protocol.AlgorithmECDSAP256, edFingerprint: protocol.AlgorithmEd25519} {
+		if got, err := FingerprintAlgorithm(fingerprint); err != nil || got != want {
+			t.Fatalf("FingerprintAlgorithm(%s) = %s, %v", fingerprint, got, err)
+		}
+	}
+	if _, err := FingerprintAlgorithm("rsa:00"); !errors.Is(err, protocol.ErrUnsupportedAlgorithm) {
+		t.Fatalf("expected ErrUnsupportedAlgorithm, got %v", err)
+	}
+	if got, err := PublicKeyAlgorithm(edKey); err != nil || got != protocol.AlgorithmEd25519 {
+		t.Fatalf("PublicKeyAlgorithm = %s, %v", got, err)
+	}
+
+	// An Ed25519 identity reloads from PKCS #8.
+	der, err := x509.MarshalPKCS8PrivateKey(ed.privateKey)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	loaded, err := LoadSecureChannel(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("load Ed25519 identity: %v", err)
+	}
+	if reloaded, _ := loaded.ExportPublicKey(); !bytes.Equal(reloaded, edKey) {
+		t.Fatal("reloaded Ed25519 identity has another key")
+	}
+
+	// Keys of other algorithms are refused.
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa key: %v", err)
+	}
+	rsaDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rsaDER})
+	if _, err := ImportPublicKey(rsaPEM); !errors.Is(err, protocol.ErrUnsupportedAlgorithm) {
+		t.Fatalf("import RSA key: expected ErrUnsupportedAlgorithm, got %v", err)
+	}
+	if err := p256.RegisterPeer("rsa", &rsaKey.PublicKey); !errors.Is(err, protocol.ErrUnsupportedAlgorithm) {
+		t.Fatalf("register RSA key: expected ErrUnsupportedAlgorithm, got %v", err)
+	}
+	if err := p256.RegisterPeer("short", ed25519.PublicKey(edKey[:8])); err == nil {
+		t.Fatal("registered a truncated Ed25519 key")
+	}
+}
+
+func TestEd25519IdentityPersistsAndRotates(t *testing.T) {
+	store, err := NewIdentityStore(filepath.Join(t.TempDir(), "identity.json"), []byte("passphrase"))
+	if err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	store.kdf = kdfParams{Time: 1, Memory: 64, Threads: 1}
+	if err := store.SetAlgorithm("rsa"); !errors.Is(err, protocol.ErrUnsupportedAlgorithm) {
+		t.Fatalf("expected ErrUnsupportedAlgorithm, got %v", err)
+	}
+	if err := store.SetAlgorithm(protocol.AlgorithmEd25519); err != nil {
+		t.Fatalf("set algorithm: %v", err)
+	}
+	node, err := store.LoadOrCreate()
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	reloaded, err := store.Load()
+	if err != nil || reloaded.Algorithm() != protocol.AlgorithmEd25519 {
+		t.Fatalf("reload = %v, %v", reloaded, err)
+	}
+
+	peer := newChannel(t, protocol.AlgorithmECDSAP256)
+	introduce(t, "node-a", node, "peer-b", peer)
+	cert, err := node.RotateIdentity("node-a", time.Hour)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if node.Algorithm() != protocol.AlgorithmEd25519 {
+		t.Fatalf("rotation changed the algorithm to %s", node.Algorithm())
+	}
+	if err := peer.ApplyRotation(cert); err != nil {
+		t.Fatalf("apply rotation: %v", err)
+	}
+	sig, _ := node.SignData([]byte("after rotation"))
+	if err := peer.VerifySignature("node-a", []byte("after rotation"), sig); err != nil {
+		t.Fatalf("verify after rotation: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// GroupKeySize is the AES-256 key length used for topic keys.
+const GroupKeySize = 32
+
+// ErrStaleGroupKey means a payload or key is for an older epoch than the
+// one held.
+var ErrStaleGroupKey = errors.New("stale group key epoch")
+
+// GroupKey is a symmetric key shared by the admitted members of a topic.
+// Members lists who the key was issued to, so any member can answer key
+// requests from the others.
+type GroupKey struct {
+	Topic   string   `json:"topic"`
+	Epoch   uint64   `json:"epoch"`
+	Key     []byte   `json:"key"`
+	Members []string `json:"members"`
+}
+
+// GossipEnvelope is an encrypted gossip payload. Only the topic and key
+// epoch travel in the clear.
+type GossipEnvelope struct {
+	Topic      string `json:"topic"`
+	Epoch      uint64 `json:"epoch"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NewGroupKey generates a fresh key for topic at epoch.
+func NewGroupKey(topic string, epoch uint64, members []string) (*GroupKey, error) {
+	key := make([]byte, GroupKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate group key: %w", err)
+	}
+	return &GroupKey{
+		Topic:   topic,
+		Epoch:   epoch,
+		Key:     key,
+		Members: append([]string(nil), members...),
+	}, nil
+}
+
+// Seal encrypts payload with AES-GCM, binding the topic and epoch as
+// additional data.
+func (k *GroupKey) Seal(payload []byte) (*GossipEnvelope, error) {
+	gcm, err := newGCM(k.Key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return &GossipEnvelope{
+		Topic:      k.Topic,
+		Epoch:      k.Epoch,
+		Ciphertext: gcm.Seal(nonce, nonce, payload, envelopeAAD(k.Topic, k.Epoch)),
+	}, nil
+}
+
+// Open decrypts an envelope sealed under this key.
+func (k *GroupKey) Open(envelope *GossipEnvelope) ([]byte, error) {
+	if envelope.Topic != k.Topic {
+		return nil, fmt.Errorf("envelope for topic %q opened with key for %q", envelope.Topic, k.Topic)
+	}
+	if envelope.Epoch != k.Epoch {
+		return nil, fmt.Errorf("%w: envelope epoch %d, key epoch %d", ErrStaleGroupKey, envelope.Epoch, k.Epoch)
+	}
+	gcm, err := newGCM(k.Key)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := envelope.Ciphertext[:gcm.NonceSize()], envelope.Ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, envelopeAAD(envelope.Topic, envelope.Epoch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// WrapGroupKey encrypts key for peerID under the pairwise session key.
+func (sc *SecureChannel) WrapGroupKey(peerID string, key *GroupKey) ([]byte, error) {
+	encoded, err := json.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("encode group key: %w", err)
+	}
+	return sc.EncryptMessage(peerID, encoded)
+}
+
+// UnwrapGroupKey decrypts a group key wrapped by peerID.
+func (sc *SecureChannel) UnwrapGroupKey(peerID string, wrapped []byte) (*GroupKey, error) {
+	encoded, err := sc.DecryptMessage(peerID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	var key GroupKey
+	if err := json.Unmarshal(encoded, &key); err != nil {
+		return nil, fmt.Errorf("decode group key: %w", err)
+	}
+	if len(key.Key) != GroupKeySize {
+		return nil, fmt.Errorf("invalid group key length %d", len(key.Key))
+	}
+	return &key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
+
+func envelopeAAD(topic string, epoch uint64) []byte {
+	aad := make([]byte, 8, 8+len(topic))
+	binary.BigEndian.PutUint64(aad, epoch)
+	return append(aad, topic...)
+}
@@ -0,0 +1,338 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"golang.org/x/crypto/argon2"
+)
+
+// Identity store errors. Match them with errors.Is.
+var (
+	// ErrIdentityPassphrase means the identity file does not decrypt under
+	// the passphrase given. Not retryable with the same passphrase.
+	ErrIdentityPassphrase = errors.New("identity passphrase incorrect")
+	// ErrIdentityCorrupt means the identity file is truncated, malformed,
+	// or was altered after it was written. Not retryable; restore the file
+	// from a backup.
+	ErrIdentityCorrupt = errors.New("identity file corrupt")
+	// ErrIdentityPermissions means users other than the owner can access
+	// the identity file. Not retryable until its mode is tightened to 0600
+	// or the store is told to allow it.
+	ErrIdentityPermissions = errors.New("identity file permissions too open")
+	// ErrIdentityUnencrypted means the identity file holds a plaintext PEM
+	// key. MigratePlaintext encrypts it in place.
+	ErrIdentityUnencrypted = errors.New("identity file is not encrypted")
+)
+
+const (
+	identityFileVersion = 1
+	identityKDF         = "argon2id"
+	identityCheckLabel  = "sovereign-mohawk/identity-check/v1"
+	// Bounds on the KDF parameters a file may ask for, so a damaged file
+	// cannot make loading allocate without limit.
+	maxIdentityKDFTime   = 16
+	maxIdentityKDFMemory = 1 << 21
+)
+
+// kdfParams are the argon2id parameters a passphrase is stretched with.
+type kdfParams struct {
+	Time    uint32
+	Memory  uint32 // KiB
+	Threads uint8
+}
+
+// defaultIdentityKDF is the second recommended argon2id profile of RFC
+// 9106: three passes over 64 MiB.
+var defaultIdentityKDF = kdfParams{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// identityFile is the on-disk form of an encrypted identity: the PKCS #8
+// private key sealed with AES-256-GCM under a key derived from the
+// passphrase. Check lets a wrong passphrase be told apart from a damaged
+// file without weakening the seal: it is an HMAC under the derived key.
+type identityFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Time       uint32 `json:"time"`
+	Memory     uint32 `json:"memory"`
+	Threads    uint8  `json:"threads"`
+	Salt       []byte `json:"salt"`
+	Check      []byte `json:"check"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// additionalData binds the KDF parameters into the seal, so they cannot be
+// swapped to make a stolen file cheaper to attack.
+func (f *identityFile) additionalData() []byte {
+	return []byte(fmt.Sprintf("%d|%s|%d|%d|%d|%x", f.Version, f.KDF, f.Time, f.Memory, f.Threads, f.Salt))
+}
+
+// IdentityStore keeps a node's identity key in a passphrase-encrypted file,
+// so the node keeps the same identity, and with it its reputation and
+// registration, across restarts.
+type IdentityStore struct {
+	path          string
+	passphrase    []byte
+	allowInsecure bool
+	kdf           kdfParams
+	algorithm     protocol.AlgorithmID
+}
+
+// NewIdentityStore returns a store for the identity file at path, which
+// need not exist yet.
+func NewIdentityStore(path string, passphrase []byte) (*IdentityStore, error) {
+	if path == "" {
+		return nil, errors.New("identity path cannot be empty")
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("identity passphrase cannot be empty")
+	}
+	return &IdentityStore{
+		path:       path,
+		passphrase: append([]byte(nil), passphrase...),
+		kdf:        defaultIdentityKDF,
+	}, nil
+}
+
+// SetAllowInsecurePermissions lets the store load a file other users can
+// access instead of failing with ErrIdentityPermissions.
+func (s *IdentityStore) SetAllowInsecurePermissions(allow bool) {
+	s.allowInsecure = allow
+}
+
+// SetAlgorithm sets the algorithm of the identity LoadOrCreate generates,
+// protocol.AlgorithmECDSAP256 unless set. An existing identity keeps its
+// own.
+func (s *IdentityStore) SetAlgorithm(algorithm protocol.AlgorithmID) error {
+	if err := algorithm.Validate(); err != nil {
+		return err
+	}
+	s.algorithm = algorithm
+	return nil
+}
+
+// Path returns the identity file's path.
+func (s *IdentityStore) Path() string {
+	return s.path
+}
+
+// LoadOrCreate loads the identity, or generates one of the store's
+// algorithm and saves it when the file does not exist yet.
+func (s *IdentityStore) LoadOrCreate() (*SecureChannel, error) {
+	channel, err := s.Load()
+	if !errors.Is(err, fs.ErrNotExist) {
+		return channel, err
+	}
+	channel, err = NewSecureChannelWithAlgorithm(s.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Save(channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// Load reads and decrypts the identity. A missing file fails with an error
+// matching fs.ErrNotExist.
+func (s *IdentityStore) Load() (*SecureChannel, error) {
+	data, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		return nil, fmt.Errorf("%w: %s", ErrIdentityUnencrypted, s.path)
+	}
+	var file identityFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIdentityCorrupt, err)
+	}
+	der, err := s.open(&file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIdentityCorrupt, err)
+	}
+	privateKey, err := identityPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIdentityCorrupt, err)
+	}
+	return newSecureChannel(privateKey), nil
+}
+
+// Save encrypts the channel's current identity key and replaces the file
+// with it, as after RotateIdentity.
+func (s *IdentityStore) Save(sc *SecureChannel) error {
+	sc.mu.RLock()
+	privateKey := sc.privateKey
+	sc.mu.RUnlock()
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to marshal identity key: %w", err)
+	}
+	return s.write(der)
+}
+
+// MigratePlaintext encrypts an identity file that holds a plaintext PEM
+// key, as written before identities were encrypted, in place. It reports
+// whether it migrated anything; an already encrypted file is left alone.
+func (s *IdentityStore) MigratePlaintext() (bool, error) {
+	data, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	if block, _ := pem.Decode(data); block == nil {
+		return false, nil
+	}
+	privateKey, err := parsePrivateKeyPEM(data)
+	if err != nil {
+		return false, fmt.Errorf("plaintext identity: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal identity key: %w", err)
+	}
+	if err := s.write(der); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// read checks the file's permissions and returns its contents.
+func (s *IdentityStore) read() ([]byte, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("read identity: %w", err)
+	}
+	if err := s.checkPermissions(info); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(s.path) // #nosec G304 -- operator-supplied identity path
+	if err != nil {
+		return nil, fmt.Errorf("read identity: %w", err)
+	}
+	return bytes.TrimSpace(data), nil
+}
+
+// checkPermissions refuses a file that users other than its owner can
+// access. Windows does not report Unix modes, so it is not checked there.
+func (s *IdentityStore) checkPermissions(info fs.FileInfo) error {
+	if s.allowInsecure || runtime.GOOS == "windows" {
+		return nil
+	}
+	if mode := info.Mode().Perm(); mode&0o077 != 0 {
+		return fmt.Errorf("%w: %s has mode %#o, want 0600", ErrIdentityPermissions, s.path, mode)
+	}
+	return nil
+}
+
+// open derives the key with the parameters file names and unseals the
+// private key.
+func (s *IdentityStore) open(file *identityFile) ([]byte, error) {
+	switch {
+	case file.Version != identityFileVersion:
+		return nil, fmt.Errorf("%w: version %d", ErrIdentityCorrupt, file.Version)
+	case file.KDF != identityKDF:
+		return nil, fmt.Errorf("%w: kdf %q", ErrIdentityCorrupt, file.KDF)
+	case file.Time == 0 || file.Time > maxIdentityKDFTime || file.Memory == 0 || file.Memory > maxIdentityKDFMemory || file.Threads == 0:
+		return nil, fmt.Errorf("%w: kdf parameters t=%d m=%d p=%d", ErrIdentityCorrupt, file.Time, file.Memory, file.Threads)
+	case len(file.Salt) == 0 || len(file.Check) == 0:
+		return nil, fmt.Errorf("%w: missing salt or check", ErrIdentityCorrupt)
+	}
+	key := argon2.IDKey(s.passphrase, file.Salt, file.Time, file.Memory, file.Threads, 32)
+	if !hmac.Equal(identityCheck(key), file.Check) {
+		return nil, ErrIdentityPassphrase
+	}
+	gcm, err := identityAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: nonce is %d bytes", ErrIdentityCorrupt, len(file.Nonce))
+	}
+	der, err := gcm.Open(nil, file.Nonce, file.Ciphertext, file.additionalData())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIdentityCorrupt, err)
+	}
+	return der, nil
+}
+
+// write seals der under a fresh salt and nonce and replaces the file.
+func (s *IdentityStore) write(der []byte) error {
+	file := identityFile{
+		Version: identityFileVersion,
+		KDF:     identityKDF,
+		Time:    s.kdf.Time,
+		Memory:  s.kdf.Memory,
+		Threads: s.kdf.Threads,
+		Salt:    make([]byte, 16),
+	}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := argon2.IDKey(s.passphrase, file.Salt, file.Time, file.Memory, file.Threads, 32)
+	gcm, err := identityAEAD(key)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	file.Check = identityCheck(key)
+	file.Ciphertext = gcm.Seal(nil, file.Nonce, der, file.additionalData())
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode identity: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("write identity: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("write identity: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("write identity: %w", err)
+	}
+	return nil
+}
+
+func identityCheck(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(identityCheckLabel))
+	return mac.Sum(nil)[:16]
+}
+
+func identityAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
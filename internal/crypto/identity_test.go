@@ -0,0 +1,175 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// testIdentityStore returns a store at path with a cheap KDF, so tests do
+// not spend 64 MiB and three passes per derivation.
+func testIdentityStore(t *testing.T, path, passphrase string) *IdentityStore {
+	t.Helper()
+	store, err := NewIdentityStore(path, []byte(passphrase))
+	if err != nil {
+		t.Fatalf("new identity store: %v", err)
+	}
+	store.kdf = kdfParams{Time: 1, Memory: 64, Threads: 1}
+	return store
+}
+
+func channelFingerprint(t *testing.T, sc *SecureChannel) string {
+	t.Helper()
+	pub, err := sc.ExportPublicKey()
+	if err != nil {
+		t.Fatalf("export public key: %v", err)
+	}
+	fingerprint, err := PublicKeyFingerprint(pub)
+	if err != nil {
+		t.Fatalf("fingerprint: %v", err)
+	}
+	return fingerprint
+}
+
+func TestIdentityStoreKeepsFingerprintAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "identity.json")
+	created, err := testIdentityStore(t, path, "correct horse").LoadOrCreate()
+	if err != nil {
+		t.Fatalf("create identity: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat identity: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Fatalf("identity written with mode %#o", info.Mode().Perm())
+	}
+
+	// A fresh store stands in for the restarted node.
+	restarted, err := testIdentityStore(t, path, "correct horse").LoadOrCreate()
+	if err != nil {
+		t.Fatalf("reload identity: %v", err)
+	}
+	if got, want := channelFingerprint(t, restarted), channelFingerprint(t, created); got != want {
+		t.Fatalf("fingerprint changed across restart: %s, was %s", got, want)
+	}
+
+	// A rotated identity persists once saved.
+	store := testIdentityStore(t, path, "correct horse")
+	if _, err := restarted.RotateIdentity("node-1", time.Hour); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if err := store.Save(restarted); err != nil {
+		t.Fatalf("save rotated identity: %v", err)
+	}
+	reloaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("load rotated identity: %v", err)
+	}
+	if channelFingerprint(t, reloaded) != channelFingerprint(t, restarted) {
+		t.Fatal("rotated identity did not persist")
+	}
+}
+
+func TestIdentityStoreRejectsWrongPassphraseAndCorruption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "identity.json")
+	if _, err := testIdentityStore(t, path, "correct horse").LoadOrCreate(); err != nil {
+		t.Fatalf("create identity: %v", err)
+	}
+	if _, err := testIdentityStore(t, path, "battery staple").Load(); !errors.Is(err, ErrIdentityPassphrase) {
+		t.Fatalf("wrong passphrase: expected ErrIdentityPassphrase, got %v", err)
+	}
+	// LoadOrCreate must not paper over a wrong passphrase with a new key.
+	if _, err := testIdentityStore(t, path, "battery staple").LoadOrCreate(); !errors.Is(err, ErrIdentityPassphrase) {
+		t.Fatalf("wrong passphrase on create: expected ErrIdentityPassphrase, got %v", err)
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read identity: %v", err)
+	}
+	var file identityFile
+	if err := json.Unmarshal(original, &file); err != nil {
+		t.Fatalf("decode identity: %v", err)
+	}
+	file.Ciphertext[len(file.Ciphertext)/2] ^= 0x01
+	flipped, _ := json.Marshal(file)
+	for name, data := range map[string][]byte{
+		"flipped ciphertext": flipped,
+		"truncated":          original[:len(original)/2],
+		"empty":              nil,
+	} {
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if _, err := testIdentityStore(t, path, "correct horse").Load(); !errors.Is(err, ErrIdentityCorrupt) {
+			t.Fatalf("%s: expected ErrIdentityCorrupt, got %v", name, err)
+		}
+	}
+}
+
+func TestIdentityStoreRefusesOpenPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix file modes")
+	}
+	path := filepath.Join(t.TempDir(), "identity.json")
+	if _, err := testIdentityStore(t, path, "correct horse").LoadOrCreate(); err != nil {
+		t.Fatalf("create identity: %v", err)
+	}
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	store := testIdentityStore(t, path, "correct horse")
+	if _, err := store.LoadOrCreate(); !errors.Is(err, ErrIdentityPermissions) {
+		t.Fatalf("world-readable identity: expected ErrIdentityPermissions, got %v", err)
+	}
+	store.SetAllowInsecurePermissions(true)
+	if _, err := store.Load(); err != nil {
+		t.Fatalf("load with override: %v", err)
+	}
+}
+
+func TestIdentityStoreMigratesPlaintextKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "identity.pem")
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatalf("write plaintext key: %v", err)
+	}
+	plaintext := newSecureChannel(key)
+
+	store := testIdentityStore(t, path, "correct horse")
+	if _, err := store.Load(); !errors.Is(err, ErrIdentityUnencrypted) {
+		t.Fatalf("plaintext key: expected ErrIdentityUnencrypted, got %v", err)
+	}
+	if migrated, err := store.MigratePlaintext(); err != nil || !migrated {
+		t.Fatalf("migrate: %v, %v", migrated, err)
+	}
+	if migrated, err := store.MigratePlaintext(); err != nil || migrated {
+		t.Fatalf("second migrate should be a no-op: %v, %v", migrated, err)
+	}
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("load migrated identity: %v", err)
+	}
+	if channelFingerprint(t, loaded) != channelFingerprint(t, plaintext) {
+		t.Fatal("migration changed the identity")
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package crypto
+
+import (
+	"container/list"
+	stdcrypto "crypto"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Peer state defaults. A node keeps every peer's key no longer than it
+// keeps using it, and never more than these many at once.
+const (
+	DefaultPeerKeyCapacity    = 65536
+	DefaultSessionKeyCapacity = 16384
+	DefaultPeerIdleTTL        = 24 * time.Hour
+)
+
+// What an eviction dropped.
+const (
+	// EvictedPeerKey means the peer's public key was dropped, with its
+	// session key and rotation state. Using the peer again re-runs the
+	// handshake through the channel's PeerKeyResolver.
+	EvictedPeerKey = "peer_key"
+	// EvictedSessionKey means only the session key was dropped; it is
+	// derived again from the peer's key on next use.
+	EvictedSessionKey = "session_key"
+)
+
+// Why an entry was evicted.
+const (
+	EvictionCapacity = "capacity"
+	EvictionIdle     = "idle"
+)
+
+// PeerStateLimits bounds the per-peer state a SecureChannel holds. Zero
+// fields take the defaults noted on them.
+type PeerStateLimits struct {
+	// PeerKeys caps the peer public keys held. Default
+	// DefaultPeerKeyCapacity.
+	PeerKeys int
+	// SessionKeys caps the session keys held. Default
+	// DefaultSessionKeyCapacity.
+	SessionKeys int
+	// IdleTTL drops state a peer has not used for this long. Default
+	// DefaultPeerIdleTTL.
+	IdleTTL time.Duration
+}
+
+// PeerEviction reports state dropped for a peer.
+type PeerEviction struct {
+	PeerID string
+	// Kind is EvictedPeerKey or EvictedSessionKey.
+	Kind string
+	// Reason is EvictionCapacity or EvictionIdle.
+	Reason string
+}
+
+// PeerEvictionHook is told of every eviction, after the channel's lock is
+// released.
+type PeerEvictionHook func(eviction PeerEviction)
+
+// PeerKeyResolver re-runs the handshake with a peer whose key the channel
+// no longer holds, returning the peer's current public key.
+type PeerKeyResolver func(peerID string) (stdcrypto.PublicKey, error)
+
+// ErrPeerKeyUnknown means the channel holds no key for a peer and could not
+// resolve one. Retryable once the peer's key is registered.
+var ErrPeerKeyUnknown = errors.New("peer public key not registered")
+
+// lruIndex orders peers by last use, most recent first.
+type lruIndex struct {
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	peerID   string
+	lastUsed time.Time
+}
+
+func newLRUIndex() *lruIndex {
+	return &lruIndex{order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (l *lruIndex) touch(peerID string, now time.Time) {
+	if element, ok := l.entries[peerID]; ok {
+		element.Value.(*lruEntry).lastUsed = now
+		l.order.MoveToFront(element)
+		return
+	}
+	l.entries[peerID] = l.order.PushFront(&lruEntry{peerID: peerID, lastUsed: now})
+}
+
+func (l *lruIndex) remove(peerID string) {
+	if element, ok := l.entries[peerID]; ok {
+		l.order.Remove(element)
+		delete(l.entries, peerID)
+	}
+}
+
+func (l *lruIndex) len() int { return l.order.Len() }
+
+// evict returns, oldest first, the unpinned peers idle since before cutoff
+// and, beyond them, enough of the least recently used to bring the index
+// down to capacity, removing them from the index. Pinned peers are never
+// returned, so the index may stay above capacity while they fill it.
+func (l *lruIndex) evict(capacity int, cutoff time.Time, pinned map[string]bool) []PeerEviction {
+	var evicted []PeerEviction
+	excess := l.order.Len() - capacity
+	for element := l.order.Back(); element != nil; {
+		entry := element.Value.(*lruEntry)
+		prev := element.Prev()
+		idle := entry.lastUsed.Before(cutoff)
+		if !idle && excess <= 0 {
+			break
+		}
+		if !pinned[entry.peerID] {
+			reason := EvictionCapacity
+			if idle {
+				reason = EvictionIdle
+			}
+			evicted = append(evicted, PeerEviction{PeerID: entry.peerID, Reason: reason})
+			l.order.Remove(element)
+			delete(l.entries, entry.peerID)
+			excess--
+		}
+		element = prev
+	}
+	return evicted
+}
+
+// SetPeerStateLimits bounds the peer state the channel holds, evicting
+// whatever exceeds the new limits.
+func (sc *SecureChannel) SetPeerStateLimits(limits PeerStateLimits) {
+	if limits.PeerKeys <= 0 {
+		limits.PeerKeys = DefaultPeerKeyCapacity
+	}
+	if limits.SessionKeys <= 0 {
+		limits.SessionKeys = DefaultSessionKeyCapacity
+	}
+	if limits.IdleTTL <= 0 {
+		limits.IdleTTL = DefaultPeerIdleTTL
+	}
+	sc.mu.Lock()
+	sc.limits = limits
+	evicted := sc.enforceLimitsLocked()
+	sc.mu.Unlock()
+	sc.notifyEvictions(evicted)
+}
+
+// SetPeerEvictionHook registers hook for evictions, replacing any previous
+// hook.
+func (sc *SecureChannel) SetPeerEvictionHook(hook PeerEvictionHook) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.evictionHook = hook
+}
+
+// SetPeerKeyResolver registers resolver to re-establish peers whose keys
+// were evicted, so they are re-admitted transparently when used again.
+func (sc *SecureChannel) SetPeerKeyResolver(resolver PeerKeyResolver) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.resolver = resolver
+}
+
+// PinPeers exempts peers, such as current shard members and seeds, from
+// eviction.
+func (sc *SecureChannel) PinPeers(peerIDs ...string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for _, peerID := range peerIDs {
+		sc.pinned[peerID] = true
+	}
+}
+
+// UnpinPeers makes peers evictable again.
+func (sc *SecureChannel) UnpinPeers(peerIDs ...string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for _, peerID := range peerIDs {
+		delete(sc.pinned, peerID)
+	}
+}
+
+// PeerStateSize returns how many peer keys and session keys the channel
+// holds.
+func (sc *SecureChannel) PeerStateSize() (peerKeys, sessionKeys int) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return len(sc.peerKeys), len(sc.sessionKeys)
+}
+
+// ExpireIdlePeerState evicts state idle for longer than the idle TTL and
+// returns how many entries it dropped. Limits are otherwise enforced as
+// state is added; call this periodically on a node that may go quiet.
+func (sc *SecureChannel) ExpireIdlePeerState() int {
+	sc.mu.Lock()
+	evicted := sc.enforceLimitsLocked()
+	sc.mu.Unlock()
+	sc.notifyEvictions(evicted)
+	return len(evicted)
+}
+
+// touchPeerLocked marks peerID's key, and its session key if held, as
+// just used. The caller holds sc.mu.
+func (sc *SecureChannel) touchPeerLocked(peerID string) {
+	now := sc.now()
+	if _, ok := sc.peerKeys[peerID]; ok {
+		sc.peerLRU.touch(peerID, now)
+	}
+	if _, ok := sc.sessionKeys[peerID]; ok {
+		sc.sessionLRU.touch(peerID, now)
+	}
+}
+
+// dropSessionLocked forgets peerID's session key. The caller holds sc.mu.
+func (sc *SecureChannel) dropSessionLocked(peerID string) {
+	delete(sc.sessionKeys, peerID)
+	sc.sessionLRU.remove(peerID)
+}
+
+// enforceLimitsLocked evicts idle and excess peer state and returns what
+// it dropped, for notifyEvictions once sc.mu is released. The caller holds
+// sc.mu.
+func (sc *SecureChannel) enforceLimitsLocked() []PeerEviction {
+	cutoff := sc.now().Add(-sc.limits.IdleTTL)
+	evicted := sc.peerLRU.evict(sc.limits.PeerKeys, cutoff, sc.pinned)
+	for i := range evicted {
+		peerID := evicted[i].PeerID
+		evicted[i].Kind = EvictedPeerKey
+		delete(sc.peerKeys, peerID)
+		delete(sc.peerSequences, peerID)
+		delete(sc.retiredKeys, peerID)
+		sc.dropSessionLocked(peerID)
+	}
+	sessions := sc.sessionLRU.evict(sc.limits.SessionKeys, cutoff, sc.pinned)
+	for i := range sessions {
+		sessions[i].Kind = EvictedSessionKey
+		delete(sc.sessionKeys, sessions[i].PeerID)
+	}
+	return append(evicted, sessions...)
+}
+
+func (sc *SecureChannel) notifyEvictions(evicted []PeerEviction) {
+	if len(evicted) == 0 {
+		return
+	}
+	sc.mu.RLock()
+	hook := sc.evictionHook
+	sc.mu.RUnlock()
+	if hook == nil {
+		return
+	}
+	for _, eviction := range evicted {
+		hook(eviction)
+	}
+}
+
+// peerKey returns peerID's public key, resolving it through the channel's
+// PeerKeyResolver when it is not held. It must be called without sc.mu.
+func (sc *SecureChannel) peerKey(peerID string) (stdcrypto.PublicKey, error) {
+	sc.mu.Lock()
+	key, ok := sc.peerKeys[peerID]
+	if ok {
+		sc.touchPeerLocked(peerID)
+	}
+	resolver := sc.resolver
+	sc.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if resolver == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPeerKeyUnknown, peerID)
+	}
+	key, err := resolver(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrPeerKeyUnknown, peerID, err)
+	}
+	if err := sc.RegisterPeer(peerID, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// sessionKey returns the session key shared with peerID, deriving it, and
+// resolving the peer's key if that was evicted, when it is not held.
+func (sc *SecureChannel) sessionKey(peerID string) ([]byte, error) {
+	sc.mu.Lock()
+	sessionKey, ok := sc.sessionKeys[peerID]
+	if ok {
+		sc.touchPeerLocked(peerID)
+	}
+	sc.mu.Unlock()
+	if ok {
+		return sessionKey, nil
+	}
+	if _, err := sc.peerKey(peerID); err != nil {
+		return nil, err
+	}
+	return sc.establishSessionKey(peerID)
+}
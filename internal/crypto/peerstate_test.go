@@ -0,0 +1,129 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package crypto
+
+import (
+	"bytes"
+	stdcrypto "crypto"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// heapInUse returns the live heap after a full collection.
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func TestPeerStateStaysBoundedUnderChurn(t *testing.T) {
+	node, _ := NewSecureChannel()
+	node.SetPeerStateLimits(PeerStateLimits{PeerKeys: 1024, SessionKeys: 256})
+	evictions := map[string]int{}
+	node.SetPeerEvictionHook(func(e PeerEviction) { evictions[e.Kind]++ })
+
+	// Synthetic peers share a few real keys: key generation is not what is
+	// under test, holding a key per peer ID is.
+	keys := make([]*SecureChannel, 16)
+	for i := range keys {
+		keys[i], _ = NewSecureChannel()
+	}
+	seeds := []string{"seed-0", "seed-1"}
+	for _, seed := range seeds {
+		if err := node.RegisterPeer(seed, keys[0].publicKey); err != nil {
+			t.Fatalf("register %s: %v", seed, err)
+		}
+	}
+	node.PinPeers(seeds...)
+
+	const peers = 100000
+	churn := func(from, to int) {
+		for i := from; i < to; i++ {
+			id := fmt.Sprintf("peer-%06d", i)
+			if err := node.RegisterPeer(id, keys[i%len(keys)].publicKey); err != nil {
+				t.Fatalf("register %s: %v", id, err)
+			}
+			if i%4 == 0 {
+				if _, err := node.EncryptMessage(id, []byte("update")); err != nil {
+					t.Fatalf("encrypt to %s: %v", id, err)
+				}
+			}
+		}
+	}
+	churn(0, peers/10)
+	settled := heapInUse()
+	churn(peers/10, peers)
+	grown := heapInUse()
+
+	peerKeys, sessionKeys := node.PeerStateSize()
+	if peerKeys > 1024 || sessionKeys > 256 {
+		t.Fatalf("holding %d peer keys and %d session keys, limits 1024 and 256", peerKeys, sessionKeys)
+	}
+	if node.peerLRU.len() != peerKeys || node.sessionLRU.len() != sessionKeys || len(node.peerSequences) > peerKeys || len(node.retiredKeys) > peerKeys {
+		t.Fatalf("index out of step with state: %d/%d peer keys, %d/%d session keys", node.peerLRU.len(), peerKeys, node.sessionLRU.len(), sessionKeys)
+	}
+	// Another 90k peers must not grow the heap by more than noise.
+	if grown > settled+2<<20 {
+		t.Fatalf("heap grew from %d to %d bytes over 90k churned peers", settled, grown)
+	}
+	if evictions[EvictedPeerKey] != peers+len(seeds)-1024 {
+		t.Fatalf("%d peer key evictions, want %d", evictions[EvictedPeerKey], peers+len(seeds)-1024)
+	}
+	for _, seed := range seeds {
+		if _, ok := node.peerKeys[seed]; !ok {
+			t.Fatalf("pinned %s was evicted", seed)
+		}
+	}
+}
+
+func TestEvictedPeerIsReestablishedTransparently(t *testing.T) {
+	node, _ := NewSecureChannel()
+	peer, _ := NewSecureChannel()
+	if err := peer.RegisterPeer("node-a", node.publicKey); err != nil {
+		t.Fatalf("RegisterPeer: %v", err)
+	}
+	now := time.Now()
+	node.now = func() time.Time { return now }
+	node.SetPeerStateLimits(PeerStateLimits{PeerKeys: 4, IdleTTL: time.Hour})
+	if err := node.RegisterPeer("peer-b", peer.publicKey); err != nil {
+		t.Fatalf("RegisterPeer: %v", err)
+	}
+	before, err := node.VoteMACKey("peer-b")
+	if err != nil {
+		t.Fatalf("VoteMACKey: %v", err)
+	}
+
+	// Idle past the TTL, the peer is dropped entirely.
+	now = now.Add(2 * time.Hour)
+	if dropped := node.ExpireIdlePeerState(); dropped != 1 {
+		t.Fatalf("expired %d entries, want the peer key, which takes its session along", dropped)
+	}
+	if peerKeys, sessionKeys := node.PeerStateSize(); peerKeys != 0 || sessionKeys != 0 {
+		t.Fatalf("holding %d peer keys and %d session keys after expiry", peerKeys, sessionKeys)
+	}
+	if _, err := node.EncryptMessage("peer-b", []byte("x")); !errors.Is(err, ErrPeerKeyUnknown) {
+		t.Fatalf("without a resolver: expected ErrPeerKeyUnknown, got %v", err)
+	}
+
+	// With a resolver, the reappearing peer is re-admitted on use.
+	resolved := 0
+	node.SetPeerKeyResolver(func(peerID string) (stdcrypto.PublicKey, error) {
+		resolved++
+		return peer.publicKey, nil
+	})
+	ciphertext, err := node.EncryptMessage("peer-b", []byte("round 9"))
+	if err != nil {
+		t.Fatalf("encrypt after eviction: %v", err)
+	}
+	if plaintext, err := peer.DecryptMessage("node-a", ciphertext); err != nil || string(plaintext) != "round 9" {
+		t.Fatalf("peer decrypt = %q, %v", plaintext, err)
+	}
+	after, err := node.VoteMACKey("peer-b")
+	if err != nil || !bytes.Equal(before, after) || resolved != 1 {
+		t.Fatalf("re-established MAC key differs or resolved %d times: %v", resolved, err)
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package crypto
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Certificate pinning errors. Match them with errors.Is.
+var (
+	// ErrPinMismatch means a server presented a key other than the one
+	// pinned for its host, with no approved rotation to it. Not retryable
+	// until an operator approves a rotation or re-provisions the pin.
+	ErrPinMismatch = errors.New("server key does not match pin")
+	// ErrPinRotation means a rotation record is malformed, expired, or not
+	// signed by the key pinned for its host.
+	ErrPinRotation = errors.New("pin rotation record invalid")
+)
+
+// How a pin was set.
+const (
+	PinFirstUse = "first_use"
+	PinRotated  = "rotation"
+	PinOverride = "override"
+)
+
+// Pin is the TLS public key trusted for one server host.
+type Pin struct {
+	Host string `json:"host"`
+	// Fingerprint is the hex SHA-256 of the certificate's
+	// SubjectPublicKeyInfo; PublicKey is that DER, kept to verify rotation
+	// records signed by it.
+	Fingerprint string    `json:"fingerprint"`
+	PublicKey   []byte    `json:"public_key"`
+	Source      string    `json:"source"`
+	PinnedAt    time.Time `json:"pinned_at"`
+	Rotations   int       `json:"rotations"`
+	// PendingRotation is the fingerprint an approved rotation will move
+	// the pin to once the server presents it.
+	PendingRotation string `json:"pending_rotation,omitempty"`
+}
+
+// PinRotation approves moving a host's pin to a new key. It is signed by
+// the key it replaces, so only whoever holds the pinned key can issue it.
+type PinRotation struct {
+	Host           string    `json:"host"`
+	OldFingerprint string    `json:"old_fingerprint"`
+	NewFingerprint string    `json:"new_fingerprint"`
+	IssuedAt       time.Time `json:"issued_at"`
+	ValidUntil     time.Time `json:"valid_until"`
+	Signature      []byte    `json:"signature,omitempty"`
+}
+
+func (r *PinRotation) signingDigest() ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = nil
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encode pin rotation: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	return sum[:], nil
+}
+
+// SignPinRotation signs r with signer, the private key of the certificate
+// being rotated away from.
+func SignPinRotation(r *PinRotation, signer stdcrypto.Signer) error {
+	digest, err := r.signingDigest()
+	if err != nil {
+		return err
+	}
+	var opts stdcrypto.SignerOpts = stdcrypto.SHA256
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		opts = stdcrypto.Hash(0)
+	}
+	signature, err := signer.Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return fmt.Errorf("sign pin rotation: %w", err)
+	}
+	r.Signature = signature
+	return nil
+}
+
+// PinStore pins the TLS keys of the servers a node calls, trusting each on
+// first use. A later connection must present the pinned key, or one an
+// approved PinRotation moves the pin to; anything else fails the handshake
+// with ErrPinMismatch. Pins are persisted to a JSON file.
+type PinStore struct {
+	path string
+
+	mu        sync.Mutex
+	pins      map[string]*Pin
+	rotations map[string]PinRotation
+	override  bool
+	now       func() time.Time
+}
+
+// NewPinStore loads the pins persisted at path, which need not exist yet.
+func NewPinStore(path string) (*PinStore, error) {
+	s := &PinStore{
+		path:      path,
+		pins:      make(map[string]*Pin),
+		rotations: make(map[string]PinRotation),
+		now:       time.Now,
+	}
+	// #nosec G304 -- path is operator configuration
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read pins: %w", err)
+	}
+	var pins []*Pin
+	if err := json.Unmarshal(data, &pins); err != nil {
+		return nil, fmt.Errorf("decode pins: %w", err)
+	}
+	for _, pin := range pins {
+		s.pins[pin.Host] = pin
+	}
+	return s, nil
+}
+
+// SetOverride makes the store re-pin a host that presents an unexpected
+// key instead of refusing it, for provisioning a node whose servers were
+// re-keyed while it was offline. Leave it off in normal operation.
+func (s *PinStore) SetOverride(override bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.override = override
+}
+
+// AddRotation approves r after checking it is signed by the key pinned for
+// its host. The pin moves when the host next presents r's new key.
+func (s *PinStore) AddRotation(r PinRotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pin, ok := s.pins[r.Host]
+	if !ok {
+		return fmt.Errorf("%w: no pin for %s", ErrPinRotation, r.Host)
+	}
+	if r.OldFingerprint != pin.Fingerprint {
+		return fmt.Errorf("%w: rotation for %s is from %.16s, pinned key is %.16s", ErrPinRotation, r.Host, r.OldFingerprint, pin.Fingerprint)
+	}
+	if r.NewFingerprint == "" || r.NewFingerprint == r.OldFingerprint {
+		return fmt.Errorf("%w: rotation for %s names no new key", ErrPinRotation, r.Host)
+	}
+	if !r.ValidUntil.IsZero() && s.now().After(r.ValidUntil) {
+		return fmt.Errorf("%w: rotation for %s expired at %s", ErrPinRotation, r.Host, r.ValidUntil.Format(time.RFC3339))
+	}
+	digest, err := r.signingDigest()
+	if err != nil {
+		return err
+	}
+	if err := verifyWithSPKI(pin.PublicKey, digest, r.Signature); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrPinRotation, r.Host, err)
+	}
+	s.rotations[r.Host] = r
+	pin.PendingRotation = r.NewFingerprint
+	return nil
+}
+
+// Check verifies that leaf, the certificate host presented, carries the key
+// pinned for host, pinning it if host has none.
+func (s *PinStore) Check(host string, leaf *x509.Certificate) error {
+	fingerprint := keyFingerprint(leaf.RawSubjectPublicKeyInfo)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	pin, ok := s.pins[host]
+	switch {
+	case !ok:
+		pin = &Pin{Host: host, Source: PinFirstUse, Rotations: 0}
+		s.pins[host] = pin
+	case pin.Fingerprint == fingerprint:
+		return nil
+	case s.rotationToLocked(host, pin.Fingerprint, fingerprint, now):
+		delete(s.rotations, host)
+		pin.Source = PinRotated
+		pin.Rotations++
+	case s.override:
+		pin.Source = PinOverride
+	default:
+		return fmt.Errorf("%w: %s presented %.16s, pinned %.16s", ErrPinMismatch, host, fingerprint, pin.Fingerprint)
+	}
+	pin.Fingerprint = fingerprint
+	pin.PublicKey = append([]byte(nil), leaf.RawSubjectPublicKeyInfo...)
+	pin.PinnedAt = now
+	pin.PendingRotation = ""
+	return s.saveLocked()
+}
+
+// rotationToLocked reports whether an approved rotation moves host's pin
+// from old to fingerprint. The caller holds s.mu.
+func (s *PinStore) rotationToLocked(host, old, fingerprint string, now time.Time) bool {
+	r, ok := s.rotations[host]
+	if !ok || r.OldFingerprint != old || r.NewFingerprint != fingerprint {
+		return false
+	}
+	return r.ValidUntil.IsZero() || !now.After(r.ValidUntil)
+}
+
+// Pins returns the pins, sorted by host.
+func (s *PinStore) Pins() []Pin {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pins := make([]Pin, 0, len(s.pins))
+	for _, pin := range s.pins {
+		pins = append(pins, *pin)
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Host < pins[j].Host })
+	return pins
+}
+
+// TLSConfig returns a copy of base for connecting to host that checks the
+// server against the store once the handshake's usual verification has
+// passed. With base's InsecureSkipVerify set, the pin is the only check,
+// for servers with self-signed certificates.
+func (s *PinStore) TLSConfig(host string, base *tls.Config) *tls.Config {
+	var cfg *tls.Config
+	if base != nil {
+		cfg = base.Clone()
+	} else {
+		cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = host
+	}
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("%w: %s presented no certificate", ErrPinMismatch, host)
+		}
+		return s.Check(host, cs.PeerCertificates[0])
+	}
+	return cfg
+}
+
+// Transport returns an HTTP transport whose TLS connections are configured
+// by base and pinned by the store, keyed by the host they dial.
+func (s *PinStore) Transport(base *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	s.PinTransport(transport, &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}, base)
+	return transport
+}
+
+// PinTransport makes transport dial its TLS connections through dialer,
+// configured by base and pinned by the store, keeping the rest of its
+// tuning. The handshake is bounded by transport.TLSHandshakeTimeout when
+// set.
+func (s *PinStore) PinTransport(transport *http.Transport, dialer *net.Dialer, base *tls.Config) {
+	handshakeTimeout := transport.TLSHandshakeTimeout
+	transport.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if handshakeTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, handshakeTimeout)
+			defer cancel()
+		}
+		conn := tls.Client(raw, s.TLSConfig(host, base))
+		if err := conn.HandshakeContext(ctx); err != nil {
+			_ = raw.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+// saveLocked writes the pins to the store's file. The caller holds s.mu.
+func (s *PinStore) saveLocked() error {
+	pins := make([]*Pin, 0, len(s.pins))
+	for _, pin := range s.pins {
+		pins = append(pins, pin)
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Host < pins[j].Host })
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode pins: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("write pins: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("write pins: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("write pins: %w", err)
+	}
+	return nil
+}
+
+// verifyWithSPKI checks signature over digest with the DER
+// SubjectPublicKeyInfo der.
+func verifyWithSPKI(der, digest, signature []byte) error {
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return fmt.Errorf("parse pinned key: %w", err)
+	}
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest, signature) {
+			return errors.New("signature does not verify")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, digest, signature) {
+			return errors.New("signature does not verify")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, stdcrypto.SHA256, digest, signature); err != nil {
+			return errors.New("signature does not verify")
+		}
+	default:
+		return fmt.Errorf("unsupported pinned key type %T", pub)
+	}
+	return nil
+}
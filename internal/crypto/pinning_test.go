@@ -0,0 +1,168 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// pinnedServer serves TLS with a fresh self-signed key, returning the
+// server and its key.
+func pinnedServer(t *testing.T) (*httptest.Server, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	cert, err := makeSelfSignedTLSCert(key)
+	if err != nil {
+		t.Fatalf("make cert: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv, key
+}
+
+// pinnedGet calls srv through a fresh client pinned by store, so no
+// connection is reused between calls.
+func pinnedGet(store *PinStore, srv *httptest.Server) error {
+	client := &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: store.Transport(&tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12}), // #nosec G402 -- the pin is the check under test
+	}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func spkiFingerprint(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+	return keyFingerprint(srv.TLS.Certificates[0].Leaf.RawSubjectPublicKeyInfo)
+}
+
+func TestPinStoreTrustsOnFirstUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pins.json")
+	store, err := NewPinStore(path)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	srv, _ := pinnedServer(t)
+	if err := pinnedGet(store, srv); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := pinnedGet(store, srv); err != nil {
+		t.Fatalf("same key again: %v", err)
+	}
+	pins := store.Pins()
+	if len(pins) != 1 || pins[0].Host != "127.0.0.1" || pins[0].Source != PinFirstUse || pins[0].Fingerprint != spkiFingerprint(t, srv) {
+		t.Fatalf("pins = %+v", pins)
+	}
+
+	// The pin survives a restart.
+	reloaded, err := NewPinStore(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := reloaded.Pins(); len(got) != 1 || got[0].Fingerprint != pins[0].Fingerprint {
+		t.Fatalf("reloaded pins = %+v", got)
+	}
+}
+
+func TestPinStoreRejectsUnexpectedKeyChange(t *testing.T) {
+	store, err := NewPinStore(filepath.Join(t.TempDir(), "pins.json"))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	original, _ := pinnedServer(t)
+	if err := pinnedGet(store, original); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	// A hijacked name answers with another key.
+	impostor, _ := pinnedServer(t)
+	if err := pinnedGet(store, impostor); !errors.Is(err, ErrPinMismatch) {
+		t.Fatalf("impostor: expected ErrPinMismatch, got %v", err)
+	}
+	if pins := store.Pins(); pins[0].Fingerprint != spkiFingerprint(t, original) {
+		t.Fatalf("pin moved to the impostor: %+v", pins)
+	}
+
+	// A rotation the impostor signs for itself is refused.
+	_, impostorKey := pinnedServer(t)
+	forged := PinRotation{Host: "127.0.0.1", OldFingerprint: spkiFingerprint(t, original), NewFingerprint: spkiFingerprint(t, impostor), IssuedAt: time.Now()}
+	if err := SignPinRotation(&forged, impostorKey); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := store.AddRotation(forged); !errors.Is(err, ErrPinRotation) {
+		t.Fatalf("forged rotation: expected ErrPinRotation, got %v", err)
+	}
+
+	// The provisioning override re-pins.
+	store.SetOverride(true)
+	if err := pinnedGet(store, impostor); err != nil {
+		t.Fatalf("override: %v", err)
+	}
+	if pins := store.Pins(); pins[0].Source != PinOverride || pins[0].Fingerprint != spkiFingerprint(t, impostor) {
+		t.Fatalf("pins after override = %+v", pins)
+	}
+}
+
+func TestPinStoreAcceptsSignedRotation(t *testing.T) {
+	store, err := NewPinStore(filepath.Join(t.TempDir(), "pins.json"))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	original, originalKey := pinnedServer(t)
+	if err := pinnedGet(store, original); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	rotated, _ := pinnedServer(t)
+	rotation := PinRotation{
+		Host:           "127.0.0.1",
+		OldFingerprint: spkiFingerprint(t, original),
+		NewFingerprint: spkiFingerprint(t, rotated),
+		IssuedAt:       time.Now().UTC(),
+		ValidUntil:     time.Now().Add(time.Hour).UTC(),
+	}
+	if err := SignPinRotation(&rotation, originalKey); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	tampered := rotation
+	tampered.ValidUntil = tampered.ValidUntil.Add(24 * time.Hour)
+	if err := store.AddRotation(tampered); !errors.Is(err, ErrPinRotation) {
+		t.Fatalf("tampered rotation: expected ErrPinRotation, got %v", err)
+	}
+	if err := store.AddRotation(rotation); err != nil {
+		t.Fatalf("add rotation: %v", err)
+	}
+	if pins := store.Pins(); pins[0].PendingRotation != rotation.NewFingerprint {
+		t.Fatalf("pending rotation not reported: %+v", pins)
+	}
+
+	if err := pinnedGet(store, rotated); err != nil {
+		t.Fatalf("rotated server: %v", err)
+	}
+	pins := store.Pins()
+	if pins[0].Source != PinRotated || pins[0].Rotations != 1 || pins[0].Fingerprint != rotation.NewFingerprint || pins[0].PendingRotation != "" {
+		t.Fatalf("pins after rotation = %+v", pins)
+	}
+	// The old key is no longer accepted.
+	if err := pinnedGet(store, original); !errors.Is(err, ErrPinMismatch) {
+		t.Fatalf("old key after rotation: expected ErrPinMismatch, got %v", err)
+	}
+}
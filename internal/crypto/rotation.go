@@ -0,0 +1,226 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package crypto
+
+import (
+	"bytes"
+	stdcrypto "crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultRotationGracePeriod is how long a peer's previous identity key is
+// still accepted for verifying signatures after it rotates.
+const DefaultRotationGracePeriod = 10 * time.Minute
+
+// Identity rotation errors. Match them with errors.Is.
+var (
+	// ErrRotationSequence means a certificate skipped a sequence number.
+	ErrRotationSequence = errors.New("rotation sequence out of order")
+	// ErrRotationReplay means a certificate was already applied or was
+	// signed by a key the peer has since rotated away from.
+	ErrRotationReplay = errors.New("rotation certificate replayed")
+	// ErrKeyRevoked means the signing key has been blacklisted.
+	ErrKeyRevoked = errors.New("identity key revoked")
+	// ErrRotationExpired means the certificate is outside its validity window.
+	ErrRotationExpired = errors.New("rotation certificate not valid at this time")
+	// ErrRotationSignature means the old key did not sign the certificate.
+	ErrRotationSignature = errors.New("rotation certificate signature invalid")
+)
+
+// RotationCertificate moves a node's identity to a new key. It is signed by
+// the key it replaces, so only the current key holder can issue it.
+type RotationCertificate struct {
+	NodeID       string    `json:"node_id"`
+	Sequence     uint64    `json:"sequence"`
+	OldPublicKey []byte    `json:"old_public_key"`
+	NewPublicKey []byte    `json:"new_public_key"`
+	ValidFrom    time.Time `json:"valid_from"`
+	ValidUntil   time.Time `json:"valid_until"`
+	Signature    []byte    `json:"signature,omitempty"`
+}
+
+// retiredKey is a rotated-out peer key still accepted until a deadline.
+type retiredKey struct {
+	key   stdcrypto.PublicKey
+	until time.Time
+}
+
+func (c *RotationCertificate) signingDigest() ([]byte, error) {
+	unsigned := *c
+	unsigned.Signature = nil
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encode rotation certificate: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	return sum[:], nil
+}
+
+// RotateIdentity replaces this channel's identity key with a fresh one of
+// the same algorithm and returns a certificate, signed by the old key, for
+// peers to apply. Session keys derived from the old key are discarded.
+func (sc *SecureChannel) RotateIdentity(nodeID string, validity time.Duration) (*RotationCertificate, error) {
+	newKey, err := generateIdentityKey(sc.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	oldDER, err := x509.MarshalPKIXPublicKey(sc.publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	newDER, err := x509.MarshalPKIXPublicKey(newKey.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	now := time.Now().UTC()
+	cert := &RotationCertificate{
+		NodeID:       nodeID,
+		Sequence:     sc.keySequence + 1,
+		OldPublicKey: oldDER,
+		NewPublicKey: newDER,
+		ValidFrom:    now,
+		ValidUntil:   now.Add(validity),
+	}
+	digest, err := cert.signingDigest()
+	if err != nil {
+		return nil, err
+	}
+	cert.Signature, err = signDigest(sc.privateKey, digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign rotation certificate: %w", err)
+	}
+
+	sc.privateKey = newKey
+	sc.publicKey = newKey.Public()
+	sc.keySequence = cert.Sequence
+	sc.sessionKeys = make(map[string][]byte)
+	sc.sessionLRU = newLRUIndex()
+	return cert, nil
+}
+
+// ApplyRotation verifies a peer's rotation certificate and atomically
+// rebinds the peer to its new key, which may be of another algorithm than
+// the old. The old key stays valid for verifying signatures during the
+// grace period.
+func (sc *SecureChannel) ApplyRotation(cert *RotationCertificate) error {
+	if cert == nil {
+		return errors.New("rotation certificate cannot be nil")
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.revokedKeys[keyFingerprint(cert.OldPublicKey)] {
+		return fmt.Errorf("%w: %s rotated from a blacklisted key", ErrKeyRevoked, cert.NodeID)
+	}
+	current, exists := sc.peerKeys[cert.NodeID]
+	if !exists {
+		return fmt.Errorf("peer public key not found: %s", cert.NodeID)
+	}
+	currentDER, err := x509.MarshalPKIXPublicKey(current)
+	if err != nil {
+		return fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	applied := sc.peerSequences[cert.NodeID]
+	if cert.Sequence <= applied {
+		return fmt.Errorf("%w: %s sequence %d, current %d", ErrRotationReplay, cert.NodeID, cert.Sequence, applied)
+	}
+	if cert.Sequence != applied+1 {
+		return fmt.Errorf("%w: %s sequence %d after %d", ErrRotationSequence, cert.NodeID, cert.Sequence, applied)
+	}
+	if !bytes.Equal(cert.OldPublicKey, currentDER) {
+		return fmt.Errorf("%w: %s certificate does not rotate the current key", ErrRotationSignature, cert.NodeID)
+	}
+	now := time.Now()
+	if now.Before(cert.ValidFrom) || !now.Before(cert.ValidUntil) {
+		return fmt.Errorf("%w: %s", ErrRotationExpired, cert.NodeID)
+	}
+
+	digest, err := cert.signingDigest()
+	if err != nil {
+		return err
+	}
+	if !verifyDigest(current, digest, cert.Signature) {
+		return fmt.Errorf("%w: %s", ErrRotationSignature, cert.NodeID)
+	}
+	newKey, err := parseIdentityPublicKey(cert.NewPublicKey)
+	if err != nil {
+		return err
+	}
+
+	sc.retiredKeys[cert.NodeID] = append(sc.pruneRetiredLocked(cert.NodeID, now), retiredKey{key: current, until: now.Add(sc.rotationGrace)})
+	sc.peerKeys[cert.NodeID] = newKey
+	sc.peerSequences[cert.NodeID] = cert.Sequence
+	sc.dropSessionLocked(cert.NodeID)
+	sc.touchPeerLocked(cert.NodeID)
+	return nil
+}
+
+// BlacklistKey revokes a public key (PKIX DER). Rotations signed by it are
+// rejected and it no longer verifies signatures during any grace period.
+func (sc *SecureChannel) BlacklistKey(publicKeyDER []byte) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.revokedKeys[keyFingerprint(publicKeyDER)] = true
+}
+
+// SetRotationGracePeriod sets how long rotated-out peer keys keep verifying.
+func (sc *SecureChannel) SetRotationGracePeriod(grace time.Duration) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.rotationGrace = grace
+}
+
+// KeySequence returns this channel's identity rotation count.
+func (sc *SecureChannel) KeySequence() uint64 {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.keySequence
+}
+
+// verifyRetiredLocked checks signature against the peer's retired keys that
+// are still within their grace period and not blacklisted.
+func (sc *SecureChannel) verifyRetiredLocked(peerID string, hash, signature []byte) bool {
+	now := time.Now()
+	for _, retired := range sc.retiredKeys[peerID] {
+		if !now.Before(retired.until) {
+			continue
+		}
+		der, err := x509.MarshalPKIXPublicKey(retired.key)
+		if err != nil || sc.revokedKeys[keyFingerprint(der)] {
+			continue
+		}
+		if verifyDigest(retired.key, hash, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sc *SecureChannel) pruneRetiredLocked(peerID string, now time.Time) []retiredKey {
+	kept := sc.retiredKeys[peerID][:0]
+	for _, retired := range sc.retiredKeys[peerID] {
+		if now.Before(retired.until) {
+			kept = append(kept, retired)
+		}
+	}
+	return kept
+}
+
+func keyFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
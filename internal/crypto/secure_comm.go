@@ -2,10 +2,10 @@
 package crypto
 
 import (
+	stdcrypto "crypto"
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/ecdsa"
-	"crypto/elliptic"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/tls"
@@ -16,61 +16,129 @@ import (
 	"io"
 	"sync"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
 )
 
-// SecureChannel manages encrypted peer-to-peer communication
+// SecureChannel manages encrypted peer-to-peer communication. Its identity
+// and its peers' are P-256 ECDSA or Ed25519 keys; see KeyAlgorithm.
 type SecureChannel struct {
-	privateKey  *ecdsa.PrivateKey
-	publicKey   *ecdsa.PublicKey
-	peerKeys    map[string]*ecdsa.PublicKey
+	privateKey  stdcrypto.Signer
+	publicKey   stdcrypto.PublicKey
+	peerKeys    map[string]stdcrypto.PublicKey
 	sessionKeys map[string][]byte
 	mu          sync.RWMutex
 	tlsConfig   *tls.Config
+
+	// Identity rotation state. keySequence counts this channel's own
+	// rotations; the rest tracks peers' rotations.
+	keySequence   uint64
+	peerSequences map[string]uint64
+	retiredKeys   map[string][]retiredKey
+	revokedKeys   map[string]bool
+	rotationGrace time.Duration
+
+	// Peer state bounds; see SetPeerStateLimits.
+	limits       PeerStateLimits
+	peerLRU      *lruIndex
+	sessionLRU   *lruIndex
+	pinned       map[string]bool
+	evictionHook PeerEvictionHook
+	resolver     PeerKeyResolver
+	now          func() time.Time
 }
 
-// NewSecureChannel creates a new secure communication channel
+// NewSecureChannel creates a new secure communication channel with a P-256
+// ECDSA identity.
 func NewSecureChannel() (*SecureChannel, error) {
-	// Generate ECDSA key pair using P-256 curve
-	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	return NewSecureChannelWithAlgorithm(protocol.AlgorithmECDSAP256)
+}
+
+// LoadSecureChannel creates a channel with a persistent identity: the P-256
+// private key in privateKeyPEM, as a SEC 1 "EC PRIVATE KEY" or PKCS #8
+// "PRIVATE KEY" block, or an Ed25519 one as PKCS #8.
+func LoadSecureChannel(privateKeyPEM []byte) (*SecureChannel, error) {
+	privateKey, err := parsePrivateKeyPEM(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return newSecureChannel(privateKey), nil
+}
+
+// parsePrivateKeyPEM parses an identity private key from a SEC 1 or
+// PKCS #8 PEM block.
+func parsePrivateKeyPEM(privateKeyPEM []byte) (stdcrypto.Signer, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate key pair: %w", err)
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
 	}
+	return identityPrivateKey(key)
+}
 
+func newSecureChannel(privateKey stdcrypto.Signer) *SecureChannel {
 	return &SecureChannel{
 		privateKey:  privateKey,
-		publicKey:   &privateKey.PublicKey,
-		peerKeys:    make(map[string]*ecdsa.PublicKey),
+		publicKey:   privateKey.Public(),
+		peerKeys:    make(map[string]stdcrypto.PublicKey),
 		sessionKeys: make(map[string][]byte),
 		tlsConfig:   createTLSConfig(),
-	}, nil
-}
 
-// RegisterPeer registers a peer's public key for secure communication
-func (sc *SecureChannel) RegisterPeer(peerID string, publicKey *ecdsa.PublicKey) error {
-	sc.mu.Lock()
-	defer sc.mu.Unlock()
+		peerSequences: make(map[string]uint64),
+		retiredKeys:   make(map[string][]retiredKey),
+		revokedKeys:   make(map[string]bool),
+		rotationGrace: DefaultRotationGracePeriod,
 
-	if publicKey == nil {
-		return errors.New("public key cannot be nil")
+		limits: PeerStateLimits{
+			PeerKeys:    DefaultPeerKeyCapacity,
+			SessionKeys: DefaultSessionKeyCapacity,
+			IdleTTL:     DefaultPeerIdleTTL,
+		},
+		peerLRU:    newLRUIndex(),
+		sessionLRU: newLRUIndex(),
+		pinned:     make(map[string]bool),
+		now:        time.Now,
 	}
+}
 
+// RegisterPeer registers a peer's public key, a *ecdsa.PublicKey on P-256
+// or an ed25519.PublicKey, for secure communication. The peer's signatures
+// are verified by its key's algorithm from then on. Registering a peer may
+// evict the least recently used unpinned peer once the channel is at its
+// limits; see SetPeerStateLimits.
+func (sc *SecureChannel) RegisterPeer(peerID string, publicKey stdcrypto.PublicKey) error {
+	if _, err := KeyAlgorithm(publicKey); err != nil {
+		return err
+	}
+
+	sc.mu.Lock()
 	sc.peerKeys[peerID] = publicKey
+	sc.touchPeerLocked(peerID)
+	evicted := sc.enforceLimitsLocked()
+	sc.mu.Unlock()
+
+	sc.notifyEvictions(evicted)
 	return nil
 }
 
 // EncryptMessage encrypts a message for a specific peer using AES-GCM
 func (sc *SecureChannel) EncryptMessage(peerID string, plaintext []byte) ([]byte, error) {
-	sc.mu.RLock()
-	sessionKey, exists := sc.sessionKeys[peerID]
-	sc.mu.RUnlock()
-
-	if !exists {
-		// Establish session key if it doesn't exist
-		var err error
-		sessionKey, err = sc.establishSessionKey(peerID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to establish session key: %w", err)
-		}
+	// Establish session key if it doesn't exist
+	sessionKey, err := sc.sessionKey(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to establish session key: %w", err)
 	}
 
 	// Create AES-GCM cipher
@@ -95,14 +163,12 @@ func (sc *SecureChannel) EncryptMessage(peerID string, plaintext []byte) ([]byte
 	return ciphertext, nil
 }
 
-// DecryptMessage decrypts a message from a peer
+// DecryptMessage decrypts a message from a peer. A session key evicted
+// since the peer last spoke is derived again.
 func (sc *SecureChannel) DecryptMessage(peerID string, ciphertext []byte) ([]byte, error) {
-	sc.mu.RLock()
-	sessionKey, exists := sc.sessionKeys[peerID]
-	sc.mu.RUnlock()
-
-	if !exists {
-		return nil, errors.New("no session key for peer")
+	sessionKey, err := sc.sessionKey(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("no session key for peer: %w", err)
 	}
 
 	// Create AES-GCM cipher
@@ -130,49 +196,89 @@ func (sc *SecureChannel) DecryptMessage(peerID string, ciphertext []byte) ([]byt
 	return plaintext, nil
 }
 
-// SignData signs data using ECDSA for authentication
+// SignData signs data with the channel's identity key, in its Algorithm.
 func (sc *SecureChannel) SignData(data []byte) ([]byte, error) {
 	hash := sha256.Sum256(data)
-	signature, err := ecdsa.SignASN1(rand.Reader, sc.privateKey, hash[:])
+	sc.mu.RLock()
+	privateKey := sc.privateKey
+	sc.mu.RUnlock()
+	signature, err := signDigest(privateKey, hash[:])
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign data: %w", err)
 	}
 	return signature, nil
 }
 
-// VerifySignature verifies a signature from a peer
+// VerifySignature verifies a signature from a peer by the algorithm of its
+// registered key. After the peer rotates its identity, signatures by its
+// previous key verify until the grace period ends.
 func (sc *SecureChannel) VerifySignature(peerID string, data, signature []byte) error {
+	publicKey, err := sc.peerKey(peerID)
+	if err != nil {
+		return fmt.Errorf("peer public key not found: %w", err)
+	}
+
+	hash := sha256.Sum256(data)
+	if verifyDigest(publicKey, hash[:], signature) {
+		return nil
+	}
 	sc.mu.RLock()
-	publicKey, exists := sc.peerKeys[peerID]
-	sc.mu.RUnlock()
+	defer sc.mu.RUnlock()
+	if sc.verifyRetiredLocked(peerID, hash[:], signature) {
+		return nil
+	}
+	return errors.New("invalid signature")
+}
 
-	if !exists {
-		return errors.New("peer public key not found")
+// VerifySignatureAs verifies a signature that a message declares to be in
+// algorithm, failing with protocol.ErrAlgorithmMismatch before any
+// verification when that is not the algorithm of peerID's registered key.
+func (sc *SecureChannel) VerifySignatureAs(peerID string, algorithm protocol.AlgorithmID, data, signature []byte) error {
+	registered, err := sc.PeerAlgorithm(peerID)
+	if err != nil {
+		return fmt.Errorf("peer public key not found: %w", err)
 	}
+	if err := protocol.CheckAlgorithm(algorithm, registered); err != nil {
+		return fmt.Errorf("peer %s: %w", peerID, err)
+	}
+	return sc.VerifySignature(peerID, data, signature)
+}
 
+// VerifyWithPublicKey verifies a SignData signature against a PEM public
+// key, for keys held outside the channel such as a peer's registered key.
+func VerifyWithPublicKey(publicKeyPEM, data, signature []byte) error {
+	publicKey, err := ImportPublicKey(publicKeyPEM)
+	if err != nil {
+		return err
+	}
 	hash := sha256.Sum256(data)
-	valid := ecdsa.VerifyASN1(publicKey, hash[:], signature)
-	if !valid {
+	if !verifyDigest(publicKey, hash[:], signature) {
 		return errors.New("invalid signature")
 	}
-
 	return nil
 }
 
-// establishSessionKeyLocked creates a shared session key using ECDH.
+// establishSessionKeyLocked creates a shared session key using ECDH, on
+// P-256 or X25519 as the identities' algorithm has it. A peer of another
+// algorithm fails with protocol.ErrAlgorithmMismatch.
 // Caller MUST hold sc.mu write-lock.
 func (sc *SecureChannel) establishSessionKeyLocked(peerID string) ([]byte, error) {
 	peerKey, exists := sc.peerKeys[peerID]
 	if !exists {
-		return nil, errors.New("peer public key not registered")
+		return nil, fmt.Errorf("%w: %s", ErrPeerKeyUnknown, peerID)
+	}
+	local, _ := KeyAlgorithm(sc.publicKey)
+	remote, _ := KeyAlgorithm(peerKey)
+	if !protocol.SharesKeyAgreement(local, remote) {
+		return nil, fmt.Errorf("%w: no key agreement between %s and %s peer %s", protocol.ErrAlgorithmMismatch, local, remote, peerID)
 	}
 
 	// Use Go 1.20+ crypto/ecdh for proper ECDH key agreement (ScalarMult is deprecated).
-	ecdhPriv, err := sc.privateKey.ECDH()
+	ecdhPriv, err := agreementPrivateKey(sc.privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("ecdh from private key: %w", err)
 	}
-	ecdhPeer, err := peerKey.ECDH()
+	ecdhPeer, err := agreementPublicKey(peerKey)
 	if err != nil {
 		return nil, fmt.Errorf("ecdh from peer public key: %w", err)
 	}
@@ -184,6 +290,7 @@ func (sc *SecureChannel) establishSessionKeyLocked(peerID string) ([]byte, error
 	// Derive session key using SHA-256
 	sessionKey := sha256.Sum256(sharedBytes)
 	sc.sessionKeys[peerID] = sessionKey[:]
+	sc.touchPeerLocked(peerID)
 
 	return sessionKey[:], nil
 }
@@ -191,19 +298,56 @@ func (sc *SecureChannel) establishSessionKeyLocked(peerID string) ([]byte, error
 // establishSessionKey creates a shared session key using ECDH (public API, acquires lock).
 func (sc *SecureChannel) establishSessionKey(peerID string) ([]byte, error) {
 	sc.mu.Lock()
-	defer sc.mu.Unlock()
-	return sc.establishSessionKeyLocked(peerID)
+	sessionKey, err := sc.establishSessionKeyLocked(peerID)
+	evicted := sc.enforceLimitsLocked()
+	sc.mu.Unlock()
+
+	sc.notifyEvictions(evicted)
+	return sessionKey, err
 }
 
 // RotateSessionKey rotates the session key for a peer.
 func (sc *SecureChannel) RotateSessionKey(peerID string) error {
 	sc.mu.Lock()
-	defer sc.mu.Unlock()
-	delete(sc.sessionKeys, peerID)
+	sc.dropSessionLocked(peerID)
 	_, err := sc.establishSessionKeyLocked(peerID)
+	evicted := sc.enforceLimitsLocked()
+	sc.mu.Unlock()
+
+	sc.notifyEvictions(evicted)
 	return err
 }
 
+// voteMACLabel and fragmentMACLabel separate the pairwise MAC keys from
+// the session's encryption key and from each other.
+const (
+	voteMACLabel     = "sovereign-vote-mac/v1"
+	fragmentMACLabel = "sovereign-fragment-mac/v1"
+)
+
+// VoteMACKey derives the pairwise key for authenticating consensus votes
+// with peerID from the peers' session key. Both ends derive the same key,
+// and it changes whenever the session key is rotated.
+func (sc *SecureChannel) VoteMACKey(peerID string) ([]byte, error) {
+	return sc.pairwiseMACKey(peerID, voteMACLabel)
+}
+
+// FragmentMACKey derives the pairwise key for authenticating transport
+// fragments exchanged with peerID, as VoteMACKey does for votes.
+func (sc *SecureChannel) FragmentMACKey(peerID string) ([]byte, error) {
+	return sc.pairwiseMACKey(peerID, fragmentMACLabel)
+}
+
+func (sc *SecureChannel) pairwiseMACKey(peerID, label string) ([]byte, error) {
+	sessionKey, err := sc.sessionKey(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to establish session key: %w", err)
+	}
+	mac := hmac.New(sha256.New, sessionKey)
+	mac.Write([]byte(label))
+	return mac.Sum(nil), nil
+}
+
 // GetTLSConfig returns the TLS configuration for secure connections
 func (sc *SecureChannel) GetTLSConfig() *tls.Config {
 	return sc.tlsConfig
@@ -211,7 +355,10 @@ func (sc *SecureChannel) GetTLSConfig() *tls.Config {
 
 // ExportPublicKey exports the public key in PEM format
 func (sc *SecureChannel) ExportPublicKey() ([]byte, error) {
-	pubKeyBytes, err := x509.MarshalPKIXPublicKey(sc.publicKey)
+	sc.mu.RLock()
+	publicKey := sc.publicKey
+	sc.mu.RUnlock()
+	pubKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal public key: %w", err)
 	}
@@ -224,24 +371,29 @@ func (sc *SecureChannel) ExportPublicKey() ([]byte, error) {
 	return pem.EncodeToMemory(pemBlock), nil
 }
 
-// ImportPublicKey imports a public key from PEM format
-func ImportPublicKey(pemData []byte) (*ecdsa.PublicKey, error) {
+// ImportPublicKey imports an identity public key from PEM format: a
+// *ecdsa.PublicKey on P-256 or an ed25519.PublicKey, as its PKIX algorithm
+// identifier says. Any other key fails with
+// protocol.ErrUnsupportedAlgorithm.
+func ImportPublicKey(pemData []byte) (stdcrypto.PublicKey, error) {
 	block, _ := pem.Decode(pemData)
 	if block == nil {
 		return nil, errors.New("failed to decode PEM block")
 	}
 
-	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse public key: %w", err)
-	}
+	return parseIdentityPublicKey(block.Bytes)
+}
 
-	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, errors.New("not an ECDSA public key")
+// PublicKeyFingerprint returns the hex SHA-256 of a PEM public key's DER
+// encoding, the short form by which signed payloads name their signer. An
+// Ed25519 key's fingerprint is prefixed "ed25519:"; a P-256 key's is bare,
+// as it was before other algorithms. FingerprintAlgorithm reads it back.
+func PublicKeyFingerprint(publicKeyPEM []byte) (string, error) {
+	der, algorithm, err := decodePublicKeyPEM(publicKeyPEM)
+	if err != nil {
+		return "", err
 	}
-
-	return ecdsaPub, nil
+	return identityFingerprint(algorithm, der), nil
 }
 
 // createTLSConfig creates a secure TLS 1.3-only configuration.
@@ -265,6 +417,8 @@ type SecureMessage struct {
 	Timestamp   time.Time
 	Ciphertext  []byte
 	Signature   []byte
+	// Algorithm is the algorithm Signature is in, the sender's.
+	Algorithm protocol.AlgorithmID
 }
 
 // SecureModelUpdate encrypts and signs a model update
@@ -285,13 +439,14 @@ func (sc *SecureChannel) SecureModelUpdate(peerID string, modelData []byte) (*Se
 		Timestamp:   time.Now(),
 		Ciphertext:  ciphertext,
 		Signature:   signature,
+		Algorithm:   sc.Algorithm(),
 	}, nil
 }
 
 // VerifyAndDecryptMessage verifies and decrypts a secure message
 func (sc *SecureChannel) VerifyAndDecryptMessage(msg *SecureMessage) ([]byte, error) {
 	// Verify signature
-	if err := sc.VerifySignature(msg.SenderID, msg.Ciphertext, msg.Signature); err != nil {
+	if err := sc.VerifySignatureAs(msg.SenderID, msg.Algorithm, msg.Ciphertext, msg.Signature); err != nil {
 		return nil, fmt.Errorf("signature verification failed: %w", err)
 	}
 
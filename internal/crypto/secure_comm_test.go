@@ -11,6 +11,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"math/big"
 	"net"
 	"testing"
@@ -37,11 +38,39 @@ func TestExportImportPublicKey(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ImportPublicKey: %v", err)
 	}
-	if pub.X.Cmp(sc.publicKey.X) != 0 || pub.Y.Cmp(sc.publicKey.Y) != 0 {
+	if key, ok := pub.(*ecdsa.PublicKey); !ok || !key.Equal(sc.publicKey) {
 		t.Fatal("imported public key does not match original")
 	}
 }
 
+func TestLoadSecureChannelKeepsIdentity(t *testing.T) {
+	sc, _ := NewSecureChannel()
+	der, err := x509.MarshalECPrivateKey(sc.privateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %v", err)
+	}
+	loaded, err := LoadSecureChannel(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("LoadSecureChannel: %v", err)
+	}
+	original, _ := sc.ExportPublicKey()
+	reloaded, _ := loaded.ExportPublicKey()
+	want, err := PublicKeyFingerprint(original)
+	if err != nil {
+		t.Fatalf("PublicKeyFingerprint: %v", err)
+	}
+	if got, _ := PublicKeyFingerprint(reloaded); got != want {
+		t.Fatalf("reloaded fingerprint %s, want %s", got, want)
+	}
+	sig, _ := loaded.SignData([]byte("payload"))
+	if err := VerifyWithPublicKey(original, []byte("payload"), sig); err != nil {
+		t.Fatalf("signature by the loaded key: %v", err)
+	}
+	if _, err := LoadSecureChannel(original); err == nil {
+		t.Fatal("loaded a public key as an identity")
+	}
+}
+
 func TestSignAndVerify(t *testing.T) {
 	sc, _ := NewSecureChannel()
 	_ = sc.RegisterPeer("self", sc.publicKey)
@@ -152,6 +181,38 @@ func TestRotateSessionKeyReestablishesSharedSecret(t *testing.T) {
 	}
 }
 
+func TestVoteMACKeyIsPairwiseAndSeparateFromSessionKey(t *testing.T) {
+	node, peer := registerPair(t)
+	nodeKey, err := node.VoteMACKey("peer-b")
+	if err != nil {
+		t.Fatalf("VoteMACKey: %v", err)
+	}
+	peerKey, err := peer.VoteMACKey("node-a")
+	if err != nil {
+		t.Fatalf("VoteMACKey: %v", err)
+	}
+	if !bytes.Equal(nodeKey, peerKey) {
+		t.Fatal("expected both ends to derive the same vote MAC key")
+	}
+	node.mu.RLock()
+	sessionKey := node.sessionKeys["peer-b"]
+	node.mu.RUnlock()
+	if bytes.Equal(nodeKey, sessionKey) {
+		t.Fatal("vote MAC key must not equal the session key")
+	}
+	nodeFragmentKey, err := node.FragmentMACKey("peer-b")
+	if err != nil {
+		t.Fatalf("FragmentMACKey: %v", err)
+	}
+	peerFragmentKey, _ := peer.FragmentMACKey("node-a")
+	if !bytes.Equal(nodeFragmentKey, peerFragmentKey) || bytes.Equal(nodeFragmentKey, nodeKey) {
+		t.Fatal("expected a shared fragment MAC key distinct from the vote MAC key")
+	}
+	if _, err := node.VoteMACKey("stranger"); err == nil {
+		t.Fatal("expected an error for an unregistered peer")
+	}
+}
+
 // TestHandshakeVerification performs an in-memory TLS 1.3 handshake and
 // asserts the negotiated version is TLS 1.3.
 func TestHandshakeVerification(t *testing.T) {
@@ -223,3 +284,104 @@ func makeSelfSignedTLSCert(key *ecdsa.PrivateKey) (tls.Certificate, error) {
 	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
 	return tls.X509KeyPair(certPEM, keyPEM)
 }
+
+func registerPair(t *testing.T) (*SecureChannel, *SecureChannel) {
+	t.Helper()
+	node, _ := NewSecureChannel()
+	peer, _ := NewSecureChannel()
+	if err := peer.RegisterPeer("node-a", node.publicKey); err != nil {
+		t.Fatalf("RegisterPeer: %v", err)
+	}
+	if err := node.RegisterPeer("peer-b", peer.publicKey); err != nil {
+		t.Fatalf("RegisterPeer: %v", err)
+	}
+	return node, peer
+}
+
+func TestIdentityRotationMidRound(t *testing.T) {
+	node, peer := registerPair(t)
+	peer.SetRotationGracePeriod(50 * time.Millisecond)
+
+	// An update signed before the rotation is still in flight afterwards.
+	inFlight := []byte("round-7 update")
+	oldSig, _ := node.SignData(inFlight)
+
+	cert, err := node.RotateIdentity("node-a", time.Minute)
+	if err != nil {
+		t.Fatalf("RotateIdentity: %v", err)
+	}
+	if cert.Sequence != 1 || node.KeySequence() != 1 {
+		t.Fatalf("expected sequence 1, got cert=%d node=%d", cert.Sequence, node.KeySequence())
+	}
+	if err := peer.ApplyRotation(cert); err != nil {
+		t.Fatalf("ApplyRotation: %v", err)
+	}
+
+	newMsg := []byte("round-7 commit vote")
+	newSig, _ := node.SignData(newMsg)
+	if err := peer.VerifySignature("node-a", newMsg, newSig); err != nil {
+		t.Fatalf("new key signature rejected: %v", err)
+	}
+	if err := peer.VerifySignature("node-a", inFlight, oldSig); err != nil {
+		t.Fatalf("old key signature rejected during grace period: %v", err)
+	}
+
+	// Sessions re-derive from the new identity on both sides.
+	ct, err := node.EncryptMessage("peer-b", []byte("weights"))
+	if err != nil {
+		t.Fatalf("EncryptMessage: %v", err)
+	}
+	if _, err := peer.establishSessionKey("node-a"); err != nil {
+		t.Fatalf("establishSessionKey: %v", err)
+	}
+	if pt, err := peer.DecryptMessage("node-a", ct); err != nil || string(pt) != "weights" {
+		t.Fatalf("decrypt after rotation: %q, %v", pt, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := peer.VerifySignature("node-a", inFlight, oldSig); err == nil {
+		t.Fatal("expected old key to stop verifying after the grace period")
+	}
+}
+
+func TestIdentityRotationRejectsReplayAndSkips(t *testing.T) {
+	node, peer := registerPair(t)
+
+	first, _ := node.RotateIdentity("node-a", time.Minute)
+	second, _ := node.RotateIdentity("node-a", time.Minute)
+	third, _ := node.RotateIdentity("node-a", time.Minute)
+
+	if err := peer.ApplyRotation(second); !errors.Is(err, ErrRotationSequence) {
+		t.Fatalf("expected ErrRotationSequence for skipped sequence, got %v", err)
+	}
+	if err := peer.ApplyRotation(first); err != nil {
+		t.Fatalf("ApplyRotation first: %v", err)
+	}
+	if err := peer.ApplyRotation(second); err != nil {
+		t.Fatalf("ApplyRotation second: %v", err)
+	}
+
+	// An attacker holding the first certificate replays it.
+	if err := peer.ApplyRotation(first); !errors.Is(err, ErrRotationReplay) {
+		t.Fatalf("expected ErrRotationReplay, got %v", err)
+	}
+
+	// Once the current key is blacklisted, rotating away from it is refused.
+	peer.BlacklistKey(third.OldPublicKey)
+	if err := peer.ApplyRotation(third); !errors.Is(err, ErrKeyRevoked) {
+		t.Fatalf("expected ErrKeyRevoked, got %v", err)
+	}
+
+	// Substituting an attacker key into a genuine certificate breaks the
+	// old key's signature.
+	attacker, _ := NewSecureChannel()
+	attackerDER, _ := x509.MarshalPKIXPublicKey(attacker.publicKey)
+	originalKey, _ := parseIdentityPublicKey(first.OldPublicKey)
+	fresh, _ := NewSecureChannel()
+	_ = fresh.RegisterPeer("node-a", originalKey)
+	tampered := *first
+	tampered.NewPublicKey = attackerDER
+	if err := fresh.ApplyRotation(&tampered); !errors.Is(err, ErrRotationSignature) {
+		t.Fatalf("expected ErrRotationSignature for tampered certificate, got %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package evaluation
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the evaluator. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrUnknownEvaluation means no evaluation round is open for the
+	// round. Not retryable unless the round is still being issued.
+	ErrUnknownEvaluation = errors.New("unknown evaluation round")
+	// ErrNotSampled means the reporting node was not sampled for the
+	// round. Not retryable.
+	ErrNotSampled = errors.New("node not sampled for evaluation")
+	// ErrInvalidReport means a report is malformed, answers another model
+	// than the round's, or repeats the node's earlier report. Not
+	// retryable with the same report.
+	ErrInvalidReport = errors.New("invalid evaluation report")
+	// ErrNoReports means no sampled node reported a positive sample count,
+	// so there is nothing to estimate from. Not retryable for the round.
+	ErrNoReports = errors.New("no evaluation reports")
+)
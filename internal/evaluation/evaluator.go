@@ -0,0 +1,203 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package evaluation measures committed global models across the
+// federation without moving data: sampled nodes score a model locally and
+// report only aggregate metrics, which the Evaluator combines into a
+// sample-weighted global estimate with confidence intervals.
+package evaluation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Confidence is the level of the intervals the evaluator reports, and
+// confidenceZ the normal quantile that gives it.
+const (
+	Confidence  = 0.95
+	confidenceZ = 1.959963984540054
+)
+
+// Evaluator collects the reports of open evaluation rounds and records each
+// round's estimate in a model store once it completes.
+type Evaluator struct {
+	store *modeldist.ModelStore
+	now   func() time.Time
+
+	mu   sync.Mutex
+	open map[int]*openEvaluation
+}
+
+type openEvaluation struct {
+	task    protocol.EvaluationTask
+	sampled map[string]bool
+	reports map[string]protocol.EvaluationReport
+}
+
+// NewEvaluator creates an evaluator recording completed rounds in store,
+// which may be nil to only return them.
+func NewEvaluator(store *modeldist.ModelStore) *Evaluator {
+	return &Evaluator{store: store, now: time.Now, open: make(map[int]*openEvaluation)}
+}
+
+// Begin opens an evaluation round for task, taking reports from the
+// sampled nodes.
+func (e *Evaluator) Begin(task protocol.EvaluationTask, sampled []string) error {
+	if err := task.Validate(); err != nil {
+		return err
+	}
+	if len(sampled) == 0 {
+		return fmt.Errorf("evaluation of round %d samples no nodes", task.Round)
+	}
+	open := &openEvaluation{
+		task:    task,
+		sampled: make(map[string]bool, len(sampled)),
+		reports: make(map[string]protocol.EvaluationReport, len(sampled)),
+	}
+	for _, nodeID := range sampled {
+		open.sampled[nodeID] = true
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, exists := e.open[task.Round]; exists {
+		return fmt.Errorf("evaluation of round %d is already open", task.Round)
+	}
+	e.open[task.Round] = open
+	return nil
+}
+
+// Submit takes a sampled node's report for an open round.
+func (e *Evaluator) Submit(report protocol.EvaluationReport) error {
+	if err := report.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReport, err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	open, ok := e.open[report.Round]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownEvaluation, report.Round)
+	}
+	if !open.sampled[report.NodeID] {
+		return fmt.Errorf("%w: %s for round %d", ErrNotSampled, report.NodeID, report.Round)
+	}
+	if report.ModelDigest != open.task.ModelDigest {
+		return fmt.Errorf("%w: %s evaluated another model than round %d's", ErrInvalidReport, report.NodeID, report.Round)
+	}
+	if _, dup := open.reports[report.NodeID]; dup {
+		return fmt.Errorf("%w: %s already reported for round %d", ErrInvalidReport, report.NodeID, report.Round)
+	}
+	open.reports[report.NodeID] = report
+	return nil
+}
+
+// Open returns the rounds with an evaluation open, ascending.
+func (e *Evaluator) Open() []int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rounds := make([]int, 0, len(e.open))
+	for round := range e.open {
+		rounds = append(rounds, round)
+	}
+	sort.Ints(rounds)
+	return rounds
+}
+
+// Complete closes round's evaluation, combines the reports it received,
+// and records the estimate in the evaluator's model store. Nodes that did
+// not report by then are left out of the estimate.
+func (e *Evaluator) Complete(round int) (modeldist.Evaluation, error) {
+	e.mu.Lock()
+	open, ok := e.open[round]
+	delete(e.open, round)
+	e.mu.Unlock()
+	if !ok {
+		return modeldist.Evaluation{}, fmt.Errorf("%w: %d", ErrUnknownEvaluation, round)
+	}
+
+	reports := make([]protocol.EvaluationReport, 0, len(open.reports))
+	for _, report := range open.reports {
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].NodeID < reports[j].NodeID })
+	evaluation, err := Combine(open.task, reports)
+	if err != nil {
+		return modeldist.Evaluation{}, err
+	}
+	evaluation.Sampled = len(open.sampled)
+	evaluation.CompletedAt = e.now().UTC()
+	if e.store != nil {
+		if err := e.store.RecordEvaluation(evaluation); err != nil {
+			return evaluation, err
+		}
+	}
+	return evaluation, nil
+}
+
+// Combine estimates task's metrics from reports, each weighted by its
+// sample count. Reports for another model are ignored.
+//
+// The standard error treats each node's data as a cluster: it is the
+// weighted dispersion of the nodes' values around the mean, which already
+// reflects any DP noise they carry, but never less than the variance that
+// noise alone adds, so a handful of noised reports that happen to agree
+// do not claim more precision than they have.
+func Combine(task protocol.EvaluationTask, reports []protocol.EvaluationReport) (modeldist.Evaluation, error) {
+	evaluation := modeldist.Evaluation{Round: task.Round, ModelDigest: task.ModelDigest, Confidence: Confidence}
+	var counted []protocol.EvaluationReport
+	for _, report := range reports {
+		if report.ModelDigest != task.ModelDigest || report.Metrics.Samples <= 0 {
+			continue
+		}
+		counted = append(counted, report)
+		evaluation.Samples += report.Metrics.Samples
+		evaluation.Noised = evaluation.Noised || report.NoiseScale > 0
+	}
+	evaluation.Reported = len(counted)
+	if evaluation.Samples == 0 {
+		return evaluation, fmt.Errorf("%w: round %d", ErrNoReports, task.Round)
+	}
+
+	if _, ok := task.Metric(protocol.MetricLoss); ok {
+		evaluation.Loss = estimate(counted, evaluation.Samples, func(m protocol.Metrics) float64 { return m.Loss })
+	}
+	if _, ok := task.Metric(protocol.MetricAccuracy); ok {
+		evaluation.Accuracy = estimate(counted, evaluation.Samples, func(m protocol.Metrics) float64 { return m.Accuracy })
+	}
+	return evaluation, nil
+}
+
+func estimate(reports []protocol.EvaluationReport, samples int, value func(protocol.Metrics) float64) modeldist.MetricEstimate {
+	total := float64(samples)
+	mean := 0.0
+	for _, report := range reports {
+		mean += float64(report.Metrics.Samples) / total * value(report.Metrics)
+	}
+
+	dispersion, noise := 0.0, 0.0
+	for _, report := range reports {
+		w := float64(report.Metrics.Samples) / total
+		d := value(report.Metrics) - mean
+		dispersion += w * w * d * d
+		// A Laplace(b) draw has variance 2b².
+		noise += w * w * 2 * report.NoiseScale * report.NoiseScale
+	}
+	if k := float64(len(reports)); k > 1 {
+		dispersion *= k / (k - 1)
+	}
+	stdErr := math.Sqrt(max(dispersion, noise))
+	return modeldist.MetricEstimate{
+		Mean:   mean,
+		StdErr: stdErr,
+		Lower:  mean - confidenceZ*stdErr,
+		Upper:  mean + confidenceZ*stdErr,
+	}
+}
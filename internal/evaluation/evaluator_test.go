@@ -0,0 +1,102 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package evaluation
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func committedStore(t *testing.T, weights []byte) *modeldist.ModelStore {
+	t.Helper()
+	store := modeldist.NewModelStore(8)
+	cert := modeldist.CommitCertificate{Round: 1, ProposalID: "p-1", ModelDigest: protocol.WeightsDigest(weights), QuorumSize: 1, Approvals: []string{"agg"}}
+	if _, err := store.Commit(1, weights, 3, nil, cert); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	return store
+}
+
+func TestEvaluatorWeighsReportsBySampleCount(t *testing.T) {
+	weights := []byte("global-model")
+	store := committedStore(t, weights)
+	evaluator := NewEvaluator(store)
+	task := protocol.EvaluationTask{Round: 1, ModelDigest: protocol.WeightsDigest(weights), Metrics: protocol.DefaultEvaluationMetrics()}
+	if err := evaluator.Begin(task, []string{"a", "b", "c"}); err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	reports := map[string]protocol.Metrics{
+		"a": {Loss: 0.2, Accuracy: 0.9, Samples: 100},
+		"b": {Loss: 0.6, Accuracy: 0.5, Samples: 300},
+	}
+	for nodeID, metrics := range reports {
+		if err := evaluator.Submit(protocol.EvaluationReport{NodeID: nodeID, Round: 1, ModelDigest: task.ModelDigest, Metrics: metrics}); err != nil {
+			t.Fatalf("submit %s: %v", nodeID, err)
+		}
+	}
+	if err := evaluator.Submit(protocol.EvaluationReport{NodeID: "z", Round: 1, ModelDigest: task.ModelDigest, Metrics: protocol.Metrics{Samples: 1}}); !errors.Is(err, ErrNotSampled) {
+		t.Fatalf("unsampled node: expected ErrNotSampled, got %v", err)
+	}
+	if err := evaluator.Submit(protocol.EvaluationReport{NodeID: "c", Round: 1, ModelDigest: "other", Metrics: protocol.Metrics{Samples: 1}}); !errors.Is(err, ErrInvalidReport) {
+		t.Fatalf("other model: expected ErrInvalidReport, got %v", err)
+	}
+	if err := evaluator.Submit(protocol.EvaluationReport{NodeID: "a", Round: 1, ModelDigest: task.ModelDigest, Metrics: protocol.Metrics{Samples: 1}}); !errors.Is(err, ErrInvalidReport) {
+		t.Fatalf("duplicate: expected ErrInvalidReport, got %v", err)
+	}
+
+	evaluation, err := evaluator.Complete(1)
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if evaluation.Sampled != 3 || evaluation.Reported != 2 || evaluation.Samples != 400 || evaluation.Noised {
+		t.Fatalf("evaluation = %+v", evaluation)
+	}
+	if math.Abs(evaluation.Accuracy.Mean-0.6) > 1e-12 || math.Abs(evaluation.Loss.Mean-0.5) > 1e-12 {
+		t.Fatalf("estimates = loss %+v accuracy %+v", evaluation.Loss, evaluation.Accuracy)
+	}
+	if !(evaluation.Accuracy.Lower < 0.6 && evaluation.Accuracy.Upper > 0.6) {
+		t.Fatalf("interval does not bracket the mean: %+v", evaluation.Accuracy)
+	}
+	if stored, ok := store.Evaluation(1); !ok || stored.Accuracy != evaluation.Accuracy {
+		t.Fatalf("stored evaluation = %+v, %v", stored, ok)
+	}
+	if _, err := evaluator.Complete(1); !errors.Is(err, ErrUnknownEvaluation) {
+		t.Fatalf("second complete: expected ErrUnknownEvaluation, got %v", err)
+	}
+}
+
+func TestCombineWidensIntervalsForNoise(t *testing.T) {
+	task := protocol.EvaluationTask{Round: 1, ModelDigest: "m", Metrics: protocol.DefaultEvaluationMetrics()}
+	reports := []protocol.EvaluationReport{
+		{NodeID: "a", ModelDigest: "m", Metrics: protocol.Metrics{Accuracy: 0.7, Samples: 50}},
+		{NodeID: "b", ModelDigest: "m", Metrics: protocol.Metrics{Accuracy: 0.7, Samples: 50}},
+	}
+	exact, err := Combine(task, reports)
+	if err != nil {
+		t.Fatalf("combine: %v", err)
+	}
+	if exact.Accuracy.StdErr != 0 {
+		t.Fatalf("agreeing exact reports should have no spread: %+v", exact.Accuracy)
+	}
+	for i := range reports {
+		reports[i].NoiseScale = 0.5
+	}
+	noised, err := Combine(task, reports)
+	if err != nil {
+		t.Fatalf("combine: %v", err)
+	}
+	// Two equal weights of Laplace(0.5): variance 2 * 0.25 * 0.5.
+	if !noised.Noised || math.Abs(noised.Accuracy.StdErr-0.5) > 1e-12 {
+		t.Fatalf("noised estimate = %+v", noised.Accuracy)
+	}
+
+	if _, err := Combine(task, []protocol.EvaluationReport{{NodeID: "a", ModelDigest: "m"}}); !errors.Is(err, ErrNoReports) {
+		t.Fatalf("empty: expected ErrNoReports, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBuffer is the buffer of a subscription that asks for none.
+const DefaultBuffer = 256
+
+// Bus delivers published events to its subscribers. Each subscription has
+// its own buffer: Publish hands an event to every subscription with room
+// for it and counts a drop for every one without, so a slow subscriber
+// loses its own events without delaying the publisher or anyone else.
+//
+// Events are sequenced and fanned out under one lock, so every subscriber
+// receives the events it keeps in the same order, the order they were
+// published in; a component that publishes a round's stages in order has
+// them delivered in order.
+type Bus struct {
+	mu   sync.Mutex
+	seq  uint64
+	subs []*Subscription
+}
+
+// NewBus creates a bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Publish sequences event, stamps its time if unset, and offers it to
+// every subscription for its kind. It never blocks on subscribers.
+func (b *Bus) Publish(event Event) {
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	event.Seq = b.seq
+	for _, sub := range b.subs {
+		sub.offer(event)
+	}
+}
+
+// Subscribe returns a subscription receiving the kinds of events listed,
+// every kind when none are, buffering up to buffer of them (DefaultBuffer
+// when buffer is not positive). name labels it in Stats.
+func (b *Bus) Subscribe(name string, buffer int, kinds ...Kind) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	sub := &Subscription{bus: b, name: name, ch: make(chan Event, buffer)}
+	if len(kinds) > 0 {
+		sub.kinds = make(map[Kind]bool, len(kinds))
+		for _, kind := range kinds {
+			sub.kinds[kind] = true
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, sub)
+	return sub
+}
+
+// Handle subscribes like Subscribe and calls handle with each event, in
+// order, on a goroutine of the subscription's own.
+func (b *Bus) Handle(name string, buffer int, handle func(Event), kinds ...Kind) *Subscription {
+	sub := b.Subscribe(name, buffer, kinds...)
+	sub.done = make(chan struct{})
+	go func() {
+		defer close(sub.done)
+		for event := range sub.ch {
+			handle(event)
+		}
+	}()
+	return sub
+}
+
+// SubscriberStats is one subscription's delivery counts.
+type SubscriberStats struct {
+	Name      string `json:"name"`
+	Delivered uint64 `json:"delivered"`
+	Dropped   uint64 `json:"dropped"`
+	Buffered  int    `json:"buffered"`
+	Capacity  int    `json:"capacity"`
+}
+
+// Stats returns the delivery counts of the open subscriptions, oldest
+// first.
+func (b *Bus) Stats() []SubscriberStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := make([]SubscriberStats, 0, len(b.subs))
+	for _, sub := range b.subs {
+		stats = append(stats, SubscriberStats{
+			Name:      sub.name,
+			Delivered: sub.delivered.Load(),
+			Dropped:   sub.dropped.Load(),
+			Buffered:  len(sub.ch),
+			Capacity:  cap(sub.ch),
+		})
+	}
+	return stats
+}
+
+// Subscription is one subscriber's feed from a Bus.
+type Subscription struct {
+	bus   *Bus
+	name  string
+	kinds map[Kind]bool
+	ch    chan Event
+	// done closes when a Handle goroutine has handled its last event.
+	done chan struct{}
+
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	closeOnce sync.Once
+}
+
+// offer delivers event if the subscription wants it and has room. Callers
+// must hold the bus lock, which also keeps Close from closing ch under a
+// send.
+func (s *Subscription) offer(event Event) {
+	if s.kinds != nil && !s.kinds[event.Kind] {
+		return
+	}
+	select {
+	case s.ch <- event:
+		s.delivered.Add(1)
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// Name returns the subscription's label.
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// Events returns the channel events are delivered on. It is closed by
+// Close. Subscriptions made with Handle are drained by their handler.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Delivered counts the events handed to the subscription.
+func (s *Subscription) Delivered() uint64 {
+	return s.delivered.Load()
+}
+
+// Dropped counts the events the subscription lost to a full buffer.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes and closes the event channel; events already
+// buffered can still be received. For a Handle subscription it returns
+// once the handler has handled them, so it must not be called from the
+// handler itself. Closing twice is a no-op.
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		b := s.bus
+		b.mu.Lock()
+		for i, sub := range b.subs {
+			if sub == s {
+				b.subs = append(b.subs[:i], b.subs[i+1:]...)
+				break
+			}
+		}
+		close(s.ch)
+		b.mu.Unlock()
+	})
+	if s.done != nil {
+		<-s.done
+	}
+}
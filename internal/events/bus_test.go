@@ -0,0 +1,213 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// roundLifecycle is the order a committed round's events are published in.
+var roundLifecycle = []Kind{KindRoundStarted, KindUpdatesClosed, KindProposalCreated, KindQuorumReached, KindCommitted}
+
+func TestBusDeliversEachRoundInOrder(t *testing.T) {
+	bus := NewBus()
+	all := bus.Subscribe("all", 64)
+	commits := bus.Subscribe("commits", 64, KindCommitted)
+	traffic := Scope(bus, "traffic")
+
+	for round := 1; round <= 3; round++ {
+		for _, kind := range roundLifecycle {
+			traffic.Publish(Event{Kind: kind, Round: round})
+		}
+	}
+	bus.Publish(Event{Kind: KindAborted, FederationID: "energy", Round: 4, Reason: ReasonReset})
+	all.Close()
+	commits.Close()
+
+	var seq uint64
+	i := 0
+	for event := range all.Events() {
+		if event.Seq != seq+1 || event.At.IsZero() {
+			t.Fatalf("event %d = %+v after seq %d", i, event, seq)
+		}
+		seq = event.Seq
+		if i < 15 {
+			if event.Kind != roundLifecycle[i%5] || event.Round != i/5+1 || event.FederationID != "traffic" {
+				t.Fatalf("event %d = %+v", i, event)
+			}
+		} else if event.FederationID != "energy" {
+			t.Fatalf("scope overrode the event's own federation: %+v", event)
+		}
+		i++
+	}
+	if i != 16 {
+		t.Fatalf("received %d events, want 16", i)
+	}
+	var rounds []int
+	for event := range commits.Events() {
+		rounds = append(rounds, event.Round)
+	}
+	if fmt.Sprint(rounds) != "[1 2 3]" || commits.Dropped() != 0 {
+		t.Fatalf("committed rounds = %v, dropped %d", rounds, commits.Dropped())
+	}
+}
+
+func TestSlowSubscriberDropsOnlyItsOwnEvents(t *testing.T) {
+	bus := NewBus()
+	stuck := bus.Subscribe("stuck", 2)
+	taken, release := make(chan struct{}, 50), make(chan struct{})
+	var handled []int
+	slow := bus.Handle("slow", 1, func(event Event) {
+		taken <- struct{}{}
+		<-release
+		handled = append(handled, event.Round)
+	})
+	fast := bus.Subscribe("fast", 100)
+
+	start := time.Now()
+	bus.Publish(Event{Kind: KindCommitted, Round: 1})
+	<-taken
+	for round := 2; round <= 50; round++ {
+		bus.Publish(Event{Kind: KindCommitted, Round: round})
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("publishing waited on slow subscribers for %s", elapsed)
+	}
+
+	stats := bus.Stats()
+	if len(stats) != 3 || stats[0].Name != "stuck" || stats[0].Delivered != 2 || stats[0].Dropped != 48 || stats[0].Buffered != 2 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if stats[2].Delivered != 50 || stats[2].Dropped != 0 {
+		t.Fatalf("fast subscriber stats = %+v", stats[2])
+	}
+	// The slow handler holds one event and buffers one more.
+	if slow.Delivered() != 2 || slow.Dropped() != 48 {
+		t.Fatalf("slow subscriber delivered %d, dropped %d", slow.Delivered(), slow.Dropped())
+	}
+	close(release)
+	slow.Close()
+	if fmt.Sprint(handled) != "[1 2]" {
+		t.Fatalf("slow handler saw rounds %v", handled)
+	}
+
+	fast.Close()
+	round := 0
+	for event := range fast.Events() {
+		round++
+		if event.Round != round {
+			t.Fatalf("fast subscriber got round %d at position %d", event.Round, round)
+		}
+	}
+	stuck.Close()
+	stuck.Close()
+	if stats := bus.Stats(); len(stats) != 0 {
+		t.Fatalf("closed subscriptions still listed: %+v", stats)
+	}
+	bus.Publish(Event{Kind: KindCommitted, Round: 51})
+}
+
+func TestConcurrentPublishersAndSubscribers(t *testing.T) {
+	const (
+		publishers  = 10
+		subscribers = 10
+		rounds      = 50
+	)
+	bus := NewBus()
+
+	type seen struct {
+		mu     sync.Mutex
+		last   map[string]Kind
+		seq    uint64
+		events int
+		err    error
+	}
+	subs := make([]*Subscription, subscribers)
+	results := make([]*seen, subscribers)
+	for i := range subs {
+		result := &seen{last: make(map[string]Kind)}
+		results[i] = result
+		// Half the subscribers handle every event; the others see only
+		// commits and aborts, through a buffer too small to keep up.
+		var kinds []Kind
+		buffer := publishers * rounds * len(roundLifecycle)
+		if i%2 == 1 {
+			kinds = []Kind{KindCommitted, KindAborted}
+			buffer = 4
+		}
+		subs[i] = bus.Handle(fmt.Sprintf("sub-%d", i), buffer, func(event Event) {
+			result.mu.Lock()
+			defer result.mu.Unlock()
+			if event.Seq <= result.seq && result.err == nil {
+				result.err = fmt.Errorf("seq %d after %d", event.Seq, result.seq)
+			}
+			result.seq = event.Seq
+			result.events++
+			key := fmt.Sprintf("%s/%d", event.FederationID, event.Round)
+			if previous, ok := result.last[key]; ok && stage(previous) >= stage(event.Kind) && result.err == nil {
+				result.err = fmt.Errorf("%s: %s after %s", key, event.Kind, previous)
+			}
+			result.last[key] = event.Kind
+		}, kinds...)
+	}
+
+	// Subscriptions coming and going while events flow neither block
+	// publishers nor disturb the others.
+	stop := make(chan struct{})
+	churned := make(chan struct{})
+	go func() {
+		defer close(churned)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			bus.Handle("churn", 1, func(Event) {}).Close()
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for p := 0; p < publishers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			pub := Scope(bus, fmt.Sprintf("federation-%d", p))
+			for round := 1; round <= rounds; round++ {
+				for _, kind := range roundLifecycle {
+					pub.Publish(Event{Kind: kind, Round: round})
+				}
+				if round%10 == 0 {
+					bus.Stats()
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+	close(stop)
+	<-churned
+
+	for i, sub := range subs {
+		sub.Close()
+		result := results[i]
+		if result.err != nil {
+			t.Fatalf("subscriber %d: %v", i, result.err)
+		}
+		if i%2 == 0 && (result.events != publishers*rounds*len(roundLifecycle) || sub.Dropped() != 0) {
+			t.Fatalf("subscriber %d handled %d events, dropped %d", i, result.events, sub.Dropped())
+		}
+		if i%2 == 1 && uint64(result.events)+sub.Dropped() != publishers*rounds {
+			t.Fatalf("subscriber %d handled %d commits and dropped %d", i, result.events, sub.Dropped())
+		}
+	}
+}
+
+func stage(kind Kind) int {
+	for i, k := range Kinds() {
+		if k == kind {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package events carries round lifecycle events between components. The
+// scheduler, aggregator, coordinator, and rollback engine publish to a
+// Bus as a round moves through its stages; the SSE API, alerting, and
+// provenance subscribe instead of being called by the components they
+// observe.
+package events
+
+import (
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// Kind is a round lifecycle stage.
+type Kind string
+
+const (
+	// KindRoundStarted: the round opened for updates until Deadline,
+	// waiting for Updates of them.
+	KindRoundStarted Kind = "round_started"
+	// KindDeadlineExtended: the round's deadline moved to Deadline at the
+	// request of Updates of its participants.
+	KindDeadlineExtended Kind = "deadline_extended"
+	// KindUpdatesClosed: the round's Updates queued updates were taken for
+	// aggregation; later ones wait for another round.
+	KindUpdatesClosed Kind = "updates_closed"
+	// KindProposalCreated: NodeID proposed the round's aggregate as
+	// ProposalID.
+	KindProposalCreated Kind = "proposal_created"
+	// KindQuorumReached: ProposalID's Approvals first reached QuorumSize.
+	KindQuorumReached Kind = "quorum_reached"
+	// KindCommitted: ProposalID committed with Approvals of QuorumSize.
+	KindCommitted Kind = "committed"
+	// KindAborted: ProposalID closed without committing, for Reason.
+	KindAborted Kind = "aborted"
+	// KindRollbackExecuted: the model of TargetRound was restored as
+	// Round.
+	KindRollbackExecuted Kind = "rollback_executed"
+	// KindHealthChanged: the node's aggregate health moved from
+	// PreviousHealth to Health. It belongs to no round.
+	KindHealthChanged Kind = "health_changed"
+)
+
+// Kinds lists every lifecycle stage in the order a round passes through
+// them, followed by the node's health transitions.
+func Kinds() []Kind {
+	return []Kind{
+		KindRoundStarted,
+		KindDeadlineExtended,
+		KindUpdatesClosed,
+		KindProposalCreated,
+		KindQuorumReached,
+		KindCommitted,
+		KindAborted,
+		KindRollbackExecuted,
+		KindHealthChanged,
+	}
+}
+
+// Reasons an aborted event carries.
+const (
+	ReasonQuorumNotReached = reasons.QuorumNotReached
+	// ReasonInvalidSignatures: the approvals reached quorum, but too few of
+	// them carried a valid signature.
+	ReasonInvalidSignatures = reasons.InvalidSignatures
+	// ReasonReset: the coordinator was reset while the proposal was still
+	// voting.
+	ReasonReset = reasons.Reset
+)
+
+// Event is one lifecycle event of one round. Fields a kind does not use
+// are left zero; see the Kind constants.
+type Event struct {
+	// Seq is the publishing bus's sequence number. Every subscriber
+	// receives events in increasing Seq; a gap means it dropped events.
+	Seq          uint64    `json:"seq"`
+	Kind         Kind      `json:"kind"`
+	FederationID string    `json:"federation_id,omitempty"`
+	Round        int       `json:"round"`
+	Source       string    `json:"source"`
+	At           time.Time `json:"at"`
+	ProposalID   string    `json:"proposal_id,omitempty"`
+	// NodeID is the proposer, or the node that executed a rollback.
+	NodeID     string       `json:"node_id,omitempty"`
+	Deadline   time.Time    `json:"deadline,omitempty"`
+	Updates    int          `json:"updates,omitempty"`
+	Approvals  int          `json:"approvals,omitempty"`
+	QuorumSize int          `json:"quorum_size,omitempty"`
+	Reason     reasons.Code `json:"reason,omitempty"`
+	// TargetRound is the round a rollback restored.
+	TargetRound int `json:"target_round,omitempty"`
+	// QuorumAtRisk marks a committed or aborted proposal closing after
+	// quorum took too much of the round budget for too many rounds
+	// running; see consensus.QuorumRisk.
+	QuorumAtRisk bool `json:"quorum_at_risk,omitempty"`
+	// Health and PreviousHealth are the node's aggregate health status
+	// after and before a health_changed event.
+	Health         string `json:"health,omitempty"`
+	PreviousHealth string `json:"previous_health,omitempty"`
+	// Manifest is the proposal's contribution manifest on proposal events,
+	// when it has one. It is shared, not copied; subscribers must not
+	// modify it.
+	Manifest *protocol.ContributionManifest `json:"-"`
+}
+
+// Publisher receives lifecycle events. Publish never blocks on
+// subscribers, so components publish while holding their own locks; that
+// is what keeps one component's events in the order its state changed.
+type Publisher interface {
+	Publish(event Event)
+}
+
+// scoped stamps a federation ID on events published through it.
+type scoped struct {
+	pub          Publisher
+	federationID string
+}
+
+func (s scoped) Publish(event Event) {
+	if event.FederationID == "" {
+		event.FederationID = s.federationID
+	}
+	s.pub.Publish(event)
+}
+
+// Scope returns a publisher that stamps federationID on the events it
+// passes to pub, or nil when pub is nil.
+func Scope(pub Publisher, federationID string) Publisher {
+	if pub == nil {
+		return nil
+	}
+	return scoped{pub: pub, federationID: federationID}
+}
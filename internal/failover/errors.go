@@ -0,0 +1,32 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package failover
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the failover layer. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrSuperseded means a standby announced a higher epoch; this node must
+	// no longer act as primary. Not retryable.
+	ErrSuperseded = errors.New("primary superseded by failover")
+	// ErrPassive means the standby has not taken over. Retryable after
+	// takeover.
+	ErrPassive = errors.New("standby is passive")
+	// ErrReplicationGap means a replication record arrived ahead of a missing
+	// one and was not applied. Retryable once the gap is resent.
+	ErrReplicationGap = errors.New("replication sequence gap")
+	// ErrNoticeSignature means a failover notice was not signed by the
+	// trusted standby key. Not retryable.
+	ErrNoticeSignature = errors.New("failover notice signature invalid")
+	// ErrNoticeUndelivered means a standby's failover notice could not be
+	// queued for every recipient, so it has not taken over. Retryable: the
+	// next check resends the notice.
+	ErrNoticeUndelivered = errors.New("failover notice undelivered")
+)
+
+// Retryable reports whether err is a transient failover failure.
+func Retryable(err error) bool {
+	return errors.Is(err, ErrPassive) || errors.Is(err, ErrReplicationGap) || errors.Is(err, ErrNoticeUndelivered)
+}
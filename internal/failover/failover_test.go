@@ -0,0 +1,178 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package failover
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+)
+
+type discardSender struct{}
+
+func (discardSender) Send(context.Context, string, p2p.OutboundMessage) error { return nil }
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+func newTestStandby(t *testing.T, key ed25519.PrivateKey) (*Standby, *consensus.Coordinator, *island.Manager) {
+	t.Helper()
+	coordinator := consensus.NewCoordinator("standby", 4, time.Minute)
+	queue := island.NewManager(time.Hour, 16, func() bool { return true })
+	transport := p2p.NewTransport(discardSender{}, p2p.QueueConfig{})
+	t.Cleanup(func() { _ = transport.Close() })
+	standby, err := NewStandby("standby", "primary", key, StandbyConfig{HeartbeatTimeout: time.Second}, coordinator, queue, transport)
+	if err != nil {
+		t.Fatalf("new standby: %v", err)
+	}
+	return standby, coordinator, queue
+}
+
+func record(t *testing.T, r Record) []byte {
+	t.Helper()
+	payload, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("encode record: %v", err)
+	}
+	return payload
+}
+
+func TestStandbyAppliesRecordsInOrderAndRejectsGaps(t *testing.T) {
+	standby, coordinator, queue := newTestStandby(t, newKey(t))
+
+	commit := consensus.ReplayEntry{Round: 1, ProposalID: "p-1", WeightsDigest: "d-1"}
+	if err := standby.HandleMessage(TopicReplication, record(t, Record{Sequence: 1, Epoch: 1, Kind: RecordCommit, Commit: &commit})); err != nil {
+		t.Fatalf("apply commit: %v", err)
+	}
+	update := island.Update{Round: 2, PeerID: "member-1", ModelDelta: []byte("delta")}
+	if err := standby.HandleMessage(TopicReplication, record(t, Record{Sequence: 3, Epoch: 1, Kind: RecordUpdate, Update: &update})); !errors.Is(err, ErrReplicationGap) {
+		t.Fatalf("expected ErrReplicationGap, got %v", err)
+	}
+	if err := standby.HandleMessage(TopicReplication, record(t, Record{Sequence: 1, Epoch: 1, Kind: RecordCommit, Commit: &commit})); err != nil {
+		t.Fatalf("expected a resent record to be ignored, got %v", err)
+	}
+	if err := standby.HandleMessage(TopicReplication, record(t, Record{Sequence: 2, Epoch: 1, Kind: RecordUpdate, Update: &update})); err != nil {
+		t.Fatalf("apply update: %v", err)
+	}
+	if standby.AppliedSequence() != 2 || len(queue.GetCachedUpdates()) != 1 {
+		t.Fatalf("expected two records applied, got sequence %d and %d queued", standby.AppliedSequence(), len(queue.GetCachedUpdates()))
+	}
+
+	if _, err := coordinator.ProposeModel(context.Background(), &consensus.ModelProposal{Round: 2, Weights: []byte("w"), ProposerID: "standby"}); !errors.Is(err, consensus.ErrNotLeader) {
+		t.Fatalf("expected a passive standby to refuse proposals, got %v", err)
+	}
+	if notice, err := standby.Check(time.Now()); notice != nil || err != nil {
+		t.Fatalf("expected no takeover while heartbeats are fresh, got %+v, %v", notice, err)
+	}
+
+	notice, err := standby.Check(time.Now().Add(2 * time.Second))
+	if err != nil || notice == nil {
+		t.Fatalf("expected takeover, got %+v, %v", notice, err)
+	}
+	if notice.Epoch != 2 || notice.LastSequence != 2 || notice.LastRound != 1 || standby.NextRound() != 2 {
+		t.Fatalf("unexpected notice %+v, next round %d", notice, standby.NextRound())
+	}
+	if len(coordinator.ReplayLog()) != 1 {
+		t.Fatal("expected replicated commits restored into the coordinator")
+	}
+}
+
+func TestStandbyStaysPassiveUntilItsNoticeIsQueued(t *testing.T) {
+	standby, coordinator, _ := newTestStandby(t, newKey(t))
+	commit := consensus.ReplayEntry{Round: 1, ProposalID: "p-1", WeightsDigest: "d-1"}
+	if err := standby.HandleMessage(TopicReplication, record(t, Record{Sequence: 1, Epoch: 1, Kind: RecordCommit, Commit: &commit})); err != nil {
+		t.Fatalf("apply commit: %v", err)
+	}
+
+	_ = standby.transport.Close()
+	if notice, err := standby.Check(time.Now().Add(2 * time.Second)); notice != nil || !errors.Is(err, ErrNoticeUndelivered) || !Retryable(err) {
+		t.Fatalf("takeover over a closed transport = %+v, %v", notice, err)
+	}
+	if standby.Active() || !errors.Is(standby.Gate(), ErrPassive) {
+		t.Fatal("expected the standby to stay passive while its notice is undelivered")
+	}
+
+	// A fresh heartbeat does not call the takeover off, and the retry
+	// announces the same notice without restoring the commits again.
+	heartbeat, _ := json.Marshal(Heartbeat{PrimaryID: "primary", Epoch: 1, Sequence: 1})
+	if err := standby.HandleMessage(TopicHeartbeat, heartbeat); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	transport := p2p.NewTransport(discardSender{}, p2p.QueueConfig{})
+	t.Cleanup(func() { _ = transport.Close() })
+	standby.transport = transport
+	notice, err := standby.Check(time.Now())
+	if err != nil || notice == nil || notice.Epoch != 2 || !standby.Active() {
+		t.Fatalf("retried takeover = %+v, %v", notice, err)
+	}
+	if len(coordinator.ReplayLog()) != 1 {
+		t.Fatalf("replay log = %v", coordinator.ReplayLog())
+	}
+}
+
+func TestPrimaryHonorsOnlyTrustedHigherEpochNotices(t *testing.T) {
+	key := newKey(t)
+	coordinator := consensus.NewCoordinator("primary", 4, time.Minute)
+	transport := p2p.NewTransport(discardSender{}, p2p.QueueConfig{})
+	defer func() { _ = transport.Close() }()
+	primary := NewPrimary("primary", 3, coordinator, transport, "standby")
+	primary.SetTrustedKey(key.Public().(ed25519.PublicKey))
+
+	send := func(notice FailoverNotice, signer ed25519.PrivateKey) error {
+		if err := notice.Sign(signer); err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		payload, _ := json.Marshal(notice)
+		return primary.HandleMessage(TopicNotice, payload)
+	}
+
+	if err := send(FailoverNotice{NodeID: "standby", Epoch: 4}, newKey(t)); !errors.Is(err, ErrNoticeSignature) {
+		t.Fatalf("expected ErrNoticeSignature, got %v", err)
+	}
+	if err := send(FailoverNotice{NodeID: "standby", Epoch: 3}, key); err != nil || primary.Gate() != nil {
+		t.Fatalf("expected a same-epoch notice to be ignored, got %v, gate %v", err, primary.Gate())
+	}
+	if err := send(FailoverNotice{NodeID: "standby", Epoch: 4}, key); err != nil {
+		t.Fatalf("handle notice: %v", err)
+	}
+	if !errors.Is(primary.Gate(), ErrSuperseded) || !errors.Is(primary.Heartbeat(), ErrSuperseded) {
+		t.Fatal("expected the primary to be fenced")
+	}
+	if _, err := coordinator.ProposeModel(context.Background(), &consensus.ModelProposal{Round: 1, Weights: []byte("w"), ProposerID: "primary"}); !errors.Is(err, consensus.ErrNotLeader) {
+		t.Fatalf("expected a fenced primary to refuse proposals, got %v", err)
+	}
+}
+
+func TestPrimaryAdvancesSequenceOnlyAfterASend(t *testing.T) {
+	coordinator := consensus.NewCoordinator("primary", 4, time.Minute)
+	transport := p2p.NewTransport(discardSender{}, p2p.QueueConfig{})
+	primary := NewPrimary("primary", 1, coordinator, transport, "standby")
+	if err := primary.ReplicateUpdate(island.Update{Round: 1, PeerID: "member-1"}); err != nil {
+		t.Fatalf("replicate: %v", err)
+	}
+	if err := transport.Close(); err != nil {
+		t.Fatalf("close transport: %v", err)
+	}
+	if err := primary.ReplicateUpdate(island.Update{Round: 1, PeerID: "member-2"}); !errors.Is(err, p2p.ErrTransportClosed) {
+		t.Fatalf("expected ErrTransportClosed, got %v", err)
+	}
+	// The unsent record's number is reused, so standbys see no gap.
+	if primary.sequence != 1 {
+		t.Fatalf("sequence = %d after one sent record, want 1", primary.sequence)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package failover keeps a warm standby for a regional aggregator. The
+// primary streams heartbeats and its consensus replay log and ingestion
+// queue to the standby over the transport; the standby takes over with a
+// signed FailoverNotice when heartbeats stop, and the notice fences the old
+// primary.
+package failover
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+)
+
+// Transport topics used by failover.
+const (
+	TopicHeartbeat   = "failover/heartbeat"
+	TopicReplication = "failover/replication"
+	TopicNotice      = "failover/notice"
+)
+
+// Heartbeat tells the standby the primary is alive and how far its
+// replication stream has advanced.
+type Heartbeat struct {
+	PrimaryID string    `json:"primary_id"`
+	Epoch     uint64    `json:"epoch"`
+	Sequence  uint64    `json:"sequence"`
+	SentAt    time.Time `json:"sent_at"`
+}
+
+// Record kinds carried on the replication stream.
+const (
+	RecordCommit = "commit"
+	RecordUpdate = "update"
+)
+
+// Record is one replicated change. Sequence numbers start at 1 and are
+// contiguous per primary epoch.
+type Record struct {
+	Sequence uint64                 `json:"sequence"`
+	Epoch    uint64                 `json:"epoch"`
+	Kind     string                 `json:"kind"`
+	Commit   *consensus.ReplayEntry `json:"commit,omitempty"`
+	Update   *island.Update         `json:"update,omitempty"`
+}
+
+// FailoverNotice announces that NodeID has taken over from PreviousPrimary
+// at Epoch, having applied the replication stream through LastSequence.
+type FailoverNotice struct {
+	NodeID          string            `json:"node_id"`
+	PreviousPrimary string            `json:"previous_primary"`
+	Epoch           uint64            `json:"epoch"`
+	LastSequence    uint64            `json:"last_sequence"`
+	LastRound       int               `json:"last_round"`
+	IssuedAt        time.Time         `json:"issued_at"`
+	SignerKey       ed25519.PublicKey `json:"signer_key"`
+	Signature       []byte            `json:"signature,omitempty"`
+}
+
+// Sign sets SignerKey and signs the notice with key.
+func (n *FailoverNotice) Sign(key ed25519.PrivateKey) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid failover signing key: need %d bytes, got %d", ed25519.PrivateKeySize, len(key))
+	}
+	n.SignerKey = append([]byte(nil), key.Public().(ed25519.PublicKey)...)
+	payload, err := n.signingPayload()
+	if err != nil {
+		return err
+	}
+	n.Signature = ed25519.Sign(key, payload)
+	return nil
+}
+
+// Verify checks that the notice was signed by trusted.
+func (n *FailoverNotice) Verify(trusted ed25519.PublicKey) error {
+	if len(trusted) != ed25519.PublicKeySize || !bytes.Equal(trusted, n.SignerKey) {
+		return fmt.Errorf("%w: signed by untrusted key", ErrNoticeSignature)
+	}
+	payload, err := n.signingPayload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(trusted, payload, n.Signature) {
+		return fmt.Errorf("%w: signature does not match contents", ErrNoticeSignature)
+	}
+	return nil
+}
+
+// signingPayload is the canonical JSON of the notice without its signature.
+func (n *FailoverNotice) signingPayload() ([]byte, error) {
+	unsigned := *n
+	unsigned.Signature = nil
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encode failover notice: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package failover
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+)
+
+// Primary replicates an active aggregator's state to its standbys and fences
+// the aggregator's coordinator once a standby takes over.
+type Primary struct {
+	mu          sync.Mutex
+	nodeID      string
+	epoch       uint64
+	sequence    uint64
+	coordinator *consensus.Coordinator
+	transport   *p2p.Transport
+	standbys    []string
+	trusted     ed25519.PublicKey
+	lastCommit  string
+	fencedBy    *FailoverNotice
+}
+
+// NewPrimary replicates coordinator's commits to standbys at epoch and
+// installs a leader gate on coordinator that refuses proposals and commits
+// once a standby has superseded it.
+func NewPrimary(nodeID string, epoch uint64, coordinator *consensus.Coordinator, transport *p2p.Transport, standbys ...string) *Primary {
+	p := &Primary{
+		nodeID:      nodeID,
+		epoch:       epoch,
+		coordinator: coordinator,
+		transport:   transport,
+		standbys:    append([]string(nil), standbys...),
+	}
+	coordinator.SetLeaderGate(p.Gate)
+	return p
+}
+
+// SetTrustedKey sets the standby key whose failover notices are honored.
+func (p *Primary) SetTrustedKey(key ed25519.PublicKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.trusted = append([]byte(nil), key...)
+}
+
+// Epoch returns the epoch this primary leads.
+func (p *Primary) Epoch() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.epoch
+}
+
+// Gate returns ErrSuperseded once a valid notice with a higher epoch has
+// been seen.
+func (p *Primary) Gate() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.gateLocked()
+}
+
+func (p *Primary) gateLocked() error {
+	if p.fencedBy != nil {
+		return fmt.Errorf("%w: %s took over at epoch %d", ErrSuperseded, p.fencedBy.NodeID, p.fencedBy.Epoch)
+	}
+	return nil
+}
+
+// Heartbeat sends a heartbeat to every standby.
+func (p *Primary) Heartbeat() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.gateLocked(); err != nil {
+		return err
+	}
+	return p.sendLocked(TopicHeartbeat, p2p.PriorityStatus, Heartbeat{
+		PrimaryID: p.nodeID,
+		Epoch:     p.epoch,
+		Sequence:  p.sequence,
+		SentAt:    time.Now().UTC(),
+	})
+}
+
+// ReplicateCommits sends every replay log entry committed since the last
+// call. Call it after each CommitModel.
+func (p *Primary) ReplicateCommits() error {
+	entries := p.coordinator.ReplayLog()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.gateLocked(); err != nil {
+		return err
+	}
+
+	start := 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].ProposalID == p.lastCommit {
+			start = i + 1
+			break
+		}
+	}
+	for i := start; i < len(entries); i++ {
+		entry := entries[i]
+		if err := p.appendLocked(Record{Kind: RecordCommit, Commit: &entry}); err != nil {
+			return err
+		}
+		p.lastCommit = entry.ProposalID
+	}
+	return nil
+}
+
+// ReplicateUpdate sends an update accepted into the ingestion queue.
+func (p *Primary) ReplicateUpdate(update island.Update) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.gateLocked(); err != nil {
+		return err
+	}
+	return p.appendLocked(Record{Kind: RecordUpdate, Update: &update})
+}
+
+// HandleMessage processes a message received from a standby. Notices with a
+// valid signature and a higher epoch fence this primary.
+func (p *Primary) HandleMessage(topic string, payload []byte) error {
+	if topic != TopicNotice {
+		return nil
+	}
+	var notice FailoverNotice
+	if err := json.Unmarshal(payload, &notice); err != nil {
+		return fmt.Errorf("decode failover notice: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := notice.Verify(p.trusted); err != nil {
+		return err
+	}
+	if notice.Epoch <= p.epoch || p.fencedBy != nil && notice.Epoch <= p.fencedBy.Epoch {
+		return nil
+	}
+	p.fencedBy = &notice
+	return nil
+}
+
+// appendLocked sends record as the next in sequence. The sequence advances
+// only once the record is queued for every standby, so a failed send is
+// retried under the same number rather than leaving standbys a gap.
+func (p *Primary) appendLocked(record Record) error {
+	record.Sequence = p.sequence + 1
+	record.Epoch = p.epoch
+	if err := p.sendLocked(TopicReplication, p2p.PriorityCommit, record); err != nil {
+		return err
+	}
+	p.sequence = record.Sequence
+	return nil
+}
+
+func (p *Primary) sendLocked(topic string, priority p2p.Priority, message interface{}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", topic, err)
+	}
+	_, err = p.transport.Broadcast(p.standbys, p2p.OutboundMessage{Topic: topic, Payload: payload, Priority: priority})
+	return err
+}
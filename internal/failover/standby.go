@@ -0,0 +1,289 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package failover
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+)
+
+// DefaultHeartbeatTimeout is how long a standby waits without a heartbeat
+// before taking over.
+const DefaultHeartbeatTimeout = 3 * time.Second
+
+// StandbyConfig configures takeover.
+type StandbyConfig struct {
+	// HeartbeatTimeout is how long the primary may stay silent before the
+	// standby takes over. Defaults to DefaultHeartbeatTimeout.
+	HeartbeatTimeout time.Duration
+	// Announce lists peers, besides the primary, that receive the
+	// FailoverNotice: typically the shard's members.
+	Announce []string
+}
+
+// Standby tails a primary's replication stream and takes over when the
+// primary stops sending heartbeats. Until then its coordinator refuses to
+// propose or commit.
+type Standby struct {
+	mu            sync.Mutex
+	nodeID        string
+	primaryID     string
+	cfg           StandbyConfig
+	key           ed25519.PrivateKey
+	coordinator   *consensus.Coordinator
+	queue         *island.Manager
+	transport     *p2p.Transport
+	epoch         uint64
+	applied       uint64
+	primarySeq    uint64
+	lastHeartbeat time.Time
+	replay        []consensus.ReplayEntry
+	committed     map[string]bool
+	// announcing is the signed notice of a takeover not yet announced to
+	// every recipient; notice is set once it has been.
+	announcing *FailoverNotice
+	notice     *FailoverNotice
+}
+
+// NewStandby creates a passive standby for primaryID. Replicated commits
+// are restored into coordinator on takeover; replicated updates go straight
+// into queue, which may be nil on a standby whose primary replicates none.
+// key signs the FailoverNotice.
+func NewStandby(nodeID, primaryID string, key ed25519.PrivateKey, cfg StandbyConfig, coordinator *consensus.Coordinator, queue *island.Manager, transport *p2p.Transport) (*Standby, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid failover signing key: need %d bytes, got %d", ed25519.PrivateKeySize, len(key))
+	}
+	if cfg.HeartbeatTimeout <= 0 {
+		cfg.HeartbeatTimeout = DefaultHeartbeatTimeout
+	}
+	s := &Standby{
+		nodeID:        nodeID,
+		primaryID:     primaryID,
+		cfg:           cfg,
+		key:           key,
+		coordinator:   coordinator,
+		queue:         queue,
+		transport:     transport,
+		lastHeartbeat: time.Now(),
+		committed:     make(map[string]bool),
+	}
+	coordinator.SetLeaderGate(s.Gate)
+	return s, nil
+}
+
+// Gate returns ErrPassive until the standby has taken over.
+func (s *Standby) Gate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.notice == nil {
+		return fmt.Errorf("%w: %s is primary", ErrPassive, s.primaryID)
+	}
+	return nil
+}
+
+// HandleMessage applies a heartbeat or replication record from the primary.
+// Records already applied are ignored; a record past a gap is rejected with
+// ErrReplicationGap.
+func (s *Standby) HandleMessage(topic string, payload []byte) error {
+	switch topic {
+	case TopicHeartbeat:
+		var heartbeat Heartbeat
+		if err := json.Unmarshal(payload, &heartbeat); err != nil {
+			return fmt.Errorf("decode heartbeat: %w", err)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if heartbeat.PrimaryID != s.primaryID || s.announcing != nil {
+			return nil
+		}
+		s.lastHeartbeat = time.Now()
+		s.observeEpochLocked(heartbeat.Epoch)
+		if heartbeat.Sequence > s.primarySeq {
+			s.primarySeq = heartbeat.Sequence
+		}
+		return nil
+	case TopicReplication:
+		var record Record
+		if err := json.Unmarshal(payload, &record); err != nil {
+			return fmt.Errorf("decode replication record: %w", err)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.applyLocked(record)
+	default:
+		return nil
+	}
+}
+
+func (s *Standby) observeEpochLocked(epoch uint64) {
+	if epoch > s.epoch {
+		s.epoch = epoch
+	}
+}
+
+func (s *Standby) applyLocked(record Record) error {
+	if s.announcing != nil || record.Sequence <= s.applied {
+		return nil
+	}
+	if record.Sequence != s.applied+1 {
+		return fmt.Errorf("%w: got %d after %d", ErrReplicationGap, record.Sequence, s.applied)
+	}
+
+	switch record.Kind {
+	case RecordCommit:
+		if record.Commit == nil {
+			return fmt.Errorf("replication record %d has no commit", record.Sequence)
+		}
+		if !s.committed[record.Commit.ProposalID] {
+			s.committed[record.Commit.ProposalID] = true
+			s.replay = append(s.replay, *record.Commit)
+		}
+	case RecordUpdate:
+		if record.Update == nil {
+			return fmt.Errorf("replication record %d has no update", record.Sequence)
+		}
+		if s.queue == nil {
+			return fmt.Errorf("replication record %d: standby has no ingestion queue", record.Sequence)
+		}
+		if err := s.queue.CacheUpdate(*record.Update); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("replication record %d has unknown kind %q", record.Sequence, record.Kind)
+	}
+	s.applied = record.Sequence
+	s.observeEpochLocked(record.Epoch)
+	if s.applied > s.primarySeq {
+		s.primarySeq = s.applied
+	}
+	return nil
+}
+
+// Check takes over if the primary has been silent longer than the heartbeat
+// timeout at now. It returns the notice once the standby is active, or nil
+// while the primary is healthy. A takeover whose notice could not be
+// queued for every recipient fails with ErrNoticeUndelivered and stays
+// passive; the next Check resends the same notice.
+func (s *Standby) Check(now time.Time) (*FailoverNotice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.notice != nil {
+		notice := *s.notice
+		return &notice, nil
+	}
+	if s.announcing == nil && now.Sub(s.lastHeartbeat) <= s.cfg.HeartbeatTimeout {
+		return nil, nil
+	}
+	return s.takeOverLocked(now)
+}
+
+func (s *Standby) takeOverLocked(now time.Time) (*FailoverNotice, error) {
+	notice := s.announcing
+	if notice == nil {
+		if err := s.coordinator.RestoreReplayLog(s.replay); err != nil {
+			return nil, fmt.Errorf("restore replicated commits: %w", err)
+		}
+		notice = &FailoverNotice{
+			NodeID:          s.nodeID,
+			PreviousPrimary: s.primaryID,
+			Epoch:           s.epoch + 1,
+			LastSequence:    s.applied,
+			LastRound:       s.lastRoundLocked(),
+			IssuedAt:        now.UTC(),
+		}
+		if err := notice.Sign(s.key); err != nil {
+			return nil, err
+		}
+		s.announcing = notice
+	}
+	payload, err := json.Marshal(notice)
+	if err != nil {
+		return nil, fmt.Errorf("encode failover notice: %w", err)
+	}
+
+	// The standby stays passive until the old primary and the announced
+	// peers all have the notice queued, so the primary never misses the
+	// epoch that supersedes it.
+	recipients := append([]string{s.primaryID}, s.cfg.Announce...)
+	shed, err := s.transport.Broadcast(recipients, p2p.OutboundMessage{Topic: TopicNotice, Payload: payload, Priority: p2p.PriorityCommit})
+	if err != nil {
+		return nil, fmt.Errorf("%w: epoch %d: %w", ErrNoticeUndelivered, notice.Epoch, err)
+	}
+	if shed > 0 {
+		return nil, fmt.Errorf("%w: epoch %d: %d of %d recipients shed a queued message", ErrNoticeUndelivered, notice.Epoch, shed, len(recipients))
+	}
+	s.notice = notice
+	s.epoch = notice.Epoch
+	if s.applied < s.primarySeq {
+		log.Printf("failover: %s took over from %s at epoch %d with %d of %d records replicated", s.nodeID, s.primaryID, notice.Epoch, s.applied, s.primarySeq)
+	} else {
+		log.Printf("failover: %s took over from %s at epoch %d through record %d", s.nodeID, s.primaryID, notice.Epoch, s.applied)
+	}
+	result := *notice
+	return &result, nil
+}
+
+func (s *Standby) lastRoundLocked() int {
+	last := 0
+	for _, entry := range s.replay {
+		if entry.Round > last {
+			last = entry.Round
+		}
+	}
+	return last
+}
+
+// Run checks for a silent primary every interval until ctx is done or the
+// standby takes over.
+func (s *Standby) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			notice, err := s.Check(now)
+			if err != nil {
+				log.Printf("failover: takeover failed: %v", err)
+				continue
+			}
+			if notice != nil {
+				return
+			}
+		}
+	}
+}
+
+// Active reports whether the standby has taken over.
+func (s *Standby) Active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notice != nil
+}
+
+// AppliedSequence returns the last replication record applied.
+func (s *Standby) AppliedSequence() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.applied
+}
+
+// NextRound returns the round a new leader should run: one past the last
+// replicated commit, so a round the primary committed is never committed
+// again.
+func (s *Standby) NextRound() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRoundLocked() + 1
+}
@@ -0,0 +1,21 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package faultmodel
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by fault model checks. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrUnknownModel means a fault model name is neither classic33 nor
+	// hierarchical55. Not retryable.
+	ErrUnknownModel = errors.New("unknown fault model")
+	// ErrUnsafeTopology means the deployment lacks what the fault model's
+	// theorem assumes, such as Hierarchical55 without a multi-tier
+	// topology. Not retryable until the configuration changes.
+	ErrUnsafeTopology = errors.New("fault model unsafe for topology")
+	// ErrThresholdExceeded means more nodes are faulty than the model
+	// tolerates. Not retryable.
+	ErrThresholdExceeded = errors.New("byzantine threshold exceeded")
+)
@@ -0,0 +1,142 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package faultmodel names the Byzantine fault models the system can run
+// under and derives every threshold from the chosen one: consensus
+// quorums, shard admission, and the aggregator's safety check. The two
+// models are not interchangeable; a deployment picks one.
+package faultmodel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/hva"
+)
+
+// Model is a Byzantine fault model. The zero value is Classic33.
+type Model string
+
+const (
+	// Classic33 is flat Byzantine agreement: n >= 3f+1 nodes tolerate f
+	// faulty ones, and any two quorums of ⌊2n/3⌋+1 share an honest node
+	// (Lamport, Shostak and Pease; the bound PBFT-style voting relies on).
+	Classic33 Model = "classic33"
+	// Hierarchical55 is Theorem 1 (/proofs/bft_resilience.md): with
+	// Multi-Krum filtering at every tier of a hierarchical topology, the
+	// global model stays safe while at least 5/9 (55.5%) of nodes are
+	// honest. A quorum is ⌈5n/9⌉, the fewest nodes that can all be honest;
+	// flat voting at that quorum is unsafe, so the model requires a
+	// multi-tier topology.
+	Hierarchical55 Model = "hierarchical55"
+)
+
+// Topology is what a fault model is validated against.
+type Topology struct {
+	// MultiTier is set when updates pass through at least one
+	// intermediate aggregation tier between the edge and the global model.
+	MultiTier bool
+}
+
+// TopologyOf describes an aggregation plan: it is multi-tier when it has a
+// level between the edge and the global aggregator.
+func TopologyOf(plan hva.Plan) Topology {
+	return Topology{MultiTier: len(plan.Levels) > 2}
+}
+
+// Parse reads a model name; empty means Classic33.
+func Parse(name string) (Model, error) {
+	switch Model(strings.ToLower(strings.TrimSpace(name))) {
+	case "", Classic33:
+		return Classic33, nil
+	case Hierarchical55:
+		return Hierarchical55, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownModel, name)
+	}
+}
+
+func (m Model) resolved() Model {
+	if m == "" {
+		return Classic33
+	}
+	return m
+}
+
+// String returns the model's name.
+func (m Model) String() string {
+	return string(m.resolved())
+}
+
+// Theorem names the result the model's thresholds implement.
+func (m Model) Theorem() string {
+	switch m.resolved() {
+	case Classic33:
+		return "classic BFT: n >= 3f+1"
+	case Hierarchical55:
+		return "Theorem 1: hierarchical Multi-Krum with >= 5/9 honest (/proofs/bft_resilience.md)"
+	default:
+		return "unknown"
+	}
+}
+
+// Validate refuses a model that is unknown or unsafe under topology.
+func (m Model) Validate(topology Topology) error {
+	switch m.resolved() {
+	case Classic33:
+		return nil
+	case Hierarchical55:
+		if !topology.MultiTier {
+			return fmt.Errorf("%w: %s needs a multi-tier topology", ErrUnsafeTopology, Hierarchical55)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownModel, string(m))
+	}
+}
+
+// MaxFaulty is the most faulty nodes out of n the model tolerates.
+func (m Model) MaxFaulty(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	if m.resolved() == Hierarchical55 {
+		return hva.MaximumByzantineNodes(n)
+	}
+	return (n - 1) / 3
+}
+
+// MinHonest is the fewest honest nodes out of n the model needs.
+func (m Model) MinHonest(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return n - m.MaxFaulty(n)
+}
+
+// Quorum is how many approving votes out of n commit a proposal. It is
+// never below one.
+func (m Model) Quorum(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	if m.resolved() == Hierarchical55 {
+		return hva.MinimumHonestNodes(n)
+	}
+	return 2*n/3 + 1
+}
+
+// CheckFaulty fails with ErrThresholdExceeded when faulty of n nodes is
+// more than the model tolerates.
+func (m Model) CheckFaulty(n, faulty int) error {
+	if n <= 0 {
+		return fmt.Errorf("%w: no nodes", ErrThresholdExceeded)
+	}
+	if faulty < 0 || faulty > n {
+		return fmt.Errorf("%w: faulty count %d out of range for %d nodes", ErrThresholdExceeded, faulty, n)
+	}
+	if limit := m.MaxFaulty(n); faulty > limit {
+		return fmt.Errorf("%w: %s tolerates at most %d of %d nodes faulty, got %d (%s)", ErrThresholdExceeded, m, limit, n, faulty, m.Theorem())
+	}
+	return nil
+}
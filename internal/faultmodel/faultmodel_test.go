@@ -0,0 +1,82 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package faultmodel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/hva"
+)
+
+func TestThresholdsPerModel(t *testing.T) {
+	cases := []struct {
+		model             Model
+		n, quorum, faulty int
+	}{
+		{Classic33, 1, 1, 0},
+		{Classic33, 4, 3, 1},
+		{Classic33, 9, 7, 2},
+		{Classic33, 10, 7, 3},
+		{Classic33, 100, 67, 33},
+		{"", 10, 7, 3},
+		{Hierarchical55, 1, 1, 0},
+		{Hierarchical55, 4, 3, 1},
+		{Hierarchical55, 9, 5, 4},
+		{Hierarchical55, 10, 6, 4},
+		{Hierarchical55, 100, 56, 44},
+	}
+	for _, tc := range cases {
+		if got := tc.model.Quorum(tc.n); got != tc.quorum {
+			t.Errorf("%s quorum of %d = %d, want %d", tc.model, tc.n, got, tc.quorum)
+		}
+		if got := tc.model.MaxFaulty(tc.n); got != tc.faulty {
+			t.Errorf("%s max faulty of %d = %d, want %d", tc.model, tc.n, got, tc.faulty)
+		}
+		if err := tc.model.CheckFaulty(tc.n, tc.faulty); err != nil {
+			t.Errorf("%s with %d of %d faulty: %v", tc.model, tc.faulty, tc.n, err)
+		}
+		if err := tc.model.CheckFaulty(tc.n, tc.faulty+1); !errors.Is(err, ErrThresholdExceeded) {
+			t.Errorf("%s with %d of %d faulty: expected ErrThresholdExceeded, got %v", tc.model, tc.faulty+1, tc.n, err)
+		}
+	}
+}
+
+func TestHierarchicalRequiresMultiTierTopology(t *testing.T) {
+	if err := Hierarchical55.Validate(Topology{}); !errors.Is(err, ErrUnsafeTopology) {
+		t.Fatalf("flat topology: expected ErrUnsafeTopology, got %v", err)
+	}
+	if err := Classic33.Validate(Topology{}); err != nil {
+		t.Fatalf("classic on a flat topology: %v", err)
+	}
+
+	flat, err := hva.BuildPlan(2, 8)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	tiered, err := hva.BuildPlan(1000, 8)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if err := Hierarchical55.Validate(TopologyOf(flat)); !errors.Is(err, ErrUnsafeTopology) {
+		t.Fatalf("two-level plan: expected ErrUnsafeTopology, got %v", err)
+	}
+	if err := Hierarchical55.Validate(TopologyOf(tiered)); err != nil {
+		t.Fatalf("multi-tier plan: %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for name, want := range map[string]Model{"": Classic33, "classic33": Classic33, " Hierarchical55 ": Hierarchical55} {
+		if got, err := Parse(name); err != nil || got != want {
+			t.Errorf("Parse(%q) = %s, %v; want %s", name, got, err, want)
+		}
+	}
+	if _, err := Parse("majority"); !errors.Is(err, ErrUnknownModel) {
+		t.Fatalf("expected ErrUnknownModel, got %v", err)
+	}
+	if err := Model("majority").Validate(Topology{MultiTier: true}); !errors.Is(err, ErrUnknownModel) {
+		t.Fatalf("expected ErrUnknownModel, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package federation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// checkpointSuffix names the checkpoint file of each round's batch.
+const checkpointSuffix = ".ckpt"
+
+// roundBatch is a round's queued updates: the accumulator aggregating them
+// and the dedup layer's record of each node's queued copy.
+type roundBatch struct {
+	acc    *batch.Accumulator
+	copies map[string]pendingUpdate
+}
+
+// roundCheckpoint returns the checkpoint policy of round's batch under the
+// federation's checkpoint directory, or the zero policy without one.
+func (f *Federation) roundCheckpoint(round int) batch.CheckpointPolicy {
+	policy := f.Checkpoints
+	if policy.Path != "" {
+		policy.Path = filepath.Join(policy.Path, "round-"+strconv.Itoa(round)+checkpointSuffix)
+	}
+	return policy
+}
+
+// batchLocked returns round's batch, opening it, and resuming its
+// checkpoint if there is one, on first use. Callers must hold f.mu.
+func (f *Federation) batchLocked(round int) (*roundBatch, error) {
+	if queued, ok := f.pending[round]; ok {
+		return queued, nil
+	}
+	acc, err := f.Aggregator.OpenAccumulator(round, f.roundCheckpoint(round))
+	if err != nil {
+		return nil, fmt.Errorf("federation %s: %w", f.ID, err)
+	}
+	queued := &roundBatch{acc: acc, copies: make(map[string]pendingUpdate)}
+	// Copies resumed from a checkpoint count as live and older than any
+	// that arrive after the restart.
+	for _, update := range acc.Updates() {
+		queued.copies[update.NodeID] = pendingUpdate{update: update, digest: protocol.UpdateDigest(update.Bytes())}
+	}
+	f.pending[round] = queued
+	return queued, nil
+}
+
+// resumeCheckpoints reopens the batch of every round the federation's
+// checkpoint directory holds one for, so updates queued before a restart
+// are aggregated with those that arrive after it.
+func (f *Federation) resumeCheckpoints() error {
+	dir := f.Checkpoints.Path
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("federation %s: checkpoint directory: %w", f.ID, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("federation %s: checkpoint directory: %w", f.ID, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), checkpointSuffix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		round, err := strconv.Atoi(strings.TrimPrefix(name, "round-"))
+		if err != nil || !strings.HasPrefix(name, "round-") {
+			continue
+		}
+		if _, err := f.batchLocked(round); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// discardCheckpoints removes the checkpoints of the rounds aggregated up to
+// and including round, once round commits and a restart should no longer
+// resume them.
+func (f *Federation) discardCheckpoints(round int) error {
+	f.mu.Lock()
+	var closed []*batch.Accumulator
+	for r, acc := range f.aggregated {
+		if r <= round {
+			closed = append(closed, acc)
+			delete(f.aggregated, r)
+		}
+	}
+	f.mu.Unlock()
+
+	var errs []error
+	for _, acc := range closed {
+		errs = append(errs, acc.Discard())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package federation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// Precedence decides which copy of a node's round update stands when the
+// node submits it twice by different paths: once synced from its island
+// cache and once live, typically after reconnecting mid-round. Both are
+// one logical submission, so only one is aggregated; the other is
+// acknowledged and recorded as superseded in provenance.
+type Precedence int
+
+const (
+	// PrecedenceLatest keeps the copy with the later timestamp, the queued
+	// one on a tie. It is the default.
+	PrecedenceLatest Precedence = iota
+	// PrecedenceLive keeps the live copy over one synced from the island
+	// cache, and otherwise the queued one.
+	PrecedenceLive
+	// PrecedenceFirst keeps whichever copy was queued first.
+	PrecedenceFirst
+)
+
+// String returns the precedence's name.
+func (p Precedence) String() string {
+	switch p {
+	case PrecedenceLatest:
+		return "latest"
+	case PrecedenceLive:
+		return "live"
+	case PrecedenceFirst:
+		return "first"
+	default:
+		return fmt.Sprintf("precedence(%d)", int(p))
+	}
+}
+
+// SetPrecedence sets which copy of a node's round update stands when one
+// is synced from its island cache and another submitted live. Unknown
+// values behave as PrecedenceLatest.
+func (f *Federation) SetPrecedence(precedence Precedence) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.precedence = precedence
+}
+
+// pendingUpdate is an update queued for aggregation with what the dedup
+// layer needs to weigh a second copy against it.
+type pendingUpdate struct {
+	update batch.Update
+	// digest is the UpdateDigest of the update's canonical encoding.
+	digest    string
+	submitted time.Time
+	// synced reports whether the update came from the node's island cache.
+	synced bool
+}
+
+// replaces reports whether incoming takes the place of queued, another
+// copy of the same node's round update.
+func (p Precedence) replaces(queued, incoming pendingUpdate) bool {
+	switch p {
+	case PrecedenceLive:
+		return queued.synced && !incoming.synced
+	case PrecedenceFirst:
+		return false
+	default:
+		return incoming.submitted.After(queued.submitted)
+	}
+}
+
+// checkOrigin checks that an island-synced update's origin names the node,
+// round, and content it arrived with.
+func checkOrigin(update *protocol.ModelUpdate, digest string) error {
+	origin := update.Origin
+	if origin.NodeID != update.NodeID || origin.Round != update.Round {
+		return fmt.Errorf("%w: node %s round %d carries origin node %s round %d", ErrInvalidOrigin, update.NodeID, update.Round, origin.NodeID, origin.Round)
+	}
+	if origin.UpdateDigest != digest {
+		return fmt.Errorf("%w: node %s round %d carries origin digest %s, content digests to %s", ErrInvalidOrigin, update.NodeID, update.Round, origin.UpdateDigest, digest)
+	}
+	return nil
+}
+
+// emitSuperseded records that pending was acknowledged but superseded by
+// another copy of it.
+func emitSuperseded(sink provenance.Sink, round int, pending pendingUpdate) {
+	sink.Emit(provenance.Event{
+		UpdateID: provenance.UpdateID(pending.update.NodeID, round, pending.digest),
+		NodeID:   pending.update.NodeID,
+		Round:    round,
+		Kind:     provenance.KindSuperseded,
+		Source:   "federation",
+		Reason:   reasons.Superseded,
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package federation
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the federation registry. Match them
+// with errors.Is; never compare error strings.
+var (
+	// ErrUnknownFederation means the host serves no federation with the
+	// requested ID. Not retryable until the federation is created.
+	ErrUnknownFederation = errors.New("unknown federation")
+	// ErrFederationExists means a federation with the ID is already served.
+	// Not retryable.
+	ErrFederationExists = errors.New("federation already exists")
+	// ErrInvalidFederation means a federation ID is malformed. Not
+	// retryable.
+	ErrInvalidFederation = errors.New("invalid federation id")
+	// ErrNotMember means the node is not bound to the federation its
+	// message names. Not retryable until the node registers for it.
+	ErrNotMember = errors.New("not a federation member")
+	// ErrFederationMismatch means a message names a different federation
+	// than the one it was routed to. Not retryable.
+	ErrFederationMismatch = errors.New("federation mismatch")
+	// ErrNoProposal means the federation has no open proposal for the
+	// round. Retryable once the federation proposes it.
+	ErrNoProposal = errors.New("no open proposal")
+	// ErrInvalidOrigin means an update synced from an island cache names
+	// an origin other than its own node, round, or content. Not retryable
+	// with the same update.
+	ErrInvalidOrigin = errors.New("invalid update origin")
+)
@@ -0,0 +1,416 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package federation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Federation is one namespace served by the host: its components, its
+// members, and the updates submitted for rounds not yet aggregated.
+type Federation struct {
+	ID string
+	Components
+
+	membership *consensus.StaticMembershipView
+
+	mu      sync.RWMutex
+	members map[string]bool
+	pending map[int]*roundBatch
+	// aggregated holds the accumulators of rounds aggregated but not yet
+	// committed, whose checkpoints a restart still resumes.
+	aggregated map[int]*batch.Accumulator
+	precedence Precedence
+	provenance provenance.Sink
+	lifecycle  events.Publisher
+	open       *Proposal
+	// summaryCheck, when set, must pass every update before it is queued.
+	summaryCheck func(update *protocol.ModelUpdate) error
+	// aggregationGate, when set, must admit a round's contributors before
+	// the round is aggregated.
+	aggregationGate func(round int, nodeIDs []string) error
+	// advisor, when set, stamps the hyperparameters of TrainingTask.
+	advisor *convergence.HyperparameterAdvisor
+	// shardEpoch, when set, is the membership epoch the members of a
+	// regional shard certify its commits under; certified is the
+	// certificate chain of the latest round they certified.
+	shardEpoch *protocol.MembershipEpochRecord
+	certified  *protocol.CertificateChain
+}
+
+func newFederation(id string, components Components) (*Federation, error) {
+	f := &Federation{
+		ID:         id,
+		Components: components,
+		membership: consensus.NewStaticMembershipView(nil),
+		members:    make(map[string]bool),
+		pending:    make(map[int]*roundBatch),
+		aggregated: make(map[int]*batch.Accumulator),
+	}
+	if f.Coordinator != nil {
+		f.Coordinator.SetMembershipView(f.membership)
+	}
+	if f.Aggregator != nil && f.ModelStore != nil {
+		f.Aggregator.SetModelSpec(f.ModelStore.Spec)
+	}
+	if err := f.resumeCheckpoints(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// IsMember reports whether nodeID is bound to the federation.
+func (f *Federation) IsMember(nodeID string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.members[nodeID]
+}
+
+// Members returns the federation's members, sorted.
+func (f *Federation) Members() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.sortedMembersLocked()
+}
+
+// MembershipView is the membership the federation's coordinator snapshots
+// for each proposal: its members and the host. Other coordinators voting
+// among the same nodes, such as a module catalog's, may share it.
+func (f *Federation) MembershipView() consensus.MembershipView {
+	return f.membership
+}
+
+// Participants returns the members selected for the federation's rounds,
+// sorted: every member, or, with Capabilities set, those whose admitted
+// manifest can train the federation's model.
+func (f *Federation) Participants() []string {
+	members := f.Members()
+	if f.Capabilities == nil {
+		return members
+	}
+	var params int64
+	if spec, ok := f.Spec(); ok {
+		params = int64(spec.Dimension)
+	}
+	return f.Capabilities.Eligible(members, params)
+}
+
+func (f *Federation) sortedMembersLocked() []string {
+	members := make([]string, 0, len(f.members))
+	for nodeID := range f.members {
+		members = append(members, nodeID)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// roundMembersLocked returns the nodes admitted to the federation's
+// consensus rounds: its members and the host, which proposes them.
+// Callers must hold f.mu, or own f before it is shared.
+func (f *Federation) roundMembersLocked() []string {
+	members := f.sortedMembersLocked()
+	if f.Coordinator == nil {
+		return members
+	}
+	host := f.Coordinator.NodeID()
+	if i := sort.SearchStrings(members, host); i == len(members) || members[i] != host {
+		members = append(members, host)
+		sort.Strings(members)
+	}
+	return members
+}
+
+func (f *Federation) addMember(nodeID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.members[nodeID] {
+		return
+	}
+	f.members[nodeID] = true
+	if f.Probation != nil {
+		f.Probation.Admit(nodeID)
+	}
+	f.membership.SetMembers(f.roundMembersLocked())
+}
+
+func (f *Federation) removeMember(nodeID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.members[nodeID] {
+		return
+	}
+	delete(f.members, nodeID)
+	if f.Probation != nil {
+		f.Probation.Remove(nodeID)
+	}
+	f.membership.SetMembers(f.roundMembersLocked())
+}
+
+// Submit queues a member's update for its round. The update must name this
+// federation, or none, and match the registered model spec, if any, with
+// protocol.ErrModelSpecMismatch otherwise. A node's second live update
+// for a round is refused with batch.ErrDuplicateUpdate. An update synced
+// from the node's island cache, which carries its origin, and another copy
+// for the same round are one submission: the precedence (see SetPrecedence)
+// picks the copy that is queued, and the other is acknowledged and
+// recorded in provenance as superseded; a copy identical to the queued one
+// is simply acknowledged. An origin that does not match the
+// update is refused with ErrInvalidOrigin. An update the summary check
+// refuses is refused with its error; see SetSummaryCheck. An update the
+// aggregator cannot ingest, or whose dimension differs from the round's
+// queued updates, is refused as batch.Accumulator.Add refuses it. With
+// checkpoints (see Components.Checkpoints) a queued update whose
+// checkpoint cannot be written stays queued and Submit returns the write
+// error.
+func (f *Federation) Submit(update *protocol.ModelUpdate) error {
+	if update.FederationID != "" && update.FederationID != f.ID {
+		return fmt.Errorf("%w: update names %s, routed to %s", ErrFederationMismatch, update.FederationID, f.ID)
+	}
+	if spec, ok := f.Spec(); ok {
+		if err := spec.CheckUpdate(update); err != nil {
+			return err
+		}
+	}
+	f.mu.RLock()
+	check := f.summaryCheck
+	f.mu.RUnlock()
+	if check != nil {
+		if err := check(update); err != nil {
+			return fmt.Errorf("node %s round %d: %w", update.NodeID, update.Round, err)
+		}
+	}
+	entry := batch.Update{
+		NodeID:      update.NodeID,
+		Quantized:   update.Quantized,
+		Sparse:      update.Sparse,
+		SampleCount: update.Metrics.Samples,
+		Statement:   update.Statement,
+		SpecVersion: update.SpecVersion,
+	}
+	if update.Weights != nil {
+		weights, err := batch.DecodeWeights(update.Weights)
+		if err != nil {
+			return fmt.Errorf("node %s: %w", update.NodeID, err)
+		}
+		entry.Weights = weights
+	}
+	incoming := pendingUpdate{
+		update:    entry,
+		digest:    protocol.UpdateDigest(entry.Bytes()),
+		submitted: update.Timestamp,
+		synced:    update.Origin != nil,
+	}
+	if incoming.synced {
+		if err := checkOrigin(update, incoming.digest); err != nil {
+			return err
+		}
+	}
+
+	f.mu.Lock()
+	if !f.members[update.NodeID] {
+		f.mu.Unlock()
+		return fmt.Errorf("%w: node %s in federation %s", ErrNotMember, update.NodeID, f.ID)
+	}
+	round, err := f.batchLocked(update.Round)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	queued, exists := round.copies[update.NodeID]
+	if exists && !queued.synced && !incoming.synced {
+		f.mu.Unlock()
+		return fmt.Errorf("%w: node %s round %d", batch.ErrDuplicateUpdate, update.NodeID, update.Round)
+	}
+	if exists && queued.digest == incoming.digest {
+		// The same content again, as when a synced update was also
+		// submitted live: already queued, with its provenance.
+		f.mu.Unlock()
+		return nil
+	}
+	accepted := !exists || f.precedence.replaces(queued, incoming)
+	var checkpointErr error
+	if accepted {
+		var receipt string
+		if exists {
+			receipt, checkpointErr = round.acc.Replace(entry)
+		} else {
+			receipt, checkpointErr = round.acc.Add(entry)
+		}
+		if receipt == "" {
+			f.mu.Unlock()
+			return fmt.Errorf("node %s: %w", update.NodeID, checkpointErr)
+		}
+		round.copies[update.NodeID] = incoming
+	}
+	sink := f.provenance
+	f.mu.Unlock()
+
+	if sink != nil {
+		emitIngest(sink, update.Round, entry)
+		switch {
+		case !accepted:
+			emitSuperseded(sink, update.Round, incoming)
+		case exists:
+			emitSuperseded(sink, update.Round, queued)
+		}
+	}
+	if !accepted {
+		return nil
+	}
+
+	if f.Metrics != nil {
+		labels := map[string]string{"federation": f.ID, "round": strconv.Itoa(update.Round)}
+		f.Metrics.Record(monitoring.MetricLoss, update.Metrics.Loss, labels, update.NodeID)
+		f.Metrics.Record(monitoring.MetricAccuracy, update.Metrics.Accuracy, labels, update.NodeID)
+	}
+	if checkpointErr != nil {
+		return fmt.Errorf("node %s round %d queued: %w", update.NodeID, update.Round, checkpointErr)
+	}
+	return nil
+}
+
+// SetSummaryCheck makes Submit pass every update through check before
+// queueing it. A global tier sets it to certchain.EpochLedger.CheckSummary
+// so a regional summary enters global aggregation only with a valid
+// certificate chain. Nil removes the check.
+func (f *Federation) SetSummaryCheck(check func(update *protocol.ModelUpdate) error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.summaryCheck = check
+}
+
+// SetAggregationGate makes Aggregate pass the node IDs of a round's queued
+// updates, in node order, through gate before aggregating them. A global
+// tier sets it to charge the contributing shards' privacy budgets; see
+// privacy.BudgetRegistry.BeginGlobalRound. gate runs under the federation's
+// lock and must not call back into it. Nil removes the gate.
+func (f *Federation) SetAggregationGate(gate func(round int, nodeIDs []string) error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.aggregationGate = gate
+}
+
+// SetProvenance sends the lifecycle events of the federation's updates to
+// sink: received and, when the training statement holds, signature_verified
+// on Submit, and the events of its aggregator and peer table. The
+// aggregated and committed events follow from the round lifecycle; see
+// SetEvents and provenance.Subscribe. nil stops emitting.
+func (f *Federation) SetProvenance(sink provenance.Sink) {
+	f.mu.Lock()
+	f.provenance = sink
+	f.mu.Unlock()
+
+	if f.Aggregator != nil {
+		f.Aggregator.SetProvenance(sink)
+	}
+	if f.Peers != nil {
+		f.Peers.SetProvenance(sink)
+	}
+}
+
+// SetEvents publishes the federation's round lifecycle to pub, stamped
+// with its ID: updates_closed from its aggregator, the proposal events
+// from its coordinator, and whatever its round scheduler passes to
+// Publish. nil stops publishing.
+func (f *Federation) SetEvents(pub events.Publisher) {
+	scoped := events.Scope(pub, f.ID)
+	f.mu.Lock()
+	f.lifecycle = scoped
+	f.mu.Unlock()
+
+	if f.Aggregator != nil {
+		f.Aggregator.SetEvents(scoped)
+	}
+	if f.Coordinator != nil {
+		f.Coordinator.SetEvents(scoped)
+	}
+}
+
+// Publish publishes event as one of the federation's, if SetEvents set a
+// publisher.
+func (f *Federation) Publish(event events.Event) {
+	f.mu.RLock()
+	pub := f.lifecycle
+	f.mu.RUnlock()
+	if pub != nil {
+		pub.Publish(event)
+	}
+}
+
+// emitIngest reports a queued update's ingest events.
+func emitIngest(sink provenance.Sink, round int, update batch.Update) {
+	digest := protocol.UpdateDigest(update.Bytes())
+	event := provenance.Event{
+		UpdateID: provenance.UpdateID(update.NodeID, round, digest),
+		NodeID:   update.NodeID,
+		Round:    round,
+		Kind:     provenance.KindReceived,
+		Source:   "federation",
+	}
+	sink.Emit(event)
+	if update.Statement != nil && update.Statement.Check(update.NodeID, round, digest) == nil {
+		event.Kind = provenance.KindSignatureVerified
+		sink.Emit(event)
+	}
+}
+
+// Pending returns how many updates are queued for round.
+func (f *Federation) Pending(round int) int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if queued, ok := f.pending[round]; ok {
+		return len(queued.copies)
+	}
+	return 0
+}
+
+// Aggregate takes the updates queued for round and aggregates them, in node
+// order, with the federation's batch aggregator, once the aggregation gate
+// admits them; a round it refuses stays queued. The round's checkpoint,
+// if any, is kept until a round at or after it commits, so a restart
+// before then queues the batch again. ctx bounds the aggregation; see
+// batch.Aggregator.Aggregate.
+func (f *Federation) Aggregate(ctx context.Context, round int) (*batch.AggregationResult, error) {
+	f.mu.Lock()
+	queued, err := f.batchLocked(round)
+	if err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	if f.aggregationGate != nil {
+		nodeIDs := make([]string, 0, len(queued.copies))
+		for _, update := range queued.acc.Updates() {
+			nodeIDs = append(nodeIDs, update.NodeID)
+		}
+		if err := f.aggregationGate(round, nodeIDs); err != nil {
+			f.mu.Unlock()
+			return nil, fmt.Errorf("federation %s round %d: %w", f.ID, round, err)
+		}
+	}
+	delete(f.pending, round)
+	f.aggregated[round] = queued.acc
+	f.mu.Unlock()
+
+	return queued.acc.Result(ctx)
+}
+
+// CastVote passes a member's vote to the federation's coordinator.
+func (f *Federation) CastVote(ctx context.Context, vote *consensus.Vote) error {
+	if !f.IsMember(vote.NodeID) {
+		return fmt.Errorf("%w: node %s in federation %s", ErrNotMember, vote.NodeID, f.ID)
+	}
+	return f.Coordinator.CastVote(ctx, vote)
+}
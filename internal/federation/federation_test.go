@@ -0,0 +1,772 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func newTestRegistry(t *testing.T, ids ...string) *Registry {
+	t.Helper()
+	registry := NewRegistry(NewFactory(Config{HostID: "host", Timeout: time.Second}))
+	for _, id := range ids {
+		if _, err := registry.Create(id); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	return registry
+}
+
+// stated attaches the training statement of a member that trained update
+// from the task f distributes for its round.
+func stated(f *Federation, update *protocol.ModelUpdate) *protocol.ModelUpdate {
+	encoded := batch.Update{Quantized: update.Quantized, Sparse: update.Sparse}.Bytes()
+	if update.Weights != nil {
+		encoded = update.Weights
+	}
+	update.Statement = protocol.NewTrainingStatement(update.NodeID, f.TrainingTask(update.Round), encoded)
+	return update
+}
+
+func TestRegistryCreateValidatesIDs(t *testing.T) {
+	registry := newTestRegistry(t, "traffic")
+	if _, err := registry.Create("traffic"); !errors.Is(err, ErrFederationExists) {
+		t.Fatalf("expected ErrFederationExists, got %v", err)
+	}
+	for _, id := range []string{"", "Traffic", "traffic/east", "a b"} {
+		if _, err := registry.Create(id); !errors.Is(err, ErrInvalidFederation) {
+			t.Fatalf("Create(%q): expected ErrInvalidFederation, got %v", id, err)
+		}
+	}
+	if _, err := registry.Get(""); !errors.Is(err, ErrUnknownFederation) {
+		t.Fatalf("expected the empty id to name the missing default federation, got %v", err)
+	}
+}
+
+func TestBindIsAllOrNothing(t *testing.T) {
+	registry := newTestRegistry(t, "traffic", "parking")
+	if _, err := registry.Bind("edge-1", []string{"traffic", "weather"}); !errors.Is(err, ErrUnknownFederation) {
+		t.Fatalf("expected ErrUnknownFederation, got %v", err)
+	}
+	if got := registry.Memberships("edge-1"); len(got) != 0 {
+		t.Fatalf("expected no bindings after a failed Bind, got %v", got)
+	}
+
+	bound, err := registry.Bind("edge-1", []string{"parking", "traffic"})
+	if err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	if len(bound) != 2 || bound[0] != "parking" || bound[1] != "traffic" {
+		t.Fatalf("bound = %v", bound)
+	}
+	if err := registry.Unbind("edge-1", "parking"); err != nil {
+		t.Fatalf("unbind: %v", err)
+	}
+	if _, err := registry.Route("parking", "edge-1"); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("expected ErrNotMember after Unbind, got %v", err)
+	}
+	if _, err := registry.Route("traffic", "edge-1"); err != nil {
+		t.Fatalf("route: %v", err)
+	}
+}
+
+func TestSubmitKeepsUpdatesInTheirFederation(t *testing.T) {
+	registry := newTestRegistry(t, "traffic", "parking")
+	if _, err := registry.Bind("edge-1", []string{"traffic"}); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	traffic, _ := registry.Get("traffic")
+	parking, _ := registry.Get("parking")
+
+	update := &protocol.ModelUpdate{
+		NodeID:  "edge-1",
+		Round:   1,
+		Weights: batch.Update{Weights: []float64{0.5, -0.5}}.Bytes(),
+		Metrics: protocol.Metrics{Loss: 0.3, Samples: 20},
+	}
+	stated(traffic, update)
+	if err := parking.Submit(update); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("expected ErrNotMember, got %v", err)
+	}
+	if err := traffic.Submit(update); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if err := traffic.Submit(update); !errors.Is(err, batch.ErrDuplicateUpdate) {
+		t.Fatalf("expected ErrDuplicateUpdate, got %v", err)
+	}
+	mislabeled := *update
+	mislabeled.Round = 2
+	mislabeled.FederationID = "parking"
+	if err := traffic.Submit(&mislabeled); !errors.Is(err, ErrFederationMismatch) {
+		t.Fatalf("expected ErrFederationMismatch, got %v", err)
+	}
+
+	result, err := traffic.Aggregate(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if len(result.Manifest.Entries) != 1 || result.Weights[0] != 0.5 {
+		t.Fatalf("unexpected aggregate %+v", result)
+	}
+	if traffic.Pending(1) != 0 {
+		t.Fatal("expected Aggregate to drain the round")
+	}
+	if len(parking.Metrics.GetMetrics()) != 0 {
+		t.Fatalf("parking recorded %d metrics for a traffic update", len(parking.Metrics.GetMetrics()))
+	}
+	for _, metric := range traffic.Metrics.GetMetrics() {
+		if metric.Labels["federation"] != "traffic" {
+			t.Fatalf("metric labeled %v", metric.Labels)
+		}
+	}
+}
+
+func TestVotesFromOtherFederationsAreRejected(t *testing.T) {
+	registry := newTestRegistry(t, "traffic", "parking")
+	for _, binding := range []struct{ node, federation string }{
+		{"traffic-1", "traffic"}, {"traffic-2", "traffic"}, {"parking-1", "parking"},
+	} {
+		if _, err := registry.Bind(binding.node, []string{binding.federation}); err != nil {
+			t.Fatalf("bind: %v", err)
+		}
+	}
+	traffic, _ := registry.Get("traffic")
+
+	ctx := context.Background()
+	proposalID, err := traffic.Coordinator.ProposeModel(ctx, &consensus.ModelProposal{Round: 1, Weights: []byte("w"), ProposerID: "host", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	vote := &consensus.Vote{NodeID: "parking-1", ProposalID: proposalID, Approve: true, Timestamp: time.Now()}
+	if err := traffic.CastVote(ctx, vote); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("expected ErrNotMember, got %v", err)
+	}
+	// A vote reaching the coordinator directly is refused by the round's
+	// membership snapshot.
+	if err := traffic.Coordinator.CastVote(ctx, vote); !errors.Is(err, consensus.ErrNotRoundMember) {
+		t.Fatalf("expected ErrNotRoundMember, got %v", err)
+	}
+	vote.NodeID = "traffic-1"
+	if err := traffic.CastVote(ctx, vote); err != nil {
+		t.Fatalf("member vote: %v", err)
+	}
+}
+
+func TestProposeAndCommitRound(t *testing.T) {
+	registry := newTestRegistry(t, "traffic")
+	traffic, _ := registry.Get("traffic")
+	members := []string{"edge-1", "edge-2", "edge-3"}
+	for i, nodeID := range members {
+		if _, err := registry.Bind(nodeID, []string{"traffic"}); err != nil {
+			t.Fatalf("bind: %v", err)
+		}
+		update := &protocol.ModelUpdate{
+			NodeID:  nodeID,
+			Round:   1,
+			Weights: batch.Update{Weights: []float64{float64(i), 1}}.Bytes(),
+			Metrics: protocol.Metrics{Samples: 10},
+		}
+		if err := traffic.Submit(stated(traffic, update)); err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	if _, err := traffic.Proposal(1); !errors.Is(err, ErrNoProposal) {
+		t.Fatalf("expected ErrNoProposal before proposing, got %v", err)
+	}
+	proposal, err := traffic.Propose(ctx, 1, "host")
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	if proposal.Samples != 30 || len(proposal.Manifest.IncludedNodes()) != 3 {
+		t.Fatalf("unexpected proposal %+v", proposal)
+	}
+	if got, err := traffic.Proposal(1); err != nil || got.ID != proposal.ID {
+		t.Fatalf("Proposal(1) = %+v, %v", got, err)
+	}
+
+	// The host and two of three members make quorum (3 of 4).
+	for _, nodeID := range members[:2] {
+		vote := &consensus.Vote{NodeID: nodeID, ProposalID: proposal.ID, Approve: true, Timestamp: time.Now()}
+		if err := traffic.CastVote(ctx, vote); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+	summary, err := traffic.Commit(ctx, 1)
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if len(summary.Certificate.Approvals) != 3 || summary.Certificate.QuorumSize != 3 {
+		t.Fatalf("certificate = %+v", summary.Certificate)
+	}
+	weights, stored, ok := traffic.ModelStore.Model(1)
+	if !ok || protocol.WeightsDigest(weights) != summary.ModelDigest || stored.ManifestDigest != proposal.Manifest.Digest() {
+		t.Fatalf("round 1 not committed with its manifest: %+v", stored)
+	}
+	if timeline := summary.VoteTimeline; timeline == nil || timeline.Round != 1 || timeline.Votes != 3 || !timeline.QuorumReached || stored.VoteTimeline == nil {
+		t.Fatalf("round 1 vote timeline = %+v, stored %+v", timeline, stored.VoteTimeline)
+	}
+	if _, err := traffic.Proposal(1); !errors.Is(err, ErrNoProposal) {
+		t.Fatalf("expected the proposal closed after commit, got %v", err)
+	}
+
+	// Round 2 trains from round 1's model; an update still trained from
+	// the genesis model is excluded as stale.
+	for i, nodeID := range members[:2] {
+		update := &protocol.ModelUpdate{NodeID: nodeID, Round: 2, Weights: batch.Update{Weights: []float64{float64(i), 1}}.Bytes(), Metrics: protocol.Metrics{Samples: 10}}
+		stated(traffic, update)
+		if nodeID == "edge-2" {
+			update.Statement = protocol.NewTrainingStatement(nodeID, protocol.TrainingTask{Round: 2}, update.Weights)
+		}
+		if err := traffic.Submit(update); err != nil {
+			t.Fatalf("submit round 2: %v", err)
+		}
+	}
+	result, err := traffic.Aggregate(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("aggregate round 2: %v", err)
+	}
+	if entry, _ := result.Manifest.Entry("edge-2"); entry.Included || entry.Reason != protocol.ReasonStaleBaseModel {
+		t.Fatalf("stale update entry = %+v", entry)
+	}
+	if entry, _ := result.Manifest.Entry("edge-1"); !entry.Included {
+		t.Fatalf("current update entry = %+v", entry)
+	}
+}
+
+func TestFederationsHaveIndependentPrivacyAccountants(t *testing.T) {
+	registry := newTestRegistry(t, "traffic", "parking")
+	traffic, _ := registry.Get("traffic")
+	parking, _ := registry.Get("parking")
+
+	if _, err := traffic.Privacy.AddGaussianNoise(1); err != nil {
+		t.Fatalf("noise: %v", err)
+	}
+	if used, _ := traffic.Privacy.GetPrivacyBudget(); used == 0 {
+		t.Fatal("expected traffic to spend privacy budget")
+	}
+	if used, _ := parking.Privacy.GetPrivacyBudget(); used != 0 {
+		t.Fatalf("parking spent %g of its budget on a traffic release", used)
+	}
+}
+
+func TestModelSpecIsCheckedAtEveryBoundary(t *testing.T) {
+	spec := protocol.ModelSpec{Name: "mlp", Version: 1, Dimension: 2, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp"))}
+	registry := NewRegistry(NewFactory(Config{HostID: "host", Timeout: time.Second, ModelSpec: &spec}))
+	traffic, err := registry.Create("traffic")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	members := []string{"edge-1", "edge-2", "edge-3"}
+	for _, nodeID := range members {
+		if _, err := registry.Bind(nodeID, []string{"traffic"}); err != nil {
+			t.Fatalf("bind: %v", err)
+		}
+	}
+	task := traffic.TrainingTask(1)
+	if task.Spec == nil || task.Spec.Version != 1 || task.FederationID != "traffic" {
+		t.Fatalf("training task = %+v", task)
+	}
+	advisor, err := convergence.NewHyperparameterAdvisor(convergence.Hyperparameters{LearningRate: 0.1, Epochs: 2}, nil)
+	if err != nil {
+		t.Fatalf("advisor: %v", err)
+	}
+	traffic.SetHyperparameterAdvisor(advisor)
+	if task := traffic.TrainingTask(1); task.LearningRate != 0.1 || task.Epochs != 2 {
+		t.Fatalf("advised training task = %+v", task)
+	}
+	update := func(nodeID string, version int, weights []float64) *protocol.ModelUpdate {
+		return stated(traffic, &protocol.ModelUpdate{
+			NodeID:      nodeID,
+			Round:       1,
+			Weights:     batch.Update{Weights: weights}.Bytes(),
+			Metrics:     protocol.Metrics{Samples: 10},
+			SpecVersion: version,
+		})
+	}
+
+	// Ingestion refuses another version or dimension before queueing.
+	for name, wrong := range map[string]*protocol.ModelUpdate{
+		"unversioned":     update("edge-1", 0, []float64{1, 2}),
+		"future version":  update("edge-1", 2, []float64{1, 2}),
+		"wrong dimension": update("edge-1", 1, []float64{1, 2, 3}),
+	} {
+		if err := traffic.Submit(wrong); !errors.Is(err, protocol.ErrModelSpecMismatch) {
+			t.Fatalf("%s: expected ErrModelSpecMismatch, got %v", name, err)
+		}
+	}
+	if traffic.Pending(1) != 0 {
+		t.Fatalf("%d mismatched updates were queued", traffic.Pending(1))
+	}
+	for i, nodeID := range members {
+		if err := traffic.Submit(update(nodeID, 1, []float64{float64(i), 1})); err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	proposal, err := traffic.Propose(ctx, 1, "host")
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	if proposal.SpecVersion != 1 {
+		t.Fatalf("proposal spec version = %d, want 1", proposal.SpecVersion)
+	}
+	for _, nodeID := range members[:2] {
+		vote := &consensus.Vote{NodeID: nodeID, ProposalID: proposal.ID, Approve: true, Timestamp: time.Now()}
+		if err := traffic.CastVote(ctx, vote); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+
+	// A spec change agreed while the proposal is open keeps it from
+	// committing.
+	next := spec
+	next.Version, next.Dimension = 2, 3
+	change := modeldist.SpecChange{Spec: next, Certificate: modeldist.CommitCertificate{
+		Round:       1,
+		ProposalID:  "spec-2",
+		ModelDigest: next.Digest(),
+		QuorumSize:  3,
+		Approvals:   []string{"host", "edge-1", "edge-2"},
+	}}
+	if err := traffic.ChangeSpec(change); err != nil {
+		t.Fatalf("change spec: %v", err)
+	}
+	if _, err := traffic.Commit(ctx, 1); !errors.Is(err, protocol.ErrModelSpecMismatch) {
+		t.Fatalf("commit under a changed spec: expected ErrModelSpecMismatch, got %v", err)
+	}
+	if _, _, ok := traffic.ModelStore.Model(1); ok {
+		t.Fatal("a proposal built for v1 was committed under v2")
+	}
+	if task := traffic.TrainingTask(1); task.Spec.Version != 2 || task.Spec.Dimension != 3 {
+		t.Fatalf("training task after the change = %+v", task.Spec)
+	}
+	if err := traffic.Submit(update("edge-1", 1, []float64{1, 2})); !errors.Is(err, protocol.ErrModelSpecMismatch) {
+		t.Fatalf("v1 update after the change: expected ErrModelSpecMismatch, got %v", err)
+	}
+	if err := traffic.Submit(update("edge-1", 2, []float64{1, 2, 3})); err != nil {
+		t.Fatalf("submit v2: %v", err)
+	}
+}
+
+func TestNewFactoryRejectsInvalidModelSpec(t *testing.T) {
+	spec := protocol.ModelSpec{Name: "mlp", Version: 1, Dimension: 0}
+	registry := NewRegistry(NewFactory(Config{HostID: "host", Timeout: time.Second, ModelSpec: &spec}))
+	if _, err := registry.Create("traffic"); !errors.Is(err, protocol.ErrInvalidModelSpec) {
+		t.Fatalf("expected ErrInvalidModelSpec, got %v", err)
+	}
+}
+
+func TestParticipantsExcludeNodesThatCannotTrainTheModel(t *testing.T) {
+	spec := protocol.ModelSpec{Name: "mlp", Version: 1, Dimension: 1000, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp"))}
+	capabilities := scheduler.NewCapabilityRegistry(scheduler.AdmissionPolicy{})
+	registry := NewRegistry(NewFactory(Config{HostID: "host", Timeout: time.Second, ModelSpec: &spec, Capabilities: capabilities}))
+	traffic, err := registry.Create("traffic")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for nodeID, memory := range map[string]int64{"edge-1": 1 << 20, "edge-2": 1000, "edge-3": 1 << 20} {
+		manifest := &protocol.CapabilityManifest{NodeID: nodeID, CPUCount: 2, CPUClass: protocol.CPUClassFor(2), MemoryBytes: memory}
+		if err := capabilities.Admit(manifest); err != nil {
+			t.Fatalf("admit %s: %v", nodeID, err)
+		}
+	}
+	// edge-4 registered no manifest.
+	for _, nodeID := range []string{"edge-1", "edge-2", "edge-3", "edge-4"} {
+		if _, err := registry.Bind(nodeID, []string{"traffic"}); err != nil {
+			t.Fatalf("bind %s: %v", nodeID, err)
+		}
+	}
+	if got := traffic.Participants(); fmt.Sprint(got) != "[edge-1 edge-3]" {
+		t.Fatalf("participants = %v, want [edge-1 edge-3]", got)
+	}
+	if got := traffic.Members(); len(got) != 4 {
+		t.Fatalf("members = %v, want all four", got)
+	}
+}
+
+func TestPersonalSegmentsNeverLeaveTheNode(t *testing.T) {
+	// Coordinates 2 and 3 are each node's personal head; the federation
+	// trains and distributes the other three.
+	spec := protocol.ModelSpec{
+		Name: "mlp", Version: 1, Dimension: 5, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp")),
+		Personal: []protocol.Segment{{Name: "head", Offset: 2, Length: 2}},
+	}
+	registry := NewRegistry(NewFactory(Config{HostID: "host", Timeout: time.Second, ModelSpec: &spec}))
+	traffic, err := registry.Create("traffic")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	members := []string{"edge-1", "edge-2", "edge-3"}
+	for _, nodeID := range members {
+		if _, err := registry.Bind(nodeID, []string{"traffic"}); err != nil {
+			t.Fatalf("bind: %v", err)
+		}
+	}
+	update := func(nodeID string, weights []float64) *protocol.ModelUpdate {
+		return stated(traffic, &protocol.ModelUpdate{NodeID: nodeID, Round: 1, Weights: batch.Update{Weights: weights}.Bytes(), Metrics: protocol.Metrics{Samples: 10}, SpecVersion: 1})
+	}
+
+	full := []float64{1, 2, 7, 8, 3}
+	if err := traffic.Submit(update("edge-1", full)); !errors.Is(err, protocol.ErrPersonalCoordinates) {
+		t.Fatalf("full vector: expected ErrPersonalCoordinates, got %v", err)
+	}
+	sparse := update("edge-1", nil)
+	sparse.Weights, sparse.Sparse = nil, &protocol.SparseUpdate{Dim: 5, Indices: []uint32{0, 3}, Values: []float64{1, 8}}
+	if err := traffic.Submit(sparse); !errors.Is(err, protocol.ErrPersonalCoordinates) {
+		t.Fatalf("sparse full vector: expected ErrPersonalCoordinates, got %v", err)
+	}
+	if err := traffic.Submit(update("edge-1", []float64{1, 2, 0, 0, 3})); !errors.Is(err, protocol.ErrModelSpecMismatch) {
+		t.Fatalf("unstripped vector: expected ErrModelSpecMismatch, got %v", err)
+	}
+	if traffic.Pending(1) != 0 {
+		t.Fatalf("%d unstripped updates were queued", traffic.Pending(1))
+	}
+
+	for i, nodeID := range members {
+		local := []float64{float64(i), 1, float64(10 * i), -1, 2}
+		stripped, err := spec.Strip(local)
+		if err != nil || len(stripped) != spec.GlobalDimension() {
+			t.Fatalf("strip %v = %v, %v", local, stripped, err)
+		}
+		if err := traffic.Submit(update(nodeID, stripped)); err != nil {
+			t.Fatalf("submit %s: %v", nodeID, err)
+		}
+	}
+	ctx := context.Background()
+	proposal, err := traffic.Propose(ctx, 1, "host")
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	for _, nodeID := range members[:2] {
+		vote := &consensus.Vote{NodeID: nodeID, ProposalID: proposal.ID, Approve: true, Timestamp: time.Now()}
+		if err := traffic.CastVote(ctx, vote); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+	if _, err := traffic.Commit(ctx, 1); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	// The next task distributes the mean of the global coordinates only.
+	task := traffic.TrainingTask(2)
+	global, err := protocol.ParseModelWeights(task.GlobalWeights)
+	if err != nil {
+		t.Fatalf("parse distributed model: %v", err)
+	}
+	if want := []float64{1, 1, 2}; len(global.Weights) != len(want) || global.Weights[0] != want[0] || global.Weights[1] != want[1] || global.Weights[2] != want[2] {
+		t.Fatalf("distributed model = %v, want %v", global.Weights, want)
+	}
+	if task.Spec == nil || task.Spec.GlobalDimension() != 3 {
+		t.Fatalf("task spec = %+v", task.Spec)
+	}
+	merged, err := task.Spec.Merge(global.Weights, full)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if want := []float64{1, 1, 7, 8, 2}; fmt.Sprint(merged) != fmt.Sprint(want) {
+		t.Fatalf("merged model = %v, want %v", merged, want)
+	}
+}
+
+func TestModelSpecValidatesPersonalSegments(t *testing.T) {
+	for name, segments := range map[string][]protocol.Segment{
+		"empty":        {{Name: "head", Offset: 1, Length: 0}},
+		"overlapping":  {{Name: "a", Offset: 0, Length: 2}, {Name: "b", Offset: 1, Length: 1}},
+		"unordered":    {{Name: "a", Offset: 3, Length: 1}, {Name: "b", Offset: 0, Length: 1}},
+		"past the end": {{Name: "head", Offset: 3, Length: 2}},
+		"everything":   {{Name: "all", Offset: 0, Length: 4}},
+	} {
+		spec := protocol.ModelSpec{Name: "mlp", Version: 1, Dimension: 4, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp")), Personal: segments}
+		if err := spec.Validate(); !errors.Is(err, protocol.ErrInvalidModelSpec) {
+			t.Fatalf("%s: expected ErrInvalidModelSpec, got %v", name, err)
+		}
+	}
+}
+
+type eventRecorder struct {
+	events []provenance.Event
+}
+
+func (r *eventRecorder) Emit(event provenance.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestIslandSyncedAndLiveCopiesAreOneSubmission(t *testing.T) {
+	now := time.Now()
+	copyOf := func(value float64, at time.Time, synced bool) *protocol.ModelUpdate {
+		update := &protocol.ModelUpdate{
+			NodeID:    "edge-1",
+			Round:     1,
+			Weights:   batch.Update{Weights: []float64{value}}.Bytes(),
+			Timestamp: at,
+			Metrics:   protocol.Metrics{Samples: 10},
+		}
+		if synced {
+			update.Origin = &protocol.UpdateOrigin{NodeID: "edge-1", Round: 1, UpdateDigest: protocol.UpdateDigest(update.Weights)}
+		}
+		return update
+	}
+	cached := copyOf(1, now.Add(-time.Minute), true)
+	live := copyOf(2, now, false)
+
+	for _, tc := range []struct {
+		precedence Precedence
+		first      *protocol.ModelUpdate
+		second     *protocol.ModelUpdate
+		want       float64
+	}{
+		{PrecedenceLatest, cached, live, 2},
+		{PrecedenceLatest, live, cached, 2},
+		{PrecedenceLive, live, cached, 2},
+		{PrecedenceFirst, cached, live, 1},
+	} {
+		registry := newTestRegistry(t, "traffic")
+		if _, err := registry.Bind("edge-1", []string{"traffic"}); err != nil {
+			t.Fatalf("bind: %v", err)
+		}
+		traffic, _ := registry.Get("traffic")
+		traffic.SetPrecedence(tc.precedence)
+		recorder := &eventRecorder{}
+		traffic.SetProvenance(recorder)
+
+		if err := traffic.Submit(stated(traffic, tc.first)); err != nil {
+			t.Fatalf("%s: first submit: %v", tc.precedence, err)
+		}
+		if err := traffic.Submit(stated(traffic, tc.second)); err != nil {
+			t.Fatalf("%s: second copy should be acknowledged, got %v", tc.precedence, err)
+		}
+		result, err := traffic.Aggregate(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("%s: aggregate: %v", tc.precedence, err)
+		}
+		if len(result.Manifest.Entries) != 1 || result.Weights[0] != tc.want {
+			t.Fatalf("%s: expected one contribution of %v, got %+v", tc.precedence, tc.want, result)
+		}
+		superseded := 0
+		for _, event := range recorder.events {
+			if event.Kind == provenance.KindSuperseded {
+				superseded++
+				if event.Reason != "superseded" || event.UpdateID == provenance.UpdateID("edge-1", 1, result.Manifest.Entries[0].UpdateDigest) {
+					t.Fatalf("%s: superseded event names the stood copy: %+v", tc.precedence, event)
+				}
+			}
+			if event.Kind == provenance.KindExcluded {
+				t.Fatalf("%s: a superseded copy must not be excluded: %+v", tc.precedence, event)
+			}
+		}
+		if superseded != 1 {
+			t.Fatalf("%s: expected one superseded event, got %d", tc.precedence, superseded)
+		}
+	}
+
+	registry := newTestRegistry(t, "traffic")
+	if _, err := registry.Bind("edge-1", []string{"traffic"}); err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	traffic, _ := registry.Get("traffic")
+	if err := traffic.Submit(live); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if err := traffic.Submit(copyOf(3, now, false)); !errors.Is(err, batch.ErrDuplicateUpdate) {
+		t.Fatalf("expected a second live update to be ErrDuplicateUpdate, got %v", err)
+	}
+	forged := copyOf(4, now, true)
+	forged.Origin.UpdateDigest = protocol.UpdateDigest([]byte("other"))
+	if err := traffic.Submit(forged); !errors.Is(err, ErrInvalidOrigin) {
+		t.Fatalf("expected ErrInvalidOrigin, got %v", err)
+	}
+}
+
+func TestCommitNeedsValidatorSignedApprovals(t *testing.T) {
+	keys := make(map[string][]byte)
+	channels := make(map[string]*crypto.SecureChannel)
+	for _, nodeID := range []string{"host", "edge-1", "edge-2"} {
+		channel, err := crypto.NewSecureChannelWithAlgorithm(protocol.AlgorithmEd25519)
+		if err != nil {
+			t.Fatalf("key: %v", err)
+		}
+		keys[nodeID], _ = channel.ExportPublicKey()
+		channels[nodeID] = channel
+	}
+	registry := NewRegistry(NewFactory(Config{
+		HostID:     "host",
+		Timeout:    time.Second,
+		Signer:     channels["host"],
+		Validators: &modeldist.Validators{Keys: keys, Quorum: 2},
+	}))
+	traffic, err := registry.Create("traffic")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	ctx := context.Background()
+	propose := func(round int) *Proposal {
+		for i, nodeID := range []string{"edge-1", "edge-2"} {
+			if _, err := registry.Bind(nodeID, []string{"traffic"}); err != nil {
+				t.Fatalf("bind: %v", err)
+			}
+			update := &protocol.ModelUpdate{NodeID: nodeID, Round: round, Weights: batch.Update{Weights: []float64{float64(i), 1}}.Bytes(), Metrics: protocol.Metrics{Samples: 10}}
+			if err := traffic.Submit(stated(traffic, update)); err != nil {
+				t.Fatalf("submit: %v", err)
+			}
+		}
+		proposal, err := traffic.Propose(ctx, round, "host")
+		if err != nil {
+			t.Fatalf("propose: %v", err)
+		}
+		return proposal
+	}
+
+	// Unsigned member approvals make the coordinator's quorum but not the
+	// validators': only the host signed.
+	proposal := propose(1)
+	for _, nodeID := range []string{"edge-1", "edge-2"} {
+		if err := traffic.CastVote(ctx, &consensus.Vote{NodeID: nodeID, ProposalID: proposal.ID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote: %v", err)
+		}
+	}
+	if _, err := traffic.Commit(ctx, 1); !errors.Is(err, modeldist.ErrInsufficientApprovals) {
+		t.Fatalf("expected ErrInsufficientApprovals, got %v", err)
+	}
+
+	proposal = propose(1)
+	for _, nodeID := range []string{"edge-1", "edge-2"} {
+		digest := proposal.ApprovalDigest()
+		signature, err := channels[nodeID].SignData(digest[:])
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		vote := &consensus.Vote{NodeID: nodeID, ProposalID: proposal.ID, Approve: true, Timestamp: time.Now(), CommitSignature: signature}
+		if err := traffic.CastVote(ctx, vote); err != nil {
+			t.Fatalf("vote: %v", err)
+		}
+	}
+	summary, err := traffic.Commit(ctx, 1)
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if err := summary.Certificate.Verify(nil, &modeldist.Validators{Keys: keys, Quorum: 3}); err != nil {
+		t.Fatalf("expected the host's and both members' signatures in the certificate: %v", err)
+	}
+}
+
+func TestRestartResumesQueuedUpdates(t *testing.T) {
+	cfg := Config{HostID: "host", Timeout: time.Second, Checkpoints: batch.CheckpointPolicy{Path: t.TempDir(), Every: 1}}
+	start := func() *Federation {
+		t.Helper()
+		registry := NewRegistry(NewFactory(cfg))
+		traffic, err := registry.Create("traffic")
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		for _, nodeID := range []string{"edge-1", "edge-2", "edge-3"} {
+			if _, err := registry.Bind(nodeID, []string{"traffic"}); err != nil {
+				t.Fatalf("bind: %v", err)
+			}
+		}
+		return traffic
+	}
+	submit := func(f *Federation, nodeID string, weight float64) error {
+		return f.Submit(stated(f, &protocol.ModelUpdate{
+			NodeID:  nodeID,
+			Round:   1,
+			Weights: batch.Update{Weights: []float64{weight}}.Bytes(),
+			Metrics: protocol.Metrics{Samples: 10},
+		}))
+	}
+
+	traffic := start()
+	if err := submit(traffic, "edge-1", 1); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if err := submit(traffic, "edge-2", 2); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	restarted := start()
+	if restarted.Pending(1) != 2 {
+		t.Fatalf("restart resumed %d updates, want 2", restarted.Pending(1))
+	}
+	if err := submit(restarted, "edge-2", 2); !errors.Is(err, batch.ErrDuplicateUpdate) {
+		t.Fatalf("resubmission after restart: %v, want ErrDuplicateUpdate", err)
+	}
+	if err := submit(restarted, "edge-3", 30); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	result, err := restarted.Aggregate(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if len(result.Manifest.Entries) != 3 || result.Manifest.Entries[2].Reason != protocol.ReasonNormOutlier {
+		t.Fatalf("manifest %+v, want edge-3 excluded as an outlier", result.Manifest.Entries)
+	}
+	if result.Weights[0] != 1.5 {
+		t.Fatalf("aggregate %v, want 1.5", result.Weights[0])
+	}
+	if again := start(); again.Pending(1) != 3 {
+		t.Fatalf("uncommitted round resumed %d updates, want 3", again.Pending(1))
+	}
+}
+
+func TestShardBudgetsGateGlobalAggregation(t *testing.T) {
+	registry := newTestRegistry(t, "global")
+	global, _ := registry.Get("global")
+	budgets := privacy.NewBudgetRegistry()
+	for _, shardID := range []string{"regional-a", "regional-b"} {
+		if err := budgets.Register(shardID, privacy.ShardBudget{
+			Allocation:    privacy.Budget{Epsilon: 1, Delta: 1e-5},
+			PerRound:      privacy.Budget{Epsilon: 1, Delta: 1e-5},
+			L2Sensitivity: 1,
+		}); err != nil {
+			t.Fatalf("register %s: %v", shardID, err)
+		}
+	}
+	global.SetAggregationGate(budgets.BeginGlobalRound)
+	submit := func(round int, nodeIDs ...string) {
+		t.Helper()
+		for _, nodeID := range nodeIDs {
+			if _, err := registry.Bind(nodeID, []string{"global"}); err != nil {
+				t.Fatalf("bind: %v", err)
+			}
+			update := &protocol.ModelUpdate{NodeID: nodeID, Round: round, Weights: batch.Update{Weights: []float64{1}}.Bytes(), Metrics: protocol.Metrics{Samples: 1}}
+			if err := global.Submit(stated(global, update)); err != nil {
+				t.Fatalf("submit: %v", err)
+			}
+		}
+	}
+
+	submit(1, "regional-a")
+	if _, err := global.Aggregate(context.Background(), 1); err != nil {
+		t.Fatalf("aggregate round 1: %v", err)
+	}
+	// regional-a spent its allocation on round 1, so round 2 is refused
+	// without charging regional-b and stays queued.
+	submit(2, "regional-a", "regional-b")
+	if _, err := global.Aggregate(context.Background(), 2); !errors.Is(err, privacy.ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	if pending := global.Pending(2); pending != 2 {
+		t.Fatalf("refused round left %d updates queued, want 2", pending)
+	}
+	state := budgets.State()
+	if state.Shards[0].Rounds != 1 || state.Shards[1].Rounds != 0 {
+		t.Fatalf("shard rounds = %+v", state.Shards)
+	}
+}
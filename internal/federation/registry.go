@@ -0,0 +1,352 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package federation isolates independent federations served by one
+// aggregator host. Each federation gets its own batch aggregator, consensus
+// coordinator, model store, privacy accountant, peer table, and metrics
+// collector; federations share only the transport and the host's crypto
+// identity.
+package federation
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Components are the per-federation state a host keeps. No component is
+// shared between federations.
+type Components struct {
+	Aggregator  *batch.Aggregator
+	Coordinator *consensus.Coordinator
+	ModelStore  *modeldist.ModelStore
+	Privacy     *privacy.DifferentialPrivacy
+	Peers       *p2p.Verifier
+	Metrics     *monitoring.Collector
+	// Probation, when set, puts every node bound to the federation on
+	// probation; see consensus.Probation.
+	Probation *consensus.Probation
+	// CentralDP, when set, noises every aggregate the federation's
+	// aggregator releases; see batch.Aggregator.SetCentralDP.
+	CentralDP *privacy.DPAggregator
+	// Checkpoints, when its Path names a directory, checkpoints each
+	// round's queued updates there under its triggers, so a restarted host
+	// resumes the batches it had not committed.
+	Checkpoints batch.CheckpointPolicy
+	// Participation, when set, settles each closed round against the
+	// upload tiers members declared in their status heartbeats.
+	Participation *scheduler.ParticipationLedger
+	// Capabilities, when set, holds the manifests members registered
+	// with; a round selects only the members able to train the
+	// federation's model. See Federation.Participants.
+	Capabilities *scheduler.CapabilityRegistry
+}
+
+// Factory builds fresh components for the federation id.
+type Factory func(id string) (Components, error)
+
+// Config sizes the components NewFactory builds. Zero fields take the
+// defaults noted on them.
+type Config struct {
+	// HostID is the host's node ID, shared by every federation's
+	// coordinator and peer table.
+	HostID string
+	// Batch is copied into each federation's aggregator.
+	Batch batch.Config
+	// Timeout bounds consensus rounds and verification requests. Default
+	// 10s.
+	Timeout time.Duration
+	// ModelStoreRounds is how many committed rounds each model store
+	// keeps. Default 256.
+	ModelStoreRounds int
+	// MinVerifications is each peer table's verification quorum. Default 3.
+	MinVerifications int
+	// CommitteePolicies, when set, override each peer table's default
+	// verification committee per artifact type.
+	CommitteePolicies map[p2p.ArtifactType]p2p.CommitteePolicy
+	// MetricsHistory is each collector's history length. Default 1024.
+	MetricsHistory int
+	// FaultModel sets every coordinator's quorums and every aggregator's
+	// safety check, validated against Topology. Default Classic33.
+	FaultModel faultmodel.Model
+	Topology   faultmodel.Topology
+	// ModelSpec, when set, is registered in each federation's model store
+	// and checks every update, aggregate, and proposal. Later versions are
+	// adopted with modeldist.ModelStore.ChangeSpec.
+	ModelSpec *protocol.ModelSpec
+	// Signer, when set, signs every round each model store commits. See
+	// modeldist.ModelStore.SetSigner.
+	Signer modeldist.ModelSigner
+	// Validators, when set, must sign every round each model store
+	// commits. See modeldist.ModelStore.SetValidators.
+	Validators *modeldist.Validators
+	// Probation, when set, gives each federation a probation tracker wired
+	// into its coordinator, aggregator, and peer table. Nodes bound to the
+	// federation serve it from their first binding.
+	Probation *consensus.ProbationConfig
+	// CentralDP, when set, gives each federation a DP aggregator of its
+	// own, calibrating noise to each round's actual cohort and refusing
+	// rounds below its minimum cohort.
+	CentralDP *privacy.DPAggregatorConfig
+	// Checkpoints, when its Path names a directory, checkpoints every
+	// federation's queued updates in a subdirectory named for it. See
+	// Components.Checkpoints.
+	Checkpoints batch.CheckpointPolicy
+	// Capabilities, when set, is shared by every federation as its
+	// Components.Capabilities.
+	Capabilities *scheduler.CapabilityRegistry
+	// Detection, when set, runs its plugins over every update each
+	// federation's aggregator sees. See batch.Aggregator.SetDetection.
+	Detection *batch.DetectionConfig
+}
+
+// NewFactory returns a Factory building components sized by cfg, with an
+// SGP-001 privacy accountant per federation.
+func NewFactory(cfg Config) Factory {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.ModelStoreRounds <= 0 {
+		cfg.ModelStoreRounds = 256
+	}
+	if cfg.MinVerifications <= 0 {
+		cfg.MinVerifications = 3
+	}
+	if cfg.MetricsHistory <= 0 {
+		cfg.MetricsHistory = 1024
+	}
+	return func(id string) (Components, error) {
+		batchCfg := cfg.Batch
+		batchCfg.FaultModel = cfg.FaultModel
+		batchCfg.Topology = cfg.Topology
+		coordinator := consensus.NewCoordinator(cfg.HostID, 1, cfg.Timeout)
+		if err := coordinator.SetFaultModel(cfg.FaultModel, cfg.Topology); err != nil {
+			return Components{}, err
+		}
+		store := modeldist.NewModelStore(cfg.ModelStoreRounds)
+		if err := store.SetSigner(cfg.Signer); err != nil {
+			return Components{}, err
+		}
+		if err := store.SetValidators(cfg.Validators); err != nil {
+			return Components{}, err
+		}
+		if cfg.ModelSpec != nil {
+			if err := store.RegisterSpec(*cfg.ModelSpec); err != nil {
+				return Components{}, err
+			}
+		}
+		components := Components{
+			Aggregator:    batch.NewAggregator(&batchCfg),
+			Coordinator:   coordinator,
+			ModelStore:    store,
+			Privacy:       privacy.NewDifferentialPrivacy(privacy.NewSGP001Config()),
+			Peers:         p2p.NewVerifier(cfg.HostID, cfg.MinVerifications, cfg.Timeout),
+			Metrics:       monitoring.NewCollector(cfg.MetricsHistory),
+			Checkpoints:   cfg.Checkpoints,
+			Participation: scheduler.NewParticipationLedger(),
+			Capabilities:  cfg.Capabilities,
+		}
+		components.Aggregator.SetBaseModelResolver(baseDigest(store))
+		components.Aggregator.SetDetection(cfg.Detection)
+		if cfg.Checkpoints.Path != "" {
+			components.Checkpoints.Path = filepath.Join(cfg.Checkpoints.Path, id)
+		}
+		for artifact, policy := range cfg.CommitteePolicies {
+			if err := components.Peers.SetCommitteePolicy(artifact, policy); err != nil {
+				return Components{}, err
+			}
+		}
+		if cfg.Probation != nil {
+			probation, err := consensus.NewProbation(*cfg.Probation)
+			if err != nil {
+				return Components{}, err
+			}
+			components.Probation = probation
+			coordinator.SetProbation(probation)
+			components.Aggregator.SetWeightScale(probation.UpdateWeight)
+			components.Peers.SetProbationCheck(probation.OnProbation)
+		}
+		if cfg.CentralDP != nil {
+			dp, err := privacy.NewDPAggregator(*cfg.CentralDP)
+			if err != nil {
+				return Components{}, err
+			}
+			components.CentralDP = dp
+			components.Aggregator.SetCentralDP(dp)
+			components.Aggregator.SetBaseWeights(baseWeights(store))
+		}
+		return components, nil
+	}
+}
+
+// baseDigest resolves the digest of the global model each round of store's
+// federation trains from, as baseWeights does its weights: before the
+// federation's first commit, the digest of the empty model a round's
+// TrainingTask then distributes.
+func baseDigest(store *modeldist.ModelStore) batch.BaseModelResolver {
+	return func(round int) (string, bool) {
+		if store.LatestRound() == 0 {
+			return protocol.WeightsDigest(nil), true
+		}
+		return store.BaseDigest(round)
+	}
+}
+
+// baseWeights resolves the global model each round of store's federation
+// trains from: the model committed in the round before it, or the zero
+// model before the federation's first commit.
+func baseWeights(store *modeldist.ModelStore) batch.BaseWeightsResolver {
+	return func(round int) ([]float64, bool) {
+		if store.LatestRound() == 0 {
+			return nil, true
+		}
+		encoded, _, ok := store.Model(round - 1)
+		if !ok {
+			return nil, false
+		}
+		weights, err := batch.DecodeWeights(encoded)
+		if err != nil {
+			return nil, false
+		}
+		return weights, true
+	}
+}
+
+// Registry holds the federations a host serves and which nodes are bound to
+// each.
+type Registry struct {
+	factory Factory
+
+	mu          sync.RWMutex
+	federations map[string]*Federation
+}
+
+// NewRegistry creates an empty registry building federations with factory.
+func NewRegistry(factory Factory) *Registry {
+	return &Registry{factory: factory, federations: make(map[string]*Federation)}
+}
+
+// Create builds and serves a federation. Its coordinator takes each
+// proposal's quorum from the federation's members, so votes from nodes
+// bound only to other federations are rejected.
+func (r *Registry) Create(id string) (*Federation, error) {
+	if err := protocol.ValidateFederationID(id); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFederation, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.federations[id]; exists {
+		return nil, fmt.Errorf("%w: %s", ErrFederationExists, id)
+	}
+	components, err := r.factory(id)
+	if err != nil {
+		return nil, fmt.Errorf("build federation %s: %w", id, err)
+	}
+	f, err := newFederation(id, components)
+	if err != nil {
+		return nil, fmt.Errorf("build federation %s: %w", id, err)
+	}
+	r.federations[id] = f
+	return f, nil
+}
+
+// Get returns the federation id; an empty id names DefaultFederation.
+func (r *Registry) Get(id string) (*Federation, error) {
+	id = protocol.FederationOf(id)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, exists := r.federations[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFederation, id)
+	}
+	return f, nil
+}
+
+// Route returns the federation id for a message from nodeID, refusing nodes
+// not bound to it.
+func (r *Registry) Route(id, nodeID string) (*Federation, error) {
+	f, err := r.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !f.IsMember(nodeID) {
+		return nil, fmt.Errorf("%w: node %s in federation %s", ErrNotMember, nodeID, f.ID)
+	}
+	return f, nil
+}
+
+// IDs returns the served federation IDs in sorted order.
+func (r *Registry) IDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.federations))
+	for id := range r.federations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Bind makes nodeID a member of each of ids, or of DefaultFederation when
+// ids is empty. Every federation must exist; on error no binding is made.
+// It returns the federations bound.
+func (r *Registry) Bind(nodeID string, ids []string) ([]string, error) {
+	if nodeID == "" {
+		return nil, fmt.Errorf("%w: empty node id", ErrNotMember)
+	}
+	if len(ids) == 0 {
+		ids = []string{protocol.DefaultFederation}
+	}
+	federations := make([]*Federation, 0, len(ids))
+	for _, id := range ids {
+		f, err := r.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		federations = append(federations, f)
+	}
+	bound := make([]string, 0, len(federations))
+	for _, f := range federations {
+		f.addMember(nodeID)
+		bound = append(bound, f.ID)
+	}
+	sort.Strings(bound)
+	return bound, nil
+}
+
+// Unbind removes nodeID from federation id.
+func (r *Registry) Unbind(nodeID, id string) error {
+	f, err := r.Get(id)
+	if err != nil {
+		return err
+	}
+	f.removeMember(nodeID)
+	return nil
+}
+
+// Memberships returns the federations nodeID is bound to, sorted.
+func (r *Registry) Memberships(nodeID string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var ids []string
+	for id, f := range r.federations {
+		if f.IsMember(nodeID) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
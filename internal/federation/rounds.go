@@ -0,0 +1,205 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package federation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Proposal is the federation's open proposal for a round: the aggregate of
+// the round's queued updates, put to its members' vote.
+type Proposal struct {
+	ID         string `json:"proposal_id"`
+	Round      int    `json:"round"`
+	ProposerID string `json:"proposer_id"`
+	// Weights is the aggregate in the batch.Update encoding.
+	Weights []byte `json:"weights"`
+	// Samples totals the sample counts of the applied updates.
+	Samples  int                            `json:"samples"`
+	Manifest *protocol.ContributionManifest `json:"manifest"`
+	// SpecVersion is the model spec version the aggregate was checked
+	// against, zero without a registered spec.
+	SpecVersion int `json:"spec_version,omitempty"`
+	// Epoch, on a regional shard's proposal, is the shard's membership
+	// epoch; its members approve by also signing QuorumCertificate.
+	Epoch *protocol.MembershipEpochRecord `json:"epoch,omitempty"`
+}
+
+// Propose aggregates the updates queued for round and proposes the result
+// to the federation's coordinator with proposerID's own approval. Members
+// fetch it with Proposal and vote with CastVote; Commit closes it. An
+// aggregate that does not fit the registered model spec is never proposed.
+func (f *Federation) Propose(ctx context.Context, round int, proposerID string) (*Proposal, error) {
+	result, err := f.Aggregate(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	specVersion := 0
+	if spec, ok := f.Spec(); ok {
+		if err := spec.CheckDimension(len(result.Weights)); err != nil {
+			return nil, fmt.Errorf("round %d aggregate: %w", round, err)
+		}
+		specVersion = spec.Version
+	}
+	weights := batch.Update{Weights: result.Weights}.Bytes()
+	now := time.Now()
+	model := &consensus.ModelProposal{Round: round, Weights: weights, ProposerID: proposerID, Timestamp: now}
+	model.AttachManifest(result.Manifest)
+	id, err := f.Coordinator.ProposeModel(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	samples := 0
+	for _, entry := range result.Manifest.Entries {
+		if entry.Included {
+			samples += entry.SampleCount
+		}
+	}
+	proposal := &Proposal{
+		ID:          id,
+		Round:       round,
+		ProposerID:  proposerID,
+		Weights:     weights,
+		Samples:     samples,
+		Manifest:    result.Manifest,
+		SpecVersion: specVersion,
+		Epoch:       f.ShardEpoch(),
+	}
+	approval := &consensus.Vote{NodeID: proposerID, ProposalID: id, Approve: true, Timestamp: now}
+	if signer := f.ModelStore.Signer(); signer != nil {
+		digest := proposal.ApprovalDigest()
+		if approval.CommitSignature, err = signer.SignData(digest[:]); err != nil {
+			f.Coordinator.Reset()
+			return nil, fmt.Errorf("sign approval of round %d: %w", round, err)
+		}
+		if proposal.Epoch != nil {
+			if _, member := proposal.Epoch.Member(proposerID); member {
+				cert := proposal.QuorumCertificate()
+				digest := cert.SigningDigest()
+				if approval.CertificateSignature, err = signer.SignData(digest[:]); err != nil {
+					f.Coordinator.Reset()
+					return nil, fmt.Errorf("sign certificate of round %d: %w", round, err)
+				}
+			}
+		}
+	}
+	if err := f.Coordinator.CastVote(ctx, approval); err != nil {
+		f.Coordinator.Reset()
+		return nil, err
+	}
+	f.mu.Lock()
+	f.open = proposal
+	f.mu.Unlock()
+	return proposal, nil
+}
+
+// ApprovalDigest is what a member signs, as its vote's CommitSignature, to
+// approve committing the proposal.
+func (p *Proposal) ApprovalDigest() [32]byte {
+	return modeldist.ApprovalDigest(p.Round, p.ID, protocol.WeightsDigest(p.Weights), p.Manifest.Digest())
+}
+
+// Proposal returns the open proposal for round.
+func (f *Federation) Proposal(round int) (*Proposal, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.open == nil || f.open.Round != round {
+		return nil, fmt.Errorf("%w: federation %s round %d", ErrNoProposal, f.ID, round)
+	}
+	return f.open, nil
+}
+
+// Commit commits the open proposal for round into the federation's model
+// store under a certificate of its approvals. The proposal is closed
+// whether or not it commits; a proposal short of quorum fails with
+// consensus.ErrQuorumNotReached, and one proposed under a model spec since
+// changed with protocol.ErrModelSpecMismatch. A committed round's batch
+// checkpoints are discarded; failing that, Commit returns the summary with
+// the error.
+func (f *Federation) Commit(ctx context.Context, round int) (modeldist.RoundSummary, error) {
+	proposal, err := f.Proposal(round)
+	if err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	defer func() {
+		f.Coordinator.Reset()
+		f.mu.Lock()
+		if f.open == proposal {
+			f.open = nil
+		}
+		f.mu.Unlock()
+	}()
+
+	if spec, ok := f.Spec(); ok {
+		if err := spec.CheckVersion(proposal.SpecVersion); err != nil {
+			return modeldist.RoundSummary{}, fmt.Errorf("proposal %s: %w", proposal.ID, err)
+		}
+	}
+	if err := f.Coordinator.CommitModel(ctx, proposal.ID); err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	consensusRound, err := f.Coordinator.GetConsensusRound(proposal.ID)
+	if err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	membership, err := f.Coordinator.GetRoundMembership(proposal.ID)
+	if err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	approvals := make([]string, 0, len(consensusRound.ValidatorVotes))
+	var signatures []modeldist.ApprovalSignature
+	for _, vote := range consensusRound.ValidatorVotes {
+		if vote != nil && vote.Approve {
+			approvals = append(approvals, vote.NodeID)
+			if len(vote.CommitSignature) > 0 {
+				signatures = append(signatures, modeldist.ApprovalSignature{NodeID: vote.NodeID, Signature: vote.CommitSignature})
+			}
+		}
+	}
+	cert := modeldist.CommitCertificate{
+		Round:          round,
+		ProposalID:     proposal.ID,
+		ModelDigest:    protocol.WeightsDigest(proposal.Weights),
+		QuorumSize:     membership.QuorumSize,
+		Approvals:      approvals,
+		Signatures:     signatures,
+		ManifestDigest: proposal.Manifest.Digest(),
+	}
+	summary, err := f.ModelStore.CommitAggregate(&protocol.AggregateModel{
+		Round:          round,
+		Weights:        proposal.Weights,
+		Participants:   proposal.Manifest.IncludedNodes(),
+		Timestamp:      time.Now().UTC(),
+		Manifest:       proposal.Manifest,
+		ManifestDigest: proposal.Manifest.Digest(),
+		FederationID:   f.ID,
+	}, cert)
+	if err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	if err := f.ModelStore.AttachManifest(proposal.Manifest); err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	if proposal.Epoch != nil {
+		f.certify(proposal, consensusRound.ValidatorVotes)
+	}
+	if timeline, err := f.Coordinator.VoteTimeline(proposal.ID); err == nil {
+		timeline.Round = round
+		if err := f.ModelStore.AttachVoteTimeline(timeline); err != nil {
+			return modeldist.RoundSummary{}, err
+		}
+		summary.VoteTimeline = &timeline
+	}
+	if err := f.discardCheckpoints(round); err != nil {
+		return summary, fmt.Errorf("round %d committed: %w", round, err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package federation
+
+import (
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// SetShardEpoch makes the federation a regional shard certifying its
+// commits under record, the shard's membership epoch as the global tier
+// committed it: each later proposal carries record, its members sign the
+// proposal's QuorumCertificate with their approvals, and a commit keeps
+// the certificate chain CertificateChain returns. Nil stops certifying.
+func (f *Federation) SetShardEpoch(record *protocol.MembershipEpochRecord) {
+	if record != nil {
+		copied := *record
+		copied.Members = append([]protocol.ShardMember(nil), record.Members...)
+		record = &copied
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.shardEpoch = record
+}
+
+// ShardEpoch returns the membership epoch set by SetShardEpoch, or nil.
+func (f *Federation) ShardEpoch() *protocol.MembershipEpochRecord {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.shardEpoch
+}
+
+// CertificateChain returns the certificate chain of round's commit, signed
+// by the members of the shard epoch it was proposed under who approved it,
+// if round is the latest round committed under a shard epoch.
+func (f *Federation) CertificateChain(round int) (*protocol.CertificateChain, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.certified == nil || f.certified.Certificate.Round != round {
+		return nil, false
+	}
+	chain := *f.certified
+	chain.Certificate.Signatures = append([]protocol.VoterSignature(nil), chain.Certificate.Signatures...)
+	return &chain, true
+}
+
+// QuorumCertificate returns the unsigned certificate of p's commit by the
+// members of its shard epoch. It is empty for a proposal without one.
+func (p *Proposal) QuorumCertificate() protocol.QuorumCertificate {
+	if p.Epoch == nil {
+		return protocol.QuorumCertificate{}
+	}
+	return protocol.QuorumCertificate{
+		ShardID:     p.Epoch.ShardID,
+		Epoch:       p.Epoch.Epoch,
+		EpochDigest: p.Epoch.Digest(),
+		Round:       p.Round,
+		ProposalID:  p.ID,
+		ModelDigest: protocol.WeightsDigest(p.Weights),
+	}
+}
+
+// certify keeps the certificate chain of committed proposal, signed by
+// every approving member of its epoch whose vote carried a certificate
+// signature. The global tier verifies the signatures; a bad one fails the
+// whole chain there, not here.
+func (f *Federation) certify(proposal *Proposal, votes []*consensus.Vote) {
+	cert := proposal.QuorumCertificate()
+	for _, vote := range votes {
+		if vote == nil || !vote.Approve || len(vote.CertificateSignature) == 0 {
+			continue
+		}
+		member, ok := proposal.Epoch.Member(vote.NodeID)
+		if !ok {
+			continue
+		}
+		algorithm, err := crypto.PublicKeyAlgorithm(member.PublicKey)
+		if err != nil {
+			continue
+		}
+		cert.Signatures = append(cert.Signatures, protocol.VoterSignature{NodeID: vote.NodeID, Signature: vote.CertificateSignature, Algorithm: algorithm})
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.certified = &protocol.CertificateChain{Epoch: *proposal.Epoch, Certificate: cert}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package federation
+
+import (
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Spec returns the model spec registered in the federation's model store,
+// or false if there is none.
+func (f *Federation) Spec() (protocol.ModelSpec, bool) {
+	if f.ModelStore == nil {
+		return protocol.ModelSpec{}, false
+	}
+	return f.ModelStore.Spec()
+}
+
+// ChangeSpec adopts the next model spec version under change's consensus
+// record. Updates still queued for the old version fail aggregation, and
+// an open proposal built under it no longer commits.
+func (f *Federation) ChangeSpec(change modeldist.SpecChange) error {
+	return f.ModelStore.ChangeSpec(change)
+}
+
+// SetHyperparameterAdvisor makes TrainingTask carry the learning rate and
+// epochs advisor currently recommends for the federation's shard. nil
+// leaves them to the members.
+func (f *Federation) SetHyperparameterAdvisor(advisor *convergence.HyperparameterAdvisor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.advisor = advisor
+}
+
+// TrainingTask is the task distributed to members for round: the model
+// committed in the previous round, if any, the registered spec whose
+// version their updates must echo, and the advised hyperparameters, if an
+// advisor is set.
+func (f *Federation) TrainingTask(round int) protocol.TrainingTask {
+	task := protocol.TrainingTask{Round: round, FederationID: f.ID}
+	f.mu.RLock()
+	advisor := f.advisor
+	f.mu.RUnlock()
+	if advisor != nil {
+		advisor.Stamp(&task)
+	}
+	if f.ModelStore == nil {
+		return task
+	}
+	if weights, _, ok := f.ModelStore.Model(round - 1); ok {
+		task.GlobalWeights = weights
+	}
+	if spec, ok := f.ModelStore.Spec(); ok {
+		task.Spec = &spec
+	}
+	return task
+}
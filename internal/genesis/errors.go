@@ -0,0 +1,30 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package genesis
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by genesis checks. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrInvalidGenesis means a genesis document is incomplete or
+	// inconsistent: a founder without a key, a threshold the founders
+	// cannot meet, or an invalid model spec, fault model, or budget. Not
+	// retryable.
+	ErrInvalidGenesis = errors.New("invalid genesis")
+	// ErrUnknownFounder means a signature names a node that is not among
+	// the genesis founders. Not retryable with the same signature.
+	ErrUnknownFounder = errors.New("unknown genesis founder")
+	// ErrGenesisSignature means a founder's signature does not verify
+	// against its key over the genesis digest. Not retryable with the same
+	// signature.
+	ErrGenesisSignature = errors.New("genesis signature invalid")
+	// ErrInsufficientSignatures means fewer founders than the threshold
+	// signed the genesis. Retryable once more founders sign.
+	ErrInsufficientSignatures = errors.New("genesis lacks threshold signatures")
+	// ErrGenesisMismatch means a genesis, or a peer's reference to one,
+	// does not match the digest the node pinned: it belongs to another
+	// federation. Not retryable.
+	ErrGenesisMismatch = errors.New("genesis digest mismatch")
+)
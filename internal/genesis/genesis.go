@@ -0,0 +1,333 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package genesis is a federation's shared root of trust. The founding
+// nodes agree on a Genesis document, naming themselves, the model spec,
+// the fault model, the privacy budget, and the shard layout, and a
+// threshold of them sign its digest. Every node pins that digest, refuses
+// to start on any other genesis, and refuses aggregators whose
+// registration responses reference another.
+package genesis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Version is the genesis format version written by New.
+const Version = 1
+
+// digestDomain prefixes the encoding the genesis digest is taken over.
+const digestDomain = "sovereign-genesis/v1"
+
+// Founder is a founding node: its identity key and the capabilities it
+// declared at the ceremony.
+type Founder struct {
+	NodeID string `json:"node_id"`
+	// PublicKey is the founder's PEM identity public key, P-256 or
+	// Ed25519.
+	PublicKey    []byte                       `json:"public_key"`
+	Capabilities *protocol.CapabilityManifest `json:"capabilities,omitempty"`
+}
+
+// Shard is one regional shard of the initial layout.
+type Shard struct {
+	ShardID string `json:"shard_id"`
+	// Aggregator is the shard's regional aggregator.
+	Aggregator string   `json:"aggregator"`
+	Members    []string `json:"members"`
+	// Budget is the shard's privacy accountant; its allocation falls
+	// within the genesis privacy budget.
+	Budget privacy.ShardBudget `json:"budget"`
+}
+
+// Signature is a founder's signature over the genesis digest.
+type Signature struct {
+	NodeID    string               `json:"node_id"`
+	Algorithm protocol.AlgorithmID `json:"algorithm,omitempty"`
+	Signature []byte               `json:"signature"`
+}
+
+// Genesis is the document a federation starts from.
+type Genesis struct {
+	Version      int       `json:"version"`
+	FederationID string    `json:"federation_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Founders     []Founder `json:"founders"`
+	// Threshold is how many founders must sign before the genesis is
+	// valid.
+	Threshold  int                `json:"threshold"`
+	ModelSpec  protocol.ModelSpec `json:"model_spec"`
+	FaultModel faultmodel.Model   `json:"fault_model"`
+	// PrivacyBudget is the guarantee the federation's releases compose
+	// to; shards cover disjoint populations, so each shard's allocation
+	// must fit within it.
+	PrivacyBudget privacy.Budget `json:"privacy_budget"`
+	Shards        []Shard        `json:"shards,omitempty"`
+	// Signatures are not covered by the digest.
+	Signatures []Signature `json:"signatures,omitempty"`
+}
+
+// New returns an unsigned genesis from draft, stamped with the format
+// version and, unless draft has one, the current time. Founders and shards
+// are sorted by ID so every founder computes the same digest.
+func New(draft Genesis) (*Genesis, error) {
+	g := draft
+	g.Version = Version
+	if g.CreatedAt.IsZero() {
+		g.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	g.Founders = append([]Founder(nil), draft.Founders...)
+	sort.Slice(g.Founders, func(i, j int) bool { return g.Founders[i].NodeID < g.Founders[j].NodeID })
+	g.Shards = append([]Shard(nil), draft.Shards...)
+	sort.Slice(g.Shards, func(i, j int) bool { return g.Shards[i].ShardID < g.Shards[j].ShardID })
+	g.Signatures = nil
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// Load reads a genesis document from a JSON file.
+func Load(path string) (*Genesis, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- operator-supplied genesis path
+	if err != nil {
+		return nil, fmt.Errorf("read genesis: %w", err)
+	}
+	var g Genesis
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidGenesis, path, err)
+	}
+	return &g, nil
+}
+
+// Validate checks that the genesis is complete and self-consistent. It
+// does not check signatures; see Verify.
+func (g *Genesis) Validate() error {
+	if g.Version != Version {
+		return fmt.Errorf("%w: format version %d, want %d", ErrInvalidGenesis, g.Version, Version)
+	}
+	if err := protocol.ValidateFederationID(g.FederationID); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	if len(g.Founders) == 0 {
+		return fmt.Errorf("%w: no founders", ErrInvalidGenesis)
+	}
+	seen := make(map[string]bool, len(g.Founders))
+	for _, founder := range g.Founders {
+		if err := founder.validate(); err != nil {
+			return err
+		}
+		if seen[founder.NodeID] {
+			return fmt.Errorf("%w: founder %s is listed twice", ErrInvalidGenesis, founder.NodeID)
+		}
+		seen[founder.NodeID] = true
+	}
+	if g.Threshold < 1 || g.Threshold > len(g.Founders) {
+		return fmt.Errorf("%w: threshold %d of %d founders", ErrInvalidGenesis, g.Threshold, len(g.Founders))
+	}
+	if err := g.ModelSpec.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	model, err := faultmodel.Parse(string(g.FaultModel))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	if err := model.Validate(g.Topology()); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	if err := g.PrivacyBudget.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	if _, err := g.BudgetRegistry(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	for _, shard := range g.Shards {
+		switch {
+		case shard.Aggregator == "":
+			return fmt.Errorf("%w: shard %s has no aggregator", ErrInvalidGenesis, shard.ShardID)
+		case len(shard.Members) == 0:
+			return fmt.Errorf("%w: shard %s has no members", ErrInvalidGenesis, shard.ShardID)
+		case shard.Budget.Allocation.Epsilon > g.PrivacyBudget.Epsilon || shard.Budget.Allocation.Delta > g.PrivacyBudget.Delta:
+			return fmt.Errorf("%w: shard %s allocation exceeds the federation's privacy budget", ErrInvalidGenesis, shard.ShardID)
+		}
+	}
+	return nil
+}
+
+func (f Founder) validate() error {
+	if f.NodeID == "" {
+		return fmt.Errorf("%w: founder without a node ID", ErrInvalidGenesis)
+	}
+	if _, err := crypto.PublicKeyAlgorithm(f.PublicKey); err != nil {
+		return fmt.Errorf("%w: founder %s key: %w", ErrInvalidGenesis, f.NodeID, err)
+	}
+	if f.Capabilities == nil {
+		return nil
+	}
+	if f.Capabilities.NodeID != f.NodeID {
+		return fmt.Errorf("%w: founder %s declares the capabilities of %s", ErrInvalidGenesis, f.NodeID, f.Capabilities.NodeID)
+	}
+	if err := f.Capabilities.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	return nil
+}
+
+// Topology is the aggregation topology the shard layout implies: multi-tier
+// when there are regional shards.
+func (g *Genesis) Topology() faultmodel.Topology {
+	return faultmodel.Topology{MultiTier: len(g.Shards) > 0}
+}
+
+// Regions places every shard's aggregator and members in a region named
+// after the shard, for deriving regional round budgets. A node listed in
+// more than one shard is placed in the first by shard ID.
+func (g *Genesis) Regions() map[string]string {
+	regions := make(map[string]string)
+	for _, shard := range g.Shards {
+		for _, nodeID := range append([]string{shard.Aggregator}, shard.Members...) {
+			if _, placed := regions[nodeID]; !placed && nodeID != "" {
+				regions[nodeID] = shard.ShardID
+			}
+		}
+	}
+	return regions
+}
+
+// BudgetRegistry returns a fresh privacy accountant for every shard of the
+// layout.
+func (g *Genesis) BudgetRegistry() (*privacy.BudgetRegistry, error) {
+	registry := privacy.NewBudgetRegistry()
+	for _, shard := range g.Shards {
+		if err := registry.Register(shard.ShardID, shard.Budget); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// signingDigest is the SHA-256 of the domain followed by the genesis's
+// JSON encoding without its signatures.
+func (g *Genesis) signingDigest() [32]byte {
+	unsigned := *g
+	unsigned.Signatures = nil
+	encoded, _ := json.Marshal(unsigned)
+	return sha256.Sum256(append([]byte(digestDomain), encoded...))
+}
+
+// Digest is the hex genesis digest nodes pin. Founders sign it; adding
+// signatures does not change it.
+func (g *Genesis) Digest() string {
+	digest := g.signingDigest()
+	return hex.EncodeToString(digest[:])
+}
+
+// Signer is a founder's identity, such as crypto.SecureChannel.
+type Signer interface {
+	SignData(data []byte) ([]byte, error)
+	Algorithm() protocol.AlgorithmID
+}
+
+// Sign adds founder nodeID's signature over the digest, replacing any it
+// made before.
+func (g *Genesis) Sign(nodeID string, signer Signer) error {
+	if _, ok := g.founder(nodeID); !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownFounder, nodeID)
+	}
+	digest := g.signingDigest()
+	signature, err := signer.SignData(digest[:])
+	if err != nil {
+		return fmt.Errorf("sign genesis as %s: %w", nodeID, err)
+	}
+	signatures := g.Signatures[:0:0]
+	for _, existing := range g.Signatures {
+		if existing.NodeID != nodeID {
+			signatures = append(signatures, existing)
+		}
+	}
+	g.Signatures = append(signatures, Signature{NodeID: nodeID, Algorithm: signer.Algorithm(), Signature: signature})
+	sort.Slice(g.Signatures, func(i, j int) bool { return g.Signatures[i].NodeID < g.Signatures[j].NodeID })
+	return nil
+}
+
+// Signers returns the founders whose signatures verify, failing on any
+// signature that does not.
+func (g *Genesis) Signers() ([]string, error) {
+	digest := g.signingDigest()
+	var signers []string
+	seen := make(map[string]bool, len(g.Signatures))
+	for _, signature := range g.Signatures {
+		founder, ok := g.founder(signature.NodeID)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFounder, signature.NodeID)
+		}
+		algorithm, err := crypto.PublicKeyAlgorithm(founder.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: founder %s key: %w", ErrInvalidGenesis, founder.NodeID, err)
+		}
+		if err := protocol.CheckAlgorithm(signature.Algorithm, algorithm); err != nil {
+			return nil, fmt.Errorf("%w: founder %s: %w", ErrGenesisSignature, founder.NodeID, err)
+		}
+		if err := crypto.VerifyWithPublicKey(founder.PublicKey, digest[:], signature.Signature); err != nil {
+			return nil, fmt.Errorf("%w: founder %s: %w", ErrGenesisSignature, founder.NodeID, err)
+		}
+		if !seen[founder.NodeID] {
+			seen[founder.NodeID] = true
+			signers = append(signers, founder.NodeID)
+		}
+	}
+	return signers, nil
+}
+
+// Verify checks that the genesis is valid, that its digest is pinned, and
+// that at least Threshold founders signed it. An empty pinned digest
+// skips the pin check, for the ceremony itself.
+func (g *Genesis) Verify(pinned string) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	if err := Check(pinned, g.Digest()); err != nil {
+		return err
+	}
+	signers, err := g.Signers()
+	if err != nil {
+		return err
+	}
+	if len(signers) < g.Threshold {
+		return fmt.Errorf("%w: %d of %d founders signed, %d required", ErrInsufficientSignatures, len(signers), len(g.Founders), g.Threshold)
+	}
+	return nil
+}
+
+func (g *Genesis) founder(nodeID string) (Founder, bool) {
+	for _, founder := range g.Founders {
+		if founder.NodeID == nodeID {
+			return founder, true
+		}
+	}
+	return Founder{}, false
+}
+
+// Check fails with ErrGenesisMismatch unless digest, a genesis digest a
+// peer presented or referenced, is the pinned one. A node that pins no
+// digest accepts any.
+func Check(pinned, digest string) error {
+	if pinned == "" || pinned == digest {
+		return nil
+	}
+	if digest == "" {
+		return fmt.Errorf("%w: pinned %s, peer references none", ErrGenesisMismatch, pinned)
+	}
+	return fmt.Errorf("%w: pinned %s, got %s", ErrGenesisMismatch, pinned, digest)
+}
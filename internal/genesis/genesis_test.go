@@ -0,0 +1,205 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package genesis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// ceremony is a three-founder genesis, one founder on Ed25519, with each
+// founder's identity.
+func ceremony(t *testing.T) (*Genesis, map[string]*crypto.SecureChannel) {
+	t.Helper()
+	identities := make(map[string]*crypto.SecureChannel)
+	var founders []Founder
+	for i, algorithm := range []protocol.AlgorithmID{protocol.AlgorithmECDSAP256, protocol.AlgorithmEd25519, protocol.AlgorithmECDSAP256} {
+		nodeID := fmt.Sprintf("founder-%d", 3-i)
+		identity, err := crypto.NewSecureChannelWithAlgorithm(algorithm)
+		if err != nil {
+			t.Fatalf("identity: %v", err)
+		}
+		publicKey, err := identity.ExportPublicKey()
+		if err != nil {
+			t.Fatalf("export key: %v", err)
+		}
+		identities[nodeID] = identity
+		founders = append(founders, Founder{
+			NodeID:       nodeID,
+			PublicKey:    publicKey,
+			Capabilities: &protocol.CapabilityManifest{NodeID: nodeID, CPUClass: protocol.CPUClassFor(4), CPUCount: 4, MemoryBytes: 8 << 30},
+		})
+	}
+	shardBudget := privacy.ShardBudget{
+		Allocation:    privacy.Budget{Epsilon: 4, Delta: 1e-5},
+		PerRound:      privacy.Budget{Epsilon: 0.1, Delta: 1e-7},
+		L2Sensitivity: 1,
+	}
+	g, err := New(Genesis{
+		FederationID: "traffic",
+		CreatedAt:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Founders:     founders,
+		Threshold:    2,
+		ModelSpec: protocol.ModelSpec{
+			Name: "mlp", Version: 1, Dimension: 4, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp")),
+		},
+		FaultModel:    faultmodel.Hierarchical55,
+		PrivacyBudget: privacy.Budget{Epsilon: 8, Delta: 1e-5},
+		Shards: []Shard{
+			{ShardID: "west", Aggregator: "founder-2", Members: []string{"edge-3", "edge-4"}, Budget: shardBudget},
+			{ShardID: "east", Aggregator: "founder-1", Members: []string{"edge-1", "edge-2"}, Budget: shardBudget},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new genesis: %v", err)
+	}
+	return g, identities
+}
+
+func TestNewGenesisIsCanonical(t *testing.T) {
+	g, _ := ceremony(t)
+	if g.Version != Version || g.Founders[0].NodeID != "founder-1" || g.Shards[0].ShardID != "east" {
+		t.Fatalf("genesis not normalized: version %d, founders %s.., shards %s..", g.Version, g.Founders[0].NodeID, g.Shards[0].ShardID)
+	}
+	if !g.Topology().MultiTier {
+		t.Fatal("a genesis with shards must imply a multi-tier topology")
+	}
+	if regions := g.Regions(); len(regions) != 6 || regions["founder-1"] != "east" || regions["edge-4"] != "west" {
+		t.Fatalf("regions = %v", regions)
+	}
+	budgets, err := g.BudgetRegistry()
+	if err != nil || len(budgets.State().Shards) != 2 {
+		t.Fatalf("budget registry: %+v, %v", budgets, err)
+	}
+
+	// The digest survives a round trip through the file every founder
+	// receives.
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	encoded, _ := json.MarshalIndent(g, "", "  ")
+	if err := os.WriteFile(path, encoded, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Digest() != g.Digest() || len(g.Digest()) != 64 {
+		t.Fatalf("digest changed across a round trip: %s, %s", loaded.Digest(), g.Digest())
+	}
+
+	renamed := *g
+	renamed.FederationID = "parking"
+	if renamed.Digest() == g.Digest() {
+		t.Fatal("digest does not cover the federation")
+	}
+}
+
+func TestGenesisRejectsInconsistentDrafts(t *testing.T) {
+	g, _ := ceremony(t)
+	for name, mutate := range map[string]func(*Genesis){
+		"no founders":          func(d *Genesis) { d.Founders = nil },
+		"duplicate founder":    func(d *Genesis) { d.Founders = append(d.Founders, d.Founders[0]) },
+		"unparsable key":       func(d *Genesis) { d.Founders[0].PublicKey = []byte("not a key") },
+		"foreign capabilities": func(d *Genesis) { d.Founders[0].Capabilities.NodeID = "founder-9" },
+		"threshold too high":   func(d *Genesis) { d.Threshold = 4 },
+		"no threshold":         func(d *Genesis) { d.Threshold = 0 },
+		"invalid spec":         func(d *Genesis) { d.ModelSpec.Dimension = 0 },
+		"flat hierarchical":    func(d *Genesis) { d.Shards = nil },
+		"overspent shard":      func(d *Genesis) { d.PrivacyBudget.Epsilon = 2 },
+		"shard without nodes":  func(d *Genesis) { d.Shards[0].Members = nil },
+		"no budget":            func(d *Genesis) { d.PrivacyBudget = privacy.Budget{} },
+	} {
+		draft := *g
+		draft.Founders = append([]Founder(nil), g.Founders...)
+		capabilities := *g.Founders[0].Capabilities
+		draft.Founders[0].Capabilities = &capabilities
+		draft.Shards = append([]Shard(nil), g.Shards...)
+		mutate(&draft)
+		if _, err := New(draft); !errors.Is(err, ErrInvalidGenesis) {
+			t.Fatalf("%s: expected ErrInvalidGenesis, got %v", name, err)
+		}
+	}
+}
+
+func TestGenesisNeedsThresholdFounderSignatures(t *testing.T) {
+	g, identities := ceremony(t)
+	digest := g.Digest()
+
+	if err := g.Sign("founder-1", identities["founder-1"]); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	// Signing twice replaces the founder's signature rather than counting
+	// it again.
+	if err := g.Sign("founder-1", identities["founder-1"]); err != nil {
+		t.Fatalf("re-sign: %v", err)
+	}
+	if err := g.Verify(digest); !errors.Is(err, ErrInsufficientSignatures) {
+		t.Fatalf("one of two signatures: expected ErrInsufficientSignatures, got %v", err)
+	}
+	if err := g.Sign("founder-2", identities["founder-2"]); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if g.Digest() != digest {
+		t.Fatal("signing changed the digest")
+	}
+	if err := g.Verify(digest); err != nil {
+		t.Fatalf("two Ed25519 and P-256 signatures of two required: %v", err)
+	}
+	if signers, err := g.Signers(); err != nil || len(signers) != 2 {
+		t.Fatalf("signers = %v, %v", signers, err)
+	}
+
+	if err := g.Sign("founder-9", identities["founder-3"]); !errors.Is(err, ErrUnknownFounder) {
+		t.Fatalf("outsider: expected ErrUnknownFounder, got %v", err)
+	}
+	forged := *g
+	forged.Signatures = append([]Signature(nil), g.Signatures...)
+	forged.Signatures[0].NodeID = "founder-3"
+	if err := forged.Verify(digest); !errors.Is(err, ErrGenesisSignature) {
+		t.Fatalf("signature claimed by another founder: expected ErrGenesisSignature, got %v", err)
+	}
+}
+
+func TestNodeRejectsMismatchedGenesis(t *testing.T) {
+	g, identities := ceremony(t)
+	for _, nodeID := range []string{"founder-1", "founder-3"} {
+		if err := g.Sign(nodeID, identities[nodeID]); err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+	}
+	pinned := g.Digest()
+
+	// A genesis altered after signing no longer has the pinned digest,
+	// and its signatures no longer cover it.
+	altered := *g
+	altered.PrivacyBudget.Epsilon = 80
+	if err := altered.Verify(pinned); !errors.Is(err, ErrGenesisMismatch) {
+		t.Fatalf("altered genesis: expected ErrGenesisMismatch, got %v", err)
+	}
+	if err := altered.Verify(""); !errors.Is(err, ErrGenesisSignature) {
+		t.Fatalf("altered genesis, unpinned: expected ErrGenesisSignature, got %v", err)
+	}
+
+	if err := Check(pinned, pinned); err != nil {
+		t.Fatalf("pinned digest: %v", err)
+	}
+	if err := Check("", altered.Digest()); err != nil {
+		t.Fatalf("a node that pins nothing accepts any genesis: %v", err)
+	}
+	for _, referenced := range []string{altered.Digest(), ""} {
+		if err := Check(pinned, referenced); !errors.Is(err, ErrGenesisMismatch) {
+			t.Fatalf("reference %q: expected ErrGenesisMismatch, got %v", referenced, err)
+		}
+	}
+}
@@ -0,0 +1,489 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package island
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultCheckpointInterval is how many archived snapshots a segment holds
+// before it is sealed with a checkpoint.
+const DefaultCheckpointInterval = 1024
+
+const (
+	segmentPattern  = "segment-*.jsonl"
+	segmentFormat   = "segment-%012d.jsonl"
+	checkpointsFile = "checkpoints.jsonl"
+	// maxArchiveLine bounds one archived record; snapshots are small, so a
+	// longer line means the file is damaged.
+	maxArchiveLine = 1 << 20
+)
+
+// ArchiveCheckpoint seals an archive segment. Cumulative folds the hash of
+// every snapshot from the first archived one through Seq, so a checkpoint
+// lets the chain after it be verified once the segments before it are
+// pruned.
+type ArchiveCheckpoint struct {
+	// Seq is the 1-based position in the archive of the last snapshot the
+	// checkpoint covers.
+	Seq        int       `json:"seq"`
+	Hash       string    `json:"hash"`
+	Cumulative string    `json:"cumulative"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// ChainReport describes a VerifyFullChain run.
+type ChainReport struct {
+	// Verified counts the snapshots whose hash and link were checked, from
+	// the archive and from memory.
+	Verified int `json:"verified"`
+	// Archived counts the snapshots read from archive segments.
+	Archived int `json:"archived"`
+	// Pruned counts the snapshots whose segments were pruned; they are
+	// covered only by the checkpoint the verification anchored on.
+	Pruned      int    `json:"pruned"`
+	Segments    int    `json:"segments"`
+	Checkpoints int    `json:"checkpoints"`
+	Head        string `json:"head"`
+}
+
+// archiveRecord is one line of a segment: a snapshot or, last, the
+// checkpoint sealing it.
+type archiveRecord struct {
+	Snapshot   *StateSnapshot     `json:"snapshot,omitempty"`
+	Checkpoint *ArchiveCheckpoint `json:"checkpoint,omitempty"`
+}
+
+// chainCursor tracks a walk along the archived chain.
+type chainCursor struct {
+	seq        int
+	hash       string
+	cumulative string
+}
+
+// advance checks that snapshot hashes correctly and links to the cursor,
+// then moves the cursor past it. The first snapshot of an archive may link
+// to anything: the chain can predate archiving.
+func (c *chainCursor) advance(sm *StateManager, snapshot *StateSnapshot) error {
+	computed, err := sm.computeHash(snapshot)
+	if err != nil {
+		return fmt.Errorf("%w: snapshot %d: %v", ErrArchiveCorrupt, c.seq+1, err)
+	}
+	if computed != snapshot.Hash {
+		return fmt.Errorf("%w: hash mismatch at snapshot %d", ErrArchiveCorrupt, c.seq+1)
+	}
+	if c.seq > 0 && snapshot.PreviousHash != c.hash {
+		return fmt.Errorf("%w: chain broken at snapshot %d", ErrArchiveCorrupt, c.seq+1)
+	}
+	c.seq++
+	c.hash = snapshot.Hash
+	c.cumulative = cumulativeHash(c.cumulative, snapshot.Hash)
+	return nil
+}
+
+// checkpoint returns the checkpoint sealing the chain at the cursor.
+func (c *chainCursor) checkpoint(timestamp time.Time) ArchiveCheckpoint {
+	return ArchiveCheckpoint{Seq: c.seq, Hash: c.hash, Cumulative: c.cumulative, Timestamp: timestamp}
+}
+
+// matches reports whether checkpoint seals the chain at the cursor.
+func (c *chainCursor) matches(checkpoint ArchiveCheckpoint) bool {
+	return checkpoint.Seq == c.seq && checkpoint.Hash == c.hash && checkpoint.Cumulative == c.cumulative
+}
+
+// cumulativeHash folds hash into the running cumulative hash previous.
+func cumulativeHash(previous, hash string) string {
+	sum := sha256.Sum256([]byte(previous + hash))
+	return hex.EncodeToString(sum[:])
+}
+
+// snapshotArchive appends snapshots leaving memory to segment files in dir.
+// Segments are named for the position of their first snapshot and are
+// append-only; each is sealed by a checkpoint, which is also appended to
+// the checkpoints file that survives pruning. The StateManager's lock
+// guards it.
+type snapshotArchive struct {
+	dir      string
+	interval int
+	cursor   chainCursor
+	segment  *os.File
+	inOpen   int
+}
+
+// openArchive opens the archive in dir, creating it if needed, and
+// resumes after its last snapshot.
+func openArchive(sm *StateManager, dir string, interval int) (*snapshotArchive, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	a := &snapshotArchive{dir: dir, interval: interval}
+	segments, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return a, nil
+	}
+	checkpoints, err := readCheckpoints(dir)
+	if err != nil {
+		return nil, err
+	}
+	last := segments[len(segments)-1]
+	if a.cursor, err = anchor(last.first, checkpoints); err != nil {
+		return nil, err
+	}
+	sealed := false
+	err = readSegment(last.path, func(record archiveRecord) error {
+		switch {
+		case sealed:
+			return fmt.Errorf("%w: %s continues past its checkpoint", ErrArchiveCorrupt, last.path)
+		case record.Snapshot != nil:
+			a.inOpen++
+			return a.cursor.advance(sm, record.Snapshot)
+		case record.Checkpoint != nil:
+			sealed = true
+			return nil
+		default:
+			return fmt.Errorf("%w: empty record in %s", ErrArchiveCorrupt, filepath.Base(last.path))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !sealed {
+		// #nosec G304 -- path is built from the configured archive directory
+		if a.segment, err = os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0600); err != nil {
+			return nil, fmt.Errorf("failed to reopen archive segment: %w", err)
+		}
+	} else {
+		a.inOpen = 0
+	}
+	return a, nil
+}
+
+// append archives snapshot, sealing the segment once it is full.
+func (a *snapshotArchive) append(sm *StateManager, snapshot StateSnapshot) error {
+	if a.cursor.seq > 0 && snapshot.PreviousHash != a.cursor.hash {
+		return fmt.Errorf("%w: round %d links to %.12s, archive ends at %.12s", ErrArchiveDiscontinuity, snapshot.Round, snapshot.PreviousHash, a.cursor.hash)
+	}
+	if a.segment == nil {
+		path := filepath.Join(a.dir, fmt.Sprintf(segmentFormat, a.cursor.seq+1))
+		segment, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open archive segment: %w", err)
+		}
+		a.segment = segment
+	}
+	cursor := a.cursor
+	if err := cursor.advance(sm, &snapshot); err != nil {
+		return err
+	}
+	if err := appendRecord(a.segment, archiveRecord{Snapshot: &snapshot}); err != nil {
+		return fmt.Errorf("failed to archive snapshot: %w", err)
+	}
+	a.cursor = cursor
+	a.inOpen++
+	if a.inOpen < a.interval {
+		return nil
+	}
+
+	checkpoint := a.cursor.checkpoint(snapshot.Timestamp)
+	if err := appendRecord(a.segment, archiveRecord{Checkpoint: &checkpoint}); err != nil {
+		return fmt.Errorf("failed to seal archive segment: %w", err)
+	}
+	if err := a.segment.Sync(); err != nil {
+		return fmt.Errorf("failed to seal archive segment: %w", err)
+	}
+	index, err := os.OpenFile(filepath.Join(a.dir, checkpointsFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to record checkpoint: %w", err)
+	}
+	defer index.Close()
+	if err := appendRecord(index, checkpoint); err != nil {
+		return fmt.Errorf("failed to record checkpoint: %w", err)
+	}
+	if err := index.Sync(); err != nil {
+		return fmt.Errorf("failed to record checkpoint: %w", err)
+	}
+	err = a.segment.Close()
+	a.segment, a.inOpen = nil, 0
+	return err
+}
+
+// close closes the open segment, if any.
+func (a *snapshotArchive) close() error {
+	if a.segment == nil {
+		return nil
+	}
+	err := a.segment.Close()
+	a.segment = nil
+	return err
+}
+
+// SetArchive makes the manager archive snapshots leaving its in-memory
+// window to append-only segments in dir, sealing a segment with a
+// checkpoint every checkpointInterval snapshots (DefaultCheckpointInterval
+// when not positive). An existing archive is resumed, and a manager with no
+// snapshots in memory links its next snapshot to the archive's last. An
+// empty dir stops archiving.
+func (sm *StateManager) SetArchive(dir string, checkpointInterval int) error {
+	if checkpointInterval <= 0 {
+		checkpointInterval = DefaultCheckpointInterval
+	}
+	var archive *snapshotArchive
+	if dir != "" {
+		var err error
+		if archive, err = openArchive(sm, dir, checkpointInterval); err != nil {
+			return err
+		}
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.archive != nil {
+		if err := sm.archive.close(); err != nil {
+			return err
+		}
+	}
+	sm.archive = archive
+	return nil
+}
+
+// VerifyFullChain verifies the chain from the archive in archiveDir through
+// the in-memory snapshots: every archived snapshot's hash and link, every
+// checkpoint against the cumulative hash, and the link from the archive to
+// the first snapshot in memory. Segments pruned from the front of the
+// archive are bridged by the checkpoint that sealed the last of them.
+func (sm *StateManager) VerifyFullChain(archiveDir string) (ChainReport, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	var report ChainReport
+	segments, err := listSegments(archiveDir)
+	if err != nil {
+		return report, err
+	}
+	checkpoints, err := readCheckpoints(archiveDir)
+	if err != nil {
+		return report, err
+	}
+	// Start at the first remaining segment or, with every segment pruned,
+	// after the last checkpoint.
+	start := 1
+	if len(segments) > 0 {
+		start = segments[0].first
+	} else {
+		for seq := range checkpoints {
+			start = max(start, seq+1)
+		}
+	}
+	cursor, err := anchor(start, checkpoints)
+	if err != nil {
+		return report, err
+	}
+	report.Pruned = cursor.seq
+	for i, segment := range segments {
+		if segment.first != cursor.seq+1 {
+			return report, fmt.Errorf("%w: expected a segment starting at %d, found %s", ErrArchiveGap, cursor.seq+1, filepath.Base(segment.path))
+		}
+		sealed := false
+		err := readSegment(segment.path, func(record archiveRecord) error {
+			switch {
+			case sealed:
+				return fmt.Errorf("%w: %s continues past its checkpoint", ErrArchiveCorrupt, filepath.Base(segment.path))
+			case record.Snapshot != nil:
+				report.Archived++
+				return cursor.advance(sm, record.Snapshot)
+			case record.Checkpoint != nil:
+				sealed = true
+				indexed, ok := checkpoints[record.Checkpoint.Seq]
+				if !cursor.matches(*record.Checkpoint) || !ok || !cursor.matches(indexed) || !indexed.Timestamp.Equal(record.Checkpoint.Timestamp) {
+					return fmt.Errorf("%w: checkpoint at snapshot %d does not match the chain", ErrArchiveCorrupt, cursor.seq)
+				}
+				report.Checkpoints++
+				return nil
+			default:
+				return fmt.Errorf("%w: empty record in %s", ErrArchiveCorrupt, filepath.Base(segment.path))
+			}
+		})
+		if err != nil {
+			return report, err
+		}
+		if !sealed && i < len(segments)-1 {
+			return report, fmt.Errorf("%w: %s was not sealed", ErrArchiveCorrupt, filepath.Base(segment.path))
+		}
+		report.Segments++
+	}
+
+	if err := sm.verifySnapshots(sm.snapshots); err != nil {
+		return report, err
+	}
+	if len(sm.snapshots) > 0 && cursor.seq > 0 && sm.snapshots[0].PreviousHash != cursor.hash {
+		return report, fmt.Errorf("%w: in-memory chain does not follow archived snapshot %d", ErrArchiveGap, cursor.seq)
+	}
+	report.Verified = report.Archived + len(sm.snapshots)
+	report.Head = cursor.hash
+	if len(sm.snapshots) > 0 {
+		report.Head = sm.snapshots[len(sm.snapshots)-1].Hash
+	}
+	return report, nil
+}
+
+// PruneArchive removes sealed segments from the front of the archive whose
+// newest snapshot is older than maxAge and returns how many it removed.
+// Their checkpoints stay in the checkpoints file, so VerifyFullChain still
+// anchors the remaining chain. The open segment is never pruned.
+func (sm *StateManager) PruneArchive(maxAge time.Duration) (int, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.archive == nil {
+		return 0, nil
+	}
+	segments, err := listSegments(sm.archive.dir)
+	if err != nil {
+		return 0, err
+	}
+	checkpoints, err := readCheckpoints(sm.archive.dir)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-maxAge)
+	pruned := 0
+	for i, segment := range segments {
+		if i == len(segments)-1 && sm.archive.segment != nil {
+			break
+		}
+		// A segment is sealed by the checkpoint just before the next one
+		// starts, or at the archive's end.
+		end := sm.archive.cursor.seq
+		if i+1 < len(segments) {
+			end = segments[i+1].first - 1
+		}
+		checkpoint, ok := checkpoints[end]
+		if !ok || !checkpoint.Timestamp.Before(cutoff) {
+			break
+		}
+		if err := os.Remove(segment.path); err != nil {
+			return pruned, fmt.Errorf("failed to prune archive segment: %w", err)
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// segmentFile is an archive segment and the position of its first
+// snapshot.
+type segmentFile struct {
+	path  string
+	first int
+}
+
+// listSegments returns dir's segments in chain order. A missing dir holds
+// none.
+func listSegments(dir string) ([]segmentFile, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, segmentPattern))
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]segmentFile, 0, len(paths))
+	for _, path := range paths {
+		var first int
+		if _, err := fmt.Sscanf(filepath.Base(path), segmentFormat, &first); err != nil || first < 1 {
+			return nil, fmt.Errorf("%w: unexpected segment %s", ErrArchiveCorrupt, filepath.Base(path))
+		}
+		segments = append(segments, segmentFile{path: path, first: first})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].first < segments[j].first })
+	return segments, nil
+}
+
+// readCheckpoints reads dir's checkpoints file, keyed by Seq. A missing
+// file holds none.
+func readCheckpoints(dir string) (map[int]ArchiveCheckpoint, error) {
+	checkpoints := make(map[int]ArchiveCheckpoint)
+	err := readLines(filepath.Join(dir, checkpointsFile), func(line []byte) error {
+		var checkpoint ArchiveCheckpoint
+		if err := json.Unmarshal(line, &checkpoint); err != nil {
+			return err
+		}
+		checkpoints[checkpoint.Seq] = checkpoint
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoints, nil
+	}
+	return checkpoints, err
+}
+
+// anchor returns the cursor a walk starting at snapshot first resumes
+// from: the start of the archive, or the checkpoint sealing the segment
+// before first.
+func anchor(first int, checkpoints map[int]ArchiveCheckpoint) (chainCursor, error) {
+	if first == 1 {
+		return chainCursor{}, nil
+	}
+	checkpoint, ok := checkpoints[first-1]
+	if !ok {
+		return chainCursor{}, fmt.Errorf("%w: no checkpoint before snapshot %d", ErrArchiveGap, first)
+	}
+	return chainCursor{seq: checkpoint.Seq, hash: checkpoint.Hash, cumulative: checkpoint.Cumulative}, nil
+}
+
+// readSegment streams the records of the segment at path to fn.
+func readSegment(path string, fn func(archiveRecord) error) error {
+	return readLines(path, func(line []byte) error {
+		var record archiveRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return err
+		}
+		return fn(record)
+	})
+}
+
+// readLines streams the lines of path to fn. Decoding failures are
+// reported as ErrArchiveCorrupt; errors fn returns pass through.
+func readLines(path string, fn func([]byte) error) error {
+	// #nosec G304 -- path is built from the archive directory
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxArchiveLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if err := fn(scanner.Bytes()); err != nil {
+			var syntax *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntax) || errors.As(err, &typeErr) {
+				return fmt.Errorf("%w: %s line %d: %v", ErrArchiveCorrupt, filepath.Base(path), line, err)
+			}
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrArchiveCorrupt, filepath.Base(path), err)
+	}
+	return nil
+}
+
+// appendRecord writes v to f as one JSON line.
+func appendRecord(f *os.File, v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,218 @@
+package island
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// archivingState returns a manager keeping window snapshots in memory and
+// archiving the rest to dir.
+func archivingState(t *testing.T, dir string, window, interval int) *StateManager {
+	t.Helper()
+	sm := NewStateManager(window)
+	if err := sm.SetArchive(dir, interval); err != nil {
+		t.Fatalf("set archive: %v", err)
+	}
+	t.Cleanup(func() { _ = sm.SetArchive("", 0) })
+	return sm
+}
+
+func createSnapshots(t *testing.T, sm *StateManager, from, count int) {
+	t.Helper()
+	for round := from; round < from+count; round++ {
+		metadata := map[string]interface{}{"peers": round % 7, "mode": "island"}
+		if _, err := sm.CreateSnapshot(round, fmt.Sprintf("model-%d", round), round%13, metadata); err != nil {
+			t.Fatalf("snapshot %d: %v", round, err)
+		}
+	}
+}
+
+// rewriteSegmentLine replaces line n of the segment at path with what edit
+// makes of its record.
+func rewriteSegmentLine(t *testing.T, path string, n int, edit func(*archiveRecord)) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read segment: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	var record archiveRecord
+	if err := json.Unmarshal([]byte(lines[n]), &record); err != nil {
+		t.Fatalf("decode line %d: %v", n, err)
+	}
+	edit(&record)
+	line, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("encode line %d: %v", n, err)
+	}
+	lines[n] = string(line)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatalf("write segment: %v", err)
+	}
+}
+
+func TestVerifyFullChainFromArchive(t *testing.T) {
+	dir := t.TempDir()
+	sm := archivingState(t, dir, 16, 500)
+	createSnapshots(t, sm, 1, 10000)
+
+	if ok, err := sm.VerifyChain(); !ok || err != nil {
+		t.Fatalf("in-memory chain: %v", err)
+	}
+	report, err := sm.VerifyFullChain(dir)
+	if err != nil {
+		t.Fatalf("verify full chain: %v", err)
+	}
+	if report.Verified != 10000 || report.Archived != 9984 || report.Pruned != 0 || report.Segments != 20 || report.Checkpoints != 19 {
+		t.Fatalf("report = %+v", report)
+	}
+	if report.Head != sm.GetLatestSnapshot().Hash {
+		t.Fatalf("head %s, latest snapshot %s", report.Head, sm.GetLatestSnapshot().Hash)
+	}
+
+	// A restarted node resumes the archive: its restored window keeps
+	// extending the chain on disk.
+	tail := sm.GetSnapshots()
+	if err := sm.SetArchive("", 0); err != nil {
+		t.Fatalf("close archive: %v", err)
+	}
+	restarted := archivingState(t, dir, 16, 500)
+	if err := restarted.RestoreSnapshots(tail); err != nil {
+		t.Fatalf("restore window: %v", err)
+	}
+	createSnapshots(t, restarted, 10001, 600)
+	if report, err = restarted.VerifyFullChain(dir); err != nil || report.Verified != 10600 || report.Checkpoints != 21 {
+		t.Fatalf("after restart: report %+v err=%v", report, err)
+	}
+
+	// A fresh window with nothing restored links to the archive.
+	fresh := archivingState(t, t.TempDir(), 4, 8)
+	createSnapshots(t, fresh, 1, 20)
+	fresh.ClearSnapshots()
+	createSnapshots(t, fresh, 21, 10)
+	if _, err := fresh.VerifyFullChain(fresh.archive.dir); err != nil {
+		t.Fatalf("after clearing the window: %v", err)
+	}
+}
+
+func TestVerifyFullChainDetectsArchiveTampering(t *testing.T) {
+	dir := t.TempDir()
+	sm := archivingState(t, dir, 16, 500)
+	createSnapshots(t, sm, 1, 10000)
+	segment := filepath.Join(dir, fmt.Sprintf(segmentFormat, 2001))
+
+	// Editing a snapshot without its hash.
+	rewriteSegmentLine(t, segment, 120, func(record *archiveRecord) {
+		record.Snapshot.UpdateCount++
+	})
+	if _, err := sm.VerifyFullChain(dir); !errors.Is(err, ErrArchiveCorrupt) || !strings.Contains(err.Error(), "hash mismatch at snapshot 2121") {
+		t.Fatalf("edited snapshot: %v", err)
+	}
+
+	// Rehashing it too breaks the link from the next snapshot.
+	rewriteSegmentLine(t, segment, 120, func(record *archiveRecord) {
+		hash, err := sm.computeHash(record.Snapshot)
+		if err != nil {
+			t.Fatalf("rehash: %v", err)
+		}
+		record.Snapshot.Hash = hash
+	})
+	if _, err := sm.VerifyFullChain(dir); !errors.Is(err, ErrArchiveCorrupt) || !strings.Contains(err.Error(), "chain broken at snapshot 2122") {
+		t.Fatalf("rehashed snapshot: %v", err)
+	}
+
+	// Rewriting a whole segment consistently still contradicts the
+	// checkpoint index kept beside it.
+	dir = t.TempDir()
+	sm = archivingState(t, dir, 4, 10)
+	createSnapshots(t, sm, 1, 40)
+	segment = filepath.Join(dir, fmt.Sprintf(segmentFormat, 11))
+	checkpoints, err := readCheckpoints(dir)
+	if err != nil {
+		t.Fatalf("read checkpoints: %v", err)
+	}
+	cursor, err := anchor(11, checkpoints)
+	if err != nil {
+		t.Fatalf("anchor: %v", err)
+	}
+	for line := 0; line < 10; line++ {
+		rewriteSegmentLine(t, segment, line, func(record *archiveRecord) {
+			if line == 0 {
+				record.Snapshot.ModelChecksum = "forged"
+			}
+			record.Snapshot.PreviousHash = cursor.hash
+			record.Snapshot.Hash, _ = sm.computeHash(record.Snapshot)
+			if err := cursor.advance(sm, record.Snapshot); err != nil {
+				t.Fatalf("forge: %v", err)
+			}
+		})
+	}
+	rewriteSegmentLine(t, segment, 10, func(record *archiveRecord) {
+		*record.Checkpoint = cursor.checkpoint(record.Checkpoint.Timestamp)
+	})
+	if _, err := sm.VerifyFullChain(dir); !errors.Is(err, ErrArchiveCorrupt) || !strings.Contains(err.Error(), "checkpoint at snapshot 20") {
+		t.Fatalf("forged segment: %v", err)
+	}
+}
+
+func TestPruneArchiveKeepsCheckpoints(t *testing.T) {
+	dir := t.TempDir()
+	sm := archivingState(t, dir, 16, 200)
+	createSnapshots(t, sm, 1, 3000)
+	mid := time.Now()
+	time.Sleep(time.Millisecond)
+	createSnapshots(t, sm, 3001, 3000)
+
+	pruned, err := sm.PruneArchive(time.Since(mid))
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	// The segments sealed at snapshot 3000 and before hold only snapshots
+	// taken before mid.
+	if pruned != 15 {
+		t.Fatalf("pruned %d segments, want 15", pruned)
+	}
+	report, err := sm.VerifyFullChain(dir)
+	if err != nil {
+		t.Fatalf("verify after prune: %v", err)
+	}
+	if report.Pruned != 3000 || report.Archived != 2984 || report.Verified != 3000 {
+		t.Fatalf("report = %+v", report)
+	}
+	checkpoints := 0
+	f, err := os.Open(filepath.Join(dir, checkpointsFile))
+	if err != nil {
+		t.Fatalf("open checkpoints: %v", err)
+	}
+	defer f.Close()
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		checkpoints++
+	}
+	if checkpoints != 29 {
+		t.Fatalf("%d checkpoints kept, want 29", checkpoints)
+	}
+
+	// Losing a segment past the pruned prefix leaves a gap no checkpoint
+	// bridges from the verified side.
+	if err := os.Remove(filepath.Join(dir, fmt.Sprintf(segmentFormat, 4001))); err != nil {
+		t.Fatalf("remove segment: %v", err)
+	}
+	if _, err := sm.VerifyFullChain(dir); !errors.Is(err, ErrArchiveGap) {
+		t.Fatalf("missing segment: %v", err)
+	}
+
+	// Pruning everything sealed still verifies from the last checkpoint.
+	if _, err := sm.PruneArchive(0); err != nil {
+		t.Fatalf("prune all: %v", err)
+	}
+	if report, err = sm.VerifyFullChain(dir); err != nil || report.Pruned != 5800 || report.Verified != 200 {
+		t.Fatalf("after pruning all sealed segments: report %+v err=%v", report, err)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package island
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// maxCacheAuditRecords bounds the cache audit trail; the oldest records
+// are dropped first.
+const maxCacheAuditRecords = 256
+
+// maxMetadataSummaryLen truncates summarized metadata values.
+const maxMetadataSummaryLen = 64
+
+// Cache audit actions.
+const (
+	CacheActionPurge   = "purge"
+	CacheActionRequeue = "requeue"
+)
+
+// CachedUpdateInfo describes a cached update for operators without its
+// model delta.
+type CachedUpdateInfo struct {
+	ID        string    `json:"id"`
+	Position  int       `json:"position"`
+	Round     int       `json:"round"`
+	PeerID    string    `json:"peer_id,omitempty"`
+	Size      int       `json:"size_bytes"`
+	Timestamp time.Time `json:"timestamp"`
+	// Metadata summarizes the update's metadata: scalar values as text,
+	// truncated, and anything else as its type only.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// CacheAuditRecord records an operator purging or requeueing a cached
+// update.
+type CacheAuditRecord struct {
+	Action   string    `json:"action"`
+	UpdateID string    `json:"update_id"`
+	Round    int       `json:"round"`
+	PeerID   string    `json:"peer_id,omitempty"`
+	Size     int       `json:"size_bytes"`
+	Actor    string    `json:"actor,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// CachedUpdatePage returns up to limit cached updates in sync order,
+// starting after the update with ID after, or from the front when after is
+// empty, and whether more follow. It fails with ErrUpdateNotCached if after
+// is no longer cached.
+func (m *Manager) CachedUpdatePage(after string, limit int) ([]CachedUpdateInfo, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	start := 0
+	if after != "" {
+		index, err := m.cachedIndexLocked(after)
+		if err != nil {
+			return nil, false, err
+		}
+		start = index + 1
+	}
+	end := len(m.cachedUpdates)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+	page := make([]CachedUpdateInfo, 0, end-start)
+	for i := start; i < end; i++ {
+		update := m.cachedUpdates[i]
+		page = append(page, CachedUpdateInfo{
+			ID:        update.ID,
+			Position:  i,
+			Round:     update.Round,
+			PeerID:    update.PeerID,
+			Size:      len(update.ModelDelta),
+			Timestamp: update.Timestamp,
+			Metadata:  summarizeMetadata(update.Metadata),
+		})
+	}
+	return page, end < len(m.cachedUpdates), nil
+}
+
+// PurgeCachedUpdate drops the cached update with ID id, keeping the order
+// of the rest, and records actor doing so in the cache audit trail. It
+// fails with ErrUpdateNotCached if the update is not cached, including
+// when a sync has already taken it.
+func (m *Manager) PurgeCachedUpdate(id, actor string) (CacheAuditRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index, err := m.cachedIndexLocked(id)
+	if err != nil {
+		return CacheAuditRecord{}, err
+	}
+	update := m.cachedUpdates[index]
+	m.cachedUpdates = append(m.cachedUpdates[:index:index], m.cachedUpdates[index+1:]...)
+	return m.auditLocked(CacheActionPurge, update, actor), nil
+}
+
+// RequeueCachedUpdate moves the cached update with ID id to the front of
+// the sync order and records actor doing so in the cache audit trail. It
+// fails with ErrUpdateNotCached if the update is not cached.
+func (m *Manager) RequeueCachedUpdate(id, actor string) (CacheAuditRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index, err := m.cachedIndexLocked(id)
+	if err != nil {
+		return CacheAuditRecord{}, err
+	}
+	update := m.cachedUpdates[index]
+	copy(m.cachedUpdates[1:index+1], m.cachedUpdates[:index])
+	m.cachedUpdates[0] = update
+	return m.auditLocked(CacheActionRequeue, update, actor), nil
+}
+
+// CacheAudit returns the cache audit trail, oldest first.
+func (m *Manager) CacheAudit() []CacheAuditRecord {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]CacheAuditRecord(nil), m.cacheAudit...)
+}
+
+// cachedIndexLocked returns the position of the cached update with ID id.
+// Callers must hold m.mu.
+func (m *Manager) cachedIndexLocked(id string) (int, error) {
+	for i, update := range m.cachedUpdates {
+		if update.ID == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUpdateNotCached, id)
+}
+
+// auditLocked appends a record of action on update to the cache audit
+// trail. Callers must hold m.mu.
+func (m *Manager) auditLocked(action string, update Update, actor string) CacheAuditRecord {
+	record := CacheAuditRecord{
+		Action:   action,
+		UpdateID: update.ID,
+		Round:    update.Round,
+		PeerID:   update.PeerID,
+		Size:     len(update.ModelDelta),
+		Actor:    actor,
+		At:       time.Now(),
+	}
+	m.cacheAudit = append(m.cacheAudit, record)
+	if len(m.cacheAudit) > maxCacheAuditRecords {
+		m.cacheAudit = m.cacheAudit[len(m.cacheAudit)-maxCacheAuditRecords:]
+	}
+	return record
+}
+
+// summarizeMetadata renders scalar metadata values as truncated text and
+// everything else, which may hold weights, as its type.
+func summarizeMetadata(metadata map[string]interface{}) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	summary := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		var text string
+		switch value := value.(type) {
+		case string, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+			text = fmt.Sprint(value)
+		default:
+			text = fmt.Sprintf("<%T>", value)
+		}
+		if runes := []rune(text); len(runes) > maxMetadataSummaryLen {
+			text = string(runes[:maxMetadataSummaryLen]) + "…"
+		}
+		summary[key] = text
+	}
+	return summary
+}
+
+// newUpdateID returns a random ID for a cached update.
+func newUpdateID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", fmt.Errorf("generate update id: %w", err)
+	}
+	return hex.EncodeToString(id[:]), nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package island
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the snapshot chain and its archive,
+// recorded by the Island Mode watchdog, and returned by the cache accessors.
+// Match them with errors.Is; never compare error strings.
+var (
+	// ErrArchiveCorrupt means an archived segment or checkpoint cannot be
+	// read, or a snapshot in it does not match its hash or link. Not
+	// retryable with the same archive.
+	ErrArchiveCorrupt = errors.New("snapshot archive corrupt")
+	// ErrArchiveGap means the archive is missing snapshots: a segment is
+	// absent with no checkpoint to bridge it, or the archive does not reach
+	// the in-memory chain. Not retryable with the same archive.
+	ErrArchiveGap = errors.New("snapshot archive has a gap")
+	// ErrArchiveDiscontinuity means a snapshot leaving memory does not
+	// extend the archive, typically after restoring an unrelated chain. Not
+	// retryable until the archive is moved aside.
+	ErrArchiveDiscontinuity = errors.New("snapshot does not extend the archive")
+	// ErrUnknownHashScheme means a snapshot names a hash scheme this build
+	// does not implement, typically one written by a newer release. Not
+	// retryable until the node is upgraded.
+	ErrUnknownHashScheme = errors.New("unknown snapshot hash scheme")
+	// ErrTransitionTimeout means a return online did not sync every cached
+	// update within the watchdog's deadline. Retryable; the manager retries
+	// after a backoff.
+	ErrTransitionTimeout = errors.New("island transition deadline exceeded")
+	// ErrSyncStalled means a return online flushed no cached update for the
+	// watchdog's stall timeout. Retryable; the manager retries after a
+	// backoff.
+	ErrSyncStalled = errors.New("island sync stalled")
+	// ErrUpdateNotCached means no cached update has the given ID: it was
+	// never cached, was purged or evicted, or a sync has taken it. Not
+	// retryable.
+	ErrUpdateNotCached = errors.New("update not cached")
+)
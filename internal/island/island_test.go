@@ -1,9 +1,15 @@
 package island
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crash"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
 )
 
 type syncerStub struct {
@@ -87,3 +93,222 @@ func TestCacheEvictsOldestWhenFull(t *testing.T) {
 		t.Fatalf("expected oldest update to be evicted, got rounds %d and %d", updates[0].Round, updates[1].Round)
 	}
 }
+
+func TestPurgeCachedUpdateKeepsOrderAndAudits(t *testing.T) {
+	mgr := NewManager(10*time.Millisecond, 10, func() bool { return true })
+	for round := 1; round <= 4; round++ {
+		_ = mgr.CacheUpdate(Update{Round: round, Timestamp: time.Now(), ModelDelta: make([]byte, round)})
+	}
+	cached := mgr.GetCachedUpdates()
+	ids := make(map[string]bool)
+	for _, update := range cached {
+		if update.ID == "" || ids[update.ID] {
+			t.Fatalf("expected unique cache IDs, got %q", update.ID)
+		}
+		ids[update.ID] = true
+	}
+
+	record, err := mgr.PurgeCachedUpdate(cached[1].ID, "admin")
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if record.Action != CacheActionPurge || record.UpdateID != cached[1].ID || record.Round != 2 || record.Size != 2 || record.Actor != "admin" {
+		t.Fatalf("unexpected audit record %+v", record)
+	}
+	var rounds []int
+	for _, update := range mgr.GetCachedUpdates() {
+		rounds = append(rounds, update.Round)
+	}
+	if len(rounds) != 3 || rounds[0] != 1 || rounds[1] != 3 || rounds[2] != 4 {
+		t.Fatalf("expected rounds [1 3 4] after purge, got %v", rounds)
+	}
+	if _, err := mgr.PurgeCachedUpdate(cached[1].ID, "admin"); !errors.Is(err, ErrUpdateNotCached) {
+		t.Fatalf("expected ErrUpdateNotCached purging twice, got %v", err)
+	}
+	if audit := mgr.CacheAudit(); len(audit) != 1 || audit[0] != record {
+		t.Fatalf("expected one audit record, got %+v", audit)
+	}
+}
+
+func TestRequeueAndPageCachedUpdates(t *testing.T) {
+	mgr := NewManager(10*time.Millisecond, 10, func() bool { return true })
+	for round := 1; round <= 5; round++ {
+		_ = mgr.CacheUpdate(Update{
+			Round:      round,
+			Timestamp:  time.Now(),
+			ModelDelta: []byte{1, 2, 3},
+			Metadata:   map[string]interface{}{"loss": 0.5, "weights": []float64{1, 2}},
+		})
+	}
+	cached := mgr.GetCachedUpdates()
+	if _, err := mgr.RequeueCachedUpdate(cached[3].ID, "admin"); err != nil {
+		t.Fatalf("requeue: %v", err)
+	}
+
+	first, more, err := mgr.CachedUpdatePage("", 2)
+	if err != nil || !more || len(first) != 2 {
+		t.Fatalf("first page: %d entries, more=%v, err=%v", len(first), more, err)
+	}
+	if first[0].Round != 4 || first[1].Round != 1 {
+		t.Fatalf("expected requeued round 4 first, got rounds %d, %d", first[0].Round, first[1].Round)
+	}
+	if first[0].Size != 3 || first[0].Metadata["loss"] != "0.5" || first[0].Metadata["weights"] != "<[]float64>" {
+		t.Fatalf("unexpected summary %+v", first[0])
+	}
+	rest, more, err := mgr.CachedUpdatePage(first[1].ID, 10)
+	if err != nil || more || len(rest) != 3 {
+		t.Fatalf("second page: %d entries, more=%v, err=%v", len(rest), more, err)
+	}
+	if rest[0].Round != 2 || rest[0].Position != 2 || rest[2].Round != 5 {
+		t.Fatalf("unexpected second page %+v", rest)
+	}
+}
+
+type backfillerStub struct {
+	mu       sync.Mutex
+	calledAt []int
+	latest   int
+}
+
+func (b *backfillerStub) BackfillRounds(_ context.Context, lastKnownRound int) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calledAt = append(b.calledAt, lastKnownRound)
+	return b.latest, nil
+}
+
+func TestRejoinNegotiatorBackfillsFromLatestSnapshot(t *testing.T) {
+	state := NewStateManager(10)
+	if _, err := state.CreateSnapshot(7, "digest-7", 1, nil); err != nil {
+		t.Fatalf("create snapshot: %v", err)
+	}
+	mgr := NewManager(time.Second, 5, func() bool { return true })
+	stub := &backfillerStub{latest: 57}
+	negotiator := NewRejoinNegotiator(mgr, state, stub, time.Second)
+
+	round, err := negotiator.Negotiate(context.Background())
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	if round != 57 || negotiator.LastRound() != 57 {
+		t.Fatalf("round = %d, want 57", round)
+	}
+	if len(stub.calledAt) != 1 || stub.calledAt[0] != 7 {
+		t.Fatalf("backfill called with %v, want [7]", stub.calledAt)
+	}
+}
+
+func TestRejoinNegotiatorBackfillsFromRoundSource(t *testing.T) {
+	mgr := NewManager(time.Second, 5, func() bool { return true })
+	stub := &backfillerStub{latest: 12}
+	negotiator := NewRejoinNegotiator(mgr, nil, stub, time.Second)
+	stored := 9
+	negotiator.SetRoundSource(func() int { return stored })
+
+	if _, err := negotiator.Negotiate(context.Background()); err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	// Rounds committed locally after the last rejoin move the start on.
+	stored, stub.latest = 20, 20
+	round, err := negotiator.Negotiate(context.Background())
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	if round != 20 {
+		t.Fatalf("round = %d, want 20", round)
+	}
+	if len(stub.calledAt) != 2 || stub.calledAt[0] != 9 || stub.calledAt[1] != 20 {
+		t.Fatalf("backfill called with %v, want [9 20]", stub.calledAt)
+	}
+}
+
+func TestRejoinNegotiatorRunsOnReturnOnline(t *testing.T) {
+	mgr := NewManager(time.Second, 5, func() bool { return true })
+	stub := &backfillerStub{latest: 3}
+	negotiator := NewRejoinNegotiator(mgr, nil, stub, time.Second)
+	negotiator.Attach()
+
+	mgr.updateMode(false)
+	mgr.updateMode(true)
+
+	deadline := time.Now().Add(300 * time.Millisecond)
+	for negotiator.LastRound() != 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected rejoin to backfill after returning online")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type blockingSyncer struct {
+	started chan struct{}
+}
+
+func (s *blockingSyncer) SyncUpdates(updates []Update) error {
+	return nil
+}
+
+func (s *blockingSyncer) SyncUpdatesContext(ctx context.Context, updates []Update) error {
+	close(s.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestForceSyncAbortKeepsUpdatesCached(t *testing.T) {
+	mgr := NewManager(time.Hour, 4, func() bool { return true })
+	syncer := &blockingSyncer{started: make(chan struct{})}
+	mgr.SetSyncer(syncer)
+	_ = mgr.CacheUpdate(Update{Round: 1})
+	_ = mgr.CacheUpdate(Update{Round: 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- mgr.ForceSync(ctx) }()
+
+	<-syncer.started
+	_ = mgr.CacheUpdate(Update{Round: 3})
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ForceSync did not return after cancellation")
+	}
+
+	cached := mgr.GetCachedUpdates()
+	if len(cached) != 3 || cached[0].Round != 1 || cached[2].Round != 3 {
+		t.Fatalf("expected rounds 1-3 cached in order, got %+v", cached)
+	}
+}
+
+func TestDetectShutdownSnapshotStampsFirstHeartbeat(t *testing.T) {
+	dir := t.TempDir()
+	rm := NewRecoveryManager(NewStateManager(4), NewManager(time.Minute, 10, func() bool { return true }), filepath.Join(dir, "recovery.json"))
+
+	if snapshot, err := rm.DetectShutdownSnapshot(dir); err != nil || snapshot != nil {
+		t.Fatalf("clean start: snapshot %+v err=%v", snapshot, err)
+	}
+
+	handler := crash.NewHandler(dir, "node-1", nil, crash.Sources{})
+	if _, err := handler.Snapshot(crash.ReasonPanic, "boom", "consensus"); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+
+	snapshot, err := rm.DetectShutdownSnapshot(dir)
+	if err != nil || snapshot == nil || snapshot.ID != "node-1-000001" {
+		t.Fatalf("detect: snapshot %+v err=%v", snapshot, err)
+	}
+	first, second := &protocol.StatusUpdate{NodeID: "node-1"}, &protocol.StatusUpdate{NodeID: "node-1"}
+	rm.StampHeartbeat(first)
+	rm.StampHeartbeat(second)
+	if first.ShutdownSnapshotID != "node-1-000001" || second.ShutdownSnapshotID != "" {
+		t.Fatalf("heartbeat ids %q, %q", first.ShutdownSnapshotID, second.ShutdownSnapshotID)
+	}
+
+	if again, err := NewRecoveryManager(nil, nil, "").DetectShutdownSnapshot(dir); err != nil || again != nil {
+		t.Fatalf("snapshot reported twice: %+v err=%v", again, err)
+	}
+}
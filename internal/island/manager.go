@@ -4,9 +4,11 @@ package island
 
 import (
 	"context"
-	"log"
 	"sync"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/profiling"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
 )
 
 // Mode represents the operational mode of a node
@@ -21,6 +23,20 @@ const (
 	ModeTransition
 )
 
+// String returns the mode as reported on status endpoints.
+func (m Mode) String() string {
+	switch m {
+	case ModeOnline:
+		return "online"
+	case ModeIsland:
+		return "island"
+	case ModeTransition:
+		return "transition"
+	default:
+		return "unknown"
+	}
+}
+
 // Manager handles Island Mode transitions for offline operation
 type Manager struct {
 	mu                sync.RWMutex
@@ -34,15 +50,39 @@ type Manager struct {
 	syncer            UpdateSyncer
 	ctx               context.Context
 	cancel            context.CancelFunc
+
+	watchdog WatchdogConfig
+	// sync is the latest sync of cached updates, nil before the first.
+	sync *syncRun
+	// lastSyncErr is the last error the syncer returned, cleared when a
+	// sync completes.
+	lastSyncErr error
+	// transitionFailures counts every failed return online; failedAttempts
+	// only those since the last success, which set the retry backoff.
+	transitionFailures int
+	failedAttempts     int
+	lastTransitionErr  error
+	retryAt            time.Time
+	retryTimer         *time.Timer
+	// cacheAudit records operator purges and requeues, oldest first.
+	cacheAudit []CacheAuditRecord
 }
 
 // Update represents a federated learning update
 type Update struct {
+	// ID identifies the update while it is cached; CacheUpdate assigns one
+	// if it is empty.
+	ID         string
 	Timestamp  time.Time
 	Round      int
 	ModelDelta []byte
 	Metadata   map[string]interface{}
 	PeerID     string
+	// Origin is the submission key the update was produced under, so the
+	// aggregator can match it to a live update for the same round once it
+	// is synced. CacheUpdate derives it from PeerID, Round, and ModelDelta
+	// if it is nil.
+	Origin *protocol.UpdateOrigin
 }
 
 // ModeChangeListener is called when mode changes
@@ -53,6 +93,13 @@ type UpdateSyncer interface {
 	SyncUpdates(updates []Update) error
 }
 
+// ContextUpdateSyncer is an UpdateSyncer whose transfer can be aborted.
+// Syncers that implement it receive the sync context.
+type ContextUpdateSyncer interface {
+	UpdateSyncer
+	SyncUpdatesContext(ctx context.Context, updates []Update) error
+}
+
 // NewManager creates a new Island Mode manager
 func NewManager(checkInterval time.Duration, maxCachedUpdates int, connectivityCheck func() bool) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -67,6 +114,7 @@ func NewManager(checkInterval time.Duration, maxCachedUpdates int, connectivityC
 		ctx:               ctx,
 		cancel:            cancel,
 		syncer:            nil, // Can be set via SetSyncer()
+		watchdog:          DefaultWatchdogConfig(),
 	}
 }
 
@@ -78,6 +126,11 @@ func (m *Manager) Start() {
 // Stop halts the Island Mode manager
 func (m *Manager) Stop() {
 	m.cancel()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.retryTimer != nil {
+		m.retryTimer.Stop()
+	}
 }
 
 // monitorConnectivity periodically checks network connectivity
@@ -105,9 +158,14 @@ func (m *Manager) updateMode(isOnline bool) {
 	newMode := oldMode
 
 	if isOnline && oldMode == ModeIsland {
-		// Transition to online: sync cached updates
+		if time.Now().Before(m.retryAt) {
+			// A failed return online is backing off.
+			return
+		}
+		// Transition to online: sync cached updates under the watchdog
 		newMode = ModeOnline
-		go m.syncCachedUpdates()
+		ctx, cancel := context.WithCancel(m.ctx)
+		go m.superviseTransition(ctx, cancel, m.startSyncLocked(), oldMode, m.watchdog)
 	} else if !isOnline && oldMode == ModeOnline {
 		// Transition to island: cache future updates
 		newMode = ModeIsland
@@ -133,6 +191,20 @@ func (m *Manager) IsOnline() bool {
 
 // CacheUpdate stores an update for later synchronization
 func (m *Manager) CacheUpdate(update Update) error {
+	if update.ID == "" {
+		id, err := newUpdateID()
+		if err != nil {
+			return err
+		}
+		update.ID = id
+	}
+	if update.Origin == nil {
+		update.Origin = &protocol.UpdateOrigin{
+			NodeID:       update.PeerID,
+			Round:        update.Round,
+			UpdateDigest: protocol.UpdateDigest(update.ModelDelta),
+		}
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -156,21 +228,99 @@ func (m *Manager) GetCachedUpdates() []Update {
 	return updates
 }
 
-// syncCachedUpdates sends cached updates when coming back online
-func (m *Manager) syncCachedUpdates() {
+// syncCachedUpdates sends cached updates when coming back online. If ctx is
+// cancelled or the syncer fails before the transfer completes, the unsent
+// updates go back into the cache so a later sync can retry them.
+func (m *Manager) syncCachedUpdates(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	m.mu.Lock()
-	updates := m.cachedUpdates
-	m.cachedUpdates = make([]Update, 0, m.maxCachedUpdates)
-	m.lastSync = time.Now()
-	syncer := m.syncer
+	run := m.startSyncLocked()
 	m.mu.Unlock()
+	return m.flush(ctx, run)
+}
 
+// startSyncLocked takes every cached update into a new sync run. Callers
+// must hold m.mu.
+func (m *Manager) startSyncLocked() *syncRun {
+	now := time.Now()
+	run := &syncRun{
+		updates:      m.cachedUpdates,
+		syncer:       m.syncer,
+		batchSize:    m.watchdog.BatchSize,
+		started:      now,
+		lastProgress: now,
+	}
+	m.cachedUpdates = make([]Update, 0, m.maxCachedUpdates)
+	m.sync = run
+	return run
+}
+
+// flush sends run's updates in batches, recording progress after each. A
+// batch that fails puts it and every later one back in the cache. Once the
+// watchdog abandons run, flush stops and leaves the cache alone; the
+// watchdog has already requeued what was not flushed.
+func (m *Manager) flush(ctx context.Context, run *syncRun) error {
+	started := time.Now()
+	defer func() { profiling.Observe(profiling.PhaseIslandSync, time.Since(started)) }()
 	// Send updates to aggregation server if syncer is configured
-	if syncer != nil && len(updates) > 0 {
-		if err := syncer.SyncUpdates(updates); err != nil {
-			log.Printf("island sync failed: %v", err)
+	if run.syncer == nil || len(run.updates) == 0 {
+		m.mu.Lock()
+		run.done = true
+		m.lastSync = time.Now()
+		m.mu.Unlock()
+		return nil
+	}
+
+	batchSize := run.batchSize
+	if batchSize <= 0 {
+		batchSize = len(run.updates)
+	}
+	for start := 0; start < len(run.updates); start += batchSize {
+		batch := run.updates[start:min(start+batchSize, len(run.updates))]
+		var err error
+		if ctxSyncer, ok := run.syncer.(ContextUpdateSyncer); ok {
+			err = ctxSyncer.SyncUpdatesContext(ctx, batch)
+		} else {
+			err = run.syncer.SyncUpdates(batch)
+		}
+
+		m.mu.Lock()
+		if run.abandoned {
+			m.mu.Unlock()
+			return run.abandonErr
+		}
+		if err != nil {
+			run.failed = true
+			m.lastSyncErr = err
+			m.requeueLocked(run.updates[start:])
+			m.mu.Unlock()
+			return err
 		}
+		run.flushed += len(batch)
+		run.lastProgress = time.Now()
+		m.mu.Unlock()
+		islandSyncedUpdatesTotal.Add(float64(len(batch)))
+	}
+
+	m.mu.Lock()
+	run.done = true
+	m.lastSync = time.Now()
+	m.lastSyncErr = nil
+	m.mu.Unlock()
+	return nil
+}
+
+// requeueLocked puts unsent updates back ahead of anything cached since,
+// keeping the newest entries when the cache would overflow. Callers must
+// hold m.mu.
+func (m *Manager) requeueLocked(updates []Update) {
+	merged := append(append(make([]Update, 0, len(updates)+len(m.cachedUpdates)), updates...), m.cachedUpdates...)
+	if len(merged) > m.maxCachedUpdates {
+		merged = merged[len(merged)-m.maxCachedUpdates:]
 	}
+	m.cachedUpdates = merged
 }
 
 // SetSyncer configures the update syncer for sending cached updates
@@ -199,34 +349,46 @@ func (m *Manager) GetStatus() map[string]interface{} {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	var modeStr string
-	switch m.mode {
-	case ModeOnline:
-		modeStr = "online"
-	case ModeIsland:
-		modeStr = "island"
-	case ModeTransition:
-		modeStr = "transition"
-	default:
-		modeStr = "unknown"
-	}
-
-	return map[string]interface{}{
-		"mode":                 modeStr,
+	status := map[string]interface{}{
+		"mode":                 m.mode.String(),
 		"cached_updates":       len(m.cachedUpdates),
 		"max_cached_updates":   m.maxCachedUpdates,
 		"last_sync":            m.lastSync,
 		"time_since_last_sync": time.Since(m.lastSync),
+		"transition_failures":  m.transitionFailures,
+	}
+	if m.sync != nil {
+		progress := m.sync.progress()
+		status["sync_in_progress"] = progress.Active
+		status["sync_flushed"] = progress.Flushed
+		status["sync_total"] = progress.Total
+		status["sync_flushed_last_interval"] = progress.FlushedLastInterval
+		status["sync_stuck"] = progress.Stuck
+	}
+	if m.lastSyncErr != nil {
+		status["last_sync_error"] = m.lastSyncErr.Error()
 	}
+	if m.lastTransitionErr != nil {
+		status["last_transition_error"] = m.lastTransitionErr.Error()
+	}
+	if !m.retryAt.IsZero() {
+		status["next_transition_retry"] = m.retryAt
+	}
+	return status
 }
 
-// ForceSync immediately attempts to sync cached updates
-func (m *Manager) ForceSync() error {
+// ForceSync immediately syncs cached updates and waits for the transfer.
+// Cancelling ctx (or stopping the manager) aborts it and keeps the updates
+// cached.
+func (m *Manager) ForceSync(ctx context.Context) error {
 	if !m.IsOnline() {
 		return nil // Skip if offline
 	}
-	go m.syncCachedUpdates()
-	return nil
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(m.ctx, cancel)
+	defer stop()
+	return m.syncCachedUpdates(ctx)
 }
 
 // CurrentMode returns the current operational mode
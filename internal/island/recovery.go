@@ -5,9 +5,15 @@ package island
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/chaos"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crash"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
 )
 
 // RecoveryManager handles state recovery after offline periods
@@ -15,6 +21,9 @@ type RecoveryManager struct {
 	stateManager    *StateManager
 	islandManager   *Manager
 	persistencePath string
+
+	mu                 sync.Mutex
+	shutdownSnapshotID string
 }
 
 // NewRecoveryManager creates a new recovery manager
@@ -57,6 +66,11 @@ func (rm *RecoveryManager) PersistState() error {
 	}
 
 	// Write to disk
+	if inj := chaos.Active(); inj != nil {
+		if err := inj.DiskWrite(rm.persistencePath); err != nil {
+			return fmt.Errorf("failed to write recovery data: %w", err)
+		}
+	}
 	if err := os.WriteFile(rm.persistencePath, jsonData, 0600); err != nil {
 		return fmt.Errorf("failed to write recovery data: %w", err)
 	}
@@ -86,7 +100,10 @@ func (rm *RecoveryManager) RecoverState() error {
 		snapshotJSON, _ := json.Marshal(snapshotData)
 		var snapshot StateSnapshot
 		if err := json.Unmarshal(snapshotJSON, &snapshot); err == nil {
-			// Re-verify snapshot integrity
+			// Re-verify the recovered snapshot and the chain it joins
+			if err := rm.stateManager.verifySnapshots([]StateSnapshot{snapshot}); err != nil {
+				return fmt.Errorf("recovered snapshot failed integrity check: %w", err)
+			}
 			valid, err := rm.stateManager.VerifyChain()
 			if err != nil || !valid {
 				return fmt.Errorf("recovered snapshot failed integrity check")
@@ -108,6 +125,37 @@ func (rm *RecoveryManager) RecoverState() error {
 	return nil
 }
 
+// DetectShutdownSnapshot looks in dir for a crash snapshot written since the
+// last start, logs its summary, and marks it reported. Its ID is attached
+// to the next heartbeat passed to StampHeartbeat. It returns nil if the
+// node shut down cleanly.
+func (rm *RecoveryManager) DetectShutdownSnapshot(dir string) (*crash.ShutdownSnapshot, error) {
+	snapshot, err := crash.LatestUnreported(dir)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+	log.Printf("recovered from shutdown snapshot %s", snapshot.Summary())
+	if err := crash.MarkReported(dir, snapshot); err != nil {
+		return nil, err
+	}
+	rm.mu.Lock()
+	rm.shutdownSnapshotID = snapshot.ID
+	rm.mu.Unlock()
+	return snapshot, nil
+}
+
+// StampHeartbeat sets the recovered snapshot's ID on the first heartbeat
+// after a crash, so operators can correlate it with the snapshot.
+func (rm *RecoveryManager) StampHeartbeat(update *protocol.StatusUpdate) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if rm.shutdownSnapshotID == "" {
+		return
+	}
+	update.ShutdownSnapshotID = rm.shutdownSnapshotID
+	rm.shutdownSnapshotID = ""
+}
+
 // ClearRecoveryData removes persisted recovery data
 func (rm *RecoveryManager) ClearRecoveryData() error {
 	if err := os.Remove(rm.persistencePath); err != nil && !os.IsNotExist(err) {
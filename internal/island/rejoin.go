@@ -0,0 +1,144 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package island
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Backfiller fetches committed rounds missed while a node was offline and
+// returns the round the node has caught up to.
+type Backfiller interface {
+	BackfillRounds(ctx context.Context, lastKnownRound int) (int, error)
+}
+
+// RejoinNegotiator fast-forwards a node to the federation's latest committed
+// round when it returns from Island Mode.
+type RejoinNegotiator struct {
+	mu          sync.Mutex
+	manager     *Manager
+	state       *StateManager
+	rounds      func() int
+	backfiller  Backfiller
+	timeout     time.Duration
+	lastRound   int
+	lastRejoin  time.Time
+	lastError   error
+	inFlight    bool
+	rejoinCount int
+}
+
+// NewRejoinNegotiator creates a negotiator. The state manager supplies the
+// last round the node knows about; it may be nil for a fresh node.
+func NewRejoinNegotiator(manager *Manager, state *StateManager, backfiller Backfiller, timeout time.Duration) *RejoinNegotiator {
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+	rn := &RejoinNegotiator{
+		manager:    manager,
+		state:      state,
+		backfiller: backfiller,
+		timeout:    timeout,
+	}
+	if state != nil {
+		if latest := state.GetLatestSnapshot(); latest != nil {
+			rn.lastRound = latest.Round
+		}
+	}
+	return rn
+}
+
+// SetRoundSource makes every negotiation start no earlier than the round
+// source reports, such as the local model store's latest committed round,
+// so a node holding rounds the negotiator never backfilled does not fetch
+// them again.
+func (rn *RejoinNegotiator) SetRoundSource(source func() int) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.rounds = source
+}
+
+// Attach registers the negotiator to run whenever the manager returns online.
+func (rn *RejoinNegotiator) Attach() {
+	if rn.manager == nil {
+		return
+	}
+	rn.manager.AddModeChangeListener(func(oldMode, newMode Mode) {
+		if oldMode == ModeIsland && newMode == ModeOnline {
+			ctx, cancel := context.WithTimeout(context.Background(), rn.timeout)
+			defer cancel()
+			if _, err := rn.Negotiate(ctx); err != nil {
+				log.Printf("island rejoin backfill failed: %v", err)
+			}
+		}
+	})
+}
+
+// Negotiate backfills every round after the last known one.
+func (rn *RejoinNegotiator) Negotiate(ctx context.Context) (int, error) {
+	rn.mu.Lock()
+	if rn.inFlight {
+		round := rn.lastRound
+		rn.mu.Unlock()
+		return round, nil
+	}
+	rn.inFlight = true
+	lastKnown := rn.lastRound
+	if rn.state != nil {
+		if latest := rn.state.GetLatestSnapshot(); latest != nil && latest.Round > lastKnown {
+			lastKnown = latest.Round
+		}
+	}
+	if rn.rounds != nil {
+		if round := rn.rounds(); round > lastKnown {
+			lastKnown = round
+		}
+	}
+	rn.mu.Unlock()
+
+	round, err := lastKnown, error(nil)
+	if rn.backfiller != nil {
+		round, err = rn.backfiller.BackfillRounds(ctx, lastKnown)
+	}
+
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.inFlight = false
+	rn.lastError = err
+	if err != nil {
+		return lastKnown, err
+	}
+	if round > rn.lastRound {
+		rn.lastRound = round
+	}
+	rn.lastRejoin = time.Now()
+	rn.rejoinCount++
+	return rn.lastRound, nil
+}
+
+// LastRound returns the latest round the node has caught up to.
+func (rn *RejoinNegotiator) LastRound() int {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	return rn.lastRound
+}
+
+// GetStatus returns rejoin negotiation status for observability endpoints.
+func (rn *RejoinNegotiator) GetStatus() map[string]interface{} {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+
+	status := map[string]interface{}{
+		"last_round":   rn.lastRound,
+		"last_rejoin":  rn.lastRejoin,
+		"rejoin_count": rn.rejoinCount,
+		"in_flight":    rn.inFlight,
+	}
+	if rn.lastError != nil {
+		status["last_error"] = rn.lastError.Error()
+	}
+	return status
+}
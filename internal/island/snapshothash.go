@@ -0,0 +1,184 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package island
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Snapshot hash schemes. A snapshot records the scheme its hash was
+// computed under, so chains written before a scheme change stay verifiable.
+const (
+	// HashSchemeLegacy hashes the JSON encoding of the snapshot's fields.
+	// That encoding depends on how encoding/json orders and formats the
+	// metadata, which is not stable across a JSON round-trip or across Go
+	// releases. Snapshots without a scheme use it.
+	HashSchemeLegacy uint8 = 0
+	// HashSchemeCanonical hashes an explicitly ordered binary encoding with
+	// integer nanosecond timestamps and the metadata flattened into sorted,
+	// type-tagged key=value pairs. New snapshots use it.
+	HashSchemeCanonical uint8 = 1
+)
+
+// computeHash computes SHA-256 hash of snapshot for tamper-evidence under
+// the snapshot's hash scheme.
+func (sm *StateManager) computeHash(snapshot *StateSnapshot) (string, error) {
+	var input []byte
+	var err error
+	switch snapshot.HashScheme {
+	case HashSchemeLegacy:
+		input, err = legacyHashInput(snapshot)
+	case HashSchemeCanonical:
+		input, err = canonicalHashInput(snapshot)
+	default:
+		return "", fmt.Errorf("%w: %d", ErrUnknownHashScheme, snapshot.HashScheme)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(input)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// legacyHashInput is the HashSchemeLegacy encoding.
+func legacyHashInput(snapshot *StateSnapshot) ([]byte, error) {
+	// Create a copy without the hash field
+	data := map[string]interface{}{
+		"timestamp":      snapshot.Timestamp.UnixNano(),
+		"round":          snapshot.Round,
+		"model_checksum": snapshot.ModelChecksum,
+		"update_count":   snapshot.UpdateCount,
+		"metadata":       snapshot.Metadata,
+		"previous_hash":  snapshot.PreviousHash,
+	}
+	return json.Marshal(data)
+}
+
+// canonicalHashInput is the HashSchemeCanonical encoding: the scheme byte,
+// then the timestamp, round, model checksum, update count, previous hash
+// and metadata pairs in that order. Integers are 8-byte big-endian and
+// strings are length-prefixed, so no two snapshots share an encoding.
+func canonicalHashInput(snapshot *StateSnapshot) ([]byte, error) {
+	pairs, err := flattenMetadata(snapshot.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := []byte{HashSchemeCanonical}
+	buf = binary.BigEndian.AppendUint64(buf, uint64(snapshot.Timestamp.UnixNano()))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(int64(snapshot.Round)))
+	buf = appendCanonicalString(buf, snapshot.ModelChecksum)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(int64(snapshot.UpdateCount)))
+	buf = appendCanonicalString(buf, snapshot.PreviousHash)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(len(pairs)))
+	for _, pair := range pairs {
+		buf = appendCanonicalString(buf, pair)
+	}
+	return buf, nil
+}
+
+func appendCanonicalString(buf []byte, s string) []byte {
+	buf = binary.BigEndian.AppendUint64(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// flattenMetadata returns metadata as sorted path=tag:value pairs. It is
+// normalized through JSON first, as a persisted snapshot's metadata is, so
+// typed values hash the same before and after a reload. Nested objects and
+// arrays contribute a pair holding their length and one per element; a nil
+// and an empty map both flatten to nothing.
+//
+// Numbers are formatted here rather than by encoding/json: integral values
+// print as integers whatever their Go type, others in shortest 'g' form.
+// Integers beyond 2^53 do not survive a JSON reload as float64, so they
+// are best kept in metadata as strings.
+func flattenMetadata(metadata map[string]interface{}) ([]string, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("encode metadata: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var normalized map[string]interface{}
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, fmt.Errorf("normalize metadata: %w", err)
+	}
+
+	var pairs []string
+	for key, value := range normalized {
+		if err := flattenValue(escapeMetadataKey(key), value, &pairs); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(pairs)
+	return pairs, nil
+}
+
+func flattenValue(path string, value interface{}, pairs *[]string) error {
+	switch v := value.(type) {
+	case nil:
+		*pairs = append(*pairs, path+"=z:")
+	case bool:
+		*pairs = append(*pairs, path+"=b:"+strconv.FormatBool(v))
+	case string:
+		*pairs = append(*pairs, path+"=s:"+v)
+	case json.Number:
+		number, err := canonicalNumber(v)
+		if err != nil {
+			return fmt.Errorf("metadata %s: %w", path, err)
+		}
+		*pairs = append(*pairs, path+"=n:"+number)
+	case []interface{}:
+		*pairs = append(*pairs, path+"=a:"+strconv.Itoa(len(v)))
+		for i, element := range v {
+			if err := flattenValue(path+"["+strconv.Itoa(i)+"]", element, pairs); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		*pairs = append(*pairs, path+"=m:"+strconv.Itoa(len(v)))
+		for key, element := range v {
+			if err := flattenValue(path+"."+escapeMetadataKey(key), element, pairs); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("metadata %s: unexpected %T", path, value)
+	}
+	return nil
+}
+
+func canonicalNumber(n json.Number) (string, error) {
+	if i, err := n.Int64(); err == nil {
+		return strconv.FormatInt(i, 10), nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return "", err
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return strconv.FormatInt(int64(f), 10), nil
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64), nil
+}
+
+// metadataKeyEscaper escapes the characters that structure a flattened
+// path, so {"a.b": 1} and {"a": {"b": 1}} flatten differently.
+var metadataKeyEscaper = strings.NewReplacer(`\`, `\\`, `.`, `\.`, `[`, `\[`, `]`, `\]`, `=`, `\=`)
+
+func escapeMetadataKey(key string) string {
+	return metadataKeyEscaper.Replace(key)
+}
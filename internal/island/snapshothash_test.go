@@ -0,0 +1,166 @@
+package island
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSnapshotHashFixtures re-verifies a chain committed to testdata, so a
+// change to either hash scheme's encoding fails here rather than on nodes
+// reloading chains they wrote earlier.
+func TestSnapshotHashFixtures(t *testing.T) {
+	data, err := os.ReadFile("testdata/snapshot_chain.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	var chain []StateSnapshot
+	if err := json.Unmarshal(data, &chain); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+
+	sm := NewStateManager(len(chain))
+	schemes := map[uint8]int{}
+	for i := range chain {
+		hash, err := sm.computeHash(&chain[i])
+		if err != nil {
+			t.Fatalf("snapshot %d: %v", i, err)
+		}
+		if hash != chain[i].Hash {
+			t.Fatalf("snapshot %d (scheme %d) hashes to %s, fixture has %s", i, chain[i].HashScheme, hash, chain[i].Hash)
+		}
+		schemes[chain[i].HashScheme]++
+	}
+	if schemes[HashSchemeLegacy] == 0 || schemes[HashSchemeCanonical] == 0 {
+		t.Fatalf("fixture should mix legacy and canonical snapshots, has %v", schemes)
+	}
+	if err := sm.RestoreSnapshots(chain); err != nil {
+		t.Fatalf("restore fixture chain: %v", err)
+	}
+	if ok, err := sm.VerifyChain(); !ok || err != nil {
+		t.Fatalf("verify fixture chain: %v, %v", ok, err)
+	}
+}
+
+func TestSnapshotChainSurvivesPersistAndReload(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewStateManager(8)
+	// Metadata whose JSON encoding changes on reload: the raw message keeps
+	// its key order until it is decoded into a map, and typed slices and
+	// maps come back as interface values.
+	metadata := []map[string]interface{}{
+		{"raw": json.RawMessage(`{"zeta":1,"alpha":2}`), "peers": []string{"b", "a"}},
+		{"ratio": float32(0.1), "counts": map[string]int{"valid": 3, "invalid": 1}, "seen": time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"nested": map[string]interface{}{"depth": map[string]interface{}{"x": 2.0}}},
+	}
+	for i, m := range metadata {
+		if _, err := sm.CreateSnapshot(i+1, "model", i, m); err != nil {
+			t.Fatalf("snapshot %d: %v", i, err)
+		}
+	}
+
+	// The legacy scheme cannot reload the first snapshot.
+	legacy := sm.GetSnapshots()[0]
+	legacy.HashScheme = HashSchemeLegacy
+	legacy.Hash, _ = sm.computeHash(&legacy)
+	path := filepath.Join(dir, "chain.json")
+	writeJSON(t, path, append([]StateSnapshot{legacy}, sm.GetSnapshots()...))
+	var reloaded []StateSnapshot
+	readJSON(t, path, &reloaded)
+	if hash, _ := sm.computeHash(&reloaded[0]); hash == legacy.Hash {
+		t.Fatal("expected the legacy hash to change across a reload of raw metadata")
+	}
+
+	restored := NewStateManager(8)
+	if err := restored.RestoreSnapshots(reloaded[1:]); err != nil {
+		t.Fatalf("restore reloaded chain: %v", err)
+	}
+	if ok, err := restored.VerifyChain(); !ok || err != nil {
+		t.Fatalf("verify reloaded chain: %v, %v", ok, err)
+	}
+
+	// A second reload of the restored chain hashes the same again.
+	writeJSON(t, path, restored.GetSnapshots())
+	var again []StateSnapshot
+	readJSON(t, path, &again)
+	if err := NewStateManager(8).RestoreSnapshots(again); err != nil {
+		t.Fatalf("restore chain after second reload: %v", err)
+	}
+
+	// RecoveryManager round-trips the latest snapshot the same way.
+	recoveryPath := filepath.Join(dir, "recovery.json")
+	if err := NewRecoveryManager(sm, NewManager(time.Second, 10, func() bool { return true }), recoveryPath).PersistState(); err != nil {
+		t.Fatalf("persist state: %v", err)
+	}
+	if err := NewRecoveryManager(restored, NewManager(time.Second, 10, func() bool { return true }), recoveryPath).RecoverState(); err != nil {
+		t.Fatalf("recover state: %v", err)
+	}
+}
+
+func TestCanonicalHashSeparatesStructure(t *testing.T) {
+	sm := NewStateManager(1)
+	hash := func(metadata map[string]interface{}) string {
+		t.Helper()
+		snapshot := StateSnapshot{Timestamp: time.Unix(0, 42), Round: 1, Metadata: metadata, HashScheme: HashSchemeCanonical}
+		h, err := sm.computeHash(&snapshot)
+		if err != nil {
+			t.Fatalf("hash %v: %v", metadata, err)
+		}
+		return h
+	}
+
+	if hash(map[string]interface{}{"n": 3}) != hash(map[string]interface{}{"n": 3.0}) {
+		t.Fatal("integral numbers should hash alike whatever their Go type")
+	}
+	if hash(nil) != hash(map[string]interface{}{}) {
+		t.Fatal("nil and empty metadata should hash alike")
+	}
+	distinct := []map[string]interface{}{
+		{"a.b": 1},
+		{"a": map[string]interface{}{"b": 1}},
+		{"a": "1"},
+		{"a": 1},
+		{"a": true},
+		{"a": nil},
+		{"a": []interface{}{}},
+		{"a": map[string]interface{}{}},
+	}
+	seen := map[string]int{}
+	for i, m := range distinct {
+		h := hash(m)
+		if j, ok := seen[h]; ok {
+			t.Fatalf("metadata %v and %v hash alike", distinct[j], m)
+		}
+		seen[h] = i
+	}
+
+	unknown := StateSnapshot{HashScheme: 9}
+	if _, err := sm.computeHash(&unknown); !errors.Is(err, ErrUnknownHashScheme) {
+		t.Fatalf("expected ErrUnknownHashScheme, got %v", err)
+	}
+}
+
+func writeJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encode %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func readJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+}
@@ -3,9 +3,6 @@
 package island
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -20,14 +17,20 @@ type StateSnapshot struct {
 	Metadata      map[string]interface{} `json:"metadata"`
 	PreviousHash  string                 `json:"previous_hash"`
 	Hash          string                 `json:"hash"`
+	// HashScheme is the scheme Hash was computed under; snapshots written
+	// before schemes were recorded use HashSchemeLegacy.
+	HashScheme uint8 `json:"hash_scheme,omitempty"`
 }
 
-// StateManager handles state persistence and recovery
+// StateManager handles state persistence and recovery. It keeps the
+// newest maxSnapshots snapshots in memory; older ones are dropped unless
+// SetArchive is used to archive them to disk.
 type StateManager struct {
 	mu           sync.RWMutex
 	snapshots    []StateSnapshot
 	maxSnapshots int
 	lastSnapshot time.Time
+	archive      *snapshotArchive
 }
 
 // NewStateManager creates a new state manager
@@ -47,6 +50,8 @@ func (sm *StateManager) CreateSnapshot(round int, modelChecksum string, updateCo
 	var previousHash string
 	if len(sm.snapshots) > 0 {
 		previousHash = sm.snapshots[len(sm.snapshots)-1].Hash
+	} else if sm.archive != nil {
+		previousHash = sm.archive.cursor.hash
 	}
 
 	snapshot := StateSnapshot{
@@ -56,6 +61,7 @@ func (sm *StateManager) CreateSnapshot(round int, modelChecksum string, updateCo
 		UpdateCount:   updateCount,
 		Metadata:      metadata,
 		PreviousHash:  previousHash,
+		HashScheme:    HashSchemeCanonical,
 	}
 
 	// Compute hash for tamper-evidence
@@ -65,8 +71,13 @@ func (sm *StateManager) CreateSnapshot(round int, modelChecksum string, updateCo
 	}
 	snapshot.Hash = hash
 
-	// Add to snapshot chain
+	// Add to snapshot chain, archiving the snapshot that leaves the window
 	if len(sm.snapshots) >= sm.maxSnapshots {
+		if sm.archive != nil {
+			if err := sm.archive.append(sm, sm.snapshots[0]); err != nil {
+				return nil, err
+			}
+		}
 		sm.snapshots = sm.snapshots[1:]
 	}
 	sm.snapshots = append(sm.snapshots, snapshot)
@@ -101,47 +112,52 @@ func (sm *StateManager) VerifyChain() (bool, error) {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
-	for i, snapshot := range sm.snapshots {
+	if err := sm.verifySnapshots(sm.snapshots); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RestoreSnapshots replaces the snapshot chain with snapshots, typically
+// read from a backup, after verifying their hashes and linkage.
+func (sm *StateManager) RestoreSnapshots(snapshots []StateSnapshot) error {
+	if err := sm.verifySnapshots(snapshots); err != nil {
+		return err
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if len(snapshots) > sm.maxSnapshots {
+		snapshots = snapshots[len(snapshots)-sm.maxSnapshots:]
+	}
+	sm.snapshots = append(make([]StateSnapshot, 0, sm.maxSnapshots), snapshots...)
+	if len(snapshots) > 0 {
+		sm.lastSnapshot = snapshots[len(snapshots)-1].Timestamp
+	}
+	return nil
+}
+
+func (sm *StateManager) verifySnapshots(snapshots []StateSnapshot) error {
+	for i, snapshot := range snapshots {
 		// Verify hash
 		computedHash, err := sm.computeHash(&snapshot)
 		if err != nil {
-			return false, fmt.Errorf("failed to compute hash for snapshot %d: %w", i, err)
+			return fmt.Errorf("failed to compute hash for snapshot %d: %w", i, err)
 		}
 
 		if computedHash != snapshot.Hash {
-			return false, fmt.Errorf("hash mismatch at snapshot %d", i)
+			return fmt.Errorf("hash mismatch at snapshot %d", i)
 		}
 
 		// Verify chain linkage
 		if i > 0 {
-			if snapshot.PreviousHash != sm.snapshots[i-1].Hash {
-				return false, fmt.Errorf("chain broken at snapshot %d", i)
+			if snapshot.PreviousHash != snapshots[i-1].Hash {
+				return fmt.Errorf("chain broken at snapshot %d", i)
 			}
 		}
 	}
-
-	return true, nil
-}
-
-// computeHash computes SHA-256 hash of snapshot for tamper-evidence
-func (sm *StateManager) computeHash(snapshot *StateSnapshot) (string, error) {
-	// Create a copy without the hash field
-	data := map[string]interface{}{
-		"timestamp":      snapshot.Timestamp.UnixNano(),
-		"round":          snapshot.Round,
-		"model_checksum": snapshot.ModelChecksum,
-		"update_count":   snapshot.UpdateCount,
-		"metadata":       snapshot.Metadata,
-		"previous_hash":  snapshot.PreviousHash,
-	}
-
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return "", err
-	}
-
-	hash := sha256.Sum256(jsonData)
-	return hex.EncodeToString(hash[:]), nil
+	return nil
 }
 
 // GetTimeSinceLastSnapshot returns duration since last snapshot
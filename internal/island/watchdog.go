@@ -0,0 +1,265 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package island
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reasons a return online fails, as labelled on
+// mohawk_island_transition_failures_total.
+const (
+	FailureDeadline  = "deadline"
+	FailureStalled   = "stalled"
+	FailureSyncError = "sync_error"
+)
+
+var (
+	islandTransitionFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mohawk_island_transition_failures_total",
+			Help: "Total failed returns from Island Mode to online, by reason.",
+		},
+		[]string{"reason"},
+	)
+
+	islandSyncedUpdatesTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mohawk_island_synced_updates_total",
+			Help: "Total cached updates flushed to the syncer.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(islandTransitionFailuresTotal, islandSyncedUpdatesTotal)
+}
+
+// WatchdogConfig bounds the return from Island Mode to online, which lasts
+// until every cached update is synced. A return that misses its deadline,
+// stalls, or fails puts the manager back in Island Mode with the unsent
+// updates cached, and the return is retried after a backoff.
+type WatchdogConfig struct {
+	// TransitionDeadline bounds the whole sync; zero leaves it unbounded.
+	TransitionDeadline time.Duration
+	// StallTimeout declares the sync stuck when no batch has been flushed
+	// for this long; zero disables stall detection.
+	StallTimeout time.Duration
+	// BatchSize is how many updates each syncer call carries, so progress
+	// shows between calls; zero sends every cached update in one call.
+	BatchSize int
+	// RetryBackoff is the wait after the first failed return; each later
+	// failure doubles it, up to MaxRetryBackoff when that is positive.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// DefaultWatchdogConfig returns the watchdog every new Manager starts with.
+func DefaultWatchdogConfig() WatchdogConfig {
+	return WatchdogConfig{
+		TransitionDeadline: 5 * time.Minute,
+		StallTimeout:       30 * time.Second,
+		BatchSize:          32,
+		RetryBackoff:       5 * time.Second,
+		MaxRetryBackoff:    5 * time.Minute,
+	}
+}
+
+// retryBackoff returns the wait after the given consecutive failure.
+func (c WatchdogConfig) retryBackoff(failures int) time.Duration {
+	wait := c.RetryBackoff
+	for i := 1; i < failures && (c.MaxRetryBackoff <= 0 || wait < c.MaxRetryBackoff); i++ {
+		wait *= 2
+	}
+	if c.MaxRetryBackoff > 0 && wait > c.MaxRetryBackoff {
+		wait = c.MaxRetryBackoff
+	}
+	return wait
+}
+
+// SetWatchdog sets the watchdog for later returns online.
+func (m *Manager) SetWatchdog(cfg WatchdogConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watchdog = cfg
+}
+
+// SyncProgress reports how far the latest sync of cached updates got.
+type SyncProgress struct {
+	Active  bool
+	Total   int
+	Flushed int
+	// FlushedLastInterval is how many updates were flushed between the
+	// watchdog's two latest stall checks.
+	FlushedLastInterval int
+	Started             time.Time
+	LastProgress        time.Time
+	// Stuck is set when the watchdog abandoned the sync.
+	Stuck bool
+}
+
+// SyncProgress returns the progress of the latest sync, the zero value if
+// none has run.
+func (m *Manager) SyncProgress() SyncProgress {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.sync == nil {
+		return SyncProgress{}
+	}
+	return m.sync.progress()
+}
+
+// syncRun is one sync of the updates cached when it started. Its fields
+// after updates are guarded by the manager's mu.
+type syncRun struct {
+	updates   []Update
+	syncer    UpdateSyncer
+	batchSize int
+
+	started      time.Time
+	lastProgress time.Time
+	flushed      int
+	// sampled is flushed as of the watchdog's previous stall check, and
+	// interval what was flushed between the two checks before that.
+	sampled  int
+	interval int
+	done     bool
+	failed   bool
+	// abandoned is set when the watchdog gives up on the run; abandonErr
+	// says why.
+	abandoned  bool
+	abandonErr error
+}
+
+func (r *syncRun) progress() SyncProgress {
+	return SyncProgress{
+		Active:              !r.done && !r.failed && !r.abandoned,
+		Total:               len(r.updates),
+		Flushed:             r.flushed,
+		FlushedLastInterval: r.interval,
+		Started:             r.started,
+		LastProgress:        r.lastProgress,
+		Stuck:               r.abandoned,
+	}
+}
+
+// superviseTransition flushes run, the updates cached when the manager
+// returned online from mode from, and fails the return if the flush errs,
+// stalls, or outlasts the deadline.
+func (m *Manager) superviseTransition(ctx context.Context, cancel context.CancelFunc, run *syncRun, from Mode, cfg WatchdogConfig) {
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- m.flush(ctx, run) }()
+
+	var deadline <-chan time.Time
+	if cfg.TransitionDeadline > 0 {
+		timer := time.NewTimer(cfg.TransitionDeadline)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	var stallCheck <-chan time.Time
+	if cfg.StallTimeout > 0 {
+		ticker := time.NewTicker(cfg.StallTimeout / 4)
+		defer ticker.Stop()
+		stallCheck = ticker.C
+	}
+
+	for {
+		select {
+		case err := <-done:
+			if err == nil {
+				m.completeTransition()
+			} else if m.ctx.Err() == nil {
+				m.failTransition(run, from, FailureSyncError, err, cfg)
+			}
+			return
+		case <-deadline:
+			m.failTransition(run, from, FailureDeadline,
+				fmt.Errorf("%w: %d of %d updates synced after %s", ErrTransitionTimeout, m.flushedOf(run), len(run.updates), cfg.TransitionDeadline), cfg)
+			return
+		case <-stallCheck:
+			if m.stalled(run, cfg.StallTimeout) {
+				m.failTransition(run, from, FailureStalled,
+					fmt.Errorf("%w: no update synced for %s, %d of %d done", ErrSyncStalled, cfg.StallTimeout, m.flushedOf(run), len(run.updates)), cfg)
+				return
+			}
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// stalled samples run's progress for the interval since the previous
+// check and reports whether nothing has been flushed for timeout.
+func (m *Manager) stalled(run *syncRun, timeout time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run.interval, run.sampled = run.flushed-run.sampled, run.flushed
+	return time.Since(run.lastProgress) >= timeout
+}
+
+func (m *Manager) flushedOf(run *syncRun) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return run.flushed
+}
+
+// completeTransition clears the retry state once a return online has
+// synced every cached update.
+func (m *Manager) completeTransition() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failedAttempts = 0
+	m.retryAt = time.Time{}
+	m.lastTransitionErr = nil
+}
+
+// failTransition abandons run, requeueing what it has not flushed, puts
+// the manager back in mode from if it is still online, and schedules a
+// retry. A syncer call still blocked on an abandoned run is left to
+// return; updates it was carrying are requeued as well, so they may reach
+// the aggregator twice.
+func (m *Manager) failTransition(run *syncRun, from Mode, reason string, err error, cfg WatchdogConfig) {
+	m.mu.Lock()
+	if run.done {
+		// The sync completed as the watchdog fired.
+		m.mu.Unlock()
+		m.completeTransition()
+		return
+	}
+	if !run.failed && !run.abandoned {
+		run.abandoned, run.abandonErr = true, err
+		m.requeueLocked(run.updates[run.flushed:])
+	}
+	m.transitionFailures++
+	m.failedAttempts++
+	m.lastTransitionErr = err
+	wait := cfg.retryBackoff(m.failedAttempts)
+	m.retryAt = time.Now().Add(wait)
+	if m.mode == ModeOnline {
+		m.mode = from
+		m.notifyListeners(ModeOnline, from)
+	}
+	if m.retryTimer != nil {
+		m.retryTimer.Stop()
+	}
+	m.retryTimer = time.AfterFunc(wait, m.retryTransition)
+	m.mu.Unlock()
+
+	islandTransitionFailuresTotal.WithLabelValues(reason).Inc()
+	log.Printf("island return online failed (%s), back in %s mode, retrying in %s: %v", reason, from, wait, err)
+}
+
+// retryTransition retries a failed return online once its backoff ends.
+func (m *Manager) retryTransition() {
+	if m.ctx.Err() != nil {
+		return
+	}
+	m.updateMode(m.connectivityCheck())
+}
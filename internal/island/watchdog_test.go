@@ -0,0 +1,234 @@
+package island
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hangingSink syncs its first ok calls, then blocks every later call until
+// its context ends or the sink is healed. SyncUpdates ignores contexts, like
+// a syncer wedged on a dead connection.
+type hangingSink struct {
+	mu      sync.Mutex
+	ok      int
+	calls   int
+	synced  []int
+	healed  chan struct{}
+	healing sync.Once
+}
+
+func newHangingSink(ok int) *hangingSink {
+	return &hangingSink{ok: ok, healed: make(chan struct{})}
+}
+
+func (s *hangingSink) heal() { s.healing.Do(func() { close(s.healed) }) }
+
+func (s *hangingSink) call(updates []Update) bool {
+	s.mu.Lock()
+	s.calls++
+	hang := s.calls > s.ok
+	s.mu.Unlock()
+	select {
+	case <-s.healed:
+		hang = false
+	default:
+	}
+	if !hang {
+		s.record(updates)
+	}
+	return hang
+}
+
+func (s *hangingSink) record(updates []Update) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, update := range updates {
+		s.synced = append(s.synced, update.Round)
+	}
+}
+
+func (s *hangingSink) SyncUpdates(updates []Update) error {
+	if s.call(updates) {
+		<-s.healed
+	}
+	return nil
+}
+
+func (s *hangingSink) syncedRounds() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.synced...)
+}
+
+// contextSink is a hangingSink whose hung calls end with their context.
+type contextSink struct{ *hangingSink }
+
+func (s contextSink) SyncUpdatesContext(ctx context.Context, updates []Update) error {
+	if s.call(updates) {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func cacheRounds(t *testing.T, mgr *Manager, rounds int) {
+	t.Helper()
+	for round := 1; round <= rounds; round++ {
+		if err := mgr.CacheUpdate(Update{Round: round, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("cache round %d: %v", round, err)
+		}
+	}
+}
+
+func cachedRounds(mgr *Manager) []int {
+	var rounds []int
+	for _, update := range mgr.GetCachedUpdates() {
+		rounds = append(rounds, update.Round)
+	}
+	return rounds
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func equalRounds(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWatchdogRevertsTransitionPastDeadline(t *testing.T) {
+	mgr := NewManager(time.Hour, 10, func() bool { return true })
+	defer mgr.Stop()
+	sink := newHangingSink(0)
+	defer sink.heal()
+	mgr.SetSyncer(sink)
+	mgr.SetWatchdog(WatchdogConfig{TransitionDeadline: 30 * time.Millisecond, BatchSize: 2, RetryBackoff: time.Hour})
+	cacheRounds(t, mgr, 3)
+
+	mgr.updateMode(false)
+	mgr.updateMode(true)
+	if !mgr.IsOnline() {
+		t.Fatal("expected the node online while its updates sync")
+	}
+	waitFor(t, "the watchdog to revert the transition", func() bool { return mgr.GetMode() == ModeIsland })
+
+	if got := cachedRounds(mgr); !equalRounds(got, []int{1, 2, 3}) {
+		t.Fatalf("cached rounds = %v, want 1-3", got)
+	}
+	if progress := mgr.SyncProgress(); !progress.Stuck || progress.Active || progress.Flushed != 0 || progress.Total != 3 {
+		t.Fatalf("progress = %+v", progress)
+	}
+	status := mgr.GetStatus()
+	if status["mode"] != "island" || status["transition_failures"] != 1 || status["sync_stuck"] != true {
+		t.Fatalf("status = %+v", status)
+	}
+	if msg, _ := status["last_transition_error"].(string); !strings.Contains(msg, ErrTransitionTimeout.Error()) {
+		t.Fatalf("last transition error = %q", msg)
+	}
+
+	// While the retry backs off, connectivity alone does not bring the
+	// node back online.
+	mgr.updateMode(true)
+	if mgr.GetMode() != ModeIsland {
+		t.Fatalf("mode during backoff = %v, want island", mgr.GetMode())
+	}
+
+	// The wedged call returning late leaves the requeued updates alone.
+	sink.heal()
+	time.Sleep(20 * time.Millisecond)
+	if got := cachedRounds(mgr); !equalRounds(got, []int{1, 2, 3}) {
+		t.Fatalf("cached rounds after the wedged call returned = %v", got)
+	}
+}
+
+func TestWatchdogDetectsStalledSyncAndRetries(t *testing.T) {
+	mgr := NewManager(time.Hour, 10, func() bool { return true })
+	defer mgr.Stop()
+	sink := contextSink{newHangingSink(1)}
+	defer sink.heal()
+	mgr.SetSyncer(sink)
+	mgr.SetWatchdog(WatchdogConfig{StallTimeout: 40 * time.Millisecond, BatchSize: 2, RetryBackoff: 50 * time.Millisecond})
+	cacheRounds(t, mgr, 5)
+
+	mgr.updateMode(false)
+	mgr.updateMode(true)
+	waitFor(t, "the stalled sync to be abandoned", func() bool { return mgr.GetMode() == ModeIsland })
+
+	// The first batch got through; the rest stay cached.
+	if progress := mgr.SyncProgress(); !progress.Stuck || progress.Flushed != 2 || progress.Total != 5 {
+		t.Fatalf("progress = %+v", progress)
+	}
+	if got := cachedRounds(mgr); !equalRounds(got, []int{3, 4, 5}) {
+		t.Fatalf("cached rounds = %v, want 3-5", got)
+	}
+	status := mgr.GetStatus()
+	if msg, _ := status["last_transition_error"].(string); !strings.Contains(msg, ErrSyncStalled.Error()) || status["transition_failures"] != 1 {
+		t.Fatalf("status = %+v", status)
+	}
+
+	// Once the sink recovers, the scheduled retry brings the node online
+	// and delivers every update once.
+	sink.heal()
+	waitFor(t, "the retry to sync the cache", func() bool {
+		return mgr.IsOnline() && len(mgr.GetCachedUpdates()) == 0 && !mgr.SyncProgress().Active
+	})
+	if got := sink.syncedRounds(); !equalRounds(got, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("synced rounds = %v, want 1-5", got)
+	}
+	if status := mgr.GetStatus(); status["next_transition_retry"] != nil || status["last_transition_error"] != nil {
+		t.Fatalf("retry state not cleared: %+v", status)
+	}
+}
+
+type failingSink struct{ err error }
+
+func (s failingSink) SyncUpdates([]Update) error { return s.err }
+
+func TestSyncErrorRevertsTransitionAndReportsSinkError(t *testing.T) {
+	mgr := NewManager(time.Hour, 10, func() bool { return true })
+	defer mgr.Stop()
+	sinkErr := errors.New("aggregator rejected the batch")
+	mgr.SetSyncer(failingSink{err: sinkErr})
+	mgr.SetWatchdog(WatchdogConfig{TransitionDeadline: time.Second, RetryBackoff: time.Hour})
+	cacheRounds(t, mgr, 2)
+
+	mgr.updateMode(false)
+	mgr.updateMode(true)
+	waitFor(t, "the failed sync to revert the transition", func() bool { return mgr.GetMode() == ModeIsland })
+
+	if got := cachedRounds(mgr); !equalRounds(got, []int{1, 2}) {
+		t.Fatalf("cached rounds = %v, want 1-2", got)
+	}
+	status := mgr.GetStatus()
+	if status["last_sync_error"] != sinkErr.Error() || status["sync_stuck"] != false {
+		t.Fatalf("status = %+v", status)
+	}
+}
+
+func TestRetryBackoffDoublesToCap(t *testing.T) {
+	cfg := WatchdogConfig{RetryBackoff: time.Second, MaxRetryBackoff: 5 * time.Second}
+	for failures, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := cfg.retryBackoff(failures); got != want {
+			t.Fatalf("backoff after %d failures = %s, want %s", failures, got, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+)
+
+// Component is a long-running part of a node the Supervisor starts,
+// restarts, and stops.
+type Component interface {
+	// Start runs the component until ctx is done, then returns nil. An
+	// error or panic before then is a crash; returning nil early means the
+	// component finished and is not restarted.
+	Start(ctx context.Context) error
+	// Stop releases what Start holds, within ctx's deadline. It is called
+	// once Start's context is cancelled, and after each crash before a
+	// restart.
+	Stop(ctx context.Context) error
+}
+
+// Readier is a Component whose dependents must wait until it is ready, not
+// merely started.
+type Readier interface {
+	Component
+	// Ready is closed once the component can serve its dependents.
+	Ready() <-chan struct{}
+}
+
+// Funcs adapts a pair of functions to Component. A nil StopFunc does
+// nothing.
+type Funcs struct {
+	StartFunc func(ctx context.Context) error
+	StopFunc  func(ctx context.Context) error
+}
+
+// Start calls StartFunc.
+func (f Funcs) Start(ctx context.Context) error { return f.StartFunc(ctx) }
+
+// Stop calls StopFunc, if set.
+func (f Funcs) Stop(ctx context.Context) error {
+	if f.StopFunc == nil {
+		return nil
+	}
+	return f.StopFunc(ctx)
+}
+
+// Loop adapts a loop that runs until its context is done, such as
+// tpm.AttestationManager.Run or capability.Reporter.Run.
+func Loop(run func(ctx context.Context)) Component {
+	return Funcs{StartFunc: func(ctx context.Context) error {
+		run(ctx)
+		return nil
+	}}
+}
+
+// Service adapts a component with its own background goroutines behind
+// argument-less Start and Stop methods, such as island.Manager.
+func Service(start, stop func()) Component {
+	return Funcs{
+		StartFunc: func(ctx context.Context) error {
+			start()
+			<-ctx.Done()
+			return nil
+		},
+		StopFunc: func(context.Context) error {
+			stop()
+			return nil
+		},
+	}
+}
+
+// startComponent runs c.Start, turning a panic into ErrPanicked.
+func startComponent(ctx context.Context, c Component) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanicked, r)
+		}
+	}()
+	return c.Start(ctx)
+}
+
+// stopComponent runs c.Stop and waits for it until ctx is done. A Stop
+// that overruns is left running; its result is discarded.
+func stopComponent(ctx context.Context, c Component) error {
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("%w: stop: %v", ErrPanicked, r)
+			}
+		}()
+		result <- c.Stop(ctx)
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v", ErrStopTimeout, ctx.Err())
+	}
+}
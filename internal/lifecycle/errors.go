@@ -0,0 +1,41 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package lifecycle
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the supervisor. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrInvalidSpec means a component spec has no name or component, or
+	// its restart policy or stop timeout is out of range. Not retryable.
+	ErrInvalidSpec = errors.New("invalid component spec")
+	// ErrDuplicateComponent means a component name is registered twice.
+	// Not retryable.
+	ErrDuplicateComponent = errors.New("component already registered")
+	// ErrUnknownDependency means a component depends on one that is not
+	// registered. Not retryable.
+	ErrUnknownDependency = errors.New("unknown component dependency")
+	// ErrDependencyCycle means components depend on each other in a
+	// cycle. Not retryable.
+	ErrDependencyCycle = errors.New("component dependency cycle")
+	// ErrAlreadyStarted means Register or Start was called on a
+	// supervisor that has started. Not retryable.
+	ErrAlreadyStarted = errors.New("supervisor already started")
+	// ErrPanicked means a component's Start or Stop panicked. The panic
+	// value is in the error text. Retryable: the supervisor restarts the
+	// component within its restart policy.
+	ErrPanicked = errors.New("component panicked")
+	// ErrRestartLimit means a component crashed more often than its
+	// restart policy allows and the supervisor gave up on it. Not
+	// retryable.
+	ErrRestartLimit = errors.New("component restart limit reached")
+	// ErrStartFailed means a component failed for good before it was
+	// ready, so the components depending on it were not started. Not
+	// retryable.
+	ErrStartFailed = errors.New("component failed to start")
+	// ErrStopTimeout means a component had not stopped by its stop
+	// deadline; the supervisor moved on without it. Not retryable.
+	ErrStopTimeout = errors.New("component stop timed out")
+)
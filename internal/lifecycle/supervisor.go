@@ -0,0 +1,480 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package lifecycle starts a node's background components in dependency
+// order, restarts the ones that crash, and stops them all in reverse order
+// with a deadline each.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultStopTimeout is how long each component has to stop unless its
+// spec or SetStopTimeout says otherwise.
+const DefaultStopTimeout = 10 * time.Second
+
+// RestartPolicy sets how the supervisor restarts a crashed component.
+type RestartPolicy struct {
+	// Backoff is the wait before the first restart; each further restart
+	// within Window doubles it, up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// MaxRestarts is how many restarts Window may hold before the
+	// supervisor gives up on the component. Zero never restarts it.
+	MaxRestarts int
+	Window      time.Duration
+}
+
+// DefaultRestartPolicy returns the policy components get unless their spec
+// or SetRestartPolicy says otherwise: five restarts in ten minutes, backing
+// off from one second to a minute.
+func DefaultRestartPolicy() RestartPolicy {
+	return RestartPolicy{
+		Backoff:     time.Second,
+		MaxBackoff:  time.Minute,
+		MaxRestarts: 5,
+		Window:      10 * time.Minute,
+	}
+}
+
+// Validate checks that the policy backs off for a positive time and counts
+// restarts over a positive window.
+func (p RestartPolicy) Validate() error {
+	if p.Backoff <= 0 || p.MaxBackoff < p.Backoff {
+		return fmt.Errorf("%w: restart backoff %s up to %s", ErrInvalidSpec, p.Backoff, p.MaxBackoff)
+	}
+	if p.MaxRestarts < 0 || p.Window <= 0 {
+		return fmt.Errorf("%w: %d restarts per %s", ErrInvalidSpec, p.MaxRestarts, p.Window)
+	}
+	return nil
+}
+
+// Spec registers a component with the supervisor.
+type Spec struct {
+	Name      string
+	Component Component
+	// DependsOn names the components started before this one and stopped
+	// after it.
+	DependsOn []string
+	// Restart, when set, replaces the supervisor's restart policy.
+	Restart *RestartPolicy
+	// StopTimeout, when positive, replaces the supervisor's stop timeout.
+	StopTimeout time.Duration
+}
+
+// State is where a component is in its lifecycle.
+type State string
+
+const (
+	// StateRegistered is a component the supervisor has not started.
+	StateRegistered State = "registered"
+	// StateRunning is a component whose Start is running.
+	StateRunning State = "running"
+	// StateRestarting is a crashed component waiting out its backoff.
+	StateRestarting State = "restarting"
+	// StateFailed is a component the supervisor gave up on.
+	StateFailed State = "failed"
+	// StateExited is a component whose Start returned nil on its own.
+	StateExited State = "exited"
+	// StateStopped is a component the supervisor stopped.
+	StateStopped State = "stopped"
+)
+
+// ComponentStatus is a snapshot of one component.
+type ComponentStatus struct {
+	Name     string `json:"name"`
+	State    State  `json:"state"`
+	Restarts int    `json:"restarts"`
+	// LastError is the component's last crash, or why it failed.
+	LastError string    `json:"last_error,omitempty"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+}
+
+// component is a registered spec and its supervision state, guarded by
+// Supervisor.mu.
+type component struct {
+	spec        Spec
+	policy      RestartPolicy
+	stopTimeout time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	state     State
+	restarts  int
+	recent    []time.Time
+	backoff   time.Duration
+	lastErr   error
+	startedAt time.Time
+}
+
+// Supervisor runs registered components. Register them all, then Start;
+// Stop shuts down whatever Start started.
+type Supervisor struct {
+	mu          sync.Mutex
+	policy      RestartPolicy
+	stopTimeout time.Duration
+	components  []*component
+	byName      map[string]*component
+	order       []*component
+	started     bool
+	stopped     bool
+	listener    func(ComponentStatus)
+}
+
+// NewSupervisor returns a supervisor with DefaultRestartPolicy and
+// DefaultStopTimeout.
+func NewSupervisor() *Supervisor {
+	return &Supervisor{
+		policy:      DefaultRestartPolicy(),
+		stopTimeout: DefaultStopTimeout,
+		byName:      make(map[string]*component),
+	}
+}
+
+// SetRestartPolicy replaces the restart policy of components registered
+// after it without one of their own. It fails with ErrInvalidSpec, keeping
+// the old one, if policy does not validate.
+func (s *Supervisor) SetRestartPolicy(policy RestartPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = policy
+	return nil
+}
+
+// SetStopTimeout replaces the stop deadline of components registered after
+// it without one of their own.
+func (s *Supervisor) SetStopTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return fmt.Errorf("%w: stop timeout %s", ErrInvalidSpec, timeout)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopTimeout = timeout
+	return nil
+}
+
+// SetStateListener calls listener with a component's status each time its
+// state changes. The listener runs without the supervisor's lock held.
+func (s *Supervisor) SetStateListener(listener func(ComponentStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listener = listener
+}
+
+// Register adds a component. Dependencies are resolved by Start, so they
+// may be registered in any order.
+func (s *Supervisor) Register(spec Spec) error {
+	if spec.Name == "" || spec.Component == nil || spec.StopTimeout < 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidSpec, spec.Name)
+	}
+	if spec.Restart != nil {
+		if err := spec.Restart.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", spec.Name, err)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("%w: register %s", ErrAlreadyStarted, spec.Name)
+	}
+	if _, exists := s.byName[spec.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateComponent, spec.Name)
+	}
+	c := &component{spec: spec, policy: s.policy, stopTimeout: s.stopTimeout, state: StateRegistered}
+	if spec.Restart != nil {
+		c.policy = *spec.Restart
+	}
+	if spec.StopTimeout > 0 {
+		c.stopTimeout = spec.StopTimeout
+	}
+	c.backoff = c.policy.Backoff
+	s.components = append(s.components, c)
+	s.byName[spec.Name] = c
+	return nil
+}
+
+// Start starts every component after the ones it depends on, waiting for
+// a Readier to be ready before starting its dependents. ctx bounds
+// startup only; components run until Stop. If a component fails for good
+// before it is ready, or ctx is done first, Start stops what it started
+// and returns the error.
+func (s *Supervisor) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+	order, err := s.startOrderLocked()
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	for _, c := range order {
+		runCtx, cancel := context.WithCancel(context.Background())
+		s.mu.Lock()
+		c.cancel, c.done = cancel, make(chan struct{})
+		s.order = append(s.order, c)
+		s.mu.Unlock()
+		go s.supervise(runCtx, c)
+
+		readier, ok := c.spec.Component.(Readier)
+		if !ok {
+			continue
+		}
+		select {
+		case <-readier.Ready():
+		case <-c.done:
+			cause := s.lastError(c)
+			if cause == nil {
+				cause = errors.New("exited before it was ready")
+			}
+			err = fmt.Errorf("%w: %s: %w", ErrStartFailed, c.spec.Name, cause)
+		case <-ctx.Done():
+			err = fmt.Errorf("%w: %s: %v", ErrStartFailed, c.spec.Name, ctx.Err())
+		}
+		if err != nil {
+			stopCtx, cancel := context.WithTimeout(context.Background(), s.shutdownBudget())
+			defer cancel()
+			return errors.Join(err, s.Stop(stopCtx))
+		}
+	}
+	return nil
+}
+
+// Stop stops the started components in reverse start order. Each has its
+// stop timeout, within ctx, to return from Stop and Start; one that
+// overruns is reported with ErrStopTimeout and left behind. Stopping an
+// unstarted or stopped supervisor does nothing.
+func (s *Supervisor) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.started || s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopped = true
+	order := append([]*component(nil), s.order...)
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(order) - 1; i >= 0; i-- {
+		if err := s.stopOne(ctx, order[i]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", order[i].spec.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Status returns every component's status in registration order.
+func (s *Supervisor) Status() []ComponentStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]ComponentStatus, len(s.components))
+	for i, c := range s.components {
+		statuses[i] = c.statusLocked()
+	}
+	return statuses
+}
+
+// stopOne cancels c's run, stops it, and waits for its Start to return,
+// all within its stop timeout.
+func (s *Supervisor) stopOne(ctx context.Context, c *component) error {
+	stopCtx, cancel := context.WithTimeout(ctx, c.stopTimeout)
+	defer cancel()
+	c.cancel()
+
+	s.mu.Lock()
+	failed := c.state == StateFailed
+	s.mu.Unlock()
+	var err error
+	if !failed {
+		err = stopComponent(stopCtx, c.spec.Component)
+	}
+	select {
+	case <-c.done:
+	case <-stopCtx.Done():
+		if err == nil {
+			err = fmt.Errorf("%w: start did not return: %v", ErrStopTimeout, stopCtx.Err())
+		}
+	}
+	return err
+}
+
+// supervise runs c until ctx is cancelled, restarting it after crashes as
+// its policy allows.
+func (s *Supervisor) supervise(ctx context.Context, c *component) {
+	defer close(c.done)
+	for {
+		s.transition(c, StateRunning, nil)
+		err := startComponent(ctx, c.spec.Component)
+		switch {
+		case ctx.Err() != nil:
+			s.transition(c, StateStopped, nil)
+			return
+		case err == nil:
+			s.transition(c, StateExited, nil)
+			return
+		}
+		if err := s.releaseCrashed(ctx, c); err != nil {
+			err = fmt.Errorf("stop after crash: %w", err)
+			s.transition(c, StateFailed, err)
+			return
+		}
+
+		backoff, ok := s.restartBackoff(c, time.Now())
+		if !ok {
+			s.transition(c, StateFailed, fmt.Errorf("%w: %w", ErrRestartLimit, err))
+			return
+		}
+		s.transition(c, StateRestarting, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.transition(c, StateStopped, nil)
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// releaseCrashed stops a crashed component before it is restarted. A Stop
+// that overruns fails the component, since restarting it over a half
+// released one could double up its work.
+func (s *Supervisor) releaseCrashed(ctx context.Context, c *component) error {
+	stopCtx, cancel := context.WithTimeout(ctx, c.stopTimeout)
+	defer cancel()
+	if err := stopComponent(stopCtx, c.spec.Component); errors.Is(err, ErrStopTimeout) {
+		return err
+	}
+	return nil
+}
+
+// restartBackoff counts a restart of c at now and returns the wait before
+// it, or false if the restart would exceed c's policy. The backoff starts
+// over once a window passes without restarts.
+func (s *Supervisor) restartBackoff(c *component, now time.Time) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cutoff := now.Add(-c.policy.Window)
+	kept := 0
+	for kept < len(c.recent) && !c.recent[kept].After(cutoff) {
+		kept++
+	}
+	c.recent = c.recent[kept:]
+	if len(c.recent) == 0 {
+		c.backoff = c.policy.Backoff
+	}
+	if len(c.recent) >= c.policy.MaxRestarts {
+		return 0, false
+	}
+	c.recent = append(c.recent, now)
+	c.restarts++
+	backoff := c.backoff
+	c.backoff = min(2*c.backoff, c.policy.MaxBackoff)
+	return backoff, true
+}
+
+// transition moves c to state, recording err as its last error when set,
+// and notifies the listener.
+func (s *Supervisor) transition(c *component, state State, err error) {
+	s.mu.Lock()
+	c.state = state
+	if err != nil {
+		c.lastErr = err
+	}
+	if state == StateRunning {
+		c.startedAt = time.Now()
+	}
+	status, listener := c.statusLocked(), s.listener
+	s.mu.Unlock()
+	if listener != nil {
+		listener(status)
+	}
+}
+
+func (s *Supervisor) lastError(c *component) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return c.lastErr
+}
+
+// shutdownBudget is the longest a full Stop can take: every component's
+// stop timeout in turn.
+func (s *Supervisor) shutdownBudget() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var budget time.Duration
+	for _, c := range s.order {
+		budget += c.stopTimeout
+	}
+	return budget
+}
+
+// startOrderLocked orders components so each follows its dependencies,
+// keeping registration order otherwise. Callers must hold s.mu.
+func (s *Supervisor) startOrderLocked() ([]*component, error) {
+	for _, c := range s.components {
+		for _, dep := range c.spec.DependsOn {
+			if _, ok := s.byName[dep]; !ok {
+				return nil, fmt.Errorf("%w: %s depends on %s", ErrUnknownDependency, c.spec.Name, dep)
+			}
+		}
+	}
+	order := make([]*component, 0, len(s.components))
+	placed := make(map[string]bool, len(s.components))
+	for len(order) < len(s.components) {
+		progressed := false
+		for _, c := range s.components {
+			if placed[c.spec.Name] || !dependenciesPlaced(c, placed) {
+				continue
+			}
+			order = append(order, c)
+			placed[c.spec.Name] = true
+			progressed = true
+		}
+		if !progressed {
+			var stuck []string
+			for _, c := range s.components {
+				if !placed[c.spec.Name] {
+					stuck = append(stuck, c.spec.Name)
+				}
+			}
+			return nil, fmt.Errorf("%w: %v", ErrDependencyCycle, stuck)
+		}
+	}
+	return order, nil
+}
+
+func dependenciesPlaced(c *component, placed map[string]bool) bool {
+	for _, dep := range c.spec.DependsOn {
+		if !placed[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *component) statusLocked() ComponentStatus {
+	status := ComponentStatus{
+		Name:      c.spec.Name,
+		State:     c.state,
+		Restarts:  c.restarts,
+		StartedAt: c.startedAt,
+	}
+	if c.lastErr != nil {
+		status.LastError = c.lastErr.Error()
+	}
+	return status
+}
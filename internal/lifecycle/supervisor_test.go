@@ -0,0 +1,258 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recorder logs the order components start and stop in.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+// ordered records its start and stop and runs until cancelled. It is ready
+// once it has recorded its start, so dependents start after it.
+type ordered struct {
+	name  string
+	log   *recorder
+	once  sync.Once
+	ready chan struct{}
+}
+
+func newOrdered(name string, log *recorder) *ordered {
+	return &ordered{name: name, log: log, ready: make(chan struct{})}
+}
+
+func (o *ordered) Start(ctx context.Context) error {
+	o.log.add("start " + o.name)
+	o.once.Do(func() { close(o.ready) })
+	<-ctx.Done()
+	return nil
+}
+
+func (o *ordered) Stop(context.Context) error {
+	o.log.add("stop " + o.name)
+	return nil
+}
+
+func (o *ordered) Ready() <-chan struct{} { return o.ready }
+
+// panicky panics on every start.
+type panicky struct{ starts atomic.Int32 }
+
+func (p *panicky) Start(context.Context) error {
+	p.starts.Add(1)
+	panic("corrupt flush buffer")
+}
+
+func (p *panicky) Stop(context.Context) error { return nil }
+
+// hanging runs until cancelled and then hangs in Stop until released.
+type hanging struct{ release chan struct{} }
+
+func (h *hanging) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (h *hanging) Stop(context.Context) error {
+	<-h.release
+	return nil
+}
+
+// settledGoroutines waits for the goroutine count to fall to at most want.
+func settledGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("%d goroutines, want at most %d:\n%s", runtime.NumGoroutine(), want, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSupervisorStartsInDependencyOrderAndStopsInReverse(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	log := &recorder{}
+	s := NewSupervisor()
+	for _, spec := range []Spec{
+		{Name: "api", Component: newOrdered("api", log), DependsOn: []string{"flusher", "gossip"}},
+		{Name: "flusher", Component: newOrdered("flusher", log), DependsOn: []string{"store"}},
+		{Name: "gossip", Component: newOrdered("gossip", log)},
+		{Name: "store", Component: newOrdered("store", log)},
+	} {
+		if err := s.Register(spec); err != nil {
+			t.Fatalf("register %s: %v", spec.Name, err)
+		}
+	}
+	if err := s.Register(Spec{Name: "gossip", Component: newOrdered("gossip", log)}); !errors.Is(err, ErrDuplicateComponent) {
+		t.Fatalf("duplicate: expected ErrDuplicateComponent, got %v", err)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	want := []string{
+		"start gossip", "start store", "start flusher", "start api",
+		"stop api", "stop flusher", "stop store", "stop gossip",
+	}
+	got := log.list()
+	if len(got) != len(want) {
+		t.Fatalf("events = %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+	for _, status := range s.Status() {
+		if status.State != StateStopped {
+			t.Fatalf("after stop: %+v", status)
+		}
+	}
+	if err := s.Register(Spec{Name: "late", Component: newOrdered("late", log)}); !errors.Is(err, ErrAlreadyStarted) {
+		t.Fatalf("register after start: expected ErrAlreadyStarted, got %v", err)
+	}
+	settledGoroutines(t, baseline)
+
+	cyclic := NewSupervisor()
+	_ = cyclic.Register(Spec{Name: "a", Component: newOrdered("a", log), DependsOn: []string{"b"}})
+	_ = cyclic.Register(Spec{Name: "b", Component: newOrdered("b", log), DependsOn: []string{"a"}})
+	if err := cyclic.Start(context.Background()); !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("cycle: expected ErrDependencyCycle, got %v", err)
+	}
+	unknown := NewSupervisor()
+	_ = unknown.Register(Spec{Name: "a", Component: newOrdered("a", log), DependsOn: []string{"missing"}})
+	if err := unknown.Start(context.Background()); !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("unknown dependency: expected ErrUnknownDependency, got %v", err)
+	}
+}
+
+func TestSupervisorGivesUpOnARepeatedlyPanickingComponent(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	s := NewSupervisor()
+	if err := s.SetRestartPolicy(RestartPolicy{Backoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond, MaxRestarts: 3, Window: time.Minute}); err != nil {
+		t.Fatalf("set policy: %v", err)
+	}
+	failed := make(chan ComponentStatus, 1)
+	var restarts []int
+	s.SetStateListener(func(status ComponentStatus) {
+		switch status.State {
+		case StateRestarting:
+			restarts = append(restarts, status.Restarts)
+		case StateFailed:
+			failed <- status
+		}
+	})
+	flusher := &panicky{}
+	log := &recorder{}
+	_ = s.Register(Spec{Name: "flusher", Component: flusher})
+	_ = s.Register(Spec{Name: "heartbeat", Component: newOrdered("heartbeat", log)})
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	var status ComponentStatus
+	select {
+	case status = <-failed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("flusher never failed: %+v", s.Status())
+	}
+	if status.Restarts != 3 || flusher.starts.Load() != 4 || len(restarts) != 3 {
+		t.Fatalf("failed after %d restarts and %d starts (%v)", status.Restarts, flusher.starts.Load(), restarts)
+	}
+	if !strings.Contains(status.LastError, ErrRestartLimit.Error()) || !strings.Contains(status.LastError, "corrupt flush buffer") {
+		t.Fatalf("failure reason: %+v", status)
+	}
+	// The crash loop leaves the other components running.
+	if heartbeat := s.Status()[1]; heartbeat.State != StateRunning || heartbeat.Restarts != 0 {
+		t.Fatalf("heartbeat = %+v", heartbeat)
+	}
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if flusher.starts.Load() != 4 {
+		t.Fatalf("failed component restarted: %d starts", flusher.starts.Load())
+	}
+	settledGoroutines(t, baseline)
+
+	// A component failing for good before it is ready aborts Start.
+	aborted := NewSupervisor()
+	_ = aborted.SetRestartPolicy(RestartPolicy{Backoff: time.Millisecond, MaxBackoff: time.Millisecond, MaxRestarts: 1, Window: time.Minute})
+	_ = aborted.Register(Spec{Name: "store", Component: &readyPanicky{}})
+	_ = aborted.Register(Spec{Name: "api", Component: newOrdered("api", log), DependsOn: []string{"store"}})
+	if err := aborted.Start(context.Background()); !errors.Is(err, ErrStartFailed) || !errors.Is(err, ErrRestartLimit) || !errors.Is(err, ErrPanicked) {
+		t.Fatalf("start over a failed dependency: %v", err)
+	}
+	if api := aborted.Status()[1]; api.State != StateRegistered {
+		t.Fatalf("dependent started: %+v", api)
+	}
+	settledGoroutines(t, baseline)
+}
+
+// readyPanicky panics before it is ever ready.
+type readyPanicky struct{ panicky }
+
+func (*readyPanicky) Ready() <-chan struct{} { return nil }
+
+func TestSupervisorBoundsShutdownOfAHangingComponent(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	log := &recorder{}
+	hung := &hanging{release: make(chan struct{})}
+	s := NewSupervisor()
+	_ = s.Register(Spec{Name: "store", Component: newOrdered("store", log)})
+	_ = s.Register(Spec{Name: "gossip", Component: hung, DependsOn: []string{"store"}, StopTimeout: 50 * time.Millisecond})
+	_ = s.Register(Spec{Name: "api", Component: newOrdered("api", log), DependsOn: []string{"gossip"}})
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	began := time.Now()
+	err := s.Stop(context.Background())
+	if elapsed := time.Since(began); elapsed > time.Second {
+		t.Fatalf("stop took %s with a 50ms deadline", elapsed)
+	}
+	if !errors.Is(err, ErrStopTimeout) {
+		t.Fatalf("expected ErrStopTimeout, got %v", err)
+	}
+	// The components around the hung one still stop, in order.
+	got := log.list()
+	if len(got) != 4 || got[2] != "stop api" || got[3] != "stop store" {
+		t.Fatalf("events = %v", got)
+	}
+	if again := s.Stop(context.Background()); again != nil {
+		t.Fatalf("second stop: %v", again)
+	}
+
+	// Only the hung Stop itself outlives the shutdown; once it returns
+	// nothing is left behind.
+	close(hung.release)
+	settledGoroutines(t, baseline)
+}
@@ -0,0 +1,230 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package modeldist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxFullBackfill is the largest gap for which every intermediate
+// model is fetched; larger gaps fetch summaries plus the latest model only.
+const DefaultMaxFullBackfill = 10
+
+// RoundsResponse is the payload served by GET /api/rounds.
+type RoundsResponse struct {
+	Rounds      []RoundSummary `json:"rounds"`
+	LatestRound int            `json:"latest_round"`
+	Count       int            `json:"count"`
+}
+
+// ModelResponse is the payload served by GET /api/model/{round}.
+type ModelResponse struct {
+	Round   int          `json:"round"`
+	Weights []byte       `json:"weights"`
+	Summary RoundSummary `json:"summary"`
+}
+
+// BackfillResult describes what a backfill fetched and verified.
+type BackfillResult struct {
+	FromRound   int
+	ToRound     int
+	Summaries   []RoundSummary
+	Models      map[int][]byte
+	SummaryOnly bool
+	// DeltaRounds lists the rounds fetched as deltas against a model the
+	// node already held. DeltaFallbacks counts deltas that did not
+	// reproduce their model, whose rounds were fetched in full instead.
+	DeltaRounds    []int
+	DeltaFallbacks int
+}
+
+// BackfillClient fetches committed rounds a node missed while offline.
+type BackfillClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	MaxFullGap int
+	Store      *ModelStore
+}
+
+// NewBackfillClient creates a client against an aggregator API base URL.
+func NewBackfillClient(baseURL string, store *ModelStore) *BackfillClient {
+	return &BackfillClient{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		MaxFullGap: DefaultMaxFullBackfill,
+		Store:      store,
+	}
+}
+
+// Backfill fetches every round after lastKnownRound. Each fetched model is
+// checked against its commit certificate, signed by the store's validators
+// when it has them, before it is accepted. A round
+// advertising a delta against a model the node holds, in its store or
+// fetched earlier in the same backfill, is fetched as that delta, falling
+// back to the full model if the delta does not reproduce it.
+func (c *BackfillClient) Backfill(ctx context.Context, lastKnownRound int) (*BackfillResult, error) {
+	var rounds RoundsResponse
+	query := url.Values{}
+	query.Set("from", strconv.Itoa(lastKnownRound+1))
+	if err := c.getJSON(ctx, "/api/rounds?"+query.Encode(), &rounds); err != nil {
+		return nil, fmt.Errorf("fetch round summaries: %w", err)
+	}
+
+	result := &BackfillResult{
+		FromRound: lastKnownRound + 1,
+		ToRound:   lastKnownRound,
+		Summaries: rounds.Rounds,
+		Models:    make(map[int][]byte),
+	}
+	if len(rounds.Rounds) == 0 {
+		return result, nil
+	}
+
+	for _, summary := range rounds.Rounds {
+		if summary.Round <= lastKnownRound {
+			return nil, fmt.Errorf("aggregator returned round %d at or before requested start %d", summary.Round, result.FromRound)
+		}
+		if err := summary.Certificate.Verify(nil, c.validators()); err != nil {
+			return nil, fmt.Errorf("round %d: %w", summary.Round, err)
+		}
+		if summary.Round > result.ToRound {
+			result.ToRound = summary.Round
+		}
+	}
+
+	maxGap := c.MaxFullGap
+	if maxGap <= 0 {
+		maxGap = DefaultMaxFullBackfill
+	}
+	fetch := make([]RoundSummary, 0, len(rounds.Rounds))
+	if len(rounds.Rounds) > maxGap {
+		result.SummaryOnly = true
+		fetch = append(fetch, rounds.Rounds[len(rounds.Rounds)-1])
+	} else {
+		fetch = append(fetch, rounds.Rounds...)
+	}
+
+	for _, summary := range fetch {
+		weights, tried := c.fetchDelta(ctx, summary, result.Models)
+		if weights != nil {
+			result.DeltaRounds = append(result.DeltaRounds, summary.Round)
+			result.Models[summary.Round] = weights
+			continue
+		}
+		if tried {
+			result.DeltaFallbacks++
+		}
+		weights, err := c.fetchModel(ctx, summary)
+		if err != nil {
+			return nil, err
+		}
+		result.Models[summary.Round] = weights
+	}
+
+	if c.Store != nil {
+		for _, summary := range rounds.Rounds {
+			if err := c.Store.Import(summary, result.Models[summary.Round]); err != nil {
+				return nil, fmt.Errorf("import round %d: %w", summary.Round, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// validators are the validators whose signatures every fetched round's
+// certificate must carry: the store's, when it has them.
+func (c *BackfillClient) validators() *Validators {
+	if c.Store == nil {
+		return nil
+	}
+	return c.Store.Validators()
+}
+
+// BackfillRounds adapts Backfill to the island rejoin flow, returning the
+// round the node has fast-forwarded to.
+func (c *BackfillClient) BackfillRounds(ctx context.Context, lastKnownRound int) (int, error) {
+	result, err := c.Backfill(ctx, lastKnownRound)
+	if err != nil {
+		return lastKnownRound, err
+	}
+	return result.ToRound, nil
+}
+
+func (c *BackfillClient) fetchModel(ctx context.Context, summary RoundSummary) ([]byte, error) {
+	var model ModelResponse
+	if err := c.getJSON(ctx, "/api/model/"+strconv.Itoa(summary.Round), &model); err != nil {
+		return nil, fmt.Errorf("fetch model for round %d: %w", summary.Round, err)
+	}
+	if model.Round != summary.Round {
+		return nil, fmt.Errorf("aggregator returned round %d for request %d", model.Round, summary.Round)
+	}
+	if err := summary.Certificate.Verify(model.Weights, c.validators()); err != nil {
+		return nil, fmt.Errorf("round %d: %w", summary.Round, err)
+	}
+	return model.Weights, nil
+}
+
+// fetchDelta fetches the round as a delta when its summary advertises one
+// against a model the node holds, returning the verified weights. tried
+// reports whether a delta was fetched, so nil weights with tried set mean
+// the delta failed and the full model is needed.
+func (c *BackfillClient) fetchDelta(ctx context.Context, summary RoundSummary, fetched map[int][]byte) (weights []byte, tried bool) {
+	for _, variant := range summary.Variants {
+		if variant.Encoding != VariantDelta {
+			continue
+		}
+		base := fetched[variant.BaseRound]
+		if base == nil && c.Store != nil {
+			if held, heldSummary, ok := c.Store.Model(variant.BaseRound); ok && heldSummary.ModelDigest == variant.BaseDigest {
+				base = held
+			}
+		}
+		if base == nil {
+			return nil, false
+		}
+
+		var delta ModelDelta
+		if err := c.getJSON(ctx, "/api/model/"+strconv.Itoa(summary.Round)+"/delta", &delta); err != nil {
+			return nil, true
+		}
+		if delta.Round != summary.Round || delta.ModelDigest != summary.ModelDigest {
+			return nil, true
+		}
+		weights, err := delta.ApplyDelta(base)
+		if err != nil || summary.Certificate.Verify(weights, c.validators()) != nil {
+			return nil, true
+		}
+		return weights, true
+	}
+	return nil, false
+}
+
+func (c *BackfillClient) getJSON(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package modeldist
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+const (
+	// DefaultCacheRounds is how many rounds a model cache keeps.
+	DefaultCacheRounds = 4
+	// DefaultCacheBytes is a model cache's byte budget.
+	DefaultCacheBytes = 1 << 30
+
+	cacheSuffix = ".model"
+)
+
+// CachedModel is a model a cache holds.
+type CachedModel struct {
+	Round  int
+	Digest string
+	Size   int64
+}
+
+// ModelCache keeps committed models on local disk so a restarted node need
+// not download a model it already holds. Each model is one file named by
+// its round and protocol.WeightsDigest, written to a temporary file and
+// renamed into place, so a crash never leaves a partial model under a
+// model's name; the digest is checked again on every read.
+type ModelCache struct {
+	dir    string
+	rounds int
+	bytes  int64
+
+	mu sync.Mutex
+}
+
+// NewModelCache opens the cache in dir, creating it if needed. It keeps
+// the latest rounds models, or DefaultCacheRounds, within bytes, or
+// DefaultCacheBytes; the latest model is kept even if it alone exceeds the
+// budget.
+func NewModelCache(dir string, rounds int, bytes int64) (*ModelCache, error) {
+	if rounds <= 0 {
+		rounds = DefaultCacheRounds
+	}
+	if bytes <= 0 {
+		bytes = DefaultCacheBytes
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create model cache: %w", err)
+	}
+	c := &ModelCache{dir: dir, rounds: rounds, bytes: bytes}
+	// Temporary files are left only by writes a crash interrupted.
+	temps, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	for _, temp := range temps {
+		_ = os.Remove(temp)
+	}
+	return c, nil
+}
+
+// Put caches weights as round's model, replacing any other copy of the
+// round, and evicts what no longer fits.
+func (c *ModelCache) Put(round int, weights []byte) error {
+	if round <= 0 {
+		return fmt.Errorf("invalid round %d", round)
+	}
+	digest := protocol.WeightsDigest(weights)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tmp, err := os.CreateTemp(c.dir, "model-*.tmp")
+	if err != nil {
+		return fmt.Errorf("cache round %d: %w", round, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(weights); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("cache round %d: %w", round, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("cache round %d: %w", round, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cache round %d: %w", round, err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(round, digest)); err != nil {
+		return fmt.Errorf("cache round %d: %w", round, err)
+	}
+	for _, cached := range c.listLocked() {
+		if cached.Round == round && cached.Digest != digest {
+			_ = os.Remove(c.path(cached.Round, cached.Digest))
+		}
+	}
+	c.evictLocked()
+	return nil
+}
+
+// Get returns round's cached model if it was cached under digest. It fails
+// with ErrCacheMiss when there is no such copy, and with ErrCacheCorrupt,
+// discarding the copy, when the file no longer matches digest.
+func (c *ModelCache) Get(round int, digest string) ([]byte, error) {
+	if !validDigest(digest) {
+		return nil, fmt.Errorf("%w: round %d digest %q", ErrCacheMiss, round, digest)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.path(round, digest)
+	weights, err := os.ReadFile(path) // #nosec G304 -- named by round and a validated hex digest
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: round %d digest %s", ErrCacheMiss, round, digest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cached round %d: %w", round, err)
+	}
+	if protocol.WeightsDigest(weights) != digest {
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("%w: round %d does not match digest %s", ErrCacheCorrupt, round, digest)
+	}
+	return weights, nil
+}
+
+// Models lists the cached models, latest round first.
+func (c *ModelCache) Models() []CachedModel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.listLocked()
+}
+
+// evictLocked removes the oldest models beyond the round count or byte
+// budget. Callers must hold c.mu.
+func (c *ModelCache) evictLocked() {
+	var total int64
+	for i, cached := range c.listLocked() {
+		total += cached.Size
+		if i == 0 || (i < c.rounds && total <= c.bytes) {
+			continue
+		}
+		_ = os.Remove(c.path(cached.Round, cached.Digest))
+	}
+}
+
+// listLocked reads the cache directory. Callers must hold c.mu.
+func (c *ModelCache) listLocked() []CachedModel {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil
+	}
+	var models []CachedModel
+	for _, entry := range entries {
+		round, digest, ok := parseCacheName(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		models = append(models, CachedModel{Round: round, Digest: digest, Size: info.Size()})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Round > models[j].Round })
+	return models
+}
+
+func (c *ModelCache) path(round int, digest string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("round-%010d-%s%s", round, digest, cacheSuffix))
+}
+
+// parseCacheName parses a file name path produced.
+func parseCacheName(name string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(name, "round-")
+	if !ok {
+		return 0, "", false
+	}
+	rest, ok = strings.CutSuffix(rest, cacheSuffix)
+	if !ok {
+		return 0, "", false
+	}
+	roundText, digest, ok := strings.Cut(rest, "-")
+	if !ok || !validDigest(digest) {
+		return 0, "", false
+	}
+	round, err := strconv.Atoi(roundText)
+	if err != nil || round <= 0 {
+		return 0, "", false
+	}
+	return round, digest, true
+}
+
+// validDigest reports whether digest is a hex SHA-256, and so safe in a
+// file name.
+func validDigest(digest string) bool {
+	decoded, err := hex.DecodeString(digest)
+	return err == nil && len(decoded) == 32 && strings.ToLower(digest) == digest
+}
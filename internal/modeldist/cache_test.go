@@ -0,0 +1,113 @@
+package modeldist
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func TestModelCacheHitStaleAndCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewModelCache(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	weights := []byte("weights-round-1")
+	digest := protocol.WeightsDigest(weights)
+	if err := cache.Put(1, weights); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	got, err := cache.Get(1, digest)
+	if err != nil || string(got) != string(weights) {
+		t.Fatalf("hit: %q, %v", got, err)
+	}
+
+	// The round was recommitted under another digest: the cached copy is
+	// stale for it.
+	if _, err := cache.Get(1, protocol.WeightsDigest([]byte("recommitted"))); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("stale: expected ErrCacheMiss, got %v", err)
+	}
+	if _, err := cache.Get(1, "../../etc/passwd"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("malformed digest: expected ErrCacheMiss, got %v", err)
+	}
+
+	// A copy damaged on disk is discarded; the next read misses, so the
+	// caller fetches it again.
+	path := cache.path(1, digest)
+	if err := os.WriteFile(path, []byte("weights-round-X"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(1, digest); !errors.Is(err, ErrCacheCorrupt) {
+		t.Fatalf("corrupt: expected ErrCacheCorrupt, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("corrupt copy kept: %v", err)
+	}
+	if _, err := cache.Get(1, digest); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("after discard: expected ErrCacheMiss, got %v", err)
+	}
+
+	// A write a crash interrupted never shows up as a model, and is swept
+	// when the cache is reopened.
+	if err := os.WriteFile(filepath.Join(dir, "model-123.tmp"), weights, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewModelCache(dir, 0, 0); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if temps, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(temps) != 0 {
+		t.Fatalf("temporary files left: %v", temps)
+	}
+}
+
+func TestModelCacheEvictsBeyondRoundsAndBytes(t *testing.T) {
+	cache, err := NewModelCache(t.TempDir(), 3, 100)
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	put := func(round, size int) {
+		t.Helper()
+		weights := []byte(fmt.Sprintf("%0*d", size, round))
+		if err := cache.Put(round, weights); err != nil {
+			t.Fatalf("put round %d: %v", round, err)
+		}
+	}
+	rounds := func() []int {
+		var out []int
+		for _, cached := range cache.Models() {
+			out = append(out, cached.Round)
+		}
+		return out
+	}
+
+	for round := 1; round <= 5; round++ {
+		put(round, 20)
+	}
+	if got := fmt.Sprint(rounds()); got != "[5 4 3]" {
+		t.Fatalf("after 5 rounds of 20 bytes, cached %s, want the last 3", got)
+	}
+
+	// Two 45-byte rounds exhaust the byte budget before the round count.
+	put(6, 45)
+	put(7, 45)
+	if got := fmt.Sprint(rounds()); got != "[7 6]" {
+		t.Fatalf("after two 45-byte rounds, cached %s, want [7 6]", got)
+	}
+
+	// The latest round stays even when it alone is over budget.
+	put(8, 150)
+	if got := fmt.Sprint(rounds()); got != "[8]" {
+		t.Fatalf("after an oversized round, cached %s, want [8]", got)
+	}
+
+	// Recaching a round replaces its copy rather than keeping both.
+	put(8, 10)
+	if models := cache.Models(); len(models) != 1 || models[0].Size != 10 {
+		t.Fatalf("recached round: %+v", models)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package modeldist
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Encodings a round's weights can be fetched in, as advertised in
+// RoundSummary.Variants.
+const (
+	VariantFull  = "full"
+	VariantDelta = "delta"
+)
+
+// ModelVariant advertises one encoding of a round's weights. A delta
+// variant names the model it applies to by round and digest.
+type ModelVariant struct {
+	Encoding   string `json:"encoding"`
+	Size       int    `json:"size"`
+	BaseRound  int    `json:"base_round,omitempty"`
+	BaseDigest string `json:"base_digest,omitempty"`
+}
+
+// deltaLevels is the largest quantized step, in units of ModelDelta.Step.
+const deltaLevels = 127
+
+// ModelDelta encodes a round's weights as the difference from an earlier
+// round's, the payload served by GET /api/model/{round}/delta.
+//
+// Each weight's difference from its base is quantized to a signed byte in
+// units of Step, and the distance in ULPs from the quantized
+// reconstruction to the exact weight is stored alongside, so ApplyDelta
+// reproduces the weights bit for bit. When every weight of both models is
+// a float32 value, as training in single precision leaves them, the
+// reconstruction is rounded to float32 and the residuals count float32
+// ULPs, which keeps them to a byte or two. The quantized steps followed by
+// the varint residuals are DEFLATE-compressed into Payload.
+type ModelDelta struct {
+	Round       int     `json:"round"`
+	BaseRound   int     `json:"base_round"`
+	BaseDigest  string  `json:"base_digest"`
+	ModelDigest string  `json:"model_digest"`
+	SpecVersion int     `json:"spec_version"`
+	Canonical   bool    `json:"canonical"`
+	Dimension   int     `json:"dimension"`
+	Precision   int     `json:"precision"`
+	Step        float64 `json:"step"`
+	Payload     []byte  `json:"payload"`
+}
+
+// EncodeDelta encodes the weights of round as a delta against those of
+// baseRound. Both must parse as model weights of the same dimension.
+func EncodeDelta(baseRound int, base []byte, round int, weights []byte) (*ModelDelta, error) {
+	from, err := protocol.ParseModelWeights(base)
+	if err != nil {
+		return nil, fmt.Errorf("delta base round %d: %w", baseRound, err)
+	}
+	to, err := protocol.ParseModelWeights(weights)
+	if err != nil {
+		return nil, fmt.Errorf("delta round %d: %w", round, err)
+	}
+	if len(from.Weights) != len(to.Weights) {
+		return nil, fmt.Errorf("delta round %d has %d weights, base round %d has %d", round, len(to.Weights), baseRound, len(from.Weights))
+	}
+	_, canonical := protocol.DecodeModelWeights(weights)
+
+	precision := 32
+	if !singlePrecision(from.Weights) || !singlePrecision(to.Weights) {
+		precision = 64
+	}
+	var largest float64
+	for i, w := range to.Weights {
+		if d := math.Abs(w - from.Weights[i]); d > largest && !math.IsInf(d, 0) {
+			largest = d
+		}
+	}
+	step := largest / deltaLevels
+	if math.IsInf(step, 0) || math.IsNaN(step) {
+		step = 0
+	}
+
+	raw := make([]byte, len(to.Weights), 3*len(to.Weights))
+	for i, w := range to.Weights {
+		q := quantizeStep(w-from.Weights[i], step)
+		raw[i] = byte(q)
+		residual := deltaOrdinal(w, precision) - deltaOrdinal(reconstruct(from.Weights[i], q, step, precision), precision)
+		if precision == 32 {
+			raw = binary.AppendVarint(raw, int64(int32(residual)))
+		} else {
+			raw = binary.AppendVarint(raw, int64(residual))
+		}
+	}
+
+	var payload bytes.Buffer
+	zw, err := flate.NewWriter(&payload, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &ModelDelta{
+		Round:       round,
+		BaseRound:   baseRound,
+		BaseDigest:  from.Digest(),
+		ModelDigest: to.Digest(),
+		SpecVersion: to.SpecVersion,
+		Canonical:   canonical == nil,
+		Dimension:   len(to.Weights),
+		Precision:   precision,
+		Step:        step,
+		Payload:     payload.Bytes(),
+	}, nil
+}
+
+// ApplyDelta reconstructs the delta's weights from base, in the encoding
+// they were committed in. It fails unless base has d.BaseDigest and the
+// result has d.ModelDigest, so a node that gets an error should fetch the
+// full model instead.
+func (d *ModelDelta) ApplyDelta(base []byte) ([]byte, error) {
+	from, err := protocol.ParseModelWeights(base)
+	if err != nil {
+		return nil, fmt.Errorf("delta base round %d: %w", d.BaseRound, err)
+	}
+	if digest := from.Digest(); digest != d.BaseDigest {
+		return nil, fmt.Errorf("delta for round %d applies to base digest %s, have %s", d.Round, d.BaseDigest, digest)
+	}
+	if d.Dimension != len(from.Weights) {
+		return nil, fmt.Errorf("delta for round %d has %d weights, base has %d", d.Round, d.Dimension, len(from.Weights))
+	}
+	if d.Precision != 32 && d.Precision != 64 {
+		return nil, fmt.Errorf("delta for round %d has unsupported precision %d", d.Round, d.Precision)
+	}
+
+	// A residual varint is at most ten bytes; reading one byte past that
+	// bound tells an oversized payload from a full one.
+	limit := int64(11 * d.Dimension)
+	raw, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(d.Payload)), limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("delta for round %d: decompress: %w", d.Round, err)
+	}
+	if int64(len(raw)) > limit || len(raw) < d.Dimension {
+		return nil, fmt.Errorf("delta for round %d: payload of %d bytes does not hold %d weights", d.Round, len(raw), d.Dimension)
+	}
+
+	to := protocol.ModelWeights{SpecVersion: d.SpecVersion, Weights: make([]float64, d.Dimension)}
+	residuals := raw[d.Dimension:]
+	for i, w := range from.Weights {
+		residual, n := binary.Varint(residuals)
+		if n <= 0 {
+			return nil, fmt.Errorf("delta for round %d: malformed residual for weight %d", d.Round, i)
+		}
+		residuals = residuals[n:]
+		rec := reconstruct(w, int8(raw[i]), d.Step, d.Precision)
+		to.Weights[i] = fromDeltaOrdinal(deltaOrdinal(rec, d.Precision)+uint64(residual), d.Precision)
+	}
+	if len(residuals) != 0 {
+		return nil, fmt.Errorf("delta for round %d: %d trailing payload bytes", d.Round, len(residuals))
+	}
+	if digest := to.Digest(); digest != d.ModelDigest {
+		return nil, fmt.Errorf("delta for round %d reconstructs digest %s, want %s", d.Round, digest, d.ModelDigest)
+	}
+
+	if d.Canonical {
+		return to.Canonical(), nil
+	}
+	out := make([]byte, 0, 8*len(to.Weights))
+	for _, w := range to.Weights {
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(w))
+	}
+	return out, nil
+}
+
+// singlePrecision reports whether every weight is exactly a float32 value.
+func singlePrecision(weights []float64) bool {
+	for _, w := range weights {
+		if math.Float64bits(float64(float32(w))) != math.Float64bits(w) {
+			return false
+		}
+	}
+	return true
+}
+
+func quantizeStep(diff, step float64) int8 {
+	if step == 0 || math.IsNaN(diff) || math.IsInf(diff, 0) {
+		return 0
+	}
+	return int8(math.Max(-deltaLevels, math.Min(deltaLevels, math.Round(diff/step))))
+}
+
+// reconstruct is base moved by q steps, rounded to precision. The explicit
+// conversions keep the compiler from fusing the multiply and add, which
+// would round differently on some architectures than on others.
+func reconstruct(base float64, q int8, step float64, precision int) float64 {
+	if q == 0 || math.IsNaN(base) || math.IsInf(base, 0) {
+		return base
+	}
+	rec := base + float64(float64(q)*step)
+	if precision == 32 {
+		return float64(float32(rec))
+	}
+	return rec
+}
+
+// deltaOrdinal maps a weight's bits at precision to an unsigned integer
+// that orders like the weight, so adjacent floats are adjacent integers
+// and a residual counts ULPs. The mapping is a bijection on bit patterns,
+// NaNs included.
+func deltaOrdinal(w float64, precision int) uint64 {
+	if precision == 32 {
+		bits := math.Float32bits(float32(w))
+		if bits>>31 == 1 {
+			return uint64(^bits)
+		}
+		return uint64(bits | 1<<31)
+	}
+	bits := math.Float64bits(w)
+	if bits>>63 == 1 {
+		return ^bits
+	}
+	return bits | 1<<63
+}
+
+func fromDeltaOrdinal(ord uint64, precision int) float64 {
+	if precision == 32 {
+		bits := uint32(ord)
+		if bits>>31 == 1 {
+			bits &^= 1 << 31
+		} else {
+			bits = ^bits
+		}
+		return float64(math.Float32frombits(bits))
+	}
+	if ord>>63 == 1 {
+		return math.Float64frombits(ord &^ (1 << 63))
+	}
+	return math.Float64frombits(^ord)
+}
@@ -0,0 +1,225 @@
+package modeldist
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// driftingModels returns rounds of a model of dim weights as SGD leaves
+// them: each round every weight moves by a small step relative to its
+// scale. singlePrecision rounds the weights to float32 values, as
+// training in float32 does.
+func driftingModels(rounds, dim int, singlePrecision bool) [][]float64 {
+	rng := rand.New(rand.NewSource(689))
+	models := make([][]float64, rounds)
+	models[0] = make([]float64, dim)
+	for i := range models[0] {
+		models[0][i] = rng.NormFloat64() * 0.05
+	}
+	for round := 1; round < rounds; round++ {
+		models[round] = make([]float64, dim)
+		for i, w := range models[round-1] {
+			models[round][i] = w + rng.NormFloat64()*1e-4
+		}
+	}
+	if singlePrecision {
+		for _, model := range models {
+			for i, w := range model {
+				model[i] = float64(float32(w))
+			}
+		}
+	}
+	return models
+}
+
+func bareWeights(weights []float64) []byte {
+	buf := make([]byte, 0, 8*len(weights))
+	for _, w := range weights {
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(w))
+	}
+	return buf
+}
+
+func TestDeltaReproducesWeightsExactly(t *testing.T) {
+	edges := []float64{0, math.Copysign(0, -1), math.Inf(1), math.Inf(-1), math.NaN(), math.MaxFloat64, -math.SmallestNonzeroFloat64, 1e-300}
+	edgeBase := []float64{1, 0, math.Inf(1), 3, 2, -math.MaxFloat64, math.SmallestNonzeroFloat64, -1e300}
+
+	for _, tc := range []struct {
+		name        string
+		base, model []byte
+		precision   int
+	}{
+		{name: "float32 weights", base: bareWeights(driftingModels(2, 1000, true)[0]), model: bareWeights(driftingModels(2, 1000, true)[1]), precision: 32},
+		{name: "float64 weights", base: bareWeights(driftingModels(2, 1000, false)[0]), model: bareWeights(driftingModels(2, 1000, false)[1]), precision: 64},
+		{name: "canonical encoding", base: protocol.ModelWeights{SpecVersion: 3, Weights: driftingModels(2, 100, false)[0]}.Canonical(),
+			model: protocol.ModelWeights{SpecVersion: 3, Weights: driftingModels(2, 100, false)[1]}.Canonical(), precision: 64},
+		// Extreme values make every quantized step useless; the residuals
+		// carry the weights on their own.
+		{name: "non-finite and extreme values", base: bareWeights(edgeBase), model: bareWeights(edges), precision: 64},
+		{name: "unchanged model", base: bareWeights(driftingModels(1, 100, true)[0]), model: bareWeights(driftingModels(1, 100, true)[0]), precision: 32},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			delta, err := EncodeDelta(1, tc.base, 2, tc.model)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			if delta.Precision != tc.precision {
+				t.Fatalf("precision = %d, want %d", delta.Precision, tc.precision)
+			}
+			applied, err := delta.ApplyDelta(tc.base)
+			if err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+			if !bytes.Equal(applied, tc.model) {
+				t.Fatal("applied delta differs from the model bit for bit")
+			}
+		})
+	}
+}
+
+func TestDeltaShrinksRealisticDrift(t *testing.T) {
+	const dim = 100000
+	for _, tc := range []struct {
+		name            string
+		singlePrecision bool
+		maxRatio        float64
+	}{
+		// Float32 training leaves residuals of a byte or two per weight.
+		{name: "float32", singlePrecision: true, maxRatio: 0.4},
+		// Float64 low-order bits are noise no encoding can remove, but the
+		// shared sign, exponent, and high mantissa bits still go.
+		{name: "float64", singlePrecision: false, maxRatio: 0.9},
+	} {
+		models := driftingModels(2, dim, tc.singlePrecision)
+		base, model := bareWeights(models[0]), bareWeights(models[1])
+		delta, err := EncodeDelta(1, base, 2, model)
+		if err != nil {
+			t.Fatalf("%s: encode: %v", tc.name, err)
+		}
+		ratio := float64(len(delta.Payload)) / float64(len(model))
+		t.Logf("%s: full %d bytes, delta %d bytes (%.1f%%)", tc.name, len(model), len(delta.Payload), 100*ratio)
+		if ratio > tc.maxRatio {
+			t.Fatalf("%s: delta is %.1f%% of the full model, want at most %.0f%%", tc.name, 100*ratio, 100*tc.maxRatio)
+		}
+	}
+}
+
+func TestApplyDeltaRejectsWrongBaseAndCorruption(t *testing.T) {
+	models := driftingModels(3, 200, true)
+	base, model := bareWeights(models[0]), bareWeights(models[1])
+	delta, err := EncodeDelta(1, base, 2, model)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if _, err := delta.ApplyDelta(bareWeights(models[2])); err == nil {
+		t.Fatal("applied a delta to the wrong base")
+	}
+
+	// A different step reconstructs other values; the digest check
+	// catches it.
+	skewed := *delta
+	skewed.Step *= 1.5
+	if _, err := skewed.ApplyDelta(base); err == nil {
+		t.Fatal("applied a delta with a corrupted step")
+	}
+	truncated := *delta
+	truncated.Payload = truncated.Payload[:len(truncated.Payload)/2]
+	if _, err := truncated.ApplyDelta(base); err == nil {
+		t.Fatal("applied a truncated delta")
+	}
+
+	if _, err := EncodeDelta(1, base, 2, bareWeights(models[1][:100])); err == nil {
+		t.Fatal("encoded a delta between models of different dimensions")
+	}
+}
+
+func commitFloats(t *testing.T, store *ModelStore, round int, weights []float64) []byte {
+	t.Helper()
+	encoded := bareWeights(weights)
+	if _, err := store.Commit(round, encoded, 10, nil, testCertificate(round, encoded)); err != nil {
+		t.Fatalf("commit round %d: %v", round, err)
+	}
+	return encoded
+}
+
+func TestStoreAdvertisesDeltaVariants(t *testing.T) {
+	models := driftingModels(3, 1000, true)
+	store := NewModelStore(8)
+	for round, weights := range models {
+		commitFloats(t, store, round+1, weights)
+	}
+
+	summaries := store.Summaries(1, 0)
+	if variants := summaries[0].Variants; len(variants) != 1 || variants[0].Encoding != VariantFull || variants[0].Size != 8000 {
+		t.Fatalf("first round variants = %+v, want full only", variants)
+	}
+	for _, summary := range summaries[1:] {
+		if len(summary.Variants) != 2 {
+			t.Fatalf("round %d variants = %+v", summary.Round, summary.Variants)
+		}
+		variant := summary.Variants[1]
+		base := summaries[summary.Round-2]
+		if variant.Encoding != VariantDelta || variant.BaseRound != base.Round || variant.BaseDigest != base.ModelDigest || variant.Size >= 8000 {
+			t.Fatalf("round %d delta variant = %+v", summary.Round, variant)
+		}
+		delta, ok := store.Delta(summary.Round)
+		if !ok || len(delta.Payload) != variant.Size || delta.ModelDigest != summary.ModelDigest {
+			t.Fatalf("round %d delta = %+v", summary.Round, delta)
+		}
+	}
+
+	// A model of another dimension is served in full only.
+	commitFloats(t, store, 4, models[2][:500])
+	if _, ok := store.Delta(4); ok {
+		t.Fatal("delta across a dimension change")
+	}
+}
+
+func TestBackfillAppliesDeltasAgainstHeldModel(t *testing.T) {
+	models := driftingModels(5, 1000, true)
+	server := NewModelStore(16)
+	for round, weights := range models {
+		commitFloats(t, server, round+1, weights)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		tamper    func(*ModelDelta)
+		deltas    []int
+		fallbacks int
+		fulls     int
+	}{
+		// Holding round 1, the node fetches 2 against it and each later
+		// round against the one fetched before.
+		{name: "deltas", deltas: []int{2, 3, 4, 5}},
+		{name: "corrupted deltas", tamper: func(d *ModelDelta) { d.Step *= 2 }, fallbacks: 4, fulls: 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, fulls := serveStoreDeltas(t, server, nil, tc.tamper)
+			local := NewModelStore(16)
+			commitFloats(t, local, 1, models[0])
+
+			client := NewBackfillClient(srv.URL, local)
+			result, err := client.Backfill(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("backfill: %v", err)
+			}
+			if len(result.DeltaRounds) != len(tc.deltas) || result.DeltaFallbacks != tc.fallbacks || *fulls != tc.fulls {
+				t.Fatalf("delta rounds %v, fallbacks %d, full fetches %d", result.DeltaRounds, result.DeltaFallbacks, *fulls)
+			}
+			for round := 2; round <= 5; round++ {
+				want, _, _ := server.Model(round)
+				got, _, ok := local.Model(round)
+				if !ok || !bytes.Equal(got, want) {
+					t.Fatalf("round %d imported weights differ from the committed model", round)
+				}
+			}
+		})
+	}
+}
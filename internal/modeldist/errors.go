@@ -0,0 +1,30 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package modeldist
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the model cache and store. Match
+// them with errors.Is; never compare error strings.
+var (
+	// ErrCacheMiss means the cache holds no copy of a round's model under
+	// the requested digest. Retryable after fetching and caching it.
+	ErrCacheMiss = errors.New("model cache miss")
+	// ErrCacheCorrupt means a cached model no longer matches the digest it
+	// was cached under. The copy has been discarded; retryable by fetching
+	// the model again.
+	ErrCacheCorrupt = errors.New("cached model corrupt")
+	// ErrNoReceipt means the store holds no manifest for a round, or the
+	// manifest has no entry for the node. Retryable until the round commits
+	// with its manifest; not for a node that sent no update.
+	ErrNoReceipt = errors.New("no inclusion receipt")
+	// ErrInvalidValidators means a validator set has a quorum it cannot
+	// reach or a key that does not parse. Not retryable without fixing
+	// the set.
+	ErrInvalidValidators = errors.New("invalid validator set")
+	// ErrInsufficientApprovals means a commit certificate carries fewer
+	// valid validator signatures than the verifier's quorum. Not retryable
+	// with the same certificate.
+	ErrInsufficientApprovals = errors.New("insufficient signed approvals")
+)
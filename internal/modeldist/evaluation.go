@@ -0,0 +1,85 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package modeldist
+
+import (
+	"fmt"
+	"time"
+)
+
+// MetricEstimate is a federation-wide estimate of one metric: the mean
+// weighted by sample count, its standard error, and a confidence interval.
+type MetricEstimate struct {
+	Mean   float64 `json:"mean"`
+	StdErr float64 `json:"std_err"`
+	Lower  float64 `json:"lower"`
+	Upper  float64 `json:"upper"`
+}
+
+// Evaluation records how a committed model scored in an evaluation round.
+type Evaluation struct {
+	Round       int    `json:"round"`
+	ModelDigest string `json:"model_digest"`
+	// Sampled counts the nodes sent the task; Reported counts those whose
+	// reports were combined.
+	Sampled  int `json:"sampled"`
+	Reported int `json:"reported"`
+	// Samples is the total sample count the estimates weigh.
+	Samples  int            `json:"samples"`
+	Loss     MetricEstimate `json:"loss"`
+	Accuracy MetricEstimate `json:"accuracy"`
+	// Confidence is the level of the intervals, such as 0.95.
+	Confidence float64 `json:"confidence"`
+	// Noised is set when any report carried local DP noise.
+	Noised      bool      `json:"noised"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// RecordEvaluation records the evaluation of a committed round's model,
+// replacing any earlier one for the round.
+func (s *ModelStore) RecordEvaluation(evaluation Evaluation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.rounds[evaluation.Round]
+	if !exists {
+		return fmt.Errorf("round %d is not committed", evaluation.Round)
+	}
+	if entry.summary.ModelDigest != evaluation.ModelDigest {
+		return fmt.Errorf("evaluation of round %d does not match its model digest", evaluation.Round)
+	}
+	entry.evaluation = &evaluation
+	return nil
+}
+
+// Evaluation returns the evaluation recorded for a round.
+func (s *ModelStore) Evaluation(round int) (Evaluation, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.rounds[round]
+	if !exists || entry.evaluation == nil {
+		return Evaluation{}, false
+	}
+	return *entry.evaluation, true
+}
+
+// Evaluations returns the evaluations recorded for rounds in [from, to],
+// ascending. A non-positive to means "through the latest round".
+func (s *ModelStore) Evaluations(from, to int) []Evaluation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if to <= 0 {
+		to = s.latest
+	}
+	out := make([]Evaluation, 0)
+	for _, round := range s.order {
+		if round < from || round > to || s.rounds[round].evaluation == nil {
+			continue
+		}
+		out = append(out, *s.rounds[round].evaluation)
+	}
+	return out
+}
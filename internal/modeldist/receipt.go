@@ -0,0 +1,79 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package modeldist
+
+import (
+	"fmt"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Receipt issues nodeID's inclusion receipt for round from the round's
+// manifest, signed by the store's signer when it has one. It fails with
+// ErrNoReceipt if the round has no manifest or nodeID no entry in it.
+func (s *ModelStore) Receipt(round int, nodeID string) (protocol.InclusionReceipt, error) {
+	manifest, ok := s.Manifest(round)
+	if !ok {
+		return protocol.InclusionReceipt{}, fmt.Errorf("%w: round %d has no manifest", ErrNoReceipt, round)
+	}
+	receipt, ok := protocol.NewInclusionReceipt(manifest, nodeID)
+	if !ok {
+		return protocol.InclusionReceipt{}, fmt.Errorf("%w: node %s is not in round %d's manifest", ErrNoReceipt, nodeID, round)
+	}
+
+	s.mu.RLock()
+	signer, fingerprint, algorithm := s.signer, s.signerFingerprint, s.signerAlgorithm
+	s.mu.RUnlock()
+	if signer == nil {
+		return receipt, nil
+	}
+	digest := receipt.SigningDigest()
+	signature, err := signer.SignData(digest[:])
+	if err != nil {
+		return protocol.InclusionReceipt{}, fmt.Errorf("sign receipt for %s in round %d: %w", nodeID, round, err)
+	}
+	receipt.Signature, receipt.SignerFingerprint, receipt.SignatureAlgorithm = signature, fingerprint, algorithm
+	return receipt, nil
+}
+
+// VerifyReceipt checks that receipt is bound to the manifest cert commits
+// and, when publicKey is not empty, signed by the aggregator whose PEM
+// public key it is, in that key's algorithm. Failures match
+// protocol.ErrInvalidReceipt.
+func VerifyReceipt(receipt protocol.InclusionReceipt, cert CommitCertificate, publicKey []byte) error {
+	switch {
+	case receipt.Round != cert.Round:
+		return fmt.Errorf("%w: receipt for round %d checked against round %d", protocol.ErrInvalidReceipt, receipt.Round, cert.Round)
+	case cert.ManifestDigest == "":
+		return fmt.Errorf("%w: round %d's certificate binds no manifest", protocol.ErrInvalidReceipt, cert.Round)
+	case receipt.ManifestDigest != cert.ManifestDigest:
+		return fmt.Errorf("%w: receipt names manifest %s, round %d committed %s", protocol.ErrInvalidReceipt, receipt.ManifestDigest, cert.Round, cert.ManifestDigest)
+	}
+	if len(publicKey) == 0 {
+		return nil
+	}
+	if len(receipt.Signature) == 0 {
+		return fmt.Errorf("%w: receipt for %s in round %d is unsigned", protocol.ErrInvalidReceipt, receipt.NodeID, receipt.Round)
+	}
+	fingerprint, err := crypto.PublicKeyFingerprint(publicKey)
+	if err != nil {
+		return fmt.Errorf("trusted signer key: %w", err)
+	}
+	if receipt.SignerFingerprint != fingerprint {
+		return fmt.Errorf("%w: signed by %q, not the trusted aggregator", protocol.ErrInvalidReceipt, receipt.SignerFingerprint)
+	}
+	algorithm, err := crypto.FingerprintAlgorithm(fingerprint)
+	if err != nil {
+		return fmt.Errorf("trusted signer key: %w", err)
+	}
+	if err := protocol.CheckAlgorithm(receipt.SignatureAlgorithm, algorithm); err != nil {
+		return fmt.Errorf("%w: %w", protocol.ErrInvalidReceipt, err)
+	}
+	digest := receipt.SigningDigest()
+	if err := crypto.VerifyWithPublicKey(publicKey, digest[:], receipt.Signature); err != nil {
+		return fmt.Errorf("%w: receipt for %s in round %d: %v", protocol.ErrInvalidReceipt, receipt.NodeID, receipt.Round, err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package modeldist
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// receiptStore commits round 1 with a manifest including node-a and
+// excluding node-b as a norm outlier, signed by signer.
+func receiptStore(t *testing.T, signer *crypto.SecureChannel) (*ModelStore, CommitCertificate) {
+	t.Helper()
+	store := NewModelStore(0)
+	if err := store.SetSigner(signer); err != nil {
+		t.Fatalf("set signer: %v", err)
+	}
+	manifest := &protocol.ContributionManifest{Round: 1, Entries: []protocol.ContributionEntry{
+		{NodeID: "node-a", UpdateDigest: protocol.UpdateDigest([]byte("a")), SampleCount: 10, AppliedWeight: 1, Included: true, Reason: protocol.ReasonIncluded},
+		{NodeID: "node-b", UpdateDigest: protocol.UpdateDigest([]byte("b")), SampleCount: 10, Reason: protocol.ReasonNormOutlier},
+	}}
+	weights := []byte("weights-round-1")
+	cert := testCertificate(1, weights)
+	cert.ManifestDigest = manifest.Digest()
+	summary, err := store.Commit(1, weights, 10, nil, cert)
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if err := store.AttachManifest(manifest); err != nil {
+		t.Fatalf("attach manifest: %v", err)
+	}
+	return store, summary.Certificate
+}
+
+func TestReceiptsVerifyAgainstTheCommittedRound(t *testing.T) {
+	signer, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	publicKey, _ := signer.ExportPublicKey()
+	store, cert := receiptStore(t, signer)
+
+	included, err := store.Receipt(1, "node-a")
+	if err != nil {
+		t.Fatalf("receipt: %v", err)
+	}
+	if !included.Included || included.AppliedWeight != 1 {
+		t.Fatalf("node-a receipt: %+v", included)
+	}
+	if err := VerifyReceipt(included, cert, publicKey); err != nil {
+		t.Fatalf("verify genuine receipt: %v", err)
+	}
+
+	excluded, err := store.Receipt(1, "node-b")
+	if err != nil {
+		t.Fatalf("receipt: %v", err)
+	}
+	if excluded.Included || excluded.Reason != protocol.ReasonNormOutlier {
+		t.Fatalf("node-b receipt: included %v, reason %q", excluded.Included, excluded.Reason)
+	}
+	if err := VerifyReceipt(excluded, cert, publicKey); err != nil {
+		t.Fatalf("verify excluded receipt: %v", err)
+	}
+
+	if _, err := store.Receipt(1, "node-c"); !errors.Is(err, ErrNoReceipt) {
+		t.Fatalf("unknown node: expected ErrNoReceipt, got %v", err)
+	}
+	if _, err := store.Receipt(2, "node-a"); !errors.Is(err, ErrNoReceipt) {
+		t.Fatalf("uncommitted round: expected ErrNoReceipt, got %v", err)
+	}
+}
+
+func TestForgedReceiptsAreRejected(t *testing.T) {
+	signer, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	publicKey, _ := signer.ExportPublicKey()
+	store, cert := receiptStore(t, signer)
+	genuine, err := store.Receipt(1, "node-b")
+	if err != nil {
+		t.Fatalf("receipt: %v", err)
+	}
+
+	forger, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("forger: %v", err)
+	}
+	forgerStore, _ := receiptStore(t, forger)
+	foreign, err := forgerStore.Receipt(1, "node-b")
+	if err != nil {
+		t.Fatalf("forger receipt: %v", err)
+	}
+
+	claimsInclusion := genuine
+	claimsInclusion.Included, claimsInclusion.AppliedWeight, claimsInclusion.Reason = true, 1, protocol.ReasonIncluded
+	otherManifest := genuine
+	otherManifest.ManifestDigest = protocol.UpdateDigest([]byte("other manifest"))
+	unsigned := genuine
+	unsigned.Signature = nil
+	otherRound := cert
+	otherRound.Round = 2
+
+	cases := []struct {
+		name    string
+		receipt protocol.InclusionReceipt
+		cert    CommitCertificate
+	}{
+		{"altered to claim inclusion", claimsInclusion, cert},
+		{"names another manifest", otherManifest, cert},
+		{"unsigned", unsigned, cert},
+		{"signed by another aggregator", foreign, cert},
+		{"checked against another round", genuine, otherRound},
+	}
+	for _, tc := range cases {
+		if err := VerifyReceipt(tc.receipt, tc.cert, publicKey); !errors.Is(err, protocol.ErrInvalidReceipt) {
+			t.Errorf("%s: expected ErrInvalidReceipt, got %v", tc.name, err)
+		}
+	}
+
+	unbound := cert
+	unbound.ManifestDigest = ""
+	if err := VerifyReceipt(genuine, unbound, nil); !errors.Is(err, protocol.ErrInvalidReceipt) {
+		t.Fatalf("certificate without manifest: expected ErrInvalidReceipt, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package modeldist
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// ModelSigner signs rounds with an aggregator's identity key.
+// *crypto.SecureChannel implements it.
+type ModelSigner interface {
+	SignData(data []byte) ([]byte, error)
+	ExportPublicKey() ([]byte, error)
+}
+
+// SetSigner makes the store sign every round it commits or imports from
+// then on, replacing any signature an imported round arrived with, so the
+// nodes it serves need trust only this aggregator's key. Nil stops
+// signing.
+func (s *ModelStore) SetSigner(signer ModelSigner) error {
+	var fingerprint string
+	var algorithm protocol.AlgorithmID
+	if signer != nil {
+		publicKey, err := signer.ExportPublicKey()
+		if err != nil {
+			return err
+		}
+		if fingerprint, err = crypto.PublicKeyFingerprint(publicKey); err != nil {
+			return err
+		}
+		if algorithm, err = crypto.PublicKeyAlgorithm(publicKey); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signer, s.signerFingerprint, s.signerAlgorithm = signer, fingerprint, algorithm
+	return nil
+}
+
+// Signer returns the store's signer, nil when it signs nothing.
+func (s *ModelStore) Signer() ModelSigner {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.signer
+}
+
+// sign signs summary with the store's signer, if it has one.
+func (s *ModelStore) sign(summary *RoundSummary) error {
+	s.mu.RLock()
+	signer, fingerprint, algorithm := s.signer, s.signerFingerprint, s.signerAlgorithm
+	s.mu.RUnlock()
+	if signer == nil {
+		return nil
+	}
+	digest := summary.SigningDigest()
+	signature, err := signer.SignData(digest[:])
+	if err != nil {
+		return err
+	}
+	summary.Signature, summary.SignerFingerprint, summary.SignatureAlgorithm = signature, fingerprint, algorithm
+	return nil
+}
+
+// CommitAggregate commits model as its round under cert, recording the
+// digest of its participants so a signature binds whom the round
+// aggregated as well as what it produced. model's signature fields are set
+// to the ones the store holds for the round.
+func (s *ModelStore) CommitAggregate(model *protocol.AggregateModel, cert CommitCertificate) (RoundSummary, error) {
+	if len(model.Weights) == 0 {
+		return RoundSummary{}, fmt.Errorf("round %d has no weights", model.Round)
+	}
+	if cert.ManifestDigest != "" && model.ManifestDigest != "" && cert.ManifestDigest != model.ManifestDigest {
+		return RoundSummary{}, fmt.Errorf("round %d certificate binds manifest %s, not %s", model.Round, cert.ManifestDigest, model.ManifestDigest)
+	}
+	summary, err := s.importRound(RoundSummary{
+		Round:              model.Round,
+		ModelDigest:        protocol.WeightsDigest(model.Weights),
+		ParticipantCount:   len(model.Participants),
+		Certificate:        cert,
+		CommittedAt:        time.Now().UTC(),
+		ParticipantsDigest: protocol.ParticipantsDigest(model.Participants),
+	}, model.Weights)
+	if err != nil {
+		return RoundSummary{}, err
+	}
+	model.Signature, model.SignerFingerprint, model.SignatureAlgorithm = summary.Signature, summary.SignerFingerprint, summary.SignatureAlgorithm
+	return summary, nil
+}
+
+// SigningDigest is the protocol.AggregateModelDigest an aggregator signs
+// for the round.
+func (s RoundSummary) SigningDigest() [32]byte {
+	return protocol.AggregateModelDigest(s.Round, s.ModelDigest, s.ParticipantsDigest)
+}
+
+// VerifySignature checks that the round is signed by the aggregator whose
+// PEM public key is publicKey, in that key's algorithm, and, when weights
+// are supplied, that the signed digest covers exactly those weights. It
+// fails with protocol.ErrUnsignedModel or protocol.ErrModelSignature, the
+// latter also matching protocol.ErrAlgorithmMismatch when the round claims
+// another algorithm than the key's.
+func (s RoundSummary) VerifySignature(publicKey, weights []byte) error {
+	if len(s.Signature) == 0 {
+		return fmt.Errorf("%w: round %d", protocol.ErrUnsignedModel, s.Round)
+	}
+	fingerprint, err := crypto.PublicKeyFingerprint(publicKey)
+	if err != nil {
+		return fmt.Errorf("trusted signer key: %w", err)
+	}
+	if s.SignerFingerprint != fingerprint {
+		return fmt.Errorf("%w: round %d signed by %q, not the trusted aggregator", protocol.ErrModelSignature, s.Round, s.SignerFingerprint)
+	}
+	algorithm, err := crypto.FingerprintAlgorithm(fingerprint)
+	if err != nil {
+		return fmt.Errorf("trusted signer key: %w", err)
+	}
+	if err := protocol.CheckAlgorithm(s.SignatureAlgorithm, algorithm); err != nil {
+		return fmt.Errorf("%w: round %d: %w", protocol.ErrModelSignature, s.Round, err)
+	}
+	if weights != nil && protocol.WeightsDigest(weights) != s.ModelDigest {
+		return fmt.Errorf("%w: round %d weights do not match the signed digest", protocol.ErrModelSignature, s.Round)
+	}
+	digest := s.SigningDigest()
+	if err := crypto.VerifyWithPublicKey(publicKey, digest[:], s.Signature); err != nil {
+		return fmt.Errorf("%w: round %d: %v", protocol.ErrModelSignature, s.Round, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package modeldist
+
+import (
+	"fmt"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// SpecChange is the consensus record of a model spec change: the new spec
+// and the certificate of the quorum that approved it. The certificate's
+// ModelDigest is the spec's Digest and its Round the first round trained
+// against the new spec.
+type SpecChange struct {
+	Spec        protocol.ModelSpec `json:"spec"`
+	Certificate CommitCertificate  `json:"certificate"`
+}
+
+// RegisterSpec registers the federation's model spec at setup. Once one is
+// registered, later versions need a SpecChange.
+func (s *ModelStore) RegisterSpec(spec protocol.ModelSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.spec != nil {
+		return fmt.Errorf("%w: %s v%d is already registered; change it with a spec change record", protocol.ErrModelSpecMismatch, s.spec.Name, s.spec.Version)
+	}
+	s.spec = &spec
+	return nil
+}
+
+// ChangeSpec replaces the registered spec with the next version once its
+// consensus record checks out: the certificate commits to the new spec's
+// digest, signed by a quorum of the store's validators when it has them, and takes effect after the latest committed round.
+func (s *ModelStore) ChangeSpec(change SpecChange) error {
+	spec := change.Spec
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	cert := change.Certificate
+	if err := cert.Verify(nil, s.Validators()); err != nil {
+		return fmt.Errorf("spec change to %s v%d: %w", spec.Name, spec.Version, err)
+	}
+	if cert.ModelDigest != spec.Digest() {
+		return fmt.Errorf("spec change to %s v%d: certificate does not commit to the spec", spec.Name, spec.Version)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.spec == nil:
+		return fmt.Errorf("%w: no spec registered to change", protocol.ErrModelSpecMismatch)
+	case spec.Name != s.spec.Name:
+		return fmt.Errorf("%w: spec change renames %s to %s", protocol.ErrModelSpecMismatch, s.spec.Name, spec.Name)
+	case spec.Version != s.spec.Version+1:
+		return fmt.Errorf("%w: spec change to v%d must follow v%d", protocol.ErrModelSpecMismatch, spec.Version, s.spec.Version)
+	case cert.Round <= s.latest:
+		return fmt.Errorf("spec change to %s v%d takes effect in round %d, already committed through %d", spec.Name, spec.Version, cert.Round, s.latest)
+	}
+	s.spec = &spec
+	s.specChanges = append(s.specChanges, change)
+	return nil
+}
+
+// Spec returns the registered model spec, or false before RegisterSpec.
+func (s *ModelStore) Spec() (protocol.ModelSpec, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.spec == nil {
+		return protocol.ModelSpec{}, false
+	}
+	return *s.spec, true
+}
+
+// SpecChanges returns the applied spec change records, oldest first.
+func (s *ModelStore) SpecChanges() []SpecChange {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]SpecChange(nil), s.specChanges...)
+}
@@ -1,6 +1,13 @@
 package modeldist
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
 
 // CheckpointRef identifies a distributed model checkpoint.
 type CheckpointRef struct {
@@ -16,3 +23,416 @@ func (c CheckpointRef) Validate() error {
 	}
 	return nil
 }
+
+// CommitCertificate records the quorum evidence attached to a committed round.
+type CommitCertificate struct {
+	Round       int    `json:"round"`
+	ProposalID  string `json:"proposal_id"`
+	ModelDigest string `json:"model_digest"`
+	// QuorumSize and Approvals record the quorum the committing
+	// coordinator required and who approved. They are informational:
+	// Verify counts only Signatures, against the verifier's own
+	// Validators.
+	QuorumSize int      `json:"quorum_size"`
+	Approvals  []string `json:"approvals"`
+	// Signatures are the approvers' signatures over ApprovalDigest.
+	Signatures []ApprovalSignature `json:"signatures,omitempty"`
+	// ManifestDigest is the digest of the round's contribution manifest,
+	// which the round's inclusion receipts name. Empty for rounds committed
+	// without a manifest, such as rollbacks.
+	ManifestDigest string `json:"manifest_digest,omitempty"`
+}
+
+// Verify checks that the certificate is well formed and, when weights are
+// supplied, that it commits to exactly those weights. With validators, it
+// also requires their quorum of signed approvals, failing with
+// ErrInsufficientApprovals; without, it attests nothing about who approved,
+// and trust in the round rests on the serving aggregator's signature.
+func (c CommitCertificate) Verify(weights []byte, validators *Validators) error {
+	if c.Round <= 0 {
+		return fmt.Errorf("commit certificate has invalid round %d", c.Round)
+	}
+	if c.ModelDigest == "" {
+		return fmt.Errorf("commit certificate for round %d has no model digest", c.Round)
+	}
+	if weights != nil && protocol.WeightsDigest(weights) != c.ModelDigest {
+		return fmt.Errorf("commit certificate for round %d does not match model digest", c.Round)
+	}
+	if validators != nil {
+		return validators.checkApprovals(c)
+	}
+	return nil
+}
+
+// RoundSummary is the metadata a returning node needs to fast-forward
+// through a committed round without necessarily fetching its weights.
+type RoundSummary struct {
+	Round              int                `json:"round"`
+	ModelDigest        string             `json:"model_digest"`
+	ParticipantCount   int                `json:"participant_count"`
+	ConvergenceMetrics map[string]float64 `json:"convergence_metrics,omitempty"`
+	Certificate        CommitCertificate  `json:"certificate"`
+	CommittedAt        time.Time          `json:"committed_at"`
+	ManifestDigest     string             `json:"manifest_digest,omitempty"`
+	// RollbackOf is set when this round restores the model of an earlier
+	// round instead of committing newly aggregated weights.
+	RollbackOf int `json:"rollback_of,omitempty"`
+	// ParticipantsDigest is protocol.ParticipantsDigest of the nodes the
+	// round aggregated, when the committing store knew them.
+	ParticipantsDigest string `json:"participants_digest,omitempty"`
+	// Signature is the serving aggregator's signature over SigningDigest,
+	// by the identity key SignerFingerprint names, in SignatureAlgorithm.
+	// See SetSigner.
+	Signature          []byte               `json:"signature,omitempty"`
+	SignerFingerprint  string               `json:"signer_fingerprint,omitempty"`
+	SignatureAlgorithm protocol.AlgorithmID `json:"signature_algorithm,omitempty"`
+	// Variants lists the encodings the serving store can send the round's
+	// weights in: in full and, when it holds the previous round's weights,
+	// as a delta against them. Empty when only the summary is retained.
+	Variants []ModelVariant `json:"variants,omitempty"`
+	// VoteTimeline is how the votes committing the round arrived, when
+	// the committing aggregator recorded them. It is not signed.
+	VoteTimeline *protocol.VoteTimeline `json:"vote_timeline,omitempty"`
+}
+
+type committedRound struct {
+	summary  RoundSummary
+	weights  []byte
+	manifest *protocol.ContributionManifest
+	// delta encodes weights against the previous retained round's, nil
+	// when they could not be encoded.
+	delta *ModelDelta
+	// evaluation is the round's evaluation, once one completes.
+	evaluation *Evaluation
+}
+
+// ModelStore retains committed global models and their round summaries so
+// nodes that missed rounds can backfill them.
+type ModelStore struct {
+	mu        sync.RWMutex
+	rounds    map[int]*committedRound
+	order     []int
+	maxRounds int
+	latest    int
+	// spec is the registered model spec, nil until RegisterSpec.
+	spec        *protocol.ModelSpec
+	specChanges []SpecChange
+	// signer, when set, signs every round the store takes in.
+	signer            ModelSigner
+	signerFingerprint string
+	signerAlgorithm   protocol.AlgorithmID
+	// validators, when set, must sign every round the store takes in.
+	validators *Validators
+}
+
+// NewModelStore creates a store retaining at most maxRounds committed rounds.
+func NewModelStore(maxRounds int) *ModelStore {
+	if maxRounds <= 0 {
+		maxRounds = 256
+	}
+	return &ModelStore{
+		rounds:    make(map[int]*committedRound),
+		order:     make([]int, 0, maxRounds),
+		maxRounds: maxRounds,
+	}
+}
+
+// Commit records a committed round with its weights and certificate.
+func (s *ModelStore) Commit(round int, weights []byte, participants int, metrics map[string]float64, cert CommitCertificate) (RoundSummary, error) {
+	if len(weights) == 0 {
+		return RoundSummary{}, fmt.Errorf("round %d has no weights", round)
+	}
+	summary := RoundSummary{
+		Round:              round,
+		ModelDigest:        protocol.WeightsDigest(weights),
+		ParticipantCount:   participants,
+		ConvergenceMetrics: cloneMetrics(metrics),
+		Certificate:        cert,
+		CommittedAt:        time.Now().UTC(),
+	}
+	return s.importRound(summary, weights)
+}
+
+// Rollback commits the weights of an earlier round as round cert.Round, so
+// history stays append-only and backfilling nodes replay the rollback like
+// any other round. The certificate must carry a quorum for the restored
+// weights and a round after the latest committed one.
+func (s *ModelStore) Rollback(target int, participants int, cert CommitCertificate) (RoundSummary, error) {
+	weights, restored, ok := s.Model(target)
+	if !ok {
+		return RoundSummary{}, fmt.Errorf("rollback target round %d has no retained weights", target)
+	}
+	if latest := s.LatestRound(); cert.Round <= latest {
+		return RoundSummary{}, fmt.Errorf("rollback round %d must follow latest round %d", cert.Round, latest)
+	}
+	summary := RoundSummary{
+		Round:              cert.Round,
+		ModelDigest:        restored.ModelDigest,
+		ParticipantCount:   participants,
+		ConvergenceMetrics: cloneMetrics(restored.ConvergenceMetrics),
+		Certificate:        cert,
+		CommittedAt:        time.Now().UTC(),
+		RollbackOf:         target,
+	}
+	return s.importRound(summary, weights)
+}
+
+// SetValidators makes the store require validators' quorum of signed
+// approvals on every round it commits or imports, and on spec changes,
+// from then on. Nil accepts any well-formed certificate.
+func (s *ModelStore) SetValidators(validators *Validators) error {
+	if validators != nil {
+		if err := validators.Validate(); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.validators = validators
+	return nil
+}
+
+// Validators returns the validators the store requires, nil when it
+// requires none.
+func (s *ModelStore) Validators() *Validators {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.validators
+}
+
+// Import stores a round fetched from a peer. Weights may be nil when only
+// the summary is retained; the certificate is verified either way. A store
+// with a signer signs the round in place of the peer.
+func (s *ModelStore) Import(summary RoundSummary, weights []byte) error {
+	_, err := s.importRound(summary, weights)
+	return err
+}
+
+// importRound is Import, returning the summary the store holds for the
+// round.
+func (s *ModelStore) importRound(summary RoundSummary, weights []byte) (RoundSummary, error) {
+	if summary.Round <= 0 {
+		return RoundSummary{}, fmt.Errorf("invalid round %d", summary.Round)
+	}
+	if summary.Certificate.Round != summary.Round {
+		return RoundSummary{}, fmt.Errorf("certificate round %d does not match summary round %d", summary.Certificate.Round, summary.Round)
+	}
+	if summary.Certificate.ModelDigest != summary.ModelDigest {
+		return RoundSummary{}, fmt.Errorf("certificate digest does not match summary digest for round %d", summary.Round)
+	}
+	if err := summary.Certificate.Verify(weights, s.Validators()); err != nil {
+		return RoundSummary{}, err
+	}
+	if err := s.sign(&summary); err != nil {
+		return RoundSummary{}, fmt.Errorf("sign round %d: %w", summary.Round, err)
+	}
+	delta := s.encodeDelta(summary.Round, weights)
+	summary.Variants = modelVariants(weights, delta)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.rounds[summary.Round]; ok {
+		if existing.summary.ModelDigest != summary.ModelDigest {
+			return RoundSummary{}, fmt.Errorf("round %d already committed with a different model", summary.Round)
+		}
+		if weights != nil && existing.weights == nil {
+			existing.weights = append([]byte(nil), weights...)
+			existing.delta = delta
+			existing.summary.Variants = summary.Variants
+		}
+		return existing.summary, nil
+	}
+
+	s.rounds[summary.Round] = &committedRound{
+		summary: summary,
+		weights: append([]byte(nil), weights...),
+		delta:   delta,
+	}
+	s.order = append(s.order, summary.Round)
+	sort.Ints(s.order)
+	for len(s.order) > s.maxRounds {
+		delete(s.rounds, s.order[0])
+		s.order = s.order[1:]
+	}
+	if summary.Round > s.latest {
+		s.latest = summary.Round
+	}
+	return summary, nil
+}
+
+// Summaries returns committed round summaries in [from, to], ascending.
+// A non-positive to means "through the latest round".
+func (s *ModelStore) Summaries(from, to int) []RoundSummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if to <= 0 {
+		to = s.latest
+	}
+	out := make([]RoundSummary, 0)
+	for _, round := range s.order {
+		if round < from || round > to {
+			continue
+		}
+		out = append(out, s.rounds[round].summary)
+	}
+	return out
+}
+
+// Model returns the weights and summary for a round. ok is false when the
+// round is unknown or only its summary is retained.
+func (s *ModelStore) Model(round int) ([]byte, RoundSummary, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.rounds[round]
+	if !exists || entry.weights == nil {
+		return nil, RoundSummary{}, false
+	}
+	return append([]byte(nil), entry.weights...), entry.summary, true
+}
+
+// Delta returns the round's weights encoded against the previous retained
+// round's, as its summary advertises. ok is false when the store holds no
+// delta for the round.
+func (s *ModelStore) Delta(round int) (*ModelDelta, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.rounds[round]
+	if !exists || entry.delta == nil {
+		return nil, false
+	}
+	delta := *entry.delta
+	return &delta, true
+}
+
+// encodeDelta encodes the weights of round against the latest retained
+// round before it that has weights. It returns nil when there is no such
+// round, the two models cannot be diffed, for example because the model
+// spec changed their dimension, or the delta would be no smaller.
+func (s *ModelStore) encodeDelta(round int, weights []byte) *ModelDelta {
+	if len(weights) == 0 {
+		return nil
+	}
+	s.mu.RLock()
+	var baseRound int
+	var base []byte
+	for i := len(s.order) - 1; i >= 0; i-- {
+		if candidate := s.order[i]; candidate < round && s.rounds[candidate].weights != nil {
+			baseRound, base = candidate, s.rounds[candidate].weights
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if base == nil {
+		return nil
+	}
+	delta, err := EncodeDelta(baseRound, base, round, weights)
+	if err != nil || len(delta.Payload) >= len(weights) {
+		return nil
+	}
+	return delta
+}
+
+func modelVariants(weights []byte, delta *ModelDelta) []ModelVariant {
+	if len(weights) == 0 {
+		return nil
+	}
+	variants := []ModelVariant{{Encoding: VariantFull, Size: len(weights)}}
+	if delta != nil {
+		variants = append(variants, ModelVariant{
+			Encoding:   VariantDelta,
+			Size:       len(delta.Payload),
+			BaseRound:  delta.BaseRound,
+			BaseDigest: delta.BaseDigest,
+		})
+	}
+	return variants
+}
+
+// AttachManifest records the contribution manifest for a committed round and
+// exposes its digest in the round summary.
+func (s *ModelStore) AttachManifest(manifest *protocol.ContributionManifest) error {
+	if manifest == nil {
+		return fmt.Errorf("manifest is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.rounds[manifest.Round]
+	if !exists {
+		return fmt.Errorf("round %d is not committed", manifest.Round)
+	}
+	digest := manifest.Digest()
+	if entry.summary.ManifestDigest != "" && entry.summary.ManifestDigest != digest {
+		return fmt.Errorf("round %d already has a different manifest", manifest.Round)
+	}
+	if bound := entry.summary.Certificate.ManifestDigest; bound != "" && bound != digest {
+		return fmt.Errorf("round %d certificate binds manifest %s, not %s", manifest.Round, bound, digest)
+	}
+	entry.manifest = manifest
+	entry.summary.ManifestDigest = digest
+	return nil
+}
+
+// AttachVoteTimeline records how the votes committing timeline's round
+// arrived and exposes it in the round summary.
+func (s *ModelStore) AttachVoteTimeline(timeline protocol.VoteTimeline) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.rounds[timeline.Round]
+	if !exists {
+		return fmt.Errorf("round %d is not committed", timeline.Round)
+	}
+	entry.summary.VoteTimeline = &timeline
+	return nil
+}
+
+// Manifest returns the contribution manifest recorded for a round.
+func (s *ModelStore) Manifest(round int) (*protocol.ContributionManifest, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.rounds[round]
+	if !exists || entry.manifest == nil {
+		return nil, false
+	}
+	return entry.manifest, true
+}
+
+// BaseDigest returns the digest of the global model distributed for round:
+// the model committed in the round before it. It matches the BaseDigest of
+// an honest TrainingStatement, so it can serve as a batch base model
+// resolver.
+func (s *ModelStore) BaseDigest(round int) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.rounds[round-1]
+	if !exists {
+		return "", false
+	}
+	return entry.summary.ModelDigest, true
+}
+
+// LatestRound returns the highest committed round, or 0 if none.
+func (s *ModelStore) LatestRound() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest
+}
+
+func cloneMetrics(metrics map[string]float64) map[string]float64 {
+	if metrics == nil {
+		return nil
+	}
+	out := make(map[string]float64, len(metrics))
+	for k, v := range metrics {
+		out[k] = v
+	}
+	return out
+}
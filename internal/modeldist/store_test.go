@@ -0,0 +1,368 @@
+package modeldist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func testCertificate(round int, weights []byte) CommitCertificate {
+	return CommitCertificate{
+		Round:       round,
+		ProposalID:  fmt.Sprintf("agg-%d", round),
+		ModelDigest: protocol.WeightsDigest(weights),
+		QuorumSize:  3,
+		Approvals:   []string{"agg", "peer-1", "peer-2", "peer-3"},
+	}
+}
+
+func seedStore(t *testing.T, rounds int) *ModelStore {
+	t.Helper()
+	store := NewModelStore(128)
+	for round := 1; round <= rounds; round++ {
+		weights := []byte(fmt.Sprintf("weights-round-%d", round))
+		if _, err := store.Commit(round, weights, 10, map[string]float64{"loss": 1.0 / float64(round)}, testCertificate(round, weights)); err != nil {
+			t.Fatalf("commit round %d: %v", round, err)
+		}
+	}
+	return store
+}
+
+// serveStore mirrors the /api/rounds and /api/model/{round} contract.
+func serveStore(t *testing.T, store *ModelStore, tamper func(*ModelResponse)) (*httptest.Server, *int) {
+	t.Helper()
+	return serveStoreDeltas(t, store, tamper, nil)
+}
+
+// serveStoreDeltas is serveStore also serving /api/model/{round}/delta,
+// with tamperDelta, when set, altering each delta served.
+func serveStoreDeltas(t *testing.T, store *ModelStore, tamper func(*ModelResponse), tamperDelta func(*ModelDelta)) (*httptest.Server, *int) {
+	t.Helper()
+	modelFetches := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rounds", func(w http.ResponseWriter, r *http.Request) {
+		from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+		summaries := store.Summaries(from, 0)
+		_ = json.NewEncoder(w).Encode(RoundsResponse{Rounds: summaries, LatestRound: store.LatestRound(), Count: len(summaries)})
+	})
+	mux.HandleFunc("/api/model/", func(w http.ResponseWriter, r *http.Request) {
+		if trimmed, isDelta := strings.CutSuffix(r.URL.Path, "/delta"); isDelta {
+			round, _ := strconv.Atoi(strings.TrimPrefix(trimmed, "/api/model/"))
+			delta, ok := store.Delta(round)
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			if tamperDelta != nil {
+				tamperDelta(delta)
+			}
+			_ = json.NewEncoder(w).Encode(delta)
+			return
+		}
+		modelFetches++
+		round, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/model/"))
+		weights, summary, ok := store.Model(round)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		resp := ModelResponse{Round: round, Weights: weights, Summary: summary}
+		if tamper != nil {
+			tamper(&resp)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &modelFetches
+}
+
+// testValidators returns a validator set of count signing validators,
+// node-1 onwards, requiring quorum of them.
+func testValidators(t *testing.T, count, quorum int) (*Validators, map[string]*crypto.SecureChannel) {
+	t.Helper()
+	validators := &Validators{Keys: make(map[string][]byte), Quorum: quorum}
+	channels := make(map[string]*crypto.SecureChannel)
+	for i := 1; i <= count; i++ {
+		channel, err := crypto.NewSecureChannelWithAlgorithm(protocol.AlgorithmEd25519)
+		if err != nil {
+			t.Fatalf("validator key: %v", err)
+		}
+		publicKey, _ := channel.ExportPublicKey()
+		nodeID := fmt.Sprintf("node-%d", i)
+		validators.Keys[nodeID], channels[nodeID] = publicKey, channel
+	}
+	return validators, channels
+}
+
+// signBy adds the approvals of nodeIDs, signed with their channels, to cert.
+func signBy(t *testing.T, cert *CommitCertificate, channels map[string]*crypto.SecureChannel, nodeIDs ...string) {
+	t.Helper()
+	for _, nodeID := range nodeIDs {
+		approval, err := cert.SignApproval(nodeID, channels[nodeID])
+		if err != nil {
+			t.Fatalf("sign approval: %v", err)
+		}
+		cert.Signatures = append(cert.Signatures, approval)
+	}
+}
+
+func TestCommitCertificateVerify(t *testing.T) {
+	weights := []byte("model")
+	cert := testCertificate(4, weights)
+	if err := cert.Verify(weights, nil); err != nil {
+		t.Fatalf("expected valid certificate: %v", err)
+	}
+	if err := cert.Verify([]byte("other"), nil); err == nil {
+		t.Fatal("expected digest mismatch to be rejected")
+	}
+
+	validators, channels := testValidators(t, 3, 2)
+	if err := validators.Validate(); err != nil {
+		t.Fatalf("validators: %v", err)
+	}
+	if err := (&Validators{Keys: validators.Keys, Quorum: 4}).Validate(); !errors.Is(err, ErrInvalidValidators) {
+		t.Fatalf("expected ErrInvalidValidators for an unreachable quorum, got %v", err)
+	}
+
+	// The certificate's own quorum and approver list count for nothing.
+	forged := testCertificate(4, weights)
+	forged.QuorumSize, forged.Approvals = 1, []string{"node-1", "node-2", "made-up"}
+	if err := forged.Verify(weights, validators); !errors.Is(err, ErrInsufficientApprovals) {
+		t.Fatalf("unsigned approvals: expected ErrInsufficientApprovals, got %v", err)
+	}
+	signBy(t, &forged, channels, "node-1", "node-1")
+	if err := forged.Verify(weights, validators); !errors.Is(err, ErrInsufficientApprovals) {
+		t.Fatalf("one validator signing twice: expected ErrInsufficientApprovals, got %v", err)
+	}
+
+	// Signatures approve one model only.
+	signed := testCertificate(4, weights)
+	signBy(t, &signed, channels, "node-1", "node-2")
+	if err := signed.Verify(weights, validators); err != nil {
+		t.Fatalf("expected a quorum of signed approvals to verify: %v", err)
+	}
+	swapped := signed
+	swapped.ModelDigest = protocol.WeightsDigest([]byte("other"))
+	if err := swapped.Verify([]byte("other"), validators); !errors.Is(err, ErrInsufficientApprovals) {
+		t.Fatalf("approvals moved to another model: expected ErrInsufficientApprovals, got %v", err)
+	}
+
+	store := NewModelStore(8)
+	if err := store.SetValidators(validators); err != nil {
+		t.Fatalf("set validators: %v", err)
+	}
+	if _, err := store.Commit(4, weights, 3, nil, forged); !errors.Is(err, ErrInsufficientApprovals) {
+		t.Fatalf("expected the store to refuse an unsigned commit, got %v", err)
+	}
+	if _, err := store.Commit(4, weights, 3, nil, signed); err != nil {
+		t.Fatalf("commit signed round: %v", err)
+	}
+}
+
+func TestSignedRoundsCarryTheSignersAlgorithm(t *testing.T) {
+	signer, err := crypto.NewSecureChannelWithAlgorithm(protocol.AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	publicKey, _ := signer.ExportPublicKey()
+	store := NewModelStore(0)
+	if err := store.SetSigner(signer); err != nil {
+		t.Fatalf("set signer: %v", err)
+	}
+	weights := []byte("weights-round-1")
+	summary, err := store.Commit(1, weights, 3, nil, testCertificate(1, weights))
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if summary.SignatureAlgorithm != protocol.AlgorithmEd25519 || !strings.HasPrefix(summary.SignerFingerprint, "ed25519:") {
+		t.Fatalf("summary signed as %q by %q", summary.SignatureAlgorithm, summary.SignerFingerprint)
+	}
+	if err := summary.VerifySignature(publicKey, weights); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	relabelled := summary
+	relabelled.SignatureAlgorithm = protocol.AlgorithmECDSAP256
+	if err := relabelled.VerifySignature(publicKey, weights); !errors.Is(err, protocol.ErrModelSignature) || !errors.Is(err, protocol.ErrAlgorithmMismatch) {
+		t.Fatalf("relabelled algorithm: expected ErrModelSignature and ErrAlgorithmMismatch, got %v", err)
+	}
+}
+
+func TestRollbackRecommitsEarlierWeightsAsNewRound(t *testing.T) {
+	store := seedStore(t, 3)
+	weights := []byte("weights-round-1")
+
+	if _, err := store.Rollback(1, 10, testCertificate(3, weights)); err == nil {
+		t.Fatal("expected a rollback that rewrites history to be rejected")
+	}
+	if _, err := store.Rollback(1, 10, testCertificate(4, []byte("weights-round-3"))); err == nil {
+		t.Fatal("expected a certificate for other weights to be rejected")
+	}
+
+	summary, err := store.Rollback(1, 10, testCertificate(4, weights))
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if summary.Round != 4 || summary.RollbackOf != 1 || store.LatestRound() != 4 {
+		t.Fatalf("expected round 4 to roll back to round 1, got %+v latest=%d", summary, store.LatestRound())
+	}
+	got, _, ok := store.Model(4)
+	if !ok || string(got) != string(weights) {
+		t.Fatalf("expected round 1 weights at round 4, got %q", got)
+	}
+}
+
+func TestBackfillFetchesEveryRoundForSmallGap(t *testing.T) {
+	remote := seedStore(t, 8)
+	server, fetches := serveStore(t, remote, nil)
+
+	local := NewModelStore(128)
+	client := NewBackfillClient(server.URL, local)
+	result, err := client.Backfill(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	if result.SummaryOnly {
+		t.Fatal("expected full backfill for a small gap")
+	}
+	if result.FromRound != 4 || result.ToRound != 8 {
+		t.Fatalf("range = [%d,%d], want [4,8]", result.FromRound, result.ToRound)
+	}
+	if *fetches != 5 || len(result.Models) != 5 {
+		t.Fatalf("fetched %d models (%d in result), want 5", *fetches, len(result.Models))
+	}
+	for round := 4; round <= 8; round++ {
+		if _, _, ok := local.Model(round); !ok {
+			t.Fatalf("round %d missing from local store", round)
+		}
+	}
+}
+
+func TestBackfillLargeGapFetchesLatestModelOnly(t *testing.T) {
+	remote := seedStore(t, 60)
+	server, fetches := serveStore(t, remote, nil)
+
+	local := NewModelStore(128)
+	client := NewBackfillClient(server.URL, local)
+	round, err := client.BackfillRounds(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	if round != 60 {
+		t.Fatalf("caught up to round %d, want 60", round)
+	}
+	if *fetches != 1 {
+		t.Fatalf("fetched %d models, want only the latest", *fetches)
+	}
+	if got := len(local.Summaries(11, 0)); got != 50 {
+		t.Fatalf("local summaries = %d, want 50", got)
+	}
+	if _, _, ok := local.Model(59); ok {
+		t.Fatal("intermediate round weights should not be fetched for a large gap")
+	}
+	if _, _, ok := local.Model(60); !ok {
+		t.Fatal("latest round weights should be present")
+	}
+}
+
+func TestBackfillRejectsModelNotMatchingCertificate(t *testing.T) {
+	remote := seedStore(t, 3)
+	server, _ := serveStore(t, remote, func(resp *ModelResponse) {
+		if resp.Round == 2 {
+			resp.Weights = []byte("poisoned")
+		}
+	})
+
+	local := NewModelStore(128)
+	client := NewBackfillClient(server.URL, local)
+	if _, err := client.Backfill(context.Background(), 0); err == nil {
+		t.Fatal("expected tampered model to fail certificate verification")
+	}
+	if local.LatestRound() != 0 {
+		t.Fatalf("nothing should be imported on failure, latest=%d", local.LatestRound())
+	}
+}
+
+func TestBaseDigestIsPreviousRoundModel(t *testing.T) {
+	store := seedStore(t, 3)
+	digest, ok := store.BaseDigest(3)
+	if !ok || digest != protocol.WeightsDigest([]byte("weights-round-2")) {
+		t.Fatalf("expected round 2's model as round 3's base, got %q %t", digest, ok)
+	}
+	if _, ok := store.BaseDigest(1); ok {
+		t.Fatal("expected no committed base for round 1")
+	}
+}
+
+func TestSpecChangesNeedAConsensusRecord(t *testing.T) {
+	store := seedStore(t, 2)
+	spec := protocol.ModelSpec{Name: "mlp", Version: 1, Dimension: 6, Layers: []protocol.LayerShape{{Name: "dense", Shape: []int{2, 3}}}, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp"))}
+	if err := store.RegisterSpec(protocol.ModelSpec{Name: "mlp", Version: 1, Dimension: 7, Layers: spec.Layers, ArchitectureDigest: spec.ArchitectureDigest}); !errors.Is(err, protocol.ErrInvalidModelSpec) {
+		t.Fatalf("expected ErrInvalidModelSpec for layers short of the dimension, got %v", err)
+	}
+	if err := store.RegisterSpec(spec); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	next := spec
+	next.Version, next.Dimension, next.Layers = 2, 8, []protocol.LayerShape{{Name: "dense", Shape: []int{2, 4}}}
+	if err := store.RegisterSpec(next); !errors.Is(err, protocol.ErrModelSpecMismatch) {
+		t.Fatalf("expected a second RegisterSpec to be refused, got %v", err)
+	}
+
+	record := func(spec protocol.ModelSpec, round int) SpecChange {
+		return SpecChange{Spec: spec, Certificate: CommitCertificate{
+			Round:       round,
+			ProposalID:  fmt.Sprintf("spec-%d", spec.Version),
+			ModelDigest: spec.Digest(),
+			QuorumSize:  3,
+			Approvals:   []string{"agg", "peer-1", "peer-2"},
+		}}
+	}
+	validators, channels := testValidators(t, 3, 2)
+	if err := store.SetValidators(validators); err != nil {
+		t.Fatalf("set validators: %v", err)
+	}
+	sign := func(change SpecChange) SpecChange {
+		signBy(t, &change.Certificate, channels, "node-1", "node-2")
+		return change
+	}
+	short := record(next, 3)
+	signBy(t, &short.Certificate, channels, "node-1")
+	skipped := next
+	skipped.Version = 3
+	forged := record(next, 3)
+	forged.Certificate.ModelDigest = spec.Digest()
+	for name, change := range map[string]SpecChange{
+		"short of quorum":   short,
+		"skipped version":   sign(record(skipped, 3)),
+		"other digest":      sign(forged),
+		"already committed": sign(record(next, 2)),
+	} {
+		if err := store.ChangeSpec(change); err == nil {
+			t.Fatalf("%s: expected ChangeSpec to fail", name)
+		}
+	}
+	if current, _ := store.Spec(); current.Version != 1 {
+		t.Fatalf("spec changed to v%d by a refused record", current.Version)
+	}
+
+	if err := store.ChangeSpec(sign(record(next, 3))); err != nil {
+		t.Fatalf("change spec: %v", err)
+	}
+	if current, ok := store.Spec(); !ok || current.Version != 2 || current.Dimension != 8 {
+		t.Fatalf("spec = %+v, %v", current, ok)
+	}
+	if changes := store.SpecChanges(); len(changes) != 1 || changes[0].Certificate.Round != 3 {
+		t.Fatalf("spec changes = %+v", changes)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package modeldist
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+)
+
+// approvalDomain separates commit approval signatures from every other use
+// of a validator's key.
+const approvalDomain = "sovereign-commit-approval/v1"
+
+// ApprovalSignature is one validator's signature over a commit
+// certificate's ApprovalDigest.
+type ApprovalSignature struct {
+	NodeID    string `json:"node_id"`
+	Signature []byte `json:"signature"`
+}
+
+// ApprovalDigest is what a validator signs to approve committing the model
+// digested to modelDigest, with the manifest digested to manifestDigest, as
+// round under proposalID. Voters compute it from the proposal they approve.
+func ApprovalDigest(round int, proposalID, modelDigest, manifestDigest string) [32]byte {
+	h := sha256.New()
+	h.Write([]byte(approvalDomain))
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(round)) // #nosec G115 -- rounds are positive
+	h.Write(buf[:])
+	for _, field := range []string{proposalID, modelDigest, manifestDigest} {
+		binary.BigEndian.PutUint32(buf[:4], uint32(len(field))) // #nosec G115 -- IDs and digests are short
+		h.Write(buf[:4])
+		h.Write([]byte(field))
+	}
+	var digest [32]byte
+	copy(digest[:], h.Sum(nil))
+	return digest
+}
+
+// ApprovalDigest is the digest the certificate's approval signatures cover.
+func (c CommitCertificate) ApprovalDigest() [32]byte {
+	return ApprovalDigest(c.Round, c.ProposalID, c.ModelDigest, c.ManifestDigest)
+}
+
+// SignApproval returns nodeID's signature, by signer, approving the commit
+// the certificate records.
+func (c CommitCertificate) SignApproval(nodeID string, signer ModelSigner) (ApprovalSignature, error) {
+	digest := c.ApprovalDigest()
+	signature, err := signer.SignData(digest[:])
+	if err != nil {
+		return ApprovalSignature{}, fmt.Errorf("sign approval of round %d: %w", c.Round, err)
+	}
+	return ApprovalSignature{NodeID: nodeID, Signature: signature}, nil
+}
+
+// Validators is a node's own record of who may approve commits and how many
+// must: a certificate counts toward a commit only the approvals these keys
+// signed, and never the quorum it declares itself.
+type Validators struct {
+	// Keys maps each validator's node ID to its PEM public key.
+	Keys map[string][]byte `json:"keys"`
+	// Quorum is how many distinct validators must sign a commit.
+	Quorum int `json:"quorum"`
+}
+
+// Validate checks that the quorum is reachable and every key parses.
+func (v *Validators) Validate() error {
+	if v.Quorum <= 0 {
+		return fmt.Errorf("%w: quorum must be positive, got %d", ErrInvalidValidators, v.Quorum)
+	}
+	if v.Quorum > len(v.Keys) {
+		return fmt.Errorf("%w: quorum %d exceeds the %d validator keys", ErrInvalidValidators, v.Quorum, len(v.Keys))
+	}
+	for nodeID, key := range v.Keys {
+		if nodeID == "" {
+			return fmt.Errorf("%w: validator key with empty node id", ErrInvalidValidators)
+		}
+		if _, err := crypto.PublicKeyFingerprint(key); err != nil {
+			return fmt.Errorf("%w: validator %s: %v", ErrInvalidValidators, nodeID, err)
+		}
+	}
+	return nil
+}
+
+// LoadValidators reads a validator set from the JSON file at path: an
+// object with "quorum" and "keys", the latter mapping node IDs to PEM
+// public keys.
+func LoadValidators(path string) (*Validators, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("read validators %s: %w", path, err)
+	}
+	var file struct {
+		Quorum int               `json:"quorum"`
+		Keys   map[string]string `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidValidators, path, err)
+	}
+	validators := &Validators{Quorum: file.Quorum, Keys: make(map[string][]byte, len(file.Keys))}
+	for nodeID, key := range file.Keys {
+		validators.Keys[nodeID] = []byte(key)
+	}
+	if err := validators.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return validators, nil
+}
+
+// checkApprovals counts the distinct validators whose signatures in cert
+// verify, failing with ErrInsufficientApprovals below the quorum.
+func (v *Validators) checkApprovals(cert CommitCertificate) error {
+	digest := cert.ApprovalDigest()
+	signed := make(map[string]bool, len(cert.Signatures))
+	for _, approval := range cert.Signatures {
+		key, known := v.Keys[approval.NodeID]
+		if !known || signed[approval.NodeID] {
+			continue
+		}
+		if crypto.VerifyWithPublicKey(key, digest[:], approval.Signature) == nil {
+			signed[approval.NodeID] = true
+		}
+	}
+	if len(signed) < v.Quorum {
+		return fmt.Errorf("%w: round %d carries %d valid validator signatures, need %d", ErrInsufficientApprovals, cert.Round, len(signed), v.Quorum)
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package moduledist approves proof-verifier wasm modules through
+// consensus and rolls them out to a campaign's nodes: the aggregator's
+// Catalog puts each ModuleProposal to a vote and serves committed
+// modules, nodes' Installers fetch, check, and hot-reload them, and a
+// Rollout follows their heartbeats and halts a module that fails too
+// often.
+package moduledist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost/abi"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// digestPattern matches a hex SHA-256.
+var digestPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Approval is a committed module proposal and the state of its rollout.
+type Approval struct {
+	protocol.ModuleProposal
+	CommittedAt time.Time `json:"committed_at"`
+	// HaltReason is set once the rollout is halted.
+	HaltReason string `json:"halt_reason,omitempty"`
+}
+
+// pendingModule is a module proposal open for votes.
+type pendingModule struct {
+	proposal protocol.ModuleProposal
+	wasmBin  []byte
+}
+
+// Catalog puts module proposals through a consensus Coordinator and keeps
+// the modules they commit, by campaign. The coordinator should be one the
+// catalog has to itself, since it holds one proposal open at a time, over
+// the membership of the nodes that vote on the modules.
+type Catalog struct {
+	mu          sync.RWMutex
+	coordinator *consensus.Coordinator
+	proposerID  string
+	round       int
+	pending     map[string]pendingModule
+	approved    map[string][]*Approval
+	modules     map[string][]byte
+}
+
+// NewCatalog returns a catalog proposing modules as proposerID through
+// coordinator.
+func NewCatalog(coordinator *consensus.Coordinator, proposerID string) *Catalog {
+	return &Catalog{
+		coordinator: coordinator,
+		proposerID:  proposerID,
+		pending:     make(map[string]pendingModule),
+		approved:    make(map[string][]*Approval),
+		modules:     make(map[string][]byte),
+	}
+}
+
+// Propose checks that wasmBin is the module proposal describes and that
+// it satisfies the host ABI, then opens the proposal for votes. It returns
+// the consensus proposal ID nodes vote on.
+func (c *Catalog) Propose(ctx context.Context, proposal protocol.ModuleProposal, wasmBin []byte) (string, error) {
+	if err := validateProposal(proposal); err != nil {
+		return "", err
+	}
+	if err := checkModule(ctx, proposal, wasmBin); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidProposal, err)
+	}
+	encoded, err := json.Marshal(proposal)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.round++
+	proposalID, err := c.coordinator.ProposeModel(ctx, &consensus.ModelProposal{
+		Round:      c.round,
+		Weights:    encoded,
+		ProposerID: c.proposerID,
+		Timestamp:  time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	c.pending[proposalID] = pendingModule{proposal: proposal, wasmBin: append([]byte(nil), wasmBin...)}
+	return proposalID, nil
+}
+
+// Proposal returns the module proposal open under proposalID, for a voter
+// to judge.
+func (c *Catalog) Proposal(proposalID string) (protocol.ModuleProposal, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	pending, ok := c.pending[proposalID]
+	if !ok {
+		return protocol.ModuleProposal{}, fmt.Errorf("%w: %s", consensus.ErrProposalNotFound, proposalID)
+	}
+	return pending.proposal, nil
+}
+
+// OpenProposals returns the module proposals open for campaignID, by
+// proposal ID.
+func (c *Catalog) OpenProposals(campaignID string) map[string]protocol.ModuleProposal {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	open := make(map[string]protocol.ModuleProposal)
+	for proposalID, pending := range c.pending {
+		if pending.proposal.CampaignID == campaignID {
+			open[proposalID] = pending.proposal
+		}
+	}
+	return open
+}
+
+// CastVote passes a node's vote on the module proposal open under
+// vote.ProposalID to the catalog's coordinator.
+func (c *Catalog) CastVote(ctx context.Context, vote *consensus.Vote) error {
+	c.mu.RLock()
+	_, ok := c.pending[vote.ProposalID]
+	c.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", consensus.ErrProposalNotFound, vote.ProposalID)
+	}
+	return c.coordinator.CastVote(ctx, vote)
+}
+
+// Commit commits proposalID and approves the module as the proposal that
+// was voted on describes it. Whether or not it commits, the proposal is
+// closed and the coordinator readied for the next.
+func (c *Catalog) Commit(ctx context.Context, proposalID string) (Approval, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending, ok := c.pending[proposalID]
+	if !ok {
+		return Approval{}, fmt.Errorf("%w: %s", consensus.ErrProposalNotFound, proposalID)
+	}
+	delete(c.pending, proposalID)
+	defer c.coordinator.Reset()
+
+	if err := c.coordinator.CommitModel(ctx, proposalID); err != nil {
+		return Approval{}, err
+	}
+	round, err := c.coordinator.GetConsensusRound(proposalID)
+	if err != nil {
+		return Approval{}, err
+	}
+	var committed protocol.ModuleProposal
+	if err := json.Unmarshal(round.ModelWeights, &committed); err != nil {
+		return Approval{}, fmt.Errorf("%w: committed proposal: %v", ErrInvalidProposal, err)
+	}
+	approval := &Approval{ModuleProposal: committed, CommittedAt: time.Now()}
+	c.approved[committed.CampaignID] = append(c.approved[committed.CampaignID], approval)
+	c.modules[committed.Digest] = pending.wasmBin
+	return *approval, nil
+}
+
+// Approved returns the committed proposal naming digest for campaignID. It
+// fails with ErrNotApproved if none does, and ErrRolloutHalted if the
+// module's rollout was halted.
+func (c *Catalog) Approved(campaignID, digest string) (protocol.ModuleProposal, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	approval := c.approvalLocked(campaignID, digest)
+	switch {
+	case approval == nil:
+		return protocol.ModuleProposal{}, fmt.Errorf("%w: %s for campaign %s", ErrNotApproved, digest, campaignID)
+	case approval.HaltReason != "":
+		return protocol.ModuleProposal{}, fmt.Errorf("%w: %s: %s", ErrRolloutHalted, digest, approval.HaltReason)
+	}
+	return approval.ModuleProposal, nil
+}
+
+// Approvals returns campaignID's committed modules, oldest first.
+func (c *Catalog) Approvals(campaignID string) []Approval {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	approvals := make([]Approval, 0, len(c.approved[campaignID]))
+	for _, approval := range c.approved[campaignID] {
+		approvals = append(approvals, *approval)
+	}
+	return approvals
+}
+
+// Latest returns the module campaignID's nodes should be running: its
+// most recently committed one.
+func (c *Catalog) Latest(campaignID string) (Approval, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	approvals := c.approved[campaignID]
+	if len(approvals) == 0 {
+		return Approval{}, false
+	}
+	return *approvals[len(approvals)-1], true
+}
+
+// Module returns the bytes of a module committed for campaignID, for
+// serving to its nodes.
+func (c *Catalog) Module(campaignID, digest string) ([]byte, error) {
+	if _, err := c.Approved(campaignID, digest); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.modules[digest], nil
+}
+
+// Halt stops campaignID's rollout of digest: nodes not yet running it
+// refuse it from now on.
+func (c *Catalog) Halt(campaignID, digest, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if approval := c.approvalLocked(campaignID, digest); approval != nil && approval.HaltReason == "" {
+		approval.HaltReason = reason
+	}
+}
+
+// Campaigns returns the campaigns with committed modules, sorted.
+func (c *Catalog) Campaigns() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	campaigns := make([]string, 0, len(c.approved))
+	for campaignID := range c.approved {
+		campaigns = append(campaigns, campaignID)
+	}
+	sort.Strings(campaigns)
+	return campaigns
+}
+
+// approvalLocked returns the latest approval of digest for campaignID, or
+// nil. Callers must hold c.mu.
+func (c *Catalog) approvalLocked(campaignID, digest string) *Approval {
+	approvals := c.approved[campaignID]
+	for i := len(approvals) - 1; i >= 0; i-- {
+		if approvals[i].Digest == digest {
+			return approvals[i]
+		}
+	}
+	return nil
+}
+
+// validateProposal checks that proposal names a campaign, a digest, a
+// size, the host's ABI version, and somewhere to fetch the module.
+func validateProposal(proposal protocol.ModuleProposal) error {
+	switch {
+	case proposal.CampaignID == "":
+		return fmt.Errorf("%w: no campaign", ErrInvalidProposal)
+	case !digestPattern.MatchString(proposal.Digest):
+		return fmt.Errorf("%w: digest %q is not a hex SHA-256", ErrInvalidProposal, proposal.Digest)
+	case proposal.Size <= 0:
+		return fmt.Errorf("%w: size %d", ErrInvalidProposal, proposal.Size)
+	case proposal.ABIVersion != abi.Version:
+		return fmt.Errorf("%w: ABI version %d, host speaks %d", ErrInvalidProposal, proposal.ABIVersion, abi.Version)
+	case len(proposal.FetchURLs) == 0:
+		return fmt.Errorf("%w: no fetch URLs", ErrInvalidProposal)
+	}
+	return nil
+}
+
+// checkModule checks that wasmBin is the module proposal describes: its
+// size and digest, and an ABI that validates at the proposal's version.
+func checkModule(ctx context.Context, proposal protocol.ModuleProposal, wasmBin []byte) error {
+	if int64(len(wasmBin)) != proposal.Size {
+		return fmt.Errorf("%w: %d bytes, proposal says %d", ErrDigestMismatch, len(wasmBin), proposal.Size)
+	}
+	if digest := wasmhost.ModuleDigest(wasmBin); digest != proposal.Digest {
+		return fmt.Errorf("%w: got %s, want %s", ErrDigestMismatch, digest, proposal.Digest)
+	}
+	report, err := abi.ValidateModule(ctx, wasmBin)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrIncompatibleModule, err)
+	}
+	if err := report.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrIncompatibleModule, err)
+	}
+	if report.ABIVersion != proposal.ABIVersion {
+		return fmt.Errorf("%w: module reports ABI version %d, proposal says %d", ErrIncompatibleModule, report.ABIVersion, proposal.ABIVersion)
+	}
+	return nil
+}
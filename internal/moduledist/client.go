@@ -0,0 +1,105 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package moduledist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// maxApprovalsBytes bounds the approval list a CatalogClient reads.
+const maxApprovalsBytes = 4 << 20
+
+// CatalogClient reads the modules an aggregator's Catalog committed from
+// its /api/modules endpoints. It is the Approver of a node that keeps no
+// catalog of its own.
+type CatalogClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewCatalogClient returns a client of the catalog served at baseURL,
+// using client, or one with a 10s timeout if nil.
+func NewCatalogClient(baseURL string, client *http.Client) *CatalogClient {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &CatalogClient{baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+// ModuleURL is where the catalog serves the committed module digest of
+// campaignID.
+func (c *CatalogClient) ModuleURL(campaignID, digest string) string {
+	return c.baseURL + "/api/modules/" + url.PathEscape(campaignID) + "/" + url.PathEscape(digest)
+}
+
+// Approvals returns campaignID's committed modules, oldest first.
+func (c *CatalogClient) Approvals(ctx context.Context, campaignID string) ([]Approval, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/modules/"+url.PathEscape(campaignID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetchFailed, err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetchFailed, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: approvals of %s answered %s", ErrFetchFailed, campaignID, resp.Status)
+	}
+	var body struct {
+		Approvals []Approval `json:"approvals"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxApprovalsBytes)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: approvals of %s: %v", ErrFetchFailed, campaignID, err)
+	}
+	return body.Approvals, nil
+}
+
+// Latest returns the module campaignID's nodes should be running: its most
+// recently committed one.
+func (c *CatalogClient) Latest(ctx context.Context, campaignID string) (Approval, bool, error) {
+	approvals, err := c.Approvals(ctx, campaignID)
+	if err != nil || len(approvals) == 0 {
+		return Approval{}, false, err
+	}
+	return approvals[len(approvals)-1], true, nil
+}
+
+// Approved returns the committed proposal naming digest for campaignID as
+// the catalog reports it, fetched first from the catalog itself and then
+// from the proposal's own fetch URLs. It fails like Catalog.Approved.
+func (c *CatalogClient) Approved(campaignID, digest string) (protocol.ModuleProposal, error) {
+	timeout := c.client.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	approvals, err := c.Approvals(ctx, campaignID)
+	if err != nil {
+		return protocol.ModuleProposal{}, err
+	}
+	for i := len(approvals) - 1; i >= 0; i-- {
+		approval := approvals[i]
+		if approval.Digest != digest {
+			continue
+		}
+		if approval.HaltReason != "" {
+			return protocol.ModuleProposal{}, fmt.Errorf("%w: %s: %s", ErrRolloutHalted, digest, approval.HaltReason)
+		}
+		proposal := approval.ModuleProposal
+		proposal.FetchURLs = append([]string{c.ModuleURL(campaignID, digest)}, proposal.FetchURLs...)
+		return proposal, nil
+	}
+	return protocol.ModuleProposal{}, fmt.Errorf("%w: %s for campaign %s", ErrNotApproved, digest, campaignID)
+}
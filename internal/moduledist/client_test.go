@@ -0,0 +1,85 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package moduledist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost"
+)
+
+// serveCatalog serves sim's catalog the way the aggregator's
+// /api/modules endpoints do.
+func serveCatalog(t *testing.T, sim *simulation) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/modules/{campaign}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"approvals": sim.catalog.Approvals(r.PathValue("campaign"))})
+	})
+	mux.HandleFunc("GET /api/modules/{campaign}/{digest}", func(w http.ResponseWriter, r *http.Request) {
+		wasmBin, err := sim.catalog.Module(r.PathValue("campaign"), r.PathValue("digest"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(wasmBin))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEdgeInstallsFromRemoteCatalog(t *testing.T) {
+	ctx := context.Background()
+	sim := newSimulation(t, 3, DefaultRolloutPolicy())
+	server := serveCatalog(t, sim)
+	client := NewCatalogClient(server.URL+"/", server.Client())
+
+	if err := sim.catalog.CastVote(ctx, &consensus.Vote{NodeID: "node-1", ProposalID: "missing", Approve: true, Timestamp: time.Now()}); !errors.Is(err, consensus.ErrProposalNotFound) {
+		t.Fatalf("vote on unknown proposal = %v, want ErrProposalNotFound", err)
+	}
+	if _, ok, err := client.Latest(ctx, campaign); err != nil || ok {
+		t.Fatalf("latest before any commit = %v (%v)", ok, err)
+	}
+
+	v1 := readModule(t, "../wasmhost/testdata/verify_delay.wasm")
+	approved := sim.approve(t, v1, "http://mirror.invalid/v1.wasm")
+	latest, ok, err := client.Latest(ctx, campaign)
+	if err != nil || !ok || latest.Digest != approved.Digest {
+		t.Fatalf("latest = %+v, %v (%v)", latest, ok, err)
+	}
+	proposal, err := client.Approved(campaign, approved.Digest)
+	if err != nil {
+		t.Fatalf("approved: %v", err)
+	}
+	if proposal.FetchURLs[0] != client.ModuleURL(campaign, approved.Digest) {
+		t.Fatalf("fetch urls = %v, want the catalog first", proposal.FetchURLs)
+	}
+
+	registry := wasmhost.NewRegistry()
+	t.Cleanup(func() { _ = registry.Close(context.Background()) })
+	installer := NewInstaller(campaign, client, NewFetcher(server.Client(), 64), registry)
+	if err := installer.Install(ctx, approved.Digest); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if installer.ActiveDigest() != approved.Digest || registry.Default() == nil {
+		t.Fatalf("active = %q, want %q", installer.ActiveDigest(), approved.Digest)
+	}
+
+	sim.catalog.Halt(campaign, approved.Digest, "error budget exhausted")
+	if _, err := client.Approved(campaign, approved.Digest); !errors.Is(err, ErrRolloutHalted) {
+		t.Fatalf("halted module = %v, want ErrRolloutHalted", err)
+	}
+	if _, err := client.Approved(campaign, "sha256:unknown"); !errors.Is(err, ErrNotApproved) {
+		t.Fatalf("unknown module = %v, want ErrNotApproved", err)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package moduledist
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the module catalog, installer, and
+// fetcher. Match them with errors.Is; never compare error strings.
+var (
+	// ErrInvalidProposal means a module proposal is missing a field, or
+	// the module offered with it does not match its digest, size, or ABI
+	// version. Not retryable.
+	ErrInvalidProposal = errors.New("invalid module proposal")
+	// ErrNotApproved means no committed proposal names the module's digest
+	// for the campaign. Retryable once a proposal for it commits.
+	ErrNotApproved = errors.New("module not approved")
+	// ErrRolloutHalted means the module's rollout was halted after its
+	// verification failure rate rose. Not retryable.
+	ErrRolloutHalted = errors.New("module rollout halted")
+	// ErrDigestMismatch means fetched bytes do not hash to the approved
+	// digest. Retryable from another fetch URL.
+	ErrDigestMismatch = errors.New("module digest mismatch")
+	// ErrIncompatibleModule means a fetched module fails ABI validation or
+	// reports an ABI version other than the approved one. Not retryable.
+	ErrIncompatibleModule = errors.New("incompatible module")
+	// ErrFetchFailed means no fetch URL served the module. Retryable.
+	ErrFetchFailed = errors.New("module fetch failed")
+)
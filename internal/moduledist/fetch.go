@@ -0,0 +1,97 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package moduledist
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultChunkSize is how many bytes a Fetcher asks for per request unless
+// NewFetcher is given another size.
+const DefaultChunkSize = 256 << 10
+
+// Fetcher downloads modules in chunks with HTTP Range requests, so a large
+// module never rides on one long response and a client can bound each
+// read.
+type Fetcher struct {
+	client    *http.Client
+	chunkSize int64
+}
+
+// NewFetcher returns a fetcher using client, or http.DefaultClient if nil,
+// asking for chunkSize bytes at a time; non-positive sizes take
+// DefaultChunkSize.
+func NewFetcher(client *http.Client, chunkSize int64) *Fetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &Fetcher{client: client, chunkSize: chunkSize}
+}
+
+// Fetch downloads the size bytes served at url. A server that ignores
+// Range and answers with the whole module is accepted as long as the
+// module is size bytes; anything longer is refused unread.
+func (f *Fetcher) Fetch(ctx context.Context, url string, size int64) ([]byte, error) {
+	module := make([]byte, 0, size)
+	for int64(len(module)) < size {
+		offset := int64(len(module))
+		end := min(offset+f.chunkSize, size) - 1
+		chunk, whole, err := f.fetchRange(ctx, url, offset, end, size)
+		if err != nil {
+			return nil, err
+		}
+		if whole {
+			if int64(len(chunk)) != size {
+				return nil, fmt.Errorf("%w: %s served %d bytes, want %d", ErrFetchFailed, url, len(chunk), size)
+			}
+			return chunk, nil
+		}
+		if len(chunk) == 0 {
+			return nil, fmt.Errorf("%w: %s served an empty range at %d", ErrFetchFailed, url, offset)
+		}
+		module = append(module, chunk...)
+	}
+	return module, nil
+}
+
+// fetchRange requests bytes offset through end of the size-byte module at
+// url. It reports whole when the server answered with the entire module
+// instead.
+func (f *Fetcher) fetchRange(ctx context.Context, url string, offset, end, size int64) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrFetchFailed, err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, end))
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	limit := end - offset + 1
+	whole := false
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		whole = true
+		limit = size
+	default:
+		return nil, false, fmt.Errorf("%w: %s answered %s", ErrFetchFailed, url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrFetchFailed, err)
+	}
+	if int64(len(body)) > limit {
+		return nil, false, fmt.Errorf("%w: %s served more than %d bytes", ErrFetchFailed, url, limit)
+	}
+	return body, whole, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package moduledist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Approver answers whether a module digest was committed for a campaign;
+// a Catalog is one.
+type Approver interface {
+	Approved(campaignID, digest string) (protocol.ModuleProposal, error)
+}
+
+// Installer installs a campaign's approved verifier modules on a node.
+// It installs nothing its Approver has not seen committed.
+type Installer struct {
+	campaignID string
+	approver   Approver
+	fetcher    *Fetcher
+	registry   *wasmhost.Registry
+
+	mu            sync.Mutex
+	active        string
+	verifications uint64
+	failures      uint64
+}
+
+// NewInstaller returns an installer of campaignID's modules that fetches
+// them with fetcher and hot-reloads them into registry.
+func NewInstaller(campaignID string, approver Approver, fetcher *Fetcher, registry *wasmhost.Registry) *Installer {
+	return &Installer{campaignID: campaignID, approver: approver, fetcher: fetcher, registry: registry}
+}
+
+// Install makes the module with digest the registry's default. The digest
+// must be committed for the campaign; Install refuses it otherwise, before
+// fetching anything. It tries the proposal's fetch URLs in order and
+// accepts the first module of the approved size and digest that validates
+// at the approved ABI version. Installing the active module is a no-op.
+func (i *Installer) Install(ctx context.Context, digest string) error {
+	proposal, err := i.approver.Approved(i.campaignID, digest)
+	if err != nil {
+		return err
+	}
+	if i.ActiveDigest() == digest {
+		return nil
+	}
+
+	var errs []error
+	for _, url := range proposal.FetchURLs {
+		wasmBin, err := i.fetcher.Fetch(ctx, url, proposal.Size)
+		if err == nil {
+			err = checkModule(ctx, proposal, wasmBin)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := i.registry.HotReload(ctx, wasmBin); err != nil {
+			return fmt.Errorf("%w: %w", ErrIncompatibleModule, err)
+		}
+		i.mu.Lock()
+		i.active = digest
+		i.verifications, i.failures = 0, 0
+		i.mu.Unlock()
+		return nil
+	}
+	return fmt.Errorf("module %s from %d URLs: %w", digest, len(proposal.FetchURLs), errors.Join(errs...))
+}
+
+// ActiveDigest returns the digest of the module the installer last
+// installed, or "" before the first.
+func (i *Installer) ActiveDigest() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.active
+}
+
+// ObserveVerification counts a verification by the active module toward
+// the counts StampHeartbeat reports. It is a wasmhost.VerifyObserver.
+func (i *Installer) ObserveVerification(sample monitoring.VerificationSample) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.active == "" || sample.Module != i.active {
+		return
+	}
+	i.verifications++
+	if sample.Outcome == monitoring.VerifyError {
+		i.failures++
+	}
+}
+
+// StampHeartbeat reports the active module and its verification counts in
+// update.
+func (i *Installer) StampHeartbeat(update *protocol.StatusUpdate) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.active == "" {
+		return
+	}
+	update.Module = &protocol.ModuleStatus{
+		CampaignID:    i.campaignID,
+		Digest:        i.active,
+		Verifications: i.verifications,
+		Failures:      i.failures,
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package moduledist
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// RolloutPolicy sets when a rollout is halted: once the nodes running the
+// new module have verified at least MinVerifications proofs between them
+// and more than MaxFailureRate of those ended in an error.
+type RolloutPolicy struct {
+	MaxFailureRate   float64
+	MinVerifications uint64
+}
+
+// DefaultRolloutPolicy halts a module failing more than 5% of at least 100
+// verifications.
+func DefaultRolloutPolicy() RolloutPolicy {
+	return RolloutPolicy{MaxFailureRate: 0.05, MinVerifications: 100}
+}
+
+// RolloutProgress is how far a campaign's latest module has spread.
+type RolloutProgress struct {
+	CampaignID string `json:"campaign_id"`
+	Digest     string `json:"digest"`
+	// Nodes counts the nodes that have reported a module; Active those
+	// running Digest, and Fraction is Active over Nodes.
+	Nodes         int     `json:"nodes"`
+	Active        int     `json:"active"`
+	Fraction      float64 `json:"fraction"`
+	Verifications uint64  `json:"verifications"`
+	Failures      uint64  `json:"failures"`
+	FailureRate   float64 `json:"failure_rate"`
+	Halted        bool    `json:"halted,omitempty"`
+	HaltReason    string  `json:"halt_reason,omitempty"`
+}
+
+// Rollout follows the modules nodes report in their heartbeats and halts
+// a campaign's latest module in its Catalog once it fails too often.
+type Rollout struct {
+	mu      sync.Mutex
+	catalog *Catalog
+	policy  RolloutPolicy
+	nodes   map[string]map[string]protocol.ModuleStatus
+}
+
+// NewRollout returns a rollout tracker for catalog's campaigns.
+func NewRollout(catalog *Catalog, policy RolloutPolicy) *Rollout {
+	return &Rollout{catalog: catalog, policy: policy, nodes: make(map[string]map[string]protocol.ModuleStatus)}
+}
+
+// Observe records the module a heartbeat reports and halts the campaign's
+// latest module if its failure rate has crossed the policy's limit.
+// Heartbeats without a module are ignored.
+func (r *Rollout) Observe(update protocol.StatusUpdate) {
+	if update.Module == nil {
+		return
+	}
+	campaignID := update.Module.CampaignID
+	r.mu.Lock()
+	if r.nodes[campaignID] == nil {
+		r.nodes[campaignID] = make(map[string]protocol.ModuleStatus)
+	}
+	r.nodes[campaignID][update.NodeID] = *update.Module
+	r.mu.Unlock()
+
+	progress := r.Progress(campaignID)
+	if progress.Digest == "" || progress.Halted || progress.Verifications < r.policy.MinVerifications || progress.FailureRate <= r.policy.MaxFailureRate {
+		return
+	}
+	r.catalog.Halt(campaignID, progress.Digest, fmt.Sprintf("%d of %d verifications failed on %d nodes", progress.Failures, progress.Verifications, progress.Active))
+}
+
+// Progress returns how far campaignID's latest module has spread.
+func (r *Rollout) Progress(campaignID string) RolloutProgress {
+	progress := RolloutProgress{CampaignID: campaignID}
+	if latest, ok := r.catalog.Latest(campaignID); ok {
+		progress.Digest = latest.Digest
+		progress.HaltReason = latest.HaltReason
+		progress.Halted = latest.HaltReason != ""
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, status := range r.nodes[campaignID] {
+		progress.Nodes++
+		if progress.Digest == "" || status.Digest != progress.Digest {
+			continue
+		}
+		progress.Active++
+		progress.Verifications += status.Verifications
+		progress.Failures += status.Failures
+	}
+	if progress.Nodes > 0 {
+		progress.Fraction = float64(progress.Active) / float64(progress.Nodes)
+	}
+	if progress.Verifications > 0 {
+		progress.FailureRate = float64(progress.Failures) / float64(progress.Verifications)
+	}
+	return progress
+}
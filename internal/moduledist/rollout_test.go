@@ -0,0 +1,260 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package moduledist
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost/abi"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+const campaign = "traffic-v2"
+
+func readModule(t *testing.T, path string) []byte {
+	t.Helper()
+	wasmBin, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return wasmBin
+}
+
+// simulation is an aggregator's catalog serving modules to nodes, each
+// with its own verifier registry.
+type simulation struct {
+	catalog *Catalog
+	rollout *Rollout
+	coord   *consensus.Coordinator
+	server  *httptest.Server
+	voters  []string
+	ranges  atomic.Int64
+}
+
+func newSimulation(t *testing.T, nodes int, policy RolloutPolicy) *simulation {
+	t.Helper()
+	sim := &simulation{coord: consensus.NewCoordinator("node-1", nodes, 5*time.Second)}
+	sim.catalog = NewCatalog(sim.coord, "node-1")
+	sim.rollout = NewRollout(sim.catalog, policy)
+	sim.voters = []string{"node-1"}
+	for i := 1; i < nodes; i++ {
+		sim.voters = append(sim.voters, fmt.Sprintf("member-%d", i))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /modules/{campaign}/{digest}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			sim.ranges.Add(1)
+		}
+		wasmBin, err := sim.catalog.Module(r.PathValue("campaign"), r.PathValue("digest"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(wasmBin))
+	})
+	mux.HandleFunc("GET /tampered/{digest}", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(bytes.Repeat([]byte{0}, 171)))
+	})
+	sim.server = httptest.NewServer(mux)
+	t.Cleanup(sim.server.Close)
+	return sim
+}
+
+// approve proposes wasmBin and commits it with every node's approval.
+func (sim *simulation) approve(t *testing.T, wasmBin []byte, mirrors ...string) protocol.ModuleProposal {
+	t.Helper()
+	ctx := context.Background()
+	digest := wasmhost.ModuleDigest(wasmBin)
+	proposal := protocol.ModuleProposal{
+		CampaignID: campaign,
+		Digest:     digest,
+		Size:       int64(len(wasmBin)),
+		ABIVersion: abi.Version,
+		FetchURLs:  append(mirrors, sim.server.URL+"/modules/"+campaign+"/"+digest),
+		Changelog:  "verifier update",
+	}
+	proposalID, err := sim.catalog.Propose(ctx, proposal, wasmBin)
+	if err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+	if got, err := sim.catalog.Proposal(proposalID); err != nil || got.Digest != digest {
+		t.Fatalf("open proposal = %+v (%v)", got, err)
+	}
+	for _, voter := range sim.voters {
+		if err := sim.catalog.CastVote(ctx, &consensus.Vote{NodeID: voter, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote %s: %v", voter, err)
+		}
+	}
+	approval, err := sim.catalog.Commit(ctx, proposalID)
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	return approval.ModuleProposal
+}
+
+// node is one simulated node's registry and installer.
+type node struct {
+	id        string
+	registry  *wasmhost.Registry
+	installer *Installer
+}
+
+func (sim *simulation) nodes(t *testing.T) []*node {
+	t.Helper()
+	nodes := make([]*node, len(sim.voters))
+	for i, id := range sim.voters {
+		registry := wasmhost.NewRegistry()
+		t.Cleanup(func() { _ = registry.Close(context.Background()) })
+		installer := NewInstaller(campaign, sim.catalog, NewFetcher(sim.server.Client(), 64), registry)
+		registry.SetObserver(installer.ObserveVerification)
+		nodes[i] = &node{id: id, registry: registry, installer: installer}
+	}
+	return nodes
+}
+
+func (sim *simulation) heartbeat(n *node) {
+	update := protocol.StatusUpdate{NodeID: n.id, Status: "training"}
+	n.installer.StampHeartbeat(&update)
+	sim.rollout.Observe(update)
+}
+
+func TestModuleRollsOutToTwentyNodes(t *testing.T) {
+	ctx := context.Background()
+	sim := newSimulation(t, 20, DefaultRolloutPolicy())
+	nodes := sim.nodes(t)
+	v1 := readModule(t, "../wasmhost/testdata/verify_delay.wasm")
+	v2 := readModule(t, "../wasmhost/abi/testdata/conformance.wasm")
+
+	first := sim.approve(t, v1)
+	for _, n := range nodes {
+		if err := n.installer.Install(ctx, first.Digest); err != nil {
+			t.Fatalf("%s install v1: %v", n.id, err)
+		}
+		sim.heartbeat(n)
+	}
+	if progress := sim.rollout.Progress(campaign); progress.Digest != first.Digest || progress.Active != 20 || progress.Fraction != 1 {
+		t.Fatalf("v1 progress = %+v", progress)
+	}
+
+	// v2 lists a mirror serving tampered bytes first; nodes fall back to
+	// the aggregator.
+	second := sim.approve(t, v2, sim.server.URL+"/tampered/x")
+	if progress := sim.rollout.Progress(campaign); progress.Digest != second.Digest || progress.Active != 0 || progress.Nodes != 20 {
+		t.Fatalf("progress before v2 installs = %+v", progress)
+	}
+	sim.ranges.Store(0)
+	for i, n := range nodes {
+		if err := n.installer.Install(ctx, second.Digest); err != nil {
+			t.Fatalf("%s install v2: %v", n.id, err)
+		}
+		if verified, err := n.registry.Default().Verify(ctx, []byte{1}); err != nil || !verified {
+			t.Fatalf("%s verify with v2: %v, %v", n.id, verified, err)
+		}
+		sim.heartbeat(n)
+		if i == 9 {
+			if progress := sim.rollout.Progress(campaign); progress.Active != 10 || progress.Fraction != 0.5 {
+				t.Fatalf("halfway progress = %+v", progress)
+			}
+		}
+	}
+	progress := sim.rollout.Progress(campaign)
+	if progress.Active != 20 || progress.Fraction != 1 || progress.Verifications != 20 || progress.Failures != 0 || progress.Halted {
+		t.Fatalf("v2 progress = %+v", progress)
+	}
+	for _, n := range nodes {
+		if got := n.registry.Default().Digest(); got != second.Digest {
+			t.Fatalf("%s runs %s", n.id, got)
+		}
+	}
+	// 171 bytes in 64-byte chunks is three ranges per node.
+	if got := sim.ranges.Load(); got != 20*3 {
+		t.Fatalf("%d range requests to the aggregator, want 60", got)
+	}
+}
+
+func TestNodesRefuseUncommittedModules(t *testing.T) {
+	ctx := context.Background()
+	sim := newSimulation(t, 4, DefaultRolloutPolicy())
+	n := sim.nodes(t)[0]
+	v1 := readModule(t, "../wasmhost/testdata/verify_delay.wasm")
+	rogue := readModule(t, "../wasmhost/abi/testdata/conformance.wasm")
+	first := sim.approve(t, v1)
+	if err := n.installer.Install(ctx, first.Digest); err != nil {
+		t.Fatalf("install v1: %v", err)
+	}
+
+	sim.ranges.Store(0)
+	rogueDigest := wasmhost.ModuleDigest(rogue)
+	if err := n.installer.Install(ctx, rogueDigest); !errors.Is(err, ErrNotApproved) {
+		t.Fatalf("uncommitted digest: expected ErrNotApproved, got %v", err)
+	}
+	if _, err := sim.catalog.Module(campaign, rogueDigest); !errors.Is(err, ErrNotApproved) {
+		t.Fatalf("serving an uncommitted digest: expected ErrNotApproved, got %v", err)
+	}
+	if got := n.registry.Default().Digest(); got != first.Digest || sim.ranges.Load() != 0 {
+		t.Fatalf("node runs %s after %d fetches", got, sim.ranges.Load())
+	}
+
+	// A digest committed for another campaign is not committed for this
+	// one, and a proposal whose module does not match it never opens.
+	other := NewInstaller("parking", sim.catalog, NewFetcher(nil, 0), wasmhost.NewRegistry())
+	if err := other.Install(ctx, first.Digest); !errors.Is(err, ErrNotApproved) {
+		t.Fatalf("other campaign: expected ErrNotApproved, got %v", err)
+	}
+	mismatched := protocol.ModuleProposal{CampaignID: campaign, Digest: rogueDigest, Size: int64(len(v1)), ABIVersion: abi.Version, FetchURLs: []string{sim.server.URL}}
+	if _, err := sim.catalog.Propose(ctx, mismatched, v1); !errors.Is(err, ErrInvalidProposal) || !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("mismatched proposal: %v", err)
+	}
+}
+
+func TestRolloutHaltsOnElevatedFailureRate(t *testing.T) {
+	ctx := context.Background()
+	sim := newSimulation(t, 4, RolloutPolicy{MaxFailureRate: 0.1, MinVerifications: 20})
+	nodes := sim.nodes(t)
+	v1 := readModule(t, "../wasmhost/testdata/verify_delay.wasm")
+	first := sim.approve(t, v1)
+	for _, n := range nodes[:2] {
+		if err := n.installer.Install(ctx, first.Digest); err != nil {
+			t.Fatalf("install: %v", err)
+		}
+	}
+
+	// Two nodes report 2 errors in 10 verifications each: 20%, but only
+	// halted once 20 verifications are in.
+	report := func(n *node) {
+		for i := 0; i < 10; i++ {
+			outcome := monitoring.VerifyAccepted
+			if i < 2 {
+				outcome = monitoring.VerifyError
+			}
+			n.installer.ObserveVerification(monitoring.VerificationSample{Module: first.Digest, Outcome: outcome})
+		}
+		sim.heartbeat(n)
+	}
+	report(nodes[0])
+	if progress := sim.rollout.Progress(campaign); progress.Halted || progress.FailureRate != 0.2 {
+		t.Fatalf("after 10 verifications: %+v", progress)
+	}
+	report(nodes[1])
+	progress := sim.rollout.Progress(campaign)
+	if !progress.Halted || progress.Verifications != 20 || progress.Failures != 4 {
+		t.Fatalf("after 20 verifications: %+v", progress)
+	}
+	if err := nodes[2].installer.Install(ctx, first.Digest); !errors.Is(err, ErrRolloutHalted) {
+		t.Fatalf("install after halt: expected ErrRolloutHalted, got %v", err)
+	}
+}
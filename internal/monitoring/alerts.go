@@ -0,0 +1,149 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package monitoring
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+)
+
+// Round alert rules.
+const (
+	// AlertRoundAborted fires when a federation's round aborts and
+	// resolves when it next commits one.
+	AlertRoundAborted = "FederatedRoundAborted"
+	// AlertRollbackExecuted fires when a federation's model is rolled back
+	// and resolves when it next commits a round.
+	AlertRollbackExecuted = "ModelRollbackExecuted"
+	// AlertQuorumAtRisk fires when a federation's proposal closes flagged
+	// at risk of missing quorum and resolves when one commits unflagged.
+	AlertQuorumAtRisk = "ConsensusQuorumAtRisk"
+)
+
+// RoundAlert is a round alert firing or resolving for one federation.
+type RoundAlert struct {
+	Rule         string    `json:"rule"`
+	Firing       bool      `json:"firing"`
+	FederationID string    `json:"federation_id,omitempty"`
+	Round        int       `json:"round"`
+	Summary      string    `json:"summary"`
+	At           time.Time `json:"at"`
+}
+
+// RoundAlertListener receives round alert transitions.
+type RoundAlertListener func(alert RoundAlert)
+
+type roundAlertKey struct {
+	rule         string
+	federationID string
+}
+
+// RoundAlerts raises alerts from the round lifecycle: AlertRoundAborted
+// while a federation's latest round aborted and AlertRollbackExecuted
+// after a rollback, both until the federation commits again, and
+// AlertQuorumAtRisk while its proposals keep closing at risk of missing
+// quorum. It follows a Bus through Subscribe.
+type RoundAlerts struct {
+	mu        sync.Mutex
+	firing    map[roundAlertKey]RoundAlert
+	listeners []RoundAlertListener
+}
+
+// NewRoundAlerts creates an alerter with nothing firing.
+func NewRoundAlerts() *RoundAlerts {
+	return &RoundAlerts{firing: make(map[roundAlertKey]RoundAlert)}
+}
+
+// AddAlertListener registers a listener for alert transitions. Listeners
+// run on the goroutine that observed the transition, the subscription's
+// own under Subscribe.
+func (a *RoundAlerts) AddAlertListener(listener RoundAlertListener) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.listeners = append(a.listeners, listener)
+}
+
+// Subscribe feeds the alerter bus's aborted, rollback, and commit events.
+func (a *RoundAlerts) Subscribe(bus *events.Bus) *events.Subscription {
+	return bus.Handle("alerts", events.DefaultBuffer, a.Observe, events.KindAborted, events.KindRollbackExecuted, events.KindCommitted)
+}
+
+// Observe applies one lifecycle event. A round aborting or a rollback
+// fires its alert, unless already firing for the federation; a commit
+// resolves every alert firing for it. A commit or abort flagged
+// QuorumAtRisk fires AlertQuorumAtRisk, which only an unflagged commit
+// resolves.
+func (a *RoundAlerts) Observe(event events.Event) {
+	var transitions []RoundAlert
+	a.mu.Lock()
+	if event.QuorumAtRisk {
+		transitions = a.fireLocked(AlertQuorumAtRisk, event, fmt.Sprintf("round %d proposal %s reached quorum late for too many rounds running", event.Round, event.ProposalID))
+	}
+	switch event.Kind {
+	case events.KindAborted:
+		transitions = append(transitions, a.fireLocked(AlertRoundAborted, event, fmt.Sprintf("round %d proposal %s aborted: %s", event.Round, event.ProposalID, event.Reason))...)
+	case events.KindRollbackExecuted:
+		transitions = append(transitions, a.fireLocked(AlertRollbackExecuted, event, fmt.Sprintf("round %d rolled the model back to round %d", event.Round, event.TargetRound))...)
+	case events.KindCommitted:
+		rules := []string{AlertRoundAborted, AlertRollbackExecuted}
+		if !event.QuorumAtRisk {
+			rules = append(rules, AlertQuorumAtRisk)
+		}
+		for _, rule := range rules {
+			key := roundAlertKey{rule: rule, federationID: event.FederationID}
+			if _, ok := a.firing[key]; !ok {
+				continue
+			}
+			delete(a.firing, key)
+			transitions = append(transitions, RoundAlert{
+				Rule:         rule,
+				FederationID: event.FederationID,
+				Round:        event.Round,
+				Summary:      fmt.Sprintf("round %d committed", event.Round),
+				At:           event.At,
+			})
+		}
+	}
+	listeners := append([]RoundAlertListener(nil), a.listeners...)
+	a.mu.Unlock()
+
+	for _, alert := range transitions {
+		for _, listener := range listeners {
+			listener(alert)
+		}
+	}
+}
+
+// fireLocked fires rule for event's federation unless it is firing. The
+// caller holds a.mu.
+func (a *RoundAlerts) fireLocked(rule string, event events.Event, summary string) []RoundAlert {
+	key := roundAlertKey{rule: rule, federationID: event.FederationID}
+	if _, ok := a.firing[key]; ok {
+		return nil
+	}
+	alert := RoundAlert{Rule: rule, Firing: true, FederationID: event.FederationID, Round: event.Round, Summary: summary, At: event.At}
+	a.firing[key] = alert
+	return []RoundAlert{alert}
+}
+
+// Firing returns the alerts firing, sorted by rule then federation.
+func (a *RoundAlerts) Firing() []RoundAlert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	alerts := make([]RoundAlert, 0, len(a.firing))
+	for _, alert := range a.firing {
+		alerts = append(alerts, alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Rule != alerts[j].Rule {
+			return alerts[i].Rule < alerts[j].Rule
+		}
+		return alerts[i].FederationID < alerts[j].FederationID
+	})
+	return alerts
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package monitoring
+
+import (
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+)
+
+func TestRoundAlertsFollowTheLifecycle(t *testing.T) {
+	bus := events.NewBus()
+	alerter := NewRoundAlerts()
+	var alerts []RoundAlert
+	alerter.AddAlertListener(func(alert RoundAlert) { alerts = append(alerts, alert) })
+	sub := alerter.Subscribe(bus)
+
+	bus.Publish(events.Event{Kind: events.KindCommitted, FederationID: "traffic", Round: 1})
+	bus.Publish(events.Event{Kind: events.KindAborted, FederationID: "traffic", Round: 2, ProposalID: "p-2", Reason: events.ReasonQuorumNotReached})
+	bus.Publish(events.Event{Kind: events.KindAborted, FederationID: "traffic", Round: 2, Reason: events.ReasonReset})
+	bus.Publish(events.Event{Kind: events.KindAborted, FederationID: "energy", Round: 7, Reason: events.ReasonReset})
+	bus.Publish(events.Event{Kind: events.KindRollbackExecuted, FederationID: "traffic", Round: 3, TargetRound: 1})
+	bus.Publish(events.Event{Kind: events.KindQuorumReached, FederationID: "traffic", Round: 4})
+	sub.Close()
+
+	firing := alerter.Firing()
+	if len(firing) != 3 || firing[0].Rule != AlertRoundAborted || firing[0].FederationID != "energy" || firing[2].Rule != AlertRollbackExecuted {
+		t.Fatalf("firing = %+v", firing)
+	}
+	if len(alerts) != 3 || alerts[0].Summary != "round 2 proposal p-2 aborted: quorum_not_reached" {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+
+	sub = alerter.Subscribe(bus)
+	bus.Publish(events.Event{Kind: events.KindCommitted, FederationID: "traffic", Round: 4})
+	sub.Close()
+	if firing := alerter.Firing(); len(firing) != 1 || firing[0].FederationID != "energy" {
+		t.Fatalf("firing after traffic committed = %+v", firing)
+	}
+	resolved := alerts[3:]
+	if len(resolved) != 2 || resolved[0].Firing || resolved[0].Rule != AlertRoundAborted || resolved[1].Rule != AlertRollbackExecuted || resolved[1].Round != 4 {
+		t.Fatalf("resolved = %+v", resolved)
+	}
+}
+
+func TestQuorumAtRiskAlertResolvesOnAnUnflaggedCommit(t *testing.T) {
+	alerter := NewRoundAlerts()
+	var alerts []RoundAlert
+	alerter.AddAlertListener(func(alert RoundAlert) { alerts = append(alerts, alert) })
+
+	alerter.Observe(events.Event{Kind: events.KindCommitted, FederationID: "traffic", Round: 3, ProposalID: "p-3", QuorumAtRisk: true})
+	alerter.Observe(events.Event{Kind: events.KindCommitted, FederationID: "traffic", Round: 4, QuorumAtRisk: true})
+	if firing := alerter.Firing(); len(firing) != 1 || firing[0].Rule != AlertQuorumAtRisk || firing[0].Round != 3 {
+		t.Fatalf("firing while at risk = %+v", firing)
+	}
+	if alerts[0].Summary != "round 3 proposal p-3 reached quorum late for too many rounds running" {
+		t.Fatalf("alert = %+v", alerts[0])
+	}
+
+	alerter.Observe(events.Event{Kind: events.KindAborted, FederationID: "traffic", Round: 5, Reason: events.ReasonQuorumNotReached})
+	if firing := alerter.Firing(); len(firing) != 2 {
+		t.Fatalf("firing after an unflagged abort = %+v", firing)
+	}
+	alerter.Observe(events.Event{Kind: events.KindCommitted, FederationID: "traffic", Round: 6})
+	if firing := alerter.Firing(); len(firing) != 0 {
+		t.Fatalf("firing after an unflagged commit = %+v", firing)
+	}
+	if len(alerts) != 4 || alerts[3].Firing || alerts[3].Round != 6 {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+}
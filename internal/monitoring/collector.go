@@ -3,7 +3,10 @@
 package monitoring
 
 import (
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,6 +25,35 @@ const (
 	MetricNodeJoin   MetricType = "node_join"
 	MetricNodeLeave  MetricType = "node_leave"
 	MetricStaleness  MetricType = "async_staleness"
+	MetricHealth     MetricType = "health_status"
+	MetricKrumPhase  MetricType = "krum_phase_seconds"
+	MetricRoundPhase MetricType = "round_phase_seconds"
+	// MetricAggregationRound is a whole aggregation round's duration in
+	// seconds; MetricPeerCount records its participants alongside.
+	MetricAggregationRound MetricType = "aggregation_round_seconds"
+	// MetricBatchTrigger is the batch trigger an auto-tuner set for the
+	// next round, labelled with its reason and rationale.
+	MetricBatchTrigger MetricType = "batch_trigger"
+	// MetricWasmVerify is one Wasm proof verification's latency in
+	// seconds, labelled with the module digest, outcome, and whether it was
+	// the module's cold start.
+	MetricWasmVerify MetricType = "wasm_verify_seconds"
+	// MetricWasmVerifySLO is the share of recent verifications within the
+	// latency bound, recorded when the SLO alert fires or resolves.
+	MetricWasmVerifySLO MetricType = "wasm_verify_slo"
+	// MetricGossipFanout is the gossip fanout a node's controller set for
+	// the next round, labelled with its reason and rationale.
+	MetricGossipFanout MetricType = "gossip_fanout"
+	// MetricGossipPropagation is how long a round's votes took, in
+	// seconds, from the first one seen to a quorum of them.
+	MetricGossipPropagation MetricType = "gossip_propagation_seconds"
+	// MetricVoteTimeline is how long a proposal's votes took to reach one
+	// stage, in seconds from the proposal, labelled with the round and
+	// stage: first_vote, half_quorum, quorum, or the inter_arrival_p50 and
+	// inter_arrival_p90 gaps between votes.
+	MetricVoteTimeline MetricType = "vote_timeline_seconds"
+	// MetricLateVotes counts a proposal's votes that arrived after quorum.
+	MetricLateVotes MetricType = "late_votes"
 )
 
 // Metric represents a single metric observation
@@ -31,6 +63,9 @@ type Metric struct {
 	Timestamp time.Time
 	Labels    map[string]string
 	NodeID    string
+	// Seq numbers observations in recording order, from 1. It is the
+	// cursor QueryMetrics pages by.
+	Seq uint64
 }
 
 // Collector aggregates metrics from federated learning operations
@@ -39,11 +74,22 @@ type Collector struct {
 	metrics      []Metric
 	maxHistory   int
 	aggregations map[MetricType]*Aggregation
+	lastSeq      uint64
+	index        metricIndex
+	// sampling is read without the lock so a skipped observation costs no
+	// more than a few atomic operations; SetSampling swaps it.
+	sampling   atomic.Pointer[sampler]
+	reservoirs map[MetricType]*reservoir
 }
 
 // Aggregation stores statistical aggregates for a metric type
 type Aggregation struct {
-	Count   int
+	// Count and Sum scale each recorded observation by the sample rate it
+	// was drawn at, so under sampling they estimate every Record call.
+	Count int
+	// Sampled counts the observations actually recorded; it equals Count
+	// for a type recorded exactly.
+	Sampled int
 	Sum     float64
 	Min     float64
 	Max     float64
@@ -58,35 +104,58 @@ func NewCollector(maxHistory int) *Collector {
 		metrics:      make([]Metric, 0, maxHistory),
 		maxHistory:   maxHistory,
 		aggregations: make(map[MetricType]*Aggregation),
+		reservoirs:   make(map[MetricType]*reservoir),
+		index:        make(metricIndex),
 	}
 }
 
-// Record adds a new metric observation
+// Record adds a new metric observation and indexes it by type, node, and
+// labels. The labels are copied, so the caller may reuse the map. Under
+// SetSampling it may only be counted towards the type's aggregate.
 func (c *Collector) Record(metricType MetricType, value float64, labels map[string]string, nodeID string) {
+	s := c.sampling.Load()
+	var ts *typeSampler
+	weight := int64(1)
+	if s != nil {
+		if ts, weight = s.admit(metricType); weight == 0 {
+			return
+		}
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	now := time.Now()
+	if ts != nil {
+		s.adaptLocked(ts, now)
+	}
+	c.lastSeq++
 	metric := Metric{
 		Type:      metricType,
 		Value:     value,
-		Timestamp: time.Now(),
-		Labels:    labels,
+		Timestamp: now,
+		Labels:    copyLabels(labels),
 		NodeID:    nodeID,
+		Seq:       c.lastSeq,
 	}
 
 	c.metrics = append(c.metrics, metric)
+	c.index.add(&metric)
 
 	// Maintain max history size
 	if len(c.metrics) > c.maxHistory {
+		c.index.evict(&c.metrics[0])
 		c.metrics = c.metrics[1:]
 	}
 
 	// Update aggregations
-	c.updateAggregation(metricType, value)
+	c.updateAggregation(metricType, value, weight, now)
+	c.sampleValueLocked(s, metricType, value)
 }
 
-// updateAggregation recalculates statistics for a metric type
-func (c *Collector) updateAggregation(metricType MetricType, newValue float64) {
+// updateAggregation recalculates statistics for a metric type; weight is
+// the sample rate newValue was recorded at.
+func (c *Collector) updateAggregation(metricType MetricType, newValue float64, weight int64, now time.Time) {
 	agg, exists := c.aggregations[metricType]
 	if !exists {
 		agg = &Aggregation{
@@ -96,10 +165,11 @@ func (c *Collector) updateAggregation(metricType MetricType, newValue float64) {
 		c.aggregations[metricType] = agg
 	}
 
-	agg.Count++
-	agg.Sum += newValue
+	agg.Count += int(weight)
+	agg.Sampled++
+	agg.Sum += newValue * float64(weight)
 	agg.Mean = agg.Sum / float64(agg.Count)
-	agg.Updated = time.Now()
+	agg.Updated = now
 
 	if newValue < agg.Min {
 		agg.Min = newValue
@@ -125,12 +195,102 @@ func (c *Collector) GetMetricsByType(metricType MetricType) []Metric {
 	defer c.mu.RUnlock()
 
 	result := make([]Metric, 0)
-	for _, m := range c.metrics {
-		if m.Type == metricType {
-			result = append(result, m)
+	c.eachMatchLocked(MetricQuery{Types: []MetricType{metricType}}, func(m *Metric) {
+		result = append(result, *m)
+	})
+	return result
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	out := make(map[string]string, len(labels))
+	for name, value := range labels {
+		out[name] = value
+	}
+	return out
+}
+
+// MetricQuery selects observations for QueryMetrics. Zero fields match
+// every observation.
+type MetricQuery struct {
+	Types []MetricType
+	// NodeID, when set, matches only that node's observations.
+	NodeID string
+	// Labels matches observations carrying every label with the value
+	// given.
+	Labels map[string]string
+	// Since and Until bound the observation time inclusively.
+	Since time.Time
+	Until time.Time
+	// After is the cursor: only observations with a greater Seq match.
+	After uint64
+	// Limit caps the page; zero returns every match.
+	Limit int
+}
+
+func (q MetricQuery) matches(metric *Metric) bool {
+	if !q.Since.IsZero() && metric.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && metric.Timestamp.After(q.Until) {
+		return false
+	}
+	if q.NodeID != "" && metric.NodeID != q.NodeID {
+		return false
+	}
+	for name, value := range q.Labels {
+		if got, ok := metric.Labels[name]; !ok || got != value {
+			return false
 		}
 	}
-	return result
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, metricType := range q.Types {
+		if metric.Type == metricType {
+			return true
+		}
+	}
+	return false
+}
+
+// MetricPage is one page of a metric query.
+type MetricPage struct {
+	Metrics []Metric
+	// Total counts every retained observation matching the query's
+	// filters, on any page.
+	Total int
+	// NextCursor is the After of the following page, or zero on the last.
+	NextCursor uint64
+}
+
+// QueryMetrics returns up to q.Limit retained observations matching q, in
+// recording order. Paging by sequence number keeps cursors stable while
+// observations are recorded and evicted: a walk never repeats an
+// observation, and it sees every one that is still retained when its page
+// is read. Only the returned page is copied. A query filtering by type,
+// node, or label reads only the observations its most selective filter
+// indexes instead of scanning the history.
+func (c *Collector) QueryMetrics(q MetricQuery) MetricPage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	page := MetricPage{Metrics: []Metric{}}
+	c.eachMatchLocked(q, func(metric *Metric) {
+		// Observations before the cursor only count towards Total.
+		page.Total++
+		if metric.Seq <= q.After {
+			return
+		}
+		if q.Limit > 0 && len(page.Metrics) == q.Limit {
+			page.NextCursor = page.Metrics[len(page.Metrics)-1].Seq
+			return
+		}
+		page.Metrics = append(page.Metrics, *metric)
+	})
+	return page
 }
 
 // GetAggregation returns statistical summary for a metric type
@@ -159,11 +319,14 @@ func (c *Collector) GetAllAggregations() map[MetricType]*Aggregation {
 	return result
 }
 
-// GetSummary returns a human-readable summary of all metrics
+// GetSummary returns a human-readable summary of all metrics, with each
+// type's effective sample rate and, when reservoirs are kept, percentiles.
 func (c *Collector) GetSummary() map[string]interface{} {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	s := c.sampling.Load()
+
 	summary := map[string]interface{}{
 		"total_metrics": len(c.metrics),
 		"max_history":   c.maxHistory,
@@ -172,13 +335,23 @@ func (c *Collector) GetSummary() map[string]interface{} {
 
 	aggSummary := make(map[string]interface{})
 	for metricType, agg := range c.aggregations {
-		aggSummary[string(metricType)] = map[string]interface{}{
-			"count":   agg.Count,
-			"mean":    agg.Mean,
-			"min":     agg.Min,
-			"max":     agg.Max,
-			"updated": agg.Updated,
+		entry := map[string]interface{}{
+			"count":       agg.Count,
+			"sampled":     agg.Sampled,
+			"sample_rate": s.rate(metricType),
+			"mean":        agg.Mean,
+			"min":         agg.Min,
+			"max":         agg.Max,
+			"updated":     agg.Updated,
+		}
+		if r := c.reservoirs[metricType]; r != nil && len(r.values) > 0 {
+			values := append([]float64(nil), r.values...)
+			sort.Float64s(values)
+			entry["p50"] = NearestRank(values, 50)
+			entry["p90"] = NearestRank(values, 90)
+			entry["p99"] = NearestRank(values, 99)
 		}
+		aggSummary[string(metricType)] = entry
 	}
 	summary["aggregations"] = aggSummary
 
@@ -191,7 +364,9 @@ func (c *Collector) Clear() {
 	defer c.mu.Unlock()
 
 	c.metrics = make([]Metric, 0, c.maxHistory)
+	c.index = make(metricIndex)
 	c.aggregations = make(map[MetricType]*Aggregation)
+	c.reservoirs = make(map[MetricType]*reservoir)
 }
 
 // GetRecentMetrics returns metrics from the last N seconds
@@ -255,3 +430,70 @@ func (c *Collector) RecordNodeLeave(nodeID string) {
 func (c *Collector) RecordAsyncStaleness(nodeID string, stalenessSeconds float64, labels map[string]string) {
 	c.Record(MetricStaleness, stalenessSeconds, labels, nodeID)
 }
+
+// RecordKrumPhase captures how long one Multi-Krum phase took in seconds.
+func (c *Collector) RecordKrumPhase(phase string, seconds float64, labels map[string]string) {
+	merged := map[string]string{"phase": phase}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	c.Record(MetricKrumPhase, seconds, merged, "")
+}
+
+// RecordBatchTrigger captures a batch auto-tuner's decision: the number of
+// updates the next round waits for.
+func (c *Collector) RecordBatchTrigger(trigger float64, labels map[string]string) {
+	c.Record(MetricBatchTrigger, trigger, labels, "")
+}
+
+// RecordGossipFanout captures a fanout controller's decision: the number of
+// peers the node gossips each message to next round.
+func (c *Collector) RecordGossipFanout(fanout int, labels map[string]string) {
+	c.Record(MetricGossipFanout, float64(fanout), labels, "")
+}
+
+// RecordGossipPropagation captures how long a round's votes took to reach
+// quorum visibility, in seconds.
+func (c *Collector) RecordGossipPropagation(seconds float64, labels map[string]string) {
+	c.Record(MetricGossipPropagation, seconds, labels, "")
+}
+
+// RecordWasmVerification captures how long one Wasm proof verification
+// took in seconds.
+func (c *Collector) RecordWasmVerification(seconds float64, labels map[string]string) {
+	c.Record(MetricWasmVerify, seconds, labels, "")
+}
+
+// RecordRoundPhase captures how long one phase of a round took in seconds.
+func (c *Collector) RecordRoundPhase(phase string, seconds float64, labels map[string]string) {
+	merged := map[string]string{"phase": phase}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	c.Record(MetricRoundPhase, seconds, merged, "")
+}
+
+// RecordVoteTimeline captures a closed proposal's vote timeline: the
+// seconds to each stage it reached and the votes that arrived after
+// quorum, labelled with the round.
+func (c *Collector) RecordVoteTimeline(round int, stages map[string]float64, lateVotes int, labels map[string]string, nodeID string) {
+	base := map[string]string{"round": strconv.Itoa(round)}
+	for k, v := range labels {
+		base[k] = v
+	}
+	for stage, seconds := range stages {
+		merged := copyLabels(base)
+		merged["stage"] = stage
+		c.Record(MetricVoteTimeline, seconds, merged, nodeID)
+	}
+	c.Record(MetricLateVotes, float64(lateVotes), base, nodeID)
+}
+
+// RecordAggregationRound captures a finished aggregation round: its duration
+// in seconds and how many participants it aggregated, labelled with the
+// round number and outcome.
+func (c *Collector) RecordAggregationRound(round int, seconds float64, participants int, outcome, nodeID string) {
+	labels := map[string]string{"round": strconv.Itoa(round), "outcome": outcome}
+	c.Record(MetricAggregationRound, seconds, labels, nodeID)
+	c.Record(MetricPeerCount, float64(participants), labels, nodeID)
+}
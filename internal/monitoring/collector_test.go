@@ -0,0 +1,298 @@
+package monitoring
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryMetricsCursorStableUnderConcurrentRecords(t *testing.T) {
+	c := NewCollector(10000)
+	const existing = 500
+	for i := 0; i < existing; i++ {
+		c.Record(MetricLoss, float64(i), nil, "node-1")
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 2000; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			c.Record(MetricLoss, -1, nil, "node-2")
+		}
+	}()
+
+	var last uint64
+	seen := 0
+	for q := (MetricQuery{Limit: 37}); ; {
+		page := c.QueryMetrics(q)
+		for _, metric := range page.Metrics {
+			if metric.Seq <= last {
+				t.Fatalf("seq %d returned after %d", metric.Seq, last)
+			}
+			last = metric.Seq
+			if metric.NodeID == "node-1" {
+				if metric.Value != float64(seen) {
+					t.Fatalf("expected observation %d, got %v", seen, metric.Value)
+				}
+				seen++
+			}
+		}
+		if page.NextCursor == 0 {
+			break
+		}
+		q.After = page.NextCursor
+	}
+	close(stop)
+	wg.Wait()
+	if seen != existing {
+		t.Fatalf("walk saw %d of %d existing observations", seen, existing)
+	}
+}
+
+func TestQueryMetricsFiltersAndEviction(t *testing.T) {
+	c := NewCollector(6)
+	for i := 0; i < 8; i++ {
+		metricType := MetricLoss
+		if i%2 == 1 {
+			metricType = MetricAccuracy
+		}
+		c.Record(metricType, float64(i), nil, "")
+	}
+	// Seqs 1 and 2 were evicted; a cursor into them resumes at the oldest
+	// retained observation.
+	page := c.QueryMetrics(MetricQuery{Types: []MetricType{MetricAccuracy}, After: 1, Limit: 2})
+	if len(page.Metrics) != 2 || page.Metrics[0].Seq != 4 || page.Metrics[1].Seq != 6 || page.Total != 3 || page.NextCursor != 6 {
+		t.Fatalf("unexpected accuracy page: %+v", page)
+	}
+	page = c.QueryMetrics(MetricQuery{Types: []MetricType{MetricAccuracy}, After: page.NextCursor, Limit: 2})
+	if len(page.Metrics) != 1 || page.Metrics[0].Seq != 8 || page.NextCursor != 0 {
+		t.Fatalf("unexpected last accuracy page: %+v", page)
+	}
+
+	all := c.QueryMetrics(MetricQuery{})
+	if len(all.Metrics) != 6 || all.Total != 6 {
+		t.Fatalf("expected the 6 retained observations, got %+v", all)
+	}
+	c.mu.Lock()
+	for i := range c.metrics {
+		c.metrics[i].Timestamp = time.Unix(int64(c.metrics[i].Seq), 0)
+	}
+	c.mu.Unlock()
+	ranged := c.QueryMetrics(MetricQuery{Since: time.Unix(4, 0), Until: time.Unix(6, 0)})
+	if len(ranged.Metrics) != 3 || ranged.Metrics[0].Seq != 4 || ranged.Metrics[2].Seq != 6 {
+		t.Fatalf("unexpected time-ranged page: %+v", ranged)
+	}
+}
+
+// scanMetrics is the linear scan indexed queries replace.
+func scanMetrics(c *Collector, q MetricQuery) []Metric {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var out []Metric
+	for i := range c.metrics {
+		if q.matches(&c.metrics[i]) {
+			out = append(out, c.metrics[i])
+		}
+	}
+	return out
+}
+
+func recordLabelled(c *Collector, n int) {
+	types := []MetricType{MetricGradient, MetricLoss, MetricRoundTime}
+	for i := 0; i < n; i++ {
+		c.Record(types[i%len(types)], float64(i%97), map[string]string{
+			"round": strconv.Itoa(i % 25),
+			"phase": []string{"train", "aggregate"}[i%2],
+		}, fmt.Sprintf("node-%03d", i%50))
+	}
+}
+
+func TestQueryMetricsMatcherCombinations(t *testing.T) {
+	c := NewCollector(3000)
+	recordLabelled(c, 5000)
+
+	queries := []MetricQuery{
+		{Types: []MetricType{MetricGradient}},
+		{Types: []MetricType{MetricGradient, MetricRoundTime}},
+		{NodeID: "node-042"},
+		{Labels: map[string]string{"round": "12"}},
+		{Types: []MetricType{MetricGradient}, NodeID: "node-042"},
+		{Types: []MetricType{MetricLoss}, Labels: map[string]string{"round": "3", "phase": "aggregate"}},
+		{NodeID: "node-007", Labels: map[string]string{"phase": "train"}},
+		{Types: []MetricType{MetricRoundTime, MetricLoss}, NodeID: "node-010", Labels: map[string]string{"phase": "train"}},
+		{NodeID: "node-999"},
+		{Labels: map[string]string{"round": "12", "missing": ""}},
+		{Types: []MetricType{MetricAccuracy}},
+	}
+	for _, q := range queries {
+		want := scanMetrics(c, q)
+		page := c.QueryMetrics(q)
+		if page.Total != len(want) || len(page.Metrics) != len(want) {
+			t.Fatalf("%+v: got %d (total %d), scan found %d", q, len(page.Metrics), page.Total, len(want))
+		}
+		for i := range want {
+			if page.Metrics[i].Seq != want[i].Seq {
+				t.Fatalf("%+v: result %d is seq %d, want %d", q, i, page.Metrics[i].Seq, want[i].Seq)
+			}
+		}
+	}
+
+	// Paging an indexed query walks the same observations.
+	q := MetricQuery{Types: []MetricType{MetricGradient}, NodeID: "node-042", Limit: 7}
+	var walked []uint64
+	for {
+		page := c.QueryMetrics(q)
+		for _, metric := range page.Metrics {
+			walked = append(walked, metric.Seq)
+		}
+		if page.NextCursor == 0 {
+			break
+		}
+		q.After = page.NextCursor
+	}
+	if want := scanMetrics(c, MetricQuery{Types: q.Types, NodeID: q.NodeID}); len(walked) != len(want) {
+		t.Fatalf("paged walk saw %d observations, want %d", len(walked), len(want))
+	}
+
+	// The caller's label map is copied, so reusing it leaves the index intact.
+	labels := map[string]string{"phase": "eval"}
+	c.Record(MetricAccuracy, 1, labels, "node-042")
+	labels["phase"] = "train"
+	if page := c.QueryMetrics(MetricQuery{Labels: map[string]string{"phase": "eval"}}); page.Total != 1 {
+		t.Fatalf("phase=eval matched %d observations, want 1", page.Total)
+	}
+}
+
+func TestMetricIndexBoundedByRetention(t *testing.T) {
+	c := NewCollector(100)
+	for i := 0; i < 10000; i++ {
+		c.Record(MetricLoss, float64(i), map[string]string{"round": strconv.Itoa(i)}, fmt.Sprintf("node-%d", i))
+	}
+	c.mu.RLock()
+	keys, postings := len(c.index), 0
+	for _, seqs := range c.index {
+		postings += len(seqs)
+	}
+	c.mu.RUnlock()
+	// One type list, plus a node and a round list per retained observation.
+	if keys != 201 || postings != 300 {
+		t.Fatalf("index holds %d keys and %d postings for 100 observations", keys, postings)
+	}
+	if page := c.QueryMetrics(MetricQuery{NodeID: "node-42"}); page.Total != 0 {
+		t.Fatalf("evicted observation still matched: %+v", page)
+	}
+	if page := c.QueryMetrics(MetricQuery{Labels: map[string]string{"round": "9999"}}); page.Total != 1 {
+		t.Fatalf("newest observation matched %d times", page.Total)
+	}
+	c.Clear()
+	if len(c.index) != 0 {
+		t.Fatalf("clear left %d index keys", len(c.index))
+	}
+}
+
+func TestTopK(t *testing.T) {
+	c := NewCollector(100)
+	for _, sample := range []struct {
+		node  string
+		value float64
+		round string
+	}{
+		{"node-a", 3, "1"}, {"node-b", 9, "1"}, {"node-a", 7, "2"},
+		{"node-c", 5, "2"}, {"node-b", 1, "3"}, {"node-c", 7, "3"},
+		{"node-d", math.NaN(), "3"},
+	} {
+		c.Record(MetricRoundTime, sample.value, map[string]string{"round": sample.round}, sample.node)
+	}
+	c.Record(MetricLoss, 100, nil, "node-a")
+
+	values := func(metrics []Metric) string {
+		var out []string
+		for _, metric := range metrics {
+			out = append(out, fmt.Sprintf("%s=%g", metric.NodeID, metric.Value))
+		}
+		return strings.Join(out, " ")
+	}
+	roundTime := MetricQuery{Types: []MetricType{MetricRoundTime}}
+	if got := values(c.TopK(TopKQuery{MetricQuery: roundTime, K: 3})); got != "node-b=9 node-a=7 node-c=7" {
+		t.Fatalf("top 3 round times: %s", got)
+	}
+	if got := values(c.TopK(TopKQuery{MetricQuery: roundTime, K: 10, GroupByNode: true})); got != "node-b=9 node-a=7 node-c=7" {
+		t.Fatalf("highest round time by node: %s", got)
+	}
+	if got := c.TopK(TopKQuery{MetricQuery: roundTime, K: 2, GroupByLabel: "round"}); len(got) != 2 || got[0].Labels["round"] != "1" || got[1].Labels["round"] != "2" {
+		t.Fatalf("highest round time by round: %s", values(got))
+	}
+	filtered := MetricQuery{Types: []MetricType{MetricRoundTime}, Labels: map[string]string{"round": "3"}}
+	if got := values(c.TopK(TopKQuery{MetricQuery: filtered, K: 1})); got != "node-c=7" {
+		t.Fatalf("top round-3 time: %s", got)
+	}
+	if got := c.TopK(TopKQuery{MetricQuery: roundTime}); len(got) != 0 {
+		t.Fatalf("k=0 returned %d", len(got))
+	}
+}
+
+var benchCollector struct {
+	once sync.Once
+	c    *Collector
+}
+
+// millionMetrics is a collector holding 1M observations from 1000 nodes
+// over 100 rounds.
+func millionMetrics(b *testing.B) *Collector {
+	benchCollector.once.Do(func() {
+		c := NewCollector(1_000_000)
+		types := []MetricType{MetricGradient, MetricLoss, MetricAccuracy, MetricRoundTime}
+		for i := 0; i < 1_000_000; i++ {
+			c.Record(types[i%len(types)], float64(i%1000), map[string]string{
+				"round": strconv.Itoa(i / 10_000),
+			}, fmt.Sprintf("node-%03d", i%1000))
+		}
+		benchCollector.c = c
+	})
+	return benchCollector.c
+}
+
+// gradientNormQuery is "gradient norm for node-042 in rounds 10-20".
+func gradientNormQuery(round int) MetricQuery {
+	return MetricQuery{Types: []MetricType{MetricGradient}, NodeID: "node-042", Labels: map[string]string{"round": strconv.Itoa(round)}}
+}
+
+func BenchmarkQueryMetricsIndexed(b *testing.B) {
+	c := millionMetrics(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for round := 10; round <= 20; round++ {
+			c.QueryMetrics(gradientNormQuery(round))
+		}
+	}
+}
+
+func BenchmarkQueryMetricsLinearScan(b *testing.B) {
+	c := millionMetrics(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for round := 10; round <= 20; round++ {
+			scanMetrics(c, gradientNormQuery(round))
+		}
+	}
+}
+
+func BenchmarkTopKByNode(b *testing.B) {
+	c := millionMetrics(b)
+	q := TopKQuery{MetricQuery: MetricQuery{Types: []MetricType{MetricRoundTime}}, K: 10, GroupByNode: true}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.TopK(q)
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+//go:build !unix
+
+package monitoring
+
+import "errors"
+
+// DiskUsage is unavailable on this platform.
+func DiskUsage(path string) (float64, error) {
+	return 0, errors.New("disk usage is not supported on this platform")
+}
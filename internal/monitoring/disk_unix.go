@@ -0,0 +1,24 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+//go:build unix
+
+package monitoring
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// DiskUsage returns the fraction of the filesystem holding path that is in
+// use, counting the blocks reserved for root as used.
+func DiskUsage(path string) (float64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("stat filesystem of %s: %w", path, err)
+	}
+	if stat.Blocks == 0 {
+		return 0, nil
+	}
+	return 1 - float64(stat.Bavail)/float64(stat.Blocks), nil
+}
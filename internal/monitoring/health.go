@@ -0,0 +1,470 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package monitoring
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+)
+
+// HealthStatus is the coarse classification of a node or one of its subsystems.
+type HealthStatus string
+
+const (
+	HealthHealthy  HealthStatus = "healthy"
+	HealthDegraded HealthStatus = "degraded"
+	HealthCritical HealthStatus = "critical"
+	HealthUnknown  HealthStatus = "unknown"
+)
+
+// Severity orders statuses for comparison: healthy and unknown 0, degraded 1,
+// critical 2.
+func (s HealthStatus) Severity() int {
+	switch s {
+	case HealthDegraded:
+		return 1
+	case HealthCritical:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// HealthDimension names one subsystem signal feeding the aggregate score.
+type HealthDimension string
+
+const (
+	DimensionIsland      HealthDimension = "island"
+	DimensionConsensus   HealthDimension = "consensus_participation"
+	DimensionReputation  HealthDimension = "reputation"
+	DimensionWasm        HealthDimension = "wasm_verifier"
+	DimensionAttestation HealthDimension = "tpm_attestation"
+	DimensionPressure    HealthDimension = "resource_pressure"
+)
+
+// HealthThresholds tunes when each dimension is considered degraded or critical.
+type HealthThresholds struct {
+	// CacheDegraded and CacheCritical are island cache fill fractions.
+	CacheDegraded float64
+	CacheCritical float64
+	// ParticipationDegraded and ParticipationCritical are the fraction of the
+	// last ParticipationWindow rounds this node took part in.
+	ParticipationWindow   int
+	ParticipationDegraded float64
+	ParticipationCritical float64
+	ReputationDegraded    float64
+	ReputationCritical    float64
+	// AttestationMaxAge is how old the last successful attestation may be
+	// before the node is degraded; twice that is critical.
+	AttestationMaxAge time.Duration
+	// PressureDegraded and PressureCritical apply to both disk usage and
+	// queue fill fractions.
+	PressureDegraded float64
+	PressureCritical float64
+}
+
+// DefaultHealthThresholds returns the thresholds used by NewHealthEvaluator.
+func DefaultHealthThresholds() HealthThresholds {
+	return HealthThresholds{
+		CacheDegraded:         0.75,
+		CacheCritical:         0.95,
+		ParticipationWindow:   20,
+		ParticipationDegraded: 0.8,
+		ParticipationCritical: 0.5,
+		ReputationDegraded:    0.6,
+		ReputationCritical:    0.3,
+		AttestationMaxAge:     time.Hour,
+		PressureDegraded:      0.8,
+		PressureCritical:      0.95,
+	}
+}
+
+// healthWeights sets how much each dimension can pull down the overall score.
+var healthWeights = map[HealthDimension]float64{
+	DimensionIsland:      1,
+	DimensionConsensus:   2,
+	DimensionReputation:  1.5,
+	DimensionWasm:        1.5,
+	DimensionAttestation: 1,
+	DimensionPressure:    1,
+}
+
+// DimensionHealth is the evaluation of a single dimension.
+type DimensionHealth struct {
+	Dimension HealthDimension `json:"dimension"`
+	Status    HealthStatus    `json:"status"`
+	// Score is 1 for fully healthy and 0 for fully failed.
+	Score  float64 `json:"score"`
+	Weight float64 `json:"weight"`
+	// Contribution is how much this dimension lowers the overall score.
+	Contribution float64 `json:"contribution"`
+	Explanation  string  `json:"explanation"`
+}
+
+// HealthReport is the aggregate node health with per-dimension detail.
+type HealthReport struct {
+	Status      HealthStatus      `json:"status"`
+	Score       float64           `json:"score"`
+	Dimensions  []DimensionHealth `json:"dimensions"`
+	EvaluatedAt time.Time         `json:"evaluated_at"`
+}
+
+// Dimension returns the evaluation for d, if it was part of the report.
+func (r HealthReport) Dimension(d HealthDimension) (DimensionHealth, bool) {
+	for _, dim := range r.Dimensions {
+		if dim.Dimension == d {
+			return dim, true
+		}
+	}
+	return DimensionHealth{}, false
+}
+
+// HealthChange is delivered to listeners when the overall status changes.
+type HealthChange struct {
+	From   HealthStatus `json:"from"`
+	To     HealthStatus `json:"to"`
+	Report HealthReport `json:"report"`
+}
+
+// HealthChangeListener is called after the overall status transitions.
+type HealthChangeListener func(change HealthChange)
+
+// HealthEvaluator combines subsystem signals into a single node status.
+// Signals are pushed with the Observe methods; dimensions that have never
+// been observed are reported as unknown and do not affect the score.
+type HealthEvaluator struct {
+	mu         sync.Mutex
+	thresholds HealthThresholds
+	now        func() time.Time
+
+	island        *DimensionHealth
+	participation []bool
+	reputation    *DimensionHealth
+	wasm          *DimensionHealth
+	attestedAt    time.Time
+	attested      bool
+	tpmDown       string
+	pressure      *DimensionHealth
+
+	lastStatus HealthStatus
+	listeners  []HealthChangeListener
+}
+
+// NewHealthEvaluator creates an evaluator with DefaultHealthThresholds.
+func NewHealthEvaluator() *HealthEvaluator {
+	return NewHealthEvaluatorWithThresholds(DefaultHealthThresholds())
+}
+
+// NewHealthEvaluatorWithThresholds creates an evaluator with custom thresholds.
+func NewHealthEvaluatorWithThresholds(thresholds HealthThresholds) *HealthEvaluator {
+	if thresholds.ParticipationWindow <= 0 {
+		thresholds.ParticipationWindow = DefaultHealthThresholds().ParticipationWindow
+	}
+	return &HealthEvaluator{
+		thresholds: thresholds,
+		now:        time.Now,
+		lastStatus: HealthUnknown,
+	}
+}
+
+// AddStatusChangeListener registers a callback for overall status transitions.
+func (e *HealthEvaluator) AddStatusChangeListener(listener HealthChangeListener) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.listeners = append(e.listeners, listener)
+}
+
+// PublishTransitions publishes each overall status transition to pub as a
+// health_changed event from source.
+func (e *HealthEvaluator) PublishTransitions(pub events.Publisher, source string) {
+	e.AddStatusChangeListener(func(change HealthChange) {
+		pub.Publish(events.Event{
+			Kind:           events.KindHealthChanged,
+			Source:         source,
+			At:             change.Report.EvaluatedAt,
+			Health:         string(change.To),
+			PreviousHealth: string(change.From),
+		})
+	})
+}
+
+// FollowRounds observes each round of the node's own coordinator, the
+// commits and aborts bus carries without a federation ID, as participated
+// reports this node took part in it.
+func (e *HealthEvaluator) FollowRounds(bus *events.Bus, participated func(event events.Event) bool) *events.Subscription {
+	return bus.Handle("health", events.DefaultBuffer, func(event events.Event) {
+		if event.FederationID != "" {
+			return
+		}
+		e.ObserveRound(participated(event))
+	}, events.KindCommitted, events.KindAborted)
+}
+
+// ObserveIsland records the island mode and update cache fill.
+func (e *HealthEvaluator) ObserveIsland(island bool, cached, maxCached int) {
+	fill := 0.0
+	if maxCached > 0 {
+		fill = float64(cached) / float64(maxCached)
+	}
+	t := e.thresholds
+	dim := DimensionHealth{Dimension: DimensionIsland, Score: 1 - fill}
+	switch {
+	case fill >= t.CacheCritical:
+		dim.Status = HealthCritical
+		dim.Explanation = fmt.Sprintf("update cache %d/%d full; further updates will be dropped", cached, maxCached)
+	case island:
+		dim.Status = HealthDegraded
+		dim.Score = clampScore(0.5 * (1 - fill))
+		dim.Explanation = fmt.Sprintf("operating in island mode with %d/%d cached updates", cached, maxCached)
+	case fill >= t.CacheDegraded:
+		dim.Status = HealthDegraded
+		dim.Explanation = fmt.Sprintf("update cache %d/%d under pressure", cached, maxCached)
+	default:
+		dim.Status = HealthHealthy
+		dim.Explanation = "online"
+	}
+	e.set(&e.island, dim)
+}
+
+// ObserveRound records whether this node took part in a consensus round.
+func (e *HealthEvaluator) ObserveRound(participated bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.participation = append(e.participation, participated)
+	if over := len(e.participation) - e.thresholds.ParticipationWindow; over > 0 {
+		e.participation = append([]bool(nil), e.participation[over:]...)
+	}
+}
+
+// ObserveReputation records this node's reputation as reported by peers.
+func (e *HealthEvaluator) ObserveReputation(score float64) {
+	t := e.thresholds
+	dim := DimensionHealth{Dimension: DimensionReputation, Score: clampScore(score)}
+	switch {
+	case score < t.ReputationCritical:
+		dim.Status = HealthCritical
+		dim.Explanation = fmt.Sprintf("peer reputation %.2f below critical floor %.2f", score, t.ReputationCritical)
+	case score < t.ReputationDegraded:
+		dim.Status = HealthDegraded
+		dim.Explanation = fmt.Sprintf("peer reputation %.2f below %.2f", score, t.ReputationDegraded)
+	default:
+		dim.Status = HealthHealthy
+		dim.Explanation = fmt.Sprintf("peer reputation %.2f", score)
+	}
+	e.set(&e.reputation, dim)
+}
+
+// ObserveWasm records whether the wasm proof verifier is loaded and usable.
+func (e *HealthEvaluator) ObserveWasm(available bool, detail string) {
+	dim := DimensionHealth{Dimension: DimensionWasm, Status: HealthHealthy, Score: 1, Explanation: "verifier loaded"}
+	if !available {
+		dim.Status = HealthCritical
+		dim.Score = 0
+		dim.Explanation = "wasm verifier unavailable"
+		if detail != "" {
+			dim.Explanation += ": " + detail
+		}
+	}
+	e.set(&e.wasm, dim)
+}
+
+// ObserveAttestation records the time of the last successful TPM attestation.
+func (e *HealthEvaluator) ObserveAttestation(at time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.attestedAt = at
+	e.attested = true
+}
+
+// ObserveAttestationHardware records whether the TPM device answers. While
+// it does not, the attestation dimension is degraded however recent the
+// last attestation, since no fresh one can follow it.
+func (e *HealthEvaluator) ObserveAttestationHardware(available bool, detail string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tpmDown = ""
+	if !available {
+		e.tpmDown = "TPM hardware unavailable"
+		if detail != "" {
+			e.tpmDown += ": " + detail
+		}
+	}
+}
+
+// ObservePressure records disk usage as a fraction and the work queue fill.
+func (e *HealthEvaluator) ObservePressure(diskUsed float64, queueDepth, queueCapacity int) {
+	queueFill := 0.0
+	if queueCapacity > 0 {
+		queueFill = float64(queueDepth) / float64(queueCapacity)
+	}
+	worst, what := diskUsed, fmt.Sprintf("disk %.0f%% used", diskUsed*100)
+	if queueFill > worst {
+		worst, what = queueFill, fmt.Sprintf("queue %d/%d", queueDepth, queueCapacity)
+	}
+	t := e.thresholds
+	dim := DimensionHealth{Dimension: DimensionPressure, Score: clampScore(1 - worst)}
+	switch {
+	case worst >= t.PressureCritical:
+		dim.Status = HealthCritical
+		dim.Explanation = what + " exceeds critical threshold"
+	case worst >= t.PressureDegraded:
+		dim.Status = HealthDegraded
+		dim.Explanation = what + " exceeds degraded threshold"
+	default:
+		dim.Status = HealthHealthy
+		dim.Explanation = what
+	}
+	e.set(&e.pressure, dim)
+}
+
+func (e *HealthEvaluator) set(slot **DimensionHealth, dim DimensionHealth) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	*slot = &dim
+}
+
+// Evaluate scores every dimension, classifies the node, and notifies
+// listeners if the overall status differs from the previous evaluation.
+func (e *HealthEvaluator) Evaluate() HealthReport {
+	e.mu.Lock()
+	now := e.now()
+	dims := []DimensionHealth{
+		orUnknown(e.island, DimensionIsland),
+		e.participationLocked(),
+		orUnknown(e.reputation, DimensionReputation),
+		orUnknown(e.wasm, DimensionWasm),
+		e.attestationLocked(now),
+		orUnknown(e.pressure, DimensionPressure),
+	}
+	report := scoreHealth(dims, now)
+
+	var change *HealthChange
+	if report.Status != e.lastStatus {
+		change = &HealthChange{From: e.lastStatus, To: report.Status, Report: report}
+		e.lastStatus = report.Status
+	}
+	listeners := append([]HealthChangeListener(nil), e.listeners...)
+	e.mu.Unlock()
+
+	if change != nil {
+		for _, listener := range listeners {
+			listener(*change)
+		}
+	}
+	return report
+}
+
+func (e *HealthEvaluator) participationLocked() DimensionHealth {
+	dim := DimensionHealth{Dimension: DimensionConsensus}
+	if len(e.participation) == 0 {
+		dim.Status = HealthUnknown
+		dim.Explanation = "no rounds observed"
+		return dim
+	}
+	joined := 0
+	for _, p := range e.participation {
+		if p {
+			joined++
+		}
+	}
+	rate := float64(joined) / float64(len(e.participation))
+	dim.Score = rate
+	t := e.thresholds
+	switch {
+	case rate < t.ParticipationCritical:
+		dim.Status = HealthCritical
+	case rate < t.ParticipationDegraded:
+		dim.Status = HealthDegraded
+	default:
+		dim.Status = HealthHealthy
+	}
+	dim.Explanation = fmt.Sprintf("participated in %d of last %d rounds", joined, len(e.participation))
+	return dim
+}
+
+func (e *HealthEvaluator) attestationLocked(now time.Time) DimensionHealth {
+	dim := DimensionHealth{Dimension: DimensionAttestation}
+	if e.tpmDown != "" {
+		dim.Status = HealthDegraded
+		dim.Score = 0.5
+		dim.Explanation = e.tpmDown
+		return dim
+	}
+	if !e.attested {
+		dim.Status = HealthUnknown
+		dim.Explanation = "no attestation observed"
+		return dim
+	}
+	age := now.Sub(e.attestedAt)
+	maxAge := e.thresholds.AttestationMaxAge
+	dim.Score = clampScore(1 - float64(age)/float64(2*maxAge))
+	switch {
+	case age > 2*maxAge:
+		dim.Status = HealthCritical
+		dim.Explanation = fmt.Sprintf("last attestation %s ago, more than twice the %s limit", age.Round(time.Second), maxAge)
+	case age > maxAge:
+		dim.Status = HealthDegraded
+		dim.Explanation = fmt.Sprintf("last attestation %s ago exceeds %s", age.Round(time.Second), maxAge)
+	default:
+		dim.Status = HealthHealthy
+		dim.Explanation = fmt.Sprintf("last attestation %s ago", age.Round(time.Second))
+	}
+	return dim
+}
+
+// scoreHealth computes the weighted score and the overall status. The node
+// takes the worst status of any known dimension, so a single critical
+// subsystem cannot be averaged away by healthy ones.
+func scoreHealth(dims []DimensionHealth, now time.Time) HealthReport {
+	totalWeight := 0.0
+	for _, d := range dims {
+		if d.Status != HealthUnknown {
+			totalWeight += healthWeights[d.Dimension]
+		}
+	}
+
+	report := HealthReport{Status: HealthHealthy, Score: 1, EvaluatedAt: now}
+	known := 0
+	for i := range dims {
+		d := &dims[i]
+		if d.Status == HealthUnknown {
+			continue
+		}
+		known++
+		d.Weight = healthWeights[d.Dimension] / totalWeight
+		d.Contribution = d.Weight * (1 - d.Score)
+		report.Score -= d.Contribution
+		if d.Status.Severity() > report.Status.Severity() {
+			report.Status = d.Status
+		}
+	}
+	if known == 0 {
+		report.Status = HealthUnknown
+	}
+	report.Score = clampScore(report.Score)
+
+	sort.SliceStable(dims, func(i, j int) bool { return dims[i].Contribution > dims[j].Contribution })
+	report.Dimensions = dims
+	return report
+}
+
+func orUnknown(dim *DimensionHealth, name HealthDimension) DimensionHealth {
+	if dim == nil {
+		return DimensionHealth{Dimension: name, Status: HealthUnknown, Explanation: "not reported"}
+	}
+	return *dim
+}
+
+func clampScore(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
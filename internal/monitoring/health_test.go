@@ -0,0 +1,168 @@
+package monitoring
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+)
+
+func healthyEvaluator(now time.Time) *HealthEvaluator {
+	e := NewHealthEvaluator()
+	e.now = func() time.Time { return now }
+	e.ObserveIsland(false, 5, 100)
+	for i := 0; i < 10; i++ {
+		e.ObserveRound(true)
+	}
+	e.ObserveReputation(0.95)
+	e.ObserveWasm(true, "")
+	e.ObserveAttestation(now.Add(-10 * time.Minute))
+	e.ObservePressure(0.4, 10, 100)
+	return e
+}
+
+func TestHealthEvaluatorHealthyBaseline(t *testing.T) {
+	report := healthyEvaluator(time.Now()).Evaluate()
+	if report.Status != HealthHealthy {
+		t.Fatalf("expected healthy, got %s: %+v", report.Status, report.Dimensions)
+	}
+	if len(report.Dimensions) != 6 {
+		t.Fatalf("expected 6 dimensions, got %d", len(report.Dimensions))
+	}
+	total := 0.0
+	for _, d := range report.Dimensions {
+		total += d.Contribution
+	}
+	if diff := 1 - total - report.Score; diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("score %f does not equal 1 minus contributions %f", report.Score, total)
+	}
+}
+
+func TestHealthEvaluatorDegradedDimensions(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name      string
+		dimension HealthDimension
+		apply     func(e *HealthEvaluator)
+		want      HealthStatus
+		explains  string
+	}{
+		{"island mode", DimensionIsland, func(e *HealthEvaluator) { e.ObserveIsland(true, 10, 100) }, HealthDegraded, "island mode"},
+		{"cache full", DimensionIsland, func(e *HealthEvaluator) { e.ObserveIsland(true, 99, 100) }, HealthCritical, "99/100 full"},
+		{"missed rounds", DimensionConsensus, func(e *HealthEvaluator) {
+			for i := 0; i < 10; i++ {
+				e.ObserveRound(i%2 != 0)
+			}
+		}, HealthDegraded, "participated in 15 of last 20"},
+		{"absent from consensus", DimensionConsensus, func(e *HealthEvaluator) {
+			for i := 0; i < 20; i++ {
+				e.ObserveRound(false)
+			}
+		}, HealthCritical, "participated in 0 of last 20"},
+		{"low reputation", DimensionReputation, func(e *HealthEvaluator) { e.ObserveReputation(0.5) }, HealthDegraded, "0.50 below 0.60"},
+		{"reputation collapse", DimensionReputation, func(e *HealthEvaluator) { e.ObserveReputation(0.1) }, HealthCritical, "critical floor"},
+		{"wasm unavailable", DimensionWasm, func(e *HealthEvaluator) { e.ObserveWasm(false, "missing export alloc") }, HealthCritical, "missing export alloc"},
+		{"stale attestation", DimensionAttestation, func(e *HealthEvaluator) { e.ObserveAttestation(now.Add(-90 * time.Minute)) }, HealthDegraded, "exceeds 1h0m0s"},
+		{"expired attestation", DimensionAttestation, func(e *HealthEvaluator) { e.ObserveAttestation(now.Add(-3 * time.Hour)) }, HealthCritical, "twice"},
+		{"tpm device lost", DimensionAttestation, func(e *HealthEvaluator) { e.ObserveAttestationHardware(false, "open /dev/tpmrm0: no such device") }, HealthDegraded, "no such device"},
+		{"disk pressure", DimensionPressure, func(e *HealthEvaluator) { e.ObservePressure(0.85, 10, 100) }, HealthDegraded, "disk 85% used"},
+		{"queue saturated", DimensionPressure, func(e *HealthEvaluator) { e.ObservePressure(0.4, 98, 100) }, HealthCritical, "queue 98/100"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := healthyEvaluator(now)
+			tc.apply(e)
+			report := e.Evaluate()
+			if report.Status != tc.want {
+				t.Fatalf("expected overall %s, got %s", tc.want, report.Status)
+			}
+			dim, ok := report.Dimension(tc.dimension)
+			if !ok || dim.Status != tc.want {
+				t.Fatalf("expected %s to be %s, got %+v", tc.dimension, tc.want, dim)
+			}
+			if !strings.Contains(dim.Explanation, tc.explains) {
+				t.Fatalf("expected explanation to mention %q, got %q", tc.explains, dim.Explanation)
+			}
+			if report.Dimensions[0].Dimension != tc.dimension {
+				t.Fatalf("expected %s to be the largest contributor, got %s", tc.dimension, report.Dimensions[0].Dimension)
+			}
+			for _, other := range report.Dimensions {
+				if other.Dimension != tc.dimension && other.Status != HealthHealthy {
+					t.Fatalf("unexpected %s status for %s", other.Status, other.Dimension)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthEvaluatorUnknownDimensionsIgnored(t *testing.T) {
+	e := NewHealthEvaluator()
+	if report := e.Evaluate(); report.Status != HealthUnknown {
+		t.Fatalf("expected unknown with no signals, got %s", report.Status)
+	}
+	e.ObserveWasm(true, "")
+	report := e.Evaluate()
+	if report.Status != HealthHealthy || report.Score != 1 {
+		t.Fatalf("expected healthy score 1 from a single healthy signal, got %s %f", report.Status, report.Score)
+	}
+	if dim, _ := report.Dimension(DimensionAttestation); dim.Status != HealthUnknown || dim.Contribution != 0 {
+		t.Fatalf("expected unobserved attestation to be unknown with no contribution, got %+v", dim)
+	}
+}
+
+func TestHealthEvaluatorNotifiesOnStatusChange(t *testing.T) {
+	e := healthyEvaluator(time.Now())
+	var changes []HealthChange
+	e.AddStatusChangeListener(func(c HealthChange) { changes = append(changes, c) })
+
+	e.Evaluate()
+	e.Evaluate()
+	e.ObserveWasm(false, "")
+	e.Evaluate()
+	e.ObserveWasm(true, "")
+	e.Evaluate()
+
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 transitions, got %d: %+v", len(changes), changes)
+	}
+	want := [][2]HealthStatus{{HealthUnknown, HealthHealthy}, {HealthHealthy, HealthCritical}, {HealthCritical, HealthHealthy}}
+	for i, c := range changes {
+		if c.From != want[i][0] || c.To != want[i][1] {
+			t.Fatalf("transition %d: expected %s->%s, got %s->%s", i, want[i][0], want[i][1], c.From, c.To)
+		}
+	}
+	if changes[1].Report.Status != HealthCritical {
+		t.Fatalf("expected change to carry the triggering report")
+	}
+}
+
+func TestHealthEvaluatorFollowsRoundsAndPublishesTransitions(t *testing.T) {
+	bus := events.NewBus()
+	transitions := bus.Subscribe("test", events.DefaultBuffer, events.KindHealthChanged)
+	e := healthyEvaluator(time.Now())
+	e.PublishTransitions(bus, "health")
+	e.Evaluate()
+
+	rounds := e.FollowRounds(bus, func(event events.Event) bool { return event.ProposalID == "voted" })
+	for i := 0; i < 10; i++ {
+		bus.Publish(events.Event{Kind: events.KindCommitted, ProposalID: "missed"})
+	}
+	bus.Publish(events.Event{Kind: events.KindAborted, FederationID: "traffic", ProposalID: "voted"})
+	rounds.Close()
+
+	report := e.Evaluate()
+	if dim, _ := report.Dimension(DimensionConsensus); dim.Status != HealthDegraded {
+		t.Fatalf("consensus after missing 10 of 20 rounds = %+v", dim)
+	}
+	transitions.Close()
+	var got [][2]string
+	for event := range transitions.Events() {
+		got = append(got, [2]string{event.PreviousHealth, event.Health})
+	}
+	want := [][2]string{{"unknown", "healthy"}, {"healthy", "degraded"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("health_changed events = %v, want %v", got, want)
+	}
+}
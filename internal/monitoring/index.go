@@ -0,0 +1,228 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package monitoring
+
+import (
+	"container/heap"
+	"math"
+)
+
+// indexKey names one posting list: every retained observation of a type,
+// from a node, or carrying a label value.
+type indexKey struct {
+	kind  byte
+	name  string
+	value string
+}
+
+const (
+	indexType byte = iota
+	indexNode
+	indexLabel
+)
+
+// metricIndex maps each type, node ID, and label pair to the sequence
+// numbers of the retained observations that have it, ascending. Entries
+// are added on Record and removed when their observation is evicted, so
+// the index never outgrows the collector's history.
+type metricIndex map[indexKey][]uint64
+
+func indexKeys(metric *Metric, visit func(indexKey)) {
+	visit(indexKey{kind: indexType, name: string(metric.Type)})
+	if metric.NodeID != "" {
+		visit(indexKey{kind: indexNode, name: metric.NodeID})
+	}
+	for name, value := range metric.Labels {
+		visit(indexKey{kind: indexLabel, name: name, value: value})
+	}
+}
+
+func (idx metricIndex) add(metric *Metric) {
+	indexKeys(metric, func(key indexKey) {
+		idx[key] = append(idx[key], metric.Seq)
+	})
+}
+
+// evict removes metric, which must be the oldest retained observation, so
+// its sequence number heads each of its lists.
+func (idx metricIndex) evict(metric *Metric) {
+	indexKeys(metric, func(key indexKey) {
+		seqs := idx[key]
+		if len(seqs) > 0 && seqs[0] == metric.Seq {
+			seqs = seqs[1:]
+		}
+		if len(seqs) == 0 {
+			delete(idx, key)
+			return
+		}
+		idx[key] = seqs
+	})
+}
+
+// candidates returns the sequence numbers of the observations that can
+// match q, ascending, from its most selective indexed filter. ok is false
+// when q has no indexed filter and every observation must be scanned.
+func (idx metricIndex) candidates(q MetricQuery) (seqs []uint64, ok bool) {
+	consider := func(list []uint64) {
+		if !ok || len(list) < len(seqs) {
+			seqs, ok = list, true
+		}
+	}
+	if q.NodeID != "" {
+		consider(idx[indexKey{kind: indexNode, name: q.NodeID}])
+	}
+	for name, value := range q.Labels {
+		consider(idx[indexKey{kind: indexLabel, name: name, value: value}])
+	}
+	if len(q.Types) > 0 && (!ok || len(seqs) > 0) {
+		lists := make([][]uint64, 0, len(q.Types))
+		total := 0
+		for _, metricType := range q.Types {
+			list := idx[indexKey{kind: indexType, name: string(metricType)}]
+			lists = append(lists, list)
+			total += len(list)
+		}
+		if !ok || total < len(seqs) {
+			seqs, ok = mergeSeqs(lists, total), true
+		}
+	}
+	return seqs, ok
+}
+
+// mergeSeqs merges ascending lists of distinct sequence numbers.
+func mergeSeqs(lists [][]uint64, total int) []uint64 {
+	if len(lists) == 1 {
+		return lists[0]
+	}
+	merged := make([]uint64, 0, total)
+	heads := make([]int, len(lists))
+	for len(merged) < total {
+		next := -1
+		for i, list := range lists {
+			if heads[i] < len(list) && (next < 0 || list[heads[i]] < lists[next][heads[next]]) {
+				next = i
+			}
+		}
+		merged = append(merged, lists[next][heads[next]])
+		heads[next]++
+	}
+	return merged
+}
+
+// eachMatchLocked calls visit with every retained observation matching
+// q's filters, in recording order; q.After and q.Limit are ignored.
+// Callers must hold c.mu.
+func (c *Collector) eachMatchLocked(q MetricQuery, visit func(metric *Metric)) {
+	seqs, indexed := c.index.candidates(q)
+	if !indexed {
+		for i := range c.metrics {
+			if metric := &c.metrics[i]; q.matches(metric) {
+				visit(metric)
+			}
+		}
+		return
+	}
+	if len(c.metrics) == 0 {
+		return
+	}
+	// Retained observations have consecutive sequence numbers, so an
+	// observation's position follows from its own.
+	base := c.metrics[0].Seq
+	for _, seq := range seqs {
+		pos := seq - base
+		if seq < base || pos >= uint64(len(c.metrics)) {
+			continue
+		}
+		if metric := &c.metrics[pos]; q.matches(metric) {
+			visit(metric)
+		}
+	}
+}
+
+// TopKQuery ranks the observations matching a MetricQuery by value; its
+// After and Limit are ignored.
+type TopKQuery struct {
+	MetricQuery
+	// K is how many observations to return.
+	K int
+	// GroupByNode ranks only each node's highest observation, and
+	// GroupByLabel, when set instead, only the highest for each value of
+	// that label; observations without the label are skipped.
+	GroupByNode  bool
+	GroupByLabel string
+}
+
+// TopK returns the q.K highest-valued retained observations matching q,
+// highest first; ties go to the earlier observation. NaN values are never
+// ranked.
+func (c *Collector) TopK(q TopKQuery) []Metric {
+	if q.K <= 0 {
+		return []Metric{}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	top := make(metricHeap, 0, q.K)
+	offer := func(metric *Metric) {
+		if len(top) < q.K {
+			heap.Push(&top, metric)
+		} else if ranksAbove(metric, top[0]) {
+			top[0] = metric
+			heap.Fix(&top, 0)
+		}
+	}
+
+	grouped := q.GroupByNode || q.GroupByLabel != ""
+	best := make(map[string]*Metric)
+	c.eachMatchLocked(q.MetricQuery, func(metric *Metric) {
+		if math.IsNaN(metric.Value) {
+			return
+		}
+		if !grouped {
+			offer(metric)
+			return
+		}
+		group := metric.NodeID
+		if !q.GroupByNode {
+			var labelled bool
+			if group, labelled = metric.Labels[q.GroupByLabel]; !labelled {
+				return
+			}
+		}
+		if current, seen := best[group]; !seen || ranksAbove(metric, current) {
+			best[group] = metric
+		}
+	})
+	for _, metric := range best {
+		offer(metric)
+	}
+
+	result := make([]Metric, len(top))
+	for i := len(top) - 1; i >= 0; i-- {
+		result[i] = *heap.Pop(&top).(*Metric)
+	}
+	return result
+}
+
+// ranksAbove reports whether a ranks above b: a higher value, or an equal
+// one recorded earlier.
+func ranksAbove(a, b *Metric) bool {
+	if a.Value != b.Value {
+		return a.Value > b.Value
+	}
+	return a.Seq < b.Seq
+}
+
+// metricHeap is a min-heap with the lowest-ranked observation on top.
+type metricHeap []*Metric
+
+func (h metricHeap) Len() int           { return len(h) }
+func (h metricHeap) Less(i, j int) bool { return ranksAbove(h[j], h[i]) }
+func (h metricHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *metricHeap) Push(x any)        { *h = append(*h, x.(*Metric)) }
+func (h *metricHeap) Pop() any {
+	old := *h
+	metric := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return metric
+}
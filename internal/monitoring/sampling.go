@@ -0,0 +1,265 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package monitoring
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sampling defaults.
+const (
+	// DefaultMaxSampleRate caps the rate adaptive sampling raises a type to.
+	DefaultMaxSampleRate = 1024
+	// DefaultAdaptiveWindow is how often adaptive sampling re-measures a
+	// type's Record frequency.
+	DefaultAdaptiveWindow = time.Second
+)
+
+// DefaultExactTypes are low-volume types whose every observation matters,
+// such as consensus outcomes and churn events. They are recorded exactly
+// unless SamplingConfig.Exact says otherwise.
+var DefaultExactTypes = []MetricType{
+	MetricConsensus,
+	MetricNodeJoin,
+	MetricNodeLeave,
+	MetricTPMAttest,
+	MetricHealth,
+	MetricAggregationRound,
+	MetricBatchTrigger,
+	MetricWasmVerifySLO,
+}
+
+// SamplingConfig bounds what Record costs on hot paths. A sampled type
+// records one observation in every N; the others are only counted. Each
+// recorded observation stands for the N calls it was drawn from, so
+// Aggregation.Count, Sum and Mean estimate what recording every call would
+// give, while Aggregation.Sampled counts what was actually kept.
+type SamplingConfig struct {
+	// Rates records one in every N observations of a type. Types left out,
+	// or with N below 2, start unsampled.
+	Rates map[MetricType]int
+	// Exact types are never sampled, whatever Rates or adaptive sampling
+	// say. Nil keeps DefaultExactTypes.
+	Exact []MetricType
+	// ReservoirSize keeps a uniform sample of up to this many recorded
+	// values per type for Quantile. Zero keeps none.
+	ReservoirSize int
+	// AdaptiveThreshold, when positive, raises a type's rate while Record
+	// is called for it more than this many times a second, so about this
+	// many observations a second are kept; the rate falls back to the
+	// configured one as calls slow. Every type not listed in Exact is
+	// adapted. A type's frequency is measured once per AdaptiveWindow, on
+	// a recorded observation, so a rate raised to N falls only after the
+	// next N calls.
+	AdaptiveThreshold float64
+	// AdaptiveWindow defaults to DefaultAdaptiveWindow; MaxRate to
+	// DefaultMaxSampleRate.
+	AdaptiveWindow time.Duration
+	MaxRate        int
+}
+
+// sampler is a SamplingConfig in force. Its type states are created once
+// and only their counters change, so Record decides whether to skip an
+// observation without taking the collector's lock.
+type sampler struct {
+	config SamplingConfig
+	exact  map[MetricType]bool
+	window time.Duration
+	max    int64
+	types  sync.Map // MetricType -> *typeSampler
+}
+
+// typeSampler is one metric type's sampling state.
+type typeSampler struct {
+	base  int64
+	calls atomic.Uint64
+	rate  atomic.Int64
+	// windowStart and windowCalls open the current frequency window; they
+	// are guarded by the collector's lock.
+	windowStart time.Time
+	windowCalls uint64
+}
+
+func newSampler(config SamplingConfig) *sampler {
+	s := &sampler{config: config, exact: make(map[MetricType]bool), window: config.AdaptiveWindow, max: int64(config.MaxRate)}
+	if s.window <= 0 {
+		s.window = DefaultAdaptiveWindow
+	}
+	if s.max <= 0 {
+		s.max = DefaultMaxSampleRate
+	}
+	exact := config.Exact
+	if exact == nil {
+		exact = DefaultExactTypes
+	}
+	for _, metricType := range exact {
+		s.exact[metricType] = true
+	}
+	for metricType, rate := range config.Rates {
+		if s.exact[metricType] || (rate < 2 && config.AdaptiveThreshold <= 0) {
+			continue
+		}
+		s.types.Store(metricType, newTypeSampler(int64(rate)))
+	}
+	return s
+}
+
+func newTypeSampler(base int64) *typeSampler {
+	if base < 1 {
+		base = 1
+	}
+	ts := &typeSampler{base: base}
+	ts.rate.Store(base)
+	return ts
+}
+
+// admit counts one Record of metricType and returns the weight to record
+// it with, or zero to skip it. ts is nil for a type recorded exactly.
+func (s *sampler) admit(metricType MetricType) (ts *typeSampler, weight int64) {
+	if s.exact[metricType] {
+		return nil, 1
+	}
+	state, ok := s.types.Load(metricType)
+	if !ok {
+		if s.config.AdaptiveThreshold <= 0 {
+			return nil, 1
+		}
+		state, _ = s.types.LoadOrStore(metricType, newTypeSampler(1))
+	}
+	ts = state.(*typeSampler)
+	calls := ts.calls.Add(1)
+	rate := ts.rate.Load()
+	if rate > 1 && calls%uint64(rate) != 0 {
+		return ts, 0
+	}
+	return ts, rate
+}
+
+// adaptLocked re-measures ts's Record frequency once its window has passed
+// and sets the rate that keeps about AdaptiveThreshold observations a
+// second. The caller holds the collector's lock.
+func (s *sampler) adaptLocked(ts *typeSampler, now time.Time) {
+	if s.config.AdaptiveThreshold <= 0 {
+		return
+	}
+	calls := ts.calls.Load()
+	if ts.windowStart.IsZero() {
+		ts.windowStart, ts.windowCalls = now, calls
+		return
+	}
+	elapsed := now.Sub(ts.windowStart)
+	if elapsed < s.window {
+		return
+	}
+	frequency := float64(calls-ts.windowCalls) / elapsed.Seconds()
+	rate := int64(math.Ceil(frequency / s.config.AdaptiveThreshold))
+	if rate < ts.base {
+		rate = ts.base
+	}
+	if rate > s.max {
+		rate = s.max
+	}
+	ts.rate.Store(rate)
+	ts.windowStart, ts.windowCalls = now, calls
+}
+
+// rate returns metricType's effective sample rate: 1 for a type recorded
+// exactly.
+func (s *sampler) rate(metricType MetricType) int {
+	if s == nil {
+		return 1
+	}
+	if state, ok := s.types.Load(metricType); ok {
+		return int(state.(*typeSampler).rate.Load())
+	}
+	return 1
+}
+
+// reservoir is a uniform sample of the values recorded for one type,
+// maintained with Algorithm R.
+type reservoir struct {
+	values []float64
+	seen   int
+}
+
+func (r *reservoir) add(value float64, size int) {
+	r.seen++
+	if len(r.values) < size {
+		r.values = append(r.values, value)
+		return
+	}
+	if i := rand.IntN(r.seen); i < size {
+		r.values[i] = value
+	}
+}
+
+// SetSampling puts config in force for every later Record, starting each
+// type's counters and reservoir afresh. Nil records every observation.
+func (c *Collector) SetSampling(config *SamplingConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reservoirs = make(map[MetricType]*reservoir)
+	if config == nil {
+		c.sampling.Store(nil)
+		return
+	}
+	c.sampling.Store(newSampler(*config))
+}
+
+// SampleRate returns the rate metricType is currently recorded at: one in
+// every SampleRate observations. It is 1 for a type recorded exactly.
+func (c *Collector) SampleRate(metricType MetricType) int {
+	return c.sampling.Load().rate(metricType)
+}
+
+// Quantile returns the p-th percentile, by nearest rank, of the values in
+// metricType's reservoir, and false when the reservoir is empty or
+// disabled.
+func (c *Collector) Quantile(metricType MetricType, p float64) (float64, bool) {
+	c.mu.RLock()
+	r := c.reservoirs[metricType]
+	var values []float64
+	if r != nil {
+		values = append(values, r.values...)
+	}
+	c.mu.RUnlock()
+	if len(values) == 0 {
+		return 0, false
+	}
+	sort.Float64s(values)
+	return NearestRank(values, p), true
+}
+
+// NearestRank returns the p-th percentile of sorted, which must not be
+// empty: the smallest value with at least p percent of the values at or
+// below it.
+func NearestRank(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// sampleValueLocked adds a recorded value to its type's reservoir. The
+// caller holds the collector's lock.
+func (c *Collector) sampleValueLocked(s *sampler, metricType MetricType, value float64) {
+	if s == nil || s.config.ReservoirSize <= 0 {
+		return
+	}
+	r := c.reservoirs[metricType]
+	if r == nil {
+		r = &reservoir{}
+		c.reservoirs[metricType] = r
+	}
+	r.add(value, s.config.ReservoirSize)
+}
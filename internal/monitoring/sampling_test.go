@@ -0,0 +1,145 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package monitoring
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestSampledAggregatesTrackTheTrueMean(t *testing.T) {
+	const calls, rate = 100000, 10
+	c := NewCollector(1024)
+	c.SetSampling(&SamplingConfig{Rates: map[MetricType]int{MetricLoss: rate, MetricConsensus: rate}})
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	trueSum := 0.0
+	for i := 0; i < calls; i++ {
+		value := 5 + 2*rng.NormFloat64()
+		trueSum += value
+		c.Record(MetricLoss, value, nil, "node-1")
+	}
+	agg := c.GetAggregation(MetricLoss)
+	if agg.Count != calls || agg.Sampled != calls/rate {
+		t.Fatalf("count %d sampled %d, want %d and %d", agg.Count, agg.Sampled, calls, calls/rate)
+	}
+	// The sampled mean has a standard error of 2/sqrt(10000) = 0.02.
+	if trueMean := trueSum / calls; math.Abs(agg.Mean-trueMean) > 0.1 {
+		t.Fatalf("sampled mean %.4f, true mean %.4f", agg.Mean, trueMean)
+	}
+	if math.Abs(agg.Sum-trueSum)/trueSum > 0.02 {
+		t.Fatalf("scaled sum %.1f, true sum %.1f", agg.Sum, trueSum)
+	}
+	if got := len(c.GetMetricsByType(MetricLoss)); got != 1024 {
+		t.Fatalf("history holds %d observations", got)
+	}
+
+	// Consensus outcomes stay exact although Rates lists them.
+	for i := 0; i < 7; i++ {
+		c.Record(MetricConsensus, 1, nil, "node-1")
+	}
+	if agg := c.GetAggregation(MetricConsensus); agg.Count != 7 || agg.Sampled != 7 || agg.Sum != 7 {
+		t.Fatalf("consensus aggregate = %+v", agg)
+	}
+
+	summary := c.GetSummary()["aggregations"].(map[string]interface{})
+	if got := summary[string(MetricLoss)].(map[string]interface{})["sample_rate"]; got != rate {
+		t.Fatalf("loss sample_rate = %v", got)
+	}
+	if got := summary[string(MetricConsensus)].(map[string]interface{})["sample_rate"]; got != 1 {
+		t.Fatalf("consensus sample_rate = %v", got)
+	}
+}
+
+func TestReservoirTracksTheValueDistribution(t *testing.T) {
+	const calls, size = 100000, 1000
+	c := NewCollector(16)
+	c.SetSampling(&SamplingConfig{ReservoirSize: size})
+	for i := 0; i < calls; i++ {
+		c.Record(MetricNetworkLag, float64(i), nil, "")
+	}
+	// A 1000-value reservoir puts the median within about 1600 of the true
+	// one at one standard deviation; allow five.
+	for _, p := range []float64{10, 50, 90} {
+		got, ok := c.Quantile(MetricNetworkLag, p)
+		if want := p / 100 * calls; !ok || math.Abs(got-want) > 8000 {
+			t.Fatalf("p%.0f = %.0f (%v), want about %.0f", p, got, ok, want)
+		}
+	}
+	if _, ok := c.Quantile(MetricLoss, 50); ok {
+		t.Fatal("quantile of a type never recorded")
+	}
+	entry := c.GetSummary()["aggregations"].(map[string]interface{})[string(MetricNetworkLag)].(map[string]interface{})
+	if _, ok := entry["p99"]; !ok {
+		t.Fatalf("summary lacks reservoir percentiles: %v", entry)
+	}
+	c.Clear()
+	if _, ok := c.Quantile(MetricNetworkLag, 50); ok {
+		t.Fatal("Clear kept the reservoir")
+	}
+}
+
+func TestAdaptiveSamplingFollowsRecordFrequency(t *testing.T) {
+	c := NewCollector(64)
+	c.SetSampling(&SamplingConfig{AdaptiveThreshold: 1000, AdaptiveWindow: 10 * time.Millisecond, MaxRate: 64})
+
+	// A hot loop records far more than 64000 times a second.
+	for deadline := time.Now().Add(100 * time.Millisecond); time.Now().Before(deadline); {
+		c.Record(MetricGradient, 1, nil, "")
+		c.Record(MetricNodeJoin, 1, nil, "")
+	}
+	if rate := c.SampleRate(MetricGradient); rate != 64 {
+		t.Fatalf("hot sample rate = %d, want the 64 cap", rate)
+	}
+	gradient, joins := c.GetAggregation(MetricGradient), c.GetAggregation(MetricNodeJoin)
+	if gradient.Sampled >= gradient.Count/2 {
+		t.Fatalf("hot type kept %d of %d observations", gradient.Sampled, gradient.Count)
+	}
+	if c.SampleRate(MetricNodeJoin) != 1 || joins.Sampled != joins.Count {
+		t.Fatalf("churn events were sampled: %+v", joins)
+	}
+	// Each scaled observation stands for the calls it was drawn from, so
+	// the estimate tracks the true count.
+	if math.Abs(float64(gradient.Count-joins.Count)) > 0.05*float64(joins.Count) {
+		t.Fatalf("estimated %d gradient calls, made %d", gradient.Count, joins.Count)
+	}
+
+	// Once calls slow, the next measured windows lower the rate again.
+	for i := 0; i < 5 && c.SampleRate(MetricGradient) > 1; i++ {
+		time.Sleep(50 * time.Millisecond)
+		for j := c.SampleRate(MetricGradient); j > 0; j-- {
+			c.Record(MetricGradient, 1, nil, "")
+		}
+	}
+	if rate := c.SampleRate(MetricGradient); rate != 1 {
+		t.Fatalf("idle sample rate = %d, want 1", rate)
+	}
+}
+
+// BenchmarkRecord compares Record's cost with and without sampling. The
+// cores@1M/s metric is the share of one core Record would take at a
+// million calls a second.
+func BenchmarkRecord(b *testing.B) {
+	for _, bench := range []struct {
+		name   string
+		config *SamplingConfig
+	}{
+		{"unsampled", nil},
+		{"sampled-1-in-100", &SamplingConfig{Rates: map[MetricType]int{MetricGradient: 100}}},
+		{"adaptive", &SamplingConfig{AdaptiveThreshold: 10000, AdaptiveWindow: 10 * time.Millisecond}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			c := NewCollector(1024)
+			c.SetSampling(bench.config)
+			labels := map[string]string{"layer": "0"}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.Record(MetricGradient, float64(i), labels, "node-1")
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/1e3, "cores@1M/s")
+		})
+	}
+}
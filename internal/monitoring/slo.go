@@ -0,0 +1,244 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package monitoring
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Verification SLO defaults.
+const (
+	// DefaultVerificationBound is the per-proof verification time Theorem 5
+	// promises (/proofs/cryptography.md).
+	DefaultVerificationBound = 10 * time.Millisecond
+	// DefaultVerificationTarget is the share of verifications expected
+	// within the bound.
+	DefaultVerificationTarget = 0.99
+	// DefaultVerificationWindow is how many recent warm verifications the
+	// SLO is judged over.
+	DefaultVerificationWindow = 1000
+	// DefaultVerificationMinSamples is how many warm verifications the
+	// window must hold before the SLO can be breached.
+	DefaultVerificationMinSamples = 50
+)
+
+// Verification outcomes.
+const (
+	VerifyAccepted = "accepted"
+	VerifyRejected = "rejected"
+	VerifyError    = "error"
+)
+
+// AlertWasmVerificationSLO names the alert a VerificationSLO fires; the
+// Prometheus rule over the exported compliance gauge has the same name.
+const AlertWasmVerificationSLO = "WasmVerificationSLOBreached"
+
+// VerificationSample is one timed Wasm proof verification.
+type VerificationSample struct {
+	// Module is the hex SHA-256 of the verifier module.
+	Module  string
+	Outcome string
+	Latency time.Duration
+	// Cold marks the first verification after the module was
+	// instantiated, which pays for warming it up.
+	Cold bool
+	At   time.Time
+}
+
+// VerificationSLOConfig configures a VerificationSLO. Zero fields take the
+// defaults noted on them.
+type VerificationSLOConfig struct {
+	// Bound is the latency a verification must finish within. Default
+	// DefaultVerificationBound.
+	Bound time.Duration
+	// Target is the share of verifications, in (0, 1], that must finish
+	// within Bound. Default DefaultVerificationTarget.
+	Target float64
+	// Window is how many recent warm verifications compliance is measured
+	// over. Default DefaultVerificationWindow.
+	Window int
+	// MinSamples is how many the window must hold before the alert can
+	// fire. Default DefaultVerificationMinSamples, capped at Window.
+	MinSamples int
+	// Collector, when set, receives every sample as a wasm_verify_seconds
+	// observation and every alert transition as a wasm_verify_slo one.
+	Collector *Collector
+}
+
+// VerificationSLOStatus is a snapshot of a VerificationSLO.
+type VerificationSLOStatus struct {
+	Bound  time.Duration `json:"bound"`
+	Target float64       `json:"target"`
+	// Compliance is the share of the Samples in the window that finished
+	// within Bound; 1 while the window is empty.
+	Compliance  float64 `json:"compliance"`
+	Samples     int     `json:"samples"`
+	WithinBound int     `json:"within_bound"`
+	// ColdStarts counts cold verifications seen; they are kept out of the
+	// window.
+	ColdStarts int       `json:"cold_starts"`
+	Breached   bool      `json:"breached"`
+	Since      time.Time `json:"since,omitempty"`
+}
+
+// SLOAlert is a VerificationSLO alert firing or resolving.
+type SLOAlert struct {
+	Rule       string    `json:"rule"`
+	Firing     bool      `json:"firing"`
+	Compliance float64   `json:"compliance"`
+	Target     float64   `json:"target"`
+	Samples    int       `json:"samples"`
+	Summary    string    `json:"summary"`
+	At         time.Time `json:"at"`
+}
+
+// SLOAlertListener receives alert transitions.
+type SLOAlertListener func(alert SLOAlert)
+
+// VerificationSLO tracks the share of Wasm proof verifications finishing
+// within the Theorem 5 bound over a rolling window of warm verifications,
+// and fires AlertWasmVerificationSLO while that share is below target.
+// Cold starts are counted separately so a module's warm-up does not count
+// against steady-state latency.
+type VerificationSLO struct {
+	cfg VerificationSLOConfig
+
+	mu         sync.Mutex
+	within     []bool
+	next       int
+	inBound    int
+	coldStarts int
+	breached   bool
+	since      time.Time
+	listeners  []SLOAlertListener
+}
+
+// NewVerificationSLO creates a tracker configured by cfg.
+func NewVerificationSLO(cfg VerificationSLOConfig) *VerificationSLO {
+	if cfg.Bound <= 0 {
+		cfg.Bound = DefaultVerificationBound
+	}
+	if cfg.Target <= 0 || cfg.Target > 1 {
+		cfg.Target = DefaultVerificationTarget
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultVerificationWindow
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = DefaultVerificationMinSamples
+	}
+	cfg.MinSamples = min(cfg.MinSamples, cfg.Window)
+	return &VerificationSLO{cfg: cfg, within: make([]bool, 0, cfg.Window)}
+}
+
+// AddAlertListener registers a listener for alert transitions. Listeners
+// run on the goroutine that observed the transition.
+func (s *VerificationSLO) AddAlertListener(listener SLOAlertListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+// Observe records sample and returns the SLO's status after it.
+func (s *VerificationSLO) Observe(sample VerificationSample) VerificationSLOStatus {
+	if sample.At.IsZero() {
+		sample.At = time.Now().UTC()
+	}
+	if s.cfg.Collector != nil {
+		start := "warm"
+		if sample.Cold {
+			start = "cold"
+		}
+		s.cfg.Collector.RecordWasmVerification(sample.Latency.Seconds(), map[string]string{
+			"module":  sample.Module,
+			"outcome": sample.Outcome,
+			"start":   start,
+		})
+	}
+
+	s.mu.Lock()
+	if sample.Cold {
+		s.coldStarts++
+		status := s.statusLocked()
+		s.mu.Unlock()
+		return status
+	}
+	ok := sample.Latency <= s.cfg.Bound
+	if len(s.within) < s.cfg.Window {
+		s.within = append(s.within, ok)
+	} else {
+		if s.within[s.next] {
+			s.inBound--
+		}
+		s.within[s.next] = ok
+	}
+	if ok {
+		s.inBound++
+	}
+	s.next = (s.next + 1) % s.cfg.Window
+
+	status := s.statusLocked()
+	breached := status.Samples >= s.cfg.MinSamples && status.Compliance < s.cfg.Target
+	if breached == s.breached {
+		s.mu.Unlock()
+		return status
+	}
+	s.breached, s.since = breached, sample.At
+	status.Breached, status.Since = breached, sample.At
+	listeners := append([]SLOAlertListener(nil), s.listeners...)
+	s.mu.Unlock()
+
+	alert := SLOAlert{
+		Rule:       AlertWasmVerificationSLO,
+		Firing:     breached,
+		Compliance: status.Compliance,
+		Target:     s.cfg.Target,
+		Samples:    status.Samples,
+		At:         sample.At,
+	}
+	if breached {
+		alert.Summary = fmt.Sprintf("%.1f%% of the last %d verifications finished within %s, below the %.1f%% target", 100*status.Compliance, status.Samples, s.cfg.Bound, 100*s.cfg.Target)
+	} else {
+		alert.Summary = fmt.Sprintf("%.1f%% of the last %d verifications finished within %s, meeting the %.1f%% target", 100*status.Compliance, status.Samples, s.cfg.Bound, 100*s.cfg.Target)
+	}
+	if s.cfg.Collector != nil {
+		s.cfg.Collector.Record(MetricWasmVerifySLO, status.Compliance, map[string]string{
+			"rule":   alert.Rule,
+			"firing": strconv.FormatBool(alert.Firing),
+			"target": strconv.FormatFloat(alert.Target, 'g', -1, 64),
+		}, "")
+	}
+	for _, listener := range listeners {
+		listener(alert)
+	}
+	return status
+}
+
+// Status returns a snapshot of the SLO.
+func (s *VerificationSLO) Status() VerificationSLOStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statusLocked()
+}
+
+// statusLocked builds the status. The caller holds s.mu.
+func (s *VerificationSLO) statusLocked() VerificationSLOStatus {
+	status := VerificationSLOStatus{
+		Bound:       s.cfg.Bound,
+		Target:      s.cfg.Target,
+		Compliance:  1,
+		Samples:     len(s.within),
+		WithinBound: s.inBound,
+		ColdStarts:  s.coldStarts,
+		Breached:    s.breached,
+		Since:       s.since,
+	}
+	if status.Samples > 0 {
+		status.Compliance = float64(s.inBound) / float64(status.Samples)
+	}
+	return status
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package monitoring
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerificationSLOKeepsColdStartsOutOfTheWindow(t *testing.T) {
+	collector := NewCollector(256)
+	slo := NewVerificationSLO(VerificationSLOConfig{Target: 0.9, Window: 10, MinSamples: 6, Collector: collector})
+	var alerts []SLOAlert
+	slo.AddAlertListener(func(alert SLOAlert) { alerts = append(alerts, alert) })
+
+	// A slow cold start does not count against the SLO.
+	status := slo.Observe(VerificationSample{Module: "m1", Outcome: VerifyAccepted, Latency: 80 * time.Millisecond, Cold: true})
+	if status.Samples != 0 || status.ColdStarts != 1 || status.Compliance != 1 {
+		t.Fatalf("after cold start: %+v", status)
+	}
+	for i := 0; i < 4; i++ {
+		slo.Observe(VerificationSample{Module: "m1", Outcome: VerifyAccepted, Latency: 2 * time.Millisecond})
+	}
+	// Too few samples to judge, even with a miss.
+	if status = slo.Observe(VerificationSample{Module: "m1", Outcome: VerifyRejected, Latency: 12 * time.Millisecond}); status.Breached || len(alerts) != 0 {
+		t.Fatalf("breached on %d samples: %+v", status.Samples, status)
+	}
+	if status = slo.Observe(VerificationSample{Module: "m1", Outcome: VerifyAccepted, Latency: 20 * time.Millisecond}); !status.Breached || status.WithinBound != 4 || status.Samples != 6 {
+		t.Fatalf("two misses in six: %+v", status)
+	}
+	if len(alerts) != 1 || !alerts[0].Firing || alerts[0].Rule != AlertWasmVerificationSLO || alerts[0].Samples != 6 {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+
+	// The misses roll out of the window and the alert resolves once.
+	for i := 0; i < 10; i++ {
+		slo.Observe(VerificationSample{Module: "m1", Outcome: VerifyAccepted, Latency: time.Millisecond})
+	}
+	if status = slo.Status(); status.Breached || status.Compliance != 1 || status.Samples != 10 {
+		t.Fatalf("after recovery: %+v", status)
+	}
+	if len(alerts) != 2 || alerts[1].Firing {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+
+	cold := 0
+	for _, metric := range collector.GetMetricsByType(MetricWasmVerify) {
+		if metric.Labels["module"] != "m1" || metric.Labels["outcome"] == "" {
+			t.Fatalf("sample labels %v", metric.Labels)
+		}
+		if metric.Labels["start"] == "cold" {
+			cold++
+		}
+	}
+	if cold != 1 {
+		t.Fatalf("%d cold samples recorded, want 1", cold)
+	}
+	if transitions := collector.GetMetricsByType(MetricWasmVerifySLO); len(transitions) != 2 || transitions[0].Labels["firing"] != "true" {
+		t.Fatalf("recorded transitions %+v", transitions)
+	}
+}
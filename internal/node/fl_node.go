@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/blockchain"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
 )
 
 // TransactionStatus tracks a transaction through its lifecycle.
@@ -402,6 +403,5 @@ func (n *FLNode) GetState() *NodeState {
 }
 
 func computeWeightsHash(weights []byte) string {
-	h := sha256.Sum256(weights)
-	return fmt.Sprintf("%x", h)
+	return protocol.WeightsDigest(weights)
 }
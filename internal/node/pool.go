@@ -125,7 +125,11 @@ func (p *NodePool) ExecuteRound(ctx context.Context) (*RoundResult, error) {
 
 	// 2. Wait for block inclusion (simulate)
 	// In production, would wait for actual consensus + block creation
-	time.Sleep(1 * time.Second)
+	select {
+	case <-ctx.Done():
+		return roundData, ctx.Err()
+	case <-time.After(1 * time.Second):
+	}
 
 	// 3. Mark transactions as confirmed
 	for nodeID := range roundData.TransactionIDs {
@@ -0,0 +1,295 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AddressBookVersion is the bundle format version written by
+// NewAddressBook.
+const AddressBookVersion = 1
+
+// addressBookSource marks peers whose record came only from an imported
+// bundle, as opposed to peers learned through live connections.
+const addressBookSource = "address_book"
+
+// AddressBookEntry is one peer in a signed address book bundle.
+type AddressBookEntry struct {
+	PeerID          string   `json:"peer_id"`
+	Addresses       []string `json:"addresses"`
+	PublicKey       []byte   `json:"public_key,omitempty"`
+	ReputationPrior float64  `json:"reputation_prior"`
+}
+
+// AddressBook is a signed peer list for bootstrapping nodes that cannot reach
+// a seed, typically carried onto an air-gapped network by an operator.
+type AddressBook struct {
+	Version   int                `json:"version"`
+	IssuerID  string             `json:"issuer_id"`
+	IssuedAt  time.Time          `json:"issued_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	Entries   []AddressBookEntry `json:"entries"`
+	SignerKey []byte             `json:"signer_key,omitempty"`
+	Signature []byte             `json:"signature,omitempty"`
+}
+
+// AddressBookImport summarizes how a bundle was merged.
+type AddressBookImport struct {
+	Added     int      `json:"added"`
+	Updated   int      `json:"updated"`
+	Kept      int      `json:"kept"`
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// NewAddressBook builds an unsigned bundle valid for ttl from now. Entries
+// are sorted by peer ID.
+func NewAddressBook(issuerID string, entries []AddressBookEntry, ttl time.Duration) (*AddressBook, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("address book ttl must be positive")
+	}
+	for _, entry := range entries {
+		if entry.PeerID == "" || len(entry.Addresses) == 0 {
+			return nil, fmt.Errorf("%w: address book entry needs a peer ID and an address", ErrInvalidPeer)
+		}
+		if entry.ReputationPrior < 0 || entry.ReputationPrior > 1 {
+			return nil, fmt.Errorf("%w: reputation prior for %s must be in [0,1]", ErrInvalidPeer, entry.PeerID)
+		}
+	}
+	sorted := append([]AddressBookEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PeerID < sorted[j].PeerID })
+
+	now := time.Now().UTC()
+	return &AddressBook{
+		Version:   AddressBookVersion,
+		IssuerID:  issuerID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+		Entries:   sorted,
+	}, nil
+}
+
+// Sign signs the bundle with key and records the matching public key.
+func (b *AddressBook) Sign(key ed25519.PrivateKey) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid address book signing key: need %d bytes, got %d", ed25519.PrivateKeySize, len(key))
+	}
+	b.SignerKey = append([]byte(nil), key.Public().(ed25519.PublicKey)...)
+	payload, err := b.signingPayload()
+	if err != nil {
+		return err
+	}
+	b.Signature = ed25519.Sign(key, payload)
+	return nil
+}
+
+// Verify checks that the bundle was signed by trusted and has not expired
+// at now.
+func (b *AddressBook) Verify(trusted ed25519.PublicKey, now time.Time) error {
+	if len(trusted) != ed25519.PublicKeySize || !bytes.Equal(trusted, b.SignerKey) {
+		return fmt.Errorf("%w: signed by untrusted key", ErrAddressBookSignature)
+	}
+	payload, err := b.signingPayload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(trusted, payload, b.Signature) {
+		return fmt.Errorf("%w: signature does not match contents", ErrAddressBookSignature)
+	}
+	if !now.Before(b.ExpiresAt) {
+		return fmt.Errorf("%w: expired at %s", ErrAddressBookExpired, b.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// signingPayload is the canonical JSON of the bundle without its signature.
+func (b *AddressBook) signingPayload() ([]byte, error) {
+	unsigned := *b
+	unsigned.Signature = nil
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encode address book: %w", err)
+	}
+	return payload, nil
+}
+
+// ExportAddressBook signs a bundle of every known peer with an address,
+// using each peer's current reputation as its prior.
+func (n *Network) ExportAddressBook(key ed25519.PrivateKey, ttl time.Duration) (*AddressBook, error) {
+	n.mu.RLock()
+	entries := make([]AddressBookEntry, 0, len(n.peers))
+	for _, peer := range n.peers {
+		addresses := peerAddressStrings(peer)
+		if len(addresses) == 0 {
+			continue
+		}
+		entries = append(entries, AddressBookEntry{
+			PeerID:          peer.ID,
+			Addresses:       addresses,
+			PublicKey:       append([]byte(nil), peer.PublicKey...),
+			ReputationPrior: clampReputation(peer.Reputation),
+		})
+	}
+	n.mu.RUnlock()
+
+	book, err := NewAddressBook(n.nodeID, entries, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if err := book.Sign(key); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// ImportAddressBook verifies book against trusted and merges its entries.
+// Peers learned locally keep their address and reputation and only gain a
+// missing public key; peers known only from an earlier bundle are refreshed.
+// An entry whose public key contradicts the one on record is skipped and
+// reported as a conflict.
+func (n *Network) ImportAddressBook(book *AddressBook, trusted ed25519.PublicKey) (AddressBookImport, error) {
+	var result AddressBookImport
+	if err := book.Verify(trusted, time.Now()); err != nil {
+		return result, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, entry := range book.Entries {
+		if entry.PeerID == n.nodeID {
+			continue
+		}
+		peer, exists := n.peers[entry.PeerID]
+		if !exists {
+			n.peers[entry.PeerID] = &Peer{
+				ID:         entry.PeerID,
+				Address:    entry.Addresses[0],
+				Addresses:  bookAddresses(entry.Addresses, nil),
+				Reputation: clampReputation(entry.ReputationPrior),
+				PublicKey:  append([]byte(nil), entry.PublicKey...),
+				Metadata: map[string]interface{}{
+					"source":          addressBookSource,
+					"address_book_by": book.IssuerID,
+				},
+			}
+			result.Added++
+			continue
+		}
+
+		if len(peer.PublicKey) > 0 && len(entry.PublicKey) > 0 && !bytes.Equal(peer.PublicKey, entry.PublicKey) {
+			result.Conflicts = append(result.Conflicts, entry.PeerID)
+			continue
+		}
+		if len(peer.PublicKey) == 0 {
+			peer.PublicKey = append([]byte(nil), entry.PublicKey...)
+		}
+		if source, _ := peer.Metadata["source"].(string); source != addressBookSource {
+			result.Kept++
+			continue
+		}
+		peer.Address = entry.Addresses[0]
+		peer.Addresses = bookAddresses(entry.Addresses, peer.Addresses)
+		peer.Reputation = clampReputation(entry.ReputationPrior)
+		peer.Metadata["address_book_by"] = book.IssuerID
+		result.Updated++
+	}
+	return result, nil
+}
+
+// PeerTable returns a copy of every known peer sorted by ID, including
+// reputation and public key, for backups.
+func (n *Network) PeerTable() []Peer {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	peers := make([]Peer, 0, len(n.peers))
+	for _, peer := range n.peers {
+		copied := *peer
+		copied.Addresses = append([]PeerAddress(nil), peer.Addresses...)
+		copied.PublicKey = append([]byte(nil), peer.PublicKey...)
+		copied.Metadata = make(map[string]interface{}, len(peer.Metadata))
+		for k, v := range peer.Metadata {
+			copied.Metadata[k] = v
+		}
+		peers = append(peers, copied)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].ID < peers[j].ID })
+	return peers
+}
+
+// RestorePeerTable adds backed-up peers that are not already known and
+// returns how many were added. Restored peers start disconnected; peers
+// learned since the backup keep their live record.
+func (n *Network) RestorePeerTable(peers []Peer) (int, error) {
+	for _, peer := range peers {
+		if peer.ID == "" {
+			return 0, fmt.Errorf("%w: peer table entry without an ID", ErrInvalidPeer)
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	added := 0
+	for _, peer := range peers {
+		if _, exists := n.peers[peer.ID]; exists || peer.ID == n.nodeID {
+			continue
+		}
+		restored := peer
+		restored.Connected = false
+		restored.Reputation = clampReputation(peer.Reputation)
+		restored.Addresses = append([]PeerAddress(nil), peer.Addresses...)
+		restored.PublicKey = append([]byte(nil), peer.PublicKey...)
+		if restored.Metadata == nil {
+			restored.Metadata = make(map[string]interface{})
+		}
+		n.peers[peer.ID] = &restored
+		added++
+	}
+	return added, nil
+}
+
+// peerAddressStrings lists every address of peer, primary first.
+func peerAddressStrings(peer *Peer) []string {
+	addresses := make([]string, 0, len(peer.Addresses)+1)
+	if peer.Address != "" {
+		addresses = append(addresses, peer.Address)
+	}
+	for _, address := range peer.Addresses {
+		if address.Address != peer.Address {
+			addresses = append(addresses, address.Address)
+		}
+	}
+	return addresses
+}
+
+// bookAddresses tags a bundle entry's addresses, keeping the reachability
+// of any in known. Addresses that are not host:port are left out, since a
+// bundle may predate the tagging.
+func bookAddresses(addresses []string, known []PeerAddress) []PeerAddress {
+	valid := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if _, err := ParsePeerAddress(address); err == nil {
+			valid = append(valid, address)
+		}
+	}
+	parsed, _ := parsePeerAddresses(valid, known)
+	return parsed
+}
+
+func clampReputation(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
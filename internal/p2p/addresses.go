@@ -0,0 +1,354 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"time"
+)
+
+// AddressFamily tags a peer address with how it is dialed.
+type AddressFamily string
+
+const (
+	FamilyIPv4 AddressFamily = "ip4"
+	FamilyIPv6 AddressFamily = "ip6"
+	// FamilyDNS is a host name, resolved when dialed.
+	FamilyDNS AddressFamily = "dns"
+)
+
+// DefaultReachability is the score a newly learned address starts at.
+// Each dial moves it towards 1 on success and towards 0 on failure.
+const DefaultReachability = 0.5
+
+// reachabilityAlpha weights the latest dial in an address's reachability.
+const reachabilityAlpha = 0.3
+
+// DefaultDialStagger is how long a dial waits on one address before also
+// trying the next, the connection attempt delay RFC 8305 recommends.
+const DefaultDialStagger = 250 * time.Millisecond
+
+// PeerAddress is one way to reach a peer.
+type PeerAddress struct {
+	Address      string        `json:"address"`
+	Family       AddressFamily `json:"family"`
+	Reachability float64       `json:"reachability"`
+}
+
+// ParsePeerAddress tags a host:port address with its family. IPv6 hosts
+// are bracketed, as in "[2001:db8::1]:4001".
+func ParsePeerAddress(address string) (PeerAddress, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" || port == "" {
+		return PeerAddress{}, fmt.Errorf("%w: %q", ErrInvalidAddress, address)
+	}
+	family := FamilyDNS
+	if ip, err := netip.ParseAddr(host); err == nil {
+		family = FamilyIPv6
+		if ip.Unmap().Is4() {
+			family = FamilyIPv4
+		}
+	}
+	return PeerAddress{Address: address, Family: family, Reachability: DefaultReachability}, nil
+}
+
+// observe folds one dial outcome into the address's reachability.
+func (a *PeerAddress) observe(reached bool) {
+	outcome := 0.0
+	if reached {
+		outcome = 1
+	}
+	a.Reachability = reachabilityAlpha*outcome + (1-reachabilityAlpha)*a.Reachability
+}
+
+// DialFunc opens a connection to one peer address.
+type DialFunc func(ctx context.Context, address PeerAddress) (net.Conn, error)
+
+// Dialer reaches a peer over whichever of its addresses answers first, in
+// the manner of happy eyeballs (RFC 8305). Addresses are tried from most to
+// least reachable, alternating families so that a broken family, such as
+// IPv4 on an IPv6-only carrier, costs one stagger rather than a timeout per
+// address. A new attempt starts each Stagger, or at once when the previous
+// one fails; the first connection wins and the others are cancelled.
+type Dialer struct {
+	// Dial opens one connection; nil dials TCP.
+	Dial DialFunc
+	// Stagger defaults to DefaultDialStagger.
+	Stagger time.Duration
+}
+
+// DialResult is the outcome of a dial across a peer's addresses.
+type DialResult struct {
+	Conn net.Conn
+	// Address is the address Conn was opened to.
+	Address PeerAddress
+	// Failed lists the addresses whose attempts failed before one won.
+	// Attempts cancelled by the winner are not listed.
+	Failed []PeerAddress
+}
+
+type dialAttempt struct {
+	address PeerAddress
+	conn    net.Conn
+	err     error
+}
+
+// DialAddresses dials addresses as the Dialer describes. It fails with
+// ErrPeerUnreachable, joined with every attempt's error, when none
+// connects.
+func (d *Dialer) DialAddresses(ctx context.Context, addresses []PeerAddress) (DialResult, error) {
+	var result DialResult
+	if len(addresses) == 0 {
+		return result, fmt.Errorf("%w: no addresses", ErrPeerUnreachable)
+	}
+	dial := d.Dial
+	if dial == nil {
+		var dialer net.Dialer
+		dial = func(ctx context.Context, address PeerAddress) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", address.Address)
+		}
+	}
+	stagger := d.Stagger
+	if stagger <= 0 {
+		stagger = DefaultDialStagger
+	}
+
+	ordered := orderAddresses(addresses)
+	attemptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Buffered so attempts still running after the dial returns never
+	// block; closeLate closes the connections they open.
+	attempts := make(chan dialAttempt, len(ordered))
+	next, running := 0, 0
+	start := func() {
+		address := ordered[next]
+		next++
+		running++
+		go func() {
+			conn, err := dial(attemptCtx, address)
+			attempts <- dialAttempt{address: address, conn: conn, err: err}
+		}()
+	}
+	closeLate := func() {
+		go func(pending int) {
+			for ; pending > 0; pending-- {
+				if late := <-attempts; late.conn != nil {
+					late.conn.Close()
+				}
+			}
+		}(running)
+	}
+
+	start()
+	timer := time.NewTimer(stagger)
+	defer timer.Stop()
+	var errs []error
+	for running > 0 {
+		select {
+		case attempt := <-attempts:
+			running--
+			if attempt.err == nil {
+				cancel()
+				closeLate()
+				result.Conn, result.Address = attempt.conn, attempt.address
+				return result, nil
+			}
+			result.Failed = append(result.Failed, attempt.address)
+			errs = append(errs, fmt.Errorf("%s: %w", attempt.address.Address, attempt.err))
+			if next < len(ordered) {
+				start()
+				timer.Reset(stagger)
+			}
+		case <-timer.C:
+			if next < len(ordered) {
+				start()
+				timer.Reset(stagger)
+			}
+		case <-ctx.Done():
+			cancel()
+			closeLate()
+			return result, ctx.Err()
+		}
+	}
+	return result, fmt.Errorf("%w: %w", ErrPeerUnreachable, errors.Join(errs...))
+}
+
+// orderAddresses sorts addresses by reachability, IPv6 first among equals,
+// then interleaves the families in the order their best address appears.
+func orderAddresses(addresses []PeerAddress) []PeerAddress {
+	sorted := append([]PeerAddress(nil), addresses...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Reachability != sorted[j].Reachability {
+			return sorted[i].Reachability > sorted[j].Reachability
+		}
+		return familyPreference(sorted[i].Family) < familyPreference(sorted[j].Family)
+	})
+
+	var families []AddressFamily
+	byFamily := make(map[AddressFamily][]PeerAddress)
+	for _, address := range sorted {
+		if _, seen := byFamily[address.Family]; !seen {
+			families = append(families, address.Family)
+		}
+		byFamily[address.Family] = append(byFamily[address.Family], address)
+	}
+	ordered := make([]PeerAddress, 0, len(sorted))
+	for len(ordered) < len(sorted) {
+		for _, family := range families {
+			if queue := byFamily[family]; len(queue) > 0 {
+				ordered = append(ordered, queue[0])
+				byFamily[family] = queue[1:]
+			}
+		}
+	}
+	return ordered
+}
+
+func familyPreference(family AddressFamily) int {
+	switch family {
+	case FamilyIPv6:
+		return 0
+	case FamilyIPv4:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// parsePeerAddresses parses addresses, keeping the reachability already
+// scored for any address in known.
+func parsePeerAddresses(addresses []string, known []PeerAddress) ([]PeerAddress, error) {
+	scores := make(map[string]float64, len(known))
+	for _, address := range known {
+		scores[address.Address] = address.Reachability
+	}
+	parsed := make([]PeerAddress, 0, len(addresses))
+	for _, raw := range addresses {
+		address, err := ParsePeerAddress(raw)
+		if err != nil {
+			return nil, err
+		}
+		if score, ok := scores[raw]; ok {
+			address.Reachability = score
+		}
+		parsed = append(parsed, address)
+	}
+	return parsed, nil
+}
+
+// peerAddresses returns peer's tagged addresses, or its single Address
+// when it has none.
+func peerAddresses(peer *Peer) []PeerAddress {
+	if len(peer.Addresses) > 0 {
+		return append([]PeerAddress(nil), peer.Addresses...)
+	}
+	if peer.Address == "" {
+		return nil
+	}
+	address, err := ParsePeerAddress(peer.Address)
+	if err != nil {
+		return nil
+	}
+	return []PeerAddress{address}
+}
+
+// SetPeerAddresses replaces a peer's addresses, keeping the reachability
+// of any it already had. The first becomes its primary Address.
+func (n *Network) SetPeerAddresses(id string, addresses []string) error {
+	if len(addresses) == 0 {
+		return fmt.Errorf("%w: peer %s needs an address", ErrInvalidAddress, id)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	peer, exists := n.peers[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, id)
+	}
+	parsed, err := parsePeerAddresses(addresses, peer.Addresses)
+	if err != nil {
+		return err
+	}
+	peer.Addresses = parsed
+	peer.Address = parsed[0].Address
+	return nil
+}
+
+// DialPeerAddresses dials a known peer across all of its addresses with
+// dialer and scores each attempt's address. On success the peer is marked
+// connected directly, at the address that answered, and is no longer
+// relayed. The caller owns the returned connection.
+func (n *Network) DialPeerAddresses(ctx context.Context, id string, dialer *Dialer) (net.Conn, error) {
+	n.mu.RLock()
+	peer, exists := n.peers[id]
+	var addresses []PeerAddress
+	if exists {
+		addresses = peerAddresses(peer)
+	}
+	n.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrPeerNotFound, id)
+	}
+
+	result, err := dialer.DialAddresses(ctx, addresses)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	peer, exists = n.peers[id]
+	if !exists {
+		if result.Conn != nil {
+			result.Conn.Close()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrPeerNotFound, id)
+	}
+	if len(peer.Addresses) == 0 {
+		peer.Addresses = addresses
+	}
+	for i := range peer.Addresses {
+		address := &peer.Addresses[i]
+		for _, failed := range result.Failed {
+			if failed.Address == address.Address {
+				address.observe(false)
+			}
+		}
+		if result.Conn != nil && address.Address == result.Address.Address {
+			address.observe(true)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", id, err)
+	}
+
+	peer.Address = result.Address.Address
+	peer.Connected = true
+	peer.LastSeen = time.Now()
+	peer.RelayedVia = ""
+	peer.Metadata["dialed_at"] = peer.LastSeen.UTC().Format(time.RFC3339)
+	peer.Metadata["dialed_address"] = result.Address.Address
+	delete(peer.Metadata, "source")
+	return result.Conn, nil
+}
+
+// MarkRelayed records that a peer is reached through relayID rather than
+// dialed, and marks it connected. An empty relayID clears the mark.
+func (n *Network) MarkRelayed(id, relayID string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	peer, exists := n.peers[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, id)
+	}
+	peer.RelayedVia = relayID
+	if relayID != "" {
+		peer.Connected = true
+		peer.LastSeen = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// PeerAddressTopic carries peers' self-signed address updates.
+const PeerAddressTopic = "peer-address"
+
+// addressUpdatePenalty is subtracted from the reputation of a peer that
+// relays an unsigned, forged, or stale address update.
+const addressUpdatePenalty = 0.2
+
+// addressSequenceKey is the peer metadata key holding the sequence of the
+// last address update applied for the peer.
+const addressSequenceKey = "address_sequence"
+
+// AddressChallenger sends nonce directly to address, bypassing any relay,
+// and returns the signature the node answering there gives for it; see
+// Network.AnswerAddressChallenge.
+type AddressChallenger func(ctx context.Context, peerID string, address PeerAddress, nonce []byte) ([]byte, error)
+
+// maxChallengeFrame bounds a challenge nonce or answer read off the wire.
+const maxChallengeFrame = 16 << 10
+
+// SetAddressChallenger sets how new addresses in a peer's address update
+// are confirmed. It defaults to DialAddressChallenger over TCP; with nil,
+// no address update naming a new address can be applied.
+func (n *Network) SetAddressChallenger(challenger AddressChallenger) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.challenger = challenger
+}
+
+// AnnounceAddresses signs an address update for this node's addresses with
+// its identity key and broadcasts it at commit priority. The sequence is
+// the announcement time in nanoseconds, moved past the previous one, so
+// it keeps increasing across restarts without being persisted.
+func (n *Network) AnnounceAddresses(addresses []string) (*protocol.PeerAddressUpdate, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%w: an address update needs an address", ErrInvalidAddress)
+	}
+	if _, err := parsePeerAddresses(addresses, nil); err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	channel := n.channel
+	if channel == nil {
+		n.mu.Unlock()
+		return nil, fmt.Errorf("address update: no secure channel configured")
+	}
+	sequence := uint64(time.Now().UnixNano()) // #nosec G115 -- the clock is past 1970
+	if sequence <= n.addressSequence {
+		sequence = n.addressSequence + 1
+	}
+	n.addressSequence = sequence
+	n.mu.Unlock()
+
+	update := &protocol.PeerAddressUpdate{
+		NodeID:    n.nodeID,
+		Addresses: append([]string(nil), addresses...),
+		Sequence:  sequence,
+	}
+	digest := update.SigningDigest()
+	signature, err := channel.SignData(digest[:])
+	if err != nil {
+		return nil, err
+	}
+	update.Signature, update.SignatureAlgorithm = signature, channel.Algorithm()
+
+	payload, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("encode address update: %w", err)
+	}
+	if err := n.BroadcastPriority(PeerAddressTopic, payload, PriorityCommit); err != nil {
+		return update, err
+	}
+	return update, nil
+}
+
+// SetLocalAddresses records the addresses this node is reachable at, as
+// discovery finds them, and announces them with AnnounceAddresses when they
+// differ from the last set recorded. It returns the update announced, or
+// nil when the addresses did not change.
+func (n *Network) SetLocalAddresses(addresses []string) (*protocol.PeerAddressUpdate, error) {
+	n.mu.RLock()
+	unchanged := slices.Equal(n.localAddresses, addresses)
+	n.mu.RUnlock()
+	if unchanged {
+		return nil, nil
+	}
+	update, err := n.AnnounceAddresses(addresses)
+	if update != nil {
+		n.mu.Lock()
+		n.localAddresses = append([]string(nil), addresses...)
+		n.mu.Unlock()
+	}
+	return update, err
+}
+
+// AnswerAddressChallenge signs a challenge received at address with this
+// node's identity key.
+func (n *Network) AnswerAddressChallenge(address string, nonce []byte) ([]byte, error) {
+	n.mu.RLock()
+	channel := n.channel
+	n.mu.RUnlock()
+	if channel == nil {
+		return nil, fmt.Errorf("address challenge: no secure channel configured")
+	}
+	digest := protocol.AddressChallengeDigest(n.nodeID, address, nonce)
+	return channel.SignData(digest[:])
+}
+
+// ApplyAddressUpdate applies a peer's address update, received from
+// relayID. The update must be signed by the identity key registered for
+// the peer it names and carry a higher sequence than the last one applied;
+// otherwise it is dropped with ErrAddressUpdateSignature or
+// ErrStaleAddressUpdate and the relayer's reputation is penalized. The
+// update last applied, which gossip delivers again through every peer
+// that forwards it, is dropped silently without error. Each
+// address the peer did not already have is then challenged directly, and
+// only addresses the peer answered for, or already had, replace its
+// current ones. Until then the current addresses stay in use; if none of
+// the new ones answers, the update fails with ErrAddressUnconfirmed and
+// may be applied again once the peer is reachable there.
+func (n *Network) ApplyAddressUpdate(ctx context.Context, relayID string, update *protocol.PeerAddressUpdate) error {
+	n.mu.RLock()
+	peer, exists := n.peers[update.NodeID]
+	var (
+		publicKey []byte
+		applied   uint64
+		known     = make(map[string]bool)
+	)
+	if exists {
+		publicKey = append([]byte(nil), peer.PublicKey...)
+		applied, _ = peer.Metadata[addressSequenceKey].(uint64)
+		for _, address := range peerAddresses(peer) {
+			known[address.Address] = true
+		}
+	}
+	challenger := n.challenger
+	n.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, update.NodeID)
+	}
+	if len(publicKey) == 0 {
+		return fmt.Errorf("%w: no identity key registered for %s", ErrAddressUpdateSignature, update.NodeID)
+	}
+
+	if err := checkAddressUpdate(publicKey, update); err != nil {
+		n.penalizeAddressRelay(relayID)
+		return fmt.Errorf("%w: update for %s relayed by %s: %v", ErrAddressUpdateSignature, update.NodeID, relayID, err)
+	}
+	if update.Sequence == applied {
+		return nil
+	}
+	if update.Sequence < applied {
+		n.penalizeAddressRelay(relayID)
+		return fmt.Errorf("%w: update for %s relayed by %s has sequence %d, applied %d", ErrStaleAddressUpdate, update.NodeID, relayID, update.Sequence, applied)
+	}
+	parsed, err := parsePeerAddresses(update.Addresses, nil)
+	if err != nil {
+		return err
+	}
+	if len(parsed) == 0 {
+		return fmt.Errorf("%w: update for %s has no address", ErrInvalidAddress, update.NodeID)
+	}
+
+	confirmed := make([]string, 0, len(parsed))
+	var unconfirmed []error
+	challenged, answered := 0, 0
+	for _, address := range parsed {
+		if known[address.Address] {
+			confirmed = append(confirmed, address.Address)
+			continue
+		}
+		challenged++
+		if challenger == nil {
+			unconfirmed = append(unconfirmed, fmt.Errorf("%s: no address challenger configured", address.Address))
+			continue
+		}
+		if err := challengeAddress(ctx, challenger, publicKey, update.NodeID, address); err != nil {
+			unconfirmed = append(unconfirmed, fmt.Errorf("%s: %w", address.Address, err))
+			continue
+		}
+		confirmed = append(confirmed, address.Address)
+		answered++
+	}
+	if challenged > 0 && answered == 0 {
+		return fmt.Errorf("%w: %s kept its previous addresses: %v", ErrAddressUnconfirmed, update.NodeID, errors.Join(unconfirmed...))
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	peer, exists = n.peers[update.NodeID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, update.NodeID)
+	}
+	// This update, or a newer one, may have been applied while this one
+	// was challenged.
+	applied, _ = peer.Metadata[addressSequenceKey].(uint64)
+	if update.Sequence == applied {
+		return nil
+	}
+	if update.Sequence < applied {
+		return fmt.Errorf("%w: update for %s was superseded by sequence %d", ErrStaleAddressUpdate, update.NodeID, applied)
+	}
+	addresses, err := parsePeerAddresses(confirmed, peerAddresses(peer))
+	if err != nil {
+		return err
+	}
+	peer.Addresses = addresses
+	peer.Address = addresses[0].Address
+	peer.Metadata[addressSequenceKey] = update.Sequence
+	return nil
+}
+
+// receiveAddressUpdate decodes and applies an address update gossiped by
+// relayID, penalizing the relay for one that does not decode.
+func (n *Network) receiveAddressUpdate(ctx context.Context, relayID string, payload []byte) error {
+	var update protocol.PeerAddressUpdate
+	if err := json.Unmarshal(payload, &update); err != nil {
+		n.penalizeAddressRelay(relayID)
+		return fmt.Errorf("%w: address update from %s: %v", ErrMalformedPayload, relayID, err)
+	}
+	return n.ApplyAddressUpdate(ctx, relayID, &update)
+}
+
+// DialAddressChallenger returns an AddressChallenger that dials the
+// challenged address alone with dialer, bypassing any relay, writes the
+// nonce, and reads back the answer; ServeAddressChallenge answers it. The
+// exchange is bounded by the context's deadline.
+func DialAddressChallenger(dialer *Dialer) AddressChallenger {
+	return func(ctx context.Context, _ string, address PeerAddress, nonce []byte) ([]byte, error) {
+		result, err := dialer.DialAddresses(ctx, []PeerAddress{address})
+		if err != nil {
+			return nil, err
+		}
+		conn := result.Conn
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			if err := conn.SetDeadline(deadline); err != nil {
+				return nil, err
+			}
+		}
+		if err := writeChallengeFrame(conn, nonce); err != nil {
+			return nil, fmt.Errorf("send challenge: %w", err)
+		}
+		signature, err := readChallengeFrame(conn)
+		if err != nil {
+			return nil, fmt.Errorf("read challenge answer: %w", err)
+		}
+		return signature, nil
+	}
+}
+
+// ServeAddressChallenge answers the address challenge a DialAddressChallenger
+// sends over conn, which this node accepted at address.
+func (n *Network) ServeAddressChallenge(conn io.ReadWriter, address string) error {
+	nonce, err := readChallengeFrame(conn)
+	if err != nil {
+		return fmt.Errorf("read challenge: %w", err)
+	}
+	signature, err := n.AnswerAddressChallenge(address, nonce)
+	if err != nil {
+		return err
+	}
+	return writeChallengeFrame(conn, signature)
+}
+
+// writeChallengeFrame writes data prefixed with its big-endian uint32
+// length.
+func writeChallengeFrame(w io.Writer, data []byte) error {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data))) // #nosec G115 -- nonces and signatures are small
+	if _, err := w.Write(append(length[:], data...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readChallengeFrame reads one length-prefixed frame of at most
+// maxChallengeFrame bytes.
+func readChallengeFrame(r io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxChallengeFrame {
+		return nil, fmt.Errorf("%w: challenge frame of %d bytes, limit %d", ErrMessageTooLarge, size, maxChallengeFrame)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// checkAddressUpdate verifies update's signature against the peer's
+// registered identity key, PEM or PKIX DER, by that key's algorithm.
+func checkAddressUpdate(publicKey []byte, update *protocol.PeerAddressUpdate) error {
+	if len(update.Signature) == 0 {
+		return errors.New("update is unsigned")
+	}
+	publicKeyPEM := peerKeyPEM(publicKey)
+	algorithm, err := crypto.PublicKeyAlgorithm(publicKeyPEM)
+	if err != nil {
+		return err
+	}
+	if err := protocol.CheckAlgorithm(update.SignatureAlgorithm, algorithm); err != nil {
+		return err
+	}
+	digest := update.SigningDigest()
+	return crypto.VerifyWithPublicKey(publicKeyPEM, digest[:], update.Signature)
+}
+
+// challengeAddress sends a fresh nonce to address and checks that the
+// answer is signed by the peer's identity key.
+func challengeAddress(ctx context.Context, challenger AddressChallenger, publicKey []byte, peerID string, address PeerAddress) error {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate challenge nonce: %w", err)
+	}
+	signature, err := challenger(ctx, peerID, address, nonce)
+	if err != nil {
+		return err
+	}
+	digest := protocol.AddressChallengeDigest(peerID, address.Address, nonce)
+	if err := crypto.VerifyWithPublicKey(peerKeyPEM(publicKey), digest[:], signature); err != nil {
+		return fmt.Errorf("challenge answer: %w", err)
+	}
+	return nil
+}
+
+// penalizeAddressRelay lowers the reputation of a peer that relayed a bad
+// address update and counts the violation.
+func (n *Network) penalizeAddressRelay(relayID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if peer, exists := n.peers[relayID]; exists {
+		peer.Reputation = clampReputation(peer.Reputation - addressUpdatePenalty)
+		violations, _ := peer.Metadata["address_update_violations"].(uint64)
+		peer.Metadata["address_update_violations"] = violations + 1
+	}
+}
+
+// peerKeyPEM returns a peer identity key recorded as PEM or PKIX DER as
+// PEM.
+func peerKeyPEM(key []byte) []byte {
+	if block, _ := pem.Decode(key); block != nil {
+		return key
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: key})
+}
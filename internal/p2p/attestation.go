@@ -0,0 +1,42 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"fmt"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// AttestationVerifier checks a registering peer's decoded attestation
+// envelope, typically against a TPM attestation manager.
+type AttestationVerifier func(envelope *protocol.AttestationEnvelope) error
+
+// SetAttestationVerifier gates RegisterPeer on attestation: once set, a peer
+// must carry an AttestationEnvelope for its own ID in TPMAttestation that
+// verify accepts. nil disables the gate.
+func (v *Verifier) SetAttestationVerifier(verify AttestationVerifier) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.attestation = verify
+}
+
+func (v *Verifier) checkAttestation(peer *PeerDetail) error {
+	v.mu.RLock()
+	verify := v.attestation
+	v.mu.RUnlock()
+	if verify == nil {
+		return nil
+	}
+
+	registration := protocol.RegistrationRequest{NodeID: peer.ID, TPMAttestat: peer.TPMAttestation}
+	envelope, err := registration.Attestation()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAttestationRejected, err)
+	}
+	if err := verify(envelope); err != nil {
+		return fmt.Errorf("%w: peer %s: %v", ErrAttestationRejected, peer.ID, err)
+	}
+	return nil
+}
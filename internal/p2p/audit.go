@@ -0,0 +1,195 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"time"
+)
+
+// Spot check defaults.
+const (
+	// DefaultDecoyPenalty is the reputation a verifier loses for approving
+	// a decoy: enough to take a verifier at the 2.0 ceiling to the floor.
+	DefaultDecoyPenalty = 2.0
+	// DefaultDecoyReward is the reputation a verifier gains for rejecting a
+	// decoy.
+	DefaultDecoyReward = 0.02
+)
+
+// Reputation change causes recorded for spot checks.
+const (
+	CauseDecoyApproved = "decoy_approved"
+	CauseDecoyRejected = "decoy_rejected"
+)
+
+// SpotCheckRand is the randomness spot checks draw on. *simrand.SeededRand
+// satisfies it.
+type SpotCheckRand interface {
+	Float64() float64
+	Intn(n int) int
+}
+
+// SpotCheckConfig configures decoy audits of verifiers. A lazy verifier
+// that approves without verifying approves the decoy too; one that does
+// the work rejects it.
+type SpotCheckConfig struct {
+	// Rate is the probability, in [0, 1], that a batch sent to a verifier
+	// carries a decoy. Zero turns spot checks off.
+	Rate float64
+	// Penalty is the reputation lost for approving a decoy. Default
+	// DefaultDecoyPenalty.
+	Penalty float64
+	// Reward is the reputation gained for rejecting one. Default
+	// DefaultDecoyReward.
+	Reward float64
+	// Rand draws which batches carry a decoy, which request it copies, and
+	// where it goes. Default math/rand/v2.
+	Rand SpotCheckRand
+}
+
+// VerifierAudit is a verifier's spot check record.
+type VerifierAudit struct {
+	VerifierID string `json:"verifier_id"`
+	Decoys     int    `json:"decoys"`
+	Approved   int    `json:"approved"`
+	Rejected   int    `json:"rejected"`
+	// Flagged is set once the verifier approves a decoy, at FlaggedAt.
+	Flagged   bool      `json:"flagged"`
+	FlaggedAt time.Time `json:"flagged_at,omitempty"`
+}
+
+type globalRand struct{}
+
+func (globalRand) Float64() float64 { return rand.Float64() }
+func (globalRand) Intn(n int) int   { return rand.IntN(n) }
+
+// SetSpotChecks turns decoy audits on with cfg, or off when cfg.Rate is
+// zero. Outstanding decoys are still scored.
+func (v *Verifier) SetSpotChecks(cfg SpotCheckConfig) error {
+	if cfg.Rate < 0 || cfg.Rate > 1 {
+		return fmt.Errorf("spot check rate must be in [0, 1], got %f", cfg.Rate)
+	}
+	if cfg.Penalty < 0 || cfg.Reward < 0 {
+		return fmt.Errorf("spot check penalty and reward must not be negative")
+	}
+	if cfg.Penalty == 0 {
+		cfg.Penalty = DefaultDecoyPenalty
+	}
+	if cfg.Reward == 0 {
+		cfg.Reward = DefaultDecoyReward
+	}
+	if cfg.Rand == nil {
+		cfg.Rand = globalRand{}
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.spotChecks = cfg
+	return nil
+}
+
+// AssignBatch returns the requests to send verifierID: batch, which must
+// already be requested, with a decoy mixed in at a random position when a
+// spot check is drawn. The decoy copies a request in batch with one byte of
+// its proof flipped, or of its weights when it has no proof, so it cannot
+// verify yet looks like any other request on the wire. Its response is
+// scored against the verifier and never counted toward any request, so
+// only real requests reach aggregation.
+func (v *Verifier) AssignBatch(verifierID string, batch []*ModelVerificationRequest) ([]*ModelVerificationRequest, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.peers[verifierID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownVerifier, verifierID)
+	}
+	for _, req := range batch {
+		if _, ok := v.verifications[req.RequestID]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRequest, req.RequestID)
+		}
+	}
+	cfg := v.spotChecks
+	if len(batch) == 0 || cfg.Rate == 0 || cfg.Rand.Float64() >= cfg.Rate {
+		return batch, nil
+	}
+
+	source := batch[cfg.Rand.Intn(len(batch))]
+	d := *source
+	d.ModelWeights = append([]byte(nil), source.ModelWeights...)
+	d.Proof = append([]byte(nil), source.Proof...)
+	target := d.Proof
+	if len(target) == 0 {
+		target = d.ModelWeights
+	}
+	if len(target) == 0 {
+		return batch, nil
+	}
+	target[cfg.Rand.Intn(len(target))] ^= byte(1 + cfg.Rand.Intn(255))
+	digest := PayloadDigest([]byte(d.ArtifactType), d.ModelWeights, d.Proof)
+	d.RequestID = RequestID(d.ProposerID, d.Round, digest, d.Attempt)
+	if _, exists := v.verifications[d.RequestID]; exists {
+		return batch, nil
+	}
+	v.decoys[d.RequestID] = verifierID
+	v.auditLocked(verifierID).Decoys++
+
+	at := cfg.Rand.Intn(len(batch) + 1)
+	assigned := make([]*ModelVerificationRequest, 0, len(batch)+1)
+	assigned = append(assigned, batch[:at]...)
+	assigned = append(assigned, &d)
+	return append(assigned, batch[at:]...), nil
+}
+
+// scoreDecoyLocked scores resp, a response to a decoy, against its
+// verifier: approving it flags the verifier and costs the penalty,
+// rejecting it earns the reward. The caller holds v.mu; call the returned
+// function after releasing it.
+func (v *Verifier) scoreDecoyLocked(peer *PeerDetail, resp *ModelVerificationResponse) func() {
+	delete(v.decoys, resp.RequestID)
+	audit := v.auditLocked(peer.ID)
+	now := time.Now()
+	cause := CauseDecoyRejected
+	if resp.Valid {
+		audit.Approved++
+		if !audit.Flagged {
+			audit.Flagged, audit.FlaggedAt = true, now
+		}
+		peer.Reputation = max(peer.Reputation-v.spotChecks.Penalty, 0.1)
+		cause = CauseDecoyApproved
+	} else {
+		audit.Rejected++
+		peer.Reputation = min(peer.Reputation+v.spotChecks.Reward, 2.0)
+	}
+	return v.recordHistoryLocked(peer.ID, now, peer.Reputation, cause)
+}
+
+func (v *Verifier) auditLocked(verifierID string) *VerifierAudit {
+	audit, ok := v.audits[verifierID]
+	if !ok {
+		audit = &VerifierAudit{VerifierID: verifierID}
+		v.audits[verifierID] = audit
+	}
+	return audit
+}
+
+// Audits returns every audited verifier's spot check record, sorted by
+// verifier.
+func (v *Verifier) Audits() []VerifierAudit {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	audits := make([]VerifierAudit, 0, len(v.audits))
+	for _, audit := range v.audits {
+		audits = append(audits, *audit)
+	}
+	sort.Slice(audits, func(i, j int) bool { return audits[i].VerifierID < audits[j].VerifierID })
+	return audits
+}
+
+// Flagged reports whether verifierID has approved a decoy.
+func (v *Verifier) Flagged(verifierID string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	audit, ok := v.audits[verifierID]
+	return ok && audit.Flagged
+}
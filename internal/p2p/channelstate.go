@@ -0,0 +1,122 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	stdcrypto "crypto"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+)
+
+// PeerHandshake re-runs the key exchange with a peer whose secure channel
+// state was evicted, returning its current identity key as PEM or PKIX
+// DER.
+type PeerHandshake func(peerID string) ([]byte, error)
+
+// ChannelStats counts the secure channel's peer state churn.
+type ChannelStats struct {
+	// PeerKeyEvictions and SessionEvictions count evictions by kind.
+	PeerKeyEvictions int `json:"peer_key_evictions"`
+	SessionEvictions int `json:"session_evictions"`
+	// Rehandshakes counts evicted peers re-established on reappearing.
+	Rehandshakes int `json:"rehandshakes"`
+}
+
+// channelEvictedKey marks, in a peer's metadata, when the secure channel
+// last evicted its key.
+const channelEvictedKey = "channel_evicted_at"
+
+// SetPeerHandshake sets how the network re-establishes a peer the secure
+// channel evicted. Without one, the peer is re-admitted with the identity
+// key recorded for it, from its address book entry or last rotation.
+func (n *Network) SetPeerHandshake(handshake PeerHandshake) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handshake = handshake
+}
+
+// SetPeerPublicKey records a peer's identity key, as PEM or PKIX DER, for
+// re-establishing the peer should the secure channel evict it.
+func (n *Network) SetPeerPublicKey(id string, key []byte) error {
+	if _, err := parsePeerKey(key); err != nil {
+		return fmt.Errorf("%w: %s identity key: %v", ErrInvalidPeer, id, err)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	peer, exists := n.peers[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, id)
+	}
+	peer.PublicKey = append([]byte(nil), key...)
+	return nil
+}
+
+// ChannelStats returns the secure channel's peer state churn.
+func (n *Network) ChannelStats() ChannelStats {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.channelStats
+}
+
+// notePeerEviction is the secure channel's eviction hook.
+func (n *Network) notePeerEviction(eviction crypto.PeerEviction) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if eviction.Kind != crypto.EvictedPeerKey {
+		n.channelStats.SessionEvictions++
+		return
+	}
+	n.channelStats.PeerKeyEvictions++
+	if peer, ok := n.peers[eviction.PeerID]; ok && peer.Metadata != nil {
+		peer.Metadata[channelEvictedKey] = time.Now()
+	}
+}
+
+// rehandshake is the secure channel's key resolver: it re-establishes a
+// peer whose key was evicted when the peer is used again.
+func (n *Network) rehandshake(peerID string) (stdcrypto.PublicKey, error) {
+	n.mu.RLock()
+	handshake := n.handshake
+	var known []byte
+	if peer, ok := n.peers[peerID]; ok {
+		known = append([]byte(nil), peer.PublicKey...)
+	}
+	n.mu.RUnlock()
+
+	key := known
+	if handshake != nil {
+		fresh, err := handshake(peerID)
+		if err != nil {
+			return nil, fmt.Errorf("handshake with %s: %w", peerID, err)
+		}
+		key = fresh
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%w: no identity key for %s", ErrPeerNotFound, peerID)
+	}
+	publicKey, err := parsePeerKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s identity key: %v", ErrInvalidPeer, peerID, err)
+	}
+
+	n.mu.Lock()
+	n.channelStats.Rehandshakes++
+	if peer, ok := n.peers[peerID]; ok {
+		peer.PublicKey = key
+		delete(peer.Metadata, channelEvictedKey)
+	}
+	n.mu.Unlock()
+	return publicKey, nil
+}
+
+// parsePeerKey parses an identity key held as PEM or as PKIX DER.
+func parsePeerKey(key []byte) (stdcrypto.PublicKey, error) {
+	if block, _ := pem.Decode(key); block == nil {
+		key = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: key})
+	}
+	return crypto.ImportPublicKey(key)
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// ArtifactType identifies what a verification request covers. Each type is
+// checked by a committee sized for the assurance it needs.
+type ArtifactType string
+
+const (
+	// ArtifactModelUpdate is a participant's model update. Requests with no
+	// artifact type use this policy.
+	ArtifactModelUpdate ArtifactType = "model_update"
+	// ArtifactZKProof is a zero-knowledge proof.
+	ArtifactZKProof ArtifactType = "zk_proof"
+	// ArtifactAttestation is a TPM or enclave attestation.
+	ArtifactAttestation ArtifactType = "attestation"
+	// ArtifactAggregateAudit is a full audit of an aggregated model.
+	ArtifactAggregateAudit ArtifactType = "aggregate_audit"
+)
+
+// defaultConfidenceThreshold is the >66% weighted approval required for
+// Byzantine fault tolerance.
+const defaultConfidenceThreshold = 0.66
+
+// CommitteePolicy sets the verification committee for one artifact type.
+type CommitteePolicy struct {
+	// Size is the number of qualifying responses required.
+	Size int `json:"size"`
+	// ConfidenceThreshold is the reputation-weighted approval ratio that
+	// must be exceeded.
+	ConfidenceThreshold float64 `json:"confidence_threshold"`
+	// ReputationFloor excludes responses from verifiers below this
+	// reputation from both the count and the weighting.
+	ReputationFloor float64 `json:"reputation_floor"`
+	// MaxRelayedFraction bounds the share of Size that verifiers reached
+	// through a relay may fill, so the relaying aggregator, which sees and
+	// can withhold their traffic, cannot supply the committee by itself.
+	// Zero leaves relayed verifiers unbounded.
+	MaxRelayedFraction float64 `json:"max_relayed_fraction,omitempty"`
+}
+
+// relayedLimit returns how many relayed verifiers count towards the
+// committee, or -1 for no bound.
+func (p CommitteePolicy) relayedLimit() int {
+	if p.MaxRelayedFraction <= 0 {
+		return -1
+	}
+	// The epsilon keeps fractions such as 1/3 of 3 from rounding down.
+	return int(math.Floor(p.MaxRelayedFraction*float64(p.Size) + 1e-9))
+}
+
+// Validate checks that the policy can be satisfied and is not weaker than
+// a simple majority.
+func (p CommitteePolicy) Validate() error {
+	if p.Size < 1 {
+		return fmt.Errorf("committee size must be at least 1, got %d", p.Size)
+	}
+	if p.ConfidenceThreshold < 0.5 || p.ConfidenceThreshold >= 1 {
+		return fmt.Errorf("confidence threshold must be in [0.5,1), got %f", p.ConfidenceThreshold)
+	}
+	if p.ReputationFloor < 0 {
+		return fmt.Errorf("reputation floor must not be negative, got %f", p.ReputationFloor)
+	}
+	if p.MaxRelayedFraction < 0 || p.MaxRelayedFraction > 1 {
+		return fmt.Errorf("max relayed fraction must be in [0,1], got %f", p.MaxRelayedFraction)
+	}
+	return nil
+}
+
+// DefaultCommitteePolicies returns the built-in policies. Model updates keep
+// the verifier's global minimum.
+func DefaultCommitteePolicies(minVerifications int) map[ArtifactType]CommitteePolicy {
+	return map[ArtifactType]CommitteePolicy{
+		ArtifactModelUpdate:    {Size: minVerifications, ConfidenceThreshold: defaultConfidenceThreshold},
+		ArtifactZKProof:        {Size: 3, ConfidenceThreshold: defaultConfidenceThreshold, ReputationFloor: 0.5},
+		ArtifactAttestation:    {Size: 5, ConfidenceThreshold: defaultConfidenceThreshold, ReputationFloor: 0.5},
+		ArtifactAggregateAudit: {Size: 12, ConfidenceThreshold: 0.75, ReputationFloor: 0.5},
+	}
+}
+
+// ParseCommitteePolicies decodes a JSON object mapping artifact type to
+// policy and validates every entry.
+func ParseCommitteePolicies(data []byte) (map[ArtifactType]CommitteePolicy, error) {
+	var policies map[ArtifactType]CommitteePolicy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("decode committee policies: %w", err)
+	}
+	for artifact, policy := range policies {
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("committee policy %q: %w", artifact, err)
+		}
+	}
+	return policies, nil
+}
+
+// LoadCommitteePolicies reads policies from a JSON file and applies them on
+// top of the defaults.
+func (v *Verifier) LoadCommitteePolicies(path string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-supplied policy path
+	if err != nil {
+		return fmt.Errorf("read committee policies: %w", err)
+	}
+	policies, err := ParseCommitteePolicies(data)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for artifact, policy := range policies {
+		v.policies[artifact] = policy
+	}
+	return nil
+}
+
+// SetCommitteePolicy sets the policy for one artifact type.
+func (v *Verifier) SetCommitteePolicy(artifact ArtifactType, policy CommitteePolicy) error {
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("committee policy %q: %w", artifact, err)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.policies[artifact] = policy
+	return nil
+}
+
+// SetProbationCheck keeps peers for which onProbation returns true off
+// verification committees: their responses are recorded but never count
+// toward a committee's size or confidence. nil admits every peer.
+func (v *Verifier) SetProbationCheck(onProbation func(peerID string) bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.onProbation = onProbation
+}
+
+// GetCommitteePolicy returns the policy applied to an artifact type.
+func (v *Verifier) GetCommitteePolicy(artifact ArtifactType) (CommitteePolicy, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.policyLocked(artifact)
+}
+
+// policyLocked resolves the policy for artifact. Unknown types are rejected
+// rather than verified under a weaker default.
+func (v *Verifier) policyLocked(artifact ArtifactType) (CommitteePolicy, error) {
+	if artifact == "" {
+		artifact = ArtifactModelUpdate
+	}
+	policy, exists := v.policies[artifact]
+	if !exists {
+		return CommitteePolicy{}, fmt.Errorf("%w: %q", ErrUnknownArtifactType, artifact)
+	}
+	return policy, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the network and verification layers.
+// Match them with errors.Is; never compare error strings.
+var (
+	// ErrPeerNotFound means the peer is not known locally. Retryable once
+	// discovery has registered it.
+	ErrPeerNotFound = errors.New("peer not found")
+	// ErrPeerExists means the peer is already registered. Not retryable.
+	ErrPeerExists = errors.New("peer already registered")
+	// ErrInvalidPeer means the peer record is malformed. Not retryable.
+	ErrInvalidPeer = errors.New("invalid peer")
+	// ErrUnknownVerifier means a verification response came from a peer
+	// that is not a registered verifier. Not retryable.
+	ErrUnknownVerifier = errors.New("unknown verifier")
+	// ErrUnknownRequest means the verification request ID is not pending.
+	// Not retryable.
+	ErrUnknownRequest = errors.New("unknown verification request")
+	// ErrRequestTimeout means a verification request did not gather enough
+	// responses before its deadline. Retryable with a fresh request.
+	ErrRequestTimeout = errors.New("verification request timed out")
+	// ErrNoValidVerifiers means no response came from a verifier with
+	// reputation weight. Retryable once reputable verifiers respond.
+	ErrNoValidVerifiers = errors.New("no valid verifiers")
+	// ErrTransportClosed means the transport no longer accepts messages.
+	// Not retryable.
+	ErrTransportClosed = errors.New("transport closed")
+	// ErrDrainTimeout means outbound queues did not drain before Close gave
+	// up; the remaining messages were discarded. Not retryable.
+	ErrDrainTimeout = errors.New("transport drain timed out")
+	// ErrAddressBookExpired means an address book bundle is past its expiry.
+	// Not retryable; request a fresh bundle.
+	ErrAddressBookExpired = errors.New("address book expired")
+	// ErrAddressBookSignature means an address book bundle was not signed by
+	// the trusted key. Not retryable.
+	ErrAddressBookSignature = errors.New("address book signature invalid")
+	// ErrNoTopicKey means no group key is held for the topic yet. Retryable
+	// after requesting the key from a member.
+	ErrNoTopicKey = errors.New("no group key for topic")
+	// ErrNotTopicMember means the peer is not admitted to the topic. Not
+	// retryable until it is admitted.
+	ErrNotTopicMember = errors.New("not a topic member")
+	// ErrUnknownArtifactType means no committee policy covers the request's
+	// artifact type. Not retryable until a policy is configured.
+	ErrUnknownArtifactType = errors.New("unknown artifact type")
+	// ErrRequestIDConflict means a request ID is already pending for a
+	// different payload. Not retryable; submit with a new attempt number.
+	ErrRequestIDConflict = errors.New("request id conflict")
+	// ErrAttestationRejected means a registering peer's attestation was
+	// missing, malformed, or did not verify. Not retryable with the same
+	// attestation.
+	ErrAttestationRejected = errors.New("attestation rejected")
+	// ErrResponseSignature means a verification response was unsigned or
+	// its signature did not verify against the verifier's registered key.
+	// Not retryable with the same response.
+	ErrResponseSignature = errors.New("verification response signature invalid")
+	// ErrMessageTooLarge means an inbound message exceeded the size limit
+	// for its kind. Not retryable.
+	ErrMessageTooLarge = errors.New("inbound message too large")
+	// ErrDecompressionLimit means a compressed payload expanded beyond the
+	// decompressed-bytes cap. Not retryable.
+	ErrDecompressionLimit = errors.New("decompressed payload exceeds limit")
+	// ErrMalformedPayload means an inbound payload could not be decoded.
+	// Not retryable.
+	ErrMalformedPayload = errors.New("malformed inbound payload")
+	// ErrFragmentAuth means a message fragment's tag did not verify under
+	// the sending peer's pairwise key, so it was forged or spliced from
+	// another message. Not retryable.
+	ErrFragmentAuth = errors.New("fragment authentication failed")
+	// ErrInvalidAddress means a peer address is not host:port. Not
+	// retryable.
+	ErrInvalidAddress = errors.New("invalid peer address")
+	// ErrPeerUnreachable means every address of a peer failed to dial.
+	// Retryable, or reach the peer through its relay.
+	ErrPeerUnreachable = errors.New("peer unreachable")
+	// ErrRelayUnavailable means a relayed peer has no open link to this
+	// node, or its link closed mid-request. Retryable once the peer
+	// reconnects.
+	ErrRelayUnavailable = errors.New("relay link unavailable")
+	// ErrWeightsTooLong means an update declared more weights than the
+	// registered model dimension. Not retryable.
+	ErrWeightsTooLong = errors.New("declared weights exceed model dimension")
+	// ErrInvalidFanoutConfig means a FanoutConfig's bounds, round budget,
+	// or AIMD factors are out of range. Not retryable.
+	ErrInvalidFanoutConfig = errors.New("invalid gossip fanout config")
+	// ErrInvalidReputationVelocity means a ReputationVelocity's window,
+	// gain limits, decay, or diversity rule is out of range. Not
+	// retryable.
+	ErrInvalidReputationVelocity = errors.New("invalid reputation velocity")
+	// ErrAddressUpdateSignature means a peer address update was unsigned or
+	// not signed by the identity key registered for the peer it names. Not
+	// retryable.
+	ErrAddressUpdateSignature = errors.New("address update signature invalid")
+	// ErrStaleAddressUpdate means a peer address update's sequence is not
+	// above the last one applied for the peer, as when an old update is
+	// replayed. Not retryable.
+	ErrStaleAddressUpdate = errors.New("stale address update")
+	// ErrAddressUnconfirmed means no new address in a peer address update
+	// answered a challenge signed by the peer's identity key, so the
+	// previous addresses were kept. Retryable once the peer is reachable
+	// at its new address.
+	ErrAddressUnconfirmed = errors.New("address update unconfirmed")
+	// ErrSendFailed means a peer answered a sent message with an error
+	// status. Retryable.
+	ErrSendFailed = errors.New("peer refused message")
+	// ErrUnknownTopic means no handler is registered for a delivered
+	// message's topic. Not retryable.
+	ErrUnknownTopic = errors.New("no handler for topic")
+)
+
+// Retryable reports whether err is a transient p2p failure.
+func Retryable(err error) bool {
+	return errors.Is(err, ErrPeerNotFound) ||
+		errors.Is(err, ErrRequestTimeout) ||
+		errors.Is(err, ErrNoValidVerifiers) ||
+		errors.Is(err, ErrNoTopicKey) ||
+		errors.Is(err, ErrPeerUnreachable) ||
+		errors.Is(err, ErrRelayUnavailable) ||
+		errors.Is(err, ErrAddressUnconfirmed) ||
+		errors.Is(err, ErrSendFailed)
+}
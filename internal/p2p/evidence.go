@@ -0,0 +1,84 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"sort"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// verificationSubject is the update a model update request verifies.
+type verificationSubject struct {
+	proposerID   string
+	round        int
+	updateDigest string
+}
+
+// VerificationEvidence is a model update request and the signed responses
+// it has received, as an audit exports them.
+type VerificationEvidence struct {
+	RequestID  string
+	ProposerID string
+	Round      int
+	// UpdateDigest is protocol.UpdateDigest of the request's ModelWeights.
+	UpdateDigest string
+	Responses    []ModelVerificationResponse
+}
+
+// trackSubjectLocked remembers the update req verifies. Callers must hold
+// v.mu.
+func (v *Verifier) trackSubjectLocked(req *ModelVerificationRequest) {
+	if req.ArtifactType != "" && req.ArtifactType != ArtifactModelUpdate {
+		return
+	}
+	v.subjects[req.RequestID] = verificationSubject{
+		proposerID:   req.ProposerID,
+		round:        req.Round,
+		updateDigest: protocol.UpdateDigest(req.ModelWeights),
+	}
+}
+
+// Evidence returns the model update requests of round with the
+// responses counted for each, ordered by request ID. Responses are copies.
+func (v *Verifier) Evidence(round int) []VerificationEvidence {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var evidence []VerificationEvidence
+	for requestID, subject := range v.subjects {
+		if subject.round != round {
+			continue
+		}
+		item := VerificationEvidence{
+			RequestID:    requestID,
+			ProposerID:   subject.proposerID,
+			Round:        subject.round,
+			UpdateDigest: subject.updateDigest,
+		}
+		for _, resp := range v.verifications[requestID] {
+			copied := *resp
+			copied.Signature = append([]byte(nil), resp.Signature...)
+			item.Responses = append(item.Responses, copied)
+		}
+		evidence = append(evidence, item)
+	}
+	sort.Slice(evidence, func(i, j int) bool { return evidence[i].RequestID < evidence[j].RequestID })
+	return evidence
+}
+
+// Peer returns a copy of a registered peer.
+func (v *Verifier) Peer(peerID string) (PeerDetail, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	peer, ok := v.peers[peerID]
+	if !ok {
+		return PeerDetail{}, false
+	}
+	copied := *peer
+	copied.Addresses = append([]PeerAddress(nil), peer.Addresses...)
+	copied.PublicKey = append([]byte(nil), peer.PublicKey...)
+	copied.TPMAttestation = append([]byte(nil), peer.TPMAttestation...)
+	return copied, true
+}
@@ -0,0 +1,403 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+// FanoutController defaults.
+const (
+	// DefaultGossipFanout is the fanout a node gossips at before its
+	// controller has observed a round.
+	DefaultGossipFanout = 6
+	// DefaultFanoutMin and DefaultFanoutMax bound the fanout.
+	DefaultFanoutMin = 2
+	DefaultFanoutMax = 24
+	// DefaultPropagationShare is the share of the round budget votes may
+	// take to reach quorum visibility.
+	DefaultPropagationShare = 0.1
+	// DefaultFanoutIncrease is the additive step taken when a round misses
+	// the propagation goal.
+	DefaultFanoutIncrease = 1
+	// DefaultFanoutDecrease is the multiplicative factor applied when a
+	// round beats the goal by more than the headroom.
+	DefaultFanoutDecrease = 0.75
+	// DefaultFanoutHeadroom is the fraction of the goal a round must come
+	// in under before the fanout is cut.
+	DefaultFanoutHeadroom = 0.5
+	// fanoutDecisionHistory bounds the decisions Status reports.
+	fanoutDecisionHistory = 32
+)
+
+// Fanout decision reasons.
+const (
+	FanoutRaised  = "raised"
+	FanoutLowered = "lowered"
+	FanoutHeld    = "held"
+	// FanoutCapped means the message budget cut the fanout or kept it
+	// from rising.
+	FanoutCapped = "capped"
+)
+
+// FanoutConfig bounds a FanoutController. Zero fields other than
+// RoundBudget take the defaults noted on them.
+type FanoutConfig struct {
+	// Min and Max bound the fanout; 1 <= Min <= Max. Defaults
+	// DefaultFanoutMin and DefaultFanoutMax.
+	Min int
+	Max int
+	// Initial is the fanout before the first observed round. Default
+	// DefaultGossipFanout, clamped to the bounds.
+	Initial int
+	// RoundBudget is the round's time budget; the propagation goal is
+	// PropagationShare of it. Required.
+	RoundBudget time.Duration
+	// PropagationShare is in (0, 1]. Default DefaultPropagationShare.
+	PropagationShare float64
+	// Increase is added to the fanout after a round that misses the goal.
+	// Default DefaultFanoutIncrease.
+	Increase int
+	// Decrease, in (0, 1), multiplies the fanout after a round that comes
+	// in under Headroom of the goal, or that overspends the message
+	// budget. Default DefaultFanoutDecrease.
+	Decrease float64
+	// Headroom, in (0, 1), is the fraction of the goal below which the
+	// fanout is cut; rounds between it and the goal hold the fanout, so a
+	// steady shard settles instead of oscillating. Default
+	// DefaultFanoutHeadroom.
+	Headroom float64
+	// MessageBudget, when positive, is the most gossip messages the node
+	// may send in a round. The fanout never rises past what the budget
+	// allows at the last round's traffic.
+	MessageBudget int
+	// Collector, when set, receives every decision as a gossip_fanout
+	// observation and every measured propagation time as a
+	// gossip_propagation_seconds one.
+	Collector *monitoring.Collector
+}
+
+func (c FanoutConfig) withDefaults() FanoutConfig {
+	if c.Min == 0 {
+		c.Min = DefaultFanoutMin
+	}
+	if c.Max == 0 {
+		c.Max = DefaultFanoutMax
+		if c.Min > c.Max {
+			c.Max = c.Min
+		}
+	}
+	if c.Initial == 0 {
+		c.Initial = clampFanout(DefaultGossipFanout, c.Min, c.Max)
+	}
+	if c.PropagationShare == 0 {
+		c.PropagationShare = DefaultPropagationShare
+	}
+	if c.Increase == 0 {
+		c.Increase = DefaultFanoutIncrease
+	}
+	if c.Decrease == 0 {
+		c.Decrease = DefaultFanoutDecrease
+	}
+	if c.Headroom == 0 {
+		c.Headroom = DefaultFanoutHeadroom
+	}
+	return c
+}
+
+// Validate checks the config with its defaults applied.
+func (c FanoutConfig) Validate() error {
+	c = c.withDefaults()
+	switch {
+	case c.Min < 1 || c.Max < c.Min:
+		return fmt.Errorf("%w: fanout bounds [%d, %d]", ErrInvalidFanoutConfig, c.Min, c.Max)
+	case c.Initial < c.Min || c.Initial > c.Max:
+		return fmt.Errorf("%w: initial fanout %d outside [%d, %d]", ErrInvalidFanoutConfig, c.Initial, c.Min, c.Max)
+	case c.RoundBudget <= 0:
+		return fmt.Errorf("%w: round budget %s", ErrInvalidFanoutConfig, c.RoundBudget)
+	case c.PropagationShare <= 0 || c.PropagationShare > 1:
+		return fmt.Errorf("%w: propagation share %g outside (0, 1]", ErrInvalidFanoutConfig, c.PropagationShare)
+	case c.Increase < 1:
+		return fmt.Errorf("%w: increase %d", ErrInvalidFanoutConfig, c.Increase)
+	case c.Decrease <= 0 || c.Decrease >= 1:
+		return fmt.Errorf("%w: decrease %g outside (0, 1)", ErrInvalidFanoutConfig, c.Decrease)
+	case c.Headroom <= 0 || c.Headroom >= 1:
+		return fmt.Errorf("%w: headroom %g outside (0, 1)", ErrInvalidFanoutConfig, c.Headroom)
+	case c.MessageBudget < 0:
+		return fmt.Errorf("%w: message budget %d", ErrInvalidFanoutConfig, c.MessageBudget)
+	}
+	return nil
+}
+
+// PropagationSample is what a node saw of one round's vote gossip.
+type PropagationSample struct {
+	Round int
+	// Propagation is the time from the first vote the node saw to the one
+	// completing a quorum. Reached is false when a quorum never became
+	// visible.
+	Propagation time.Duration
+	Reached     bool
+	// Messages counts the gossip messages the node sent in the round.
+	Messages int
+}
+
+// FanoutDecision records what the controller made of one round.
+type FanoutDecision struct {
+	Round       int           `json:"round"`
+	Propagation time.Duration `json:"propagation"`
+	Reached     bool          `json:"reached"`
+	Messages    int           `json:"messages"`
+	Goal        time.Duration `json:"goal"`
+	Previous    int           `json:"previous"`
+	Fanout      int           `json:"fanout"`
+	// Reason is one of the Fanout constants; Rationale explains it.
+	Reason    string    `json:"reason"`
+	Rationale string    `json:"rationale"`
+	At        time.Time `json:"at"`
+}
+
+// FanoutStatus is a snapshot of a FanoutController.
+type FanoutStatus struct {
+	Fanout        int           `json:"fanout"`
+	Min           int           `json:"min"`
+	Max           int           `json:"max"`
+	Goal          time.Duration `json:"goal"`
+	MessageBudget int           `json:"message_budget,omitempty"`
+	// Decisions holds the most recent decisions, oldest first.
+	Decisions []FanoutDecision `json:"decisions"`
+}
+
+// FanoutController sets a node's gossip fanout with an AIMD controller: a
+// round whose votes reach quorum visibility later than the goal raises
+// the fanout by Increase, one that beats the goal by more than the
+// headroom multiplies it by Decrease, and the message budget caps it. A
+// small shard so settles on a low fanout and a large one on a high one.
+type FanoutController struct {
+	cfg  FanoutConfig
+	goal time.Duration
+
+	mu        sync.Mutex
+	fanout    int
+	decisions []FanoutDecision
+}
+
+// NewFanoutController creates a controller at cfg's initial fanout.
+func NewFanoutController(cfg FanoutConfig) (*FanoutController, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg = cfg.withDefaults()
+	return &FanoutController{
+		cfg:    cfg,
+		goal:   time.Duration(float64(cfg.RoundBudget) * cfg.PropagationShare),
+		fanout: cfg.Initial,
+	}, nil
+}
+
+// Fanout returns the number of peers to gossip each message to.
+func (c *FanoutController) Fanout() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fanout
+}
+
+// Goal returns the propagation time the controller targets.
+func (c *FanoutController) Goal() time.Duration {
+	return c.goal
+}
+
+// Observe records a round's propagation and decides the fanout for the
+// next round.
+func (c *FanoutController) Observe(sample PropagationSample) FanoutDecision {
+	c.mu.Lock()
+	decision := FanoutDecision{
+		Round:       sample.Round,
+		Propagation: sample.Propagation,
+		Reached:     sample.Reached,
+		Messages:    sample.Messages,
+		Goal:        c.goal,
+		Previous:    c.fanout,
+		At:          time.Now().UTC(),
+	}
+	c.decideLocked(&decision)
+	c.decisions = append(c.decisions, decision)
+	if len(c.decisions) > fanoutDecisionHistory {
+		c.decisions = c.decisions[len(c.decisions)-fanoutDecisionHistory:]
+	}
+	c.mu.Unlock()
+
+	if c.cfg.Collector != nil {
+		round := strconv.Itoa(decision.Round)
+		if decision.Reached {
+			c.cfg.Collector.RecordGossipPropagation(decision.Propagation.Seconds(), map[string]string{"round": round})
+		}
+		c.cfg.Collector.RecordGossipFanout(decision.Fanout, map[string]string{
+			"round":     round,
+			"reason":    decision.Reason,
+			"messages":  strconv.Itoa(decision.Messages),
+			"rationale": decision.Rationale,
+		})
+	}
+	return decision
+}
+
+// decideLocked sets decision's fanout and reason, and moves the
+// controller's fanout to it. The caller holds c.mu.
+func (c *FanoutController) decideLocked(decision *FanoutDecision) {
+	previous := c.fanout
+	budget := c.cfg.MessageBudget
+	observed := "quorum never became visible"
+	if decision.Reached {
+		observed = fmt.Sprintf("quorum visible after %s against a %s goal", decision.Propagation, c.goal)
+	}
+
+	next := previous
+	switch {
+	case budget > 0 && decision.Messages > budget:
+		next = c.decreased(previous)
+		decision.Reason = FanoutCapped
+		decision.Rationale = fmt.Sprintf("%d messages overspent the budget of %d; fanout %d", decision.Messages, budget, next)
+	case !decision.Reached || decision.Propagation > c.goal:
+		raised := clampFanout(previous+c.cfg.Increase, previous, c.cfg.Max)
+		switch {
+		case raised == previous:
+			decision.Reason = FanoutHeld
+			decision.Rationale = fmt.Sprintf("%s; fanout %d is the maximum", observed, previous)
+		case budget > 0 && projectMessages(decision.Messages, previous, raised) > budget:
+			decision.Reason = FanoutCapped
+			decision.Rationale = fmt.Sprintf("%s; fanout %d would send about %d messages, over the budget of %d", observed, raised, projectMessages(decision.Messages, previous, raised), budget)
+		default:
+			next = raised
+			decision.Reason = FanoutRaised
+			decision.Rationale = fmt.Sprintf("%s; fanout %d within [%d, %d]", observed, next, c.cfg.Min, c.cfg.Max)
+		}
+	case float64(decision.Propagation) < c.cfg.Headroom*float64(c.goal):
+		next = c.decreased(previous)
+		if next == previous {
+			decision.Reason = FanoutHeld
+			decision.Rationale = fmt.Sprintf("%s; fanout %d is the minimum", observed, previous)
+		} else {
+			decision.Reason = FanoutLowered
+			decision.Rationale = fmt.Sprintf("%s, under %g of it; fanout %d within [%d, %d]", observed, c.cfg.Headroom, next, c.cfg.Min, c.cfg.Max)
+		}
+	default:
+		decision.Reason = FanoutHeld
+		decision.Rationale = fmt.Sprintf("%s; fanout %d meets it", observed, previous)
+	}
+	decision.Fanout = next
+	c.fanout = next
+}
+
+// decreased is fanout multiplied by Decrease, rounded to the nearest but at
+// least one lower, and no lower than Min.
+func (c *FanoutController) decreased(fanout int) int {
+	next := int(math.Round(float64(fanout) * c.cfg.Decrease))
+	if next >= fanout {
+		next = fanout - 1
+	}
+	if next < c.cfg.Min {
+		next = c.cfg.Min
+	}
+	return next
+}
+
+// clampFanout limits fanout to [lo, hi]. The package's min and max are
+// for reputations.
+func clampFanout(fanout, lo, hi int) int {
+	if fanout < lo {
+		return lo
+	}
+	if fanout > hi {
+		return hi
+	}
+	return fanout
+}
+
+// projectMessages scales a round's messages sent at fanout from to fanout
+// to, rounding up.
+func projectMessages(messages, from, to int) int {
+	if from <= 0 {
+		return messages
+	}
+	return (messages*to + from - 1) / from
+}
+
+// Status returns a snapshot of the controller.
+func (c *FanoutController) Status() FanoutStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return FanoutStatus{
+		Fanout:        c.fanout,
+		Min:           c.cfg.Min,
+		Max:           c.cfg.Max,
+		Goal:          c.goal,
+		MessageBudget: c.cfg.MessageBudget,
+		Decisions:     append([]FanoutDecision(nil), c.decisions...),
+	}
+}
+
+// PropagationMeter measures one round's quorum visibility at a node: the
+// time from the first vote it sees to the vote that brings the distinct
+// voters it has seen to quorum. It also counts the gossip messages the
+// node sends, so Sample is ready for a FanoutController.
+type PropagationMeter struct {
+	mu       sync.Mutex
+	quorum   int
+	voters   map[string]struct{}
+	first    time.Time
+	reached  time.Time
+	messages int
+}
+
+// NewPropagationMeter creates a meter for a round needing quorum votes.
+func NewPropagationMeter(quorum int) *PropagationMeter {
+	if quorum < 1 {
+		quorum = 1
+	}
+	return &PropagationMeter{quorum: quorum, voters: make(map[string]struct{})}
+}
+
+// Seen records a vote from voter arriving at at, and reports whether it
+// completed the quorum. Repeated votes from a voter count once.
+func (m *PropagationMeter) Seen(voter string, at time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.voters[voter]; ok {
+		return false
+	}
+	m.voters[voter] = struct{}{}
+	if m.first.IsZero() || at.Before(m.first) {
+		m.first = at
+	}
+	if len(m.voters) == m.quorum {
+		m.reached = at
+		return true
+	}
+	return false
+}
+
+// Sent counts gossip messages the node sent.
+func (m *PropagationMeter) Sent(messages int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages += messages
+}
+
+// Sample reports the round's propagation.
+func (m *PropagationMeter) Sample(round int) PropagationSample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sample := PropagationSample{Round: round, Messages: m.messages}
+	if !m.reached.IsZero() {
+		sample.Reached = true
+		sample.Propagation = m.reached.Sub(m.first)
+	}
+	return sample
+}
@@ -0,0 +1,354 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FragmentKeyFunc returns the pairwise key that authenticates fragments
+// exchanged with peerID, such as crypto.SecureChannel.FragmentMACKey. The
+// sender looks up its destination and the receiver its source, so both
+// must derive the same key.
+type FragmentKeyFunc func(peerID string) ([]byte, error)
+
+const (
+	// MinFragmentMTU is the smallest MTU a Fragmenter accepts.
+	MinFragmentMTU = 256
+	// DefaultReassemblyTimeout bounds how long a partial message waits for
+	// its missing fragments.
+	DefaultReassemblyTimeout = 10 * time.Second
+	// DefaultMaxReassemblyBytes bounds the partial message bytes held for
+	// one peer.
+	DefaultMaxReassemblyBytes = 16 << 20
+
+	// fragmentTopicPrefix marks fragments on the wire; the rest of the
+	// topic is the original message's.
+	fragmentTopicPrefix = "fragment/"
+	fragmentDomain      = "sovereign-fragment/v1"
+	// fragmentHeaderSize is the message ID, index, and count.
+	fragmentHeaderSize = 16
+	fragmentTagSize    = sha256.Size
+	// minFragmentChunk is the fewest payload bytes any fragment but a
+	// message's last carries, at MinFragmentMTU.
+	minFragmentChunk = MinFragmentMTU - fragmentHeaderSize - fragmentTagSize
+)
+
+// IsFragment reports whether topic carries a fragment rather than a whole
+// message.
+func IsFragment(topic string) bool {
+	return strings.HasPrefix(topic, fragmentTopicPrefix)
+}
+
+// Fragmenter splits messages whose payload exceeds the MTU into sequenced
+// fragments. Each fragment carries the message ID, its index, and the
+// fragment count, and is tagged with HMAC-SHA256 under the pairwise key of
+// its destination over those fields, the topic, and its bytes, so a
+// fragment cannot be spliced into another message, position, or topic.
+type Fragmenter struct {
+	mtu int
+	key FragmentKeyFunc
+}
+
+// NewFragmenter creates a fragmenter for mtu-byte payloads.
+func NewFragmenter(mtu int, key FragmentKeyFunc) (*Fragmenter, error) {
+	if mtu < MinFragmentMTU {
+		return nil, fmt.Errorf("fragment MTU %d below minimum %d", mtu, MinFragmentMTU)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("fragmenter needs a key function")
+	}
+	return &Fragmenter{mtu: mtu, key: key}, nil
+}
+
+// MTU returns the largest payload sent unfragmented, and the size of every
+// fragment but the last.
+func (f *Fragmenter) MTU() int {
+	return f.mtu
+}
+
+// Split returns msg alone when its payload fits the MTU, and otherwise its
+// fragments for peerID in order.
+func (f *Fragmenter) Split(peerID string, msg OutboundMessage) ([]OutboundMessage, error) {
+	if len(msg.Payload) <= f.mtu {
+		return []OutboundMessage{msg}, nil
+	}
+	key, err := f.key(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("fragment key for %s: %w", peerID, err)
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, fmt.Errorf("fragment message id: %w", err)
+	}
+	messageID := binary.BigEndian.Uint64(id[:])
+
+	chunkSize := f.mtu - fragmentHeaderSize - fragmentTagSize
+	total := (len(msg.Payload) + chunkSize - 1) / chunkSize
+	fragments := make([]OutboundMessage, 0, total)
+	for index := 0; index < total; index++ {
+		end := (index + 1) * chunkSize
+		if end > len(msg.Payload) {
+			end = len(msg.Payload)
+		}
+		chunk := msg.Payload[index*chunkSize : end]
+		fragment := msg
+		fragment.Topic = fragmentTopicPrefix + msg.Topic
+		fragment.Payload = sealFragment(key, msg.Topic, messageID, index, total, chunk)
+		fragments = append(fragments, fragment)
+	}
+	return fragments, nil
+}
+
+func sealFragment(key []byte, topic string, messageID uint64, index, total int, chunk []byte) []byte {
+	frame := make([]byte, 0, fragmentHeaderSize+len(chunk)+fragmentTagSize)
+	frame = binary.BigEndian.AppendUint64(frame, messageID)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(index)) // #nosec G115 -- index < total, bounded by the payload size
+	frame = binary.BigEndian.AppendUint32(frame, uint32(total)) // #nosec G115 -- bounded by the payload size
+	frame = append(frame, chunk...)
+	return append(frame, fragmentTag(key, topic, frame)...)
+}
+
+func fragmentTag(key []byte, topic string, frame []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(fragmentDomain))
+	_ = binary.Write(mac, binary.BigEndian, uint32(len(topic))) // #nosec G115 -- topics are far below 4 GiB
+	mac.Write([]byte(topic))
+	mac.Write(frame)
+	return mac.Sum(nil)
+}
+
+// FragmentStats counts a reassembler's outcomes.
+type FragmentStats struct {
+	// Reassembled counts messages completed from their fragments.
+	Reassembled uint64 `json:"reassembled"`
+	// Expired counts partial messages dropped with fragments still missing
+	// after the reassembly timeout.
+	Expired uint64 `json:"expired"`
+	// Rejected counts fragments that failed authentication, were
+	// malformed, or would exceed the peer's buffer.
+	Rejected uint64 `json:"rejected"`
+	// Duplicates counts fragments received again.
+	Duplicates uint64 `json:"duplicates"`
+	// Pending is the partial messages currently buffered.
+	Pending int `json:"pending"`
+}
+
+type partialKey struct {
+	peerID    string
+	messageID uint64
+}
+
+// partialMessage holds the fragments of a message received so far. Chunks
+// are keyed by index, so a forged fragment count costs nothing until its
+// fragments arrive.
+type partialMessage struct {
+	topic   string
+	total   int
+	chunks  map[int][]byte
+	bytes   int
+	started time.Time
+}
+
+// Reassembler rebuilds fragmented messages on the receiving side. Partial
+// messages wait at most the reassembly timeout and hold at most
+// maxBytes per peer; a message still missing fragments after the timeout
+// is dropped, counted, and reported to the drop handler. Completed message
+// IDs are remembered for the timeout too, so late duplicates are ignored
+// rather than starting a partial that can never complete.
+type Reassembler struct {
+	key      FragmentKeyFunc
+	timeout  time.Duration
+	maxBytes int
+	now      func() time.Time
+
+	mu        sync.Mutex
+	partials  map[partialKey]*partialMessage
+	completed map[partialKey]time.Time
+	peerBytes map[string]int
+	stats     FragmentStats
+	onDrop    func(peerID string, messageID uint64, received, total int)
+}
+
+// NewReassembler creates a reassembler. A zero timeout or maxBytes takes
+// DefaultReassemblyTimeout or DefaultMaxReassemblyBytes.
+func NewReassembler(key FragmentKeyFunc, timeout time.Duration, maxBytes int) *Reassembler {
+	if timeout <= 0 {
+		timeout = DefaultReassemblyTimeout
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxReassemblyBytes
+	}
+	return &Reassembler{
+		key:       key,
+		timeout:   timeout,
+		maxBytes:  maxBytes,
+		now:       time.Now,
+		partials:  make(map[partialKey]*partialMessage),
+		completed: make(map[partialKey]time.Time),
+		peerBytes: make(map[string]int),
+	}
+}
+
+// SetDropHandler registers a callback invoked, outside the reassembler's
+// lock, for every partial message that expires.
+func (r *Reassembler) SetDropHandler(handler func(peerID string, messageID uint64, received, total int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onDrop = handler
+}
+
+// Accept takes a message received from peerID. A whole message is returned
+// as is; a fragment is buffered, and the message it completes is returned
+// with true. A fragment that fails authentication is refused with
+// ErrFragmentAuth; one that is malformed or inconsistent with its message
+// with ErrMalformedPayload; one that would exceed the peer's buffer with
+// ErrMessageTooLarge.
+func (r *Reassembler) Accept(peerID string, msg OutboundMessage) (OutboundMessage, bool, error) {
+	if !IsFragment(msg.Topic) {
+		return msg, true, nil
+	}
+	r.Expire()
+
+	topic := strings.TrimPrefix(msg.Topic, fragmentTopicPrefix)
+	frame := msg.Payload
+	if len(frame) <= fragmentHeaderSize+fragmentTagSize {
+		return OutboundMessage{}, false, r.reject(fmt.Errorf("%w: fragment of %d bytes from %s", ErrMalformedPayload, len(frame), peerID))
+	}
+	key, err := r.key(peerID)
+	if err != nil {
+		return OutboundMessage{}, false, r.reject(fmt.Errorf("%w: no key for %s: %v", ErrFragmentAuth, peerID, err))
+	}
+	body, tag := frame[:len(frame)-fragmentTagSize], frame[len(frame)-fragmentTagSize:]
+	if !hmac.Equal(tag, fragmentTag(key, topic, body)) {
+		return OutboundMessage{}, false, r.reject(fmt.Errorf("%w: fragment from %s on %s", ErrFragmentAuth, peerID, topic))
+	}
+	messageID := binary.BigEndian.Uint64(body[0:8])
+	index := int(binary.BigEndian.Uint32(body[8:12]))
+	total := int(binary.BigEndian.Uint32(body[12:16]))
+	chunk := body[fragmentHeaderSize:]
+	// A message within maxBytes has at most this many fragments, all but
+	// the last carrying at least minFragmentChunk bytes.
+	maxTotal := (r.maxBytes + minFragmentChunk - 1) / minFragmentChunk
+	if total < 2 || index >= total || total > maxTotal {
+		return OutboundMessage{}, false, r.reject(fmt.Errorf("%w: fragment %d of %d from %s", ErrMalformedPayload, index, total, peerID))
+	}
+
+	r.mu.Lock()
+	k := partialKey{peerID: peerID, messageID: messageID}
+	if _, done := r.completed[k]; done {
+		r.stats.Duplicates++
+		r.mu.Unlock()
+		return OutboundMessage{}, false, nil
+	}
+	partial, exists := r.partials[k]
+	if !exists {
+		partial = &partialMessage{topic: topic, total: total, chunks: make(map[int][]byte), started: r.now()}
+	}
+	if partial.topic != topic || partial.total != total {
+		r.stats.Rejected++
+		r.mu.Unlock()
+		return OutboundMessage{}, false, fmt.Errorf("%w: fragment %d of message %x from %s disagrees with its message", ErrMalformedPayload, index, messageID, peerID)
+	}
+	if _, received := partial.chunks[index]; received {
+		r.stats.Duplicates++
+		r.mu.Unlock()
+		return OutboundMessage{}, false, nil
+	}
+	if r.peerBytes[peerID]+len(chunk) > r.maxBytes {
+		r.stats.Rejected++
+		r.mu.Unlock()
+		return OutboundMessage{}, false, fmt.Errorf("%w: %s would buffer more than %d fragment bytes", ErrMessageTooLarge, peerID, r.maxBytes)
+	}
+	if !exists {
+		r.partials[k] = partial
+	}
+	partial.chunks[index] = append([]byte(nil), chunk...)
+	partial.bytes += len(chunk)
+	r.peerBytes[peerID] += len(chunk)
+	if len(partial.chunks) < total {
+		r.mu.Unlock()
+		return OutboundMessage{}, false, nil
+	}
+
+	r.removeLocked(k, partial)
+	r.completed[k] = partial.started
+	r.stats.Reassembled++
+	r.mu.Unlock()
+
+	payload := make([]byte, 0, partial.bytes)
+	for index := 0; index < total; index++ {
+		payload = append(payload, partial.chunks[index]...)
+	}
+	whole := msg
+	whole.Topic = topic
+	whole.Payload = payload
+	return whole, true, nil
+}
+
+// Expire drops partial messages older than the reassembly timeout and
+// returns how many it dropped. Accept calls it; call it periodically too
+// when fragments may stop arriving.
+func (r *Reassembler) Expire() int {
+	type dropped struct {
+		key             partialKey
+		received, total int
+	}
+	var expired []dropped
+
+	r.mu.Lock()
+	cutoff := r.now().Add(-r.timeout)
+	for k, partial := range r.partials {
+		if partial.started.Before(cutoff) {
+			expired = append(expired, dropped{key: k, received: len(partial.chunks), total: partial.total})
+			r.removeLocked(k, partial)
+			r.stats.Expired++
+		}
+	}
+	for k, started := range r.completed {
+		if started.Before(cutoff) {
+			delete(r.completed, k)
+		}
+	}
+	onDrop := r.onDrop
+	r.mu.Unlock()
+
+	if onDrop != nil {
+		for _, d := range expired {
+			onDrop(d.key.peerID, d.key.messageID, d.received, d.total)
+		}
+	}
+	return len(expired)
+}
+
+// Stats returns the reassembler's counters.
+func (r *Reassembler) Stats() FragmentStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := r.stats
+	stats.Pending = len(r.partials)
+	return stats
+}
+
+func (r *Reassembler) removeLocked(k partialKey, partial *partialMessage) {
+	delete(r.partials, k)
+	r.peerBytes[k.peerID] -= partial.bytes
+	if r.peerBytes[k.peerID] <= 0 {
+		delete(r.peerBytes, k.peerID)
+	}
+}
+
+func (r *Reassembler) reject(err error) error {
+	r.mu.Lock()
+	r.stats.Rejected++
+	r.mu.Unlock()
+	return err
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MessagePath is the node API path HTTPSender posts messages to.
+const MessagePath = "/api/v1/p2p/messages"
+
+// Headers carrying a posted message's envelope; the body is its payload.
+const (
+	HeaderFrom     = "X-Mohawk-From"
+	HeaderTopic    = "X-Mohawk-Topic"
+	HeaderPriority = "X-Mohawk-Priority"
+)
+
+// HTTPSender is a Sender that posts each message to the peer's node API.
+type HTTPSender struct {
+	nodeID string
+	token  string
+	client *http.Client
+	peers  map[string]string
+}
+
+// NewHTTPSender sends as nodeID to the peers in peers, which maps peer IDs
+// to node API base URLs, authenticating with token in the node API role.
+// Empty token sends no credentials.
+func NewHTTPSender(nodeID, token string, client *http.Client, peers map[string]string) *HTTPSender {
+	urls := make(map[string]string, len(peers))
+	for id, base := range peers {
+		urls[id] = strings.TrimRight(base, "/")
+	}
+	return &HTTPSender{nodeID: nodeID, token: token, client: client, peers: urls}
+}
+
+// Send posts msg to peerID. A peer without a URL fails with
+// ErrPeerNotFound, a refused message with ErrSendFailed.
+func (s *HTTPSender) Send(ctx context.Context, peerID string, msg OutboundMessage) error {
+	base, ok := s.peers[peerID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, peerID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+MessagePath, bytes.NewReader(msg.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set(HeaderFrom, s.nodeID)
+	req.Header.Set(HeaderTopic, msg.Topic)
+	req.Header.Set(HeaderPriority, strconv.Itoa(int(msg.Priority)))
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+		req.Header.Set("X-API-Role", "node")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrPeerUnreachable, peerID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: %s: status %d: %s", ErrSendFailed, peerID, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// MessageHandler processes a message peer from delivered on a topic.
+type MessageHandler func(ctx context.Context, from string, payload []byte) error
+
+// Receiver dispatches messages delivered to this node to the handler
+// registered for their topic, reassembling fragmented ones first when it
+// has a reassembler.
+type Receiver struct {
+	mu          sync.RWMutex
+	handlers    map[string]MessageHandler
+	reassembler *Reassembler
+}
+
+// NewReceiver creates a receiver with no topics.
+func NewReceiver() *Receiver {
+	return &Receiver{handlers: make(map[string]MessageHandler)}
+}
+
+// Handle routes messages on topic to handler, replacing any handler
+// registered before.
+func (r *Receiver) Handle(topic string, handler MessageHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[topic] = handler
+}
+
+// SetReassembler reassembles the fragments peers' transports split their
+// messages into. Without one, fragments are refused with ErrUnknownTopic.
+func (r *Receiver) SetReassembler(reassembler *Reassembler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reassembler = reassembler
+}
+
+// Deliver hands msg from peer from to its topic's handler. A fragment is
+// buffered until it completes its message, which is then handed on; a
+// fragment the reassembler refuses fails with its error. A topic without
+// a handler fails with ErrUnknownTopic.
+func (r *Receiver) Deliver(ctx context.Context, from string, msg OutboundMessage) error {
+	r.mu.RLock()
+	reassembler := r.reassembler
+	r.mu.RUnlock()
+	if reassembler != nil {
+		whole, complete, err := reassembler.Accept(from, msg)
+		if err != nil || !complete {
+			return err
+		}
+		msg = whole
+	}
+
+	r.mu.RLock()
+	handler, ok := r.handlers[msg.Topic]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownTopic, msg.Topic)
+	}
+	return handler(ctx, from, msg.Payload)
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MessageKind classifies inbound messages for size limits.
+type MessageKind string
+
+const (
+	KindVote         MessageKind = "vote"
+	KindStatus       MessageKind = "status"
+	KindVerification MessageKind = "verification"
+	KindCommit       MessageKind = "commit"
+	KindModelChunk   MessageKind = "model_chunk"
+	KindUpdate       MessageKind = "update"
+)
+
+// inboundViolationPenalty is subtracted from a peer's reputation for each
+// message that breaks an inbound limit.
+const inboundViolationPenalty = 0.1
+
+// InboundLimits bounds what a peer can make this node hold in memory.
+type InboundLimits struct {
+	// MaxEncryptedBytes caps the on-the-wire size of each message kind.
+	// Kinds without an entry are refused.
+	MaxEncryptedBytes map[MessageKind]int
+	// MaxDecompressedBytes caps the size a compressed payload may expand to.
+	MaxDecompressedBytes int64
+}
+
+// DefaultInboundLimits keeps control traffic small and allows model chunks
+// up to 4 MiB on the wire and 64 MiB decompressed, and whole model updates
+// up to 64 MiB.
+func DefaultInboundLimits() InboundLimits {
+	return InboundLimits{
+		MaxEncryptedBytes: map[MessageKind]int{
+			KindVote:         4 << 10,
+			KindStatus:       16 << 10,
+			KindVerification: 16 << 10,
+			KindCommit:       64 << 10,
+			KindModelChunk:   4 << 20,
+			KindUpdate:       64 << 20,
+		},
+		MaxDecompressedBytes: 64 << 20,
+	}
+}
+
+// InboundGuard enforces InboundLimits on messages before they are decrypted,
+// decompressed, or decoded. Every violation is reported to the violation
+// handler, which Network uses to penalize the sender.
+type InboundGuard struct {
+	limits InboundLimits
+
+	mu          sync.RWMutex
+	onViolation func(peerID string, err error)
+}
+
+// NewInboundGuard creates a guard enforcing limits.
+func NewInboundGuard(limits InboundLimits) *InboundGuard {
+	return &InboundGuard{limits: limits}
+}
+
+// SetViolationHandler registers a callback receiving the sender and the
+// typed error of every violation.
+func (g *InboundGuard) SetViolationHandler(handler func(peerID string, err error)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onViolation = handler
+}
+
+// Limit returns the on-the-wire size limit of kind, and false if the kind
+// has none and is refused.
+func (g *InboundGuard) Limit(kind MessageKind) (int, bool) {
+	limit, ok := g.limits.MaxEncryptedBytes[kind]
+	return limit, ok
+}
+
+// CheckPayload refuses an encrypted payload larger than its kind allows
+// with ErrMessageTooLarge.
+func (g *InboundGuard) CheckPayload(peerID string, kind MessageKind, payload []byte) error {
+	limit, ok := g.limits.MaxEncryptedBytes[kind]
+	if !ok {
+		return g.violation(peerID, fmt.Errorf("%w: no limit for message kind %q from %s", ErrMessageTooLarge, kind, peerID))
+	}
+	if len(payload) > limit {
+		return g.violation(peerID, fmt.Errorf("%w: %s message of %d bytes from %s, limit %d", ErrMessageTooLarge, kind, len(payload), peerID, limit))
+	}
+	return nil
+}
+
+// Decompress inflates a gzip payload, streaming so that at most
+// MaxDecompressedBytes are ever held. A payload that expands further is
+// abandoned with ErrDecompressionLimit.
+func (g *InboundGuard) Decompress(peerID string, compressed []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, g.violation(peerID, fmt.Errorf("%w: from %s: %v", ErrMalformedPayload, peerID, err))
+	}
+	defer reader.Close()
+
+	var out bytes.Buffer
+	limit := g.limits.MaxDecompressedBytes
+	n, err := io.Copy(&out, io.LimitReader(reader, limit+1))
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, g.violation(peerID, fmt.Errorf("%w: from %s: %v", ErrMalformedPayload, peerID, err))
+	}
+	if n > limit {
+		return nil, g.violation(peerID, fmt.Errorf("%w: payload from %s expands beyond %d bytes", ErrDecompressionLimit, peerID, limit))
+	}
+	if err != nil {
+		return nil, g.violation(peerID, fmt.Errorf("%w: from %s: %v", ErrMalformedPayload, peerID, err))
+	}
+	return out.Bytes(), nil
+}
+
+// CheckWeights refuses an update declaring more weights than the model
+// registered for the round with ErrWeightsTooLong. Call it with the declared
+// length before allocating the weights array.
+func (g *InboundGuard) CheckWeights(peerID string, declared, modelDim int) error {
+	if declared > modelDim || declared < 0 {
+		return g.violation(peerID, fmt.Errorf("%w: %s declares %d weights, model has %d", ErrWeightsTooLong, peerID, declared, modelDim))
+	}
+	return nil
+}
+
+func (g *InboundGuard) violation(peerID string, err error) error {
+	g.mu.RLock()
+	handler := g.onViolation
+	g.mu.RUnlock()
+	if handler != nil {
+		handler(peerID, err)
+	}
+	return err
+}
+
+// SetInboundGuard penalizes the reputation of peers whose messages break
+// guard's limits.
+func (n *Network) SetInboundGuard(guard *InboundGuard) {
+	guard.SetViolationHandler(func(peerID string, _ error) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if peer, exists := n.peers[peerID]; exists {
+			peer.Reputation = clampReputation(peer.Reputation - inboundViolationPenalty)
+			violations, _ := peer.Metadata["inbound_violations"].(uint64)
+			peer.Metadata["inbound_violations"] = violations + 1
+		}
+	})
+}
@@ -4,9 +4,13 @@
 package p2p
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
 )
 
 // Network manages peer-to-peer connections for federated learning
@@ -16,8 +20,21 @@ type Network struct {
 	peers        map[string]*Peer
 	topics       map[string][]GossipMessage
 	verification *VerificationProtocol
+	transport    *Transport
+	channel      *crypto.SecureChannel
+	topicKeys    *TopicKeyManager
+	handshake    PeerHandshake
+	channelStats ChannelStats
+
+	challenger      AddressChallenger
+	addressSequence uint64
+	localAddresses  []string
 }
 
+// dropReputationPenalty is subtracted from a peer's reputation each time its
+// outbound queue sheds a message.
+const dropReputationPenalty = 0.01
+
 // GossipMessage captures a published payload on a topic.
 type GossipMessage struct {
 	Topic     string    `json:"topic"`
@@ -28,12 +45,19 @@ type GossipMessage struct {
 
 // Peer represents a connected peer node
 type Peer struct {
-	ID          string                 `json:"id"`
-	Address     string                 `json:"address"`
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	// Addresses lists every way to reach the peer, IPv4 and IPv6, each
+	// scored by how often dialing it succeeded. Address is the primary.
+	Addresses []PeerAddress `json:"addresses,omitempty"`
+	// RelayedVia names the node that relays to this peer when it cannot
+	// be dialed, as behind carrier-grade NAT; empty for a direct peer.
+	RelayedVia  string                 `json:"relayed_via,omitempty"`
 	Connected   bool                   `json:"connected"`
 	LastSeen    time.Time              `json:"last_seen"`
 	Reputation  float64                `json:"reputation"`
 	UpdateCount int                    `json:"update_count"`
+	PublicKey   []byte                 `json:"public_key,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -42,8 +66,9 @@ func NewNetwork(nodeID string, minVerifiers int, timeout time.Duration) *Network
 	return &Network{
 		nodeID:       nodeID,
 		peers:        make(map[string]*Peer),
-		topics:       make(map[string][]GossipMessage),
+		topics:       map[string][]GossipMessage{PeerAddressTopic: {}},
 		verification: NewVerificationProtocol(nodeID, minVerifiers, timeout),
+		challenger:   DialAddressChallenger(&Dialer{}),
 	}
 }
 
@@ -63,11 +88,23 @@ func (n *Network) AddPeer(id, address string, reputation float64) {
 	}
 }
 
-// RemovePeer removes a peer from the network
-func (n *Network) RemovePeer(id string) {
+// RemovePeer removes a peer from the network. With topic keys set, every
+// topic the peer held a key for is rekeyed without it, so it cannot read
+// their later traffic; a rekey that fails is returned.
+func (n *Network) RemovePeer(id string) error {
 	n.mu.Lock()
-	defer n.mu.Unlock()
 	delete(n.peers, id)
+	transport := n.transport
+	keys := n.topicKeys
+	n.mu.Unlock()
+
+	if transport != nil {
+		transport.RemovePeer(id)
+	}
+	if keys != nil {
+		return n.rekeyWithout(keys, id)
+	}
+	return nil
 }
 
 // UpdatePeerLastSeen updates the last seen timestamp for a peer
@@ -87,18 +124,107 @@ func (n *Network) GetPeers() []map[string]interface{} {
 
 	result := make([]map[string]interface{}, 0, len(n.peers))
 	for _, peer := range n.peers {
-		result = append(result, map[string]interface{}{
-			"id":           peer.ID,
-			"address":      peer.Address,
-			"connected":    peer.Connected,
-			"last_seen":    peer.LastSeen,
-			"reputation":   peer.Reputation,
-			"update_count": peer.UpdateCount,
-		})
+		result = append(result, peerSummary(peer))
 	}
 	return result
 }
 
+func peerSummary(peer *Peer) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           peer.ID,
+		"address":      peer.Address,
+		"connected":    peer.Connected,
+		"last_seen":    peer.LastSeen,
+		"reputation":   peer.Reputation,
+		"update_count": peer.UpdateCount,
+		"addresses":    peerAddresses(peer),
+		"relayed_via":  peer.RelayedVia,
+	}
+}
+
+// peerActiveWindow is how recently a connected peer must have been seen to
+// count as active.
+const peerActiveWindow = 5 * time.Minute
+
+func peerActive(peer *Peer, now time.Time) bool {
+	return peer.Connected && now.Sub(peer.LastSeen) < peerActiveWindow
+}
+
+// PeerStatus filters peers by liveness.
+type PeerStatus string
+
+const (
+	// PeerStatusActive matches connected peers seen in the last 5 minutes.
+	PeerStatusActive PeerStatus = "active"
+	// PeerStatusInactive matches every other peer.
+	PeerStatusInactive PeerStatus = "inactive"
+)
+
+// PeerFilter selects peers for QueryPeers. Zero fields match every peer.
+type PeerFilter struct {
+	Status PeerStatus
+	// MinReputation and MaxReputation bound reputation inclusively.
+	MinReputation *float64
+	MaxReputation *float64
+}
+
+func (f PeerFilter) matches(peer *Peer, now time.Time) bool {
+	if f.Status != "" && peerActive(peer, now) != (f.Status == PeerStatusActive) {
+		return false
+	}
+	if f.MinReputation != nil && peer.Reputation < *f.MinReputation {
+		return false
+	}
+	return f.MaxReputation == nil || peer.Reputation <= *f.MaxReputation
+}
+
+// PeerPage is one page of a peer query.
+type PeerPage struct {
+	Peers []map[string]interface{}
+	// Total counts every peer matching the filter, on any page.
+	Total int
+	// NextCursor is the After of the following page, or empty on the last.
+	NextCursor string
+}
+
+// QueryPeers returns up to limit peers matching filter whose IDs sort after
+// after, in ID order. Paging by ID keeps cursors stable while peers come and
+// go: a page never repeats a peer, and a peer present throughout a walk is
+// returned exactly once. Only the returned page is copied.
+func (n *Network) QueryPeers(filter PeerFilter, after string, limit int) PeerPage {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	now := time.Now()
+	page := PeerPage{Peers: []map[string]interface{}{}}
+	ids := make([]string, 0, limit+1)
+	for id, peer := range n.peers {
+		if !filter.matches(peer, now) {
+			continue
+		}
+		page.Total++
+		if id > after {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+		page.NextCursor = ids[limit-1]
+	}
+	for _, id := range ids {
+		page.Peers = append(page.Peers, peerSummary(n.peers[id]))
+	}
+	return page
+}
+
+// PeerCount returns the number of known peers.
+func (n *Network) PeerCount() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return len(n.peers)
+}
+
 // GetActivePeerCount returns the number of currently active peers
 func (n *Network) GetActivePeerCount() int {
 	n.mu.RLock()
@@ -106,10 +232,8 @@ func (n *Network) GetActivePeerCount() int {
 
 	activeCount := 0
 	now := time.Now()
-	timeout := 5 * time.Minute
-
 	for _, peer := range n.peers {
-		if peer.Connected && now.Sub(peer.LastSeen) < timeout {
+		if peerActive(peer, now) {
 			activeCount++
 		}
 	}
@@ -131,24 +255,60 @@ func (n *Network) GetPeer(id string) (*Peer, bool) {
 	return &copy, true
 }
 
+// SetTransport routes broadcasts through per-peer send queues. Messages a
+// peer's queue sheds count against that peer's reputation.
+func (n *Network) SetTransport(transport *Transport) {
+	n.mu.Lock()
+	n.transport = transport
+	n.mu.Unlock()
+
+	if transport == nil {
+		return
+	}
+	transport.SetDropHandler(func(peerID string, _ OutboundMessage) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if peer, exists := n.peers[peerID]; exists {
+			peer.Reputation -= dropReputationPenalty
+			if peer.Reputation < 0 {
+				peer.Reputation = 0
+			}
+			drops, _ := peer.Metadata["send_queue_drops"].(uint64)
+			peer.Metadata["send_queue_drops"] = drops + 1
+		}
+	})
+}
+
 // Broadcast sends a message to all connected peers
 func (n *Network) Broadcast(message []byte) error {
+	return n.BroadcastPriority("", message, PriorityStatus)
+}
+
+// BroadcastPriority enqueues a message for all connected peers. It never
+// waits on a peer; a full queue sheds its lowest-priority message instead.
+func (n *Network) BroadcastPriority(topic string, message []byte, priority Priority) error {
 	n.mu.RLock()
-	peers := make([]*Peer, 0, len(n.peers))
+	peerIDs := make([]string, 0, len(n.peers))
 	for _, peer := range n.peers {
 		if peer.Connected {
-			peers = append(peers, peer)
+			peerIDs = append(peerIDs, peer.ID)
 		}
 	}
+	transport := n.transport
 	n.mu.RUnlock()
 
-	// In production, implement actual network communication
-	// For now, this is a placeholder for the networking layer
-	for _, peer := range peers {
-		_ = peer // Would send message to peer
+	// Without a transport there is no wire to write to; the broadcast is a
+	// local no-op as in single-process deployments.
+	if transport == nil {
+		return nil
 	}
 
-	return nil
+	_, err := transport.Broadcast(peerIDs, OutboundMessage{
+		Topic:    topic,
+		Payload:  append([]byte(nil), message...),
+		Priority: priority,
+	})
+	return err
 }
 
 // SetPeerConnected updates the connection status of a peer
@@ -160,6 +320,7 @@ func (n *Network) SetPeerConnected(id string, connected bool) {
 		peer.Connected = connected
 		if connected {
 			peer.LastSeen = time.Now()
+			delete(peer.Metadata, "source")
 		}
 	}
 }
@@ -171,12 +332,14 @@ func (n *Network) DialPeer(id string) error {
 
 	peer, exists := n.peers[id]
 	if !exists {
-		return errors.New("peer not found")
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, id)
 	}
 
 	peer.Connected = true
 	peer.LastSeen = time.Now()
 	peer.Metadata["dialed_at"] = peer.LastSeen.UTC().Format(time.RFC3339)
+	// A live dial supersedes anything learned from an address book.
+	delete(peer.Metadata, "source")
 	return nil
 }
 
@@ -209,7 +372,26 @@ func (n *Network) JoinTopic(topic string) {
 }
 
 // Publish publishes a message to a topic and broadcasts to active peers.
+// On a topic with a group key (see SetTopicKeys) peers receive the payload
+// sealed under it.
 func (n *Network) Publish(topic string, payload []byte) (int, error) {
+	n.mu.RLock()
+	keys := n.topicKeys
+	n.mu.RUnlock()
+	wire := payload
+	if keyedTopic(keys, topic) {
+		sealed, err := sealGossip(keys, topic, payload)
+		if err != nil {
+			return 0, err
+		}
+		wire = sealed
+	}
+	return n.publish(topic, payload, wire)
+}
+
+// publish keeps payload in topic's local history and broadcasts wire, its
+// form on the wire.
+func (n *Network) publish(topic string, payload, wire []byte) (int, error) {
 	n.mu.Lock()
 	if _, exists := n.topics[topic]; !exists {
 		n.topics[topic] = make([]GossipMessage, 0)
@@ -223,12 +405,50 @@ func (n *Network) Publish(topic string, payload []byte) (int, error) {
 	n.topics[topic] = append(n.topics[topic], msg)
 	n.mu.Unlock()
 
-	if err := n.Broadcast(payload); err != nil {
+	if err := n.BroadcastPriority(topic, wire, PriorityStatus); err != nil {
 		return 0, err
 	}
 	return n.GetActivePeerCount(), nil
 }
 
+// Receive handles a gossip message on topic that peer from delivered.
+// Address updates, on PeerAddressTopic, which every network joins, are
+// applied with ApplyAddressUpdate and from treated as their relay. With
+// topic keys set, key grants and requests are served, and messages on a
+// topic with a group key are opened, those that do not open being
+// refused. Messages on other joined topics are kept for GetTopicMessages,
+// and messages on topics this node has not joined are dropped.
+func (n *Network) Receive(ctx context.Context, from, topic string, payload []byte) error {
+	if topic == PeerAddressTopic {
+		return n.receiveAddressUpdate(ctx, from, payload)
+	}
+	n.mu.RLock()
+	keys := n.topicKeys
+	n.mu.RUnlock()
+	if keys != nil && (topic == TopicKeyGrantTopic || topic == TopicKeyRequestTopic) {
+		return n.receiveTopicKeyTraffic(keys, from, topic, payload)
+	}
+	if keyedTopic(keys, topic) {
+		opened, err := openGossip(keys, topic, payload)
+		if err != nil {
+			return fmt.Errorf("gossip from %s on %s: %w", from, topic, err)
+		}
+		payload = opened
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if messages, joined := n.topics[topic]; joined {
+		n.topics[topic] = append(messages, GossipMessage{
+			Topic:     topic,
+			FromNode:  from,
+			Payload:   append([]byte(nil), payload...),
+			Published: time.Now(),
+		})
+	}
+	return nil
+}
+
 // GetTopicMessages returns published messages for a topic.
 func (n *Network) GetTopicMessages(topic string) []GossipMessage {
 	n.mu.RLock()
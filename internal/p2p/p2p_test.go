@@ -1,20 +1,59 @@
 package p2p
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
 )
 
+// registerSigningPeer registers peer with a fresh channel identity and
+// returns the channel that signs its verification responses.
+func registerSigningPeer(t *testing.T, v *Verifier, peer *PeerDetail) *crypto.SecureChannel {
+	t.Helper()
+	channel, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("new channel: %v", err)
+	}
+	if peer.PublicKey, err = channel.ExportPublicKey(); err != nil {
+		t.Fatalf("export key: %v", err)
+	}
+	if err := v.RegisterPeer(peer); err != nil {
+		t.Fatalf("register %s: %v", peer.ID, err)
+	}
+	return channel
+}
+
+// signed signs resp as its verifier. It reports failures with t.Errorf so
+// it is safe to call from other goroutines.
+func signed(t *testing.T, channel *crypto.SecureChannel, resp *ModelVerificationResponse) *ModelVerificationResponse {
+	t.Helper()
+	if err := resp.Sign(channel); err != nil {
+		t.Errorf("sign response: %v", err)
+	}
+	return resp
+}
+
 func TestVerifierHappyPath(t *testing.T) {
 	v := NewVerifier("node-main", 2, time.Second)
 
-	if err := v.RegisterPeer(&PeerDetail{ID: "peer-a"}); err != nil {
-		t.Fatalf("register peer-a: %v", err)
-	}
-	if err := v.RegisterPeer(&PeerDetail{ID: "peer-b"}); err != nil {
-		t.Fatalf("register peer-b: %v", err)
-	}
+	channelA := registerSigningPeer(t, v, &PeerDetail{ID: "peer-a"})
+	channelB := registerSigningPeer(t, v, &PeerDetail{ID: "peer-b"})
 
 	requestID, err := v.RequestVerification(context.Background(), &ModelVerificationRequest{
 		ModelWeights: []byte("weights"),
@@ -27,22 +66,22 @@ func TestVerifierHappyPath(t *testing.T) {
 		t.Fatalf("request verification: %v", err)
 	}
 
-	err = v.SubmitVerification(context.Background(), &ModelVerificationResponse{
+	err = v.SubmitVerification(context.Background(), "peer-a", signed(t, channelA, &ModelVerificationResponse{
 		RequestID:  requestID,
 		VerifierID: "peer-a",
 		Valid:      true,
 		Timestamp:  time.Now(),
-	})
+	}))
 	if err != nil {
 		t.Fatalf("submit verification peer-a: %v", err)
 	}
 
-	err = v.SubmitVerification(context.Background(), &ModelVerificationResponse{
+	err = v.SubmitVerification(context.Background(), "peer-b", signed(t, channelB, &ModelVerificationResponse{
 		RequestID:  requestID,
 		VerifierID: "peer-b",
 		Valid:      true,
 		Timestamp:  time.Now(),
-	})
+	}))
 	if err != nil {
 		t.Fatalf("submit verification peer-b: %v", err)
 	}
@@ -59,6 +98,125 @@ func TestVerifierHappyPath(t *testing.T) {
 	}
 }
 
+func TestVerifierAcceptsMixedAlgorithmVerifiers(t *testing.T) {
+	v := NewVerifier("node-main", 2, time.Second)
+	channelA := registerSigningPeer(t, v, &PeerDetail{ID: "peer-a"})
+	channelB, err := crypto.NewSecureChannelWithAlgorithm(protocol.AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("new channel: %v", err)
+	}
+	keyB, _ := channelB.ExportPublicKey()
+
+	// A key must be of the algorithm its registration declares.
+	if err := v.RegisterPeer(&PeerDetail{ID: "peer-b", PublicKey: keyB, KeyAlgorithm: protocol.AlgorithmECDSAP256}); !errors.Is(err, ErrInvalidPeer) {
+		t.Fatalf("mislabelled key: expected ErrInvalidPeer, got %v", err)
+	}
+	peerB := &PeerDetail{ID: "peer-b", PublicKey: keyB}
+	if err := v.RegisterPeer(peerB); err != nil {
+		t.Fatalf("register Ed25519 peer: %v", err)
+	}
+	if peerB.KeyAlgorithm != protocol.AlgorithmEd25519 {
+		t.Fatalf("registered key algorithm %q", peerB.KeyAlgorithm)
+	}
+
+	requestID, err := v.RequestVerification(context.Background(), &ModelVerificationRequest{
+		ModelWeights: []byte("weights"),
+		ProposerID:   "node-main",
+		Round:        1,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("request verification: %v", err)
+	}
+	// A response claiming another algorithm than its verifier's key is
+	// refused however it is signed.
+	mislabelled := signed(t, channelB, &ModelVerificationResponse{RequestID: requestID, VerifierID: "peer-b", Valid: true, Timestamp: time.Now()})
+	mislabelled.SignatureAlgorithm = protocol.AlgorithmECDSAP256
+	if err := v.SubmitVerification(context.Background(), "peer-b", mislabelled); !errors.Is(err, ErrResponseSignature) {
+		t.Fatalf("mislabelled response: expected ErrResponseSignature, got %v", err)
+	}
+	for id, channel := range map[string]*crypto.SecureChannel{"peer-a": channelA, "peer-b": channelB} {
+		resp := signed(t, channel, &ModelVerificationResponse{RequestID: requestID, VerifierID: id, Valid: true, Timestamp: time.Now()})
+		if err := v.SubmitVerification(context.Background(), id, resp); err != nil {
+			t.Fatalf("submit %s (%s): %v", id, resp.SignatureAlgorithm, err)
+		}
+	}
+	if complete, _, err := v.CheckVerificationStatus(requestID); err != nil || !complete {
+		t.Fatalf("mixed committee did not complete: %v", err)
+	}
+}
+
+func TestZeroReputationCommitteeHasNoValidVerifiers(t *testing.T) {
+	v := NewVerifier("node-main", 2, time.Second)
+	channels := map[string]*crypto.SecureChannel{
+		"peer-a": registerSigningPeer(t, v, &PeerDetail{ID: "peer-a"}),
+		"peer-b": registerSigningPeer(t, v, &PeerDetail{ID: "peer-b"}),
+	}
+	requestID, err := v.RequestVerification(context.Background(), &ModelVerificationRequest{ProposerID: "node-main", Round: 1})
+	if err != nil {
+		t.Fatalf("request verification: %v", err)
+	}
+	for id, channel := range channels {
+		if err := v.SubmitVerification(context.Background(), id, signed(t, channel, &ModelVerificationResponse{
+			RequestID:  requestID,
+			VerifierID: id,
+			Valid:      true,
+		})); err != nil {
+			t.Fatalf("submit %s: %v", id, err)
+		}
+	}
+	// Penalties have floored both verifiers, which the default committee
+	// still admits.
+	v.mu.Lock()
+	for id := range channels {
+		v.peers[id].Reputation = 0
+	}
+	v.mu.Unlock()
+
+	complete, confidence, err := v.CheckVerificationStatus(requestID)
+	if !errors.Is(err, ErrNoValidVerifiers) || complete || confidence != 0 {
+		t.Fatalf("expected ErrNoValidVerifiers, got complete=%v confidence=%v err=%v", complete, confidence, err)
+	}
+}
+
+func TestProbationaryVerifiersStayOffCommittees(t *testing.T) {
+	v := NewVerifier("node-main", 2, time.Second)
+	v.SetProbationCheck(func(peerID string) bool { return peerID == "newcomer" })
+	channels := map[string]*crypto.SecureChannel{}
+	for _, id := range []string{"peer-a", "newcomer", "peer-b"} {
+		channels[id] = registerSigningPeer(t, v, &PeerDetail{ID: id})
+	}
+	requestID, err := v.RequestVerification(context.Background(), &ModelVerificationRequest{
+		ModelWeights: []byte("weights"),
+		ProposerID:   "node-main",
+		Round:        1,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("request verification: %v", err)
+	}
+
+	// The newcomer's response is recorded but does not fill the second
+	// committee seat.
+	for _, id := range []string{"peer-a", "newcomer", "peer-b"} {
+		if err := v.SubmitVerification(context.Background(), id, signed(t, channels[id], &ModelVerificationResponse{
+			RequestID: requestID, VerifierID: id, Valid: id != "newcomer", Timestamp: time.Now(),
+		})); err != nil {
+			t.Fatalf("submit %s: %v", id, err)
+		}
+		complete, confidence, err := v.CheckVerificationStatus(requestID)
+		if err != nil {
+			t.Fatalf("status after %s: %v", id, err)
+		}
+		if complete != (id == "peer-b") {
+			t.Fatalf("complete after %s = %t", id, complete)
+		}
+		if complete && confidence != 1 {
+			t.Fatalf("confidence %f counts the newcomer's rejection", confidence)
+		}
+	}
+}
+
 func TestVerifierRejectsUnknownPeer(t *testing.T) {
 	v := NewVerifier("node-main", 1, time.Second)
 
@@ -71,7 +229,7 @@ func TestVerifierRejectsUnknownPeer(t *testing.T) {
 		t.Fatalf("request verification: %v", err)
 	}
 
-	err = v.SubmitVerification(context.Background(), &ModelVerificationResponse{
+	err = v.SubmitVerification(context.Background(), "missing-peer", &ModelVerificationResponse{
 		RequestID:  requestID,
 		VerifierID: "missing-peer",
 		Valid:      true,
@@ -125,3 +283,2077 @@ func TestVerificationProtocolRequestLifecycle(t *testing.T) {
 		t.Fatalf("expected positive confidence, got %f", confidence)
 	}
 }
+
+func TestTypedErrorsSurviveWrapping(t *testing.T) {
+	vp := NewVerificationProtocol("node-1", 2, 10*time.Millisecond)
+	if err := vp.RegisterPeer("peer-1"); err != nil {
+		t.Fatalf("register peer: %v", err)
+	}
+	if err := vp.RegisterPeer("peer-1"); !errors.Is(err, ErrPeerExists) {
+		t.Fatalf("expected ErrPeerExists, got %v", err)
+	}
+
+	requestID, err := vp.RequestVerification(context.Background(), []byte("data"), []byte("sig"))
+	if err != nil {
+		t.Fatalf("request verification: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	_, _, err = vp.CheckVerificationStatus(requestID)
+	wrapped := fmt.Errorf("verify round 4: %w", err)
+	if !errors.Is(wrapped, ErrRequestTimeout) || !Retryable(wrapped) {
+		t.Fatalf("expected retryable ErrRequestTimeout, got %v", wrapped)
+	}
+
+	verifier := NewVerifier("node-1", 1, time.Second)
+	err = verifier.SubmitVerification(context.Background(), "ghost", &ModelVerificationResponse{VerifierID: "ghost", RequestID: "r"})
+	if !errors.Is(fmt.Errorf("submit: %w", err), ErrUnknownVerifier) || Retryable(err) {
+		t.Fatalf("expected non-retryable ErrUnknownVerifier, got %v", err)
+	}
+
+	network := NewNetwork("node-1", 1, time.Second)
+	if err := network.DialPeer("nobody"); !errors.Is(err, ErrPeerNotFound) {
+		t.Fatalf("expected ErrPeerNotFound, got %v", err)
+	}
+}
+
+func TestVerificationProtocolBoundsResolvedRequests(t *testing.T) {
+	vp := NewVerificationProtocol("node-main", 2, time.Hour)
+	vp.SetResolvedHistory(1024)
+	// Callbacks run on the resolving goroutine, which is this one.
+	called := map[VerificationOutcome]int{}
+	vp.SetResolvedCallback(func(record VerificationRecord) {
+		called[record.Outcome]++
+	})
+
+	const requests = 100000
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	want := map[VerificationOutcome]int{}
+	var stale string
+	for i := 0; i < requests; i++ {
+		requestID, err := vp.RequestVerificationAttempt(context.Background(), i, 0, []byte(fmt.Sprintf("update-%d", i)), []byte("sig"))
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if i%10 == 9 {
+			// Left unanswered and backdated, so the next request expires it.
+			vp.mu.Lock()
+			vp.pendingRequests[requestID].Timestamp = time.Now().Add(-2 * time.Hour)
+			vp.mu.Unlock()
+			want[OutcomeTimedOut]++
+			stale = requestID
+			continue
+		}
+		valid := i%3 != 0
+		for _, verifier := range []string{"peer-1", "peer-2"} {
+			if err := vp.SubmitVerificationResponse(context.Background(), &VerificationResponse{RequestID: requestID, Valid: valid, VerifierID: verifier, Confidence: 0.9}); err != nil {
+				t.Fatalf("request %d response from %s: %v", i, verifier, err)
+			}
+		}
+		consensus, _, err := vp.CheckVerificationStatus(requestID)
+		if err != nil || consensus != valid {
+			t.Fatalf("request %d: consensus %v, err %v; want %v", i, consensus, err, valid)
+		}
+		if valid {
+			want[OutcomeValid]++
+		} else {
+			want[OutcomeInvalid]++
+		}
+	}
+	if expired := vp.ExpirePending(); expired != 1 {
+		t.Fatalf("expected the last backdated request to expire, got %d", expired)
+	}
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+
+	vp.mu.RLock()
+	pending, responses, order := len(vp.pendingRequests), len(vp.verifications), len(vp.pendingOrder)
+	vp.mu.RUnlock()
+	if pending != 0 || responses != 0 || order != 0 {
+		t.Fatalf("resolved requests still held: %d pending, %d response sets, %d queued", pending, responses, order)
+	}
+	if resolved := vp.Resolved(); len(resolved) != 1024 || resolved[len(resolved)-1].RequestID != stale {
+		t.Fatalf("expected the newest 1024 records ending with %s, got %d", stale, len(resolved))
+	}
+	if growth := int64(after.HeapAlloc) - int64(before.HeapAlloc); growth > 16<<20 {
+		t.Fatalf("heap grew by %d bytes over %d requests", growth, requests)
+	}
+
+	metrics := vp.GetVerificationMetrics()
+	if metrics["pending_requests"] != 0 || metrics["resolved_valid"] != want[OutcomeValid] ||
+		metrics["resolved_invalid"] != want[OutcomeInvalid] || metrics["timed_out"] != want[OutcomeTimedOut] ||
+		metrics["completed_verifications"] != want[OutcomeValid]+want[OutcomeInvalid] || metrics["resolved_history"] != 1024 {
+		t.Fatalf("metrics = %v, want %v", metrics, want)
+	}
+	for outcome, count := range want {
+		if called[outcome] != count {
+			t.Fatalf("callback saw %d %s records, want %d", called[outcome], outcome, count)
+		}
+	}
+
+	// A record still in the history answers later checks.
+	if _, _, err := vp.CheckVerificationStatus(stale); !errors.Is(err, ErrRequestTimeout) {
+		t.Fatalf("expected recorded timeout, got %v", err)
+	}
+	if err := vp.SubmitVerificationResponse(context.Background(), &VerificationResponse{RequestID: stale, VerifierID: "peer-1"}); !errors.Is(err, ErrUnknownRequest) {
+		t.Fatalf("expected ErrUnknownRequest for a resolved request, got %v", err)
+	}
+}
+
+func TestVerificationProtocolWithoutTimeoutDropsResolvedIDs(t *testing.T) {
+	vp := NewVerificationProtocol("node-main", 2, 0)
+	ctx := context.Background()
+	// The first request is never answered and, without a timeout, never
+	// expires; the resolved ones behind it must not queue up after it.
+	if _, err := vp.RequestVerificationAttempt(ctx, 0, 0, []byte("unanswered"), []byte("sig")); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	for i := 1; i <= 1000; i++ {
+		requestID, err := vp.RequestVerificationAttempt(ctx, i, 0, []byte(fmt.Sprintf("update-%d", i)), []byte("sig"))
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		for _, verifier := range []string{"peer-1", "peer-2"} {
+			if err := vp.SubmitVerificationResponse(ctx, &VerificationResponse{RequestID: requestID, Valid: true, VerifierID: verifier, Confidence: 0.9}); err != nil {
+				t.Fatalf("request %d response: %v", i, err)
+			}
+		}
+		if consensus, _, err := vp.CheckVerificationStatus(requestID); err != nil || !consensus {
+			t.Fatalf("request %d: consensus %v, err %v", i, consensus, err)
+		}
+	}
+	if expired := vp.ExpirePending(); expired != 0 {
+		t.Fatalf("expired %d requests without a timeout", expired)
+	}
+	vp.mu.RLock()
+	pending, order := len(vp.pendingRequests), len(vp.pendingOrder)
+	vp.mu.RUnlock()
+	if pending != 1 || order > 2 {
+		t.Fatalf("%d pending requests, %d queued IDs; want the unanswered one alone", pending, order)
+	}
+}
+
+func TestResolvedRingKeepsReresolvedIndex(t *testing.T) {
+	ring := newResolvedRing(3)
+	for _, id := range []string{"a", "b", "a", "c"} {
+		ring.add(VerificationRecord{RequestID: id, Round: len(ring.index)})
+	}
+	// Overwriting a's first record leaves its newer one indexed.
+	for _, id := range []string{"a", "b", "c"} {
+		if record, ok := ring.get(id); !ok || record.RequestID != id {
+			t.Fatalf("get(%s) = %+v, %v", id, record, ok)
+		}
+	}
+	ring.add(VerificationRecord{RequestID: "d"})
+	if _, ok := ring.get("b"); ok {
+		t.Fatal("evicted record still indexed")
+	}
+}
+
+type latencySender struct {
+	mu       sync.Mutex
+	latency  map[string]time.Duration
+	received map[string]int
+}
+
+func (s *latencySender) Send(ctx context.Context, peerID string, msg OutboundMessage) error {
+	s.mu.Lock()
+	delay := s.latency[peerID]
+	s.mu.Unlock()
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	s.mu.Lock()
+	s.received[peerID]++
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *latencySender) count(peerID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received[peerID]
+}
+
+func TestTransportIsolatesSlowPeer(t *testing.T) {
+	sender := &latencySender{
+		latency:  map[string]time.Duration{"slow": 2 * time.Second},
+		received: make(map[string]int),
+	}
+	transport := NewTransport(sender, QueueConfig{Depth: 4, DrainTimeout: 100 * time.Millisecond})
+	network := NewNetwork("node-0", 1, time.Second)
+	network.SetTransport(transport)
+	network.AddPeer("slow", "10.0.0.99:9000", 1.0)
+	for i := 0; i < 20; i++ {
+		network.AddPeer(fmt.Sprintf("fast-%02d", i), fmt.Sprintf("10.0.0.%d:9000", i), 1.0)
+	}
+
+	// One round: status chatter, votes, then the commit.
+	start := time.Now()
+	messages := []Priority{PriorityStatus, PriorityStatus, PriorityStatus, PriorityVote, PriorityVote, PriorityVote, PriorityCommit}
+	for _, priority := range messages {
+		if err := network.BroadcastPriority("round-1", []byte("payload"), priority); err != nil {
+			t.Fatalf("broadcast: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Fatalf("broadcast blocked on slow peer for %s", elapsed)
+	}
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for i := 0; i < 20; i++ {
+		peerID := fmt.Sprintf("fast-%02d", i)
+		for sender.count(peerID) < len(messages) && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if got := sender.count(peerID); got != len(messages) {
+			t.Fatalf("%s received %d/%d messages before round deadline", peerID, got, len(messages))
+		}
+		if drops := transport.Drops(peerID); drops != 0 {
+			t.Fatalf("%s reported %d drops, expected none", peerID, drops)
+		}
+	}
+
+	if drops := transport.Drops("slow"); drops == 0 {
+		t.Fatal("expected slow peer queue to shed messages")
+	}
+	slow, _ := network.GetPeer("slow")
+	if slow.Reputation >= 1.0 {
+		t.Fatalf("expected slow peer reputation to drop, got %f", slow.Reputation)
+	}
+
+	closeStart := time.Now()
+	if err := transport.Close(); !errors.Is(err, ErrDrainTimeout) {
+		t.Fatalf("expected ErrDrainTimeout from stuck slow peer, got %v", err)
+	}
+	if elapsed := time.Since(closeStart); elapsed > time.Second {
+		t.Fatalf("close took %s despite drain timeout", elapsed)
+	}
+	if _, err := transport.Enqueue("fast-00", OutboundMessage{}); !errors.Is(err, ErrTransportClosed) {
+		t.Fatalf("expected ErrTransportClosed after close, got %v", err)
+	}
+}
+
+func TestTransportShedsLowestPriorityFirst(t *testing.T) {
+	queue := &peerQueue{notify: make(chan struct{}, 1)}
+	for _, priority := range []Priority{PriorityCommit, PriorityStatus, PriorityVote} {
+		queue.push(OutboundMessage{Priority: priority}, 3)
+	}
+
+	dropped, ok := queue.push(OutboundMessage{Priority: PriorityVote}, 3)
+	if ok || dropped.Priority != PriorityStatus {
+		t.Fatalf("expected status message shed first, got %v (ok=%v)", dropped.Priority, ok)
+	}
+	dropped, ok = queue.push(OutboundMessage{Priority: PriorityStatus}, 3)
+	if ok || dropped.Priority != PriorityStatus {
+		t.Fatalf("expected incoming status message shed when queue holds only higher priorities, got %v", dropped.Priority)
+	}
+	if queue.drops != 2 {
+		t.Fatalf("expected 2 drops, got %d", queue.drops)
+	}
+}
+
+func signedAddressBook(t *testing.T, key ed25519.PrivateKey, entries []AddressBookEntry) *AddressBook {
+	t.Helper()
+	book, err := NewAddressBook("regional-agg", entries, time.Hour)
+	if err != nil {
+		t.Fatalf("new address book: %v", err)
+	}
+	if err := book.Sign(key); err != nil {
+		t.Fatalf("sign address book: %v", err)
+	}
+	return book
+}
+
+func TestAddressBookRejectsExpiredAndWrongSigner(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(rand.Reader)
+	otherPub, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	entries := []AddressBookEntry{{PeerID: "peer-a", Addresses: []string{"10.0.0.1:4001"}, ReputationPrior: 0.8}}
+
+	book := signedAddressBook(t, key, entries)
+	if err := book.Verify(pub, time.Now().Add(2*time.Hour)); !errors.Is(err, ErrAddressBookExpired) {
+		t.Fatalf("expected ErrAddressBookExpired, got %v", err)
+	}
+
+	network := NewNetwork("node-main", 1, time.Second)
+	forged := signedAddressBook(t, otherKey, entries)
+	if _, err := network.ImportAddressBook(forged, pub); !errors.Is(err, ErrAddressBookSignature) {
+		t.Fatalf("expected ErrAddressBookSignature for wrong signer, got %v", err)
+	}
+
+	// Claiming the trusted key does not help without its signature.
+	forged.SignerKey = pub
+	if _, err := network.ImportAddressBook(forged, pub); !errors.Is(err, ErrAddressBookSignature) {
+		t.Fatalf("expected ErrAddressBookSignature for forged signer key, got %v", err)
+	}
+
+	tampered := signedAddressBook(t, key, entries)
+	tampered.Entries[0].ReputationPrior = 1
+	if _, err := network.ImportAddressBook(tampered, pub); !errors.Is(err, ErrAddressBookSignature) {
+		t.Fatalf("expected ErrAddressBookSignature for tampered bundle, got %v", err)
+	}
+	if _, exists := network.GetPeer("peer-a"); exists {
+		t.Fatal("rejected bundle must not add peers")
+	}
+	if _, err := network.ImportAddressBook(signedAddressBook(t, otherKey, entries), otherPub); err != nil {
+		t.Fatalf("expected bundle to import under its own signer, got %v", err)
+	}
+}
+
+func TestAddressBookMergeKeepsLocalData(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(rand.Reader)
+	network := NewNetwork("node-main", 1, time.Second)
+	network.AddPeer("peer-local", "192.168.1.5:4001", 0.95)
+
+	first := signedAddressBook(t, key, []AddressBookEntry{
+		{PeerID: "peer-local", Addresses: []string{"10.0.0.5:4001"}, PublicKey: []byte("local-key"), ReputationPrior: 0.2},
+		{PeerID: "peer-new", Addresses: []string{"10.0.0.6:4001"}, PublicKey: []byte("new-key"), ReputationPrior: 0.5},
+	})
+	result, err := network.ImportAddressBook(first, pub)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Added != 1 || result.Kept != 1 {
+		t.Fatalf("unexpected import result %+v", result)
+	}
+
+	local, _ := network.GetPeer("peer-local")
+	if local.Address != "192.168.1.5:4001" || local.Reputation != 0.95 {
+		t.Fatalf("locally learned peer was overridden: %+v", local)
+	}
+	if string(local.PublicKey) != "local-key" {
+		t.Fatalf("expected missing public key to be filled in, got %q", local.PublicKey)
+	}
+
+	second := signedAddressBook(t, key, []AddressBookEntry{
+		{PeerID: "peer-local", Addresses: []string{"10.0.0.5:4001"}, PublicKey: []byte("other-key"), ReputationPrior: 0.2},
+		{PeerID: "peer-new", Addresses: []string{"10.0.0.7:4001"}, PublicKey: []byte("new-key"), ReputationPrior: 0.6},
+	})
+	result, err = network.ImportAddressBook(second, pub)
+	if err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+	if result.Updated != 1 || len(result.Conflicts) != 1 || result.Conflicts[0] != "peer-local" {
+		t.Fatalf("unexpected second import result %+v", result)
+	}
+	imported, _ := network.GetPeer("peer-new")
+	if imported.Address != "10.0.0.7:4001" || imported.Reputation != 0.6 {
+		t.Fatalf("expected bundle-only peer to be refreshed, got %+v", imported)
+	}
+
+	if err := network.DialPeer("peer-new"); err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	result, _ = network.ImportAddressBook(first, pub)
+	if result.Kept != 2 {
+		t.Fatalf("expected dialed peer to be treated as locally learned, got %+v", result)
+	}
+}
+
+func TestApplyIdentityRotationRebindsPeerKey(t *testing.T) {
+	rotating, _ := crypto.NewSecureChannel()
+	local, _ := crypto.NewSecureChannel()
+	pemKey, _ := rotating.ExportPublicKey()
+	pub, _ := crypto.ImportPublicKey(pemKey)
+	if err := local.RegisterPeer("peer-a", pub); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	network := NewNetwork("node-main", 1, time.Second)
+	network.SetSecureChannel(local)
+	network.AddPeer("peer-a", "10.0.0.1:4001", 0.9)
+
+	cert, err := rotating.RotateIdentity("peer-a", time.Minute)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if err := network.ApplyIdentityRotation(cert); err != nil {
+		t.Fatalf("apply rotation: %v", err)
+	}
+	peer, _ := network.GetPeer("peer-a")
+	if string(peer.PublicKey) != string(cert.NewPublicKey) || peer.Metadata["key_sequence"] != uint64(1) {
+		t.Fatalf("peer not rebound to rotated key: %+v", peer)
+	}
+	if err := network.ApplyIdentityRotation(cert); !errors.Is(err, crypto.ErrRotationReplay) {
+		t.Fatalf("expected replayed certificate to be rejected, got %v", err)
+	}
+}
+
+func TestNetworkReestablishesEvictedChannelPeers(t *testing.T) {
+	local, _ := crypto.NewSecureChannel()
+	local.SetPeerStateLimits(crypto.PeerStateLimits{PeerKeys: 2})
+	network := NewNetwork("node-main", 1, time.Second)
+	network.SetSecureChannel(local)
+
+	remotes := make(map[string]*crypto.SecureChannel)
+	for _, id := range []string{"peer-a", "peer-b", "peer-c"} {
+		remote, _ := crypto.NewSecureChannel()
+		pemKey, _ := remote.ExportPublicKey()
+		pub, _ := crypto.ImportPublicKey(pemKey)
+		network.AddPeer(id, "10.0.0.1:4001", 0.9)
+		if err := network.SetPeerPublicKey(id, pemKey); err != nil {
+			t.Fatalf("set key: %v", err)
+		}
+		if err := local.RegisterPeer(id, pub); err != nil {
+			t.Fatalf("register: %v", err)
+		}
+		localKey, _ := local.ExportPublicKey()
+		localPub, _ := crypto.ImportPublicKey(localKey)
+		_ = remote.RegisterPeer("node-main", localPub)
+		remotes[id] = remote
+	}
+
+	// peer-a fell out of the channel; the network was told.
+	if stats := network.ChannelStats(); stats.PeerKeyEvictions != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if peer, _ := network.GetPeer("peer-a"); peer.Metadata[channelEvictedKey] == nil {
+		t.Fatalf("eviction not recorded on peer: %+v", peer.Metadata)
+	}
+
+	// When peer-a reappears it is re-established from its recorded key
+	// without the caller noticing.
+	ciphertext, err := local.EncryptMessage("peer-a", []byte("shard sync"))
+	if err != nil {
+		t.Fatalf("encrypt to evicted peer: %v", err)
+	}
+	if plaintext, err := remotes["peer-a"].DecryptMessage("node-main", ciphertext); err != nil || string(plaintext) != "shard sync" {
+		t.Fatalf("decrypt = %q, %v", plaintext, err)
+	}
+	stats := network.ChannelStats()
+	if stats.Rehandshakes != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if peer, _ := network.GetPeer("peer-a"); peer.Metadata[channelEvictedKey] != nil {
+		t.Fatalf("re-established peer still marked evicted: %+v", peer.Metadata)
+	}
+
+	// A handshake, when set, supplies the key instead.
+	network.SetPeerHandshake(func(peerID string) ([]byte, error) {
+		return nil, fmt.Errorf("%s unreachable", peerID)
+	})
+	if _, err := local.EncryptMessage("peer-b", []byte("x")); err == nil {
+		t.Fatal("expected a failed handshake to fail the send")
+	}
+}
+
+func newTopicGroup(t *testing.T, ids ...string) map[string]*TopicKeyManager {
+	t.Helper()
+	channels := make(map[string]*crypto.SecureChannel, len(ids))
+	for _, id := range ids {
+		channel, err := crypto.NewSecureChannel()
+		if err != nil {
+			t.Fatalf("new channel: %v", err)
+		}
+		channels[id] = channel
+	}
+	managers := make(map[string]*TopicKeyManager, len(ids))
+	for _, id := range ids {
+		for _, other := range ids {
+			if other == id {
+				continue
+			}
+			pemKey, _ := channels[other].ExportPublicKey()
+			pub, _ := crypto.ImportPublicKey(pemKey)
+			if err := channels[id].RegisterPeer(other, pub); err != nil {
+				t.Fatalf("register %s with %s: %v", other, id, err)
+			}
+		}
+		managers[id] = NewTopicKeyManager(id, channels[id])
+	}
+	return managers
+}
+
+func installGrants(t *testing.T, managers map[string]*TopicKeyManager, topic, from string, grants map[string][]byte) {
+	t.Helper()
+	for peerID, wrapped := range grants {
+		if err := managers[peerID].InstallKey(topic, from, wrapped); err != nil {
+			t.Fatalf("install key for %s: %v", peerID, err)
+		}
+	}
+}
+
+func TestTopicRekeyLocksOutRemovedMember(t *testing.T) {
+	const topic = "shard-3/votes"
+	managers := newTopicGroup(t, "controller", "member-a", "member-b", "member-c")
+	controller := managers["controller"]
+
+	grants, err := controller.SetMembers(topic, []string{"controller", "member-a", "member-b", "member-c"})
+	if err != nil {
+		t.Fatalf("set members: %v", err)
+	}
+	installGrants(t, managers, topic, "controller", grants)
+
+	envelope, _ := managers["member-a"].Seal(topic, []byte("vote: approve"))
+	if plaintext, err := managers["member-c"].Open(envelope); err != nil || string(plaintext) != "vote: approve" {
+		t.Fatalf("member-c could not read pre-rekey traffic: %q, %v", plaintext, err)
+	}
+
+	grants, err = controller.Remove(topic, "member-c")
+	if err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, included := grants["member-c"]; included || controller.Epoch(topic) != 2 {
+		t.Fatalf("expected epoch 2 without member-c, got epoch %d grants %v", controller.Epoch(topic), len(grants))
+	}
+	// member-b misses the rekey; only member-a installs it.
+	if err := managers["member-a"].InstallKey(topic, "controller", grants["member-a"]); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	envelope, _ = managers["member-a"].Seal(topic, []byte("vote: reject"))
+	if _, err := managers["member-c"].Open(envelope); err == nil {
+		t.Fatal("removed member decrypted post-rekey traffic")
+	}
+	if _, err := managers["member-b"].Open(envelope); !errors.Is(err, crypto.ErrStaleGroupKey) {
+		t.Fatalf("expected member that missed the rekey to see a stale key, got %v", err)
+	}
+
+	// member-b re-authenticates and fetches the key from member-a.
+	req, _ := managers["member-b"].NewKeyRequest(topic)
+	wrapped, err := managers["member-a"].HandleKeyRequest(req)
+	if err != nil {
+		t.Fatalf("handle key request: %v", err)
+	}
+	if err := managers["member-b"].InstallKey(topic, "member-a", wrapped); err != nil {
+		t.Fatalf("install requested key: %v", err)
+	}
+	if plaintext, err := managers["member-b"].Open(envelope); err != nil || string(plaintext) != "vote: reject" {
+		t.Fatalf("member-b could not read after catching up: %q, %v", plaintext, err)
+	}
+
+	// The removed member cannot talk its way back in.
+	req, _ = managers["member-c"].NewKeyRequest(topic)
+	if _, err := managers["member-a"].HandleKeyRequest(req); !errors.Is(err, ErrNotTopicMember) {
+		t.Fatalf("expected ErrNotTopicMember for removed member, got %v", err)
+	}
+	forged := *req
+	forged.PeerID = "member-b"
+	if _, err := managers["member-a"].HandleKeyRequest(&forged); !errors.Is(err, ErrInvalidPeer) {
+		t.Fatalf("expected forged key request to fail re-authentication, got %v", err)
+	}
+}
+
+func TestVerifierAppliesCommitteePolicyPerArtifactType(t *testing.T) {
+	v := NewVerifier("node-main", 2, time.Second)
+	channels := make(map[string]*crypto.SecureChannel)
+	for i := 0; i < 12; i++ {
+		id := fmt.Sprintf("peer-%02d", i)
+		channels[id] = registerSigningPeer(t, v, &PeerDetail{ID: id})
+	}
+	// A low-reputation verifier that always rejects does not count toward
+	// floored committees.
+	channels["peer-low"] = registerSigningPeer(t, v, &PeerDetail{ID: "peer-low", Reputation: 0.2})
+
+	policyPath := filepath.Join(t.TempDir(), "committees.json")
+	if err := os.WriteFile(policyPath, []byte(`{"zk_proof":{"size":4,"confidence_threshold":0.66,"reputation_floor":0.5}}`), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	if err := v.LoadCommitteePolicies(policyPath); err != nil {
+		t.Fatalf("load policies: %v", err)
+	}
+	if _, err := v.RequestVerification(context.Background(), &ModelVerificationRequest{ArtifactType: "unknown"}); !errors.Is(err, ErrUnknownArtifactType) {
+		t.Fatalf("expected ErrUnknownArtifactType, got %v", err)
+	}
+
+	cases := []struct {
+		artifact  ArtifactType
+		responses int
+		rejects   int
+		complete  bool
+	}{
+		{ArtifactModelUpdate, 2, 0, true},
+		{ArtifactZKProof, 3, 0, false}, // loaded policy raised the size to 4
+		{ArtifactZKProof, 4, 0, true},
+		{ArtifactAttestation, 4, 0, false},
+		{ArtifactAttestation, 5, 0, true},
+		{ArtifactAggregateAudit, 12, 4, false}, // 8/12 does not exceed 0.75
+		{ArtifactAggregateAudit, 12, 2, true},
+	}
+
+	requestIDs := make([]string, len(cases))
+	for i, tc := range cases {
+		id, err := v.RequestVerification(context.Background(), &ModelVerificationRequest{
+			RequestID:    fmt.Sprintf("req-%d", i),
+			ArtifactType: tc.artifact,
+		})
+		if err != nil {
+			t.Fatalf("request %s: %v", tc.artifact, err)
+		}
+		requestIDs[i] = id
+	}
+
+	var wg sync.WaitGroup
+	for i, tc := range cases {
+		wg.Add(1)
+		go func(requestID string, responses, rejects int) {
+			defer wg.Done()
+			submit := func(verifier string, valid bool) {
+				if err := v.SubmitVerification(context.Background(), verifier, signed(t, channels[verifier], &ModelVerificationResponse{
+					RequestID:  requestID,
+					VerifierID: verifier,
+					Valid:      valid,
+				})); err != nil {
+					t.Errorf("submit: %v", err)
+				}
+			}
+			submit("peer-low", false)
+			for r := 0; r < responses; r++ {
+				submit(fmt.Sprintf("peer-%02d", r), r >= rejects)
+			}
+		}(requestIDs[i], tc.responses, tc.rejects)
+	}
+	wg.Wait()
+
+	for i, tc := range cases {
+		complete, confidence, err := v.CheckVerificationStatus(requestIDs[i])
+		if err != nil {
+			t.Fatalf("%s case %d: %v", tc.artifact, i, err)
+		}
+		if complete != tc.complete {
+			t.Fatalf("%s case %d: expected complete=%v, got %v (confidence %.3f)", tc.artifact, i, tc.complete, complete, confidence)
+		}
+	}
+}
+
+func TestReputationHistoryPartialBucketStaysOrdered(t *testing.T) {
+	hour := time.Now().Add(-48 * time.Hour).Truncate(reputationBucketWidth)
+	h := &reputationHistory{}
+	for _, minutes := range []int{5, 10, 25} {
+		h.record(ReputationSample{Timestamp: hour.Add(time.Duration(minutes) * time.Minute), Score: float64(minutes), Cause: CauseVerificationValid})
+	}
+	// The cutoff falls mid-hour: the 5 and 10 minute samples move into a
+	// bucket while the 25 minute one stays at full resolution behind it.
+	h.record(ReputationSample{Timestamp: hour.Add(reputationRecentWindow + 20*time.Minute), Score: 1, Cause: CauseVerificationValid})
+
+	if len(h.buckets) != 1 || h.buckets[0].Merged != 2 {
+		t.Fatalf("buckets = %+v, want one merging two samples", h.buckets)
+	}
+	if want := hour.Add(7*time.Minute + 30*time.Second); !h.buckets[0].Timestamp.Equal(want) {
+		t.Fatalf("bucket stamped %v, want the samples' mean time %v", h.buckets[0].Timestamp, want)
+	}
+	history := h.since(time.Time{})
+	for i := 1; i < len(history); i++ {
+		if history[i].Timestamp.Before(history[i-1].Timestamp) {
+			t.Fatalf("history out of order at %d: %v before %v", i, history[i].Timestamp, history[i-1].Timestamp)
+		}
+	}
+
+	// The rest of the hour folds into the same bucket at the new mean.
+	h.record(ReputationSample{Timestamp: hour.Add(reputationRecentWindow + 40*time.Minute), Score: 1, Cause: CauseVerificationValid})
+	if len(h.buckets) != 1 || h.buckets[0].Merged != 3 || math.Abs(h.buckets[0].Score-40.0/3) > 1e-9 {
+		t.Fatalf("buckets = %+v, want one merging three samples", h.buckets)
+	}
+	if want := hour.Add(time.Duration(40) * time.Minute / 3); !h.buckets[0].Timestamp.Equal(want) {
+		t.Fatalf("bucket stamped %v, want %v", h.buckets[0].Timestamp, want)
+	}
+}
+
+func TestReputationHistoryDownsamplingPreservesTrend(t *testing.T) {
+	v := NewVerifier("node-main", 1, time.Second)
+	var observed []float64
+	v.SetReputationTrendObserver(func(peerID string, slope float64) {
+		observed = append(observed, slope)
+	})
+
+	// A month of updates every 10 minutes, declining 0.001 per hour with a
+	// daily oscillation, ending at the registration score.
+	const slope = -0.001
+	now := time.Now()
+	score := func(at time.Time) float64 {
+		hours := at.Sub(now).Hours()
+		return 0.8 + slope*hours + 0.02*math.Sin(2*math.Pi*hours/24)
+	}
+	if err := v.RegisterPeer(&PeerDetail{ID: "peer-a", Reputation: 0.8}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	samples := 0
+	for at := now.Add(-30 * 24 * time.Hour); at.Before(now); at = at.Add(10 * time.Minute) {
+		if err := v.RecordReputation("peer-a", at, score(at), CauseVerificationValid); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+		samples++
+	}
+
+	history, err := v.GetReputationHistory("peer-a", 0)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(history) >= samples/4 {
+		t.Fatalf("expected downsampling to shrink %d samples, kept %d", samples, len(history))
+	}
+	for i := 1; i < len(history); i++ {
+		if history[i].Timestamp.Before(history[i-1].Timestamp) {
+			t.Fatalf("history out of order at %d", i)
+		}
+	}
+	if history[0].Cause != CauseDownsampled || history[len(history)-1].Cause == CauseDownsampled {
+		t.Fatal("expected downsampled buckets first and full-resolution samples last")
+	}
+
+	// Hourly means keep the line: the fitted slope over the month matches
+	// the generated one.
+	fitted, used, err := v.ReputationTrend("peer-a", 0)
+	if err != nil || used != len(history) {
+		t.Fatalf("trend: %v (used %d of %d)", err, used, len(history))
+	}
+	if math.Abs(fitted-slope) > 0.1*math.Abs(slope) {
+		t.Fatalf("downsampled slope %f drifted from generated slope %f", fitted, slope)
+	}
+
+	week, err := v.GetReputationHistory("peer-a", 7*24*time.Hour)
+	if err != nil || len(week) == 0 || len(week) >= len(history) {
+		t.Fatalf("expected windowed history to be a proper subset, got %d of %d (%v)", len(week), len(history), err)
+	}
+	if len(observed) != samples+1 {
+		t.Fatalf("expected trend observer on every update, got %d calls", len(observed))
+	}
+	if _, err := v.GetReputationHistory("missing", 0); !errors.Is(err, ErrPeerNotFound) {
+		t.Fatalf("expected ErrPeerNotFound, got %v", err)
+	}
+}
+
+func TestRequestIDsDeduplicateIdenticalSubmissions(t *testing.T) {
+	v := NewVerifier("node-main", 1, time.Second)
+	channel := registerSigningPeer(t, v, &PeerDetail{ID: "peer-a"})
+	submit := func(req *ModelVerificationRequest) string {
+		t.Helper()
+		id, err := v.RequestVerification(context.Background(), req)
+		if err != nil {
+			t.Fatalf("request verification: %v", err)
+		}
+		return id
+	}
+	proposal := func() *ModelVerificationRequest {
+		return &ModelVerificationRequest{ModelWeights: []byte("weights"), Proof: []byte("proof"), ProposerID: "node-b", Round: 3, Timestamp: time.Now()}
+	}
+
+	first := submit(proposal())
+	if err := v.SubmitVerification(context.Background(), "peer-a", signed(t, channel, &ModelVerificationResponse{RequestID: first, VerifierID: "peer-a", Valid: true})); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	later := proposal()
+	later.Timestamp = later.Timestamp.Add(time.Minute)
+	if again := submit(later); again != first {
+		t.Fatalf("identical proposal got a new ID: %s vs %s", again, first)
+	}
+	if complete, _, err := v.CheckVerificationStatus(first); err != nil || !complete {
+		t.Fatalf("resubmission reset the pending responses: complete=%v err=%v", complete, err)
+	}
+
+	retry := proposal()
+	retry.Attempt = 1
+	if id := submit(retry); id == first {
+		t.Fatal("expected an explicit retry attempt to get a distinct ID")
+	}
+
+	hijack := proposal()
+	hijack.RequestID = first
+	hijack.Proof = []byte("other proof")
+	if _, err := v.RequestVerification(context.Background(), hijack); !errors.Is(err, ErrRequestIDConflict) {
+		t.Fatalf("expected ErrRequestIDConflict, got %v", err)
+	}
+
+	vp := NewVerificationProtocol("node-main", 1, time.Second)
+	a, err := vp.RequestVerification(context.Background(), []byte("data"), []byte("sig"))
+	if err != nil {
+		t.Fatalf("protocol request: %v", err)
+	}
+	b, err := vp.RequestVerification(context.Background(), []byte("data"), []byte("sig-2"))
+	if err != nil || a != b {
+		t.Fatalf("expected identical data to deduplicate, got %s and %s (%v)", a, b, err)
+	}
+	c, err := vp.RequestVerificationAttempt(context.Background(), 0, 1, []byte("data"), []byte("sig"))
+	if err != nil || c == a {
+		t.Fatalf("expected retry attempt to get a distinct ID, got %s (%v)", c, err)
+	}
+}
+
+func TestRequestIDsDistinctForSameSecondProposals(t *testing.T) {
+	v := NewVerifier("node-main", 1, time.Second)
+	now := time.Now().Truncate(time.Second)
+	seen := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		id, err := v.RequestVerification(context.Background(), &ModelVerificationRequest{
+			ModelWeights: []byte(fmt.Sprintf("weights-%d", i)),
+			ProposerID:   "node-b",
+			Round:        3,
+			Timestamp:    now,
+		})
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if seen[id] {
+			t.Fatalf("proposal %d collided with an earlier proposal in the same second", i)
+		}
+		seen[id] = true
+	}
+
+	// Length prefixing keeps field boundaries: shifting bytes between
+	// proposer and payload must not produce the same ID.
+	if RequestID("ab", 1, PayloadDigest([]byte("c")), 0) == RequestID("a", 1, PayloadDigest([]byte("bc")), 0) {
+		t.Fatal("request ID encoding is ambiguous")
+	}
+	if PayloadDigest([]byte("ab"), []byte("c")) == PayloadDigest([]byte("a"), []byte("bc")) {
+		t.Fatal("payload digest encoding is ambiguous")
+	}
+}
+
+func TestRegisterPeerRequiresVerifiedAttestationWhenGated(t *testing.T) {
+	v := NewVerifier("node-main", 1, time.Second)
+	var checked []string
+	v.SetAttestationVerifier(func(envelope *protocol.AttestationEnvelope) error {
+		checked = append(checked, envelope.NodeID)
+		if envelope.Nonce != "bm9uY2U=" {
+			return errors.New("stale nonce")
+		}
+		return nil
+	})
+
+	envelopeFor := func(nodeID, nonce string) []byte {
+		envelope := &protocol.AttestationEnvelope{
+			Version:     protocol.AttestationEnvelopeVersion,
+			NodeID:      nodeID,
+			Timestamp:   time.Now(),
+			Quote:       "cXVvdGU=",
+			PCRs:        map[string]string{"0": "00"},
+			Nonce:       nonce,
+			Signature:   "c2ln",
+			AKPublicKey: "-----BEGIN PUBLIC KEY-----\n-----END PUBLIC KEY-----\n",
+		}
+		encoded, err := envelope.Marshal()
+		if err != nil {
+			t.Fatalf("marshal envelope: %v", err)
+		}
+		return encoded
+	}
+
+	cases := map[string][]byte{
+		"missing":    nil,
+		"malformed":  []byte("{}"),
+		"other node": envelopeFor("peer-b", "bm9uY2U="),
+		"rejected":   envelopeFor("peer-a", "c3RhbGU="),
+	}
+	for name, attestation := range cases {
+		if err := v.RegisterPeer(&PeerDetail{ID: "peer-a", TPMAttestation: attestation}); !errors.Is(err, ErrAttestationRejected) {
+			t.Fatalf("%s: expected ErrAttestationRejected, got %v", name, err)
+		}
+	}
+	if len(v.GetActivePeers()) != 0 {
+		t.Fatal("expected no peers admitted without a verified attestation")
+	}
+
+	if err := v.RegisterPeer(&PeerDetail{ID: "peer-a", TPMAttestation: envelopeFor("peer-a", "bm9uY2U=")}); err != nil {
+		t.Fatalf("expected attested peer to register: %v", err)
+	}
+	if len(checked) != 2 {
+		t.Fatalf("expected the verifier to see only well-formed envelopes for the peer, got %v", checked)
+	}
+}
+
+func TestSubmitVerificationRejectsTamperedResponses(t *testing.T) {
+	v := NewVerifier("node-main", 1, time.Second)
+	channel := registerSigningPeer(t, v, &PeerDetail{ID: "peer-a"})
+	registerSigningPeer(t, v, &PeerDetail{ID: "relay"})
+	if err := v.RegisterPeer(&PeerDetail{ID: "keyless"}); err != nil {
+		t.Fatalf("register keyless: %v", err)
+	}
+	if err := v.RegisterPeer(&PeerDetail{ID: "bad-key", PublicKey: []byte("not a key")}); !errors.Is(err, ErrInvalidPeer) {
+		t.Fatalf("expected ErrInvalidPeer for a malformed key, got %v", err)
+	}
+	requestID, err := v.RequestVerification(context.Background(), &ModelVerificationRequest{ProposerID: "node-b", Round: 1})
+	if err != nil {
+		t.Fatalf("request verification: %v", err)
+	}
+
+	// The verifier rejected the update; a relay flips the verdict in transit.
+	flipped := signed(t, channel, &ModelVerificationResponse{
+		RequestID:  requestID,
+		VerifierID: "peer-a",
+		Valid:      false,
+		ReasonCode: "proof_invalid",
+		Timestamp:  time.Now(),
+	})
+	flipped.Valid = true
+	if err := v.SubmitVerification(context.Background(), "relay", flipped); !errors.Is(err, ErrResponseSignature) || Retryable(err) {
+		t.Fatalf("expected non-retryable ErrResponseSignature for a flipped verdict, got %v", err)
+	}
+	unsigned := &ModelVerificationResponse{RequestID: requestID, VerifierID: "peer-a", Valid: true, Timestamp: time.Now()}
+	if err := v.SubmitVerification(context.Background(), "relay", unsigned); !errors.Is(err, ErrResponseSignature) {
+		t.Fatalf("expected ErrResponseSignature for an unsigned response, got %v", err)
+	}
+	// A stranger forging a response in the verifier's name costs the
+	// verifier nothing.
+	if err := v.SubmitVerification(context.Background(), "stranger", unsigned); !errors.Is(err, ErrResponseSignature) {
+		t.Fatalf("expected ErrResponseSignature for a forged response, got %v", err)
+	}
+	keyless := &ModelVerificationResponse{RequestID: requestID, VerifierID: "keyless", Valid: true, Timestamp: time.Now()}
+	if err := v.SubmitVerification(context.Background(), "keyless", keyless); !errors.Is(err, ErrResponseSignature) {
+		t.Fatalf("expected ErrResponseSignature for a verifier without a key, got %v", err)
+	}
+	if complete, _, _ := v.CheckVerificationStatus(requestID); complete {
+		t.Fatal("rejected responses must not count toward the committee")
+	}
+
+	relayScore, _ := v.Reputation("relay")
+	if relayScore >= 1.0-0.3 {
+		t.Fatalf("expected the relay penalized twice, reputation %.2f", relayScore)
+	}
+	if score, _ := v.Reputation("peer-a"); score != 1.0 {
+		t.Fatalf("verifier reputation changed to %.2f by responses it did not sign", score)
+	}
+	history, err := v.GetReputationHistory("relay", 0)
+	if err != nil || history[len(history)-1].Cause != CauseForgedResponse {
+		t.Fatalf("expected a forged_response history entry, got %v (%v)", history, err)
+	}
+
+	// Sub-second precision is not signed, so dropping it in transit is
+	// harmless; the honest verdict still counts.
+	honest := signed(t, channel, &ModelVerificationResponse{
+		RequestID:  requestID,
+		VerifierID: "peer-a",
+		Valid:      true,
+		Timestamp:  time.Now(),
+	})
+	honest.Timestamp = honest.Timestamp.Truncate(time.Second)
+	if err := v.SubmitVerification(context.Background(), "relay", honest); err != nil {
+		t.Fatalf("honest relayed response: %v", err)
+	}
+	if complete, _, err := v.CheckVerificationStatus(requestID); err != nil || !complete {
+		t.Fatalf("expected the signed response to complete the request: complete=%v err=%v", complete, err)
+	}
+}
+
+func TestInboundGuardBoundsHostilePayloads(t *testing.T) {
+	network := NewNetwork("node-main", 1, time.Second)
+	network.AddPeer("attacker", "10.0.0.9:9000", 1.0)
+	limits := DefaultInboundLimits()
+	limits.MaxDecompressedBytes = 1 << 20
+	guard := NewInboundGuard(limits)
+	network.SetInboundGuard(guard)
+
+	if err := guard.CheckPayload("attacker", KindVote, make([]byte, 5<<10)); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge for an oversized vote, got %v", err)
+	}
+	if err := guard.CheckPayload("attacker", KindModelChunk, make([]byte, 5<<10)); err != nil {
+		t.Fatalf("model chunk within its limit: %v", err)
+	}
+
+	// 256 MiB of zeros compresses by three orders of magnitude.
+	var bomb bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&bomb, gzip.BestCompression)
+	if err != nil {
+		t.Fatalf("gzip writer: %v", err)
+	}
+	zeros := make([]byte, 1<<20)
+	for i := 0; i < 256; i++ {
+		if _, err := writer.Write(zeros); err != nil {
+			t.Fatalf("write bomb: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close bomb: %v", err)
+	}
+	if ratio := (256 << 20) / bomb.Len(); ratio < 500 {
+		t.Fatalf("bomb ratio only %d:1", ratio)
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	if _, err := guard.Decompress("attacker", bomb.Bytes()); !errors.Is(err, ErrDecompressionLimit) {
+		t.Fatalf("expected ErrDecompressionLimit, got %v", err)
+	}
+	if err := guard.CheckWeights("attacker", math.MaxInt32, 1000); !errors.Is(err, ErrWeightsTooLong) {
+		t.Fatalf("expected ErrWeightsTooLong, got %v", err)
+	}
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 8<<20 {
+		t.Fatalf("rejecting hostile payloads allocated %d bytes", allocated)
+	}
+
+	var small bytes.Buffer
+	writer = gzip.NewWriter(&small)
+	_, _ = writer.Write([]byte("honest status"))
+	_ = writer.Close()
+	if out, err := guard.Decompress("attacker", small.Bytes()); err != nil || string(out) != "honest status" {
+		t.Fatalf("honest payload: %q, %v", out, err)
+	}
+
+	peer, _ := network.GetPeer("attacker")
+	if violations, _ := peer.Metadata["inbound_violations"].(uint64); violations != 3 {
+		t.Fatalf("expected 3 recorded violations, got %d", violations)
+	}
+	if math.Abs(peer.Reputation-0.7) > 1e-9 {
+		t.Fatalf("expected the sender penalized to 0.7, got %.2f", peer.Reputation)
+	}
+}
+
+// fragmentKeys returns the fragment key functions of node-a and peer-b,
+// backed by secure channels registered with each other.
+func fragmentKeys(t *testing.T) (FragmentKeyFunc, FragmentKeyFunc) {
+	t.Helper()
+	node, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("new channel: %v", err)
+	}
+	peer, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("new channel: %v", err)
+	}
+	for _, pair := range []struct {
+		owner, other *crypto.SecureChannel
+		otherID      string
+	}{{node, peer, "peer-b"}, {peer, node, "node-a"}} {
+		pem, err := pair.other.ExportPublicKey()
+		if err != nil {
+			t.Fatalf("export key: %v", err)
+		}
+		key, err := crypto.ImportPublicKey(pem)
+		if err != nil {
+			t.Fatalf("import key: %v", err)
+		}
+		if err := pair.owner.RegisterPeer(pair.otherID, key); err != nil {
+			t.Fatalf("register %s: %v", pair.otherID, err)
+		}
+	}
+	return node.FragmentMACKey, peer.FragmentMACKey
+}
+
+type captureSender struct {
+	mu     sync.Mutex
+	frames []OutboundMessage
+}
+
+func (s *captureSender) Send(_ context.Context, _ string, msg OutboundMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frames = append(s.frames, msg)
+	return nil
+}
+
+// shuffled returns frames reordered: odd positions first, then even ones
+// in reverse.
+func shuffled(frames []OutboundMessage) []OutboundMessage {
+	out := make([]OutboundMessage, 0, len(frames))
+	for i := 1; i < len(frames); i += 2 {
+		out = append(out, frames[i])
+	}
+	for i := (len(frames) - 1) &^ 1; i >= 0; i -= 2 {
+		out = append(out, frames[i])
+	}
+	return out
+}
+
+func TestFragmentedGossipSurvivesLossAndReordering(t *testing.T) {
+	nodeKeys, peerKeys := fragmentKeys(t)
+	payload := make([]byte, 1<<20)
+	if _, err := rand.Read(payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	fragmenter, err := NewFragmenter(16<<10, nodeKeys)
+	if err != nil {
+		t.Fatalf("new fragmenter: %v", err)
+	}
+
+	// The transport splits the model but sends the status message whole.
+	sender := &captureSender{}
+	transport := NewTransport(sender, QueueConfig{})
+	transport.SetFragmenter(fragmenter)
+	for _, msg := range []OutboundMessage{
+		{Topic: "model/round-3", Payload: payload, Priority: PriorityCommit},
+		{Topic: "status", Payload: []byte("alive"), Priority: PriorityStatus},
+	} {
+		if _, err := transport.Enqueue("peer-b", msg); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	if err := transport.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	fragments := len(sender.frames) - 1
+	if fragments < (1<<20)/(16<<10) {
+		t.Fatalf("1MB at a 16KB MTU became %d fragments", fragments)
+	}
+	for _, frame := range sender.frames {
+		if len(frame.Payload) > 16<<10 {
+			t.Fatalf("%s frame of %d bytes exceeds the MTU", frame.Topic, len(frame.Payload))
+		}
+	}
+	stats := transport.GetQueueStats()["peer-b"].(map[string]interface{})
+	if stats["fragments"] != uint64(fragments) || stats["sent"] != uint64(2) {
+		t.Fatalf("queue stats = %v, want %d fragments of 2 messages", stats, fragments)
+	}
+
+	// Reordered, with duplicates, the model reassembles once.
+	reassembler := NewReassembler(peerKeys, time.Second, 0)
+	delivered := shuffled(sender.frames)
+	delivered = append(delivered, delivered[3], delivered[7])
+	var whole []OutboundMessage
+	for _, frame := range delivered {
+		msg, done, err := reassembler.Accept("node-a", frame)
+		if err != nil {
+			t.Fatalf("accept %s: %v", frame.Topic, err)
+		}
+		if done {
+			whole = append(whole, msg)
+		}
+	}
+	if len(whole) != 2 || whole[0].Topic != "status" || whole[1].Topic != "model/round-3" || !bytes.Equal(whole[1].Payload, payload) {
+		t.Fatalf("reassembled %d messages, want the status and the intact model", len(whole))
+	}
+	if got := reassembler.Stats(); got.Reassembled != 1 || got.Duplicates != 2 || got.Pending != 0 {
+		t.Fatalf("stats = %+v", got)
+	}
+
+	// With one fragment lost, the partial model is dropped at the timeout.
+	lossy, err := fragmenter.Split("peer-b", OutboundMessage{Topic: "model/round-4", Payload: payload})
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	now := time.Now()
+	reassembler.now = func() time.Time { return now }
+	var drops []string
+	reassembler.SetDropHandler(func(peerID string, _ uint64, received, total int) {
+		drops = append(drops, fmt.Sprintf("%s %d/%d", peerID, received, total))
+	})
+	for i, frame := range shuffled(lossy) {
+		if i == 10 {
+			continue
+		}
+		if _, done, err := reassembler.Accept("node-a", frame); err != nil || done {
+			t.Fatalf("accept fragment: done=%v err=%v", done, err)
+		}
+	}
+	if got := reassembler.Stats(); got.Pending != 1 {
+		t.Fatalf("pending = %d, want the partial model", got.Pending)
+	}
+	if expired := reassembler.Expire(); expired != 0 {
+		t.Fatalf("expired %d before the timeout", expired)
+	}
+	now = now.Add(2 * time.Second)
+	if expired := reassembler.Expire(); expired != 1 {
+		t.Fatalf("expired %d, want the partial model", expired)
+	}
+	want := fmt.Sprintf("node-a %d/%d", len(lossy)-1, len(lossy))
+	if len(drops) != 1 || drops[0] != want {
+		t.Fatalf("drops = %v, want [%s]", drops, want)
+	}
+	if got := reassembler.Stats(); got.Expired != 1 || got.Pending != 0 {
+		t.Fatalf("stats after timeout = %+v", got)
+	}
+}
+
+func TestReassemblerRejectsSplicedFragments(t *testing.T) {
+	nodeKeys, peerKeys := fragmentKeys(t)
+	fragmenter, err := NewFragmenter(MinFragmentMTU, nodeKeys)
+	if err != nil {
+		t.Fatalf("new fragmenter: %v", err)
+	}
+	split := func(topic string, fill byte) []OutboundMessage {
+		fragments, err := fragmenter.Split("peer-b", OutboundMessage{Topic: topic, Payload: bytes.Repeat([]byte{fill}, 1000)})
+		if err != nil {
+			t.Fatalf("split: %v", err)
+		}
+		return fragments
+	}
+	first, second := split("model", 'a'), split("model", 'b')
+	reassembler := NewReassembler(peerKeys, time.Minute, 0)
+
+	// A fragment of the second message carrying the first's ID.
+	spliced := second[1]
+	spliced.Payload = append([]byte(nil), spliced.Payload...)
+	copy(spliced.Payload[:8], first[0].Payload[:8])
+	// A genuine fragment moved to another topic.
+	moved := first[1]
+	moved.Topic = "fragment/votes"
+	// A genuine fragment moved to another position.
+	reindexed := first[2]
+	reindexed.Payload = append([]byte(nil), reindexed.Payload...)
+	reindexed.Payload[11]--
+	for _, forged := range []OutboundMessage{spliced, moved, reindexed} {
+		if _, _, err := reassembler.Accept("node-a", forged); !errors.Is(err, ErrFragmentAuth) {
+			t.Fatalf("expected ErrFragmentAuth, got %v", err)
+		}
+	}
+	// Only the sender's pairwise key authenticates its fragments.
+	if _, _, err := reassembler.Accept("stranger", first[0]); !errors.Is(err, ErrFragmentAuth) {
+		t.Fatalf("fragment from an unknown peer: expected ErrFragmentAuth, got %v", err)
+	}
+
+	var whole OutboundMessage
+	for _, frame := range first {
+		msg, done, err := reassembler.Accept("node-a", frame)
+		if err != nil {
+			t.Fatalf("accept: %v", err)
+		}
+		if done {
+			whole = msg
+		}
+	}
+	if !bytes.Equal(whole.Payload, bytes.Repeat([]byte{'a'}, 1000)) {
+		t.Fatal("forgeries corrupted the genuine message")
+	}
+	if got := reassembler.Stats(); got.Rejected != 4 || got.Reassembled != 1 {
+		t.Fatalf("stats = %+v", got)
+	}
+}
+
+func TestReassemblerBoundsFragmentCounts(t *testing.T) {
+	nodeKeys, peerKeys := fragmentKeys(t)
+	key, err := nodeKeys("peer-b")
+	if err != nil {
+		t.Fatalf("key: %v", err)
+	}
+	fragment := func(messageID uint64, total int) OutboundMessage {
+		return OutboundMessage{Topic: fragmentTopicPrefix + "model", Payload: sealFragment(key, "model", messageID, 0, total, []byte("chunk"))}
+	}
+	// 4 KiB holds at most 20 fragments of the smallest MTU.
+	reassembler := NewReassembler(peerKeys, time.Minute, 4<<10)
+
+	// An authenticated peer claiming a huge count is refused outright.
+	if _, _, err := reassembler.Accept("node-a", fragment(1, 1<<30)); !errors.Is(err, ErrMalformedPayload) {
+		t.Fatalf("forged count: expected ErrMalformedPayload, got %v", err)
+	}
+	if _, _, err := reassembler.Accept("node-a", fragment(2, 21)); !errors.Is(err, ErrMalformedPayload) {
+		t.Fatalf("count past the buffer: expected ErrMalformedPayload, got %v", err)
+	}
+	// Counts the buffer could hold wait for their fragments.
+	for id := uint64(3); id < 10; id++ {
+		if _, done, err := reassembler.Accept("node-a", fragment(id, 20)); err != nil || done {
+			t.Fatalf("accept: done=%v err=%v", done, err)
+		}
+	}
+	if got := reassembler.Stats(); got.Pending != 7 || got.Rejected != 2 {
+		t.Fatalf("stats = %+v", got)
+	}
+}
+
+func TestReceiverReassemblesBeforeDispatch(t *testing.T) {
+	nodeKeys, peerKeys := fragmentKeys(t)
+	fragmenter, err := NewFragmenter(MinFragmentMTU, nodeKeys)
+	if err != nil {
+		t.Fatalf("new fragmenter: %v", err)
+	}
+	payload := bytes.Repeat([]byte{'m'}, 1000)
+	fragments, err := fragmenter.Split("peer-b", OutboundMessage{Topic: "model", Payload: payload})
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+
+	receiver := NewReceiver()
+	var delivered [][]byte
+	receiver.Handle("model", func(_ context.Context, from string, got []byte) error {
+		if from != "node-a" {
+			t.Fatalf("delivered from %s", from)
+		}
+		delivered = append(delivered, got)
+		return nil
+	})
+	ctx := context.Background()
+	if err := receiver.Deliver(ctx, "node-a", fragments[0]); !errors.Is(err, ErrUnknownTopic) {
+		t.Fatalf("fragment without a reassembler: expected ErrUnknownTopic, got %v", err)
+	}
+
+	receiver.SetReassembler(NewReassembler(peerKeys, time.Minute, 0))
+	for _, frame := range shuffled(fragments) {
+		if err := receiver.Deliver(ctx, "node-a", frame); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+	}
+	if len(delivered) != 1 || !bytes.Equal(delivered[0], payload) {
+		t.Fatalf("delivered %d messages, want the reassembled model once", len(delivered))
+	}
+	if err := receiver.Deliver(ctx, "stranger", fragments[0]); !errors.Is(err, ErrFragmentAuth) {
+		t.Fatalf("unauthenticated fragment: expected ErrFragmentAuth, got %v", err)
+	}
+}
+
+func TestQueryPeersCursorStableUnderConcurrentInserts(t *testing.T) {
+	n := NewNetwork("node-1", 1, time.Second)
+	const existing = 60
+	for i := 0; i < existing; i++ {
+		n.AddPeer(fmt.Sprintf("peer-%03d", i), "addr", 0.5)
+	}
+
+	// Peers join while the walk runs; their IDs interleave with existing
+	// ones, both before and after the cursor.
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			n.AddPeer(fmt.Sprintf("peer-%03d-late-%d", i%existing, i), "addr", 0.5)
+		}
+	}()
+
+	seen := make(map[string]int)
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10*existing {
+			t.Fatal("walk did not terminate")
+		}
+		page := n.QueryPeers(PeerFilter{}, cursor, 7)
+		for _, peer := range page.Peers {
+			id := peer["id"].(string)
+			if id <= cursor {
+				t.Fatalf("page after %q returned %q", cursor, id)
+			}
+			seen[id]++
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	close(stop)
+	wg.Wait()
+
+	for id, count := range seen {
+		if count != 1 {
+			t.Fatalf("peer %s returned %d times", id, count)
+		}
+	}
+	for i := 0; i < existing; i++ {
+		if seen[fmt.Sprintf("peer-%03d", i)] != 1 {
+			t.Fatalf("peer-%03d missing from walk", i)
+		}
+	}
+}
+
+func TestQueryPeersFilters(t *testing.T) {
+	n := NewNetwork("node-1", 1, time.Second)
+	for i := 0; i < 10; i++ {
+		n.AddPeer(fmt.Sprintf("peer-%d", i), "addr", float64(i)/10)
+	}
+	n.mu.Lock()
+	n.peers["peer-1"].Connected = false
+	n.peers["peer-2"].LastSeen = time.Now().Add(-time.Hour)
+	n.mu.Unlock()
+
+	ids := func(page PeerPage) []string {
+		out := make([]string, 0, len(page.Peers))
+		for _, peer := range page.Peers {
+			out = append(out, peer["id"].(string))
+		}
+		return out
+	}
+	inactive := n.QueryPeers(PeerFilter{Status: PeerStatusInactive}, "", 0)
+	if got := ids(inactive); fmt.Sprint(got) != "[peer-1 peer-2]" || inactive.Total != 2 {
+		t.Fatalf("inactive peers = %v (total %d)", got, inactive.Total)
+	}
+
+	low, high := 0.25, 0.65
+	ranged := n.QueryPeers(PeerFilter{Status: PeerStatusActive, MinReputation: &low, MaxReputation: &high}, "", 2)
+	if got := ids(ranged); fmt.Sprint(got) != "[peer-3 peer-4]" || ranged.Total != 4 || ranged.NextCursor != "peer-4" {
+		t.Fatalf("first ranged page = %v (total %d, next %q)", got, ranged.Total, ranged.NextCursor)
+	}
+	ranged = n.QueryPeers(PeerFilter{Status: PeerStatusActive, MinReputation: &low, MaxReputation: &high}, ranged.NextCursor, 2)
+	if got := ids(ranged); fmt.Sprint(got) != "[peer-5 peer-6]" || ranged.NextCursor != "" {
+		t.Fatalf("second ranged page = %v (next %q)", got, ranged.NextCursor)
+	}
+}
+
+func TestParsePeerAddressTagsFamilies(t *testing.T) {
+	for address, family := range map[string]AddressFamily{
+		"203.0.113.7:4001":        FamilyIPv4,
+		"[2001:db8::7]:4001":      FamilyIPv6,
+		"[::ffff:203.0.113.7]:80": FamilyIPv4,
+		"edge-7.example:4001":     FamilyDNS,
+	} {
+		if parsed, err := ParsePeerAddress(address); err != nil || parsed.Family != family {
+			t.Fatalf("ParsePeerAddress(%q) = %+v, %v; want %s", address, parsed, err, family)
+		}
+	}
+	if _, err := ParsePeerAddress("2001:db8::7"); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress for a bare IPv6 host, got %v", err)
+	}
+}
+
+func TestDialPeerAddressesRacesFamilies(t *testing.T) {
+	n := NewNetwork("regional-1", 1, time.Second)
+	n.AddPeer("edge-1", "", 1)
+	if err := n.SetPeerAddresses("edge-1", []string{"203.0.113.7:4001", "[2001:db8::7]:4001"}); err != nil {
+		t.Fatalf("set addresses: %v", err)
+	}
+
+	// IPv6 is tried first but black-holed; IPv4 answers once the stagger
+	// starts it, and the IPv6 attempt is cancelled.
+	var mu sync.Mutex
+	var tried []AddressFamily
+	cancelled := make(chan struct{})
+	dialer := &Dialer{Stagger: 20 * time.Millisecond, Dial: func(ctx context.Context, address PeerAddress) (net.Conn, error) {
+		mu.Lock()
+		tried = append(tried, address.Family)
+		mu.Unlock()
+		if address.Family == FamilyIPv6 {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		}
+		local, remote := net.Pipe()
+		remote.Close()
+		return local, nil
+	}}
+	conn, err := n.DialPeerAddresses(context.Background(), "edge-1", dialer)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	conn.Close()
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("losing IPv6 attempt was not cancelled")
+	}
+	mu.Lock()
+	if len(tried) != 2 || tried[0] != FamilyIPv6 {
+		t.Fatalf("attempt order = %v, want IPv6 first", tried)
+	}
+	mu.Unlock()
+
+	peer, _ := n.GetPeer("edge-1")
+	if peer.Address != "203.0.113.7:4001" || peer.Addresses[0].Reachability <= DefaultReachability || peer.Addresses[1].Reachability != DefaultReachability {
+		t.Fatalf("peer after dial = %+v", peer)
+	}
+	// The address that answered is now tried first.
+	if ordered := orderAddresses(peer.Addresses); ordered[0].Family != FamilyIPv4 {
+		t.Fatalf("order after a successful IPv4 dial = %+v", ordered)
+	}
+}
+
+func TestUnreachablePeerVerifiesThroughRelay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	n := NewNetwork("regional-1", 1, time.Second)
+	v := NewVerifier("regional-1", 1, time.Second)
+	relay := NewRelay("regional-1", v)
+
+	// Both edges sit behind carrier-grade NAT: no address answers a dial.
+	refused := &Dialer{Stagger: time.Millisecond, Dial: func(context.Context, PeerAddress) (net.Conn, error) {
+		return nil, errors.New("connection timed out")
+	}}
+	channels := make(map[string]*crypto.SecureChannel)
+	var serving sync.WaitGroup
+	for _, edge := range []string{"edge-1", "edge-2"} {
+		n.AddPeer(edge, "", 1)
+		if err := n.SetPeerAddresses(edge, []string{"100.64.0.7:4001", "[2001:db8::7]:4001"}); err != nil {
+			t.Fatalf("set addresses: %v", err)
+		}
+		if _, err := n.DialPeerAddresses(ctx, edge, refused); !errors.Is(err, ErrPeerUnreachable) || !Retryable(err) {
+			t.Fatalf("dial %s: expected a retryable ErrPeerUnreachable, got %v", edge, err)
+		}
+		channel := registerSigningPeer(t, v, &PeerDetail{ID: edge})
+		channels[edge] = channel
+
+		// The edge dials out to its regional aggregator instead and
+		// answers verification requests over that connection.
+		edgeSide, regionalSide := net.Pipe()
+		if err := relay.Attach(edge, regionalSide); err != nil {
+			t.Fatalf("attach %s: %v", edge, err)
+		}
+		if err := n.MarkRelayed(edge, "regional-1"); err != nil {
+			t.Fatalf("mark %s relayed: %v", edge, err)
+		}
+		serving.Add(1)
+		go func(edge string) {
+			defer serving.Done()
+			err := ServeRelay(ctx, edgeSide, func(_ context.Context, req *ModelVerificationRequest) (*ModelVerificationResponse, error) {
+				return signed(t, channel, &ModelVerificationResponse{RequestID: req.RequestID, VerifierID: edge, Valid: true, Timestamp: time.Now()}), nil
+			})
+			if err != nil {
+				t.Errorf("serve relay for %s: %v", edge, err)
+			}
+		}(edge)
+	}
+	if got := relay.Relayed(); fmt.Sprint(got) != "[edge-1 edge-2]" {
+		t.Fatalf("relayed peers = %v", got)
+	}
+	if page := n.QueryPeers(PeerFilter{}, "", 1); page.Peers[0]["relayed_via"] != "regional-1" {
+		t.Fatalf("peer summary = %v", page.Peers[0])
+	}
+
+	req := &ModelVerificationRequest{ModelWeights: []byte("weights"), ProposerID: "regional-1", Round: 1}
+	if _, err := v.RequestVerification(ctx, req); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if err := relay.ForwardVerification(ctx, "edge-1", req); err != nil {
+		t.Fatalf("forward to edge-1: %v", err)
+	}
+	if complete, _, err := v.CheckVerificationStatus(req.RequestID); err != nil || !complete {
+		t.Fatalf("relayed verification: complete=%v err=%v", complete, err)
+	}
+
+	// A committee of two admits at most one relayed verifier, so the second
+	// relayed edge does not complete it; a direct verifier does.
+	if err := v.SetCommitteePolicy(ArtifactZKProof, CommitteePolicy{Size: 2, ConfidenceThreshold: 0.66, MaxRelayedFraction: 0.5}); err != nil {
+		t.Fatalf("set policy: %v", err)
+	}
+	proof := &ModelVerificationRequest{ArtifactType: ArtifactZKProof, Proof: []byte("proof"), ProposerID: "regional-1", Round: 1}
+	if _, err := v.RequestVerification(ctx, proof); err != nil {
+		t.Fatalf("request proof: %v", err)
+	}
+	for _, edge := range []string{"edge-1", "edge-2"} {
+		if err := relay.ForwardVerification(ctx, edge, proof); err != nil {
+			t.Fatalf("forward proof to %s: %v", edge, err)
+		}
+	}
+	if complete, _, _ := v.CheckVerificationStatus(proof.RequestID); complete {
+		t.Fatal("two relayed verifiers filled a committee that admits one")
+	}
+	direct := registerSigningPeer(t, v, &PeerDetail{ID: "regional-2"})
+	if err := v.SubmitVerification(ctx, "regional-2", signed(t, direct, &ModelVerificationResponse{RequestID: proof.RequestID, VerifierID: "regional-2", Valid: true})); err != nil {
+		t.Fatalf("direct response: %v", err)
+	}
+	if complete, _, err := v.CheckVerificationStatus(proof.RequestID); err != nil || !complete {
+		t.Fatalf("committee with a direct verifier: complete=%v err=%v", complete, err)
+	}
+
+	relay.Detach("edge-2")
+	if _, err := relay.Forward(ctx, "edge-2", req); !errors.Is(err, ErrRelayUnavailable) {
+		t.Fatalf("forward after detach: expected ErrRelayUnavailable, got %v", err)
+	}
+	relay.Detach("edge-1")
+	serving.Wait()
+}
+
+func TestSpotCheckDecoysAuditVerifiersOnly(t *testing.T) {
+	v := NewVerifier("node-main", 1, time.Second)
+	lazy := registerSigningPeer(t, v, &PeerDetail{ID: "lazy"})
+	honest := registerSigningPeer(t, v, &PeerDetail{ID: "honest"})
+	if err := v.SetSpotChecks(SpotCheckConfig{Rate: 1}); err != nil {
+		t.Fatalf("set spot checks: %v", err)
+	}
+	req := &ModelVerificationRequest{ModelWeights: []byte("weights"), Proof: []byte("proof"), ProposerID: "node-a", Round: 1}
+	if _, err := v.RequestVerification(context.Background(), req); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+
+	decoyFor := func(verifierID string) *ModelVerificationRequest {
+		t.Helper()
+		assigned, err := v.AssignBatch(verifierID, []*ModelVerificationRequest{req})
+		if err != nil {
+			t.Fatalf("assign %s: %v", verifierID, err)
+		}
+		if len(assigned) != 2 {
+			t.Fatalf("assigned %d requests to %s, want the request and a decoy", len(assigned), verifierID)
+		}
+		for _, candidate := range assigned {
+			if candidate.RequestID != req.RequestID {
+				if len(candidate.Proof) != len(req.Proof) || bytes.Equal(candidate.Proof, req.Proof) {
+					t.Fatalf("decoy proof %q is not a perturbed copy of %q", candidate.Proof, req.Proof)
+				}
+				return candidate
+			}
+		}
+		t.Fatalf("no decoy assigned to %s", verifierID)
+		return nil
+	}
+	lazyDecoy, honestDecoy := decoyFor("lazy"), decoyFor("honest")
+
+	// A decoy is answerable only by the verifier it was sent to.
+	if err := v.SubmitVerification(context.Background(), "honest", signed(t, honest, &ModelVerificationResponse{RequestID: lazyDecoy.RequestID, VerifierID: "honest"})); !errors.Is(err, ErrUnknownRequest) {
+		t.Fatalf("answering another verifier's decoy: expected ErrUnknownRequest, got %v", err)
+	}
+	if err := v.SubmitVerification(context.Background(), "lazy", signed(t, lazy, &ModelVerificationResponse{RequestID: lazyDecoy.RequestID, VerifierID: "lazy", Valid: true})); err != nil {
+		t.Fatalf("lazy decoy response: %v", err)
+	}
+	if err := v.SubmitVerification(context.Background(), "honest", signed(t, honest, &ModelVerificationResponse{RequestID: honestDecoy.RequestID, VerifierID: "honest", Valid: false})); err != nil {
+		t.Fatalf("honest decoy response: %v", err)
+	}
+
+	if !v.Flagged("lazy") || v.Flagged("honest") {
+		t.Fatalf("audits = %+v", v.Audits())
+	}
+	if rep, _ := v.Reputation("lazy"); rep != 0.1 {
+		t.Fatalf("lazy reputation %f, want the 0.1 floor", rep)
+	}
+	if rep, _ := v.Reputation("honest"); rep <= 1 {
+		t.Fatalf("honest reputation %f, want a boost", rep)
+	}
+	// Decoy verdicts never count toward the real request.
+	if passed, _, err := v.CheckVerificationStatus(req.RequestID); passed || err != nil {
+		t.Fatalf("real request passed on decoy responses: passed=%v err=%v", passed, err)
+	}
+	if _, _, err := v.CheckVerificationStatus(lazyDecoy.RequestID); !errors.Is(err, ErrUnknownRequest) {
+		t.Fatalf("decoy status: expected ErrUnknownRequest, got %v", err)
+	}
+}
+
+func TestFanoutControllerIsAIMD(t *testing.T) {
+	collector := monitoring.NewCollector(100)
+	controller, err := NewFanoutController(FanoutConfig{RoundBudget: 4 * time.Second, Max: 8, Collector: collector})
+	if err != nil {
+		t.Fatalf("new controller: %v", err)
+	}
+	if controller.Fanout() != DefaultGossipFanout || controller.Goal() != 400*time.Millisecond {
+		t.Fatalf("fanout %d, goal %s", controller.Fanout(), controller.Goal())
+	}
+
+	for i, step := range []struct {
+		sample PropagationSample
+		fanout int
+		reason string
+	}{
+		{PropagationSample{Propagation: 500 * time.Millisecond, Reached: true}, 7, FanoutRaised},
+		{PropagationSample{}, 8, FanoutRaised},
+		{PropagationSample{}, 8, FanoutHeld},
+		{PropagationSample{Propagation: 300 * time.Millisecond, Reached: true}, 8, FanoutHeld},
+		{PropagationSample{Propagation: 100 * time.Millisecond, Reached: true}, 6, FanoutLowered},
+		{PropagationSample{Propagation: 100 * time.Millisecond, Reached: true}, 5, FanoutLowered},
+		{PropagationSample{Propagation: 100 * time.Millisecond, Reached: true}, 4, FanoutLowered},
+		{PropagationSample{Propagation: 100 * time.Millisecond, Reached: true}, 3, FanoutLowered},
+		{PropagationSample{Propagation: 100 * time.Millisecond, Reached: true}, 2, FanoutLowered},
+		{PropagationSample{Propagation: 100 * time.Millisecond, Reached: true}, 2, FanoutHeld},
+	} {
+		step.sample.Round = i + 1
+		if decision := controller.Observe(step.sample); decision.Fanout != step.fanout || decision.Reason != step.reason {
+			t.Fatalf("round %d: fanout %d (%s), want %d (%s): %s", i+1, decision.Fanout, decision.Reason, step.fanout, step.reason, decision.Rationale)
+		}
+	}
+	if decisions := collector.GetMetricsByType(monitoring.MetricGossipFanout); len(decisions) != 10 || decisions[0].Value != 7 || decisions[0].Labels["reason"] != FanoutRaised {
+		t.Fatalf("fanout metrics = %+v", decisions)
+	}
+	// The rounds in which quorum never became visible have no propagation
+	// time to report.
+	if propagation := collector.GetMetricsByType(monitoring.MetricGossipPropagation); len(propagation) != 8 || propagation[0].Value != 0.5 {
+		t.Fatalf("propagation metrics = %+v", propagation)
+	}
+	if status := controller.Status(); status.Fanout != 2 || len(status.Decisions) != 10 {
+		t.Fatalf("status = %+v", status)
+	}
+}
+
+func TestFanoutControllerKeepsWithinMessageBudget(t *testing.T) {
+	controller, err := NewFanoutController(FanoutConfig{RoundBudget: time.Second, Initial: 4, MessageBudget: 500})
+	if err != nil {
+		t.Fatalf("new controller: %v", err)
+	}
+	slow := PropagationSample{Propagation: time.Second, Reached: true}
+
+	slow.Messages = 400
+	if decision := controller.Observe(slow); decision.Fanout != 5 || decision.Reason != FanoutRaised {
+		t.Fatalf("raise to 500 projected messages: %+v", decision)
+	}
+	slow.Messages = 500
+	if decision := controller.Observe(slow); decision.Fanout != 5 || decision.Reason != FanoutCapped {
+		t.Fatalf("raise to 600 projected messages: %+v", decision)
+	}
+	slow.Messages = 900
+	if decision := controller.Observe(slow); decision.Fanout != 4 || decision.Reason != FanoutCapped {
+		t.Fatalf("overspent budget: %+v", decision)
+	}
+
+	for _, cfg := range []FanoutConfig{
+		{},
+		{RoundBudget: time.Second, Min: 5, Max: 3},
+		{RoundBudget: time.Second, Initial: 40},
+		{RoundBudget: time.Second, Decrease: 1.5},
+		{RoundBudget: time.Second, PropagationShare: 2},
+		{RoundBudget: time.Second, MessageBudget: -1},
+	} {
+		if _, err := NewFanoutController(cfg); !errors.Is(err, ErrInvalidFanoutConfig) {
+			t.Fatalf("%+v: expected ErrInvalidFanoutConfig, got %v", cfg, err)
+		}
+	}
+}
+
+func TestPropagationMeterTimesQuorumVisibility(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	meter := NewPropagationMeter(3)
+	meter.Seen("node-1", start)
+	meter.Seen("node-1", start.Add(10*time.Millisecond))
+	if meter.Seen("node-2", start.Add(40*time.Millisecond)) {
+		t.Fatal("two distinct voters reached a quorum of three")
+	}
+	if sample := meter.Sample(1); sample.Reached {
+		t.Fatalf("sample before quorum = %+v", sample)
+	}
+	if !meter.Seen("node-3", start.Add(90*time.Millisecond)) {
+		t.Fatal("the third voter did not complete the quorum")
+	}
+	meter.Seen("node-4", start.Add(200*time.Millisecond))
+	meter.Sent(12)
+	if sample := meter.Sample(7); !sample.Reached || sample.Propagation != 90*time.Millisecond || sample.Messages != 12 || sample.Round != 7 {
+		t.Fatalf("sample = %+v", sample)
+	}
+}
+
+func TestReputationPumpingRingPlateaus(t *testing.T) {
+	v := NewVerifier("node-main", 1, time.Second)
+	now := time.Now()
+	v.now = func() time.Time { return now }
+	target := registerSigningPeer(t, v, &PeerDetail{ID: "target"})
+	organic := registerSigningPeer(t, v, &PeerDetail{ID: "organic"})
+
+	requests := 0
+	verify := func(channel *crypto.SecureChannel, verifierID, proposerID string) {
+		t.Helper()
+		requests++
+		requestID, err := v.RequestVerification(context.Background(), &ModelVerificationRequest{
+			ModelWeights: []byte(fmt.Sprintf("weights-%d", requests)),
+			ProposerID:   proposerID,
+			Round:        requests,
+		})
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		resp := signed(t, channel, &ModelVerificationResponse{RequestID: requestID, VerifierID: verifierID, Valid: true, Timestamp: now})
+		if err := v.SubmitVerification(context.Background(), verifierID, resp); err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+	}
+
+	// Two colluders feed the target 20 cheap proposals an hour for six
+	// hours, while an honest peer verifies one proposal each for five
+	// distinct proposers an hour.
+	limits := DefaultReputationVelocity()
+	previous := 1.0
+	for hour := 0; hour < 6; hour++ {
+		for i := 0; i < 20; i++ {
+			verify(target, "target", []string{"ring-a", "ring-b"}[i%2])
+		}
+		for i := 0; i < 5; i++ {
+			verify(organic, "organic", fmt.Sprintf("proposer-%d", hour*5+i))
+		}
+		score, _ := v.Reputation("target")
+		if score-previous > 2*limits.MaxPairGain+1e-9 {
+			t.Fatalf("hour %d: the ring raised the target %.2f, over two pair caps", hour, score-previous)
+		}
+		previous = score
+		now = now.Add(time.Hour)
+	}
+
+	if score, _ := v.Reputation("target"); math.Abs(score-limits.DiversityThreshold) > 1e-9 {
+		t.Fatalf("pumped reputation %.2f, want the plateau at %.2f", score, limits.DiversityThreshold)
+	}
+	if score, _ := v.Reputation("organic"); math.Abs(score-2.0) > 1e-9 {
+		t.Fatalf("organic reputation %.2f, want the 2.0 ceiling after 30 verifications", score)
+	}
+	history, err := v.GetReputationHistory("target", 0)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	limited := map[string]int{}
+	for _, sample := range history {
+		if sample.Withheld > 0 {
+			limited[sample.Cause]++
+		}
+	}
+	if limited[CausePairLimited] == 0 || limited[CauseDiversityLimited] == 0 {
+		t.Fatalf("limiting events in history: %v", limited)
+	}
+	organicHistory, _ := v.GetReputationHistory("organic", 0)
+	for _, sample := range organicHistory {
+		if sample.Withheld > 0 {
+			t.Fatalf("organic peer was limited: %+v", sample)
+		}
+	}
+
+	// A ring wide enough to pass the diversity rule is still held to the
+	// gain per window.
+	wide := registerSigningPeer(t, v, &PeerDetail{ID: "wide"})
+	for i := 0; i < 20; i++ {
+		verify(wide, "wide", fmt.Sprintf("ring-%d", i))
+	}
+	if score, _ := v.Reputation("wide"); math.Abs(score-(1+limits.MaxGain)) > 1e-9 {
+		t.Fatalf("wide ring raised its member to %.2f within an hour, want %.2f", score, 1+limits.MaxGain)
+	}
+	if err := v.SetReputationVelocity(ReputationVelocity{Window: time.Hour, MaxGain: 1, MaxPairGain: 1, PairDecay: 1.5, DiversityThreshold: 1, MinCounterparties: 1}); !errors.Is(err, ErrInvalidReputationVelocity) {
+		t.Fatalf("decay over 1: expected ErrInvalidReputationVelocity, got %v", err)
+	}
+}
+
+// addressUpdateFixture is a node watching peer-a, which announces its own
+// addresses, and relay-b, which gossips them on.
+type addressUpdateFixture struct {
+	local, owner *Network
+	// reachable maps the addresses peer-a answers challenges at to the
+	// network answering there.
+	reachable map[string]*Network
+}
+
+func newAddressUpdateFixture(t *testing.T) *addressUpdateFixture {
+	t.Helper()
+	ownerChannel, _ := crypto.NewSecureChannel()
+	owner := NewNetwork("peer-a", 1, time.Second)
+	owner.SetSecureChannel(ownerChannel)
+	ownerKey, _ := ownerChannel.ExportPublicKey()
+
+	local := NewNetwork("node-main", 1, time.Second)
+	local.AddPeer("peer-a", "10.0.0.1:4001", 0.9)
+	local.AddPeer("relay-b", "10.0.0.2:4001", 0.8)
+	if err := local.SetPeerPublicKey("peer-a", ownerKey); err != nil {
+		t.Fatalf("set key: %v", err)
+	}
+	f := &addressUpdateFixture{local: local, owner: owner, reachable: map[string]*Network{"10.0.0.1:4001": owner}}
+	local.SetAddressChallenger(func(_ context.Context, peerID string, address PeerAddress, nonce []byte) ([]byte, error) {
+		answering, ok := f.reachable[address.Address]
+		if !ok {
+			return nil, fmt.Errorf("dial %s: connection refused", address.Address)
+		}
+		return answering.AnswerAddressChallenge(address.Address, nonce)
+	})
+	return f
+}
+
+func TestPeerAddressUpdateMigratesAfterChallenge(t *testing.T) {
+	f := newAddressUpdateFixture(t)
+	update, err := f.owner.AnnounceAddresses([]string{"203.0.113.7:4001"})
+	if err != nil {
+		t.Fatalf("announce: %v", err)
+	}
+
+	// Before peer-a is up at its new address, the update is not applied
+	// and the old address stays in use.
+	if err := f.local.ApplyAddressUpdate(context.Background(), "relay-b", update); !errors.Is(err, ErrAddressUnconfirmed) || !Retryable(err) {
+		t.Fatalf("unreachable new address: expected ErrAddressUnconfirmed, got %v", err)
+	}
+	if peer, _ := f.local.GetPeer("peer-a"); peer.Address != "10.0.0.1:4001" {
+		t.Fatalf("unconfirmed update moved the peer to %s", peer.Address)
+	}
+
+	// Once it answers there, the relayed update moves the peer.
+	f.reachable["203.0.113.7:4001"] = f.owner
+	if err := f.local.ApplyAddressUpdate(context.Background(), "relay-b", update); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	peer, _ := f.local.GetPeer("peer-a")
+	if peer.Address != "203.0.113.7:4001" || len(peer.Addresses) != 1 || peer.Metadata[addressSequenceKey] != update.Sequence {
+		t.Fatalf("peer not migrated: %+v", peer)
+	}
+	if relay, _ := f.local.GetPeer("relay-b"); relay.Reputation != 0.8 {
+		t.Fatalf("honest relay penalized: %.2f", relay.Reputation)
+	}
+
+	// Another node answering at an announced address cannot confirm it.
+	impostorChannel, _ := crypto.NewSecureChannel()
+	impostor := NewNetwork("peer-a", 1, time.Second)
+	impostor.SetSecureChannel(impostorChannel)
+	f.reachable["198.51.100.9:4001"] = impostor
+	next, _ := f.owner.AnnounceAddresses([]string{"198.51.100.9:4001"})
+	if err := f.local.ApplyAddressUpdate(context.Background(), "peer-a", next); !errors.Is(err, ErrAddressUnconfirmed) {
+		t.Fatalf("impostor at new address: expected ErrAddressUnconfirmed, got %v", err)
+	}
+	if peer, _ := f.local.GetPeer("peer-a"); peer.Address != "203.0.113.7:4001" {
+		t.Fatalf("impostor confirmed the address: %s", peer.Address)
+	}
+}
+
+func TestPeerAddressUpdateRejectsReplays(t *testing.T) {
+	f := newAddressUpdateFixture(t)
+	f.reachable["203.0.113.7:4001"] = f.owner
+	f.reachable["203.0.113.8:4001"] = f.owner
+	old, _ := f.owner.AnnounceAddresses([]string{"203.0.113.7:4001"})
+	current, _ := f.owner.AnnounceAddresses([]string{"203.0.113.8:4001"})
+	if current.Sequence <= old.Sequence {
+		t.Fatalf("sequence did not increase: %d then %d", old.Sequence, current.Sequence)
+	}
+	if err := f.local.ApplyAddressUpdate(context.Background(), "peer-a", current); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	// Gossip delivers the applied update again through every forwarder;
+	// that is dropped without blame.
+	if err := f.local.ApplyAddressUpdate(context.Background(), "relay-b", current); err != nil {
+		t.Fatalf("redelivery of the applied update: %v", err)
+	}
+	if relay, _ := f.local.GetPeer("relay-b"); relay.Reputation != 0.8 {
+		t.Fatalf("relay of the applied update penalized: %.2f", relay.Reputation)
+	}
+
+	// The older update, replayed, would move the peer back.
+	if err := f.local.ApplyAddressUpdate(context.Background(), "relay-b", old); !errors.Is(err, ErrStaleAddressUpdate) {
+		t.Fatalf("replay of sequence %d: expected ErrStaleAddressUpdate, got %v", old.Sequence, err)
+	}
+	if peer, _ := f.local.GetPeer("peer-a"); peer.Address != "203.0.113.8:4001" {
+		t.Fatalf("replay moved the peer to %s", peer.Address)
+	}
+	relay, _ := f.local.GetPeer("relay-b")
+	if math.Abs(relay.Reputation-(0.8-addressUpdatePenalty)) > 1e-9 || relay.Metadata["address_update_violations"] != uint64(1) {
+		t.Fatalf("replaying relay not penalized: %+v", relay)
+	}
+}
+
+func TestPeerAddressUpdateGossipsAndChallengesOverTheWire(t *testing.T) {
+	f := newAddressUpdateFixture(t)
+	// peer-a answers challenges on whatever connection reaches it.
+	f.local.SetAddressChallenger(DialAddressChallenger(&Dialer{Dial: func(_ context.Context, address PeerAddress) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			_ = f.owner.ServeAddressChallenge(server, address.Address)
+		}()
+		return client, nil
+	}}))
+	sender := &captureSender{}
+	transport := NewTransport(sender, QueueConfig{})
+	f.owner.SetTransport(transport)
+	f.owner.AddPeer("node-main", "10.0.0.3:4001", 1)
+
+	update, err := f.owner.SetLocalAddresses([]string{"203.0.113.7:4001"})
+	if err != nil || update == nil {
+		t.Fatalf("set local addresses: %v, %v", update, err)
+	}
+	if again, err := f.owner.SetLocalAddresses([]string{"203.0.113.7:4001"}); again != nil || err != nil {
+		t.Fatalf("unchanged addresses announced again: %v, %v", again, err)
+	}
+	if err := transport.Close(); err != nil {
+		t.Fatalf("close transport: %v", err)
+	}
+	sender.mu.Lock()
+	frames := append([]OutboundMessage(nil), sender.frames...)
+	sender.mu.Unlock()
+	if len(frames) != 1 || frames[0].Topic != PeerAddressTopic {
+		t.Fatalf("announced frames = %+v", frames)
+	}
+
+	if err := f.local.Receive(context.Background(), "relay-b", frames[0].Topic, frames[0].Payload); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if peer, _ := f.local.GetPeer("peer-a"); peer.Address != "203.0.113.7:4001" {
+		t.Fatalf("gossiped update did not move the peer: %s", peer.Address)
+	}
+	if err := f.local.Receive(context.Background(), "relay-b", PeerAddressTopic, []byte("{")); !errors.Is(err, ErrMalformedPayload) {
+		t.Fatalf("garbled update: expected ErrMalformedPayload, got %v", err)
+	}
+}
+
+func TestPeerAddressUpdateRejectsForgeries(t *testing.T) {
+	f := newAddressUpdateFixture(t)
+	attackerChannel, _ := crypto.NewSecureChannel()
+	attacker := NewNetwork("peer-a", 1, time.Second)
+	attacker.SetSecureChannel(attackerChannel)
+	f.reachable["198.51.100.9:4001"] = attacker
+
+	// An update for peer-a signed by a key that is not peer-a's, and one
+	// carrying no signature at all.
+	forged, err := attacker.AnnounceAddresses([]string{"198.51.100.9:4001"})
+	if err != nil {
+		t.Fatalf("announce: %v", err)
+	}
+	unsigned := *forged
+	unsigned.Signature = nil
+	// A genuine update altered in transit.
+	altered, _ := f.owner.AnnounceAddresses([]string{"203.0.113.7:4001"})
+	altered.Addresses = []string{"198.51.100.9:4001"}
+
+	for name, update := range map[string]*protocol.PeerAddressUpdate{"forged": forged, "unsigned": &unsigned, "altered": altered} {
+		if err := f.local.ApplyAddressUpdate(context.Background(), "relay-b", update); !errors.Is(err, ErrAddressUpdateSignature) {
+			t.Fatalf("%s: expected ErrAddressUpdateSignature, got %v", name, err)
+		}
+	}
+	peer, _ := f.local.GetPeer("peer-a")
+	if peer.Address != "10.0.0.1:4001" || peer.Metadata[addressSequenceKey] != nil {
+		t.Fatalf("rejected update changed the peer: %+v", peer)
+	}
+	if relay, _ := f.local.GetPeer("relay-b"); math.Abs(relay.Reputation-(0.8-3*addressUpdatePenalty)) > 1e-9 || relay.Metadata["address_update_violations"] != uint64(3) {
+		t.Fatalf("forging relay not penalized: %+v", relay)
+	}
+}
+
+type routedMessage struct {
+	to  string
+	msg OutboundMessage
+}
+
+type routeSender struct {
+	mu   sync.Mutex
+	sent []routedMessage
+}
+
+func (s *routeSender) Send(_ context.Context, peerID string, msg OutboundMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, routedMessage{to: peerID, msg: msg})
+	return nil
+}
+
+func TestShardRekeyOnMembershipChangeReachesPublishPath(t *testing.T) {
+	const topic = "shard/shard-1"
+	ids := []string{"controller", "member-a", "member-b", "member-c"}
+	channels := make(map[string]*crypto.SecureChannel, len(ids))
+	networks := make(map[string]*Network, len(ids))
+	var members []protocol.ShardMember
+	for _, id := range ids {
+		channel, err := crypto.NewSecureChannel()
+		if err != nil {
+			t.Fatalf("new channel: %v", err)
+		}
+		channels[id] = channel
+		networks[id] = NewNetwork(id, 1, time.Second)
+		networks[id].SetSecureChannel(channel)
+		networks[id].SetTopicKeys(NewTopicKeyManager(id, channel))
+		if id != "controller" {
+			key, _ := channel.ExportPublicKey()
+			members = append(members, protocol.ShardMember{NodeID: id, PublicKey: key})
+			networks[id].JoinTopic(topic)
+		}
+	}
+	controllerKey, _ := channels["controller"].ExportPublicKey()
+	pub, _ := crypto.ImportPublicKey(controllerKey)
+	for _, id := range ids[1:] {
+		if err := channels[id].RegisterPeer("controller", pub); err != nil {
+			t.Fatalf("register controller with %s: %v", id, err)
+		}
+		for _, other := range ids {
+			if other != id {
+				networks[id].AddPeer(other, other+":9000", 1)
+			}
+		}
+		networks["controller"].AddPeer(id, id+":9000", 1)
+	}
+	// send runs fn on from's network and delivers what it sent.
+	send := func(from string, fn func(n *Network) error) map[string]error {
+		t.Helper()
+		sender := &routeSender{}
+		transport := NewTransport(sender, QueueConfig{})
+		networks[from].SetTransport(transport)
+		if err := fn(networks[from]); err != nil {
+			t.Fatalf("%s: %v", from, err)
+		}
+		_ = transport.Close()
+		networks[from].SetTransport(nil)
+		errs := make(map[string]error)
+		for _, routed := range sender.sent {
+			errs[routed.to] = networks[routed.to].Receive(context.Background(), from, routed.msg.Topic, routed.msg.Payload)
+		}
+		return errs
+	}
+
+	for to, err := range send("controller", func(n *Network) error { return n.SetTopicMembers(topic, members) }) {
+		if err != nil {
+			t.Fatalf("grant to %s: %v", to, err)
+		}
+	}
+	if errs := send("member-a", func(n *Network) error { _, err := n.Publish(topic, []byte("vote: approve")); return err }); errs["member-c"] != nil {
+		t.Fatalf("member-c could not open pre-rekey gossip: %v", errs["member-c"])
+	}
+	if got := networks["member-c"].GetTopicMessages(topic); len(got) != 1 || string(got[0].Payload) != "vote: approve" {
+		t.Fatalf("member-c kept %+v", got)
+	}
+
+	// member-c leaves: the controller rekeys the shard without it.
+	send("controller", func(n *Network) error { return n.RemovePeer("member-c") })
+	errs := send("member-a", func(n *Network) error { _, err := n.Publish(topic, []byte("vote: reject")); return err })
+	if errs["member-b"] != nil {
+		t.Fatalf("member-b could not open post-rekey gossip: %v", errs["member-b"])
+	}
+	if errs["member-c"] == nil {
+		t.Fatal("removed member opened post-rekey gossip")
+	}
+	if got := networks["member-c"].GetTopicMessages(topic); len(got) != 1 {
+		t.Fatalf("removed member kept %d messages", len(got))
+	}
+}
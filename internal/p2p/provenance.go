@@ -0,0 +1,63 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"sort"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// provenanceSubject is the update a model update request verifies.
+type provenanceSubject struct {
+	nodeID   string
+	round    int
+	updateID string
+}
+
+// SetProvenance emits a proof_verified event, naming the qualifying
+// committee, the first time a model update request passes verification.
+// Requests carry the update's canonical encoding as ModelWeights, so the
+// event shares the update's provenance ID. Only requests made after the
+// sink is set are tracked. nil stops emitting.
+func (v *Verifier) SetProvenance(sink provenance.Sink) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.provenance = sink
+}
+
+// trackProvenanceLocked remembers the update req verifies. Callers must
+// hold v.mu.
+func (v *Verifier) trackProvenanceLocked(req *ModelVerificationRequest) {
+	if v.provenance == nil || (req.ArtifactType != "" && req.ArtifactType != ArtifactModelUpdate) {
+		return
+	}
+	v.provenanceSubjects[req.RequestID] = provenanceSubject{
+		nodeID:   req.ProposerID,
+		round:    req.Round,
+		updateID: provenance.UpdateID(req.ProposerID, req.Round, protocol.UpdateDigest(req.ModelWeights)),
+	}
+}
+
+// emitProofVerified reports a passed request once.
+func (v *Verifier) emitProofVerified(requestID string, committee []string) {
+	v.mu.Lock()
+	subject, tracked := v.provenanceSubjects[requestID]
+	sink := v.provenance
+	delete(v.provenanceSubjects, requestID)
+	v.mu.Unlock()
+	if !tracked || sink == nil {
+		return
+	}
+	sort.Strings(committee)
+	sink.Emit(provenance.Event{
+		UpdateID:  subject.updateID,
+		NodeID:    subject.nodeID,
+		Round:     subject.round,
+		Kind:      provenance.KindProofVerified,
+		Source:    "p2p",
+		Committee: committee,
+	})
+}
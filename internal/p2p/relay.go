@@ -0,0 +1,265 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"sync"
+)
+
+// relayFrame is one message on a relay link: a request travelling down to
+// the relayed peer, or its response or error travelling back up.
+type relayFrame struct {
+	RequestID string                     `json:"request_id"`
+	Request   *ModelVerificationRequest  `json:"request,omitempty"`
+	Response  *ModelVerificationResponse `json:"response,omitempty"`
+	Error     string                     `json:"error,omitempty"`
+}
+
+// Relay lets peers that cannot be dialed, such as edge nodes behind
+// carrier-grade NAT or reachable only over IPv6, take part in verification.
+// Such a peer keeps an outbound connection open to its regional aggregator,
+// which attaches it here; the aggregator then forwards verification
+// requests down that link and the peer's responses back up. Responses stay
+// signed by the verifier, so a relay can withhold them but not forge them.
+type Relay struct {
+	mu       sync.Mutex
+	nodeID   string
+	verifier *Verifier
+	links    map[string]*relayLink
+}
+
+// relayLink is the open connection from one relayed peer.
+type relayLink struct {
+	peerID  string
+	conn    net.Conn
+	writeMu sync.Mutex
+	encoder *json.Encoder
+
+	mu      sync.Mutex
+	waiting map[string]chan relayFrame
+	closed  chan struct{}
+	err     error
+}
+
+// NewRelay creates the relay run by nodeID. Peers attached to it are marked
+// relayed through nodeID in verifier, which may be nil.
+func NewRelay(nodeID string, verifier *Verifier) *Relay {
+	return &Relay{nodeID: nodeID, verifier: verifier, links: make(map[string]*relayLink)}
+}
+
+// Attach takes over conn, a connection peerID opened to this node, and
+// relays to peerID through it until it closes or Detach is called. It
+// replaces any earlier link from peerID.
+func (r *Relay) Attach(peerID string, conn net.Conn) error {
+	if r.verifier != nil {
+		if err := r.verifier.MarkRelayed(peerID, r.nodeID); err != nil {
+			return err
+		}
+	}
+	link := &relayLink{
+		peerID:  peerID,
+		conn:    conn,
+		encoder: json.NewEncoder(conn),
+		waiting: make(map[string]chan relayFrame),
+		closed:  make(chan struct{}),
+	}
+	r.mu.Lock()
+	previous := r.links[peerID]
+	r.links[peerID] = link
+	r.mu.Unlock()
+
+	if previous != nil {
+		previous.close(fmt.Errorf("%w: %s reconnected", ErrRelayUnavailable, peerID))
+	}
+	go r.read(link)
+	return nil
+}
+
+// Detach closes peerID's link. Requests waiting on it fail with
+// ErrRelayUnavailable.
+func (r *Relay) Detach(peerID string) {
+	r.mu.Lock()
+	link := r.links[peerID]
+	delete(r.links, peerID)
+	r.mu.Unlock()
+	if link != nil {
+		link.close(fmt.Errorf("%w: %s detached", ErrRelayUnavailable, peerID))
+	}
+}
+
+// Relayed returns the peers with an open link, sorted.
+func (r *Relay) Relayed() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	peers := make([]string, 0, len(r.links))
+	for peerID := range r.links {
+		peers = append(peers, peerID)
+	}
+	sort.Strings(peers)
+	return peers
+}
+
+// Forward sends req down peerID's link and waits for the peer's response.
+// req must carry the ID RequestVerification assigned it.
+func (r *Relay) Forward(ctx context.Context, peerID string, req *ModelVerificationRequest) (*ModelVerificationResponse, error) {
+	if req.RequestID == "" {
+		return nil, fmt.Errorf("%w: relayed request has no ID", ErrUnknownRequest)
+	}
+	r.mu.Lock()
+	link := r.links[peerID]
+	r.mu.Unlock()
+	if link == nil {
+		return nil, fmt.Errorf("%w: no link from %s", ErrRelayUnavailable, peerID)
+	}
+
+	reply := make(chan relayFrame, 1)
+	link.mu.Lock()
+	if link.err != nil {
+		err := link.err
+		link.mu.Unlock()
+		return nil, err
+	}
+	link.waiting[req.RequestID] = reply
+	link.mu.Unlock()
+	defer func() {
+		link.mu.Lock()
+		delete(link.waiting, req.RequestID)
+		link.mu.Unlock()
+	}()
+
+	if err := link.write(ctx, relayFrame{RequestID: req.RequestID, Request: req}); err != nil {
+		link.close(fmt.Errorf("%w: write to %s: %v", ErrRelayUnavailable, peerID, err))
+		return nil, fmt.Errorf("%w: write to %s: %v", ErrRelayUnavailable, peerID, err)
+	}
+
+	select {
+	case frame := <-reply:
+		if frame.Error != "" {
+			return nil, fmt.Errorf("relayed peer %s: %s", peerID, frame.Error)
+		}
+		if frame.Response == nil || frame.Response.RequestID != req.RequestID {
+			return nil, fmt.Errorf("%w: relayed peer %s answered without a response", ErrMalformedPayload, peerID)
+		}
+		return frame.Response, nil
+	case <-link.closed:
+		link.mu.Lock()
+		err := link.err
+		link.mu.Unlock()
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ForwardVerification forwards req to peerID and submits the peer's
+// response to the relay's verifier as relayed by this node, so a response
+// the relay tampered with is rejected and costs it reputation.
+func (r *Relay) ForwardVerification(ctx context.Context, peerID string, req *ModelVerificationRequest) error {
+	if r.verifier == nil {
+		return fmt.Errorf("%w: relay has no verifier", ErrRelayUnavailable)
+	}
+	resp, err := r.Forward(ctx, peerID, req)
+	if err != nil {
+		return err
+	}
+	return r.verifier.SubmitVerification(ctx, r.nodeID, resp)
+}
+
+// read delivers the frames peerID sends up its link until it closes.
+func (r *Relay) read(link *relayLink) {
+	decoder := json.NewDecoder(link.conn)
+	for {
+		var frame relayFrame
+		if err := decoder.Decode(&frame); err != nil {
+			link.close(fmt.Errorf("%w: link from %s closed: %v", ErrRelayUnavailable, link.peerID, err))
+			r.mu.Lock()
+			if r.links[link.peerID] == link {
+				delete(r.links, link.peerID)
+			}
+			r.mu.Unlock()
+			return
+		}
+		link.mu.Lock()
+		reply := link.waiting[frame.RequestID]
+		delete(link.waiting, frame.RequestID)
+		link.mu.Unlock()
+		if reply != nil {
+			reply <- frame
+		}
+	}
+}
+
+// write sends frame, giving up at ctx's deadline.
+func (l *relayLink) write(ctx context.Context, frame relayFrame) error {
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+	deadline, _ := ctx.Deadline()
+	if err := l.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	return l.encoder.Encode(frame)
+}
+
+// close fails the link with err once; later calls keep the first error.
+func (l *relayLink) close(err error) {
+	l.mu.Lock()
+	if l.err != nil {
+		l.mu.Unlock()
+		return
+	}
+	l.err = err
+	l.mu.Unlock()
+	close(l.closed)
+	l.conn.Close()
+}
+
+// ServeRelay answers verification requests arriving over conn, this node's
+// outbound connection to its relay, with verify. Requests are answered
+// concurrently. It returns nil once ctx ends or the relay closes conn, and
+// closes conn when it returns.
+func ServeRelay(ctx context.Context, conn net.Conn, verify func(context.Context, *ModelVerificationRequest) (*ModelVerificationResponse, error)) error {
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	var writeMu sync.Mutex
+	var answering sync.WaitGroup
+	defer answering.Wait()
+	defer conn.Close()
+	encoder := json.NewEncoder(conn)
+	decoder := json.NewDecoder(conn)
+	for {
+		var frame relayFrame
+		if err := decoder.Decode(&frame); err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrClosedPipe) {
+				return nil
+			}
+			return fmt.Errorf("%w: relay frame: %v", ErrMalformedPayload, err)
+		}
+		if frame.Request == nil {
+			continue
+		}
+		answering.Add(1)
+		go func(req *ModelVerificationRequest) {
+			defer answering.Done()
+			answer := relayFrame{RequestID: req.RequestID}
+			if resp, err := verify(ctx, req); err != nil {
+				answer.Error = err.Error()
+			} else {
+				answer.Response = resp
+			}
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			// A failed write means the link is gone; the relay fails the
+			// request on its side.
+			_ = encoder.Encode(answer)
+		}(frame.Request)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// reputationRecentWindow keeps samples at full resolution; older
+	// samples are averaged into reputationBucketWidth buckets.
+	reputationRecentWindow = 24 * time.Hour
+	reputationBucketWidth  = time.Hour
+	// maxRecentReputationSamples bounds full-resolution samples per peer.
+	maxRecentReputationSamples = 2048
+	// maxReputationBuckets keeps roughly 90 days of hourly history.
+	maxReputationBuckets = 90 * 24
+
+	// DefaultReputationTrendWindow is the window used for the trend reported
+	// to the trend observer.
+	DefaultReputationTrendWindow = 6 * time.Hour
+)
+
+// Reputation change causes recorded in history.
+const (
+	CauseRegistered          = "registered"
+	CauseVerificationValid   = "verification_valid"
+	CauseVerificationInvalid = "verification_invalid"
+	CauseForgedResponse      = "forged_response"
+	CauseUnrevealedVote      = "unrevealed_vote"
+	CauseExtensionRequest    = "extension_request"
+	CauseDownsampled         = "downsampled"
+	// A valid verification whose reward a ReputationVelocity limit cut:
+	// repeats for one counterparty, the gain per window, or too few
+	// distinct counterparties above the diversity threshold.
+	CausePairLimited      = "pair_limited"
+	CauseVelocityLimited  = "velocity_limited"
+	CauseDiversityLimited = "diversity_limited"
+)
+
+// ReputationSample is one point in a peer's reputation history. Downsampled
+// samples carry the bucket's mean score and how many samples it merged;
+// samples a velocity limit cut carry the reward it withheld.
+type ReputationSample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Score     float64   `json:"score"`
+	Cause     string    `json:"cause"`
+	Merged    int       `json:"merged,omitempty"`
+	Withheld  float64   `json:"withheld,omitempty"`
+}
+
+// reputationHistory is a bounded per-peer history: recent samples at full
+// resolution, older ones averaged into hourly buckets.
+type reputationHistory struct {
+	buckets []ReputationSample
+	recent  []ReputationSample
+}
+
+func (h *reputationHistory) record(sample ReputationSample) {
+	h.recent = insertSample(h.recent, sample)
+	cutoff := h.recent[len(h.recent)-1].Timestamp.Add(-reputationRecentWindow)
+
+	moved := 0
+	for moved < len(h.recent) && (h.recent[moved].Timestamp.Before(cutoff) || len(h.recent)-moved > maxRecentReputationSamples) {
+		h.downsample(h.recent[moved])
+		moved++
+	}
+	if moved > 0 {
+		h.recent = append(h.recent[:0], h.recent[moved:]...)
+	}
+	if len(h.buckets) > maxReputationBuckets {
+		h.buckets = append(h.buckets[:0], h.buckets[len(h.buckets)-maxReputationBuckets:]...)
+	}
+}
+
+// downsample folds sample into its hourly bucket. The bucket is stamped at
+// the mean time of the samples it merged, which keeps trend fits over mixed
+// resolutions unbiased and keeps a partially filled bucket ahead of the
+// full-resolution samples that follow it in the same hour.
+func (h *reputationHistory) downsample(sample ReputationSample) {
+	start := sample.Timestamp.Truncate(reputationBucketWidth)
+	i := sort.Search(len(h.buckets), func(i int) bool {
+		return !h.buckets[i].Timestamp.Truncate(reputationBucketWidth).Before(start)
+	})
+	if i < len(h.buckets) && h.buckets[i].Timestamp.Truncate(reputationBucketWidth).Equal(start) {
+		bucket := &h.buckets[i]
+		merged := float64(bucket.Merged + 1)
+		bucket.Score += (sample.Score - bucket.Score) / merged
+		bucket.Timestamp = bucket.Timestamp.Add(time.Duration(float64(sample.Timestamp.Sub(bucket.Timestamp)) / merged))
+		bucket.Merged++
+		return
+	}
+	h.buckets = insertSample(h.buckets, ReputationSample{
+		Timestamp: sample.Timestamp,
+		Score:     sample.Score,
+		Cause:     CauseDownsampled,
+		Merged:    1,
+	})
+}
+
+// insertSample keeps series ordered by timestamp; in-order appends are the
+// common case.
+func insertSample(series []ReputationSample, sample ReputationSample) []ReputationSample {
+	i := sort.Search(len(series), func(i int) bool { return series[i].Timestamp.After(sample.Timestamp) })
+	series = append(series, ReputationSample{})
+	copy(series[i+1:], series[i:])
+	series[i] = sample
+	return series
+}
+
+// since returns samples at or after from, oldest first.
+func (h *reputationHistory) since(from time.Time) []ReputationSample {
+	out := make([]ReputationSample, 0, len(h.buckets)+len(h.recent))
+	for _, series := range [][]ReputationSample{h.buckets, h.recent} {
+		start := sort.Search(len(series), func(i int) bool { return !series[i].Timestamp.Before(from) })
+		out = append(out, series[start:]...)
+	}
+	return out
+}
+
+// RecordReputation sets a peer's reputation and appends it to the peer's
+// history with the given cause and timestamp.
+func (v *Verifier) RecordReputation(peerID string, at time.Time, score float64, cause string) error {
+	v.mu.Lock()
+	peer, exists := v.peers[peerID]
+	if !exists {
+		v.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, peerID)
+	}
+	peer.Reputation = score
+	notify := v.recordHistoryLocked(peerID, at, score, cause)
+	v.mu.Unlock()
+
+	notify()
+	return nil
+}
+
+// GetReputationHistory returns a peer's reputation samples from the last
+// window, oldest first. A non-positive window returns the full history.
+func (v *Verifier) GetReputationHistory(peerID string, window time.Duration) ([]ReputationSample, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	history, exists := v.history[peerID]
+	if !exists {
+		if _, known := v.peers[peerID]; !known {
+			return nil, fmt.Errorf("%w: %s", ErrPeerNotFound, peerID)
+		}
+		return []ReputationSample{}, nil
+	}
+	var from time.Time
+	if window > 0 {
+		from = time.Now().Add(-window)
+	}
+	return history.since(from), nil
+}
+
+// ReputationTrend returns the least-squares slope of a peer's reputation, in
+// score per hour, over the last window, and the number of samples it used.
+func (v *Verifier) ReputationTrend(peerID string, window time.Duration) (float64, int, error) {
+	samples, err := v.GetReputationHistory(peerID, window)
+	if err != nil {
+		return 0, 0, err
+	}
+	return reputationSlope(samples), len(samples), nil
+}
+
+// SetReputationTrendObserver registers a callback receiving a peer's trend
+// over DefaultReputationTrendWindow after each reputation change, for
+// export to alerting.
+func (v *Verifier) SetReputationTrendObserver(observer func(peerID string, slopePerHour float64)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.trendObserver = observer
+}
+
+// recordHistoryLocked appends a sample and returns a function that notifies
+// the trend observer; call it after releasing v.mu.
+func (v *Verifier) recordHistoryLocked(peerID string, at time.Time, score float64, cause string) func() {
+	return v.recordSampleLocked(peerID, ReputationSample{Timestamp: at, Score: score, Cause: cause})
+}
+
+// recordSampleLocked is recordHistoryLocked for a complete sample.
+func (v *Verifier) recordSampleLocked(peerID string, sample ReputationSample) func() {
+	at := sample.Timestamp
+	history, exists := v.history[peerID]
+	if !exists {
+		history = &reputationHistory{}
+		v.history[peerID] = history
+	}
+	history.record(sample)
+
+	observer := v.trendObserver
+	if observer == nil {
+		return func() {}
+	}
+	slope := reputationSlope(history.since(at.Add(-DefaultReputationTrendWindow)))
+	return func() { observer(peerID, slope) }
+}
+
+// reputationSlope fits score against time in hours.
+func reputationSlope(samples []ReputationSample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	origin := samples[0].Timestamp
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.Timestamp.Sub(origin).Hours()
+		sumX += x
+		sumY += sample.Score
+		sumXY += x * sample.Score
+		sumXX += x * x
+	}
+	n := float64(len(samples))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
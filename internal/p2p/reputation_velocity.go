@@ -0,0 +1,146 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"fmt"
+	"time"
+)
+
+// verificationReward is what one valid verification earns before any
+// velocity limit.
+const verificationReward = 0.1
+
+// maxReputationGains bounds the verifications remembered per peer.
+const maxReputationGains = 4096
+
+// gainTolerance absorbs rounding in summed gains, so a peer exactly at a
+// limit is not reported as cut by it.
+const gainTolerance = 1e-9
+
+// ReputationVelocity limits how fast valid verifications raise a peer's
+// reputation, so a colluding ring cannot pump a member by trading cheap
+// verifications. The gain limits are measured over the trailing Window and
+// the diversity rule over the trailing DiversityWindow; the counterparty of
+// a verification is the proposer of the update verified.
+type ReputationVelocity struct {
+	// Window is the trailing interval the gain limits are measured over.
+	Window time.Duration
+	// MaxGain is the most a peer's reputation may rise within Window.
+	MaxGain float64
+	// PairDecay, in (0, 1], scales each repeat verification for the same
+	// counterparty within Window: the nth earns PairDecay^n of the reward.
+	PairDecay float64
+	// MaxPairGain is the most verifications for one counterparty may
+	// contribute within Window.
+	MaxPairGain float64
+	// DiversityThreshold is the reputation above which gains count only
+	// once the peer has verified for MinCounterparties distinct
+	// counterparties within DiversityWindow, which is at least Window.
+	DiversityThreshold float64
+	MinCounterparties  int
+	DiversityWindow    time.Duration
+}
+
+// DefaultReputationVelocity returns the limits every verifier starts with:
+// at most 0.5 an hour, halving returns and at most 0.2 an hour per
+// counterparty, and five distinct counterparties a day to rise past 1.5.
+func DefaultReputationVelocity() ReputationVelocity {
+	return ReputationVelocity{
+		Window:             time.Hour,
+		MaxGain:            0.5,
+		PairDecay:          0.5,
+		MaxPairGain:        0.2,
+		DiversityThreshold: 1.5,
+		MinCounterparties:  5,
+		DiversityWindow:    24 * time.Hour,
+	}
+}
+
+// Validate checks that the limits are positive and the decay in range.
+func (r ReputationVelocity) Validate() error {
+	switch {
+	case r.Window <= 0:
+		return fmt.Errorf("%w: window %s", ErrInvalidReputationVelocity, r.Window)
+	case r.MaxGain <= 0 || r.MaxPairGain <= 0:
+		return fmt.Errorf("%w: max gain %g, max pair gain %g", ErrInvalidReputationVelocity, r.MaxGain, r.MaxPairGain)
+	case r.PairDecay <= 0 || r.PairDecay > 1:
+		return fmt.Errorf("%w: pair decay %g outside (0, 1]", ErrInvalidReputationVelocity, r.PairDecay)
+	case r.DiversityThreshold <= 0 || r.MinCounterparties < 1 || r.DiversityWindow < r.Window:
+		return fmt.Errorf("%w: diversity threshold %g, min counterparties %d, diversity window %s", ErrInvalidReputationVelocity, r.DiversityThreshold, r.MinCounterparties, r.DiversityWindow)
+	}
+	return nil
+}
+
+// SetReputationVelocity replaces the verifier's reputation velocity limits.
+// It fails with ErrInvalidReputationVelocity, keeping the old ones, if
+// velocity does not validate.
+func (v *Verifier) SetReputationVelocity(velocity ReputationVelocity) error {
+	if err := velocity.Validate(); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.velocity = velocity
+	return nil
+}
+
+// reputationGain is one valid verification and what it earned.
+type reputationGain struct {
+	at           time.Time
+	counterparty string
+	earned       float64
+}
+
+// limitGainLocked returns what a valid verification for counterparty earns
+// peer at now under the velocity limits, and the cause to record: the
+// verification's own, or the limit that withheld part of the reward.
+// Callers must hold v.mu.
+func (v *Verifier) limitGainLocked(peer *PeerDetail, counterparty string, now time.Time) (float64, string) {
+	limits := v.velocity
+	gains := v.gains[peer.ID]
+	kept := 0
+	if len(gains) >= maxReputationGains {
+		kept = len(gains) + 1 - maxReputationGains
+	}
+	for kept < len(gains) && !gains[kept].at.After(now.Add(-limits.DiversityWindow)) {
+		kept++
+	}
+	gains = gains[kept:]
+
+	cutoff := now.Add(-limits.Window)
+	var total, pairTotal float64
+	repeats := 0
+	counterparties := map[string]struct{}{counterparty: {}}
+	for _, gain := range gains {
+		counterparties[gain.counterparty] = struct{}{}
+		if !gain.at.After(cutoff) {
+			continue
+		}
+		total += gain.earned
+		if gain.counterparty == counterparty {
+			pairTotal += gain.earned
+			repeats++
+		}
+	}
+
+	earned, cause := verificationReward, CauseVerificationValid
+	for i := 0; i < repeats; i++ {
+		earned *= limits.PairDecay
+	}
+	if repeats > 0 || earned > limits.MaxPairGain-pairTotal+gainTolerance {
+		earned = max(min(earned, limits.MaxPairGain-pairTotal), 0)
+		cause = CausePairLimited
+	}
+	if earned > limits.MaxGain-total+gainTolerance {
+		earned = max(limits.MaxGain-total, 0)
+		cause = CauseVelocityLimited
+	}
+	if len(counterparties) < limits.MinCounterparties && peer.Reputation+earned > limits.DiversityThreshold+gainTolerance {
+		earned = max(limits.DiversityThreshold-peer.Reputation, 0)
+		cause = CauseDiversityLimited
+	}
+	v.gains[peer.ID] = append(gains, reputationGain{at: now, counterparty: counterparty, earned: earned})
+	return earned, cause
+}
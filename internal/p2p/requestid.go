@@ -0,0 +1,43 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// RequestID returns the content-addressed ID of a verification request:
+// SHA-256 over a length-prefixed encoding of the proposer, round, payload
+// digest and attempt. Identical submissions map to the same ID so they are
+// deduplicated; attempt distinguishes deliberate retries of the same payload.
+func RequestID(proposer string, round int, payloadDigest [32]byte, attempt int) string {
+	h := sha256.New()
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(proposer)))
+	h.Write(n[:])
+	h.Write([]byte(proposer))
+	binary.BigEndian.PutUint64(n[:], uint64(int64(round)))
+	h.Write(n[:])
+	h.Write(payloadDigest[:])
+	binary.BigEndian.PutUint64(n[:], uint64(int64(attempt)))
+	h.Write(n[:])
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// PayloadDigest hashes the parts of a request payload. Each part is length
+// prefixed so that moving bytes between parts changes the digest.
+func PayloadDigest(parts ...[]byte) [32]byte {
+	h := sha256.New()
+	var n [8]byte
+	for _, part := range parts {
+		binary.BigEndian.PutUint64(n[:], uint64(len(part)))
+		h.Write(n[:])
+		h.Write(part)
+	}
+	var digest [32]byte
+	copy(digest[:], h.Sum(nil))
+	return digest
+}
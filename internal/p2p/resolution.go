@@ -0,0 +1,205 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultResolvedHistory is how many resolved verifications a
+// VerificationProtocol keeps until SetResolvedHistory changes it.
+const DefaultResolvedHistory = 4096
+
+// VerificationOutcome is how a verification request was resolved.
+type VerificationOutcome string
+
+const (
+	OutcomeValid    VerificationOutcome = "valid"
+	OutcomeInvalid  VerificationOutcome = "invalid"
+	OutcomeTimedOut VerificationOutcome = "timed_out"
+)
+
+// VerificationRecord is what a VerificationProtocol keeps of a request once
+// it is resolved, in place of the request and its responses.
+type VerificationRecord struct {
+	RequestID   string              `json:"request_id"`
+	Round       int                 `json:"round"`
+	Attempt     int                 `json:"attempt"`
+	Outcome     VerificationOutcome `json:"outcome"`
+	Confidence  float64             `json:"confidence"`
+	Verifiers   []string            `json:"verifiers"`
+	RequestedAt time.Time           `json:"requested_at"`
+	ResolvedAt  time.Time           `json:"resolved_at"`
+}
+
+// ResolvedCallback is told of every resolved request, after the protocol's
+// lock is released.
+type ResolvedCallback func(record VerificationRecord)
+
+// resolvedRing holds the newest resolved records, indexed by request ID.
+type resolvedRing struct {
+	records []VerificationRecord
+	next    int
+	full    bool
+	index   map[string]int
+}
+
+func newResolvedRing(size int) *resolvedRing {
+	return &resolvedRing{records: make([]VerificationRecord, size), index: make(map[string]int, size)}
+}
+
+func (r *resolvedRing) add(record VerificationRecord) {
+	// A request ID resolved again indexes its newer slot; overwriting the
+	// older one must not drop that.
+	if evicted := r.records[r.next].RequestID; r.full && r.index[evicted] == r.next {
+		delete(r.index, evicted)
+	}
+	r.records[r.next] = record
+	r.index[record.RequestID] = r.next
+	r.next++
+	if r.next == len(r.records) {
+		r.next, r.full = 0, true
+	}
+}
+
+func (r *resolvedRing) get(requestID string) (VerificationRecord, bool) {
+	i, ok := r.index[requestID]
+	if !ok {
+		return VerificationRecord{}, false
+	}
+	return r.records[i], true
+}
+
+// all returns the records, oldest first.
+func (r *resolvedRing) all() []VerificationRecord {
+	if !r.full {
+		return append([]VerificationRecord(nil), r.records[:r.next]...)
+	}
+	out := make([]VerificationRecord, 0, len(r.records))
+	out = append(out, r.records[r.next:]...)
+	return append(out, r.records[:r.next]...)
+}
+
+// SetResolvedHistory keeps the newest size resolved records, dropping
+// older ones now if there are more. Non-positive sizes keep
+// DefaultResolvedHistory.
+func (vp *VerificationProtocol) SetResolvedHistory(size int) {
+	if size <= 0 {
+		size = DefaultResolvedHistory
+	}
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	records := vp.resolved.all()
+	if len(records) > size {
+		records = records[len(records)-size:]
+	}
+	vp.resolved = newResolvedRing(size)
+	for _, record := range records {
+		vp.resolved.add(record)
+	}
+}
+
+// SetResolvedCallback calls resolved with each request's record once it is
+// resolved, replacing any previous callback.
+func (vp *VerificationProtocol) SetResolvedCallback(resolved ResolvedCallback) {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	vp.onResolved = resolved
+}
+
+// Resolved returns the retained resolved records, oldest first.
+func (vp *VerificationProtocol) Resolved() []VerificationRecord {
+	vp.mu.RLock()
+	defer vp.mu.RUnlock()
+	return vp.resolved.all()
+}
+
+// ExpirePending resolves every pending request older than the timeout as
+// timed out and returns how many it resolved. Requests are otherwise
+// resolved when their status is checked; new requests expire older ones
+// as they arrive, so a request nobody checks is still freed.
+func (vp *VerificationProtocol) ExpirePending() int {
+	vp.mu.Lock()
+	records := vp.expireLocked(time.Now())
+	callback := vp.onResolved
+	vp.mu.Unlock()
+	notifyResolved(callback, records)
+	return len(records)
+}
+
+// expireLocked resolves the pending requests that timed out by now, oldest
+// first, and drops resolved IDs from pendingOrder. Without a timeout
+// nothing expires, but resolved IDs are still dropped. The caller holds
+// vp.mu.
+func (vp *VerificationProtocol) expireLocked(now time.Time) []VerificationRecord {
+	var records []VerificationRecord
+	for len(vp.pendingOrder) > 0 {
+		request, pending := vp.pendingRequests[vp.pendingOrder[0]]
+		if pending && (vp.timeout <= 0 || now.Sub(request.Timestamp) <= vp.timeout) {
+			break
+		}
+		vp.pendingOrder = vp.pendingOrder[1:]
+		if pending {
+			records = append(records, vp.resolveLocked(request, OutcomeTimedOut, 0, now))
+		}
+	}
+	// Resolved IDs behind a request still pending would otherwise wait for
+	// it; compacting once they outnumber pending ones keeps the queue
+	// within twice the pending requests.
+	if len(vp.pendingOrder) > 2*len(vp.pendingRequests) {
+		queued := make(map[string]bool, len(vp.pendingRequests))
+		order := make([]string, 0, len(vp.pendingRequests))
+		for _, requestID := range vp.pendingOrder {
+			if _, pending := vp.pendingRequests[requestID]; pending && !queued[requestID] {
+				queued[requestID] = true
+				order = append(order, requestID)
+			}
+		}
+		vp.pendingOrder = order
+	}
+	return records
+}
+
+// resolveLocked condenses request and its responses into a record, frees
+// them, and counts the outcome. The caller holds vp.mu.
+func (vp *VerificationProtocol) resolveLocked(request *VerificationRequest, outcome VerificationOutcome, confidence float64, now time.Time) VerificationRecord {
+	responses := vp.verifications[request.RequestID]
+	verifiers := make([]string, 0, len(responses))
+	for _, resp := range responses {
+		verifiers = append(verifiers, resp.VerifierID)
+	}
+	sort.Strings(verifiers)
+	record := VerificationRecord{
+		RequestID:   request.RequestID,
+		Round:       request.Round,
+		Attempt:     request.Attempt,
+		Outcome:     outcome,
+		Confidence:  confidence,
+		Verifiers:   verifiers,
+		RequestedAt: request.Timestamp,
+		ResolvedAt:  now,
+	}
+	delete(vp.pendingRequests, request.RequestID)
+	delete(vp.verifications, request.RequestID)
+	vp.resolved.add(record)
+	switch outcome {
+	case OutcomeValid:
+		vp.resolvedValid++
+	case OutcomeInvalid:
+		vp.resolvedInvalid++
+	case OutcomeTimedOut:
+		vp.timedOut++
+	}
+	return record
+}
+
+func notifyResolved(callback ResolvedCallback, records []VerificationRecord) {
+	if callback == nil {
+		return
+	}
+	for _, record := range records {
+		callback(record)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"errors"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Digest returns the canonical digest the verifier signs; see
+// protocol.VerificationResponseDigest.
+func (r *ModelVerificationResponse) Digest() [32]byte {
+	return protocol.VerificationResponseDigest(r.RequestID, r.VerifierID, r.Valid, string(r.ReasonCode), r.Timestamp)
+}
+
+// Sign signs the response's digest with the verifier's channel identity.
+// Set every other field first; changing one afterwards voids the signature.
+func (r *ModelVerificationResponse) Sign(channel *crypto.SecureChannel) error {
+	digest := r.Digest()
+	signature, err := channel.SignData(digest[:])
+	if err != nil {
+		return err
+	}
+	r.Signature, r.SignatureAlgorithm = signature, channel.Algorithm()
+	return nil
+}
+
+// CheckSignature verifies the response's signature against the verifier's
+// PEM public key, by that key's algorithm; a response declaring another
+// fails with protocol.ErrAlgorithmMismatch.
+func (r *ModelVerificationResponse) CheckSignature(publicKeyPEM []byte) error {
+	if len(r.Signature) == 0 {
+		return errors.New("response is unsigned")
+	}
+	algorithm, err := crypto.PublicKeyAlgorithm(publicKeyPEM)
+	if err != nil {
+		return err
+	}
+	if err := protocol.CheckAlgorithm(r.SignatureAlgorithm, algorithm); err != nil {
+		return err
+	}
+	digest := r.Digest()
+	return crypto.VerifyWithPublicKey(publicKeyPEM, digest[:], r.Signature)
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+)
+
+// IdentityRotationTopic carries rotation certificates between peers.
+const IdentityRotationTopic = "identity-rotation"
+
+// SetSecureChannel binds the channel holding this node's identity key and
+// its peers' keys. The network learns of peers the channel evicts and
+// re-establishes them when they reappear; see SetPeerHandshake.
+func (n *Network) SetSecureChannel(channel *crypto.SecureChannel) {
+	n.mu.Lock()
+	n.channel = channel
+	n.mu.Unlock()
+	if channel != nil {
+		channel.SetPeerEvictionHook(n.notePeerEviction)
+		channel.SetPeerKeyResolver(n.rehandshake)
+	}
+}
+
+// RotateIdentity rotates this node's identity key and broadcasts the
+// certificate at commit priority so it is shed last.
+func (n *Network) RotateIdentity(validity time.Duration) (*crypto.RotationCertificate, error) {
+	n.mu.RLock()
+	channel := n.channel
+	n.mu.RUnlock()
+	if channel == nil {
+		return nil, fmt.Errorf("identity rotation: no secure channel configured")
+	}
+
+	cert, err := channel.RotateIdentity(n.nodeID, validity)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(cert)
+	if err != nil {
+		return nil, fmt.Errorf("encode rotation certificate: %w", err)
+	}
+	if err := n.BroadcastPriority(IdentityRotationTopic, payload, PriorityCommit); err != nil {
+		return cert, err
+	}
+	return cert, nil
+}
+
+// ApplyIdentityRotation applies a peer's rotation certificate to the secure
+// channel and, once accepted, rebinds the peer record to the new key.
+func (n *Network) ApplyIdentityRotation(cert *crypto.RotationCertificate) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.channel == nil {
+		return fmt.Errorf("identity rotation: no secure channel configured")
+	}
+	peer, exists := n.peers[cert.NodeID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, cert.NodeID)
+	}
+	if err := n.channel.ApplyRotation(cert); err != nil {
+		return err
+	}
+	peer.PublicKey = append([]byte(nil), cert.NewPublicKey...)
+	peer.Metadata["key_sequence"] = cert.Sequence
+	return nil
+}
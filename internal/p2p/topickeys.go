@@ -0,0 +1,441 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// keyRequestMaxAge bounds how old a signed key request may be.
+const keyRequestMaxAge = time.Minute
+
+// Topic key traffic: grants carry a topic's group key wrapped for one
+// member, and key requests ask any member for the current key after a
+// missed rekey.
+const (
+	TopicKeyGrantTopic   = "topic-key-grant"
+	TopicKeyRequestTopic = "topic-key-request"
+)
+
+// ShardTopic is the gossip topic of a shard's members, encrypted under the
+// group key the shard admission controller rekeys on every membership
+// change.
+func ShardTopic(shardID string) string {
+	return "shard/" + shardID
+}
+
+// KeyGrant delivers a topic's group key wrapped for its recipient.
+type KeyGrant struct {
+	Topic   string `json:"topic"`
+	Wrapped []byte `json:"wrapped"`
+}
+
+// KeyRequest asks any topic member for the current group key. It is signed
+// with the requester's identity key so members can re-authenticate it.
+type KeyRequest struct {
+	Topic     string    `json:"topic"`
+	PeerID    string    `json:"peer_id"`
+	IssuedAt  time.Time `json:"issued_at"`
+	Signature []byte    `json:"signature,omitempty"`
+}
+
+func (r *KeyRequest) signingPayload() []byte {
+	return []byte(fmt.Sprintf("%s|%s|%d", r.Topic, r.PeerID, r.IssuedAt.UnixNano()))
+}
+
+// TopicKeyManager holds per-topic group keys. On the shard's admission
+// controller it issues a new key whenever membership changes; on members it
+// installs distributed keys and serves them to members that missed a rekey.
+type TopicKeyManager struct {
+	mu      sync.RWMutex
+	nodeID  string
+	channel *crypto.SecureChannel
+	keys    map[string]*crypto.GroupKey
+}
+
+// NewTopicKeyManager creates a manager that distributes keys over channel.
+func NewTopicKeyManager(nodeID string, channel *crypto.SecureChannel) *TopicKeyManager {
+	return &TopicKeyManager{
+		nodeID:  nodeID,
+		channel: channel,
+		keys:    make(map[string]*crypto.GroupKey),
+	}
+}
+
+// SetMembers rekeys topic if its admitted members changed and returns the
+// new key wrapped for every member other than this node. It returns nil
+// grants when membership is unchanged.
+func (m *TopicKeyManager) SetMembers(topic string, members []string) (map[string][]byte, error) {
+	sorted := append([]string(nil), members...)
+	sort.Strings(sorted)
+	unique := sorted[:0]
+	for i, member := range sorted {
+		if i == 0 || member != sorted[i-1] {
+			unique = append(unique, member)
+		}
+	}
+	sorted = unique
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var epoch uint64
+	if current, exists := m.keys[topic]; exists {
+		if equalMembers(current.Members, sorted) {
+			return nil, nil
+		}
+		epoch = current.Epoch
+	}
+	key, err := crypto.NewGroupKey(topic, epoch+1, sorted)
+	if err != nil {
+		return nil, err
+	}
+
+	grants := make(map[string][]byte, len(sorted))
+	for _, member := range sorted {
+		if member == m.nodeID {
+			continue
+		}
+		wrapped, err := m.channel.WrapGroupKey(member, key)
+		if err != nil {
+			return nil, fmt.Errorf("wrap %s key for %s: %w", topic, member, err)
+		}
+		grants[member] = wrapped
+	}
+	m.keys[topic] = key
+	return grants, nil
+}
+
+// SetMemberKeys registers each member's identity key with the manager's
+// secure channel, so keys can be wrapped for it and its key requests
+// verified, and then rekeys topic for them as SetMembers does.
+func (m *TopicKeyManager) SetMemberKeys(topic string, members []protocol.ShardMember) (map[string][]byte, error) {
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.NodeID)
+		if member.NodeID == m.nodeID {
+			continue
+		}
+		key, err := parsePeerKey(member.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s identity key: %v", ErrInvalidPeer, member.NodeID, err)
+		}
+		if err := m.channel.RegisterPeer(member.NodeID, key); err != nil {
+			return nil, fmt.Errorf("%w: %s identity key: %v", ErrInvalidPeer, member.NodeID, err)
+		}
+	}
+	return m.SetMembers(topic, ids)
+}
+
+// Admit adds peerID to topic and rekeys.
+func (m *TopicKeyManager) Admit(topic, peerID string) (map[string][]byte, error) {
+	members := append(m.Members(topic), peerID)
+	return m.SetMembers(topic, members)
+}
+
+// Remove drops peerID from topic and rekeys, so it cannot read later traffic.
+func (m *TopicKeyManager) Remove(topic, peerID string) (map[string][]byte, error) {
+	members := m.Members(topic)
+	kept := members[:0]
+	for _, member := range members {
+		if member != peerID {
+			kept = append(kept, member)
+		}
+	}
+	return m.SetMembers(topic, kept)
+}
+
+// Members returns the members the current key for topic was issued to.
+func (m *TopicKeyManager) Members(topic string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if key, exists := m.keys[topic]; exists {
+		return append([]string(nil), key.Members...)
+	}
+	return nil
+}
+
+// Topics returns, sorted, the topics whose current key was issued to
+// peerID.
+func (m *TopicKeyManager) Topics(peerID string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var topics []string
+	for topic, key := range m.keys {
+		if containsMember(key.Members, peerID) {
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Epoch returns the current key epoch for topic, or zero if none is held.
+func (m *TopicKeyManager) Epoch(topic string) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if key, exists := m.keys[topic]; exists {
+		return key.Epoch
+	}
+	return 0
+}
+
+// InstallKey unwraps a key grant sent by fromPeer. Keys for older epochs
+// are rejected.
+func (m *TopicKeyManager) InstallKey(topic, fromPeer string, wrapped []byte) error {
+	key, err := m.channel.UnwrapGroupKey(fromPeer, wrapped)
+	if err != nil {
+		return fmt.Errorf("unwrap %s key from %s: %w", topic, fromPeer, err)
+	}
+	if key.Topic != topic {
+		return fmt.Errorf("%w: key for topic %q delivered as %q", ErrInvalidPeer, key.Topic, topic)
+	}
+	if !containsMember(key.Members, m.nodeID) {
+		return fmt.Errorf("%w: %s not in %s key epoch %d", ErrNotTopicMember, m.nodeID, topic, key.Epoch)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current, exists := m.keys[topic]; exists && key.Epoch <= current.Epoch {
+		return fmt.Errorf("%w: %s epoch %d, holding %d", crypto.ErrStaleGroupKey, topic, key.Epoch, current.Epoch)
+	}
+	m.keys[topic] = key
+	return nil
+}
+
+// Seal encrypts payload with the current key for topic.
+func (m *TopicKeyManager) Seal(topic string, payload []byte) (*crypto.GossipEnvelope, error) {
+	m.mu.RLock()
+	key, exists := m.keys[topic]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrNoTopicKey, topic)
+	}
+	return key.Seal(payload)
+}
+
+// Open decrypts an envelope with the current key for its topic. Traffic
+// from an epoch this node was never given cannot be opened.
+func (m *TopicKeyManager) Open(envelope *crypto.GossipEnvelope) ([]byte, error) {
+	m.mu.RLock()
+	key, exists := m.keys[envelope.Topic]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrNoTopicKey, envelope.Topic)
+	}
+	return key.Open(envelope)
+}
+
+// NewKeyRequest builds a signed request for the current key of topic.
+func (m *TopicKeyManager) NewKeyRequest(topic string) (*KeyRequest, error) {
+	req := &KeyRequest{Topic: topic, PeerID: m.nodeID, IssuedAt: time.Now().UTC()}
+	signature, err := m.channel.SignData(req.signingPayload())
+	if err != nil {
+		return nil, err
+	}
+	req.Signature = signature
+	return req, nil
+}
+
+// HandleKeyRequest re-authenticates a member that missed a rekey and
+// returns the current key wrapped for it.
+func (m *TopicKeyManager) HandleKeyRequest(req *KeyRequest) ([]byte, error) {
+	if age := time.Since(req.IssuedAt); age > keyRequestMaxAge || age < -keyRequestMaxAge {
+		return nil, fmt.Errorf("%w: key request from %s is %s old", ErrInvalidPeer, req.PeerID, age)
+	}
+	if err := m.channel.VerifySignature(req.PeerID, req.signingPayload(), req.Signature); err != nil {
+		return nil, fmt.Errorf("%w: key request from %s: %v", ErrInvalidPeer, req.PeerID, err)
+	}
+
+	m.mu.RLock()
+	key, exists := m.keys[req.Topic]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrNoTopicKey, req.Topic)
+	}
+	if !containsMember(key.Members, req.PeerID) {
+		return nil, fmt.Errorf("%w: %s in %s", ErrNotTopicMember, req.PeerID, req.Topic)
+	}
+	return m.channel.WrapGroupKey(req.PeerID, key)
+}
+
+// SetTopicKeys encrypts gossip on topics that have a group key: Publish
+// seals their payloads and Receive opens them, grants and key requests
+// arriving on TopicKeyGrantTopic and TopicKeyRequestTopic are served, and
+// a removed peer's topics are rekeyed without it.
+func (n *Network) SetTopicKeys(keys *TopicKeyManager) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.topicKeys = keys
+}
+
+// PublishSealed encrypts payload with the topic's group key and publishes
+// the envelope, failing with ErrNoTopicKey when the topic has none.
+func (n *Network) PublishSealed(topic string, payload []byte) (int, error) {
+	n.mu.RLock()
+	keys := n.topicKeys
+	n.mu.RUnlock()
+	if keys == nil {
+		return 0, fmt.Errorf("%w: %s", ErrNoTopicKey, topic)
+	}
+	sealed, err := sealGossip(keys, topic, payload)
+	if err != nil {
+		return 0, err
+	}
+	return n.publish(topic, payload, sealed)
+}
+
+// SetTopicMembers rekeys topic for members, as the shard admission
+// controller does on every join and leave, and sends each member other
+// than this node its wrapped key. Members no longer listed cannot open
+// the topic's later traffic.
+func (n *Network) SetTopicMembers(topic string, members []protocol.ShardMember) error {
+	n.mu.RLock()
+	keys := n.topicKeys
+	n.mu.RUnlock()
+	if keys == nil {
+		return fmt.Errorf("%w: %s", ErrNoTopicKey, topic)
+	}
+	grants, err := keys.SetMemberKeys(topic, members)
+	if err != nil {
+		return err
+	}
+	return n.sendGrants(topic, grants)
+}
+
+// RequestTopicKey asks the topic's members for its current key, as a
+// member that missed a rekey does; the first grant that arrives is
+// installed by Receive.
+func (n *Network) RequestTopicKey(topic string) error {
+	n.mu.RLock()
+	keys := n.topicKeys
+	n.mu.RUnlock()
+	if keys == nil {
+		return fmt.Errorf("%w: %s", ErrNoTopicKey, topic)
+	}
+	req, err := keys.NewKeyRequest(topic)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encode key request: %w", err)
+	}
+	return n.BroadcastPriority(TopicKeyRequestTopic, payload, PriorityCommit)
+}
+
+// rekeyWithout rekeys every topic peerID held a key for without it.
+func (n *Network) rekeyWithout(keys *TopicKeyManager, peerID string) error {
+	for _, topic := range keys.Topics(peerID) {
+		grants, err := keys.Remove(topic, peerID)
+		if err != nil {
+			return err
+		}
+		if err := n.sendGrants(topic, grants); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendGrants queues each wrapped key for its member at commit priority.
+// Without a transport there is no wire to send on, as for broadcasts.
+func (n *Network) sendGrants(topic string, grants map[string][]byte) error {
+	n.mu.RLock()
+	transport := n.transport
+	n.mu.RUnlock()
+	if transport == nil {
+		return nil
+	}
+	for peerID, wrapped := range grants {
+		payload, err := json.Marshal(KeyGrant{Topic: topic, Wrapped: wrapped})
+		if err != nil {
+			return fmt.Errorf("encode %s key grant: %w", topic, err)
+		}
+		if _, err := transport.Enqueue(peerID, OutboundMessage{Topic: TopicKeyGrantTopic, Payload: payload, Priority: PriorityCommit}); err != nil {
+			return fmt.Errorf("send %s key to %s: %w", topic, peerID, err)
+		}
+	}
+	return nil
+}
+
+// receiveTopicKeyTraffic installs a grant from peer from or answers its
+// key request.
+func (n *Network) receiveTopicKeyTraffic(keys *TopicKeyManager, from, topic string, payload []byte) error {
+	if topic == TopicKeyGrantTopic {
+		var grant KeyGrant
+		if err := json.Unmarshal(payload, &grant); err != nil {
+			return fmt.Errorf("%w: key grant from %s: %v", ErrInvalidPeer, from, err)
+		}
+		return keys.InstallKey(grant.Topic, from, grant.Wrapped)
+	}
+	var req KeyRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return fmt.Errorf("%w: key request from %s: %v", ErrInvalidPeer, from, err)
+	}
+	wrapped, err := keys.HandleKeyRequest(&req)
+	if err != nil {
+		return err
+	}
+	return n.sendGrants(req.Topic, map[string][]byte{req.PeerID: wrapped})
+}
+
+// openGossip decrypts an envelope received on topic, a topic keys holds a
+// group key for.
+func openGossip(keys *TopicKeyManager, topic string, payload []byte) ([]byte, error) {
+	var envelope crypto.GossipEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %s gossip envelope: %v", ErrInvalidPeer, topic, err)
+	}
+	if envelope.Topic != topic {
+		return nil, fmt.Errorf("%w: %s envelope delivered on %s", ErrInvalidPeer, envelope.Topic, topic)
+	}
+	return keys.Open(&envelope)
+}
+
+// sealGossip encrypts payload under topic's group key.
+func sealGossip(keys *TopicKeyManager, topic string, payload []byte) ([]byte, error) {
+	envelope, err := keys.Seal(topic, payload)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("encode gossip envelope: %w", err)
+	}
+	return encoded, nil
+}
+
+// keyedTopic reports whether keys holds a group key for topic.
+func keyedTopic(keys *TopicKeyManager, topic string) bool {
+	return keys != nil && keys.Epoch(topic) > 0
+}
+
+func equalMembers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsMember(members []string, peerID string) bool {
+	for _, member := range members {
+		if member == peerID {
+			return true
+		}
+	}
+	return false
+}
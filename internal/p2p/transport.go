@@ -0,0 +1,402 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/chaos"
+)
+
+// Priority orders outbound messages for load shedding. When a peer's queue
+// is full, lower priorities are dropped first.
+type Priority int
+
+const (
+	// PriorityStatus is for heartbeats and status updates.
+	PriorityStatus Priority = iota
+	// PriorityVote is for consensus votes and verification traffic.
+	PriorityVote
+	// PriorityCommit is for commit announcements.
+	PriorityCommit
+)
+
+const (
+	// DefaultQueueDepth is the per-peer outbound queue depth.
+	DefaultQueueDepth = 64
+	// DefaultDrainTimeout bounds how long Close waits for queues to drain.
+	DefaultDrainTimeout = 5 * time.Second
+)
+
+// OutboundMessage is a message waiting in a peer's send queue.
+type OutboundMessage struct {
+	Topic    string
+	Payload  []byte
+	Priority Priority
+	Enqueued time.Time
+
+	// reordered marks a message already pushed back by chaos injection.
+	reordered bool
+}
+
+// Sender writes a single message to a peer on the wire.
+type Sender interface {
+	Send(ctx context.Context, peerID string, msg OutboundMessage) error
+}
+
+// QueueConfig configures per-peer outbound queues.
+type QueueConfig struct {
+	Depth        int
+	DrainTimeout time.Duration
+}
+
+// Transport fans messages out to peers through bounded per-peer queues, each
+// drained by its own writer goroutine, so one slow peer cannot stall others.
+type Transport struct {
+	mu      sync.Mutex
+	sender  Sender
+	config  QueueConfig
+	queues  map[string]*peerQueue
+	onDrop  func(peerID string, msg OutboundMessage)
+	split   *Fragmenter
+	closed  bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	writers sync.WaitGroup
+}
+
+type peerQueue struct {
+	mu        sync.Mutex
+	peerID    string
+	pending   []OutboundMessage
+	notify    chan struct{}
+	closing   bool
+	drops     uint64
+	sent      uint64
+	failures  uint64
+	fragments uint64
+}
+
+// NewTransport creates a transport writing through sender.
+func NewTransport(sender Sender, config QueueConfig) *Transport {
+	if config.Depth <= 0 {
+		config.Depth = DefaultQueueDepth
+	}
+	if config.DrainTimeout <= 0 {
+		config.DrainTimeout = DefaultDrainTimeout
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Transport{
+		sender: sender,
+		config: config,
+		queues: make(map[string]*peerQueue),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// SetDropHandler registers a callback invoked whenever a message is shed
+// from a peer's queue. It runs on the enqueuing goroutine.
+func (t *Transport) SetDropHandler(handler func(peerID string, msg OutboundMessage)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onDrop = handler
+}
+
+// SetFragmenter splits messages larger than the fragmenter's MTU as they
+// are written. A message takes one queue slot however many fragments it
+// becomes, so fragments are never shed individually.
+func (t *Transport) SetFragmenter(fragmenter *Fragmenter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.split = fragmenter
+}
+
+// Enqueue queues msg for peerID without blocking. It returns false if a
+// message (possibly msg itself) had to be dropped to stay within the depth.
+func (t *Transport) Enqueue(peerID string, msg OutboundMessage) (bool, error) {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return false, fmt.Errorf("%w: transport closed", ErrTransportClosed)
+	}
+	queue := t.queueLocked(peerID)
+	onDrop := t.onDrop
+	t.mu.Unlock()
+
+	if msg.Enqueued.IsZero() {
+		msg.Enqueued = time.Now()
+	}
+
+	dropped, ok := queue.push(msg, t.config.Depth)
+	if !ok && onDrop != nil {
+		onDrop(peerID, dropped)
+	}
+	return ok, nil
+}
+
+// Broadcast enqueues msg for every peer in peerIDs and returns the number of
+// peers whose queue shed a message.
+func (t *Transport) Broadcast(peerIDs []string, msg OutboundMessage) (int, error) {
+	shed := 0
+	for _, peerID := range peerIDs {
+		ok, err := t.Enqueue(peerID, msg)
+		if err != nil {
+			return shed, err
+		}
+		if !ok {
+			shed++
+		}
+	}
+	return shed, nil
+}
+
+// RemovePeer stops the peer's writer after it drains its queue.
+func (t *Transport) RemovePeer(peerID string) {
+	t.mu.Lock()
+	queue, exists := t.queues[peerID]
+	delete(t.queues, peerID)
+	t.mu.Unlock()
+	if exists {
+		queue.close()
+	}
+}
+
+// Drops returns how many messages were shed from a peer's queue.
+func (t *Transport) Drops(peerID string) uint64 {
+	t.mu.Lock()
+	queue, exists := t.queues[peerID]
+	t.mu.Unlock()
+	if !exists {
+		return 0
+	}
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return queue.drops
+}
+
+// Close stops accepting messages and waits for queued messages to drain.
+// Writers still busy after the drain timeout are cancelled and Close returns
+// ErrDrainTimeout.
+func (t *Transport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	queues := make([]*peerQueue, 0, len(t.queues))
+	for _, queue := range t.queues {
+		queues = append(queues, queue)
+	}
+	t.mu.Unlock()
+
+	for _, queue := range queues {
+		queue.close()
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		t.writers.Wait()
+		close(drained)
+	}()
+
+	timer := time.NewTimer(t.config.DrainTimeout)
+	defer timer.Stop()
+	select {
+	case <-drained:
+		t.cancel()
+		return nil
+	case <-timer.C:
+		t.cancel()
+		<-drained
+		return fmt.Errorf("%w after %s", ErrDrainTimeout, t.config.DrainTimeout)
+	}
+}
+
+// GetQueueStats returns per-peer queue depth and counters for observability.
+func (t *Transport) GetQueueStats() map[string]interface{} {
+	t.mu.Lock()
+	queues := make([]*peerQueue, 0, len(t.queues))
+	for _, queue := range t.queues {
+		queues = append(queues, queue)
+	}
+	t.mu.Unlock()
+
+	stats := make(map[string]interface{}, len(queues))
+	for _, queue := range queues {
+		queue.mu.Lock()
+		stats[queue.peerID] = map[string]interface{}{
+			"depth":     len(queue.pending),
+			"drops":     queue.drops,
+			"sent":      queue.sent,
+			"failures":  queue.failures,
+			"fragments": queue.fragments,
+		}
+		queue.mu.Unlock()
+	}
+	return stats
+}
+
+func (t *Transport) queueLocked(peerID string) *peerQueue {
+	if queue, exists := t.queues[peerID]; exists {
+		return queue
+	}
+	queue := &peerQueue{
+		peerID:  peerID,
+		pending: make([]OutboundMessage, 0, t.config.Depth),
+		notify:  make(chan struct{}, 1),
+	}
+	t.queues[peerID] = queue
+	t.writers.Add(1)
+	go t.runWriter(queue)
+	return queue
+}
+
+// runWriter sends queued messages in order until the queue is closed and
+// empty, or the transport is cancelled.
+func (t *Transport) runWriter(queue *peerQueue) {
+	defer t.writers.Done()
+	for {
+		msg, ok, done := queue.pop()
+		if done {
+			return
+		}
+		if !ok {
+			select {
+			case <-queue.notify:
+			case <-t.ctx.Done():
+				return
+			}
+			continue
+		}
+
+		sends := 1
+		if inj := chaos.Active(); inj != nil {
+			sends = t.injectFault(inj, queue, msg)
+		}
+		if sends == 0 {
+			continue
+		}
+
+		frames, err := t.frames(queue.peerID, msg)
+		for i := 0; i < sends && err == nil; i++ {
+			for _, frame := range frames {
+				if err = t.sender.Send(t.ctx, queue.peerID, frame); err != nil {
+					break
+				}
+			}
+		}
+		queue.mu.Lock()
+		if len(frames) > 1 {
+			queue.fragments += uint64(len(frames))
+		}
+		if err != nil {
+			queue.failures++
+		} else {
+			queue.sent++
+		}
+		queue.mu.Unlock()
+		if t.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// frames returns what to write for msg: its fragments when a fragmenter is
+// set and msg exceeds the MTU, msg itself otherwise.
+func (t *Transport) frames(peerID string, msg OutboundMessage) ([]OutboundMessage, error) {
+	t.mu.Lock()
+	split := t.split
+	t.mu.Unlock()
+	if split == nil {
+		return []OutboundMessage{msg}, nil
+	}
+	return split.Split(peerID, msg)
+}
+
+// injectFault applies a chaos decision to msg and returns how many times it
+// should be sent. Reordered messages go to the back of the queue once.
+func (t *Transport) injectFault(inj *chaos.Injector, queue *peerQueue, msg OutboundMessage) int {
+	fault := inj.MessageFault("", queue.peerID)
+	if fault.Drop {
+		return 0
+	}
+	if fault.Reorder && !msg.reordered {
+		msg.reordered = true
+		queue.mu.Lock()
+		queue.pending = append(queue.pending, msg)
+		queue.mu.Unlock()
+		return 0
+	}
+	if fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-t.ctx.Done():
+			return 0
+		}
+	}
+	if fault.Duplicate {
+		return 2
+	}
+	return 1
+}
+
+// push appends msg, shedding the oldest lowest-priority message when full.
+// It returns the shed message and false if anything was dropped.
+func (q *peerQueue) push(msg OutboundMessage, depth int) (OutboundMessage, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) < depth {
+		q.pending = append(q.pending, msg)
+		q.signal()
+		return OutboundMessage{}, true
+	}
+
+	victim := -1
+	for i, queued := range q.pending {
+		if queued.Priority <= msg.Priority && (victim < 0 || queued.Priority < q.pending[victim].Priority) {
+			victim = i
+		}
+	}
+	q.drops++
+	if victim < 0 {
+		return msg, false
+	}
+
+	dropped := q.pending[victim]
+	q.pending = append(q.pending[:victim], q.pending[victim+1:]...)
+	q.pending = append(q.pending, msg)
+	q.signal()
+	return dropped, false
+}
+
+func (q *peerQueue) pop() (OutboundMessage, bool, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return OutboundMessage{}, false, q.closing
+	}
+	msg := q.pending[0]
+	q.pending = q.pending[1:]
+	return msg, true, false
+}
+
+func (q *peerQueue) close() {
+	q.mu.Lock()
+	q.closing = true
+	q.mu.Unlock()
+	q.signal()
+}
+
+func (q *peerQueue) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
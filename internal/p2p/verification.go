@@ -4,18 +4,22 @@
 package p2p
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
 )
 
 // VerificationRequest represents a request to verify data from a peer
 type VerificationRequest struct {
 	RequestID string
 	PeerID    string
+	Round     int
+	Attempt   int
 	Data      []byte
 	Signature []byte
 	Timestamp time.Time
@@ -29,9 +33,14 @@ type VerificationResponse struct {
 	Proof      []byte
 	VerifiedAt time.Time
 	Confidence float64
+	// ReasonCode says why an invalid response rejected the data.
+	ReasonCode reasons.Code
 }
 
-// VerificationProtocol manages peer-to-peer verification
+// VerificationProtocol manages peer-to-peer verification. A request is
+// pending until its status check reaches a decision or it times out; it is
+// then resolved into a VerificationRecord, kept in a bounded history, and
+// its responses are freed.
 type VerificationProtocol struct {
 	mu              sync.RWMutex
 	nodeID          string
@@ -40,6 +49,15 @@ type VerificationProtocol struct {
 	verifications   map[string][]*VerificationResponse
 	minVerifiers    int
 	timeout         time.Duration
+	// pendingOrder holds pending request IDs oldest first, so expiry need
+	// not scan every request. Resolved IDs are skipped as they surface.
+	pendingOrder []string
+	resolved     *resolvedRing
+	onResolved   ResolvedCallback
+	// Resolution counts since the protocol started.
+	resolvedValid   int
+	resolvedInvalid int
+	timedOut        int
 }
 
 // PeerInfo stores information about a peer
@@ -60,20 +78,41 @@ func NewVerificationProtocol(nodeID string, minVerifiers int, timeout time.Durat
 		verifications:   make(map[string][]*VerificationResponse),
 		minVerifiers:    minVerifiers,
 		timeout:         timeout,
+		resolved:        newResolvedRing(DefaultResolvedHistory),
 	}
 }
 
-// RequestVerification initiates a verification request to peers
+// RequestVerification initiates a verification request to peers. Requesting
+// the same data again returns the pending request's ID without rebroadcasting.
 func (vp *VerificationProtocol) RequestVerification(ctx context.Context, data []byte, signature []byte) (string, error) {
-	vp.mu.Lock()
-	defer vp.mu.Unlock()
+	return vp.RequestVerificationAttempt(ctx, 0, 0, data, signature)
+}
 
-	// Generate request ID
-	requestID := vp.generateRequestID(data)
+// RequestVerificationAttempt initiates a verification request for a round.
+// Increment attempt to retry the same data under a fresh request ID.
+// Pending requests that have timed out are resolved first.
+func (vp *VerificationProtocol) RequestVerificationAttempt(ctx context.Context, round, attempt int, data []byte, signature []byte) (string, error) {
+	vp.mu.Lock()
+	expired := vp.expireLocked(time.Now())
+	callback := vp.onResolved
+	defer func() {
+		vp.mu.Unlock()
+		notifyResolved(callback, expired)
+	}()
+
+	requestID := RequestID(vp.nodeID, round, PayloadDigest(data), attempt)
+	if existing, ok := vp.pendingRequests[requestID]; ok {
+		if !bytes.Equal(existing.Data, data) {
+			return "", fmt.Errorf("%w: %s", ErrRequestIDConflict, requestID)
+		}
+		return requestID, nil
+	}
 
 	request := &VerificationRequest{
 		RequestID: requestID,
 		PeerID:    vp.nodeID,
+		Round:     round,
+		Attempt:   attempt,
 		Data:      data,
 		Signature: signature,
 		Timestamp: time.Now(),
@@ -81,9 +120,17 @@ func (vp *VerificationProtocol) RequestVerification(ctx context.Context, data []
 
 	vp.pendingRequests[requestID] = request
 	vp.verifications[requestID] = make([]*VerificationResponse, 0)
+	vp.pendingOrder = append(vp.pendingOrder, requestID)
 
-	// Broadcast verification request to peers
-	go vp.broadcastVerificationRequest(ctx, request)
+	// Broadcast verification request to peers. The peer list is copied here
+	// because the broadcast runs after the lock is released.
+	peerIDs := make([]string, 0, len(vp.peers))
+	for peerID := range vp.peers {
+		if peerID != vp.nodeID {
+			peerIDs = append(peerIDs, peerID)
+		}
+	}
+	go vp.broadcastVerificationRequest(ctx, request, peerIDs)
 
 	return requestID, nil
 }
@@ -110,18 +157,22 @@ func (vp *VerificationProtocol) VerifyData(ctx context.Context, request *Verific
 		VerifiedAt: time.Now(),
 		Confidence: confidence,
 	}
+	if !valid {
+		response.ReasonCode = reasons.InvalidSignature
+	}
 
 	return response, nil
 }
 
-// SubmitVerificationResponse records a verification response from a peer
+// SubmitVerificationResponse records a verification response from a peer.
+// Responses to a request already resolved fail with ErrUnknownRequest.
 func (vp *VerificationProtocol) SubmitVerificationResponse(ctx context.Context, response *VerificationResponse) error {
 	vp.mu.Lock()
 	defer vp.mu.Unlock()
 
 	// Check if request exists
 	if _, exists := vp.pendingRequests[response.RequestID]; !exists {
-		return fmt.Errorf("verification request %s not found", response.RequestID)
+		return fmt.Errorf("%w: %s", ErrUnknownRequest, response.RequestID)
 	}
 
 	// Add response to verifications
@@ -133,18 +184,33 @@ func (vp *VerificationProtocol) SubmitVerificationResponse(ctx context.Context,
 	return nil
 }
 
-// CheckVerificationStatus checks if verification is complete
+// CheckVerificationStatus checks if verification is complete. Once the
+// minimum number of verifiers has responded, or the request times out, the
+// request is resolved and later checks report its recorded outcome for as
+// long as the resolved history keeps it.
 func (vp *VerificationProtocol) CheckVerificationStatus(requestID string) (bool, float64, error) {
-	vp.mu.RLock()
-	defer vp.mu.RUnlock()
-
-	responses, exists := vp.verifications[requestID]
-	if !exists {
-		return false, 0, fmt.Errorf("verification request %s not found", requestID)
+	vp.mu.Lock()
+	if record, ok := vp.resolved.get(requestID); ok {
+		vp.mu.Unlock()
+		return record.status(vp.timeout, vp.minVerifiers)
+	}
+	request, pending := vp.pendingRequests[requestID]
+	if !pending {
+		vp.mu.Unlock()
+		return false, 0, fmt.Errorf("%w: %s", ErrUnknownRequest, requestID)
 	}
+	responses := vp.verifications[requestID]
 
 	// Check if minimum verifiers reached
 	if len(responses) < vp.minVerifiers {
+		if vp.timeout > 0 && time.Since(request.Timestamp) > vp.timeout {
+			record := vp.resolveLocked(request, OutcomeTimedOut, 0, time.Now())
+			callback := vp.onResolved
+			vp.mu.Unlock()
+			notifyResolved(callback, []VerificationRecord{record})
+			return record.status(vp.timeout, vp.minVerifiers)
+		}
+		vp.mu.Unlock()
 		return false, 0, nil
 	}
 
@@ -163,16 +229,32 @@ func (vp *VerificationProtocol) CheckVerificationStatus(requestID string) (bool,
 	consensusReached := validCount >= (len(responses)+1)/2
 	averageConfidence := totalConfidence / float64(len(responses))
 
+	outcome := OutcomeInvalid
+	if consensusReached {
+		outcome = OutcomeValid
+	}
+	record := vp.resolveLocked(request, outcome, averageConfidence, time.Now())
+	callback := vp.onResolved
+	vp.mu.Unlock()
+	notifyResolved(callback, []VerificationRecord{record})
 	return consensusReached, averageConfidence, nil
 }
 
+// status reports the record as CheckVerificationStatus does.
+func (r VerificationRecord) status(timeout time.Duration, minVerifiers int) (bool, float64, error) {
+	if r.Outcome == OutcomeTimedOut {
+		return false, 0, fmt.Errorf("%w: %s after %s with %d of %d responses", ErrRequestTimeout, r.RequestID, timeout, len(r.Verifiers), minVerifiers)
+	}
+	return r.Outcome == OutcomeValid, r.Confidence, nil
+}
+
 // RegisterPeer adds a new peer to the network
 func (vp *VerificationProtocol) RegisterPeer(peerID string) error {
 	vp.mu.Lock()
 	defer vp.mu.Unlock()
 
 	if _, exists := vp.peers[peerID]; exists {
-		return fmt.Errorf("peer %s already registered", peerID)
+		return fmt.Errorf("%w: %s", ErrPeerExists, peerID)
 	}
 
 	vp.peers[peerID] = &PeerInfo{
@@ -193,7 +275,7 @@ func (vp *VerificationProtocol) GetPeerReputation(peerID string) (float64, error
 
 	peer, exists := vp.peers[peerID]
 	if !exists {
-		return 0, fmt.Errorf("peer %s not found", peerID)
+		return 0, fmt.Errorf("%w: %s", ErrPeerNotFound, peerID)
 	}
 
 	return peer.ReputationScore, nil
@@ -201,22 +283,15 @@ func (vp *VerificationProtocol) GetPeerReputation(peerID string) (float64, error
 
 // Helper functions
 
-func (vp *VerificationProtocol) generateRequestID(data []byte) string {
-	hash := sha256.Sum256(data)
-	timestamp := time.Now().UnixNano()
-	combined := append(hash[:], []byte(fmt.Sprintf("%d", timestamp))...)
-	finalHash := sha256.Sum256(combined)
-	return hex.EncodeToString(finalHash[:])
-}
-
-func (vp *VerificationProtocol) broadcastVerificationRequest(ctx context.Context, request *VerificationRequest) {
+func (vp *VerificationProtocol) broadcastVerificationRequest(ctx context.Context, request *VerificationRequest, peerIDs []string) {
 	// Simulate broadcasting to all peers
 	// In production, this would use actual P2P networking
-	_ = ctx
 	_ = request
-	for peerID := range vp.peers {
-		if peerID == vp.nodeID {
-			continue
+	for range peerIDs {
+		select {
+		case <-ctx.Done():
+			return
+		default:
 		}
 		// Send verification request to peer
 		// This is a placeholder for actual network communication
@@ -291,7 +366,11 @@ func (vp *VerificationProtocol) GetVerificationMetrics() map[string]interface{}
 	return map[string]interface{}{
 		"total_peers":             len(vp.peers),
 		"pending_requests":        len(vp.pendingRequests),
-		"completed_verifications": len(vp.verifications),
+		"resolved_valid":          vp.resolvedValid,
+		"resolved_invalid":        vp.resolvedInvalid,
+		"timed_out":               vp.timedOut,
+		"completed_verifications": vp.resolvedValid + vp.resolvedInvalid,
+		"resolved_history":        len(vp.resolved.index),
 		"min_verifiers":           vp.minVerifiers,
 	}
 }
@@ -19,18 +19,32 @@ package p2p
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
 )
 
 // PeerDetail represents detailed information about a peer node
 type PeerDetail struct {
-	ID             string
-	Address        string
+	ID      string
+	Address string
+	// Addresses lists every address the peer can be dialed at.
+	Addresses []PeerAddress
+	// RelayedVia names the node relaying to the peer when it cannot be
+	// dialed. Committee policies may bound how many relayed verifiers
+	// count; see CommitteePolicy.MaxRelayedFraction.
+	RelayedVia string
+	// PublicKey is the peer's PEM channel identity key. Verification
+	// responses from a peer without one are rejected. KeyAlgorithm is its
+	// algorithm: RegisterPeer fills it in, and refuses a key whose algorithm
+	// is not the one declared, if one is.
 	PublicKey      []byte
+	KeyAlgorithm   protocol.AlgorithmID
 	TPMAttestation []byte
 	LastSeen       time.Time
 	Reputation     float64
@@ -44,6 +58,11 @@ type ModelVerificationRequest struct {
 	ProposerID   string
 	Round        int
 	Timestamp    time.Time
+	// ArtifactType selects the committee policy; empty means a model update.
+	ArtifactType ArtifactType
+	// Attempt distinguishes deliberate resubmissions of the same proposal,
+	// which would otherwise deduplicate to the pending request.
+	Attempt int
 }
 
 // ModelVerificationResponse represents a peer's verification result
@@ -52,8 +71,12 @@ type ModelVerificationResponse struct {
 	VerifierID string
 	Valid      bool
 	Signature  []byte
-	Timestamp  time.Time
-	ReasonCode string
+	// SignatureAlgorithm is the algorithm Signature is in, the verifier's.
+	SignatureAlgorithm protocol.AlgorithmID
+	Timestamp          time.Time
+	// ReasonCode says why an invalid response rejected the update. It is
+	// signed, and decoding refuses unregistered codes.
+	ReasonCode reasons.Code
 }
 
 // Verifier handles peer-to-peer verification of model updates
@@ -62,30 +85,76 @@ type Verifier struct {
 	nodeID           string
 	peers            map[string]*PeerDetail
 	verifications    map[string][]*ModelVerificationResponse
+	requestPolicies  map[string]CommitteePolicy
+	requestDigests   map[string][32]byte
+	policies         map[ArtifactType]CommitteePolicy
 	minVerifications int
 	timeout          time.Duration
+	history          map[string]*reputationHistory
+	velocity         ReputationVelocity
+	gains            map[string][]reputationGain
+	now              func() time.Time
+	trendObserver    func(peerID string, slopePerHour float64)
+	attestation      AttestationVerifier
+	spotChecks       SpotCheckConfig
+	onProbation      func(peerID string) bool
+	// decoys maps each outstanding decoy request to the verifier it was
+	// sent to; see AssignBatch.
+	decoys map[string]string
+	audits map[string]*VerifierAudit
+
+	provenance         provenance.Sink
+	provenanceSubjects map[string]provenanceSubject
+	// subjects records the update each model update request verifies; see
+	// Evidence.
+	subjects map[string]verificationSubject
 }
 
 // NewVerifier creates a new P2P verifier
 func NewVerifier(nodeID string, minVerifications int, timeout time.Duration) *Verifier {
 	return &Verifier{
-		nodeID:           nodeID,
-		peers:            make(map[string]*PeerDetail),
-		verifications:    make(map[string][]*ModelVerificationResponse),
-		minVerifications: minVerifications,
-		timeout:          timeout,
+		nodeID:             nodeID,
+		peers:              make(map[string]*PeerDetail),
+		verifications:      make(map[string][]*ModelVerificationResponse),
+		requestPolicies:    make(map[string]CommitteePolicy),
+		requestDigests:     make(map[string][32]byte),
+		policies:           DefaultCommitteePolicies(minVerifications),
+		history:            make(map[string]*reputationHistory),
+		velocity:           DefaultReputationVelocity(),
+		gains:              make(map[string][]reputationGain),
+		now:                time.Now,
+		decoys:             make(map[string]string),
+		audits:             make(map[string]*VerifierAudit),
+		minVerifications:   minVerifications,
+		provenanceSubjects: make(map[string]provenanceSubject),
+		subjects:           make(map[string]verificationSubject),
+		timeout:            timeout,
 	}
 }
 
 // RegisterPeer adds a new peer to the verification network
 func (v *Verifier) RegisterPeer(peer *PeerDetail) error {
-	v.mu.Lock()
-	defer v.mu.Unlock()
-
 	if peer.ID == "" {
-		return fmt.Errorf("peer ID cannot be empty")
+		return fmt.Errorf("%w: peer ID cannot be empty", ErrInvalidPeer)
+	}
+	if len(peer.PublicKey) > 0 {
+		algorithm, err := crypto.PublicKeyAlgorithm(peer.PublicKey)
+		if err != nil {
+			return fmt.Errorf("%w: peer %s public key: %v", ErrInvalidPeer, peer.ID, err)
+		}
+		if peer.KeyAlgorithm != "" {
+			if err := protocol.CheckAlgorithm(peer.KeyAlgorithm, algorithm); err != nil {
+				return fmt.Errorf("%w: peer %s public key: %v", ErrInvalidPeer, peer.ID, err)
+			}
+		}
+		peer.KeyAlgorithm = algorithm
+	}
+	if err := v.checkAttestation(peer); err != nil {
+		return err
 	}
 
+	v.mu.Lock()
+
 	// Initialize reputation score
 	if peer.Reputation == 0 {
 		peer.Reputation = 1.0
@@ -93,7 +162,10 @@ func (v *Verifier) RegisterPeer(peer *PeerDetail) error {
 
 	peer.LastSeen = time.Now()
 	v.peers[peer.ID] = peer
+	notify := v.recordHistoryLocked(peer.ID, peer.LastSeen, peer.Reputation, CauseRegistered)
+	v.mu.Unlock()
 
+	notify()
 	return nil
 }
 
@@ -102,82 +174,243 @@ func (v *Verifier) RemovePeer(peerID string) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	delete(v.peers, peerID)
+	delete(v.gains, peerID)
+	for requestID, verifierID := range v.decoys {
+		if verifierID == peerID {
+			delete(v.decoys, requestID)
+		}
+	}
+}
+
+// MarkRelayed records that a registered peer is reached through relayID.
+// An empty relayID marks it direct again.
+func (v *Verifier) MarkRelayed(peerID, relayID string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	peer, exists := v.peers[peerID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, peerID)
+	}
+	peer.RelayedVia = relayID
+	return nil
 }
 
-// RequestVerification broadcasts a verification request to peers
+// Reputation returns a registered peer's reputation score.
+func (v *Verifier) Reputation(peerID string) (float64, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	peer, ok := v.peers[peerID]
+	if !ok {
+		return 0, false
+	}
+	return peer.Reputation, true
+}
+
+// RequestVerification broadcasts a verification request to peers. The
+// committee policy for the request's artifact type is fixed at this point.
+// Submitting an identical request again returns the pending request's ID
+// rather than resetting its responses; reusing an ID for a different
+// payload fails with ErrRequestIDConflict.
 func (v *Verifier) RequestVerification(ctx context.Context, req *ModelVerificationRequest) (string, error) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
+	policy, err := v.policyLocked(req.ArtifactType)
+	if err != nil {
+		return "", err
+	}
+
+	digest := PayloadDigest([]byte(req.ArtifactType), req.ModelWeights, req.Proof)
 	if req.RequestID == "" {
-		req.RequestID = v.generateRequestID(req)
+		req.RequestID = RequestID(req.ProposerID, req.Round, digest, req.Attempt)
 	}
+	if _, exists := v.verifications[req.RequestID]; exists {
+		if v.requestDigests[req.RequestID] != digest {
+			return "", fmt.Errorf("%w: %s", ErrRequestIDConflict, req.RequestID)
+		}
+		return req.RequestID, nil
+	}
+	v.requestDigests[req.RequestID] = digest
 
 	v.verifications[req.RequestID] = make([]*ModelVerificationResponse, 0)
+	v.requestPolicies[req.RequestID] = policy
+	v.trackProvenanceLocked(req)
+	v.trackSubjectLocked(req)
 
 	return req.RequestID, nil
 }
 
-// SubmitVerification records a verification response from a peer
-func (v *Verifier) SubmitVerification(ctx context.Context, resp *ModelVerificationResponse) error {
-	v.mu.Lock()
-	defer v.mu.Unlock()
+// SubmitVerification records a verification response that transport peer
+// from delivered, either the verifier itself or a relay forwarding its
+// response; from must be the sender the transport authenticated, never the
+// response's self-declared VerifierID. The response counts only if its
+// signature verifies against the verifier's registered key; a response to
+// a decoy the verifier was assigned is scored against it instead. An
+// unsigned or mis-signed response is rejected with ErrResponseSignature and
+// costs from reputation, since it either forged or failed to drop the
+// response; the verifier it names is not penalized for a response it did
+// not deliver.
+func (v *Verifier) SubmitVerification(ctx context.Context, from string, resp *ModelVerificationResponse) error {
+	v.mu.RLock()
+	peer, exists := v.peers[resp.VerifierID]
+	var publicKey []byte
+	if exists {
+		publicKey = peer.PublicKey
+	}
+	_, pending := v.verifications[resp.RequestID]
+	if decoyFor, ok := v.decoys[resp.RequestID]; ok && decoyFor == resp.VerifierID {
+		pending = true
+	}
+	v.mu.RUnlock()
 
 	// Verify the peer exists
-	peer, exists := v.peers[resp.VerifierID]
 	if !exists {
-		return fmt.Errorf("unknown verifier: %s", resp.VerifierID)
+		return fmt.Errorf("%w: %s", ErrUnknownVerifier, resp.VerifierID)
 	}
 
 	// Check if request exists
-	if _, exists := v.verifications[resp.RequestID]; !exists {
-		return fmt.Errorf("unknown request: %s", resp.RequestID)
+	if !pending {
+		return fmt.Errorf("%w: %s", ErrUnknownRequest, resp.RequestID)
+	}
+
+	if len(publicKey) == 0 {
+		return fmt.Errorf("%w: verifier %s has no registered key", ErrResponseSignature, resp.VerifierID)
+	}
+	if err := resp.CheckSignature(publicKey); err != nil {
+		v.penalizeRelay(from)
+		return fmt.Errorf("%w: verifier %s on %s via %s: %v", ErrResponseSignature, resp.VerifierID, resp.RequestID, from, err)
+	}
+
+	v.mu.Lock()
+	peer, exists = v.peers[resp.VerifierID]
+	if !exists {
+		v.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownVerifier, resp.VerifierID)
+	}
+	if decoyFor, ok := v.decoys[resp.RequestID]; ok && decoyFor == resp.VerifierID {
+		notify := v.scoreDecoyLocked(peer, resp)
+		v.mu.Unlock()
+		notify()
+		return nil
+	}
+	if _, pending := v.verifications[resp.RequestID]; !pending {
+		v.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownRequest, resp.RequestID)
 	}
 
 	// Record verification
 	v.verifications[resp.RequestID] = append(v.verifications[resp.RequestID], resp)
 
 	// Update peer reputation based on response
-	v.updateReputation(peer, resp.Valid)
+	notify := v.updateReputation(peer, v.subjects[resp.RequestID].proposerID, resp.Valid)
+	v.mu.Unlock()
 
+	notify()
 	return nil
 }
 
+// penalizeRelay lowers the reputation of the peer that relayed a forged
+// response, if it is registered.
+func (v *Verifier) penalizeRelay(relayID string) {
+	v.penalize(relayID, CauseForgedResponse)
+}
+
+// PenalizeUnrevealedVote lowers the reputation of a peer that committed to a
+// vote and never revealed it, as a commit-reveal coordinator reports.
+// Unknown peers are ignored.
+func (v *Verifier) PenalizeUnrevealedVote(peerID string) {
+	v.penalize(peerID, CauseUnrevealedVote)
+}
+
+// PenalizeExtensionRequest lowers the reputation of a peer that asked to
+// extend a round's deadline more often than the extension policy allows,
+// as a scheduler.ExtensionNegotiator reports. Unknown peers are ignored.
+func (v *Verifier) PenalizeExtensionRequest(peerID string) {
+	v.penalize(peerID, CauseExtensionRequest)
+}
+
+// penalize lowers a registered peer's reputation as for an invalid
+// verification and records cause.
+func (v *Verifier) penalize(peerID, cause string) {
+	v.mu.Lock()
+	peer, exists := v.peers[peerID]
+	if !exists {
+		v.mu.Unlock()
+		return
+	}
+	peer.Reputation = max(peer.Reputation-0.2, 0.1)
+	notify := v.recordHistoryLocked(peer.ID, time.Now(), peer.Reputation, cause)
+	v.mu.Unlock()
+
+	notify()
+}
+
 // CheckVerificationStatus checks if sufficient verifications have been received
 func (v *Verifier) CheckVerificationStatus(requestID string) (bool, float64, error) {
 	v.mu.RLock()
-	defer v.mu.RUnlock()
+	passed, confidenceScore, committee, err := v.verificationStatusLocked(requestID)
+	v.mu.RUnlock()
 
+	if passed {
+		v.emitProofVerified(requestID, committee)
+	}
+	return passed, confidenceScore, err
+}
+
+// verificationStatusLocked scores requestID's responses and returns the
+// qualifying verifiers. Callers must hold v.mu.
+func (v *Verifier) verificationStatusLocked(requestID string) (bool, float64, []string, error) {
 	responses, exists := v.verifications[requestID]
 	if !exists {
-		return false, 0, fmt.Errorf("request not found: %s", requestID)
+		return false, 0, nil, fmt.Errorf("%w: %s", ErrUnknownRequest, requestID)
 	}
 
-	if len(responses) < v.minVerifications {
-		return false, 0, nil
-	}
+	policy := v.requestPolicies[requestID]
 
-	// Calculate weighted verification score based on peer reputation
+	// Calculate weighted verification score based on peer reputation,
+	// counting only verifiers off probation, at or above the committee's
+	// reputation floor and, in response order, no more relayed verifiers
+	// than the policy admits
+	qualifying := make([]string, 0, len(responses))
 	totalWeight := 0.0
 	validWeight := 0.0
+	relayed, relayedLimit := 0, policy.relayedLimit()
 
 	for _, resp := range responses {
-		if peer, exists := v.peers[resp.VerifierID]; exists {
-			totalWeight += peer.Reputation
-			if resp.Valid {
-				validWeight += peer.Reputation
+		peer, exists := v.peers[resp.VerifierID]
+		if !exists || peer.Reputation < policy.ReputationFloor {
+			continue
+		}
+		if v.onProbation != nil && v.onProbation(resp.VerifierID) {
+			continue
+		}
+		if peer.RelayedVia != "" {
+			if relayedLimit >= 0 && relayed >= relayedLimit {
+				continue
 			}
+			relayed++
+		}
+		qualifying = append(qualifying, resp.VerifierID)
+		totalWeight += peer.Reputation
+		if resp.Valid {
+			validWeight += peer.Reputation
 		}
 	}
 
+	if len(qualifying) == 0 && len(responses) >= policy.Size {
+		return false, 0, nil, ErrNoValidVerifiers
+	}
+	if len(qualifying) < policy.Size {
+		return false, 0, nil, nil
+	}
+	// Qualifying verifiers can all sit at a zero reputation floor.
 	if totalWeight == 0 {
-		return false, 0, fmt.Errorf("no valid verifiers")
+		return false, 0, nil, ErrNoValidVerifiers
 	}
 
 	confidenceScore := validWeight / totalWeight
 
-	// Require >66% confidence for Byzantine fault tolerance
-	return confidenceScore > 0.66, confidenceScore, nil
+	return confidenceScore > policy.ConfidenceThreshold, confidenceScore, qualifying, nil
 }
 
 // GetActivePeers returns list of active peers
@@ -198,19 +431,27 @@ func (v *Verifier) GetActivePeers() []*PeerDetail {
 }
 
 // updateReputation adjusts peer reputation based on verification behavior
-func (v *Verifier) updateReputation(peer *PeerDetail, valid bool) {
-	if valid {
-		peer.Reputation = min(peer.Reputation+0.1, 2.0)
-	} else {
+// and records the change. A valid verification earns what the velocity
+// limits allow for counterparty, the proposer verified; a verifier flagged
+// by a spot check earns nothing for approvals, which it may not have
+// checked. Caller holds v.mu; call the returned function after releasing
+// it.
+func (v *Verifier) updateReputation(peer *PeerDetail, counterparty string, valid bool) func() {
+	if audit, ok := v.audits[peer.ID]; valid && ok && audit.Flagged {
+		return func() {}
+	}
+	now := v.now()
+	if !valid {
 		peer.Reputation = max(peer.Reputation-0.2, 0.1)
+		return v.recordHistoryLocked(peer.ID, now, peer.Reputation, CauseVerificationInvalid)
 	}
-}
-
-// generateRequestID creates a unique ID for verification requests
-func (v *Verifier) generateRequestID(req *ModelVerificationRequest) string {
-	data := fmt.Sprintf("%s-%d-%d", req.ProposerID, req.Round, req.Timestamp.Unix())
-	hash := sha256.Sum256([]byte(data))
-	return hex.EncodeToString(hash[:])
+	earned, cause := v.limitGainLocked(peer, counterparty, now)
+	peer.Reputation = min(peer.Reputation+earned, 2.0)
+	sample := ReputationSample{Timestamp: now, Score: peer.Reputation, Cause: cause}
+	if cause != CauseVerificationValid {
+		sample.Withheld = verificationReward - earned
+	}
+	return v.recordSampleLocked(peer.ID, sample)
 }
 
 func min(a, b float64) float64 {
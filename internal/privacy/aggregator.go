@@ -0,0 +1,203 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package privacy
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// DPAggregatorConfig configures central differential privacy for an
+// aggregator's released model.
+type DPAggregatorConfig struct {
+	// PerRound is the (ε, δ) each release is calibrated to.
+	PerRound Budget `json:"per_round"`
+	// Allocation is the most the releases may compose to.
+	Allocation Budget `json:"allocation"`
+	// ClipNorm bounds the L2 norm of every included update; longer updates
+	// are scaled down to it before aggregation.
+	ClipNorm float64 `json:"clip_norm"`
+	// ExpectedCohort is the number of participants rounds are planned for.
+	// It is recorded with each release; the noise is calibrated to the
+	// cohort actually included.
+	ExpectedCohort int `json:"expected_cohort"`
+	// MinCohort is the fewest included participants a release is made
+	// for. Below it one participant's update dominates the aggregate, so
+	// the noise needed for PerRound would leave it useless, and the round
+	// is refused instead.
+	MinCohort int `json:"min_cohort"`
+}
+
+// DefaultDPAggregatorConfig releases at the SGP-001 (ε=1, δ=1e-5) per
+// round within an allocation of a hundred rounds, clips updates to unit
+// norm, and plans for cohorts of 100, refusing rounds of fewer than 20.
+func DefaultDPAggregatorConfig() DPAggregatorConfig {
+	return DPAggregatorConfig{
+		PerRound:       Budget{Epsilon: 1.0, Delta: 1e-5},
+		Allocation:     Budget{Epsilon: 100, Delta: 1e-3},
+		ClipNorm:       1.0,
+		ExpectedCohort: 100,
+		MinCohort:      20,
+	}
+}
+
+// Validate checks the budgets, the clip norm, and that
+// 1 <= MinCohort <= ExpectedCohort.
+func (c DPAggregatorConfig) Validate() error {
+	if err := c.PerRound.Validate(); err != nil {
+		return fmt.Errorf("per-round budget: %w", err)
+	}
+	if err := c.Allocation.Validate(); err != nil {
+		return fmt.Errorf("allocation: %w", err)
+	}
+	if c.PerRound.Delta == 0 {
+		return fmt.Errorf("%w: the Gaussian mechanism needs a positive delta", ErrInvalidBudget)
+	}
+	if exceeds(c.PerRound, c.Allocation) {
+		return fmt.Errorf("%w: the allocation cannot afford a single round", ErrInvalidBudget)
+	}
+	if !(c.ClipNorm > 0) || math.IsInf(c.ClipNorm, 0) {
+		return fmt.Errorf("%w: clip norm must be positive and finite, got %g", ErrInvalidBudget, c.ClipNorm)
+	}
+	if c.MinCohort < 1 || c.MinCohort > c.ExpectedCohort {
+		return fmt.Errorf("%w: need 1 <= min cohort <= expected cohort, got %d and %d", ErrInvalidBudget, c.MinCohort, c.ExpectedCohort)
+	}
+	return nil
+}
+
+// DPAggregator adds central Gaussian noise to aggregates and accounts for
+// the privacy each release spends. The noise is calibrated at release time
+// to the participants actually included, not the cohort the round was
+// planned for: one participant's influence on a weighted mean is the clip
+// norm times its applied weight, so a round where only 60 of 200 expected
+// participants report needs over three times the noise for the same
+// guarantee. Noise calibrated to the planned cohort would instead spend
+// over three times the planned ε, unaccounted.
+type DPAggregator struct {
+	mu       sync.Mutex
+	config   DPAggregatorConfig
+	dp       *DifferentialPrivacy
+	spent    Budget
+	releases []protocol.PrivacyRelease
+}
+
+// NewDPAggregator creates an aggregator with nothing spent.
+func NewDPAggregator(config DPAggregatorConfig) (*DPAggregator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &DPAggregator{
+		config: config,
+		dp: NewDifferentialPrivacy(&SGP001Config{
+			Epsilon:       config.PerRound.Epsilon,
+			Delta:         config.PerRound.Delta,
+			L2Sensitivity: config.ClipNorm,
+		}),
+	}, nil
+}
+
+// Config returns the aggregator's configuration.
+func (a *DPAggregator) Config() DPAggregatorConfig {
+	return a.config
+}
+
+// SetNoiseSource draws the aggregator's noise from source; see
+// DifferentialPrivacy.SetNoiseSource. Production code must never call it.
+func (a *DPAggregator) SetNoiseSource(source io.Reader) {
+	a.dp.SetNoiseSource(source)
+}
+
+// Clip returns update scaled down to the clip norm, or update itself if it
+// is within it.
+func (a *DPAggregator) Clip(update []float64) []float64 {
+	return a.dp.clipGradients(update, a.config.ClipNorm)
+}
+
+// NoiseScaleFor is the noise scale a release over participants equally
+// weighted updates carries.
+func (a *DPAggregator) NoiseScaleFor(participants int) float64 {
+	return gaussianNoiseScale(a.config.ClipNorm/float64(participants), a.config.PerRound)
+}
+
+// Privatize adds noise to aggregate, the weighted mean of clipped updates
+// with the given applied weights, one per included participant, and
+// charges the release to the accountant. Fewer weights than MinCohort
+// fail with ErrCohortTooSmall, and a release the allocation cannot afford
+// fails with ErrBudgetExceeded; either way aggregate is left unchanged and
+// nothing is charged.
+func (a *DPAggregator) Privatize(round int, aggregate, weights []float64) (protocol.PrivacyRelease, error) {
+	if len(weights) < a.config.MinCohort {
+		return protocol.PrivacyRelease{}, fmt.Errorf("round %d: %w: %d participants, need %d", round, ErrCohortTooSmall, len(weights), a.config.MinCohort)
+	}
+	largest := 0.0
+	for _, weight := range weights {
+		if !(weight > 0) || weight > 1 {
+			return protocol.PrivacyRelease{}, fmt.Errorf("round %d: applied weights must be in (0, 1], got %g", round, weight)
+		}
+		largest = math.Max(largest, weight)
+	}
+
+	sensitivity := a.config.ClipNorm * largest
+	sigma := gaussianNoiseScale(sensitivity, a.config.PerRound)
+	release := protocol.PrivacyRelease{
+		Round:          round,
+		Participants:   len(weights),
+		ExpectedCohort: a.config.ExpectedCohort,
+		L2Sensitivity:  sensitivity,
+		NoiseScale:     sigma,
+		// Charged from the noise actually added, not the configured
+		// budget, so the accountant stays right if the calibration ever
+		// drifts from PerRound.
+		Epsilon: GaussianEpsilon(sensitivity, sigma, a.config.PerRound.Delta),
+		Delta:   a.config.PerRound.Delta,
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	next := Budget{Epsilon: a.spent.Epsilon + release.Epsilon, Delta: a.spent.Delta + release.Delta}
+	if exceeds(next, a.config.Allocation) {
+		return protocol.PrivacyRelease{}, fmt.Errorf("round %d: %w: would spend ε=%.4g δ=%.3g of ε=%.4g δ=%.3g",
+			round, ErrBudgetExceeded, next.Epsilon, next.Delta, a.config.Allocation.Epsilon, a.config.Allocation.Delta)
+	}
+	noised, err := a.dp.gaussianVector(aggregate, sigma)
+	if err != nil {
+		return protocol.PrivacyRelease{}, fmt.Errorf("round %d: failed to generate noise: %w", round, err)
+	}
+	copy(aggregate, noised)
+	a.spent = next
+	a.releases = append(a.releases, release)
+	return release, nil
+}
+
+// Spent returns what the releases so far compose to, sequentially.
+func (a *DPAggregator) Spent() Budget {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.spent
+}
+
+// Releases returns the privacy audit log: every release charged, oldest
+// first.
+func (a *DPAggregator) Releases() []protocol.PrivacyRelease {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]protocol.PrivacyRelease(nil), a.releases...)
+}
+
+// gaussianNoiseScale is the Gaussian mechanism's σ for sensitivity at
+// budget: Δ * sqrt(2 ln(1.25/δ)) / ε.
+func gaussianNoiseScale(sensitivity float64, budget Budget) float64 {
+	return sensitivity * math.Sqrt(2*math.Log(1.25/budget.Delta)) / budget.Epsilon
+}
+
+// GaussianEpsilon is the ε that Gaussian noise of scale sigma buys for a
+// release of the given L2 sensitivity at delta, the inverse of the
+// calibration DifferentialPrivacy applies.
+func GaussianEpsilon(sensitivity, sigma, delta float64) float64 {
+	return sensitivity * math.Sqrt(2*math.Log(1.25/delta)) / sigma
+}
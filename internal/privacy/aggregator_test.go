@@ -0,0 +1,158 @@
+package privacy
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// equalWeights is the applied weights of n equally weighted participants.
+func equalWeights(n int) []float64 {
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 1 / float64(n)
+	}
+	return weights
+}
+
+// sampleStddev is the standard deviation of noised around center.
+func sampleStddev(noised []float64, center float64) float64 {
+	sum := 0.0
+	for _, v := range noised {
+		sum += (v - center) * (v - center)
+	}
+	return math.Sqrt(sum / float64(len(noised)))
+}
+
+func newTestDPAggregator(t *testing.T, config DPAggregatorConfig) *DPAggregator {
+	t.Helper()
+	dp, err := NewDPAggregator(config)
+	if err != nil {
+		t.Fatalf("new dp aggregator: %v", err)
+	}
+	dp.SetNoiseSource(rand.New(rand.NewSource(719)))
+	return dp
+}
+
+func TestDPAggregatorCalibratesNoiseToTheActualCohort(t *testing.T) {
+	config := DefaultDPAggregatorConfig()
+	config.ExpectedCohort = 200
+	config.MinCohort = 50
+	dp := newTestDPAggregator(t, config)
+
+	// Normal cohort: all 200 report, and the release costs the per-round
+	// budget at the noise planned for.
+	aggregate := make([]float64, 20000)
+	normal, err := dp.Privatize(1, aggregate, equalWeights(200))
+	if err != nil {
+		t.Fatalf("normal cohort: %v", err)
+	}
+	if normal.Participants != 200 || normal.ExpectedCohort != 200 || math.Abs(normal.L2Sensitivity-1.0/200) > 1e-12 {
+		t.Fatalf("normal release = %+v", normal)
+	}
+	if math.Abs(normal.NoiseScale-dp.NoiseScaleFor(200)) > 1e-12 || math.Abs(normal.Epsilon-1) > 1e-9 || normal.Delta != 1e-5 {
+		t.Fatalf("normal release = %+v, want σ=%g at ε=1", normal, dp.NoiseScaleFor(200))
+	}
+	if got := sampleStddev(aggregate, 0); math.Abs(got/normal.NoiseScale-1) > 0.03 {
+		t.Fatalf("noise stddev = %g, want %g", got, normal.NoiseScale)
+	}
+
+	// Reduced cohort: 60 of 200 report. Each participant now moves the
+	// mean 200/60 times as far, so the noise grows by as much and the
+	// release still costs the per-round budget.
+	aggregate = make([]float64, 20000)
+	reduced, err := dp.Privatize(2, aggregate, equalWeights(60))
+	if err != nil {
+		t.Fatalf("reduced cohort: %v", err)
+	}
+	if ratio := reduced.NoiseScale / normal.NoiseScale; reduced.Participants != 60 || math.Abs(ratio-200.0/60) > 1e-9 {
+		t.Fatalf("reduced release = %+v, noise ratio %g, want %g", reduced, ratio, 200.0/60)
+	}
+	if math.Abs(reduced.Epsilon-1) > 1e-9 {
+		t.Fatalf("reduced release charged ε=%g, want 1", reduced.Epsilon)
+	}
+	if got := sampleStddev(aggregate, 0); math.Abs(got/reduced.NoiseScale-1) > 0.03 {
+		t.Fatalf("noise stddev = %g, want %g", got, reduced.NoiseScale)
+	}
+	// The noise planned for 200 would have cost 200/60 times ε on 60.
+	if planned := GaussianEpsilon(reduced.L2Sensitivity, normal.NoiseScale, reduced.Delta); math.Abs(planned-200.0/60) > 1e-9 {
+		t.Fatalf("planned noise on the reduced cohort buys ε=%g, want %g", planned, 200.0/60)
+	}
+
+	// Below the minimum: refused, the aggregate untouched, nothing charged.
+	aggregate = []float64{0.5, -0.5}
+	if _, err := dp.Privatize(3, aggregate, equalWeights(49)); !errors.Is(err, ErrCohortTooSmall) {
+		t.Fatalf("tiny cohort: expected ErrCohortTooSmall, got %v", err)
+	}
+	if aggregate[0] != 0.5 || aggregate[1] != -0.5 {
+		t.Fatalf("refused round noised the aggregate: %v", aggregate)
+	}
+
+	// The accountant charges each release's own ε and δ.
+	releases := dp.Releases()
+	if len(releases) != 2 || releases[0].Round != 1 || releases[1].Round != 2 {
+		t.Fatalf("audit log = %+v", releases)
+	}
+	spent := Budget{Epsilon: normal.Epsilon + reduced.Epsilon, Delta: normal.Delta + reduced.Delta}
+	if !closeBudget(dp.Spent(), spent) {
+		t.Fatalf("spent %+v, want %+v", dp.Spent(), spent)
+	}
+}
+
+func TestDPAggregatorChargesTheLargestAppliedWeight(t *testing.T) {
+	dp := newTestDPAggregator(t, DefaultDPAggregatorConfig())
+
+	// A participant with a quarter of the weight bounds the sensitivity,
+	// however many others there are.
+	weights := append([]float64{0.25}, equalWeights(75)...)
+	for i := 1; i < len(weights); i++ {
+		weights[i] *= 0.75
+	}
+	release, err := dp.Privatize(1, make([]float64, 4), weights)
+	if err != nil {
+		t.Fatalf("privatize: %v", err)
+	}
+	if math.Abs(release.L2Sensitivity-0.25) > 1e-12 || math.Abs(release.NoiseScale-dp.NoiseScaleFor(4)) > 1e-12 {
+		t.Fatalf("release = %+v, want the sensitivity of a quarter weight", release)
+	}
+	if clipped := dp.Clip([]float64{3, 4}); math.Abs(clipped[0]-0.6) > 1e-12 || math.Abs(clipped[1]-0.8) > 1e-12 {
+		t.Fatalf("clipped = %v, want unit norm", clipped)
+	}
+}
+
+func TestDPAggregatorStopsAtItsAllocation(t *testing.T) {
+	config := DefaultDPAggregatorConfig()
+	config.Allocation = Budget{Epsilon: 3, Delta: 1e-3}
+	dp := newTestDPAggregator(t, config)
+	for round := 1; round <= 3; round++ {
+		if _, err := dp.Privatize(round, make([]float64, 4), equalWeights(100)); err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+	}
+	if _, err := dp.Privatize(4, make([]float64, 4), equalWeights(100)); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("fourth round: expected ErrBudgetExceeded, got %v", err)
+	}
+	if len(dp.Releases()) != 3 || !closeBudget(dp.Spent(), Budget{Epsilon: 3, Delta: 3e-5}) {
+		t.Fatalf("refused round was charged: %+v", dp.Spent())
+	}
+}
+
+func TestDPAggregatorConfigValidate(t *testing.T) {
+	if err := DefaultDPAggregatorConfig().Validate(); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+	for name, mutate := range map[string]func(*DPAggregatorConfig){
+		"no delta":            func(c *DPAggregatorConfig) { c.PerRound.Delta = 0 },
+		"round over budget":   func(c *DPAggregatorConfig) { c.Allocation.Epsilon = 0.5 },
+		"no clip norm":        func(c *DPAggregatorConfig) { c.ClipNorm = 0 },
+		"no minimum":          func(c *DPAggregatorConfig) { c.MinCohort = 0 },
+		"minimum over cohort": func(c *DPAggregatorConfig) { c.MinCohort = c.ExpectedCohort + 1 },
+	} {
+		config := DefaultDPAggregatorConfig()
+		mutate(&config)
+		if err := config.Validate(); !errors.Is(err, ErrInvalidBudget) {
+			t.Fatalf("%s: expected ErrInvalidBudget, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package privacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Budget is an (ε, δ) differential privacy guarantee.
+type Budget struct {
+	Epsilon float64 `json:"epsilon"`
+	Delta   float64 `json:"delta"`
+}
+
+// Validate checks that the budget is a usable guarantee: a positive, finite
+// epsilon and a delta in [0, 1).
+func (b Budget) Validate() error {
+	if !(b.Epsilon > 0) || math.IsInf(b.Epsilon, 0) || b.Delta < 0 || b.Delta >= 1 {
+		return fmt.Errorf("%w: epsilon %g, delta %g", ErrInvalidBudget, b.Epsilon, b.Delta)
+	}
+	return nil
+}
+
+// ShardBudget configures one shard's accountant.
+type ShardBudget struct {
+	// Allocation is the most the shard's releases may compose to.
+	Allocation Budget `json:"allocation"`
+	// PerRound is the guarantee of one round's release by the shard.
+	PerRound Budget `json:"per_round"`
+	// L2Sensitivity bounds one participant's influence on a release.
+	L2Sensitivity float64 `json:"l2_sensitivity"`
+}
+
+// ShardState is a shard accountant's position.
+type ShardState struct {
+	ShardID    string `json:"shard_id"`
+	Allocation Budget `json:"allocation"`
+	PerRound   Budget `json:"per_round"`
+	Spent      Budget `json:"spent"`
+	Rounds     int    `json:"rounds"`
+}
+
+// RegistryState is the budget registry's position: every shard and the
+// global guarantee they compose to.
+type RegistryState struct {
+	Global    Budget       `json:"global"`
+	LastRound int          `json:"last_round"`
+	Shards    []ShardState `json:"shards"`
+}
+
+type shardAccountant struct {
+	budget ShardBudget
+	dp     *DifferentialPrivacy
+	rounds int
+}
+
+// spent composes the shard's rounds sequentially: k releases at (ε, δ)
+// spend (kε, kδ).
+func (s *shardAccountant) spent(rounds int) Budget {
+	return Budget{
+		Epsilon: float64(rounds) * s.budget.PerRound.Epsilon,
+		Delta:   float64(rounds) * s.budget.PerRound.Delta,
+	}
+}
+
+// BudgetRegistry holds a privacy accountant and a DifferentialPrivacy
+// instance for each regional shard of a hierarchical aggregation. Shards
+// cover disjoint populations, so their releases compose in parallel: the
+// global guarantee is the largest any shard has spent, while each shard's
+// own rounds compose sequentially.
+type BudgetRegistry struct {
+	mu        sync.RWMutex
+	shards    map[string]*shardAccountant
+	lastRound int
+}
+
+// NewBudgetRegistry creates an empty registry.
+func NewBudgetRegistry() *BudgetRegistry {
+	return &BudgetRegistry{shards: make(map[string]*shardAccountant)}
+}
+
+// LoadBudgetRegistry reads a JSON object mapping shard IDs to ShardBudgets.
+func LoadBudgetRegistry(path string) (*BudgetRegistry, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- operator-supplied config path
+	if err != nil {
+		return nil, fmt.Errorf("read privacy budgets: %w", err)
+	}
+	var budgets map[string]ShardBudget
+	if err := json.Unmarshal(raw, &budgets); err != nil {
+		return nil, fmt.Errorf("parse privacy budgets: %w", err)
+	}
+	registry := NewBudgetRegistry()
+	for shardID, budget := range budgets {
+		if err := registry.Register(shardID, budget); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Register adds a shard with a fresh accountant.
+func (r *BudgetRegistry) Register(shardID string, budget ShardBudget) error {
+	if shardID == "" {
+		return fmt.Errorf("%w: empty shard id", ErrInvalidBudget)
+	}
+	if err := budget.Allocation.Validate(); err != nil {
+		return fmt.Errorf("shard %s allocation: %w", shardID, err)
+	}
+	if err := budget.PerRound.Validate(); err != nil {
+		return fmt.Errorf("shard %s per-round budget: %w", shardID, err)
+	}
+	if budget.PerRound.Epsilon > budget.Allocation.Epsilon || budget.PerRound.Delta > budget.Allocation.Delta {
+		return fmt.Errorf("%w: shard %s cannot afford a single round", ErrInvalidBudget, shardID)
+	}
+	if budget.L2Sensitivity <= 0 {
+		return fmt.Errorf("%w: shard %s sensitivity %g", ErrInvalidBudget, shardID, budget.L2Sensitivity)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.shards[shardID]; exists {
+		return fmt.Errorf("%w: shard %s already registered", ErrInvalidBudget, shardID)
+	}
+	r.shards[shardID] = &shardAccountant{
+		budget: budget,
+		dp: NewDifferentialPrivacy(&SGP001Config{
+			Epsilon:       budget.PerRound.Epsilon,
+			Delta:         budget.PerRound.Delta,
+			L2Sensitivity: budget.L2Sensitivity,
+		}),
+	}
+	return nil
+}
+
+// Shard returns the DifferentialPrivacy instance a shard noises its
+// per-round release with.
+func (r *BudgetRegistry) Shard(shardID string) (*DifferentialPrivacy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	shard, ok := r.shards[shardID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownShard, shardID)
+	}
+	return shard.dp, nil
+}
+
+// BeginGlobalRound charges one round to each contributing shard. If any
+// shard would exceed its allocation, the round is refused with
+// ErrBudgetExceeded and no shard is charged.
+func (r *BudgetRegistry) BeginGlobalRound(round int, shardIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	charged := make([]*shardAccountant, 0, len(shardIDs))
+	seen := make(map[string]bool, len(shardIDs))
+	for _, shardID := range shardIDs {
+		if seen[shardID] {
+			continue
+		}
+		seen[shardID] = true
+		shard, ok := r.shards[shardID]
+		if !ok {
+			return fmt.Errorf("round %d: %w: %s", round, ErrUnknownShard, shardID)
+		}
+		next := shard.spent(shard.rounds + 1)
+		if exceeds(next, shard.budget.Allocation) {
+			return fmt.Errorf("round %d: %w: shard %s would spend ε=%.4g δ=%.3g of ε=%.4g δ=%.3g",
+				round, ErrBudgetExceeded, shardID, next.Epsilon, next.Delta, shard.budget.Allocation.Epsilon, shard.budget.Allocation.Delta)
+		}
+		charged = append(charged, shard)
+	}
+	for _, shard := range charged {
+		shard.rounds++
+	}
+	r.lastRound = round
+	return nil
+}
+
+// exceeds compares with a relative tolerance, so a shard whose allocation
+// is an exact multiple of its per-round cost can spend all of it.
+func exceeds(spent, allocation Budget) bool {
+	const tolerance = 1e-9
+	return spent.Epsilon > allocation.Epsilon*(1+tolerance) || spent.Delta > allocation.Delta*(1+tolerance)
+}
+
+// Global returns the effective global guarantee: by parallel composition
+// over disjoint shards, the largest ε and δ any shard has spent.
+func (r *BudgetRegistry) Global() Budget {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.globalLocked()
+}
+
+func (r *BudgetRegistry) globalLocked() Budget {
+	var global Budget
+	for _, shard := range r.shards {
+		spent := shard.spent(shard.rounds)
+		global.Epsilon = math.Max(global.Epsilon, spent.Epsilon)
+		global.Delta = math.Max(global.Delta, spent.Delta)
+	}
+	return global
+}
+
+// State returns every shard's accountant, sorted by shard ID, and the
+// global guarantee.
+func (r *BudgetRegistry) State() RegistryState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	state := RegistryState{
+		Global:    r.globalLocked(),
+		LastRound: r.lastRound,
+		Shards:    make([]ShardState, 0, len(r.shards)),
+	}
+	for shardID, shard := range r.shards {
+		state.Shards = append(state.Shards, ShardState{
+			ShardID:    shardID,
+			Allocation: shard.budget.Allocation,
+			PerRound:   shard.budget.PerRound,
+			Spent:      shard.spent(shard.rounds),
+			Rounds:     shard.rounds,
+		})
+	}
+	sort.Slice(state.Shards, func(i, j int) bool { return state.Shards[i].ShardID < state.Shards[j].ShardID })
+	return state
+}
@@ -0,0 +1,115 @@
+package privacy
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBudgetRegistryComposesDisjointShards(t *testing.T) {
+	registry := NewBudgetRegistry()
+	shards := map[string]ShardBudget{
+		"eu":   {Allocation: Budget{Epsilon: 1.0, Delta: 1e-5}, PerRound: Budget{Epsilon: 0.1, Delta: 1e-7}, L2Sensitivity: 1},
+		"us":   {Allocation: Budget{Epsilon: 1.0, Delta: 1e-5}, PerRound: Budget{Epsilon: 0.25, Delta: 2e-7}, L2Sensitivity: 1},
+		"apac": {Allocation: Budget{Epsilon: 2.0, Delta: 1e-5}, PerRound: Budget{Epsilon: 0.2, Delta: 5e-7}, L2Sensitivity: 1},
+	}
+	for id, budget := range shards {
+		if err := registry.Register(id, budget); err != nil {
+			t.Fatalf("register %s: %v", id, err)
+		}
+	}
+	if dp, err := registry.Shard("eu"); err != nil || dp == nil {
+		t.Fatalf("shard dp: %v", err)
+	}
+
+	// apac sits out round 2, so it spends sequentially over 3 rounds while
+	// eu and us spend over 4.
+	rounds := [][]string{{"eu", "us", "apac"}, {"eu", "us"}, {"eu", "us", "apac"}, {"eu", "us", "apac"}}
+	for i, participants := range rounds {
+		if err := registry.BeginGlobalRound(i+1, participants); err != nil {
+			t.Fatalf("round %d: %v", i+1, err)
+		}
+	}
+
+	// Sequential within a shard: k rounds at (ε, δ) spend (kε, kδ).
+	want := map[string]Budget{
+		"eu":   {Epsilon: 4 * 0.1, Delta: 4 * 1e-7},
+		"us":   {Epsilon: 4 * 0.25, Delta: 4 * 2e-7},
+		"apac": {Epsilon: 3 * 0.2, Delta: 3 * 5e-7},
+	}
+	state := registry.State()
+	if len(state.Shards) != 3 || state.Shards[0].ShardID != "apac" || state.LastRound != 4 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+	for _, shard := range state.Shards {
+		if !closeBudget(shard.Spent, want[shard.ShardID]) {
+			t.Fatalf("shard %s spent %+v, want %+v", shard.ShardID, shard.Spent, want[shard.ShardID])
+		}
+	}
+	// Parallel across disjoint shards: the global guarantee is the largest
+	// ε and the largest δ any shard spent.
+	global := Budget{Epsilon: 1.0, Delta: 1.5e-6}
+	if !closeBudget(state.Global, global) || !closeBudget(registry.Global(), global) {
+		t.Fatalf("global %+v, want %+v", state.Global, global)
+	}
+
+	// us has spent its whole ε allocation, so a round including it is
+	// refused and no shard is charged, while a round without it proceeds.
+	if err := registry.BeginGlobalRound(5, []string{"eu", "us"}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	if spent := registry.State().Shards[1].Spent; !closeBudget(spent, want["eu"]) {
+		t.Fatalf("refused round charged eu: %+v", spent)
+	}
+	if err := registry.BeginGlobalRound(5, []string{"eu", "apac"}); err != nil {
+		t.Fatalf("round without the exhausted shard: %v", err)
+	}
+	if err := registry.BeginGlobalRound(6, []string{"mars"}); !errors.Is(err, ErrUnknownShard) {
+		t.Fatalf("expected ErrUnknownShard, got %v", err)
+	}
+}
+
+func closeBudget(got, want Budget) bool {
+	return math.Abs(got.Epsilon-want.Epsilon) < 1e-12 && math.Abs(got.Delta-want.Delta) < 1e-18
+}
+
+func TestBudgetRegistryRejectsInvalidBudgets(t *testing.T) {
+	registry := NewBudgetRegistry()
+	valid := ShardBudget{Allocation: Budget{Epsilon: 1, Delta: 1e-5}, PerRound: Budget{Epsilon: 0.1, Delta: 1e-6}, L2Sensitivity: 1}
+	cases := map[string]ShardBudget{
+		"zero epsilon":      {Allocation: Budget{Delta: 1e-5}, PerRound: valid.PerRound, L2Sensitivity: 1},
+		"round over budget": {Allocation: valid.Allocation, PerRound: Budget{Epsilon: 2, Delta: 1e-6}, L2Sensitivity: 1},
+		"no sensitivity":    {Allocation: valid.Allocation, PerRound: valid.PerRound},
+	}
+	for name, budget := range cases {
+		if err := registry.Register("eu", budget); !errors.Is(err, ErrInvalidBudget) {
+			t.Fatalf("%s: expected ErrInvalidBudget, got %v", name, err)
+		}
+	}
+	if err := registry.Register("eu", valid); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := registry.Register("eu", valid); !errors.Is(err, ErrInvalidBudget) {
+		t.Fatalf("expected duplicate shard rejected, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "budgets.json")
+	config := `{"eu":{"allocation":{"epsilon":1,"delta":1e-5},"per_round":{"epsilon":0.5,"delta":1e-6},"l2_sensitivity":1}}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	loaded, err := LoadBudgetRegistry(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for round := 1; round <= 2; round++ {
+		if err := loaded.BeginGlobalRound(round, []string{"eu"}); err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+	}
+	if err := loaded.BeginGlobalRound(3, []string{"eu"}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected the third round refused, got %v", err)
+	}
+}
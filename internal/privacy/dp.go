@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 	"sync"
 )
@@ -31,6 +32,7 @@ func NewSGP001Config() *SGP001Config {
 type DifferentialPrivacy struct {
 	config     *SGP001Config
 	budgetUsed float64
+	noise      io.Reader
 	mu         sync.RWMutex
 }
 
@@ -39,9 +41,20 @@ func NewDifferentialPrivacy(config *SGP001Config) *DifferentialPrivacy {
 	return &DifferentialPrivacy{
 		config:     config,
 		budgetUsed: 0.0,
+		noise:      rand.Reader,
 	}
 }
 
+// SetNoiseSource replaces crypto/rand as the source noise is drawn from.
+// Simulations pass a seeded source so runs are reproducible; noise from a
+// predictable source provides no privacy, so production code must never
+// call this.
+func (dp *DifferentialPrivacy) SetNoiseSource(source io.Reader) {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+	dp.noise = source
+}
+
 // AddGaussianNoise adds calibrated Gaussian noise for differential privacy
 // Implements the Gaussian mechanism for (ε,δ)-differential privacy
 func (dp *DifferentialPrivacy) AddGaussianNoise(value float64) (float64, error) {
@@ -105,12 +118,12 @@ func (dp *DifferentialPrivacy) gaussianNoise(mean, stddev float64) (float64, err
 
 	// Generate uniform random numbers
 	buf := make([]byte, 8)
-	if _, err := rand.Read(buf); err != nil {
+	if _, err := io.ReadFull(dp.noise, buf); err != nil {
 		return 0, err
 	}
 	u1 = float64(binary.BigEndian.Uint64(buf)) / float64(math.MaxUint64)
 
-	if _, err := rand.Read(buf); err != nil {
+	if _, err := io.ReadFull(dp.noise, buf); err != nil {
 		return 0, err
 	}
 	u2 = float64(binary.BigEndian.Uint64(buf)) / float64(math.MaxUint64)
@@ -120,10 +133,26 @@ func (dp *DifferentialPrivacy) gaussianNoise(mean, stddev float64) (float64, err
 	return mean + z0*stddev, nil
 }
 
+// gaussianVector returns a copy of values with independent Gaussian noise
+// of scale sigma added to each element.
+func (dp *DifferentialPrivacy) gaussianVector(values []float64, sigma float64) ([]float64, error) {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+	noised := make([]float64, len(values))
+	for i, value := range values {
+		noise, err := dp.gaussianNoise(0, sigma)
+		if err != nil {
+			return nil, err
+		}
+		noised[i] = value + noise
+	}
+	return noised, nil
+}
+
 // laplaceNoise generates noise from a Laplace distribution
 func (dp *DifferentialPrivacy) laplaceNoise(scale float64) (float64, error) {
 	buf := make([]byte, 8)
-	if _, err := rand.Read(buf); err != nil {
+	if _, err := io.ReadFull(dp.noise, buf); err != nil {
 		return 0, err
 	}
 
@@ -0,0 +1,25 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package privacy
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the budget registry and the DP
+// aggregator. Match them with errors.Is; never compare error strings.
+var (
+	// ErrBudgetExceeded means a round would take a shard, or a DP
+	// aggregator, past its privacy allocation. Not retryable; the budget is
+	// spent.
+	ErrBudgetExceeded = errors.New("privacy budget exceeded")
+	// ErrUnknownShard means no budget is registered for the shard. Not
+	// retryable until the shard is registered.
+	ErrUnknownShard = errors.New("unknown privacy shard")
+	// ErrCohortTooSmall means a round included fewer participants than
+	// the central DP aggregator's minimum cohort. Retryable once more
+	// participants report, such as after a deadline extension.
+	ErrCohortTooSmall = errors.New("cohort below minimum")
+	// ErrInvalidBudget means a budget is malformed or a shard is registered
+	// twice. Not retryable.
+	ErrInvalidBudget = errors.New("invalid privacy budget")
+)
@@ -0,0 +1,95 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package privacy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// EvaluationBudget accounts a node's evaluation reports against its
+// metric-DP allocation. Reports are noised by the node's MetricsPrivatizer
+// and never touch the model privacy budget; each noised metric spends the
+// privatizer's ε, composed sequentially, and a report that would exceed the
+// allocation is refused.
+type EvaluationBudget struct {
+	privatizer *MetricsPrivatizer
+	allocation float64
+
+	mu      sync.Mutex
+	spent   float64
+	reports int
+}
+
+// NewEvaluationBudget spends at most allocation ε on evaluation reports
+// noised by privatizer.
+func NewEvaluationBudget(privatizer *MetricsPrivatizer, allocation float64) (*EvaluationBudget, error) {
+	if privatizer == nil {
+		return nil, fmt.Errorf("%w: evaluation budget needs a metrics privatizer", ErrInvalidBudget)
+	}
+	if !(allocation > 0) {
+		return nil, fmt.Errorf("%w: evaluation allocation %g", ErrInvalidBudget, allocation)
+	}
+	return &EvaluationBudget{privatizer: privatizer, allocation: allocation}, nil
+}
+
+// Privatize returns report with the metrics task asks for clamped to their
+// ranges and noised, the rest zeroed, and the sample count bucketed. It
+// fails with ErrBudgetExceeded, leaving the budget untouched, when the
+// report would take the node past its allocation, and with ErrInvalidBudget
+// when a metric's range is wider than the privatizer's sensitivity.
+func (b *EvaluationBudget) Privatize(task protocol.EvaluationTask, report protocol.EvaluationReport) (protocol.EvaluationReport, error) {
+	loss, hasLoss := task.Metric(protocol.MetricLoss)
+	accuracy, hasAccuracy := task.Metric(protocol.MetricAccuracy)
+	cost := 0.0
+	for _, metric := range task.Metrics {
+		if width := metric.Upper - metric.Lower; width > b.privatizer.config.Sensitivity {
+			return protocol.EvaluationReport{}, fmt.Errorf("%w: metric %s range %g exceeds sensitivity %g", ErrInvalidBudget, metric.Name, width, b.privatizer.config.Sensitivity)
+		}
+		cost += b.privatizer.config.Epsilon
+	}
+
+	b.mu.Lock()
+	if exceeds(Budget{Epsilon: b.spent + cost}, Budget{Epsilon: b.allocation}) {
+		spent := b.spent
+		b.mu.Unlock()
+		return protocol.EvaluationReport{}, fmt.Errorf("%w: evaluation round %d would spend ε=%.4g of ε=%.4g", ErrBudgetExceeded, task.Round, spent+cost, b.allocation)
+	}
+	b.spent += cost
+	b.reports++
+	b.mu.Unlock()
+
+	out := report
+	out.Metrics = protocol.Metrics{Samples: b.privatizer.BucketCount(report.Metrics.Samples)}
+	if hasLoss {
+		out.Metrics.Loss = b.noise(report.Metrics.Loss, loss)
+	}
+	if hasAccuracy {
+		out.Metrics.Accuracy = b.noise(report.Metrics.Accuracy, accuracy)
+	}
+	out.NoiseScale = b.privatizer.config.Sensitivity / b.privatizer.config.Epsilon
+	return out, nil
+}
+
+// noise clamps value to metric's range and adds Laplace noise scaled to the
+// privatizer's sensitivity.
+func (b *EvaluationBudget) noise(value float64, metric protocol.MetricDefinition) float64 {
+	return b.privatizer.Noise(clamp(value, metric.Lower, metric.Upper), b.privatizer.config.Sensitivity)
+}
+
+// Spent returns the ε spent and the number of reports released.
+func (b *EvaluationBudget) Spent() (epsilon float64, reports int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent, b.reports
+}
+
+// Remaining returns the ε left of the allocation.
+func (b *EvaluationBudget) Remaining() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return max(b.allocation-b.spent, 0)
+}
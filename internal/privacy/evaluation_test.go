@@ -0,0 +1,45 @@
+package privacy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func TestEvaluationBudgetStopsAtMetricAllocation(t *testing.T) {
+	p, err := NewMetricsPrivatizer(MetricsDPConfig{Epsilon: 0.5, Sensitivity: 1, RoundBucket: 1, CountBucket: 10})
+	if err != nil {
+		t.Fatalf("new privatizer: %v", err)
+	}
+	// Loss and accuracy cost ε=0.5 each, so ε=2 pays for two reports.
+	budget, err := NewEvaluationBudget(p, 2)
+	if err != nil {
+		t.Fatalf("new budget: %v", err)
+	}
+	task := protocol.EvaluationTask{Round: 3, ModelDigest: "m", Metrics: protocol.DefaultEvaluationMetrics()}
+	exact := protocol.EvaluationReport{NodeID: "node-a", Round: 3, ModelDigest: "m", Metrics: protocol.Metrics{Loss: 4.2, Accuracy: 0.8, Samples: 137}}
+
+	for i := 0; i < 2; i++ {
+		out, err := budget.Privatize(task, exact)
+		if err != nil {
+			t.Fatalf("report %d: %v", i, err)
+		}
+		if out.Metrics.Samples != 130 || out.NoiseScale != 2 || out.NodeID != "node-a" {
+			t.Fatalf("report %d = %+v", i, out)
+		}
+	}
+	if _, err := budget.Privatize(task, exact); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("third report: expected ErrBudgetExceeded, got %v", err)
+	}
+	if spent, reports := budget.Spent(); spent != 2 || reports != 2 || budget.Remaining() != 0 {
+		t.Fatalf("spent ε=%g over %d reports, remaining %g", spent, reports, budget.Remaining())
+	}
+
+	wide := task
+	wide.Metrics = []protocol.MetricDefinition{{Name: protocol.MetricLoss, Lower: 0, Upper: 10}}
+	fresh, _ := NewEvaluationBudget(p, 2)
+	if _, err := fresh.Privatize(wide, exact); !errors.Is(err, ErrInvalidBudget) {
+		t.Fatalf("range wider than sensitivity: expected ErrInvalidBudget, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+package privacy
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// MetricsDPConfig configures local differential privacy for metric values
+// that leave the node. It is independent of the model privacy budget.
+type MetricsDPConfig struct {
+	// Epsilon is the per-value privacy loss of the Laplace mechanism.
+	Epsilon float64
+	// Sensitivity bounds how much one record can move a reported value.
+	// Loss and accuracy are clamped to [0, Sensitivity] before noising.
+	Sensitivity float64
+	// RoundBucket and CountBucket are the widths rounds and counts are
+	// floored to instead of being noised.
+	RoundBucket int
+	CountBucket int
+}
+
+// DefaultMetricsDPConfig returns ε = 1 with unit sensitivity and buckets of 10.
+func DefaultMetricsDPConfig() MetricsDPConfig {
+	return MetricsDPConfig{Epsilon: 1.0, Sensitivity: 1.0, RoundBucket: 10, CountBucket: 10}
+}
+
+// MetricsPrivatizer noises metric values for off-node reporting. Exact
+// values should be kept for local use and only privatized copies sent out.
+type MetricsPrivatizer struct {
+	config MetricsDPConfig
+	dp     *DifferentialPrivacy
+}
+
+// NewMetricsPrivatizer validates config and creates a privatizer.
+func NewMetricsPrivatizer(config MetricsDPConfig) (*MetricsPrivatizer, error) {
+	if config.Epsilon <= 0 {
+		return nil, fmt.Errorf("metrics dp epsilon must be positive, got %f", config.Epsilon)
+	}
+	if config.Sensitivity <= 0 {
+		return nil, fmt.Errorf("metrics dp sensitivity must be positive, got %f", config.Sensitivity)
+	}
+	if config.RoundBucket <= 0 || config.CountBucket <= 0 {
+		return nil, fmt.Errorf("metrics dp buckets must be positive, got round=%d count=%d", config.RoundBucket, config.CountBucket)
+	}
+	return &MetricsPrivatizer{
+		config: config,
+		dp:     NewDifferentialPrivacy(&SGP001Config{Epsilon: config.Epsilon, L2Sensitivity: config.Sensitivity}),
+	}, nil
+}
+
+// Config returns the privatizer's configuration.
+func (p *MetricsPrivatizer) Config() MetricsDPConfig {
+	return p.config
+}
+
+// SetNoiseSource draws the privatizer's noise from source; see
+// DifferentialPrivacy.SetNoiseSource. Production code must never call it.
+func (p *MetricsPrivatizer) SetNoiseSource(source io.Reader) {
+	p.dp.SetNoiseSource(source)
+}
+
+// Noise returns value plus Laplace noise scaled to sensitivity/ε. If the
+// randomness source fails the value is withheld and 0 is returned.
+func (p *MetricsPrivatizer) Noise(value, sensitivity float64) float64 {
+	noisy, err := p.dp.AddLaplaceNoise(value, sensitivity)
+	if err != nil {
+		return 0
+	}
+	return noisy
+}
+
+// BucketRound floors round to the configured round bucket.
+func (p *MetricsPrivatizer) BucketRound(round int) int {
+	return bucket(round, p.config.RoundBucket)
+}
+
+// BucketCount floors count to the configured count bucket.
+func (p *MetricsPrivatizer) BucketCount(count int) int {
+	return bucket(count, p.config.CountBucket)
+}
+
+// PrivatizeMetrics returns a copy of m with loss and accuracy clamped and
+// noised and the sample count bucketed.
+func (p *MetricsPrivatizer) PrivatizeMetrics(m protocol.Metrics) protocol.Metrics {
+	s := p.config.Sensitivity
+	return protocol.Metrics{
+		Loss:     p.Noise(clamp(m.Loss, 0, s), s),
+		Accuracy: p.Noise(clamp(m.Accuracy, 0, s), s),
+		Samples:  p.BucketCount(m.Samples),
+	}
+}
+
+// PrivatizeStatus returns a copy of update safe to send off-node: the round
+// is bucketed, progress and any attached metrics are noised.
+func (p *MetricsPrivatizer) PrivatizeStatus(update protocol.StatusUpdate) protocol.StatusUpdate {
+	out := update
+	out.Round = p.BucketRound(update.Round)
+	out.Progress = clamp(p.Noise(update.Progress, 1), 0, 1)
+	if update.Metrics != nil {
+		metrics := p.PrivatizeMetrics(*update.Metrics)
+		out.Metrics = &metrics
+	}
+	return out
+}
+
+func bucket(n, width int) int {
+	if n < 0 {
+		return -bucket(-n, width)
+	}
+	return n - n%width
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
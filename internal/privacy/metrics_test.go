@@ -0,0 +1,56 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func TestPrivatizeStatusNoisesMetricsAndBucketsCounts(t *testing.T) {
+	p, err := NewMetricsPrivatizer(DefaultMetricsDPConfig())
+	if err != nil {
+		t.Fatalf("new privatizer: %v", err)
+	}
+	exact := protocol.StatusUpdate{
+		NodeID:   "node-a",
+		Status:   "training",
+		Round:    47,
+		Progress: 0.5,
+		Metrics:  &protocol.Metrics{Loss: 0.42, Accuracy: 0.91, Samples: 137},
+	}
+
+	differs := 0
+	for i := 0; i < 20; i++ {
+		out := p.PrivatizeStatus(exact)
+		if out.Round != 40 || out.Metrics.Samples != 130 {
+			t.Fatalf("expected round and samples bucketed to 40/130, got %d/%d", out.Round, out.Metrics.Samples)
+		}
+		if out.NodeID != exact.NodeID || out.Status != exact.Status {
+			t.Fatal("expected identity fields to pass through")
+		}
+		if out.Progress < 0 || out.Progress > 1 {
+			t.Fatalf("progress %f escaped [0, 1]", out.Progress)
+		}
+		if out.Metrics.Loss != exact.Metrics.Loss && out.Metrics.Accuracy != exact.Metrics.Accuracy {
+			differs++
+		}
+	}
+	if differs == 0 {
+		t.Fatal("expected noised metrics to differ from the exact values")
+	}
+	if exact.Round != 47 || exact.Metrics.Loss != 0.42 || exact.Metrics.Samples != 137 {
+		t.Fatalf("privatizing modified the local copy: %+v", exact)
+	}
+}
+
+func TestNewMetricsPrivatizerRejectsInvalidConfig(t *testing.T) {
+	for _, config := range []MetricsDPConfig{
+		{Epsilon: 0, Sensitivity: 1, RoundBucket: 10, CountBucket: 10},
+		{Epsilon: 1, Sensitivity: 0, RoundBucket: 10, CountBucket: 10},
+		{Epsilon: 1, Sensitivity: 1, RoundBucket: 0, CountBucket: 10},
+	} {
+		if _, err := NewMetricsPrivatizer(config); err == nil {
+			t.Fatalf("expected config %+v to be rejected", config)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package profiling
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PhaseTiming summarises one phase's observations.
+type PhaseTiming struct {
+	Phase   string  `json:"phase"`
+	Count   int64   `json:"count"`
+	TotalMS float64 `json:"total_ms"`
+	MeanMS  float64 `json:"mean_ms"`
+	MaxMS   float64 `json:"max_ms"`
+}
+
+// RoundTiming is the phases observed in one round.
+type RoundTiming struct {
+	Round   int           `json:"round"`
+	TotalMS float64       `json:"total_ms"`
+	Phases  []PhaseTiming `json:"phases"`
+}
+
+// FlameNode is a node of a flame graph in the nested form d3-flame-graph
+// and speedscope import: a name, a value in nanoseconds that includes its
+// children's, and the children.
+type FlameNode struct {
+	Name     string      `json:"name"`
+	Value    int64       `json:"value"`
+	Children []FlameNode `json:"children,omitempty"`
+}
+
+// Breakdown is a snapshot of the timers: each phase in total, each
+// retained round's phases, and both as a flame graph rooted at "node",
+// with one child per retained round and one for time not attributed to
+// any, split by subsystem and phase.
+type Breakdown struct {
+	CurrentRound int           `json:"current_round"`
+	Phases       []PhaseTiming `json:"phases"`
+	Rounds       []RoundTiming `json:"rounds"`
+	Flame        FlameNode     `json:"flame"`
+}
+
+type phaseTotals struct {
+	count, total, max int64
+}
+
+func (s *phaseStat) load() phaseTotals {
+	return phaseTotals{count: s.count.Load(), total: s.total.Load(), max: s.max.Load()}
+}
+
+func (p phaseTotals) timing(phase Phase) PhaseTiming {
+	timing := PhaseTiming{Phase: phase.String(), Count: p.count, TotalMS: millis(p.total), MaxMS: millis(p.max)}
+	if p.count > 0 {
+		timing.MeanMS = millis(p.total / p.count)
+	}
+	return timing
+}
+
+func millis(nanos int64) float64 {
+	return float64(nanos) / float64(time.Millisecond)
+}
+
+// Snapshot summarises the timers. Observations racing it may be partly
+// counted.
+func (t *Timers) Snapshot() Breakdown {
+	breakdown := Breakdown{CurrentRound: int(t.current.Load())}
+	var totals [phaseCount]phaseTotals
+	for phase := Phase(0); phase < phaseCount; phase++ {
+		totals[phase] = t.totals[phase].load()
+		if totals[phase].count > 0 {
+			breakdown.Phases = append(breakdown.Phases, totals[phase].timing(phase))
+		}
+	}
+
+	unattributed := totals
+	var rounds []FlameNode
+	slots := make([]*roundSlot, 0, len(t.rounds))
+	for i := range t.rounds {
+		if t.rounds[i].round.Load() > 0 {
+			slots = append(slots, &t.rounds[i])
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].round.Load() < slots[j].round.Load() })
+	for _, slot := range slots {
+		round := slot.round.Load()
+		timing := RoundTiming{Round: int(round)}
+		var phases [phaseCount]int64
+		var total int64
+		for phase := Phase(0); phase < phaseCount; phase++ {
+			stat := slot.phases[phase].load()
+			if stat.count == 0 {
+				continue
+			}
+			timing.Phases = append(timing.Phases, stat.timing(phase))
+			phases[phase] = stat.total
+			total += stat.total
+			unattributed[phase].total -= stat.total
+		}
+		if len(timing.Phases) == 0 {
+			continue
+		}
+		timing.TotalMS = millis(total)
+		breakdown.Rounds = append(breakdown.Rounds, timing)
+		rounds = append(rounds, flameOf("round "+strconv.FormatInt(round, 10), phases))
+	}
+
+	var rest [phaseCount]int64
+	for phase := range unattributed {
+		rest[phase] = max(unattributed[phase].total, 0)
+	}
+	if other := flameOf("unattributed", rest); other.Value > 0 {
+		rounds = append(rounds, other)
+	}
+	breakdown.Flame = FlameNode{Name: "node", Children: rounds}
+	for _, child := range rounds {
+		breakdown.Flame.Value += child.Value
+	}
+	return breakdown
+}
+
+// flameOf nests phase totals under name by subsystem, the part of each
+// phase name before its dot.
+func flameOf(name string, phases [phaseCount]int64) FlameNode {
+	node := FlameNode{Name: name}
+	subsystems := make(map[string]int)
+	for phase := Phase(0); phase < phaseCount; phase++ {
+		nanos := phases[phase]
+		if nanos <= 0 {
+			continue
+		}
+		node.Value += nanos
+		subsystem, leaf, nested := strings.Cut(phase.String(), ".")
+		if !nested {
+			node.Children = append(node.Children, FlameNode{Name: subsystem, Value: nanos})
+			continue
+		}
+		i, ok := subsystems[subsystem]
+		if !ok {
+			i = len(node.Children)
+			subsystems[subsystem] = i
+			node.Children = append(node.Children, FlameNode{Name: subsystem})
+		}
+		node.Children[i].Value += nanos
+		node.Children[i].Children = append(node.Children[i].Children, FlameNode{Name: leaf, Value: nanos})
+	}
+	return node
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package profiling keeps always-on timers for the node's cold paths:
+// consensus phases, aggregation, proof and TPM verification, and island
+// sync. Each observation is a handful of atomic adds into fixed arrays, with
+// no locks and no allocation, so the timers stay on in the field and are
+// only summarised when an operator asks for a Breakdown. Observations are
+// attributed to a round, either the one the caller names or the round most
+// recently proposed on the node; see SetRound.
+package profiling
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Phase is an instrumented path.
+type Phase uint8
+
+const (
+	PhaseConsensusPropose Phase = iota
+	PhaseConsensusVote
+	PhaseConsensusCommit
+	PhaseAggregation
+	PhaseWasmVerify
+	PhaseAttestationVerify
+	PhaseTPMQuote
+	PhaseIslandSync
+	phaseCount
+)
+
+// phaseNames are dotted so a flame graph nests each phase under its
+// subsystem.
+var phaseNames = [phaseCount]string{
+	PhaseConsensusPropose:  "consensus.propose",
+	PhaseConsensusVote:     "consensus.vote",
+	PhaseConsensusCommit:   "consensus.commit",
+	PhaseAggregation:       "aggregation",
+	PhaseWasmVerify:        "verification.wasm",
+	PhaseAttestationVerify: "verification.attestation",
+	PhaseTPMQuote:          "verification.tpm_quote",
+	PhaseIslandSync:        "island.sync",
+}
+
+// String returns the phase's dotted name.
+func (p Phase) String() string {
+	if p >= phaseCount {
+		return "unknown"
+	}
+	return phaseNames[p]
+}
+
+// DefaultRoundHistory is how many recent rounds the default timers
+// attribute observations to.
+const DefaultRoundHistory = 64
+
+type phaseStat struct {
+	count atomic.Int64
+	total atomic.Int64
+	max   atomic.Int64
+}
+
+func (s *phaseStat) add(nanos int64) {
+	s.count.Add(1)
+	s.total.Add(nanos)
+	for {
+		current := s.max.Load()
+		if nanos <= current || s.max.CompareAndSwap(current, nanos) {
+			return
+		}
+	}
+}
+
+func (s *phaseStat) reset() {
+	s.count.Store(0)
+	s.total.Store(0)
+	s.max.Store(0)
+}
+
+// roundSlot holds the phases of one round in the ring of recent rounds.
+type roundSlot struct {
+	round  atomic.Int64
+	phases [phaseCount]phaseStat
+}
+
+// Timers accumulates phase timings in total and per recent round.
+type Timers struct {
+	totals  [phaseCount]phaseStat
+	rounds  []roundSlot
+	current atomic.Int64
+}
+
+// NewTimers creates timers attributing observations to the last history
+// rounds; older rounds count only toward the totals.
+func NewTimers(history int) *Timers {
+	if history <= 0 {
+		history = DefaultRoundHistory
+	}
+	return &Timers{rounds: make([]roundSlot, history)}
+}
+
+var defaultTimers = NewTimers(DefaultRoundHistory)
+
+// Default returns the process-wide timers the instrumented packages record
+// into.
+func Default() *Timers {
+	return defaultTimers
+}
+
+// SetRound makes round the one observations without a round of their own
+// are attributed to.
+func (t *Timers) SetRound(round int) {
+	t.current.Store(int64(round))
+}
+
+// Observe records d in phase, attributed to the current round.
+func (t *Timers) Observe(phase Phase, d time.Duration) {
+	t.observe(phase, t.current.Load(), int64(d))
+}
+
+// ObserveRound records d in phase, attributed to round. Rounds below one
+// count only toward the totals.
+func (t *Timers) ObserveRound(phase Phase, round int, d time.Duration) {
+	t.observe(phase, int64(round), int64(d))
+}
+
+func (t *Timers) observe(phase Phase, round, nanos int64) {
+	if phase >= phaseCount {
+		return
+	}
+	t.totals[phase].add(nanos)
+	if round <= 0 {
+		return
+	}
+	slot := &t.rounds[round%int64(len(t.rounds))]
+	for {
+		held := slot.round.Load()
+		if held == round {
+			break
+		}
+		if held > round {
+			// The round has left the history; a late observation of it
+			// counts only toward the totals.
+			return
+		}
+		if slot.round.CompareAndSwap(held, round) {
+			for i := range slot.phases {
+				slot.phases[i].reset()
+			}
+			break
+		}
+	}
+	slot.phases[phase].add(nanos)
+}
+
+// Observe records d in phase on the default timers, attributed to the
+// current round.
+func Observe(phase Phase, d time.Duration) {
+	defaultTimers.Observe(phase, d)
+}
+
+// ObserveRound records d in phase on the default timers, attributed to
+// round.
+func ObserveRound(phase Phase, round int, d time.Duration) {
+	defaultTimers.ObserveRound(phase, round, d)
+}
+
+// ObserveSince records the time since started in phase on the default
+// timers, attributed to round. It suits a deferred call:
+//
+//	defer profiling.ObserveSince(profiling.PhaseAggregation, round, time.Now())
+func ObserveSince(phase Phase, round int, started time.Time) {
+	defaultTimers.ObserveRound(phase, round, time.Since(started))
+}
+
+// SetRound sets the current round of the default timers.
+func SetRound(round int) {
+	defaultTimers.SetRound(round)
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package profiling
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func timingOf(t *testing.T, phases []PhaseTiming, name string) PhaseTiming {
+	t.Helper()
+	for _, phase := range phases {
+		if phase.Phase == name {
+			return phase
+		}
+	}
+	t.Fatalf("phase %s missing from %+v", name, phases)
+	return PhaseTiming{}
+}
+
+func TestSimulatedRoundIsAttributedByPhase(t *testing.T) {
+	timers := NewTimers(4)
+
+	// Round 7: propose, four votes, aggregation, and a commit. Proof
+	// verification names no round and lands in the current one.
+	timers.SetRound(7)
+	timers.ObserveRound(PhaseConsensusPropose, 7, 2*time.Millisecond)
+	for i := 0; i < 4; i++ {
+		timers.ObserveRound(PhaseConsensusVote, 7, time.Millisecond)
+	}
+	timers.Observe(PhaseWasmVerify, 3*time.Millisecond)
+	timers.ObserveRound(PhaseAggregation, 7, 10*time.Millisecond)
+	timers.ObserveRound(PhaseConsensusCommit, 7, 5*time.Millisecond)
+	// An island sync before any round counts only toward the totals.
+	timers.ObserveRound(PhaseIslandSync, 0, 20*time.Millisecond)
+
+	breakdown := timers.Snapshot()
+	if breakdown.CurrentRound != 7 || len(breakdown.Rounds) != 1 || breakdown.Rounds[0].Round != 7 {
+		t.Fatalf("rounds = %+v", breakdown.Rounds)
+	}
+	round := breakdown.Rounds[0]
+	if round.TotalMS != 24 {
+		t.Fatalf("round 7 total = %vms, want 24", round.TotalMS)
+	}
+	if vote := timingOf(t, round.Phases, "consensus.vote"); vote.Count != 4 || vote.TotalMS != 4 || vote.MeanMS != 1 || vote.MaxMS != 1 {
+		t.Fatalf("votes = %+v", vote)
+	}
+	if wasm := timingOf(t, round.Phases, "verification.wasm"); wasm.Count != 1 || wasm.TotalMS != 3 {
+		t.Fatalf("wasm verification = %+v", wasm)
+	}
+	if sync := timingOf(t, breakdown.Phases, "island.sync"); sync.Count != 1 || sync.TotalMS != 20 {
+		t.Fatalf("island sync total = %+v", sync)
+	}
+
+	// The flame graph nests each round's phases by subsystem, with time
+	// outside any round under its own root.
+	flame := breakdown.Flame
+	if flame.Value != int64(44*time.Millisecond) || len(flame.Children) != 2 {
+		t.Fatalf("flame root = %+v", flame)
+	}
+	round7, unattributed := flame.Children[0], flame.Children[1]
+	if round7.Name != "round 7" || round7.Value != int64(24*time.Millisecond) {
+		t.Fatalf("round node = %+v", round7)
+	}
+	consensus := round7.Children[0]
+	if consensus.Name != "consensus" || consensus.Value != int64(11*time.Millisecond) || len(consensus.Children) != 3 {
+		t.Fatalf("consensus node = %+v", consensus)
+	}
+	if unattributed.Name != "unattributed" || unattributed.Value != int64(20*time.Millisecond) || unattributed.Children[0].Name != "island" {
+		t.Fatalf("unattributed node = %+v", unattributed)
+	}
+}
+
+func TestRoundsOutsideTheHistoryCountOnlyInTotals(t *testing.T) {
+	timers := NewTimers(2)
+	for round := 1; round <= 3; round++ {
+		timers.ObserveRound(PhaseAggregation, round, time.Millisecond)
+	}
+	// Round 1 shared its slot with round 3, so its late commit is no
+	// longer attributable.
+	timers.ObserveRound(PhaseConsensusCommit, 1, time.Millisecond)
+
+	breakdown := timers.Snapshot()
+	if len(breakdown.Rounds) != 2 || breakdown.Rounds[0].Round != 2 || breakdown.Rounds[1].Round != 3 {
+		t.Fatalf("rounds = %+v", breakdown.Rounds)
+	}
+	for _, round := range breakdown.Rounds {
+		if len(round.Phases) != 1 || round.Phases[0].Phase != "aggregation" {
+			t.Fatalf("round %d phases = %+v", round.Round, round.Phases)
+		}
+	}
+	if commit := timingOf(t, breakdown.Phases, "consensus.commit"); commit.Count != 1 {
+		t.Fatalf("commit total = %+v", commit)
+	}
+	if aggregation := timingOf(t, breakdown.Phases, "aggregation"); aggregation.Count != 3 {
+		t.Fatalf("aggregation total = %+v", aggregation)
+	}
+}
+
+func TestConcurrentObservationsAreAllCounted(t *testing.T) {
+	timers := NewTimers(8)
+	const workers, each = 8, 1000
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				timers.ObserveRound(PhaseConsensusVote, 5, time.Microsecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	breakdown := timers.Snapshot()
+	if vote := timingOf(t, breakdown.Rounds[0].Phases, "consensus.vote"); vote.Count != workers*each {
+		t.Fatalf("round 5 votes = %d, want %d", vote.Count, workers*each)
+	}
+}
+
+func TestObservationsDoNotAllocate(t *testing.T) {
+	timers := NewTimers(DefaultRoundHistory)
+	round := 0
+	if allocs := testing.AllocsPerRun(1000, func() {
+		round++
+		timers.ObserveRound(PhaseAggregation, round, time.Millisecond)
+		timers.Observe(PhaseWasmVerify, time.Millisecond)
+	}); allocs != 0 {
+		t.Fatalf("observation allocates %v times", allocs)
+	}
+	started := time.Now()
+	if allocs := testing.AllocsPerRun(1000, func() {
+		ObserveSince(PhaseConsensusVote, 1, started)
+	}); allocs != 0 {
+		t.Fatalf("ObserveSince allocates %v times", allocs)
+	}
+}
+
+func TestObservationOverheadIsNegligible(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping overhead benchmark in short mode")
+	}
+	observe := testing.Benchmark(BenchmarkObserve)
+	baseline := testing.Benchmark(BenchmarkBaselineAtomicAdd)
+	// An observation is a few atomic operations; a microsecond is orders
+	// of magnitude above that even under the race detector, and far below
+	// any phase the timers measure.
+	if observe.NsPerOp() > 1000 {
+		t.Fatalf("observation costs %dns, baseline atomic add %dns", observe.NsPerOp(), baseline.NsPerOp())
+	}
+	if observe.AllocsPerOp() != 0 {
+		t.Fatalf("observation allocates %d times", observe.AllocsPerOp())
+	}
+}
+
+// The benchmarks compare an observation with a bare atomic add and with
+// reading the clock twice, which any timing of a phase costs; the
+// difference is what the timers add to an instrumented path.
+
+func BenchmarkBaselineAtomicAdd(b *testing.B) {
+	var counter atomic.Int64
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		counter.Add(1)
+	}
+}
+
+func BenchmarkBaselineTimeNow(b *testing.B) {
+	var total time.Duration
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		total += time.Since(time.Now())
+	}
+	_ = total
+}
+
+func BenchmarkObserve(b *testing.B) {
+	timers := NewTimers(DefaultRoundHistory)
+	timers.SetRound(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		timers.Observe(PhaseConsensusVote, time.Microsecond)
+	}
+}
+
+func BenchmarkObserveSince(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ObserveSince(PhaseConsensusVote, 1, time.Now())
+	}
+}
+
+func BenchmarkObserveParallel(b *testing.B) {
+	timers := NewTimers(DefaultRoundHistory)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			timers.ObserveRound(PhaseConsensusVote, 1, time.Microsecond)
+		}
+	})
+}
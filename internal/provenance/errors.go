@@ -0,0 +1,20 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package provenance
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the provenance tracker. Match them
+// with errors.Is; never compare error strings.
+var (
+	// ErrUnknownUpdate means no retained round holds events for the update
+	// ID. Not retryable unless the update is still being processed.
+	ErrUnknownUpdate = errors.New("unknown update provenance")
+	// ErrUnknownRound means the round has no retained events, because none
+	// were recorded or retention dropped them. Not retryable.
+	ErrUnknownRound = errors.New("unknown provenance round")
+	// ErrInvalidEvent means an event lacks its update ID, node, round, or
+	// kind. Not retryable.
+	ErrInvalidEvent = errors.New("invalid provenance event")
+)
@@ -0,0 +1,74 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package provenance records what happened to each model update, from
+// ingest through verification and aggregation to commit. Every package
+// that handles updates emits lifecycle events to a Sink; the events of one
+// update share an ID every package can compute from the update alone.
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// Kind is an update lifecycle stage.
+type Kind string
+
+const (
+	// KindReceived: the update was ingested.
+	KindReceived Kind = "received"
+	// KindSignatureVerified: the update's training statement commitment
+	// matched the node, round, and update.
+	KindSignatureVerified Kind = "signature_verified"
+	// KindProofVerified: a verification committee accepted the update;
+	// Committee lists its members.
+	KindProofVerified Kind = "proof_verified"
+	// KindIncluded and KindExcluded: the aggregator applied or excluded
+	// the update; Reason is the manifest reason.
+	KindIncluded Kind = "included"
+	KindExcluded Kind = "excluded"
+	// KindSuperseded: another copy of the node's round update, one synced
+	// from its island cache or submitted live, took the update's place
+	// before aggregation; Reason is reasons.Superseded. The update was
+	// acknowledged, not rejected.
+	KindSuperseded Kind = "superseded"
+	// KindAggregated: the update fed proposal ProposalID.
+	KindAggregated Kind = "aggregated"
+	// KindCommitted: the proposal holding the update committed in
+	// CommitRound.
+	KindCommitted Kind = "committed"
+)
+
+// Event is one lifecycle event of one update.
+type Event struct {
+	UpdateID    string       `json:"update_id"`
+	NodeID      string       `json:"node_id"`
+	Round       int          `json:"round"`
+	Kind        Kind         `json:"kind"`
+	Source      string       `json:"source"`
+	At          time.Time    `json:"at"`
+	Reason      reasons.Code `json:"reason,omitempty"`
+	Committee   []string     `json:"committee,omitempty"`
+	ProposalID  string       `json:"proposal_id,omitempty"`
+	CommitRound int          `json:"commit_round,omitempty"`
+}
+
+// Sink receives lifecycle events. Emitters call it synchronously, after
+// releasing their own locks, and ignore failures: provenance never blocks
+// an update's progress.
+type Sink interface {
+	Emit(event Event)
+}
+
+// UpdateID is the provenance ID of nodeID's round update whose canonical
+// encoding digests to updateDigest (see protocol.UpdateDigest). Ingest,
+// verification, aggregation, and consensus all derive the same ID.
+func UpdateID(nodeID string, round int, updateDigest string) string {
+	sum := sha256.Sum256([]byte(nodeID + "\x00" + strconv.Itoa(round) + "\x00" + updateDigest))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package provenance
+
+import (
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+)
+
+// Subscribe follows bus's round lifecycle for sink: an aggregated event for
+// every included update of a proposal's manifest when the proposal is
+// created, and a committed event for each when it commits. Proposals
+// without a manifest emit nothing. Closing the subscription returns once
+// the events already delivered have reached sink.
+func Subscribe(bus *events.Bus, sink Sink) *events.Subscription {
+	return bus.Handle("provenance", events.DefaultBuffer, func(event events.Event) {
+		for _, e := range manifestEvents(event) {
+			sink.Emit(e)
+		}
+	}, events.KindProposalCreated, events.KindCommitted)
+}
+
+// manifestEvents returns the update events a proposal lifecycle event
+// implies.
+func manifestEvents(event events.Event) []Event {
+	manifest := event.Manifest
+	if manifest == nil {
+		return nil
+	}
+	kind := KindAggregated
+	if event.Kind == events.KindCommitted {
+		kind = KindCommitted
+	}
+	var updates []Event
+	for _, entry := range manifest.Entries {
+		if !entry.Included {
+			continue
+		}
+		update := Event{
+			UpdateID:   UpdateID(entry.NodeID, manifest.Round, entry.UpdateDigest),
+			NodeID:     entry.NodeID,
+			Round:      manifest.Round,
+			Kind:       kind,
+			Source:     event.Source,
+			At:         event.At,
+			ProposalID: event.ProposalID,
+		}
+		if kind == KindCommitted {
+			update.CommitRound = event.Round
+		}
+		updates = append(updates, update)
+	}
+	return updates
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package provenance
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultRetainRounds is how many update rounds a tracker keeps when
+// NewTracker is given zero.
+const DefaultRetainRounds = 1024
+
+// roundFilePattern names each round's event log under the tracker's
+// directory.
+const roundFilePattern = "round-%09d.jsonl"
+
+// Tracker is the Sink that stores events on disk, one append-only JSON
+// Lines file per update round, and indexes update IDs to rounds in memory.
+// Only the newest retained rounds are kept; older files are deleted as
+// new rounds arrive, which bounds both the disk and the index.
+type Tracker struct {
+	dir    string
+	retain int
+
+	mu      sync.RWMutex
+	index   map[string]int
+	rounds  map[int][]string
+	dropped uint64
+	lastErr error
+}
+
+// NewTracker opens the event logs under dir, creating it if needed, and
+// rebuilds the index from them. retainRounds of zero or less keeps
+// DefaultRetainRounds.
+func NewTracker(dir string, retainRounds int) (*Tracker, error) {
+	if retainRounds <= 0 {
+		retainRounds = DefaultRetainRounds
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create provenance directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "round-*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("list provenance rounds: %w", err)
+	}
+	t := &Tracker{dir: dir, retain: retainRounds, index: make(map[string]int), rounds: make(map[int][]string)}
+	for _, path := range paths {
+		var round int
+		if _, err := fmt.Sscanf(filepath.Base(path), roundFilePattern, &round); err != nil {
+			continue
+		}
+		events, err := t.readRound(round)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			t.indexLocked(event)
+		}
+		if _, ok := t.rounds[round]; !ok {
+			t.rounds[round] = nil
+		}
+	}
+	if err := t.pruneLocked(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Emit records event, dropping it if it is invalid or cannot be written.
+// Dropped reports how many were; Record returns the error instead.
+func (t *Tracker) Emit(event Event) {
+	_ = t.Record(event)
+}
+
+// Record appends event to its round's log. At defaults to now.
+func (t *Tracker) Record(event Event) error {
+	if event.UpdateID == "" || event.NodeID == "" || event.Round < 0 || event.Kind == "" {
+		err := fmt.Errorf("%w: update %q node %q round %d kind %q", ErrInvalidEvent, event.UpdateID, event.NodeID, event.Round, event.Kind)
+		t.drop(err)
+		return err
+	}
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		err = fmt.Errorf("encode provenance event: %w", err)
+		t.drop(err)
+		return err
+	}
+	line = append(line, '\n')
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.appendLocked(event.Round, line); err != nil {
+		t.dropped++
+		t.lastErr = err
+		return err
+	}
+	_, known := t.rounds[event.Round]
+	t.indexLocked(event)
+	if !known {
+		return t.pruneLocked()
+	}
+	return nil
+}
+
+func (t *Tracker) drop(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dropped++
+	t.lastErr = err
+}
+
+// Dropped returns how many events Emit dropped and the last reason.
+func (t *Tracker) Dropped() (uint64, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.dropped, t.lastErr
+}
+
+func (t *Tracker) appendLocked(round int, line []byte) error {
+	path := filepath.Join(t.dir, fmt.Sprintf(roundFilePattern, round))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) // #nosec G304 -- files under the tracker's own directory
+	if err != nil {
+		return fmt.Errorf("open provenance round %d: %w", round, err)
+	}
+	if _, err := file.Write(line); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("write provenance round %d: %w", round, err)
+	}
+	return file.Close()
+}
+
+func (t *Tracker) indexLocked(event Event) {
+	if _, seen := t.index[event.UpdateID]; !seen {
+		t.rounds[event.Round] = append(t.rounds[event.Round], event.UpdateID)
+	}
+	t.index[event.UpdateID] = event.Round
+}
+
+// pruneLocked deletes the oldest rounds beyond the retention bound.
+func (t *Tracker) pruneLocked() error {
+	if len(t.rounds) <= t.retain {
+		return nil
+	}
+	rounds := make([]int, 0, len(t.rounds))
+	for round := range t.rounds {
+		rounds = append(rounds, round)
+	}
+	sort.Ints(rounds)
+	for _, round := range rounds[:len(rounds)-t.retain] {
+		path := filepath.Join(t.dir, fmt.Sprintf(roundFilePattern, round))
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("expire provenance round %d: %w", round, err)
+		}
+		for _, id := range t.rounds[round] {
+			delete(t.index, id)
+		}
+		delete(t.rounds, round)
+	}
+	return nil
+}
+
+// Chain returns every event recorded for updateID, in the order recorded.
+func (t *Tracker) Chain(updateID string) ([]Event, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	round, ok := t.index[updateID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownUpdate, updateID)
+	}
+	events, err := t.readRound(round)
+	if err != nil {
+		return nil, err
+	}
+	chain := make([]Event, 0, 8)
+	for _, event := range events {
+		if event.UpdateID == updateID {
+			chain = append(chain, event)
+		}
+	}
+	return chain, nil
+}
+
+// RoundEvents returns every event recorded for updates of round, in the
+// order recorded.
+func (t *Tracker) RoundEvents(round int) ([]Event, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if _, ok := t.rounds[round]; !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownRound, round)
+	}
+	return t.readRound(round)
+}
+
+// ExportRound writes round's events to w as JSON Lines.
+func (t *Tracker) ExportRound(round int, w io.Writer) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if _, ok := t.rounds[round]; !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownRound, round)
+	}
+	file, err := os.Open(filepath.Join(t.dir, fmt.Sprintf(roundFilePattern, round))) // #nosec G304 -- files under the tracker's own directory
+	if err != nil {
+		return fmt.Errorf("open provenance round %d: %w", round, err)
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// Rounds returns the retained rounds, oldest first.
+func (t *Tracker) Rounds() []int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	rounds := make([]int, 0, len(t.rounds))
+	for round := range t.rounds {
+		rounds = append(rounds, round)
+	}
+	sort.Ints(rounds)
+	return rounds
+}
+
+// readRound decodes a round's log. A torn final line, left by a crash
+// mid-append, is skipped.
+func (t *Tracker) readRound(round int) ([]Event, error) {
+	raw, err := os.ReadFile(filepath.Join(t.dir, fmt.Sprintf(roundFilePattern, round))) // #nosec G304 -- files under the tracker's own directory
+	if err != nil {
+		return nil, fmt.Errorf("read provenance round %d: %w", round, err)
+	}
+	var events []Event
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package provenance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func recordChain(t *testing.T, tracker *Tracker, nodeID string, round int, kinds ...Kind) string {
+	t.Helper()
+	id := UpdateID(nodeID, round, "digest-"+nodeID)
+	for _, kind := range kinds {
+		if err := tracker.Record(Event{UpdateID: id, NodeID: nodeID, Round: round, Kind: kind, Source: "test"}); err != nil {
+			t.Fatalf("record %s %s: %v", nodeID, kind, err)
+		}
+	}
+	return id
+}
+
+func kindsOf(events []Event) string {
+	kinds := make([]string, len(events))
+	for i, event := range events {
+		kinds[i] = string(event.Kind)
+	}
+	return strings.Join(kinds, ",")
+}
+
+func TestTrackerChainsSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+	tracker, err := NewTracker(dir, 0)
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	honest := recordChain(t, tracker, "node-1", 3, KindReceived, KindSignatureVerified, KindIncluded, KindAggregated, KindCommitted)
+	rejected := recordChain(t, tracker, "node-2", 3, KindReceived, KindExcluded)
+
+	// A crash mid-append leaves a torn line the reload skips.
+	file, err := os.OpenFile(filepath.Join(dir, fmt.Sprintf(roundFilePattern, 3)), os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("open round: %v", err)
+	}
+	_, _ = file.WriteString(`{"update_id":"`)
+	_ = file.Close()
+
+	reloaded, err := NewTracker(dir, 0)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	chain, err := reloaded.Chain(honest)
+	if err != nil {
+		t.Fatalf("chain: %v", err)
+	}
+	if got := kindsOf(chain); got != "received,signature_verified,included,aggregated,committed" {
+		t.Fatalf("honest chain = %s", got)
+	}
+	if chain, _ := reloaded.Chain(rejected); kindsOf(chain) != "received,excluded" {
+		t.Fatalf("rejected chain = %s", kindsOf(chain))
+	}
+	if events, err := reloaded.RoundEvents(3); err != nil || len(events) != 7 {
+		t.Fatalf("round events = %d, err %v", len(events), err)
+	}
+
+	var buf bytes.Buffer
+	if err := reloaded.ExportRound(3, &buf); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 7 {
+		t.Fatalf("export has %d complete lines, want 7", lines)
+	}
+}
+
+func TestTrackerExpiresOldestRounds(t *testing.T) {
+	dir := t.TempDir()
+	tracker, err := NewTracker(dir, 2)
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	ids := make([]string, 4)
+	for round := range ids {
+		ids[round] = recordChain(t, tracker, "node-1", round, KindReceived)
+	}
+	if got := fmt.Sprint(tracker.Rounds()); got != "[2 3]" {
+		t.Fatalf("retained rounds = %s", got)
+	}
+	if _, err := tracker.Chain(ids[0]); !errors.Is(err, ErrUnknownUpdate) {
+		t.Fatalf("expired update: expected ErrUnknownUpdate, got %v", err)
+	}
+	if _, err := tracker.RoundEvents(1); !errors.Is(err, ErrUnknownRound) {
+		t.Fatalf("expired round: expected ErrUnknownRound, got %v", err)
+	}
+	if paths, _ := filepath.Glob(filepath.Join(dir, "round-*.jsonl")); len(paths) != 2 {
+		t.Fatalf("%d round files on disk, want 2", len(paths))
+	}
+
+	// Reopening with a tighter bound prunes on load.
+	reopened, err := NewTracker(dir, 1)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := fmt.Sprint(reopened.Rounds()); got != "[3]" {
+		t.Fatalf("retained rounds after reopen = %s", got)
+	}
+}
+
+func TestTrackerDropsInvalidEvents(t *testing.T) {
+	tracker, err := NewTracker(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	if err := tracker.Record(Event{NodeID: "node-1", Kind: KindReceived}); !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("expected ErrInvalidEvent, got %v", err)
+	}
+	tracker.Emit(Event{UpdateID: "id", NodeID: "node-1", Round: -1, Kind: KindReceived})
+	if dropped, last := tracker.Dropped(); dropped != 2 || !errors.Is(last, ErrInvalidEvent) {
+		t.Fatalf("dropped = %d (%v), want 2", dropped, last)
+	}
+}
+
+func TestSubscribeTracesProposalsFromLifecycle(t *testing.T) {
+	tracker, err := NewTracker(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	bus := events.NewBus()
+	sub := Subscribe(bus, tracker)
+
+	manifest := &protocol.ContributionManifest{Round: 4, Entries: []protocol.ContributionEntry{
+		{NodeID: "node-1", UpdateDigest: "digest-node-1", Included: true},
+		{NodeID: "node-2", UpdateDigest: "digest-node-2", Reason: protocol.ReasonNormOutlier},
+	}}
+	id := recordChain(t, tracker, "node-1", 4, KindReceived, KindIncluded)
+	bus.Publish(events.Event{Kind: events.KindProposalCreated, Round: 4, Source: "consensus", ProposalID: "p-4", Manifest: manifest})
+	bus.Publish(events.Event{Kind: events.KindQuorumReached, Round: 4, ProposalID: "p-4", Manifest: manifest})
+	bus.Publish(events.Event{Kind: events.KindCommitted, Round: 4, Source: "consensus", ProposalID: "p-4", Manifest: manifest})
+	bus.Publish(events.Event{Kind: events.KindCommitted, Round: 5, ProposalID: "p-5"})
+	sub.Close()
+
+	chain, err := tracker.Chain(id)
+	if err != nil {
+		t.Fatalf("chain: %v", err)
+	}
+	if got := kindsOf(chain); got != "received,included,aggregated,committed" {
+		t.Fatalf("chain = %s", got)
+	}
+	if chain[3].ProposalID != "p-4" || chain[3].CommitRound != 4 || chain[3].Source != "consensus" {
+		t.Fatalf("committed event = %+v", chain[3])
+	}
+	// Excluded updates and proposals without a manifest emit nothing.
+	if all, err := tracker.RoundEvents(4); err != nil || len(all) != 4 {
+		t.Fatalf("round 4 events = %+v, %v", all, err)
+	}
+	if rounds := tracker.Rounds(); len(rounds) != 1 {
+		t.Fatalf("rounds = %v", rounds)
+	}
+}
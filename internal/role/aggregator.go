@@ -0,0 +1,311 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Default round windows for aggregator tiers.
+const (
+	DefaultCollectWindow = 30 * time.Second
+	DefaultVoteWindow    = 10 * time.Second
+)
+
+// TierConfig configures the consensus an aggregator tier runs over its own
+// federation.
+type TierConfig struct {
+	NodeID     string
+	Federation *federation.Federation
+	// Expected is how many updates a round waits for before proposing.
+	// Zero waits for every participant the federation selects.
+	Expected int
+	// CollectWindow bounds the wait for updates; a round proposes what
+	// arrived once it passes. VoteWindow bounds the wait for every member
+	// to vote; the round commits if a quorum approved by then.
+	CollectWindow time.Duration
+	VoteWindow    time.Duration
+	PollInterval  time.Duration
+	// Tuner, when set and enabled, replaces Expected with a trigger tuned
+	// to the federation's recent participation. Each round's on-time
+	// submissions are reported to it whether it is enabled or not.
+	Tuner *batch.AutoTuner
+}
+
+// tierRound collects round's updates in cfg's federation, proposes their
+// aggregate, and commits it once every member voted or the vote window
+// closes. It announces the round on the federation's events as it opens.
+func tierRound(ctx context.Context, cfg TierConfig, round int) (*federation.Proposal, modeldist.RoundSummary, error) {
+	f := cfg.Federation
+	collectWindow := cfg.CollectWindow
+	if collectWindow <= 0 {
+		collectWindow = DefaultCollectWindow
+	}
+	voteWindow := cfg.VoteWindow
+	if voteWindow <= 0 {
+		voteWindow = DefaultVoteWindow
+	}
+
+	target := cfg.Expected
+	if target <= 0 {
+		target = len(f.Participants())
+	}
+	f.Publish(scheduler.NewRoundState(round, target, collectWindow).StartedEvent())
+
+	collect, cancel := context.WithTimeout(ctx, collectWindow)
+	err := poll(collect, cfg.PollInterval, func() error {
+		expected := cfg.Expected
+		if expected <= 0 {
+			expected = len(f.Participants())
+		}
+		if cfg.Tuner != nil {
+			expected = cfg.Tuner.Trigger(expected)
+		}
+		if expected > 0 && f.Pending(round) >= expected {
+			return nil
+		}
+		return ErrNotReady
+	})
+	cancel()
+	if err != nil && ctx.Err() != nil {
+		return nil, modeldist.RoundSummary{}, ctx.Err()
+	}
+	if f.Pending(round) == 0 {
+		if cfg.Tuner != nil {
+			cfg.Tuner.Observe(round, 0)
+		}
+		settle(f, round, nil)
+		return nil, modeldist.RoundSummary{}, fmt.Errorf("%w: federation %s round %d", ErrNoUpdates, f.ID, round)
+	}
+
+	proposal, err := f.Propose(ctx, round, cfg.NodeID)
+	if err != nil {
+		return nil, modeldist.RoundSummary{}, fmt.Errorf("propose round %d: %w", round, err)
+	}
+	membership, err := f.Coordinator.GetRoundMembership(proposal.ID)
+	if err != nil {
+		return nil, modeldist.RoundSummary{}, err
+	}
+	voting, cancel := context.WithTimeout(ctx, voteWindow)
+	err = poll(voting, cfg.PollInterval, func() error {
+		for _, state := range f.Coordinator.ProposalStates() {
+			if state.ProposalID == proposal.ID && state.Votes >= membership.ActiveCount {
+				return nil
+			}
+		}
+		return ErrNotReady
+	})
+	cancel()
+	if err != nil && ctx.Err() != nil {
+		return nil, modeldist.RoundSummary{}, ctx.Err()
+	}
+
+	// Updates that arrived after an early flush, while the round voted,
+	// count as on time too, so a low trigger does not censor the
+	// participation it is tuned from.
+	if cfg.Tuner != nil {
+		cfg.Tuner.Observe(round, len(proposal.Manifest.Entries)+f.Pending(round))
+	}
+	settle(f, round, proposal.Manifest)
+	summary, err := f.Commit(ctx, round)
+	if err != nil {
+		return nil, modeldist.RoundSummary{}, fmt.Errorf("commit round %d: %w", round, err)
+	}
+	return proposal, summary, nil
+}
+
+// settle closes round in f's participation ledger: participants manifest
+// lists submitted, and the rest either skipped as they declared or
+// faulted.
+func settle(f *federation.Federation, round int, manifest *protocol.ContributionManifest) {
+	if f.Participation == nil {
+		return
+	}
+	var submitted []string
+	if manifest != nil {
+		for _, entry := range manifest.Entries {
+			submitted = append(submitted, entry.NodeID)
+		}
+	}
+	f.Participation.CloseRound(round, f.Participants(), submitted)
+}
+
+// RegionalConfig configures a regional aggregator.
+type RegionalConfig struct {
+	TierConfig
+	// Upstream is the global aggregator; UpstreamFederation the federation
+	// the regional joins there. Empty joins protocol.DefaultFederation.
+	Upstream           *Client
+	UpstreamFederation string
+	// Store is the model store the regional serves to its edges. Each
+	// committed global model is imported into it.
+	Store *modeldist.ModelStore
+	// Capabilities is sent with the upstream registration.
+	Capabilities *protocol.CapabilityManifest
+	// Certify, when set, returns the certificate chain proving the shard
+	// committed a round, which the regional summary carries upstream for
+	// a global tier that requires one; see package certchain and
+	// CertifyShard.
+	Certify func(ctx context.Context, committed modeldist.RoundSummary) (*protocol.CertificateChain, error)
+	// ShardID, when set, is the shard the regional aggregates for the
+	// global tier. Each round is proposed under the shard's current
+	// membership epoch, fetched from Upstream, for its members to sign.
+	ShardID string
+}
+
+// CertifyShard is a RegionalConfig.Certify returning the certificate chain
+// f's shard members signed for a round; f should be the regional's
+// federation, certifying under RegionalConfig.ShardID.
+func CertifyShard(f *federation.Federation) func(ctx context.Context, committed modeldist.RoundSummary) (*protocol.CertificateChain, error) {
+	return func(_ context.Context, committed modeldist.RoundSummary) (*protocol.CertificateChain, error) {
+		chain, ok := f.CertificateChain(committed.Round)
+		if !ok {
+			return nil, fmt.Errorf("round %d was not committed under a shard epoch", committed.Round)
+		}
+		return chain, nil
+	}
+}
+
+// RegionalAggregator commits its shard's round by consensus among its
+// edges, submits the shard model upstream as its regional summary, votes on
+// the global proposal, and relays the committed global model to its edges.
+type RegionalAggregator struct {
+	cfg RegionalConfig
+}
+
+// NewRegional creates a regional aggregator.
+func NewRegional(cfg RegionalConfig) *RegionalAggregator {
+	return &RegionalAggregator{cfg: cfg}
+}
+
+// Join registers the regional with the global aggregator and returns the
+// first round it should aggregate.
+func (r *RegionalAggregator) Join(ctx context.Context) (int, error) {
+	resp, err := r.cfg.Upstream.Register(ctx, protocol.RegistrationRequest{
+		NodeID:       r.cfg.NodeID,
+		Capabilities: r.cfg.Capabilities,
+		Federations:  []string{protocol.FederationOf(r.cfg.UpstreamFederation)},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("register %s upstream: %w", r.cfg.NodeID, err)
+	}
+	if resp.Round <= 0 {
+		return 1, nil
+	}
+	return resp.Round, nil
+}
+
+// Round runs round through the shard and global tiers and returns the
+// committed global model's summary.
+func (r *RegionalAggregator) Round(ctx context.Context, round int) (modeldist.RoundSummary, error) {
+	if r.cfg.ShardID != "" {
+		record, err := r.cfg.Upstream.ShardEpoch(ctx, r.cfg.ShardID)
+		if err != nil {
+			return modeldist.RoundSummary{}, fmt.Errorf("fetch shard %s epoch for round %d: %w", r.cfg.ShardID, round, err)
+		}
+		r.cfg.Federation.SetShardEpoch(&record)
+	}
+	proposal, committed, err := tierRound(ctx, r.cfg.TierConfig, round)
+	if err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	var chain *protocol.CertificateChain
+	if r.cfg.Certify != nil {
+		if chain, err = r.cfg.Certify(ctx, committed); err != nil {
+			return modeldist.RoundSummary{}, fmt.Errorf("certify round %d: %w", round, err)
+		}
+	}
+	task, err := r.cfg.Upstream.Task(ctx, r.cfg.UpstreamFederation, round)
+	if err != nil {
+		return modeldist.RoundSummary{}, fmt.Errorf("fetch upstream task for round %d: %w", round, err)
+	}
+	summary := &protocol.ModelUpdate{
+		NodeID:       r.cfg.NodeID,
+		Round:        round,
+		Weights:      proposal.Weights,
+		Statement:    protocol.NewTrainingStatement(r.cfg.NodeID, task, proposal.Weights),
+		Timestamp:    time.Now().UTC(),
+		Metrics:      protocol.Metrics{Samples: proposal.Samples},
+		FederationID: protocol.FederationOf(r.cfg.UpstreamFederation),
+		SpecVersion:  specVersion(task),
+		Certificate:  chain,
+	}
+	if err := r.cfg.Upstream.SubmitUpdate(ctx, r.cfg.UpstreamFederation, summary); err != nil {
+		return modeldist.RoundSummary{}, fmt.Errorf("submit regional summary for round %d: %w", round, err)
+	}
+	if err := voteOn(ctx, r.cfg.Upstream, r.cfg.UpstreamFederation, r.cfg.NodeID, round, proposal.Weights, r.cfg.PollInterval); err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	return fetchModel(ctx, r.cfg.Upstream, r.cfg.Store, round, r.cfg.PollInterval)
+}
+
+// Run aggregates from round until ctx ends. A round that closes without
+// updates is skipped; any other failure stops the run.
+func (r *RegionalAggregator) Run(ctx context.Context, round int) error {
+	return runRounds(ctx, round, r.Round)
+}
+
+// GlobalConfig configures the global aggregator.
+type GlobalConfig struct {
+	TierConfig
+	// Store is the model store the global aggregator serves. Each
+	// committed round is imported into it.
+	Store *modeldist.ModelStore
+}
+
+// GlobalAggregator commits each round's global model by consensus among
+// the regional aggregators.
+type GlobalAggregator struct {
+	cfg GlobalConfig
+}
+
+// NewGlobal creates the global aggregator.
+func NewGlobal(cfg GlobalConfig) *GlobalAggregator {
+	return &GlobalAggregator{cfg: cfg}
+}
+
+// Round commits round's global model and serves it from the store. A
+// round an automatic rollback already committed into the store is served
+// as it stands; members waiting on its proposal find it committed.
+func (g *GlobalAggregator) Round(ctx context.Context, round int) (modeldist.RoundSummary, error) {
+	if _, summary, ok := g.cfg.Store.Model(round); ok && summary.RollbackOf > 0 {
+		return summary, nil
+	}
+	proposal, summary, err := tierRound(ctx, g.cfg.TierConfig, round)
+	if err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	if err := g.cfg.Store.Import(summary, proposal.Weights); err != nil {
+		return modeldist.RoundSummary{}, fmt.Errorf("serve round %d: %w", round, err)
+	}
+	return summary, nil
+}
+
+// Run aggregates from round until ctx ends. A round that closes without
+// updates is skipped; any other failure stops the run.
+func (g *GlobalAggregator) Run(ctx context.Context, round int) error {
+	return runRounds(ctx, round, g.Round)
+}
+
+func runRounds(ctx context.Context, round int, run func(context.Context, int) (modeldist.RoundSummary, error)) error {
+	for ctx.Err() == nil {
+		_, err := run(ctx, round)
+		if err != nil && !errors.Is(err, ErrNoUpdates) {
+			return err
+		}
+		if err == nil {
+			round++
+		}
+	}
+	return ctx.Err()
+}
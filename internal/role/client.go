@@ -0,0 +1,249 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package role
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/genesis"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Client calls the node API of the aggregator one tier up.
+type Client struct {
+	BaseURL string
+	// Token and APIRole are sent as the bearer token and X-API-Role of
+	// every request. Empty Token sends neither.
+	Token      string
+	APIRole    string
+	HTTPClient *http.Client
+	// TrustedSigner, when set, is the PEM identity key of the aggregator.
+	// Model then refuses a round the aggregator did not sign.
+	TrustedSigner []byte
+	// Validators, when set, must sign every round's commit certificate;
+	// Model and CheckModel refuse a round short of their quorum.
+	Validators *modeldist.Validators
+	// Signer, when set, signs the node's approvals, so certificates of
+	// rounds it approves carry its signature.
+	Signer modeldist.ModelSigner
+	// GenesisDigest, when set, is the genesis digest the node pinned.
+	// Register sends it and refuses an aggregator whose response
+	// references another genesis.
+	GenesisDigest string
+}
+
+// NewClient creates a client against an aggregator API base URL,
+// authenticating with token in the node API role.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		APIRole:    "node",
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Register registers the node and binds it to the federations req lists.
+// With GenesisDigest set, a response referencing another genesis fails
+// with genesis.ErrGenesisMismatch.
+func (c *Client) Register(ctx context.Context, req protocol.RegistrationRequest) (protocol.RegistrationResponse, error) {
+	var resp protocol.RegistrationResponse
+	req.GenesisDigest = c.GenesisDigest
+	if err := c.do(ctx, http.MethodPost, "/api/v1/register", req, &resp); err != nil {
+		return resp, err
+	}
+	if err := genesis.Check(c.GenesisDigest, resp.GenesisDigest); err != nil {
+		return protocol.RegistrationResponse{}, fmt.Errorf("aggregator %s: %w", c.BaseURL, err)
+	}
+	return resp, nil
+}
+
+// SubmitUpdate queues update in federationID for its round.
+func (c *Client) SubmitUpdate(ctx context.Context, federationID string, update *protocol.ModelUpdate) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/"+url.PathEscape(protocol.FederationOf(federationID))+"/updates", update, nil)
+}
+
+// PostStatus sends the node's status heartbeat for federationID.
+func (c *Client) PostStatus(ctx context.Context, federationID string, update protocol.StatusUpdate) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/"+url.PathEscape(protocol.FederationOf(federationID))+"/heartbeats", update, nil)
+}
+
+// Proposal fetches federationID's open proposal for round, failing with
+// ErrNotReady until there is one.
+func (c *Client) Proposal(ctx context.Context, federationID string, round int) (*federation.Proposal, error) {
+	var proposal federation.Proposal
+	path := "/api/v1/" + url.PathEscape(protocol.FederationOf(federationID)) + "/rounds/" + strconv.Itoa(round) + "/proposal"
+	if err := c.do(ctx, http.MethodGet, path, nil, &proposal); err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// ShardEpoch fetches shardID's current membership epoch as the global
+// tier committed it.
+func (c *Client) ShardEpoch(ctx context.Context, shardID string) (protocol.MembershipEpochRecord, error) {
+	var record protocol.MembershipEpochRecord
+	err := c.do(ctx, http.MethodGet, "/api/v1/shards/"+url.PathEscape(shardID)+"/epoch", nil, &record)
+	return record, err
+}
+
+// Task fetches federationID's training task for round, carrying the model
+// spec the round's updates must echo.
+func (c *Client) Task(ctx context.Context, federationID string, round int) (protocol.TrainingTask, error) {
+	var task protocol.TrainingTask
+	path := "/api/v1/" + url.PathEscape(protocol.FederationOf(federationID)) + "/rounds/" + strconv.Itoa(round) + "/task"
+	err := c.do(ctx, http.MethodGet, path, nil, &task)
+	return task, err
+}
+
+// Vote casts vote in federationID.
+func (c *Client) Vote(ctx context.Context, federationID string, vote *consensus.Vote) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/"+url.PathEscape(protocol.FederationOf(federationID))+"/votes", vote, nil)
+}
+
+// Model fetches the committed model of round, failing with ErrNotReady
+// until it is committed. The weights are checked against the round's
+// commit certificate, with Validators its signed approvals, and, with a
+// TrustedSigner, its signature.
+func (c *Client) Model(ctx context.Context, round int) (modeldist.ModelResponse, error) {
+	var model modeldist.ModelResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/model/"+strconv.Itoa(round), nil, &model); err != nil {
+		return modeldist.ModelResponse{}, err
+	}
+	if model.Round != round {
+		return modeldist.ModelResponse{}, fmt.Errorf("%w: aggregator returned round %d for request %d", ErrRequestFailed, model.Round, round)
+	}
+	if err := model.Summary.Certificate.Verify(model.Weights, c.Validators); err != nil {
+		return modeldist.ModelResponse{}, fmt.Errorf("round %d: %w", round, err)
+	}
+	if len(c.TrustedSigner) == 0 {
+		return model, nil
+	}
+	if err := model.Summary.VerifySignature(c.TrustedSigner, model.Weights); err != nil {
+		// The payload's summary may be stale rather than forged, so ask
+		// the commit certificate path for the round's record; it must
+		// itself be signed and cover these weights.
+		summary, fallbackErr := c.RoundSummary(ctx, round)
+		if fallbackErr != nil {
+			return modeldist.ModelResponse{}, fmt.Errorf("%w; certificate path: %v", err, fallbackErr)
+		}
+		if fallbackErr = summary.VerifySignature(c.TrustedSigner, model.Weights); fallbackErr != nil {
+			return modeldist.ModelResponse{}, fmt.Errorf("%w; certificate path: %v", err, fallbackErr)
+		}
+		if fallbackErr = summary.Certificate.Verify(model.Weights, c.Validators); fallbackErr != nil {
+			return modeldist.ModelResponse{}, fmt.Errorf("%w; certificate path: %v", err, fallbackErr)
+		}
+		model.Summary = summary
+	}
+	return model, nil
+}
+
+// RoundSummary fetches round's summary and commit certificate from the
+// rounds listing, without its weights.
+func (c *Client) RoundSummary(ctx context.Context, round int) (modeldist.RoundSummary, error) {
+	var rounds modeldist.RoundsResponse
+	path := "/api/v1/rounds?from=" + strconv.Itoa(round) + "&to=" + strconv.Itoa(round)
+	if err := c.do(ctx, http.MethodGet, path, nil, &rounds); err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	for _, summary := range rounds.Rounds {
+		if summary.Round == round {
+			return summary, nil
+		}
+	}
+	return modeldist.RoundSummary{}, fmt.Errorf("%w: round %d is not listed", ErrRequestFailed, round)
+}
+
+// Receipt fetches nodeID's inclusion receipt for round, failing with
+// ErrNotReady until the round commits. The receipt must name the manifest
+// the round's commit certificate binds and, with a TrustedSigner, carry the
+// aggregator's signature; otherwise it fails with
+// protocol.ErrInvalidReceipt.
+func (c *Client) Receipt(ctx context.Context, round int, nodeID string) (protocol.InclusionReceipt, error) {
+	var receipt protocol.InclusionReceipt
+	path := "/api/v1/receipts/" + strconv.Itoa(round) + "/" + url.PathEscape(nodeID)
+	if err := c.do(ctx, http.MethodGet, path, nil, &receipt); err != nil {
+		return protocol.InclusionReceipt{}, err
+	}
+	if receipt.NodeID != nodeID {
+		return protocol.InclusionReceipt{}, fmt.Errorf("%w: aggregator returned %s's receipt for %s", protocol.ErrInvalidReceipt, receipt.NodeID, nodeID)
+	}
+	summary, err := c.RoundSummary(ctx, round)
+	if err != nil {
+		return protocol.InclusionReceipt{}, err
+	}
+	if err := modeldist.VerifyReceipt(receipt, summary.Certificate, c.TrustedSigner); err != nil {
+		return protocol.InclusionReceipt{}, err
+	}
+	return receipt, nil
+}
+
+// CheckModel checks weights the node already holds against summary, as
+// RoundSummary returned it: the weights must be the ones its commit
+// certificate covers, approved by Validators when set, and, with a
+// TrustedSigner, the ones the aggregator signed.
+func (c *Client) CheckModel(summary modeldist.RoundSummary, weights []byte) error {
+	if err := summary.Certificate.Verify(weights, c.Validators); err != nil {
+		return fmt.Errorf("round %d: %w", summary.Round, err)
+	}
+	if len(c.TrustedSigner) == 0 {
+		return nil
+	}
+	return summary.VerifySignature(c.TrustedSigner, weights)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		req.Header.Set("X-API-Role", c.APIRole)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusServiceUnavailable:
+		return fmt.Errorf("%w: %s %s: status %d", ErrNotReady, method, path, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: %s %s: status %d: %s", ErrRequestFailed, method, path, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,329 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/upload"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Trainer trains one round from the base model and returns the local model
+// and the number of samples it was trained on.
+type Trainer func(ctx context.Context, round int, base []float64) ([]float64, int, error)
+
+// QuadraticTrainer takes one gradient step of rate on 0.5*||x - target||^2
+// per round, over samples local samples.
+func QuadraticTrainer(target []float64, rate float64, samples int) Trainer {
+	return func(_ context.Context, _ int, base []float64) ([]float64, int, error) {
+		if len(base) != len(target) {
+			return nil, 0, fmt.Errorf("%w: base model has %d weights, target %d", batch.ErrShapeMismatch, len(base), len(target))
+		}
+		local := make([]float64, len(base))
+		for i := range base {
+			local[i] = base[i] - rate*(base[i]-target[i])
+		}
+		return local, samples, nil
+	}
+}
+
+// EdgeConfig configures an edge node.
+type EdgeConfig struct {
+	NodeID string
+	// Federation is the regional federation the edge joins. Empty joins
+	// protocol.DefaultFederation.
+	Federation string
+	Regional   *Client
+	Trainer    Trainer
+	// Store keeps the global models the edge has fetched, each checked
+	// against its commit certificate.
+	Store *modeldist.ModelStore
+	// Cache, when set, keeps the fetched models on disk, so a restarted
+	// edge imports the model it trains from without downloading it again
+	// while the cached copy is the one the regional aggregator committed.
+	Cache *modeldist.ModelCache
+	// Dim is the model size trained from when no round is committed yet
+	// and the federation's spec does not partition the model.
+	Dim          int
+	PollInterval time.Duration
+	// Capabilities is sent with the registration.
+	Capabilities *protocol.CapabilityManifest
+	// Stamp, when set, annotates each status heartbeat before it is sent.
+	Stamp func(update *protocol.StatusUpdate)
+	// Upload, when set, chooses each round's upload tier. The edge
+	// declares the tier in a heartbeat before submitting, and a skipped
+	// round sends no update, so the regional counts it as skipped rather
+	// than as a fault.
+	Upload *upload.Policy
+}
+
+// EdgeNode trains each round from the last global model, submits its update
+// to its regional aggregator, and approves the regional proposal only if
+// the proposal applied its update unaltered.
+type EdgeNode struct {
+	cfg EdgeConfig
+	// personal is the last local model, whose personal segments the next
+	// round trains from when the spec partitions the model.
+	personal []float64
+}
+
+// NewEdge creates an edge node.
+func NewEdge(cfg EdgeConfig) *EdgeNode {
+	return &EdgeNode{cfg: cfg}
+}
+
+// Join registers the edge with its regional aggregator and returns the
+// first round it should train.
+func (e *EdgeNode) Join(ctx context.Context) (int, error) {
+	resp, err := e.cfg.Regional.Register(ctx, protocol.RegistrationRequest{
+		NodeID:       e.cfg.NodeID,
+		Capabilities: e.cfg.Capabilities,
+		Federations:  []string{protocol.FederationOf(e.cfg.Federation)},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("register %s: %w", e.cfg.NodeID, err)
+	}
+	if resp.Round <= 0 {
+		return 1, nil
+	}
+	return resp.Round, nil
+}
+
+// Round trains and votes in round and returns the global model it
+// committed.
+func (e *EdgeNode) Round(ctx context.Context, round int) (modeldist.RoundSummary, error) {
+	e.heartbeat(ctx, protocol.StatusUpdate{Status: "training", Round: round})
+	task, err := e.cfg.Regional.Task(ctx, e.cfg.Federation, round)
+	if err != nil {
+		return modeldist.RoundSummary{}, fmt.Errorf("fetch task for round %d: %w", round, err)
+	}
+	spec := task.Spec
+	partitioned := spec != nil && spec.Partitioned()
+	base, err := e.base(ctx, round-1, task)
+	if err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	if partitioned {
+		if base, err = spec.Merge(base, e.personal); err != nil {
+			return modeldist.RoundSummary{}, fmt.Errorf("merge round %d: %w", round-1, err)
+		}
+	}
+	local, samples, err := e.cfg.Trainer(ctx, round, base)
+	if err != nil {
+		return modeldist.RoundSummary{}, fmt.Errorf("train round %d: %w", round, err)
+	}
+	if partitioned {
+		// The personal segments stay here; only the global ones are sent.
+		e.personal = local
+		if local, err = spec.Strip(local); err != nil {
+			return modeldist.RoundSummary{}, fmt.Errorf("strip round %d: %w", round, err)
+		}
+	}
+	prepared := &batch.Update{Weights: local, SampleCount: samples}
+	if e.cfg.Upload != nil {
+		decision := e.cfg.Upload.Decide(round, len(local))
+		e.heartbeat(ctx, decision.Status(e.cfg.NodeID))
+		if prepared, err = decision.Prepare(e.cfg.NodeID, local, samples); err != nil {
+			return modeldist.RoundSummary{}, fmt.Errorf("prepare round %d: %w", round, err)
+		}
+		if prepared == nil {
+			log.Printf("edge %s: skipping upload of round %d: %s", e.cfg.NodeID, round, decision.Reason)
+			return e.fetch(ctx, round)
+		}
+	}
+	encoded := prepared.Bytes()
+	update := &protocol.ModelUpdate{
+		NodeID:       e.cfg.NodeID,
+		Round:        round,
+		Quantized:    prepared.Quantized,
+		Statement:    protocol.NewTrainingStatement(e.cfg.NodeID, task, encoded),
+		Timestamp:    time.Now().UTC(),
+		Metrics:      protocol.Metrics{Samples: samples},
+		FederationID: protocol.FederationOf(e.cfg.Federation),
+		SpecVersion:  specVersion(task),
+	}
+	if prepared.Quantized == nil {
+		update.Weights = encoded
+	}
+	if err := e.cfg.Regional.SubmitUpdate(ctx, e.cfg.Federation, update); err != nil {
+		return modeldist.RoundSummary{}, fmt.Errorf("submit round %d: %w", round, err)
+	}
+
+	if err := voteOn(ctx, e.cfg.Regional, e.cfg.Federation, e.cfg.NodeID, round, encoded, e.cfg.PollInterval); err != nil {
+		return modeldist.RoundSummary{}, err
+	}
+	return e.fetch(ctx, round)
+}
+
+// Run trains from round until ctx ends or a round fails.
+func (e *EdgeNode) Run(ctx context.Context, round int) error {
+	for ; ctx.Err() == nil; round++ {
+		if _, err := e.Round(ctx, round); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// heartbeat stamps update as the edge's status and sends it to the
+// regional aggregator. Heartbeats are best effort: a failed one is logged
+// and the round goes on.
+func (e *EdgeNode) heartbeat(ctx context.Context, update protocol.StatusUpdate) {
+	update.NodeID = e.cfg.NodeID
+	update.FederationID = protocol.FederationOf(e.cfg.Federation)
+	update.Timestamp = time.Now().UTC()
+	if e.cfg.Stamp != nil {
+		e.cfg.Stamp(&update)
+	}
+	if err := e.cfg.Regional.PostStatus(ctx, e.cfg.Federation, update); err != nil {
+		log.Printf("edge %s: status heartbeat for round %d: %v", e.cfg.NodeID, update.Round, err)
+	}
+}
+
+// base returns the committed model of round, fetching it from the regional
+// aggregator if the edge does not hold it, or zeros before round 1: the
+// global segment of task's spec when it partitions the model.
+func (e *EdgeNode) base(ctx context.Context, round int, task protocol.TrainingTask) ([]float64, error) {
+	if round <= 0 {
+		if task.Spec != nil && task.Spec.Partitioned() {
+			return make([]float64, task.Spec.GlobalDimension()), nil
+		}
+		return make([]float64, e.cfg.Dim), nil
+	}
+	weights, _, ok := e.cfg.Store.Model(round)
+	if !ok && e.fromCache(ctx, round) {
+		weights, _, ok = e.cfg.Store.Model(round)
+	}
+	if !ok {
+		summary, err := e.fetch(ctx, round)
+		if err != nil {
+			return nil, err
+		}
+		weights, _, _ = e.cfg.Store.Model(summary.Round)
+	}
+	return batch.DecodeWeights(weights)
+}
+
+// fetch is fetchModel, caching the model it imports.
+func (e *EdgeNode) fetch(ctx context.Context, round int) (modeldist.RoundSummary, error) {
+	summary, err := fetchModel(ctx, e.cfg.Regional, e.cfg.Store, round, e.cfg.PollInterval)
+	if err != nil || e.cfg.Cache == nil {
+		return summary, err
+	}
+	if weights, _, ok := e.cfg.Store.Model(round); ok {
+		if err := e.cfg.Cache.Put(round, weights); err != nil {
+			log.Printf("edge %s: %v", e.cfg.NodeID, err)
+		}
+	}
+	return summary, nil
+}
+
+// fromCache imports round's model from the cache if the cached copy is
+// the one the regional aggregator lists for the round, and reports
+// whether it did. A missing, stale, or corrupt copy is left to be fetched
+// again; a corrupt one has been discarded by the cache.
+func (e *EdgeNode) fromCache(ctx context.Context, round int) bool {
+	if e.cfg.Cache == nil {
+		return false
+	}
+	summary, err := e.cfg.Regional.RoundSummary(ctx, round)
+	if err != nil {
+		return false
+	}
+	weights, err := e.cfg.Cache.Get(round, summary.ModelDigest)
+	if err != nil {
+		if errors.Is(err, modeldist.ErrCacheCorrupt) {
+			log.Printf("edge %s: %v; fetching it again", e.cfg.NodeID, err)
+		}
+		return false
+	}
+	if err := e.cfg.Regional.CheckModel(summary, weights); err != nil {
+		return false
+	}
+	return e.cfg.Store.Import(summary, weights) == nil
+}
+
+// specVersion is the spec version updates answering task must carry, zero
+// when the federation registers no spec.
+func specVersion(task protocol.TrainingTask) int {
+	if task.Spec == nil {
+		return 0
+	}
+	return task.Spec.Version
+}
+
+// voteOn waits for federationID's proposal for round and votes on it as
+// nodeID, approving only if the proposal applied the update whose encoding
+// is submitted, and signing the approval with upstream's Signer, and, as a
+// member of the proposal's shard epoch, its quorum certificate too. A
+// round that commits before the vote is cast needs none.
+func voteOn(ctx context.Context, upstream *Client, federationID, nodeID string, round int, submitted []byte, interval time.Duration) error {
+	committed := func() bool {
+		_, err := upstream.Model(ctx, round)
+		return err == nil
+	}
+	var proposal *federation.Proposal
+	err := poll(ctx, interval, func() error {
+		var err error
+		proposal, err = upstream.Proposal(ctx, federationID, round)
+		if errors.Is(err, ErrNotReady) && committed() {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("await proposal for round %d: %w", round, err)
+	}
+	if proposal == nil {
+		return nil
+	}
+	entry, ok := proposal.Manifest.Entry(nodeID)
+	approve := ok && entry.Included && entry.UpdateDigest == protocol.UpdateDigest(submitted)
+	vote := &consensus.Vote{NodeID: nodeID, ProposalID: proposal.ID, Approve: approve, Timestamp: time.Now()}
+	if approve && upstream.Signer != nil {
+		digest := proposal.ApprovalDigest()
+		if vote.CommitSignature, err = upstream.Signer.SignData(digest[:]); err != nil {
+			return fmt.Errorf("sign approval of round %d: %w", round, err)
+		}
+		if proposal.Epoch != nil {
+			if _, member := proposal.Epoch.Member(nodeID); member {
+				cert := proposal.QuorumCertificate()
+				digest := cert.SigningDigest()
+				if vote.CertificateSignature, err = upstream.Signer.SignData(digest[:]); err != nil {
+					return fmt.Errorf("sign certificate of round %d: %w", round, err)
+				}
+			}
+		}
+	}
+	if err := upstream.Vote(ctx, federationID, vote); err != nil && !committed() {
+		return fmt.Errorf("vote on round %d: %w", round, err)
+	}
+	return nil
+}
+
+// fetchModel waits for round's committed model and imports it into store.
+func fetchModel(ctx context.Context, upstream *Client, store *modeldist.ModelStore, round int, interval time.Duration) (modeldist.RoundSummary, error) {
+	var model modeldist.ModelResponse
+	err := poll(ctx, interval, func() error {
+		var err error
+		model, err = upstream.Model(ctx, round)
+		return err
+	})
+	if err != nil {
+		return modeldist.RoundSummary{}, fmt.Errorf("await model for round %d: %w", round, err)
+	}
+	if err := store.Import(model.Summary, model.Weights); err != nil {
+		return modeldist.RoundSummary{}, fmt.Errorf("import round %d: %w", round, err)
+	}
+	return model.Summary, nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package role
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the role runners and their client.
+// Match them with errors.Is; never compare error strings.
+var (
+	// ErrUnknownRole means the configured role is not edge, regional, or
+	// global. Not retryable.
+	ErrUnknownRole = errors.New("unknown role")
+	// ErrNotReady means the aggregator has not produced the round's
+	// proposal or model yet. Retryable.
+	ErrNotReady = errors.New("round not ready")
+	// ErrRequestFailed means the aggregator refused a request. Not
+	// retryable without changing the request.
+	ErrRequestFailed = errors.New("aggregator request failed")
+	// ErrNoUpdates means a round closed with no updates to aggregate.
+	// Retryable in a later round.
+	ErrNoUpdates = errors.New("no updates for round")
+	// ErrInvalidTransportConfig means a transport's pool limits or
+	// timeouts are out of range. Not retryable with the same config.
+	ErrInvalidTransportConfig = errors.New("invalid transport config")
+)
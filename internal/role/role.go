@@ -0,0 +1,69 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package role runs one tier of the aggregation hierarchy. Edge nodes train
+// and vote on their regional aggregator's proposals; regional aggregators
+// run their shard's consensus and submit each committed shard model upstream
+// as a regional summary; the global aggregator runs the top-tier consensus
+// over the regional summaries and serves the committed global model, which
+// regionals relay to their edges.
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Role selects the tier a node-agent runs.
+type Role string
+
+const (
+	// Edge trains on local data and votes on its regional's proposals.
+	Edge Role = "edge"
+	// Regional aggregates its edges' updates and submits the result
+	// upstream.
+	Regional Role = "regional"
+	// Global aggregates regional summaries into the global model.
+	Global Role = "global"
+)
+
+// Parse reads a role name. Empty is Edge, the tier every node-agent ran
+// before roles existed.
+func Parse(name string) (Role, error) {
+	switch r := Role(strings.ToLower(strings.TrimSpace(name))); r {
+	case "":
+		return Edge, nil
+	case Edge, Regional, Global:
+		return r, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownRole, name)
+	}
+}
+
+// DefaultPollInterval is how often a tier polls for the next step of a
+// round when its config leaves the interval zero.
+const DefaultPollInterval = 50 * time.Millisecond
+
+// poll calls fn every interval until it returns something other than
+// ErrNotReady or ctx ends.
+func poll(ctx context.Context, interval time.Duration, fn func() error) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := fn()
+		if !errors.Is(err, ErrNotReady) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v", ctx.Err(), err)
+		case <-ticker.C:
+		}
+	}
+}
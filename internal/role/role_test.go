@@ -0,0 +1,837 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package role
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/api"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/certchain"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/genesis"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/upload"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// aggregatorNode is an in-process aggregator tier: the federation it runs
+// consensus over, the model store it serves, and its node API.
+type aggregatorNode struct {
+	id         string
+	federation *federation.Federation
+	store      *modeldist.ModelStore
+	handler    *api.Handler
+	server     *httptest.Server
+}
+
+func startAggregator(t *testing.T, id string) *aggregatorNode {
+	t.Helper()
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: id, MinVerifications: 1, Timeout: 5 * time.Second}))
+	f, err := registry.Create(protocol.DefaultFederation)
+	if err != nil {
+		t.Fatalf("create federation: %v", err)
+	}
+	store := modeldist.NewModelStore(0)
+	h := api.NewHandler(nil, nil, nil, nil)
+	h.SetVerifier(p2p.NewVerifier(id, 1, time.Second))
+	h.SetFederationRegistry(registry)
+	h.SetModelStore(store)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return &aggregatorNode{id: id, federation: f, store: store, handler: h, server: server}
+}
+
+func configureNodeAuth(t *testing.T) string {
+	t.Helper()
+	tokenPath := filepath.Join(t.TempDir(), "api_token")
+	if err := os.WriteFile(tokenPath, []byte("node-token"), 0o600); err != nil {
+		t.Fatalf("write token file: %v", err)
+	}
+	t.Setenv("MOHAWK_API_AUTH_MODE", "file-only")
+	t.Setenv("MOHAWK_API_TOKEN_FILE", tokenPath)
+	t.Setenv("MOHAWK_API_ENFORCE_ROLES", "true")
+	return "node-token"
+}
+
+func TestParseRole(t *testing.T) {
+	for name, want := range map[string]Role{"": Edge, "edge": Edge, " Regional ": Regional, "GLOBAL": Global} {
+		if got, err := Parse(name); err != nil || got != want {
+			t.Fatalf("Parse(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := Parse("observer"); !errors.Is(err, ErrUnknownRole) {
+		t.Fatalf("expected ErrUnknownRole, got %v", err)
+	}
+}
+
+// TestHierarchyCompletesRounds brings up 1 global, 2 regional, and 6 edge
+// tiers, 3 edges per regional, and runs two rounds through them.
+func TestHierarchyCompletesRounds(t *testing.T) {
+	token := configureNodeAuth(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	const dim, rounds, perRegion = 4, 2, 3
+	tiers := TierConfig{CollectWindow: 5 * time.Second, VoteWindow: 5 * time.Second, PollInterval: 5 * time.Millisecond}
+
+	global := startAggregator(t, "global")
+	globalTier := tiers
+	globalTier.NodeID, globalTier.Federation, globalTier.Expected = global.id, global.federation, 2
+	globalAgg := NewGlobal(GlobalConfig{TierConfig: globalTier, Store: global.store})
+	bus := events.NewBus()
+	lifecycle := bus.Subscribe("test", 0)
+	global.federation.SetEvents(bus)
+
+	var regionals []*RegionalAggregator
+	var edges []*EdgeNode
+	var edgeStores []*modeldist.ModelStore
+	targets := make(map[string][]float64)
+	for r := 0; r < 2; r++ {
+		node := startAggregator(t, fmt.Sprintf("regional-%d", r))
+		tier := tiers
+		tier.NodeID, tier.Federation, tier.Expected = node.id, node.federation, perRegion
+		regional := NewRegional(RegionalConfig{TierConfig: tier, Upstream: NewClient(global.server.URL, token), Store: node.store})
+		if _, err := regional.Join(ctx); err != nil {
+			t.Fatalf("join %s: %v", node.id, err)
+		}
+		regionals = append(regionals, regional)
+
+		for e := 0; e < perRegion; e++ {
+			nodeID := fmt.Sprintf("edge-%d-%d", r, e)
+			target := make([]float64, dim)
+			for i := range target {
+				target[i] = float64(r*perRegion+e) + float64(i)
+			}
+			targets[nodeID] = target
+			store := modeldist.NewModelStore(0)
+			edge := NewEdge(EdgeConfig{
+				NodeID:       nodeID,
+				Regional:     NewClient(node.server.URL, token),
+				Trainer:      QuadraticTrainer(target, 0.5, 10),
+				Store:        store,
+				Dim:          dim,
+				PollInterval: 5 * time.Millisecond,
+			})
+			if first, err := edge.Join(ctx); err != nil || first != 1 {
+				t.Fatalf("join %s: first round %d, %v", nodeID, first, err)
+			}
+			edges = append(edges, edge)
+			edgeStores = append(edgeStores, store)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(edges)+len(regionals)+1)
+	run := func(name string, round func(context.Context, int) (modeldist.RoundSummary, error)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := 1; r <= rounds; r++ {
+				if _, err := round(ctx, r); err != nil {
+					errs <- fmt.Errorf("%s round %d: %w", name, r, err)
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+	run("global", globalAgg.Round)
+	for i, regional := range regionals {
+		run(fmt.Sprintf("regional-%d", i), regional.Round)
+	}
+	for i, edge := range edges {
+		run(fmt.Sprintf("edge-%d", i), edge.Round)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	// Every edge trains 10 samples, so the global model is the plain mean
+	// of the edges' steps: x_r = x_{r-1} + 0.5*(mean target - x_{r-1}).
+	mean := make([]float64, dim)
+	for _, target := range targets {
+		for i := range target {
+			mean[i] += target[i] / float64(len(targets))
+		}
+	}
+	want := make([]float64, dim)
+	for r := 0; r < rounds; r++ {
+		for i := range want {
+			want[i] += 0.5 * (mean[i] - want[i])
+		}
+	}
+	weights, summary, ok := global.store.Model(rounds)
+	if !ok {
+		t.Fatalf("global store lacks round %d", rounds)
+	}
+	got, err := batch.DecodeWeights(weights)
+	if err != nil {
+		t.Fatalf("decode global model: %v", err)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Fatalf("global model = %v, want %v", got, want)
+		}
+	}
+	// The global quorum is the global aggregator and both regionals.
+	if len(summary.Certificate.Approvals) != 3 {
+		t.Fatalf("global certificate approvals = %v", summary.Certificate.Approvals)
+	}
+	for i, store := range edgeStores {
+		if _, edgeSummary, ok := store.Model(rounds); !ok || edgeSummary.ModelDigest != summary.ModelDigest {
+			t.Fatalf("edge %d did not receive the committed global model", i)
+		}
+	}
+	for _, regional := range regionals {
+		if _, shard, ok := regional.cfg.Federation.ModelStore.Model(rounds); !ok || shard.ParticipantCount != perRegion {
+			t.Fatalf("%s shard round %d: %+v", regional.cfg.NodeID, rounds, shard)
+		}
+	}
+
+	// The global round's scheduler, aggregator, and coordinator published
+	// each round's stages in order.
+	lifecycle.Close()
+	stages := []events.Kind{events.KindRoundStarted, events.KindUpdatesClosed, events.KindProposalCreated, events.KindQuorumReached, events.KindCommitted}
+	i := 0
+	for event := range lifecycle.Events() {
+		if i >= rounds*len(stages) || event.Kind != stages[i%len(stages)] || event.Round != i/len(stages)+1 || event.FederationID != protocol.DefaultFederation {
+			t.Fatalf("global event %d = %+v", i, event)
+		}
+		i++
+	}
+	if i != rounds*len(stages) {
+		t.Fatalf("global published %d events, want %d", i, rounds*len(stages))
+	}
+}
+
+// TestRegionalSummaryIsCertifiedByItsShard runs a round through a global
+// tier that admits regional summaries only with a certificate chain, and a
+// regional whose edges are the members of its shard's epoch.
+func TestRegionalSummaryIsCertifiedByItsShard(t *testing.T) {
+	token := configureNodeAuth(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	const perRegion = 3
+	tiers := TierConfig{CollectWindow: 5 * time.Second, VoteWindow: 5 * time.Second, PollInterval: 5 * time.Millisecond}
+	globalKey, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("global identity: %v", err)
+	}
+	ledger, err := certchain.NewEpochLedger(globalKey)
+	if err != nil {
+		t.Fatalf("ledger: %v", err)
+	}
+	global := startAggregator(t, "global")
+	global.handler.SetEpochLedger(ledger)
+	global.federation.SetSummaryCheck(ledger.CheckSummary)
+	globalTier := tiers
+	globalTier.NodeID, globalTier.Federation, globalTier.Expected = global.id, global.federation, 1
+	globalAgg := NewGlobal(GlobalConfig{TierConfig: globalTier, Store: global.store})
+
+	node := startAggregator(t, "regional-east")
+	tier := tiers
+	tier.NodeID, tier.Federation, tier.Expected = node.id, node.federation, perRegion
+	regional := NewRegional(RegionalConfig{
+		TierConfig: tier,
+		Upstream:   NewClient(global.server.URL, token),
+		Store:      node.store,
+		ShardID:    "east",
+		Certify:    CertifyShard(node.federation),
+	})
+	if _, err := regional.Join(ctx); err != nil {
+		t.Fatalf("join regional: %v", err)
+	}
+
+	record := protocol.MembershipEpochRecord{ShardID: "east", Epoch: 1, Aggregator: node.id}
+	var edges []*EdgeNode
+	for e := 0; e < perRegion; e++ {
+		nodeID := fmt.Sprintf("edge-%d", e)
+		identity, err := crypto.NewSecureChannel()
+		if err != nil {
+			t.Fatalf("edge identity: %v", err)
+		}
+		publicKey, _ := identity.ExportPublicKey()
+		record.Members = append(record.Members, protocol.ShardMember{NodeID: nodeID, PublicKey: publicKey})
+		client := NewClient(node.server.URL, token)
+		client.Signer = identity
+		edge := NewEdge(EdgeConfig{
+			NodeID:       nodeID,
+			Regional:     client,
+			Trainer:      QuadraticTrainer([]float64{float64(e), 1}, 0.5, 10),
+			Store:        modeldist.NewModelStore(0),
+			Dim:          2,
+			PollInterval: 5 * time.Millisecond,
+		})
+		if _, err := edge.Join(ctx); err != nil {
+			t.Fatalf("join %s: %v", nodeID, err)
+		}
+		edges = append(edges, edge)
+	}
+	if _, err := ledger.Commit(record); err != nil {
+		t.Fatalf("commit epoch: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(edges)+2)
+	run := func(name string, round func(context.Context, int) (modeldist.RoundSummary, error)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := round(ctx, 1); err != nil {
+				errs <- fmt.Errorf("%s: %w", name, err)
+				cancel()
+			}
+		}()
+	}
+	run("global", globalAgg.Round)
+	run("regional", regional.Round)
+	for i, edge := range edges {
+		run(fmt.Sprintf("edge-%d", i), edge.Round)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	if _, _, ok := global.store.Model(1); !ok {
+		t.Fatal("global did not commit the certified round")
+	}
+	chain, ok := node.federation.CertificateChain(1)
+	if !ok || len(chain.Certificate.Signatures) != perRegion {
+		t.Fatalf("shard certificate = %+v, %v", chain, ok)
+	}
+	if err := certchain.ValidateCertificateChain(*chain, ledger.PublicKey(), 0); err != nil {
+		t.Fatalf("validate chain: %v", err)
+	}
+}
+
+// serveModels mirrors the /api/v1/model/{round} and /api/v1/rounds
+// contract over store, passing each model payload through tamper.
+func serveModels(t *testing.T, store *modeldist.ModelStore, tamper func(*modeldist.ModelResponse)) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/rounds", func(w http.ResponseWriter, r *http.Request) {
+		from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+		to, _ := strconv.Atoi(r.URL.Query().Get("to"))
+		summaries := store.Summaries(from, to)
+		_ = json.NewEncoder(w).Encode(modeldist.RoundsResponse{Rounds: summaries, LatestRound: store.LatestRound(), Count: len(summaries)})
+	})
+	mux.HandleFunc("/api/v1/model/", func(w http.ResponseWriter, r *http.Request) {
+		round, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/v1/model/"))
+		weights, summary, ok := store.Model(round)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		resp := modeldist.ModelResponse{Round: round, Weights: weights, Summary: summary}
+		if tamper != nil {
+			tamper(&resp)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/api/v1/receipts/{round}/{nodeID}", func(w http.ResponseWriter, r *http.Request) {
+		round, _ := strconv.Atoi(r.PathValue("round"))
+		receipt, err := store.Receipt(round, r.PathValue("nodeID"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(receipt)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientRefusesModelsTheRegionalDidNotSign(t *testing.T) {
+	ctx := context.Background()
+	regional, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("regional identity: %v", err)
+	}
+	regionalKey, err := regional.ExportPublicKey()
+	if err != nil {
+		t.Fatalf("export key: %v", err)
+	}
+	store := modeldist.NewModelStore(0)
+	if err := store.SetSigner(regional); err != nil {
+		t.Fatalf("set signer: %v", err)
+	}
+	weights := batch.Update{Weights: []float64{1, 2, 3}}.Bytes()
+	cert := modeldist.CommitCertificate{Round: 1, ProposalID: "p-1", ModelDigest: protocol.WeightsDigest(weights), QuorumSize: 2, Approvals: []string{"edge-1", "edge-2"}}
+	model := &protocol.AggregateModel{Round: 1, Weights: weights, Participants: []string{"edge-2", "edge-1"}}
+	if _, err := store.CommitAggregate(model, cert); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if digest := model.SigningDigest(); crypto.VerifyWithPublicKey(regionalKey, digest[:], model.Signature) != nil {
+		t.Fatal("committed aggregate does not carry the regional's signature")
+	}
+
+	fetch := func(tamper func(*modeldist.ModelResponse), trusted []byte) (modeldist.ModelResponse, error) {
+		client := NewClient(serveModels(t, store, tamper).URL, "")
+		client.TrustedSigner = trusted
+		return client.Model(ctx, 1)
+	}
+
+	got, err := fetch(nil, regionalKey)
+	if err != nil || got.Summary.SignerFingerprint != model.SignerFingerprint {
+		t.Fatalf("correctly signed model refused: %v", err)
+	}
+
+	// Poisoned weights, with the certificate and digest rewritten to match
+	// and the signature left over from the honest model: the round's record
+	// on the certificate path still names the honest weights.
+	poisoned := batch.Update{Weights: []float64{100, 200, 300}}.Bytes()
+	_, err = fetch(func(resp *modeldist.ModelResponse) {
+		resp.Weights = poisoned
+		resp.Summary.ModelDigest = protocol.WeightsDigest(poisoned)
+		resp.Summary.Certificate.ModelDigest = resp.Summary.ModelDigest
+	}, regionalKey)
+	if !errors.Is(err, protocol.ErrModelSignature) {
+		t.Fatalf("tampered weights with a stale signature: expected ErrModelSignature, got %v", err)
+	}
+
+	// A payload whose summary lost its signature on the way is recovered
+	// from the certificate path, since the weights are the signed ones.
+	got, err = fetch(func(resp *modeldist.ModelResponse) { resp.Summary.Signature = nil }, regionalKey)
+	if err != nil || len(got.Summary.Signature) == 0 {
+		t.Fatalf("unsigned payload of signed weights: %v", err)
+	}
+
+	impostor, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("impostor identity: %v", err)
+	}
+	impostorKey, err := impostor.ExportPublicKey()
+	if err != nil {
+		t.Fatalf("export key: %v", err)
+	}
+	if _, err := fetch(nil, impostorKey); !errors.Is(err, protocol.ErrModelSignature) {
+		t.Fatalf("model signed by another aggregator: expected ErrModelSignature, got %v", err)
+	}
+
+	unsigned := modeldist.NewModelStore(0)
+	if _, err := unsigned.Commit(1, weights, 2, nil, cert); err != nil {
+		t.Fatalf("commit unsigned: %v", err)
+	}
+	client := NewClient(serveModels(t, unsigned, nil).URL, "")
+	client.TrustedSigner = regionalKey
+	if _, err := client.Model(ctx, 1); !errors.Is(err, protocol.ErrUnsignedModel) {
+		t.Fatalf("unsigned model: expected ErrUnsignedModel, got %v", err)
+	}
+}
+
+func TestClientVerifiesItsInclusionReceipt(t *testing.T) {
+	ctx := context.Background()
+	regional, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("regional identity: %v", err)
+	}
+	regionalKey, err := regional.ExportPublicKey()
+	if err != nil {
+		t.Fatalf("export key: %v", err)
+	}
+	store := modeldist.NewModelStore(0)
+	if err := store.SetSigner(regional); err != nil {
+		t.Fatalf("set signer: %v", err)
+	}
+	manifest := &protocol.ContributionManifest{Round: 1, Entries: []protocol.ContributionEntry{
+		{NodeID: "edge-1", UpdateDigest: protocol.UpdateDigest([]byte("edge-1")), SampleCount: 10, AppliedWeight: 1, Included: true, Reason: protocol.ReasonIncluded},
+		{NodeID: "edge-2", UpdateDigest: protocol.UpdateDigest([]byte("edge-2")), SampleCount: 10, Reason: protocol.ReasonNormOutlier},
+	}}
+	weights := batch.Update{Weights: []float64{1, 2, 3}}.Bytes()
+	cert := modeldist.CommitCertificate{Round: 1, ProposalID: "p-1", ModelDigest: protocol.WeightsDigest(weights), QuorumSize: 1, Approvals: []string{"edge-1"}, ManifestDigest: manifest.Digest()}
+	model := &protocol.AggregateModel{Round: 1, Weights: weights, Participants: []string{"edge-1"}, ManifestDigest: manifest.Digest()}
+	if _, err := store.CommitAggregate(model, cert); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if err := store.AttachManifest(manifest); err != nil {
+		t.Fatalf("attach manifest: %v", err)
+	}
+
+	client := NewClient(serveModels(t, store, nil).URL, "")
+	client.TrustedSigner = regionalKey
+	receipt, err := client.Receipt(ctx, 1, "edge-2")
+	if err != nil {
+		t.Fatalf("genuine receipt refused: %v", err)
+	}
+	if receipt.Included || receipt.Reason != protocol.ReasonNormOutlier {
+		t.Fatalf("edge-2 receipt: included %v, reason %q", receipt.Included, receipt.Reason)
+	}
+	if _, err := client.Receipt(ctx, 1, "edge-3"); !errors.Is(err, ErrNotReady) {
+		t.Fatalf("node outside the manifest: expected ErrNotReady, got %v", err)
+	}
+
+	impostor, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("impostor identity: %v", err)
+	}
+	client.TrustedSigner, err = impostor.ExportPublicKey()
+	if err != nil {
+		t.Fatalf("export key: %v", err)
+	}
+	if _, err := client.Receipt(ctx, 1, "edge-1"); !errors.Is(err, protocol.ErrInvalidReceipt) {
+		t.Fatalf("receipt signed by another aggregator: expected ErrInvalidReceipt, got %v", err)
+	}
+}
+
+func TestEdgeRefusesAggregatorOfAnotherGenesis(t *testing.T) {
+	ctx := context.Background()
+	pinned := protocol.UpdateDigest([]byte("genesis"))
+	var sent protocol.RegistrationRequest
+	serve := func(referenced string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&sent)
+			_ = json.NewEncoder(w).Encode(protocol.RegistrationResponse{NodeID: sent.NodeID, Approved: true, Round: 3, GenesisDigest: referenced})
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	join := func(referenced string) (int, error) {
+		client := NewClient(serve(referenced).URL, "")
+		client.GenesisDigest = pinned
+		return NewEdge(EdgeConfig{NodeID: "edge-1", Regional: client}).Join(ctx)
+	}
+
+	if round, err := join(pinned); err != nil || round != 3 || sent.GenesisDigest != pinned {
+		t.Fatalf("join under the pinned genesis: round %d, %v; request carried %q", round, err, sent.GenesisDigest)
+	}
+	for _, referenced := range []string{protocol.UpdateDigest([]byte("another genesis")), ""} {
+		if _, err := join(referenced); !errors.Is(err, genesis.ErrGenesisMismatch) {
+			t.Fatalf("aggregator referencing %q: expected ErrGenesisMismatch, got %v", referenced, err)
+		}
+	}
+}
+
+func TestEdgeRestartsFromItsModelCache(t *testing.T) {
+	if testing.Short() {
+		t.Skip("moves a 50MB model")
+	}
+	ctx := context.Background()
+	regional, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("regional identity: %v", err)
+	}
+	regionalKey, _ := regional.ExportPublicKey()
+	store := modeldist.NewModelStore(0)
+	if err := store.SetSigner(regional); err != nil {
+		t.Fatalf("set signer: %v", err)
+	}
+	commit := func(round int, fill float64) {
+		t.Helper()
+		model := make([]float64, 50<<20/8)
+		for i := range model {
+			model[i] = fill + float64(i%1000)
+		}
+		weights := batch.Update{Weights: model}.Bytes()
+		cert := modeldist.CommitCertificate{Round: round, ProposalID: fmt.Sprintf("p-%d", round), ModelDigest: protocol.WeightsDigest(weights), QuorumSize: 1, Approvals: []string{"edge-1"}}
+		if _, err := store.Commit(round, weights, 1, nil, cert); err != nil {
+			t.Fatalf("commit round %d: %v", round, err)
+		}
+	}
+	commit(1, 1)
+	var fetched atomic.Int32
+	server := serveModels(t, store, func(*modeldist.ModelResponse) { fetched.Add(1) })
+
+	dir := t.TempDir()
+	// start is a node-agent restart: a fresh model store over the same
+	// cache directory.
+	start := func(round int) time.Duration {
+		t.Helper()
+		cache, err := modeldist.NewModelCache(dir, 2, 0)
+		if err != nil {
+			t.Fatalf("open cache: %v", err)
+		}
+		client := NewClient(server.URL, "")
+		client.TrustedSigner = regionalKey
+		edge := NewEdge(EdgeConfig{NodeID: "edge-1", Regional: client, Store: modeldist.NewModelStore(0), Cache: cache, PollInterval: time.Millisecond})
+		began := time.Now()
+		base, err := edge.base(ctx, round, protocol.TrainingTask{})
+		if err != nil {
+			t.Fatalf("base round %d: %v", round, err)
+		}
+		if len(base) != 50<<20/8 {
+			t.Fatalf("base round %d has %d weights", round, len(base))
+		}
+		return time.Since(began)
+	}
+
+	cold := start(1)
+	if downloads := fetched.Load(); downloads != 1 {
+		t.Fatalf("cold start downloaded %d models, want 1", downloads)
+	}
+	warm := start(1)
+	if fetched.Load() != 1 {
+		t.Fatalf("warm start downloaded the cached model again")
+	}
+	t.Logf("startup with a 50MB model: %v cold, %v from the cache", cold, warm)
+	if warm >= cold {
+		t.Fatalf("cached startup took %v, no faster than downloading (%v)", warm, cold)
+	}
+
+	// A newer round makes the cached copy stale.
+	commit(2, 2)
+	start(2)
+	if downloads := fetched.Load(); downloads != 2 {
+		t.Fatalf("stale cache: %d downloads, want 2", downloads)
+	}
+
+	// A damaged copy is discarded and downloaded again, then cached anew.
+	paths, _ := filepath.Glob(filepath.Join(dir, "round-0000000002-*"))
+	if len(paths) != 1 {
+		t.Fatalf("round 2 cached as %v", paths)
+	}
+	if err := os.WriteFile(paths[0], []byte("damaged"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	start(2)
+	if downloads := fetched.Load(); downloads != 3 {
+		t.Fatalf("corrupt cache: %d downloads, want 3", downloads)
+	}
+	start(2)
+	if fetched.Load() != 3 {
+		t.Fatalf("recached model downloaded again")
+	}
+}
+
+// pooledServer is a TLS aggregator accepting update submissions, recording
+// each connection it accepts and whether the connection resumed a TLS
+// session.
+func pooledServer(t *testing.T, idleTimeout time.Duration) (*httptest.Server, func() []bool) {
+	t.Helper()
+	var mu sync.Mutex
+	var resumed []bool
+	seen := make(map[net.Conn]bool)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/default/updates" {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSONBody(w, map[string]string{"status": "queued"})
+	}))
+	server.Config.IdleTimeout = idleTimeout
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state != http.StateActive {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if tlsConn, ok := conn.(*tls.Conn); ok && !seen[conn] {
+			seen[conn] = true
+			resumed = append(resumed, tlsConn.ConnectionState().DidResume)
+		}
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server, func() []bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]bool(nil), resumed...)
+	}
+}
+
+func writeJSONBody(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// pooledClient is a client of server over a new Transport.
+func pooledClient(t *testing.T, server *httptest.Server) (*Client, *Transport) {
+	t.Helper()
+	trusted := server.Client().Transport.(*http.Transport).TLSClientConfig
+	transport, err := NewTransport("test", DefaultTransportConfig(), trusted, nil)
+	if err != nil {
+		t.Fatalf("new transport: %v", err)
+	}
+	client := NewClient(server.URL, "")
+	client.HTTPClient = transport.Client(0)
+	return client, transport
+}
+
+func TestTransportReusesConnectionsAcrossSubmissions(t *testing.T) {
+	server, accepted := pooledServer(t, 0)
+	client, transport := pooledClient(t, server)
+
+	for round := 1; round <= 5; round++ {
+		if err := client.SubmitUpdate(context.Background(), "", &protocol.ModelUpdate{NodeID: "edge-1", Round: round}); err != nil {
+			t.Fatalf("submit round %d: %v", round, err)
+		}
+	}
+	if stats := transport.Stats(); stats.New != 1 || stats.Reused != 4 {
+		t.Fatalf("stats = %+v, want one connection reused four times", stats)
+	}
+	if got := accepted(); len(got) != 1 {
+		t.Fatalf("server accepted %d connections for five sequential submissions", len(got))
+	}
+
+	// A refused submission's error body is read off, so its connection
+	// goes back to the pool too.
+	client.BaseURL += "/missing"
+	if err := client.SubmitUpdate(context.Background(), "", &protocol.ModelUpdate{NodeID: "edge-1", Round: 6}); !errors.Is(err, ErrNotReady) {
+		t.Fatalf("submit to a missing path: expected ErrNotReady, got %v", err)
+	}
+	client.BaseURL = server.URL
+	if err := client.SubmitUpdate(context.Background(), "", &protocol.ModelUpdate{NodeID: "edge-1", Round: 6}); err != nil {
+		t.Fatalf("submit after an error: %v", err)
+	}
+	if stats := transport.Stats(); stats.New != 1 || stats.Reused != 6 {
+		t.Fatalf("stats after an error response = %+v", stats)
+	}
+}
+
+func TestTransportRedialsAfterTheServerClosesIdleConnections(t *testing.T) {
+	server, accepted := pooledServer(t, 50*time.Millisecond)
+	client, transport := pooledClient(t, server)
+
+	if err := client.SubmitUpdate(context.Background(), "", &protocol.ModelUpdate{NodeID: "edge-1", Round: 1}); err != nil {
+		t.Fatalf("first submit: %v", err)
+	}
+	// The server drops the idle connection; the next submission must not
+	// fail on it but dial again, resuming the TLS session.
+	time.Sleep(300 * time.Millisecond)
+	if err := client.SubmitUpdate(context.Background(), "", &protocol.ModelUpdate{NodeID: "edge-1", Round: 2}); err != nil {
+		t.Fatalf("submit after the server closed the idle connection: %v", err)
+	}
+	if stats := transport.Stats(); stats.New != 2 || stats.Reused != 0 {
+		t.Fatalf("stats = %+v, want two dials", stats)
+	}
+	if got := accepted(); len(got) != 2 || got[0] || !got[1] {
+		t.Fatalf("resumed = %v, want the second connection to resume the first one's session", got)
+	}
+}
+
+func TestTransportConfigValidate(t *testing.T) {
+	if err := DefaultTransportConfig().Validate(); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+	for name, mutate := range map[string]func(*TransportConfig){
+		"no connections":     func(c *TransportConfig) { c.MaxConnsPerHost = 0 },
+		"more idle than max": func(c *TransportConfig) { c.MaxIdleConnsPerHost = c.MaxConnsPerHost + 1 },
+		"no keep-alive":      func(c *TransportConfig) { c.KeepAlive = 0 },
+		"no dial timeout":    func(c *TransportConfig) { c.DialTimeout = 0 },
+	} {
+		cfg := DefaultTransportConfig()
+		mutate(&cfg)
+		if _, err := NewTransport("test", cfg, nil, nil); !errors.Is(err, ErrInvalidTransportConfig) {
+			t.Fatalf("%s: expected ErrInvalidTransportConfig, got %v", name, err)
+		}
+	}
+}
+
+func TestEdgeHeartbeatIsStamped(t *testing.T) {
+	received := make(chan protocol.StatusUpdate, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/"+protocol.DefaultFederation+"/heartbeats" {
+			http.NotFound(w, r)
+			return
+		}
+		var update protocol.StatusUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			t.Errorf("decode status: %v", err)
+		}
+		received <- update
+	}))
+	defer server.Close()
+
+	edge := NewEdge(EdgeConfig{
+		NodeID:   "edge-1",
+		Regional: NewClient(server.URL, ""),
+		Stamp: func(update *protocol.StatusUpdate) {
+			update.ShutdownSnapshotID = "snapshot-1"
+		},
+	})
+	edge.heartbeat(context.Background(), protocol.StatusUpdate{Status: "training", Round: 3})
+	update := <-received
+	if update.NodeID != "edge-1" || update.Round != 3 || update.FederationID != protocol.DefaultFederation || update.ShutdownSnapshotID != "snapshot-1" {
+		t.Fatalf("heartbeat = %+v", update)
+	}
+}
+
+func TestRegionalCountsDeclaredSkipsApart(t *testing.T) {
+	token := configureNodeAuth(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	node := startAggregator(t, "regional")
+	newEdge := func(nodeID string, uploads *upload.Policy) *EdgeNode {
+		edge := NewEdge(EdgeConfig{
+			NodeID:       nodeID,
+			Regional:     NewClient(node.server.URL, token),
+			Trainer:      QuadraticTrainer([]float64{1, 2}, 0.5, 10),
+			Store:        modeldist.NewModelStore(0),
+			Dim:          2,
+			PollInterval: 5 * time.Millisecond,
+			Upload:       uploads,
+		})
+		if _, err := edge.Join(ctx); err != nil {
+			t.Fatalf("join %s: %v", nodeID, err)
+		}
+		return edge
+	}
+	metered, err := upload.NewPolicy(upload.Constraints{UnmeteredOnly: true})
+	if err != nil {
+		t.Fatalf("new upload policy: %v", err)
+	}
+	metered.SetNetworkProbe(func() bool { return true })
+	skipping := newEdge("edge-skip", metered)
+	uploading := newEdge("edge-full", nil)
+	newEdge("edge-silent", nil)
+
+	// The skipping edge declares its tier and then only waits for the
+	// round's model, which this regional never receives from upstream.
+	waiting, stop := context.WithTimeout(ctx, 200*time.Millisecond)
+	_, _ = skipping.Round(waiting, 1)
+	stop()
+	if pending := node.federation.Pending(1); pending != 0 {
+		t.Fatalf("skipping edge queued %d updates", pending)
+	}
+	voting, stopVoting := context.WithCancel(ctx)
+	defer stopVoting()
+	go func() { _, _ = uploading.Round(voting, 1) }()
+
+	tier := TierConfig{NodeID: node.id, Federation: node.federation, Expected: 1, CollectWindow: 5 * time.Second, VoteWindow: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond}
+	// Two of four members cannot commit the round, but it closed.
+	var short *consensus.ErrQuorumNotReached
+	if _, _, err := tierRound(ctx, tier, 1); !errors.As(err, &short) {
+		t.Fatalf("tier round: %v", err)
+	}
+	ledger := node.federation.Participation
+	for nodeID, want := range map[string]scheduler.Participation{
+		"edge-skip":   {Skipped: 1},
+		"edge-full":   {Full: 1},
+		"edge-silent": {Faults: 1},
+	} {
+		if got := ledger.Participation(nodeID); got != want {
+			t.Fatalf("%s participation = %+v, want %+v", nodeID, got, want)
+		}
+	}
+}
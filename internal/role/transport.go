@@ -0,0 +1,197 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package role
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+)
+
+var httpClientConnectionsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "mohawk_http_client_connections_total",
+		Help: "Total connections requests to other tiers were sent on, by client and whether the connection was reused from the pool.",
+	},
+	[]string{"client", "reused"},
+)
+
+func init() {
+	prometheus.MustRegister(httpClientConnectionsTotal)
+}
+
+// TransportConfig tunes the connections a node keeps to the aggregator
+// one tier up, so each round's submission goes over a warm TLS connection
+// instead of paying a new handshake on a high-RTT link.
+type TransportConfig struct {
+	// MaxConnsPerHost caps the connections to one aggregator, in use or
+	// idle; MaxIdleConnsPerHost caps the idle ones kept for reuse.
+	MaxConnsPerHost     int `json:"max_conns_per_host"`
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host"`
+	// IdleTimeout closes a connection left idle for longer.
+	IdleTimeout time.Duration `json:"idle_timeout"`
+	// KeepAlive is the TCP keep-alive probe interval. Aligned with the
+	// heartbeat interval, the probes keep NAT mappings of idle connections
+	// from expiring between rounds.
+	KeepAlive time.Duration `json:"keep_alive"`
+	// DialTimeout, TLSHandshakeTimeout, and ResponseHeaderTimeout bound
+	// the TCP connect, the TLS handshake, and the wait for response
+	// headers once the request is sent.
+	DialTimeout           time.Duration `json:"dial_timeout"`
+	TLSHandshakeTimeout   time.Duration `json:"tls_handshake_timeout"`
+	ResponseHeaderTimeout time.Duration `json:"response_header_timeout"`
+	// RequestTimeout bounds a whole request, body included, for clients
+	// that set no timeout of their own.
+	RequestTimeout time.Duration `json:"request_timeout"`
+}
+
+// DefaultTransportConfig keeps up to four connections per aggregator, all
+// of which may idle for 90s, probes idle connections every 15s, and
+// allows 10s to connect, 10s to handshake, 30s for response headers, and
+// 30s per request.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxConnsPerHost:       4,
+		MaxIdleConnsPerHost:   4,
+		IdleTimeout:           90 * time.Second,
+		KeepAlive:             15 * time.Second,
+		DialTimeout:           10 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		RequestTimeout:        30 * time.Second,
+	}
+}
+
+// Validate checks the pool limits and timeouts.
+func (c TransportConfig) Validate() error {
+	if c.MaxConnsPerHost < 1 || c.MaxIdleConnsPerHost < 1 || c.MaxIdleConnsPerHost > c.MaxConnsPerHost {
+		return fmt.Errorf("%w: need 1 <= idle connections <= connections per host, got %d and %d", ErrInvalidTransportConfig, c.MaxIdleConnsPerHost, c.MaxConnsPerHost)
+	}
+	for name, timeout := range map[string]time.Duration{
+		"idle timeout":            c.IdleTimeout,
+		"keep-alive":              c.KeepAlive,
+		"dial timeout":            c.DialTimeout,
+		"TLS handshake timeout":   c.TLSHandshakeTimeout,
+		"response header timeout": c.ResponseHeaderTimeout,
+		"request timeout":         c.RequestTimeout,
+	} {
+		if timeout <= 0 {
+			return fmt.Errorf("%w: %s must be positive, got %s", ErrInvalidTransportConfig, name, timeout)
+		}
+	}
+	return nil
+}
+
+// ConnectionStats counts the connections a Transport's requests went out
+// on.
+type ConnectionStats struct {
+	// New counts requests that dialed a connection, Reused those that took
+	// one from the pool.
+	New    uint64 `json:"new"`
+	Reused uint64 `json:"reused"`
+}
+
+// Transport is a pooled HTTP transport shared by every client a node uses
+// to call one tier, counting how often its requests reuse a connection.
+type Transport struct {
+	name   string
+	cfg    TransportConfig
+	pooled *http.Transport
+
+	created atomic.Uint64
+	reused  atomic.Uint64
+}
+
+// NewTransport creates a transport tuned by cfg, labelled name in
+// mohawk_http_client_connections_total. Its TLS connections are configured
+// by tlsConfig, such as a crypto.SecureChannel's, and pinned by pins
+// unless it is nil. TLS sessions are resumed through tlsConfig's session
+// cache, or a new one if it has none.
+func NewTransport(name string, cfg TransportConfig, tlsConfig *tls.Config, pins *crypto.PinStore) (*Transport, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	if tlsConfig.ClientSessionCache == nil {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(64)
+	}
+	dialer := &net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: cfg.KeepAlive}
+	pooled := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConnsPerHost * 4,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	if pins != nil {
+		pins.PinTransport(pooled, dialer, tlsConfig)
+	}
+	return &Transport{name: name, cfg: cfg, pooled: pooled}, nil
+}
+
+// Client returns a client over the shared pool, with timeout bounding each
+// request, or the configured RequestTimeout if timeout is zero.
+func (t *Transport) Client(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = t.cfg.RequestTimeout
+	}
+	return &http.Client{Transport: t, Timeout: timeout}
+}
+
+// RoundTrip sends req over a pooled connection, counting whether it was
+// reused.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{GotConn: func(info httptrace.GotConnInfo) {
+		if info.Reused {
+			t.reused.Add(1)
+			httpClientConnectionsTotal.WithLabelValues(t.name, "true").Inc()
+		} else {
+			t.created.Add(1)
+			httpClientConnectionsTotal.WithLabelValues(t.name, "false").Inc()
+		}
+	}}
+	return t.pooled.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
+
+// CloseIdleConnections closes the pool's idle connections.
+func (t *Transport) CloseIdleConnections() {
+	t.pooled.CloseIdleConnections()
+}
+
+// Stats returns how many requests dialed and how many reused a
+// connection.
+func (t *Transport) Stats() ConnectionStats {
+	return ConnectionStats{New: t.created.Load(), Reused: t.reused.Load()}
+}
+
+// maxDrainBytes is how much of an unread response body is read off before
+// closing it so its connection can return to the pool; larger remainders
+// cost less to abandon with the connection.
+const maxDrainBytes = 64 << 10
+
+// drainAndClose closes body after reading off what is left of it, up to
+// maxDrainBytes, so the connection it came on is reused.
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, maxDrainBytes))
+	_ = body.Close()
+}
@@ -0,0 +1,372 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package rollback proposes automatic model rollbacks when loss diverges
+// shortly after a commit that Byzantine detection flagged as suspicious.
+// Rollbacks go through consensus like any other model and are committed via
+// ModelStore.Rollback only once a quorum approves them.
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// maxEvents bounds the retained action log.
+const maxEvents = 256
+
+// ErrNoPendingRollback means there is no rollback proposal to finalize.
+var ErrNoPendingRollback = errors.New("no pending rollback")
+
+// Policy decides when a commit is treated as poisoned.
+type Policy struct {
+	// LossIncrease is the relative loss rise over the pre-commit loss that
+	// counts as divergence, e.g. 0.2 for 20%.
+	LossIncrease float64 `json:"loss_increase"`
+	// Window is how many rounds after a commit divergence is attributed
+	// to it.
+	Window int `json:"window"`
+	// FlaggedFraction is the share of the commit's participants that
+	// Byzantine detection must have flagged.
+	FlaggedFraction float64 `json:"flagged_fraction"`
+}
+
+// DefaultPolicy returns a 20% loss rise within 3 rounds with a quarter of
+// participants flagged.
+func DefaultPolicy() Policy {
+	return Policy{LossIncrease: 0.2, Window: 3, FlaggedFraction: 0.25}
+}
+
+// Validate checks that the policy can trigger.
+func (p Policy) Validate() error {
+	if p.LossIncrease <= 0 {
+		return fmt.Errorf("loss increase must be positive, got %f", p.LossIncrease)
+	}
+	if p.Window <= 0 {
+		return fmt.Errorf("window must be positive, got %d", p.Window)
+	}
+	if p.FlaggedFraction < 0 || p.FlaggedFraction > 1 {
+		return fmt.Errorf("flagged fraction must be in [0, 1], got %f", p.FlaggedFraction)
+	}
+	return nil
+}
+
+// Detector reports which participants of a committed round were flagged as
+// Byzantine, and how many participated in total.
+type Detector interface {
+	Flagged(round int) (flagged []string, participants int)
+}
+
+// ManifestDetector treats participants excluded as norm outliers or
+// rejected by Multi-Krum in a round's contribution manifest as flagged.
+type ManifestDetector struct {
+	Store *modeldist.ModelStore
+}
+
+// Flagged implements Detector.
+func (d ManifestDetector) Flagged(round int) ([]string, int) {
+	manifest, ok := d.Store.Manifest(round)
+	if !ok {
+		return nil, 0
+	}
+	var flagged []string
+	for _, entry := range manifest.Entries {
+		if entry.Reason == protocol.ReasonNormOutlier || entry.Reason == protocol.ReasonKrumRejected {
+			flagged = append(flagged, entry.NodeID)
+		}
+	}
+	sort.Strings(flagged)
+	return flagged, len(manifest.Entries)
+}
+
+// Evidence is what triggered an automatic rollback.
+type Evidence struct {
+	SuspectRound    int      `json:"suspect_round"`
+	ObservedRound   int      `json:"observed_round"`
+	BaselineLoss    float64  `json:"baseline_loss"`
+	ObservedLoss    float64  `json:"observed_loss"`
+	LossIncrease    float64  `json:"loss_increase"`
+	Flagged         []string `json:"flagged"`
+	Participants    int      `json:"participants"`
+	FlaggedFraction float64  `json:"flagged_fraction"`
+}
+
+// Actions recorded in the event log.
+const (
+	ActionProposed  = "proposed"
+	ActionCommitted = "committed"
+	ActionRejected  = "rejected"
+)
+
+// Event is one automatic action with the evidence behind it.
+type Event struct {
+	Time          time.Time `json:"time"`
+	Action        string    `json:"action"`
+	TargetRound   int       `json:"target_round"`
+	RollbackRound int       `json:"rollback_round,omitempty"`
+	ProposalID    string    `json:"proposal_id,omitempty"`
+	Evidence      Evidence  `json:"evidence"`
+	Error         string    `json:"error,omitempty"`
+}
+
+type pendingRollback struct {
+	proposalID string
+	target     int
+	round      int
+	evidence   Evidence
+}
+
+// AutoRollback watches per-round loss and proposes rollbacks to the last
+// healthy round when a recent commit both diverged and was flagged.
+type AutoRollback struct {
+	mu          sync.Mutex
+	nodeID      string
+	policy      Policy
+	coordinator *consensus.Coordinator
+	store       *modeldist.ModelStore
+	detector    Detector
+	losses      map[int]float64
+	handled     map[int]bool
+	pending     *pendingRollback
+	events      []Event
+	lifecycle   events.Publisher
+}
+
+// NewAutoRollback creates a policy engine that proposes through coordinator
+// and commits into store. A nil detector defaults to ManifestDetector.
+func NewAutoRollback(nodeID string, policy Policy, coordinator *consensus.Coordinator, store *modeldist.ModelStore, detector Detector) (*AutoRollback, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if detector == nil {
+		detector = ManifestDetector{Store: store}
+	}
+	return &AutoRollback{
+		nodeID:      nodeID,
+		policy:      policy,
+		coordinator: coordinator,
+		store:       store,
+		detector:    detector,
+		losses:      make(map[int]float64),
+		handled:     make(map[int]bool),
+	}, nil
+}
+
+// ObserveRound records the loss measured after round committed and, when
+// the policy triggers, proposes a rollback. The coordinator must be ready
+// for a new proposal, i.e. Reset after the round's commit. It returns the
+// proposal ID, or "" when no rollback was proposed.
+func (a *AutoRollback) ObserveRound(ctx context.Context, round int, loss float64) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.losses[round] = loss
+	for r := range a.losses {
+		if r < round-a.policy.Window-1 {
+			delete(a.losses, r)
+		}
+	}
+	for r := range a.handled {
+		if r < round-a.policy.Window {
+			delete(a.handled, r)
+		}
+	}
+	if a.pending != nil {
+		return "", nil
+	}
+
+	evidence, target, ok := a.evaluateLocked(round, loss)
+	if !ok {
+		return "", nil
+	}
+	a.handled[evidence.SuspectRound] = true
+
+	weights, _, found := a.store.Model(target)
+	if !found {
+		a.recordLocked(Event{Action: ActionRejected, TargetRound: target, Evidence: evidence, Error: "target weights not retained"})
+		return "", fmt.Errorf("rollback target round %d has no retained weights", target)
+	}
+	rollbackRound := a.store.LatestRound() + 1
+	proposalID, err := a.coordinator.ProposeModel(ctx, &consensus.ModelProposal{
+		Round:      rollbackRound,
+		Weights:    weights,
+		ProposerID: a.nodeID,
+		Timestamp:  time.Now(),
+	})
+	if err != nil {
+		a.recordLocked(Event{Action: ActionRejected, TargetRound: target, RollbackRound: rollbackRound, Evidence: evidence, Error: err.Error()})
+		return "", err
+	}
+	a.pending = &pendingRollback{proposalID: proposalID, target: target, round: rollbackRound, evidence: evidence}
+	a.recordLocked(Event{Action: ActionProposed, TargetRound: target, RollbackRound: rollbackRound, ProposalID: proposalID, Evidence: evidence})
+	return proposalID, nil
+}
+
+// evaluateLocked finds the earliest commit within the window whose loss has
+// since risen past the policy and whose participants were flagged. The
+// rollback target is the round before that commit.
+func (a *AutoRollback) evaluateLocked(round int, loss float64) (Evidence, int, bool) {
+	for suspect := round - a.policy.Window + 1; suspect <= round; suspect++ {
+		baseline, ok := a.losses[suspect-1]
+		if !ok || baseline <= 0 || a.handled[suspect] {
+			continue
+		}
+		increase := (loss - baseline) / baseline
+		if increase <= a.policy.LossIncrease {
+			continue
+		}
+		flagged, participants := a.detector.Flagged(suspect)
+		if participants == 0 {
+			continue
+		}
+		fraction := float64(len(flagged)) / float64(participants)
+		if fraction < a.policy.FlaggedFraction {
+			continue
+		}
+		return Evidence{
+			SuspectRound:    suspect,
+			ObservedRound:   round,
+			BaselineLoss:    baseline,
+			ObservedLoss:    loss,
+			LossIncrease:    increase,
+			Flagged:         flagged,
+			Participants:    participants,
+			FlaggedFraction: fraction,
+		}, suspect - 1, true
+	}
+	return Evidence{}, 0, false
+}
+
+// SetEvents publishes rollback_executed to pub when a rollback commits
+// into the store. nil stops publishing.
+func (a *AutoRollback) SetEvents(pub events.Publisher) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lifecycle = pub
+}
+
+// Pending returns the proposal ID of the rollback awaiting votes, if any.
+func (a *AutoRollback) Pending() (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending == nil {
+		return "", false
+	}
+	return a.pending.proposalID, true
+}
+
+// Finalize commits the pending rollback once its proposal reached quorum.
+// If the quorum was not reached the rollback is dropped and recorded as
+// rejected; the coordinator error is returned.
+func (a *AutoRollback) Finalize(ctx context.Context) (modeldist.RoundSummary, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	pending := a.pending
+	if pending == nil {
+		return modeldist.RoundSummary{}, ErrNoPendingRollback
+	}
+	a.pending = nil
+
+	event := Event{Action: ActionRejected, TargetRound: pending.target, RollbackRound: pending.round, ProposalID: pending.proposalID, Evidence: pending.evidence}
+	if err := a.coordinator.CommitModel(ctx, pending.proposalID); err != nil {
+		event.Error = err.Error()
+		a.recordLocked(event)
+		return modeldist.RoundSummary{}, err
+	}
+
+	cert, participants, err := a.certificate(pending)
+	if err == nil {
+		var summary modeldist.RoundSummary
+		summary, err = a.store.Rollback(pending.target, participants, cert)
+		if err == nil {
+			event.Action = ActionCommitted
+			a.recordLocked(event)
+			if a.lifecycle != nil {
+				a.lifecycle.Publish(events.Event{
+					Kind:        events.KindRollbackExecuted,
+					Round:       pending.round,
+					Source:      "rollback",
+					ProposalID:  pending.proposalID,
+					NodeID:      a.nodeID,
+					TargetRound: pending.target,
+				})
+			}
+			return summary, nil
+		}
+	}
+	event.Error = err.Error()
+	a.recordLocked(event)
+	return modeldist.RoundSummary{}, err
+}
+
+// certificate builds the commit certificate from the approvals the
+// coordinator collected for the rollback proposal, with the signatures
+// they carry.
+func (a *AutoRollback) certificate(pending *pendingRollback) (modeldist.CommitCertificate, int, error) {
+	round, err := a.coordinator.GetConsensusRound(pending.proposalID)
+	if err != nil {
+		return modeldist.CommitCertificate{}, 0, err
+	}
+	membership, err := a.coordinator.GetRoundMembership(pending.proposalID)
+	if err != nil {
+		return modeldist.CommitCertificate{}, 0, err
+	}
+	approvals := make([]string, 0, len(round.ValidatorVotes))
+	var signatures []modeldist.ApprovalSignature
+	for _, vote := range round.ValidatorVotes {
+		if vote != nil && vote.Approve {
+			approvals = append(approvals, vote.NodeID)
+			if len(vote.CommitSignature) > 0 {
+				signatures = append(signatures, modeldist.ApprovalSignature{NodeID: vote.NodeID, Signature: vote.CommitSignature})
+			}
+		}
+	}
+	return modeldist.CommitCertificate{
+		Round:       pending.round,
+		ProposalID:  pending.proposalID,
+		ModelDigest: protocol.WeightsDigest(round.ModelWeights),
+		QuorumSize:  membership.QuorumSize,
+		Approvals:   approvals,
+		Signatures:  signatures,
+	}, len(round.ValidatorVotes), nil
+}
+
+// Events returns the recorded automatic actions, oldest first.
+func (a *AutoRollback) Events() []Event {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Event(nil), a.events...)
+}
+
+func (a *AutoRollback) recordLocked(event Event) {
+	event.Time = time.Now().UTC()
+	a.events = append(a.events, event)
+	if len(a.events) > maxEvents {
+		a.events = append(a.events[:0], a.events[len(a.events)-maxEvents:]...)
+	}
+	log.Printf(
+		"auto-rollback %s: target=%d rollback_round=%d proposal=%s suspect=%d loss %.4f->%.4f (+%.1f%%) flagged=%d/%d %s",
+		event.Action,
+		event.TargetRound,
+		event.RollbackRound,
+		event.ProposalID,
+		event.Evidence.SuspectRound,
+		event.Evidence.BaselineLoss,
+		event.Evidence.ObservedLoss,
+		event.Evidence.LossIncrease*100,
+		len(event.Evidence.Flagged),
+		event.Evidence.Participants,
+		event.Error,
+	)
+}
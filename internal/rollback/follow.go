@@ -0,0 +1,87 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package rollback
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+)
+
+// LossFunc returns the loss measured for round of the federation, and
+// whether any was.
+type LossFunc func(federationID string, round int) (float64, bool)
+
+// Follow feeds auto from the round lifecycle on bus. Each round
+// federationID commits is observed, with the loss reports for it, once
+// auto's store holds the round; a round_started of the federation retries
+// rounds the store did not hold yet. A rollback proposal is finalized when
+// it reaches quorum, or dropped when the federation commits another round
+// without it doing so, and the coordinator is reset either way.
+func Follow(bus *events.Bus, auto *AutoRollback, federationID string, loss LossFunc) *events.Subscription {
+	f := &follower{auto: auto, federationID: federationID, loss: loss}
+	return bus.Handle("auto-rollback", events.DefaultBuffer, f.handle, events.KindCommitted, events.KindRoundStarted, events.KindQuorumReached)
+}
+
+// follower is Follow's state. Its subscription calls handle from one
+// goroutine, so it needs no lock.
+type follower struct {
+	auto         *AutoRollback
+	federationID string
+	loss         LossFunc
+	// waiting holds committed rounds not yet observed.
+	waiting map[int]bool
+}
+
+func (f *follower) handle(event events.Event) {
+	ctx := context.Background()
+	if event.Kind == events.KindQuorumReached {
+		if pending, ok := f.auto.Pending(); ok && event.ProposalID == pending {
+			f.finalize(ctx)
+		}
+		return
+	}
+	if event.FederationID != f.federationID {
+		return
+	}
+	if event.Kind == events.KindCommitted {
+		if _, ok := f.auto.Pending(); ok {
+			f.finalize(ctx)
+		}
+		if f.waiting == nil {
+			f.waiting = make(map[int]bool)
+		}
+		f.waiting[event.Round] = true
+	}
+	f.observe(ctx)
+}
+
+// observe observes, oldest first, the waiting rounds the store holds.
+func (f *follower) observe(ctx context.Context) {
+	latest := f.auto.store.LatestRound()
+	rounds := make([]int, 0, len(f.waiting))
+	for round := range f.waiting {
+		if round <= latest {
+			rounds = append(rounds, round)
+		}
+	}
+	sort.Ints(rounds)
+	for _, round := range rounds {
+		delete(f.waiting, round)
+		loss, ok := f.loss(f.federationID, round)
+		if !ok {
+			continue
+		}
+		// A failed proposal is recorded in the action log.
+		_, _ = f.auto.ObserveRound(ctx, round, loss)
+	}
+}
+
+// finalize commits or drops the pending rollback, as recorded in the
+// action log, and readies the coordinator for the next proposal.
+func (f *follower) finalize(ctx context.Context) {
+	_, _ = f.auto.Finalize(ctx)
+	f.auto.coordinator.Reset()
+}
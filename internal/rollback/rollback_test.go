@@ -0,0 +1,219 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+const testNodes = 4
+
+type fixture struct {
+	coordinator *consensus.Coordinator
+	store       *modeldist.ModelStore
+	auto        *AutoRollback
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{
+		coordinator: consensus.NewCoordinator("node-0", testNodes, time.Minute),
+		store:       modeldist.NewModelStore(16),
+	}
+	auto, err := NewAutoRollback("node-0", DefaultPolicy(), f.coordinator, f.store, nil)
+	if err != nil {
+		t.Fatalf("new auto rollback: %v", err)
+	}
+	f.auto = auto
+	return f
+}
+
+// commit stores round with flagged of testNodes participants excluded as
+// norm outliers.
+func (f *fixture) commit(t *testing.T, round int, weights string, flagged int) {
+	t.Helper()
+	cert := modeldist.CommitCertificate{
+		Round:       round,
+		ProposalID:  fmt.Sprintf("proposal-%d", round),
+		ModelDigest: protocol.WeightsDigest([]byte(weights)),
+		QuorumSize:  3,
+		Approvals:   []string{"node-0", "member-1", "member-2"},
+	}
+	if _, err := f.store.Commit(round, []byte(weights), testNodes, nil, cert); err != nil {
+		t.Fatalf("commit round %d: %v", round, err)
+	}
+	manifest := &protocol.ContributionManifest{Round: round}
+	for i := 0; i < testNodes; i++ {
+		entry := protocol.ContributionEntry{NodeID: fmt.Sprintf("peer-%d", i), Included: true, Reason: protocol.ReasonIncluded}
+		if i < flagged {
+			entry.Included = false
+			entry.Reason = protocol.ReasonNormOutlier
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+	if err := f.store.AttachManifest(manifest); err != nil {
+		t.Fatalf("attach manifest %d: %v", round, err)
+	}
+}
+
+func (f *fixture) vote(t *testing.T, proposalID string, voters ...string) {
+	t.Helper()
+	for _, nodeID := range voters {
+		if err := f.coordinator.CastVote(context.Background(), &consensus.Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote %s: %v", nodeID, err)
+		}
+	}
+}
+
+func TestAutoRollbackProposesAndCommitsAfterFlaggedDivergence(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	bus := events.NewBus()
+	executed := bus.Subscribe("test", 4, events.KindRollbackExecuted)
+	f.auto.SetEvents(bus)
+
+	f.commit(t, 1, "healthy", 0)
+	if id, err := f.auto.ObserveRound(ctx, 1, 0.5); err != nil || id != "" {
+		t.Fatalf("expected no rollback for a healthy round, got %q, %v", id, err)
+	}
+	f.commit(t, 2, "poisoned", 2)
+	proposalID, err := f.auto.ObserveRound(ctx, 2, 0.9)
+	if err != nil {
+		t.Fatalf("observe poisoned round: %v", err)
+	}
+	if proposalID == "" {
+		t.Fatal("expected a rollback proposal")
+	}
+	if pending, ok := f.auto.Pending(); !ok || pending != proposalID {
+		t.Fatalf("expected %s to be pending, got %q", proposalID, pending)
+	}
+
+	f.vote(t, proposalID, "node-0", "member-1", "member-2")
+	summary, err := f.auto.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+	if summary.Round != 3 || summary.RollbackOf != 1 {
+		t.Fatalf("expected round 3 to roll back to round 1, got %+v", summary)
+	}
+	weights, _, _ := f.store.Model(3)
+	if string(weights) != "healthy" {
+		t.Fatalf("expected healthy weights after rollback, got %q", weights)
+	}
+	executed.Close()
+	if event := <-executed.Events(); event.Round != 3 || event.TargetRound != 1 || event.ProposalID != proposalID || event.NodeID != "node-0" {
+		t.Fatalf("unexpected rollback event %+v", event)
+	}
+
+	events := f.auto.Events()
+	if len(events) != 2 || events[0].Action != ActionProposed || events[1].Action != ActionCommitted {
+		t.Fatalf("expected proposed then committed events, got %+v", events)
+	}
+	evidence := events[1].Evidence
+	if evidence.SuspectRound != 2 || len(evidence.Flagged) != 2 || evidence.Participants != testNodes {
+		t.Fatalf("unexpected evidence %+v", evidence)
+	}
+}
+
+func TestAutoRollbackIgnoresUnflaggedDivergence(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	f.commit(t, 1, "healthy", 0)
+	if _, err := f.auto.ObserveRound(ctx, 1, 0.5); err != nil {
+		t.Fatalf("observe: %v", err)
+	}
+	f.commit(t, 2, "noisy", 0)
+	if id, err := f.auto.ObserveRound(ctx, 2, 0.9); err != nil || id != "" {
+		t.Fatalf("expected no rollback without flagged participants, got %q, %v", id, err)
+	}
+	if events := f.auto.Events(); len(events) != 0 {
+		t.Fatalf("expected no events, got %+v", events)
+	}
+}
+
+func TestAutoRollbackWithoutQuorumIsRejected(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	f.commit(t, 1, "healthy", 0)
+	if _, err := f.auto.ObserveRound(ctx, 1, 0.5); err != nil {
+		t.Fatalf("observe: %v", err)
+	}
+	f.commit(t, 2, "poisoned", 2)
+	proposalID, err := f.auto.ObserveRound(ctx, 2, 0.9)
+	if err != nil || proposalID == "" {
+		t.Fatalf("expected a rollback proposal, got %q, %v", proposalID, err)
+	}
+
+	f.vote(t, proposalID, "node-0")
+	if _, err := f.auto.Finalize(ctx); err == nil {
+		t.Fatal("expected finalize to fail without quorum")
+	}
+	if f.store.LatestRound() != 2 {
+		t.Fatalf("expected no rollback commit, latest round is %d", f.store.LatestRound())
+	}
+	events := f.auto.Events()
+	if last := events[len(events)-1]; last.Action != ActionRejected || last.Error == "" {
+		t.Fatalf("expected a rejected event with an error, got %+v", last)
+	}
+	if _, err := f.auto.Finalize(ctx); !errors.Is(err, ErrNoPendingRollback) {
+		t.Fatalf("expected ErrNoPendingRollback, got %v", err)
+	}
+}
+
+func TestFollowRollsBackFromCommittedEvents(t *testing.T) {
+	f := newFixture(t)
+	bus := events.NewBus()
+	executed := bus.Subscribe("test", 4, events.KindRollbackExecuted)
+	f.coordinator.SetEvents(bus)
+	f.auto.SetEvents(bus)
+	losses := map[int]float64{1: 0.5, 2: 0.9}
+	follow := Follow(bus, f.auto, "fed-a", func(federationID string, round int) (float64, bool) {
+		loss, ok := losses[round]
+		return loss, ok && federationID == "fed-a"
+	})
+	defer follow.Close()
+
+	f.commit(t, 1, "healthy", 0)
+	bus.Publish(events.Event{Kind: events.KindCommitted, FederationID: "fed-a", Round: 1})
+	// Round 2 commits into the store only after its event, as a tier
+	// imports the round once its coordinator has committed it.
+	bus.Publish(events.Event{Kind: events.KindCommitted, FederationID: "fed-a", Round: 2})
+	bus.Publish(events.Event{Kind: events.KindCommitted, FederationID: "fed-b", Round: 2})
+	f.commit(t, 2, "poisoned", 2)
+	bus.Publish(events.Event{Kind: events.KindRoundStarted, FederationID: "fed-a", Round: 3})
+
+	var proposalID string
+	deadline := time.Now().Add(5 * time.Second)
+	for proposalID == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a rollback proposal from the committed events")
+		}
+		time.Sleep(time.Millisecond)
+		proposalID, _ = f.auto.Pending()
+	}
+	f.vote(t, proposalID, "node-0", "member-1", "member-2")
+
+	select {
+	case event := <-executed.Events():
+		if event.Round != 3 || event.TargetRound != 1 || event.ProposalID != proposalID {
+			t.Fatalf("unexpected rollback event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the rollback to commit once its proposal reached quorum")
+	}
+	if weights, _, _ := f.store.Model(3); string(weights) != "healthy" {
+		t.Fatalf("expected healthy weights after rollback, got %q", weights)
+	}
+}
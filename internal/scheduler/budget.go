@@ -0,0 +1,239 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+// Phase is one stage of a federated learning round. Phases run in the order
+// verification, aggregation, consensus.
+type Phase string
+
+const (
+	PhaseVerification Phase = "verification"
+	PhaseAggregation  Phase = "aggregation"
+	PhaseConsensus    Phase = "consensus"
+)
+
+// phaseOrder is the order phases run in within a round.
+var phaseOrder = []Phase{PhaseVerification, PhaseAggregation, PhaseConsensus}
+
+// BudgetSplit is the share of the round deadline reserved for each phase.
+type BudgetSplit struct {
+	Verification float64 `json:"verification"`
+	Aggregation  float64 `json:"aggregation"`
+	Consensus    float64 `json:"consensus"`
+}
+
+// DefaultBudgetSplit reserves 30% of a round for verification, 40% for
+// aggregation, and 30% for consensus.
+func DefaultBudgetSplit() BudgetSplit {
+	return BudgetSplit{Verification: 0.3, Aggregation: 0.4, Consensus: 0.3}
+}
+
+// Validate checks that every share is positive and the shares sum to at
+// most the whole round.
+func (s BudgetSplit) Validate() error {
+	for _, phase := range phaseOrder {
+		if share := s.share(phase); share <= 0 {
+			return fmt.Errorf("%s share must be positive, got %f", phase, share)
+		}
+	}
+	if total := s.Verification + s.Aggregation + s.Consensus; total > 1+1e-9 {
+		return fmt.Errorf("budget shares sum to %f, must not exceed 1", total)
+	}
+	return nil
+}
+
+func (s BudgetSplit) share(phase Phase) float64 {
+	switch phase {
+	case PhaseVerification:
+		return s.Verification
+	case PhaseAggregation:
+		return s.Aggregation
+	case PhaseConsensus:
+		return s.Consensus
+	}
+	return 0
+}
+
+// PhaseReport is how one phase used its budget.
+type PhaseReport struct {
+	Phase   Phase         `json:"phase"`
+	Budget  time.Duration `json:"budget"`
+	Actual  time.Duration `json:"actual"`
+	Overran bool          `json:"overran"`
+}
+
+// RoundBudget divides one round's deadline between its phases. Each phase
+// gets its share of the time still remaining when it starts, relative to
+// the phases not yet run, so time an early phase leaves unused rolls
+// forward. A phase's context ends at its budget, but nothing stops work
+// that ignores it: a phase that overruns spends time the later phases
+// would have had, and they split what is left, down to nothing at the
+// round deadline. Reports records each overrun.
+type RoundBudget struct {
+	mu        sync.Mutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	round     int
+	deadline  time.Time
+	split     BudgetSplit
+	tier      *TierBudget
+	collector *monitoring.Collector
+	reports   map[Phase]PhaseReport
+}
+
+// NewRoundBudget starts the budget for round, which must resolve within
+// total of now. The returned budget's Context carries the round deadline.
+func NewRoundBudget(parent context.Context, round int, total time.Duration, split BudgetSplit) (*RoundBudget, error) {
+	if total <= 0 {
+		return nil, fmt.Errorf("round budget must be positive, got %s", total)
+	}
+	if err := split.Validate(); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(total)
+	ctx, cancel := context.WithDeadline(parent, deadline)
+	return &RoundBudget{
+		ctx:      ctx,
+		cancel:   cancel,
+		round:    round,
+		deadline: deadline,
+		split:    split,
+		reports:  make(map[Phase]PhaseReport),
+	}, nil
+}
+
+// NewTieredRoundBudget starts the budget for round with the total a
+// Topology derived for its tier, so regional rounds run to their region's
+// deadline and global rounds get the slack their links need.
+func NewTieredRoundBudget(parent context.Context, round int, tier TierBudget, split BudgetSplit) (*RoundBudget, error) {
+	b, err := NewRoundBudget(parent, round, tier.Budget, split)
+	if err != nil {
+		return nil, err
+	}
+	b.tier = &tier
+	return b, nil
+}
+
+// SetCollector records each phase's actual duration into collector.
+func (b *RoundBudget) SetCollector(collector *monitoring.Collector) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.collector = collector
+}
+
+// Context returns the round context, cancelled at the round deadline.
+func (b *RoundBudget) Context() context.Context {
+	return b.ctx
+}
+
+// Round returns the round the budget covers.
+func (b *RoundBudget) Round() int {
+	return b.round
+}
+
+// Tier returns the derived budget the round was started with, if it was
+// started by NewTieredRoundBudget.
+func (b *RoundBudget) Tier() (TierBudget, bool) {
+	if b.tier == nil {
+		return TierBudget{}, false
+	}
+	return *b.tier, true
+}
+
+// Deadline returns the round deadline.
+func (b *RoundBudget) Deadline() time.Time {
+	return b.deadline
+}
+
+// Remaining returns the time left before the round deadline.
+func (b *RoundBudget) Remaining() time.Duration {
+	return max(time.Until(b.deadline), 0)
+}
+
+// DeadlineFor returns a context bounded by phase's budget. Calling the
+// returned cancel function ends the phase and records its duration; it
+// must be called once the phase's work is done.
+func (b *RoundBudget) DeadlineFor(phase Phase) (context.Context, context.CancelFunc) {
+	start := time.Now()
+	budget := b.phaseBudget(phase, start)
+	ctx, cancel := context.WithDeadline(b.ctx, start.Add(budget))
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			cancel()
+			b.record(phase, budget, time.Since(start))
+		})
+	}
+}
+
+// phaseBudget is phase's share of the remaining time among it and the
+// phases after it.
+func (b *RoundBudget) phaseBudget(phase Phase, now time.Time) time.Duration {
+	remaining := b.deadline.Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+	share := b.split.share(phase)
+	rest := 0.0
+	for i, p := range phaseOrder {
+		if p != phase {
+			continue
+		}
+		for _, later := range phaseOrder[i:] {
+			rest += b.split.share(later)
+		}
+	}
+	if rest <= 0 {
+		return remaining
+	}
+	return time.Duration(float64(remaining) * share / rest)
+}
+
+func (b *RoundBudget) record(phase Phase, budget, actual time.Duration) {
+	report := PhaseReport{Phase: phase, Budget: budget, Actual: actual, Overran: actual > budget}
+
+	b.mu.Lock()
+	b.reports[phase] = report
+	collector := b.collector
+	b.mu.Unlock()
+
+	if collector != nil {
+		labels := map[string]string{
+			"round":          strconv.Itoa(b.round),
+			"budget_seconds": strconv.FormatFloat(budget.Seconds(), 'f', 3, 64),
+			"overran":        strconv.FormatBool(report.Overran),
+		}
+		if b.tier != nil {
+			labels["tier"] = string(b.tier.Tier)
+			labels["region"] = b.tier.Region
+		}
+		collector.RecordRoundPhase(string(phase), actual.Seconds(), labels)
+	}
+}
+
+// Reports returns the recorded phases in the order they run.
+func (b *RoundBudget) Reports() []PhaseReport {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	reports := make([]PhaseReport, 0, len(b.reports))
+	for _, phase := range phaseOrder {
+		if report, ok := b.reports[phase]; ok {
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+// Close releases the round context.
+func (b *RoundBudget) Close() {
+	b.cancel()
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+func TestBudgetSplitValidate(t *testing.T) {
+	if err := DefaultBudgetSplit().Validate(); err != nil {
+		t.Fatalf("default split: %v", err)
+	}
+	if err := (BudgetSplit{Verification: 0.5, Aggregation: 0.5, Consensus: 0.5}).Validate(); err == nil {
+		t.Fatal("expected shares over 1 to be rejected")
+	}
+	if err := (BudgetSplit{Verification: 0.5, Aggregation: 0.5}).Validate(); err == nil {
+		t.Fatal("expected a phase without budget to be rejected")
+	}
+	if _, err := NewRoundBudget(context.Background(), 1, 0, DefaultBudgetSplit()); err == nil {
+		t.Fatal("expected a zero round budget to be rejected")
+	}
+}
+
+func TestRoundBudgetRollsUnusedTimeForward(t *testing.T) {
+	budget, err := NewRoundBudget(context.Background(), 1, time.Second, DefaultBudgetSplit())
+	if err != nil {
+		t.Fatalf("new budget: %v", err)
+	}
+	defer budget.Close()
+
+	_, done := budget.DeadlineFor(PhaseVerification)
+	done()
+	ctx, done := budget.DeadlineFor(PhaseAggregation)
+	defer done()
+
+	deadline, _ := ctx.Deadline()
+	// Verification returned at once, so aggregation gets 4/7 of the round
+	// rather than 40%.
+	if got := time.Until(deadline); got < 500*time.Millisecond {
+		t.Fatalf("expected aggregation to inherit unused verification time, got %s", got)
+	}
+}
+
+func TestRoundBudgetSlowPhasesResolveByDeadline(t *testing.T) {
+	collector := monitoring.NewCollector(10)
+	total := 300 * time.Millisecond
+	budget, err := NewRoundBudget(context.Background(), 7, total, DefaultBudgetSplit())
+	if err != nil {
+		t.Fatalf("new budget: %v", err)
+	}
+	defer budget.Close()
+	budget.SetCollector(collector)
+
+	// Verification ignores its deadline and overruns it.
+	_, done := budget.DeadlineFor(PhaseVerification)
+	time.Sleep(150 * time.Millisecond)
+	done()
+
+	// Aggregation and consensus are slow but honour their deadlines.
+	for _, phase := range []Phase{PhaseAggregation, PhaseConsensus} {
+		ctx, done := budget.DeadlineFor(phase)
+		<-ctx.Done()
+		done()
+	}
+
+	if late := time.Since(budget.Deadline()); late > 20*time.Millisecond {
+		t.Fatalf("round resolved %s after its deadline", late)
+	}
+	reports := budget.Reports()
+	if len(reports) != 3 {
+		t.Fatalf("expected three phase reports, got %+v", reports)
+	}
+	if !reports[0].Overran {
+		t.Fatalf("expected verification to be reported as overrun, got %+v", reports[0])
+	}
+	if consensus := reports[2]; consensus.Budget < 40*time.Millisecond {
+		t.Fatalf("expected consensus to keep its reserve, got %s", consensus.Budget)
+	}
+	if got := len(collector.GetMetricsByType(monitoring.MetricRoundPhase)); got != 3 {
+		t.Fatalf("expected three recorded phase durations, got %d", got)
+	}
+}
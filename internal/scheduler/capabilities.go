@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// BytesPerParameter is the training memory a node needs per model
+// parameter: float64 weights, gradients, two optimizer moments, and an
+// error-feedback residual.
+const BytesPerParameter = 40
+
+// AdmissionPolicy is the minimum capability a node needs to register. Zero
+// fields impose no constraint.
+type AdmissionPolicy struct {
+	MinCPUCount int `json:"min_cpu_count"`
+	// ModelParameters sizes the model every admitted node must be able to
+	// train; it adds BytesPerParameter per parameter to MinMemoryBytes.
+	ModelParameters      int64         `json:"model_parameters"`
+	MinMemoryBytes       int64         `json:"min_memory_bytes"`
+	RequireTPM           bool          `json:"require_tpm"`
+	MaxWasmVerifyLatency time.Duration `json:"max_wasm_verify_latency"`
+}
+
+// requiredMemory is the memory needed to train a model with params
+// parameters.
+func requiredMemory(params int64) int64 {
+	return params * BytesPerParameter
+}
+
+// Check returns ErrUnderCapacity naming the first constraint manifest
+// misses.
+func (p AdmissionPolicy) Check(manifest *protocol.CapabilityManifest) error {
+	if manifest.CPUCount < p.MinCPUCount {
+		return fmt.Errorf("%w: %s has %d cpus, need %d", ErrUnderCapacity, manifest.NodeID, manifest.CPUCount, p.MinCPUCount)
+	}
+	if need := p.MinMemoryBytes + requiredMemory(p.ModelParameters); manifest.MemoryBytes < need {
+		return fmt.Errorf("%w: %s has %d bytes of memory, need %d", ErrUnderCapacity, manifest.NodeID, manifest.MemoryBytes, need)
+	}
+	if p.RequireTPM && !manifest.TPMPresent {
+		return fmt.Errorf("%w: %s has no TPM", ErrUnderCapacity, manifest.NodeID)
+	}
+	if p.MaxWasmVerifyLatency > 0 && manifest.WasmVerifyLatency() > p.MaxWasmVerifyLatency {
+		return fmt.Errorf("%w: %s verifies in %s, need %s", ErrUnderCapacity, manifest.NodeID, manifest.WasmVerifyLatency(), p.MaxWasmVerifyLatency)
+	}
+	return nil
+}
+
+// CapabilityRegistry holds the latest admitted manifest per node for
+// participant selection.
+type CapabilityRegistry struct {
+	mu        sync.RWMutex
+	policy    AdmissionPolicy
+	manifests map[string]protocol.CapabilityManifest
+}
+
+// NewCapabilityRegistry admits nodes under policy.
+func NewCapabilityRegistry(policy AdmissionPolicy) *CapabilityRegistry {
+	return &CapabilityRegistry{policy: policy, manifests: make(map[string]protocol.CapabilityManifest)}
+}
+
+// Policy returns the admission policy.
+func (r *CapabilityRegistry) Policy() AdmissionPolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.policy
+}
+
+// Admit validates manifest against the admission policy and stores it. A
+// node re-submitting a manifest that no longer meets the policy is removed,
+// so it drops out of selection.
+func (r *CapabilityRegistry) Admit(manifest *protocol.CapabilityManifest) error {
+	if manifest == nil {
+		return fmt.Errorf("%w: registration has no capability manifest", ErrInvalidManifest)
+	}
+	if err := manifest.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.policy.Check(manifest); err != nil {
+		delete(r.manifests, manifest.NodeID)
+		return err
+	}
+	r.manifests[manifest.NodeID] = *manifest
+	return nil
+}
+
+// Manifest returns a node's admitted manifest.
+func (r *CapabilityRegistry) Manifest(nodeID string) (protocol.CapabilityManifest, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	manifest, ok := r.manifests[nodeID]
+	return manifest, ok
+}
+
+// Remove forgets a node.
+func (r *CapabilityRegistry) Remove(nodeID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.manifests, nodeID)
+}
+
+// Eligible returns, in sorted order, the candidates with an admitted
+// manifest and enough memory to train a model with params parameters.
+func (r *CapabilityRegistry) Eligible(candidates []string, params int64) []string {
+	need := requiredMemory(params)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	eligible := make([]string, 0, len(candidates))
+	for _, nodeID := range candidates {
+		if manifest, ok := r.manifests[nodeID]; ok && manifest.MemoryBytes >= need {
+			eligible = append(eligible, nodeID)
+		}
+	}
+	sort.Strings(eligible)
+	return eligible
+}
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func testManifest(nodeID string, cpus int, memory int64) *protocol.CapabilityManifest {
+	return &protocol.CapabilityManifest{
+		NodeID:      nodeID,
+		CPUClass:    protocol.CPUClassFor(cpus),
+		CPUCount:    cpus,
+		MemoryBytes: memory,
+	}
+}
+
+func TestAdmissionRejectsUnderCapacityNodes(t *testing.T) {
+	registry := NewCapabilityRegistry(AdmissionPolicy{
+		MinCPUCount:          2,
+		ModelParameters:      1_000_000,
+		RequireTPM:           true,
+		MaxWasmVerifyLatency: 20 * time.Millisecond,
+	})
+
+	capable := testManifest("capable", 4, 1<<30)
+	capable.TPMPresent = true
+	capable.WasmVerifyMicros = 5000
+	if err := registry.Admit(capable); err != nil {
+		t.Fatalf("admit capable node: %v", err)
+	}
+
+	cases := map[string]func(m *protocol.CapabilityManifest){
+		"too few cpus":       func(m *protocol.CapabilityManifest) { m.CPUCount, m.CPUClass = 1, protocol.CPUClassLow },
+		"too little memory":  func(m *protocol.CapabilityManifest) { m.MemoryBytes = 1 << 20 },
+		"no tpm":             func(m *protocol.CapabilityManifest) { m.TPMPresent = false },
+		"slow wasm verifier": func(m *protocol.CapabilityManifest) { m.WasmVerifyMicros = 50000 },
+	}
+	for name, weaken := range cases {
+		manifest := *capable
+		manifest.NodeID = name
+		weaken(&manifest)
+		if err := registry.Admit(&manifest); !errors.Is(err, ErrUnderCapacity) {
+			t.Fatalf("%s: expected ErrUnderCapacity, got %v", name, err)
+		}
+		if _, ok := registry.Manifest(name); ok {
+			t.Fatalf("%s: refused manifest was stored", name)
+		}
+	}
+
+	mislabeled := *capable
+	mislabeled.CPUClass = protocol.CPUClassHigh
+	if err := registry.Admit(&mislabeled); !errors.Is(err, ErrInvalidManifest) {
+		t.Fatalf("expected ErrInvalidManifest for an inconsistent cpu class, got %v", err)
+	}
+	if err := registry.Admit(nil); !errors.Is(err, ErrInvalidManifest) {
+		t.Fatalf("expected ErrInvalidManifest without a manifest, got %v", err)
+	}
+
+	// A re-submitted manifest that no longer qualifies drops the node.
+	degraded := *capable
+	degraded.MemoryBytes = 1 << 20
+	if err := registry.Admit(&degraded); !errors.Is(err, ErrUnderCapacity) {
+		t.Fatalf("expected a degraded re-submission to be refused, got %v", err)
+	}
+	if _, ok := registry.Manifest("capable"); ok {
+		t.Fatal("expected the degraded node to be removed")
+	}
+}
+
+func TestEligibleFiltersByModelSize(t *testing.T) {
+	registry := NewCapabilityRegistry(AdmissionPolicy{})
+	for _, manifest := range []*protocol.CapabilityManifest{
+		testManifest("large", 16, 8<<30),
+		testManifest("medium", 4, 512<<20),
+		testManifest("small", 2, 64<<20),
+	} {
+		if err := registry.Admit(manifest); err != nil {
+			t.Fatalf("admit %s: %v", manifest.NodeID, err)
+		}
+	}
+	candidates := []string{"small", "medium", "large", "unregistered"}
+
+	// 10M parameters need 400MB of training memory.
+	if got := registry.Eligible(candidates, 10_000_000); !reflect.DeepEqual(got, []string{"large", "medium"}) {
+		t.Fatalf("unexpected eligible nodes for a 10M model: %v", got)
+	}
+	if got := registry.Eligible(candidates, 100_000_000); !reflect.DeepEqual(got, []string{"large"}) {
+		t.Fatalf("unexpected eligible nodes for a 100M model: %v", got)
+	}
+	if got := registry.Eligible(candidates, 0); !reflect.DeepEqual(got, []string{"large", "medium", "small"}) {
+		t.Fatalf("expected every registered node eligible for an empty model, got %v", got)
+	}
+}
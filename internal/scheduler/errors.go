@@ -0,0 +1,48 @@
+package scheduler
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the scheduler. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrUnderCapacity means a node's capability manifest does not meet the
+	// admission policy. Retryable once the node's capabilities improve.
+	ErrUnderCapacity = errors.New("node under capacity")
+	// ErrInvalidManifest means a capability manifest is missing or
+	// malformed. Not retryable with the same manifest.
+	ErrInvalidManifest = errors.New("invalid capability manifest")
+	// ErrWorkQueueFull means a work class's queue is at its depth.
+	// Retryable once the queue drains.
+	ErrWorkQueueFull = errors.New("work queue is full")
+	// ErrUnknownWorkClass means the work class is not configured. Not
+	// retryable.
+	ErrUnknownWorkClass = errors.New("unknown work class")
+	// ErrSchedulerClosed means the work scheduler no longer runs work. Not
+	// retryable.
+	ErrSchedulerClosed = errors.New("work scheduler closed")
+	// ErrRoundNotOpen means an extension request names a round the
+	// negotiator is not running, or arrived after its deadline. Not
+	// retryable.
+	ErrRoundNotOpen = errors.New("round not open for extension")
+	// ErrNotParticipant means an extension request came from a node not
+	// selected for the round. Not retryable.
+	ErrNotParticipant = errors.New("node not selected for round")
+	// ErrInvalidExtensionRequest means an extension request is unsigned,
+	// forged, or does not project a missed deadline. Not retryable with the
+	// same request.
+	ErrInvalidExtensionRequest = errors.New("invalid extension request")
+	// ErrExtensionGranted means the round was already extended; rounds are
+	// extended at most once. Not retryable.
+	ErrExtensionGranted = errors.New("round already extended")
+	// ErrRoundBudgetExhausted means the round's deadline is already at its
+	// budget, so there is nothing left to extend it by. Not retryable.
+	ErrRoundBudgetExhausted = errors.New("round budget exhausted")
+	// ErrInvalidLatencyPolicy means a latency policy or region assignment
+	// is malformed. Not retryable with the same configuration.
+	ErrInvalidLatencyPolicy = errors.New("invalid latency policy")
+)
+
+// Retryable reports whether err is a transient scheduler failure.
+func Retryable(err error) bool {
+	return errors.Is(err, ErrUnderCapacity) || errors.Is(err, ErrWorkQueueFull)
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Evaluation sampling defaults.
+const (
+	DefaultEvaluationSampleRate = 0.1
+	DefaultEvaluationMinNodes   = 3
+	DefaultEvaluationTimeout    = 2 * time.Minute
+)
+
+// EvaluationRand is the randomness evaluation sampling draws on.
+// *simrand.SeededRand satisfies it.
+type EvaluationRand interface {
+	Perm(n int) []int
+}
+
+// EvaluationPolicy decides which nodes measure a committed model.
+type EvaluationPolicy struct {
+	// SampleRate is the fraction of nodes sampled. Default
+	// DefaultEvaluationSampleRate.
+	SampleRate float64
+	// MinNodes is the fewest nodes sampled when that many are available.
+	// Default DefaultEvaluationMinNodes.
+	MinNodes int
+	// Metrics is what the nodes measure. Default
+	// protocol.DefaultEvaluationMetrics.
+	Metrics []protocol.MetricDefinition
+	// Timeout is how long the nodes have to report. Default
+	// DefaultEvaluationTimeout.
+	Timeout time.Duration
+	// Rand draws the sample. Default math/rand/v2.
+	Rand EvaluationRand
+}
+
+type globalPerm struct{}
+
+func (globalPerm) Perm(n int) []int { return rand.Perm(n) }
+
+func (p EvaluationPolicy) withDefaults() EvaluationPolicy {
+	if p.SampleRate <= 0 {
+		p.SampleRate = DefaultEvaluationSampleRate
+	}
+	if p.MinNodes <= 0 {
+		p.MinNodes = DefaultEvaluationMinNodes
+	}
+	if len(p.Metrics) == 0 {
+		p.Metrics = protocol.DefaultEvaluationMetrics()
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = DefaultEvaluationTimeout
+	}
+	if p.Rand == nil {
+		p.Rand = globalPerm{}
+	}
+	return p
+}
+
+// IssueEvaluation samples the nodes to evaluate round's committed model,
+// identified by modelDigest, and returns the task to send them with the
+// sampled nodes, sorted.
+func (p EvaluationPolicy) IssueEvaluation(round int, modelDigest string, nodes []string) (protocol.EvaluationTask, []string, error) {
+	if p.SampleRate > 1 {
+		return protocol.EvaluationTask{}, nil, fmt.Errorf("evaluation sample rate must be in (0, 1], got %f", p.SampleRate)
+	}
+	p = p.withDefaults()
+	task := protocol.EvaluationTask{
+		Round:       round,
+		ModelDigest: modelDigest,
+		Metrics:     append([]protocol.MetricDefinition(nil), p.Metrics...),
+		Deadline:    time.Now().UTC().Add(p.Timeout),
+	}
+	if err := task.Validate(); err != nil {
+		return protocol.EvaluationTask{}, nil, err
+	}
+	if len(nodes) == 0 {
+		return protocol.EvaluationTask{}, nil, fmt.Errorf("no nodes to evaluate round %d", round)
+	}
+
+	count := int(p.SampleRate * float64(len(nodes)))
+	count = min(max(count, p.MinNodes), len(nodes))
+	sampled := make([]string, count)
+	for i, n := range p.Rand.Perm(len(nodes))[:count] {
+		sampled[i] = nodes[n]
+	}
+	sort.Strings(sampled)
+	return task, sampled, nil
+}
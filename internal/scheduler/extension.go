@@ -0,0 +1,296 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// ExtensionPolicy decides when a round's deadline is extended and by how
+// much.
+type ExtensionPolicy struct {
+	// Threshold is the fraction of the round's selected participants that
+	// must request an extension before one is granted; more than this
+	// fraction must ask.
+	Threshold float64 `json:"threshold"`
+	// MaxExtension bounds the one extension a round gets.
+	MaxExtension time.Duration `json:"max_extension"`
+	// RoundBudget is the global round budget: an extended deadline never
+	// falls later than this long after the round started.
+	RoundBudget time.Duration `json:"round_budget"`
+	// FreeRequests is how many extension requests a node makes, across
+	// rounds, before each further one is penalized.
+	FreeRequests int `json:"free_requests"`
+	// MinCohort, when positive, is the fewest participants a round's
+	// aggregate may be released from, such as a central DP aggregator's
+	// minimum cohort. Once so many participants project missing the
+	// deadline that fewer than MinCohort would make it, the round is
+	// extended whatever the Threshold, since the round would otherwise be
+	// refused. Zero disables.
+	MinCohort int `json:"min_cohort"`
+	// Penalize is called, without the negotiator's lock held, for each
+	// request a node makes beyond FreeRequests, such as
+	// p2p.Verifier.PenalizeExtensionRequest.
+	Penalize func(nodeID string) `json:"-"`
+}
+
+// DefaultExtensionPolicy extends a round when more than half its
+// participants ask, by at most five minutes within a fifteen-minute round
+// budget, and penalizes a node's requests beyond its tenth.
+func DefaultExtensionPolicy() ExtensionPolicy {
+	return ExtensionPolicy{
+		Threshold:    0.5,
+		MaxExtension: 5 * time.Minute,
+		RoundBudget:  15 * time.Minute,
+		FreeRequests: 10,
+	}
+}
+
+// Validate checks the policy's bounds.
+func (p ExtensionPolicy) Validate() error {
+	if p.Threshold < 0 || p.Threshold >= 1 {
+		return fmt.Errorf("extension threshold must be in [0, 1), got %f", p.Threshold)
+	}
+	if p.MaxExtension <= 0 {
+		return fmt.Errorf("max extension must be positive, got %s", p.MaxExtension)
+	}
+	if p.RoundBudget <= 0 {
+		return fmt.Errorf("round budget must be positive, got %s", p.RoundBudget)
+	}
+	if p.FreeRequests < 0 {
+		return fmt.Errorf("free requests must not be negative, got %d", p.FreeRequests)
+	}
+	if p.MinCohort < 0 {
+		return fmt.Errorf("min cohort must not be negative, got %d", p.MinCohort)
+	}
+	return nil
+}
+
+// ExtensionVerifier checks a node's signature over data, declared to be in
+// algorithm, such as crypto.SecureChannel.VerifySignatureAs.
+type ExtensionVerifier func(nodeID string, algorithm protocol.AlgorithmID, data, signature []byte) error
+
+// ExtensionNegotiator collects the extension requests of one round at a
+// time and extends the round's deadline once enough of its participants
+// project missing it.
+type ExtensionNegotiator struct {
+	mu        sync.Mutex
+	policy    ExtensionPolicy
+	verify    ExtensionVerifier
+	publisher events.Publisher
+	now       func() time.Time
+
+	round    RoundState
+	open     bool
+	selected map[string]bool
+	requests map[string]protocol.ExtensionRequest
+	granted  *protocol.DeadlineExtension
+
+	counts     map[string]int
+	extensions []protocol.DeadlineExtension
+}
+
+// NewExtensionNegotiator creates a negotiator that checks request
+// signatures with verify.
+func NewExtensionNegotiator(policy ExtensionPolicy, verify ExtensionVerifier) (*ExtensionNegotiator, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if verify == nil {
+		return nil, errors.New("extension negotiator needs a signature verifier")
+	}
+	return &ExtensionNegotiator{
+		policy: policy,
+		verify: verify,
+		now:    time.Now,
+		counts: make(map[string]int),
+	}, nil
+}
+
+// SetEvents publishes a deadline_extended event for every extension
+// granted.
+func (n *ExtensionNegotiator) SetEvents(pub events.Publisher) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.publisher = pub
+}
+
+// SetClock replaces the clock that decides whether a request arrived
+// before the deadline.
+func (n *ExtensionNegotiator) SetClock(now func() time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.now = now
+}
+
+// Open starts negotiating round, for which selected were chosen to train,
+// forgetting the requests of any round before it.
+func (n *ExtensionNegotiator) Open(round RoundState, selected []string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.round = round
+	n.open = true
+	n.selected = make(map[string]bool, len(selected))
+	for _, nodeID := range selected {
+		n.selected[nodeID] = true
+	}
+	n.requests = make(map[string]protocol.ExtensionRequest)
+	n.granted = nil
+}
+
+// Round returns the round being negotiated, with its deadline as extended.
+func (n *ExtensionNegotiator) Round() RoundState {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.round
+}
+
+// Request records a participant's extension request. It returns the
+// extension when this request carries the round past the policy's
+// threshold, and nil while more requests are needed. A request once the
+// round was extended fails with ErrExtensionGranted. Every request a node
+// makes counts toward its FreeRequests, whether or not it is granted.
+func (n *ExtensionNegotiator) Request(req protocol.ExtensionRequest) (*protocol.DeadlineExtension, error) {
+	n.mu.Lock()
+	extension, penalize, err := n.requestLocked(req)
+	policy, publisher := n.policy, n.publisher
+	n.mu.Unlock()
+
+	if penalize && policy.Penalize != nil {
+		policy.Penalize(req.NodeID)
+	}
+	if extension != nil && publisher != nil {
+		publisher.Publish(events.Event{
+			Kind:         events.KindDeadlineExtended,
+			FederationID: extension.FederationID,
+			Round:        extension.Round,
+			Source:       "scheduler",
+			At:           extension.GrantedAt,
+			Deadline:     extension.Deadline,
+			Updates:      len(extension.Requesters),
+		})
+	}
+	return extension, err
+}
+
+// requestLocked records req and reports the extension it grants, if any,
+// and whether its node is over its free requests. The caller holds n.mu.
+func (n *ExtensionNegotiator) requestLocked(req protocol.ExtensionRequest) (*protocol.DeadlineExtension, bool, error) {
+	now := n.now()
+	switch {
+	case !n.open || req.Round != n.round.RoundID:
+		return nil, false, fmt.Errorf("%w: round %d", ErrRoundNotOpen, req.Round)
+	case !n.selected[req.NodeID]:
+		return nil, false, fmt.Errorf("%w: %s in round %d", ErrNotParticipant, req.NodeID, req.Round)
+	}
+	if err := n.checkLocked(req); err != nil {
+		return nil, false, err
+	}
+
+	n.counts[req.NodeID]++
+	penalize := n.counts[req.NodeID] > n.policy.FreeRequests
+	if n.granted != nil {
+		return nil, penalize, fmt.Errorf("%w: round %d runs to %s", ErrExtensionGranted, req.Round, n.granted.Deadline.Format(time.RFC3339))
+	}
+	if now.After(n.round.Deadline) {
+		return nil, penalize, fmt.Errorf("%w: round %d closed at %s", ErrRoundNotOpen, req.Round, n.round.Deadline.Format(time.RFC3339))
+	}
+	n.requests[req.NodeID] = req
+	if !n.extensionDueLocked() {
+		return nil, penalize, nil
+	}
+
+	extension, err := n.grantLocked(now)
+	if err != nil {
+		return nil, penalize, err
+	}
+	return extension, penalize, nil
+}
+
+// extensionDueLocked reports whether the requests so far warrant an
+// extension: more than the policy's threshold asked, or too few would
+// finish on time to make up the minimum cohort that the selected
+// participants could. The caller holds n.mu.
+func (n *ExtensionNegotiator) extensionDueLocked() bool {
+	if float64(len(n.requests)) > n.policy.Threshold*float64(len(n.selected)) {
+		return true
+	}
+	onTime := len(n.selected) - len(n.requests)
+	return n.policy.MinCohort > 0 && len(n.selected) >= n.policy.MinCohort && onTime < n.policy.MinCohort
+}
+
+// checkLocked verifies req's signature and that it projects missing the
+// round's deadline. The caller holds n.mu.
+func (n *ExtensionNegotiator) checkLocked(req protocol.ExtensionRequest) error {
+	if len(req.Signature) == 0 {
+		return fmt.Errorf("%w: unsigned request from %s", ErrInvalidExtensionRequest, req.NodeID)
+	}
+	digest := req.SigningDigest()
+	if err := n.verify(req.NodeID, req.SignatureAlgorithm, digest[:], req.Signature); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidExtensionRequest, req.NodeID, err)
+	}
+	if !req.ProjectedFinish.After(n.round.Deadline) {
+		return fmt.Errorf("%w: %s projects finishing by the deadline", ErrInvalidExtensionRequest, req.NodeID)
+	}
+	return nil
+}
+
+// grantLocked extends the round to the latest finish its requesters
+// project, within MaxExtension and the round budget. The caller holds
+// n.mu.
+func (n *ExtensionNegotiator) grantLocked(now time.Time) (*protocol.DeadlineExtension, error) {
+	previous := n.round.Deadline
+	latest := previous
+	requesters := make([]string, 0, len(n.requests))
+	for nodeID, req := range n.requests {
+		requesters = append(requesters, nodeID)
+		if req.ProjectedFinish.After(latest) {
+			latest = req.ProjectedFinish
+		}
+	}
+	sort.Strings(requesters)
+
+	deadline := latest
+	if bound := previous.Add(n.policy.MaxExtension); deadline.After(bound) {
+		deadline = bound
+	}
+	if budget := n.round.StartedAt.Add(n.policy.RoundBudget); deadline.After(budget) {
+		deadline = budget
+	}
+	if !deadline.After(previous) {
+		return nil, fmt.Errorf("%w: round %d already runs to %s", ErrRoundBudgetExhausted, n.round.RoundID, previous.Format(time.RFC3339))
+	}
+
+	n.round.Deadline = deadline
+	n.granted = &protocol.DeadlineExtension{
+		FederationID:     n.requests[requesters[0]].FederationID,
+		Round:            n.round.RoundID,
+		PreviousDeadline: previous,
+		Deadline:         deadline,
+		Requesters:       requesters,
+		Selected:         len(n.selected),
+		GrantedAt:        now,
+	}
+	n.extensions = append(n.extensions, *n.granted)
+	extension := *n.granted
+	return &extension, nil
+}
+
+// Requests returns how many extension requests nodeID has made.
+func (n *ExtensionNegotiator) Requests(nodeID string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.counts[nodeID]
+}
+
+// Extensions returns every extension granted, oldest first.
+func (n *ExtensionNegotiator) Extensions() []protocol.DeadlineExtension {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]protocol.DeadlineExtension(nil), n.extensions...)
+}
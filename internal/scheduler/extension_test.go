@@ -0,0 +1,270 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// extensionRound is a ten-minute round of four participants, each with a
+// registered identity.
+type extensionRound struct {
+	negotiator *ExtensionNegotiator
+	identities map[string]*crypto.SecureChannel
+	state      RoundState
+	task       protocol.TrainingTask
+	now        time.Time
+}
+
+func newExtensionRound(t *testing.T, policy ExtensionPolicy) *extensionRound {
+	t.Helper()
+	aggregator, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("aggregator identity: %v", err)
+	}
+	negotiator, err := NewExtensionNegotiator(policy, aggregator.VerifySignatureAs)
+	if err != nil {
+		t.Fatalf("new negotiator: %v", err)
+	}
+	started := time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)
+	r := &extensionRound{
+		negotiator: negotiator,
+		identities: make(map[string]*crypto.SecureChannel),
+		state:      RoundState{RoundID: 3, StartedAt: started, Deadline: started.Add(10 * time.Minute), NodeTarget: 4},
+		now:        started.Add(5 * time.Minute),
+	}
+	r.task = protocol.TrainingTask{Round: 3, Deadline: r.state.Deadline}
+	negotiator.SetClock(func() time.Time { return r.now })
+
+	var selected []string
+	for i := 0; i < 4; i++ {
+		nodeID := fmt.Sprintf("edge-%d", i)
+		identity, err := crypto.NewSecureChannel()
+		if err != nil {
+			t.Fatalf("identity: %v", err)
+		}
+		publicKeyPEM, _ := identity.ExportPublicKey()
+		publicKey, err := crypto.ImportPublicKey(publicKeyPEM)
+		if err != nil {
+			t.Fatalf("import key: %v", err)
+		}
+		if err := aggregator.RegisterPeer(nodeID, publicKey); err != nil {
+			t.Fatalf("register: %v", err)
+		}
+		r.identities[nodeID] = identity
+		selected = append(selected, nodeID)
+	}
+	negotiator.Open(r.state, selected)
+	return r
+}
+
+// request returns nodeID's signed request, progress done halfway to the
+// deadline.
+func (r *extensionRound) request(t *testing.T, nodeID string, progress float64) protocol.ExtensionRequest {
+	t.Helper()
+	req, late := protocol.NewExtensionRequest(nodeID, r.task, r.state.StartedAt, r.now, progress)
+	if !late && progress < 0.5 {
+		t.Fatalf("%s at %.2f progress should project missing the deadline", nodeID, progress)
+	}
+	digest := req.SigningDigest()
+	identity := r.identities[nodeID]
+	signature, err := identity.SignData(digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	req.Signature = signature
+	req.SignatureAlgorithm = identity.Algorithm()
+	return req
+}
+
+func TestExtensionGrantedOnceMoreThanThresholdAsk(t *testing.T) {
+	r := newExtensionRound(t, DefaultExtensionPolicy())
+	bus := events.NewBus()
+	sub := bus.Subscribe("extensions", events.DefaultBuffer, events.KindDeadlineExtended)
+	defer sub.Close()
+	r.negotiator.SetEvents(bus)
+
+	// Five minutes in, 40% done projects finishing at 12:30 past the
+	// start; 45% at about 11:07.
+	for _, nodeID := range []string{"edge-0", "edge-1"} {
+		if extension, err := r.negotiator.Request(r.request(t, nodeID, 0.4)); err != nil || extension != nil {
+			t.Fatalf("%s: half the participants must not extend the round: %+v, %v", nodeID, extension, err)
+		}
+	}
+	extension, err := r.negotiator.Request(r.request(t, "edge-2", 0.45))
+	if err != nil || extension == nil {
+		t.Fatalf("third request: expected an extension, got %+v, %v", extension, err)
+	}
+	want := r.state.StartedAt.Add(12*time.Minute + 30*time.Second)
+	if !extension.Deadline.Equal(want) || !extension.PreviousDeadline.Equal(r.state.Deadline) || len(extension.Requesters) != 3 || extension.Selected != 4 {
+		t.Fatalf("extension = %+v, want deadline %s", extension, want)
+	}
+	if round := r.negotiator.Round(); !round.Deadline.Equal(want) {
+		t.Fatalf("round deadline = %s, want %s", round.Deadline, want)
+	}
+	if !r.task.Extend(*extension) || !r.task.Deadline.Equal(want) {
+		t.Fatalf("task deadline = %s, want %s", r.task.Deadline, want)
+	}
+
+	select {
+	case event := <-sub.Events():
+		if event.Round != 3 || !event.Deadline.Equal(want) || event.Updates != 3 {
+			t.Fatalf("event = %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no deadline_extended event")
+	}
+
+	// The round is extended once; the last participant is too late.
+	if _, err := r.negotiator.Request(r.request(t, "edge-3", 0.1)); !errors.Is(err, ErrExtensionGranted) {
+		t.Fatalf("request after the grant: expected ErrExtensionGranted, got %v", err)
+	}
+	if extensions := r.negotiator.Extensions(); len(extensions) != 1 {
+		t.Fatalf("extensions = %+v", extensions)
+	}
+}
+
+func TestExtensionIsBoundedByPolicyAndRoundBudget(t *testing.T) {
+	for name, c := range map[string]struct {
+		policy ExtensionPolicy
+		want   time.Duration
+		err    error
+	}{
+		"max extension": {ExtensionPolicy{Threshold: 0.5, MaxExtension: time.Minute, RoundBudget: time.Hour}, 11 * time.Minute, nil},
+		"round budget":  {ExtensionPolicy{Threshold: 0.5, MaxExtension: time.Hour, RoundBudget: 12 * time.Minute}, 12 * time.Minute, nil},
+		"budget spent":  {ExtensionPolicy{Threshold: 0.5, MaxExtension: time.Hour, RoundBudget: 10 * time.Minute}, 0, ErrRoundBudgetExhausted},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := newExtensionRound(t, c.policy)
+			var extension *protocol.DeadlineExtension
+			var err error
+			// Ten percent done halfway projects a fifty-minute task.
+			for _, nodeID := range []string{"edge-0", "edge-1", "edge-2"} {
+				extension, err = r.negotiator.Request(r.request(t, nodeID, 0.1))
+			}
+			if !errors.Is(err, c.err) {
+				t.Fatalf("expected %v, got %v", c.err, err)
+			}
+			if c.err != nil {
+				if extension != nil || !r.negotiator.Round().Deadline.Equal(r.state.Deadline) {
+					t.Fatalf("budget spent, yet extended: %+v", extension)
+				}
+				return
+			}
+			if want := r.state.StartedAt.Add(c.want); extension == nil || !extension.Deadline.Equal(want) {
+				t.Fatalf("extension = %+v, want deadline %s", extension, want)
+			}
+		})
+	}
+}
+
+func TestExtensionRequestsAreChecked(t *testing.T) {
+	r := newExtensionRound(t, DefaultExtensionPolicy())
+
+	forged := r.request(t, "edge-0", 0.4)
+	forged.ProjectedFinish = forged.ProjectedFinish.Add(time.Hour)
+	if _, err := r.negotiator.Request(forged); !errors.Is(err, ErrInvalidExtensionRequest) {
+		t.Fatalf("altered request: expected ErrInvalidExtensionRequest, got %v", err)
+	}
+	impersonated := r.request(t, "edge-1", 0.4)
+	impersonated.NodeID = "edge-0"
+	if _, err := r.negotiator.Request(impersonated); !errors.Is(err, ErrInvalidExtensionRequest) {
+		t.Fatalf("request signed by another node: expected ErrInvalidExtensionRequest, got %v", err)
+	}
+	unsigned := r.request(t, "edge-0", 0.4)
+	unsigned.Signature = nil
+	if _, err := r.negotiator.Request(unsigned); !errors.Is(err, ErrInvalidExtensionRequest) {
+		t.Fatalf("unsigned request: expected ErrInvalidExtensionRequest, got %v", err)
+	}
+	if _, err := r.negotiator.Request(r.request(t, "edge-0", 0.9)); !errors.Is(err, ErrInvalidExtensionRequest) {
+		t.Fatalf("request projecting an on-time finish: expected ErrInvalidExtensionRequest, got %v", err)
+	}
+
+	outsider := r.request(t, "edge-0", 0.4)
+	outsider.NodeID = "edge-9"
+	if _, err := r.negotiator.Request(outsider); !errors.Is(err, ErrNotParticipant) {
+		t.Fatalf("outsider: expected ErrNotParticipant, got %v", err)
+	}
+	stale := r.request(t, "edge-0", 0.4)
+	stale.Round = 2
+	if _, err := r.negotiator.Request(stale); !errors.Is(err, ErrRoundNotOpen) {
+		t.Fatalf("other round: expected ErrRoundNotOpen, got %v", err)
+	}
+	r.now = r.state.Deadline.Add(time.Second)
+	if _, err := r.negotiator.Request(r.request(t, "edge-0", 0.4)); !errors.Is(err, ErrRoundNotOpen) {
+		t.Fatalf("request after the deadline: expected ErrRoundNotOpen, got %v", err)
+	}
+}
+
+func TestRepeatedExtensionRequestsArePenalized(t *testing.T) {
+	var penalized []string
+	policy := DefaultExtensionPolicy()
+	policy.FreeRequests = 2
+	policy.Penalize = func(nodeID string) { penalized = append(penalized, nodeID) }
+	r := newExtensionRound(t, policy)
+
+	for i := 0; i < 4; i++ {
+		if _, err := r.negotiator.Request(r.request(t, "edge-0", 0.4)); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if got := r.negotiator.Requests("edge-0"); got != 4 {
+		t.Fatalf("edge-0 requests = %d, want 4", got)
+	}
+	// Asking again does not count twice toward the threshold.
+	if round := r.negotiator.Round(); !round.Deadline.Equal(r.state.Deadline) {
+		t.Fatalf("one node extended the round to %s", round.Deadline)
+	}
+	if len(penalized) != 2 || penalized[0] != "edge-0" {
+		t.Fatalf("penalized = %v, want edge-0 twice", penalized)
+	}
+}
+
+func TestExtensionProtectsTheMinimumCohort(t *testing.T) {
+	policy := DefaultExtensionPolicy()
+	policy.Threshold = 0.9
+	policy.MinCohort = 3
+	r := newExtensionRound(t, policy)
+
+	// With one of four late, three still make the minimum cohort.
+	if extension, err := r.negotiator.Request(r.request(t, "edge-0", 0.4)); err != nil || extension != nil {
+		t.Fatalf("first request: %+v, %v", extension, err)
+	}
+	// With two late only two would, and the round would be refused, so it
+	// is extended far below the threshold.
+	extension, err := r.negotiator.Request(r.request(t, "edge-1", 0.4))
+	if err != nil || extension == nil || len(extension.Requesters) != 2 {
+		t.Fatalf("second request: expected an extension, got %+v, %v", extension, err)
+	}
+
+	// A minimum the selected participants cannot make is not extended for.
+	policy.MinCohort = 5
+	r = newExtensionRound(t, policy)
+	for _, nodeID := range []string{"edge-0", "edge-1", "edge-2"} {
+		if extension, err := r.negotiator.Request(r.request(t, nodeID, 0.4)); err != nil || extension != nil {
+			t.Fatalf("%s: extended for an unreachable cohort: %+v, %v", nodeID, extension, err)
+		}
+	}
+}
+
+func TestExtensionPolicyValidate(t *testing.T) {
+	if err := DefaultExtensionPolicy().Validate(); err != nil {
+		t.Fatalf("default policy: %v", err)
+	}
+	for _, policy := range []ExtensionPolicy{
+		{Threshold: 1, MaxExtension: time.Minute, RoundBudget: time.Hour},
+		{Threshold: 0.5, RoundBudget: time.Hour},
+		{Threshold: 0.5, MaxExtension: time.Minute},
+		{Threshold: 0.5, MaxExtension: time.Minute, RoundBudget: time.Hour, FreeRequests: -1},
+		{Threshold: 0.5, MaxExtension: time.Minute, RoundBudget: time.Hour, MinCohort: -1},
+	} {
+		if err := policy.Validate(); err == nil {
+			t.Fatalf("expected %+v to be rejected", policy)
+		}
+	}
+}
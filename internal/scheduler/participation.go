@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Participation counts how a node took part in closed rounds.
+type Participation struct {
+	Full      int `json:"full"`
+	Quantized int `json:"quantized"`
+	Skipped   int `json:"skipped"`
+	Faults    int `json:"faults"`
+}
+
+// RoundParticipation is the outcome of one closed round.
+type RoundParticipation struct {
+	Round     int      `json:"round"`
+	Submitted []string `json:"submitted"`
+	Skipped   []string `json:"skipped"`
+	Faults    []string `json:"faults"`
+}
+
+// ParticipationLedger accounts for the upload tier each node declares in its
+// StatusUpdate. A node that declares a skip and sends nothing is skipped; a
+// node that sends nothing without declaring a skip is a fault.
+type ParticipationLedger struct {
+	mu       sync.Mutex
+	declared map[int]map[string]protocol.UploadTier
+	nodes    map[string]*Participation
+}
+
+// NewParticipationLedger creates an empty ledger.
+func NewParticipationLedger() *ParticipationLedger {
+	return &ParticipationLedger{
+		declared: make(map[int]map[string]protocol.UploadTier),
+		nodes:    make(map[string]*Participation),
+	}
+}
+
+// RecordStatus records the upload tier a node declared for a round. Updates
+// without a tier are ignored.
+func (l *ParticipationLedger) RecordStatus(update protocol.StatusUpdate) {
+	if update.UploadTier == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	round := l.declared[update.Round]
+	if round == nil {
+		round = make(map[string]protocol.UploadTier)
+		l.declared[update.Round] = round
+	}
+	round[update.NodeID] = update.UploadTier
+}
+
+// CloseRound settles round for every expected node given the nodes whose
+// updates arrived, and forgets the round's declarations.
+func (l *ParticipationLedger) CloseRound(round int, expected, submitted []string) RoundParticipation {
+	arrived := make(map[string]bool, len(submitted))
+	for _, nodeID := range submitted {
+		arrived[nodeID] = true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	declared := l.declared[round]
+	delete(l.declared, round)
+
+	result := RoundParticipation{Round: round}
+	for _, nodeID := range expected {
+		counts := l.nodes[nodeID]
+		if counts == nil {
+			counts = &Participation{}
+			l.nodes[nodeID] = counts
+		}
+		switch {
+		case arrived[nodeID] && declared[nodeID] == protocol.UploadTierQuantized:
+			counts.Quantized++
+			result.Submitted = append(result.Submitted, nodeID)
+		case arrived[nodeID]:
+			counts.Full++
+			result.Submitted = append(result.Submitted, nodeID)
+		case declared[nodeID] == protocol.UploadTierSkipped:
+			counts.Skipped++
+			result.Skipped = append(result.Skipped, nodeID)
+		default:
+			counts.Faults++
+			result.Faults = append(result.Faults, nodeID)
+		}
+	}
+	sort.Strings(result.Submitted)
+	sort.Strings(result.Skipped)
+	sort.Strings(result.Faults)
+	return result
+}
+
+// Participation returns a node's counts across closed rounds.
+func (l *ParticipationLedger) Participation(nodeID string) Participation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if counts := l.nodes[nodeID]; counts != nil {
+		return *counts
+	}
+	return Participation{}
+}
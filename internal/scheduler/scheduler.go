@@ -1,6 +1,10 @@
 package scheduler
 
-import "time"
+import (
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+)
 
 // RoundState captures minimum scheduler state needed to coordinate FL rounds.
 type RoundState struct {
@@ -25,3 +29,15 @@ func NewRoundState(roundID, nodeTarget int, timeout time.Duration) RoundState {
 func (r RoundState) IsExpired(now time.Time) bool {
 	return now.After(r.Deadline)
 }
+
+// StartedEvent returns the round_started event announcing r.
+func (r RoundState) StartedEvent() events.Event {
+	return events.Event{
+		Kind:     events.KindRoundStarted,
+		Round:    r.RoundID,
+		Source:   "scheduler",
+		At:       r.StartedAt,
+		Deadline: r.Deadline,
+		Updates:  r.NodeTarget,
+	}
+}
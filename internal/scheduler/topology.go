@@ -0,0 +1,268 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tier is the scope of a round. Regional rounds run among the members of
+// one region; global rounds run among regions.
+type Tier string
+
+const (
+	TierRegional Tier = "regional"
+	TierGlobal   Tier = "global"
+)
+
+// TierTimeout derives one tier's round budget from the round-trip time its
+// messages see.
+type TierTimeout struct {
+	// RoundTrips is the budget in multiples of the tier's p95 RTT.
+	RoundTrips float64 `json:"round_trips"`
+	// Floor and Ceiling bound the budget. A tier with no RTT measured yet
+	// gets its Ceiling.
+	Floor   time.Duration `json:"floor"`
+	Ceiling time.Duration `json:"ceiling"`
+}
+
+func (t TierTimeout) validate(tier Tier) error {
+	if t.RoundTrips <= 0 || math.IsNaN(t.RoundTrips) || math.IsInf(t.RoundTrips, 0) {
+		return fmt.Errorf("%w: %s round trips must be positive, got %g", ErrInvalidLatencyPolicy, tier, t.RoundTrips)
+	}
+	if t.Floor <= 0 || t.Ceiling < t.Floor {
+		return fmt.Errorf("%w: %s budget needs 0 < floor <= ceiling, got %s and %s", ErrInvalidLatencyPolicy, tier, t.Floor, t.Ceiling)
+	}
+	return nil
+}
+
+// budget is RoundTrips times p95, clamped to [Floor, Ceiling].
+func (t TierTimeout) budget(p95 time.Duration, measured bool) time.Duration {
+	if !measured {
+		return t.Ceiling
+	}
+	derived := time.Duration(t.RoundTrips * float64(p95))
+	return min(max(derived, t.Floor), t.Ceiling)
+}
+
+// LatencyPolicy sets how a Topology turns measured RTTs into round
+// budgets.
+type LatencyPolicy struct {
+	Regional TierTimeout `json:"regional"`
+	Global   TierTimeout `json:"global"`
+	// Samples is how many recent RTTs are kept per region pair.
+	Samples int `json:"samples"`
+}
+
+// DefaultLatencyPolicy budgets 40 p95 round trips per round, between 2s
+// and 1m for regional rounds and between 5s and 5m for global ones, over
+// the last 256 RTTs of each region pair.
+func DefaultLatencyPolicy() LatencyPolicy {
+	return LatencyPolicy{
+		Regional: TierTimeout{RoundTrips: 40, Floor: 2 * time.Second, Ceiling: time.Minute},
+		Global:   TierTimeout{RoundTrips: 40, Floor: 5 * time.Second, Ceiling: 5 * time.Minute},
+		Samples:  256,
+	}
+}
+
+// Validate checks both tiers' bounds and the sample window.
+func (p LatencyPolicy) Validate() error {
+	if err := p.Regional.validate(TierRegional); err != nil {
+		return err
+	}
+	if err := p.Global.validate(TierGlobal); err != nil {
+		return err
+	}
+	if p.Samples < 1 {
+		return fmt.Errorf("%w: at least one RTT sample must be kept, got %d", ErrInvalidLatencyPolicy, p.Samples)
+	}
+	return nil
+}
+
+func (p LatencyPolicy) tier(tier Tier) TierTimeout {
+	if tier == TierGlobal {
+		return p.Global
+	}
+	return p.Regional
+}
+
+// TierBudget is the round budget a Topology derived for one tier: a region
+// for regional rounds, every region for global ones.
+type TierBudget struct {
+	Tier   Tier   `json:"tier"`
+	Region string `json:"region,omitempty"`
+	// P95 is the p95 RTT over Samples measurements; both are zero until
+	// the tier's links have been measured.
+	P95     time.Duration `json:"p95_rtt"`
+	Samples int           `json:"samples"`
+	Budget  time.Duration `json:"budget"`
+}
+
+// Topology assigns nodes to regions and keeps the RTTs the transport
+// measures between them, from which it derives tiered round budgets:
+// regional rounds get a budget proportional to their region's p95 RTT,
+// global rounds one proportional to the p95 RTT between regions. Nodes in
+// different regions thus no longer share one timeout sized for the
+// farthest of them.
+type Topology struct {
+	policy LatencyPolicy
+
+	mu      sync.RWMutex
+	regions map[string]string
+	rtts    map[[2]string]*rttWindow
+}
+
+// rttWindow is a ring of the most recent RTTs between two regions.
+type rttWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+// NewTopology creates a topology placing nodes by regions, a node ID to
+// region map, as read from configuration or the genesis shard layout.
+func NewTopology(policy LatencyPolicy, regions map[string]string) (*Topology, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	t := &Topology{policy: policy, regions: make(map[string]string, len(regions)), rtts: make(map[[2]string]*rttWindow)}
+	for nodeID, region := range regions {
+		if nodeID == "" || region == "" {
+			return nil, fmt.Errorf("%w: region assignment %q=%q needs both a node and a region", ErrInvalidLatencyPolicy, nodeID, region)
+		}
+		t.regions[nodeID] = region
+	}
+	return t, nil
+}
+
+// Policy returns the topology's latency policy.
+func (t *Topology) Policy() LatencyPolicy {
+	return t.policy
+}
+
+// SetRegion places nodeID in region, for nodes that join after the
+// topology was built.
+func (t *Topology) SetRegion(nodeID, region string) {
+	if nodeID == "" || region == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.regions[nodeID] = region
+}
+
+// Region returns the region nodeID is placed in.
+func (t *Topology) Region(nodeID string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	region, ok := t.regions[nodeID]
+	return region, ok
+}
+
+// Regions returns every region a node is placed in, sorted.
+func (t *Topology) Regions() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.regionsLocked()
+}
+
+func (t *Topology) regionsLocked() []string {
+	seen := make(map[string]bool)
+	var regions []string
+	for _, region := range t.regions {
+		if !seen[region] {
+			seen[region] = true
+			regions = append(regions, region)
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// ObserveRTT records a round trip the transport measured between two
+// nodes. It returns false, recording nothing, when either node has no
+// region or rtt is not positive.
+func (t *Topology) ObserveRTT(from, to string, rtt time.Duration) bool {
+	if rtt <= 0 {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	a, okA := t.regions[from]
+	b, okB := t.regions[to]
+	if !okA || !okB {
+		return false
+	}
+	key := regionPair(a, b)
+	window := t.rtts[key]
+	if window == nil {
+		window = &rttWindow{}
+		t.rtts[key] = window
+	}
+	if len(window.samples) < t.policy.Samples {
+		window.samples = append(window.samples, rtt)
+	} else {
+		window.samples[window.next] = rtt
+	}
+	window.next = (window.next + 1) % t.policy.Samples
+	return true
+}
+
+// Derive returns the round budget for a regional round in region, or, for
+// TierGlobal, a global round; region is then ignored.
+func (t *Topology) Derive(tier Tier, region string) TierBudget {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.deriveLocked(tier, region)
+}
+
+// Budgets returns the budget of every region's regional rounds, in region
+// order, followed by that of global rounds.
+func (t *Topology) Budgets() []TierBudget {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	regions := t.regionsLocked()
+	budgets := make([]TierBudget, 0, len(regions)+1)
+	for _, region := range regions {
+		budgets = append(budgets, t.deriveLocked(TierRegional, region))
+	}
+	return append(budgets, t.deriveLocked(TierGlobal, ""))
+}
+
+// deriveLocked computes a tier's budget. Callers must hold t.mu.
+func (t *Topology) deriveLocked(tier Tier, region string) TierBudget {
+	var samples []time.Duration
+	if tier == TierGlobal {
+		region = ""
+		for key, window := range t.rtts {
+			if key[0] != key[1] {
+				samples = append(samples, window.samples...)
+			}
+		}
+	} else if window := t.rtts[regionPair(region, region)]; window != nil {
+		samples = append(samples, window.samples...)
+	}
+	budget := TierBudget{Tier: tier, Region: region, Samples: len(samples)}
+	if len(samples) > 0 {
+		budget.P95 = percentile95(samples)
+	}
+	budget.Budget = t.policy.tier(tier).budget(budget.P95, len(samples) > 0)
+	return budget
+}
+
+// regionPair keys the RTTs between two regions, in either direction.
+func regionPair(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// percentile95 returns the nearest-rank 95th percentile of samples, which
+// it sorts.
+func percentile95(samples []time.Duration) time.Duration {
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	rank := int(math.Ceil(0.95*float64(len(samples)))) - 1
+	return samples[max(rank, 0)]
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTopologyDerivesTieredBudgets(t *testing.T) {
+	policy := LatencyPolicy{
+		Regional: TierTimeout{RoundTrips: 10, Floor: 100 * time.Millisecond, Ceiling: 2 * time.Second},
+		Global:   TierTimeout{RoundTrips: 10, Floor: 500 * time.Millisecond, Ceiling: 10 * time.Second},
+		Samples:  20,
+	}
+	topology, err := NewTopology(policy, map[string]string{"a-1": "us", "a-2": "us", "b-1": "eu", "b-2": "eu"})
+	if err != nil {
+		t.Fatalf("new topology: %v", err)
+	}
+
+	// Nothing measured yet: every tier falls back to its ceiling.
+	if budget := topology.Derive(TierRegional, "us"); budget.Budget != 2*time.Second || budget.Samples != 0 {
+		t.Fatalf("unmeasured regional budget = %+v", budget)
+	}
+
+	for i := 1; i <= 20; i++ {
+		topology.ObserveRTT("a-1", "a-2", time.Duration(i)*time.Millisecond)
+		topology.ObserveRTT("b-1", "b-2", 80*time.Millisecond)
+		topology.ObserveRTT("a-1", "b-2", time.Duration(100+i*10)*time.Millisecond)
+	}
+	if topology.ObserveRTT("a-1", "stranger", time.Millisecond) {
+		t.Fatal("recorded an RTT to a node without a region")
+	}
+
+	// us: p95 of 1..20ms is 19ms; ten round trips fall below the floor.
+	if budget := topology.Derive(TierRegional, "us"); budget.P95 != 19*time.Millisecond || budget.Budget != 190*time.Millisecond {
+		t.Fatalf("us budget = %+v", budget)
+	}
+	if budget := topology.Derive(TierRegional, "eu"); budget.Budget != 800*time.Millisecond {
+		t.Fatalf("eu budget = %+v", budget)
+	}
+	// Global: p95 of 110..300ms is 290ms, intra-region links excluded.
+	global := topology.Derive(TierGlobal, "us")
+	if global.P95 != 290*time.Millisecond || global.Budget != 2900*time.Millisecond || global.Region != "" {
+		t.Fatalf("global budget = %+v", global)
+	}
+
+	// The window keeps only the latest samples.
+	for i := 0; i < 20; i++ {
+		topology.ObserveRTT("a-2", "a-1", time.Millisecond)
+	}
+	if budget := topology.Derive(TierRegional, "us"); budget.Budget != 100*time.Millisecond || budget.Samples != 20 {
+		t.Fatalf("us budget after the window moved = %+v", budget)
+	}
+	if budgets := topology.Budgets(); len(budgets) != 3 || budgets[0].Region != "eu" || budgets[2].Tier != TierGlobal {
+		t.Fatalf("budgets = %+v", budgets)
+	}
+
+	budget, err := NewTieredRoundBudget(context.Background(), 4, global, DefaultBudgetSplit())
+	if err != nil {
+		t.Fatalf("new tiered budget: %v", err)
+	}
+	defer budget.Close()
+	if tier, ok := budget.Tier(); !ok || tier != global {
+		t.Fatalf("round budget tier = %+v, %v", tier, ok)
+	}
+	if remaining := budget.Remaining(); remaining > global.Budget || remaining < global.Budget-time.Second {
+		t.Fatalf("round budget remaining = %s, want about %s", remaining, global.Budget)
+	}
+}
+
+func TestLatencyPolicyValidate(t *testing.T) {
+	if err := DefaultLatencyPolicy().Validate(); err != nil {
+		t.Fatalf("default policy: %v", err)
+	}
+	inverted := DefaultLatencyPolicy()
+	inverted.Global.Ceiling = time.Second
+	if err := inverted.Validate(); !errors.Is(err, ErrInvalidLatencyPolicy) {
+		t.Fatalf("ceiling below floor: %v", err)
+	}
+	if _, err := NewTopology(DefaultLatencyPolicy(), map[string]string{"node-1": ""}); !errors.Is(err, ErrInvalidLatencyPolicy) {
+		t.Fatalf("node without a region: %v", err)
+	}
+}
@@ -0,0 +1,397 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// WorkClass groups node-agent work that shares one CPU time budget.
+type WorkClass string
+
+const (
+	ClassVerification WorkClass = "verification"
+	ClassTraining     WorkClass = "training"
+	ClassSync         WorkClass = "sync"
+	ClassAdmin        WorkClass = "admin"
+)
+
+// WorkClasses lists every work class in a stable order.
+var WorkClasses = []WorkClass{ClassVerification, ClassTraining, ClassSync, ClassAdmin}
+
+const (
+	// DefaultWorkQueueDepth bounds each class's queue when its config leaves
+	// Depth at zero.
+	DefaultWorkQueueDepth = 256
+	// DefaultShareWindow is the burst a class may run beyond its share
+	// before it yields to contending classes.
+	DefaultShareWindow = 100 * time.Millisecond
+)
+
+// Task is one batch of work. Tasks should be short: classes yield to each
+// other only between tasks, so a long job should be split into batches.
+type Task func(ctx context.Context) error
+
+// ClassConfig configures one work class.
+type ClassConfig struct {
+	// Share is the class's fraction of CPU time while other classes have
+	// work queued. Shares are normalized to sum to one.
+	Share float64
+	// Depth bounds the class's queue; zero means DefaultWorkQueueDepth.
+	Depth int
+}
+
+// WorkSchedulerConfig configures a WorkScheduler.
+type WorkSchedulerConfig struct {
+	// Workers is the CPU budget in concurrent tasks; zero means
+	// runtime.NumCPU(). Each class gets its share of it in workers,
+	// rounded, and at least one.
+	Workers int
+	// Window is the burst a class may run beyond its share; zero means
+	// DefaultShareWindow.
+	Window time.Duration
+	// Classes holds the configured classes; others are refused.
+	Classes map[WorkClass]ClassConfig
+}
+
+// DefaultWorkSchedulerConfig favours verification and training, leaving a
+// tenth of the CPU each to sync and admin work.
+func DefaultWorkSchedulerConfig() WorkSchedulerConfig {
+	return WorkSchedulerConfig{
+		Classes: map[WorkClass]ClassConfig{
+			ClassVerification: {Share: 0.4},
+			ClassTraining:     {Share: 0.4},
+			ClassSync:         {Share: 0.1},
+			ClassAdmin:        {Share: 0.1},
+		},
+	}
+}
+
+// WorkClassStats is one class's queue and time accounting.
+type WorkClassStats struct {
+	Share     float64       `json:"share"`
+	Workers   int           `json:"workers"`
+	Depth     int           `json:"depth"`
+	Capacity  int           `json:"capacity"`
+	Running   int           `json:"running"`
+	Completed uint64        `json:"completed"`
+	Failed    uint64        `json:"failed"`
+	Rejected  uint64        `json:"rejected"`
+	Busy      time.Duration `json:"busy"`
+}
+
+// WorkScheduler runs node-agent work from weighted per-class queues so a
+// burst of one class, typically verification, cannot starve the others.
+// Each class has its own workers, sized by its share. A class also carries
+// a time budget that refills at its share of the CPU and is charged the
+// wall time of every task it runs; between tasks, a class whose budget is
+// spent waits while other classes have work queued. Without contention a
+// class may use idle CPU freely.
+type WorkScheduler struct {
+	workers int
+	window  time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	classes  map[WorkClass]*workClass
+	closed   bool
+	observer func(class WorkClass, depth int)
+}
+
+type workClass struct {
+	name     WorkClass
+	share    float64
+	workers  int
+	depth    int
+	queue    []queuedTask
+	running  int
+	budget   time.Duration
+	refilled time.Time
+	stats    WorkClassStats
+}
+
+type queuedTask struct {
+	task Task
+	done chan error
+}
+
+// NewWorkScheduler creates a scheduler and starts its workers.
+func NewWorkScheduler(config WorkSchedulerConfig) (*WorkScheduler, error) {
+	if len(config.Classes) == 0 {
+		return nil, fmt.Errorf("work scheduler needs at least one class")
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultShareWindow
+	}
+	total := 0.0
+	for class, cc := range config.Classes {
+		if !knownWorkClass(class) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownWorkClass, class)
+		}
+		if cc.Share <= 0 || math.IsNaN(cc.Share) || math.IsInf(cc.Share, 0) {
+			return nil, fmt.Errorf("work class %s share must be positive, got %v", class, cc.Share)
+		}
+		total += cc.Share
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &WorkScheduler{
+		workers: config.Workers,
+		window:  config.Window,
+		ctx:     ctx,
+		cancel:  cancel,
+		classes: make(map[WorkClass]*workClass, len(config.Classes)),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	now := time.Now()
+	for class, cc := range config.Classes {
+		share := cc.Share / total
+		depth := cc.Depth
+		if depth <= 0 {
+			depth = DefaultWorkQueueDepth
+		}
+		workers := int(math.Round(share * float64(config.Workers)))
+		if workers < 1 {
+			workers = 1
+		}
+		c := &workClass{name: class, share: share, workers: workers, depth: depth, refilled: now}
+		c.budget = c.burst(s)
+		s.classes[class] = c
+		for i := 0; i < workers; i++ {
+			s.running.Add(1)
+			go s.runWorker(c)
+		}
+	}
+	return s, nil
+}
+
+func knownWorkClass(class WorkClass) bool {
+	for _, known := range WorkClasses {
+		if class == known {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDepthObserver registers a callback invoked with a class's queue depth
+// whenever it changes, such as a metrics gauge setter.
+func (s *WorkScheduler) SetDepthObserver(observer func(class WorkClass, depth int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observer = observer
+}
+
+// Workers returns the number of workers serving class.
+func (s *WorkScheduler) Workers(class WorkClass) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.classes[class]; ok {
+		return c.workers
+	}
+	return 0
+}
+
+// Submit queues task on class without waiting for it to run. It fails with
+// ErrWorkQueueFull when the class's queue is at its depth.
+func (s *WorkScheduler) Submit(class WorkClass, task Task) error {
+	return s.enqueue(class, queuedTask{task: task})
+}
+
+// Do queues task on class and waits for its result. If ctx ends first, Do
+// returns ctx's error; the task still runs when its turn comes.
+func (s *WorkScheduler) Do(ctx context.Context, class WorkClass, task Task) error {
+	done := make(chan error, 1)
+	if err := s.enqueue(class, queuedTask{task: task, done: done}); err != nil {
+		return err
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *WorkScheduler) enqueue(class WorkClass, item queuedTask) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrSchedulerClosed
+	}
+	c, ok := s.classes[class]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrUnknownWorkClass, class)
+	}
+	if len(c.queue) >= c.depth {
+		c.stats.Rejected++
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s at depth %d", ErrWorkQueueFull, class, c.depth)
+	}
+	c.queue = append(c.queue, item)
+	depth := len(c.queue)
+	observer := s.observer
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	if observer != nil {
+		observer(class, depth)
+	}
+	return nil
+}
+
+// Stats returns every configured class's accounting.
+func (s *WorkScheduler) Stats() map[WorkClass]WorkClassStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make(map[WorkClass]WorkClassStats, len(s.classes))
+	for class, c := range s.classes {
+		st := c.stats
+		st.Share = c.share
+		st.Workers = c.workers
+		st.Depth = len(c.queue)
+		st.Capacity = c.depth
+		st.Running = c.running
+		stats[class] = st
+	}
+	return stats
+}
+
+// Close stops accepting work, fails queued tasks with ErrSchedulerClosed,
+// cancels the context of running tasks, and waits for them to return.
+func (s *WorkScheduler) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	var abandoned []queuedTask
+	for _, c := range s.classes {
+		abandoned = append(abandoned, c.queue...)
+		c.queue = nil
+	}
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	s.cancel()
+	for _, item := range abandoned {
+		if item.done != nil {
+			item.done <- ErrSchedulerClosed
+		}
+	}
+	s.running.Wait()
+}
+
+// runWorker runs c's tasks until the scheduler closes, yielding between
+// tasks whenever c has spent its share while other classes wait.
+func (s *WorkScheduler) runWorker(c *workClass) {
+	defer s.running.Done()
+	for {
+		item, ok := s.next(c)
+		if !ok {
+			return
+		}
+		started := time.Now()
+		err := item.task(s.ctx)
+		elapsed := time.Since(started)
+
+		s.mu.Lock()
+		c.running--
+		c.stats.Busy += elapsed
+		if err != nil {
+			c.stats.Failed++
+		} else {
+			c.stats.Completed++
+		}
+		c.budget -= elapsed
+		if floor := -c.burst(s); c.budget < floor {
+			c.budget = floor
+		}
+		s.mu.Unlock()
+
+		if item.done != nil {
+			item.done <- err
+		}
+		runtime.Gosched()
+	}
+}
+
+// next blocks until c may run its next task and dequeues it.
+func (s *WorkScheduler) next(c *workClass) (queuedTask, bool) {
+	s.mu.Lock()
+	for {
+		if s.closed {
+			s.mu.Unlock()
+			return queuedTask{}, false
+		}
+		if len(c.queue) == 0 {
+			s.cond.Wait()
+			continue
+		}
+		s.refillLocked(c)
+		if c.budget >= 0 || !s.contendedLocked(c) {
+			break
+		}
+		wait := time.Duration(float64(-c.budget) / c.rate(s))
+		timer := time.AfterFunc(wait, func() {
+			s.mu.Lock()
+			s.cond.Broadcast()
+			s.mu.Unlock()
+		})
+		s.cond.Wait()
+		timer.Stop()
+	}
+	item := c.queue[0]
+	c.queue = c.queue[1:]
+	c.running++
+	depth := len(c.queue)
+	observer := s.observer
+	// Another worker may be waiting on a class whose contention just ended.
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	if observer != nil {
+		observer(c.name, depth)
+	}
+	return item, true
+}
+
+// contendedLocked reports whether another class has work queued.
+func (s *WorkScheduler) contendedLocked(c *workClass) bool {
+	for _, other := range s.classes {
+		if other != c && len(other.queue) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *WorkScheduler) refillLocked(c *workClass) {
+	now := time.Now()
+	c.budget += time.Duration(float64(now.Sub(c.refilled)) * c.rate(s))
+	c.refilled = now
+	if burst := c.burst(s); c.budget > burst {
+		c.budget = burst
+	}
+}
+
+// rate is the CPU time c earns per unit of wall time.
+func (c *workClass) rate(s *WorkScheduler) float64 {
+	return c.share * float64(s.workers)
+}
+
+// burst is how far c's budget may run ahead of, or behind, its share.
+func (c *workClass) burst(s *WorkScheduler) time.Duration {
+	return time.Duration(float64(s.window) * c.rate(s))
+}
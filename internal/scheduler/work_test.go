@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func sleepTask(d time.Duration) Task {
+	return func(ctx context.Context) error {
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+		}
+		return nil
+	}
+}
+
+func TestWorkSchedulerKeepsTrainingWithinShareUnderVerificationFlood(t *testing.T) {
+	// Two workers' worth of CPU: verification rounds to both, training to
+	// one, so only the time budget holds each to its share.
+	sched, err := NewWorkScheduler(WorkSchedulerConfig{
+		Workers: 2,
+		Window:  20 * time.Millisecond,
+		Classes: map[WorkClass]ClassConfig{
+			ClassVerification: {Share: 0.75, Depth: 10000},
+			ClassTraining:     {Share: 0.25, Depth: 10000},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new scheduler: %v", err)
+	}
+	defer sched.Close()
+	if sched.Workers(ClassVerification) != 2 || sched.Workers(ClassTraining) != 1 {
+		t.Fatalf("workers = %d verification, %d training", sched.Workers(ClassVerification), sched.Workers(ClassTraining))
+	}
+	var mu sync.Mutex
+	maxDepth := make(map[WorkClass]int)
+	sched.SetDepthObserver(func(class WorkClass, depth int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if depth > maxDepth[class] {
+			maxDepth[class] = depth
+		}
+	})
+
+	for i := 0; i < 5000; i++ {
+		if err := sched.Submit(ClassVerification, sleepTask(2*time.Millisecond)); err != nil {
+			t.Fatalf("submit verification: %v", err)
+		}
+		if err := sched.Submit(ClassTraining, sleepTask(2*time.Millisecond)); err != nil {
+			t.Fatalf("submit training: %v", err)
+		}
+	}
+	time.Sleep(600 * time.Millisecond)
+
+	stats := sched.Stats()
+	verification, training := stats[ClassVerification], stats[ClassTraining]
+	if verification.Depth == 0 {
+		t.Fatal("verification queue drained; the flood did not saturate it")
+	}
+	if training.Completed < 30 {
+		t.Fatalf("training completed %d tasks under the verification flood", training.Completed)
+	}
+	// Worker counts alone would give training a third.
+	share := float64(training.Busy) / float64(training.Busy+verification.Busy)
+	if share < 0.18 || share > 0.3 {
+		t.Fatalf("training ran %.2f of the busy time, configured 0.25", share)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if maxDepth[ClassVerification] < 4000 {
+		t.Fatalf("observed verification depth peaked at %d", maxDepth[ClassVerification])
+	}
+}
+
+func TestWorkSchedulerQueueLimitsAndClose(t *testing.T) {
+	sched, err := NewWorkScheduler(WorkSchedulerConfig{
+		Workers: 1,
+		Classes: map[WorkClass]ClassConfig{ClassAdmin: {Share: 1, Depth: 1}},
+	})
+	if err != nil {
+		t.Fatalf("new scheduler: %v", err)
+	}
+	if err := sched.Submit(ClassSync, sleepTask(0)); !errors.Is(err, ErrUnknownWorkClass) {
+		t.Fatalf("unconfigured class: expected ErrUnknownWorkClass, got %v", err)
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := sched.Submit(ClassAdmin, func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	<-started
+	queued := make(chan error, 1)
+	go func() { queued <- sched.Do(context.Background(), ClassAdmin, sleepTask(0)) }()
+	deadline := time.Now().Add(time.Second)
+	for sched.Stats()[ClassAdmin].Depth == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := sched.Submit(ClassAdmin, sleepTask(0)); !errors.Is(err, ErrWorkQueueFull) || !Retryable(err) {
+		t.Fatalf("full queue: expected retryable ErrWorkQueueFull, got %v", err)
+	}
+	if rejected := sched.Stats()[ClassAdmin].Rejected; rejected != 1 {
+		t.Fatalf("rejected = %d, want 1", rejected)
+	}
+
+	close(release)
+	if err := <-queued; err != nil {
+		t.Fatalf("queued task: %v", err)
+	}
+	sched.Close()
+	if err := sched.Submit(ClassAdmin, sleepTask(0)); !errors.Is(err, ErrSchedulerClosed) {
+		t.Fatalf("after close: expected ErrSchedulerClosed, got %v", err)
+	}
+}
@@ -0,0 +1,568 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package sharding assigns nodes to shards. Nodes are placed on a
+// consistent-hash ring by their identity key fingerprint, so membership
+// changes move as few nodes as possible, and every move is staged so that
+// no shard ever drops below the size the fault model needs to stay safe.
+package sharding
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/tpm"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Config sets the shard layout and how fast it may change.
+type Config struct {
+	// Shards is how many shards the ring holds, named by ShardIDs.
+	Shards int `json:"shards"`
+	// VirtualNodes is how many ring points each shard owns; more points
+	// spread nodes more evenly.
+	VirtualNodes int `json:"virtual_nodes"`
+	// MaxMovedFraction bounds the fraction of admitted nodes one Rebalance
+	// moves between shards. At least one node may always move. Draining a
+	// removed shard needs a budget of at least MinMembers nodes.
+	MaxMovedFraction float64 `json:"max_moved_fraction"`
+	// FaultModel and MaxFaulty set the per-shard safety check: a shard of
+	// n members is safe when tpm.VerifyShardIntegrityUnder passes for n
+	// and MaxFaulty faulty members.
+	FaultModel faultmodel.Model `json:"fault_model,omitempty"`
+	MaxFaulty  int              `json:"max_faulty"`
+}
+
+// DefaultConfig is four shards of 128 virtual nodes each, moving at most
+// 5% of nodes per epoch, each shard tolerating one faulty member under the
+// classic fault model.
+func DefaultConfig() Config {
+	return Config{Shards: 4, VirtualNodes: 128, MaxMovedFraction: 0.05, FaultModel: faultmodel.Classic33, MaxFaulty: 1}
+}
+
+// Validate checks the shard count, ring density, move budget, and fault
+// tolerance.
+func (c Config) Validate() error {
+	if c.Shards < 1 {
+		return fmt.Errorf("%w: shard count must be positive, got %d", ErrInvalidConfig, c.Shards)
+	}
+	if c.VirtualNodes < 1 {
+		return fmt.Errorf("%w: virtual nodes per shard must be positive, got %d", ErrInvalidConfig, c.VirtualNodes)
+	}
+	if !(c.MaxMovedFraction > 0 && c.MaxMovedFraction <= 1) {
+		return fmt.Errorf("%w: moved fraction must be in (0, 1], got %g", ErrInvalidConfig, c.MaxMovedFraction)
+	}
+	if c.MaxFaulty < 0 {
+		return fmt.Errorf("%w: tolerated faulty members must not be negative, got %d", ErrInvalidConfig, c.MaxFaulty)
+	}
+	if _, err := faultmodel.Parse(string(c.FaultModel)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// Signer signs shard assignments with the global tier's identity key;
+// crypto.SecureChannel implements it.
+type Signer interface {
+	SignData(data []byte) ([]byte, error)
+	ExportPublicKey() ([]byte, error)
+}
+
+// Move is one node changing shards.
+type Move struct {
+	NodeID string `json:"node_id"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// RebalanceReport is what one Rebalance did.
+type RebalanceReport struct {
+	Epoch uint64 `json:"epoch"`
+	Moves []Move `json:"moves"`
+	// Assignments are the signed records of the moved nodes' new shards.
+	Assignments []protocol.ShardAssignment `json:"assignments"`
+	// Pending counts the nodes still outside the shard the ring places
+	// them in, left for later epochs.
+	Pending int `json:"pending"`
+}
+
+// ShardStatus is a shard's size against the safety check.
+type ShardStatus struct {
+	ShardID string `json:"shard_id"`
+	Members int    `json:"members"`
+	// Safe is whether the shard passes the per-shard safety check.
+	Safe bool `json:"safe"`
+	// Retiring is set for shards no longer on the ring whose members are
+	// still being moved out.
+	Retiring bool `json:"retiring,omitempty"`
+}
+
+type member struct {
+	publicKey   []byte
+	fingerprint string
+	shard       string
+	assignment  protocol.ShardAssignment
+}
+
+// Assigner is the shard admission controller. It admits each node into
+// the shard the ring places it in and, when the ring changes, moves nodes
+// to their new shards through Rebalance, a bounded number per epoch.
+//
+// A shard is safe once it has MinMembers members. While any shard is safe
+// the assigner keeps it so: a node never leaves a shard it would leave
+// unsafe, except when the shard's last members drain out together, and a
+// shard below MinMembers only receives nodes in a batch that makes it
+// safe. A node whose shard is not yet safe is admitted into the next safe
+// shard on the ring until Rebalance can move it. Until the first
+// Rebalance, while the federation bootstraps, nodes are admitted straight
+// into their shards.
+type Assigner struct {
+	cfg         Config
+	minMembers  int
+	signer      Signer
+	publicKey   []byte
+	fingerprint string
+	algorithm   protocol.AlgorithmID
+
+	mu      sync.RWMutex
+	ring    *Ring
+	epoch   uint64
+	members map[string]*member
+	sizes   map[string]int
+	// onChange, when set, is told each shard's members after they change.
+	onChange func(shardID string, members []protocol.ShardMember)
+}
+
+// NewAssigner creates an assigner signing assignments with signer, the
+// global tier's identity.
+func NewAssigner(cfg Config, signer Signer) (*Assigner, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	ring, err := NewRing(ShardIDs(cfg.Shards), cfg.VirtualNodes)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := signer.ExportPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := crypto.PublicKeyFingerprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := crypto.PublicKeyAlgorithm(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Assigner{
+		cfg:         cfg,
+		minMembers:  minSafeMembers(cfg.FaultModel, cfg.MaxFaulty),
+		signer:      signer,
+		publicKey:   publicKey,
+		fingerprint: fingerprint,
+		algorithm:   algorithm,
+		ring:        ring,
+		members:     make(map[string]*member),
+		sizes:       make(map[string]int),
+	}, nil
+}
+
+// minSafeMembers is the smallest shard that passes the safety check with
+// maxFaulty faulty members.
+func minSafeMembers(model faultmodel.Model, maxFaulty int) int {
+	n := max(maxFaulty, 1)
+	for tpm.VerifyShardIntegrityUnder(model, n, maxFaulty) != nil {
+		n++
+	}
+	return n
+}
+
+// PublicKey returns the PEM key assignments are signed with, the one
+// verifiers pass to VerifyAssignment.
+func (a *Assigner) PublicKey() []byte {
+	return append([]byte(nil), a.publicKey...)
+}
+
+// MinMembers is the fewest members a shard needs to be safe.
+func (a *Assigner) MinMembers() int {
+	return a.minMembers
+}
+
+// Epoch returns the current assignment epoch, advanced by each Rebalance.
+func (a *Assigner) Epoch() uint64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.epoch
+}
+
+// SetMembershipHook calls hook with a shard's members, in node ID order,
+// after every admission, departure, key change, or Rebalance move that
+// changes them, so the shard's group key can be rotated (see
+// p2p.Network.SetTopicMembers). hook runs outside the assigner's lock.
+// nil removes it.
+func (a *Assigner) SetMembershipHook(hook func(shardID string, members []protocol.ShardMember)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onChange = hook
+}
+
+// notify passes each of shards' members to the membership hook.
+func (a *Assigner) notify(shards ...string) {
+	a.mu.RLock()
+	hook := a.onChange
+	a.mu.RUnlock()
+	if hook == nil {
+		return
+	}
+	for _, shard := range shards {
+		hook(shard, a.Members(shard))
+	}
+}
+
+// Admit places a node in a shard and returns its signed assignment. A node
+// admitted before keeps its shard; its assignment is reissued if its key
+// changed.
+func (a *Assigner) Admit(nodeID string, publicKey []byte) (protocol.ShardAssignment, error) {
+	assignment, changed, err := a.admit(nodeID, publicKey)
+	if err == nil && changed {
+		a.notify(assignment.ShardID)
+	}
+	return assignment, err
+}
+
+// admit is Admit, reporting whether the node's shard membership changed.
+func (a *Assigner) admit(nodeID string, publicKey []byte) (protocol.ShardAssignment, bool, error) {
+	if nodeID == "" {
+		return protocol.ShardAssignment{}, false, fmt.Errorf("%w: node ID is required", ErrInvalidAssignment)
+	}
+	fingerprint, err := crypto.PublicKeyFingerprint(publicKey)
+	if err != nil {
+		return protocol.ShardAssignment{}, false, fmt.Errorf("%w: node %s key: %v", ErrInvalidAssignment, nodeID, err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if existing, ok := a.members[nodeID]; ok {
+		if existing.fingerprint == fingerprint {
+			return existing.assignment, false, nil
+		}
+		existing.publicKey, existing.fingerprint = append([]byte(nil), publicKey...), fingerprint
+		if err := a.assignLocked(nodeID, existing); err != nil {
+			return protocol.ShardAssignment{}, false, err
+		}
+		return existing.assignment, true, nil
+	}
+
+	shard := a.ring.Locate(fingerprint)
+	if a.epoch > 0 && a.sizes[shard] < a.minMembers && a.anySafeLocked() {
+		shard = a.ring.LocateWhere(fingerprint, func(candidate string) bool { return a.sizes[candidate] >= a.minMembers })
+	}
+	m := &member{publicKey: append([]byte(nil), publicKey...), fingerprint: fingerprint, shard: shard}
+	if err := a.assignLocked(nodeID, m); err != nil {
+		return protocol.ShardAssignment{}, false, err
+	}
+	a.members[nodeID] = m
+	a.sizes[shard]++
+	return m.assignment, true, nil
+}
+
+// Remove drops a departed node. A departure is not refused; a shard it
+// leaves unsafe is reported as such by Shards.
+func (a *Assigner) Remove(nodeID string) error {
+	a.mu.Lock()
+	m, ok := a.members[nodeID]
+	if !ok {
+		a.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownNode, nodeID)
+	}
+	delete(a.members, nodeID)
+	if a.sizes[m.shard]--; a.sizes[m.shard] == 0 {
+		delete(a.sizes, m.shard)
+	}
+	a.mu.Unlock()
+
+	a.notify(m.shard)
+	return nil
+}
+
+// SetShardCount rebuilds the ring with count shards. No node moves until
+// Rebalance.
+func (a *Assigner) SetShardCount(count int) error {
+	cfg := a.cfg
+	cfg.Shards = count
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	ring, err := NewRing(ShardIDs(count), cfg.VirtualNodes)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cfg, a.ring = cfg, ring
+	return nil
+}
+
+// Rebalance advances the epoch and moves nodes toward the shards the ring
+// places them in, at most MaxMovedFraction of admitted nodes, in node ID
+// order. Moves into shards still below MinMembers go first, and only in
+// batches that make the shard safe; then moves into safe shards; then
+// retiring shards small enough to drain in one epoch are drained.
+func (a *Assigner) Rebalance() (RebalanceReport, error) {
+	report, err := a.rebalance()
+	changed := make(map[string]bool)
+	for _, move := range report.Moves {
+		changed[move.From], changed[move.To] = true, true
+	}
+	shards := make([]string, 0, len(changed))
+	for shard := range changed {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+	a.notify(shards...)
+	return report, err
+}
+
+// rebalance is Rebalance before the membership hook runs.
+func (a *Assigner) rebalance() (RebalanceReport, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.epoch++
+	report := RebalanceReport{Epoch: a.epoch}
+	budget := max(1, int(math.Ceil(a.cfg.MaxMovedFraction*float64(len(a.members)))))
+
+	var pending []string
+	targets := make(map[string]string)
+	for nodeID, m := range a.members {
+		if target := a.ring.Locate(m.fingerprint); target != m.shard {
+			pending = append(pending, nodeID)
+			targets[nodeID] = target
+		}
+	}
+	sort.Strings(pending)
+	moved := make(map[string]bool)
+	canLeave := func(shard string, leaving int) bool { return a.sizes[shard]-leaving >= a.minMembers }
+	move := func(nodeID string) error {
+		m := a.members[nodeID]
+		from, to := m.shard, targets[nodeID]
+		m.shard = to
+		if err := a.assignLocked(nodeID, m); err != nil {
+			m.shard = from
+			return err
+		}
+		if a.sizes[from]--; a.sizes[from] == 0 {
+			delete(a.sizes, from)
+		}
+		a.sizes[to]++
+		moved[nodeID] = true
+		budget--
+		report.Moves = append(report.Moves, Move{NodeID: nodeID, From: from, To: to})
+		report.Assignments = append(report.Assignments, m.assignment)
+		return nil
+	}
+
+	// Shards below MinMembers take a whole batch that makes them safe or
+	// nothing.
+	forming := make(map[string][]string)
+	for _, nodeID := range pending {
+		if target := targets[nodeID]; a.sizes[target] < a.minMembers {
+			forming[target] = append(forming[target], nodeID)
+		}
+	}
+	for _, shard := range sortedKeys(forming) {
+		leaving := make(map[string]int)
+		var batch []string
+		for _, nodeID := range forming[shard] {
+			if len(batch) == budget {
+				break
+			}
+			if from := a.members[nodeID].shard; canLeave(from, leaving[from]+1) {
+				leaving[from]++
+				batch = append(batch, nodeID)
+			}
+		}
+		if a.sizes[shard]+len(batch) < a.minMembers {
+			continue
+		}
+		for _, nodeID := range batch {
+			if err := move(nodeID); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	// Safe shards take nodes one at a time from shards that stay safe.
+	for _, nodeID := range pending {
+		if budget == 0 {
+			break
+		}
+		if moved[nodeID] || a.sizes[targets[nodeID]] < a.minMembers || !canLeave(a.members[nodeID].shard, 1) {
+			continue
+		}
+		if err := move(nodeID); err != nil {
+			return report, err
+		}
+	}
+
+	// Retiring shards drain their last members together, once every one
+	// of them fits in the budget and has a safe shard to go to.
+	retiring := make(map[string][]string)
+	for _, nodeID := range pending {
+		if from := a.members[nodeID].shard; !moved[nodeID] && !a.onRingLocked(from) {
+			retiring[from] = append(retiring[from], nodeID)
+		}
+	}
+	for _, shard := range sortedKeys(retiring) {
+		nodes := retiring[shard]
+		if len(nodes) != a.sizes[shard] || len(nodes) > budget {
+			continue
+		}
+		ready := true
+		for _, nodeID := range nodes {
+			ready = ready && a.sizes[targets[nodeID]] >= a.minMembers
+		}
+		if !ready {
+			continue
+		}
+		for _, nodeID := range nodes {
+			if err := move(nodeID); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	report.Pending = len(pending) - len(moved)
+	return report, nil
+}
+
+// Assignment returns a node's latest signed assignment.
+func (a *Assigner) Assignment(nodeID string) (protocol.ShardAssignment, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	m, ok := a.members[nodeID]
+	if !ok {
+		return protocol.ShardAssignment{}, false
+	}
+	return m.assignment, true
+}
+
+// Members returns a shard's members in node ID order, as a
+// protocol.MembershipEpochRecord lists them.
+func (a *Assigner) Members(shardID string) []protocol.ShardMember {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var members []protocol.ShardMember
+	for nodeID, m := range a.members {
+		if m.shard == shardID {
+			members = append(members, protocol.ShardMember{NodeID: nodeID, PublicKey: append([]byte(nil), m.publicKey...)})
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].NodeID < members[j].NodeID })
+	return members
+}
+
+// Shards returns every shard on the ring and every retiring shard with
+// members left, in shard ID order.
+func (a *Assigner) Shards() []ShardStatus {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	shards := a.ring.Shards()
+	for shard := range a.sizes {
+		if !a.onRingLocked(shard) {
+			shards = append(shards, shard)
+		}
+	}
+	sort.Strings(shards)
+	statuses := make([]ShardStatus, 0, len(shards))
+	for _, shard := range shards {
+		statuses = append(statuses, ShardStatus{
+			ShardID:  shard,
+			Members:  a.sizes[shard],
+			Safe:     a.sizes[shard] >= a.minMembers,
+			Retiring: !a.onRingLocked(shard),
+		})
+	}
+	return statuses
+}
+
+// assignLocked signs m's current shard for nodeID at the current epoch.
+// Callers must hold a.mu.
+func (a *Assigner) assignLocked(nodeID string, m *member) error {
+	record := protocol.ShardAssignment{NodeID: nodeID, NodeFingerprint: m.fingerprint, ShardID: m.shard, Epoch: a.epoch}
+	digest := record.SigningDigest()
+	signature, err := a.signer.SignData(digest[:])
+	if err != nil {
+		return fmt.Errorf("sign node %s shard %s epoch %d: %w", nodeID, m.shard, a.epoch, err)
+	}
+	record.Signature, record.SignerFingerprint, record.SignatureAlgorithm = signature, a.fingerprint, a.algorithm
+	m.assignment = record
+	return nil
+}
+
+// anySafeLocked reports whether any shard on the ring is safe. Callers
+// must hold a.mu.
+func (a *Assigner) anySafeLocked() bool {
+	for _, shard := range a.ring.Shards() {
+		if a.sizes[shard] >= a.minMembers {
+			return true
+		}
+	}
+	return false
+}
+
+// onRingLocked reports whether shard is on the current ring. Callers must
+// hold a.mu.
+func (a *Assigner) onRingLocked(shard string) bool {
+	shards := a.ring.shards
+	i := sort.SearchStrings(shards, shard)
+	return i < len(shards) && shards[i] == shard
+}
+
+// VerifyAssignment checks that record is signed by globalKey, the global
+// tier's PEM identity key, and names nodeKey, the PEM key of the node
+// presenting it.
+func VerifyAssignment(record protocol.ShardAssignment, globalKey, nodeKey []byte) error {
+	if record.NodeID == "" || record.ShardID == "" || len(record.Signature) == 0 {
+		return fmt.Errorf("%w: node, shard, and signature are required", ErrInvalidAssignment)
+	}
+	fingerprint, err := crypto.PublicKeyFingerprint(nodeKey)
+	if err != nil {
+		return fmt.Errorf("%w: node key: %v", ErrInvalidAssignment, err)
+	}
+	if fingerprint != record.NodeFingerprint {
+		return fmt.Errorf("%w: node %s assignment is for key %s, not %s", ErrInvalidAssignment, record.NodeID, record.NodeFingerprint, fingerprint)
+	}
+	signer, err := crypto.PublicKeyFingerprint(globalKey)
+	if err != nil {
+		return fmt.Errorf("%w: global key: %v", ErrInvalidAssignment, err)
+	}
+	if signer != record.SignerFingerprint {
+		return fmt.Errorf("%w: node %s assignment signed by %s, not the global tier", ErrInvalidAssignment, record.NodeID, record.SignerFingerprint)
+	}
+	algorithm, err := crypto.PublicKeyAlgorithm(globalKey)
+	if err != nil {
+		return fmt.Errorf("%w: global key: %v", ErrInvalidAssignment, err)
+	}
+	if err := protocol.CheckAlgorithm(record.SignatureAlgorithm, algorithm); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAssignment, err)
+	}
+	digest := record.SigningDigest()
+	if err := crypto.VerifyWithPublicKey(globalKey, digest[:], record.Signature); err != nil {
+		return fmt.Errorf("%w: node %s signature: %v", ErrInvalidAssignment, record.NodeID, err)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,21 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package sharding
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by shard assignment. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrInvalidConfig means an assigner configuration is out of range.
+	// Not retryable with the same configuration.
+	ErrInvalidConfig = errors.New("invalid shard assignment config")
+	// ErrUnknownNode means the node has not been admitted to any shard.
+	// Retryable once the node is admitted.
+	ErrUnknownNode = errors.New("node has no shard assignment")
+	// ErrInvalidAssignment means a shard assignment record is malformed,
+	// not for the presenting node's key, or not signed by the trusted
+	// global tier. Not retryable with the same record.
+	ErrInvalidAssignment = errors.New("invalid shard assignment")
+)
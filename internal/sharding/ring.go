@@ -0,0 +1,101 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package sharding
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// Ring is a consistent-hash ring of shards. Each shard owns virtualNodes
+// points on the ring, and a node belongs to the shard owning the first
+// point at or after the hash of its key fingerprint. Adding or removing a
+// node thus moves no other node, and adding a shard to n moves only about
+// 1/(n+1) of them, all into the new shard.
+type Ring struct {
+	shards []string
+	points []ringPoint
+}
+
+type ringPoint struct {
+	hash  uint64
+	shard string
+}
+
+// NewRing builds the ring of shards with virtualNodes points each.
+func NewRing(shards []string, virtualNodes int) (*Ring, error) {
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("%w: a ring needs at least one shard", ErrInvalidConfig)
+	}
+	if virtualNodes < 1 {
+		return nil, fmt.Errorf("%w: each shard needs at least one virtual node, got %d", ErrInvalidConfig, virtualNodes)
+	}
+	r := &Ring{shards: append([]string(nil), shards...), points: make([]ringPoint, 0, len(shards)*virtualNodes)}
+	sort.Strings(r.shards)
+	for i, shard := range r.shards {
+		if shard == "" || (i > 0 && r.shards[i-1] == shard) {
+			return nil, fmt.Errorf("%w: shard IDs must be unique and non-empty, got %q", ErrInvalidConfig, shard)
+		}
+		for v := 0; v < virtualNodes; v++ {
+			r.points = append(r.points, ringPoint{hash: ringHash(fmt.Sprintf("shard/%s/%d", shard, v)), shard: shard})
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool {
+		if r.points[i].hash != r.points[j].hash {
+			return r.points[i].hash < r.points[j].hash
+		}
+		return r.points[i].shard < r.points[j].shard
+	})
+	return r, nil
+}
+
+// Shards returns the ring's shard IDs, sorted.
+func (r *Ring) Shards() []string {
+	return append([]string(nil), r.shards...)
+}
+
+// Locate returns the shard a node with the given key fingerprint belongs
+// to.
+func (r *Ring) Locate(fingerprint string) string {
+	return r.points[r.start(fingerprint)].shard
+}
+
+// LocateWhere returns the first shard accept takes, walking the ring from
+// where Locate would stop, or Locate's shard if accept takes none.
+func (r *Ring) LocateWhere(fingerprint string, accept func(shard string) bool) string {
+	start := r.start(fingerprint)
+	for step := 0; step < len(r.points); step++ {
+		if shard := r.points[(start+step)%len(r.points)].shard; accept(shard) {
+			return shard
+		}
+	}
+	return r.points[start].shard
+}
+
+// start is the index of the first ring point at or after the hash of
+// fingerprint, wrapping around.
+func (r *Ring) start(fingerprint string) int {
+	hash := ringHash("node/" + fingerprint)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= hash })
+	if i == len(r.points) {
+		i = 0
+	}
+	return i
+}
+
+// ShardIDs names count shards shard-000, shard-001, and so on.
+func ShardIDs(count int) []string {
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("shard-%03d", i)
+	}
+	return ids
+}
+
+func ringHash(s string) uint64 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint64(sum[:8])
+}
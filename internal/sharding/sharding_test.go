@@ -0,0 +1,301 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package sharding
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// nodeKeys returns the PEM identity keys of count nodes named node-000 on.
+func nodeKeys(t *testing.T, from, count int) map[string][]byte {
+	t.Helper()
+	keys := make(map[string][]byte, count)
+	for i := from; i < from+count; i++ {
+		identity, err := crypto.NewSecureChannel()
+		if err != nil {
+			t.Fatalf("identity: %v", err)
+		}
+		publicKey, err := identity.ExportPublicKey()
+		if err != nil {
+			t.Fatalf("export key: %v", err)
+		}
+		keys[fmt.Sprintf("node-%03d", i)] = publicKey
+	}
+	return keys
+}
+
+// bootstrap admits 200 nodes into 8 shards tolerating two faulty members
+// each, and closes the bootstrap with a first Rebalance.
+func bootstrap(t *testing.T) (*Assigner, map[string][]byte) {
+	t.Helper()
+	global, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("global identity: %v", err)
+	}
+	assigner, err := NewAssigner(Config{Shards: 8, VirtualNodes: 128, MaxMovedFraction: 0.05, FaultModel: faultmodel.Classic33, MaxFaulty: 2}, global)
+	if err != nil {
+		t.Fatalf("new assigner: %v", err)
+	}
+	keys := nodeKeys(t, 0, 200)
+	for nodeID, publicKey := range keys {
+		if _, err := assigner.Admit(nodeID, publicKey); err != nil {
+			t.Fatalf("admit %s: %v", nodeID, err)
+		}
+	}
+	if report, err := assigner.Rebalance(); err != nil || len(report.Moves) != 0 {
+		t.Fatalf("bootstrap rebalance: %+v, %v", report, err)
+	}
+	requireSafe(t, assigner, "bootstrap")
+	return assigner, keys
+}
+
+// requireSafe fails unless every shard with members satisfies n > 2f+1.
+func requireSafe(t *testing.T, assigner *Assigner, step string) {
+	t.Helper()
+	for _, shard := range assigner.Shards() {
+		if shard.Members > 0 && (!shard.Safe || shard.Members <= 2*2+1) {
+			t.Fatalf("%s: shard %s has %d members, below n > 2f+1 with f=2 (%+v)", step, shard.ShardID, shard.Members, assigner.Shards())
+		}
+	}
+}
+
+func shardsOf(assigner *Assigner, keys map[string][]byte) map[string]string {
+	shards := make(map[string]string, len(keys))
+	for nodeID := range keys {
+		if assignment, ok := assigner.Assignment(nodeID); ok {
+			shards[nodeID] = assignment.ShardID
+		}
+	}
+	return shards
+}
+
+func TestJoiningAndLeavingNodesMoveNoOneElse(t *testing.T) {
+	assigner, keys := bootstrap(t)
+	if assigner.MinMembers() != 7 {
+		t.Fatalf("min members = %d, want 3f+1 = 7", assigner.MinMembers())
+	}
+	before := shardsOf(assigner, keys)
+
+	// 10% join: each lands where the ring places it, and nobody else
+	// moves.
+	joined := nodeKeys(t, 200, 20)
+	for nodeID, publicKey := range joined {
+		assignment, err := assigner.Admit(nodeID, publicKey)
+		if err != nil {
+			t.Fatalf("admit %s: %v", nodeID, err)
+		}
+		fingerprint, _ := crypto.PublicKeyFingerprint(publicKey)
+		if assignment.ShardID != assigner.ring.Locate(fingerprint) {
+			t.Fatalf("%s admitted into %s, not its ring shard", nodeID, assignment.ShardID)
+		}
+	}
+	if report, err := assigner.Rebalance(); err != nil || len(report.Moves) != 0 || report.Pending != 0 {
+		t.Fatalf("rebalance after joins: %+v, %v", report, err)
+	}
+	requireSafe(t, assigner, "joins")
+
+	// 10% of the original nodes leave.
+	left := 0
+	for nodeID := range keys {
+		if left == 20 {
+			break
+		}
+		if err := assigner.Remove(nodeID); err != nil {
+			t.Fatalf("remove %s: %v", nodeID, err)
+		}
+		delete(before, nodeID)
+		left++
+	}
+	if report, err := assigner.Rebalance(); err != nil || len(report.Moves) != 0 {
+		t.Fatalf("rebalance after departures: %+v, %v", report, err)
+	}
+	requireSafe(t, assigner, "departures")
+
+	for nodeID, shard := range shardsOf(assigner, keys) {
+		if _, stayed := before[nodeID]; stayed && before[nodeID] != shard {
+			t.Fatalf("%s moved from %s to %s on an unrelated membership change", nodeID, before[nodeID], shard)
+		}
+	}
+	if err := assigner.Remove("node-999"); !errors.Is(err, ErrUnknownNode) {
+		t.Fatalf("remove unknown node: expected ErrUnknownNode, got %v", err)
+	}
+}
+
+func TestShardCountChangesAreStagedAndStaySafe(t *testing.T) {
+	assigner, keys := bootstrap(t)
+	before := shardsOf(assigner, keys)
+
+	// Growing from 8 to 10 shards should move about 2/10 of the nodes, and
+	// only into the new shards; modulo placement would move about 4/5.
+	if err := assigner.SetShardCount(10); err != nil {
+		t.Fatalf("set shard count: %v", err)
+	}
+	minimum := 0
+	for nodeID, publicKey := range keys {
+		fingerprint, _ := crypto.PublicKeyFingerprint(publicKey)
+		if assigner.ring.Locate(fingerprint) != before[nodeID] {
+			minimum++
+		}
+	}
+	if minimum > 60 {
+		t.Fatalf("ring reassigns %d of 200 nodes for two new shards", minimum)
+	}
+
+	moved := converge(t, assigner, "grow")
+	if moved != minimum {
+		t.Fatalf("moved %d nodes, want exactly the %d the ring reassigns", moved, minimum)
+	}
+	for nodeID, shard := range shardsOf(assigner, keys) {
+		if shard != before[nodeID] && shard != "shard-008" && shard != "shard-009" {
+			t.Fatalf("%s moved between old shards, %s to %s", nodeID, before[nodeID], shard)
+		}
+	}
+
+	// Shrinking back drains the two shards, the last seven members of each
+	// together, and returns every node home.
+	if err := assigner.SetShardCount(8); err != nil {
+		t.Fatalf("set shard count: %v", err)
+	}
+	if moved := converge(t, assigner, "shrink"); moved != minimum {
+		t.Fatalf("moved %d nodes back, want %d", moved, minimum)
+	}
+	for nodeID, shard := range shardsOf(assigner, keys) {
+		if shard != before[nodeID] {
+			t.Fatalf("%s ended in %s, not %s", nodeID, shard, before[nodeID])
+		}
+	}
+	if shards := assigner.Shards(); len(shards) != 8 {
+		t.Fatalf("retired shards still listed: %+v", shards)
+	}
+}
+
+// converge rebalances until no node is pending, checking the move budget
+// and shard safety every epoch, and returns how many nodes moved.
+func converge(t *testing.T, assigner *Assigner, step string) int {
+	t.Helper()
+	moved := 0
+	for epoch := 0; epoch < 50; epoch++ {
+		report, err := assigner.Rebalance()
+		if err != nil {
+			t.Fatalf("%s rebalance: %v", step, err)
+		}
+		if len(report.Moves) > 10 || len(report.Assignments) != len(report.Moves) {
+			t.Fatalf("%s epoch %d moved %d nodes with %d assignments, budget 10", step, report.Epoch, len(report.Moves), len(report.Assignments))
+		}
+		for _, assignment := range report.Assignments {
+			if assignment.Epoch != report.Epoch {
+				t.Fatalf("%s: assignment for epoch %d in epoch %d", step, assignment.Epoch, report.Epoch)
+			}
+		}
+		requireSafe(t, assigner, fmt.Sprintf("%s epoch %d", step, report.Epoch))
+		moved += len(report.Moves)
+		if report.Pending == 0 {
+			return moved
+		}
+	}
+	t.Fatalf("%s did not converge", step)
+	return 0
+}
+
+func TestAssignmentsProveShardMembership(t *testing.T) {
+	assigner, keys := bootstrap(t)
+	assignment, ok := assigner.Assignment("node-007")
+	if !ok {
+		t.Fatal("no assignment for an admitted node")
+	}
+	if err := VerifyAssignment(assignment, assigner.PublicKey(), keys["node-007"]); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if again, err := assigner.Admit("node-007", keys["node-007"]); err != nil || again.ShardID != assignment.ShardID {
+		t.Fatalf("readmission changed the assignment: %+v, %v", again, err)
+	}
+	members := assigner.Members(assignment.ShardID)
+	found := false
+	for _, member := range members {
+		found = found || member.NodeID == "node-007"
+	}
+	if !found {
+		t.Fatalf("node-007 not among shard %s members", assignment.ShardID)
+	}
+
+	other, _ := crypto.NewSecureChannel()
+	otherKey, _ := other.ExportPublicKey()
+	moved := assignment
+	moved.ShardID = "shard-999"
+	for name, check := range map[string]func() error{
+		"another node's key": func() error { return VerifyAssignment(assignment, assigner.PublicKey(), keys["node-008"]) },
+		"untrusted signer":   func() error { return VerifyAssignment(assignment, otherKey, keys["node-007"]) },
+		"altered shard":      func() error { return VerifyAssignment(moved, assigner.PublicKey(), keys["node-007"]) },
+	} {
+		if err := check(); !errors.Is(err, ErrInvalidAssignment) {
+			t.Fatalf("%s: expected ErrInvalidAssignment, got %v", name, err)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+	for name, mutate := range map[string]func(*Config){
+		"no shards":      func(c *Config) { c.Shards = 0 },
+		"no ring points": func(c *Config) { c.VirtualNodes = 0 },
+		"no moves":       func(c *Config) { c.MaxMovedFraction = 0 },
+		"negative f":     func(c *Config) { c.MaxFaulty = -1 },
+		"unknown model":  func(c *Config) { c.FaultModel = "majority" },
+	} {
+		cfg := DefaultConfig()
+		mutate(&cfg)
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("%s: expected ErrInvalidConfig, got %v", name, err)
+		}
+	}
+}
+
+func TestMembershipHookSeesEveryChange(t *testing.T) {
+	assigner, keys := bootstrap(t)
+	changes := make(map[string][]protocol.ShardMember)
+	assigner.SetMembershipHook(func(shardID string, members []protocol.ShardMember) {
+		changes[shardID] = members
+	})
+
+	joined := nodeKeys(t, 200, 1)
+	var shard string
+	for nodeID, publicKey := range joined {
+		assignment, err := assigner.Admit(nodeID, publicKey)
+		if err != nil {
+			t.Fatalf("admit %s: %v", nodeID, err)
+		}
+		shard = assignment.ShardID
+		if _, err := assigner.Admit(nodeID, publicKey); err != nil {
+			t.Fatalf("readmit %s: %v", nodeID, err)
+		}
+	}
+	if len(changes) != 1 || len(changes[shard]) != len(assigner.Members(shard)) {
+		t.Fatalf("admission reported %d shards, want only %s with its members", len(changes), shard)
+	}
+
+	clear(changes)
+	for nodeID := range keys {
+		leaving, _ := assigner.Assignment(nodeID)
+		if err := assigner.Remove(nodeID); err != nil {
+			t.Fatalf("remove %s: %v", nodeID, err)
+		}
+		for _, member := range changes[leaving.ShardID] {
+			if member.NodeID == nodeID {
+				t.Fatalf("departed %s still listed in %s", nodeID, leaving.ShardID)
+			}
+		}
+		if len(changes) != 1 {
+			t.Fatalf("departure reported %d shards, want 1", len(changes))
+		}
+		break
+	}
+}
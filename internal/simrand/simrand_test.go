@@ -0,0 +1,32 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package simrand
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDeriveIgnoresParentConsumption(t *testing.T) {
+	fresh := New(659)
+	used := New(659)
+	for i := 0; i < 100; i++ {
+		used.Float64()
+	}
+	a, b := make([]byte, 32), make([]byte, 32)
+	_, _ = fresh.Derive("gossip").Read(a)
+	_, _ = used.Derive("gossip").Read(b)
+	if !bytes.Equal(a, b) {
+		t.Fatal("derived stream depends on how much of the parent was consumed")
+	}
+
+	other := make([]byte, 32)
+	_, _ = fresh.Derive("privacy").Read(other)
+	if bytes.Equal(a, other) {
+		t.Fatal("components share a stream")
+	}
+	if fresh.Derive("gossip").Seed() == New(660).Derive("gossip").Seed() {
+		t.Fatal("different seeds derived the same component seed")
+	}
+}
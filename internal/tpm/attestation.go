@@ -12,6 +12,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/profiling"
 )
 
 var (
@@ -41,6 +43,18 @@ type AttestationManager struct {
 	latencySpikeUs   time.Duration
 	spikeCount       uint64
 	enabled          bool
+	// latest is each node's most recent report, served while the TPM is
+	// degraded until the attestation cache TTL runs out.
+	latest map[string]*AttestationReport
+	// observer is told of every report generated from a fresh quote.
+	observer func(report *AttestationReport)
+
+	hwMu        sync.Mutex
+	backend     Backend
+	policy      DegradationPolicy
+	hw          hardwareHealth
+	hwListeners []HardwareStateListener
+	now         func() time.Time
 }
 
 // AttestationCache stores recently verified attestations
@@ -67,6 +81,11 @@ func NewAttestationManager(maxReports int, cacheTTL time.Duration, enabled bool)
 		},
 		latencySpikeUs: 200 * time.Microsecond,
 		enabled:        enabled,
+		latest:         make(map[string]*AttestationReport),
+		backend:        simulatorBackend{},
+		policy:         DefaultDegradationPolicy(),
+		hw:             hardwareHealth{state: HardwareNormal},
+		now:            time.Now,
 	}
 }
 
@@ -78,6 +97,15 @@ func (am *AttestationManager) SetLatencySpikeThreshold(threshold time.Duration)
 	am.latencySpikeUs = threshold
 }
 
+// SetAttestationObserver registers a callback receiving every report
+// generated from a fresh TPM quote, for health tracking. Reports served
+// from the last one while the TPM is degraded are not passed to it.
+func (am *AttestationManager) SetAttestationObserver(observer func(report *AttestationReport)) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.observer = observer
+}
+
 // LatencySpikeCount returns the number of verification latency spikes observed.
 func (am *AttestationManager) LatencySpikeCount() uint64 {
 	am.mu.RLock()
@@ -85,7 +113,9 @@ func (am *AttestationManager) LatencySpikeCount() uint64 {
 	return am.spikeCount
 }
 
-// GenerateAttestation creates a new TPM attestation report
+// GenerateAttestation creates a new TPM attestation report. While the TPM
+// is degraded it returns nodeID's last report if that is still within the
+// attestation cache TTL, and fails with ErrHardwareUnavailable otherwise.
 func (am *AttestationManager) GenerateAttestation(nodeID string, nonce []byte) (*AttestationReport, error) {
 	if !am.enabled {
 		return nil, fmt.Errorf("TPM attestation is disabled")
@@ -100,16 +130,14 @@ func (am *AttestationManager) GenerateAttestation(nodeID string, nonce []byte) (
 		}
 	}
 
-	// Generate TPM quote (this would interface with actual TPM hardware)
-	quote, err := generateTPMQuoteForNode(nodeID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate TPM quote: %w", err)
+	if am.degraded() {
+		return am.unexpiredReport(nodeID)
 	}
 
-	// Read PCR values (Platform Configuration Registers)
-	pcrValues, err := readPCRValues()
+	// Generate TPM quote and read PCR values (Platform Configuration Registers)
+	quote, pcrValues, err := am.quote(nodeID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read PCR values: %w", err)
+		return nil, err
 	}
 
 	// Create attestation report
@@ -136,8 +164,25 @@ func (am *AttestationManager) GenerateAttestation(nodeID string, nonce []byte) (
 		am.evictOldestReport()
 	}
 	am.reports[report.AttestationID] = report
+	am.latest[nodeID] = report
+	observer := am.observer
 	am.mu.Unlock()
 
+	if observer != nil {
+		observer(report)
+	}
+	return report, nil
+}
+
+// unexpiredReport returns nodeID's last report if it is within the
+// attestation cache TTL of when it was generated.
+func (am *AttestationManager) unexpiredReport(nodeID string) (*AttestationReport, error) {
+	am.mu.RLock()
+	report, ok := am.latest[nodeID]
+	am.mu.RUnlock()
+	if !ok || !am.now().Before(report.Timestamp.Add(am.attestationCache.ttl)) {
+		return nil, fmt.Errorf("%w: no unexpired quote for node %s", ErrHardwareUnavailable, nodeID)
+	}
 	return report, nil
 }
 
@@ -170,6 +215,7 @@ func (am *AttestationManager) VerifyAttestation(report *AttestationReport) (bool
 			return true, nil
 		}
 	}
+	defer func() { profiling.Observe(profiling.PhaseAttestationVerify, time.Since(verifyStart)) }()
 
 	// Verify timestamp is recent (within 5 minutes)
 	if time.Since(report.Timestamp) > 5*time.Minute {
@@ -0,0 +1,118 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package tpm
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// ToEnvelope converts a report to its wire format.
+func ToEnvelope(report *AttestationReport) (*protocol.AttestationEnvelope, error) {
+	if report == nil {
+		return nil, fmt.Errorf("attestation report is nil")
+	}
+	if len(report.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size: %d", len(report.PublicKey))
+	}
+	der, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(report.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("encode attestation key: %w", err)
+	}
+
+	pcrs := make(map[string]string, len(report.PCRValues))
+	for index, value := range report.PCRValues {
+		pcrs[strconv.Itoa(index)] = hex.EncodeToString(value)
+	}
+	envelope := &protocol.AttestationEnvelope{
+		Version:       protocol.AttestationEnvelopeVersion,
+		NodeID:        report.NodeID,
+		AttestationID: report.AttestationID,
+		Timestamp:     report.Timestamp.UTC(),
+		Quote:         base64.StdEncoding.EncodeToString(report.Quote),
+		PCRs:          pcrs,
+		Nonce:         base64.StdEncoding.EncodeToString(report.Nonce),
+		Signature:     base64.StdEncoding.EncodeToString(report.Signature),
+		AKPublicKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+	if err := envelope.Validate(); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// FromEnvelope converts a validated envelope back to a report.
+func FromEnvelope(envelope *protocol.AttestationEnvelope) (*AttestationReport, error) {
+	if envelope == nil {
+		return nil, fmt.Errorf("attestation envelope is nil")
+	}
+	if err := envelope.Validate(); err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode([]byte(envelope.AKPublicKey))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("attestation key is not a PEM public key")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("decode attestation key: %w", err)
+	}
+	publicKey, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("attestation key is %T, want ed25519", parsed)
+	}
+
+	// Validate has already checked every encoded field decodes.
+	pcrs := make(map[int][]byte, len(envelope.PCRs))
+	for index, value := range envelope.PCRs {
+		i, _ := strconv.Atoi(index)
+		pcrs[i], _ = hex.DecodeString(value)
+	}
+	quote, _ := base64.StdEncoding.DecodeString(envelope.Quote)
+	nonce, _ := base64.StdEncoding.DecodeString(envelope.Nonce)
+	signature, _ := base64.StdEncoding.DecodeString(envelope.Signature)
+
+	return &AttestationReport{
+		NodeID:        envelope.NodeID,
+		Timestamp:     envelope.Timestamp,
+		Quote:         quote,
+		PCRValues:     pcrs,
+		Nonce:         nonce,
+		Signature:     signature,
+		PublicKey:     append([]byte(nil), publicKey...),
+		AttestationID: envelope.AttestationID,
+	}, nil
+}
+
+// VerifyEnvelope converts envelope and verifies the resulting report. The
+// signature is checked before the verification cache is consulted, because
+// the cache is keyed by an attestation ID a remote sender chooses.
+func (am *AttestationManager) VerifyEnvelope(envelope *protocol.AttestationEnvelope) error {
+	report, err := FromEnvelope(envelope)
+	if err != nil {
+		return err
+	}
+	if report.AttestationID != am.generateAttestationID(report) {
+		return fmt.Errorf("attestation id does not match report contents")
+	}
+	if am.enabled && !ed25519.Verify(ed25519.PublicKey(report.PublicKey), buildAttestationPayload(report), report.Signature) {
+		return fmt.Errorf("invalid attestation signature")
+	}
+	ok, err := am.VerifyAttestation(report)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("attestation for %s did not verify", envelope.NodeID)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package tpm
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the attestation manager. Match them
+// with errors.Is; never compare error strings.
+var (
+	// ErrHardwareUnavailable means the manager is degraded after repeated
+	// TPM errors and holds no quote for the node still within its TTL.
+	// Retryable once the device is reopened.
+	ErrHardwareUnavailable = errors.New("TPM hardware unavailable")
+	// ErrInvalidDegradationPolicy means a degradation policy's threshold,
+	// backoff, or participation is out of range. Not retryable.
+	ErrInvalidDegradationPolicy = errors.New("invalid TPM degradation policy")
+)
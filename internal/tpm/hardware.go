@@ -0,0 +1,319 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package tpm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Backend is the TPM device an AttestationManager quotes through. Every
+// error it returns is counted as a hardware error.
+type Backend interface {
+	// Open (re)opens the device, such as /dev/tpmrm0.
+	Open() error
+	// Quote produces a quote bound to nodeID.
+	Quote(nodeID string) ([]byte, error)
+	// ReadPCRs reads the platform configuration registers.
+	ReadPCRs() (map[int][]byte, error)
+}
+
+// simulatorBackend is the software TPM every manager starts with.
+type simulatorBackend struct{}
+
+func (simulatorBackend) Open() error { return nil }
+
+func (simulatorBackend) Quote(nodeID string) ([]byte, error) {
+	return generateTPMQuoteForNode(nodeID)
+}
+
+func (simulatorBackend) ReadPCRs() (map[int][]byte, error) { return readPCRValues() }
+
+// HardwareState is whether the manager's TPM is answering.
+type HardwareState string
+
+const (
+	// HardwareNormal means quotes come from the device.
+	HardwareNormal HardwareState = "normal"
+	// HardwareDegraded means the device failed ErrorThreshold times in a
+	// row and is being reopened with backoff.
+	HardwareDegraded HardwareState = "degraded"
+)
+
+// Participation is how a node takes part in rounds given its TPM state.
+type Participation string
+
+const (
+	// ParticipationFull is a node with a working TPM.
+	ParticipationFull Participation = "full"
+	// ParticipationProbation is a degraded node that keeps training with
+	// the reduced trust of a probationary node.
+	ParticipationProbation Participation = "probation"
+	// ParticipationPaused is a degraded node that sits rounds out until
+	// its TPM recovers.
+	ParticipationPaused Participation = "paused"
+)
+
+// DegradationPolicy sets when the manager degrades, how it retries the
+// device, and how the node participates meanwhile.
+type DegradationPolicy struct {
+	// ErrorThreshold is how many consecutive hardware errors degrade the
+	// manager.
+	ErrorThreshold int
+	// ReopenBackoff is the wait before the first reopen; each failed
+	// reopen doubles it, up to MaxReopenBackoff.
+	ReopenBackoff    time.Duration
+	MaxReopenBackoff time.Duration
+	// Degraded is ParticipationProbation or ParticipationPaused.
+	Degraded Participation
+}
+
+// DefaultDegradationPolicy returns the policy of NewAttestationManager:
+// degrade after three consecutive errors, reopen after 5s backing off to
+// 5m, and keep participating on probation.
+func DefaultDegradationPolicy() DegradationPolicy {
+	return DegradationPolicy{
+		ErrorThreshold:   3,
+		ReopenBackoff:    5 * time.Second,
+		MaxReopenBackoff: 5 * time.Minute,
+		Degraded:         ParticipationProbation,
+	}
+}
+
+// Validate checks that policy degrades on at least one error, backs off
+// for a positive time, and names a degraded participation.
+func (p DegradationPolicy) Validate() error {
+	if p.ErrorThreshold < 1 {
+		return fmt.Errorf("%w: error threshold must be at least 1, got %d", ErrInvalidDegradationPolicy, p.ErrorThreshold)
+	}
+	if p.ReopenBackoff <= 0 || p.MaxReopenBackoff < p.ReopenBackoff {
+		return fmt.Errorf("%w: reopen backoff %s up to %s", ErrInvalidDegradationPolicy, p.ReopenBackoff, p.MaxReopenBackoff)
+	}
+	if p.Degraded != ParticipationProbation && p.Degraded != ParticipationPaused {
+		return fmt.Errorf("%w: degraded participation %q", ErrInvalidDegradationPolicy, p.Degraded)
+	}
+	return nil
+}
+
+// HardwareHealth is a snapshot of the manager's TPM health.
+type HardwareHealth struct {
+	State             HardwareState `json:"state"`
+	Participation     Participation `json:"participation"`
+	ConsecutiveErrors int           `json:"consecutive_errors"`
+	LastError         string        `json:"last_error,omitempty"`
+	// DegradedSince and NextReopen are set while degraded.
+	DegradedSince time.Time `json:"degraded_since,omitempty"`
+	NextReopen    time.Time `json:"next_reopen,omitempty"`
+	// ReopenAttempts counts reopens since the manager last degraded.
+	ReopenAttempts int `json:"reopen_attempts"`
+}
+
+// HardwareStateListener is called after the manager degrades or recovers.
+type HardwareStateListener func(HardwareHealth)
+
+// hardwareHealth is the manager's mutable TPM health, guarded by hwMu.
+type hardwareHealth struct {
+	state          HardwareState
+	consecutive    int
+	lastErr        string
+	degradedSince  time.Time
+	nextReopen     time.Time
+	backoff        time.Duration
+	reopenAttempts int
+}
+
+// SetBackend replaces the TPM device the manager quotes through.
+func (am *AttestationManager) SetBackend(backend Backend) {
+	am.hwMu.Lock()
+	defer am.hwMu.Unlock()
+	am.backend = backend
+}
+
+// SetDegradationPolicy replaces the manager's degradation policy. It fails
+// with ErrInvalidDegradationPolicy, keeping the old one, if policy does
+// not validate.
+func (am *AttestationManager) SetDegradationPolicy(policy DegradationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	am.hwMu.Lock()
+	defer am.hwMu.Unlock()
+	am.policy = policy
+	return nil
+}
+
+// AddHardwareStateListener registers a callback for degradation and
+// recovery.
+func (am *AttestationManager) AddHardwareStateListener(listener HardwareStateListener) {
+	am.hwMu.Lock()
+	defer am.hwMu.Unlock()
+	am.hwListeners = append(am.hwListeners, listener)
+}
+
+// HardwareHealth returns the manager's current TPM health.
+func (am *AttestationManager) HardwareHealth() HardwareHealth {
+	am.hwMu.Lock()
+	defer am.hwMu.Unlock()
+	return am.hardwareHealthLocked()
+}
+
+// Participation returns how the node should take part in rounds: fully
+// with a working TPM, otherwise as the degradation policy decides.
+func (am *AttestationManager) Participation() Participation {
+	return am.HardwareHealth().Participation
+}
+
+// StampHeartbeat marks a heartbeat sent while the TPM is degraded, so the
+// aggregator can hold the node to probation, and reports a paused node as
+// such.
+func (am *AttestationManager) StampHeartbeat(update *protocol.StatusUpdate) {
+	health := am.HardwareHealth()
+	if health.State != HardwareDegraded {
+		return
+	}
+	update.AttestationDegraded = true
+	if health.Participation == ParticipationPaused {
+		update.Status = "paused"
+	}
+}
+
+// Maintain reopens the device of a degraded manager once its backoff has
+// elapsed. On success the manager returns to normal and re-attests every
+// node it has attested before; on failure the backoff doubles. It returns
+// whether the manager recovered.
+func (am *AttestationManager) Maintain() bool {
+	am.hwMu.Lock()
+	now := am.now()
+	if am.hw.state != HardwareDegraded || now.Before(am.hw.nextReopen) {
+		am.hwMu.Unlock()
+		return false
+	}
+	am.hw.reopenAttempts++
+	if err := am.backend.Open(); err != nil {
+		am.hw.lastErr = err.Error()
+		am.hw.backoff = min(2*am.hw.backoff, am.policy.MaxReopenBackoff)
+		am.hw.nextReopen = now.Add(am.hw.backoff)
+		am.hwMu.Unlock()
+		observeReopen(false)
+		return false
+	}
+	am.hw = hardwareHealth{state: HardwareNormal, reopenAttempts: am.hw.reopenAttempts}
+	health := am.hardwareHealthLocked()
+	listeners := append([]HardwareStateListener(nil), am.hwListeners...)
+	am.hwMu.Unlock()
+	observeReopen(true)
+	observeHardwareDegraded(false)
+
+	for _, listener := range listeners {
+		listener(health)
+	}
+	am.reattest()
+	return true
+}
+
+// Run calls Maintain every interval until ctx is done.
+func (am *AttestationManager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			am.Maintain()
+		}
+	}
+}
+
+// reattest regenerates the attestation of every node the manager last
+// attested, so none waits out a pre-degradation report after recovery.
+func (am *AttestationManager) reattest() {
+	am.mu.RLock()
+	nodeIDs := make([]string, 0, len(am.latest))
+	for nodeID := range am.latest {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	am.mu.RUnlock()
+	sort.Strings(nodeIDs)
+	for _, nodeID := range nodeIDs {
+		_, _ = am.GenerateAttestation(nodeID, nil)
+	}
+}
+
+// quote reads a quote and PCRs from the backend, counting the outcome
+// toward the manager's health.
+func (am *AttestationManager) quote(nodeID string) ([]byte, map[int][]byte, error) {
+	am.hwMu.Lock()
+	backend := am.backend
+	am.hwMu.Unlock()
+
+	quote, err := backend.Quote(nodeID)
+	if err != nil {
+		am.recordHardwareError(err)
+		return nil, nil, fmt.Errorf("failed to generate TPM quote: %w", err)
+	}
+	pcrValues, err := backend.ReadPCRs()
+	if err != nil {
+		am.recordHardwareError(err)
+		return nil, nil, fmt.Errorf("failed to read PCR values: %w", err)
+	}
+	am.hwMu.Lock()
+	if am.hw.state == HardwareNormal {
+		am.hw.consecutive = 0
+	}
+	am.hwMu.Unlock()
+	return quote, pcrValues, nil
+}
+
+func (am *AttestationManager) recordHardwareError(err error) {
+	observeHardwareError()
+	am.hwMu.Lock()
+	am.hw.consecutive++
+	am.hw.lastErr = err.Error()
+	if am.hw.state == HardwareDegraded || am.hw.consecutive < am.policy.ErrorThreshold {
+		am.hwMu.Unlock()
+		return
+	}
+	now := am.now()
+	am.hw.state = HardwareDegraded
+	am.hw.degradedSince = now
+	am.hw.reopenAttempts = 0
+	am.hw.backoff = am.policy.ReopenBackoff
+	am.hw.nextReopen = now.Add(am.hw.backoff)
+	health := am.hardwareHealthLocked()
+	listeners := append([]HardwareStateListener(nil), am.hwListeners...)
+	am.hwMu.Unlock()
+	observeHardwareDegraded(true)
+
+	for _, listener := range listeners {
+		listener(health)
+	}
+}
+
+// degraded reports whether the manager is degraded.
+func (am *AttestationManager) degraded() bool {
+	am.hwMu.Lock()
+	defer am.hwMu.Unlock()
+	return am.hw.state == HardwareDegraded
+}
+
+func (am *AttestationManager) hardwareHealthLocked() HardwareHealth {
+	health := HardwareHealth{
+		State:             am.hw.state,
+		Participation:     ParticipationFull,
+		ConsecutiveErrors: am.hw.consecutive,
+		LastError:         am.hw.lastErr,
+		ReopenAttempts:    am.hw.reopenAttempts,
+	}
+	if am.hw.state == HardwareDegraded {
+		health.Participation = am.policy.Degraded
+		health.DegradedSince = am.hw.degradedSince
+		health.NextReopen = am.hw.nextReopen
+	}
+	return health
+}
@@ -0,0 +1,181 @@
+package tpm
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// flakyBackend is the simulator TPM with a device that can vanish.
+type flakyBackend struct {
+	mu      sync.Mutex
+	present bool
+	quotes  int
+	opens   int
+}
+
+var errNoDevice = errors.New("open /dev/tpmrm0: no such device")
+
+func (b *flakyBackend) setPresent(present bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.present = present
+}
+
+func (b *flakyBackend) Open() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.opens++
+	if !b.present {
+		return errNoDevice
+	}
+	return nil
+}
+
+func (b *flakyBackend) Quote(nodeID string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.quotes++
+	if !b.present {
+		return nil, errNoDevice
+	}
+	return generateTPMQuoteForNode(nodeID)
+}
+
+func (b *flakyBackend) ReadPCRs() (map[int][]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.present {
+		return nil, errNoDevice
+	}
+	return readPCRValues()
+}
+
+func flakyManager(t *testing.T, policy DegradationPolicy) (*AttestationManager, *flakyBackend, *time.Time) {
+	t.Helper()
+	manager := NewAttestationManager(16, 30*time.Second, true)
+	backend := &flakyBackend{present: true}
+	manager.SetBackend(backend)
+	if err := manager.SetDegradationPolicy(policy); err != nil {
+		t.Fatalf("set policy: %v", err)
+	}
+	now := time.Now()
+	manager.now = func() time.Time { return now }
+	return manager, backend, &now
+}
+
+func TestManagerDegradesAndRecoversWithTheDevice(t *testing.T) {
+	policy := DegradationPolicy{ErrorThreshold: 3, ReopenBackoff: time.Second, MaxReopenBackoff: 4 * time.Second, Degraded: ParticipationProbation}
+	manager, backend, now := flakyManager(t, policy)
+	var transitions []HardwareState
+	manager.AddHardwareStateListener(func(health HardwareHealth) { transitions = append(transitions, health.State) })
+
+	first, err := manager.GenerateAttestation("edge-1", nil)
+	if err != nil {
+		t.Fatalf("attest with the device present: %v", err)
+	}
+
+	backend.setPresent(false)
+	for i := 1; i <= 2; i++ {
+		if _, err := manager.GenerateAttestation("edge-1", nil); err == nil || errors.Is(err, ErrHardwareUnavailable) {
+			t.Fatalf("error %d: expected the device error, got %v", i, err)
+		}
+		if state := manager.HardwareHealth().State; state != HardwareNormal {
+			t.Fatalf("degraded after %d errors, threshold 3", i)
+		}
+	}
+	if _, err := manager.GenerateAttestation("edge-1", nil); err == nil {
+		t.Fatal("third error: expected the device error")
+	}
+	health := manager.HardwareHealth()
+	if health.State != HardwareDegraded || health.Participation != ParticipationProbation || health.ConsecutiveErrors != 3 {
+		t.Fatalf("after three errors: %+v", health)
+	}
+
+	// Degraded, the manager stops touching the device and serves the last
+	// report only until its original TTL runs out.
+	quotes := backend.quotes
+	if report, err := manager.GenerateAttestation("edge-1", nil); err != nil || report != first {
+		t.Fatalf("within TTL: expected the last report, got %v", err)
+	}
+	if _, err := manager.GenerateAttestation("edge-2", nil); !errors.Is(err, ErrHardwareUnavailable) {
+		t.Fatalf("node never attested: expected ErrHardwareUnavailable, got %v", err)
+	}
+	*now = first.Timestamp.Add(31 * time.Second)
+	if _, err := manager.GenerateAttestation("edge-1", nil); !errors.Is(err, ErrHardwareUnavailable) {
+		t.Fatalf("past TTL: expected ErrHardwareUnavailable, got %v", err)
+	}
+	if backend.quotes != quotes {
+		t.Fatalf("degraded manager quoted the device %d more times", backend.quotes-quotes)
+	}
+
+	// Reopens back off 1s, 2s, 4s, 4s while the device stays gone.
+	*now = manager.HardwareHealth().NextReopen.Add(-time.Millisecond)
+	if manager.Maintain() || backend.opens != 0 {
+		t.Fatal("reopened before the backoff elapsed")
+	}
+	*now = now.Add(time.Millisecond)
+	for i, wait := range []time.Duration{2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		if manager.Maintain() {
+			t.Fatalf("attempt %d recovered without a device", i+1)
+		}
+		if next := manager.HardwareHealth().NextReopen; !next.Equal(now.Add(wait)) {
+			t.Fatalf("attempt %d: next reopen in %s, want %s", i+1, next.Sub(*now), wait)
+		}
+		*now = now.Add(wait)
+	}
+
+	backend.setPresent(true)
+	if !manager.Maintain() {
+		t.Fatalf("device is back: %+v", manager.HardwareHealth())
+	}
+	if health := manager.HardwareHealth(); health.State != HardwareNormal || health.Participation != ParticipationFull || health.ConsecutiveErrors != 0 || health.ReopenAttempts != 4 {
+		t.Fatalf("after recovery: %+v", health)
+	}
+	manager.mu.RLock()
+	reattested := manager.latest["edge-1"]
+	manager.mu.RUnlock()
+	if reattested == first {
+		t.Fatal("recovery did not re-attest edge-1")
+	}
+	if len(transitions) != 2 || transitions[0] != HardwareDegraded || transitions[1] != HardwareNormal {
+		t.Fatalf("transitions = %v", transitions)
+	}
+}
+
+func TestDegradedParticipationFollowsPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		participation Participation
+		status        string
+	}{
+		{ParticipationProbation, "training"},
+		{ParticipationPaused, "paused"},
+	} {
+		policy := DegradationPolicy{ErrorThreshold: 1, ReopenBackoff: time.Second, MaxReopenBackoff: time.Second, Degraded: tc.participation}
+		manager, backend, _ := flakyManager(t, policy)
+
+		heartbeat := protocol.StatusUpdate{NodeID: "edge-1", Status: "training"}
+		manager.StampHeartbeat(&heartbeat)
+		if heartbeat.AttestationDegraded || manager.Participation() != ParticipationFull {
+			t.Fatalf("%s: healthy node stamped %+v", tc.participation, heartbeat)
+		}
+
+		backend.setPresent(false)
+		_, _ = manager.GenerateAttestation("edge-1", nil)
+		if got := manager.Participation(); got != tc.participation {
+			t.Fatalf("%s: participation %s", tc.participation, got)
+		}
+		manager.StampHeartbeat(&heartbeat)
+		if !heartbeat.AttestationDegraded || heartbeat.Status != tc.status {
+			t.Fatalf("%s: degraded heartbeat %+v", tc.participation, heartbeat)
+		}
+	}
+
+	manager := NewAttestationManager(1, time.Second, true)
+	if err := manager.SetDegradationPolicy(DegradationPolicy{ErrorThreshold: 1, ReopenBackoff: time.Second, MaxReopenBackoff: time.Second, Degraded: ParticipationFull}); !errors.Is(err, ErrInvalidDegradationPolicy) {
+		t.Fatalf("full participation while degraded: expected ErrInvalidDegradationPolicy, got %v", err)
+	}
+}
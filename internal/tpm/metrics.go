@@ -60,6 +60,28 @@ var (
 		},
 	)
 
+	tpmHardwareDegraded = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "mohawk_tpm_hardware_degraded",
+			Help: "1 while the TPM device is degraded after repeated hardware errors, else 0.",
+		},
+	)
+
+	tpmHardwareErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mohawk_tpm_hardware_errors_total",
+			Help: "Total number of TPM quote or PCR read errors.",
+		},
+	)
+
+	tpmReopenAttemptsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mohawk_tpm_reopen_attempts_total",
+			Help: "Total number of attempts to reopen a degraded TPM device by result.",
+		},
+		[]string{"result"},
+	)
+
 	quoteCacheHitsAtomic         atomic.Uint64
 	quoteCacheMissesAtomic       atomic.Uint64
 	attestationCacheHitsAtomic   atomic.Uint64
@@ -80,6 +102,9 @@ func init() {
 		tpmPrewarmRequestsTotal,
 		tpmPrewarmWarmedNodesTotal,
 		tpmNonceReplayRejectionsTotal,
+		tpmHardwareDegraded,
+		tpmHardwareErrorsTotal,
+		tpmReopenAttemptsTotal,
 	)
 }
 
@@ -165,3 +190,23 @@ func observeNonceReplayRejected() {
 	tpmNonceReplayRejectionsTotal.Inc()
 	nonceReplayRejectsAtomic.Add(1)
 }
+
+func observeHardwareError() {
+	tpmHardwareErrorsTotal.Inc()
+}
+
+func observeHardwareDegraded(degraded bool) {
+	if degraded {
+		tpmHardwareDegraded.Set(1)
+	} else {
+		tpmHardwareDegraded.Set(0)
+	}
+}
+
+func observeReopen(recovered bool) {
+	if recovered {
+		tpmReopenAttemptsTotal.WithLabelValues("recovered").Inc()
+	} else {
+		tpmReopenAttemptsTotal.WithLabelValues("failed").Inc()
+	}
+}
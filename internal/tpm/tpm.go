@@ -11,7 +11,8 @@ import (
 	"sync"
 	"time"
 
-	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/hva"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/profiling"
 )
 
 type CachedQuote struct {
@@ -72,7 +73,9 @@ func GetVerifiedQuote(nodeID string) ([]byte, error) {
 	observeQuoteCacheMiss()
 
 	// Fallback to the hardware call (identified as a major performance bottleneck).
+	quoteStart := time.Now()
 	quote, err := generateTPMQuoteForNode(nodeID)
+	profiling.Observe(profiling.PhaseTPMQuote, time.Since(quoteStart))
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate quote for node %s: %w", nodeID, err)
 	}
@@ -371,20 +374,20 @@ func buildQuotePayload(timestampPart int64, noncePart, entropyPart, nodeBinding,
 // Byzantine fault tolerance (f) per the Hierarchical Multi-Krum proof.
 // Reference: /proofs/bft_resilience.md
 func VerifyByzantineResilience(totalNodes int, maliciousNodes int) (bool, error) {
+	return VerifyByzantineResilienceUnder(faultmodel.Hierarchical55, totalNodes, maliciousNodes)
+}
+
+// VerifyByzantineResilienceUnder is VerifyByzantineResilience for the
+// threshold of model. Failures wrap faultmodel.ErrThresholdExceeded.
+func VerifyByzantineResilienceUnder(model faultmodel.Model, totalNodes int, maliciousNodes int) (bool, error) {
 	if totalNodes <= 0 {
 		return false, fmt.Errorf("total nodes must be positive")
 	}
 	if maliciousNodes < 0 || maliciousNodes > totalNodes {
 		return false, fmt.Errorf("malicious node count out of range")
 	}
-
-	maxByzantine := hva.MaximumByzantineNodes(totalNodes)
-	if maliciousNodes > maxByzantine {
-		return false, fmt.Errorf(
-			"security threshold violated: Theorem 1 allows at most %d Byzantine nodes out of %d at the 55.5%% honest boundary",
-			maxByzantine,
-			totalNodes,
-		)
+	if err := model.CheckFaulty(totalNodes, maliciousNodes); err != nil {
+		return false, fmt.Errorf("security threshold violated: %w", err)
 	}
 	return true, nil
 }
@@ -1,6 +1,7 @@
 package tpm
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/blockchain"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
 )
 
 func TestNewAttestationManager(t *testing.T) {
@@ -101,6 +104,32 @@ func TestByzantineVerification(t *testing.T) {
 	}
 }
 
+func TestShardAdmissionFollowsFaultModel(t *testing.T) {
+	cases := []struct {
+		model        faultmodel.Model
+		participants int
+		maxFaulty    int
+	}{
+		{faultmodel.Classic33, 4, 1},
+		{faultmodel.Classic33, 10, 3},
+		{faultmodel.Classic33, 31, 10},
+		{faultmodel.Hierarchical55, 4, 1},
+		{faultmodel.Hierarchical55, 10, 4},
+		{faultmodel.Hierarchical55, 31, 13},
+	}
+	for _, tc := range cases {
+		if err := VerifyShardIntegrityUnder(tc.model, tc.participants, tc.maxFaulty); err != nil {
+			t.Errorf("%s shard of %d with %d faulty: %v", tc.model, tc.participants, tc.maxFaulty, err)
+		}
+		if err := VerifyShardIntegrityUnder(tc.model, tc.participants, tc.maxFaulty+1); !errors.Is(err, faultmodel.ErrThresholdExceeded) {
+			t.Errorf("%s shard of %d with %d faulty: expected ErrThresholdExceeded, got %v", tc.model, tc.participants, tc.maxFaulty+1, err)
+		}
+		if ok, err := VerifyByzantineResilienceUnder(tc.model, tc.participants, tc.maxFaulty+1); ok || !errors.Is(err, faultmodel.ErrThresholdExceeded) {
+			t.Errorf("%s resilience of %d with %d faulty: ok=%v err=%v", tc.model, tc.participants, tc.maxFaulty+1, ok, err)
+		}
+	}
+}
+
 func TestPCRIntegrityChecks(t *testing.T) {
 	manager := NewAttestationManager(10, time.Minute, true)
 	nodeID := "test-node-004"
@@ -580,6 +609,8 @@ func TestTPMMetricsRegistered(t *testing.T) {
 		"mohawk_tpm_prewarm_requests_total":        false,
 		"mohawk_tpm_prewarm_warmed_nodes_total":    false,
 		"mohawk_tpm_nonce_replay_rejections_total": false,
+		"mohawk_tpm_hardware_degraded":             false,
+		"mohawk_tpm_hardware_errors_total":         false,
 	}
 
 	for _, family := range families {
@@ -594,3 +625,86 @@ func TestTPMMetricsRegistered(t *testing.T) {
 		}
 	}
 }
+
+func TestAttestationEnvelopeRoundTrip(t *testing.T) {
+	manager := NewAttestationManager(10, time.Minute, true)
+	report, err := manager.GenerateAttestation("envelope-node", []byte("envelope-nonce"))
+	if err != nil {
+		t.Fatalf("generate attestation: %v", err)
+	}
+
+	envelope, err := ToEnvelope(report)
+	if err != nil {
+		t.Fatalf("to envelope: %v", err)
+	}
+	encoded, err := envelope.Marshal()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	decoded, err := protocol.UnmarshalAttestation(encoded)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	reencoded, _ := decoded.Marshal()
+	if string(reencoded) != string(encoded) {
+		t.Fatalf("expected canonical re-encoding:\n%s\n%s", encoded, reencoded)
+	}
+	original, _ := envelope.Digest()
+	roundTripped, _ := decoded.Digest()
+	if original != roundTripped {
+		t.Fatal("expected digest to survive a round trip")
+	}
+
+	restored, err := FromEnvelope(decoded)
+	if err != nil {
+		t.Fatalf("from envelope: %v", err)
+	}
+	if !restored.Timestamp.Equal(report.Timestamp) || string(restored.Quote) != string(report.Quote) ||
+		string(restored.PCRValues[7]) != string(report.PCRValues[7]) || string(restored.PublicKey) != string(report.PublicKey) {
+		t.Fatalf("expected restored report to match the original")
+	}
+	if err := NewAttestationManager(10, time.Minute, true).VerifyEnvelope(decoded); err != nil {
+		t.Fatalf("expected round-tripped envelope to verify: %v", err)
+	}
+}
+
+func TestAttestationEnvelopeDetectsFieldMutation(t *testing.T) {
+	manager := NewAttestationManager(10, time.Minute, true)
+	report, err := manager.GenerateAttestation("mutation-node", []byte("mutation-nonce"))
+	if err != nil {
+		t.Fatalf("generate attestation: %v", err)
+	}
+	envelope, err := ToEnvelope(report)
+	if err != nil {
+		t.Fatalf("to envelope: %v", err)
+	}
+	digest, _ := envelope.Digest()
+
+	mutations := map[string]func(e *protocol.AttestationEnvelope){
+		"node_id":        func(e *protocol.AttestationEnvelope) { e.NodeID = "other-node" },
+		"attestation_id": func(e *protocol.AttestationEnvelope) { e.AttestationID = "forged" },
+		"timestamp":      func(e *protocol.AttestationEnvelope) { e.Timestamp = e.Timestamp.Add(time.Nanosecond) },
+		"quote":          func(e *protocol.AttestationEnvelope) { e.Quote = "AAAA" + e.Quote[4:] },
+		"pcr":            func(e *protocol.AttestationEnvelope) { e.PCRs["7"] = strings.Repeat("00", 32) },
+		"nonce":          func(e *protocol.AttestationEnvelope) { e.Nonce = "AAAA" + e.Nonce[4:] },
+		"signature":      func(e *protocol.AttestationEnvelope) { e.Signature = "AAAA" + e.Signature[4:] },
+		"ak_public_key":  func(e *protocol.AttestationEnvelope) { e.AKPublicKey += "\n" },
+	}
+	for field, mutate := range mutations {
+		mutated := *envelope
+		mutated.PCRs = make(map[string]string, len(envelope.PCRs))
+		for k, v := range envelope.PCRs {
+			mutated.PCRs[k] = v
+		}
+		mutate(&mutated)
+		if got, err := mutated.Digest(); err == nil && got == digest {
+			t.Fatalf("expected a %s mutation to change the digest", field)
+		}
+		if field == "ak_public_key" {
+			continue
+		}
+		if err := NewAttestationManager(10, time.Minute, true).VerifyEnvelope(&mutated); err == nil {
+			t.Fatalf("expected a %s mutation to fail verification", field)
+		}
+	}
+}
@@ -13,19 +13,26 @@
 // limitations under the License.
 
 // Reference: /proofs/bft_resilience.md
-// Supporting verification for Theorem 1 (55.5% Byzantine Tolerance).
+// Supporting verification for Theorem 1 (55.5% Byzantine Tolerance) and,
+// under the Classic33 fault model, for n >= 3f+1.
 
 package tpm
 
 import (
 	"fmt"
 
-	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/hva"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/faultmodel"
 )
 
 // VerifyShardIntegrity ensures that a regional shard has enough participants
 // to meet Mohawk's 55.5% honest-node threshold.
 func VerifyShardIntegrity(participants int, faultyNodes int) error {
+	return VerifyShardIntegrityUnder(faultmodel.Hierarchical55, participants, faultyNodes)
+}
+
+// VerifyShardIntegrityUnder admits a shard only if its honest participants
+// meet model's threshold. Failures wrap faultmodel.ErrThresholdExceeded.
+func VerifyShardIntegrityUnder(model faultmodel.Model, participants int, faultyNodes int) error {
 	if participants <= 0 {
 		return fmt.Errorf("participants must be positive")
 	}
@@ -34,12 +41,14 @@ func VerifyShardIntegrity(participants int, faultyNodes int) error {
 	}
 
 	honestNodes := participants - faultyNodes
-	minimumHonest := hva.MinimumHonestNodes(participants)
+	minimumHonest := model.MinHonest(participants)
 	if honestNodes < minimumHonest {
 		return fmt.Errorf(
-			"shard security failure: honest=%d requires >= %d to satisfy the 55.5%% Theorem 1 boundary",
+			"%w: shard security failure: honest=%d requires >= %d under %s",
+			faultmodel.ErrThresholdExceeded,
 			honestNodes,
 			minimumHonest,
+			model.Theorem(),
 		)
 	}
 	return nil
@@ -0,0 +1,215 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package upload decides how a metered or battery-powered edge node uploads
+// its model update each round: at full precision, quantized, or not at all.
+package upload
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Skip reasons recorded in decisions.
+const (
+	ReasonLowBattery = "battery below minimum"
+	ReasonMetered    = "metered network"
+	ReasonBudget     = "daily byte budget exhausted"
+)
+
+// Constraints bound what a node spends on uploads. The zero value imposes
+// no constraints.
+type Constraints struct {
+	// MaxBytesPerDay caps upload bytes per UTC day; zero means unlimited.
+	MaxBytesPerDay int64 `json:"max_bytes_per_day"`
+	// UnmeteredOnly skips every round while the network is metered.
+	UnmeteredOnly bool `json:"unmetered_only"`
+	// MinBattery is the charge fraction in [0, 1] below which a node that
+	// is not charging skips the round.
+	MinBattery float64 `json:"min_battery"`
+}
+
+// Validate checks the constraints are in range.
+func (c Constraints) Validate() error {
+	if c.MaxBytesPerDay < 0 {
+		return fmt.Errorf("max bytes per day must not be negative, got %d", c.MaxBytesPerDay)
+	}
+	if c.MinBattery < 0 || c.MinBattery > 1 {
+		return fmt.Errorf("min battery must be in [0, 1], got %f", c.MinBattery)
+	}
+	return nil
+}
+
+// BatteryProbe reports the charge fraction in [0, 1] and whether the node is
+// charging. ok is false when the node has no battery.
+type BatteryProbe func() (level float64, charging bool, ok bool)
+
+// NetworkProbe reports whether the current network is metered.
+type NetworkProbe func() bool
+
+// Decision is the tier chosen for one round.
+type Decision struct {
+	Round  int                 `json:"round"`
+	Tier   protocol.UploadTier `json:"tier"`
+	Bytes  int                 `json:"bytes"`
+	Reason string              `json:"reason,omitempty"`
+}
+
+// Policy chooses an upload tier per round under its constraints, preferring
+// full precision and falling back to a quantized update when only that fits
+// the remaining daily budget.
+type Policy struct {
+	mu          sync.Mutex
+	constraints Constraints
+	battery     BatteryProbe
+	metered     NetworkProbe
+	now         func() time.Time
+	day         time.Time
+	spent       int64
+}
+
+// NewPolicy creates a policy. Without probes the node is treated as mains
+// powered on an unmetered network.
+func NewPolicy(constraints Constraints) (*Policy, error) {
+	if err := constraints.Validate(); err != nil {
+		return nil, err
+	}
+	return &Policy{constraints: constraints, now: time.Now}, nil
+}
+
+// SetBatteryProbe sets the probe consulted for MinBattery.
+func (p *Policy) SetBatteryProbe(probe BatteryProbe) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.battery = probe
+}
+
+// SetNetworkProbe sets the probe consulted for UnmeteredOnly.
+func (p *Policy) SetNetworkProbe(probe NetworkProbe) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.metered = probe
+}
+
+// SetClock replaces the clock that decides when the daily budget resets.
+func (p *Policy) SetClock(now func() time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = now
+}
+
+// Constraints returns the configured constraints.
+func (p *Policy) Constraints() Constraints {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.constraints
+}
+
+// Decide chooses the tier for a round whose update has dims weights and
+// charges its size against today's budget.
+func (p *Policy) Decide(round, dims int) Decision {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	decision := Decision{Round: round, Tier: protocol.UploadTierSkipped}
+	if p.battery != nil && p.constraints.MinBattery > 0 {
+		if level, charging, ok := p.battery(); ok && !charging && level < p.constraints.MinBattery {
+			decision.Reason = ReasonLowBattery
+			return decision
+		}
+	}
+	if p.metered != nil && p.constraints.UnmeteredOnly && p.metered() {
+		decision.Reason = ReasonMetered
+		return decision
+	}
+
+	remaining := p.remainingLocked()
+	switch full, quantized := protocol.FullUpdateBytes(dims), protocol.QuantizedUpdateBytes(dims); {
+	case remaining < 0 || int64(full) <= remaining:
+		decision.Tier, decision.Bytes = protocol.UploadTierFull, full
+	case int64(quantized) <= remaining:
+		decision.Tier, decision.Bytes = protocol.UploadTierQuantized, quantized
+	default:
+		decision.Reason = ReasonBudget
+		return decision
+	}
+	p.spent += int64(decision.Bytes)
+	return decision
+}
+
+// Remaining returns the bytes left in today's budget, or -1 when unlimited.
+func (p *Policy) Remaining() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.remainingLocked()
+}
+
+func (p *Policy) remainingLocked() int64 {
+	if day := p.now().UTC().Truncate(24 * time.Hour); !day.Equal(p.day) {
+		p.day = day
+		p.spent = 0
+	}
+	if p.constraints.MaxBytesPerDay == 0 {
+		return -1
+	}
+	return p.constraints.MaxBytesPerDay - p.spent
+}
+
+// Status returns the StatusUpdate that reports the decision to the
+// scheduler.
+func (d Decision) Status(nodeID string) protocol.StatusUpdate {
+	return protocol.StatusUpdate{
+		NodeID:     nodeID,
+		Status:     "training",
+		Round:      d.Round,
+		Progress:   1,
+		Timestamp:  time.Now().UTC(),
+		UploadTier: d.Tier,
+	}
+}
+
+// Prepare encodes weights at the decided tier. It returns nil when the round
+// is skipped.
+func (d Decision) Prepare(nodeID string, weights []float64, samples int) (*batch.Update, error) {
+	switch d.Tier {
+	case protocol.UploadTierSkipped:
+		return nil, nil
+	case protocol.UploadTierQuantized:
+		q, err := protocol.Quantize(weights)
+		if err != nil {
+			return nil, err
+		}
+		return &batch.Update{NodeID: nodeID, Quantized: q, SampleCount: samples}, nil
+	case protocol.UploadTierFull:
+		return &batch.Update{NodeID: nodeID, Weights: weights, SampleCount: samples}, nil
+	default:
+		return nil, fmt.Errorf("unknown upload tier %q", d.Tier)
+	}
+}
+
+// SysfsBattery returns a probe reading a Linux power_supply directory such
+// as /sys/class/power_supply/BAT0. A missing or unreadable battery reports
+// ok false.
+func SysfsBattery(dir string) BatteryProbe {
+	return func() (float64, bool, bool) {
+		raw, err := os.ReadFile(dir + "/capacity") // #nosec G304 -- battery path is operator configuration
+		if err != nil {
+			return 0, false, false
+		}
+		percent, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+		if err != nil {
+			return 0, false, false
+		}
+		status, _ := os.ReadFile(dir + "/status") // #nosec G304 -- battery path is operator configuration
+		state := strings.TrimSpace(string(status))
+		charging := state == "Charging" || state == "Full"
+		return float64(percent) / 100, charging, true
+	}
+}
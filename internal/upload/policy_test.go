@@ -0,0 +1,240 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func TestByteBudgetOverTwentyRounds(t *testing.T) {
+	const dims = 1000
+	// Three full updates (8000 bytes each) and five quantized ones (1009
+	// bytes each) fit in the budget; the rest of the day is skipped.
+	policy, err := NewPolicy(Constraints{MaxBytesPerDay: 30000})
+	if err != nil {
+		t.Fatalf("new policy: %v", err)
+	}
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	policy.SetClock(func() time.Time { return now })
+
+	ledger := scheduler.NewParticipationLedger()
+	agg := batch.NewAggregator(&batch.Config{TotalNodes: 2, HonestNodes: 2, RedundancyFactor: 10, ClipNorm: 100})
+	expected := []string{"edge", "mains"}
+
+	weights := make([]float64, dims)
+	for j := range weights {
+		weights[j] = math.Sin(float64(j))
+	}
+
+	var tiers []protocol.UploadTier
+	for round := 1; round <= 20; round++ {
+		now = now.Add(30 * time.Minute)
+		decision := policy.Decide(round, dims)
+		tiers = append(tiers, decision.Tier)
+		ledger.RecordStatus(decision.Status("edge"))
+		ledger.RecordStatus(protocol.StatusUpdate{NodeID: "mains", Round: round, UploadTier: protocol.UploadTierFull})
+
+		edge, err := decision.Prepare("edge", weights, 300)
+		if err != nil {
+			t.Fatalf("round %d: prepare: %v", round, err)
+		}
+		updates := []batch.Update{{NodeID: "mains", Weights: weights, SampleCount: 100}}
+		submitted := []string{"mains"}
+		if edge != nil {
+			updates = append(updates, *edge)
+			submitted = append(submitted, "edge")
+		}
+
+		result, err := agg.Aggregate(context.Background(), round, updates)
+		if err != nil {
+			t.Fatalf("round %d: aggregate: %v", round, err)
+		}
+		if entry, ok := result.Manifest.Entry("edge"); ok {
+			if entry.UploadTier != decision.Tier {
+				t.Fatalf("round %d: manifest tier %q, decided %q", round, entry.UploadTier, decision.Tier)
+			}
+			if math.Abs(entry.AppliedWeight-0.75) > 1e-12 {
+				t.Fatalf("round %d: %s update weighted %f, want 0.75", round, decision.Tier, entry.AppliedWeight)
+			}
+		}
+
+		outcome := ledger.CloseRound(round, expected, submitted)
+		if len(outcome.Faults) != 0 {
+			t.Fatalf("round %d: unexpected faults %v", round, outcome.Faults)
+		}
+	}
+
+	for i, tier := range tiers {
+		want := protocol.UploadTierSkipped
+		switch {
+		case i < 3:
+			want = protocol.UploadTierFull
+		case i < 8:
+			want = protocol.UploadTierQuantized
+		}
+		if tier != want {
+			t.Fatalf("round %d: expected %q, got %q (all: %v)", i+1, want, tier, tiers)
+		}
+	}
+	if got := policy.Remaining(); got != 30000-3*8000-5*1009 {
+		t.Fatalf("unexpected remaining budget %d", got)
+	}
+
+	counts := ledger.Participation("edge")
+	if counts != (scheduler.Participation{Full: 3, Quantized: 5, Skipped: 12}) {
+		t.Fatalf("unexpected edge participation %+v", counts)
+	}
+
+	// The budget resets at the next UTC day.
+	now = now.Add(24 * time.Hour)
+	if decision := policy.Decide(21, dims); decision.Tier != protocol.UploadTierFull {
+		t.Fatalf("expected a full update after the daily reset, got %+v", decision)
+	}
+}
+
+func TestUndeclaredMissCountsAsFault(t *testing.T) {
+	ledger := scheduler.NewParticipationLedger()
+	ledger.RecordStatus(protocol.StatusUpdate{NodeID: "a", Round: 1, UploadTier: protocol.UploadTierSkipped})
+	outcome := ledger.CloseRound(1, []string{"a", "b"}, nil)
+	if len(outcome.Skipped) != 1 || outcome.Skipped[0] != "a" {
+		t.Fatalf("expected a to be skipped, got %+v", outcome)
+	}
+	if len(outcome.Faults) != 1 || outcome.Faults[0] != "b" {
+		t.Fatalf("expected b to be a fault, got %+v", outcome)
+	}
+}
+
+func TestBatteryAndMeteredConstraints(t *testing.T) {
+	policy, err := NewPolicy(Constraints{UnmeteredOnly: true, MinBattery: 0.3})
+	if err != nil {
+		t.Fatalf("new policy: %v", err)
+	}
+	level, charging, metered := 0.2, false, false
+	policy.SetBatteryProbe(func() (float64, bool, bool) { return level, charging, true })
+	policy.SetNetworkProbe(func() bool { return metered })
+
+	if d := policy.Decide(1, 10); d.Tier != protocol.UploadTierSkipped || d.Reason != ReasonLowBattery {
+		t.Fatalf("expected a low-battery skip, got %+v", d)
+	}
+	charging = true
+	if d := policy.Decide(2, 10); d.Tier != protocol.UploadTierFull {
+		t.Fatalf("expected a full update while charging, got %+v", d)
+	}
+	metered = true
+	if d := policy.Decide(3, 10); d.Tier != protocol.UploadTierSkipped || d.Reason != ReasonMetered {
+		t.Fatalf("expected a metered skip, got %+v", d)
+	}
+
+	if _, err := NewPolicy(Constraints{MinBattery: 1.5}); err == nil {
+		t.Fatal("expected an out-of-range battery minimum to be rejected")
+	}
+}
+
+func TestSparsifierCarriesResidual(t *testing.T) {
+	sparsifier, err := NewSparsifier(0.25)
+	if err != nil {
+		t.Fatalf("new sparsifier: %v", err)
+	}
+	first, err := sparsifier.Sparsify([]float64{0.1, -0.5, 0.2, 0.05})
+	if err != nil {
+		t.Fatalf("sparsify: %v", err)
+	}
+	if len(first.Indices) != 1 || first.Indices[0] != 1 || first.Values[0] != -0.5 {
+		t.Fatalf("expected only coordinate 1 sent, got %+v", first)
+	}
+
+	// Coordinate 2 has now accumulated 0.4, more than the new coordinate 1.
+	second, err := sparsifier.Sparsify([]float64{0.1, 0.3, 0.2, 0.05})
+	if err != nil {
+		t.Fatalf("sparsify: %v", err)
+	}
+	if second.Indices[0] != 2 || math.Abs(second.Values[0]-0.4) > 1e-12 {
+		t.Fatalf("expected the accumulated coordinate 2 sent, got %+v", second)
+	}
+	want := []float64{0.2, 0.3, 0, 0.1}
+	for i, r := range sparsifier.Residual() {
+		if math.Abs(r-want[i]) > 1e-12 {
+			t.Fatalf("residual %v, want %v", sparsifier.Residual(), want)
+		}
+	}
+	if _, err := sparsifier.Sparsify([]float64{1}); err == nil {
+		t.Fatal("expected a dimension change to be rejected")
+	}
+}
+
+func TestPersonalSegmentsStayOutOfTheUploadPipeline(t *testing.T) {
+	// A twelve-weight model whose last four are each node's personal head.
+	spec := protocol.ModelSpec{
+		Name: "mlp", Version: 1, Dimension: 12, ArchitectureDigest: protocol.UpdateDigest([]byte("mlp")),
+		Personal: []protocol.Segment{{Name: "head", Offset: 8, Length: 4}},
+	}
+	agg := batch.NewAggregator(&batch.Config{OutlierFactor: -1, ClipNorm: 100})
+	agg.SetModelSpec(func() (protocol.ModelSpec, bool) { return spec, true })
+	dp := privacy.NewDifferentialPrivacy(privacy.NewSGP001Config())
+	dp.SetNoiseSource(rand.New(rand.NewSource(1)))
+	sparsifier, err := NewSparsifier(0.5)
+	if err != nil {
+		t.Fatalf("new sparsifier: %v", err)
+	}
+
+	var updates []batch.Update
+	for n, tier := range []protocol.UploadTier{protocol.UploadTierFull, protocol.UploadTierQuantized, protocol.UploadTierSparse} {
+		local := make([]float64, spec.Dimension)
+		for j := range local {
+			local[j] = math.Sin(float64(n*spec.Dimension + j))
+		}
+		stripped, err := spec.Strip(local)
+		if err != nil {
+			t.Fatalf("strip: %v", err)
+		}
+		// Clipping and noise see only the global coordinates.
+		noisy, err := dp.AddNoiseToGradients(stripped, 1)
+		if err != nil || len(noisy) != spec.GlobalDimension() {
+			t.Fatalf("privatize: %d weights, %v", len(noisy), err)
+		}
+		nodeID := fmt.Sprintf("edge-%d", n)
+		var update *batch.Update
+		if tier == protocol.UploadTierSparse {
+			sparse, err := sparsifier.Sparsify(noisy)
+			if err != nil || sparse.Dim != spec.GlobalDimension() || len(sparsifier.Residual()) != spec.GlobalDimension() {
+				t.Fatalf("sparsify: %+v, %v", sparse, err)
+			}
+			update = &batch.Update{NodeID: nodeID, Sparse: sparse, SampleCount: 10}
+		} else if update, err = (Decision{Round: 1, Tier: tier}).Prepare(nodeID, noisy, 10); err != nil {
+			t.Fatalf("prepare %s: %v", tier, err)
+		}
+		update.SpecVersion = spec.Version
+		updates = append(updates, *update)
+	}
+
+	quantized, err := protocol.Quantize(make([]float64, spec.Dimension))
+	if err != nil {
+		t.Fatalf("quantize: %v", err)
+	}
+	unstripped := batch.Update{NodeID: "edge-9", Quantized: quantized, SampleCount: 10, SpecVersion: spec.Version}
+	if _, err := agg.Aggregate(context.Background(), 1, append(updates, unstripped)); !errors.Is(err, protocol.ErrModelSpecMismatch) {
+		t.Fatalf("unstripped update: expected ErrModelSpecMismatch, got %v", err)
+	}
+	result, err := agg.Aggregate(context.Background(), 1, updates)
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if len(result.Weights) != spec.GlobalDimension() {
+		t.Fatalf("aggregate has %d weights, want %d", len(result.Weights), spec.GlobalDimension())
+	}
+	if merged, err := spec.Merge(result.Weights, nil); err != nil || len(merged) != spec.Dimension {
+		t.Fatalf("merge: %d weights, %v", len(merged), err)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package upload
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// Sparsifier sends the top fraction of an update's coordinates each round
+// and keeps the rest as a residual added to the next round's update (error
+// feedback), so unsent coordinates are delayed rather than lost.
+type Sparsifier struct {
+	mu       sync.Mutex
+	fraction float64
+	residual []float64
+}
+
+// NewSparsifier keeps fraction of the coordinates, in (0, 1], per round.
+func NewSparsifier(fraction float64) (*Sparsifier, error) {
+	if fraction <= 0 || fraction > 1 {
+		return nil, fmt.Errorf("sparsity fraction must be in (0, 1], got %f", fraction)
+	}
+	return &Sparsifier{fraction: fraction}, nil
+}
+
+// K returns how many coordinates of a dims-long update are sent: at least
+// one.
+func (s *Sparsifier) K(dims int) int {
+	k := int(math.Ceil(s.fraction * float64(dims)))
+	if k < 1 {
+		k = 1
+	}
+	return k
+}
+
+// Sparsify adds the carried residual to update, sends the top-k coordinates
+// of the sum, and carries the remainder forward. update is not modified.
+func (s *Sparsifier) Sparsify(update []float64) (*protocol.SparseUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.residual != nil && len(s.residual) != len(update) {
+		return nil, fmt.Errorf("update has %d weights, residual has %d", len(update), len(s.residual))
+	}
+
+	accumulated := make([]float64, len(update))
+	for i, w := range update {
+		accumulated[i] = w
+		if s.residual != nil {
+			accumulated[i] += s.residual[i]
+		}
+	}
+	sparse, err := protocol.TopK(accumulated, s.K(len(update)))
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range sparse.Indices {
+		accumulated[index] = 0
+	}
+	s.residual = accumulated
+	return sparse, nil
+}
+
+// Residual returns a copy of the coordinates not yet sent.
+func (s *Sparsifier) Residual() []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]float64(nil), s.residual...)
+}
+
+// Reset drops the residual, e.g. after the model dimension changes.
+func (s *Sparsifier) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.residual = nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package abi defines the contract a proof-verifier guest module must export
+// and checks candidate modules against it before the host loads them.
+//
+// A version 1 guest exports:
+//
+//	memory                                  linear memory the host writes proofs into
+//	alloc(size i32) -> i32                  reserve size bytes, return the offset
+//	free(ptr i32, size i32)                 release a buffer returned by alloc
+//	verify_proof(ptr i32, len i32) -> i32   1 if the proof at [ptr, ptr+len) is valid
+//	abi_version() -> i32                    the ABI version the guest implements
+//
+// testdata/conformance.wat is the reference implementation.
+//
+// A Byzantine detection plugin exports the same memory, alloc, free, and
+// abi_version, and in place of verify_proof:
+//
+//	analyze(ptr i32, len i32) -> i64        judge the update at [ptr, ptr+len)
+//
+// The update is the model's weights as little-endian float64s. The result
+// packs a suspicion score in parts per million (0 to 1000000) into the high
+// 32 bits and flags into the low 32 bits; flag bit 0 marks the update as
+// Byzantine.
+package abi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Version is the guest ABI version this host speaks.
+const Version = 1
+
+// Required export names.
+const (
+	ExportMemory      = "memory"
+	ExportAlloc       = "alloc"
+	ExportFree        = "free"
+	ExportVerifyProof = "verify_proof"
+	ExportABIVersion  = "abi_version"
+	ExportAnalyze     = "analyze"
+)
+
+// PluginVersion is the detection plugin ABI version this host speaks.
+const PluginVersion = 1
+
+// Packed analyze result layout.
+const (
+	// PluginScoreScale is the analyze score that means certainly Byzantine.
+	PluginScoreScale = 1_000_000
+	// PluginFlagByzantine marks an update the plugin considers malicious
+	// regardless of its score.
+	PluginFlagByzantine uint32 = 1 << 0
+)
+
+var (
+	// ErrIncompatibleModule indicates a guest that does not satisfy the ABI.
+	ErrIncompatibleModule = errors.New("wasm module incompatible with guest ABI")
+	// ErrInvalidModule indicates bytes that do not compile or instantiate.
+	ErrInvalidModule = errors.New("invalid wasm module")
+)
+
+// Signature is the parameter and result types of an exported function.
+type Signature struct {
+	Params  []api.ValueType
+	Results []api.ValueType
+}
+
+func (s Signature) String() string {
+	return fmt.Sprintf("(%s) -> (%s)", typeNames(s.Params), typeNames(s.Results))
+}
+
+func (s Signature) equal(o Signature) bool {
+	return string(s.Params) == string(o.Params) && string(s.Results) == string(o.Results)
+}
+
+// Functions lists the function exports a version 1 guest must provide.
+var Functions = map[string]Signature{
+	ExportAlloc:       {Params: []api.ValueType{api.ValueTypeI32}, Results: []api.ValueType{api.ValueTypeI32}},
+	ExportFree:        {Params: []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}},
+	ExportVerifyProof: {Params: []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, Results: []api.ValueType{api.ValueTypeI32}},
+	ExportABIVersion:  {Results: []api.ValueType{api.ValueTypeI32}},
+}
+
+// PluginFunctions lists the function exports a version 1 detection plugin
+// must provide.
+var PluginFunctions = map[string]Signature{
+	ExportAlloc:      {Params: []api.ValueType{api.ValueTypeI32}, Results: []api.ValueType{api.ValueTypeI32}},
+	ExportFree:       {Params: []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}},
+	ExportAnalyze:    {Params: []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, Results: []api.ValueType{api.ValueTypeI64}},
+	ExportABIVersion: {Results: []api.ValueType{api.ValueTypeI32}},
+}
+
+// UnpackAnalysis splits an analyze result into a score in [0, 1] and flags.
+func UnpackAnalysis(packed uint64) (float64, uint32) {
+	score := float64(uint32(packed>>32)) / PluginScoreScale // #nosec G115 -- high half of a uint64
+	return min(score, 1), uint32(packed)                    // #nosec G115 -- low half of a uint64
+}
+
+// Mismatch records an export whose signature differs from the ABI.
+type Mismatch struct {
+	Export string `json:"export"`
+	Want   string `json:"want"`
+	Got    string `json:"got"`
+}
+
+// Report is the result of checking a module against the ABI.
+type Report struct {
+	// ABIVersion is the version the guest reported, or 0 if it could not be read.
+	ABIVersion int        `json:"abi_version"`
+	Expected   int        `json:"expected_version"`
+	Missing    []string   `json:"missing,omitempty"`
+	Mismatched []Mismatch `json:"mismatched,omitempty"`
+	Compatible bool       `json:"compatible"`
+
+	functions map[string]Signature
+}
+
+// Err returns nil for a compatible module, otherwise an ErrIncompatibleModule
+// listing every problem and what the guest needs to change.
+func (r *Report) Err() error {
+	if r.Compatible {
+		return nil
+	}
+	var problems []string
+	for _, name := range r.Missing {
+		if sig, ok := r.required()[name]; ok {
+			problems = append(problems, fmt.Sprintf("missing export %q: add func %s %s", name, name, sig))
+		} else {
+			problems = append(problems, fmt.Sprintf("missing export %q: export the module's linear memory as %q", name, name))
+		}
+	}
+	for _, m := range r.Mismatched {
+		problems = append(problems, fmt.Sprintf("export %q has signature %s, want %s", m.Export, m.Got, m.Want))
+	}
+	if len(r.Missing) == 0 && len(r.Mismatched) == 0 && r.ABIVersion != r.Expected {
+		problems = append(problems, fmt.Sprintf("abi_version returned %d, host requires %d: rebuild the guest against ABI v%d", r.ABIVersion, r.Expected, r.Expected))
+	}
+	return fmt.Errorf("%w: %s", ErrIncompatibleModule, strings.Join(problems, "; "))
+}
+
+// required is the function set the report was checked against.
+func (r *Report) required() map[string]Signature {
+	if r.functions == nil {
+		return Functions
+	}
+	return r.functions
+}
+
+// ValidateModule compiles wasmBin, checks its exports against the ABI and,
+// when the signatures match, instantiates it to read abi_version. A non-nil
+// error means the bytes could not be evaluated at all; ABI violations are
+// reported through Report.Compatible and Report.Err.
+func ValidateModule(ctx context.Context, wasmBin []byte) (*Report, error) {
+	return validate(ctx, wasmBin, Functions, Version)
+}
+
+// ValidatePlugin is ValidateModule for the detection plugin ABI.
+func ValidatePlugin(ctx context.Context, wasmBin []byte) (*Report, error) {
+	return validate(ctx, wasmBin, PluginFunctions, PluginVersion)
+}
+
+func validate(ctx context.Context, wasmBin []byte, functions map[string]Signature, version int) (*Report, error) {
+	r := wazero.NewRuntime(ctx)
+	defer r.Close(ctx)
+
+	compiled, err := r.CompileModule(ctx, wasmBin)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModule, err)
+	}
+
+	report := check(compiled, functions, version)
+	if len(report.Missing) > 0 || len(report.Mismatched) > 0 {
+		return report, nil
+	}
+
+	mod, err := r.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: instantiate: %v", ErrInvalidModule, err)
+	}
+	defer mod.Close(ctx)
+
+	results, err := mod.ExportedFunction(ExportABIVersion).Call(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: abi_version trapped: %v", ErrInvalidModule, err)
+	}
+	report.ABIVersion = int(api.DecodeI32(results[0]))
+	report.Compatible = report.ABIVersion == report.Expected
+	return report, nil
+}
+
+// Check inspects the exports of an already compiled module. It does not run
+// any guest code, so ABIVersion is left at 0 and Compatible false.
+func Check(compiled wazero.CompiledModule) *Report {
+	return check(compiled, Functions, Version)
+}
+
+// CheckPlugin is Check for the detection plugin ABI.
+func CheckPlugin(compiled wazero.CompiledModule) *Report {
+	return check(compiled, PluginFunctions, PluginVersion)
+}
+
+func check(compiled wazero.CompiledModule, functions map[string]Signature, version int) *Report {
+	report := &Report{Expected: version, functions: functions}
+
+	if _, ok := compiled.ExportedMemories()[ExportMemory]; !ok {
+		report.Missing = append(report.Missing, ExportMemory)
+	}
+
+	exported := compiled.ExportedFunctions()
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := functions[name]
+		def, ok := exported[name]
+		if !ok {
+			report.Missing = append(report.Missing, name)
+			continue
+		}
+		got := Signature{Params: def.ParamTypes(), Results: def.ResultTypes()}
+		if !got.equal(want) {
+			report.Mismatched = append(report.Mismatched, Mismatch{Export: name, Want: want.String(), Got: got.String()})
+		}
+	}
+	return report
+}
+
+func typeNames(types []api.ValueType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = api.ValueTypeName(t)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,63 @@
+package abi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readModule(t *testing.T, name string) []byte {
+	t.Helper()
+	bin, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return bin
+}
+
+func TestConformanceModuleSatisfiesABI(t *testing.T) {
+	report, err := ValidateModule(context.Background(), readModule(t, "conformance.wasm"))
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if !report.Compatible || report.ABIVersion != Version {
+		t.Fatalf("expected compatible v%d module, got %+v", Version, report)
+	}
+	if err := report.Err(); err != nil {
+		t.Fatalf("expected no error for compatible module, got %v", err)
+	}
+}
+
+func TestMissingExportsModuleRejected(t *testing.T) {
+	report, err := ValidateModule(context.Background(), readModule(t, "missing_exports.wasm"))
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if report.Compatible {
+		t.Fatal("expected module without allocator exports to be incompatible")
+	}
+	if strings.Join(report.Missing, ",") != "alloc,free" {
+		t.Fatalf("expected alloc and free missing, got %v", report.Missing)
+	}
+	if len(report.Mismatched) != 1 || report.Mismatched[0].Export != ExportVerifyProof {
+		t.Fatalf("expected verify_proof signature mismatch, got %+v", report.Mismatched)
+	}
+
+	err = report.Err()
+	if !errors.Is(err, ErrIncompatibleModule) {
+		t.Fatalf("expected ErrIncompatibleModule, got %v", err)
+	}
+	for _, hint := range []string{"func alloc (i32) -> (i32)", "func free (i32, i32) -> ()", "want (i32, i32) -> (i32)"} {
+		if !strings.Contains(err.Error(), hint) {
+			t.Fatalf("expected error to mention %q, got %v", hint, err)
+		}
+	}
+}
+
+func TestValidateModuleRejectsGarbage(t *testing.T) {
+	if _, err := ValidateModule(context.Background(), []byte("not wasm")); !errors.Is(err, ErrInvalidModule) {
+		t.Fatalf("expected ErrInvalidModule, got %v", err)
+	}
+}
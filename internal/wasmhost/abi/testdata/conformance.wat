@@ -0,0 +1,47 @@
+;; Reference guest for the Mohawk proof-verifier ABI, version 1.
+;;
+;; The host allocates a buffer with alloc, copies the proof into exported
+;; memory, calls verify_proof(ptr, len) and releases the buffer with free.
+;; This module accepts any non-empty proof whose first byte is 0x01.
+;;
+;; Rebuild with: wat2wasm conformance.wat -o conformance.wasm
+(module
+  (memory (export "memory") 1)
+  (global $heap (mut i32) (i32.const 1024))
+
+  (func (export "abi_version") (result i32)
+    i32.const 1)
+
+  ;; Bump allocator: hands out the next free offset.
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    global.get $heap
+    local.set $ptr
+    global.get $heap
+    local.get $size
+    i32.add
+    global.set $heap
+    local.get $ptr)
+
+  ;; Releasing the most recent allocation rewinds the bump pointer.
+  (func (export "free") (param $ptr i32) (param $size i32)
+    local.get $ptr
+    local.get $size
+    i32.add
+    global.get $heap
+    i32.eq
+    if
+      local.get $ptr
+      global.set $heap
+    end)
+
+  (func (export "verify_proof") (param $ptr i32) (param $len i32) (result i32)
+    local.get $len
+    if (result i32)
+      local.get $ptr
+      i32.load8_u
+      i32.const 1
+      i32.eq
+    else
+      i32.const 0
+    end))
@@ -0,0 +1,14 @@
+;; Pre-ABI guest: no allocator exports and the legacy single-argument
+;; verify_proof(len). ValidateModule must reject it.
+;;
+;; Rebuild with: wat2wasm missing_exports.wat -o missing_exports.wasm
+(module
+  (memory (export "memory") 1)
+
+  (func (export "abi_version") (result i32)
+    i32.const 1)
+
+  (func (export "verify_proof") (param $len i32) (result i32)
+    local.get $len
+    i32.const 200
+    i32.eq))
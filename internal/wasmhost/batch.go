@@ -0,0 +1,34 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package wasmhost
+
+import (
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// DefaultBatchBudget is how long one verification batch may take on a node.
+const DefaultBatchBudget = 50 * time.Millisecond
+
+// MaxBatchSize caps proofs per verification batch.
+const MaxBatchSize = 256
+
+// BatchSize chooses how many proofs a node verifies per batch so a batch
+// fits in budget: the number of verifications its measured latency allows
+// per core, across its cores, doubled with SIMD. Nodes without a measured
+// latency verify one proof at a time.
+func BatchSize(manifest *protocol.CapabilityManifest, budget time.Duration) int {
+	if manifest == nil || manifest.WasmVerifyMicros <= 0 {
+		return 1
+	}
+	if budget <= 0 {
+		budget = DefaultBatchBudget
+	}
+	size := int(budget/manifest.WasmVerifyLatency()) * max(manifest.CPUCount, 1)
+	if manifest.WasmSIMD {
+		size *= 2
+	}
+	return min(max(size, 1), MaxBatchSize)
+}
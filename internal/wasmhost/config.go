@@ -0,0 +1,98 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package wasmhost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+)
+
+// DetectionFile is the JSON form of a node's detection plugin config.
+type DetectionFile struct {
+	// Approved lists the plugin digests, as PluginDigest reports them, an
+	// operator has reviewed. A plugin whose digest is not listed is
+	// refused.
+	Approved []string `json:"approved"`
+	// Plugins lists the plugin modules to load, relative to the config
+	// file's directory unless absolute.
+	Plugins []string `json:"plugins"`
+	// Weights maps plugin digests to weights. Plugins not listed weigh 1.
+	Weights map[string]float64 `json:"weights,omitempty"`
+	// BuiltinWeight and Threshold are as in batch.DetectionConfig.
+	BuiltinWeight float64 `json:"builtin_weight,omitempty"`
+	Threshold     float64 `json:"threshold,omitempty"`
+	// TimeoutMs and MemoryPages set each plugin's budget. Zero takes
+	// DefaultPluginBudget's.
+	TimeoutMs   int    `json:"timeout_ms,omitempty"`
+	MemoryPages uint32 `json:"memory_pages,omitempty"`
+}
+
+// LoadDetectionConfig reads the DetectionFile at path and loads each of its
+// plugins with LoadDetectionPlugin against its approved digests. On error
+// the plugins already loaded are closed.
+func LoadDetectionConfig(ctx context.Context, path string) (*batch.DetectionConfig, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path is operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("read detection config %s: %w", path, err)
+	}
+	var file DetectionFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPluginConfig, path, err)
+	}
+	if len(file.Plugins) == 0 {
+		return nil, fmt.Errorf("%w: %s lists no plugins", ErrInvalidPluginConfig, path)
+	}
+	if file.TimeoutMs < 0 {
+		return nil, fmt.Errorf("%w: %s: timeout_ms must not be negative, got %d", ErrInvalidPluginConfig, path, file.TimeoutMs)
+	}
+	budget := PluginBudget{Timeout: time.Duration(file.TimeoutMs) * time.Millisecond, MemoryPages: file.MemoryPages}
+
+	cfg := &batch.DetectionConfig{
+		PluginWeights: file.Weights,
+		BuiltinWeight: file.BuiltinWeight,
+		Threshold:     file.Threshold,
+	}
+	for _, name := range file.Plugins {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(filepath.Dir(path), name)
+		}
+		plugin, err := loadPluginFile(ctx, name, file.Approved, budget)
+		if err != nil {
+			CloseDetection(ctx, cfg)
+			return nil, err
+		}
+		cfg.Plugins = append(cfg.Plugins, plugin)
+	}
+	return cfg, nil
+}
+
+func loadPluginFile(ctx context.Context, path string, approved []string, budget PluginBudget) (*DetectionPlugin, error) {
+	wasmBin, err := os.ReadFile(path) // #nosec G304 -- path is operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("read detection plugin %s: %w", path, err)
+	}
+	plugin, err := LoadDetectionPlugin(ctx, wasmBin, approved, budget)
+	if err != nil {
+		return nil, fmt.Errorf("detection plugin %s: %w", path, err)
+	}
+	return plugin, nil
+}
+
+// CloseDetection closes the wasmhost plugins in cfg.
+func CloseDetection(ctx context.Context, cfg *batch.DetectionConfig) {
+	if cfg == nil {
+		return
+	}
+	for _, plugin := range cfg.Plugins {
+		if p, ok := plugin.(*DetectionPlugin); ok {
+			_ = p.Close(ctx)
+		}
+	}
+}
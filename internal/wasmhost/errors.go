@@ -0,0 +1,27 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package wasmhost
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by verifier hosts and detection
+// plugins. Match them with errors.Is; never compare error strings.
+var (
+	// ErrReinstantiateDeadline means a verification's context ended while
+	// it waited for a reclaimed module to be re-instantiated. Retryable: the
+	// re-instantiation carries on, so a retry finds the module warm.
+	ErrReinstantiateDeadline = errors.New("wasm module re-instantiation outlasted the verification deadline")
+	// ErrPluginNotApproved means a plugin's digest is not in the approved
+	// list. Not retryable until an operator approves the digest.
+	ErrPluginNotApproved = errors.New("detection plugin not approved")
+	// ErrPluginBudgetExceeded means a plugin ran past its time budget on an
+	// update. Not retryable within the round.
+	ErrPluginBudgetExceeded = errors.New("detection plugin exceeded budget")
+	// ErrPluginFailed means a plugin trapped or could not take an update.
+	// Not retryable within the round.
+	ErrPluginFailed = errors.New("detection plugin failed")
+	// ErrInvalidPluginConfig means a detection plugin config file is
+	// malformed. Not retryable until the operator fixes the file.
+	ErrInvalidPluginConfig = errors.New("invalid detection plugin config")
+)
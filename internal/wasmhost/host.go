@@ -23,7 +23,9 @@ import (
 	"encoding/hex"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost/abi"
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/api"
 )
@@ -32,9 +34,23 @@ const compilationCacheDir = "/tmp/mohawk-wasm-cache"
 
 // Host manages the WebAssembly runtime environment for zk-SNARK verification.
 type Host struct {
+	// runtime, mod, and cache are nil while the instance is reclaimed.
 	runtime wazero.Runtime
 	mod     api.Module
+	cache   wazero.CompilationCache
+	wasmBin []byte
+	digest  string
 	mu      sync.Mutex
+	// warm is set by the first verification after instantiation.
+	warm     bool
+	closed   bool
+	observer VerifyObserver
+
+	idleTimeout     time.Duration
+	idleTimer       *time.Timer
+	lastUsed        time.Time
+	rewarm          *rewarm
+	reclaimObserver ReclaimObserver
 }
 
 // Registry stores hash-addressed modules and supports default module hot reload.
@@ -42,28 +58,65 @@ type Registry struct {
 	mu          sync.RWMutex
 	modules     map[string]*Host
 	defaultHash string
+	observer    VerifyObserver
+
+	idleTimeout     time.Duration
+	reclaimObserver ReclaimObserver
 }
 
 func NewRegistry() *Registry {
 	return &Registry{modules: make(map[string]*Host)}
 }
 
-// NewHost initializes a high-performance Wasm environment.
+// NewHost initializes a high-performance Wasm environment. The module must
+// satisfy the guest ABI in package abi; incompatible modules are rejected
+// with an error naming each missing or mis-typed export.
 func NewHost(ctx context.Context, wasmBin []byte) (*Host, error) {
-	cfg := wazero.NewRuntimeConfig().WithCompilationCache(newCompilationCache())
+	report, err := abi.ValidateModule(ctx, wasmBin)
+	if err != nil {
+		return nil, err
+	}
+	if err := report.Err(); err != nil {
+		return nil, err
+	}
+
+	cache := newCompilationCache()
+	r, mod, err := instantiate(ctx, cache, wasmBin)
+	if err != nil {
+		_ = cache.Close(ctx)
+		return nil, err
+	}
+
+	return &Host{
+		runtime:  r,
+		mod:      mod,
+		cache:    cache,
+		wasmBin:  wasmBin,
+		digest:   ModuleDigest(wasmBin),
+		lastUsed: time.Now(),
+	}, nil
+}
+
+// instantiate compiles wasmBin through cache, which reads back the code an
+// earlier runtime compiled to disk, and instantiates it on a new runtime.
+func instantiate(ctx context.Context, cache wazero.CompilationCache, wasmBin []byte) (wazero.Runtime, api.Module, error) {
+	cfg := wazero.NewRuntimeConfig().WithCompilationCache(cache)
 	r := wazero.NewRuntimeWithConfig(ctx, cfg)
 
 	// Instantiate the module with hardware acceleration where available
 	mod, err := r.Instantiate(ctx, wasmBin)
 	if err != nil {
 		_ = r.Close(ctx)
-		return nil, fmt.Errorf("failed to instantiate wasm: %w", err)
+		return nil, nil, fmt.Errorf("failed to instantiate wasm: %w", err)
 	}
+	return r, mod, nil
+}
 
-	return &Host{
-		runtime: r,
-		mod:     mod,
-	}, nil
+// ModuleDigest returns the hex SHA-256 of a verifier module, the hash the
+// registry stores it under.
+func ModuleDigest(wasmBin []byte) string {
+	sum := sha256.Sum256(wasmBin)
+	return hex.EncodeToString(sum[:])
 }
 
 func newCompilationCache() wazero.CompilationCache {
@@ -80,8 +133,7 @@ func (r *Registry) Upsert(ctx context.Context, wasmBin []byte) (string, error) {
 	if len(wasmBin) == 0 {
 		return "", fmt.Errorf("empty wasm module")
 	}
-	hashBytes := sha256.Sum256(wasmBin)
-	hash := hex.EncodeToString(hashBytes[:])
+	hash := ModuleDigest(wasmBin)
 
 	r.mu.RLock()
 	_, exists := r.modules[hash]
@@ -101,6 +153,9 @@ func (r *Registry) Upsert(ctx context.Context, wasmBin []byte) (string, error) {
 		_ = host.Close(ctx)
 		return hash, nil
 	}
+	host.SetObserver(r.observer)
+	host.SetReclaimObserver(r.reclaimObserver)
+	host.SetIdleTimeout(r.idleTimeout)
 	r.modules[hash] = host
 	if r.defaultHash == "" {
 		r.defaultHash = hash
@@ -137,6 +192,39 @@ func (r *Registry) HotReload(ctx context.Context, wasmBin []byte) (string, error
 	return hash, nil
 }
 
+// SetObserver installs observer on every module in the registry and on
+// those added later. Nil removes it.
+func (r *Registry) SetObserver(observer VerifyObserver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observer = observer
+	for _, host := range r.modules {
+		host.SetObserver(observer)
+	}
+}
+
+// SetIdleTimeout sets the idle timeout of every module in the registry and
+// of those added later; see Host.SetIdleTimeout.
+func (r *Registry) SetIdleTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.idleTimeout = timeout
+	for _, host := range r.modules {
+		host.SetIdleTimeout(timeout)
+	}
+}
+
+// SetReclaimObserver installs observer on every module in the registry and
+// on those added later. Nil removes it.
+func (r *Registry) SetReclaimObserver(observer ReclaimObserver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reclaimObserver = observer
+	for _, host := range r.modules {
+		host.SetReclaimObserver(observer)
+	}
+}
+
 func (r *Registry) Close(ctx context.Context) error {
 	r.mu.Lock()
 	modules := r.modules
@@ -152,18 +240,66 @@ func (r *Registry) Close(ctx context.Context) error {
 	return nil
 }
 
-// Verify executes the zk-SNARK proof verification in the Wasm sandbox.
-func (h *Host) Verify(ctx context.Context, proof []byte) (bool, error) {
+// Digest returns the hex SHA-256 of the host's module.
+func (h *Host) Digest() string {
+	return h.digest
+}
+
+// SetObserver makes the host report the latency of every verification to
+// observer. Nil stops reporting.
+func (h *Host) SetObserver(observer VerifyObserver) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	h.observer = observer
+}
 
-	fn := h.mod.ExportedFunction("verify_proof")
-	if fn == nil {
-		return false, fmt.Errorf("wasm module missing required export: verify_proof")
+// Verify executes the zk-SNARK proof verification in the Wasm sandbox. On a
+// reclaimed host it first waits for the module to be re-instantiated, but
+// no longer than ctx allows; see SetIdleTimeout.
+func (h *Host) Verify(ctx context.Context, proof []byte) (bool, error) {
+	waitStarted := time.Now()
+	rewarmed, err := h.lockInstance(ctx)
+	if err != nil {
+		h.mu.Lock()
+		observer := h.observer
+		h.mu.Unlock()
+		observe(observer, h.digest, true, time.Since(waitStarted), false, err)
+		return false, err
+	}
+	// A re-warm the call waited for is part of its cold start.
+	started := time.Now()
+	if rewarmed {
+		started = waitStarted
+	}
+	verified, err := h.verifyLocked(ctx, proof)
+	latency := time.Since(started)
+	cold := !h.warm
+	h.warm = true
+	h.lastUsed = time.Now()
+	observer := h.observer
+	h.mu.Unlock()
+
+	observe(observer, h.digest, cold, latency, verified, err)
+	return verified, err
+}
+
+// verifyLocked runs verify_proof over proof. The caller holds h.mu.
+func (h *Host) verifyLocked(ctx context.Context, proof []byte) (bool, error) {
+	size := uint64(len(proof))
+	alloc, err := h.mod.ExportedFunction(abi.ExportAlloc).Call(ctx, size)
+	if err != nil {
+		return false, fmt.Errorf("wasm alloc error: %w", err)
+	}
+	ptr := alloc[0]
+	defer func() {
+		_, _ = h.mod.ExportedFunction(abi.ExportFree).Call(ctx, ptr, size)
+	}()
+	if !h.mod.Memory().Write(uint32(ptr), proof) {
+		return false, fmt.Errorf("wasm alloc returned out-of-range buffer at %d for %d bytes", ptr, size)
 	}
 
 	// Theorem 5: Constant-time verification check
-	results, err := fn.Call(ctx, uint64(len(proof)))
+	results, err := h.mod.ExportedFunction(abi.ExportVerifyProof).Call(ctx, ptr, size)
 	if err != nil {
 		return false, fmt.Errorf("wasm execution error: %w", err)
 	}
@@ -182,5 +318,15 @@ func (h *Host) FastVerify(ctx context.Context, proof []byte) (bool, error) {
 
 // Close releases Wasm resources.
 func (h *Host) Close(ctx context.Context) error {
-	return h.runtime.Close(ctx)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	h.closed = true
+	if h.idleTimer != nil {
+		h.idleTimer.Stop()
+	}
+	_, err := h.reclaimLocked(ctx)
+	return err
 }
@@ -0,0 +1,229 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package wasmhost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// A host idle for its idle timeout is reclaimed: its runtime, module
+// instance, and in-memory compiled code are torn down, freeing the
+// instance's linear memory on constrained devices, while the compiled code
+// stays in the on-disk compilation cache. The next Verify re-instantiates
+// the module from that cache in a background re-warm and waits for it, but
+// no longer than its context allows; a caller whose deadline passes gets
+// ErrReinstantiateDeadline while the re-warm carries on for the next.
+
+// errHostClosed is returned by Verify on a closed host.
+var errHostClosed = errors.New("wasm host is closed")
+
+// ReclaimKind tells a reclaim from the re-instantiation after it.
+type ReclaimKind string
+
+const (
+	// ReclaimIdle is an instance torn down after idling, or by Reclaim.
+	ReclaimIdle ReclaimKind = "reclaimed"
+	// ReclaimReinstantiated is a reclaimed instance re-warmed for Verify.
+	ReclaimReinstantiated ReclaimKind = "reinstantiated"
+)
+
+// ReclaimEvent reports one reclaim or re-instantiation of a host's module.
+type ReclaimEvent struct {
+	Module string
+	Kind   ReclaimKind
+	// Bytes is the linear memory a reclaim freed; zero on re-instantiation.
+	Bytes uint64
+	// Latency is how long re-instantiation took; zero on a reclaim.
+	Latency time.Duration
+	At      time.Time
+}
+
+// ReclaimObserver receives every reclaim and re-instantiation of a host's
+// module, such as api.ObserveWasmReclaim. It runs after the host is
+// released.
+type ReclaimObserver func(event ReclaimEvent)
+
+// rewarm is one background re-instantiation; done closes once err is set.
+type rewarm struct {
+	done chan struct{}
+	err  error
+}
+
+// SetIdleTimeout makes the host reclaim its module instance once no
+// verification has run for timeout. Zero, the default, never reclaims.
+func (h *Host) SetIdleTimeout(timeout time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.idleTimeout = timeout
+	h.armIdleLocked()
+}
+
+// SetReclaimObserver makes the host report every reclaim and
+// re-instantiation to observer. Nil stops reporting.
+func (h *Host) SetReclaimObserver(observer ReclaimObserver) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reclaimObserver = observer
+}
+
+// Reclaim tears the module instance down now, as the idle timeout would,
+// for a caller under memory pressure. It does nothing on a reclaimed or
+// closed host.
+func (h *Host) Reclaim(ctx context.Context) error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	event, err := h.reclaimLocked(ctx)
+	observer := h.reclaimObserver
+	h.mu.Unlock()
+
+	if event != nil {
+		observeReclaim(observer, *event)
+	}
+	return err
+}
+
+// armIdleLocked schedules the idle check for the current timeout. The
+// caller holds h.mu.
+func (h *Host) armIdleLocked() {
+	if h.idleTimeout <= 0 || h.closed || h.mod == nil {
+		if h.idleTimer != nil {
+			h.idleTimer.Stop()
+		}
+		return
+	}
+	if h.idleTimer == nil {
+		h.idleTimer = time.AfterFunc(h.idleTimeout, h.reclaimIfIdle)
+		return
+	}
+	h.idleTimer.Reset(h.idleTimeout)
+}
+
+// reclaimIfIdle reclaims the instance if it has idled for the timeout, or
+// checks again when it will have.
+func (h *Host) reclaimIfIdle() {
+	h.mu.Lock()
+	if h.idleTimeout <= 0 || h.closed || h.mod == nil {
+		h.mu.Unlock()
+		return
+	}
+	if idle := time.Since(h.lastUsed); idle < h.idleTimeout {
+		h.idleTimer.Reset(h.idleTimeout - idle)
+		h.mu.Unlock()
+		return
+	}
+	event, _ := h.reclaimLocked(context.Background())
+	observer := h.reclaimObserver
+	h.mu.Unlock()
+
+	if event != nil {
+		observeReclaim(observer, *event)
+	}
+}
+
+// reclaimLocked closes the runtime and the in-memory compilation cache,
+// returning nil if they already were. The caller holds h.mu.
+func (h *Host) reclaimLocked(ctx context.Context) (*ReclaimEvent, error) {
+	if h.mod == nil {
+		return nil, nil
+	}
+	var freed uint64
+	if mem := h.mod.Memory(); mem != nil {
+		freed = uint64(mem.Size())
+	}
+	err := h.runtime.Close(ctx)
+	if cacheErr := h.cache.Close(ctx); err == nil {
+		err = cacheErr
+	}
+	h.runtime, h.mod, h.cache = nil, nil, nil
+	return &ReclaimEvent{Module: h.digest, Kind: ReclaimIdle, Bytes: freed, At: time.Now().UTC()}, err
+}
+
+// lockInstance locks h.mu with the module instantiated, first waiting for
+// a re-warm if it was reclaimed, and reports whether it waited. It returns
+// with h.mu unlocked on error.
+func (h *Host) lockInstance(ctx context.Context) (bool, error) {
+	waited := false
+	for {
+		h.mu.Lock()
+		if h.closed {
+			h.mu.Unlock()
+			return waited, errHostClosed
+		}
+		if h.mod != nil {
+			return waited, nil
+		}
+		rw := h.startRewarmLocked()
+		h.mu.Unlock()
+
+		waited = true
+		if err := ctx.Err(); err != nil {
+			return waited, fmt.Errorf("%w: %v", ErrReinstantiateDeadline, err)
+		}
+		select {
+		case <-rw.done:
+			if rw.err != nil {
+				return waited, rw.err
+			}
+		case <-ctx.Done():
+			return waited, fmt.Errorf("%w: %v", ErrReinstantiateDeadline, ctx.Err())
+		}
+	}
+}
+
+// startRewarmLocked returns the re-warm in progress, starting one if there
+// is none. The caller holds h.mu.
+func (h *Host) startRewarmLocked() *rewarm {
+	if h.rewarm == nil {
+		h.rewarm = &rewarm{done: make(chan struct{})}
+		go h.runRewarm(h.rewarm)
+	}
+	return h.rewarm
+}
+
+// runRewarm re-instantiates the module from the on-disk compilation cache.
+// It runs on a context of its own, so a waiter giving up does not stop it.
+func (h *Host) runRewarm(rw *rewarm) {
+	ctx := context.Background()
+	started := time.Now()
+	cache := newCompilationCache()
+	r, mod, err := instantiate(ctx, cache, h.wasmBin)
+	latency := time.Since(started)
+
+	h.mu.Lock()
+	switch {
+	case err != nil:
+		_ = cache.Close(ctx)
+	case h.closed:
+		_ = r.Close(ctx)
+		_ = cache.Close(ctx)
+		err = errHostClosed
+	default:
+		h.runtime, h.mod, h.cache = r, mod, cache
+		h.warm = false
+		h.lastUsed = time.Now()
+		h.armIdleLocked()
+	}
+	rw.err = err
+	h.rewarm = nil
+	observer := h.reclaimObserver
+	h.mu.Unlock()
+	close(rw.done)
+
+	if err == nil {
+		observeReclaim(observer, ReclaimEvent{Module: h.digest, Kind: ReclaimReinstantiated, Latency: latency, At: time.Now().UTC()})
+	}
+}
+
+// observeReclaim reports event to observer, if there is one.
+func observeReclaim(observer ReclaimObserver, event ReclaimEvent) {
+	if observer != nil {
+		observer(event)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package wasmhost
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+// heapAlloc returns the live heap after a collection.
+func heapAlloc() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func nextReclaim(t *testing.T, events <-chan ReclaimEvent, kind ReclaimKind) ReclaimEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		if event.Kind != kind {
+			t.Fatalf("event %+v, want %s", event, kind)
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no %s event", kind)
+		return ReclaimEvent{}
+	}
+}
+
+func TestIdleHostReclaimsInstanceMemory(t *testing.T) {
+	ctx := context.Background()
+	host := loadHost(t, "testdata/verify_large.wasm")
+	events := make(chan ReclaimEvent, 4)
+	host.SetReclaimObserver(func(event ReclaimEvent) { events <- event })
+	if ok, err := host.Verify(ctx, delayProof(0)); !ok || err != nil {
+		t.Fatalf("verify: ok=%v err=%v", ok, err)
+	}
+
+	before := heapAlloc()
+	host.SetIdleTimeout(20 * time.Millisecond)
+	reclaimed := nextReclaim(t, events, ReclaimIdle)
+	after := heapAlloc()
+	if reclaimed.Module != host.Digest() || reclaimed.Bytes != 256*65536 {
+		t.Fatalf("reclaimed %+v", reclaimed)
+	}
+	if after > before || before-after < reclaimed.Bytes/2 {
+		t.Fatalf("heap went from %d to %d bytes after reclaiming %d", before, after, reclaimed.Bytes)
+	}
+
+	// The first verification after the reclaim re-instantiates the module
+	// from the compilation cache and verifies as before, as a cold start.
+	host.SetIdleTimeout(0)
+	var samples []monitoring.VerificationSample
+	host.SetObserver(func(sample monitoring.VerificationSample) { samples = append(samples, sample) })
+	if ok, err := host.Verify(ctx, delayProof(0)); !ok || err != nil {
+		t.Fatalf("first verify after reclaim: ok=%v err=%v", ok, err)
+	}
+	if ok, err := host.Verify(ctx, []byte{1}); ok || err != nil {
+		t.Fatalf("short proof after reclaim: ok=%v err=%v", ok, err)
+	}
+	if reinstantiated := nextReclaim(t, events, ReclaimReinstantiated); reinstantiated.Latency <= 0 || reinstantiated.Bytes != 0 {
+		t.Fatalf("reinstantiated %+v", reinstantiated)
+	}
+	if len(samples) != 2 || !samples[0].Cold || samples[1].Cold || samples[1].Outcome != monitoring.VerifyRejected {
+		t.Fatalf("samples = %+v", samples)
+	}
+}
+
+func TestReclaimedHostHonoursVerificationDeadline(t *testing.T) {
+	ctx := context.Background()
+	host := loadDelayHost(t)
+	events := make(chan ReclaimEvent, 4)
+	host.SetReclaimObserver(func(event ReclaimEvent) { events <- event })
+	if err := host.Reclaim(ctx); err != nil {
+		t.Fatalf("reclaim: %v", err)
+	}
+	nextReclaim(t, events, ReclaimIdle)
+	if err := host.Reclaim(ctx); err != nil {
+		t.Fatalf("reclaim again: %v", err)
+	}
+
+	// A caller out of time gives up at once, but the re-warm it started
+	// carries on and serves the next caller.
+	expired, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := host.Verify(expired, delayProof(0)); !errors.Is(err, ErrReinstantiateDeadline) {
+		t.Fatalf("expected ErrReinstantiateDeadline, got %v", err)
+	}
+	nextReclaim(t, events, ReclaimReinstantiated)
+	if ok, err := host.Verify(ctx, delayProof(0)); !ok || err != nil {
+		t.Fatalf("verify after re-warm: ok=%v err=%v", ok, err)
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected %+v", event)
+	default:
+	}
+
+	if err := host.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := host.Verify(ctx, delayProof(0)); err == nil {
+		t.Fatal("verified on a closed host")
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package wasmhost
+
+import (
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/profiling"
+)
+
+// VerifyObserver receives the timing of each proof verification, such as
+// monitoring.VerificationSLO.Observe. It runs on the verifying goroutine
+// after the module is released.
+type VerifyObserver func(sample monitoring.VerificationSample)
+
+// observe records one verification in the profiling timers and reports it
+// to observer, if there is one.
+func observe(observer VerifyObserver, module string, cold bool, latency time.Duration, verified bool, err error) {
+	profiling.Observe(profiling.PhaseWasmVerify, latency)
+	if observer == nil {
+		return
+	}
+	outcome := monitoring.VerifyRejected
+	switch {
+	case err != nil:
+		outcome = monitoring.VerifyError
+	case verified:
+		outcome = monitoring.VerifyAccepted
+	}
+	observer(monitoring.VerificationSample{
+		Module:  module,
+		Outcome: outcome,
+		Latency: latency,
+		Cold:    cold,
+		At:      time.Now().UTC(),
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package wasmhost
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/monitoring"
+)
+
+// delayProof asks verify_delay.wasm to spin for iterations before
+// accepting.
+func delayProof(iterations uint32) []byte {
+	proof := make([]byte, 8)
+	binary.LittleEndian.PutUint32(proof, iterations)
+	return proof
+}
+
+func loadDelayHost(t *testing.T) *Host {
+	t.Helper()
+	return loadHost(t, "testdata/verify_delay.wasm")
+}
+
+func loadHost(t *testing.T, path string) *Host {
+	t.Helper()
+	bin, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	host, err := NewHost(context.Background(), bin)
+	if err != nil {
+		t.Fatalf("new host: %v", err)
+	}
+	t.Cleanup(func() { _ = host.Close(context.Background()) })
+	return host
+}
+
+// slowIterations returns a spin count that takes host at least twice the
+// Theorem 5 bound on this machine.
+func slowIterations(t *testing.T, host *Host) uint32 {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := host.Verify(ctx, delayProof(0)); err != nil {
+		t.Fatalf("warm up: %v", err)
+	}
+	for iterations := uint32(1 << 20); iterations < 1<<31; iterations *= 2 {
+		started := time.Now()
+		if _, err := host.Verify(ctx, delayProof(iterations)); err != nil {
+			t.Fatalf("calibrate: %v", err)
+		}
+		if time.Since(started) >= 2*monitoring.DefaultVerificationBound {
+			return iterations
+		}
+	}
+	t.Fatal("could not make verification slow enough")
+	return 0
+}
+
+func TestVerifyLatencyDrivesSLOAlert(t *testing.T) {
+	ctx := context.Background()
+	slow := slowIterations(t, loadDelayHost(t))
+
+	host := loadDelayHost(t)
+	collector := monitoring.NewCollector(256)
+	slo := monitoring.NewVerificationSLO(monitoring.VerificationSLOConfig{Target: 0.9, Window: 20, MinSamples: 10, Collector: collector})
+	alerts := make(chan monitoring.SLOAlert, 4)
+	slo.AddAlertListener(func(alert monitoring.SLOAlert) { alerts <- alert })
+	host.SetObserver(func(sample monitoring.VerificationSample) { slo.Observe(sample) })
+
+	// The first call after instantiation is the cold start, slow or not.
+	if ok, err := host.Verify(ctx, delayProof(slow)); !ok || err != nil {
+		t.Fatalf("cold verify: ok=%v err=%v", ok, err)
+	}
+	for i := 0; i < 15; i++ {
+		if ok, err := host.Verify(ctx, delayProof(0)); !ok || err != nil {
+			t.Fatalf("fast verify: ok=%v err=%v", ok, err)
+		}
+	}
+	if status := slo.Status(); status.ColdStarts != 1 || status.Samples != 15 || status.Breached {
+		t.Fatalf("steady state: %+v", status)
+	}
+
+	// Two slow calls after fifteen fast ones put the window below 90%.
+	var fired *monitoring.SLOAlert
+	for i := 0; i < 5 && fired == nil; i++ {
+		if _, err := host.Verify(ctx, delayProof(slow)); err != nil {
+			t.Fatalf("slow verify: %v", err)
+		}
+		select {
+		case alert := <-alerts:
+			fired = &alert
+		default:
+		}
+	}
+	if fired == nil || !fired.Firing || fired.Rule != monitoring.AlertWasmVerificationSLO || fired.Compliance >= 0.9 {
+		t.Fatalf("alert = %+v", fired)
+	}
+
+	var cold, warm int
+	for _, metric := range collector.GetMetricsByType(monitoring.MetricWasmVerify) {
+		if metric.Labels["module"] != host.Digest() || metric.Labels["outcome"] != monitoring.VerifyAccepted {
+			t.Fatalf("sample labels %v", metric.Labels)
+		}
+		switch metric.Labels["start"] {
+		case "cold":
+			cold++
+		case "warm":
+			warm++
+		}
+	}
+	if cold != 1 || warm < 16 {
+		t.Fatalf("recorded %d cold and %d warm samples", cold, warm)
+	}
+}
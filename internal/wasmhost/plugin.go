@@ -0,0 +1,155 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package wasmhost
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/wasmhost/abi"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// PluginBudget bounds one analyze call. wazero does not meter
+// instructions, so fuel is spent as wall time: a call still running at
+// Timeout is aborted.
+type PluginBudget struct {
+	Timeout time.Duration
+	// MemoryPages caps the plugin's linear memory in 64 KiB pages.
+	MemoryPages uint32
+}
+
+// DefaultPluginBudget fits an analysis of a few million weights.
+var DefaultPluginBudget = PluginBudget{Timeout: 100 * time.Millisecond, MemoryPages: 1024}
+
+// DetectionPlugin runs a Byzantine detection heuristic compiled to Wasm in
+// its own sandboxed runtime. It implements batch.DetectionPlugin.
+type DetectionPlugin struct {
+	digest   string
+	budget   PluginBudget
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+
+	mu  sync.Mutex
+	mod api.Module
+}
+
+var _ batch.DetectionPlugin = (*DetectionPlugin)(nil)
+
+// PluginDigest is the hex SHA-256 that identifies a plugin module.
+func PluginDigest(wasmBin []byte) string {
+	sum := sha256.Sum256(wasmBin)
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadDetectionPlugin checks wasmBin against the plugin ABI and loads it if
+// its digest is among approved.
+func LoadDetectionPlugin(ctx context.Context, wasmBin []byte, approved []string, budget PluginBudget) (*DetectionPlugin, error) {
+	digest := PluginDigest(wasmBin)
+	allowed := false
+	for _, candidate := range approved {
+		if candidate == digest {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: %s", ErrPluginNotApproved, digest)
+	}
+
+	report, err := abi.ValidatePlugin(ctx, wasmBin)
+	if err != nil {
+		return nil, err
+	}
+	if err := report.Err(); err != nil {
+		return nil, err
+	}
+
+	if budget.Timeout <= 0 {
+		budget.Timeout = DefaultPluginBudget.Timeout
+	}
+	if budget.MemoryPages == 0 {
+		budget.MemoryPages = DefaultPluginBudget.MemoryPages
+	}
+	cfg := wazero.NewRuntimeConfig().
+		WithCompilationCache(newCompilationCache()).
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(budget.MemoryPages)
+	r := wazero.NewRuntimeWithConfig(ctx, cfg)
+	compiled, err := r.CompileModule(ctx, wasmBin)
+	if err != nil {
+		_ = r.Close(ctx)
+		return nil, fmt.Errorf("%w: %v", abi.ErrInvalidModule, err)
+	}
+	return &DetectionPlugin{digest: digest, budget: budget, runtime: r, compiled: compiled}, nil
+}
+
+// ID returns the plugin's digest.
+func (p *DetectionPlugin) ID() string {
+	return p.digest
+}
+
+// Analyze passes update to the plugin's analyze export. A trap or an
+// overrun discards the instance; the next call starts a fresh one, so a
+// plugin disabled for one round is back for the next.
+func (p *DetectionPlugin) Analyze(update []byte) (batch.DetectionVerdict, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.budget.Timeout)
+	defer cancel()
+	packed, err := p.analyze(ctx, update)
+	if err != nil {
+		if p.mod != nil {
+			_ = p.mod.Close(context.Background())
+			p.mod = nil
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return batch.DetectionVerdict{}, fmt.Errorf("%w: %s after %s", ErrPluginBudgetExceeded, p.digest, p.budget.Timeout)
+		}
+		return batch.DetectionVerdict{}, fmt.Errorf("%w: %s: %v", ErrPluginFailed, p.digest, err)
+	}
+	score, flags := abi.UnpackAnalysis(packed)
+	return batch.DetectionVerdict{Score: score, Byzantine: flags&abi.PluginFlagByzantine != 0}, nil
+}
+
+func (p *DetectionPlugin) analyze(ctx context.Context, update []byte) (uint64, error) {
+	if p.mod == nil {
+		mod, err := p.runtime.InstantiateModule(ctx, p.compiled, wazero.NewModuleConfig().WithName(""))
+		if err != nil {
+			return 0, fmt.Errorf("instantiate: %w", err)
+		}
+		p.mod = mod
+	}
+
+	size := uint64(len(update))
+	alloc, err := p.mod.ExportedFunction(abi.ExportAlloc).Call(ctx, size)
+	if err != nil {
+		return 0, fmt.Errorf("alloc: %w", err)
+	}
+	ptr := alloc[0]
+	if !p.mod.Memory().Write(uint32(ptr), update) {
+		return 0, fmt.Errorf("alloc returned out-of-range buffer at %d for %d bytes", ptr, size)
+	}
+	results, err := p.mod.ExportedFunction(abi.ExportAnalyze).Call(ctx, ptr, size)
+	if err != nil {
+		return 0, fmt.Errorf("analyze: %w", err)
+	}
+	if _, err := p.mod.ExportedFunction(abi.ExportFree).Call(ctx, ptr, size); err != nil {
+		return 0, fmt.Errorf("free: %w", err)
+	}
+	return results[0], nil
+}
+
+// Close releases the plugin's runtime.
+func (p *DetectionPlugin) Close(ctx context.Context) error {
+	return p.runtime.Close(ctx)
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package wasmhost
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+func loadPlugin(t *testing.T, name string, budget PluginBudget) *DetectionPlugin {
+	t.Helper()
+	bin, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	plugin, err := LoadDetectionPlugin(context.Background(), bin, []string{PluginDigest(bin)}, budget)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	t.Cleanup(func() { _ = plugin.Close(context.Background()) })
+	return plugin
+}
+
+func TestLoadDetectionPluginRequiresApproval(t *testing.T) {
+	bin, err := os.ReadFile("testdata/plugin_threshold.wasm")
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if _, err := LoadDetectionPlugin(context.Background(), bin, []string{"0123"}, DefaultPluginBudget); !errors.Is(err, ErrPluginNotApproved) {
+		t.Fatalf("unapproved plugin: %v", err)
+	}
+}
+
+func TestThresholdPluginFlagsLargeFirstWeight(t *testing.T) {
+	plugin := loadPlugin(t, "plugin_threshold.wasm", DefaultPluginBudget)
+	agg := batch.NewAggregator(&batch.Config{OutlierFactor: -1})
+	agg.SetDetection(&batch.DetectionConfig{Plugins: []batch.DetectionPlugin{plugin}, BuiltinWeight: -1})
+
+	result, err := agg.Aggregate(context.Background(), 1, []batch.Update{
+		{NodeID: "honest-1", Weights: []float64{1, 2}, SampleCount: 10},
+		{NodeID: "honest-2", Weights: []float64{3, 2}, SampleCount: 10},
+		{NodeID: "poisoner", Weights: []float64{50, 2}, SampleCount: 10},
+	})
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if len(result.PluginFailures) != 0 {
+		t.Fatalf("unexpected failures %+v", result.PluginFailures)
+	}
+	poisoner := result.Manifest.Entries[2]
+	if poisoner.Included || poisoner.Reason != protocol.ReasonDetected || poisoner.DetectionScore != 1 {
+		t.Fatalf("poisoner entry %+v", poisoner)
+	}
+	if result.Weights[0] != 2 {
+		t.Fatalf("aggregate %v, want the honest mean", result.Weights)
+	}
+}
+
+func TestMisbehavingPluginsAreDisabledForTheRound(t *testing.T) {
+	trap := loadPlugin(t, "plugin_trap.wasm", DefaultPluginBudget)
+	spin := loadPlugin(t, "plugin_spin.wasm", PluginBudget{Timeout: 20 * time.Millisecond})
+	threshold := loadPlugin(t, "plugin_threshold.wasm", DefaultPluginBudget)
+
+	if _, err := trap.Analyze(make([]byte, 16)); !errors.Is(err, ErrPluginFailed) {
+		t.Fatalf("trapping plugin: %v", err)
+	}
+	if _, err := spin.Analyze(make([]byte, 16)); !errors.Is(err, ErrPluginBudgetExceeded) {
+		t.Fatalf("spinning plugin: %v", err)
+	}
+
+	agg := batch.NewAggregator(&batch.Config{OutlierFactor: -1})
+	agg.SetDetection(&batch.DetectionConfig{
+		Plugins:       []batch.DetectionPlugin{trap, spin, threshold},
+		BuiltinWeight: -1,
+	})
+	result, err := agg.Aggregate(context.Background(), 1, []batch.Update{
+		{NodeID: "honest", Weights: []float64{1}, SampleCount: 1},
+		{NodeID: "poisoner", Weights: []float64{50}, SampleCount: 1},
+	})
+	if err != nil {
+		t.Fatalf("a broken plugin failed the round: %v", err)
+	}
+	if len(result.PluginFailures) != 2 || result.PluginFailures[0].Plugin != trap.ID() || result.PluginFailures[1].Plugin != spin.ID() {
+		t.Fatalf("failures %+v", result.PluginFailures)
+	}
+	if result.Manifest.Entries[1].Reason != protocol.ReasonDetected {
+		t.Fatalf("working plugin ignored: %+v", result.Manifest.Entries[1])
+	}
+
+}
+
+func TestLoadDetectionConfigLoadsOnlyApprovedPlugins(t *testing.T) {
+	bin, err := os.ReadFile("testdata/plugin_threshold.wasm")
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "threshold.wasm"), bin, 0o600); err != nil {
+		t.Fatalf("write plugin: %v", err)
+	}
+	writeConfig := func(approved string) string {
+		path := filepath.Join(dir, "detection.json")
+		raw := `{"approved": ["` + approved + `"], "plugins": ["threshold.wasm"], "weights": {"` + approved + `": 2}, "threshold": 0.75}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return path
+	}
+
+	if _, err := LoadDetectionConfig(context.Background(), writeConfig("0123")); !errors.Is(err, ErrPluginNotApproved) {
+		t.Fatalf("unapproved plugin: %v", err)
+	}
+	digest := PluginDigest(bin)
+	cfg, err := LoadDetectionConfig(context.Background(), writeConfig(digest))
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	t.Cleanup(func() { CloseDetection(context.Background(), cfg) })
+	if len(cfg.Plugins) != 1 || cfg.Plugins[0].ID() != digest {
+		t.Fatalf("plugins %+v, want the approved one", cfg.Plugins)
+	}
+	if cfg.PluginWeights[digest] != 2 || cfg.Threshold != 0.75 {
+		t.Fatalf("config %+v", cfg)
+	}
+}
@@ -3,22 +3,47 @@ package wasmhost
 import (
 	"context"
 	"errors"
+	"sync"
+	"time"
 )
 
 type Runner struct {
 	wasmBinary []byte
+	digest     string
+
+	mu       sync.Mutex
+	warm     bool
+	observer VerifyObserver
 }
 
 func NewRunner(ctx context.Context, wasmBin []byte) (*Runner, error) {
 	if len(wasmBin) < 8 {
 		return nil, errors.New("invalid WASM binary")
 	}
-	return &Runner{wasmBinary: wasmBin}, nil
+	return &Runner{wasmBinary: wasmBin, digest: ModuleDigest(wasmBin)}, nil
+}
+
+// SetObserver makes the runner report the latency of every verification to
+// observer. Nil stops reporting.
+func (r *Runner) SetObserver(observer VerifyObserver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observer = observer
 }
 
 func (r *Runner) Verify(ctx context.Context, proof []byte) (bool, error) {
+	started := time.Now()
 	// Mock verification for now
-	return len(proof) == 200, nil
+	verified := len(proof) == 200
+	latency := time.Since(started)
+
+	r.mu.Lock()
+	cold := !r.warm
+	r.warm = true
+	observer := r.observer
+	r.mu.Unlock()
+	observe(observer, r.digest, cold, latency, verified, nil)
+	return verified, nil
 }
 
 func (r *Runner) Close(ctx context.Context) error {
@@ -0,0 +1,37 @@
+;; Misbehaving detection plugin: analyze never returns, so it always
+;; exhausts its time budget.
+;;
+;; Rebuild with: wat2wasm plugin_spin.wat -o plugin_spin.wasm
+(module
+  (memory (export "memory") 1)
+  (global $heap (mut i32) (i32.const 1024))
+
+  (func (export "abi_version") (result i32)
+    i32.const 1)
+
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    global.get $heap
+    local.set $ptr
+    global.get $heap
+    local.get $size
+    i32.add
+    global.set $heap
+    local.get $ptr)
+
+  (func (export "free") (param $ptr i32) (param $size i32)
+    local.get $ptr
+    local.get $size
+    i32.add
+    global.get $heap
+    i32.eq
+    if
+      local.get $ptr
+      global.set $heap
+    end)
+
+  (func (export "analyze") (param $ptr i32) (param $len i32) (result i64)
+    loop
+      br 0
+    end
+    i64.const 0))
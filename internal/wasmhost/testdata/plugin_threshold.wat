@@ -0,0 +1,54 @@
+;; Example detection plugin for the Mohawk plugin ABI, version 1.
+;;
+;; The host copies an update's weights (little-endian float64) into exported
+;; memory and calls analyze(ptr, len). This plugin flags any update whose
+;; first weight exceeds 10.0 with score 1000000 (certain) and flag bit 0
+;; (Byzantine), and passes every other update with score 0.
+;;
+;; Rebuild with: wat2wasm plugin_threshold.wat -o plugin_threshold.wasm
+(module
+  (memory (export "memory") 1)
+  (global $heap (mut i32) (i32.const 1024))
+
+  (func (export "abi_version") (result i32)
+    i32.const 1)
+
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    global.get $heap
+    local.set $ptr
+    global.get $heap
+    local.get $size
+    i32.add
+    global.set $heap
+    local.get $ptr)
+
+  (func (export "free") (param $ptr i32) (param $size i32)
+    local.get $ptr
+    local.get $size
+    i32.add
+    global.get $heap
+    i32.eq
+    if
+      local.get $ptr
+      global.set $heap
+    end)
+
+  (func (export "analyze") (param $ptr i32) (param $len i32) (result i64)
+    local.get $len
+    i32.const 8
+    i32.ge_u
+    if (result i64)
+      local.get $ptr
+      f64.load
+      f64.const 10
+      f64.gt
+      if (result i64)
+        ;; (1000000 << 32) | 1
+        i64.const 4294967296000001
+      else
+        i64.const 0
+      end
+    else
+      i64.const 0
+    end))
@@ -0,0 +1,58 @@
+;; Delayed verifier for latency tests: verify_proof spins for as many
+;; iterations as the proof's first four bytes give (little-endian), then
+;; accepts. Proofs shorter than four bytes are rejected at once.
+;;
+;; Rebuild with: wat2wasm verify_delay.wat -o verify_delay.wasm
+(module
+  (memory (export "memory") 1)
+  (global $heap (mut i32) (i32.const 1024))
+
+  (func (export "abi_version") (result i32)
+    i32.const 1)
+
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    global.get $heap
+    local.set $ptr
+    global.get $heap
+    local.get $size
+    i32.add
+    global.set $heap
+    local.get $ptr)
+
+  (func (export "free") (param $ptr i32) (param $size i32)
+    local.get $ptr
+    local.get $size
+    i32.add
+    global.get $heap
+    i32.eq
+    if
+      local.get $ptr
+      global.set $heap
+    end)
+
+  (func (export "verify_proof") (param $ptr i32) (param $len i32) (result i32)
+    (local $n i32)
+    local.get $len
+    i32.const 4
+    i32.lt_u
+    if
+      i32.const 0
+      return
+    end
+    local.get $ptr
+    i32.load
+    local.set $n
+    block
+      loop
+        local.get $n
+        i32.eqz
+        br_if 1
+        local.get $n
+        i32.const 1
+        i32.sub
+        local.set $n
+        br 0
+      end
+    end
+    i32.const 1))
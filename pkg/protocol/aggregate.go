@@ -0,0 +1,58 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+)
+
+// aggregateModelDomain separates aggregate model signatures from every
+// other digest an identity key signs.
+const aggregateModelDomain = "sovereign-mohawk/aggregate-model/v1"
+
+// ParticipantsDigest returns a hex SHA-256 over the sorted participant
+// IDs, each a big-endian uint32 length followed by its bytes, so the digest
+// does not depend on the order participants were listed in.
+func ParticipantsDigest(participants []string) string {
+	sorted := append([]string(nil), participants...)
+	sort.Strings(sorted)
+	h := sha256.New()
+	var length [4]byte
+	for _, nodeID := range sorted {
+		binary.BigEndian.PutUint32(length[:], uint32(len(nodeID)))
+		h.Write(length[:])
+		h.Write([]byte(nodeID))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// AggregateModelDigest is the digest an aggregator signs when it commits a
+// round's model. It is the SHA-256 of
+//
+//	domain ‖ round ‖ weightsDigest ‖ participantsDigest
+//
+// where round is a big-endian uint64 and each string is a big-endian
+// uint32 length followed by its bytes. weightsDigest is WeightsDigest of the
+// weights and participantsDigest is ParticipantsDigest of the round's
+// participants.
+func AggregateModelDigest(round int, weightsDigest, participantsDigest string) [32]byte {
+	buf := make([]byte, 0, 4*3+len(aggregateModelDomain)+8+len(weightsDigest)+len(participantsDigest))
+	appendString := func(s string) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(s)))
+		buf = append(buf, s...)
+	}
+	appendString(aggregateModelDomain)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(round))
+	appendString(weightsDigest)
+	appendString(participantsDigest)
+	return sha256.Sum256(buf)
+}
+
+// SigningDigest returns the AggregateModelDigest of m.
+func (m *AggregateModel) SigningDigest() [32]byte {
+	return AggregateModelDigest(m.Round, WeightsDigest(m.Weights), ParticipantsDigest(m.Participants))
+}
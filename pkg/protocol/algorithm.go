@@ -0,0 +1,74 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import "fmt"
+
+// AlgorithmID names the signature algorithm of an identity key and of the
+// signatures it makes. Messages carry it beside every public key and
+// signature so a verifier can refuse a signature claiming another
+// algorithm than the signer's registered key; empty means
+// AlgorithmECDSAP256, the only algorithm before Ed25519 was supported.
+type AlgorithmID string
+
+const (
+	// AlgorithmECDSAP256 is ECDSA on P-256 with the SHA-256 of the signed
+	// data as its hash, ASN.1 encoded.
+	AlgorithmECDSAP256 AlgorithmID = "ecdsa-p256"
+	// AlgorithmEd25519 is Ed25519 over the SHA-256 of the signed data.
+	AlgorithmEd25519 AlgorithmID = "ed25519"
+)
+
+// Algorithms lists the supported algorithms, the default first.
+func Algorithms() []AlgorithmID {
+	return []AlgorithmID{AlgorithmECDSAP256, AlgorithmEd25519}
+}
+
+// OrDefault returns a, or AlgorithmECDSAP256 when a is empty.
+func (a AlgorithmID) OrDefault() AlgorithmID {
+	if a == "" {
+		return AlgorithmECDSAP256
+	}
+	return a
+}
+
+// Validate fails with ErrUnsupportedAlgorithm unless a, or the default it
+// stands for, is supported.
+func (a AlgorithmID) Validate() error {
+	for _, supported := range Algorithms() {
+		if a.OrDefault() == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, string(a))
+}
+
+// ParseAlgorithmID parses an algorithm name, empty as the default.
+func ParseAlgorithmID(name string) (AlgorithmID, error) {
+	a := AlgorithmID(name).OrDefault()
+	if err := a.Validate(); err != nil {
+		return "", err
+	}
+	return a, nil
+}
+
+// CheckAlgorithm fails with ErrAlgorithmMismatch when a message declares
+// an algorithm other than registered, the algorithm of the key it is
+// verified against. An undeclared algorithm is the default.
+func CheckAlgorithm(declared, registered AlgorithmID) error {
+	if declared.OrDefault() != registered.OrDefault() {
+		return fmt.Errorf("%w: declared %s, key is %s", ErrAlgorithmMismatch, declared.OrDefault(), registered.OrDefault())
+	}
+	return nil
+}
+
+// SharesKeyAgreement reports whether identities of algorithms a and b can
+// derive a pairwise key: only when they are the same algorithm, as P-256
+// keys agree by ECDH on P-256 and Ed25519 keys by X25519. A mixed pair
+// still verifies each other's signatures, each by the other's registered
+// algorithm, but authenticates by signature wherever a same-algorithm pair
+// would use a pairwise key, such as vote MACs.
+func SharesKeyAgreement(a, b AlgorithmID) bool {
+	return a.OrDefault() == b.OrDefault()
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AttestationEnvelopeVersion is the wire format version produced by
+// AttestationEnvelope.Marshal.
+const AttestationEnvelopeVersion = 1
+
+// AttestationEnvelope is the wire format for a TPM attestation report. Binary
+// fields are base64 (std encoding), PCR values are hex keyed by the decimal
+// PCR index, and the attestation key is a PKIX public key in PEM.
+type AttestationEnvelope struct {
+	Version       int               `json:"version"`
+	NodeID        string            `json:"node_id"`
+	AttestationID string            `json:"attestation_id,omitempty"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Quote         string            `json:"quote"`
+	PCRs          map[string]string `json:"pcrs"`
+	Nonce         string            `json:"nonce"`
+	Signature     string            `json:"signature"`
+	AKPublicKey   string            `json:"ak_public_key"`
+}
+
+// Marshal returns the canonical encoding: fields in declaration order, PCR
+// keys sorted, the timestamp in UTC, and no insignificant whitespace. Equal
+// envelopes always encode to the same bytes.
+func (e *AttestationEnvelope) Marshal() ([]byte, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	canonical := *e
+	canonical.Timestamp = e.Timestamp.UTC()
+	return json.Marshal(canonical)
+}
+
+// UnmarshalAttestation decodes and validates an envelope. Unknown fields are
+// rejected so a sender cannot smuggle data past the digest.
+func UnmarshalAttestation(data []byte) (*AttestationEnvelope, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var envelope AttestationEnvelope
+	if err := decoder.Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("decode attestation envelope: %w", err)
+	}
+	if err := envelope.Validate(); err != nil {
+		return nil, err
+	}
+	return &envelope, nil
+}
+
+// Validate checks the version and that every encoded field decodes.
+func (e *AttestationEnvelope) Validate() error {
+	if e.Version != AttestationEnvelopeVersion {
+		return fmt.Errorf("unsupported attestation envelope version %d", e.Version)
+	}
+	if strings.TrimSpace(e.NodeID) == "" {
+		return fmt.Errorf("attestation envelope has no node id")
+	}
+	for name, value := range map[string]string{"quote": e.Quote, "nonce": e.Nonce, "signature": e.Signature} {
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			return fmt.Errorf("attestation envelope %s is not base64: %w", name, err)
+		}
+	}
+	for index, value := range e.PCRs {
+		if _, err := strconv.ParseUint(index, 10, 8); err != nil {
+			return fmt.Errorf("attestation envelope PCR index %q is invalid", index)
+		}
+		if _, err := hex.DecodeString(value); err != nil {
+			return fmt.Errorf("attestation envelope PCR %s is not hex: %w", index, err)
+		}
+	}
+	if !strings.HasPrefix(strings.TrimSpace(e.AKPublicKey), "-----BEGIN PUBLIC KEY-----") {
+		return fmt.Errorf("attestation envelope key is not a PEM public key")
+	}
+	return nil
+}
+
+// Digest returns a hex SHA-256 over the canonical encoding. Any change to
+// any field changes the digest.
+func (e *AttestationEnvelope) Digest() (string, error) {
+	encoded, err := e.Marshal()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
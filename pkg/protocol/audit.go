@@ -0,0 +1,221 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// An audit bundle is the evidence for one committed round, packaged so an
+// external auditor can check it offline with VerifyAuditBundle and nothing
+// but this package: a tar stream of JSON artifacts and an index listing
+// each artifact's size and SHA-256, signed by the node that exported it.
+
+// AuditBundleVersion is the audit bundle format version this package reads
+// and writes.
+const AuditBundleVersion = 1
+
+// auditIndexDomain separates audit index signatures from every other
+// digest an identity key signs.
+const auditIndexDomain = "sovereign-mohawk/audit-index/v1"
+
+// Audit bundle entries. The index is written last so artifacts can be
+// streamed before it.
+const (
+	AuditIndexPath         = "index.json"
+	AuditCommitPath        = "artifacts/commit.json"
+	AuditManifestPath      = "artifacts/manifest.json"
+	AuditVerificationsPath = "artifacts/verifications.json"
+	AuditKeysPath          = "artifacts/keys.json"
+	AuditAttestationsPath  = "artifacts/attestations.json"
+	AuditPrivacyPath       = "artifacts/privacy.json"
+	AuditReplayPath        = "artifacts/replay.json"
+)
+
+// AuditArtifactPaths lists the artifacts every bundle carries, in the
+// order they are written.
+func AuditArtifactPaths() []string {
+	return []string{
+		AuditCommitPath,
+		AuditManifestPath,
+		AuditVerificationsPath,
+		AuditKeysPath,
+		AuditAttestationsPath,
+		AuditPrivacyPath,
+		AuditReplayPath,
+	}
+}
+
+// Roles of the public keys an audit bundle carries.
+const (
+	// AuditRoleAggregator keys sign committed rounds.
+	AuditRoleAggregator = "aggregator"
+	// AuditRoleVerifier keys sign verification responses.
+	AuditRoleVerifier = "verifier"
+)
+
+// AuditArtifact is one artifact as the index lists it.
+type AuditArtifact struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// AuditIndex lists a bundle's artifacts and is signed by the exporter's
+// identity key, which it carries as PEM. An auditor who knows the
+// exporter's key out of band passes it to VerifyAuditBundle; otherwise the
+// signature shows only that the bundle is intact.
+type AuditIndex struct {
+	Version      int       `json:"version"`
+	FederationID string    `json:"federation_id"`
+	Round        int       `json:"round"`
+	ExportedAt   time.Time `json:"exported_at"`
+	ExporterID   string    `json:"exporter_id"`
+	// ExporterKey is the exporter's PEM identity public key, P-256 or
+	// Ed25519.
+	ExporterKey        []byte          `json:"exporter_key"`
+	Artifacts          []AuditArtifact `json:"artifacts"`
+	SignatureAlgorithm AlgorithmID     `json:"signature_algorithm,omitempty"`
+	Signature          []byte          `json:"signature,omitempty"`
+}
+
+// SigningDigest is the digest the exporter signs: the SHA-256 of the
+// domain, as a big-endian uint32 length and its bytes, followed by the
+// index's JSON encoding without its signature.
+func (i *AuditIndex) SigningDigest() ([32]byte, error) {
+	unsigned := *i
+	unsigned.ExportedAt = i.ExportedAt.UTC()
+	unsigned.Signature = nil
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	buf := binary.BigEndian.AppendUint32(nil, uint32(len(auditIndexDomain)))
+	buf = append(buf, auditIndexDomain...)
+	return sha256.Sum256(append(buf, payload...)), nil
+}
+
+// Artifact returns the index entry for path.
+func (i *AuditIndex) Artifact(path string) (AuditArtifact, bool) {
+	for _, artifact := range i.Artifacts {
+		if artifact.Path == path {
+			return artifact, true
+		}
+	}
+	return AuditArtifact{}, false
+}
+
+// AuditCertificate is the commit certificate of an audited round: the
+// consensus proposal it committed and the validators that approved it.
+type AuditCertificate struct {
+	Round       int      `json:"round"`
+	ProposalID  string   `json:"proposal_id"`
+	ModelDigest string   `json:"model_digest"`
+	QuorumSize  int      `json:"quorum_size"`
+	Approvals   []string `json:"approvals"`
+}
+
+// AuditCommit is the committed round an audit bundle is about: the model
+// digest and participants the aggregator signed, under the certificate
+// that committed them.
+type AuditCommit struct {
+	Round              int              `json:"round"`
+	ModelDigest        string           `json:"model_digest"`
+	ParticipantCount   int              `json:"participant_count"`
+	Certificate        AuditCertificate `json:"certificate"`
+	CommittedAt        time.Time        `json:"committed_at"`
+	ManifestDigest     string           `json:"manifest_digest,omitempty"`
+	ParticipantsDigest string           `json:"participants_digest,omitempty"`
+	// Signature is the aggregator's signature over SigningDigest, by the
+	// identity key SignerFingerprint names, in SignatureAlgorithm.
+	Signature          []byte      `json:"signature,omitempty"`
+	SignerFingerprint  string      `json:"signer_fingerprint,omitempty"`
+	SignatureAlgorithm AlgorithmID `json:"signature_algorithm,omitempty"`
+}
+
+// SigningDigest is the AggregateModelDigest the aggregator signed for the
+// round.
+func (c *AuditCommit) SigningDigest() [32]byte {
+	return AggregateModelDigest(c.Round, c.ModelDigest, c.ParticipantsDigest)
+}
+
+// AuditVerification is one verifier's signed verdict on one participant's
+// update, with the update it was asked about.
+type AuditVerification struct {
+	RequestID    string       `json:"request_id"`
+	ProposerID   string       `json:"proposer_id"`
+	Round        int          `json:"round"`
+	UpdateDigest string       `json:"update_digest"`
+	VerifierID   string       `json:"verifier_id"`
+	Valid        bool         `json:"valid"`
+	ReasonCode   reasons.Code `json:"reason_code,omitempty"`
+	// Timestamp is the response time the verifier signed, to the second.
+	Timestamp          time.Time   `json:"timestamp"`
+	Signature          []byte      `json:"signature"`
+	SignatureAlgorithm AlgorithmID `json:"signature_algorithm,omitempty"`
+}
+
+// Digest is the VerificationResponseDigest the verifier signed.
+func (v *AuditVerification) Digest() [32]byte {
+	return VerificationResponseDigest(v.RequestID, v.VerifierID, v.Valid, string(v.ReasonCode), v.Timestamp)
+}
+
+// AuditKey is a public key an audit bundle's signatures verify against.
+type AuditKey struct {
+	NodeID string `json:"node_id"`
+	Role   string `json:"role"`
+	// PublicKey is the node's PEM identity public key, P-256 or Ed25519.
+	PublicKey []byte `json:"public_key"`
+}
+
+// AuditBudget is an (ε, δ) differential privacy guarantee.
+type AuditBudget struct {
+	Epsilon float64 `json:"epsilon"`
+	Delta   float64 `json:"delta"`
+}
+
+// AuditShard is one shard accountant's position: Rounds releases at
+// PerRound compose sequentially to Spent, within Allocation.
+type AuditShard struct {
+	ShardID    string      `json:"shard_id"`
+	Allocation AuditBudget `json:"allocation"`
+	PerRound   AuditBudget `json:"per_round"`
+	Spent      AuditBudget `json:"spent"`
+	Rounds     int         `json:"rounds"`
+}
+
+// AuditPrivacy is the federation's privacy accounting as of the audited
+// round.
+type AuditPrivacy struct {
+	Round  int          `json:"round"`
+	Budget AuditBudget  `json:"budget"`
+	Spent  AuditBudget  `json:"spent"`
+	Shards []AuditShard `json:"shards,omitempty"`
+	// Releases is the central DP aggregator's log of noised aggregates,
+	// oldest first, when the federation has one.
+	Releases []PrivacyRelease `json:"releases,omitempty"`
+}
+
+// AuditReplay is a consensus replay log entry: the commit of one proposal
+// as the coordinator recorded it.
+type AuditReplay struct {
+	Round           int               `json:"round"`
+	ProposalID      string            `json:"proposal_id"`
+	ProposerID      string            `json:"proposer_id"`
+	WeightsDigest   string            `json:"weights_digest"`
+	ManifestDigest  string            `json:"manifest_digest,omitempty"`
+	Approvals       []string          `json:"approvals"`
+	QuorumSize      int               `json:"quorum_size"`
+	MembershipEpoch uint64            `json:"membership_epoch"`
+	CommittedAt     time.Time         `json:"committed_at"`
+	VoteDigests     map[string]string `json:"vote_digests,omitempty"`
+	// Equivocations carries the coordinator's equivocation proofs as it
+	// encoded them; the bundle does not interpret them.
+	Equivocations json.RawMessage `json:"equivocations,omitempty"`
+}
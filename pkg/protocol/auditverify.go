@@ -0,0 +1,553 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"archive/tar"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// maxAuditEntrySize bounds one audit bundle entry.
+const maxAuditEntrySize = 1 << 30
+
+// AuditFinding is one problem an audit found, attributed to the bundle
+// entry at fault.
+type AuditFinding struct {
+	Artifact string `json:"artifact"`
+	Detail   string `json:"detail"`
+}
+
+// AuditReport is the outcome of verifying an audit bundle.
+type AuditReport struct {
+	Index    *AuditIndex    `json:"index"`
+	Findings []AuditFinding `json:"findings,omitempty"`
+}
+
+// Valid reports whether the audit found nothing wrong.
+func (r *AuditReport) Valid() bool {
+	return len(r.Findings) == 0
+}
+
+// Faulty returns the entries with findings, sorted.
+func (r *AuditReport) Faulty() []string {
+	seen := make(map[string]bool)
+	var faulty []string
+	for _, finding := range r.Findings {
+		if !seen[finding.Artifact] {
+			seen[finding.Artifact] = true
+			faulty = append(faulty, finding.Artifact)
+		}
+	}
+	sort.Strings(faulty)
+	return faulty
+}
+
+// VerifyAuditBundle checks an audit bundle offline. It verifies the index
+// signature, against trustedKey when one is given, then that every
+// artifact is present and matches the index, then what the intact
+// artifacts claim: that the aggregator signed the committed round, the
+// certificate reaches its quorum, the manifest, replay entry, and privacy
+// accounting agree with the commit, each sampled verification response is
+// signed by its verifier about an update the manifest lists, and each
+// attestation envelope is well formed and a participant's. An artifact
+// that fails its digest is reported once and not checked further, so
+// damage to one artifact is attributed to it alone; against an index whose
+// signature fails, the mismatch is attributed to the index instead.
+//
+// It fails with ErrInvalidAuditBundle only when there is no index to
+// check against; every other problem is a finding in the report.
+func VerifyAuditBundle(r io.Reader, trustedKey []byte) (*AuditReport, error) {
+	entries := make(map[string][]byte)
+	var order []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			// A truncated stream still yields the entries read so far;
+			// what it lost is reported against the index below.
+			break
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size < 0 || hdr.Size > maxAuditEntrySize {
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(tr, hdr.Size))
+		if err != nil || int64(len(body)) != hdr.Size {
+			continue
+		}
+		if _, seen := entries[hdr.Name]; !seen {
+			order = append(order, hdr.Name)
+		}
+		entries[hdr.Name] = body
+	}
+
+	raw, ok := entries[AuditIndexPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: no %s", ErrInvalidAuditBundle, AuditIndexPath)
+	}
+	var index AuditIndex
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, fmt.Errorf("%w: decode index: %v", ErrInvalidAuditBundle, err)
+	}
+	if index.Version != AuditBundleVersion {
+		return nil, fmt.Errorf("%w: version %d, this build reads %d", ErrInvalidAuditBundle, index.Version, AuditBundleVersion)
+	}
+
+	a := &auditor{report: &AuditReport{Index: &index}, index: &index}
+	a.checkIndex(trustedKey)
+	a.readArtifacts(entries, order)
+	a.checkKeys()
+	a.checkCommit()
+	a.checkManifest()
+	a.checkVerifications()
+	a.checkAttestations()
+	a.checkPrivacy()
+	a.checkReplay()
+	return a.report, nil
+}
+
+// auditor holds a bundle under verification. Each artifact field is nil
+// unless the artifact matched the index and decoded.
+type auditor struct {
+	report *AuditReport
+	index  *AuditIndex
+
+	commit        *AuditCommit
+	manifest      *ContributionManifest
+	verifications []AuditVerification
+	keys          []AuditKey
+	attestations  []AttestationEnvelope
+	privacy       *AuditPrivacy
+	replay        []AuditReplay
+	intact        map[string]bool
+	// indexSigned is whether the index signature verified. Against an
+	// index that does not, a mismatched artifact is the index's fault as
+	// likely as its own.
+	indexSigned bool
+
+	// roleKeys maps each role to its nodes' parsed keys.
+	roleKeys map[string]map[string]auditPublicKey
+}
+
+func (a *auditor) fail(artifact, format string, args ...interface{}) {
+	a.report.Findings = append(a.report.Findings, AuditFinding{Artifact: artifact, Detail: fmt.Sprintf(format, args...)})
+}
+
+// checkIndex verifies the exporter's signature on the index.
+func (a *auditor) checkIndex(trustedKey []byte) {
+	exporter, err := parseAuditKey(a.index.ExporterKey)
+	if err != nil {
+		a.fail(AuditIndexPath, "exporter key: %v", err)
+		return
+	}
+	if len(trustedKey) > 0 {
+		trusted, err := parseAuditKey(trustedKey)
+		if err != nil {
+			a.fail(AuditIndexPath, "trusted key: %v", err)
+			return
+		}
+		if trusted.fingerprint != exporter.fingerprint {
+			a.fail(AuditIndexPath, "exported by %s under key %s, not the trusted key %s", a.index.ExporterID, exporter.fingerprint, trusted.fingerprint)
+			return
+		}
+	}
+	digest, err := a.index.SigningDigest()
+	if err != nil {
+		a.fail(AuditIndexPath, "encode index: %v", err)
+		return
+	}
+	if err := exporter.verify(a.index.SignatureAlgorithm, digest[:], a.index.Signature); err != nil {
+		a.fail(AuditIndexPath, "signature by %s: %v", a.index.ExporterID, err)
+		return
+	}
+	a.indexSigned = true
+}
+
+// readArtifacts checks every artifact against the index and decodes the
+// intact ones.
+func (a *auditor) readArtifacts(entries map[string][]byte, order []string) {
+	a.intact = make(map[string]bool)
+	targets := map[string]interface{}{
+		AuditCommitPath:        &a.commit,
+		AuditManifestPath:      &a.manifest,
+		AuditVerificationsPath: &a.verifications,
+		AuditKeysPath:          &a.keys,
+		AuditAttestationsPath:  &a.attestations,
+		AuditPrivacyPath:       &a.privacy,
+		AuditReplayPath:        &a.replay,
+	}
+	listed := make(map[string]bool, len(a.index.Artifacts))
+	for _, artifact := range a.index.Artifacts {
+		if listed[artifact.Path] {
+			a.fail(AuditIndexPath, "lists %s twice", artifact.Path)
+		}
+		listed[artifact.Path] = true
+		if _, known := targets[artifact.Path]; !known {
+			a.fail(AuditIndexPath, "lists unknown artifact %s", artifact.Path)
+		}
+	}
+	for _, path := range order {
+		if path != AuditIndexPath && !listed[path] {
+			a.fail(path, "not listed in the index")
+		}
+	}
+
+	for _, path := range AuditArtifactPaths() {
+		artifact, ok := a.index.Artifact(path)
+		if !ok {
+			a.fail(path, "not listed in the index")
+			continue
+		}
+		body, ok := entries[path]
+		if !ok {
+			a.fail(path, "missing from the bundle")
+			continue
+		}
+		mismatch := ""
+		switch {
+		case int64(len(body)) != artifact.Size:
+			mismatch = fmt.Sprintf("size %d, index says %d", len(body), artifact.Size)
+		case auditChecksum(body) != artifact.SHA256:
+			mismatch = fmt.Sprintf("SHA-256 %s, index says %s", auditChecksum(body), artifact.SHA256)
+		}
+		if mismatch != "" {
+			if a.indexSigned {
+				a.fail(path, "%s", mismatch)
+			} else {
+				a.fail(AuditIndexPath, "%s: %s", path, mismatch)
+			}
+			continue
+		}
+		if err := json.Unmarshal(body, targets[path]); err != nil {
+			a.fail(path, "decode: %v", err)
+			continue
+		}
+		a.intact[path] = true
+	}
+	// A decoding failure may have left a partial value behind.
+	if !a.intact[AuditCommitPath] {
+		a.commit = nil
+	}
+	if !a.intact[AuditManifestPath] {
+		a.manifest = nil
+	}
+	if !a.intact[AuditPrivacyPath] {
+		a.privacy = nil
+	}
+}
+
+// checkKeys parses the bundle's public keys.
+func (a *auditor) checkKeys() {
+	if !a.intact[AuditKeysPath] {
+		return
+	}
+	a.roleKeys = map[string]map[string]auditPublicKey{AuditRoleAggregator: {}, AuditRoleVerifier: {}}
+	for _, key := range a.keys {
+		nodes, known := a.roleKeys[key.Role]
+		if !known {
+			a.fail(AuditKeysPath, "%s has unknown role %q", key.NodeID, key.Role)
+			continue
+		}
+		if _, dup := nodes[key.NodeID]; dup {
+			a.fail(AuditKeysPath, "%s %s listed twice", key.Role, key.NodeID)
+			continue
+		}
+		parsed, err := parseAuditKey(key.PublicKey)
+		if err != nil {
+			a.fail(AuditKeysPath, "%s %s: %v", key.Role, key.NodeID, err)
+			continue
+		}
+		nodes[key.NodeID] = parsed
+	}
+}
+
+// checkCommit verifies the round's certificate and the aggregator's
+// signature on it.
+func (a *auditor) checkCommit() {
+	c := a.commit
+	if c == nil {
+		return
+	}
+	if c.Round != a.index.Round {
+		a.fail(AuditCommitPath, "round %d, index says %d", c.Round, a.index.Round)
+	}
+	cert := c.Certificate
+	if cert.Round != c.Round || cert.ModelDigest != c.ModelDigest {
+		a.fail(AuditCommitPath, "certificate is for round %d model %s, not round %d model %s", cert.Round, cert.ModelDigest, c.Round, c.ModelDigest)
+	}
+	if approvals := distinct(cert.Approvals); cert.QuorumSize <= 0 || len(approvals) < cert.QuorumSize {
+		a.fail(AuditCommitPath, "certificate has %d distinct approvals of a quorum of %d", len(approvals), cert.QuorumSize)
+	}
+
+	if len(c.Signature) == 0 {
+		a.fail(AuditCommitPath, "round %d is unsigned", c.Round)
+		return
+	}
+	if a.roleKeys == nil {
+		// Without the keys there is nothing to check the signature
+		// against; the damaged keys artifact is already reported.
+		return
+	}
+	var signer *auditPublicKey
+	for _, key := range a.roleKeys[AuditRoleAggregator] {
+		if key.fingerprint == c.SignerFingerprint {
+			signer = &key
+			break
+		}
+	}
+	if signer == nil {
+		a.fail(AuditCommitPath, "signed by %s, which is no aggregator key in the bundle", c.SignerFingerprint)
+		return
+	}
+	digest := c.SigningDigest()
+	if err := signer.verify(c.SignatureAlgorithm, digest[:], c.Signature); err != nil {
+		a.fail(AuditCommitPath, "aggregator signature: %v", err)
+	}
+}
+
+// checkManifest holds the contribution manifest to the commit, which the
+// aggregator signed.
+func (a *auditor) checkManifest() {
+	m := a.manifest
+	if m == nil {
+		return
+	}
+	if m.Round != a.index.Round {
+		a.fail(AuditManifestPath, "round %d, index says %d", m.Round, a.index.Round)
+	}
+	if a.commit == nil {
+		return
+	}
+	if a.commit.ManifestDigest != "" && m.Digest() != a.commit.ManifestDigest {
+		a.fail(AuditManifestPath, "digest %s, committed digest %s", m.Digest(), a.commit.ManifestDigest)
+	}
+	if a.commit.ParticipantsDigest != "" && ParticipantsDigest(m.IncludedNodes()) != a.commit.ParticipantsDigest {
+		a.fail(AuditManifestPath, "included nodes are not the participants the aggregator signed")
+	}
+}
+
+// checkVerifications verifies each sampled response's signature and that
+// it is about an update the manifest lists.
+func (a *auditor) checkVerifications() {
+	if !a.intact[AuditVerificationsPath] {
+		return
+	}
+	for _, v := range a.verifications {
+		name := fmt.Sprintf("response of %s to %s", v.VerifierID, v.RequestID)
+		if v.Round != a.index.Round {
+			a.fail(AuditVerificationsPath, "%s is for round %d", name, v.Round)
+		}
+		if a.manifest != nil {
+			entry, ok := a.manifest.Entry(v.ProposerID)
+			switch {
+			case !ok:
+				a.fail(AuditVerificationsPath, "%s verifies an update of %s, who is not in the manifest", name, v.ProposerID)
+			case entry.UpdateDigest != v.UpdateDigest:
+				a.fail(AuditVerificationsPath, "%s verifies update %s, the manifest records %s for %s", name, v.UpdateDigest, entry.UpdateDigest, v.ProposerID)
+			}
+		}
+		if a.roleKeys == nil {
+			continue
+		}
+		key, ok := a.roleKeys[AuditRoleVerifier][v.VerifierID]
+		if !ok {
+			a.fail(AuditVerificationsPath, "%s: the bundle has no key for the verifier", name)
+			continue
+		}
+		digest := v.Digest()
+		if err := key.verify(v.SignatureAlgorithm, digest[:], v.Signature); err != nil {
+			a.fail(AuditVerificationsPath, "%s: %v", name, err)
+		}
+	}
+}
+
+// checkAttestations validates each envelope and that it is a
+// participant's.
+func (a *auditor) checkAttestations() {
+	if !a.intact[AuditAttestationsPath] {
+		return
+	}
+	for i := range a.attestations {
+		envelope := &a.attestations[i]
+		if err := envelope.Validate(); err != nil {
+			a.fail(AuditAttestationsPath, "envelope %d: %v", i, err)
+			continue
+		}
+		if a.manifest != nil {
+			if _, ok := a.manifest.Entry(envelope.NodeID); !ok {
+				a.fail(AuditAttestationsPath, "envelope of %s, who is not in the manifest", envelope.NodeID)
+			}
+		}
+	}
+}
+
+// checkPrivacy checks that spending composes and stays within budget.
+func (a *auditor) checkPrivacy() {
+	p := a.privacy
+	if p == nil {
+		return
+	}
+	if p.Round != a.index.Round {
+		a.fail(AuditPrivacyPath, "round %d, index says %d", p.Round, a.index.Round)
+	}
+	if exceedsBudget(p.Spent, p.Budget) {
+		a.fail(AuditPrivacyPath, "spent (ε=%g, δ=%g) of a budget of (ε=%g, δ=%g)", p.Spent.Epsilon, p.Spent.Delta, p.Budget.Epsilon, p.Budget.Delta)
+	}
+	for _, shard := range p.Shards {
+		composed := AuditBudget{Epsilon: float64(shard.Rounds) * shard.PerRound.Epsilon, Delta: float64(shard.Rounds) * shard.PerRound.Delta}
+		if !closeTo(composed.Epsilon, shard.Spent.Epsilon) || !closeTo(composed.Delta, shard.Spent.Delta) {
+			a.fail(AuditPrivacyPath, "shard %s spent (ε=%g, δ=%g), %d rounds compose to (ε=%g, δ=%g)", shard.ShardID, shard.Spent.Epsilon, shard.Spent.Delta, shard.Rounds, composed.Epsilon, composed.Delta)
+		}
+		if exceedsBudget(shard.Spent, shard.Allocation) {
+			a.fail(AuditPrivacyPath, "shard %s spent (ε=%g, δ=%g) of an allocation of (ε=%g, δ=%g)", shard.ShardID, shard.Spent.Epsilon, shard.Spent.Delta, shard.Allocation.Epsilon, shard.Allocation.Delta)
+		}
+	}
+}
+
+// checkReplay finds the round's commit in the replay segment and holds it
+// to the certificate.
+func (a *auditor) checkReplay() {
+	if !a.intact[AuditReplayPath] {
+		return
+	}
+	var entry *AuditReplay
+	for i := range a.replay {
+		if a.replay[i].Round != a.index.Round {
+			a.fail(AuditReplayPath, "entry for proposal %s is of round %d", a.replay[i].ProposalID, a.replay[i].Round)
+			continue
+		}
+		if entry != nil {
+			a.fail(AuditReplayPath, "round %d committed twice", a.index.Round)
+			continue
+		}
+		entry = &a.replay[i]
+	}
+	if entry == nil {
+		a.fail(AuditReplayPath, "no commit of round %d", a.index.Round)
+		return
+	}
+	if approvals := distinct(entry.Approvals); entry.QuorumSize <= 0 || len(approvals) < entry.QuorumSize {
+		a.fail(AuditReplayPath, "%d distinct approvals of a quorum of %d", len(approvals), entry.QuorumSize)
+	}
+	c := a.commit
+	if c == nil {
+		return
+	}
+	if entry.ProposalID != c.Certificate.ProposalID {
+		a.fail(AuditReplayPath, "committed proposal %s, the certificate %s", entry.ProposalID, c.Certificate.ProposalID)
+	}
+	if entry.WeightsDigest != c.ModelDigest {
+		a.fail(AuditReplayPath, "committed model %s, the aggregator signed %s", entry.WeightsDigest, c.ModelDigest)
+	}
+	if entry.ManifestDigest != "" && c.ManifestDigest != "" && entry.ManifestDigest != c.ManifestDigest {
+		a.fail(AuditReplayPath, "committed manifest %s, the commit %s", entry.ManifestDigest, c.ManifestDigest)
+	}
+	if entry.QuorumSize != c.Certificate.QuorumSize || !equalSets(entry.Approvals, c.Certificate.Approvals) {
+		a.fail(AuditReplayPath, "approvals differ from the certificate's")
+	}
+}
+
+// auditPublicKey is a parsed identity public key.
+type auditPublicKey struct {
+	key         stdcrypto.PublicKey
+	algorithm   AlgorithmID
+	fingerprint string
+}
+
+// parseAuditKey parses a PEM identity public key, P-256 or Ed25519, and
+// computes its fingerprint: the hex SHA-256 of its PKIX DER, prefixed with
+// the algorithm for Ed25519.
+func parseAuditKey(publicKeyPEM []byte) (auditPublicKey, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return auditPublicKey{}, errors.New("no PEM block")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return auditPublicKey{}, err
+	}
+	parsed := auditPublicKey{key: key, fingerprint: auditChecksum(block.Bytes)}
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return auditPublicKey{}, fmt.Errorf("%w: ECDSA on %s", ErrUnsupportedAlgorithm, k.Curve.Params().Name)
+		}
+		parsed.algorithm = AlgorithmECDSAP256
+	case ed25519.PublicKey:
+		parsed.algorithm = AlgorithmEd25519
+		parsed.fingerprint = string(AlgorithmEd25519) + ":" + parsed.fingerprint
+	default:
+		return auditPublicKey{}, fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, key)
+	}
+	return parsed, nil
+}
+
+// verify checks a signature made as identity keys sign: over the SHA-256
+// of data, ASN.1 encoded for ECDSA.
+func (k auditPublicKey) verify(declared AlgorithmID, data, signature []byte) error {
+	if err := CheckAlgorithm(declared, k.algorithm); err != nil {
+		return err
+	}
+	hash := sha256.Sum256(data)
+	var ok bool
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(key, hash[:], signature)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(key, hash[:], signature)
+	}
+	if !ok {
+		return errors.New("signature does not verify")
+	}
+	return nil
+}
+
+func auditChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func distinct(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func equalSets(a, b []string) bool {
+	as, bs := distinct(a), distinct(b)
+	if len(as) != len(bs) {
+		return false
+	}
+	for id := range as {
+		if !bs[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// exceedsBudget reports whether spent is past budget, allowing for
+// floating point error in composition.
+func exceedsBudget(spent, budget AuditBudget) bool {
+	return spent.Epsilon > budget.Epsilon && !closeTo(spent.Epsilon, budget.Epsilon) ||
+		spent.Delta > budget.Delta && !closeTo(spent.Delta, budget.Delta)
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
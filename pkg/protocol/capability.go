@@ -0,0 +1,110 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// CPUClass buckets a node's compute for scheduling.
+type CPUClass string
+
+const (
+	CPUClassLow      CPUClass = "low"
+	CPUClassStandard CPUClass = "standard"
+	CPUClassHigh     CPUClass = "high"
+)
+
+// CPUClassFor buckets a core count: up to 2 cores is low, up to 8 standard.
+func CPUClassFor(cores int) CPUClass {
+	switch {
+	case cores <= 2:
+		return CPUClassLow
+	case cores <= 8:
+		return CPUClassStandard
+	default:
+		return CPUClassHigh
+	}
+}
+
+// Material change thresholds for CapabilityManifest.MaterialChange.
+const (
+	// materialResourceChange is the relative change in memory or bandwidth
+	// that warrants re-submitting a manifest.
+	materialResourceChange = 0.2
+	// materialLatencyChange is the relative change in wasm verification
+	// latency that warrants re-submitting a manifest.
+	materialLatencyChange = 0.5
+)
+
+// CapabilityManifest describes what a node can run. Nodes submit it at
+// registration and again whenever it changes materially.
+type CapabilityManifest struct {
+	NodeID   string   `json:"node_id"`
+	CPUClass CPUClass `json:"cpu_class"`
+	CPUCount int      `json:"cpu_count"`
+	// MemoryBytes is the memory available to the node: its cgroup limit
+	// when one is set.
+	MemoryBytes int64 `json:"memory_bytes"`
+	WasmSIMD    bool  `json:"wasm_simd"`
+	TPMPresent  bool  `json:"tpm_present"`
+	// BandwidthBytesPerSec estimates upload bandwidth; zero if unknown.
+	BandwidthBytesPerSec int64 `json:"bandwidth_bytes_per_sec"`
+	// WasmVerifyMicros is the measured latency of one wasm proof
+	// verification; zero if not measured.
+	WasmVerifyMicros int64     `json:"wasm_verify_micros"`
+	ProbedAt         time.Time `json:"probed_at"`
+}
+
+// Validate checks that the manifest is complete and consistent.
+func (m *CapabilityManifest) Validate() error {
+	if m.NodeID == "" {
+		return fmt.Errorf("capability manifest has no node id")
+	}
+	if m.CPUCount <= 0 {
+		return fmt.Errorf("capability manifest for %s has %d cpus", m.NodeID, m.CPUCount)
+	}
+	if m.CPUClass != CPUClassFor(m.CPUCount) {
+		return fmt.Errorf("capability manifest for %s claims cpu class %q for %d cpus", m.NodeID, m.CPUClass, m.CPUCount)
+	}
+	if m.MemoryBytes <= 0 {
+		return fmt.Errorf("capability manifest for %s has %d bytes of memory", m.NodeID, m.MemoryBytes)
+	}
+	if m.BandwidthBytesPerSec < 0 || m.WasmVerifyMicros < 0 {
+		return fmt.Errorf("capability manifest for %s has a negative estimate", m.NodeID)
+	}
+	return nil
+}
+
+// WasmVerifyLatency returns WasmVerifyMicros as a duration.
+func (m *CapabilityManifest) WasmVerifyLatency() time.Duration {
+	return time.Duration(m.WasmVerifyMicros) * time.Microsecond
+}
+
+// MaterialChange reports whether m differs enough from previous to be
+// re-submitted: a different CPU class, SIMD or TPM support, a 20% change in
+// memory or bandwidth, or a 50% change in verification latency.
+func (m *CapabilityManifest) MaterialChange(previous *CapabilityManifest) bool {
+	if previous == nil {
+		return true
+	}
+	if m.CPUClass != previous.CPUClass || m.WasmSIMD != previous.WasmSIMD || m.TPMPresent != previous.TPMPresent {
+		return true
+	}
+	return relativeChange(m.MemoryBytes, previous.MemoryBytes) >= materialResourceChange ||
+		relativeChange(m.BandwidthBytesPerSec, previous.BandwidthBytesPerSec) >= materialResourceChange ||
+		relativeChange(m.WasmVerifyMicros, previous.WasmVerifyMicros) >= materialLatencyChange
+}
+
+func relativeChange(current, previous int64) float64 {
+	if previous == 0 {
+		if current == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return math.Abs(float64(current-previous)) / float64(previous)
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by protocol validation. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrInvalidModelSpec means a model spec is incomplete or its layers do
+	// not add up to its dimension. Not retryable.
+	ErrInvalidModelSpec = errors.New("invalid model spec")
+	// ErrModelSpecMismatch means a message was built for another version or
+	// shape of the model than the registered spec. Not retryable with the
+	// same message; retrain against the current spec.
+	ErrModelSpecMismatch = errors.New("model spec mismatch")
+	// ErrPersonalCoordinates means an update carries nonzero values in the
+	// spec's personal segments, which must never leave their node. Not
+	// retryable with the same update; strip it with ModelSpec.Strip.
+	ErrPersonalCoordinates = errors.New("update carries personal coordinates")
+	// ErrUnsignedModel means a distributed model carries no signature
+	// where its aggregator's was required. Not retryable with the same
+	// payload.
+	ErrUnsignedModel = errors.New("model is unsigned")
+	// ErrModelSignature means a distributed model's signature is not its
+	// aggregator's, or does not cover its round, weights, or participants.
+	// Not retryable with the same payload.
+	ErrModelSignature = errors.New("model signature invalid")
+	// ErrInvalidWeights means a weights payload is neither a canonical
+	// encoding nor a whole number of float64 values. Not retryable with the
+	// same payload.
+	ErrInvalidWeights = errors.New("invalid model weights")
+	// ErrUnsupportedAlgorithm means a key or signature names an algorithm
+	// this node does not implement. Not retryable with the same key.
+	ErrUnsupportedAlgorithm = errors.New("unsupported signature algorithm")
+	// ErrAlgorithmMismatch means a message declares a signature algorithm
+	// other than that of the key it must verify against. Not retryable with
+	// the same message.
+	ErrAlgorithmMismatch = errors.New("signature algorithm mismatch")
+	// ErrInvalidAuditBundle means an audit bundle cannot be read at all: it
+	// is not a tar stream, or its index is missing, undecodable, or of an
+	// unknown version. Damage to the artifacts is reported as findings
+	// instead. Not retryable with the same bundle.
+	ErrInvalidAuditBundle = errors.New("invalid audit bundle")
+	// ErrInvalidReceipt means an inclusion receipt is unsigned, is not
+	// signed by its aggregator, or names another round, node, or manifest
+	// than the committed round binds. Not retryable with the same receipt.
+	ErrInvalidReceipt = errors.New("invalid inclusion receipt")
+)
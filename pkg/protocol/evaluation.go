@@ -0,0 +1,110 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"fmt"
+	"time"
+)
+
+// Metrics an evaluation round can ask for.
+const (
+	MetricLoss     = "loss"
+	MetricAccuracy = "accuracy"
+)
+
+// MetricDefinition names a metric an evaluation round measures and the
+// range a node clamps its value to before reporting it, which bounds one
+// node's influence on the global estimate.
+type MetricDefinition struct {
+	Name  string  `json:"name"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// DefaultEvaluationMetrics asks for loss and accuracy, both in [0, 1].
+func DefaultEvaluationMetrics() []MetricDefinition {
+	return []MetricDefinition{
+		{Name: MetricLoss, Lower: 0, Upper: 1},
+		{Name: MetricAccuracy, Lower: 0, Upper: 1},
+	}
+}
+
+// EvaluationTask asks a sampled node to measure a committed global model on
+// its local data. The node trains nothing and returns only an
+// EvaluationReport of aggregate metrics.
+type EvaluationTask struct {
+	Round        int    `json:"round"`
+	ModelDigest  string `json:"model_digest"`
+	FederationID string `json:"federation_id,omitempty"`
+	// Metrics lists what to measure; a node reports Loss and Accuracy for
+	// those it names.
+	Metrics  []MetricDefinition `json:"metrics"`
+	Deadline time.Time          `json:"deadline"`
+}
+
+// Validate checks that the task names a model and well-formed metrics.
+func (t EvaluationTask) Validate() error {
+	if t.Round <= 0 {
+		return fmt.Errorf("evaluation task has invalid round %d", t.Round)
+	}
+	if t.ModelDigest == "" {
+		return fmt.Errorf("evaluation task for round %d has no model digest", t.Round)
+	}
+	if len(t.Metrics) == 0 {
+		return fmt.Errorf("evaluation task for round %d names no metrics", t.Round)
+	}
+	for _, metric := range t.Metrics {
+		if metric.Name != MetricLoss && metric.Name != MetricAccuracy {
+			return fmt.Errorf("evaluation task for round %d names unknown metric %q", t.Round, metric.Name)
+		}
+		if !(metric.Upper > metric.Lower) {
+			return fmt.Errorf("evaluation metric %s has empty range [%g, %g]", metric.Name, metric.Lower, metric.Upper)
+		}
+	}
+	return nil
+}
+
+// Metric returns the task's definition of name.
+func (t EvaluationTask) Metric(name string) (MetricDefinition, bool) {
+	for _, metric := range t.Metrics {
+		if metric.Name == name {
+			return metric, true
+		}
+	}
+	return MetricDefinition{}, false
+}
+
+// EvaluationReport is a node's answer to an EvaluationTask: its metrics over
+// its local evaluation data, and nothing about the data itself.
+type EvaluationReport struct {
+	NodeID       string    `json:"node_id"`
+	Round        int       `json:"round"`
+	ModelDigest  string    `json:"model_digest"`
+	FederationID string    `json:"federation_id,omitempty"`
+	Metrics      Metrics   `json:"metrics"`
+	Timestamp    time.Time `json:"timestamp"`
+	// NoiseScale is the Laplace scale of the local DP noise added to each
+	// of Loss and Accuracy, or zero when they are exact. The evaluator
+	// widens its confidence intervals by it.
+	NoiseScale float64 `json:"noise_scale,omitempty"`
+}
+
+// Validate checks that the report answers a round with a positive sample
+// count.
+func (r EvaluationReport) Validate() error {
+	if r.NodeID == "" {
+		return fmt.Errorf("evaluation report has no node id")
+	}
+	if r.Round <= 0 {
+		return fmt.Errorf("evaluation report from %s has invalid round %d", r.NodeID, r.Round)
+	}
+	if r.ModelDigest == "" {
+		return fmt.Errorf("evaluation report from %s has no model digest", r.NodeID)
+	}
+	if r.Metrics.Samples < 0 || r.NoiseScale < 0 {
+		return fmt.Errorf("evaluation report from %s has negative samples or noise scale", r.NodeID)
+	}
+	return nil
+}
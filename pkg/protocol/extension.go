@@ -0,0 +1,117 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// A node that projects, from its local progress rate, missing its
+// TrainingTask's deadline sends the scheduler an ExtensionRequest. When
+// enough of a round's participants ask, the scheduler extends the round
+// once and broadcasts the new deadline as a DeadlineExtension, so a cohort
+// that is slow together, such as a region charging overnight, finishes a
+// few minutes late rather than failing the round.
+
+const extensionRequestDomain = "sovereign-mohawk/extension-request/v1"
+
+// ExtensionRequest is a node's signed projection that it will miss a
+// round's deadline.
+type ExtensionRequest struct {
+	NodeID       string `json:"node_id"`
+	FederationID string `json:"federation_id,omitempty"`
+	Round        int    `json:"round"`
+	// Deadline is the deadline the node is working to.
+	Deadline time.Time `json:"deadline"`
+	// ProjectedFinish is when the node expects to finish at its current
+	// rate; see ProjectFinish.
+	ProjectedFinish time.Time `json:"projected_finish"`
+	// Progress is the fraction of the task done when the node asked.
+	Progress  float64   `json:"progress"`
+	Timestamp time.Time `json:"timestamp"`
+	// Signature is the node's identity signature over SigningDigest, in
+	// SignatureAlgorithm.
+	Signature          []byte      `json:"signature,omitempty"`
+	SignatureAlgorithm AlgorithmID `json:"signature_algorithm,omitempty"`
+}
+
+// NewExtensionRequest returns nodeID's request to extend task, begun at
+// started and progress done at now, and whether it needs one: whether its
+// projected finish falls after the task's deadline.
+func NewExtensionRequest(nodeID string, task TrainingTask, started, now time.Time, progress float64) (ExtensionRequest, bool) {
+	finish, ok := ProjectFinish(started, now, progress)
+	request := ExtensionRequest{
+		NodeID:          nodeID,
+		FederationID:    task.FederationID,
+		Round:           task.Round,
+		Deadline:        task.Deadline,
+		ProjectedFinish: finish,
+		Progress:        progress,
+		Timestamp:       now,
+	}
+	return request, ok && finish.After(task.Deadline)
+}
+
+// ProjectFinish extrapolates when a task begun at started and progress
+// done at now will finish at the rate it has made so far. It reports false
+// when no progress has been made to extrapolate from.
+func ProjectFinish(started, now time.Time, progress float64) (time.Time, bool) {
+	elapsed := now.Sub(started)
+	if progress <= 0 || elapsed <= 0 {
+		return time.Time{}, false
+	}
+	if progress >= 1 {
+		return now, true
+	}
+	return started.Add(time.Duration(float64(elapsed) / progress)), true
+}
+
+// SigningDigest is the digest the node signs. It is the SHA-256 of
+//
+//	domain ‖ nodeID ‖ federationID ‖ round ‖ deadline ‖ projectedFinish ‖ timestamp
+//
+// with round and the times, as Unix nanoseconds, big-endian uint64s and
+// each string a big-endian uint32 length followed by its bytes. Progress
+// is advisory and not signed.
+func (r *ExtensionRequest) SigningDigest() [32]byte {
+	buf := make([]byte, 0, 128)
+	appendString := func(s string) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(s))) // #nosec G115 -- request fields are far below 4 GiB
+		buf = append(buf, s...)
+	}
+	appendString(extensionRequestDomain)
+	appendString(r.NodeID)
+	appendString(r.FederationID)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(r.Round)) // #nosec G115 -- rounds are positive
+	for _, at := range []time.Time{r.Deadline, r.ProjectedFinish, r.Timestamp} {
+		buf = binary.BigEndian.AppendUint64(buf, uint64(at.UnixNano())) // #nosec G115 -- the sign bit is hashed as is
+	}
+	return sha256.Sum256(buf)
+}
+
+// DeadlineExtension is the scheduler's broadcast of a round's extended
+// deadline.
+type DeadlineExtension struct {
+	FederationID     string    `json:"federation_id,omitempty"`
+	Round            int       `json:"round"`
+	PreviousDeadline time.Time `json:"previous_deadline"`
+	Deadline         time.Time `json:"deadline"`
+	// Requesters are the participants whose requests carried the
+	// extension, of Selected selected for the round.
+	Requesters []string  `json:"requesters"`
+	Selected   int       `json:"selected"`
+	GrantedAt  time.Time `json:"granted_at"`
+}
+
+// Extend moves t's deadline to the extension's, if the extension is for
+// t's round and later than its deadline, and reports whether it did.
+func (t *TrainingTask) Extend(extension DeadlineExtension) bool {
+	if extension.Round != t.Round || extension.FederationID != t.FederationID || !extension.Deadline.After(t.Deadline) {
+		return false
+	}
+	t.Deadline = extension.Deadline
+	return true
+}
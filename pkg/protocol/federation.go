@@ -0,0 +1,39 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import "fmt"
+
+// DefaultFederation is the federation of a message that names none, so a
+// host serving a single federation needs no configuration.
+const DefaultFederation = "default"
+
+// maxFederationIDLength bounds federation IDs, which appear in API paths
+// and metric labels.
+const maxFederationIDLength = 64
+
+// FederationOf returns id, or DefaultFederation when id is empty.
+func FederationOf(id string) string {
+	if id == "" {
+		return DefaultFederation
+	}
+	return id
+}
+
+// ValidateFederationID checks that id is 1 to 64 lowercase letters, digits,
+// hyphens, and underscores, so it is safe in a URL path segment and a
+// metric label.
+func ValidateFederationID(id string) error {
+	if id == "" || len(id) > maxFederationIDLength {
+		return fmt.Errorf("federation id must be 1 to %d characters, got %d", maxFederationIDLength, len(id))
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return fmt.Errorf("federation id %q may only contain lowercase letters, digits, '-' and '_'", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// Exclusion reasons recorded in contribution manifests.
+const (
+	ReasonIncluded     = reasons.Included
+	ReasonNormOutlier  = reasons.NormOutlier
+	ReasonNoSamples    = reasons.NoSamples
+	ReasonStaleUpdate  = reasons.StaleUpdate
+	ReasonKrumRejected = reasons.KrumRejected
+	ReasonDetected     = reasons.ByzantineDetected
+)
+
+// ContributionEntry records how one participant's update was treated during
+// aggregation. Only the update digest is recorded, never the update itself.
+type ContributionEntry struct {
+	NodeID        string       `json:"node_id"`
+	UpdateDigest  string       `json:"update_digest"`
+	SampleCount   int          `json:"sample_count"`
+	AppliedWeight float64      `json:"applied_weight"`
+	Included      bool         `json:"included"`
+	Reason        reasons.Code `json:"reason"`
+	// QuantizationErrorBound is the worst-case L2 error introduced by
+	// dequantizing an int8 update; zero for float updates.
+	QuantizationErrorBound float64 `json:"quantization_error_bound,omitempty"`
+	// UploadTier is the precision the update arrived at. It does not affect
+	// AppliedWeight, which depends only on SampleCount and, for nodes on
+	// probation, their reduced update weight.
+	UploadTier UploadTier `json:"upload_tier,omitempty"`
+	// BaseDigest is the global model digest the update's training
+	// statement claims it started from.
+	BaseDigest string `json:"base_digest,omitempty"`
+	// DetectionScore is the combined built-in and plugin suspicion score,
+	// recorded when detection plugins are configured.
+	DetectionScore float64 `json:"detection_score,omitempty"`
+	// ConsensusWeight is the factor a retry scaled the update's weight by
+	// after consensus found it suspicious but did not exclude it; zero when
+	// it was not scaled.
+	ConsensusWeight float64 `json:"consensus_weight,omitempty"`
+}
+
+// ContributionManifest lists who contributed what to an aggregated model.
+type ContributionManifest struct {
+	Round   int                 `json:"round"`
+	Entries []ContributionEntry `json:"entries"`
+	// Privacy records the central differential privacy noise added to the
+	// aggregate, when the aggregator adds any.
+	Privacy *PrivacyRelease `json:"privacy,omitempty"`
+}
+
+// PrivacyRelease records one noised release of an aggregate: the cohort it
+// was computed over, the noise it carries, and the (ε, δ) the privacy
+// accountant charged for it.
+type PrivacyRelease struct {
+	Round int `json:"round"`
+	// Participants is the number of updates actually included, which the
+	// noise was calibrated to; ExpectedCohort is the number the deployment
+	// planned for.
+	Participants   int `json:"participants"`
+	ExpectedCohort int `json:"expected_cohort"`
+	// L2Sensitivity bounds one participant's influence on the aggregate:
+	// the clip norm times the largest applied weight.
+	L2Sensitivity float64 `json:"l2_sensitivity"`
+	// NoiseScale is the standard deviation of the Gaussian noise added to
+	// each coordinate.
+	NoiseScale float64 `json:"noise_scale"`
+	Epsilon    float64 `json:"epsilon"`
+	Delta      float64 `json:"delta"`
+}
+
+// UpdateDigest returns the hex SHA-256 digest recorded for an update.
+func UpdateDigest(weights []byte) string {
+	sum := sha256.Sum256(weights)
+	return hex.EncodeToString(sum[:])
+}
+
+// Digest returns a hex SHA-256 over the canonical manifest encoding. Entries
+// are ordered by node ID so the digest does not depend on arrival order.
+func (m *ContributionManifest) Digest() string {
+	if m == nil {
+		return ""
+	}
+	canonical := ContributionManifest{
+		Round:   m.Round,
+		Entries: append([]ContributionEntry(nil), m.Entries...),
+		Privacy: m.Privacy,
+	}
+	sort.Slice(canonical.Entries, func(i, j int) bool {
+		return canonical.Entries[i].NodeID < canonical.Entries[j].NodeID
+	})
+	encoded, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// IncludedNodes returns the node IDs whose updates were applied, sorted.
+func (m *ContributionManifest) IncludedNodes() []string {
+	if m == nil {
+		return nil
+	}
+	nodes := make([]string, 0, len(m.Entries))
+	for _, entry := range m.Entries {
+		if entry.Included {
+			nodes = append(nodes, entry.NodeID)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Entry returns the manifest entry for nodeID.
+func (m *ContributionManifest) Entry(nodeID string) (ContributionEntry, bool) {
+	if m == nil {
+		return ContributionEntry{}, false
+	}
+	for _, entry := range m.Entries {
+		if entry.NodeID == nodeID {
+			return entry, true
+		}
+	}
+	return ContributionEntry{}, false
+}
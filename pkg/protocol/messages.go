@@ -15,6 +15,7 @@
 package protocol
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -26,6 +27,35 @@ type ModelUpdate struct {
 	Proof     []byte    `json:"proof"`
 	Timestamp time.Time `json:"timestamp"`
 	Metrics   Metrics   `json:"metrics"`
+	// Quantized carries an int8 update in place of Weights.
+	Quantized *QuantizedUpdate `json:"quantized,omitempty"`
+	// Sparse carries a top-k update in place of Weights.
+	Sparse *SparseUpdate `json:"sparse,omitempty"`
+	// Statement binds the update to the global model it was trained from.
+	Statement *TrainingStatement `json:"statement,omitempty"`
+	// FederationID names the federation the update was trained for. Empty
+	// means DefaultFederation.
+	FederationID string `json:"federation_id,omitempty"`
+	// SpecVersion is the version of the federation's ModelSpec the update
+	// was trained against, as distributed in its TrainingTask.
+	SpecVersion int `json:"spec_version,omitempty"`
+	// Certificate, on a regional summary, proves the regional shard
+	// committed the weights.
+	Certificate *CertificateChain `json:"certificate,omitempty"`
+	// Origin, on an update synced from a node's island cache, is the key
+	// the update was cached under. Live updates carry none.
+	Origin *UpdateOrigin `json:"origin,omitempty"`
+}
+
+// UpdateOrigin is the key of one logical submission: the node, the round
+// it trained for, and the UpdateDigest of the update's canonical encoding.
+// A node's island cache records it with every update it caches, so the
+// aggregator can tell the cached copy synced on return from a live update
+// the node submitted for the same round.
+type UpdateOrigin struct {
+	NodeID       string `json:"node_id"`
+	Round        int    `json:"round"`
+	UpdateDigest string `json:"update_digest"`
 }
 
 // Metrics holds training metrics
@@ -37,17 +67,57 @@ type Metrics struct {
 
 // AggregateModel represents the aggregated global model
 type AggregateModel struct {
-	Round        int       `json:"round"`
-	Weights      []byte    `json:"weights"`
-	Participants []string  `json:"participants"`
-	Timestamp    time.Time `json:"timestamp"`
+	Round          int                   `json:"round"`
+	Weights        []byte                `json:"weights"`
+	Participants   []string              `json:"participants"`
+	Timestamp      time.Time             `json:"timestamp"`
+	Manifest       *ContributionManifest `json:"manifest,omitempty"`
+	ManifestDigest string                `json:"manifest_digest,omitempty"`
+	FederationID   string                `json:"federation_id,omitempty"`
+	// Signature is the committing aggregator's signature over
+	// SigningDigest, by the identity key SignerFingerprint names, in
+	// SignatureAlgorithm.
+	Signature          []byte      `json:"signature,omitempty"`
+	SignerFingerprint  string      `json:"signer_fingerprint,omitempty"`
+	SignatureAlgorithm AlgorithmID `json:"signature_algorithm,omitempty"`
 }
 
 // RegistrationRequest is sent by a node to join the federation
 type RegistrationRequest struct {
-	NodeID      string `json:"node_id"`
-	Capacity    int    `json:"capacity"`
+	NodeID   string `json:"node_id"`
+	Capacity int    `json:"capacity"`
+	// TPMAttestat carries a canonically encoded AttestationEnvelope.
 	TPMAttestat []byte `json:"tpm_attestation,omitempty"`
+	// PublicKey is the node's PEM channel identity key, against which its
+	// signed verification responses are checked. KeyAlgorithm, when set,
+	// must be the key's algorithm.
+	PublicKey    []byte      `json:"public_key,omitempty"`
+	KeyAlgorithm AlgorithmID `json:"key_algorithm,omitempty"`
+	// Capabilities describes the node's hardware for admission and
+	// participant selection.
+	Capabilities *CapabilityManifest `json:"capabilities,omitempty"`
+	// Federations lists the federations the node joins. Empty joins
+	// DefaultFederation.
+	Federations []string `json:"federations,omitempty"`
+	// GenesisDigest is the genesis digest the node pinned, if any. An
+	// aggregator started from another genesis refuses the registration.
+	GenesisDigest string `json:"genesis_digest,omitempty"`
+}
+
+// Attestation decodes the request's attestation envelope and checks that it
+// was issued for the registering node.
+func (r *RegistrationRequest) Attestation() (*AttestationEnvelope, error) {
+	if len(r.TPMAttestat) == 0 {
+		return nil, fmt.Errorf("registration for %s has no attestation", r.NodeID)
+	}
+	envelope, err := UnmarshalAttestation(r.TPMAttestat)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.NodeID != r.NodeID {
+		return nil, fmt.Errorf("attestation for %s does not match registering node %s", envelope.NodeID, r.NodeID)
+	}
+	return envelope, nil
 }
 
 // RegistrationResponse confirms node registration
@@ -55,6 +125,18 @@ type RegistrationResponse struct {
 	NodeID   string `json:"node_id"`
 	Approved bool   `json:"approved"`
 	Round    int    `json:"round"`
+	// Federations lists the federations the node was bound to.
+	Federations []string `json:"federations,omitempty"`
+	// Probationary is set while the node serves its admission probation:
+	// it cannot propose or verify, and its votes and updates count for
+	// less.
+	Probationary bool `json:"probationary,omitempty"`
+	// GenesisDigest is the digest of the genesis the aggregator started
+	// from; a node that pinned another must not join.
+	GenesisDigest string `json:"genesis_digest,omitempty"`
+	// ShardAssignment is the node's signed shard assignment, which it
+	// presents to verifiers to prove its shard membership.
+	ShardAssignment *ShardAssignment `json:"shard_assignment,omitempty"`
 }
 
 // TrainingTask is sent to nodes to start a training round
@@ -64,13 +146,31 @@ type TrainingTask struct {
 	Epochs        int       `json:"epochs"`
 	LearningRate  float64   `json:"learning_rate"`
 	Deadline      time.Time `json:"deadline"`
+	FederationID  string    `json:"federation_id,omitempty"`
+	// Spec is the model the round trains; updates echo its version.
+	Spec *ModelSpec `json:"model_spec,omitempty"`
 }
 
 // StatusUpdate is sent periodically by nodes
 type StatusUpdate struct {
 	NodeID    string    `json:"node_id"`
-	Status    string    `json:"status"` // training, idle, error
+	Status    string    `json:"status"` // training, idle, error, paused
 	Round     int       `json:"round"`
 	Progress  float64   `json:"progress"`
 	Timestamp time.Time `json:"timestamp"`
+	// Metrics optionally reports local training metrics with the heartbeat.
+	Metrics *Metrics `json:"metrics,omitempty"`
+	// UploadTier reports the precision the node chose for this round's
+	// update, so a declared skip is not mistaken for a missed deadline.
+	UploadTier UploadTier `json:"upload_tier,omitempty"`
+	// ShutdownSnapshotID names the crash snapshot the node recovered from,
+	// sent on its first heartbeat after restarting.
+	ShutdownSnapshotID string `json:"shutdown_snapshot_id,omitempty"`
+	// FederationID names the federation whose round the status reports on.
+	FederationID string `json:"federation_id,omitempty"`
+	// AttestationDegraded reports that the node's TPM stopped answering, so
+	// it has no fresh hardware quote until the device is reopened.
+	AttestationDegraded bool `json:"attestation_degraded,omitempty"`
+	// Module reports the node's active verifier module.
+	Module *ModuleStatus `json:"module,omitempty"`
 }
@@ -0,0 +1,161 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// LayerShape is one layer of a model architecture.
+type LayerShape struct {
+	Name  string `json:"name"`
+	Shape []int  `json:"shape"`
+}
+
+// Size is the number of parameters in the layer.
+func (l LayerShape) Size() int {
+	size := 1
+	for _, d := range l.Shape {
+		size *= d
+	}
+	return size
+}
+
+// ModelSpec declares the model a federation trains. Every update, decoded
+// payload, and proposal is checked against the registered spec, so a node
+// training another version or shape is refused where it first touches the
+// federation rather than deep in aggregation.
+type ModelSpec struct {
+	Name string `json:"name"`
+	// Version starts at 1 and increases by one with each coordinated spec
+	// change.
+	Version int `json:"version"`
+	// Dimension is the flattened parameter count every update must carry.
+	Dimension int `json:"dimension"`
+	// Layers, when given, must add up to Dimension.
+	Layers []LayerShape `json:"layers,omitempty"`
+	// ArchitectureDigest is the hex SHA-256 of the architecture
+	// description the spec was derived from.
+	ArchitectureDigest string `json:"architecture_digest"`
+	// Personal, when given, partitions the model: the coordinates these
+	// segments cover are each node's own fine-tuning layers and never leave
+	// it. Updates, aggregates, and distributed models carry only the
+	// remaining global coordinates, GlobalDimension of them; see Strip.
+	Personal []Segment `json:"personal,omitempty"`
+}
+
+// Validate checks that the spec is complete and self-consistent.
+func (s *ModelSpec) Validate() error {
+	switch {
+	case s.Name == "":
+		return fmt.Errorf("%w: no name", ErrInvalidModelSpec)
+	case s.Version <= 0:
+		return fmt.Errorf("%w: %s has version %d", ErrInvalidModelSpec, s.Name, s.Version)
+	case s.Dimension <= 0:
+		return fmt.Errorf("%w: %s has dimension %d", ErrInvalidModelSpec, s.Name, s.Dimension)
+	case len(s.ArchitectureDigest) != 64:
+		return fmt.Errorf("%w: %s has no architecture digest", ErrInvalidModelSpec, s.Name)
+	}
+	if err := s.validatePersonal(); err != nil {
+		return err
+	}
+	if len(s.Layers) == 0 {
+		return nil
+	}
+	total := 0
+	for _, layer := range s.Layers {
+		if len(layer.Shape) == 0 {
+			return fmt.Errorf("%w: %s layer %q has no shape", ErrInvalidModelSpec, s.Name, layer.Name)
+		}
+		for _, d := range layer.Shape {
+			if d <= 0 {
+				return fmt.Errorf("%w: %s layer %q has shape %v", ErrInvalidModelSpec, s.Name, layer.Name, layer.Shape)
+			}
+		}
+		total += layer.Size()
+	}
+	if total != s.Dimension {
+		return fmt.Errorf("%w: %s layers hold %d parameters, dimension is %d", ErrInvalidModelSpec, s.Name, total, s.Dimension)
+	}
+	return nil
+}
+
+// Digest is the hex SHA-256 over the spec's canonical JSON encoding. A spec
+// change's consensus record commits to it.
+func (s *ModelSpec) Digest() string {
+	encoded, _ := json.Marshal(s)
+	return UpdateDigest(encoded)
+}
+
+// CheckVersion fails with ErrModelSpecMismatch unless version is the spec's.
+func (s *ModelSpec) CheckVersion(version int) error {
+	if version != s.Version {
+		return fmt.Errorf("%w: built for %s v%d, registered spec is v%d", ErrModelSpecMismatch, s.Name, version, s.Version)
+	}
+	return nil
+}
+
+// CheckDimension fails with ErrModelSpecMismatch unless dim is the spec's
+// global dimension, the length of every update and model the federation
+// exchanges.
+func (s *ModelSpec) CheckDimension(dim int) error {
+	if global := s.GlobalDimension(); dim != global {
+		return fmt.Errorf("%w: %d weights, %s v%d has %d", ErrModelSpecMismatch, dim, s.Name, s.Version, global)
+	}
+	return nil
+}
+
+// CheckUpdate checks an update's declared spec version and the length of
+// whichever weight encoding it carries, without decoding the weights. On a
+// partitioned spec, an update of the full dimension fails with
+// ErrPersonalCoordinates when any of its personal coordinates is nonzero,
+// and with ErrModelSpecMismatch otherwise.
+func (s *ModelSpec) CheckUpdate(update *ModelUpdate) error {
+	if err := s.CheckVersion(update.SpecVersion); err != nil {
+		return fmt.Errorf("node %s: %w", update.NodeID, err)
+	}
+	var dim int
+	switch {
+	case update.Quantized != nil:
+		dim = update.Quantized.Len()
+	case update.Sparse != nil:
+		dim = update.Sparse.Dim
+	case len(update.Weights)%8 != 0:
+		return fmt.Errorf("node %s: %w: %d bytes is not a whole number of float64 weights", update.NodeID, ErrModelSpecMismatch, len(update.Weights))
+	default:
+		dim = len(update.Weights) / 8
+	}
+	err := s.CheckDimension(dim)
+	if err != nil && s.Partitioned() && dim == s.Dimension {
+		err = s.checkStripped(update)
+	}
+	if err != nil {
+		return fmt.Errorf("node %s: %w", update.NodeID, err)
+	}
+	return nil
+}
+
+// DecodeSparse decodes a sparse update's Bytes encoding, which must declare
+// exactly the spec's global dimension.
+func (s *ModelSpec) DecodeSparse(buf []byte) (*SparseUpdate, error) {
+	if len(buf) >= 4 {
+		if err := s.CheckDimension(int(binary.LittleEndian.Uint32(buf))); err != nil {
+			return nil, err
+		}
+	}
+	return DecodeSparseUpdate(buf, s.GlobalDimension())
+}
+
+// DecodeQuantized decodes a quantized update's Bytes encoding, which must
+// carry exactly the spec's global dimension.
+func (s *ModelSpec) DecodeQuantized(buf []byte) (*QuantizedUpdate, error) {
+	if len(buf) >= quantizedHeaderBytes {
+		if err := s.CheckDimension(len(buf) - quantizedHeaderBytes); err != nil {
+			return nil, err
+		}
+	}
+	return DecodeQuantizedUpdate(buf)
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+// ModuleProposal proposes a proof-verifier wasm module for a campaign's
+// nodes. Nodes install a module only once a proposal naming its digest
+// has committed through consensus.
+type ModuleProposal struct {
+	CampaignID string `json:"campaign_id"`
+	// Digest is the hex SHA-256 of the module, as wasmhost.ModuleDigest
+	// computes it; Size is its length in bytes.
+	Digest     string `json:"digest"`
+	Size       int64  `json:"size"`
+	ABIVersion int    `json:"abi_version"`
+	// FetchURLs are tried in order; each must serve the module's bytes
+	// and honour Range requests.
+	FetchURLs []string `json:"fetch_urls"`
+	Changelog string   `json:"changelog,omitempty"`
+}
+
+// ModuleStatus reports the verifier module a node is running, sent with
+// its heartbeats so the aggregator can follow a rollout.
+type ModuleStatus struct {
+	CampaignID string `json:"campaign_id"`
+	Digest     string `json:"digest"`
+	// Verifications and Failures count the proofs the module has verified
+	// since the node installed it, and those that ended in an error rather
+	// than a verdict.
+	Verifications uint64 `json:"verifications"`
+	Failures      uint64 `json:"failures"`
+}
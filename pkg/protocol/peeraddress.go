@@ -0,0 +1,71 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+const (
+	peerAddressDomain      = "sovereign-peer-address/v1"
+	addressChallengeDomain = "sovereign-address-challenge/v1"
+)
+
+// PeerAddressUpdate announces the addresses a node can be reached at. It
+// is signed by the node's own identity key, so relaying peers can carry it
+// but cannot alter it, and Sequence increases with every announcement, so
+// a replayed older update loses to the one that replaced it.
+type PeerAddressUpdate struct {
+	NodeID    string   `json:"node_id"`
+	Addresses []string `json:"addresses"`
+	Sequence  uint64   `json:"sequence"`
+	// Signature is the node's signature over SigningDigest, by its
+	// identity key, in SignatureAlgorithm.
+	Signature          []byte      `json:"signature,omitempty"`
+	SignatureAlgorithm AlgorithmID `json:"signature_algorithm,omitempty"`
+}
+
+// SigningDigest is the digest the node signs when it announces u. It is
+// the SHA-256 of
+//
+//	domain ‖ nodeID ‖ sequence ‖ count ‖ address…
+//
+// where sequence is a big-endian uint64, count a big-endian uint32, and
+// each string a big-endian uint32 length followed by its bytes.
+func (u *PeerAddressUpdate) SigningDigest() [32]byte {
+	buf := make([]byte, 0, 128)
+	appendString := func(s string) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(s))) // #nosec G115 -- record fields are far below 4 GiB
+		buf = append(buf, s...)
+	}
+	appendString(peerAddressDomain)
+	appendString(u.NodeID)
+	buf = binary.BigEndian.AppendUint64(buf, u.Sequence)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(u.Addresses))) // #nosec G115 -- a node has a handful of addresses
+	for _, address := range u.Addresses {
+		appendString(address)
+	}
+	return sha256.Sum256(buf)
+}
+
+// AddressChallengeDigest is the digest a node signs to answer a challenge
+// sent directly to one of its announced addresses, proving it holds its
+// identity key at that address. It is the SHA-256 of
+//
+//	domain ‖ nodeID ‖ address ‖ nonce
+//
+// with each field a big-endian uint32 length followed by its bytes.
+func AddressChallengeDigest(nodeID, address string, nonce []byte) [32]byte {
+	buf := make([]byte, 0, 128)
+	appendBytes := func(b []byte) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(b))) // #nosec G115 -- challenge fields are far below 4 GiB
+		buf = append(buf, b...)
+	}
+	appendBytes([]byte(addressChallengeDomain))
+	appendBytes([]byte(nodeID))
+	appendBytes([]byte(address))
+	appendBytes(nonce)
+	return sha256.Sum256(buf)
+}
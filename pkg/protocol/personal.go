@@ -0,0 +1,146 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Segment is a contiguous run of coordinates of the full, flattened model.
+type Segment struct {
+	Name   string `json:"name"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+}
+
+// End is the coordinate just past the segment.
+func (g Segment) End() int {
+	return g.Offset + g.Length
+}
+
+// validatePersonal checks that the personal segments are in order, do not
+// overlap, fall within the model, and leave some of it global.
+func (s *ModelSpec) validatePersonal() error {
+	end := 0
+	for _, segment := range s.Personal {
+		switch {
+		case segment.Length <= 0:
+			return fmt.Errorf("%w: %s personal segment %q has length %d", ErrInvalidModelSpec, s.Name, segment.Name, segment.Length)
+		case segment.Offset < end:
+			return fmt.Errorf("%w: %s personal segment %q at %d overlaps or precedes the one before it", ErrInvalidModelSpec, s.Name, segment.Name, segment.Offset)
+		case segment.End() > s.Dimension:
+			return fmt.Errorf("%w: %s personal segment %q ends at %d, past dimension %d", ErrInvalidModelSpec, s.Name, segment.Name, segment.End(), s.Dimension)
+		}
+		end = segment.End()
+	}
+	if s.Partitioned() && s.GlobalDimension() <= 0 {
+		return fmt.Errorf("%w: %s personal segments cover the whole model", ErrInvalidModelSpec, s.Name)
+	}
+	return nil
+}
+
+// Partitioned reports whether the spec reserves personal coordinates.
+func (s *ModelSpec) Partitioned() bool {
+	return len(s.Personal) > 0
+}
+
+// GlobalDimension is the number of coordinates outside the personal
+// segments: Dimension for a spec without any.
+func (s *ModelSpec) GlobalDimension() int {
+	dim := s.Dimension
+	for _, segment := range s.Personal {
+		dim -= segment.Length
+	}
+	return dim
+}
+
+// Strip returns the global coordinates of full, a node's Dimension-long
+// local model, in order. A node strips its model before submitting it.
+func (s *ModelSpec) Strip(full []float64) ([]float64, error) {
+	if len(full) != s.Dimension {
+		return nil, fmt.Errorf("%w: %d weights to strip, %s v%d has %d", ErrModelSpecMismatch, len(full), s.Name, s.Version, s.Dimension)
+	}
+	global := make([]float64, 0, s.GlobalDimension())
+	start := 0
+	for _, segment := range s.Personal {
+		global = append(global, full[start:segment.Offset]...)
+		start = segment.End()
+	}
+	return append(global, full[start:]...), nil
+}
+
+// Merge returns local, a node's Dimension-long model, with its global
+// coordinates replaced by global's and its personal ones kept. A nil local
+// starts the personal segments at zero.
+func (s *ModelSpec) Merge(global, local []float64) ([]float64, error) {
+	if err := s.CheckDimension(len(global)); err != nil {
+		return nil, err
+	}
+	full := make([]float64, s.Dimension)
+	if local != nil {
+		if len(local) != s.Dimension {
+			return nil, fmt.Errorf("%w: %d local weights, %s v%d has %d", ErrModelSpecMismatch, len(local), s.Name, s.Version, s.Dimension)
+		}
+		copy(full, local)
+	}
+	start, next := 0, 0
+	for _, segment := range s.Personal {
+		next += copy(full[start:segment.Offset], global[next:])
+		start = segment.End()
+	}
+	copy(full[start:], global[next:])
+	return full, nil
+}
+
+// personal reports whether coordinate i of the full model is personal.
+func (s *ModelSpec) personal(i int) bool {
+	for _, segment := range s.Personal {
+		if i < segment.Offset {
+			return false
+		}
+		if i < segment.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStripped explains why a full-dimension update is refused: with
+// ErrPersonalCoordinates when it leaks a nonzero personal coordinate, with
+// ErrModelSpecMismatch when it is merely unstripped.
+func (s *ModelSpec) checkStripped(update *ModelUpdate) error {
+	leaked := -1
+	if update.Sparse != nil {
+		for k, i := range update.Sparse.Indices {
+			if k < len(update.Sparse.Values) && update.Sparse.Values[k] != 0 && s.personal(int(i)) {
+				leaked = int(i)
+				break
+			}
+		}
+	} else {
+		value := func(i int) float64 {
+			return math.Float64frombits(binary.LittleEndian.Uint64(update.Weights[i*8:]))
+		}
+		if update.Quantized != nil {
+			value = func(i int) float64 {
+				return update.Quantized.Scale * float64(int(int8(update.Quantized.Payload[i]))-int(update.Quantized.ZeroPoint))
+			}
+		}
+	scan:
+		for _, segment := range s.Personal {
+			for i := segment.Offset; i < segment.End(); i++ {
+				if value(i) != 0 {
+					leaked = i
+					break scan
+				}
+			}
+		}
+	}
+	if leaked >= 0 {
+		return fmt.Errorf("%w: coordinate %d of %s v%d is personal", ErrPersonalCoordinates, leaked, s.Name, s.Version)
+	}
+	return fmt.Errorf("%w: %d weights include the personal segments; %s v%d updates carry the %d global ones", ErrModelSpecMismatch, s.Dimension, s.Name, s.Version, s.GlobalDimension())
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// QuantizedUpdate is an int8 affine-quantized model update. Element i
+// dequantizes to Scale * (int8(Payload[i]) - ZeroPoint).
+type QuantizedUpdate struct {
+	Scale     float64 `json:"scale"`
+	ZeroPoint int8    `json:"zero_point"`
+	Payload   []byte  `json:"payload"`
+}
+
+// Quantize encodes weights as int8. The range always includes zero so that
+// zero weights survive exactly; rounding error is at most Scale/2 per element.
+func Quantize(weights []float64) (*QuantizedUpdate, error) {
+	lo, hi := 0.0, 0.0
+	for _, w := range weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("cannot quantize non-finite weight %v", w)
+		}
+		lo = math.Min(lo, w)
+		hi = math.Max(hi, w)
+	}
+
+	q := &QuantizedUpdate{Payload: make([]byte, len(weights))}
+	if hi == lo {
+		return q, nil
+	}
+	q.Scale = (hi - lo) / 255
+	q.ZeroPoint = int8(clampInt8(math.Round(-128 - lo/q.Scale)))
+	for i, w := range weights {
+		q.Payload[i] = byte(int8(clampInt8(math.Round(w/q.Scale) + float64(q.ZeroPoint))))
+	}
+	return q, nil
+}
+
+// Len returns the number of quantized elements.
+func (q *QuantizedUpdate) Len() int {
+	return len(q.Payload)
+}
+
+// Dequantize expands the payload back to float64.
+func (q *QuantizedUpdate) Dequantize() []float64 {
+	weights := make([]float64, len(q.Payload))
+	for i, b := range q.Payload {
+		weights[i] = q.Scale * float64(int(int8(b))-int(q.ZeroPoint))
+	}
+	return weights
+}
+
+// MaxMagnitude is the largest absolute value the declared scale and zero
+// point can represent, whatever the payload holds.
+func (q *QuantizedUpdate) MaxMagnitude() float64 {
+	zp := float64(q.ZeroPoint)
+	return q.Scale * math.Max(math.Abs(math.MinInt8-zp), math.Abs(math.MaxInt8-zp))
+}
+
+// ErrorBound is the worst-case L2 distance between the dequantized update
+// and the weights it was quantized from: Scale/2 per element.
+func (q *QuantizedUpdate) ErrorBound() float64 {
+	return q.Scale / 2 * math.Sqrt(float64(len(q.Payload)))
+}
+
+// Validate checks that the quantization parameters are usable.
+func (q *QuantizedUpdate) Validate() error {
+	if math.IsNaN(q.Scale) || math.IsInf(q.Scale, 0) || q.Scale < 0 {
+		return fmt.Errorf("invalid quantization scale %v", q.Scale)
+	}
+	return nil
+}
+
+// quantizedHeaderBytes is the scale and zero point preceding the payload in
+// the Bytes encoding.
+const quantizedHeaderBytes = 9
+
+// Bytes is the canonical wire encoding digested into contribution
+// manifests: little-endian scale, zero point, then the payload.
+func (q *QuantizedUpdate) Bytes() []byte {
+	buf := make([]byte, quantizedHeaderBytes+len(q.Payload))
+	binary.LittleEndian.PutUint64(buf, math.Float64bits(q.Scale))
+	buf[8] = byte(q.ZeroPoint)
+	copy(buf[quantizedHeaderBytes:], q.Payload)
+	return buf
+}
+
+// DecodeQuantizedUpdate parses the Bytes encoding.
+func DecodeQuantizedUpdate(buf []byte) (*QuantizedUpdate, error) {
+	if len(buf) < quantizedHeaderBytes {
+		return nil, fmt.Errorf("quantized update encoding has %d bytes", len(buf))
+	}
+	q := &QuantizedUpdate{
+		Scale:     math.Float64frombits(binary.LittleEndian.Uint64(buf)),
+		ZeroPoint: int8(buf[8]),
+		Payload:   append([]byte(nil), buf[quantizedHeaderBytes:]...),
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func clampInt8(v float64) float64 {
+	return math.Max(math.MinInt8, math.Min(math.MaxInt8, v))
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+)
+
+// A regional summary carries a CertificateChain so the global tier need
+// not take the regional aggregator's word that its shard committed it: the
+// shard's voters sign a QuorumCertificate for the committed model, and the
+// certificate names the MembershipEpochRecord, committed and signed at the
+// global tier, that says who the voters are and under which fault model
+// they reach quorum.
+
+const (
+	membershipEpochDomain   = "sovereign-membership-epoch/v1"
+	quorumCertificateDomain = "sovereign-quorum-certificate/v1"
+)
+
+// ShardMember is a voter of a shard at one membership epoch.
+type ShardMember struct {
+	NodeID string `json:"node_id"`
+	// PublicKey is the member's PEM identity public key, P-256 or Ed25519.
+	PublicKey []byte `json:"public_key"`
+}
+
+// MembershipEpochRecord is a shard's membership at one epoch as the global
+// tier committed it.
+type MembershipEpochRecord struct {
+	ShardID string `json:"shard_id"`
+	Epoch   uint64 `json:"epoch"`
+	// Aggregator is the regional aggregator that submits the shard's
+	// summaries upstream.
+	Aggregator string `json:"aggregator"`
+	// FaultModel names the fault model the shard's quorum is counted
+	// under; empty is the classic one.
+	FaultModel string        `json:"fault_model,omitempty"`
+	Members    []ShardMember `json:"members"`
+	// Signature is the global tier's signature over SigningDigest, by the
+	// identity key SignerFingerprint names, in SignatureAlgorithm.
+	Signature          []byte      `json:"signature,omitempty"`
+	SignerFingerprint  string      `json:"signer_fingerprint,omitempty"`
+	SignatureAlgorithm AlgorithmID `json:"signature_algorithm,omitempty"`
+}
+
+// SigningDigest is the digest the global tier signs when it commits r. It
+// is the SHA-256 of
+//
+//	domain ‖ shardID ‖ epoch ‖ aggregator ‖ faultModel ‖ count ‖ members
+//
+// where epoch and count are big-endian uint64s, each member is its node ID
+// followed by the SHA-256 of its public key, members are in node ID order,
+// and each string is a big-endian uint32 length followed by its bytes.
+func (r *MembershipEpochRecord) SigningDigest() [32]byte {
+	members := append([]ShardMember(nil), r.Members...)
+	sort.Slice(members, func(i, j int) bool { return members[i].NodeID < members[j].NodeID })
+
+	buf := make([]byte, 0, 256)
+	appendString := func(s string) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(s))) // #nosec G115 -- record fields are far below 4 GiB
+		buf = append(buf, s...)
+	}
+	appendString(membershipEpochDomain)
+	appendString(r.ShardID)
+	buf = binary.BigEndian.AppendUint64(buf, r.Epoch)
+	appendString(r.Aggregator)
+	appendString(r.FaultModel)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(len(members)))
+	for _, member := range members {
+		appendString(member.NodeID)
+		keyDigest := sha256.Sum256(member.PublicKey)
+		buf = append(buf, keyDigest[:]...)
+	}
+	return sha256.Sum256(buf)
+}
+
+// Digest returns the hex SigningDigest of r, the form a QuorumCertificate
+// references it by.
+func (r *MembershipEpochRecord) Digest() string {
+	digest := r.SigningDigest()
+	return hex.EncodeToString(digest[:])
+}
+
+// Member returns the member with nodeID, if r has one.
+func (r *MembershipEpochRecord) Member(nodeID string) (ShardMember, bool) {
+	for _, member := range r.Members {
+		if member.NodeID == nodeID {
+			return member, true
+		}
+	}
+	return ShardMember{}, false
+}
+
+// VoterSignature is one shard member's signature over a
+// QuorumCertificate's SigningDigest.
+type VoterSignature struct {
+	NodeID    string      `json:"node_id"`
+	Signature []byte      `json:"signature"`
+	Algorithm AlgorithmID `json:"algorithm,omitempty"`
+}
+
+// QuorumCertificate is a shard's commit of a round's model, signed by the
+// members who approved it.
+type QuorumCertificate struct {
+	ShardID string `json:"shard_id"`
+	// Epoch and EpochDigest name the MembershipEpochRecord the voters
+	// belong to.
+	Epoch       uint64           `json:"epoch"`
+	EpochDigest string           `json:"epoch_digest"`
+	Round       int              `json:"round"`
+	ProposalID  string           `json:"proposal_id"`
+	ModelDigest string           `json:"model_digest"`
+	Signatures  []VoterSignature `json:"signatures"`
+}
+
+// SigningDigest is the digest every voter signs. It is the SHA-256 of
+//
+//	domain ‖ shardID ‖ epoch ‖ epochDigest ‖ round ‖ proposalID ‖ modelDigest
+//
+// with epoch and round as big-endian uint64s and each string a big-endian
+// uint32 length followed by its bytes.
+func (c *QuorumCertificate) SigningDigest() [32]byte {
+	buf := make([]byte, 0, 256)
+	appendString := func(s string) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(s))) // #nosec G115 -- certificate fields are far below 4 GiB
+		buf = append(buf, s...)
+	}
+	appendString(quorumCertificateDomain)
+	appendString(c.ShardID)
+	buf = binary.BigEndian.AppendUint64(buf, c.Epoch)
+	appendString(c.EpochDigest)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(c.Round)) // #nosec G115 -- rounds are positive
+	appendString(c.ProposalID)
+	appendString(c.ModelDigest)
+	return sha256.Sum256(buf)
+}
+
+// CertificateChain is the evidence a regional summary carries that its
+// shard committed it: the certificate and the epoch record it references.
+type CertificateChain struct {
+	Epoch       MembershipEpochRecord `json:"epoch"`
+	Certificate QuorumCertificate     `json:"certificate"`
+}
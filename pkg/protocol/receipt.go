@@ -0,0 +1,83 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// inclusionReceiptDomain separates inclusion receipt signatures from every
+// other digest an identity key signs.
+const inclusionReceiptDomain = "sovereign-mohawk/inclusion-receipt/v1"
+
+// InclusionReceipt is an aggregator's signed statement of how one
+// participant's update was treated in a round, taken from the round's
+// contribution manifest. ManifestDigest is the digest the round's commit
+// certificate binds, so the receipt proves, against the committed round,
+// that the update was included with AppliedWeight, or excluded for Reason.
+type InclusionReceipt struct {
+	Round          int          `json:"round"`
+	NodeID         string       `json:"node_id"`
+	UpdateDigest   string       `json:"update_digest"`
+	Included       bool         `json:"included"`
+	Reason         reasons.Code `json:"reason"`
+	AppliedWeight  float64      `json:"applied_weight"`
+	ManifestDigest string       `json:"manifest_digest"`
+	// Signature is the aggregator's signature over SigningDigest, by the
+	// identity key SignerFingerprint names, in SignatureAlgorithm.
+	Signature          []byte      `json:"signature,omitempty"`
+	SignerFingerprint  string      `json:"signer_fingerprint,omitempty"`
+	SignatureAlgorithm AlgorithmID `json:"signature_algorithm,omitempty"`
+}
+
+// NewInclusionReceipt returns the unsigned receipt for nodeID's entry in
+// manifest, or false if the manifest has none.
+func NewInclusionReceipt(manifest *ContributionManifest, nodeID string) (InclusionReceipt, bool) {
+	entry, ok := manifest.Entry(nodeID)
+	if !ok {
+		return InclusionReceipt{}, false
+	}
+	return InclusionReceipt{
+		Round:          manifest.Round,
+		NodeID:         entry.NodeID,
+		UpdateDigest:   entry.UpdateDigest,
+		Included:       entry.Included,
+		Reason:         entry.Reason,
+		AppliedWeight:  entry.AppliedWeight,
+		ManifestDigest: manifest.Digest(),
+	}, true
+}
+
+// SigningDigest is the digest an aggregator signs for the receipt. It is
+// the SHA-256 of
+//
+//	domain ‖ round ‖ nodeID ‖ updateDigest ‖ included ‖ reason ‖ appliedWeight ‖ manifestDigest
+//
+// where round is a big-endian uint64, included one byte, appliedWeight
+// the big-endian IEEE 754 bits, and each string a big-endian uint32 length
+// followed by its bytes.
+func (r *InclusionReceipt) SigningDigest() [32]byte {
+	buf := make([]byte, 0, 128+len(r.NodeID))
+	appendString := func(s string) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(s)))
+		buf = append(buf, s...)
+	}
+	appendString(inclusionReceiptDomain)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(r.Round))
+	appendString(r.NodeID)
+	appendString(r.UpdateDigest)
+	if r.Included {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	appendString(string(r.Reason))
+	buf = binary.BigEndian.AppendUint64(buf, math.Float64bits(r.AppliedWeight))
+	appendString(r.ManifestDigest)
+	return sha256.Sum256(buf)
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+const shardAssignmentDomain = "sovereign-shard-assignment/v1"
+
+// ShardAssignment places a node in a shard from an epoch on, as the
+// global tier's shard admission controller decided it. A node presents
+// its latest assignment to prove to verifiers which shard it belongs to.
+type ShardAssignment struct {
+	NodeID string `json:"node_id"`
+	// NodeFingerprint is the fingerprint of the node's identity key, the
+	// key its position on the shard ring is hashed from.
+	NodeFingerprint string `json:"node_fingerprint"`
+	ShardID         string `json:"shard_id"`
+	Epoch           uint64 `json:"epoch"`
+	// Signature is the global tier's signature over SigningDigest, by the
+	// identity key SignerFingerprint names, in SignatureAlgorithm.
+	Signature          []byte      `json:"signature,omitempty"`
+	SignerFingerprint  string      `json:"signer_fingerprint,omitempty"`
+	SignatureAlgorithm AlgorithmID `json:"signature_algorithm,omitempty"`
+}
+
+// SigningDigest is the digest the global tier signs when it issues a. It
+// is the SHA-256 of
+//
+//	domain ‖ nodeID ‖ nodeFingerprint ‖ shardID ‖ epoch
+//
+// where epoch is a big-endian uint64 and each string is a big-endian
+// uint32 length followed by its bytes.
+func (a *ShardAssignment) SigningDigest() [32]byte {
+	buf := make([]byte, 0, 160)
+	appendString := func(s string) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(s))) // #nosec G115 -- record fields are far below 4 GiB
+		buf = append(buf, s...)
+	}
+	appendString(shardAssignmentDomain)
+	appendString(a.NodeID)
+	appendString(a.NodeFingerprint)
+	appendString(a.ShardID)
+	buf = binary.BigEndian.AppendUint64(buf, a.Epoch)
+	return sha256.Sum256(buf)
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SparseUpdate is a top-k sparsified model update: only the coordinates in
+// Indices are sent, every other coordinate of the Dim-long update is zero.
+type SparseUpdate struct {
+	Dim     int       `json:"dim"`
+	Indices []uint32  `json:"indices"`
+	Values  []float64 `json:"values"`
+}
+
+// TopK keeps the k largest-magnitude coordinates of weights. Ties go to the
+// lower index so the selection is deterministic; indices are returned in
+// ascending order.
+func TopK(weights []float64, k int) (*SparseUpdate, error) {
+	if k < 0 {
+		return nil, fmt.Errorf("top-k needs a non-negative k, got %d", k)
+	}
+	if k > len(weights) {
+		k = len(weights)
+	}
+	order := make([]int, len(weights))
+	for i, w := range weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("cannot sparsify non-finite weight %v", w)
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return math.Abs(weights[order[a]]) > math.Abs(weights[order[b]])
+	})
+	kept := order[:k]
+	sort.Ints(kept)
+
+	s := &SparseUpdate{Dim: len(weights), Indices: make([]uint32, k), Values: make([]float64, k)}
+	for i, index := range kept {
+		s.Indices[i] = uint32(index) // #nosec G115 -- index < len(weights)
+		s.Values[i] = weights[index]
+	}
+	return s, nil
+}
+
+// Validate checks that every index is inside the model dimension, no index
+// repeats, and every value is finite.
+func (s *SparseUpdate) Validate() error {
+	if s.Dim < 0 {
+		return fmt.Errorf("invalid sparse dimension %d", s.Dim)
+	}
+	if len(s.Indices) != len(s.Values) {
+		return fmt.Errorf("sparse update has %d indices but %d values", len(s.Indices), len(s.Values))
+	}
+	seen := make(map[uint32]bool, len(s.Indices))
+	for i, index := range s.Indices {
+		if int64(index) >= int64(s.Dim) {
+			return fmt.Errorf("sparse index %d outside dimension %d", index, s.Dim)
+		}
+		if seen[index] {
+			return fmt.Errorf("duplicate sparse index %d", index)
+		}
+		seen[index] = true
+		if v := s.Values[i]; math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("non-finite sparse value %v at index %d", v, index)
+		}
+	}
+	return nil
+}
+
+// Len returns the dense dimension.
+func (s *SparseUpdate) Len() int {
+	return s.Dim
+}
+
+// Densify expands the update to Dim float64 weights. Call Validate first.
+func (s *SparseUpdate) Densify() []float64 {
+	weights := make([]float64, s.Dim)
+	for i, index := range s.Indices {
+		weights[index] = s.Values[i]
+	}
+	return weights
+}
+
+// MaxMagnitude is the largest absolute value carried.
+func (s *SparseUpdate) MaxMagnitude() float64 {
+	largest := 0.0
+	for _, v := range s.Values {
+		largest = math.Max(largest, math.Abs(v))
+	}
+	return largest
+}
+
+// Bytes is the canonical wire encoding digested into contribution
+// manifests: little-endian dimension, then (index, value) pairs.
+func (s *SparseUpdate) Bytes() []byte {
+	buf := make([]byte, SparseUpdateBytes(len(s.Indices)))
+	binary.LittleEndian.PutUint32(buf, uint32(s.Dim)) // #nosec G115 -- dimensions fit in 32 bits
+	for i, index := range s.Indices {
+		offset := 4 + 12*i
+		binary.LittleEndian.PutUint32(buf[offset:], index)
+		binary.LittleEndian.PutUint64(buf[offset+4:], math.Float64bits(s.Values[i]))
+	}
+	return buf
+}
+
+// DecodeSparseUpdate parses the Bytes encoding. The declared dimension is
+// checked against maxDim before anything is allocated, so a forged header
+// cannot make the receiver reserve memory for a model it does not run.
+func DecodeSparseUpdate(buf []byte, maxDim int) (*SparseUpdate, error) {
+	if len(buf) < 4 || (len(buf)-4)%12 != 0 {
+		return nil, fmt.Errorf("sparse update encoding has %d bytes", len(buf))
+	}
+	dim := int(binary.LittleEndian.Uint32(buf))
+	if dim > maxDim {
+		return nil, fmt.Errorf("sparse update declares dimension %d, model has %d", dim, maxDim)
+	}
+	k := (len(buf) - 4) / 12
+	if k > dim {
+		return nil, fmt.Errorf("sparse update carries %d entries for dimension %d", k, dim)
+	}
+	s := &SparseUpdate{Dim: dim, Indices: make([]uint32, k), Values: make([]float64, k)}
+	for i := range s.Indices {
+		offset := 4 + 12*i
+		s.Indices[i] = binary.LittleEndian.Uint32(buf[offset:])
+		s.Values[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[offset+4:]))
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
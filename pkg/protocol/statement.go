@@ -0,0 +1,83 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// Training statement rejection reasons recorded in contribution manifests.
+const (
+	// ReasonStaleBaseModel means the update was trained from a model other
+	// than the one committed for its round.
+	ReasonStaleBaseModel = reasons.StaleBaseModel
+	// ReasonInvalidStatement means the update's training statement is
+	// missing or does not describe the update it came with.
+	ReasonInvalidStatement = reasons.InvalidTrainingStatement
+)
+
+// TrainingStatement is a proof-of-training statement binding an update to
+// the TrainingTask it answers: the round and the digest of the GlobalWeights
+// the node started from. Until a zk circuit proves the statement, it is a
+// hash commitment the aggregator recomputes.
+type TrainingStatement struct {
+	NodeID       string `json:"node_id"`
+	Round        int    `json:"round"`
+	BaseDigest   string `json:"base_digest"`
+	UpdateDigest string `json:"update_digest"`
+	Commitment   string `json:"commitment"`
+}
+
+// NewTrainingStatement commits to an update trained from task. update is
+// the update's canonical encoding, as digested into contribution manifests.
+func NewTrainingStatement(nodeID string, task TrainingTask, update []byte) *TrainingStatement {
+	statement := &TrainingStatement{
+		NodeID:       nodeID,
+		Round:        task.Round,
+		BaseDigest:   WeightsDigest(task.GlobalWeights),
+		UpdateDigest: UpdateDigest(update),
+	}
+	statement.Commitment = statement.commit()
+	return statement
+}
+
+func (s *TrainingStatement) commit() string {
+	unsigned := *s
+	unsigned.Commitment = ""
+	// Marshaling a struct of strings and ints cannot fail.
+	payload, _ := json.Marshal(unsigned)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Check verifies that the statement was made by nodeID for round about the
+// update whose manifest digest is updateDigest, and that its commitment
+// matches. It does not check the base digest; see CheckBase.
+func (s *TrainingStatement) Check(nodeID string, round int, updateDigest string) error {
+	switch {
+	case s.NodeID != nodeID:
+		return fmt.Errorf("training statement is for node %s, not %s", s.NodeID, nodeID)
+	case s.Round != round:
+		return fmt.Errorf("training statement is for round %d, not %d", s.Round, round)
+	case s.UpdateDigest != updateDigest:
+		return fmt.Errorf("training statement does not describe the submitted update")
+	case s.Commitment != s.commit():
+		return fmt.Errorf("training statement commitment does not match its contents")
+	}
+	return nil
+}
+
+// CheckBase verifies that the statement's base digest is committedDigest,
+// the digest of the model actually distributed for the statement's round.
+func (s *TrainingStatement) CheckBase(committedDigest string) error {
+	if s.BaseDigest != committedDigest {
+		return fmt.Errorf("%s: trained from %s, round %d distributed %s", ReasonStaleBaseModel, s.BaseDigest, s.Round, committedDigest)
+	}
+	return nil
+}
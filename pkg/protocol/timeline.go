@@ -0,0 +1,43 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import "time"
+
+// VoteTimeline is how the votes on one proposal arrived, each stage
+// measured from the proposal. A stage the proposal never reached is zero.
+type VoteTimeline struct {
+	Round      int    `json:"round"`
+	ProposalID string `json:"proposal_id"`
+	QuorumSize int    `json:"quorum_size"`
+	// Votes counts the distinct voters.
+	Votes int `json:"votes"`
+	// FirstVote, HalfQuorum, and Quorum are the time from the proposal to
+	// its first vote, to its approvals reaching half its quorum, and to
+	// quorum.
+	FirstVote     time.Duration `json:"first_vote_ns"`
+	HalfQuorum    time.Duration `json:"half_quorum_ns"`
+	Quorum        time.Duration `json:"quorum_ns"`
+	QuorumReached bool          `json:"quorum_reached"`
+	// InterArrival is the distribution of gaps between consecutive votes.
+	InterArrival VoteInterArrival `json:"inter_arrival"`
+	// LateVotes counts votes that arrived after quorum, work the round did
+	// not need.
+	LateVotes int `json:"late_votes"`
+	// Budget is the round's time budget. AtRisk is set once quorum has
+	// taken too large a share of it for too many consecutive rounds.
+	Budget time.Duration `json:"budget_ns,omitempty"`
+	AtRisk bool          `json:"at_risk,omitempty"`
+}
+
+// VoteInterArrival summarizes the gaps between consecutive votes.
+// Percentiles use the nearest-rank method; all are zero with fewer than
+// two votes.
+type VoteInterArrival struct {
+	Count int           `json:"count"`
+	Mean  time.Duration `json:"mean_ns"`
+	P50   time.Duration `json:"p50_ns"`
+	P90   time.Duration `json:"p90_ns"`
+	Max   time.Duration `json:"max_ns"`
+}
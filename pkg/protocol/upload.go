@@ -0,0 +1,36 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+// UploadTier is the precision a node chose to upload at for a round.
+type UploadTier string
+
+const (
+	// UploadTierFull is a float64 update.
+	UploadTierFull UploadTier = "full"
+	// UploadTierQuantized is an int8 QuantizedUpdate.
+	UploadTierQuantized UploadTier = "quantized"
+	// UploadTierSparse is a top-k SparseUpdate.
+	UploadTierSparse UploadTier = "sparse"
+	// UploadTierSkipped declares that the node is sitting the round out.
+	// A declared skip is not a fault.
+	UploadTierSkipped UploadTier = "skipped"
+)
+
+// FullUpdateBytes is the encoded size of a float64 update with dims weights.
+func FullUpdateBytes(dims int) int {
+	return 8 * dims
+}
+
+// QuantizedUpdateBytes is the encoded size of an int8 update with dims
+// weights: the payload plus its scale and zero point.
+func QuantizedUpdateBytes(dims int) int {
+	return 9 + dims
+}
+
+// SparseUpdateBytes is the encoded size of a sparse update carrying k
+// coordinates: the dimension plus an index and value per coordinate.
+func SparseUpdateBytes(k int) int {
+	return 4 + 12*k
+}
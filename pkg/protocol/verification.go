@@ -0,0 +1,38 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// VerificationResponseDigest is the digest a verifier signs over its
+// verdict on a verification request. It is the SHA-256 of
+//
+//	requestID ‖ verifierID ‖ valid ‖ reason ‖ timestamp
+//
+// where each string is a big-endian uint32 length followed by its bytes,
+// valid is one byte (1 or 0), and timestamp is the response time truncated
+// to Unix seconds as a big-endian int64. The length prefixes keep distinct
+// responses from sharing an encoding; truncating the timestamp keeps the
+// digest stable across encodings that drop sub-second precision.
+func VerificationResponseDigest(requestID, verifierID string, valid bool, reason string, timestamp time.Time) [32]byte {
+	buf := make([]byte, 0, 4*3+len(requestID)+len(verifierID)+len(reason)+1+8)
+	appendString := func(s string) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(s)))
+		buf = append(buf, s...)
+	}
+	appendString(requestID)
+	appendString(verifierID)
+	if valid {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	appendString(reason)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(timestamp.Unix()))
+	return sha256.Sum256(buf)
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package protocol
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+)
+
+// canonicalWeightsMagic opens every canonical weights encoding.
+const canonicalWeightsMagic = "SMW1"
+
+// canonicalWeightsHeader is the length of the canonical header: the magic,
+// a uint32 spec version, and a uint64 dimension.
+const canonicalWeightsHeader = len(canonicalWeightsMagic) + 4 + 8
+
+// canonicalNaN is the one NaN bit pattern the canonical encoding writes.
+const canonicalNaN = 0x7ff8000000000001
+
+// ModelWeights is a model's flattened parameters with the version of the
+// model spec they follow. Two nodes holding the same model agree on its
+// Digest however each of them serialized or received the weights.
+type ModelWeights struct {
+	SpecVersion int       `json:"spec_version"`
+	Weights     []float64 `json:"weights"`
+}
+
+// Canonical returns the canonical encoding: the magic "SMW1", the spec
+// version as a little-endian uint32 and the dimension as a little-endian
+// uint64, then each weight as a little-endian IEEE 754 float64. Negative
+// zero is written as zero and every NaN as one quiet NaN, so weights that
+// compare equal encode identically.
+func (m ModelWeights) Canonical() []byte {
+	buf := make([]byte, 0, canonicalWeightsHeader+8*len(m.Weights))
+	buf = append(buf, canonicalWeightsMagic...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(m.SpecVersion))
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(m.Weights)))
+	for _, w := range m.Weights {
+		bits := math.Float64bits(w)
+		switch {
+		case w == 0:
+			bits = 0
+		case math.IsNaN(w):
+			bits = canonicalNaN
+		}
+		buf = binary.LittleEndian.AppendUint64(buf, bits)
+	}
+	return buf
+}
+
+// Digest returns the hex SHA-256 of the canonical encoding. It identifies
+// the model in proposals, commit certificates, signed aggregates, and the
+// model store.
+func (m ModelWeights) Digest() string {
+	sum := sha256.Sum256(m.Canonical())
+	return hex.EncodeToString(sum[:])
+}
+
+// DecodeModelWeights decodes a canonical encoding.
+func DecodeModelWeights(buf []byte) (ModelWeights, error) {
+	if len(buf) < canonicalWeightsHeader || string(buf[:len(canonicalWeightsMagic)]) != canonicalWeightsMagic {
+		return ModelWeights{}, fmt.Errorf("%w: no canonical header", ErrInvalidWeights)
+	}
+	version := binary.LittleEndian.Uint32(buf[len(canonicalWeightsMagic):])
+	dimension := binary.LittleEndian.Uint64(buf[len(canonicalWeightsMagic)+4:])
+	body := buf[canonicalWeightsHeader:]
+	if uint64(len(body)) != 8*dimension || dimension > math.MaxInt32 {
+		return ModelWeights{}, fmt.Errorf("%w: header declares %d weights, body holds %d bytes", ErrInvalidWeights, dimension, len(body))
+	}
+	m := ModelWeights{SpecVersion: int(version), Weights: make([]float64, dimension)}
+	for i := range m.Weights {
+		m.Weights[i] = math.Float64frombits(binary.LittleEndian.Uint64(body[i*8:]))
+	}
+	return m, nil
+}
+
+// ParseModelWeights reads weights as they travel between nodes: either a
+// canonical encoding, or a bare little-endian float64 array as aggregates
+// are exchanged. A bare array carries no spec version and reads as
+// version 0.
+func ParseModelWeights(buf []byte) (ModelWeights, error) {
+	if m, err := DecodeModelWeights(buf); err == nil {
+		return m, nil
+	}
+	if len(buf)%8 != 0 {
+		return ModelWeights{}, fmt.Errorf("%w: %d bytes is not a whole number of float64 weights", ErrInvalidWeights, len(buf))
+	}
+	m := ModelWeights{Weights: make([]float64, len(buf)/8)}
+	for i := range m.Weights {
+		m.Weights[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[i*8:]))
+	}
+	return m, nil
+}
+
+// WeightsDigest returns the Digest of the model buf encodes, as
+// ParseModelWeights reads it. A payload that is not model weights at all
+// is hashed as-is, so it still has a stable identity; its length keeps it
+// from colliding with any canonical encoding.
+func WeightsDigest(buf []byte) string {
+	m, err := ParseModelWeights(buf)
+	if err != nil {
+		sum := sha256.Sum256(buf)
+		return hex.EncodeToString(sum[:])
+	}
+	return m.Digest()
+}
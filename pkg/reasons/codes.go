@@ -0,0 +1,184 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package reasons
+
+import "net/http"
+
+// Built-in codes. IDs are grouped by category: 1xx verification, 2xx
+// aggregation, 3xx consensus, 4xx membership, 9xx API requests. Codes and
+// IDs are never reused; retire a code by leaving it registered and
+// unemitted.
+const (
+	// ProofInvalid: a verifier found an update's proof invalid.
+	ProofInvalid Code = "proof_invalid"
+	// StaleBaseModel: the update was trained from a model other than the
+	// one committed for its round.
+	StaleBaseModel Code = "stale_base_model"
+	// InvalidTrainingStatement: the update's training statement is missing
+	// or does not describe the update it came with.
+	InvalidTrainingStatement Code = "invalid_training_statement"
+	// InvalidSignature: a vote or response carries a signature that does
+	// not verify.
+	InvalidSignature Code = "invalid_signature"
+	// InvalidMAC: a vote carries a MAC that does not verify.
+	InvalidMAC Code = "invalid_mac"
+
+	// Included: the update was aggregated. It is not a rejection, but
+	// manifests record it in the same field.
+	Included Code = "included"
+	// NormOutlier: the outlier filter excluded the update.
+	NormOutlier Code = "norm_outlier"
+	// NoSamples: the update claims no training samples.
+	NoSamples Code = "no_samples"
+	// StaleUpdate: the update arrived for a round already aggregated.
+	StaleUpdate Code = "stale_update"
+	// KrumRejected: Krum did not select the update.
+	KrumRejected Code = "krum_rejected"
+	// ByzantineDetected: Byzantine detection flagged the update.
+	ByzantineDetected Code = "byzantine_detected"
+	// Superseded: another copy of the node's round update, synced from its
+	// island cache or submitted live, took the copy's place.
+	Superseded Code = "superseded"
+
+	// QuorumNotReached: the proposal closed without enough approvals.
+	QuorumNotReached Code = "quorum_not_reached"
+	// InvalidSignatures: approvals reached quorum, but too few carried a
+	// valid signature.
+	InvalidSignatures Code = "invalid_signatures"
+	// Reset: the coordinator was reset while the proposal was open.
+	Reset Code = "reset"
+	// NotVoting: the vote arrived while the coordinator was not voting.
+	NotVoting Code = "not_voting"
+	// UnknownProposal: the vote names a proposal the coordinator does not
+	// know.
+	UnknownProposal Code = "unknown_proposal"
+	// DuplicateVote: the node already voted on the proposal.
+	DuplicateVote Code = "duplicate_vote"
+	// BelowReputationFloor: the voter's reputation is below the floor.
+	BelowReputationFloor Code = "below_reputation_floor"
+	// RateLimited: the voter exceeded its vote rate.
+	RateLimited Code = "rate_limited"
+	// NotRevealed: a commit-reveal vote was not revealed.
+	NotRevealed Code = "not_revealed"
+	// RevealMismatch: a revealed vote does not match its commitment.
+	RevealMismatch Code = "reveal_mismatch"
+	// Equivocated: the voter signed conflicting votes, and is blacklisted.
+	Equivocated Code = "equivocated"
+	// SealedEpoch: the message arrived late, for a proposal of an epoch
+	// the coordinator has since sealed.
+	SealedEpoch Code = "sealed_epoch"
+	// DuplicateDelivery: the same signed vote was already delivered, and
+	// the copy was dropped before verification.
+	DuplicateDelivery Code = "duplicate_delivery"
+	// FutureVote: the vote is stamped further ahead of the coordinator's
+	// clock than the allowed skew.
+	FutureVote Code = "future_vote"
+
+	// NotRoundMember: the node is outside the round's membership.
+	NotRoundMember Code = "not_round_member"
+
+	// Unavailable: the service cannot take the request now.
+	Unavailable Code = "unavailable"
+	// Timeout: the request timed out.
+	Timeout Code = "timeout"
+	// NotFound: the request names something that does not exist.
+	NotFound Code = "not_found"
+	// Conflict: the request conflicts with the current state.
+	Conflict Code = "conflict"
+	// Forbidden: the caller may not make the request.
+	Forbidden Code = "forbidden"
+	// InvalidRequest: the request is malformed.
+	InvalidRequest Code = "invalid_request"
+	// TooLarge: the request exceeds a size limit.
+	TooLarge Code = "too_large"
+	// Unprocessable: the request is well formed but violates a rule.
+	Unprocessable Code = "unprocessable"
+	// NotImplemented: the server lacks the component the request needs.
+	NotImplemented Code = "not_implemented"
+	// Internal: the server failed.
+	Internal Code = "internal"
+)
+
+var builtin = []Info{
+	{Code: ProofInvalid, ID: 100, Severity: SeverityWarning, Category: CategoryVerification,
+		Message: "The update's proof did not verify."},
+	{Code: StaleBaseModel, ID: 101, Severity: SeverityWarning, Category: CategoryVerification,
+		Status: http.StatusConflict, Message: "The update was trained from a stale base model."},
+	{Code: InvalidTrainingStatement, ID: 102, Severity: SeverityError, Category: CategoryVerification,
+		Status: http.StatusBadRequest, Message: "The update's training statement is missing or does not match it."},
+	{Code: InvalidSignature, ID: 103, Severity: SeverityError, Category: CategoryVerification,
+		Status: http.StatusForbidden, Message: "The signature does not verify."},
+	{Code: InvalidMAC, ID: 104, Severity: SeverityError, Category: CategoryVerification,
+		Status: http.StatusForbidden, Message: "The message authentication code does not verify."},
+	{Code: Included, ID: 200, Severity: SeverityInfo, Category: CategoryAggregation,
+		Status: http.StatusOK, Message: "The update was included."},
+	{Code: NormOutlier, ID: 201, Severity: SeverityWarning, Category: CategoryAggregation,
+		Message: "The update's norm is an outlier."},
+	{Code: NoSamples, ID: 202, Severity: SeverityWarning, Category: CategoryAggregation,
+		Status: http.StatusBadRequest, Message: "The update claims no training samples."},
+	{Code: StaleUpdate, ID: 203, Severity: SeverityWarning, Category: CategoryAggregation,
+		Status: http.StatusConflict, Message: "The update is for a past round."},
+	{Code: KrumRejected, ID: 204, Severity: SeverityWarning, Category: CategoryAggregation,
+		Message: "Krum did not select the update."},
+	{Code: ByzantineDetected, ID: 205, Severity: SeverityError, Category: CategoryAggregation,
+		Message: "Byzantine detection flagged the update."},
+	{Code: Superseded, ID: 206, Severity: SeverityInfo, Category: CategoryAggregation,
+		Status: http.StatusOK, Message: "Another copy of the node's update for the round took its place."},
+	{Code: QuorumNotReached, ID: 300, Severity: SeverityWarning, Category: CategoryConsensus,
+		Status: http.StatusServiceUnavailable, Message: "The proposal did not reach quorum."},
+	{Code: InvalidSignatures, ID: 301, Severity: SeverityError, Category: CategoryConsensus,
+		Status: http.StatusForbidden, Message: "Too few approvals carry a valid signature."},
+	{Code: Reset, ID: 302, Severity: SeverityInfo, Category: CategoryConsensus,
+		Status: http.StatusConflict, Message: "The round was reset."},
+	{Code: NotVoting, ID: 303, Severity: SeverityInfo, Category: CategoryConsensus,
+		Status: http.StatusConflict, Message: "No vote is open."},
+	{Code: UnknownProposal, ID: 304, Severity: SeverityWarning, Category: CategoryConsensus,
+		Status: http.StatusNotFound, Message: "The proposal is unknown."},
+	{Code: DuplicateVote, ID: 305, Severity: SeverityInfo, Category: CategoryConsensus,
+		Status: http.StatusConflict, Message: "The node already voted."},
+	{Code: BelowReputationFloor, ID: 306, Severity: SeverityWarning, Category: CategoryConsensus,
+		Status: http.StatusForbidden, Message: "The voter's reputation is below the floor."},
+	{Code: RateLimited, ID: 307, Severity: SeverityWarning, Category: CategoryConsensus,
+		Status: http.StatusTooManyRequests, Message: "Too many votes."},
+	{Code: NotRevealed, ID: 308, Severity: SeverityWarning, Category: CategoryConsensus,
+		Status: http.StatusConflict, Message: "The vote must be committed and revealed."},
+	{Code: RevealMismatch, ID: 309, Severity: SeverityError, Category: CategoryConsensus,
+		Status: http.StatusForbidden, Message: "The revealed vote does not match its commitment."},
+	{Code: Equivocated, ID: 310, Severity: SeverityCritical, Category: CategoryConsensus,
+		Status: http.StatusForbidden, Message: "The voter signed conflicting votes."},
+	{Code: SealedEpoch, ID: 311, Severity: SeverityInfo, Category: CategoryConsensus,
+		Status: http.StatusConflict, Message: "The proposal's epoch is sealed."},
+	{Code: DuplicateDelivery, ID: 312, Severity: SeverityInfo, Category: CategoryConsensus,
+		Status: http.StatusConflict, Message: "The vote was already delivered."},
+	{Code: FutureVote, ID: 313, Severity: SeverityWarning, Category: CategoryConsensus,
+		Status: http.StatusBadRequest, Message: "The vote is stamped in the future."},
+	{Code: NotRoundMember, ID: 400, Severity: SeverityWarning, Category: CategoryMembership,
+		Status: http.StatusForbidden, Message: "The node is not a member of the round."},
+	{Code: Unavailable, ID: 900, Severity: SeverityWarning, Category: CategoryRequest,
+		Status: http.StatusServiceUnavailable, Message: "The service is temporarily unavailable."},
+	{Code: Timeout, ID: 901, Severity: SeverityWarning, Category: CategoryRequest,
+		Status: http.StatusGatewayTimeout, Message: "The request timed out."},
+	{Code: NotFound, ID: 902, Severity: SeverityWarning, Category: CategoryRequest,
+		Status: http.StatusNotFound, Message: "Not found."},
+	{Code: Conflict, ID: 903, Severity: SeverityWarning, Category: CategoryRequest,
+		Status: http.StatusConflict, Message: "The request conflicts with the current state."},
+	{Code: Forbidden, ID: 904, Severity: SeverityWarning, Category: CategoryRequest,
+		Status: http.StatusForbidden, Message: "Forbidden."},
+	{Code: InvalidRequest, ID: 905, Severity: SeverityWarning, Category: CategoryRequest,
+		Status: http.StatusBadRequest, Message: "The request is invalid."},
+	{Code: TooLarge, ID: 906, Severity: SeverityWarning, Category: CategoryRequest,
+		Status: http.StatusRequestEntityTooLarge, Message: "The request is too large."},
+	{Code: Unprocessable, ID: 907, Severity: SeverityWarning, Category: CategoryRequest,
+		Message: "The request cannot be processed."},
+	{Code: NotImplemented, ID: 908, Severity: SeverityWarning, Category: CategoryRequest,
+		Status: http.StatusNotImplemented, Message: "Not configured on this server."},
+	{Code: Internal, ID: 909, Severity: SeverityError, Category: CategoryRequest,
+		Status: http.StatusInternalServerError, Message: "Internal error."},
+}
+
+func init() {
+	for _, info := range builtin {
+		MustRegister(info)
+	}
+}
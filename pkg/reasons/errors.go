@@ -0,0 +1,22 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+package reasons
+
+import "errors"
+
+// Sentinel errors returned (wrapped) by the reason registry. Match them with
+// errors.Is; never compare error strings.
+var (
+	// ErrUnknownCode means a decoded or looked-up reason code was never
+	// registered. Not retryable; the sender runs a newer or foreign
+	// version and must register the code first.
+	ErrUnknownCode = errors.New("unknown reason code")
+	// ErrCodeCollision means a registration reuses a code or numeric ID
+	// already registered. Not retryable; pick an unused code and ID.
+	ErrCodeCollision = errors.New("reason code collision")
+	// ErrInvalidCode means a registration is malformed: an empty or
+	// non-snake-case code, a zero ID, or an unknown severity. Not
+	// retryable.
+	ErrInvalidCode = errors.New("invalid reason code")
+)
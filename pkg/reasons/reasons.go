@@ -0,0 +1,254 @@
+// Copyright 2026 Sovereign-Mohawk Core Team
+// Licensed under the Apache License, Version 2.0
+
+// Package reasons is the single vocabulary for why something was rejected,
+// excluded, or aborted: verifier responses, contribution manifests,
+// consensus vote rejections and aborts, blacklistings, provenance records,
+// and API error bodies all carry a Code from here. Every code is
+// registered with a numeric ID, a severity, a category, an HTTP status,
+// and an English message that catalogs can translate.
+package reasons
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Code is a stable snake_case reason identifier. It is what goes on the
+// wire and into signed digests; decoding refuses unregistered codes. The
+// empty Code means no reason.
+type Code string
+
+// Severity ranks how much a reason matters to an operator.
+type Severity int
+
+// Severities, least severe first.
+const (
+	// SeverityInfo is a normal outcome, such as an update's inclusion.
+	SeverityInfo Severity = iota + 1
+	// SeverityWarning is a rejection honest nodes can cause, such as a
+	// stale update or a late vote.
+	SeverityWarning
+	// SeverityError is a rejection an honest, up-to-date node should never
+	// cause, such as a bad signature.
+	SeverityError
+	// SeverityCritical is proven misbehaviour, such as equivocation.
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// MarshalText encodes the severity by name.
+func (s Severity) MarshalText() ([]byte, error) {
+	if _, ok := severityNames[s]; !ok {
+		return nil, fmt.Errorf("%w: severity %d", ErrInvalidCode, int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name.
+func (s *Severity) UnmarshalText(text []byte) error {
+	for severity, name := range severityNames {
+		if name == string(text) {
+			*s = severity
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: severity %q", ErrInvalidCode, text)
+}
+
+// Category groups codes by the component that emits them.
+type Category string
+
+// Categories.
+const (
+	CategoryVerification Category = "verification"
+	CategoryAggregation  Category = "aggregation"
+	CategoryConsensus    Category = "consensus"
+	CategoryMembership   Category = "membership"
+	CategoryRequest      Category = "request"
+)
+
+// Info describes a registered code.
+type Info struct {
+	Code     Code     `json:"code"`
+	ID       uint16   `json:"id"`
+	Severity Severity `json:"severity"`
+	Category Category `json:"category"`
+	// Status is the HTTP status of an API error carrying the code.
+	Status int `json:"status"`
+	// Message is the English message, used when a catalog lacks the code.
+	Message string `json:"message"`
+}
+
+var (
+	mu     sync.RWMutex
+	byCode = make(map[Code]Info)
+	byID   = make(map[uint16]Code)
+)
+
+// Register adds info to the registry. It fails with ErrCodeCollision if the
+// code or ID is taken, and with ErrInvalidCode if info is malformed. A
+// zero Status defaults to 422 Unprocessable Entity.
+func Register(info Info) error {
+	if !wellFormed(info.Code) || info.ID == 0 {
+		return fmt.Errorf("%w: code %q, id %d", ErrInvalidCode, info.Code, info.ID)
+	}
+	if _, ok := severityNames[info.Severity]; !ok {
+		return fmt.Errorf("%w: code %s has severity %d", ErrInvalidCode, info.Code, int(info.Severity))
+	}
+	if info.Status == 0 {
+		info.Status = http.StatusUnprocessableEntity
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, taken := byCode[info.Code]; taken {
+		return fmt.Errorf("%w: code %s is registered", ErrCodeCollision, info.Code)
+	}
+	if holder, taken := byID[info.ID]; taken {
+		return fmt.Errorf("%w: id %d is registered to %s", ErrCodeCollision, info.ID, holder)
+	}
+	byCode[info.Code] = info
+	byID[info.ID] = info.Code
+	return nil
+}
+
+// MustRegister is Register for package initialization; it panics on error.
+func MustRegister(info Info) Code {
+	if err := Register(info); err != nil {
+		panic(err)
+	}
+	return info.Code
+}
+
+// Lookup returns the registration of code.
+func Lookup(code Code) (Info, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	info, ok := byCode[code]
+	return info, ok
+}
+
+// ByID returns the registration with numeric id.
+func ByID(id uint16) (Info, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	code, ok := byID[id]
+	if !ok {
+		return Info{}, false
+	}
+	return byCode[code], true
+}
+
+// All returns every registration, by ID.
+func All() []Info {
+	mu.RLock()
+	defer mu.RUnlock()
+	infos := make([]Info, 0, len(byCode))
+	for _, info := range byCode {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// Registered reports whether c is registered.
+func (c Code) Registered() bool {
+	_, ok := Lookup(c)
+	return ok
+}
+
+// Validate returns nil for the empty code and registered codes, and an
+// error wrapping ErrUnknownCode otherwise.
+func (c Code) Validate() error {
+	if c == "" || c.Registered() {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownCode, string(c))
+}
+
+// MarshalText encodes c, refusing unregistered codes.
+func (c Code) MarshalText() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(c), nil
+}
+
+// UnmarshalText decodes a code, refusing unregistered codes.
+func (c *Code) UnmarshalText(text []byte) error {
+	code := Code(text)
+	if err := code.Validate(); err != nil {
+		return err
+	}
+	*c = code
+	return nil
+}
+
+// HTTPStatus is the HTTP status of an API error carrying code: 200 for the
+// empty code and 500 for unregistered codes.
+func HTTPStatus(code Code) int {
+	if code == "" {
+		return http.StatusOK
+	}
+	if info, ok := Lookup(code); ok {
+		return info.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// MetricLabel is code as a metric label value. Unregistered codes share
+// the label "unknown", so a peer sending arbitrary codes cannot grow a
+// metric's cardinality.
+func MetricLabel(code Code) string {
+	switch {
+	case code == "":
+		return "none"
+	case code.Registered():
+		return string(code)
+	default:
+		return "unknown"
+	}
+}
+
+// Catalog holds translated messages by code.
+type Catalog map[Code]string
+
+// Message is code's message from catalog, falling back to the registered
+// English message, then to the code itself.
+func (c Catalog) Message(code Code) string {
+	if message, ok := c[code]; ok {
+		return message
+	}
+	if info, ok := Lookup(code); ok {
+		return info.Message
+	}
+	return string(code)
+}
+
+// wellFormed reports whether code is non-empty snake_case.
+func wellFormed(code Code) bool {
+	if code == "" || code[0] == '_' || code[len(code)-1] == '_' {
+		return false
+	}
+	for _, r := range code {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package reasons
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRegisterRefusesCollisionsAndMalformedCodes(t *testing.T) {
+	custom := Info{Code: "custom_test_reason", ID: 60001, Severity: SeverityWarning, Category: CategoryRequest}
+	if err := Register(custom); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if info, ok := Lookup(custom.Code); !ok || info.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("lookup = %+v, %v; want the default status", info, ok)
+	}
+	if info, ok := ByID(custom.ID); !ok || info.Code != custom.Code {
+		t.Fatalf("by id = %+v, %v", info, ok)
+	}
+
+	for name, info := range map[string]Info{
+		"same code":   {Code: custom.Code, ID: 60002, Severity: SeverityInfo},
+		"same id":     {Code: "other_test_reason", ID: custom.ID, Severity: SeverityInfo},
+		"built-in id": {Code: "other_test_reason", ID: 100, Severity: SeverityInfo},
+		"built-in":    {Code: NormOutlier, ID: 60003, Severity: SeverityInfo},
+	} {
+		if err := Register(info); !errors.Is(err, ErrCodeCollision) {
+			t.Fatalf("%s: %v, want ErrCodeCollision", name, err)
+		}
+	}
+	for name, info := range map[string]Info{
+		"empty code":   {ID: 60004, Severity: SeverityInfo},
+		"prose code":   {Code: "excluded: norm outlier", ID: 60005, Severity: SeverityInfo},
+		"zero id":      {Code: "zero_id_reason", Severity: SeverityInfo},
+		"bad severity": {Code: "bad_severity_reason", ID: 60006},
+	} {
+		if err := Register(info); !errors.Is(err, ErrInvalidCode) {
+			t.Fatalf("%s: %v, want ErrInvalidCode", name, err)
+		}
+	}
+}
+
+func TestCodeDecodingAndMappings(t *testing.T) {
+	var decoded struct {
+		Reason Code `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(`{"reason":"stale_update"}`), &decoded); err != nil || decoded.Reason != StaleUpdate {
+		t.Fatalf("decode registered: %q, %v", decoded.Reason, err)
+	}
+	if err := json.Unmarshal([]byte(`{"reason":""}`), &decoded); err != nil || decoded.Reason != "" {
+		t.Fatalf("decode empty: %q, %v", decoded.Reason, err)
+	}
+	if err := json.Unmarshal([]byte(`{"reason":"made_up"}`), &decoded); !errors.Is(err, ErrUnknownCode) {
+		t.Fatalf("decode unknown: %v, want ErrUnknownCode", err)
+	}
+	if _, err := json.Marshal(struct{ Reason Code }{"made_up"}); !errors.Is(err, ErrUnknownCode) {
+		t.Fatalf("encode unknown: %v, want ErrUnknownCode", err)
+	}
+
+	if got := HTTPStatus(RateLimited); got != http.StatusTooManyRequests {
+		t.Fatalf("status of rate_limited = %d", got)
+	}
+	if got := HTTPStatus("made_up"); got != http.StatusInternalServerError {
+		t.Fatalf("status of unknown code = %d", got)
+	}
+	for code, want := range map[Code]string{Equivocated: "equivocated", "": "none", "made_up": "unknown"} {
+		if got := MetricLabel(code); got != want {
+			t.Fatalf("label of %q = %q, want %q", code, got, want)
+		}
+	}
+
+	catalog := Catalog{NormOutlier: "La norme de la mise à jour est aberrante."}
+	if got := catalog.Message(NormOutlier); got != catalog[NormOutlier] {
+		t.Fatalf("translated message = %q", got)
+	}
+	if info, _ := Lookup(NoSamples); catalog.Message(NoSamples) != info.Message {
+		t.Fatalf("fallback message = %q", catalog.Message(NoSamples))
+	}
+
+	var severity Severity
+	if err := json.Unmarshal([]byte(`"critical"`), &severity); err != nil || severity != SeverityCritical {
+		t.Fatalf("severity = %v, %v", severity, err)
+	}
+}
+
+func TestBuiltinCodesAreConsistent(t *testing.T) {
+	for _, info := range builtin {
+		if info.Message == "" || info.Category == "" {
+			t.Fatalf("%s lacks a message or category", info.Code)
+		}
+		if got, _ := ByID(info.ID); got.Code != info.Code {
+			t.Fatalf("id %d resolves to %s, not %s", info.ID, got.Code, info.Code)
+		}
+	}
+}
@@ -4,10 +4,17 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/simrand"
 )
 
+// entropy is crypto/rand unless -seed asks for reproducible output.
+var entropy io.Reader = rand.Reader
+
 type ModelUpdate struct {
 	NodeID      string    `json:"node_id"`
 	Round       int       `json:"round"`
@@ -17,6 +24,12 @@ type ModelUpdate struct {
 }
 
 func main() {
+	seed := flag.Int64("seed", 0, "seed for reproducible output (0 uses crypto/rand)")
+	flag.Parse()
+	if *seed != 0 {
+		entropy = simrand.New(*seed)
+	}
+
 	if err := os.MkdirAll("test-data", 0750); err != nil {
 		fmt.Printf("failed to create test-data directory: %v\n", err)
 		os.Exit(1)
@@ -76,7 +89,7 @@ func generateWeights(byzantine bool) []float64 {
 
 func secureFloat64() (float64, error) {
 	var bytes [8]byte
-	if _, err := rand.Read(bytes[:]); err != nil {
+	if _, err := io.ReadFull(entropy, bytes[:]); err != nil {
 		return 0, err
 	}
 	u := binary.LittleEndian.Uint64(bytes[:])
@@ -0,0 +1,77 @@
+package scenarios
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+// voteGossipRun gossips 40 rounds of votes among nodeCount nodes over 20ms
+// links. A 700ms round budget puts the propagation goal at 70ms.
+func voteGossipRun(t *testing.T, nodeCount, messagesPerNode int) *simulator.AdaptiveGossipReport {
+	t.Helper()
+	result, err := simulator.RunContext(t.Context(), simulator.Config{
+		NodeCount:     nodeCount,
+		Rounds:        40,
+		RoundDuration: 700 * time.Millisecond,
+		RandomSeed:    700,
+		AdaptiveGossip: &simulator.AdaptiveGossip{
+			Fanout:     p2p.FanoutConfig{Headroom: 0.7, MessageBudget: messagesPerNode * nodeCount},
+			HopLatency: simulator.LatencyDistribution{Mu: math.Log(20), Sigma: 0.3},
+		},
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.AdaptiveGossip == nil || len(result.AdaptiveGossip.Rounds) != 40 || result.AdaptiveGossip.Goal != 70*time.Millisecond {
+		t.Fatalf("adaptive gossip report = %+v", result.AdaptiveGossip)
+	}
+	return result.AdaptiveGossip
+}
+
+func TestFanoutConvergesPerShardSize(t *testing.T) {
+	settled := make(map[int]float64)
+	for _, nodeCount := range []int{50, 200, 1000} {
+		report := voteGossipRun(t, nodeCount, 12)
+		settled[nodeCount] = report.Settled(20)
+
+		met := 0
+		for _, round := range report.Rounds[20:] {
+			if round.Reached && round.Propagation <= report.Goal {
+				met++
+			}
+		}
+		if met < 18 {
+			t.Fatalf("%d nodes: %d of the last 20 rounds met the %s goal", nodeCount, met, report.Goal)
+		}
+		for _, round := range report.Rounds {
+			if round.Messages > report.MessageBudget {
+				t.Fatalf("%d nodes: round %d sent %d messages, over the budget of %d", nodeCount, round.Round, round.Messages, report.MessageBudget)
+			}
+		}
+	}
+	// The fixed fanout floods the small shard and is too slow for the
+	// large one.
+	if !(settled[50] < p2p.DefaultGossipFanout && settled[50] < settled[200] && settled[200] < settled[1000] && settled[1000] > p2p.DefaultGossipFanout) {
+		t.Fatalf("settled fanouts %v should grow with the shard around the default %d", settled, p2p.DefaultGossipFanout)
+	}
+}
+
+func TestFanoutStaysUnderMessageBudget(t *testing.T) {
+	// Eight messages a node per vote is too few for 1000 nodes to meet the
+	// goal; the controller stops at the budget instead of overspending it.
+	report := voteGossipRun(t, 1000, 8)
+	capped := false
+	for _, round := range report.Rounds {
+		if round.Messages > report.MessageBudget {
+			t.Fatalf("round %d sent %d messages, over the budget of %d", round.Round, round.Messages, report.MessageBudget)
+		}
+		capped = capped || round.Reason == p2p.FanoutCapped
+	}
+	if !capped || report.Settled(10) > 8 {
+		t.Fatalf("expected the budget to cap the fanout at 8, settled at %.1f: %+v", report.Settled(10), report.Rounds[len(report.Rounds)-1])
+	}
+}
@@ -0,0 +1,159 @@
+package scenarios
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func runAttackScenario(t *testing.T, model *simulator.QuadraticModel) simulator.Result {
+	t.Helper()
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:     40,
+		Rounds:        30,
+		RoundDuration: time.Millisecond,
+		RandomSeed:    688,
+		Training:      model,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	return result
+}
+
+func attackOutcomes(result simulator.Result) map[string]simulator.AttackOutcome {
+	outcomes := make(map[string]simulator.AttackOutcome)
+	for _, outcome := range result.Training.Attacks {
+		outcomes[outcome.Strategy] = outcome
+	}
+	return outcomes
+}
+
+func TestAttackStrategiesReportPerStrategySuccess(t *testing.T) {
+	model, err := simulator.LoadQuadraticModel("../simulator/plans/adaptive-attacks-training.json")
+	if err != nil {
+		t.Fatalf("load scenario: %v", err)
+	}
+	result := runAttackScenario(t, model)
+	outcomes := attackOutcomes(result)
+	if len(outcomes) != 9 {
+		t.Fatalf("expected nine strategies in the report, got %+v", result.Training.Attacks)
+	}
+
+	// Both magnitude attacks, static and adaptive, are caught every round.
+	for _, strategy := range []string{simulator.AttackGradientPoisoning, simulator.AttackModelReplacement} {
+		outcome := outcomes[strategy]
+		if outcome.Poisoned != 30 || outcome.SuccessRate != 0 || outcome.Excluded[protocol.ReasonNormOutlier] != 30 {
+			t.Fatalf("%s: %+v", strategy, outcome)
+		}
+	}
+	// Attacks shaped like honest steps get through.
+	for _, strategy := range []string{simulator.AttackSignFlipping, simulator.AttackFreeRider, simulator.AttackColludingSybil} {
+		if outcome := outcomes[strategy]; outcome.Poisoned == 0 || outcome.SuccessRate != 1 {
+			t.Fatalf("%s: %+v", strategy, outcome)
+		}
+	}
+	if outcome := outcomes[simulator.AttackColludingSybil]; outcome.Nodes != 3 || outcome.Poisoned != 90 {
+		t.Fatalf("colluding sybils: %+v", outcome)
+	}
+	// Stealth nodes poison only odd rounds.
+	if outcome := outcomes[simulator.AttackStealth]; outcome.Nodes != 2 || outcome.Poisoned != 30 {
+		t.Fatalf("stealth: %+v", outcome)
+	}
+	// Under the norm filter training still converges.
+	if result.Training.FinalLoss > result.Training.InitialLoss/100 {
+		t.Fatalf("training did not converge: %+v", result.Training)
+	}
+
+	again := runAttackScenario(t, model)
+	if !reflect.DeepEqual(result.Training, again.Training) {
+		t.Fatalf("same seed, different reports:\n%+v\n%+v", result.Training, again.Training)
+	}
+}
+
+func TestColludingSybilsEvadeNormFilterBelowItsThreshold(t *testing.T) {
+	clean := runAttackScenario(t, &simulator.QuadraticModel{Dim: 8, LearningRate: 0.3})
+	colluders := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	run := func(normRatio float64) simulator.Result {
+		return runAttackScenario(t, &simulator.QuadraticModel{Dim: 8, LearningRate: 0.3, Attacks: []simulator.AttackAssignment{
+			{Strategy: simulator.AttackColludingSybil, Nodes: colluders, Params: map[string]float64{"norm_ratio": normRatio}},
+		}})
+	}
+
+	// Within the filter's 3x median norm, the group is never excluded and
+	// holds the model far from the optimum.
+	stealthy := run(2.5)
+	if outcome := attackOutcomes(stealthy)[simulator.AttackColludingSybil]; outcome.SuccessRate != 1 || outcome.Poisoned != 360 {
+		t.Fatalf("stealthy colluders: %+v", outcome)
+	}
+	if stealthy.Training.FinalLoss < 1000*clean.Training.FinalLoss {
+		t.Fatalf("colluders did no damage: loss %g, clean %g", stealthy.Training.FinalLoss, clean.Training.FinalLoss)
+	}
+
+	// Beyond it, every update is excluded.
+	loud := run(5)
+	if outcome := attackOutcomes(loud)[simulator.AttackColludingSybil]; outcome.SuccessRate != 0 || outcome.Excluded[protocol.ReasonNormOutlier] != 360 {
+		t.Fatalf("loud colluders: %+v", outcome)
+	}
+}
+
+func TestAttackAssignmentsAreValidated(t *testing.T) {
+	for _, bad := range []simulator.AttackAssignment{
+		{Strategy: "unknown", Nodes: []int{0}},
+		{Strategy: simulator.AttackStealth},
+		{Strategy: simulator.AttackStealth, Nodes: []int{0}, Params: map[string]float64{"boost": 2}},
+		{Strategy: simulator.AttackStealth, Nodes: []int{0}, Params: map[string]float64{"period": 0}},
+		{Strategy: simulator.AttackSybil, Nodes: []int{1, 1}},
+	} {
+		model := simulator.QuadraticModel{Dim: 4, LearningRate: 0.1, Attacks: []simulator.AttackAssignment{bad}}
+		if err := model.Validate(); err == nil {
+			t.Fatalf("accepted %+v", bad)
+		}
+	}
+
+	model := &simulator.QuadraticModel{Dim: 4, LearningRate: 0.1, Attacks: []simulator.AttackAssignment{
+		{Strategy: simulator.AttackFreeRider, Nodes: []int{3}},
+		{Strategy: simulator.AttackSybil, Nodes: []int{3}},
+	}}
+	if _, err := simulator.RunContext(context.Background(), simulator.Config{NodeCount: 8, Rounds: 1, RandomSeed: 1, Training: model}); err == nil {
+		t.Fatal("accepted a node assigned two strategies")
+	}
+	model.Attacks = []simulator.AttackAssignment{{Strategy: simulator.AttackFreeRider, Nodes: []int{8}}}
+	if _, err := simulator.RunContext(context.Background(), simulator.Config{NodeCount: 8, Rounds: 1, RandomSeed: 1, Training: model}); err == nil {
+		t.Fatal("accepted an attack on a node that does not exist")
+	}
+}
+
+func TestAttackStrategiesScoredInsideFederation(t *testing.T) {
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "aggregator", Timeout: time.Second}))
+	if _, err := registry.Create("traffic"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:     10,
+		Rounds:        6,
+		RoundDuration: time.Millisecond,
+		RandomSeed:    688,
+		Training: &simulator.QuadraticModel{Dim: 8, LearningRate: 0.3, Attacks: []simulator.AttackAssignment{
+			{Strategy: simulator.AttackModelReplacement, Nodes: []int{0}, Params: map[string]float64{"boost": 40}},
+			{Strategy: simulator.AttackStealth, Nodes: []int{1}},
+		}},
+		Federations:  registry,
+		FederationID: "traffic",
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	outcomes := attackOutcomes(result)
+	if outcome := outcomes[simulator.AttackModelReplacement]; outcome.Poisoned != 6 || outcome.Excluded[protocol.ReasonNormOutlier] != 6 {
+		t.Fatalf("model replacement: %+v", outcome)
+	}
+	if outcome := outcomes[simulator.AttackStealth]; outcome.Poisoned != 3 || outcome.Included != 3 {
+		t.Fatalf("stealth: %+v", outcome)
+	}
+}
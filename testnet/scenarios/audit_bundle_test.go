@@ -0,0 +1,184 @@
+package scenarios
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/audit"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+// auditedRound runs a signed, verified federation for three rounds and
+// collects the evidence for round 2.
+func auditedRound(t *testing.T) (*audit.Bundle, *crypto.SecureChannel) {
+	t.Helper()
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "aggregator", Timeout: time.Second}))
+	f, err := registry.Create("traffic")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	identity, err := crypto.NewSecureChannel()
+	if err != nil {
+		t.Fatalf("identity: %v", err)
+	}
+	if err := f.ModelStore.SetSigner(identity); err != nil {
+		t.Fatalf("set signer: %v", err)
+	}
+	if _, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:             6,
+		Rounds:                3,
+		RoundDuration:         time.Millisecond,
+		RandomSeed:            701,
+		Training:              &simulator.QuadraticModel{Dim: 20, LearningRate: 0.1, ByzantineNodes: 1},
+		Federations:           registry,
+		FederationID:          "traffic",
+		VerificationCommittee: 3,
+	}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	bundle, err := audit.Collect(f, 2, audit.Options{HostID: "aggregator", Sample: 4})
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := len(bundle.Verifications); got != 4*3 {
+		t.Fatalf("exported %d responses, want 3 for each of 4 sampled requests", got)
+	}
+	return bundle, identity
+}
+
+func exportBundle(t *testing.T, bundle *audit.Bundle, identity *crypto.SecureChannel) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := bundle.Export(&buf, "aggregator", identity); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func verifyBundle(t *testing.T, data, trusted []byte) *protocol.AuditReport {
+	t.Helper()
+	report, err := protocol.VerifyAuditBundle(bytes.NewReader(data), trusted)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	return report
+}
+
+// corruptEntry flips one byte in the middle of a bundle entry.
+func corruptEntry(t *testing.T, data []byte, name string) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	tr, tw := tar.NewReader(bytes.NewReader(data)), tar.NewWriter(&out)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read bundle: %v", err)
+		}
+		body, _ := io.ReadAll(tr)
+		if hdr.Name == name {
+			body[len(body)/2] ^= 0x01
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestAuditBundleVerifiesOffline(t *testing.T) {
+	bundle, identity := auditedRound(t)
+	data := exportBundle(t, bundle, identity)
+	trusted, _ := identity.ExportPublicKey()
+
+	report := verifyBundle(t, data, trusted)
+	if !report.Valid() {
+		t.Fatalf("clean bundle has findings: %+v", report.Findings)
+	}
+	if report.Index.Round != 2 || report.Index.FederationID != "traffic" || len(report.Index.Artifacts) != len(protocol.AuditArtifactPaths()) {
+		t.Fatalf("index = %+v", report.Index)
+	}
+
+	// Another exporter's bundle is intact but not the trusted one's.
+	other, _ := crypto.NewSecureChannel()
+	otherKey, _ := other.ExportPublicKey()
+	if faulty := verifyBundle(t, data, otherKey).Faulty(); len(faulty) != 1 || faulty[0] != protocol.AuditIndexPath {
+		t.Fatalf("untrusted exporter: faulty = %v", faulty)
+	}
+}
+
+func TestAuditVerifierPinpointsCorruptArtifact(t *testing.T) {
+	bundle, identity := auditedRound(t)
+	data := exportBundle(t, bundle, identity)
+	trusted, _ := identity.ExportPublicKey()
+
+	for _, path := range append(protocol.AuditArtifactPaths(), protocol.AuditIndexPath) {
+		report := verifyBundle(t, corruptEntry(t, data, path), trusted)
+		if faulty := report.Faulty(); len(faulty) != 1 || faulty[0] != path {
+			t.Fatalf("corrupt %s: faulty = %v, findings %+v", path, faulty, report.Findings)
+		}
+	}
+}
+
+func TestAuditVerifierChecksArtifactClaims(t *testing.T) {
+	bundle, identity := auditedRound(t)
+	trusted, _ := identity.ExportPublicKey()
+
+	// Each artifact below is tampered with before export, so its digest
+	// matches a correctly signed index and only its content gives it away.
+	for name, tc := range map[string]struct {
+		path   string
+		tamper func(b *audit.Bundle)
+		detail string
+	}{
+		"flipped verdict": {protocol.AuditVerificationsPath, func(b *audit.Bundle) {
+			b.Verifications[0].Valid = !b.Verifications[0].Valid
+		}, "signature does not verify"},
+		"response about another update": {protocol.AuditVerificationsPath, func(b *audit.Bundle) {
+			b.Verifications[0].UpdateDigest = protocol.UpdateDigest([]byte("other"))
+		}, "the manifest records"},
+		"dropped participant": {protocol.AuditManifestPath, func(b *audit.Bundle) {
+			manifest := *b.Manifest
+			manifest.Entries = manifest.Entries[1:]
+			b.Manifest = &manifest
+		}, "committed digest"},
+		"forged approval": {protocol.AuditReplayPath, func(b *audit.Bundle) {
+			b.Replay[0].Approvals = append([]string{"intruder"}, b.Replay[0].Approvals...)
+		}, "approvals differ"},
+		"overspent budget": {protocol.AuditPrivacyPath, func(b *audit.Bundle) {
+			b.Privacy.Spent.Epsilon = b.Privacy.Budget.Epsilon * 2
+		}, "of a budget"},
+		"reweighted model": {protocol.AuditCommitPath, func(b *audit.Bundle) {
+			b.Commit.ModelDigest = protocol.UpdateDigest([]byte("other"))
+			b.Commit.Certificate.ModelDigest = b.Commit.ModelDigest
+		}, "aggregator signature"},
+	} {
+		tampered := *bundle
+		tampered.Verifications = append([]protocol.AuditVerification(nil), bundle.Verifications...)
+		tampered.Replay = append([]protocol.AuditReplay(nil), bundle.Replay...)
+		tc.tamper(&tampered)
+
+		report := verifyBundle(t, exportBundle(t, &tampered, identity), trusted)
+		faulty := report.Faulty()
+		if len(faulty) == 0 || faulty[0] != tc.path || !strings.Contains(report.Findings[0].Detail, tc.detail) {
+			t.Fatalf("%s: faulty = %v, findings %+v", name, faulty, report.Findings)
+		}
+	}
+}
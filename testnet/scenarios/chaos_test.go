@@ -0,0 +1,37 @@
+package scenarios
+
+import (
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/chaos"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func TestByzantine55WithChaosPlanIsReproducible(t *testing.T) {
+	plan, err := chaos.LoadPlan("../simulator/plans/byzantine-55-chaos.json")
+	if err != nil {
+		t.Fatalf("load chaos plan: %v", err)
+	}
+	cfg, err := simulator.Preset("byzantine-55")
+	if err != nil {
+		t.Fatalf("preset: %v", err)
+	}
+	cfg.RandomSeed = 55
+	cfg.Chaos = plan
+
+	first := simulator.Run(cfg)
+	second := simulator.Run(cfg)
+
+	if first.Chaos == nil || second.Chaos == nil {
+		t.Fatal("expected a chaos report")
+	}
+	if simulator.FormatSummary(first) != simulator.FormatSummary(second) {
+		t.Fatalf("expected reproducible runs:\n%s\n%s", simulator.FormatSummary(first), simulator.FormatSummary(second))
+	}
+	if first.RoundsCompleted+first.FailedRounds != cfg.Rounds {
+		t.Fatalf("expected every round to be accounted for, got %d completed and %d failed", first.RoundsCompleted, first.FailedRounds)
+	}
+	if first.Chaos.Partitioned == 0 || first.Chaos.PeerDownRounds == 0 || first.Chaos.Dropped == 0 {
+		t.Fatalf("expected partition, crash, and drop faults, got %+v", *first.Chaos)
+	}
+}
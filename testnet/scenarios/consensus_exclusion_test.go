@@ -0,0 +1,92 @@
+package scenarios
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+func TestPoisonedRoundIsRetriedWithoutFlaggedUpdates(t *testing.T) {
+	ctx := context.Background()
+	peers := []string{"peer-1", "peer-2", "peer-3", "peer-4"}
+	agg := consensus.NewDistributedAggregator("agg-1", peers, 5*time.Second)
+	// The poison is subtle enough to pass the aggregator's own filters;
+	// only the voters' review catches it.
+	agg.SetBatchAggregator(batch.NewAggregator(&batch.Config{OutlierFactor: -1}))
+	agg.SetRetryPolicy(consensus.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+
+	// Every peer rejects a proposal that applied node-poison's update and
+	// flags it; peer-4 also finds node-2 suspicious.
+	proposals := 0
+	agg.SetPeerReviewer(func(peerID string, proposal *consensus.ModelProposal) (bool, map[string]reasons.Code) {
+		if peerID == peers[0] {
+			proposals++
+		}
+		flags := make(map[string]reasons.Code)
+		for _, entry := range proposal.Manifest.Entries {
+			if !entry.Included {
+				continue
+			}
+			switch {
+			case entry.NodeID == "node-poison":
+				flags[batch.ReceiptID(proposal.Round, entry)] = protocol.ReasonDetected
+			case entry.NodeID == "node-2" && peerID == "peer-4":
+				flags[batch.ReceiptID(proposal.Round, entry)] = protocol.ReasonNormOutlier
+			}
+		}
+		if _, poisoned := proposal.Manifest.Entry("node-poison"); poisoned && len(flags) > 0 {
+			return false, flags
+		}
+		return true, nil
+	})
+
+	for nodeID, weights := range map[string][]float64{
+		"node-1":      {1, 1},
+		"node-2":      {2, 2},
+		"node-3":      {3, 3},
+		"node-poison": {40, -40},
+	} {
+		if err := agg.SubmitModel(ctx, nodeID, batch.Update{Weights: weights}.Bytes()); err != nil {
+			t.Fatalf("submit %s: %v", nodeID, err)
+		}
+	}
+
+	committed, err := agg.AggregateWithConsensus(ctx)
+	if err != nil {
+		t.Fatalf("expected the retry to commit, got %v", err)
+	}
+	if proposals != 2 {
+		t.Fatalf("expected one rejected proposal and one committed, got %d proposals", proposals)
+	}
+	history := agg.History()
+	if len(history) != 2 || history[0].Outcome != consensus.OutcomeFailed || history[1].Outcome != consensus.OutcomeCommitted {
+		t.Fatalf("history = %+v", history)
+	}
+
+	// node-2 counts for the three quarters of peers that did not flag it.
+	weights, err := batch.DecodeWeights(committed)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := (1 + 0.75*2 + 3) / 2.75
+	if math.Abs(weights[0]-want) > 1e-9 || math.Abs(weights[1]-want) > 1e-9 {
+		t.Fatalf("committed %v, want %g in both coordinates", weights, want)
+	}
+
+	// The committed manifest tells node-poison why it was left out.
+	manifest := agg.GetLastManifest()
+	receipt, ok := protocol.NewInclusionReceipt(manifest, "node-poison")
+	if !ok || receipt.Included || receipt.Reason != protocol.ReasonDetected {
+		t.Fatalf("node-poison receipt = %+v", receipt)
+	}
+	suspicious, _ := manifest.Entry("node-2")
+	if !suspicious.Included || suspicious.ConsensusWeight != 0.75 || math.Abs(suspicious.AppliedWeight-0.75/2.75) > 1e-9 {
+		t.Fatalf("node-2 entry = %+v", suspicious)
+	}
+}
@@ -0,0 +1,80 @@
+package scenarios
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+// slowRegionConfig is a run in which 60% of the nodes, a region on an
+// overnight charging window, train two minutes past a ten-minute task
+// deadline.
+func slowRegionConfig(extensions *scheduler.ExtensionPolicy) simulator.Config {
+	return simulator.Config{
+		NodeCount:          20,
+		Rounds:             6,
+		RoundDuration:      9 * time.Minute,
+		RandomSeed:         697,
+		TaskDeadline:       10 * time.Minute,
+		SlowCohort:         &simulator.SlowCohort{Fraction: 0.6, Delay: 2 * time.Minute},
+		DeadlineExtensions: extensions,
+		Training:           &simulator.QuadraticModel{Dim: 8, LearningRate: 0.5},
+	}
+}
+
+func TestSlowCohortRoundsFailWithoutExtensions(t *testing.T) {
+	result, err := simulator.RunContext(t.Context(), slowRegionConfig(nil))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.FailedRounds != result.RoundsRequested || result.RoundsCompleted != 0 {
+		t.Fatalf("failed %d, completed %d of %d rounds; the slow cohort should fail every round", result.FailedRounds, result.RoundsCompleted, result.RoundsRequested)
+	}
+	if result.Deadlines == nil || result.Deadlines.MissedUpdates != 12*result.RoundsRequested || result.Deadlines.Extensions != 0 {
+		t.Fatalf("deadlines = %+v", result.Deadlines)
+	}
+}
+
+func TestSlowCohortRoundsSucceedWithExtensions(t *testing.T) {
+	policy := scheduler.DefaultExtensionPolicy()
+	result, err := simulator.RunContext(t.Context(), slowRegionConfig(&policy))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.FailedRounds != 0 || result.RoundsCompleted != result.RoundsRequested {
+		t.Fatalf("failed %d, completed %d of %d rounds", result.FailedRounds, result.RoundsCompleted, result.RoundsRequested)
+	}
+	// Eleven of the twelve slow nodes carry the round past half of the
+	// twenty; the twelfth hears the new deadline before it asks.
+	deadlines := result.Deadlines
+	if deadlines.Extensions != result.RoundsRequested || deadlines.MissedUpdates != 0 || deadlines.Requests != 11*result.RoundsRequested {
+		t.Fatalf("deadlines = %+v", deadlines)
+	}
+	// The slow cohort finishes two minutes late, so rounds run eleven.
+	if result.AverageRoundDuration != 11*time.Minute {
+		t.Fatalf("average round = %s, want 11m", result.AverageRoundDuration)
+	}
+	if result.Training.FinalLoss >= result.Training.InitialLoss {
+		t.Fatalf("training did not progress: %+v", result.Training)
+	}
+	// One request a round stays within every node's free requests.
+	if deadlines.Penalties != 0 {
+		t.Fatalf("penalties = %d", deadlines.Penalties)
+	}
+}
+
+func TestExtensionsNeverExceedTheRoundBudget(t *testing.T) {
+	policy := scheduler.DefaultExtensionPolicy()
+	// Thirty seconds of budget beyond the deadline cannot cover a
+	// two-minute delay.
+	policy.RoundBudget = 10*time.Minute + 30*time.Second
+	result, err := simulator.RunContext(t.Context(), slowRegionConfig(&policy))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.FailedRounds != result.RoundsRequested || result.Deadlines.Extensions != result.RoundsRequested {
+		t.Fatalf("failed %d rounds, extended %d", result.FailedRounds, result.Deadlines.Extensions)
+	}
+}
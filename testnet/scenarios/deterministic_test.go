@@ -0,0 +1,74 @@
+package scenarios
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/chaos"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+// byzantine55Report runs byzantine-55 with every seeded component enabled
+// and returns the JSON report the simulator CLI would write.
+func byzantine55Report(t *testing.T, seed int64) (simulator.Result, []byte) {
+	t.Helper()
+	cfg, err := simulator.Preset("byzantine-55")
+	if err != nil {
+		t.Fatalf("preset: %v", err)
+	}
+	if cfg.Chaos, err = chaos.LoadPlan("../simulator/plans/byzantine-55-chaos.json"); err != nil {
+		t.Fatalf("load chaos plan: %v", err)
+	}
+	if cfg.Network, err = simulator.LoadNetworkModel("../simulator/plans/wan-3-region-network.json"); err != nil {
+		t.Fatalf("load network model: %v", err)
+	}
+	cfg.RandomSeed = seed
+	cfg.Training = &simulator.QuadraticModel{Dim: 20, LearningRate: 0.2, Sparsity: 0.25, ClipNorm: 0.05}
+	cfg.ParticipationRate = 0.6
+	cfg.GossipFanout = 3
+
+	result := simulator.Run(cfg)
+	report, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("encode report: %v", err)
+	}
+	return result, report
+}
+
+func TestSeededByzantine55IsBitReproducible(t *testing.T) {
+	first, firstReport := byzantine55Report(t, 659)
+	second, secondReport := byzantine55Report(t, 659)
+	if string(firstReport) != string(secondReport) {
+		t.Fatalf("same seed produced different reports:\n%s\n%s", firstReport, secondReport)
+	}
+	if simulator.FormatSummary(first) != simulator.FormatSummary(second) {
+		t.Fatalf("same seed produced different summaries:\n%s\n%s", simulator.FormatSummary(first), simulator.FormatSummary(second))
+	}
+	if first.Seed != 659 || first.Training == nil || first.Gossip == nil || first.Participants == 0 {
+		t.Fatalf("expected every seeded component in the report, got %+v", first)
+	}
+
+	other, otherReport := byzantine55Report(t, 660)
+	if string(firstReport) == string(otherReport) {
+		t.Fatal("different seeds produced identical reports")
+	}
+	if reflect.DeepEqual(first.Training, other.Training) {
+		t.Fatalf("training data and privacy noise did not depend on the seed: %+v", *first.Training)
+	}
+	if reflect.DeepEqual(first.Gossip, other.Gossip) {
+		t.Fatalf("gossip targets did not depend on the seed: %+v", *first.Gossip)
+	}
+}
+
+func TestZeroSeedIsRecordedForReplay(t *testing.T) {
+	cfg := simulator.Config{NodeCount: 10, Rounds: 5, StragglerRate: 0.5, GossipFanout: 2}
+	first := simulator.Run(cfg)
+	if first.Seed == 0 {
+		t.Fatal("expected the clock seed to be recorded")
+	}
+	cfg.RandomSeed = first.Seed
+	if replay := simulator.Run(cfg); !reflect.DeepEqual(first, replay) {
+		t.Fatalf("replaying seed %d diverged:\n%+v\n%+v", first.Seed, first, replay)
+	}
+}
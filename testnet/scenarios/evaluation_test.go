@@ -0,0 +1,159 @@
+package scenarios
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+// syntheticAccuracy gives node n of count a known accuracy spread evenly
+// over [0.55, 0.95].
+func syntheticAccuracy(count int) []float64 {
+	accuracy := make([]float64, count)
+	for n := range accuracy {
+		accuracy[n] = 0.55 + 0.4*float64(n)/float64(count-1)
+	}
+	return accuracy
+}
+
+func TestEvaluationEstimatesWeightedAccuracy(t *testing.T) {
+	const nodes = 40
+	accuracy := syntheticAccuracy(nodes)
+
+	// Sampling every node without noise recovers the weighted accuracy
+	// exactly.
+	census, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:     nodes,
+		Rounds:        3,
+		RoundDuration: time.Millisecond,
+		RandomSeed:    681,
+		Training:      &simulator.QuadraticModel{Dim: 8, LearningRate: 0.1},
+		Evaluation:    &simulator.EvaluationConfig{SampleRate: 1, NodeAccuracy: accuracy},
+	})
+	if err != nil {
+		t.Fatalf("census run: %v", err)
+	}
+	report := census.Evaluation
+	if report == nil || len(report.Rounds) != 3 {
+		t.Fatalf("census evaluation = %+v", report)
+	}
+	for i, round := range report.Rounds {
+		if round.Reported != nodes || math.Abs(round.Accuracy.Mean-report.TrueAccuracy[i]) > 1e-12 {
+			t.Fatalf("round %d estimate %.6f from %d reports, true %.6f", round.Round, round.Accuracy.Mean, round.Reported, report.TrueAccuracy[i])
+		}
+	}
+
+	// A quarter sample lands within its confidence interval of the truth.
+	sampled, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:     nodes,
+		Rounds:        10,
+		RoundDuration: time.Millisecond,
+		RandomSeed:    681,
+		Training:      &simulator.QuadraticModel{Dim: 8, LearningRate: 0.1},
+		Evaluation:    &simulator.EvaluationConfig{SampleRate: 0.25, NodeAccuracy: accuracy},
+	})
+	if err != nil {
+		t.Fatalf("sampled run: %v", err)
+	}
+	covered := 0
+	for i, round := range sampled.Evaluation.Rounds {
+		if round.Sampled != nodes/4 || round.Reported != nodes/4 {
+			t.Fatalf("round %d sampled %d, reported %d", round.Round, round.Sampled, round.Reported)
+		}
+		if math.Abs(round.Accuracy.Mean-sampled.Evaluation.TrueAccuracy[i]) > 0.1 {
+			t.Fatalf("round %d estimate %.4f far from true %.4f", round.Round, round.Accuracy.Mean, sampled.Evaluation.TrueAccuracy[i])
+		}
+		if round.Accuracy.Lower <= sampled.Evaluation.TrueAccuracy[i] && sampled.Evaluation.TrueAccuracy[i] <= round.Accuracy.Upper {
+			covered++
+		}
+	}
+	if covered < 8 {
+		t.Fatalf("95%% intervals covered the truth in only %d of 10 rounds", covered)
+	}
+}
+
+func TestEvaluationStaysWithinMetricAllocation(t *testing.T) {
+	const (
+		nodes  = 20
+		rounds = 12
+		// Loss and accuracy cost ε=0.5 each: ε=3 pays for three reports.
+		allocation = 3.0
+		maxReports = 3
+	)
+	accuracy := syntheticAccuracy(nodes)
+	dp := privacy.MetricsDPConfig{Epsilon: 0.5, Sensitivity: 1, RoundBucket: 1, CountBucket: 10}
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:     nodes,
+		Rounds:        rounds,
+		RoundDuration: time.Millisecond,
+		RandomSeed:    6810,
+		Training:      &simulator.QuadraticModel{Dim: 8, LearningRate: 0.1},
+		Evaluation:    &simulator.EvaluationConfig{SampleRate: 0.5, NodeAccuracy: accuracy, MetricsDP: &dp, Allocation: allocation},
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	report := result.Evaluation
+	if report.Declined == 0 {
+		t.Fatalf("no node ever exhausted its allocation: %+v", report)
+	}
+	for nodeID, count := range report.Reports {
+		if count > maxReports {
+			t.Fatalf("%s released %d reports on an allocation for %d", nodeID, count, maxReports)
+		}
+	}
+	for i, round := range report.Rounds {
+		if !round.Noised {
+			t.Fatalf("round %d reports were not noised", round.Round)
+		}
+		if !(round.Accuracy.Lower <= report.TrueAccuracy[i] && report.TrueAccuracy[i] <= round.Accuracy.Upper) {
+			t.Fatalf("round %d interval [%.3f, %.3f] misses true accuracy %.3f", round.Round, round.Accuracy.Lower, round.Accuracy.Upper, report.TrueAccuracy[i])
+		}
+	}
+	// Evaluation draws only on the metric allocation; training converges
+	// as it would without it.
+	if result.Training.FinalLoss >= result.Training.InitialLoss {
+		t.Fatalf("training did not converge: %+v", result.Training)
+	}
+}
+
+func TestEvaluationIsRecordedInFederationModelStore(t *testing.T) {
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "aggregator", Timeout: time.Second}))
+	f, err := registry.Create("eval")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	const rounds = 4
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:     10,
+		Rounds:        rounds,
+		RoundDuration: time.Millisecond,
+		RandomSeed:    681,
+		Training:      &simulator.QuadraticModel{Dim: 8, LearningRate: 0.1},
+		Federations:   registry,
+		FederationID:  "eval",
+		Evaluation:    &simulator.EvaluationConfig{SampleRate: 0.5},
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	stored := f.ModelStore.Evaluations(1, 0)
+	if len(stored) != rounds || len(result.Evaluation.Rounds) != rounds {
+		t.Fatalf("stored %d evaluations, reported %d, want %d", len(stored), len(result.Evaluation.Rounds), rounds)
+	}
+	for i, evaluation := range stored {
+		summary := f.ModelStore.Summaries(evaluation.Round, evaluation.Round)
+		if len(summary) != 1 || summary[0].ModelDigest != evaluation.ModelDigest {
+			t.Fatalf("evaluation %d does not match its committed model", evaluation.Round)
+		}
+		// Accuracy defaults to 1/(1+loss), which rises as training converges.
+		if i > 0 && evaluation.Accuracy.Mean <= stored[i-1].Accuracy.Mean {
+			t.Fatalf("accuracy fell from %.4f to %.4f", stored[i-1].Accuracy.Mean, evaluation.Accuracy.Mean)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package scenarios
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/failover"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+)
+
+// loopback delivers transport messages straight to registered handlers.
+type loopback struct {
+	mu       sync.Mutex
+	handlers map[string]func(topic string, payload []byte) error
+	down     map[string]bool
+}
+
+func (l *loopback) Send(_ context.Context, peerID string, msg p2p.OutboundMessage) error {
+	l.mu.Lock()
+	handler, down := l.handlers[peerID], l.down[peerID]
+	l.mu.Unlock()
+	if handler == nil || down {
+		return nil
+	}
+	return handler(msg.Topic, msg.Payload)
+}
+
+func (l *loopback) setDown(peerID string, down bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.down[peerID] = down
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// commitRound proposes round on coordinator and commits it with votes from
+// the proposer and members.
+func commitRound(ctx context.Context, coordinator *consensus.Coordinator, proposer string, round int) error {
+	proposalID, err := coordinator.ProposeModel(ctx, &consensus.ModelProposal{
+		Round:      round,
+		Weights:    []byte(fmt.Sprintf("global-%d", round)),
+		ProposerID: proposer,
+		Timestamp:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, voter := range []string{proposer, "member-1", "member-2"} {
+		if err := coordinator.CastVote(ctx, &consensus.Vote{NodeID: voter, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			return err
+		}
+	}
+	if err := coordinator.CommitModel(ctx, proposalID); err != nil {
+		return err
+	}
+	coordinator.Reset()
+	return nil
+}
+
+func TestStandbyCompletesRoundAfterPrimaryDiesMidRound(t *testing.T) {
+	ctx := context.Background()
+	_, standbyKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	// Each aggregator stands in for member-3 in the other's 4-node shard.
+	net := &loopback{handlers: make(map[string]func(string, []byte) error), down: make(map[string]bool)}
+	transport := p2p.NewTransport(net, p2p.QueueConfig{Depth: 256})
+	defer func() { _ = transport.Close() }()
+
+	primaryCoordinator := consensus.NewCoordinator("aggregator-a", 4, time.Minute)
+	primary := failover.NewPrimary("aggregator-a", 1, primaryCoordinator, transport, "aggregator-b")
+	primary.SetTrustedKey(standbyKey.Public().(ed25519.PublicKey))
+
+	standbyCoordinator := consensus.NewCoordinator("aggregator-b", 4, time.Minute)
+	standbyQueue := island.NewManager(time.Hour, 64, func() bool { return true })
+	var noticesSeen sync.Map
+	standby, err := failover.NewStandby("aggregator-b", "aggregator-a", standbyKey,
+		failover.StandbyConfig{HeartbeatTimeout: 200 * time.Millisecond, Announce: []string{"member-1", "member-2"}},
+		standbyCoordinator, standbyQueue, transport)
+	if err != nil {
+		t.Fatalf("new standby: %v", err)
+	}
+
+	net.handlers["aggregator-b"] = standby.HandleMessage
+	net.handlers["aggregator-a"] = primary.HandleMessage
+	for _, member := range []string{"member-1", "member-2"} {
+		member := member
+		net.handlers[member] = func(topic string, _ []byte) error {
+			noticesSeen.Store(member+"/"+topic, true)
+			return nil
+		}
+	}
+
+	// Rounds 1-3 commit on the primary and replicate as they go.
+	for round := 1; round <= 3; round++ {
+		if err := commitRound(ctx, primaryCoordinator, "aggregator-a", round); err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+		if err := primary.ReplicateCommits(); err != nil {
+			t.Fatalf("round %d: replicate: %v", round, err)
+		}
+		if err := primary.Heartbeat(); err != nil {
+			t.Fatalf("round %d: heartbeat: %v", round, err)
+		}
+	}
+
+	// Round 4 ingests updates and gathers some votes, then the primary dies.
+	for i := 1; i <= 2; i++ {
+		update := island.Update{Round: 4, PeerID: fmt.Sprintf("member-%d", i), ModelDelta: []byte("delta"), Timestamp: time.Now()}
+		if err := primary.ReplicateUpdate(update); err != nil {
+			t.Fatalf("replicate update: %v", err)
+		}
+	}
+	inflight, err := primaryCoordinator.ProposeModel(ctx, &consensus.ModelProposal{Round: 4, Weights: []byte("global-4"), ProposerID: "aggregator-a", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("propose round 4: %v", err)
+	}
+	for _, voter := range []string{"aggregator-a", "member-1"} {
+		if err := primaryCoordinator.CastVote(ctx, &consensus.Vote{NodeID: voter, ProposalID: inflight, Approve: true, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("vote: %v", err)
+		}
+	}
+	waitFor(t, "replication", func() bool { return standby.AppliedSequence() == 5 })
+	net.setDown("aggregator-a", true)
+
+	if _, err := standbyCoordinator.ProposeModel(ctx, &consensus.ModelProposal{Round: 4, Weights: []byte("w"), ProposerID: "aggregator-b"}); !errors.Is(err, consensus.ErrNotLeader) {
+		t.Fatalf("expected the standby to stay passive before takeover, got %v", err)
+	}
+	if notice, _ := standby.Check(time.Now()); notice != nil {
+		t.Fatal("expected no takeover before the heartbeat timeout")
+	}
+
+	notice, err := standby.Check(time.Now().Add(time.Second))
+	if err != nil || notice == nil {
+		t.Fatalf("expected takeover, got %+v, %v", notice, err)
+	}
+	if notice.LastSequence != 5 || notice.LastRound != 3 || notice.Epoch != 2 {
+		t.Fatalf("unexpected failover notice %+v", notice)
+	}
+	if got := len(standbyQueue.GetCachedUpdates()); got != 2 {
+		t.Fatalf("expected the round 4 updates on the standby, got %d", got)
+	}
+	waitFor(t, "members to see the notice", func() bool {
+		_, ok := noticesSeen.Load("member-2/" + failover.TopicNotice)
+		return ok
+	})
+
+	if err := commitRound(ctx, standbyCoordinator, "aggregator-b", standby.NextRound()); err != nil {
+		t.Fatalf("standby round %d: %v", standby.NextRound(), err)
+	}
+
+	// The old primary recovers, receives the notice, and must not finish
+	// its in-flight round.
+	net.setDown("aggregator-a", false)
+	if _, err := transport.Enqueue("aggregator-a", p2p.OutboundMessage{Topic: failover.TopicNotice, Payload: mustJSON(t, notice), Priority: p2p.PriorityCommit}); err != nil {
+		t.Fatalf("redeliver notice: %v", err)
+	}
+	waitFor(t, "the primary to be fenced", func() bool { return primary.Gate() != nil })
+	if err := primaryCoordinator.CastVote(ctx, &consensus.Vote{NodeID: "member-2", ProposalID: inflight, Approve: true, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("late vote: %v", err)
+	}
+	if err := primaryCoordinator.CommitModel(ctx, inflight); !errors.Is(err, consensus.ErrNotLeader) {
+		t.Fatalf("expected the fenced primary to refuse its commit, got %v", err)
+	}
+	if err := primary.Heartbeat(); !errors.Is(err, failover.ErrSuperseded) {
+		t.Fatalf("expected the fenced primary to stop heartbeating, got %v", err)
+	}
+
+	commits := make(map[int]map[string]bool)
+	for _, log := range [][]consensus.ReplayEntry{primaryCoordinator.ReplayLog(), standbyCoordinator.ReplayLog()} {
+		for _, entry := range log {
+			if commits[entry.Round] == nil {
+				commits[entry.Round] = make(map[string]bool)
+			}
+			commits[entry.Round][entry.ProposalID] = true
+		}
+	}
+	for round := 1; round <= 4; round++ {
+		if len(commits[round]) != 1 {
+			t.Fatalf("expected exactly one commit for round %d, got %v", round, commits[round])
+		}
+	}
+	if len(standbyCoordinator.ReplayLog()) != 4 {
+		t.Fatalf("expected the standby to hold rounds 1-4, got %d entries", len(standbyCoordinator.ReplayLog()))
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	payload, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return payload
+}
@@ -0,0 +1,120 @@
+package scenarios
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func TestConcurrentFederationsDoNotLeak(t *testing.T) {
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "aggregator", Timeout: time.Second}))
+	ids := []string{"traffic", "parking"}
+	for _, id := range ids {
+		if _, err := registry.Create(id); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+
+	const rounds = 20
+	results := make(map[string]simulator.Result)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(id string, nodes int) {
+			defer wg.Done()
+			result, err := simulator.RunContext(context.Background(), simulator.Config{
+				NodeCount:     nodes,
+				Rounds:        rounds,
+				RoundDuration: time.Millisecond,
+				RandomSeed:    658,
+				Training:      &simulator.QuadraticModel{Dim: 50, LearningRate: 0.1},
+				Federations:   registry,
+				FederationID:  id,
+			})
+			if err != nil {
+				t.Errorf("federation %s: %v", id, err)
+			}
+			mu.Lock()
+			results[id] = result
+			mu.Unlock()
+		}(id, 6+2*i)
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	for i, id := range ids {
+		f, err := registry.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prefix := id + "-node-"
+		nodes := 6 + 2*i
+
+		if got := results[id].RoundsCompleted; got != rounds {
+			t.Fatalf("%s completed %d rounds, want %d", id, got, rounds)
+		}
+		if training := results[id].Training; training.FinalLoss >= training.InitialLoss {
+			t.Fatalf("%s did not train: loss %g -> %g", id, training.InitialLoss, training.FinalLoss)
+		}
+		if f.ModelStore.LatestRound() != rounds {
+			t.Fatalf("%s committed through round %d, want %d", id, f.ModelStore.LatestRound(), rounds)
+		}
+
+		// Updates: every manifest lists only the federation's own nodes.
+		for round := 1; round <= rounds; round++ {
+			manifest, ok := f.ModelStore.Manifest(round)
+			if !ok {
+				t.Fatalf("%s has no manifest for round %d", id, round)
+			}
+			if len(manifest.Entries) != nodes {
+				t.Fatalf("%s round %d manifest has %d entries, want %d", id, round, len(manifest.Entries), nodes)
+			}
+			for _, entry := range manifest.Entries {
+				if !strings.HasPrefix(entry.NodeID, prefix) {
+					t.Fatalf("%s round %d includes update from %s", id, round, entry.NodeID)
+				}
+			}
+		}
+
+		// Votes: every commit certificate carries only the federation's
+		// members, and no vote was refused for crossing federations.
+		for _, summary := range f.ModelStore.Summaries(1, rounds) {
+			for _, approver := range summary.Certificate.Approvals {
+				if !strings.HasPrefix(approver, prefix) {
+					t.Fatalf("%s round %d approved by %s", id, summary.Round, approver)
+				}
+			}
+		}
+		if rejections := f.Coordinator.VoteRejections(); len(rejections) != 0 {
+			t.Fatalf("%s rejected votes: %+v", id, rejections)
+		}
+
+		// Metrics: the collector and the simulator export stay in namespace.
+		metrics := f.Metrics.GetMetrics()
+		if len(metrics) == 0 {
+			t.Fatalf("%s recorded no metrics", id)
+		}
+		for _, metric := range metrics {
+			if !strings.HasPrefix(metric.NodeID, prefix) || metric.Labels["federation"] != id {
+				t.Fatalf("%s collector holds metric from %s labeled %v", id, metric.NodeID, metric.Labels)
+			}
+		}
+		exported := simulator.Prometheus(results[id], "federations")
+		if !strings.Contains(exported, `federation="`+id+`"`) {
+			t.Fatalf("%s export lacks its federation label", id)
+		}
+		for _, other := range ids {
+			if other != id && strings.Contains(exported, `federation="`+other+`"`) {
+				t.Fatalf("%s export carries %s's label", id, other)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package scenarios
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func TestAdvisorCutsDivergenceOnNonIIDShards(t *testing.T) {
+	const rounds = 40
+	run := func(policy convergence.AdvisorPolicy) *simulator.TrainingReport {
+		t.Helper()
+		result, err := simulator.RunContext(context.Background(), simulator.Config{
+			NodeCount:     10,
+			Rounds:        rounds,
+			RoundDuration: time.Millisecond,
+			RandomSeed:    706,
+			// Curvatures up to 7 make the static rate overshoot on the
+			// steepest shards.
+			Training: &simulator.QuadraticModel{Dim: 20, LearningRate: 0.3, Epochs: 3, CurvatureSpread: 6, Advisor: policy},
+		})
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		return result.Training
+	}
+	static := run(nil)
+	advised := run(convergence.DispersionPolicy{Reference: 0.5})
+
+	if static.DivergenceAlerts == 0 {
+		t.Fatal("static hyperparameters never diverged; the shards are not heterogeneous enough")
+	}
+	if advised.DivergenceAlerts >= static.DivergenceAlerts {
+		t.Fatalf("advised run raised %d divergence alerts, static %d", advised.DivergenceAlerts, static.DivergenceAlerts)
+	}
+	if advised.FinalLoss > static.FinalLoss {
+		t.Fatalf("advised loss %g is above static %g", advised.FinalLoss, static.FinalLoss)
+	}
+
+	// Both runs record the hyperparameters of every round, and of the next.
+	for name, report := range map[string]*simulator.TrainingReport{"static": static, "advised": advised} {
+		if len(report.Hyperparameters) != rounds+1 {
+			t.Fatalf("%s run recorded %d rounds, want %d", name, len(report.Hyperparameters), rounds+1)
+		}
+		for i, rec := range report.Hyperparameters {
+			if rec.Round != i+1 || rec.Observation.Round != i {
+				t.Fatalf("%s recommendation %d is for round %d after %d", name, i, rec.Round, rec.Observation.Round)
+			}
+		}
+	}
+	for _, rec := range static.Hyperparameters {
+		if rec.Hyperparameters != (convergence.Hyperparameters{LearningRate: 0.3, Epochs: 3}) {
+			t.Fatalf("static run trained round %d with %+v", rec.Round, rec.Hyperparameters)
+		}
+	}
+	if first := advised.Hyperparameters[0].Hyperparameters; first.LearningRate != 0.15 || first.Epochs != 2 {
+		t.Fatalf("first advised round trained with %+v, want one bounded step down", first)
+	}
+}
@@ -0,0 +1,99 @@
+package scenarios
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func TestIslandRejoinCountsEachNodeOncePerRound(t *testing.T) {
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "aggregator", Timeout: time.Second}))
+	traffic, err := registry.Create("traffic")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	tracker, err := provenance.NewTracker(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+
+	const (
+		nodes       = 6
+		rounds      = 3
+		rejoinRound = 2
+		rejoining   = 3
+	)
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:     nodes,
+		Rounds:        rounds,
+		RoundDuration: time.Millisecond,
+		RandomSeed:    723,
+		Training:      &simulator.QuadraticModel{Dim: 8, LearningRate: 0.1},
+		Federations:   registry,
+		FederationID:  "traffic",
+		Provenance:    tracker,
+		IslandRejoin:  &simulator.IslandRejoin{Round: rejoinRound, Nodes: rejoining},
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.RoundsCompleted != rounds {
+		t.Fatalf("completed %d rounds, want %d", result.RoundsCompleted, rounds)
+	}
+	report := result.IslandRejoin
+	if report == nil || report.Synced != rejoining || report.LiveSubmitted != rejoining {
+		t.Fatalf("expected %d synced and %d live updates, got %+v", rejoining, rejoining, report)
+	}
+	if report.Contributions != rejoining || report.DuplicateContributions != 0 {
+		t.Fatalf("expected one contribution per rejoining node, got %+v", report)
+	}
+
+	for round := 1; round <= rounds; round++ {
+		manifest, ok := traffic.ModelStore.Manifest(round)
+		if !ok {
+			t.Fatalf("round %d has no manifest", round)
+		}
+		contributions := make(map[string]int)
+		for _, entry := range manifest.Entries {
+			contributions[entry.NodeID]++
+		}
+		if len(contributions) != nodes {
+			t.Fatalf("round %d: %d nodes contributed, want %d", round, len(contributions), nodes)
+		}
+		for nodeID, count := range contributions {
+			if count != 1 {
+				t.Fatalf("round %d: %s contributed %d times", round, nodeID, count)
+			}
+		}
+	}
+
+	events, err := tracker.RoundEvents(rejoinRound)
+	if err != nil {
+		t.Fatalf("round %d events: %v", rejoinRound, err)
+	}
+	superseded := make(map[string]int)
+	for _, event := range events {
+		switch event.Kind {
+		case provenance.KindSuperseded:
+			if event.Reason != reasons.Superseded {
+				t.Fatalf("superseded event carries reason %q", event.Reason)
+			}
+			superseded[event.NodeID]++
+		case provenance.KindExcluded:
+			t.Fatalf("round %d: %s was excluded: %+v", rejoinRound, event.NodeID, event)
+		}
+	}
+	if len(superseded) != rejoining {
+		t.Fatalf("expected a superseded copy for each of %d rejoining nodes, got %v", rejoining, superseded)
+	}
+	for nodeID, count := range superseded {
+		if count != 1 {
+			t.Fatalf("%s has %d superseded copies, want 1", nodeID, count)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package scenarios
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func TestSpotChecksIdentifyLazyVerifiers(t *testing.T) {
+	const (
+		rounds    = 20
+		committee = 6
+		lazy      = 2
+		// With a decoy in a quarter of batches, a lazy verifier is caught
+		// within catchRounds with probability 1-0.75^10, about 94%; the
+		// seed fixes the outcome.
+		spotCheckRate = 0.25
+		catchRounds   = 10
+	)
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:             8,
+		Rounds:                rounds,
+		RoundDuration:         time.Millisecond,
+		RandomSeed:            679,
+		Training:              &simulator.QuadraticModel{Dim: 16, LearningRate: 0.1, ByzantineNodes: 1},
+		VerificationCommittee: committee,
+		LazyVerifiers:         lazy,
+		SpotCheckRate:         spotCheckRate,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.RoundsCompleted != rounds {
+		t.Fatalf("completed %d rounds, want %d", result.RoundsCompleted, rounds)
+	}
+	report := result.Verification
+	if report == nil || report.Decoys == 0 {
+		t.Fatalf("no spot checks ran: %+v", report)
+	}
+
+	for _, id := range []string{"verifier-00", "verifier-01"} {
+		round, flagged := report.Flagged[id]
+		if !flagged {
+			t.Fatalf("lazy %s was never flagged: %+v", id, report)
+		}
+		if round > catchRounds {
+			t.Fatalf("lazy %s flagged only in round %d", id, round)
+		}
+		if report.Reputation[id] > 0.5 {
+			t.Fatalf("lazy %s kept reputation %.2f", id, report.Reputation[id])
+		}
+	}
+	for _, id := range []string{"verifier-02", "verifier-03", "verifier-04", "verifier-05"} {
+		if round, flagged := report.Flagged[id]; flagged {
+			t.Fatalf("honest %s flagged in round %d", id, round)
+		}
+		if report.Reputation[id] < 1 {
+			t.Fatalf("honest %s lost reputation: %.2f", id, report.Reputation[id])
+		}
+	}
+	// Decoys never reach aggregation: training converges as without them.
+	if result.Training.FinalLoss >= result.Training.InitialLoss {
+		t.Fatalf("training did not converge: %+v", result.Training)
+	}
+}
@@ -0,0 +1,77 @@
+package scenarios
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func runWithModelSize(modelBytes int64) simulator.Result {
+	return simulator.Run(simulator.Config{
+		NodeCount:     20,
+		Rounds:        5,
+		RoundDuration: 200 * time.Millisecond,
+		RandomSeed:    644,
+		Network: &simulator.NetworkModel{
+			UplinkBytesPerSec:   1_000_000,
+			DownlinkBytesPerSec: 1_000_000,
+			JitterMs:            5,
+			ModelBytes:          modelBytes,
+		},
+	})
+}
+
+func TestDoublingModelSizeDoublesTransferTimeUnderBandwidthCap(t *testing.T) {
+	small := runWithModelSize(1_000_000)
+	large := runWithModelSize(2_000_000)
+	if small.Network == nil || large.Network == nil {
+		t.Fatal("expected a network report")
+	}
+
+	for _, phase := range []string{simulator.PhaseDistribution, simulator.PhaseUpload} {
+		ratio := large.Network.Phase(phase).Network.Seconds() / small.Network.Phase(phase).Network.Seconds()
+		if ratio < 1.8 || ratio > 2.2 {
+			t.Fatalf("expected %s network time to roughly double, got ratio %.2f", phase, ratio)
+		}
+	}
+	if training := large.Network.Phase(simulator.PhaseTraining); training.Compute != 5*200*time.Millisecond || training.Network != 0 {
+		t.Fatalf("expected training to be pure compute, got %+v", training)
+	}
+	if large.AverageRoundDuration <= small.AverageRoundDuration {
+		t.Fatalf("expected larger models to slow rounds, got %s vs %s", large.AverageRoundDuration, small.AverageRoundDuration)
+	}
+}
+
+func TestNetworkModelIsReproducibleAndHeavyTailed(t *testing.T) {
+	model, err := simulator.LoadNetworkModel("../simulator/plans/wan-3-region-network.json")
+	if err != nil {
+		t.Fatalf("load network model: %v", err)
+	}
+	cfg := simulator.Config{NodeCount: 60, Rounds: 10, RoundDuration: 250 * time.Millisecond, RandomSeed: 7, Network: model}
+
+	first := simulator.Run(cfg)
+	second := simulator.Run(cfg)
+	if simulator.FormatSummary(first) != simulator.FormatSummary(second) {
+		t.Fatalf("expected reproducible runs:\n%s\n%s", simulator.FormatSummary(first), simulator.FormatSummary(second))
+	}
+	if first.Network.Seed != 644 {
+		t.Fatalf("expected the model seed to be used, got %d", first.Network.Seed)
+	}
+
+	// Updates cross the 1MB/s uplink while the model comes down at 10MB/s.
+	if first.Network.Phase(simulator.PhaseUpload).Network <= first.Network.Phase(simulator.PhaseDistribution).Network {
+		t.Fatalf("expected asymmetric bandwidth to make uploads slower, got %+v", first.Network.Phases)
+	}
+	networkTime, computeTime := first.Network.Totals()
+	// AverageRoundDuration truncates to whole nanoseconds.
+	got := first.AverageRoundDuration * time.Duration(first.RoundsCompleted)
+	if diff := networkTime + computeTime - got; diff < 0 || diff >= time.Duration(first.RoundsCompleted) {
+		t.Fatalf("expected round time to be network plus compute, got %s vs %s+%s", got, networkTime, computeTime)
+	}
+
+	flat := simulator.Run(simulator.Config{NodeCount: 60, Rounds: 10, RoundDuration: 250 * time.Millisecond, RandomSeed: 7})
+	if first.StragglerEvents != flat.StragglerEvents || first.MaliciousNodeEvents != flat.MaliciousNodeEvents {
+		t.Fatal("expected the network model not to perturb other simulated events")
+	}
+}
@@ -0,0 +1,53 @@
+package scenarios
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/rollback"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func TestPoisonedRoundIsRolledBackWithinWindow(t *testing.T) {
+	policy := rollback.DefaultPolicy()
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:         12,
+		Rounds:            20,
+		RoundDuration:     10 * time.Millisecond,
+		MaliciousNodeRate: 0.34,
+		RandomSeed:        640,
+		PoisonRound:       10,
+		AutoRollback:      &policy,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if result.Rollbacks != 1 {
+		t.Fatalf("expected exactly one rollback, got %d", result.Rollbacks)
+	}
+	if result.RecoveredRound < result.PoisonedRound || result.RecoveredRound > result.PoisonedRound+policy.Window {
+		t.Fatalf("expected recovery within rounds %d..%d, got %d", result.PoisonedRound, result.PoisonedRound+policy.Window, result.RecoveredRound)
+	}
+	if result.RoundsCompleted != 20 {
+		t.Fatalf("expected training to continue after the rollback, got %d rounds", result.RoundsCompleted)
+	}
+}
+
+func TestPoisonedRoundWithoutAutoRollbackIsNotRecovered(t *testing.T) {
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:         12,
+		Rounds:            15,
+		RoundDuration:     10 * time.Millisecond,
+		MaliciousNodeRate: 0.34,
+		RandomSeed:        640,
+		PoisonRound:       10,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.Rollbacks != 0 || result.RecoveredRound != 0 {
+		t.Fatalf("expected no rollback without a policy, got %d at round %d", result.Rollbacks, result.RecoveredRound)
+	}
+}
@@ -0,0 +1,96 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func TestUpdateProvenanceFromIngestToCommit(t *testing.T) {
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "aggregator", Timeout: time.Second}))
+	if _, err := registry.Create("traffic"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	tracker, err := provenance.NewTracker(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+
+	const rounds = 3
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:             6,
+		Rounds:                rounds,
+		RoundDuration:         time.Millisecond,
+		RandomSeed:            661,
+		Training:              &simulator.QuadraticModel{Dim: 20, LearningRate: 0.1, ByzantineNodes: 1},
+		Federations:           registry,
+		FederationID:          "traffic",
+		Provenance:            tracker,
+		VerificationCommittee: 3,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.RoundsCompleted != rounds {
+		t.Fatalf("completed %d rounds, want %d", result.RoundsCompleted, rounds)
+	}
+	if dropped, last := tracker.Dropped(); dropped != 0 {
+		t.Fatalf("tracker dropped %d events: %v", dropped, last)
+	}
+
+	for round := 1; round <= rounds; round++ {
+		events, err := tracker.RoundEvents(round)
+		if err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+		updates := make(map[string]string)
+		for _, event := range events {
+			if previous, ok := updates[event.NodeID]; ok && previous != event.UpdateID {
+				t.Fatalf("round %d: %s has events under %s and %s", round, event.NodeID, previous, event.UpdateID)
+			}
+			updates[event.NodeID] = event.UpdateID
+		}
+		if len(updates) != 6 {
+			t.Fatalf("round %d traced %d updates, want 6", round, len(updates))
+		}
+
+		honest, err := tracker.Chain(updates["traffic-node-001"])
+		if err != nil {
+			t.Fatalf("round %d honest chain: %v", round, err)
+		}
+		assertChain(t, honest, "received", "signature_verified", "proof_verified", "included", "aggregated", "committed")
+		if committee := strings.Join(honest[2].Committee, ","); committee != "verifier-00,verifier-01,verifier-02" {
+			t.Fatalf("round %d committee = %s", round, committee)
+		}
+		if honest[4].ProposalID == "" || honest[5].ProposalID != honest[4].ProposalID || honest[5].CommitRound != round {
+			t.Fatalf("round %d commit does not follow its proposal: %+v", round, honest[4:])
+		}
+
+		rejected, err := tracker.Chain(updates["traffic-node-000"])
+		if err != nil {
+			t.Fatalf("round %d rejected chain: %v", round, err)
+		}
+		assertChain(t, rejected, "received", "signature_verified", "proof_verified", "excluded")
+		if rejected[3].Reason != protocol.ReasonNormOutlier {
+			t.Fatalf("round %d rejected for %q, want %q", round, rejected[3].Reason, protocol.ReasonNormOutlier)
+		}
+	}
+}
+
+func assertChain(t *testing.T, events []provenance.Event, kinds ...string) {
+	t.Helper()
+	got := make([]string, len(events))
+	for i, event := range events {
+		got[i] = string(event.Kind)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(kinds) {
+		t.Fatalf("chain = %v, want %v", got, kinds)
+	}
+}
@@ -0,0 +1,127 @@
+package scenarios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func assertRegistered(t *testing.T, where string, code reasons.Code) {
+	t.Helper()
+	if code == "" || !code.Registered() {
+		t.Fatalf("%s emitted unregistered reason code %q", where, code)
+	}
+}
+
+func TestSimulatorRejectionsCarryRegisteredCodes(t *testing.T) {
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "aggregator", Timeout: time.Second}))
+	f, err := registry.Create("traffic")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	tracker, err := provenance.NewTracker(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("tracker: %v", err)
+	}
+	const rounds = 8
+	result, err := simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:             6,
+		Rounds:                rounds,
+		RoundDuration:         time.Millisecond,
+		RandomSeed:            704,
+		Training:              &simulator.QuadraticModel{Dim: 20, LearningRate: 0.1, ByzantineNodes: 1},
+		Federations:           registry,
+		FederationID:          "traffic",
+		Provenance:            tracker,
+		VerificationCommittee: 4,
+		LazyVerifiers:         1,
+		SpotCheckRate:         0.5,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	// Honest verifiers reject every decoy they are sent.
+	if len(result.Verification.Rejections) == 0 {
+		t.Fatalf("no verifier rejected anything: %+v", result.Verification)
+	}
+	for code := range result.Verification.Rejections {
+		assertRegistered(t, "verifier", code)
+	}
+
+	exclusions := 0
+	for round := 1; round <= rounds; round++ {
+		manifest, ok := f.ModelStore.Manifest(round)
+		if !ok {
+			t.Fatalf("round %d has no manifest", round)
+		}
+		for _, entry := range manifest.Entries {
+			assertRegistered(t, "manifest", entry.Reason)
+			if !entry.Included {
+				exclusions++
+			}
+		}
+		traced, err := tracker.RoundEvents(round)
+		if err != nil {
+			t.Fatalf("round %d events: %v", round, err)
+		}
+		for _, event := range traced {
+			if event.Kind == provenance.KindIncluded || event.Kind == provenance.KindExcluded {
+				assertRegistered(t, "provenance", event.Reason)
+			}
+		}
+	}
+	if exclusions == 0 {
+		t.Fatal("the Byzantine node was never excluded")
+	}
+	for _, rejection := range f.Coordinator.VoteRejections() {
+		assertRegistered(t, "vote chain "+rejection.Middleware, rejection.Reason)
+	}
+}
+
+func TestUnknownReasonCodesRejectedAtDecode(t *testing.T) {
+	for name, decode := range map[string]func() error{
+		"verification response": func() error {
+			var resp p2p.ModelVerificationResponse
+			return json.Unmarshal([]byte(`{"RequestID":"r","Valid":false,"ReasonCode":"looked_wrong"}`), &resp)
+		},
+		"audit verification": func() error {
+			var v protocol.AuditVerification
+			return json.Unmarshal([]byte(`{"request_id":"r","reason_code":"looked_wrong"}`), &v)
+		},
+		"manifest": func() error {
+			var manifest protocol.ContributionManifest
+			return json.Unmarshal([]byte(`{"round":1,"entries":[{"node_id":"n","reason":"excluded: norm outlier"}]}`), &manifest)
+		},
+		"provenance event": func() error {
+			var event provenance.Event
+			return json.Unmarshal([]byte(`{"update_id":"u","kind":"excluded","reason":"looked_wrong"}`), &event)
+		},
+		"lifecycle event": func() error {
+			var event events.Event
+			return json.Unmarshal([]byte(`{"kind":"aborted","reason":"looked_wrong"}`), &event)
+		},
+	} {
+		if err := decode(); !errors.Is(err, reasons.ErrUnknownCode) {
+			t.Fatalf("%s: decode error %v, want ErrUnknownCode", name, err)
+		}
+	}
+
+	var manifest protocol.ContributionManifest
+	if err := json.Unmarshal([]byte(`{"round":1,"entries":[{"node_id":"n","reason":"norm_outlier"}]}`), &manifest); err != nil {
+		t.Fatalf("registered code refused: %v", err)
+	}
+	if manifest.Entries[0].Reason != protocol.ReasonNormOutlier {
+		t.Fatalf("reason = %q", manifest.Entries[0].Reason)
+	}
+}
@@ -0,0 +1,119 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+// readInvalid loads one corrupted fixture into a 10-node scenario, as the
+// part its name starts with.
+func readInvalid(t *testing.T, fixture string) simulator.Config {
+	t.Helper()
+	var training, chaosPlan, network string
+	path := filepath.Join("testdata", "invalid", fixture)
+	switch {
+	case strings.HasPrefix(fixture, "training-"):
+		training = path
+	case strings.HasPrefix(fixture, "chaos-"):
+		chaosPlan = path
+	case strings.HasPrefix(fixture, "network-"):
+		network = path
+	default:
+		t.Fatalf("fixture %s names no scenario part", fixture)
+	}
+	cfg, err := simulator.ReadScenario(simulator.Config{NodeCount: 10, Rounds: 5, RoundDuration: time.Millisecond, RandomSeed: 718}, training, chaosPlan, network)
+	if err != nil {
+		t.Fatalf("read %s: %v", fixture, err)
+	}
+	return cfg
+}
+
+func requireViolation(t *testing.T, name string, err error, path, rule string) {
+	t.Helper()
+	var scenarioErr *simulator.ScenarioError
+	if !errors.As(err, &scenarioErr) || !errors.Is(err, simulator.ErrInvalidScenario) {
+		t.Fatalf("%s: expected a ScenarioError, got %v", name, err)
+	}
+	for _, violation := range scenarioErr.Violations {
+		if violation.Path == path && violation.Rule == rule {
+			return
+		}
+	}
+	t.Fatalf("%s: no %s violation at %s in %+v", name, rule, path, scenarioErr.Violations)
+}
+
+func TestCorruptedScenariosAreRefusedWithTheirPath(t *testing.T) {
+	for fixture, want := range map[string]simulator.Violation{
+		"training-duplicate-attack-node.json":   {Path: "Training.attacks[1].nodes[0]", Rule: simulator.RuleUniqueIDs},
+		"training-attacker-is-byzantine.json":   {Path: "Training.attacks[0].nodes[0]", Rule: simulator.RuleByzantineRatio},
+		"training-attacker-out-of-range.json":   {Path: "Training.attacks[0].nodes[1]", Rule: simulator.RuleByzantineRatio},
+		"training-no-honest-nodes.json":         {Path: "Training", Rule: simulator.RuleByzantineRatio},
+		"training-unknown-strategy.json":        {Path: "Training.attacks[0].strategy", Rule: simulator.RuleAttackRegistry},
+		"training-unknown-param.json":           {Path: "Training.attacks[0].params.amplitude", Rule: simulator.RuleAttackRegistry},
+		"training-no-dimension.json":            {Path: "Training.dim", Rule: simulator.RuleShape},
+		"chaos-unknown-peer.json":               {Path: "Chaos.partitions[0].groups[1][1]", Rule: simulator.RuleMembership},
+		"chaos-duplicate-partition-member.json": {Path: "Chaos.partitions[0].groups[1][1]", Rule: simulator.RuleUniqueIDs},
+		"chaos-churn-join-present.json":         {Path: "Chaos.churn[0].join[0]", Rule: simulator.RuleMembership},
+		"chaos-churn-empties-run.json":          {Path: "Chaos.churn[1]", Rule: simulator.RuleMembership},
+		"network-duplicate-region.json":         {Path: "Network.regions[2]", Rule: simulator.RuleUniqueIDs},
+		"network-link-unknown-region.json":      {Path: "Network.links[0].to", Rule: simulator.RuleMembership},
+	} {
+		cfg := readInvalid(t, fixture)
+		requireViolation(t, fixture, simulator.Validate(cfg), want.Path, want.Rule)
+
+		// The simulator refuses it before running a round.
+		result, err := simulator.RunContext(context.Background(), cfg)
+		requireViolation(t, fixture+" run", err, want.Path, want.Rule)
+		if result.RoundsCompleted != 0 {
+			t.Fatalf("%s: ran %d rounds of an invalid scenario", fixture, result.RoundsCompleted)
+		}
+	}
+}
+
+func TestScenarioSettingsNeedTheirParts(t *testing.T) {
+	training := readInvalid(t, "training-attacker-out-of-range.json").Training
+	training.Attacks = nil
+	for name, tc := range map[string]struct {
+		cfg        simulator.Config
+		path, rule string
+	}{
+		"committee without training":    {simulator.Config{VerificationCommittee: 5}, "VerificationCommittee", simulator.RuleRequiredFields},
+		"more lazy than verifiers":      {simulator.Config{Training: training, VerificationCommittee: 3, LazyVerifiers: 4}, "LazyVerifiers", simulator.RuleRequiredFields},
+		"spot checks without committee": {simulator.Config{Training: training, SpotCheckRate: 0.2}, "SpotCheckRate", simulator.RuleRequiredFields},
+		"sybils without federations":    {simulator.Config{Training: training, SybilWave: &simulator.SybilWave{}}, "SybilWave", simulator.RuleRequiredFields},
+		"tiers without regions":         {simulator.Config{TieredRounds: &simulator.TieredRounds{}}, "TieredRounds", simulator.RuleRequiredFields},
+		"accuracy per missing node": {
+			simulator.Config{NodeCount: 2, Training: training, Evaluation: &simulator.EvaluationConfig{NodeAccuracy: []float64{0.9, 0.8, 0.7}}},
+			"Evaluation.node_accuracy", simulator.RuleShape,
+		},
+	} {
+		requireViolation(t, name, simulator.Validate(tc.cfg), tc.path, tc.rule)
+	}
+}
+
+func TestShippedScenariosValidate(t *testing.T) {
+	plans := filepath.Join("..", "simulator", "plans")
+	cfg, err := simulator.Preset("byzantine-55")
+	if err != nil {
+		t.Fatalf("preset: %v", err)
+	}
+	if cfg, err = simulator.ReadScenario(cfg, "", filepath.Join(plans, "byzantine-55-chaos.json"), filepath.Join(plans, "wan-3-region-network.json")); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if err := simulator.Validate(cfg); err != nil {
+		t.Fatalf("byzantine-55 with chaos and WAN: %v", err)
+	}
+	cfg, err = simulator.ReadScenario(simulator.Config{NodeCount: 40}, filepath.Join(plans, "adaptive-attacks-training.json"), "", "")
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if err := simulator.Validate(cfg); err != nil {
+		t.Fatalf("adaptive attacks: %v", err)
+	}
+}
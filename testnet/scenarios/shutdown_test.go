@@ -0,0 +1,98 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+type blockingSyncer struct{}
+
+func (blockingSyncer) SyncUpdates(updates []island.Update) error {
+	return nil
+}
+
+func (blockingSyncer) SyncUpdatesContext(ctx context.Context, updates []island.Update) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestShutdownWithRoundsInFlightLeaksNoGoroutines(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mgr := island.NewManager(time.Millisecond, 8, func() bool { return true })
+	mgr.SetSyncer(blockingSyncer{})
+	if err := mgr.CacheUpdate(island.Update{Round: 1, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("cache update: %v", err)
+	}
+	mgr.Start()
+
+	verification := p2p.NewNetwork("node-0", 2, time.Second).GetVerificationProtocol()
+	for i := 1; i <= 8; i++ {
+		_ = verification.RegisterPeer(fmt.Sprintf("peer-%d", i))
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			nodeID := fmt.Sprintf("agg-%d", worker)
+			agg := consensus.NewDistributedAggregator(nodeID, []string{"peer-a", "peer-b"}, time.Second)
+			weights := make([]byte, 1<<20)
+			for ctx.Err() == nil {
+				_ = agg.SubmitModel(ctx, nodeID, weights)
+				_, _ = agg.AggregateWithConsensus(ctx)
+				_, _ = verification.RequestVerification(ctx, weights[:64], []byte("sig"))
+			}
+		}(i)
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := mgr.ForceSync(ctx); !errors.Is(err, context.Canceled) {
+			errs <- fmt.Errorf("expected cancelled force sync, got %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		_, err := simulator.RunContext(ctx, simulator.Config{NodeCount: 64, Rounds: 1 << 30, RandomSeed: 7})
+		if !errors.Is(err, context.Canceled) {
+			errs <- fmt.Errorf("expected cancelled simulation, got %v", err)
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	mgr.Stop()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	if cached, _ := mgr.GetCachedUpdateStats(); cached != 1 {
+		t.Fatalf("expected aborted sync to keep 1 cached update, got %d", cached)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > baseline {
+		buf := make([]byte, 1<<16)
+		t.Fatalf("goroutines leaked: baseline %d, now %d\n%s", baseline, n, buf[:runtime.Stack(buf, true)])
+	}
+}
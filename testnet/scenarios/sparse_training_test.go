@@ -0,0 +1,42 @@
+package scenarios
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func TestSparseTrainingTracksDenseConvergence(t *testing.T) {
+	run := func(sparsity float64) *simulator.TrainingReport {
+		t.Helper()
+		result, err := simulator.RunContext(context.Background(), simulator.Config{
+			NodeCount:     10,
+			Rounds:        500,
+			RoundDuration: time.Millisecond,
+			RandomSeed:    648,
+			Training:      &simulator.QuadraticModel{Dim: 1000, LearningRate: 0.01, Sparsity: sparsity},
+		})
+		if err != nil {
+			t.Fatalf("run with sparsity %f: %v", sparsity, err)
+		}
+		return result.Training
+	}
+
+	dense := run(0)
+	sparse := run(0.01)
+
+	// Excess loss is measured against the sample-weighted optimum, so a
+	// sparse update weighted any other way would not converge to it.
+	tolerance := 1e-4 * dense.InitialLoss
+	if dense.FinalLoss > tolerance {
+		t.Fatalf("dense training did not converge: loss %g of %g", dense.FinalLoss, dense.InitialLoss)
+	}
+	if sparse.FinalLoss > dense.FinalLoss+tolerance {
+		t.Fatalf("1%% sparse training loss %g is not within %g of dense %g", sparse.FinalLoss, tolerance, dense.FinalLoss)
+	}
+	if ratio := float64(dense.UploadBytes) / float64(sparse.UploadBytes); ratio < 50 {
+		t.Fatalf("expected 1%% sparsity to cut upload bytes at least 50x, got %.1fx", ratio)
+	}
+}
@@ -0,0 +1,61 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+func runSybilWave(t *testing.T, probation *consensus.ProbationConfig) (simulator.Result, error) {
+	t.Helper()
+	registry := federation.NewRegistry(federation.NewFactory(federation.Config{HostID: "aggregator", Timeout: time.Second, Probation: probation}))
+	if _, err := registry.Create("traffic"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	return simulator.RunContext(context.Background(), simulator.Config{
+		NodeCount:     10,
+		Rounds:        8,
+		RoundDuration: time.Millisecond,
+		RandomSeed:    691,
+		Training:      &simulator.QuadraticModel{Dim: 8, LearningRate: 0.3},
+		Federations:   registry,
+		FederationID:  "traffic",
+		// More sybils than honest members: counted as members, they would
+		// push the quorum out of the honest nodes' reach.
+		SybilWave: &simulator.SybilWave{Round: 3, Nodes: 12},
+	})
+}
+
+func TestSybilWaveOnProbationCannotFlipRounds(t *testing.T) {
+	probation := consensus.DefaultProbationConfig()
+	result, err := runSybilWave(t, &probation)
+	if err != nil {
+		t.Fatalf("sybils on probation stalled the run: %v", err)
+	}
+	if result.RoundsCompleted != 8 {
+		t.Fatalf("completed %d of 8 rounds", result.RoundsCompleted)
+	}
+	sybils := result.Sybils
+	if sybils == nil || sybils.RoundsCommitted != 6 || sybils.RejectVotes != 72 || sybils.OnProbation != 12 {
+		t.Fatalf("sybil report = %+v", sybils)
+	}
+	if result.Training.FinalLoss > result.Training.InitialLoss/10 {
+		t.Fatalf("training did not progress: %+v", result.Training)
+	}
+}
+
+func TestSybilWaveWithoutProbationBlocksQuorum(t *testing.T) {
+	result, err := runSybilWave(t, nil)
+	var quorum *consensus.ErrQuorumNotReached
+	if !errors.As(err, &quorum) {
+		t.Fatalf("expected the sybils to keep round 3 from quorum, got %v", err)
+	}
+	if result.RoundsCompleted != 2 || result.Sybils == nil || result.Sybils.RoundsCommitted != 0 {
+		t.Fatalf("result = %+v, sybils %+v", result, result.Sybils)
+	}
+}
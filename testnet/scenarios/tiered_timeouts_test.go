@@ -0,0 +1,91 @@
+package scenarios
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
+)
+
+// threeRegionNetwork has tight links inside each region, at different
+// latencies, and slow, heavy-tailed links between them.
+func threeRegionNetwork() *simulator.NetworkModel {
+	link := func(from, to string, ms, sigma float64) simulator.LinkModel {
+		return simulator.LinkModel{From: from, To: to, Latency: simulator.LatencyDistribution{Mu: math.Log(ms), Sigma: sigma}}
+	}
+	return &simulator.NetworkModel{
+		Seed:             715,
+		Regions:          []string{"us-east", "eu-west", "ap-south"},
+		AggregatorRegion: "us-east",
+		Links: []simulator.LinkModel{
+			link("us-east", "us-east", 5, 0.2),
+			link("eu-west", "eu-west", 10, 0.2),
+			link("ap-south", "ap-south", 20, 0.3),
+			link("us-east", "eu-west", 80, 0.3),
+			link("us-east", "ap-south", 150, 0.4),
+			link("eu-west", "ap-south", 120, 0.4),
+		},
+		JitterMs: 2,
+	}
+}
+
+func runTieredRounds(tiers simulator.TieredRounds) simulator.Result {
+	return simulator.Run(simulator.Config{
+		NodeCount:     30,
+		Rounds:        40,
+		RoundDuration: 100 * time.Millisecond,
+		RandomSeed:    715,
+		Network:       threeRegionNetwork(),
+		TieredRounds:  &tiers,
+	})
+}
+
+func TestTopologyDerivedTimeoutsBeatAFlatTimeout(t *testing.T) {
+	// One node in each region of ten never answers, so regional rounds run
+	// to their timeout.
+	flat := runTieredRounds(simulator.TieredRounds{FlatTimeout: time.Second, Unresponsive: 0.15})
+	derived := runTieredRounds(simulator.TieredRounds{
+		Policy: scheduler.LatencyPolicy{
+			Regional: scheduler.TierTimeout{RoundTrips: 8, Floor: 50 * time.Millisecond, Ceiling: 2 * time.Second},
+			Global:   scheduler.TierTimeout{RoundTrips: 8, Floor: 500 * time.Millisecond, Ceiling: 10 * time.Second},
+			Samples:  256,
+		},
+		Unresponsive: 0.15,
+	})
+	if flat.TieredRounds == nil || derived.TieredRounds == nil {
+		t.Fatal("expected tiered round reports")
+	}
+	t.Logf("flat: %s", simulator.FormatSummary(flat))
+	t.Logf("derived: %s", simulator.FormatSummary(derived))
+
+	// Tight regional deadlines stop waiting on silent nodes sooner.
+	if got, flatMean := derived.TieredRounds.Regional.Mean(), flat.TieredRounds.Regional.Mean(); got*2 > flatMean {
+		t.Fatalf("expected derived regional rounds to take under half the flat ones, got %s vs %s", got, flatMean)
+	}
+	if derived.TieredRounds.Regional.TimedOut != 0 {
+		t.Fatalf("expected every regional round to reach quorum, %d timed out", derived.TieredRounds.Regional.TimedOut)
+	}
+
+	// The flat timeout is too tight for three exchanges across the ocean.
+	if flat.TieredRounds.Global.TimedOut == 0 {
+		t.Fatal("expected the flat timeout to time out global rounds")
+	}
+	if derived.TieredRounds.Global.TimedOut != 0 {
+		t.Fatalf("expected derived global budgets to stop spurious timeouts, %d timed out", derived.TieredRounds.Global.TimedOut)
+	}
+	if derived.FailedRounds >= flat.FailedRounds || derived.RoundsCompleted != 40 {
+		t.Fatalf("expected fewer failed rounds, got %d vs %d (%d completed)", derived.FailedRounds, flat.FailedRounds, derived.RoundsCompleted)
+	}
+
+	// Budgets follow the links: the slowest region gets the most slack,
+	// global rounds more than any region.
+	budgets := map[string]time.Duration{}
+	for _, budget := range derived.TieredRounds.Budgets {
+		budgets[string(budget.Tier)+"/"+budget.Region] = budget.Budget
+	}
+	if !(budgets["regional/us-east"] < budgets["regional/ap-south"] && budgets["regional/ap-south"] < budgets["global/"]) {
+		t.Fatalf("budgets = %v", budgets)
+	}
+}
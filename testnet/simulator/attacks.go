@@ -0,0 +1,452 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/simrand"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// Attack strategy names, as assigned in scenario files and reported in
+// TrainingReport.Attacks. The first six upload a fixed pattern; the rest
+// adapt to the global model each round.
+const (
+	AttackGradientPoisoning = "gradient_poisoning"
+	AttackLabelFlipping     = "label_flipping"
+	AttackSignFlipping      = "sign_flipping"
+	AttackGaussianNoise     = "gaussian_noise"
+	AttackFreeRider         = "free_rider"
+	AttackSybil             = "sybil_attack"
+	AttackModelReplacement  = "model_replacement"
+	AttackStealth           = "stealth_poisoning"
+	AttackColludingSybil    = "colluding_sybil"
+)
+
+// ModelUpdate is the step an attacking node uploads in one round.
+type ModelUpdate struct {
+	Step []float64
+	// Poisoned is false in rounds a strategy trains honestly, so they do
+	// not count towards its success rate.
+	Poisoned bool
+}
+
+// AttackStrategy computes a Byzantine node's upload. Unlike the patterns
+// baked into generated fixtures, a strategy sees the global model each
+// round trains from and may react to it.
+type AttackStrategy interface {
+	NextUpdate(round int, globalWeights []float64) ModelUpdate
+}
+
+// AttackAssignment gives the nodes it lists, by index, one strategy.
+// Params override the strategy's defaults; see attackParams.
+type AttackAssignment struct {
+	Strategy string             `json:"strategy"`
+	Nodes    []int              `json:"nodes"`
+	Params   map[string]float64 `json:"params,omitempty"`
+}
+
+// AttackNode is what a strategy knows of the node it runs on: the local
+// target and learning rate its honest step would use, and a seeded stream.
+type AttackNode struct {
+	Index        int
+	Target       []float64
+	LearningRate float64
+	Rand         *simrand.SeededRand
+}
+
+// HonestStep is the step the node would upload if it were honest.
+func (n AttackNode) HonestStep(globalWeights []float64) []float64 {
+	step := make([]float64, len(globalWeights))
+	for j, w := range globalWeights {
+		step[j] = -n.LearningRate * (w - n.Target[j])
+	}
+	return step
+}
+
+// attackParams lists each strategy's parameters and their defaults; a
+// parameter not listed is refused.
+var attackParams = map[string]map[string]float64{
+	// scale multiplies the honest step.
+	AttackGradientPoisoning: {"scale": byzantineScale},
+	// The node trains towards the mirror image of its target.
+	AttackLabelFlipping: {},
+	// scale multiplies the negated honest step.
+	AttackSignFlipping: {"scale": 1},
+	// sigma is the noise's standard deviation per coordinate.
+	AttackGaussianNoise: {"sigma": 1},
+	AttackFreeRider:     {},
+	// Every node of the assignment uploads value in every coordinate.
+	AttackSybil: {"value": 1},
+	// boost scales the difference between the attacker's goal, the mirror
+	// image of its target, and the global model, so that the goal survives
+	// averaging with honest updates.
+	AttackModelReplacement: {"boost": 10},
+	// Poisons in rounds where round % period == phase, with the honest
+	// step reversed and rescaled to norm_ratio times the honest norm, and
+	// trains honestly otherwise.
+	AttackStealth: {"period": 2, "phase": 0, "norm_ratio": 1.5},
+	// The assignment's nodes agree each round on one direction, reversing
+	// the global model's last move, and each uploads it at norm_ratio times
+	// the group's mean honest norm, perturbed by jitter of that norm.
+	AttackColludingSybil: {"norm_ratio": 2, "jitter": 0.01},
+}
+
+// Validate checks the strategy name and parameters and that no node is
+// listed twice.
+func (a *AttackAssignment) Validate() error {
+	defaults, known := attackParams[a.Strategy]
+	if !known {
+		return fmt.Errorf("unknown attack strategy %q", a.Strategy)
+	}
+	if len(a.Nodes) == 0 {
+		return fmt.Errorf("attack %s assigns no nodes", a.Strategy)
+	}
+	for name, value := range a.Params {
+		if _, ok := defaults[name]; !ok {
+			return fmt.Errorf("attack %s has no parameter %q", a.Strategy, name)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("attack %s parameter %s must be finite", a.Strategy, name)
+		}
+	}
+	if a.Strategy == AttackStealth && a.param("period") < 1 {
+		return fmt.Errorf("attack %s period must be at least 1", a.Strategy)
+	}
+	seen := make(map[int]bool, len(a.Nodes))
+	for _, n := range a.Nodes {
+		if n < 0 || seen[n] {
+			return fmt.Errorf("attack %s lists node %d invalidly", a.Strategy, n)
+		}
+		seen[n] = true
+	}
+	return nil
+}
+
+func (a *AttackAssignment) param(name string) float64 {
+	if value, ok := a.Params[name]; ok {
+		return value
+	}
+	return attackParams[a.Strategy][name]
+}
+
+// newAttackStrategies builds one strategy per node of the assignment.
+// Colluding nodes of one assignment share their state.
+func newAttackStrategies(a AttackAssignment, nodes []AttackNode) []AttackStrategy {
+	strategies := make([]AttackStrategy, len(nodes))
+	var group *sybilGroup
+	if a.Strategy == AttackColludingSybil {
+		group = &sybilGroup{members: nodes, normRatio: a.param("norm_ratio"), jitter: a.param("jitter"), round: -1}
+	}
+	for i, node := range nodes {
+		switch a.Strategy {
+		case AttackGradientPoisoning:
+			strategies[i] = scaledAttack{node: node, scale: a.param("scale")}
+		case AttackSignFlipping:
+			strategies[i] = scaledAttack{node: node, scale: -a.param("scale")}
+		case AttackLabelFlipping:
+			flipped := node
+			flipped.Target = make([]float64, len(node.Target))
+			for j, c := range node.Target {
+				flipped.Target[j] = -c
+			}
+			strategies[i] = scaledAttack{node: flipped, scale: 1}
+		case AttackGaussianNoise:
+			strategies[i] = noiseAttack{node: node, sigma: a.param("sigma")}
+		case AttackFreeRider:
+			strategies[i] = constantAttack{value: 0}
+		case AttackSybil:
+			strategies[i] = constantAttack{value: a.param("value")}
+		case AttackModelReplacement:
+			strategies[i] = replacementAttack{node: node, boost: a.param("boost")}
+		case AttackStealth:
+			strategies[i] = stealthAttack{node: node, period: int(a.param("period")), phase: int(a.param("phase")), normRatio: a.param("norm_ratio")}
+		case AttackColludingSybil:
+			strategies[i] = &sybilMember{group: group, index: i}
+		}
+	}
+	return strategies
+}
+
+// scaledAttack uploads its node's honest step times scale.
+type scaledAttack struct {
+	node  AttackNode
+	scale float64
+}
+
+func (s scaledAttack) NextUpdate(_ int, globalWeights []float64) ModelUpdate {
+	step := s.node.HonestStep(globalWeights)
+	for j := range step {
+		step[j] *= s.scale
+	}
+	return ModelUpdate{Step: step, Poisoned: true}
+}
+
+// noiseAttack uploads Gaussian noise.
+type noiseAttack struct {
+	node  AttackNode
+	sigma float64
+}
+
+func (s noiseAttack) NextUpdate(_ int, globalWeights []float64) ModelUpdate {
+	step := make([]float64, len(globalWeights))
+	for j := range step {
+		step[j] = s.sigma * s.node.Rand.NormFloat64()
+	}
+	return ModelUpdate{Step: step, Poisoned: true}
+}
+
+// constantAttack uploads value in every coordinate.
+type constantAttack struct {
+	value float64
+}
+
+func (s constantAttack) NextUpdate(_ int, globalWeights []float64) ModelUpdate {
+	step := make([]float64, len(globalWeights))
+	for j := range step {
+		step[j] = s.value
+	}
+	return ModelUpdate{Step: step, Poisoned: true}
+}
+
+// replacementAttack steers the global model to the mirror image of its
+// node's target: the step boost*(goal - global) cancels the honest majority
+// when boost is near the participant count.
+type replacementAttack struct {
+	node  AttackNode
+	boost float64
+}
+
+func (s replacementAttack) NextUpdate(_ int, globalWeights []float64) ModelUpdate {
+	step := make([]float64, len(globalWeights))
+	for j, w := range globalWeights {
+		step[j] = s.boost * (-s.node.Target[j] - w)
+	}
+	return ModelUpdate{Step: step, Poisoned: true}
+}
+
+// stealthAttack poisons only in alternate rounds, and only as hard as an
+// honest step of normRatio times the honest norm, to stay below norm
+// filters keyed to the median.
+type stealthAttack struct {
+	node      AttackNode
+	period    int
+	phase     int
+	normRatio float64
+}
+
+func (s stealthAttack) NextUpdate(round int, globalWeights []float64) ModelUpdate {
+	step := s.node.HonestStep(globalWeights)
+	if round%s.period != s.phase {
+		return ModelUpdate{Step: step}
+	}
+	for j := range step {
+		step[j] *= -s.normRatio
+	}
+	return ModelUpdate{Step: step, Poisoned: true}
+}
+
+// sybilGroup is the state colluding sybils share: the direction they agree
+// on for the current round and the global model they last saw.
+type sybilGroup struct {
+	members   []AttackNode
+	normRatio float64
+	jitter    float64
+
+	round      int
+	direction  []float64
+	norm       float64
+	lastGlobal []float64
+}
+
+// plan agrees on the round's direction once, on the first member's call.
+func (g *sybilGroup) plan(round int, globalWeights []float64) {
+	if g.round == round {
+		return
+	}
+	g.round = round
+	honest := make([]float64, len(globalWeights))
+	g.norm = 0
+	for _, member := range g.members {
+		step := member.HonestStep(globalWeights)
+		g.norm += l2(step) / float64(len(g.members))
+		for j, v := range step {
+			honest[j] += v / float64(len(g.members))
+		}
+	}
+	// Reverse the global model's last move, or before there is one, the
+	// group's mean honest step.
+	g.direction = make([]float64, len(globalWeights))
+	for j := range g.direction {
+		if g.lastGlobal != nil {
+			g.direction[j] = g.lastGlobal[j] - globalWeights[j]
+		} else {
+			g.direction[j] = -honest[j]
+		}
+	}
+	if norm := l2(g.direction); norm > 0 {
+		for j := range g.direction {
+			g.direction[j] /= norm
+		}
+	}
+	g.lastGlobal = append(g.lastGlobal[:0], globalWeights...)
+}
+
+// sybilMember is one colluding node.
+type sybilMember struct {
+	group *sybilGroup
+	index int
+}
+
+func (s *sybilMember) NextUpdate(round int, globalWeights []float64) ModelUpdate {
+	g := s.group
+	g.plan(round, globalWeights)
+	rng := g.members[s.index].Rand
+	magnitude := g.normRatio * g.norm
+	step := make([]float64, len(g.direction))
+	for j, d := range g.direction {
+		step[j] = magnitude * (d + g.jitter*rng.NormFloat64()/math.Sqrt(float64(len(step))))
+	}
+	return ModelUpdate{Step: step, Poisoned: true}
+}
+
+func l2(v []float64) float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+// AttackOutcome is one strategy's record over a run. A poisoned update
+// succeeds when the aggregator includes it.
+type AttackOutcome struct {
+	Strategy    string  `json:"strategy"`
+	Nodes       int     `json:"nodes"`
+	Poisoned    int     `json:"poisoned"`
+	Included    int     `json:"included"`
+	SuccessRate float64 `json:"success_rate"`
+	// Excluded counts the poisoned updates the aggregator excluded, by
+	// manifest reason.
+	Excluded map[reasons.Code]int `json:"excluded,omitempty"`
+}
+
+// attackSim runs the nodes that have a strategy and scores their poisoned
+// updates against each round's contribution manifest.
+type attackSim struct {
+	strategies map[int]AttackStrategy
+	names      map[int]string
+	// strategyOf and poisoned are keyed by the node IDs uploads carry,
+	// which depend on the federation the run joins.
+	strategyOf map[string]string
+	poisoned   map[string]bool
+	outcomes   map[string]*AttackOutcome
+}
+
+// newAttackSim builds the assigned strategies; each node's noise comes from
+// its own stream of rng.
+func newAttackSim(assignments []AttackAssignment, t *trainingSim, rng *simrand.SeededRand) (*attackSim, error) {
+	a := &attackSim{
+		strategies: make(map[int]AttackStrategy),
+		names:      make(map[int]string),
+		strategyOf: make(map[string]string),
+		poisoned:   make(map[string]bool),
+		outcomes:   make(map[string]*AttackOutcome),
+	}
+	for _, assignment := range assignments {
+		nodes := make([]AttackNode, len(assignment.Nodes))
+		for i, n := range assignment.Nodes {
+			if n >= len(t.targets) {
+				return nil, fmt.Errorf("attack %s assigns node %d of %d", assignment.Strategy, n, len(t.targets))
+			}
+			if _, taken := a.strategies[n]; taken {
+				return nil, fmt.Errorf("node %d is assigned two attack strategies", n)
+			}
+			a.strategies[n] = nil
+			nodes[i] = AttackNode{Index: n, Target: t.targets[n], LearningRate: t.model.LearningRate, Rand: rng.Derive(fmt.Sprintf("node-%03d", n))}
+		}
+		for i, strategy := range newAttackStrategies(assignment, nodes) {
+			n := assignment.Nodes[i]
+			a.strategies[n] = strategy
+			a.names[n] = assignment.Strategy
+		}
+		outcome := a.outcomes[assignment.Strategy]
+		if outcome == nil {
+			outcome = &AttackOutcome{Strategy: assignment.Strategy, Excluded: make(map[reasons.Code]int)}
+			a.outcomes[assignment.Strategy] = outcome
+		}
+		outcome.Nodes += len(assignment.Nodes)
+	}
+	return a, nil
+}
+
+// step returns node n's upload in round and whether it has a strategy.
+func (a *attackSim) step(n int, nodeID string, round int, globalWeights []float64) ([]float64, bool) {
+	strategy, ok := a.strategies[n]
+	if !ok {
+		return nil, false
+	}
+	update := strategy.NextUpdate(round, append([]float64(nil), globalWeights...))
+	a.strategyOf[nodeID] = a.names[n]
+	a.poisoned[nodeID] = update.Poisoned
+	return update.Step, true
+}
+
+// score credits every poisoned update in manifest to its strategy.
+func (a *attackSim) score(manifest *protocol.ContributionManifest) {
+	if manifest == nil {
+		return
+	}
+	for _, entry := range manifest.Entries {
+		if !a.poisoned[entry.NodeID] {
+			continue
+		}
+		outcome := a.outcomes[a.strategyOf[entry.NodeID]]
+		outcome.Poisoned++
+		if entry.Included {
+			outcome.Included++
+		} else {
+			outcome.Excluded[entry.Reason]++
+		}
+		outcome.SuccessRate = float64(outcome.Included) / float64(outcome.Poisoned)
+	}
+	for nodeID := range a.poisoned {
+		delete(a.poisoned, nodeID)
+	}
+}
+
+// report returns each strategy's outcome, sorted by strategy.
+func (a *attackSim) report() []AttackOutcome {
+	report := make([]AttackOutcome, 0, len(a.outcomes))
+	for _, outcome := range a.outcomes {
+		copied := *outcome
+		copied.Excluded = make(map[reasons.Code]int, len(outcome.Excluded))
+		for reason, count := range outcome.Excluded {
+			copied.Excluded[reason] = count
+		}
+		report = append(report, copied)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Strategy < report[j].Strategy })
+	return report
+}
+
+// LoadQuadraticModel reads a training scenario, including its attack
+// assignments, from a JSON file.
+func LoadQuadraticModel(path string) (*QuadraticModel, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-supplied scenario path
+	if err != nil {
+		return nil, fmt.Errorf("read training scenario: %w", err)
+	}
+	var model QuadraticModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("decode training scenario: %w", err)
+	}
+	if err := model.Validate(); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/chaos"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/lifecycle"
 	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/testnet/simulator"
 )
 
@@ -15,7 +22,15 @@ func main() {
 	roundMs := flag.Int("round-ms", 250, "base round duration in milliseconds")
 	stragglerRate := flag.Float64("straggler-rate", 0.1, "fraction of rounds with straggler delay [0,1]")
 	maliciousRate := flag.Float64("malicious-rate", 0.02, "fraction of rounds with malicious-event detection [0,1]")
-	seed := flag.Int64("seed", 0, "random seed (0 uses current time)")
+	seed := flag.Int64("seed", 0, "random seed for every component; the same seed reproduces the report (0 uses current time)")
+	participationRate := flag.Float64("participation-rate", 0, "fraction of nodes selected to train each round (0 selects all)")
+	gossipFanout := flag.Int("gossip-fanout", 0, "spread each round's model by push gossip to this many peers per hop (0 disables)")
+	scenario := flag.String("scenario", "", "named scenario preset (e.g. byzantine-55); overrides the rate flags")
+	chaosPlan := flag.String("chaos-plan", "", "path to a JSON chaos plan")
+	networkModel := flag.String("network-model", "", "path to a JSON network model (latency, bandwidth, loss)")
+	trainingScenario := flag.String("training", "", "path to a JSON training scenario (quadratic model and attack strategies per node)")
+	reportPath := flag.String("report", "", "write the result as JSON to this path")
+	validate := flag.Bool("validate", false, "check the scenario for inconsistencies, print every violation, and exit without running it")
 	flag.Parse()
 
 	cfg := simulator.Config{
@@ -24,15 +39,125 @@ func main() {
 		RoundDuration:     time.Duration(*roundMs) * time.Millisecond,
 		StragglerRate:     *stragglerRate,
 		MaliciousNodeRate: *maliciousRate,
-		RandomSeed:        *seed,
+	}
+	if *scenario != "" {
+		preset, err := simulator.Preset(*scenario)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		cfg = preset
+	}
+	cfg.RandomSeed = *seed
+	cfg.ParticipationRate = *participationRate
+	cfg.GossipFanout = *gossipFanout
+	if *validate {
+		os.Exit(validateScenario(cfg, *trainingScenario, *chaosPlan, *networkModel))
+	}
+	if *chaosPlan != "" {
+		plan, err := chaos.LoadPlan(*chaosPlan)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		cfg.Chaos = plan
+	}
+
+	if *networkModel != "" {
+		model, err := simulator.LoadNetworkModel(*networkModel)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		cfg.Network = model
+	}
+
+	if *trainingScenario != "" {
+		model, err := simulator.LoadQuadraticModel(*trainingScenario)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		cfg.Training = model
+	}
+
+	if err := simulator.Validate(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// The run is supervised so SIGINT or SIGTERM stops it between rounds,
+	// still reporting the rounds completed. A simulation is not restarted.
+	var (
+		result simulator.Result
+		runErr error
+	)
+	finished := make(chan struct{})
+	supervisor := lifecycle.NewSupervisor()
+	_ = supervisor.Register(lifecycle.Spec{
+		Name:    "simulation",
+		Restart: &lifecycle.RestartPolicy{Backoff: time.Second, MaxBackoff: time.Second, MaxRestarts: 0, Window: time.Second},
+		Component: lifecycle.Funcs{StartFunc: func(ctx context.Context) error {
+			defer close(finished)
+			result, runErr = simulator.RunContext(ctx, cfg)
+			return nil
+		}},
+	})
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	if err := supervisor.Start(signals); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	select {
+	case <-finished:
+	case <-signals.Done():
+	}
+	if err := supervisor.Stop(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, "shutdown:", err)
+	}
+	if runErr != nil {
+		fmt.Fprintln(os.Stderr, "simulation stopped:", runErr)
 	}
 
-	result := simulator.Run(cfg)
 	summary := simulator.FormatSummary(result)
 	fmt.Println(summary)
 
-	if result.RoundsCompleted < result.RoundsRequested {
+	if *reportPath != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err == nil {
+			err = os.WriteFile(*reportPath, data, 0o600)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to write report:", err)
+			os.Exit(1)
+		}
+	}
+
+	if result.RoundsCompleted+result.FailedRounds < result.RoundsRequested {
 		fmt.Fprintln(os.Stderr, "simulation did not complete all requested rounds")
 		os.Exit(1)
 	}
 }
+
+// validateScenario reports every violation in the scenario the flags
+// describe and returns the exit code: 0 if it is valid, 1 if not, and 2
+// if a file cannot be read.
+func validateScenario(cfg simulator.Config, trainingPath, chaosPath, networkPath string) int {
+	cfg, err := simulator.ReadScenario(cfg, trainingPath, chaosPath, networkPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	err = simulator.Validate(cfg)
+	var scenarioErr *simulator.ScenarioError
+	if !errors.As(err, &scenarioErr) {
+		fmt.Println("scenario is valid")
+		return 0
+	}
+	for _, violation := range scenarioErr.Violations {
+		fmt.Println(violation)
+	}
+	fmt.Fprintf(os.Stderr, "%d violations\n", len(scenarioErr.Violations))
+	return 1
+}
@@ -0,0 +1,203 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// SlowCohort makes a group of nodes, the first Fraction of them by index,
+// train for Delay longer than the round duration, as a region on an
+// overnight charging window would.
+type SlowCohort struct {
+	Fraction float64       `json:"fraction"`
+	Delay    time.Duration `json:"delay"`
+}
+
+// Validate checks the cohort's bounds.
+func (c SlowCohort) Validate() error {
+	if c.Fraction < 0 || c.Fraction > 1 {
+		return fmt.Errorf("slow cohort fraction must be in [0, 1], got %f", c.Fraction)
+	}
+	if c.Delay < 0 {
+		return fmt.Errorf("slow cohort delay must not be negative, got %s", c.Delay)
+	}
+	return nil
+}
+
+// DeadlineReport records how rounds fared against their task deadlines.
+type DeadlineReport struct {
+	// Requests counts the extension requests nodes sent, and Extensions
+	// the rounds extended.
+	Requests   int
+	Extensions int
+	// MissedUpdates counts participants that finished after their round's
+	// deadline, extended or not.
+	MissedUpdates int
+	// Penalties counts requests beyond the policy's FreeRequests.
+	Penalties int
+}
+
+// deadlineStart is the simulated time the first round starts; round r
+// starts r hours later, so rounds never overlap.
+var deadlineStart = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// deadlineSim times each participant's training against the round's task
+// deadline. Halfway to the deadline every participant projects its finish
+// from its progress so far, and those projecting a miss send a signed
+// extension request to the scheduler's negotiator.
+type deadlineSim struct {
+	deadline   time.Duration
+	compute    time.Duration
+	cohort     SlowCohort
+	slow       int
+	negotiator *scheduler.ExtensionNegotiator
+	// aggregator holds the identity keys of the nodes that have asked.
+	aggregator *crypto.SecureChannel
+	identities map[int]*crypto.SecureChannel
+	clock      time.Time
+	report     DeadlineReport
+}
+
+func newDeadlineSim(cfg Config) (*deadlineSim, error) {
+	d := &deadlineSim{deadline: cfg.TaskDeadline, compute: cfg.RoundDuration}
+	if cfg.SlowCohort != nil {
+		if err := cfg.SlowCohort.Validate(); err != nil {
+			return nil, err
+		}
+		d.cohort = *cfg.SlowCohort
+		d.slow = int(d.cohort.Fraction * float64(cfg.NodeCount))
+	}
+	if cfg.DeadlineExtensions == nil {
+		return d, nil
+	}
+
+	aggregator, err := crypto.NewSecureChannel()
+	if err != nil {
+		return nil, err
+	}
+	policy := *cfg.DeadlineExtensions
+	penalize := policy.Penalize
+	policy.Penalize = func(nodeID string) {
+		d.report.Penalties++
+		if penalize != nil {
+			penalize(nodeID)
+		}
+	}
+	negotiator, err := scheduler.NewExtensionNegotiator(policy, aggregator.VerifySignatureAs)
+	if err != nil {
+		return nil, err
+	}
+	negotiator.SetClock(func() time.Time { return d.clock })
+	d.negotiator = negotiator
+	d.aggregator = aggregator
+	d.identities = make(map[int]*crypto.SecureChannel)
+	return d, nil
+}
+
+// round runs round for participants and returns those that finished by
+// the deadline and how long the round took.
+func (d *deadlineSim) round(round int, participants []int) ([]int, time.Duration, error) {
+	started := deadlineStart.Add(time.Duration(round) * time.Hour)
+	state := scheduler.RoundState{RoundID: round, StartedAt: started, Deadline: started.Add(d.deadline), NodeTarget: len(participants)}
+	task := protocol.TrainingTask{Round: round, Deadline: state.Deadline}
+
+	if d.negotiator != nil {
+		ids := make([]string, len(participants))
+		for i, n := range participants {
+			ids[i] = nodeName(n)
+		}
+		d.negotiator.Open(state, ids)
+		checkpoint := started.Add(d.deadline / 2)
+		d.clock = checkpoint
+		for _, n := range participants {
+			progress := min(float64(d.deadline/2)/float64(d.trainTime(n)), 1)
+			req, late := protocol.NewExtensionRequest(nodeName(n), task, started, checkpoint, progress)
+			if !late {
+				continue
+			}
+			if err := d.sign(n, &req); err != nil {
+				return nil, 0, err
+			}
+			d.report.Requests++
+			extension, err := d.negotiator.Request(req)
+			if err != nil && !errors.Is(err, scheduler.ErrExtensionGranted) && !errors.Is(err, scheduler.ErrRoundBudgetExhausted) {
+				return nil, 0, err
+			}
+			if extension != nil {
+				d.report.Extensions++
+				task.Extend(*extension)
+			}
+		}
+	}
+
+	var onTime []int
+	elapsed := time.Duration(0)
+	for _, n := range participants {
+		finish := started.Add(d.trainTime(n))
+		if finish.After(task.Deadline) {
+			d.report.MissedUpdates++
+			elapsed = task.Deadline.Sub(started)
+			continue
+		}
+		onTime = append(onTime, n)
+		elapsed = max(elapsed, finish.Sub(started))
+	}
+	return onTime, elapsed, nil
+}
+
+// trainTime is how long node n trains for.
+func (d *deadlineSim) trainTime(n int) time.Duration {
+	if n < d.slow {
+		return d.compute + d.cohort.Delay
+	}
+	return d.compute
+}
+
+// sign signs req with node n's identity, creating and registering it with
+// the aggregator on the node's first request.
+func (d *deadlineSim) sign(n int, req *protocol.ExtensionRequest) error {
+	identity := d.identities[n]
+	if identity == nil {
+		var err error
+		if identity, err = crypto.NewSecureChannel(); err != nil {
+			return err
+		}
+		publicKeyPEM, err := identity.ExportPublicKey()
+		if err != nil {
+			return err
+		}
+		publicKey, err := crypto.ImportPublicKey(publicKeyPEM)
+		if err != nil {
+			return err
+		}
+		if err := d.aggregator.RegisterPeer(nodeName(n), publicKey); err != nil {
+			return err
+		}
+		d.identities[n] = identity
+	}
+	digest := req.SigningDigest()
+	signature, err := identity.SignData(digest[:])
+	if err != nil {
+		return err
+	}
+	req.Signature = signature
+	req.SignatureAlgorithm = identity.Algorithm()
+	return nil
+}
+
+func nodeName(n int) string {
+	return fmt.Sprintf("node-%03d", n)
+}
+
+func deadlineReport(deadlines *deadlineSim) *DeadlineReport {
+	if deadlines == nil {
+		return nil
+	}
+	report := deadlines.report
+	return &report
+}
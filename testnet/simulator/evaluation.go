@@ -0,0 +1,188 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/evaluation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/simrand"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// EvaluationConfig runs an evaluation round on every model training
+// commits: a sample of nodes scores it on local data and reports only
+// aggregate metrics, which are combined into a global estimate.
+type EvaluationConfig struct {
+	// SampleRate is the fraction of nodes sampled each round, at least
+	// one.
+	SampleRate float64 `json:"sample_rate"`
+	// NodeAccuracy is each node's accuracy on its local evaluation data.
+	// Nodes beyond it score 1/(1+loss) of their local objective.
+	NodeAccuracy []float64 `json:"node_accuracy,omitempty"`
+	// MetricsDP, when set, makes every node noise its reports with local
+	// DP, spending at most Allocation ε of metric budget on them; a node
+	// whose allocation is spent declines further rounds. Noise comes from
+	// the run's seeded stream.
+	MetricsDP  *privacy.MetricsDPConfig `json:"metrics_dp,omitempty"`
+	Allocation float64                  `json:"allocation,omitempty"`
+}
+
+// Validate checks the evaluation parameters.
+func (c *EvaluationConfig) Validate() error {
+	if c.SampleRate <= 0 || c.SampleRate > 1 {
+		return fmt.Errorf("evaluation sample rate must be in (0, 1], got %f", c.SampleRate)
+	}
+	if c.MetricsDP != nil && c.Allocation <= 0 {
+		return fmt.Errorf("evaluation with metrics DP needs a positive allocation, got %f", c.Allocation)
+	}
+	return nil
+}
+
+// EvaluationReport records every evaluation round of a run.
+type EvaluationReport struct {
+	// Rounds holds each round's global estimate.
+	Rounds []modeldist.Evaluation
+	// TrueAccuracy is, for each of Rounds, the sample-weighted accuracy
+	// over every node: what the estimate targets.
+	TrueAccuracy []float64
+	// Declined counts sampled nodes that withheld a report because their
+	// metric allocation was spent; Reports counts each node's reports.
+	Declined int
+	Reports  map[string]int
+}
+
+// evaluationSim samples nodes to score each committed model and combines
+// their reports through an Evaluator.
+type evaluationSim struct {
+	config    EvaluationConfig
+	policy    scheduler.EvaluationPolicy
+	evaluator *evaluation.Evaluator
+	// budgets holds each node's metric allocation when reports are noised.
+	budgets []*privacy.EvaluationBudget
+	report  EvaluationReport
+}
+
+// newEvaluationSim samples from rng's stream and draws DP noise from its
+// "noise" stream. store, when set, records each round's estimate.
+func newEvaluationSim(config EvaluationConfig, nodeCount int, store *modeldist.ModelStore, rng *simrand.SeededRand) (*evaluationSim, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	e := &evaluationSim{
+		config:    config,
+		policy:    scheduler.EvaluationPolicy{SampleRate: config.SampleRate, MinNodes: 1, Rand: rng},
+		evaluator: evaluation.NewEvaluator(store),
+		report:    EvaluationReport{Reports: make(map[string]int)},
+	}
+	if config.MetricsDP != nil {
+		noise := rng.Derive("noise")
+		e.budgets = make([]*privacy.EvaluationBudget, nodeCount)
+		for n := range e.budgets {
+			privatizer, err := privacy.NewMetricsPrivatizer(*config.MetricsDP)
+			if err != nil {
+				return nil, err
+			}
+			privatizer.SetNoiseSource(noise)
+			if e.budgets[n], err = privacy.NewEvaluationBudget(privatizer, config.Allocation); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return e, nil
+}
+
+// accuracy is node n's accuracy on its local data at the current model.
+func (e *evaluationSim) accuracy(t *trainingSim, n int) float64 {
+	if n < len(e.config.NodeAccuracy) {
+		return e.config.NodeAccuracy[n]
+	}
+	return 1 / (1 + t.nodeLoss(n))
+}
+
+// round evaluates the model training committed in round.
+func (e *evaluationSim) round(t *trainingSim, round int) error {
+	nodes := make([]string, len(t.targets))
+	index := make(map[string]int, len(nodes))
+	for n := range nodes {
+		nodes[n] = t.nodeID(n)
+		index[nodes[n]] = n
+	}
+	digest := protocol.WeightsDigest(batch.Update{Weights: t.weights}.Bytes())
+	task, sampled, err := e.policy.IssueEvaluation(round, digest, nodes)
+	if err != nil {
+		return err
+	}
+	if t.federation != nil {
+		task.FederationID = t.federation.ID
+	}
+	if err := e.evaluator.Begin(task, sampled); err != nil {
+		return err
+	}
+
+	for _, nodeID := range sampled {
+		n := index[nodeID]
+		report := protocol.EvaluationReport{
+			NodeID:       nodeID,
+			Round:        round,
+			ModelDigest:  digest,
+			FederationID: task.FederationID,
+			Metrics:      protocol.Metrics{Loss: t.nodeLoss(n), Accuracy: e.accuracy(t, n), Samples: t.samples[n]},
+			Timestamp:    time.Now(),
+		}
+		if e.budgets != nil {
+			noised, err := e.budgets[n].Privatize(task, report)
+			if errors.Is(err, privacy.ErrBudgetExceeded) {
+				e.report.Declined++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			report = noised
+		}
+		if err := e.evaluator.Submit(report); err != nil {
+			return err
+		}
+		e.report.Reports[nodeID]++
+	}
+
+	result, err := e.evaluator.Complete(round)
+	if errors.Is(err, evaluation.ErrNoReports) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	e.report.Rounds = append(e.report.Rounds, result)
+	e.report.TrueAccuracy = append(e.report.TrueAccuracy, e.trueAccuracy(t))
+	return nil
+}
+
+// trueAccuracy is the sample-weighted accuracy over every node.
+func (e *evaluationSim) trueAccuracy(t *trainingSim) float64 {
+	weighted, total := 0.0, 0
+	for n, samples := range t.samples {
+		weighted += float64(samples) * e.accuracy(t, n)
+		total += samples
+	}
+	return weighted / float64(total)
+}
+
+func evaluationReport(e *evaluationSim) *EvaluationReport {
+	if e == nil {
+		return nil
+	}
+	report := e.report
+	report.Rounds = append([]modeldist.Evaluation(nil), e.report.Rounds...)
+	report.TrueAccuracy = append([]float64(nil), e.report.TrueAccuracy...)
+	report.Reports = make(map[string]int, len(e.report.Reports))
+	for nodeID, count := range e.report.Reports {
+		report.Reports[nodeID] = count
+	}
+	return &report
+}
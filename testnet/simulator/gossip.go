@@ -0,0 +1,238 @@
+package simulator
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/simrand"
+)
+
+// GossipReport records how each completed round's global model spread by
+// push gossip.
+type GossipReport struct {
+	Fanout int
+	// Messages counts pushes; Redundant those reaching a node that already
+	// had the model.
+	Messages  int
+	Redundant int
+	// MaxHops is the most hops any round needed to reach every node.
+	MaxHops int
+}
+
+// gossipSim spreads models by push gossip: every hop, the aggregator and
+// every node holding the model push it to Fanout peers chosen at random.
+type gossipSim struct {
+	rng    *simrand.SeededRand
+	report GossipReport
+}
+
+func newGossipSim(fanout int, rng *simrand.SeededRand) *gossipSim {
+	return &gossipSim{rng: rng, report: GossipReport{Fanout: fanout}}
+}
+
+// round gossips one model to nodeCount nodes and returns the hops taken.
+func (g *gossipSim) round(nodeCount int) int {
+	informed := make([]bool, nodeCount)
+	holders := []int{-1} // -1 is the aggregator
+	hops := 0
+	for len(holders) <= nodeCount {
+		hops++
+		var reached []int
+		for _, sender := range holders {
+			for k := 0; k < g.report.Fanout; k++ {
+				peer, ok := g.peer(sender, nodeCount)
+				if !ok {
+					break
+				}
+				g.report.Messages++
+				if informed[peer] {
+					g.report.Redundant++
+					continue
+				}
+				informed[peer] = true
+				reached = append(reached, peer)
+			}
+		}
+		holders = append(holders, reached...)
+	}
+	if hops > g.report.MaxHops {
+		g.report.MaxHops = hops
+	}
+	return hops
+}
+
+// peer picks a gossip target for sender uniformly among the other nodes.
+func (g *gossipSim) peer(sender, nodeCount int) (int, bool) {
+	if sender < 0 {
+		return g.rng.Intn(nodeCount), true
+	}
+	if nodeCount < 2 {
+		return 0, false
+	}
+	peer := g.rng.Intn(nodeCount - 1)
+	if peer >= sender {
+		peer++
+	}
+	return peer, true
+}
+
+// AdaptiveGossip gossips every completed round's consensus votes with a
+// fanout a p2p.FanoutController adjusts from the propagation it measured
+// the round before.
+type AdaptiveGossip struct {
+	// Fanout bounds the controller. A zero RoundBudget is the run's
+	// RoundDuration.
+	Fanout p2p.FanoutConfig `json:"fanout"`
+	// HopLatency is the latency of each gossip message. The zero value is
+	// a flat 50ms.
+	HopLatency LatencyDistribution `json:"hop_latency"`
+	// Quorum is the share of nodes whose votes make a quorum; default 2/3.
+	Quorum float64 `json:"quorum,omitempty"`
+}
+
+// AdaptiveGossipRound records one round of adaptive vote gossip.
+type AdaptiveGossipRound struct {
+	Round int
+	// Fanout is the fanout the round gossiped at; Next the one the
+	// controller chose from it, for Reason.
+	Fanout int
+	Next   int
+	Reason string
+	// Propagation is the time the observing node took to see a quorum of
+	// votes; Reached is false when it never did.
+	Propagation time.Duration
+	Reached     bool
+	// Messages counts the gossip messages the observing node sent.
+	Messages int
+}
+
+// AdaptiveGossipReport records how the fanout controller fared.
+type AdaptiveGossipReport struct {
+	Goal          time.Duration
+	MessageBudget int
+	Rounds        []AdaptiveGossipRound
+}
+
+// Settled returns the mean fanout of the last n rounds.
+func (r *AdaptiveGossipReport) Settled(n int) float64 {
+	rounds := r.Rounds[max(len(r.Rounds)-n, 0):]
+	if len(rounds) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, round := range rounds {
+		sum += round.Fanout
+	}
+	return float64(sum) / float64(len(rounds))
+}
+
+// adaptiveGossipSim spreads a round's votes by push gossip in which every
+// node forwards a vote to Fanout random peers the first time it receives
+// it. It observes node 000: since push gossip is symmetric in
+// distribution, the time another node's vote takes to reach it is sampled
+// as the time its own vote takes to reach that node.
+type adaptiveGossipSim struct {
+	cfg        AdaptiveGossip
+	controller *p2p.FanoutController
+	rng        *simrand.SeededRand
+	report     AdaptiveGossipReport
+}
+
+func newAdaptiveGossipSim(cfg AdaptiveGossip, roundDuration time.Duration, rng *simrand.SeededRand) (*adaptiveGossipSim, error) {
+	if cfg.Fanout.RoundBudget == 0 {
+		cfg.Fanout.RoundBudget = roundDuration
+	}
+	if cfg.Quorum <= 0 || cfg.Quorum > 1 {
+		cfg.Quorum = 2.0 / 3
+	}
+	if cfg.HopLatency.Mu == 0 {
+		cfg.HopLatency.Mu = math.Log(defaultLatencyMs)
+	}
+	controller, err := p2p.NewFanoutController(cfg.Fanout)
+	if err != nil {
+		return nil, err
+	}
+	return &adaptiveGossipSim{
+		cfg:        cfg,
+		controller: controller,
+		rng:        rng,
+		report:     AdaptiveGossipReport{Goal: controller.Goal(), MessageBudget: cfg.Fanout.MessageBudget},
+	}, nil
+}
+
+// round gossips one round's votes among nodeCount nodes and lets the
+// controller decide the next round's fanout.
+func (g *adaptiveGossipSim) round(round, nodeCount int) {
+	fanout := g.controller.Fanout()
+	arrivals := g.spread(fanout, nodeCount)
+
+	start := deadlineStart.Add(time.Duration(round) * time.Hour)
+	meter := p2p.NewPropagationMeter(int(math.Ceil(g.cfg.Quorum * float64(nodeCount))))
+	for _, arrival := range arrivals {
+		meter.Seen(fmt.Sprintf("node-%03d", arrival.node), start.Add(arrival.at))
+		meter.Sent(fanout)
+	}
+	decision := g.controller.Observe(meter.Sample(round))
+	g.report.Rounds = append(g.report.Rounds, AdaptiveGossipRound{
+		Round:       round,
+		Fanout:      fanout,
+		Next:        decision.Fanout,
+		Reason:      decision.Reason,
+		Propagation: decision.Propagation,
+		Reached:     decision.Reached,
+		Messages:    decision.Messages,
+	})
+}
+
+type gossipArrival struct {
+	node int
+	at   time.Duration
+}
+
+// spread pushes a message from node 0 and returns when it reached each
+// node it reached, earliest first, node 0 itself at zero.
+func (g *adaptiveGossipSim) spread(fanout, nodeCount int) []gossipArrival {
+	arrived := make([]bool, nodeCount)
+	pending := &arrivalQueue{{node: 0}}
+	var arrivals []gossipArrival
+	for pending.Len() > 0 {
+		next := heap.Pop(pending).(gossipArrival)
+		if arrived[next.node] {
+			continue
+		}
+		arrived[next.node] = true
+		arrivals = append(arrivals, next)
+		for k := 0; k < fanout && nodeCount > 1; k++ {
+			peer := g.rng.Intn(nodeCount - 1)
+			if peer >= next.node {
+				peer++
+			}
+			if !arrived[peer] {
+				heap.Push(pending, gossipArrival{node: peer, at: next.at + g.latency()})
+			}
+		}
+	}
+	return arrivals
+}
+
+func (g *adaptiveGossipSim) latency() time.Duration {
+	ms := math.Exp(g.cfg.HopLatency.Mu + g.cfg.HopLatency.Sigma*g.rng.NormFloat64())
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// arrivalQueue orders pending arrivals earliest first.
+type arrivalQueue []gossipArrival
+
+func (q arrivalQueue) Len() int           { return len(q) }
+func (q arrivalQueue) Less(i, j int) bool { return q[i].at < q[j].at }
+func (q arrivalQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *arrivalQueue) Push(x any)        { *q = append(*q, x.(gossipArrival)) }
+func (q *arrivalQueue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,163 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/island"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// IslandRejoin takes nodes offline for one round. Each trains the round in
+// island mode and caches its update, then reconnects before the round
+// closes: its island manager syncs the cached update, and the node, having
+// kept training since, also submits a fresh live update for the same
+// round. The federation must count each node once.
+type IslandRejoin struct {
+	// Round is the round the nodes train offline.
+	Round int
+	// Nodes is how many nodes, the first ones, go offline.
+	Nodes int
+}
+
+// Validate checks that the rejoin takes at least one node offline in a
+// real round.
+func (r IslandRejoin) Validate() error {
+	if r.Round < 1 {
+		return fmt.Errorf("island rejoin round must be positive, got %d", r.Round)
+	}
+	if r.Nodes < 1 {
+		return fmt.Errorf("island rejoin must take at least one node offline, got %d", r.Nodes)
+	}
+	return nil
+}
+
+// IslandRejoinReport records how the federation counted the rejoining
+// nodes.
+type IslandRejoinReport struct {
+	// Synced counts the cached updates the island managers synced.
+	Synced int
+	// LiveSubmitted counts the live updates the rejoining nodes submitted
+	// for the round they trained offline.
+	LiveSubmitted int
+	// Contributions counts the rejoining nodes' manifest entries for that
+	// round.
+	Contributions int
+	// DuplicateContributions counts manifest entries, in every round,
+	// beyond a node's first.
+	DuplicateContributions int
+}
+
+// rejoinSim caches the offline nodes' updates and replays their return.
+type rejoinSim struct {
+	rejoin IslandRejoin
+	// nodes holds the IDs of the nodes that went offline.
+	nodes  map[string]bool
+	report IslandRejoinReport
+}
+
+func newRejoinSim(rejoin IslandRejoin) (*rejoinSim, error) {
+	if err := rejoin.Validate(); err != nil {
+		return nil, err
+	}
+	return &rejoinSim{rejoin: rejoin, nodes: make(map[string]bool)}, nil
+}
+
+// offline reports whether node n trains round offline.
+func (r *rejoinSim) offline(n, round int) bool {
+	return round == r.rejoin.Round && n < r.rejoin.Nodes
+}
+
+// submit caches cached, the update the node trained offline, in a fresh
+// island manager and syncs it to f once the node is back online, then
+// submits live, the node's newer update for the same round.
+func (r *rejoinSim) submit(ctx context.Context, f *federation.Federation, cached, live *protocol.ModelUpdate) error {
+	r.nodes[cached.NodeID] = true
+	manager := island.NewManager(time.Minute, 1, func() bool { return true })
+	manager.SetSyncer(rejoinSyncer{federation: f, report: &r.report})
+	if err := manager.CacheUpdate(island.Update{
+		Timestamp:  cached.Timestamp,
+		Round:      cached.Round,
+		ModelDelta: cached.Weights,
+		PeerID:     cached.NodeID,
+		Metadata: map[string]interface{}{
+			"loss":      cached.Metrics.Loss,
+			"samples":   cached.Metrics.Samples,
+			"statement": cached.Statement,
+		},
+	}); err != nil {
+		return err
+	}
+	if err := manager.ForceSync(ctx); err != nil {
+		return err
+	}
+	if err := f.Submit(live); err != nil {
+		return err
+	}
+	r.report.LiveSubmitted++
+	return nil
+}
+
+// score counts the rejoining nodes' contributions to manifest.
+func (r *rejoinSim) score(manifest *protocol.ContributionManifest) {
+	seen := make(map[string]bool, len(manifest.Entries))
+	for _, entry := range manifest.Entries {
+		if seen[entry.NodeID] {
+			r.report.DuplicateContributions++
+		}
+		seen[entry.NodeID] = true
+	}
+	if manifest.Round != r.rejoin.Round {
+		return
+	}
+	for _, entry := range manifest.Entries {
+		if r.nodes[entry.NodeID] {
+			r.report.Contributions++
+		}
+	}
+}
+
+// rejoinSyncer submits synced island updates to the federation as the
+// node's sync client would, carrying the origin each was cached under.
+type rejoinSyncer struct {
+	federation *federation.Federation
+	report     *IslandRejoinReport
+}
+
+func (s rejoinSyncer) SyncUpdates(updates []island.Update) error {
+	for _, update := range updates {
+		loss, _ := update.Metadata["loss"].(float64)
+		samples, _ := update.Metadata["samples"].(int)
+		statement, _ := update.Metadata["statement"].(*protocol.TrainingStatement)
+		if err := s.federation.Submit(&protocol.ModelUpdate{
+			NodeID:       update.PeerID,
+			Round:        update.Round,
+			Weights:      update.ModelDelta,
+			Statement:    statement,
+			Timestamp:    update.Timestamp,
+			Metrics:      protocol.Metrics{Loss: loss, Samples: samples},
+			FederationID: s.federation.ID,
+			Origin:       update.Origin,
+		}); err != nil {
+			return err
+		}
+		s.report.Synced++
+	}
+	return nil
+}
+
+// liveUpdate is node n's update for task after training one epoch past the
+// copy it cached offline.
+func (t *trainingSim) liveUpdate(task protocol.TrainingTask, n int) (batch.Update, error) {
+	task.Epochs++
+	step, err := t.step(task, n)
+	if err != nil {
+		return batch.Update{}, err
+	}
+	update := batch.Update{NodeID: t.nodeID(n), SampleCount: t.samples[n], Weights: step}
+	update.Statement = protocol.NewTrainingStatement(update.NodeID, task, update.Bytes())
+	return update, nil
+}
@@ -9,6 +9,9 @@ import (
 func Prometheus(r Result, scenario string) string {
 	s := sanitizeLabel(scenario)
 	labels := fmt.Sprintf("scenario=\"%s\"", s)
+	if r.Federation != "" {
+		labels += fmt.Sprintf(",federation=\"%s\"", sanitizeLabel(r.Federation))
+	}
 
 	var b strings.Builder
 	b.WriteString("# HELP sovereign_simulator_nodes Number of simulated nodes\n")
@@ -34,6 +37,26 @@ func Prometheus(r Result, scenario string) string {
 		labels,
 		r.AverageRoundDuration.Seconds(),
 	)
+	if r.Network != nil {
+		b.WriteString("# HELP sovereign_simulator_phase_network_seconds Simulated network time per round phase\n")
+		b.WriteString("# TYPE sovereign_simulator_phase_network_seconds gauge\n")
+		for _, phase := range r.Network.Phases {
+			fmt.Fprintf(&b, "sovereign_simulator_phase_network_seconds{%s,phase=\"%s\"} %.6f\n", labels, phase.Phase, phase.Network.Seconds())
+		}
+		b.WriteString("# HELP sovereign_simulator_phase_compute_seconds Simulated compute time per round phase\n")
+		b.WriteString("# TYPE sovereign_simulator_phase_compute_seconds gauge\n")
+		for _, phase := range r.Network.Phases {
+			fmt.Fprintf(&b, "sovereign_simulator_phase_compute_seconds{%s,phase=\"%s\"} %.6f\n", labels, phase.Phase, phase.Compute.Seconds())
+		}
+	}
+	if r.Chaos != nil {
+		b.WriteString("# HELP sovereign_simulator_failed_rounds_total Rounds that lost more than half of their updates\n")
+		b.WriteString("# TYPE sovereign_simulator_failed_rounds_total counter\n")
+		fmt.Fprintf(&b, "sovereign_simulator_failed_rounds_total{%s} %d\n", labels, r.FailedRounds)
+		b.WriteString("# HELP sovereign_simulator_chaos_dropped_messages_total Messages dropped by chaos injection\n")
+		b.WriteString("# TYPE sovereign_simulator_chaos_dropped_messages_total counter\n")
+		fmt.Fprintf(&b, "sovereign_simulator_chaos_dropped_messages_total{%s} %d\n", labels, r.Chaos.Dropped+r.Chaos.Partitioned)
+	}
 
 	return b.String()
 }
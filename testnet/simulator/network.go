@@ -0,0 +1,269 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+)
+
+// Simulated round phases reported by NetworkReport.
+const (
+	PhaseDistribution = "distribution"
+	PhaseTraining     = "training"
+	PhaseUpload       = "upload"
+	PhaseConsensus    = "consensus"
+)
+
+const (
+	defaultLatencyMs = 50
+	defaultVoteBytes = 256
+	// maxRetransmits bounds how often one lost message is resent.
+	maxRetransmits = 5
+)
+
+// LatencyDistribution is a lognormal link latency: the natural log of the
+// one-way latency in milliseconds is normally distributed with mean Mu and
+// standard deviation Sigma. Mu = ln(50), Sigma = 0 is a flat 50ms.
+type LatencyDistribution struct {
+	Mu    float64 `json:"mu"`
+	Sigma float64 `json:"sigma"`
+}
+
+func (d LatencyDistribution) sample(rng *rand.Rand) time.Duration {
+	ms := math.Exp(d.Mu + d.Sigma*rng.NormFloat64())
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// LinkModel sets the latency between two regions in both directions.
+type LinkModel struct {
+	From    string              `json:"from"`
+	To      string              `json:"to"`
+	Latency LatencyDistribution `json:"latency"`
+}
+
+// NetworkModel shapes every simulated transfer between nodes and the
+// aggregator. Together with Seed it fully determines network timing.
+type NetworkModel struct {
+	// Seed drives latency, jitter, and loss sampling. 0 uses the
+	// simulation's RandomSeed.
+	Seed int64 `json:"seed"`
+	// Regions are assigned to nodes round-robin: node-000 gets Regions[0].
+	Regions []string `json:"regions,omitempty"`
+	// AggregatorRegion defaults to the first region.
+	AggregatorRegion string `json:"aggregator_region,omitempty"`
+	// DefaultLatency applies to region pairs without a Link. The zero value
+	// is a flat 50ms.
+	DefaultLatency LatencyDistribution `json:"default_latency"`
+	Links          []LinkModel         `json:"links,omitempty"`
+	// Per-node bandwidth caps in bytes per second; 0 is unlimited. The
+	// aggregator's own link is not modeled as a bottleneck.
+	UplinkBytesPerSec   int64 `json:"uplink_bytes_per_sec"`
+	DownlinkBytesPerSec int64 `json:"downlink_bytes_per_sec"`
+	// JitterMs adds a uniform [0, JitterMs] delay to every message.
+	JitterMs int `json:"jitter_ms"`
+	// PacketLoss is the probability a message is lost and resent after a
+	// timeout of twice its link latency.
+	PacketLoss float64 `json:"packet_loss"`
+	// ModelBytes is the size of the global model and of each update.
+	ModelBytes int64 `json:"model_bytes"`
+	// VoteBytes is the size of a consensus proposal or vote; default 256.
+	VoteBytes int64 `json:"vote_bytes,omitempty"`
+}
+
+// LoadNetworkModel reads and validates a JSON network model file.
+func LoadNetworkModel(path string) (*NetworkModel, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-supplied network model path
+	if err != nil {
+		return nil, fmt.Errorf("read network model: %w", err)
+	}
+	var model NetworkModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("decode network model: %w", err)
+	}
+	if err := model.Validate(); err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// Validate checks rates, sizes, and distribution parameters.
+func (m *NetworkModel) Validate() error {
+	if m.PacketLoss < 0 || m.PacketLoss >= 1 {
+		return fmt.Errorf("network model: packet_loss must be in [0,1), got %f", m.PacketLoss)
+	}
+	if m.UplinkBytesPerSec < 0 || m.DownlinkBytesPerSec < 0 {
+		return fmt.Errorf("network model: bandwidth caps must not be negative")
+	}
+	if m.JitterMs < 0 || m.ModelBytes < 0 || m.VoteBytes < 0 {
+		return fmt.Errorf("network model: jitter_ms, model_bytes, and vote_bytes must not be negative")
+	}
+	if m.DefaultLatency.Sigma < 0 {
+		return fmt.Errorf("network model: default_latency.sigma must not be negative")
+	}
+	for _, link := range m.Links {
+		if link.From == "" || link.To == "" {
+			return fmt.Errorf("network model: link needs both regions")
+		}
+		if link.Latency.Sigma < 0 {
+			return fmt.Errorf("network model: link %s-%s sigma must not be negative", link.From, link.To)
+		}
+	}
+	return nil
+}
+
+// PhaseTiming splits the time spent in one phase, summed over completed
+// rounds, into network transfer and local compute.
+type PhaseTiming struct {
+	Phase   string        `json:"phase"`
+	Network time.Duration `json:"network"`
+	Compute time.Duration `json:"compute"`
+}
+
+// NetworkReport summarizes the network model's effect on a simulation.
+type NetworkReport struct {
+	Seed        int64         `json:"seed"`
+	Phases      []PhaseTiming `json:"phases"`
+	Transfers   int           `json:"transfers"`
+	Retransmits int           `json:"retransmits"`
+	BytesSent   int64         `json:"bytes_sent"`
+}
+
+// Phase returns the timing for a named phase.
+func (r *NetworkReport) Phase(name string) PhaseTiming {
+	for _, phase := range r.Phases {
+		if phase.Phase == name {
+			return phase
+		}
+	}
+	return PhaseTiming{Phase: name}
+}
+
+// Totals returns network and compute time summed over every phase.
+func (r *NetworkReport) Totals() (network, compute time.Duration) {
+	for _, phase := range r.Phases {
+		network += phase.Network
+		compute += phase.Compute
+	}
+	return network, compute
+}
+
+// networkSim samples transfers for a NetworkModel with its own RNG, so
+// enabling the model does not shift the simulation's other random events.
+type networkSim struct {
+	model   NetworkModel
+	rng     *rand.Rand
+	latency map[[2]string]LatencyDistribution
+	report  NetworkReport
+}
+
+func newNetworkSim(model NetworkModel, seed int64) *networkSim {
+	if model.Seed == 0 {
+		model.Seed = seed
+	}
+	if model.DefaultLatency == (LatencyDistribution{}) {
+		model.DefaultLatency = LatencyDistribution{Mu: math.Log(defaultLatencyMs)}
+	}
+	if model.VoteBytes == 0 {
+		model.VoteBytes = defaultVoteBytes
+	}
+	if model.AggregatorRegion == "" && len(model.Regions) > 0 {
+		model.AggregatorRegion = model.Regions[0]
+	}
+
+	latency := make(map[[2]string]LatencyDistribution, 2*len(model.Links))
+	for _, link := range model.Links {
+		latency[[2]string{link.From, link.To}] = link.Latency
+		latency[[2]string{link.To, link.From}] = link.Latency
+	}
+	return &networkSim{
+		model:   model,
+		rng:     rand.New(rand.NewSource(model.Seed)), // #nosec G404 -- deterministic pseudo-randomness is required for repeatable simulation tests
+		latency: latency,
+		report: NetworkReport{
+			Seed: model.Seed,
+			Phases: []PhaseTiming{
+				{Phase: PhaseDistribution},
+				{Phase: PhaseTraining},
+				{Phase: PhaseUpload},
+				{Phase: PhaseConsensus},
+			},
+		},
+	}
+}
+
+func (s *networkSim) region(node int) string {
+	if len(s.model.Regions) == 0 {
+		return ""
+	}
+	return s.model.Regions[node%len(s.model.Regions)]
+}
+
+// transfer returns how long one message of size bytes takes over a link
+// capped at bytesPerSec, including jitter and retransmissions.
+func (s *networkSim) transfer(from, to string, size, bytesPerSec int64) time.Duration {
+	dist, ok := s.latency[[2]string{from, to}]
+	if !ok {
+		dist = s.model.DefaultLatency
+	}
+
+	var elapsed time.Duration
+	for attempt := 0; ; attempt++ {
+		latency := dist.sample(s.rng)
+		if s.model.JitterMs > 0 {
+			latency += time.Duration(s.rng.Intn(s.model.JitterMs+1)) * time.Millisecond
+		}
+		s.report.Transfers++
+		s.report.BytesSent += size
+		if attempt < maxRetransmits && s.model.PacketLoss > 0 && s.rng.Float64() < s.model.PacketLoss {
+			s.report.Retransmits++
+			elapsed += 2 * latency
+			continue
+		}
+		elapsed += latency
+		if bytesPerSec > 0 {
+			elapsed += time.Duration(float64(size) / float64(bytesPerSec) * float64(time.Second))
+		}
+		return elapsed
+	}
+}
+
+// round simulates one round with compute time spent training and returns
+// the network time it added. Phases are barriers: training starts once the
+// last node has the model, and consensus once the last update has arrived.
+func (s *networkSim) round(nodeCount int, compute time.Duration) time.Duration {
+	agg := s.model.AggregatorRegion
+
+	var distribution, upload time.Duration
+	for n := 0; n < nodeCount; n++ {
+		if d := s.transfer(agg, s.region(n), s.model.ModelBytes, s.model.DownlinkBytesPerSec); d > distribution {
+			distribution = d
+		}
+		if d := s.transfer(s.region(n), agg, s.model.ModelBytes, s.model.UplinkBytesPerSec); d > upload {
+			upload = d
+		}
+	}
+
+	// The aggregator commits once a two-thirds quorum of vote round trips
+	// has completed.
+	trips := make([]time.Duration, nodeCount)
+	for n := range trips {
+		trips[n] = s.transfer(agg, s.region(n), s.model.VoteBytes, s.model.DownlinkBytesPerSec) +
+			s.transfer(s.region(n), agg, s.model.VoteBytes, s.model.UplinkBytesPerSec)
+	}
+	sort.Slice(trips, func(i, j int) bool { return trips[i] < trips[j] })
+	quorum := 2*nodeCount/3 + 1
+	if quorum > nodeCount {
+		quorum = nodeCount
+	}
+	consensus := trips[quorum-1]
+
+	s.report.Phases[0].Network += distribution
+	s.report.Phases[1].Compute += compute
+	s.report.Phases[2].Network += upload
+	s.report.Phases[3].Network += consensus
+	return distribution + upload + consensus
+}
@@ -0,0 +1,161 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/rollback"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// poisonedLossFactor is how much a poisoned model inflates loss.
+const poisonedLossFactor = 2.0
+
+// modelState is the simulated training state a committed model encodes.
+type modelState struct {
+	step     int
+	poisoned bool
+}
+
+func (s modelState) loss() float64 {
+	loss := 1 / (1 + 0.1*float64(s.step))
+	if s.poisoned {
+		loss *= poisonedLossFactor
+	}
+	return loss
+}
+
+func (s modelState) weights() []byte {
+	return []byte(fmt.Sprintf("model-step-%d-poisoned-%t", s.step, s.poisoned))
+}
+
+// modelSim commits a simulated global model every round through a real
+// coordinator and model store so AutoRollback runs against the same paths
+// a node uses.
+type modelSim struct {
+	cfg         Config
+	coordinator *consensus.Coordinator
+	store       *modeldist.ModelStore
+	auto        *rollback.AutoRollback
+	states      map[int]modelState
+	current     modelState
+}
+
+func newModelSim(cfg Config) (*modelSim, error) {
+	m := &modelSim{
+		cfg:         cfg,
+		coordinator: consensus.NewCoordinator(aggregatorID, cfg.NodeCount, cfg.RoundDuration),
+		store:       modeldist.NewModelStore(cfg.Rounds * 2),
+		states:      make(map[int]modelState),
+	}
+	if cfg.AutoRollback != nil {
+		auto, err := rollback.NewAutoRollback(aggregatorID, *cfg.AutoRollback, m.coordinator, m.store, nil)
+		if err != nil {
+			return nil, err
+		}
+		m.auto = auto
+	}
+	return m, nil
+}
+
+// round trains one step, commits it, and lets AutoRollback react. It
+// reports whether a rollback was committed this round.
+func (m *modelSim) round(ctx context.Context, simRound int) (bool, error) {
+	next := modelState{step: m.current.step + 1, poisoned: m.current.poisoned || simRound == m.cfg.PoisonRound}
+	committed := m.store.LatestRound() + 1
+	flagged := 0
+	if simRound == m.cfg.PoisonRound {
+		flagged = int(m.cfg.MaliciousNodeRate * float64(m.cfg.NodeCount))
+	}
+	if err := m.commit(ctx, committed, next, flagged); err != nil {
+		return false, err
+	}
+	m.current = next
+	m.coordinator.Reset()
+
+	if m.auto == nil {
+		return false, nil
+	}
+	proposalID, err := m.auto.ObserveRound(ctx, committed, next.loss())
+	if err != nil || proposalID == "" {
+		return false, err
+	}
+	if err := m.vote(ctx, proposalID); err != nil {
+		return false, err
+	}
+	summary, err := m.auto.Finalize(ctx)
+	m.coordinator.Reset()
+	if err != nil {
+		return false, err
+	}
+	m.current = m.states[summary.RollbackOf]
+	m.states[summary.Round] = m.current
+	return true, nil
+}
+
+func (m *modelSim) commit(ctx context.Context, round int, state modelState, flagged int) error {
+	weights := state.weights()
+	proposalID, err := m.coordinator.ProposeModel(ctx, &consensus.ModelProposal{
+		Round:      round,
+		Weights:    weights,
+		ProposerID: aggregatorID,
+		Timestamp:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := m.vote(ctx, proposalID); err != nil {
+		return err
+	}
+	if err := m.coordinator.CommitModel(ctx, proposalID); err != nil {
+		return err
+	}
+	membership, err := m.coordinator.GetRoundMembership(proposalID)
+	if err != nil {
+		return err
+	}
+	cert := modeldist.CommitCertificate{
+		Round:       round,
+		ProposalID:  proposalID,
+		ModelDigest: protocol.WeightsDigest(weights),
+		QuorumSize:  membership.QuorumSize,
+		Approvals:   m.voters(),
+	}
+	if _, err := m.store.Commit(round, weights, m.cfg.NodeCount, map[string]float64{"loss": state.loss()}, cert); err != nil {
+		return err
+	}
+	m.states[round] = state
+
+	manifest := &protocol.ContributionManifest{Round: round}
+	for n := 0; n < m.cfg.NodeCount; n++ {
+		entry := protocol.ContributionEntry{NodeID: fmt.Sprintf("node-%03d", n), Included: true, Reason: protocol.ReasonIncluded}
+		if n < flagged {
+			entry.Included = false
+			entry.Reason = protocol.ReasonNormOutlier
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+	return m.store.AttachManifest(manifest)
+}
+
+// vote has every member approve proposalID; simulated validators cannot
+// tell a poisoned model from a healthy one at vote time.
+func (m *modelSim) vote(ctx context.Context, proposalID string) error {
+	for _, nodeID := range m.voters() {
+		if err := m.coordinator.CastVote(ctx, &consensus.Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *modelSim) voters() []string {
+	voters := []string{aggregatorID}
+	for i := 1; i < m.cfg.NodeCount; i++ {
+		voters = append(voters, fmt.Sprintf("member-%d", i))
+	}
+	return voters
+}
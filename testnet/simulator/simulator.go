@@ -1,11 +1,23 @@
 package simulator
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
+	"sort"
 	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/chaos"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/rollback"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/simrand"
 )
 
+// aggregatorID is the peer every simulated node sends its update to.
+const aggregatorID = "aggregator"
+
 // Config controls simulation scale and timing for soak tests.
 type Config struct {
 	NodeCount         int
@@ -13,23 +25,169 @@ type Config struct {
 	RoundDuration     time.Duration
 	StragglerRate     float64
 	MaliciousNodeRate float64
-	RandomSeed        int64
+	// RandomSeed seeds every random choice in the run: events, chaos,
+	// network sampling, training data, participant selection, gossip
+	// targets, and privacy noise. The same seed reproduces the run and its
+	// report exactly. Zero seeds from the clock; Result.Seed records it.
+	RandomSeed int64
+	// ParticipationRate is the fraction of nodes selected to train each
+	// round. Zero or one selects every node.
+	ParticipationRate float64
+	// GossipFanout, when positive, spreads each completed round's model by
+	// push gossip to this many peers per holder per hop.
+	GossipFanout int
+	// AdaptiveGossip, when set, gossips each completed round's votes with
+	// a fanout an AIMD controller adjusts toward a propagation goal.
+	AdaptiveGossip *AdaptiveGossip
+	// Chaos, when set, injects message, crash, churn, partition, and clock
+	// faults into every round. Nodes are named node-000, node-001, ...
+	Chaos *chaos.Plan
+	// PoisonRound, when positive, makes that round's commit a successful
+	// poisoning attack: loss roughly doubles until the model is rolled back.
+	PoisonRound int
+	// AutoRollback, when set, runs the automatic rollback policy after
+	// every committed round.
+	AutoRollback *rollback.Policy
+	// Network, when set, adds sampled transfer time for model distribution,
+	// update upload, and consensus votes to every round. RoundDuration is
+	// then the compute time of the training phase alone.
+	Network *NetworkModel
+	// Training, when set, trains a toy quadratic objective through the batch
+	// aggregator every completed round.
+	Training *QuadraticModel
+	// Federations, when set with Training, runs training inside the
+	// federation FederationID of the registry: nodes are bound to it and
+	// named <federation>-node-000, ..., updates are submitted through it,
+	// and every aggregate is voted on by its members and committed to its
+	// model store. Runs sharing a registry may proceed concurrently.
+	Federations  *federation.Registry
+	FederationID string
+	// Provenance, when set with Training, receives the lifecycle events of
+	// every update: ingest, verification, aggregation and, with
+	// Federations, the proposal and commit that carried it.
+	Provenance provenance.Sink
+	// VerificationCommittee, when positive with Training, runs every
+	// update past a committee of this many verifiers, each checking the
+	// update's training statement and signing its verdict, before
+	// aggregation. With Federations the committee joins the federation's
+	// peer table.
+	VerificationCommittee int
+	// LazyVerifiers makes the first committee members approve every
+	// request without checking it.
+	LazyVerifiers int
+	// SpotCheckRate is the probability that a verifier's batch carries a
+	// decoy; verifiers that approve one are flagged. Decoys are drawn from
+	// the run's seeded stream.
+	SpotCheckRate float64
+	// Evaluation, when set with Training, runs an evaluation round on the
+	// model every training round produces. With Federations the estimates
+	// are recorded in the federation's model store.
+	Evaluation *EvaluationConfig
+	// SybilWave, when set with Training and Federations, registers a wave
+	// of sybil identities that vote against every proposal from its round
+	// on. A round the sybils keep from quorum fails the run.
+	SybilWave *SybilWave
+	// IslandRejoin, when set with Training and Federations, takes nodes
+	// offline for a round; on reconnecting each syncs its cached update
+	// and also submits a live one for the same round.
+	IslandRejoin *IslandRejoin
+	// TaskDeadline, when positive, gives each round's training tasks a
+	// deadline this long after the round starts. Participants that finish
+	// later miss the round, which fails when fewer than half finish in
+	// time.
+	TaskDeadline time.Duration
+	// SlowCohort, when set with TaskDeadline, slows a group of nodes down.
+	SlowCohort *SlowCohort
+	// DeadlineExtensions, when set with TaskDeadline, lets participants
+	// that project missing the deadline ask the scheduler to extend it
+	// under this policy.
+	DeadlineExtensions *scheduler.ExtensionPolicy
+	// TieredRounds, when set with a multi-region Network, adds a regional
+	// round in every region and a global round among regions to every
+	// round, each against a flat or topology-derived timeout. A global
+	// round that misses quorum fails the round.
+	TieredRounds *TieredRounds
 }
 
 // Result summarizes simulation outcomes for operator review.
 type Result struct {
 	NodeCount            int
+	Seed                 int64
 	RoundsRequested      int
 	RoundsCompleted      int
 	StragglerEvents      int
 	MaliciousNodeEvents  int
 	AverageRoundDuration time.Duration
+	// FailedRounds counts rounds in which fewer than half of the updates
+	// reached the aggregator.
+	FailedRounds int
+	Chaos        *chaos.Report
+	// PoisonedRound echoes Config.PoisonRound. RecoveredRound is the round in
+	// which a rollback committed, or 0 if none did.
+	PoisonedRound  int
+	Rollbacks      int
+	RecoveredRound int
+	// Network splits each phase into network and compute time when
+	// Config.Network is set.
+	Network *NetworkReport
+	// Training reports convergence when Config.Training is set.
+	Training *TrainingReport
+	// Federation echoes Config.FederationID when Config.Federations is set.
+	Federation string
+	// Participants counts node-rounds selected to train when
+	// Config.ParticipationRate is below one.
+	Participants int
+	// Gossip reports model dissemination when Config.GossipFanout is set.
+	Gossip *GossipReport
+	// AdaptiveGossip reports the fanout controller's decisions when
+	// Config.AdaptiveGossip is set.
+	AdaptiveGossip *AdaptiveGossipReport
+	// Verification reports spot checks and verifier reputations when
+	// Config.VerificationCommittee is set.
+	Verification *VerificationReport
+	// Evaluation reports global model quality when Config.Evaluation is
+	// set.
+	Evaluation *EvaluationReport
+	// Sybils reports the sybil wave when Config.SybilWave is set.
+	Sybils *SybilReport
+	// IslandRejoin reports how the rejoining nodes were counted when
+	// Config.IslandRejoin is set.
+	IslandRejoin *IslandRejoinReport
+	// Deadlines reports missed deadlines and extensions when
+	// Config.TaskDeadline is set.
+	Deadlines *DeadlineReport
+	// TieredRounds reports regional and global round timings when
+	// Config.TieredRounds is set.
+	TieredRounds *TieredRoundsReport
+}
+
+// Preset returns the configuration for a named scenario.
+func Preset(name string) (Config, error) {
+	switch name {
+	case "byzantine-55":
+		return Config{
+			NodeCount:         100,
+			Rounds:            50,
+			RoundDuration:     250 * time.Millisecond,
+			StragglerRate:     0.1,
+			MaliciousNodeRate: 0.55,
+		}, nil
+	default:
+		return Config{}, fmt.Errorf("unknown simulator scenario %q", name)
+	}
 }
 
 // Run executes a deterministic-in-shape, stochastic-in-events training simulation.
 func Run(cfg Config) Result {
+	result, _ := RunContext(context.Background(), cfg)
+	return result
+}
+
+// RunContext is Run with cancellation checked between rounds. On
+// cancellation it returns the rounds completed so far and ctx.Err().
+func RunContext(ctx context.Context, cfg Config) (Result, error) {
 	if cfg.NodeCount <= 0 {
-		cfg.NodeCount = 50
+		cfg.NodeCount = defaultNodeCount
 	}
 	if cfg.Rounds <= 0 {
 		cfg.Rounds = 100
@@ -49,15 +207,150 @@ func Run(cfg Config) Result {
 	if cfg.MaliciousNodeRate > 1 {
 		cfg.MaliciousNodeRate = 1
 	}
+	if cfg.ParticipationRate < 0 || cfg.ParticipationRate > 1 {
+		cfg.ParticipationRate = 0
+	}
 	if cfg.RandomSeed == 0 {
 		cfg.RandomSeed = time.Now().UnixNano()
 	}
+	if err := Validate(cfg); err != nil {
+		return Result{NodeCount: cfg.NodeCount, Seed: cfg.RandomSeed, RoundsRequested: cfg.Rounds}, err
+	}
 
-	rng := rand.New(rand.NewSource(cfg.RandomSeed)) // #nosec G404 -- deterministic pseudo-randomness is required for repeatable simulation tests
-	result := Result{NodeCount: cfg.NodeCount, RoundsRequested: cfg.Rounds}
+	rng := simrand.New(cfg.RandomSeed)
+	result := Result{NodeCount: cfg.NodeCount, Seed: cfg.RandomSeed, RoundsRequested: cfg.Rounds, PoisonedRound: cfg.PoisonRound}
+	if cfg.Federations != nil {
+		result.Federation = cfg.FederationID
+	}
 	var totalDuration time.Duration
 
+	var inj *chaos.Injector
+	if cfg.Chaos != nil {
+		inj = chaos.NewInjector(cfg.Chaos, cfg.RandomSeed)
+	}
+
+	var network *networkSim
+	if cfg.Network != nil {
+		if err := cfg.Network.Validate(); err != nil {
+			return result, err
+		}
+		network = newNetworkSim(*cfg.Network, cfg.RandomSeed)
+	}
+
+	var training *trainingSim
+	if cfg.Training != nil {
+		if err := cfg.Training.Validate(); err != nil {
+			return result, err
+		}
+		var err error
+		if training, err = newTrainingSim(*cfg.Training, cfg.NodeCount, rng); err != nil {
+			return result, err
+		}
+		if cfg.Federations != nil {
+			if err := training.joinFederation(cfg.Federations, cfg.FederationID); err != nil {
+				return result, err
+			}
+		}
+		if cfg.SybilWave != nil {
+			if training.federation == nil {
+				return result, fmt.Errorf("a sybil wave needs a federation to register with")
+			}
+			if training.sybils, err = newSybilSim(*cfg.SybilWave, cfg.Federations); err != nil {
+				return result, err
+			}
+		}
+		if cfg.IslandRejoin != nil {
+			if training.federation == nil {
+				return result, fmt.Errorf("an island rejoin needs a federation to sync to")
+			}
+			if training.rejoin, err = newRejoinSim(*cfg.IslandRejoin); err != nil {
+				return result, err
+			}
+		}
+		if cfg.Provenance != nil {
+			training.setProvenance(cfg.Provenance)
+			defer training.closeProvenance()
+		}
+		if cfg.VerificationCommittee > 0 {
+			if err := training.verifyWith(cfg.VerificationCommittee, cfg.LazyVerifiers, cfg.SpotCheckRate, rng.Derive("spot-checks"), cfg.RoundDuration); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	var evaluation *evaluationSim
+	if training != nil && cfg.Evaluation != nil {
+		var store *modeldist.ModelStore
+		if training.federation != nil {
+			store = training.federation.ModelStore
+		}
+		var err error
+		if evaluation, err = newEvaluationSim(*cfg.Evaluation, cfg.NodeCount, store, rng.Derive("evaluation")); err != nil {
+			return result, err
+		}
+	}
+
+	var participants *simrand.SeededRand
+	if cfg.ParticipationRate > 0 && cfg.ParticipationRate < 1 {
+		participants = rng.Derive("participants")
+	}
+
+	var gossip *gossipSim
+	if cfg.GossipFanout > 0 {
+		gossip = newGossipSim(cfg.GossipFanout, rng.Derive("gossip"))
+	}
+
+	var adaptive *adaptiveGossipSim
+	if cfg.AdaptiveGossip != nil {
+		var err error
+		if adaptive, err = newAdaptiveGossipSim(*cfg.AdaptiveGossip, cfg.RoundDuration, rng.Derive("adaptive-gossip")); err != nil {
+			return result, err
+		}
+	}
+
+	var model *modelSim
+	if cfg.PoisonRound > 0 || cfg.AutoRollback != nil {
+		var err error
+		if model, err = newModelSim(cfg); err != nil {
+			return result, err
+		}
+	}
+
+	var deadlines *deadlineSim
+	if cfg.TaskDeadline > 0 {
+		var err error
+		if deadlines, err = newDeadlineSim(cfg); err != nil {
+			return result, err
+		}
+	}
+
+	var tiers *tierSim
+	if cfg.TieredRounds != nil {
+		var err error
+		if tiers, err = newTierSim(*cfg.TieredRounds, cfg.Network, cfg.NodeCount, rng.Derive("tiered-rounds").Int63()); err != nil {
+			return result, err
+		}
+	}
+
 	for i := 0; i < cfg.Rounds; i++ {
+		if err := ctx.Err(); err != nil {
+			if result.RoundsCompleted > 0 {
+				result.AverageRoundDuration = totalDuration / time.Duration(result.RoundsCompleted)
+			}
+			result.Chaos = chaosReport(inj)
+			result.Network = networkReport(network)
+			result.Training = trainingReport(training)
+			result.Gossip = gossipReport(gossip)
+			result.AdaptiveGossip = adaptiveGossipReport(adaptive)
+			result.Verification = verificationReport(training)
+			result.Evaluation = evaluationReport(evaluation)
+			result.Sybils = sybilReport(training)
+			result.IslandRejoin = rejoinReport(training)
+			result.Deadlines = deadlineReport(deadlines)
+			result.TieredRounds = tieredRoundsReport(tiers)
+			return result, err
+		}
+
 		roundDuration := cfg.RoundDuration
 
 		if rng.Float64() < cfg.StragglerRate {
@@ -69,18 +362,227 @@ func Run(cfg Config) Result {
 			result.MaliciousNodeEvents++
 		}
 
+		compute := roundDuration
+
+		if inj != nil {
+			delay, stragglers, ok := chaosRound(inj, cfg, i+1)
+			roundDuration += delay
+			result.StragglerEvents += stragglers
+			if !ok {
+				result.FailedRounds++
+				continue
+			}
+		}
+
+		if network != nil {
+			roundDuration += network.round(cfg.NodeCount, compute)
+		}
+
+		if tiers != nil {
+			elapsed, ok := tiers.round()
+			roundDuration += elapsed
+			if !ok {
+				result.FailedRounds++
+				continue
+			}
+		}
+
+		var selected []int
+		if participants != nil {
+			selected = selectParticipants(participants, cfg.NodeCount, cfg.ParticipationRate)
+			result.Participants += len(selected)
+		}
+
+		if deadlines != nil {
+			participating := selected
+			if participating == nil {
+				participating = make([]int, cfg.NodeCount)
+				for n := range participating {
+					participating[n] = n
+				}
+			}
+			onTime, elapsed, err := deadlines.round(i+1, participating)
+			if err != nil {
+				return result, fmt.Errorf("round %d: %w", i+1, err)
+			}
+			roundDuration = max(roundDuration, elapsed)
+			if len(onTime)*2 < len(participating) {
+				result.FailedRounds++
+				continue
+			}
+			selected = onTime
+		}
+
+		if training != nil {
+			if err := training.round(ctx, i+1, selected); err != nil {
+				result.Sybils = sybilReport(training)
+				return result, fmt.Errorf("round %d: %w", i+1, err)
+			}
+			if evaluation != nil {
+				if err := evaluation.round(training, i+1); err != nil {
+					return result, fmt.Errorf("round %d evaluation: %w", i+1, err)
+				}
+			}
+		}
+
+		if model != nil {
+			rolledBack, err := model.round(ctx, i+1)
+			if err != nil {
+				return result, fmt.Errorf("round %d: %w", i+1, err)
+			}
+			if rolledBack {
+				result.Rollbacks++
+				result.RecoveredRound = i + 1
+			}
+		}
+
+		if gossip != nil {
+			gossip.round(cfg.NodeCount)
+		}
+		if adaptive != nil {
+			adaptive.round(i+1, cfg.NodeCount)
+		}
+
 		totalDuration += roundDuration
 		result.RoundsCompleted++
 	}
 
-	result.AverageRoundDuration = totalDuration / time.Duration(result.RoundsCompleted)
-	return result
+	if result.RoundsCompleted > 0 {
+		result.AverageRoundDuration = totalDuration / time.Duration(result.RoundsCompleted)
+	}
+	result.Chaos = chaosReport(inj)
+	result.Network = networkReport(network)
+	result.Training = trainingReport(training)
+	result.Gossip = gossipReport(gossip)
+	result.AdaptiveGossip = adaptiveGossipReport(adaptive)
+	result.Verification = verificationReport(training)
+	result.Evaluation = evaluationReport(evaluation)
+	result.Sybils = sybilReport(training)
+	result.IslandRejoin = rejoinReport(training)
+	result.Deadlines = deadlineReport(deadlines)
+	result.TieredRounds = tieredRoundsReport(tiers)
+	return result, nil
+}
+
+// selectParticipants picks a rate fraction of nodeCount nodes, at least one,
+// in node order.
+func selectParticipants(rng *simrand.SeededRand, nodeCount int, rate float64) []int {
+	count := int(rate * float64(nodeCount))
+	if count < 1 {
+		count = 1
+	}
+	selected := rng.Perm(nodeCount)[:count]
+	sort.Ints(selected)
+	return selected
+}
+
+func rejoinReport(training *trainingSim) *IslandRejoinReport {
+	if training == nil || training.rejoin == nil {
+		return nil
+	}
+	report := training.rejoin.report
+	return &report
+}
+
+func verificationReport(training *trainingSim) *VerificationReport {
+	if training == nil || training.verification == nil {
+		return nil
+	}
+	return training.verification.finalReport()
+}
+
+func sybilReport(training *trainingSim) *SybilReport {
+	if training == nil || training.sybils == nil {
+		return nil
+	}
+	return training.sybils.finalReport(training.federation)
+}
+
+func gossipReport(gossip *gossipSim) *GossipReport {
+	if gossip == nil {
+		return nil
+	}
+	report := gossip.report
+	return &report
+}
+
+func adaptiveGossipReport(adaptive *adaptiveGossipSim) *AdaptiveGossipReport {
+	if adaptive == nil {
+		return nil
+	}
+	report := adaptive.report
+	report.Rounds = append([]AdaptiveGossipRound(nil), report.Rounds...)
+	return &report
+}
+
+func chaosReport(inj *chaos.Injector) *chaos.Report {
+	if inj == nil {
+		return nil
+	}
+	report := inj.Report()
+	return &report
+}
+
+func networkReport(network *networkSim) *NetworkReport {
+	if network == nil {
+		return nil
+	}
+	report := network.report
+	report.Phases = append([]PhaseTiming(nil), network.report.Phases...)
+	return &report
+}
+
+func trainingReport(training *trainingSim) *TrainingReport {
+	if training == nil {
+		return nil
+	}
+	report := training.report
+	report.Hyperparameters = training.advisor.History()
+	if training.attacks != nil {
+		report.Attacks = training.attacks.report()
+	}
+	return &report
+}
+
+// chaosRound sends one update per node to the aggregator through inj. It
+// returns the extra round latency, the number of clock-skewed stragglers,
+// and whether at least half of the updates arrived.
+func chaosRound(inj *chaos.Injector, cfg Config, round int) (time.Duration, int, bool) {
+	inj.AdvanceRound(round)
+
+	delivered := 0
+	stragglers := 0
+	var maxDelay time.Duration
+	for n := 0; n < cfg.NodeCount; n++ {
+		nodeID := fmt.Sprintf("node-%03d", n)
+		fault := inj.MessageFault(nodeID, aggregatorID)
+		if fault.Drop {
+			continue
+		}
+		delivered++
+		if fault.Delay > maxDelay {
+			maxDelay = fault.Delay
+		}
+		skew := inj.Skew(nodeID)
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > cfg.RoundDuration/2 {
+			stragglers++
+		}
+	}
+	return maxDelay, stragglers, delivered*2 >= cfg.NodeCount
 }
 
 // FormatSummary renders a human-readable summary for CI logs.
 func FormatSummary(r Result) string {
-	return fmt.Sprintf(
-		"nodes=%d rounds=%d/%d avg_round=%s stragglers=%d malicious_events=%d",
+	summary := ""
+	if r.Federation != "" {
+		summary = fmt.Sprintf("federation=%s ", r.Federation)
+	}
+	summary += fmt.Sprintf(
+		"seed=%d nodes=%d rounds=%d/%d avg_round=%s stragglers=%d malicious_events=%d",
+		r.Seed,
 		r.NodeCount,
 		r.RoundsCompleted,
 		r.RoundsRequested,
@@ -88,4 +590,54 @@ func FormatSummary(r Result) string {
 		r.StragglerEvents,
 		r.MaliciousNodeEvents,
 	)
+	if r.PoisonedRound > 0 {
+		summary += fmt.Sprintf(" poisoned_round=%d rollbacks=%d recovered_round=%d", r.PoisonedRound, r.Rollbacks, r.RecoveredRound)
+	}
+	if r.Network != nil {
+		networkTime, computeTime := r.Network.Totals()
+		summary += fmt.Sprintf(" network_seed=%d network=%s compute=%s", r.Network.Seed, networkTime, computeTime)
+		for _, phase := range r.Network.Phases {
+			summary += fmt.Sprintf(" %s=%s/%s", phase.Phase, phase.Network, phase.Compute)
+		}
+		summary += fmt.Sprintf(" retransmits=%d", r.Network.Retransmits)
+	}
+	if r.TieredRounds != nil {
+		summary += fmt.Sprintf(" regional_round=%s regional_timeouts=%d global_round=%s global_timeouts=%d",
+			r.TieredRounds.Regional.Mean(), r.TieredRounds.Regional.TimedOut, r.TieredRounds.Global.Mean(), r.TieredRounds.Global.TimedOut)
+	}
+	if r.Participants > 0 {
+		summary += fmt.Sprintf(" participants=%d", r.Participants)
+	}
+	if r.Gossip != nil {
+		summary += fmt.Sprintf(" gossip_fanout=%d gossip_messages=%d gossip_redundant=%d gossip_max_hops=%d", r.Gossip.Fanout, r.Gossip.Messages, r.Gossip.Redundant, r.Gossip.MaxHops)
+	}
+	if r.AdaptiveGossip != nil && len(r.AdaptiveGossip.Rounds) > 0 {
+		last := r.AdaptiveGossip.Rounds[len(r.AdaptiveGossip.Rounds)-1]
+		summary += fmt.Sprintf(" gossip_goal=%s gossip_fanout_next=%d gossip_propagation=%s", r.AdaptiveGossip.Goal, last.Next, last.Propagation)
+	}
+	if r.Training != nil {
+		summary += fmt.Sprintf(" initial_loss=%.6g final_loss=%.6g upload_bytes=%d", r.Training.InitialLoss, r.Training.FinalLoss, r.Training.UploadBytes)
+		for _, attack := range r.Training.Attacks {
+			summary += fmt.Sprintf(" attack_%s=%d/%d", attack.Strategy, attack.Included, attack.Poisoned)
+		}
+	}
+	if r.Evaluation != nil && len(r.Evaluation.Rounds) > 0 {
+		last := r.Evaluation.Rounds[len(r.Evaluation.Rounds)-1]
+		summary += fmt.Sprintf(" evaluated_rounds=%d accuracy=%.4g±%.2g evaluation_declined=%d", len(r.Evaluation.Rounds), last.Accuracy.Mean, last.Accuracy.Upper-last.Accuracy.Mean, r.Evaluation.Declined)
+	}
+	if r.Chaos != nil {
+		summary += fmt.Sprintf(
+			" failed_rounds=%d chaos_seed=%d messages=%d dropped=%d partitioned=%d duplicated=%d delayed=%d reordered=%d peer_down_rounds=%d",
+			r.FailedRounds,
+			r.Chaos.Seed,
+			r.Chaos.Messages,
+			r.Chaos.Dropped,
+			r.Chaos.Partitioned,
+			r.Chaos.Duplicated,
+			r.Chaos.Delayed,
+			r.Chaos.Reordered,
+			r.Chaos.PeerDownRounds,
+		)
+	}
+	return summary
 }
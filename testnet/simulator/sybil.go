@@ -0,0 +1,111 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+)
+
+// SybilWave registers a burst of fresh identities with the federation
+// partway through a run. The sybils submit no updates and vote to reject
+// every proposal from the round they join, trying to stall or flip its
+// outcome.
+type SybilWave struct {
+	// Round is the round the sybils register in and first vote.
+	Round int
+	// Nodes is how many sybil identities register.
+	Nodes int
+}
+
+// Validate checks that the wave registers at least one sybil in a real
+// round.
+func (w SybilWave) Validate() error {
+	if w.Round < 1 {
+		return fmt.Errorf("sybil wave round must be positive, got %d", w.Round)
+	}
+	if w.Nodes < 1 {
+		return fmt.Errorf("sybil wave must register at least one node, got %d", w.Nodes)
+	}
+	return nil
+}
+
+// SybilReport records what a sybil wave achieved.
+type SybilReport struct {
+	Nodes int
+	// RejectVotes counts the votes the sybils cast against proposals.
+	RejectVotes int
+	// RoundsCommitted counts the rounds that committed despite the wave,
+	// from the one it registered in.
+	RoundsCommitted int
+	// OnProbation counts the sybils still on probation when the run ended;
+	// zero when the federation has no probation.
+	OnProbation int
+}
+
+// sybilSim registers the wave's identities and casts their votes.
+type sybilSim struct {
+	wave     SybilWave
+	registry *federation.Registry
+	nodes    []string
+	report   SybilReport
+}
+
+func newSybilSim(wave SybilWave, registry *federation.Registry) (*sybilSim, error) {
+	if err := wave.Validate(); err != nil {
+		return nil, err
+	}
+	return &sybilSim{wave: wave, registry: registry, report: SybilReport{Nodes: wave.Nodes}}, nil
+}
+
+// join registers the sybils with f in the wave's round; it reports whether
+// they have joined by round.
+func (s *sybilSim) join(f *federation.Federation, round int) (bool, error) {
+	if round < s.wave.Round {
+		return false, nil
+	}
+	if s.nodes == nil {
+		for i := 0; i < s.wave.Nodes; i++ {
+			nodeID := fmt.Sprintf("%s-sybil-%03d", f.ID, i)
+			if _, err := s.registry.Bind(nodeID, []string{f.ID}); err != nil {
+				return false, err
+			}
+			s.nodes = append(s.nodes, nodeID)
+		}
+	}
+	return true, nil
+}
+
+func (s *sybilSim) isSybil(nodeID string) bool {
+	for _, sybil := range s.nodes {
+		if sybil == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+// vote casts every sybil's rejection of proposalID.
+func (s *sybilSim) vote(ctx context.Context, f *federation.Federation, proposalID string) error {
+	for _, nodeID := range s.nodes {
+		if err := f.CastVote(ctx, &consensus.Vote{NodeID: nodeID, ProposalID: proposalID, Approve: false, Timestamp: time.Now()}); err != nil {
+			return err
+		}
+		s.report.RejectVotes++
+	}
+	return nil
+}
+
+func (s *sybilSim) finalReport(f *federation.Federation) *SybilReport {
+	report := s.report
+	if f.Probation != nil {
+		for _, nodeID := range s.nodes {
+			if f.Probation.OnProbation(nodeID) {
+				report.OnProbation++
+			}
+		}
+	}
+	return &report
+}
@@ -0,0 +1,214 @@
+package simulator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/scheduler"
+)
+
+const defaultTierExchanges = 3
+
+// TieredRounds runs every round as a regional round in each region of the
+// network model, between the region's first node, as its aggregator, and
+// its other nodes, followed by a global round between the aggregator
+// region's aggregator and the other regions' aggregators. Each round must
+// reach a two-thirds quorum of its participants before its timeout.
+type TieredRounds struct {
+	// FlatTimeout, when positive, times out every regional and global
+	// round after it, as a deployment with one global timeout does.
+	// Otherwise each round's timeout is derived under Policy from the RTTs
+	// measured in earlier rounds.
+	FlatTimeout time.Duration `json:"flat_timeout,omitempty"`
+	// Policy derives the timeouts; the zero value is
+	// scheduler.DefaultLatencyPolicy.
+	Policy scheduler.LatencyPolicy `json:"policy"`
+	// Exchanges is how many sequential message round trips a round takes,
+	// such as propose, vote, and commit; default 3.
+	Exchanges int `json:"exchanges,omitempty"`
+	// Unresponsive is the fraction of each region's nodes, not counting
+	// its aggregator, that never answer, so regional rounds last until
+	// their timeout rather than until their last node answers.
+	Unresponsive float64 `json:"unresponsive,omitempty"`
+}
+
+// Validate checks the timeout, policy, and fractions.
+func (t TieredRounds) Validate() error {
+	if t.FlatTimeout < 0 {
+		return fmt.Errorf("tiered rounds: flat timeout must not be negative, got %s", t.FlatTimeout)
+	}
+	if t.Policy != (scheduler.LatencyPolicy{}) {
+		if err := t.Policy.Validate(); err != nil {
+			return err
+		}
+	}
+	if t.Exchanges < 0 {
+		return fmt.Errorf("tiered rounds: exchanges must not be negative, got %d", t.Exchanges)
+	}
+	if t.Unresponsive < 0 || t.Unresponsive >= 1 {
+		return fmt.Errorf("tiered rounds: unresponsive fraction must be in [0, 1), got %f", t.Unresponsive)
+	}
+	return nil
+}
+
+// TierReport records how one tier's rounds fared against their timeouts.
+type TierReport struct {
+	Rounds int `json:"rounds"`
+	// TimedOut counts rounds that missed quorum by their timeout.
+	TimedOut int `json:"timed_out"`
+	// Elapsed sums the rounds' durations, timed out or not.
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// Mean returns the average round duration.
+func (r TierReport) Mean() time.Duration {
+	if r.Rounds == 0 {
+		return 0
+	}
+	return r.Elapsed / time.Duration(r.Rounds)
+}
+
+// TieredRoundsReport records the regional and global rounds. Regional
+// counts one round per region per simulated round.
+type TieredRoundsReport struct {
+	Regional TierReport `json:"regional"`
+	Global   TierReport `json:"global"`
+	// Budgets are the budgets derived from the RTTs measured over the run,
+	// reported even under a flat timeout.
+	Budgets []scheduler.TierBudget `json:"budgets"`
+}
+
+// tierSim times regional and global rounds over its own sample of the
+// network model, so enabling it does not shift the network report.
+type tierSim struct {
+	cfg        TieredRounds
+	network    *networkSim
+	topology   *scheduler.Topology
+	regions    []string
+	members    map[string][]int
+	aggregator string
+	report     TieredRoundsReport
+}
+
+func newTierSim(cfg TieredRounds, model *NetworkModel, nodeCount int, seed int64) (*tierSim, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if model == nil || len(model.Regions) < 2 {
+		return nil, fmt.Errorf("tiered rounds need a network model with at least two regions")
+	}
+	if cfg.Policy == (scheduler.LatencyPolicy{}) {
+		cfg.Policy = scheduler.DefaultLatencyPolicy()
+	}
+	if cfg.Exchanges == 0 {
+		cfg.Exchanges = defaultTierExchanges
+	}
+
+	sampled := *model
+	sampled.Seed = seed
+	t := &tierSim{cfg: cfg, network: newNetworkSim(sampled, seed), members: make(map[string][]int)}
+	assignments := make(map[string]string, nodeCount)
+	for n := 0; n < nodeCount; n++ {
+		region := t.network.region(n)
+		if len(t.members[region]) == 0 {
+			t.regions = append(t.regions, region)
+		}
+		t.members[region] = append(t.members[region], n)
+		assignments[nodeName(n)] = region
+	}
+	t.aggregator = t.network.model.AggregatorRegion
+	if len(t.members[t.aggregator]) == 0 {
+		return nil, fmt.Errorf("tiered rounds: aggregator region %q has no nodes", t.aggregator)
+	}
+	topology, err := scheduler.NewTopology(cfg.Policy, assignments)
+	if err != nil {
+		return nil, err
+	}
+	t.topology = topology
+	return t, nil
+}
+
+// round runs one regional round per region and then the global round, and
+// returns how long they took and whether the global round reached quorum.
+func (t *tierSim) round() (time.Duration, bool) {
+	var regional time.Duration
+	var regionAggregators []int
+	for _, region := range t.regions {
+		members := t.members[region]
+		regionAggregators = append(regionAggregators, members[0])
+		silent := int(t.cfg.Unresponsive * float64(len(members)-1))
+		elapsed, _ := t.tierRound(&t.report.Regional, t.timeout(scheduler.TierRegional, region), members[0], members[1:], silent)
+		regional = max(regional, elapsed)
+	}
+
+	var root int
+	var others []int
+	for i, region := range t.regions {
+		if region == t.aggregator {
+			root = regionAggregators[i]
+		} else {
+			others = append(others, regionAggregators[i])
+		}
+	}
+	global, ok := t.tierRound(&t.report.Global, t.timeout(scheduler.TierGlobal, ""), root, others, 0)
+	return regional + global, ok
+}
+
+// timeout is the round timeout for a tier: the flat timeout if set, the
+// derived budget otherwise.
+func (t *tierSim) timeout(tier scheduler.Tier, region string) time.Duration {
+	if t.cfg.FlatTimeout > 0 {
+		return t.cfg.FlatTimeout
+	}
+	return t.topology.Derive(tier, region).Budget
+}
+
+// tierRound runs one round from leader to members, the last silent of
+// which never answer, and records it in report. A member answers after
+// Exchanges round trips; each one is measured into the topology. The round
+// ends when every member has answered or at its timeout, and fails if
+// fewer than two-thirds of the participants, the leader included, had
+// answered by then.
+func (t *tierSim) tierRound(report *TierReport, timeout time.Duration, leader int, members []int, silent int) (time.Duration, bool) {
+	participants := len(members) + 1
+	quorum := min(2*participants/3+1, participants)
+	answered := 1
+	var last time.Duration
+	for i, member := range members {
+		if i >= len(members)-silent {
+			last = timeout
+			continue
+		}
+		from, to := t.network.region(leader), t.network.region(member)
+		var finish time.Duration
+		for exchange := 0; exchange < t.cfg.Exchanges; exchange++ {
+			rtt := t.network.transfer(from, to, t.network.model.VoteBytes, t.network.model.DownlinkBytesPerSec) +
+				t.network.transfer(to, from, t.network.model.VoteBytes, t.network.model.UplinkBytesPerSec)
+			t.topology.ObserveRTT(nodeName(leader), nodeName(member), rtt)
+			finish += rtt
+		}
+		if finish > timeout {
+			last = timeout
+			continue
+		}
+		answered++
+		last = max(last, finish)
+	}
+
+	ok := answered >= quorum
+	report.Rounds++
+	report.Elapsed += last
+	if !ok {
+		report.TimedOut++
+	}
+	return last, ok
+}
+
+func tieredRoundsReport(tiers *tierSim) *TieredRoundsReport {
+	if tiers == nil {
+		return nil
+	}
+	report := tiers.report
+	report.Budgets = tiers.topology.Budgets()
+	return &report
+}
@@ -0,0 +1,623 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/consensus"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/convergence"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/events"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/federation"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/modeldist"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/privacy"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/provenance"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/simrand"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/upload"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+)
+
+// QuadraticModel trains a toy objective so update encodings can be compared
+// end to end. Node n holds a target c_n near a shared one and a sample count
+// s_n; the global objective is the sample-weighted mean of
+// 0.5*||x - c_n||^2, minimized at the sample-weighted mean of the targets.
+type QuadraticModel struct {
+	Dim          int     `json:"dim"`
+	LearningRate float64 `json:"learning_rate"`
+	// Sparsity is the fraction of coordinates each node uploads per round
+	// as a top-k sparse update with error feedback. Zero or one uploads
+	// dense updates.
+	Sparsity float64 `json:"sparsity"`
+	// ClipNorm, when positive, makes every node clip its step to this L2
+	// norm and add SGP-001 Gaussian noise calibrated to it before upload.
+	// Noise comes from the run's seeded stream, not crypto/rand.
+	ClipNorm float64 `json:"clip_norm"`
+	// ByzantineNodes makes the first nodes upload their step scaled by
+	// byzantineScale, and enables the aggregator's norm outlier filter so
+	// their updates are excluded.
+	ByzantineNodes int `json:"byzantine_nodes"`
+	// Attacks assigns attack strategies to nodes by index, in place of
+	// honest training; see AttackStrategy. Like ByzantineNodes they enable
+	// the aggregator's norm outlier filter.
+	Attacks []AttackAssignment `json:"attacks,omitempty"`
+	// Epochs is the number of local gradient steps each node takes per
+	// round. Zero takes one.
+	Epochs int `json:"epochs"`
+	// CurvatureSpread makes node data non-IID in scale as well as in
+	// target: node n's objective is multiplied by a curvature drawn
+	// uniformly from [1, 1+CurvatureSpread], so a learning rate that suits
+	// one shard overshoots on another. Zero gives every node curvature one.
+	CurvatureSpread float64 `json:"curvature_spread"`
+	// Advisor, when set, recommends each round's learning rate and epochs
+	// from the dispersion of the node gradients and the trend of the
+	// global gradient norm. LearningRate and Epochs are its static values.
+	Advisor convergence.AdvisorPolicy `json:"-"`
+}
+
+// byzantineScale is how far a Byzantine node overshoots its honest step.
+const byzantineScale = 100
+
+// Validate checks the model parameters.
+func (m *QuadraticModel) Validate() error {
+	if m.Dim <= 0 {
+		return fmt.Errorf("quadratic model needs a positive dimension, got %d", m.Dim)
+	}
+	if m.LearningRate <= 0 || m.LearningRate > 1 {
+		return fmt.Errorf("learning rate must be in (0, 1], got %f", m.LearningRate)
+	}
+	if m.Sparsity < 0 || m.Sparsity > 1 {
+		return fmt.Errorf("sparsity must be in [0, 1], got %f", m.Sparsity)
+	}
+	if m.ClipNorm < 0 {
+		return fmt.Errorf("clip norm must not be negative, got %f", m.ClipNorm)
+	}
+	if m.ByzantineNodes < 0 {
+		return fmt.Errorf("byzantine nodes must not be negative, got %d", m.ByzantineNodes)
+	}
+	if m.Epochs < 0 {
+		return fmt.Errorf("epochs must not be negative, got %d", m.Epochs)
+	}
+	if m.CurvatureSpread < 0 {
+		return fmt.Errorf("curvature spread must not be negative, got %f", m.CurvatureSpread)
+	}
+	for i := range m.Attacks {
+		if err := m.Attacks[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TrainingReport records how far training got. Losses are excess over the
+// optimum, so zero means converged.
+type TrainingReport struct {
+	InitialLoss float64
+	FinalLoss   float64
+	UploadBytes int64
+	// Attacks breaks down, per strategy, how many poisoned updates the
+	// aggregator included.
+	Attacks []AttackOutcome `json:",omitempty"`
+	// DivergenceAlerts counts rounds whose loss rose over the previous
+	// round's.
+	DivergenceAlerts int
+	// Hyperparameters records the learning rate and epochs every round
+	// trained with, and what the advisor observed before choosing them.
+	Hyperparameters []convergence.Recommendation `json:",omitempty"`
+}
+
+// targetSpread is the standard deviation of node targets around a shared
+// one: node data is similar but not identical.
+const targetSpread = 0.1
+
+// trainingSim runs the quadratic model through the batch aggregator.
+type trainingSim struct {
+	model      QuadraticModel
+	aggregator *batch.Aggregator
+	targets    [][]float64
+	samples    []int
+	// curvatures scales each node's objective when
+	// QuadraticModel.CurvatureSpread is set; curvature is their
+	// sample-weighted mean.
+	curvatures  []float64
+	curvature   float64
+	sparsifiers []*upload.Sparsifier
+	privacy     *privacy.DifferentialPrivacy
+	optimum     []float64
+	weights     []float64
+	base        []byte
+	report      TrainingReport
+	// advisor recommends each round's hyperparameters from what detector
+	// observes of the previous one.
+	advisor  *convergence.HyperparameterAdvisor
+	detector *convergence.Detector
+	// federation, when set, carries updates and commits; see
+	// Config.Federations.
+	federation *federation.Federation
+	// verification, when set, runs every update past a verifier committee
+	// before aggregation; see Config.VerificationCommittee.
+	verification *verificationSim
+	// provenance receives the ingest events the simulator emits for updates
+	// it aggregates directly; see Config.Provenance.
+	provenance provenance.Sink
+	// traced follows the federation's round lifecycle into the provenance
+	// sink; see setProvenance.
+	traced *events.Subscription
+	// attacks, when set, computes the uploads of nodes assigned a strategy.
+	attacks *attackSim
+	// sybils, when set, registers a sybil wave with the federation; see
+	// Config.SybilWave.
+	sybils *sybilSim
+	// rejoin, when set, takes nodes offline for a round and replays their
+	// return; see Config.IslandRejoin.
+	rejoin *rejoinSim
+}
+
+// newTrainingSim draws node data from rng's "training" stream, curvatures
+// from its "curvature" stream, privacy noise from its "privacy" stream, and
+// attack noise from its "attacks" stream.
+func newTrainingSim(model QuadraticModel, nodeCount int, rng *simrand.SeededRand) (*trainingSim, error) {
+	data := rng.Derive("training")
+	outlierFactor := -1.0
+	if model.ByzantineNodes > 0 || len(model.Attacks) > 0 {
+		outlierFactor = 0
+	}
+	t := &trainingSim{
+		model:      model,
+		aggregator: batch.NewAggregator(&batch.Config{OutlierFactor: outlierFactor}),
+		targets:    make([][]float64, nodeCount),
+		samples:    make([]int, nodeCount),
+		optimum:    make([]float64, model.Dim),
+		weights:    make([]float64, model.Dim),
+		curvature:  1,
+	}
+
+	shared := make([]float64, model.Dim)
+	for j := range shared {
+		shared[j] = data.NormFloat64()
+	}
+	total := 0
+	for n := range t.targets {
+		t.targets[n] = make([]float64, model.Dim)
+		for j := range t.targets[n] {
+			t.targets[n][j] = shared[j] + targetSpread*data.NormFloat64()
+		}
+		t.samples[n] = 50 + data.Intn(200)
+		total += t.samples[n]
+	}
+	if model.CurvatureSpread > 0 {
+		curvature := rng.Derive("curvature")
+		t.curvatures = make([]float64, nodeCount)
+		t.curvature = 0
+		for n := range t.curvatures {
+			t.curvatures[n] = 1 + model.CurvatureSpread*curvature.Float64()
+			t.curvature += float64(t.samples[n]) / float64(total) * t.curvatures[n]
+		}
+	}
+	for n, target := range t.targets {
+		share := float64(t.samples[n]) / float64(total)
+		if t.curvatures != nil {
+			share *= t.curvatures[n] / t.curvature
+		}
+		for j, c := range target {
+			t.optimum[j] += share * c
+		}
+	}
+
+	if model.Sparsity > 0 && model.Sparsity < 1 {
+		t.sparsifiers = make([]*upload.Sparsifier, nodeCount)
+		for n := range t.sparsifiers {
+			sparsifier, err := upload.NewSparsifier(model.Sparsity)
+			if err != nil {
+				return nil, err
+			}
+			t.sparsifiers[n] = sparsifier
+		}
+	}
+	if len(model.Attacks) > 0 {
+		attacks, err := newAttackSim(model.Attacks, t, rng.Derive("attacks"))
+		if err != nil {
+			return nil, err
+		}
+		t.attacks = attacks
+	}
+	if model.ClipNorm > 0 {
+		cfg := privacy.NewSGP001Config()
+		cfg.L2Sensitivity = model.ClipNorm
+		t.privacy = privacy.NewDifferentialPrivacy(cfg)
+		t.privacy.SetNoiseSource(rng.Derive("privacy"))
+	}
+	// Every update carries a training statement bound to the global model
+	// distributed at the start of its round.
+	t.aggregator.SetBaseModelResolver(func(int) (string, bool) {
+		return protocol.WeightsDigest(t.base), true
+	})
+	epochs := model.Epochs
+	if epochs == 0 {
+		epochs = 1
+	}
+	advisor, err := convergence.NewHyperparameterAdvisor(convergence.Hyperparameters{LearningRate: model.LearningRate, Epochs: epochs}, model.Advisor)
+	if err != nil {
+		return nil, err
+	}
+	t.advisor = advisor
+	t.detector = convergence.NewDetector(0, 0, 2, 2)
+	t.report.InitialLoss = t.loss()
+	t.report.FinalLoss = t.report.InitialLoss
+	t.observe(0)
+	return t, nil
+}
+
+// joinFederation binds every node to federation id of registry and routes
+// the aggregator's rounds through it. When the federation puts new members
+// on probation, the simulated nodes are its vouched-for founders and
+// graduate at once; only nodes joining later serve probation.
+func (t *trainingSim) joinFederation(registry *federation.Registry, id string) error {
+	f, err := registry.Get(id)
+	if err != nil {
+		return err
+	}
+	t.federation = f
+	t.aggregator = f.Aggregator
+	t.aggregator.SetBaseModelResolver(func(int) (string, bool) {
+		return protocol.WeightsDigest(t.base), true
+	})
+	for n := range t.targets {
+		if _, err := registry.Bind(t.nodeID(n), []string{id}); err != nil {
+			return err
+		}
+		if f.Probation != nil {
+			f.Probation.Graduate(t.nodeID(n))
+		}
+	}
+	return nil
+}
+
+// setProvenance sends the lifecycle events of every update to sink. With a
+// federation, its proposal and commit events reach sink through an event
+// bus; closeProvenance waits for them to arrive.
+func (t *trainingSim) setProvenance(sink provenance.Sink) {
+	if t.federation != nil {
+		bus := events.NewBus()
+		t.federation.SetProvenance(sink)
+		t.federation.SetEvents(bus)
+		t.traced = provenance.Subscribe(bus, sink)
+		return
+	}
+	t.provenance = sink
+	t.aggregator.SetProvenance(sink)
+}
+
+// closeProvenance delivers the lifecycle events still in flight to the
+// provenance sink.
+func (t *trainingSim) closeProvenance() {
+	if t.traced != nil {
+		t.traced.Close()
+	}
+}
+
+// verifyWith runs every update past a committee of committee verifiers,
+// the first lazy of them lazy, spot checked at spotCheckRate with decoys
+// drawn from rng: on the federation's peer table when there is a
+// federation, otherwise on a verifier of the simulator's own. Call it after
+// setProvenance.
+func (t *trainingSim) verifyWith(committee, lazy int, spotCheckRate float64, rng *simrand.SeededRand, timeout time.Duration) error {
+	var verifier *p2p.Verifier
+	if t.federation != nil {
+		verifier = t.federation.Peers
+	} else {
+		verifier = p2p.NewVerifier(aggregatorID, committee, timeout)
+		verifier.SetProvenance(t.provenance)
+	}
+	verification, err := newVerificationSim(verifier, committee, lazy, spotCheckRate, rng)
+	if err != nil {
+		return err
+	}
+	t.verification = verification
+	return nil
+}
+
+// nodeID names node n, prefixed with its federation when there is one.
+func (t *trainingSim) nodeID(n int) string {
+	if t.federation != nil {
+		return fmt.Sprintf("%s-node-%03d", t.federation.ID, n)
+	}
+	return fmt.Sprintf("node-%03d", n)
+}
+
+// loss is the objective's excess over its optimum: 0.5*H*||x - x*||^2,
+// where H is the mean curvature, one unless CurvatureSpread is set.
+func (t *trainingSim) loss() float64 {
+	sum := 0.0
+	for j, w := range t.weights {
+		d := w - t.optimum[j]
+		sum += d * d
+	}
+	if t.curvatures != nil {
+		sum *= t.curvature
+	}
+	return sum / 2
+}
+
+// curvatureOf is node n's curvature.
+func (t *trainingSim) curvatureOf(n int) float64 {
+	if t.curvatures == nil {
+		return 1
+	}
+	return t.curvatures[n]
+}
+
+// observe feeds the detector the loss and gradients at the model round
+// produced, counts a divergence alert if the loss rose, and has the advisor
+// recommend the next round's hyperparameters.
+func (t *trainingSim) observe(round int) {
+	t.detector.RecordLoss(t.loss())
+	if t.detector.Diverging() {
+		t.report.DivergenceAlerts++
+	}
+	grads := make([][]float64, len(t.targets))
+	for n, target := range t.targets {
+		h := t.curvatureOf(n)
+		grads[n] = make([]float64, len(t.weights))
+		for j, w := range t.weights {
+			grads[n][j] = h * (w - target[j])
+		}
+	}
+	norm := 0.0
+	for j, w := range t.weights {
+		d := w - t.optimum[j]
+		norm += d * d
+	}
+	t.detector.RecordGradient(t.curvature * math.Sqrt(norm))
+	t.detector.RecordNodeGradients(grads)
+	t.advisor.Observe(t.detector.Observation(round))
+}
+
+// round takes one gradient step on every participant, or on every node when
+// participants is nil, and applies the aggregate.
+func (t *trainingSim) round(ctx context.Context, round int, participants []int) error {
+	if participants == nil {
+		participants = make([]int, len(t.targets))
+		for n := range participants {
+			participants[n] = n
+		}
+	}
+	t.base = batch.Update{Weights: t.weights}.Bytes()
+	task := protocol.TrainingTask{Round: round, GlobalWeights: t.base}
+	t.advisor.Stamp(&task)
+	updates := make([]batch.Update, len(participants))
+	for i, n := range participants {
+		step, err := t.step(task, n)
+		if err != nil {
+			return err
+		}
+		update := batch.Update{NodeID: t.nodeID(n), SampleCount: t.samples[n]}
+		if t.sparsifiers == nil {
+			update.Weights = step
+			t.report.UploadBytes += int64(protocol.FullUpdateBytes(len(step)))
+		} else {
+			sparse, err := t.sparsifiers[n].Sparsify(step)
+			if err != nil {
+				return err
+			}
+			update.Sparse = sparse
+			t.report.UploadBytes += int64(protocol.SparseUpdateBytes(len(sparse.Indices)))
+		}
+		update.Statement = protocol.NewTrainingStatement(update.NodeID, task, update.Bytes())
+		updates[i] = update
+	}
+
+	if t.federation != nil {
+		var rejoined map[int]batch.Update
+		if t.rejoin != nil {
+			rejoined = make(map[int]batch.Update)
+			for _, n := range participants {
+				if !t.rejoin.offline(n, round) {
+					continue
+				}
+				live, err := t.liveUpdate(task, n)
+				if err != nil {
+					return err
+				}
+				rejoined[n] = live
+			}
+		}
+		return t.federatedRound(ctx, round, participants, updates, rejoined)
+	}
+	for _, update := range updates {
+		t.emitIngest(round, update)
+	}
+	if err := t.verify(ctx, round, updates); err != nil {
+		return err
+	}
+	result, err := t.aggregator.Aggregate(ctx, round, updates)
+	if err != nil {
+		return err
+	}
+	t.apply(result)
+	return nil
+}
+
+// step is node n's upload for task: its strategy's, when it attacks, or
+// otherwise its honest local steps at the task's learning rate, noised
+// under ClipNorm and scaled if it is one of the ByzantineNodes.
+func (t *trainingSim) step(task protocol.TrainingTask, n int) ([]float64, error) {
+	if t.attacks != nil {
+		if step, ok := t.attacks.step(n, t.nodeID(n), task.Round, t.weights); ok {
+			return step, nil
+		}
+	}
+	rate := task.LearningRate * t.curvatureOf(n)
+	step := make([]float64, t.model.Dim)
+	for epoch := 0; epoch < task.Epochs; epoch++ {
+		for j, c := range t.targets[n] {
+			step[j] -= rate * (t.weights[j] + step[j] - c)
+		}
+	}
+	if t.privacy != nil {
+		noisy, err := t.privacy.AddNoiseToGradients(step, t.model.ClipNorm)
+		if err != nil {
+			return nil, err
+		}
+		step = noisy
+	}
+	if n < t.model.ByzantineNodes {
+		for j := range step {
+			step[j] *= byzantineScale
+		}
+	}
+	return step, nil
+}
+
+// emitIngest reports update's ingest as federation.Submit would.
+func (t *trainingSim) emitIngest(round int, update batch.Update) {
+	if t.provenance == nil {
+		return
+	}
+	digest := protocol.UpdateDigest(update.Bytes())
+	event := provenance.Event{
+		UpdateID: provenance.UpdateID(update.NodeID, round, digest),
+		NodeID:   update.NodeID,
+		Round:    round,
+		Kind:     provenance.KindReceived,
+		Source:   "simulator",
+	}
+	t.provenance.Emit(event)
+	if update.Statement.Check(update.NodeID, round, digest) == nil {
+		event.Kind = provenance.KindSignatureVerified
+		t.provenance.Emit(event)
+	}
+}
+
+func (t *trainingSim) verify(ctx context.Context, round int, updates []batch.Update) error {
+	if t.verification == nil {
+		return nil
+	}
+	return t.verification.verify(ctx, round, updates)
+}
+
+func (t *trainingSim) apply(result *batch.AggregationResult) {
+	for j, delta := range result.Weights {
+		t.weights[j] += delta
+	}
+	t.report.FinalLoss = t.loss()
+	t.observe(result.Manifest.Round)
+	if t.attacks != nil {
+		t.attacks.score(result.Manifest)
+	}
+}
+
+// federatedRound submits updates to the federation as its members would,
+// aggregates them, and commits the new global model once every honest
+// member has approved it and any sybils have rejected it. A participant
+// with an update in rejoined trained updates[i] offline: it reaches the
+// federation through the node's island cache, followed by the live one.
+func (t *trainingSim) federatedRound(ctx context.Context, round int, participants []int, updates []batch.Update, rejoined map[int]batch.Update) error {
+	f := t.federation
+	sybilsJoined := false
+	if t.sybils != nil {
+		joined, err := t.sybils.join(f, round)
+		if err != nil {
+			return err
+		}
+		sybilsJoined = joined
+	}
+	for i, update := range updates {
+		n := participants[i]
+		message := &protocol.ModelUpdate{
+			NodeID:       update.NodeID,
+			Round:        round,
+			Sparse:       update.Sparse,
+			Statement:    update.Statement,
+			Timestamp:    time.Now(),
+			Metrics:      protocol.Metrics{Loss: t.nodeLoss(n), Samples: update.SampleCount},
+			FederationID: f.ID,
+		}
+		if update.Sparse == nil {
+			message.Weights = update.Bytes()
+		}
+		if live, ok := rejoined[n]; ok {
+			liveMessage := *message
+			liveMessage.Weights = live.Bytes()
+			liveMessage.Statement = live.Statement
+			// The node kept training after caching its offline update.
+			liveMessage.Timestamp = message.Timestamp.Add(time.Millisecond)
+			if err := t.rejoin.submit(ctx, f, message, &liveMessage); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := f.Submit(message); err != nil {
+			return err
+		}
+	}
+	if err := t.verify(ctx, round, updates); err != nil {
+		return err
+	}
+	result, err := f.Aggregate(ctx, round)
+	if err != nil {
+		return err
+	}
+	if t.rejoin != nil {
+		t.rejoin.score(result.Manifest)
+	}
+	t.apply(result)
+
+	weights := batch.Update{Weights: t.weights}.Bytes()
+	proposal := &consensus.ModelProposal{Round: round, Weights: weights, ProposerID: aggregatorID, Timestamp: time.Now()}
+	proposal.AttachManifest(result.Manifest)
+	proposalID, err := f.Coordinator.ProposeModel(ctx, proposal)
+	if err != nil {
+		return err
+	}
+	defer f.Coordinator.Reset()
+	var members []string
+	for _, nodeID := range f.Members() {
+		if t.sybils != nil && t.sybils.isSybil(nodeID) {
+			continue
+		}
+		members = append(members, nodeID)
+		if err := f.CastVote(ctx, &consensus.Vote{NodeID: nodeID, ProposalID: proposalID, Approve: true, Timestamp: time.Now()}); err != nil {
+			return err
+		}
+	}
+	if sybilsJoined {
+		if err := t.sybils.vote(ctx, f, proposalID); err != nil {
+			return err
+		}
+	}
+	if err := f.Coordinator.CommitModel(ctx, proposalID); err != nil {
+		return err
+	}
+	if sybilsJoined {
+		t.sybils.report.RoundsCommitted++
+	}
+	membership, err := f.Coordinator.GetRoundMembership(proposalID)
+	if err != nil {
+		return err
+	}
+	cert := modeldist.CommitCertificate{
+		Round:          round,
+		ProposalID:     proposalID,
+		ModelDigest:    protocol.WeightsDigest(weights),
+		QuorumSize:     membership.QuorumSize,
+		Approvals:      members,
+		ManifestDigest: result.Manifest.Digest(),
+	}
+	if _, err := f.ModelStore.Commit(round, weights, len(members), map[string]float64{"loss": t.report.FinalLoss}, cert); err != nil {
+		return err
+	}
+	return f.ModelStore.AttachManifest(result.Manifest)
+}
+
+// nodeLoss is node n's local objective at the current global model.
+func (t *trainingSim) nodeLoss(n int) float64 {
+	sum := 0.0
+	for j, w := range t.weights {
+		d := w - t.targets[n][j]
+		sum += d * d
+	}
+	return t.curvatureOf(n) * sum / 2
+}
@@ -0,0 +1,389 @@
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/chaos"
+)
+
+// defaultNodeCount is the node count of a Config that sets none.
+const defaultNodeCount = 50
+
+// Rules a scenario Violation breaks.
+const (
+	// RuleUniqueIDs: no node, peer, region, or link is listed twice where
+	// it can appear once.
+	RuleUniqueIDs = "unique_ids"
+	// RuleByzantineRatio: Byzantine and attacking nodes are real nodes,
+	// flagged once each, and leave at least one honest node.
+	RuleByzantineRatio = "byzantine_ratio"
+	// RuleAttackRegistry: attack strategies and their parameters are ones
+	// the simulator implements.
+	RuleAttackRegistry = "attack_registry"
+	// RuleShape: the model has a dimension and per-node vectors have at
+	// most one entry per node.
+	RuleShape = "shape"
+	// RuleRequiredFields: settings that sign, verify, or certify updates
+	// come with the parts they depend on.
+	RuleRequiredFields = "required_fields"
+	// RuleMembership: chaos events name nodes of the run, and partitions
+	// and churn never count more nodes than there are.
+	RuleMembership = "membership"
+	// RuleRange: a part's own range checks, reported at the part.
+	RuleRange = "range"
+)
+
+// ErrInvalidScenario is wrapped by every ScenarioError.
+var ErrInvalidScenario = errors.New("invalid scenario")
+
+// Violation is one inconsistency in a scenario. Path starts at the Config
+// field the offending part is set in and continues as a JSON path into the
+// file that part is loaded from, such as Training.attacks[2].nodes[0] or
+// Chaos.partitions[0].groups[1][3].
+type Violation struct {
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s (%s)", v.Path, v.Message, v.Rule)
+}
+
+// ScenarioError lists every violation Validate found.
+type ScenarioError struct {
+	Violations []Violation
+}
+
+func (e *ScenarioError) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		lines[i] = violation.String()
+	}
+	return fmt.Sprintf("%s: %d violations:\n%s", ErrInvalidScenario, len(e.Violations), strings.Join(lines, "\n"))
+}
+
+func (e *ScenarioError) Unwrap() error {
+	return ErrInvalidScenario
+}
+
+// Validate checks a scenario's parts against each other and against its
+// node count before it is run, and returns a *ScenarioError listing every
+// violation, in path order, or nil. RunContext refuses a scenario that
+// fails it.
+func Validate(cfg Config) error {
+	v := &scenarioValidator{nodes: cfg.NodeCount}
+	if v.nodes <= 0 {
+		v.nodes = defaultNodeCount
+	}
+	if cfg.Training != nil {
+		v.training(cfg.Training)
+	}
+	if cfg.Chaos != nil {
+		v.chaos(cfg.Chaos)
+	}
+	if cfg.Network != nil {
+		v.network(cfg.Network)
+	}
+	v.requiredFields(cfg)
+	if len(v.violations) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.violations, func(i, j int) bool { return v.violations[i].Path < v.violations[j].Path })
+	return &ScenarioError{Violations: v.violations}
+}
+
+// ReadScenario sets cfg's training scenario, chaos plan, and network model
+// from the files at the given paths, skipping empty ones. Unlike the
+// loaders it only decodes them, so Validate can report every violation
+// with its path rather than the first one without.
+func ReadScenario(cfg Config, trainingPath, chaosPath, networkPath string) (Config, error) {
+	for _, file := range []struct {
+		path string
+		into func() interface{}
+	}{
+		{trainingPath, func() interface{} { cfg.Training = &QuadraticModel{}; return cfg.Training }},
+		{chaosPath, func() interface{} { cfg.Chaos = &chaos.Plan{}; return cfg.Chaos }},
+		{networkPath, func() interface{} { cfg.Network = &NetworkModel{}; return cfg.Network }},
+	} {
+		if file.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(file.path) // #nosec G304 -- operator-supplied scenario path
+		if err != nil {
+			return cfg, fmt.Errorf("read scenario file: %w", err)
+		}
+		if err := json.Unmarshal(data, file.into()); err != nil {
+			return cfg, fmt.Errorf("decode %s: %w", file.path, err)
+		}
+	}
+	return cfg, nil
+}
+
+type scenarioValidator struct {
+	nodes      int
+	violations []Violation
+}
+
+func (v *scenarioValidator) report(path, rule, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Path: path, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// count reports how many violations have been found, so a part's own
+// Validate runs only when the specific rules passed it.
+func (v *scenarioValidator) count() int {
+	return len(v.violations)
+}
+
+func (v *scenarioValidator) training(m *QuadraticModel) {
+	before := v.count()
+	if m.Dim <= 0 {
+		v.report("Training.dim", RuleShape, "the model needs a positive dimension, got %d", m.Dim)
+	}
+	if m.ByzantineNodes < 0 || m.ByzantineNodes > v.nodes {
+		v.report("Training.byzantine_nodes", RuleByzantineRatio, "%d Byzantine nodes is not between 0 and the %d nodes of the run", m.ByzantineNodes, v.nodes)
+	}
+
+	attacked := make(map[int]string)
+	for i, assignment := range m.Attacks {
+		path := fmt.Sprintf("Training.attacks[%d]", i)
+		defaults, known := attackParams[assignment.Strategy]
+		if !known {
+			v.report(path+".strategy", RuleAttackRegistry, "unknown attack strategy %q", assignment.Strategy)
+		}
+		for _, name := range sortedParams(assignment.Params) {
+			if _, ok := defaults[name]; known && !ok {
+				v.report(path+".params."+name, RuleAttackRegistry, "%s has no parameter %q", assignment.Strategy, name)
+			} else if value := assignment.Params[name]; math.IsNaN(value) || math.IsInf(value, 0) {
+				v.report(path+".params."+name, RuleAttackRegistry, "parameter must be finite")
+			}
+		}
+		if len(assignment.Nodes) == 0 {
+			v.report(path+".nodes", RuleAttackRegistry, "%s assigns no nodes", assignment.Strategy)
+		}
+		for j, node := range assignment.Nodes {
+			nodePath := fmt.Sprintf("%s.nodes[%d]", path, j)
+			switch {
+			case node < 0 || node >= v.nodes:
+				v.report(nodePath, RuleByzantineRatio, "node %d is not one of the %d nodes of the run", node, v.nodes)
+			case attacked[node] != "":
+				v.report(nodePath, RuleUniqueIDs, "node %d is already assigned at %s", node, attacked[node])
+			case node < m.ByzantineNodes:
+				v.report(nodePath, RuleByzantineRatio, "node %d is already one of the %d byzantine_nodes", node, m.ByzantineNodes)
+			default:
+				attacked[node] = nodePath
+			}
+		}
+	}
+	if flagged := max(m.ByzantineNodes, 0) + len(attacked); flagged >= v.nodes && v.count() == before {
+		v.report("Training", RuleByzantineRatio, "all %d nodes are Byzantine or attacking; none trains honestly", v.nodes)
+	}
+	if v.count() == before {
+		if err := m.Validate(); err != nil {
+			v.report("Training", RuleRange, "%v", err)
+		}
+	}
+}
+
+// chaos checks that every peer a plan names is a node of the run or the
+// aggregator, that a partition lists each at most once, and that churn
+// only removes nodes that are in the run and only returns nodes that left.
+func (v *scenarioValidator) chaos(plan *chaos.Plan) {
+	before := v.count()
+	known := func(peer string) bool {
+		if peer == aggregatorID {
+			return true
+		}
+		var n int
+		if _, err := fmt.Sscanf(peer, "node-%d", &n); err != nil || nodeName(n) != peer {
+			return false
+		}
+		return n >= 0 && n < v.nodes
+	}
+
+	for i, crash := range plan.Crashes {
+		if !known(crash.Peer) {
+			v.report(fmt.Sprintf("Chaos.crashes[%d].peer", i), RuleMembership, "%q is not a node of the %d-node run", crash.Peer, v.nodes)
+		}
+	}
+	skewed := make(map[string]string)
+	for i, skew := range plan.ClockSkew {
+		path := fmt.Sprintf("Chaos.clock_skew[%d].peer", i)
+		switch {
+		case !known(skew.Peer):
+			v.report(path, RuleMembership, "%q is not a node of the %d-node run", skew.Peer, v.nodes)
+		case skewed[skew.Peer] != "":
+			v.report(path, RuleUniqueIDs, "%s is already skewed at %s", skew.Peer, skewed[skew.Peer])
+		default:
+			skewed[skew.Peer] = path
+		}
+	}
+	for i, partition := range plan.Partitions {
+		grouped := make(map[string]string)
+		for g, group := range partition.Groups {
+			if len(group) == 0 {
+				v.report(fmt.Sprintf("Chaos.partitions[%d].groups[%d]", i, g), RuleMembership, "group has no nodes")
+			}
+			for k, peer := range group {
+				path := fmt.Sprintf("Chaos.partitions[%d].groups[%d][%d]", i, g, k)
+				switch {
+				case !known(peer):
+					v.report(path, RuleMembership, "%q is not a node of the %d-node run", peer, v.nodes)
+				case grouped[peer] != "":
+					v.report(path, RuleUniqueIDs, "%s is already partitioned at %s", peer, grouped[peer])
+				default:
+					grouped[peer] = path
+				}
+			}
+		}
+	}
+
+	// Replay churn in round order, as the injector applies it.
+	order := make([]int, len(plan.Churn))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return plan.Churn[order[a]].Round < plan.Churn[order[b]].Round })
+	left := make(map[string]bool)
+	for _, i := range order {
+		event := plan.Churn[i]
+		for k, peer := range event.Leave {
+			path := fmt.Sprintf("Chaos.churn[%d].leave[%d]", i, k)
+			switch {
+			case !known(peer):
+				v.report(path, RuleMembership, "%q is not a node of the %d-node run", peer, v.nodes)
+			case left[peer]:
+				v.report(path, RuleMembership, "%s leaves in round %d but has already left", peer, event.Round)
+			default:
+				left[peer] = true
+			}
+		}
+		if gone := len(left) - btoi(left[aggregatorID]); gone >= v.nodes {
+			v.report(fmt.Sprintf("Chaos.churn[%d]", i), RuleMembership, "every node has left by round %d", event.Round)
+		}
+		for k, peer := range event.Join {
+			path := fmt.Sprintf("Chaos.churn[%d].join[%d]", i, k)
+			switch {
+			case !known(peer):
+				v.report(path, RuleMembership, "%q is not a node of the %d-node run", peer, v.nodes)
+			case !left[peer]:
+				v.report(path, RuleMembership, "%s joins in round %d without having left", peer, event.Round)
+			default:
+				delete(left, peer)
+			}
+		}
+	}
+	if v.count() == before {
+		if err := plan.Validate(); err != nil {
+			v.report("Chaos", RuleRange, "%v", err)
+		}
+	}
+}
+
+func (v *scenarioValidator) network(model *NetworkModel) {
+	before := v.count()
+	regions := make(map[string]string)
+	for i, region := range model.Regions {
+		path := fmt.Sprintf("Network.regions[%d]", i)
+		if regions[region] != "" {
+			v.report(path, RuleUniqueIDs, "region %q is already listed at %s", region, regions[region])
+			continue
+		}
+		regions[region] = path
+	}
+	if model.AggregatorRegion != "" && len(regions) > 0 && regions[model.AggregatorRegion] == "" {
+		v.report("Network.aggregator_region", RuleMembership, "region %q is not one of the model's regions", model.AggregatorRegion)
+	}
+	links := make(map[[2]string]string)
+	for i, link := range model.Links {
+		path := fmt.Sprintf("Network.links[%d]", i)
+		for _, end := range []struct{ field, region string }{{"from", link.From}, {"to", link.To}} {
+			if len(regions) > 0 && regions[end.region] == "" {
+				v.report(path+"."+end.field, RuleMembership, "region %q is not one of the model's regions", end.region)
+			}
+		}
+		key := [2]string{link.From, link.To}
+		if links[key] != "" {
+			v.report(path, RuleUniqueIDs, "link %s-%s is already modelled at %s", link.From, link.To, links[key])
+			continue
+		}
+		links[key] = path
+	}
+	if v.count() == before {
+		if err := model.Validate(); err != nil {
+			v.report("Network", RuleRange, "%v", err)
+		}
+	}
+}
+
+// requiredFields checks the settings that only take effect with another
+// part: verifiers check training statements, evaluation and sybils need
+// the training they score and vote on, rejoining nodes need a federation to
+// sync to, and tiered rounds need regions.
+func (v *scenarioValidator) requiredFields(cfg Config) {
+	if cfg.VerificationCommittee < 0 {
+		v.report("VerificationCommittee", RuleRange, "committee size must not be negative, got %d", cfg.VerificationCommittee)
+	}
+	if cfg.VerificationCommittee > 0 && cfg.Training == nil {
+		v.report("VerificationCommittee", RuleRequiredFields, "a verification committee checks training statements, so it needs Training")
+	}
+	if cfg.LazyVerifiers < 0 || cfg.LazyVerifiers > max(cfg.VerificationCommittee, 0) {
+		v.report("LazyVerifiers", RuleRequiredFields, "%d lazy verifiers is not between 0 and the committee of %d", cfg.LazyVerifiers, cfg.VerificationCommittee)
+	}
+	if cfg.SpotCheckRate < 0 || cfg.SpotCheckRate > 1 {
+		v.report("SpotCheckRate", RuleRange, "spot-check rate must be in [0, 1], got %g", cfg.SpotCheckRate)
+	} else if cfg.SpotCheckRate > 0 && cfg.VerificationCommittee <= 0 {
+		v.report("SpotCheckRate", RuleRequiredFields, "spot checks are decoys for a verification committee, which is not set")
+	}
+	if cfg.Evaluation != nil {
+		if cfg.Training == nil {
+			v.report("Evaluation", RuleRequiredFields, "evaluation scores the models Training produces, which is not set")
+		}
+		if len(cfg.Evaluation.NodeAccuracy) > v.nodes {
+			v.report("Evaluation.node_accuracy", RuleShape, "%d accuracies for %d nodes", len(cfg.Evaluation.NodeAccuracy), v.nodes)
+		}
+		for i, accuracy := range cfg.Evaluation.NodeAccuracy {
+			if !(accuracy >= 0 && accuracy <= 1) {
+				v.report(fmt.Sprintf("Evaluation.node_accuracy[%d]", i), RuleRange, "accuracy must be in [0, 1], got %g", accuracy)
+			}
+		}
+	}
+	if cfg.SybilWave != nil && (cfg.Training == nil || cfg.Federations == nil) {
+		v.report("SybilWave", RuleRequiredFields, "sybils register in Federations and vote on Training rounds; both must be set")
+	}
+	if cfg.IslandRejoin != nil {
+		if cfg.Training == nil || cfg.Federations == nil {
+			v.report("IslandRejoin", RuleRequiredFields, "rejoining nodes sync Training updates to Federations; both must be set")
+		} else if cfg.Training.Sparsity > 0 && cfg.Training.Sparsity < 1 {
+			v.report("IslandRejoin", RuleRequiredFields, "the island cache holds full updates, so Training.sparsity must be unset")
+		}
+		if cfg.IslandRejoin.Nodes > v.nodes {
+			v.report("IslandRejoin.nodes", RuleShape, "%d rejoining nodes for %d nodes", cfg.IslandRejoin.Nodes, v.nodes)
+		}
+	}
+	if cfg.TieredRounds != nil && (cfg.Network == nil || len(cfg.Network.Regions) < 2) {
+		v.report("TieredRounds", RuleRequiredFields, "tiered rounds need a Network with at least two regions")
+	}
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sortedParams(params map[string]float64) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
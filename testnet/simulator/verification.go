@@ -0,0 +1,167 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/batch"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/crypto"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/p2p"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/internal/simrand"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/protocol"
+	"github.com/rwilliamspbg-ops/Sovereign_Map_Federated_Learning/pkg/reasons"
+)
+
+// VerificationReport records how the verifier committee fared when
+// Config.VerificationCommittee is set.
+type VerificationReport struct {
+	// Decoys counts spot checks sent to verifiers.
+	Decoys int
+	// Flagged maps each verifier caught approving a decoy to the round it
+	// was caught in.
+	Flagged map[string]int
+	// Reputation is every verifier's final reputation.
+	Reputation map[string]float64
+	// Rejections counts the verdicts that rejected a request, decoys
+	// included, by reason code.
+	Rejections map[reasons.Code]int
+}
+
+// verificationSim runs every round's updates past a committee of verifiers
+// before they are aggregated. Each request carries the update's training
+// statement as its proof. Honest verifiers check the statement against the
+// update, so Byzantine updates, which commit to what they send, pass and
+// are left to the aggregator; lazy verifiers approve without checking.
+type verificationSim struct {
+	verifier *p2p.Verifier
+	ids      []string
+	channels []*crypto.SecureChannel
+	// lazy is how many of the first verifiers approve without checking.
+	lazy   int
+	report VerificationReport
+}
+
+// newVerificationSim registers size verifiers with verifier, the first lazy
+// of them lazy, and sizes its model update committee to match. With a
+// positive spotCheckRate, batches carry decoys drawn from rng.
+func newVerificationSim(verifier *p2p.Verifier, size, lazy int, spotCheckRate float64, rng *simrand.SeededRand) (*verificationSim, error) {
+	policy, err := verifier.GetCommitteePolicy(p2p.ArtifactModelUpdate)
+	if err != nil {
+		return nil, err
+	}
+	policy.Size = size
+	if err := verifier.SetCommitteePolicy(p2p.ArtifactModelUpdate, policy); err != nil {
+		return nil, err
+	}
+	if spotCheckRate > 0 {
+		if err := verifier.SetSpotChecks(p2p.SpotCheckConfig{Rate: spotCheckRate, Rand: rng}); err != nil {
+			return nil, err
+		}
+	}
+	v := &verificationSim{verifier: verifier, lazy: lazy, report: VerificationReport{Flagged: make(map[string]int), Rejections: make(map[reasons.Code]int)}}
+	for i := 0; i < size; i++ {
+		channel, err := crypto.NewSecureChannel()
+		if err != nil {
+			return nil, err
+		}
+		publicKey, err := channel.ExportPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		id := fmt.Sprintf("verifier-%02d", i)
+		if err := verifier.RegisterPeer(&p2p.PeerDetail{ID: id, PublicKey: publicKey}); err != nil {
+			return nil, err
+		}
+		v.ids = append(v.ids, id)
+		v.channels = append(v.channels, channel)
+	}
+	return v, nil
+}
+
+// verify sends every verifier the round's updates as one batch, collects
+// its signed verdict on each request, and requires every update to pass.
+func (v *verificationSim) verify(ctx context.Context, round int, updates []batch.Update) error {
+	requests := make([]*p2p.ModelVerificationRequest, len(updates))
+	for i, update := range updates {
+		proof, err := json.Marshal(update.Statement)
+		if err != nil {
+			return err
+		}
+		requests[i] = &p2p.ModelVerificationRequest{
+			ModelWeights: update.Bytes(),
+			Proof:        proof,
+			ProposerID:   update.NodeID,
+			Round:        round,
+			Timestamp:    time.Now(),
+		}
+		if _, err := v.verifier.RequestVerification(ctx, requests[i]); err != nil {
+			return err
+		}
+	}
+
+	for i, channel := range v.channels {
+		assigned, err := v.verifier.AssignBatch(v.ids[i], requests)
+		if err != nil {
+			return err
+		}
+		v.report.Decoys += len(assigned) - len(requests)
+		for _, req := range assigned {
+			valid := i < v.lazy || checkStatement(req) == nil
+			resp := &p2p.ModelVerificationResponse{RequestID: req.RequestID, VerifierID: v.ids[i], Valid: valid, Timestamp: time.Now()}
+			if !valid {
+				resp.ReasonCode = reasons.InvalidTrainingStatement
+				v.report.Rejections[resp.ReasonCode]++
+			}
+			if err := resp.Sign(channel); err != nil {
+				return err
+			}
+			if err := v.verifier.SubmitVerification(ctx, v.ids[i], resp); err != nil {
+				return err
+			}
+		}
+		if _, seen := v.report.Flagged[v.ids[i]]; !seen && v.verifier.Flagged(v.ids[i]) {
+			v.report.Flagged[v.ids[i]] = round
+		}
+	}
+
+	for i, req := range requests {
+		passed, _, err := v.verifier.CheckVerificationStatus(req.RequestID)
+		if err != nil {
+			return err
+		}
+		if !passed {
+			return fmt.Errorf("update %s round %d failed verification", updates[i].NodeID, round)
+		}
+	}
+	return nil
+}
+
+// checkStatement verifies the training statement in req's proof against
+// the update it carries, as an honest verifier does.
+func checkStatement(req *p2p.ModelVerificationRequest) error {
+	var statement protocol.TrainingStatement
+	if err := json.Unmarshal(req.Proof, &statement); err != nil {
+		return err
+	}
+	return statement.Check(req.ProposerID, req.Round, protocol.UpdateDigest(req.ModelWeights))
+}
+
+// finalReport fills in the verifiers' reputations.
+func (v *verificationSim) finalReport() *VerificationReport {
+	report := v.report
+	report.Flagged = make(map[string]int, len(v.report.Flagged))
+	for id, round := range v.report.Flagged {
+		report.Flagged[id] = round
+	}
+	report.Rejections = make(map[reasons.Code]int, len(v.report.Rejections))
+	for code, count := range v.report.Rejections {
+		report.Rejections[code] = count
+	}
+	report.Reputation = make(map[string]float64, len(v.ids))
+	for _, id := range v.ids {
+		report.Reputation[id], _ = v.verifier.Reputation(id)
+	}
+	return &report
+}